@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"net"
 	"strconv"
@@ -13,13 +14,16 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/0xPolygonHermez/zkevm-node/aggregator/artifacts"
 	"github.com/0xPolygonHermez/zkevm-node/aggregator/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/aggregator/prover"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
 	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
+	"github.com/0xPolygonHermez/zkevm-node/event"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4"
@@ -36,6 +40,9 @@ const (
 	monitoredIDFormat = "proof-from-%v-to-%v"
 )
 
+// errNotSynced is returned while the synchronizer is not synced to retry the check
+var errNotSynced = errors.New("synchronizer is not synced")
+
 type finalProofMsg struct {
 	proverName     string
 	proverID       string
@@ -61,6 +68,22 @@ type Aggregator struct {
 	finalProof     chan finalProofMsg
 	verifyingProof bool
 
+	fallbackProverNames map[string]struct{}
+	fallbackProofs      int
+	fallbackProofsMutex *sync.Mutex
+
+	connectedProvers      map[string]prover.Info
+	connectedProversMutex *sync.RWMutex
+
+	eventLog *event.EventLog
+
+	// artifacts stores the prover inputs and the final proofs, nil if disabled
+	artifacts *artifacts.Storage
+
+	// integrityCheckedUntil is the last batch of the last sequence whose data integrity
+	// has been checked, it's guarded by StateDBMutex
+	integrityCheckedUntil uint64
+
 	srv  *grpc.Server
 	ctx  context.Context
 	exit context.CancelFunc
@@ -72,6 +95,7 @@ func New(
 	stateInterface stateInterface,
 	ethTxManager ethTxManager,
 	etherman etherman,
+	eventLog *event.EventLog,
 ) (Aggregator, error) {
 	var profitabilityChecker aggregatorTxProfitabilityChecker
 	switch cfg.TxProfitabilityCheckerType {
@@ -93,6 +117,26 @@ func New(
 		TimeCleanupLockedProofs: cfg.CleanupLockedProofsInterval,
 
 		finalProof: make(chan finalProofMsg),
+
+		fallbackProverNames: make(map[string]struct{}, len(cfg.FallbackProvers.ProverNames)),
+		fallbackProofsMutex: &sync.Mutex{},
+
+		connectedProvers:      make(map[string]prover.Info),
+		connectedProversMutex: &sync.RWMutex{},
+
+		eventLog: eventLog,
+	}
+
+	for _, name := range cfg.FallbackProvers.ProverNames {
+		a.fallbackProverNames[name] = struct{}{}
+	}
+
+	if cfg.Artifacts.Enabled {
+		storage, err := artifacts.New(cfg.Artifacts)
+		if err != nil {
+			return Aggregator{}, fmt.Errorf("failed to create prover artifacts storage: %w", err)
+		}
+		a.artifacts = storage
 	}
 
 	return a, nil
@@ -145,6 +189,9 @@ func (a *Aggregator) Start(ctx context.Context) error {
 
 	go a.cleanupLockedProofs()
 	go a.sendFinalProof()
+	if a.artifacts != nil {
+		go a.artifacts.Start(ctx)
+	}
 
 	<-ctx.Done()
 	return ctx.Err()
@@ -180,12 +227,23 @@ func (a *Aggregator) Channel(stream prover.AggregatorService_ChannelServer) erro
 	)
 	log.Info("Establishing stream connection with prover")
 
-	// Check if prover supports the required Fork ID
-	if !prover.SupportsForkID(a.cfg.ForkId) {
-		err := errors.New("prover does not support required fork ID")
+	// Check if prover is compatible with the aggregator
+	proverInfo := prover.Info()
+	log.Infof("Prover versions: proto %s, server %s, fork ID %d", proverInfo.VersionProto, proverInfo.VersionServer, proverInfo.ForkID)
+	if err := a.checkProverCompatibility(proverInfo); err != nil {
 		log.Warn(FirstToUpper(err.Error()))
+		a.logProverIncompatibleEvent(ctx, proverInfo, err)
 		return err
 	}
+	a.addConnectedProver(proverInfo)
+	defer a.removeConnectedProver(proverInfo)
+
+	isFallback := a.isFallbackProver(prover)
+	proverPool := metrics.ProverPoolPrimary
+	if isFallback {
+		proverPool = metrics.ProverPoolFallback
+		log.Info("Prover belongs to the fallback prover pool")
+	}
 
 	for {
 		select {
@@ -198,8 +256,13 @@ func (a *Aggregator) Channel(stream prover.AggregatorService_ChannelServer) erro
 
 		default:
 			isIdle, err := prover.IsIdle()
+			failures := a.recordProverStatus(prover.ID(), err)
 			if err != nil {
 				log.Errorf("Failed to check if prover is idle: %v", err)
+				if a.cfg.MaxProverStatusFailures > 0 && failures >= a.cfg.MaxProverStatusFailures {
+					log.Warnf("Disconnecting prover after %d consecutive failed status requests", failures)
+					return fmt.Errorf("prover status failed %d consecutive times: %w", failures, err)
+				}
 				time.Sleep(a.cfg.RetryTime.Duration)
 				continue
 			}
@@ -209,6 +272,19 @@ func (a *Aggregator) Channel(stream prover.AggregatorService_ChannelServer) erro
 				continue
 			}
 
+			if isFallback {
+				proofGenerated, err := a.tryGenerateFallbackBatchProof(ctx, prover)
+				if err != nil {
+					log.Errorf("Error trying to generate proof with fallback prover: %v", err)
+				}
+				if proofGenerated {
+					metrics.BatchProofGenerated(proverPool)
+				} else {
+					time.Sleep(a.cfg.RetryTime.Duration)
+				}
+				continue
+			}
+
 			_, err = a.tryBuildFinalProof(ctx, prover, nil)
 			if err != nil {
 				log.Errorf("Error checking proofs to verify: %v", err)
@@ -223,6 +299,9 @@ func (a *Aggregator) Channel(stream prover.AggregatorService_ChannelServer) erro
 				if err != nil {
 					log.Errorf("Error trying to generate proof: %v", err)
 				}
+				if proofGenerated {
+					metrics.BatchProofGenerated(proverPool)
+				}
 			}
 			if !proofGenerated {
 				// if no proof was generated (aggregated or batch) wait some time before retry
@@ -282,6 +361,7 @@ func (a *Aggregator) sendFinalProof() {
 				a.handleFailureToAddVerifyBatchToBeMonitored(ctx, proof)
 				continue
 			}
+			a.storeVerifiedBatchProof(ctx, proof, &inputs)
 
 			// process monitored batch verifications before starting a next cycle
 			a.EthTxManager.ProcessPendingMonitoredTxs(ctx, ethTxManagerOwner, func(result ethtxmanager.MonitoredTxResult, dbTx pgx.Tx) {
@@ -304,6 +384,33 @@ func (a *Aggregator) handleFailureToAddVerifyBatchToBeMonitored(ctx context.Cont
 	a.endProofVerification()
 }
 
+// storeVerifiedBatchProof stores the final proof sent to L1 along with its public inputs,
+// so it can be retrieved from the node once the batches are verified
+func (a *Aggregator) storeVerifiedBatchProof(ctx context.Context, proof *state.Proof, inputs *ethmanTypes.FinalProofInputs) {
+	public := inputs.FinalProof.GetPublic()
+	publicInputs := public.GetPublicInputs()
+	verifiedBatchProof := &state.VerifiedBatchProof{
+		BatchNumber:      proof.BatchNumber,
+		BatchNumberFinal: proof.BatchNumberFinal,
+		Proof:            inputs.FinalProof.GetProof(),
+		PublicInputs: state.FinalProofPublicInputs{
+			OldStateRoot:     common.BytesToHash(publicInputs.GetOldStateRoot()),
+			OldAccInputHash:  common.BytesToHash(publicInputs.GetOldAccInputHash()),
+			OldBatchNum:      proof.BatchNumber - 1,
+			ChainID:          publicInputs.GetChainId(),
+			ForkID:           publicInputs.GetForkId(),
+			NewStateRoot:     common.BytesToHash(inputs.NewStateRoot),
+			NewAccInputHash:  common.BytesToHash(public.GetNewAccInputHash()),
+			NewLocalExitRoot: common.BytesToHash(inputs.NewLocalExitRoot),
+			NewBatchNum:      proof.BatchNumberFinal,
+			AggregatorAddr:   common.HexToAddress(publicInputs.GetAggregatorAddr()),
+		},
+	}
+	if err := a.State.AddVerifiedBatchProof(ctx, verifiedBatchProof, nil); err != nil {
+		log.Errorf("Failed to store the final proof of batches %d-%d: %v", proof.BatchNumber, proof.BatchNumberFinal, err)
+	}
+}
+
 // buildFinalProof builds and return the final proof for an aggregated/batch proof.
 func (a *Aggregator) buildFinalProof(ctx context.Context, prover proverInterface, proof *state.Proof) (*prover.FinalProof, error) {
 	log := log.WithFields(
@@ -370,10 +477,8 @@ func (a *Aggregator) tryBuildFinalProof(ctx context.Context, prover proverInterf
 	}
 	log.Debug("Send final proof time reached")
 
-	for !a.isSynced(ctx, nil) {
-		log.Info("Waiting for synchronizer to sync...")
-		time.Sleep(a.cfg.RetryTime.Duration)
-		continue
+	if err := a.waitForSynced(ctx, nil); err != nil {
+		return false, err
 	}
 
 	var lastVerifiedBatchNum uint64
@@ -434,6 +539,8 @@ func (a *Aggregator) tryBuildFinalProof(ctx context.Context, prover proverInterf
 		return false, err
 	}
 
+	a.storeFinalProof(proof, finalProof)
+
 	msg := finalProofMsg{
 		proverName:     proverName,
 		proverID:       proverID,
@@ -546,7 +653,7 @@ func (a *Aggregator) getAndLockProofsToAggregate(ctx context.Context, prover pro
 	a.StateDBMutex.Lock()
 	defer a.StateDBMutex.Unlock()
 
-	proof1, proof2, err := a.State.GetProofsToAggregate(ctx, nil)
+	proof1, proof2, err := a.State.GetProofsToAggregate(ctx, a.cfg.MaxAggregatedBatches, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -764,6 +871,11 @@ func (a *Aggregator) getAndLockBatchToProve(ctx context.Context, prover proverIn
 	log.Infof("Found virtual batch %d pending to generate proof", batchToVerify.BatchNumber)
 	log = log.WithFields("batch", batchToVerify.BatchNumber)
 
+	if err := a.checkBatchDataIntegrity(ctx, batchToVerify.BatchNumber); err != nil {
+		log.Errorf("Failed to check the data integrity of the batch, err: %v", err)
+		return nil, nil, err
+	}
+
 	log.Info("Checking profitability to aggregate batch")
 
 	// pass matic collateral as zero here, bcs in smart contract fee for aggregator is not defined yet
@@ -798,9 +910,10 @@ func (a *Aggregator) getAndLockBatchToProve(ctx context.Context, prover proverIn
 }
 
 func (a *Aggregator) tryGenerateBatchProof(ctx context.Context, prover proverInterface) (bool, error) {
+	proverID := prover.ID()
 	log := log.WithFields(
 		"prover", prover.Name(),
-		"proverId", prover.ID(),
+		"proverId", proverID,
 		"proverAddr", prover.Addr(),
 	)
 	log.Debug("tryGenerateBatchProof start")
@@ -816,14 +929,19 @@ func (a *Aggregator) tryGenerateBatchProof(ctx context.Context, prover proverInt
 	}
 
 	log = log.WithFields("batch", batchToProve.BatchNumber)
+	a.startProvingBatch(proverID, batchToProve.BatchNumber)
 
 	var (
-		genProofID *string
-		err        error
+		genProofID  *string
+		err         error
+		provingTime time.Duration
 	)
 
 	defer func() {
+		// the proving time is only set once the prover generated the proof
+		a.endProvingBatch(proverID, provingTime, err)
 		if err != nil {
+			// the proof in progress is deleted, so the batch can be proven by any prover
 			err2 := a.State.DeleteGeneratedProofs(a.ctx, proof.BatchNumber, proof.BatchNumberFinal, nil)
 			if err2 != nil {
 				log.Errorf("Failed to delete proof in progress, err: %v", err2)
@@ -850,10 +968,12 @@ func (a *Aggregator) tryGenerateBatchProof(ctx context.Context, prover proverInt
 	}
 
 	proof.InputProver = string(b)
+	a.storeProverInput(batchToProve.BatchNumber, b)
 
 	log.Infof("Sending a batch to the prover. OldStateRoot [%#x], OldBatchNum [%d]",
 		inputProver.PublicInputs.OldStateRoot, inputProver.PublicInputs.OldBatchNum)
 
+	provingStart := time.Now()
 	genProofID, err = prover.BatchProof(inputProver)
 	if err != nil {
 		err = fmt.Errorf("failed to get batch proof id, %w", err)
@@ -866,14 +986,26 @@ func (a *Aggregator) tryGenerateBatchProof(ctx context.Context, prover proverInt
 	log.Infof("Proof ID %v", *proof.ProofID)
 	log = log.WithFields("proofId", *proof.ProofID)
 
-	resGetProof, err := prover.WaitRecursiveProof(ctx, *proof.ProofID)
+	waitCtx := ctx
+	if a.cfg.BatchProofTimeout.Duration > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, a.cfg.BatchProofTimeout.Duration)
+		defer cancel()
+	}
+	resGetProof, err := prover.WaitRecursiveProof(waitCtx, *proof.ProofID)
 	if err != nil {
 		err = fmt.Errorf("failed to get proof from prover, %w", err)
 		log.Error(FirstToUpper(err.Error()))
 		return false, err
 	}
 
-	log.Info("Batch proof generated")
+	provingTime = time.Since(provingStart)
+	log.Infof("Batch proof generated in %v", provingTime)
+
+	// the proving time is used by the sequencer to limit the batches proving complexity
+	if err := a.State.AddBatchProvingTime(ctx, batchToProve.BatchNumber, proof.Prover, provingTime, nil); err != nil {
+		log.Warnf("Failed to store the batch proving time, err: %v", err)
+	}
 
 	proof.Proof = resGetProof
 
@@ -971,6 +1103,18 @@ func (a *Aggregator) isSynced(ctx context.Context, batchNum *uint64) bool {
 	return true
 }
 
+// waitForSynced checks if the synchronizer is synced, see isSynced, every RetryTime until it is
+// or the context is done
+func (a *Aggregator) waitForSynced(ctx context.Context, batchNum *uint64) error {
+	return retry.Do(ctx, "aggregator_wait_for_synced", retry.Constant(math.MaxInt, a.cfg.RetryTime.Duration), nil, func() error {
+		if !a.isSynced(ctx, batchNum) {
+			log.Info("Waiting for synchronizer to sync...")
+			return errNotSynced
+		}
+		return nil
+	})
+}
+
 func (a *Aggregator) buildInputProver(ctx context.Context, batchToVerify *state.Batch) (*prover.InputProver, error) {
 	previousBatch, err := a.State.GetBatchByNumber(ctx, batchToVerify.BatchNumber-1, nil)
 	if err != nil && err != state.ErrNotFound {
@@ -1029,6 +1173,7 @@ func (hc *healthChecker) Watch(req *grpchealth.HealthCheckRequest, server grpche
 func (a *Aggregator) handleMonitoredTxResult(result ethtxmanager.MonitoredTxResult) {
 	mTxResultLogger := ethtxmanager.CreateMonitoredTxResultLogger(ethTxManagerOwner, result)
 	if result.Status == ethtxmanager.MonitoredTxStatusFailed {
+		a.logL1TxRevertedEvent(result)
 		mTxResultLogger.Fatal("failed to send batch verification, TODO: review this fatal and define what to do in this case")
 	}
 
@@ -1051,9 +1196,9 @@ func (a *Aggregator) handleMonitoredTxResult(result ethtxmanager.MonitoredTxResu
 
 	// wait for the synchronizer to catch up the verified batches
 	log.Debug("A final proof has been sent, waiting for the network to be synced")
-	for !a.isSynced(a.ctx, &proofBatchNumberFinal) {
-		log.Info("Waiting for synchronizer to sync...")
-		time.Sleep(a.cfg.RetryTime.Duration)
+	if err := a.waitForSynced(a.ctx, &proofBatchNumberFinal); err != nil {
+		log.Errorf("Failed waiting for the network to be synced with the final proof: %v", err)
+		return
 	}
 
 	// network is synced with the final proof, we can safely delete all recursive
@@ -1094,3 +1239,22 @@ func FirstToUpper(s string) string {
 	runes[0] = unicode.ToUpper(runes[0])
 	return string(runes)
 }
+
+// logL1TxRevertedEvent logs the event for a verification tx reverted in L1 including the decoded revert messages
+func (a *Aggregator) logL1TxRevertedEvent(result ethtxmanager.MonitoredTxResult) {
+	if a.eventLog == nil {
+		return
+	}
+
+	ev := &event.Event{
+		ReceivedAt:  time.Now(),
+		Source:      event.Source_Node,
+		Component:   event.Component_Aggregator,
+		Level:       event.Level_Critical,
+		EventID:     event.EventID_L1TxReverted,
+		Description: fmt.Sprintf("verify batches tx %s reverted: %s", result.ID, strings.Join(result.RevertMessages(), "; ")),
+	}
+	if err := a.eventLog.LogEvent(context.Background(), ev); err != nil {
+		log.Errorf("error storing event: %v", err)
+	}
+}
@@ -161,6 +161,10 @@ func TestSendFinalProof(t *testing.T) {
 				}).Return(&to, data, nil).Once()
 				monitoredTxID := buildMonitoredTxID(batchNum, batchNumFinal)
 				m.ethTxManager.On("Add", mock.Anything, ethTxManagerOwner, monitoredTxID, from, &to, value, data, cfg.GasOffset, nil).Return(nil).Once()
+				m.stateMock.On("AddVerifiedBatchProof", mock.Anything, mock.MatchedBy(func(p *state.VerifiedBatchProof) bool {
+					return p.BatchNumber == batchNum && p.BatchNumberFinal == batchNumFinal &&
+						p.PublicInputs.NewStateRoot == finalBatch.StateRoot && p.PublicInputs.NewLocalExitRoot == finalBatch.LocalExitRoot
+				}), nil).Return(nil).Once()
 				ethTxManResult := ethtxmanager.MonitoredTxResult{
 					ID:     monitoredTxID,
 					Status: ethtxmanager.MonitoredTxStatusConfirmed,
@@ -190,7 +194,7 @@ func TestSendFinalProof(t *testing.T) {
 			stateMock := mocks.NewStateMock(t)
 			ethTxManager := mocks.NewEthTxManager(t)
 			etherman := mocks.NewEtherman(t)
-			a, err := New(cfg, stateMock, ethTxManager, etherman)
+			a, err := New(cfg, stateMock, ethTxManager, etherman, nil)
 			require.NoError(err)
 			a.ctx, a.exit = context.WithCancel(context.Background())
 			m := mox{
@@ -255,7 +259,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				m.proverMock.On("Name").Return(proverName).Twice()
 				m.proverMock.On("ID").Return(proverID).Twice()
 				m.proverMock.On("Addr").Return("addr")
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(nil, nil, errBanana).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(nil, nil, errBanana).Once()
 			},
 			asserts: func(result bool, a *Aggregator, err error) {
 				assert.False(result)
@@ -268,7 +272,21 @@ func TestTryAggregateProofs(t *testing.T) {
 				m.proverMock.On("Name").Return(proverName).Twice()
 				m.proverMock.On("ID").Return(proverID).Twice()
 				m.proverMock.On("Addr").Return("addr")
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(nil, nil, state.ErrNotFound).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(nil, nil, state.ErrNotFound).Once()
+			},
+			asserts: func(result bool, a *Aggregator, err error) {
+				assert.False(result)
+				assert.NoError(err)
+			},
+		},
+		{
+			name: "getAndLockProofsToAggregate limits the aggregated batches",
+			setup: func(m mox, a *Aggregator) {
+				a.cfg.MaxAggregatedBatches = 10
+				m.proverMock.On("Name").Return(proverName).Twice()
+				m.proverMock.On("ID").Return(proverID).Twice()
+				m.proverMock.On("Addr").Return("addr")
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(10), nil).Return(nil, nil, state.ErrNotFound).Once()
 			},
 			asserts: func(result bool, a *Aggregator, err error) {
 				assert.False(result)
@@ -284,7 +302,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				dbTx := &mocks.DbTxMock{}
 				dbTx.On("Rollback", mock.MatchedBy(matchProverCtxFn)).Return(nil).Once()
 				m.stateMock.On("BeginStateTransaction", mock.MatchedBy(matchProverCtxFn)).Return(dbTx, nil).Once()
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(&proof1, &proof2, nil).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(&proof1, &proof2, nil).Once()
 				m.stateMock.
 					On("UpdateGeneratedProof", mock.MatchedBy(matchProverCtxFn), &proof1, dbTx).
 					Run(func(args mock.Arguments) {
@@ -307,7 +325,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				dbTx := &mocks.DbTxMock{}
 				lockProofsTxBegin := m.stateMock.On("BeginStateTransaction", mock.MatchedBy(matchProverCtxFn)).Return(dbTx, nil).Once()
 				lockProofsTxCommit := dbTx.On("Commit", mock.MatchedBy(matchProverCtxFn)).Return(nil).Once()
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(&proof1, &proof2, nil).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(&proof1, &proof2, nil).Once()
 				proof1GeneratingTrueCall := m.stateMock.
 					On("UpdateGeneratedProof", mock.MatchedBy(matchProverCtxFn), &proof1, dbTx).
 					Run(func(args mock.Arguments) {
@@ -356,7 +374,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				dbTx := &mocks.DbTxMock{}
 				lockProofsTxBegin := m.stateMock.On("BeginStateTransaction", mock.MatchedBy(matchProverCtxFn)).Return(dbTx, nil).Once()
 				lockProofsTxCommit := dbTx.On("Commit", mock.MatchedBy(matchProverCtxFn)).Return(nil).Once()
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(&proof1, &proof2, nil).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(&proof1, &proof2, nil).Once()
 				proof1GeneratingTrueCall := m.stateMock.
 					On("UpdateGeneratedProof", mock.MatchedBy(matchProverCtxFn), &proof1, dbTx).
 					Run(func(args mock.Arguments) {
@@ -406,7 +424,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				dbTx := &mocks.DbTxMock{}
 				lockProofsTxBegin := m.stateMock.On("BeginStateTransaction", mock.MatchedBy(matchProverCtxFn)).Return(dbTx, nil).Once()
 				dbTx.On("Commit", mock.MatchedBy(matchProverCtxFn)).Return(nil).Once()
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(&proof1, &proof2, nil).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(&proof1, &proof2, nil).Once()
 				proof1GeneratingTrueCall := m.stateMock.
 					On("UpdateGeneratedProof", mock.MatchedBy(matchProverCtxFn), &proof1, dbTx).
 					Run(func(args mock.Arguments) {
@@ -448,7 +466,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				dbTx := &mocks.DbTxMock{}
 				lockProofsTxBegin := m.stateMock.On("BeginStateTransaction", mock.MatchedBy(matchProverCtxFn)).Return(dbTx, nil).Twice()
 				lockProofsTxCommit := dbTx.On("Commit", mock.MatchedBy(matchProverCtxFn)).Return(nil).Once()
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(&proof1, &proof2, nil).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(&proof1, &proof2, nil).Once()
 				proof1GeneratingTrueCall := m.stateMock.
 					On("UpdateGeneratedProof", mock.MatchedBy(matchProverCtxFn), &proof1, dbTx).
 					Run(func(args mock.Arguments) {
@@ -500,7 +518,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				dbTx := &mocks.DbTxMock{}
 				lockProofsTxBegin := m.stateMock.On("BeginStateTransaction", mock.MatchedBy(matchProverCtxFn)).Return(dbTx, nil).Twice()
 				lockProofsTxCommit := dbTx.On("Commit", mock.MatchedBy(matchProverCtxFn)).Return(nil).Once()
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(&proof1, &proof2, nil).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(&proof1, &proof2, nil).Once()
 				proof1GeneratingTrueCall := m.stateMock.
 					On("UpdateGeneratedProof", mock.MatchedBy(matchProverCtxFn), &proof1, dbTx).
 					Run(func(args mock.Arguments) {
@@ -553,7 +571,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				dbTx := &mocks.DbTxMock{}
 				m.stateMock.On("BeginStateTransaction", mock.MatchedBy(matchProverCtxFn)).Return(dbTx, nil).Twice()
 				dbTx.On("Commit", mock.MatchedBy(matchProverCtxFn)).Return(nil).Twice()
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(&proof1, &proof2, nil).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(&proof1, &proof2, nil).Once()
 				m.stateMock.
 					On("UpdateGeneratedProof", mock.MatchedBy(matchProverCtxFn), &proof1, dbTx).
 					Run(func(args mock.Arguments) {
@@ -617,7 +635,7 @@ func TestTryAggregateProofs(t *testing.T) {
 				dbTx := &mocks.DbTxMock{}
 				m.stateMock.On("BeginStateTransaction", mock.MatchedBy(matchProverCtxFn)).Return(dbTx, nil).Twice()
 				dbTx.On("Commit", mock.MatchedBy(matchProverCtxFn)).Return(nil).Twice()
-				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), nil).Return(&proof1, &proof2, nil).Once()
+				m.stateMock.On("GetProofsToAggregate", mock.MatchedBy(matchProverCtxFn), uint64(0), nil).Return(&proof1, &proof2, nil).Once()
 				m.stateMock.
 					On("UpdateGeneratedProof", mock.MatchedBy(matchProverCtxFn), &proof1, dbTx).
 					Run(func(args mock.Arguments) {
@@ -685,7 +703,7 @@ func TestTryAggregateProofs(t *testing.T) {
 			ethTxManager := mocks.NewEthTxManager(t)
 			etherman := mocks.NewEtherman(t)
 			proverMock := mocks.NewProverMock(t)
-			a, err := New(cfg, stateMock, ethTxManager, etherman)
+			a, err := New(cfg, stateMock, ethTxManager, etherman, nil)
 			require.NoError(err)
 			aggregatorCtx := context.WithValue(context.Background(), "owner", "aggregator") //nolint:staticcheck
 			a.ctx, a.exit = context.WithCancel(aggregatorCtx)
@@ -795,6 +813,12 @@ func TestTryGenerateBatchProof(t *testing.T) {
 			asserts: func(result bool, a *Aggregator, err error) {
 				assert.False(result)
 				assert.ErrorIs(err, errBanana)
+				provers := a.ConnectedProvers()
+				require.Len(provers, 1)
+				assert.Equal(uint64(0), provers[0].ProvingBatch)
+				assert.Equal(uint64(1), provers[0].BatchProofsFailed)
+				assert.Equal(uint64(0), provers[0].BatchProofsGenerated)
+				assert.Equal(uint64(0), provers[0].AvgBatchProvingTimeMs)
 			},
 		},
 		{
@@ -880,6 +904,7 @@ func TestTryGenerateBatchProof(t *testing.T) {
 				require.NoError(err)
 				m.proverMock.On("BatchProof", expectedInputProver).Return(&proofID, nil).Once()
 				m.proverMock.On("WaitRecursiveProof", mock.MatchedBy(matchProverCtxFn), proofID).Return(recursiveProof, nil).Once()
+				m.stateMock.On("AddBatchProvingTime", mock.MatchedBy(matchProverCtxFn), batchToProve.BatchNumber, &proverName, mock.AnythingOfType("time.Duration"), nil).Return(nil).Once()
 				b, err := json.Marshal(expectedInputProver)
 				require.NoError(err)
 				m.stateMock.On("UpdateGeneratedProof", mock.MatchedBy(matchAggregatorCtxFn), mock.Anything, nil).Run(
@@ -898,6 +923,10 @@ func TestTryGenerateBatchProof(t *testing.T) {
 			asserts: func(result bool, a *Aggregator, err error) {
 				assert.True(result)
 				assert.NoError(err)
+				provers := a.ConnectedProvers()
+				require.Len(provers, 1)
+				assert.Equal(uint64(0), provers[0].BatchProofsFailed)
+				assert.Equal(uint64(1), provers[0].BatchProofsGenerated)
 			},
 		},
 		{
@@ -924,6 +953,7 @@ func TestTryGenerateBatchProof(t *testing.T) {
 				require.NoError(err)
 				m.proverMock.On("BatchProof", expectedInputProver).Return(&proofID, nil).Once()
 				m.proverMock.On("WaitRecursiveProof", mock.MatchedBy(matchProverCtxFn), proofID).Return(recursiveProof, nil).Once()
+				m.stateMock.On("AddBatchProvingTime", mock.MatchedBy(matchProverCtxFn), batchToProve.BatchNumber, &proverName, mock.AnythingOfType("time.Duration"), nil).Return(nil).Once()
 				b, err := json.Marshal(expectedInputProver)
 				require.NoError(err)
 				isSyncedCall := m.stateMock.
@@ -958,7 +988,7 @@ func TestTryGenerateBatchProof(t *testing.T) {
 			ethTxManager := mocks.NewEthTxManager(t)
 			etherman := mocks.NewEtherman(t)
 			proverMock := mocks.NewProverMock(t)
-			a, err := New(cfg, stateMock, ethTxManager, etherman)
+			a, err := New(cfg, stateMock, ethTxManager, etherman, nil)
 			require.NoError(err)
 			aggregatorCtx := context.WithValue(context.Background(), "owner", "aggregator") //nolint:staticcheck
 			a.ctx, a.exit = context.WithCancel(aggregatorCtx)
@@ -968,6 +998,7 @@ func TestTryGenerateBatchProof(t *testing.T) {
 				etherman:     etherman,
 				proverMock:   proverMock,
 			}
+			a.addConnectedProver(prover.Info{Name: proverName, ID: proverID})
 			if tc.setup != nil {
 				tc.setup(m, &a)
 			}
@@ -1235,7 +1266,7 @@ func TestTryBuildFinalProof(t *testing.T) {
 			ethTxManager := mocks.NewEthTxManager(t)
 			etherman := mocks.NewEtherman(t)
 			proverMock := mocks.NewProverMock(t)
-			a, err := New(cfg, stateMock, ethTxManager, etherman)
+			a, err := New(cfg, stateMock, ethTxManager, etherman, nil)
 			require.NoError(err)
 			aggregatorCtx := context.WithValue(context.Background(), "owner", "aggregator") //nolint:staticcheck
 			a.ctx, a.exit = context.WithCancel(aggregatorCtx)
@@ -1365,7 +1396,7 @@ func TestIsSynced(t *testing.T) {
 			ethTxManager := mocks.NewEthTxManager(t)
 			etherman := mocks.NewEtherman(t)
 			proverMock := mocks.NewProverMock(t)
-			a, err := New(cfg, stateMock, ethTxManager, etherman)
+			a, err := New(cfg, stateMock, ethTxManager, etherman, nil)
 			require.NoError(err)
 			aggregatorCtx := context.WithValue(context.Background(), "owner", "aggregator") //nolint:staticcheck
 			a.ctx, a.exit = context.WithCancel(aggregatorCtx)
@@ -1385,3 +1416,214 @@ func TestIsSynced(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldEscalateToFallback(t *testing.T) {
+	lastVerifiedBatch := &state.VerifiedBatch{BatchNumber: 10}
+
+	testCases := []struct {
+		name     string
+		cfg      FallbackProversConfig
+		setup    func(m *mocks.StateMock)
+		expected bool
+	}{
+		{
+			name: "queue depth below threshold",
+			cfg:  FallbackProversConfig{Enabled: true, MaxQueueDepth: 5},
+			setup: func(m *mocks.StateMock) {
+				m.On("GetLastVerifiedBatch", mock.Anything, nil).Return(lastVerifiedBatch, nil).Once()
+				m.On("GetLastVirtualBatchNum", mock.Anything, nil).Return(uint64(15), nil).Once()
+			},
+			expected: false,
+		},
+		{
+			name: "queue depth above threshold",
+			cfg:  FallbackProversConfig{Enabled: true, MaxQueueDepth: 5},
+			setup: func(m *mocks.StateMock) {
+				m.On("GetLastVerifiedBatch", mock.Anything, nil).Return(lastVerifiedBatch, nil).Once()
+				m.On("GetLastVirtualBatchNum", mock.Anything, nil).Return(uint64(16), nil).Once()
+			},
+			expected: true,
+		},
+		{
+			name: "oldest pending batch too old",
+			cfg:  FallbackProversConfig{Enabled: true, MaxPendingBatchAge: configTypes.NewDuration(time.Minute)},
+			setup: func(m *mocks.StateMock) {
+				m.On("GetLastVerifiedBatch", mock.Anything, nil).Return(lastVerifiedBatch, nil).Once()
+				batch := &state.Batch{BatchNumber: 11, Timestamp: time.Now().Add(-2 * time.Minute)}
+				m.On("GetVirtualBatchToProve", mock.Anything, lastVerifiedBatch.BatchNumber, nil).Return(batch, nil).Once()
+			},
+			expected: true,
+		},
+		{
+			name: "nothing pending to prove",
+			cfg:  FallbackProversConfig{Enabled: true, MaxPendingBatchAge: configTypes.NewDuration(time.Minute)},
+			setup: func(m *mocks.StateMock) {
+				m.On("GetLastVerifiedBatch", mock.Anything, nil).Return(lastVerifiedBatch, nil).Once()
+				m.On("GetVirtualBatchToProve", mock.Anything, lastVerifiedBatch.BatchNumber, nil).Return(nil, state.ErrNotFound).Once()
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stateMock := mocks.NewStateMock(t)
+			tc.setup(stateMock)
+			a, err := New(Config{FallbackProvers: tc.cfg}, stateMock, nil, nil, nil)
+			require.NoError(t, err)
+
+			escalate, err := a.shouldEscalateToFallback(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, escalate)
+		})
+	}
+}
+
+func TestCheckProverCompatibility(t *testing.T) {
+	testCases := []struct {
+		name          string
+		versionsProto []string
+		info          prover.Info
+		expectedErr   error
+	}{
+		{
+			name:        "fork ID mismatch",
+			info:        prover.Info{ForkID: 5, VersionProto: "v0_0_1"},
+			expectedErr: ErrProverForkIDNotSupported,
+		},
+		{
+			name: "any protocol version accepted",
+			info: prover.Info{ForkID: 6, VersionProto: "v9_9_9"},
+		},
+		{
+			name:          "protocol version accepted",
+			versionsProto: []string{"v0_0_1", "v0_0_2"},
+			info:          prover.Info{ForkID: 6, VersionProto: "v0_0_2"},
+		},
+		{
+			name:          "protocol version not supported",
+			versionsProto: []string{"v0_0_1"},
+			info:          prover.Info{ForkID: 6, VersionProto: "v0_0_2"},
+			expectedErr:   ErrProverVersionNotSupported,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := Aggregator{cfg: Config{ForkId: 6, ProverVersionsProto: tc.versionsProto}}
+
+			err := a.checkProverCompatibility(tc.info)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConnectedProversTracking(t *testing.T) {
+	errBanana := errors.New("banana")
+	a, err := New(Config{}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	info := prover.Info{Name: "prover", ID: "proverID"}
+	a.addConnectedProver(info)
+
+	assert.Equal(t, 1, a.recordProverStatus(info.ID, errBanana))
+	assert.Equal(t, 2, a.recordProverStatus(info.ID, errBanana))
+	assert.Equal(t, 0, a.recordProverStatus(info.ID, nil))
+
+	a.startProvingBatch(info.ID, 10)
+	provers := a.ConnectedProvers()
+	require.Len(t, provers, 1)
+	assert.Equal(t, uint64(10), provers[0].ProvingBatch)
+	assert.False(t, provers[0].ConnectedAt.IsZero())
+
+	a.endProvingBatch(info.ID, 2*time.Second, nil)
+	a.startProvingBatch(info.ID, 11)
+	a.endProvingBatch(info.ID, 4*time.Second, nil)
+	a.startProvingBatch(info.ID, 12)
+	a.endProvingBatch(info.ID, time.Second, errBanana)
+
+	provers = a.ConnectedProvers()
+	require.Len(t, provers, 1)
+	assert.Equal(t, uint64(0), provers[0].ProvingBatch)
+	assert.Equal(t, uint64(2), provers[0].BatchProofsGenerated)
+	assert.Equal(t, uint64(1), provers[0].BatchProofsFailed)
+	assert.Equal(t, uint64(3000), provers[0].AvgBatchProvingTimeMs)
+
+	a.removeConnectedProver(info)
+	a.startProvingBatch(info.ID, 13)
+	assert.Empty(t, a.ConnectedProvers())
+}
+
+func TestCheckBatchDataIntegrity(t *testing.T) {
+	sequence := state.Sequence{FromBatchNumber: 4, ToBatchNumber: 6}
+	oldAccInputHash := common.HexToHash("0x1")
+	l1AccInputHash := common.HexToHash("0x2")
+
+	testCases := []struct {
+		name        string
+		l1Info      ethmanTypes.SequencedBatchesInfo
+		integrity   *state.SequenceDataIntegrity
+		expectedErr error
+	}{
+		{
+			name:   "data matches L1",
+			l1Info: ethmanTypes.SequencedBatchesInfo{AccInputHash: l1AccInputHash, PreviousLastBatchSequenced: 3},
+			integrity: &state.SequenceDataIntegrity{
+				FromBatchNumber: 4,
+				ToBatchNumber:   6,
+				L1AccInputHash:  l1AccInputHash,
+				AccInputHash:    l1AccInputHash,
+			},
+		},
+		{
+			name:   "data doesn't match L1",
+			l1Info: ethmanTypes.SequencedBatchesInfo{AccInputHash: l1AccInputHash, PreviousLastBatchSequenced: 3},
+			integrity: &state.SequenceDataIntegrity{
+				FromBatchNumber:   4,
+				ToBatchNumber:     6,
+				L1AccInputHash:    l1AccInputHash,
+				AccInputHash:      common.HexToHash("0x3"),
+				MismatchedBatches: []uint64{5},
+			},
+			expectedErr: state.ErrBatchDataIntegrity,
+		},
+		{
+			name:        "sequence bounds don't match L1",
+			l1Info:      ethmanTypes.SequencedBatchesInfo{AccInputHash: l1AccInputHash, PreviousLastBatchSequenced: 2},
+			expectedErr: state.ErrBatchDataIntegrity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stateMock := mocks.NewStateMock(t)
+			ethermanMock := mocks.NewEtherman(t)
+			a := Aggregator{cfg: Config{CheckBatchDataIntegrity: true}, State: stateMock, Ethman: ethermanMock}
+			ctx := context.Background()
+
+			stateMock.On("GetSequencesInRange", ctx, uint64(5), uint64(5), nil).Return([]state.Sequence{sequence}, nil).Once()
+			ethermanMock.On("GetSequencedBatchesInfo", ctx, uint64(6)).Return(tc.l1Info, nil).Once()
+			if tc.integrity != nil {
+				ethermanMock.On("GetSequencedBatchesInfo", ctx, uint64(3)).Return(ethmanTypes.SequencedBatchesInfo{AccInputHash: oldAccInputHash}, nil).Once()
+				stateMock.On("CheckSequenceDataIntegrity", ctx, sequence, oldAccInputHash, l1AccInputHash, nil).Return(tc.integrity, nil).Once()
+			}
+
+			err := a.checkBatchDataIntegrity(ctx, 5)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Zero(t, a.integrityCheckedUntil)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(6), a.integrityCheckedUntil)
+				// the batches of a checked sequence are not checked again
+				assert.NoError(t, a.checkBatchDataIntegrity(ctx, 6))
+			}
+		})
+	}
+}
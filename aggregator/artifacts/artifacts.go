@@ -0,0 +1,151 @@
+// Package artifacts stores the prover inputs and the final proofs generated by
+// the aggregator in an S3 compatible object storage, so the proofs can be audited
+// later without keeping them in the state DB forever.
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const (
+	proverInputsDir = "prover-inputs"
+	finalProofsDir  = "final-proofs"
+	jsonContentType = "application/json"
+)
+
+// FinalProofInfo identifies a final proof stored in the object storage
+type FinalProofInfo struct {
+	BatchNumber      uint64
+	BatchNumberFinal uint64
+	StoredAt         time.Time
+}
+
+// Storage stores and retrieves the prover artifacts
+type Storage struct {
+	cfg   Config
+	store *objectStore
+}
+
+// New creates a storage of prover artifacts
+func New(cfg Config) (*Storage, error) {
+	store, err := newObjectStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Storage{cfg: cfg, store: store}, nil
+}
+
+// PutProverInput stores the input sent to the prover to generate the proof of a batch
+func (s *Storage) PutProverInput(ctx context.Context, batchNumber uint64, input []byte) error {
+	return s.store.put(ctx, s.proverInputKey(batchNumber), input, jsonContentType)
+}
+
+// GetProverInput returns the input sent to the prover to generate the proof of a batch
+func (s *Storage) GetProverInput(ctx context.Context, batchNumber uint64) ([]byte, error) {
+	return s.store.get(ctx, s.proverInputKey(batchNumber))
+}
+
+// PutFinalProof stores the final proof of a range of batches
+func (s *Storage) PutFinalProof(ctx context.Context, batchNumber, batchNumberFinal uint64, proof []byte) error {
+	return s.store.put(ctx, s.finalProofKey(batchNumber, batchNumberFinal), proof, jsonContentType)
+}
+
+// GetFinalProof returns the final proof of a range of batches
+func (s *Storage) GetFinalProof(ctx context.Context, batchNumber, batchNumberFinal uint64) ([]byte, error) {
+	return s.store.get(ctx, s.finalProofKey(batchNumber, batchNumberFinal))
+}
+
+// FindFinalProof returns the info of the last final proof stored that includes the batch
+func (s *Storage) FindFinalProof(ctx context.Context, batchNumber uint64) (*FinalProofInfo, error) {
+	objects, err := s.store.list(ctx, s.key(finalProofsDir)+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	var found *FinalProofInfo
+	for _, obj := range objects {
+		var from, to uint64
+		if _, err := fmt.Sscanf(path.Base(obj.Key), "%d-%d.json", &from, &to); err != nil {
+			continue
+		}
+		if batchNumber < from || batchNumber > to {
+			continue
+		}
+		if found == nil || obj.LastModified.After(found.StoredAt) {
+			found = &FinalProofInfo{BatchNumber: from, BatchNumberFinal: to, StoredAt: obj.LastModified}
+		}
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+// DeleteOlderThan deletes the artifacts stored before the provided date, it
+// returns the number of artifacts deleted
+func (s *Storage) DeleteOlderThan(ctx context.Context, date time.Time) (int, error) {
+	deleted := 0
+	for _, dir := range []string{proverInputsDir, finalProofsDir} {
+		objects, err := s.store.list(ctx, s.key(dir)+"/")
+		if err != nil {
+			return deleted, err
+		}
+		for _, obj := range objects {
+			if !obj.LastModified.Before(date) {
+				continue
+			}
+			if err := s.store.delete(ctx, obj.Key); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Start applies periodically the retention policy until the context is done
+func (s *Storage) Start(ctx context.Context) {
+	if s.cfg.RetentionPeriod.Duration == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.cfg.RetentionCheckInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := s.DeleteOlderThan(ctx, time.Now().Add(-s.cfg.RetentionPeriod.Duration))
+			if err != nil {
+				log.Errorf("failed to delete expired prover artifacts: %v", err)
+			}
+			if deleted > 0 {
+				log.Infof("%d expired prover artifacts deleted", deleted)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Storage) proverInputKey(batchNumber uint64) string {
+	return s.key(proverInputsDir, fmt.Sprintf("%020d.json", batchNumber))
+}
+
+func (s *Storage) finalProofKey(batchNumber, batchNumberFinal uint64) string {
+	return s.key(finalProofsDir, fmt.Sprintf("%020d-%020d.json", batchNumber, batchNumberFinal))
+}
+
+func (s *Storage) key(elems ...string) string {
+	prefix := strings.Trim(s.cfg.Prefix, "/")
+	if prefix == "" {
+		return path.Join(elems...)
+	}
+	return path.Join(append([]string{prefix}, elems...)...)
+}
@@ -0,0 +1,111 @@
+package artifacts
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeObjectStorage is an in memory implementation of the subset of the S3 API used by the storage
+type fakeObjectStorage struct {
+	mutex   sync.Mutex
+	objects map[string][]byte
+	dates   map[string]time.Time
+}
+
+func (f *fakeObjectStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), sigV4Algorithm) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		var resp listObjectsResponse
+		keys := make([]string, 0, len(f.objects))
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			resp.Contents = append(resp.Contents, object{Key: k, LastModified: f.dates[k], Size: int64(len(f.objects[k]))})
+		}
+		b, _ := xml.Marshal(resp)
+		_, _ = w.Write(b)
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+		f.dates[key] = time.Now()
+	case r.Method == http.MethodGet:
+		data, found := f.objects[key]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		delete(f.dates, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeObjectStorage{objects: map[string][]byte{}, dates: map[string]time.Time{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	storage, err := New(Config{
+		Endpoint:       srv.URL,
+		Region:         "auto",
+		Bucket:         "bucket",
+		Prefix:         "/mainnet/",
+		AccessKeyID:    "key",
+		RequestTimeout: types.NewDuration(time.Second),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, storage.PutProverInput(ctx, 7, []byte(`{"batch":7}`)))
+	require.NoError(t, storage.PutFinalProof(ctx, 5, 9, []byte(`{"proof":"5-9"}`)))
+	assert.Contains(t, fake.objects, "mainnet/prover-inputs/00000000000000000007.json")
+
+	input, err := storage.GetProverInput(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, `{"batch":7}`, string(input))
+	_, err = storage.GetProverInput(ctx, 8)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	info, err := storage.FindFinalProof(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), info.BatchNumber)
+	assert.Equal(t, uint64(9), info.BatchNumberFinal)
+	proof, err := storage.GetFinalProof(ctx, info.BatchNumber, info.BatchNumberFinal)
+	require.NoError(t, err)
+	assert.Equal(t, `{"proof":"5-9"}`, string(proof))
+	_, err = storage.FindFinalProof(ctx, 10)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	fake.dates["mainnet/prover-inputs/00000000000000000007.json"] = time.Now().Add(-time.Hour)
+	deleted, err := storage.DeleteOlderThan(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Len(t, fake.objects, 1)
+}
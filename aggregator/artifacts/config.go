@@ -0,0 +1,39 @@
+package artifacts
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+// Config represents the configuration of the storage of the prover artifacts
+// (prover inputs and final proofs) in an S3 compatible object storage
+type Config struct {
+	// Enabled indicates if the prover artifacts are uploaded to the object storage
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Endpoint is the URL of the S3 compatible API of the object storage,
+	// e.g. https://s3.us-east-1.amazonaws.com or https://storage.googleapis.com
+	Endpoint string `mapstructure:"Endpoint"`
+
+	// Region is the region of the bucket used to sign the requests, use "auto" for GCS
+	Region string `mapstructure:"Region"`
+
+	// Bucket is the name of the bucket where the artifacts are stored
+	Bucket string `mapstructure:"Bucket"`
+
+	// Prefix is prepended to the key of all the artifacts, it allows sharing a bucket
+	Prefix string `mapstructure:"Prefix"`
+
+	// AccessKeyID is the access key used to sign the requests (HMAC key for GCS)
+	AccessKeyID string `mapstructure:"AccessKeyID"`
+
+	// SecretAccessKey is the secret of the access key
+	SecretAccessKey string `mapstructure:"SecretAccessKey"`
+
+	// RequestTimeout is the timeout of each request sent to the object storage
+	RequestTimeout types.Duration `mapstructure:"RequestTimeout"`
+
+	// RetentionPeriod is the time the artifacts are kept in the object storage.
+	// 0 means they are kept forever
+	RetentionPeriod types.Duration `mapstructure:"RetentionPeriod"`
+
+	// RetentionCheckInterval is the interval to delete the artifacts older than the retention period
+	RetentionCheckInterval types.Duration `mapstructure:"RetentionCheckInterval"`
+}
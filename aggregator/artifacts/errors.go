@@ -0,0 +1,10 @@
+package artifacts
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when the requested artifact doesn't exist in the object storage
+	ErrNotFound = errors.New("artifact not found")
+	// ErrBucketNotConfigured is returned when the object storage is enabled without a bucket
+	ErrBucketNotConfigured = errors.New("object storage bucket is not configured")
+)
@@ -0,0 +1,162 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// object is an object stored in the bucket
+type object struct {
+	Key          string    `xml:"Key"`
+	LastModified time.Time `xml:"LastModified"`
+	Size         int64     `xml:"Size"`
+}
+
+type listObjectsResponse struct {
+	Contents              []object `xml:"Contents"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+}
+
+// objectStore is a minimal client of the S3 API, it's compatible with any storage
+// implementing it like AWS S3, GCS (interoperability API) or MinIO. The bucket is
+// always addressed using the path style
+type objectStore struct {
+	endpoint   *url.URL
+	bucket     string
+	signer     signer
+	httpClient *http.Client
+}
+
+func newObjectStore(cfg Config) (*objectStore, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object storage endpoint %s: %w", cfg.Endpoint, err)
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid object storage endpoint %s: scheme and host are required", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, ErrBucketNotConfigured
+	}
+
+	return &objectStore{
+		endpoint: endpoint,
+		bucket:   cfg.Bucket,
+		signer: signer{
+			accessKeyID:     cfg.AccessKeyID,
+			secretAccessKey: cfg.SecretAccessKey,
+			region:          cfg.Region,
+		},
+		httpClient: &http.Client{Timeout: cfg.RequestTimeout.Duration},
+	}, nil
+}
+
+// put uploads an object
+func (s *objectStore) put(ctx context.Context, key string, data []byte, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, nil, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	_, err = s.do(req, data)
+	return err
+}
+
+// get downloads an object, it returns ErrNotFound if it doesn't exist
+func (s *objectStore) get(ctx context.Context, key string) ([]byte, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.do(req, nil)
+}
+
+// delete deletes an object, deleting an object that doesn't exist is not an error
+func (s *objectStore) delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.do(req, nil)
+	return err
+}
+
+// list returns all the objects whose key starts with the prefix
+func (s *objectStore) list(ctx context.Context, prefix string) ([]object, error) {
+	var (
+		objects           []object
+		continuationToken string
+	)
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		req, err := s.newRequest(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := s.do(req, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp listObjectsResponse
+		if err := xml.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("failed to decode list objects response: %w", err)
+		}
+		objects = append(objects, resp.Contents...)
+
+		if !resp.IsTruncated || resp.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = resp.NextContinuationToken
+	}
+}
+
+func (s *objectStore) newRequest(ctx context.Context, method, key string, query url.Values, data []byte) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (s *objectStore) do(req *http.Request, data []byte) ([]byte, error) {
+	s.signer.sign(req, hashHex(data), time.Now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("object storage %s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, string(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,139 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/aggregator/prover"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+var (
+	// ErrProverForkIDNotSupported is returned when the prover doesn't support the fork ID required by the aggregator
+	ErrProverForkIDNotSupported = errors.New("prover does not support required fork ID")
+	// ErrProverVersionNotSupported is returned when the protocol version reported by the prover is not accepted by the aggregator
+	ErrProverVersionNotSupported = errors.New("prover protocol version is not supported")
+)
+
+// checkProverCompatibility checks the fork ID and the protocol version reported
+// by the prover when it connects against the ones required by the aggregator
+func (a *Aggregator) checkProverCompatibility(info prover.Info) error {
+	if info.ForkID != a.cfg.ForkId {
+		return fmt.Errorf("%w, required %d, prover %d", ErrProverForkIDNotSupported, a.cfg.ForkId, info.ForkID)
+	}
+
+	if len(a.cfg.ProverVersionsProto) == 0 {
+		return nil
+	}
+	for _, version := range a.cfg.ProverVersionsProto {
+		if version == info.VersionProto {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w, accepted %v, prover %s", ErrProverVersionNotSupported, a.cfg.ProverVersionsProto, info.VersionProto)
+}
+
+// logProverIncompatibleEvent logs the event for a prover rejected because it's not compatible
+func (a *Aggregator) logProverIncompatibleEvent(ctx context.Context, info prover.Info, err error) {
+	if a.eventLog == nil {
+		return
+	}
+
+	ev := &event.Event{
+		ReceivedAt:  time.Now(),
+		IPAddress:   info.Addr,
+		Source:      event.Source_Node,
+		Component:   event.Component_Aggregator,
+		Level:       event.Level_Warning,
+		EventID:     event.EventID_ProverIncompatible,
+		Description: fmt.Sprintf("prover %s (%s) rejected: %v. Versions: proto %s, server %s, fork ID %d", info.Name, info.ID, err, info.VersionProto, info.VersionServer, info.ForkID),
+	}
+	if err := a.eventLog.LogEvent(ctx, ev); err != nil {
+		log.Errorf("error storing event: %v", err)
+	}
+}
+
+func (a *Aggregator) addConnectedProver(info prover.Info) {
+	a.connectedProversMutex.Lock()
+	defer a.connectedProversMutex.Unlock()
+	info.ConnectedAt = time.Now()
+	a.connectedProvers[info.ID] = info
+}
+
+func (a *Aggregator) removeConnectedProver(info prover.Info) {
+	a.connectedProversMutex.Lock()
+	defer a.connectedProversMutex.Unlock()
+	delete(a.connectedProvers, info.ID)
+}
+
+// updateConnectedProver applies the update to the tracked info of the prover, if it's connected
+func (a *Aggregator) updateConnectedProver(proverID string, update func(info *prover.Info)) {
+	a.connectedProversMutex.Lock()
+	defer a.connectedProversMutex.Unlock()
+	info, found := a.connectedProvers[proverID]
+	if !found {
+		return
+	}
+	update(&info)
+	a.connectedProvers[proverID] = info
+}
+
+// recordProverStatus tracks the result of a status request to the prover and
+// returns the number of consecutive failed requests
+func (a *Aggregator) recordProverStatus(proverID string, err error) int {
+	var failures int
+	a.updateConnectedProver(proverID, func(info *prover.Info) {
+		if err != nil {
+			info.StatusFailures++
+		} else {
+			info.StatusFailures = 0
+		}
+		failures = info.StatusFailures
+	})
+	return failures
+}
+
+// startProvingBatch tracks the batch whose proof the prover starts generating
+func (a *Aggregator) startProvingBatch(proverID string, batchNumber uint64) {
+	a.updateConnectedProver(proverID, func(info *prover.Info) {
+		info.ProvingBatch = batchNumber
+	})
+}
+
+// endProvingBatch tracks the result of the batch proof generated by the prover
+func (a *Aggregator) endProvingBatch(proverID string, provingTime time.Duration, err error) {
+	a.updateConnectedProver(proverID, func(info *prover.Info) {
+		info.ProvingBatch = 0
+		if err != nil {
+			info.BatchProofsFailed++
+			return
+		}
+		info.BatchProofsGenerated++
+		// running average of the proving times
+		info.AvgBatchProvingTimeMs = (info.AvgBatchProvingTimeMs*(info.BatchProofsGenerated-1) + uint64(provingTime.Milliseconds())) / info.BatchProofsGenerated
+	})
+}
+
+// ConnectedProvers returns the identity, the negotiated versions, the health and the
+// throughput of the provers currently connected to the aggregator, sorted by name
+func (a *Aggregator) ConnectedProvers() []prover.Info {
+	a.connectedProversMutex.RLock()
+	defer a.connectedProversMutex.RUnlock()
+
+	provers := make([]prover.Info, 0, len(a.connectedProvers))
+	for _, info := range a.connectedProvers {
+		provers = append(provers, info)
+	}
+	sort.Slice(provers, func(i, j int) bool {
+		if provers[i].Name == provers[j].Name {
+			return provers[i].ID < provers[j].ID
+		}
+		return provers[i].Name < provers[j].Name
+	})
+
+	return provers
+}
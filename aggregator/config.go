@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/0xPolygonHermez/zkevm-node/aggregator/artifacts"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 )
@@ -62,6 +63,11 @@ type Config struct {
 	// ForkID is the L2 ForkID provided by the Network Config
 	ForkId uint64
 
+	// ProverVersionsProto is the list of protocol versions reported by the provers that are
+	// accepted by the aggregator, provers reporting any other version are rejected when they
+	// connect. If empty, any protocol version is accepted
+	ProverVersionsProto []string `mapstructure:"ProverVersionsProto"`
+
 	// SenderAddress defines which private key the eth tx manager needs to use
 	// to sign the L1 txs
 	SenderAddress string `mapstructure:"SenderAddress"`
@@ -74,6 +80,19 @@ type Config struct {
 	// allowed to be cleared.
 	GeneratingProofCleanupThreshold string `mapstructure:"GeneratingProofCleanupThreshold"`
 
+	// BatchProofTimeout is the max time a prover can take to generate a batch proof, once reached the
+	// batch is released to be proven by another prover. 0 disables the timeout
+	BatchProofTimeout types.Duration `mapstructure:"BatchProofTimeout"`
+
+	// MaxProverStatusFailures is the number of consecutive failed status requests after which a prover
+	// is disconnected, so it can reconnect with a healthy stream. 0 disables the limit
+	MaxProverStatusFailures int `mapstructure:"MaxProverStatusFailures"`
+
+	// MaxAggregatedBatches is the max number of batches covered by a recursive proof, so it's the
+	// max number of batches verified by a single verifyBatches tx in L1. The consecutive proofs are
+	// not aggregated when the result would exceed it. 0 means no limit
+	MaxAggregatedBatches uint64 `mapstructure:"MaxAggregatedBatches"`
+
 	// GasOffset is the amount of gas to be added to the gas estimation in order
 	// to provide an amount that is higher than the estimated one. This is used
 	// to avoid the TX getting reverted in case something has changed in the network
@@ -85,4 +104,38 @@ type Config struct {
 	// gas offset: 100
 	// final gas: 1100
 	GasOffset uint64 `mapstructure:"GasOffset"`
+
+	// CheckBatchDataIntegrity enables the check of the stored data of the sequence of each batch
+	// against the accumulated input hashes stored in L1 before proving it
+	CheckBatchDataIntegrity bool `mapstructure:"CheckBatchDataIntegrity"`
+
+	// FallbackProvers is the configuration of the fallback prover pool
+	FallbackProvers FallbackProversConfig `mapstructure:"FallbackProvers"`
+
+	// Artifacts is the configuration of the upload of the prover inputs and
+	// final proofs to an S3 compatible object storage
+	Artifacts artifacts.Config `mapstructure:"Artifacts"`
+}
+
+// FallbackProversConfig represents the configuration of the fallback prover pool (e.g. cloud burst
+// provers), these provers are only used when the primary pool is falling behind
+type FallbackProversConfig struct {
+	// Enabled indicates if the fallback prover pool is enabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// ProverNames are the names of the provers that belong to the fallback pool,
+	// the provers not included in the list belong to the primary pool
+	ProverNames []string `mapstructure:"ProverNames"`
+
+	// MaxQueueDepth is the number of virtual batches pending to be verified above which
+	// the fallback provers are used. 0 disables this threshold
+	MaxQueueDepth uint64 `mapstructure:"MaxQueueDepth"`
+
+	// MaxPendingBatchAge is the age of the oldest virtual batch pending to be proved above which
+	// the fallback provers are used. 0 disables this threshold
+	MaxPendingBatchAge types.Duration `mapstructure:"MaxPendingBatchAge"`
+
+	// MaxConcurrentProofs is the max number of batch proofs generated at the same time by the
+	// fallback provers, it limits the cost of the fallback pool. 0 means no limit
+	MaxConcurrentProofs int `mapstructure:"MaxConcurrentProofs"`
 }
@@ -0,0 +1,97 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/aggregator/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// isFallbackProver returns true if the prover belongs to the fallback prover pool
+func (a *Aggregator) isFallbackProver(prover proverInterface) bool {
+	if !a.cfg.FallbackProvers.Enabled {
+		return false
+	}
+	_, found := a.fallbackProverNames[prover.Name()]
+	return found
+}
+
+// shouldEscalateToFallback returns true if the primary prover pool is falling behind, this is
+// when the number of virtual batches pending to be proved or the age of the oldest one exceed
+// the configured thresholds
+func (a *Aggregator) shouldEscalateToFallback(ctx context.Context) (bool, error) {
+	cfg := a.cfg.FallbackProvers
+
+	lastVerifiedBatch, err := a.State.GetLastVerifiedBatch(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if cfg.MaxQueueDepth > 0 {
+		lastVirtualBatchNum, err := a.State.GetLastVirtualBatchNum(ctx, nil)
+		if err != nil {
+			return false, err
+		}
+		if lastVirtualBatchNum > lastVerifiedBatch.BatchNumber && lastVirtualBatchNum-lastVerifiedBatch.BatchNumber > cfg.MaxQueueDepth {
+			log.Debugf("escalating to fallback provers, pending batches %d exceed max queue depth %d",
+				lastVirtualBatchNum-lastVerifiedBatch.BatchNumber, cfg.MaxQueueDepth)
+			return true, nil
+		}
+	}
+
+	if cfg.MaxPendingBatchAge.Duration > 0 {
+		batchToProve, err := a.State.GetVirtualBatchToProve(ctx, lastVerifiedBatch.BatchNumber, nil)
+		if errors.Is(err, state.ErrNotFound) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		if age := time.Since(batchToProve.Timestamp); age > cfg.MaxPendingBatchAge.Duration {
+			log.Debugf("escalating to fallback provers, oldest pending batch %d age %v exceeds max pending batch age %v",
+				batchToProve.BatchNumber, age, cfg.MaxPendingBatchAge.Duration)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// tryGenerateFallbackBatchProof generates a batch proof with a fallback prover only if the primary
+// prover pool is falling behind and the max number of concurrent fallback proofs has not been reached.
+// Fallback provers only generate batch proofs, the cheaper aggregations and final proofs are left to
+// the primary pool
+func (a *Aggregator) tryGenerateFallbackBatchProof(ctx context.Context, prover proverInterface) (bool, error) {
+	escalate, err := a.shouldEscalateToFallback(ctx)
+	metrics.FallbackEscalated(escalate)
+	if err != nil || !escalate {
+		return false, err
+	}
+
+	if !a.acquireFallbackSlot() {
+		log.Debugf("max concurrent fallback proofs %d reached", a.cfg.FallbackProvers.MaxConcurrentProofs)
+		return false, nil
+	}
+	defer a.releaseFallbackSlot()
+
+	return a.tryGenerateBatchProof(ctx, prover)
+}
+
+func (a *Aggregator) acquireFallbackSlot() bool {
+	a.fallbackProofsMutex.Lock()
+	defer a.fallbackProofsMutex.Unlock()
+
+	if a.cfg.FallbackProvers.MaxConcurrentProofs > 0 && a.fallbackProofs >= a.cfg.FallbackProvers.MaxConcurrentProofs {
+		return false
+	}
+	a.fallbackProofs++
+	return true
+}
+
+func (a *Aggregator) releaseFallbackSlot() {
+	a.fallbackProofsMutex.Lock()
+	defer a.fallbackProofsMutex.Unlock()
+	a.fallbackProofs--
+}
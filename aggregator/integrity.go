@@ -0,0 +1,80 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// checkBatchDataIntegrity checks the stored data of the sequence including the batch against the
+// accumulated input hashes stored in L1, so a batch whose data doesn't match what was sequenced
+// in L1 is never proved
+func (a *Aggregator) checkBatchDataIntegrity(ctx context.Context, batchNumber uint64) error {
+	if !a.cfg.CheckBatchDataIntegrity || batchNumber <= a.integrityCheckedUntil {
+		return nil
+	}
+
+	sequences, err := a.State.GetSequencesInRange(ctx, batchNumber, batchNumber, nil)
+	if err != nil {
+		return err
+	}
+	if len(sequences) == 0 {
+		return fmt.Errorf("sequence of batch %d not found", batchNumber)
+	}
+	sequence := sequences[0]
+
+	l1Info, err := a.Ethman.GetSequencedBatchesInfo(ctx, sequence.ToBatchNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get the L1 data of the sequence ending in batch %d, %w", sequence.ToBatchNumber, err)
+	}
+	if l1Info.PreviousLastBatchSequenced+1 != sequence.FromBatchNumber {
+		err = fmt.Errorf("%w: sequence [%d, %d] starts at batch %d in L1", state.ErrBatchDataIntegrity,
+			sequence.FromBatchNumber, sequence.ToBatchNumber, l1Info.PreviousLastBatchSequenced+1)
+		a.logBatchDataIntegrityEvent(err)
+		return err
+	}
+
+	oldL1Info, err := a.Ethman.GetSequencedBatchesInfo(ctx, l1Info.PreviousLastBatchSequenced)
+	if err != nil {
+		return fmt.Errorf("failed to get the L1 data of the sequence ending in batch %d, %w", l1Info.PreviousLastBatchSequenced, err)
+	}
+
+	integrity, err := a.State.CheckSequenceDataIntegrity(ctx, sequence, oldL1Info.AccInputHash, l1Info.AccInputHash, nil)
+	if err != nil {
+		return err
+	}
+	if !integrity.IsValid() {
+		err = fmt.Errorf("%w: sequence [%d, %d], L1 acc input hash %s, computed acc input hash %s, mismatched batches %v", state.ErrBatchDataIntegrity,
+			integrity.FromBatchNumber, integrity.ToBatchNumber, integrity.L1AccInputHash, integrity.AccInputHash, integrity.MismatchedBatches)
+		a.logBatchDataIntegrityEvent(err)
+		return err
+	}
+
+	log.Debugf("Data integrity of sequence [%d, %d] checked", sequence.FromBatchNumber, sequence.ToBatchNumber)
+	a.integrityCheckedUntil = sequence.ToBatchNumber
+
+	return nil
+}
+
+// logBatchDataIntegrityEvent logs the event for a sequence whose stored data doesn't match L1
+func (a *Aggregator) logBatchDataIntegrityEvent(err error) {
+	if a.eventLog == nil {
+		return
+	}
+
+	ev := &event.Event{
+		ReceivedAt:  time.Now(),
+		Source:      event.Source_Node,
+		Component:   event.Component_Aggregator,
+		Level:       event.Level_Critical,
+		EventID:     event.EventID_BatchDataIntegrityMismatch,
+		Description: err.Error(),
+	}
+	if err := a.eventLog.LogEvent(context.Background(), ev); err != nil {
+		log.Errorf("error storing event: %v", err)
+	}
+}
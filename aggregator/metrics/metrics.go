@@ -9,6 +9,21 @@ const (
 	prefix                      = "aggregator_"
 	currentConnectedProversName = prefix + "current_connected_provers"
 	currentWorkingProversName   = prefix + "current_working_provers"
+	batchProofsGeneratedName    = prefix + "batch_proofs_generated"
+	fallbackEscalatedName       = prefix + "fallback_escalated"
+
+	proverPoolLabelName = "pool"
+)
+
+// ProverPoolLabel represents the possible values for the
+// `aggregator_batch_proofs_generated` metric `pool` label.
+type ProverPoolLabel string
+
+const (
+	// ProverPoolPrimary represents a proof generated by the primary prover pool
+	ProverPoolPrimary ProverPoolLabel = "primary"
+	// ProverPoolFallback represents a proof generated by the fallback prover pool
+	ProverPoolFallback ProverPoolLabel = "fallback"
 )
 
 // Register the metrics for the sequencer package.
@@ -22,9 +37,24 @@ func Register() {
 			Name: currentWorkingProversName,
 			Help: "[AGGREGATOR] current working provers",
 		},
+		{
+			Name: fallbackEscalatedName,
+			Help: "[AGGREGATOR] 1 if the batch proofs are being escalated to the fallback prover pool, 0 otherwise",
+		},
+	}
+
+	counterVecs := []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: batchProofsGeneratedName,
+				Help: "[AGGREGATOR] number of batch proofs generated by prover pool",
+			},
+			Labels: []string{proverPoolLabelName},
+		},
 	}
 
 	metrics.RegisterGauges(gauges...)
+	metrics.RegisterCounterVecs(counterVecs...)
 }
 
 // ConnectedProver increments the gauge for the current number of connected
@@ -49,3 +79,19 @@ func WorkingProver() {
 func IdlingProver() {
 	metrics.GaugeDec(currentWorkingProversName)
 }
+
+// BatchProofGenerated increments the batch proofs generated counter vector by
+// one for the given prover pool.
+func BatchProofGenerated(pool ProverPoolLabel) {
+	metrics.CounterVecInc(batchProofsGeneratedName, string(pool))
+}
+
+// FallbackEscalated sets the gauge that indicates if the batch proofs are
+// being escalated to the fallback prover pool.
+func FallbackEscalated(escalated bool) {
+	value := float64(0)
+	if escalated {
+		value = 1
+	}
+	metrics.GaugeSet(fallbackEscalatedName, value)
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	common "github.com/ethereum/go-ethereum/common"
 	mock "github.com/stretchr/testify/mock"
 
@@ -73,6 +75,30 @@ func (_m *Etherman) GetLatestVerifiedBatchNum() (uint64, error) {
 	return r0, r1
 }
 
+// GetSequencedBatchesInfo provides a mock function with given fields: ctx, lastBatchNumber
+func (_m *Etherman) GetSequencedBatchesInfo(ctx context.Context, lastBatchNumber uint64) (types.SequencedBatchesInfo, error) {
+	ret := _m.Called(ctx, lastBatchNumber)
+
+	var r0 types.SequencedBatchesInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (types.SequencedBatchesInfo, error)); ok {
+		return rf(ctx, lastBatchNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) types.SequencedBatchesInfo); ok {
+		r0 = rf(ctx, lastBatchNumber)
+	} else {
+		r0 = ret.Get(0).(types.SequencedBatchesInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, lastBatchNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewEtherman creates a new instance of Etherman. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewEtherman(t interface {
@@ -5,6 +5,10 @@ package mocks
 import (
 	context "context"
 
+	common "github.com/ethereum/go-ethereum/common"
+
+	time "time"
+
 	pgx "github.com/jackc/pgx/v4"
 	mock "github.com/stretchr/testify/mock"
 
@@ -16,6 +20,20 @@ type StateMock struct {
 	mock.Mock
 }
 
+// AddBatchProvingTime provides a mock function with given fields: ctx, batchNumber, prover, duration, dbTx
+func (_m *StateMock) AddBatchProvingTime(ctx context.Context, batchNumber uint64, prover *string, duration time.Duration, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, batchNumber, prover, duration, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, *string, time.Duration, pgx.Tx) error); ok {
+		r0 = rf(ctx, batchNumber, prover, duration, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddGeneratedProof provides a mock function with given fields: ctx, proof, dbTx
 func (_m *StateMock) AddGeneratedProof(ctx context.Context, proof *state.Proof, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, proof, dbTx)
@@ -30,6 +48,20 @@ func (_m *StateMock) AddGeneratedProof(ctx context.Context, proof *state.Proof,
 	return r0
 }
 
+// AddVerifiedBatchProof provides a mock function with given fields: ctx, proof, dbTx
+func (_m *StateMock) AddVerifiedBatchProof(ctx context.Context, proof *state.VerifiedBatchProof, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, proof, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *state.VerifiedBatchProof, pgx.Tx) error); ok {
+		r0 = rf(ctx, proof, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BeginStateTransaction provides a mock function with given fields: ctx
 func (_m *StateMock) BeginStateTransaction(ctx context.Context) (pgx.Tx, error) {
 	ret := _m.Called(ctx)
@@ -80,6 +112,32 @@ func (_m *StateMock) CheckProofContainsCompleteSequences(ctx context.Context, pr
 	return r0, r1
 }
 
+// CheckSequenceDataIntegrity provides a mock function with given fields: ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx
+func (_m *StateMock) CheckSequenceDataIntegrity(ctx context.Context, sequence state.Sequence, oldAccInputHash common.Hash, l1AccInputHash common.Hash, dbTx pgx.Tx) (*state.SequenceDataIntegrity, error) {
+	ret := _m.Called(ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx)
+
+	var r0 *state.SequenceDataIntegrity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, state.Sequence, common.Hash, common.Hash, pgx.Tx) (*state.SequenceDataIntegrity, error)); ok {
+		return rf(ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, state.Sequence, common.Hash, common.Hash, pgx.Tx) *state.SequenceDataIntegrity); ok {
+		r0 = rf(ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.SequenceDataIntegrity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, state.Sequence, common.Hash, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CleanupGeneratedProofs provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StateMock) CleanupGeneratedProofs(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -198,6 +256,30 @@ func (_m *StateMock) GetLastVerifiedBatch(ctx context.Context, dbTx pgx.Tx) (*st
 	return r0, r1
 }
 
+// GetLastVirtualBatchNum provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetLastVirtualBatchNum(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProofReadyToVerify provides a mock function with given fields: ctx, lastVerfiedBatchNumber, dbTx
 func (_m *StateMock) GetProofReadyToVerify(ctx context.Context, lastVerfiedBatchNumber uint64, dbTx pgx.Tx) (*state.Proof, error) {
 	ret := _m.Called(ctx, lastVerfiedBatchNumber, dbTx)
@@ -224,34 +306,34 @@ func (_m *StateMock) GetProofReadyToVerify(ctx context.Context, lastVerfiedBatch
 	return r0, r1
 }
 
-// GetProofsToAggregate provides a mock function with given fields: ctx, dbTx
-func (_m *StateMock) GetProofsToAggregate(ctx context.Context, dbTx pgx.Tx) (*state.Proof, *state.Proof, error) {
-	ret := _m.Called(ctx, dbTx)
+// GetProofsToAggregate provides a mock function with given fields: ctx, maxBatches, dbTx
+func (_m *StateMock) GetProofsToAggregate(ctx context.Context, maxBatches uint64, dbTx pgx.Tx) (*state.Proof, *state.Proof, error) {
+	ret := _m.Called(ctx, maxBatches, dbTx)
 
 	var r0 *state.Proof
 	var r1 *state.Proof
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (*state.Proof, *state.Proof, error)); ok {
-		return rf(ctx, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.Proof, *state.Proof, error)); ok {
+		return rf(ctx, maxBatches, dbTx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) *state.Proof); ok {
-		r0 = rf(ctx, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.Proof); ok {
+		r0 = rf(ctx, maxBatches, dbTx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*state.Proof)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) *state.Proof); ok {
-		r1 = rf(ctx, dbTx)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) *state.Proof); ok {
+		r1 = rf(ctx, maxBatches, dbTx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*state.Proof)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, pgx.Tx) error); ok {
-		r2 = rf(ctx, dbTx)
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r2 = rf(ctx, maxBatches, dbTx)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -259,6 +341,32 @@ func (_m *StateMock) GetProofsToAggregate(ctx context.Context, dbTx pgx.Tx) (*st
 	return r0, r1, r2
 }
 
+// GetSequencesInRange provides a mock function with given fields: ctx, fromBatchNumber, toBatchNumber, dbTx
+func (_m *StateMock) GetSequencesInRange(ctx context.Context, fromBatchNumber uint64, toBatchNumber uint64, dbTx pgx.Tx) ([]state.Sequence, error) {
+	ret := _m.Called(ctx, fromBatchNumber, toBatchNumber, dbTx)
+
+	var r0 []state.Sequence
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) ([]state.Sequence, error)); ok {
+		return rf(ctx, fromBatchNumber, toBatchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []state.Sequence); ok {
+		r0 = rf(ctx, fromBatchNumber, toBatchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.Sequence)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, fromBatchNumber, toBatchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVirtualBatchToProve provides a mock function with given fields: ctx, lastVerfiedBatchNumber, dbTx
 func (_m *StateMock) GetVirtualBatchToProve(ctx context.Context, lastVerfiedBatchNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
 	ret := _m.Called(ctx, lastVerfiedBatchNumber, dbTx)
@@ -22,10 +22,33 @@ var (
 	ErrProofCanceled        = errors.New("Proof has been canceled")                  //nolint:revive
 )
 
+// Info contains the identity and the versions reported by a prover when it connects,
+// along with the health and the throughput tracked by the aggregator since then.
+type Info struct {
+	Name          string `json:"name"`
+	ID            string `json:"id"`
+	Addr          string `json:"addr"`
+	VersionProto  string `json:"versionProto"`
+	VersionServer string `json:"versionServer"`
+	ForkID        uint64 `json:"forkId"`
+
+	ConnectedAt time.Time `json:"connectedAt"`
+	// StatusFailures is the number of consecutive status requests that failed, the prover is healthy if it's 0
+	StatusFailures int `json:"statusFailures"`
+	// ProvingBatch is the batch whose proof the prover is generating, 0 if none
+	ProvingBatch          uint64 `json:"provingBatch"`
+	BatchProofsGenerated  uint64 `json:"batchProofsGenerated"`
+	BatchProofsFailed     uint64 `json:"batchProofsFailed"`
+	AvgBatchProvingTimeMs uint64 `json:"avgBatchProvingTimeMs"`
+}
+
 // Prover abstraction of the grpc prover client.
 type Prover struct {
 	name                      string
 	id                        string
+	versionProto              string
+	versionServer             string
+	forkID                    uint64
 	address                   net.Addr
 	proofStatePollingInterval types.Duration
 	stream                    AggregatorService_ChannelServer
@@ -44,6 +67,9 @@ func New(stream AggregatorService_ChannelServer, addr net.Addr, proofStatePollin
 	}
 	p.name = status.ProverName
 	p.id = status.ProverId
+	p.versionProto = status.VersionProto
+	p.versionServer = status.VersionServer
+	p.forkID = status.ForkId
 	return p, nil
 }
 
@@ -53,6 +79,18 @@ func (p *Prover) Name() string { return p.name }
 // ID returns the Prover ID.
 func (p *Prover) ID() string { return p.id }
 
+// Info returns the identity and the versions reported by the prover when it connected.
+func (p *Prover) Info() Info {
+	return Info{
+		Name:          p.name,
+		ID:            p.id,
+		Addr:          p.Addr(),
+		VersionProto:  p.versionProto,
+		VersionServer: p.versionServer,
+		ForkID:        p.forkID,
+	}
+}
+
 // Addr returns the prover IP address.
 func (p *Prover) Addr() string {
 	if p.address == nil {
@@ -0,0 +1,45 @@
+package aggregator
+
+import (
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/aggregator/prover"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// storeProverInput uploads in background the input sent to the prover to
+// generate the proof of a batch, if the artifacts storage is enabled
+func (a *Aggregator) storeProverInput(batchNumber uint64, input []byte) {
+	if a.artifacts == nil {
+		return
+	}
+
+	go func() {
+		if err := a.artifacts.PutProverInput(a.ctx, batchNumber, input); err != nil {
+			log.Errorf("failed to store prover input of batch %d: %v", batchNumber, err)
+		}
+	}()
+}
+
+// storeFinalProof uploads in background the final proof of a range of
+// batches, if the artifacts storage is enabled
+func (a *Aggregator) storeFinalProof(proof *state.Proof, finalProof *prover.FinalProof) {
+	if a.artifacts == nil {
+		return
+	}
+
+	batches := fmt.Sprintf("%d-%d", proof.BatchNumber, proof.BatchNumberFinal)
+	b, err := protojson.Marshal(finalProof)
+	if err != nil {
+		log.Errorf("failed to serialize final proof of batches %s: %v", batches, err)
+		return
+	}
+
+	go func() {
+		if err := a.artifacts.PutFinalProof(a.ctx, proof.BatchNumber, proof.BatchNumberFinal, b); err != nil {
+			log.Errorf("failed to store final proof of batches %s: %v", batches, err)
+		}
+	}()
+}
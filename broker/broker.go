@@ -0,0 +1,250 @@
+// Package broker contains the internal event broker publishing the new L2 blocks, their
+// receipts and the batch status transitions to pluggable sinks, like the WebSocket
+// subscriptions of the JSON-RPC server or the gRPC broadcast stream
+package broker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// batchStatusOverlap is how far back the batch status transitions are queried again in each
+// poll, so the transitions stored by db txs committed after later ones are still published
+const batchStatusOverlap = time.Minute
+
+// Sink receives the events published by the broker
+type Sink interface {
+	// Name identifies the sink in the logs
+	Name() string
+	// Send delivers an event to the sink, the events of a sink are sent in order from a
+	// single goroutine
+	Send(event Event) error
+}
+
+// FuncSink is a Sink calling a func for each event, used by the in-process consumers
+type FuncSink struct {
+	name string
+	f    func(event Event) error
+}
+
+// NewFuncSink creates a FuncSink
+func NewFuncSink(name string, f func(event Event) error) *FuncSink {
+	return &FuncSink{name: name, f: f}
+}
+
+// Name returns the name of the sink
+func (s *FuncSink) Name() string {
+	return s.name
+}
+
+// Send calls the func of the sink with the event
+func (s *FuncSink) Send(event Event) error {
+	return s.f(event)
+}
+
+// sinkQueue queues the events of a sink while they are sent
+type sinkQueue struct {
+	sink   Sink
+	events chan Event
+	done   chan struct{}
+}
+
+func (q *sinkQueue) run() {
+	for {
+		select {
+		case <-q.done:
+			return
+		case event := <-q.events:
+			if err := q.sink.Send(event); err != nil {
+				log.Warnf("failed to send %s event to sink %s: %v", event.Type, q.sink.Name(), err)
+			}
+		}
+	}
+}
+
+// Broker publishes the events of the new L2 blocks, receipts and batch status transitions
+// to the sinks added to it
+type Broker struct {
+	cfg   Config
+	state stateInterface
+
+	queues map[*sinkQueue]struct{}
+	mux    sync.RWMutex
+}
+
+// New creates a Broker
+func New(cfg Config, st stateInterface) *Broker {
+	return &Broker{
+		cfg:    cfg,
+		state:  st,
+		queues: map[*sinkQueue]struct{}{},
+	}
+}
+
+// Start starts publishing the events of the state and serving the gRPC broadcast stream
+// when enabled, until the context is done
+func (b *Broker) Start(ctx context.Context) {
+	b.state.RegisterNewL2BlockEventHandler(b.onNewL2Block)
+	b.state.StartToMonitorNewL2Blocks()
+	go b.pollBatchStatus(ctx)
+	if b.cfg.GRPC.Enabled {
+		go b.serveGRPC(ctx)
+	}
+}
+
+// AddSink adds a sink receiving the events published from now on, it returns the func
+// removing the sink
+func (b *Broker) AddSink(sink Sink) func() {
+	q := &sinkQueue{
+		sink:   sink,
+		events: make(chan Event, b.cfg.SinkBufferSize),
+		done:   make(chan struct{}),
+	}
+	b.mux.Lock()
+	b.queues[q] = struct{}{}
+	b.mux.Unlock()
+	go q.run()
+	log.Infof("broker sink %s added", sink.Name())
+
+	return func() {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+		if _, found := b.queues[q]; !found {
+			return
+		}
+		delete(b.queues, q)
+		close(q.done)
+		log.Infof("broker sink %s removed", sink.Name())
+	}
+}
+
+// Publish queues the event in all the sinks without blocking, the event is dropped for
+// the sinks whose queue is full
+func (b *Broker) Publish(event Event) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	for q := range b.queues {
+		select {
+		case q.events <- event:
+		default:
+			log.Warnf("queue of broker sink %s is full, %s event dropped", q.sink.Name(), event.Type)
+		}
+	}
+}
+
+func (b *Broker) hasSinks() bool {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return len(b.queues) > 0
+}
+
+// onNewL2Block publishes the new L2 block followed by the receipts of its txs
+func (b *Broker) onNewL2Block(e state.NewL2BlockEvent) {
+	if !b.hasSinks() {
+		return
+	}
+	block, err := types.NewBlock(&e.Block, nil, false, false)
+	if err != nil {
+		log.Errorf("failed to build the event of the l2 block %v: %v", e.Block.NumberU64(), err)
+		return
+	}
+	b.Publish(Event{Type: EventTypeL2Block, L2Block: block})
+
+	for _, tx := range e.Block.Transactions() {
+		receipt, err := b.state.GetTransactionReceipt(context.Background(), tx.Hash(), nil)
+		if err != nil {
+			log.Errorf("failed to get the receipt of the tx %v of the l2 block %v: %v", tx.Hash().String(), e.Block.NumberU64(), err)
+			continue
+		}
+		r, err := types.NewReceipt(*tx, receipt)
+		if err != nil {
+			log.Errorf("failed to build the event of the receipt of the tx %v: %v", tx.Hash().String(), err)
+			continue
+		}
+		b.Publish(Event{Type: EventTypeReceipt, Receipt: &r})
+	}
+}
+
+// OnTxSelected publishes the tx selected by the sequencer for the WIP batch, it's registered
+// as a handler of the sequencer so it doesn't block the finalizer
+func (b *Broker) OnTxSelected(e sequencer.TxSelectedEvent) {
+	if !b.hasSinks() {
+		return
+	}
+	tx := types.NewSelectedTransaction(e)
+	b.Publish(Event{Type: EventTypeTxSelected, TxSelected: &tx})
+}
+
+// OnL2Reorg publishes the txs reorged by the sequencer, so the sinks caching their receipts can
+// invalidate them. It's registered as a handler of the sequencer so it doesn't block the finalizer
+func (b *Broker) OnL2Reorg(e sequencer.L2ReorgEvent) {
+	if !b.hasSinks() {
+		return
+	}
+	txHashes := make([]common.Hash, len(e.TxHashes))
+	copy(txHashes, e.TxHashes)
+	b.Publish(Event{Type: EventTypeL2Reorg, ReorgedTxs: txHashes})
+}
+
+// batchStage identifies the transition of a batch to a stage
+type batchStage struct {
+	batchNumber uint64
+	stage       state.BatchLifecycleStage
+}
+
+// pollBatchStatus publishes the batch status transitions stored since the broker was started
+// every poll interval until the context is done
+func (b *Broker) pollBatchStatus(ctx context.Context) {
+	start := time.Now()
+	since := start
+	published := map[batchStage]time.Time{}
+
+	ticker := time.NewTicker(b.cfg.BatchStatusPollInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			since = b.publishBatchStatus(ctx, start, since, published)
+		}
+	}
+}
+
+// publishBatchStatus publishes the transitions stored after start that weren't already
+// published, returning the time of the last transition seen
+func (b *Broker) publishBatchStatus(ctx context.Context, start, since time.Time, published map[batchStage]time.Time) time.Time {
+	transitions, err := b.state.GetBatchLifecycleTransitionsSince(ctx, since.Add(-batchStatusOverlap), nil)
+	if err != nil {
+		log.Errorf("failed to get the batch status transitions since %v: %v", since, err)
+		return since
+	}
+
+	for _, t := range transitions {
+		key := batchStage{batchNumber: t.BatchNumber, stage: t.Stage}
+		if _, found := published[key]; found || !t.Timestamp.After(start) {
+			continue
+		}
+		published[key] = t.Timestamp
+		if t.Timestamp.After(since) {
+			since = t.Timestamp
+		}
+		status := types.NewBatchLifecycle(t.BatchNumber, []state.BatchLifecycleTransition{t})
+		b.Publish(Event{Type: EventTypeBatchStatus, BatchStatus: &status})
+	}
+
+	// the transitions out of the queried window can't be seen again
+	for key, timestamp := range published {
+		if timestamp.Before(since.Add(-batchStatusOverlap)) {
+			delete(published, key)
+		}
+	}
+	return since
+}
@@ -0,0 +1,183 @@
+package broker
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stateStub struct {
+	transitions []state.BatchLifecycleTransition
+	since       time.Time
+}
+
+func (s *stateStub) RegisterNewL2BlockEventHandler(h state.NewL2BlockEventHandler) {}
+
+func (s *stateStub) StartToMonitorNewL2Blocks() {}
+
+func (s *stateStub) GetTransactionReceipt(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*ethTypes.Receipt, error) {
+	return nil, state.ErrNotFound
+}
+
+func (s *stateStub) GetBatchLifecycleTransitionsSince(ctx context.Context, since time.Time, dbTx pgx.Tx) ([]state.BatchLifecycleTransition, error) {
+	s.since = since
+	return s.transitions, nil
+}
+
+func receiveEvent(t *testing.T, events chan Event) Event {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		require.FailNow(t, "event not received")
+		return Event{}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	b := New(Config{SinkBufferSize: 10}, &stateStub{})
+
+	events1 := make(chan Event, 10)
+	remove1 := b.AddSink(NewFuncSink("sink1", func(event Event) error {
+		events1 <- event
+		return nil
+	}))
+	events2 := make(chan Event, 10)
+	b.AddSink(NewFuncSink("sink2", func(event Event) error {
+		events2 <- event
+		return nil
+	}))
+
+	b.Publish(Event{Type: EventTypeL2Block})
+	assert.Equal(t, EventTypeL2Block, receiveEvent(t, events1).Type)
+	assert.Equal(t, EventTypeL2Block, receiveEvent(t, events2).Type)
+
+	remove1()
+	remove1()
+	b.Publish(Event{Type: EventTypeReceipt})
+	assert.Equal(t, EventTypeReceipt, receiveEvent(t, events2).Type)
+	assert.Empty(t, events1)
+}
+
+func TestPublishDropsEventsOfFullSinks(t *testing.T) {
+	b := New(Config{SinkBufferSize: 1}, &stateStub{})
+
+	blocked := make(chan struct{})
+	events := make(chan Event, 10)
+	b.AddSink(NewFuncSink("slow", func(event Event) error {
+		<-blocked
+		events <- event
+		return nil
+	}))
+
+	// the first event is being sent, the second one is queued and the rest are dropped
+	b.Publish(Event{Type: EventTypeL2Block})
+	time.Sleep(100 * time.Millisecond)
+	b.Publish(Event{Type: EventTypeReceipt})
+	b.Publish(Event{Type: EventTypeBatchStatus})
+	close(blocked)
+
+	assert.Equal(t, EventTypeL2Block, receiveEvent(t, events).Type)
+	assert.Equal(t, EventTypeReceipt, receiveEvent(t, events).Type)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, events)
+}
+
+func TestPublishBatchStatus(t *testing.T) {
+	st := &stateStub{}
+	b := New(Config{SinkBufferSize: 10}, st)
+	events := make(chan Event, 10)
+	b.AddSink(NewFuncSink("sink", func(event Event) error {
+		events <- event
+		return nil
+	}))
+
+	start := time.Unix(1700000000, 0)
+	l1TxHash := common.HexToHash("0x1")
+	st.transitions = []state.BatchLifecycleTransition{
+		{BatchNumber: 1, Stage: state.BatchTrustedStage, Timestamp: start.Add(-time.Second)},
+		{BatchNumber: 2, Stage: state.BatchTrustedStage, Timestamp: start.Add(time.Second)},
+		{BatchNumber: 1, Stage: state.BatchVirtualizedStage, L1TxHash: &l1TxHash, Timestamp: start.Add(2 * time.Second)},
+	}
+	published := map[batchStage]time.Time{}
+	since := b.publishBatchStatus(context.Background(), start, start, published)
+	assert.Equal(t, start.Add(-batchStatusOverlap), st.since)
+	assert.Equal(t, start.Add(2*time.Second), since)
+
+	// the transitions before the start aren't published
+	event := receiveEvent(t, events)
+	require.Equal(t, EventTypeBatchStatus, event.Type)
+	assert.Equal(t, uint64(2), uint64(event.BatchStatus.Number))
+	require.NotNil(t, event.BatchStatus.Stage)
+	assert.Equal(t, "trusted", *event.BatchStatus.Stage)
+	event = receiveEvent(t, events)
+	assert.Equal(t, uint64(1), uint64(event.BatchStatus.Number))
+	assert.Equal(t, "virtualized", *event.BatchStatus.Stage)
+	assert.Equal(t, &l1TxHash, event.BatchStatus.Transitions[0].L1TxHash)
+
+	// the transitions already published are skipped in the next polls
+	st.transitions = append(st.transitions, state.BatchLifecycleTransition{BatchNumber: 2, Stage: state.BatchVirtualizedStage, Timestamp: start.Add(time.Second)})
+	since = b.publishBatchStatus(context.Background(), start, since, published)
+	assert.Equal(t, start.Add(2*time.Second-batchStatusOverlap), st.since)
+	assert.Equal(t, start.Add(2*time.Second), since)
+	event = receiveEvent(t, events)
+	assert.Equal(t, uint64(2), uint64(event.BatchStatus.Number))
+	assert.Equal(t, "virtualized", *event.BatchStatus.Stage)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, events)
+}
+
+func TestOnTxSelected(t *testing.T) {
+	b := New(Config{SinkBufferSize: 10}, &stateStub{})
+	e := sequencer.TxSelectedEvent{
+		BatchNumber: 5,
+		Position:    2,
+		Hash:        common.HexToHash("0x1"),
+		From:        common.HexToAddress("0x2"),
+		GasPrice:    big.NewInt(1000),
+		ZKCounters:  state.ZKCounters{UsedSteps: 100},
+		SelectedAt:  time.UnixMilli(1700000000123),
+	}
+	// nothing is built without sinks
+	b.OnTxSelected(e)
+
+	events := make(chan Event, 10)
+	b.AddSink(NewFuncSink("sink", func(event Event) error {
+		events <- event
+		return nil
+	}))
+	b.OnTxSelected(e)
+
+	event := receiveEvent(t, events)
+	require.Equal(t, EventTypeTxSelected, event.Type)
+	require.NotNil(t, event.TxSelected)
+	assert.Equal(t, uint64(5), uint64(event.TxSelected.BatchNumber))
+	assert.Equal(t, uint64(2), uint64(event.TxSelected.Position))
+	assert.Equal(t, e.Hash, event.TxSelected.Hash)
+	assert.Equal(t, e.From, event.TxSelected.From)
+	assert.Equal(t, big.NewInt(1000), (*big.Int)(event.TxSelected.GasPrice))
+	assert.Equal(t, uint64(100), uint64(event.TxSelected.ZKCounters.Steps))
+	assert.Equal(t, uint64(1700000000123), uint64(event.TxSelected.SelectedAt))
+}
+
+func TestEventToStruct(t *testing.T) {
+	stage := "proven"
+	msg, err := eventToStruct(Event{Type: EventTypeBatchStatus, BatchStatus: &types.BatchLifecycle{Number: 3, Stage: &stage}})
+	require.NoError(t, err)
+	assert.Equal(t, "batchStatus", msg.Fields["type"].GetStringValue())
+	batchStatus := msg.Fields["batchStatus"].GetStructValue()
+	require.NotNil(t, batchStatus)
+	assert.Equal(t, "0x3", batchStatus.Fields["number"].GetStringValue())
+	assert.Equal(t, "proven", batchStatus.Fields["stage"].GetStringValue())
+	assert.NotContains(t, msg.Fields, "l2Block")
+}
@@ -0,0 +1,32 @@
+package broker
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+// Config has parameters to config the broker publishing the events of the new L2 blocks,
+// receipts and batch status transitions
+type Config struct {
+	// Enabled defines if the broker is started
+	Enabled bool `mapstructure:"Enabled"`
+
+	// SinkBufferSize is the number of events queued for each sink, the events published while the
+	// queue of a sink is full are dropped for it
+	SinkBufferSize int `mapstructure:"SinkBufferSize"`
+
+	// BatchStatusPollInterval is the time between the checks of the new batch status transitions
+	BatchStatusPollInterval types.Duration `mapstructure:"BatchStatusPollInterval"`
+
+	// GRPC is the config of the gRPC broadcast stream other services can consume
+	GRPC GRPCConfig `mapstructure:"GRPC"`
+}
+
+// GRPCConfig has parameters to config the gRPC broadcast stream
+type GRPCConfig struct {
+	// Enabled defines if the gRPC broadcast stream is served
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Host is the address to bind the gRPC server
+	Host string `mapstructure:"Host"`
+
+	// Port is the port to bind the gRPC server
+	Port int `mapstructure:"Port"`
+}
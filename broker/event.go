@@ -0,0 +1,35 @@
+package broker
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EventType is the type of the events published by the broker
+type EventType string
+
+const (
+	// EventTypeL2Block is published when a new L2 block is added to the state
+	EventTypeL2Block EventType = "l2Block"
+	// EventTypeReceipt is published for each tx of a new L2 block, after the block event
+	EventTypeReceipt EventType = "receipt"
+	// EventTypeBatchStatus is published when a batch reaches a new stage of its lifecycle
+	EventTypeBatchStatus EventType = "batchStatus"
+	// EventTypeTxSelected is published when the sequencer running in the same process selects
+	// a tx for the WIP batch, before the tx is executed
+	EventTypeTxSelected EventType = "txSelected"
+	// EventTypeL2Reorg is published when the sequencer running in the same process handles an
+	// L2 reorg, the blocks and receipts previously published for the reorged txs are no longer valid
+	EventTypeL2Reorg EventType = "l2Reorg"
+)
+
+// Event is an event published by the broker, only the field matching its type is set.
+// The payloads have the same format as the JSON-RPC responses
+type Event struct {
+	Type        EventType                  `json:"type"`
+	L2Block     *types.Block               `json:"l2Block,omitempty"`
+	Receipt     *types.Receipt             `json:"receipt,omitempty"`
+	BatchStatus *types.BatchLifecycle      `json:"batchStatus,omitempty"`
+	TxSelected  *types.SelectedTransaction `json:"txSelected,omitempty"`
+	ReorgedTxs  []common.Hash              `json:"reorgedTxs,omitempty"`
+}
@@ -0,0 +1,131 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// broadcastServiceServer is the server API of the broadcast service defined in
+// proto/src/proto/broadcast/v1/broadcast.proto
+type broadcastServiceServer interface {
+	Subscribe(*emptypb.Empty, grpc.ServerStream) error
+}
+
+// broadcastServiceDesc is the grpc.ServiceDesc of the broadcast service, it only uses well
+// known types so the events are sent as google.protobuf.Struct with the JSON format of the events
+var broadcastServiceDesc = grpc.ServiceDesc{
+	ServiceName: "broadcast.v1.BroadcastService",
+	HandlerType: (*broadcastServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       broadcastServiceSubscribeHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "broadcast/v1/broadcast.proto",
+}
+
+func broadcastServiceSubscribeHandler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(broadcastServiceServer).Subscribe(m, stream)
+}
+
+// serveGRPC serves the gRPC broadcast stream until the context is done
+func (b *Broker) serveGRPC(ctx context.Context) {
+	address := fmt.Sprintf("%s:%d", b.cfg.GRPC.Host, b.cfg.GRPC.Port)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatalf("failed to listen for the broker gRPC broadcast stream: %v", err)
+	}
+
+	srv := grpc.NewServer()
+	srv.RegisterService(&broadcastServiceDesc, &broadcastServer{broker: b})
+	go func() {
+		<-ctx.Done()
+		srv.Stop()
+	}()
+
+	log.Infof("broker gRPC broadcast stream listening on %s", address)
+	if err := srv.Serve(lis); err != nil {
+		log.Errorf("failed to serve the broker gRPC broadcast stream: %v", err)
+	}
+}
+
+// broadcastServer implements the broadcast service, adding a sink to the broker for each
+// subscription
+type broadcastServer struct {
+	broker *Broker
+}
+
+// Subscribe streams the events published by the broker until the client cancels the
+// subscription or the stream fails
+func (s *broadcastServer) Subscribe(_ *emptypb.Empty, stream grpc.ServerStream) error {
+	name := "grpc"
+	if p, ok := peer.FromContext(stream.Context()); ok {
+		name = fmt.Sprintf("grpc %s", p.Addr.String())
+	}
+	sink := &grpcSink{name: name, stream: stream, failed: make(chan error, 1)}
+	remove := s.broker.AddSink(sink)
+	defer remove()
+
+	select {
+	case <-stream.Context().Done():
+		return nil
+	case err := <-sink.failed:
+		return err
+	}
+}
+
+// grpcSink sends the events to the stream of a gRPC subscription
+type grpcSink struct {
+	name   string
+	stream grpc.ServerStream
+	failed chan error
+}
+
+// Name returns the name of the sink
+func (s *grpcSink) Name() string {
+	return s.name
+}
+
+// Send sends the event to the stream, ending the subscription if it fails
+func (s *grpcSink) Send(event Event) error {
+	msg, err := eventToStruct(event)
+	if err != nil {
+		return err
+	}
+	if err := s.stream.SendMsg(msg); err != nil {
+		select {
+		case s.failed <- err:
+		default:
+		}
+		return err
+	}
+	return nil
+}
+
+// eventToStruct converts the event to a google.protobuf.Struct with its JSON format
+func eventToStruct(event Event) (*structpb.Struct, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
@@ -0,0 +1,19 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// stateInterface gathers the methods required to interact with the state.
+type stateInterface interface {
+	RegisterNewL2BlockEventHandler(h state.NewL2BlockEventHandler)
+	StartToMonitorNewL2Blocks()
+	GetTransactionReceipt(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*ethTypes.Receipt, error)
+	GetBatchLifecycleTransitionsSince(ctx context.Context, since time.Time, dbTx pgx.Tx) ([]state.BatchLifecycleTransition, error)
+}
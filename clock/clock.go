@@ -0,0 +1,69 @@
+// Package clock contains the clock shared by the components of the node that depend on the
+// current time, like the batch timestamps of the finalizer or the lifetime of the pool txs, so
+// the tests can control the time, and the guard checking the local clock against NTP servers
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+}
+
+// System is the clock reading the local time of the system
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+// Now returns the current local time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// Fake is a clock whose time only changes when it's set or advanced, for the tests
+type Fake struct {
+	now time.Time
+	mux sync.RWMutex
+}
+
+// NewFake creates a fake clock starting at the provided time
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the current time of the fake clock
+func (f *Fake) Now() time.Time {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	return f.now
+}
+
+// Since returns the time elapsed since t according to the fake clock
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Set sets the current time of the fake clock
+func (f *Fake) Set(now time.Time) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.now = now
+}
+
+// Advance moves forward the current time of the fake clock
+func (f *Fake) Advance(d time.Duration) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -0,0 +1,21 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	clock := NewFake(start)
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), clock.Now())
+	assert.Equal(t, time.Minute, clock.Since(start))
+
+	clock.Set(start)
+	assert.Equal(t, time.Duration(0), clock.Since(start))
+}
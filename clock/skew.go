@@ -0,0 +1,183 @@
+package clock
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const (
+	// ntpPacketSize is the size of the NTP packets without extensions
+	ntpPacketSize = 48
+	// ntpEpochOffset is the number of seconds from the NTP epoch (1900) to the unix epoch (1970)
+	ntpEpochOffset = 2208988800
+	// ntpClientRequest is the first byte of a SNTP client request: no leap indicator, version 4 and client mode
+	ntpClientRequest = 0x23
+	// ntpServerMode is the mode of the server responses
+	ntpServerMode = 4
+)
+
+// ErrSkewTooBig is returned when the local clock drifts more than the max skew allowed
+var ErrSkewTooBig = errors.New("local clock skew exceeds the max allowed")
+
+// Config has parameters to config the guard checking the skew of the local clock
+type Config struct {
+	// SkewCheckEnabled defines if the skew of the local clock is checked against the NTP servers
+	SkewCheckEnabled bool `mapstructure:"SkewCheckEnabled"`
+
+	// NTPServers are the addresses of the NTP servers queried, the first one answering is used.
+	// The port defaults to 123
+	NTPServers []string `mapstructure:"NTPServers"`
+
+	// MaxSkew is the max drift of the local clock allowed, it must be lower than the tolerance of the
+	// batch timestamps so the batches aren't rejected by L1
+	MaxSkew types.Duration `mapstructure:"MaxSkew"`
+
+	// CheckInterval is the time between the checks while the node is running
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+
+	// Timeout is the max time to wait for the response of a NTP server
+	Timeout types.Duration `mapstructure:"Timeout"`
+
+	// HaltOnStartup defines if the node refuses to start when the skew exceeds MaxSkew
+	HaltOnStartup bool `mapstructure:"HaltOnStartup"`
+}
+
+// SkewGuard checks the drift of the local clock against the NTP servers, raising an event
+// when it exceeds the max skew allowed
+type SkewGuard struct {
+	cfg      Config
+	eventLog *event.EventLog
+	// queryOffset returns the offset of the local clock against a NTP server, overridden by the tests
+	queryOffset func(server string, timeout time.Duration) (time.Duration, error)
+}
+
+// NewSkewGuard creates a SkewGuard
+func NewSkewGuard(cfg Config, eventLog *event.EventLog) *SkewGuard {
+	return &SkewGuard{
+		cfg:         cfg,
+		eventLog:    eventLog,
+		queryOffset: QueryOffset,
+	}
+}
+
+// Check returns the offset of the local clock against the first NTP server answering, it
+// returns ErrSkewTooBig along with the offset when it exceeds the max skew, raising an event
+func (g *SkewGuard) Check(ctx context.Context) (time.Duration, error) {
+	if len(g.cfg.NTPServers) == 0 {
+		return 0, errors.New("no NTP servers configured")
+	}
+	var errs []error
+	for _, server := range g.cfg.NTPServers {
+		offset, err := g.queryOffset(server, g.cfg.Timeout.Duration)
+		if err != nil {
+			log.Debugf("failed to query NTP server %s: %v", server, err)
+			errs = append(errs, err)
+			continue
+		}
+
+		if offset.Abs() <= g.cfg.MaxSkew.Duration {
+			log.Debugf("local clock offset against NTP server %s: %v", server, offset)
+			return offset, nil
+		}
+
+		err = fmt.Errorf("%w: the offset against NTP server %s is %v, the max skew is %v", ErrSkewTooBig, server, offset, g.cfg.MaxSkew.Duration)
+		log.Warn(err.Error())
+		if g.eventLog != nil {
+			ev := &event.Event{
+				ReceivedAt:  time.Now(),
+				Source:      event.Source_Node,
+				Component:   event.Component_Clock,
+				Level:       event.Level_Critical,
+				EventID:     event.EventID_ClockSkew,
+				Description: err.Error(),
+			}
+			if logErr := g.eventLog.LogEvent(ctx, ev); logErr != nil {
+				log.Errorf("error storing clock skew event: %v", logErr)
+			}
+		}
+		return offset, err
+	}
+	return 0, fmt.Errorf("failed to query the NTP servers: %w", errors.Join(errs...))
+}
+
+// Start checks the local clock every check interval until the context is done
+func (g *SkewGuard) Start(ctx context.Context) {
+	ticker := time.NewTicker(g.cfg.CheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := g.Check(ctx); err != nil && !errors.Is(err, ErrSkewTooBig) {
+				log.Warnf("failed to check the local clock skew: %v", err)
+			}
+		}
+	}
+}
+
+// QueryOffset queries the NTP server with a SNTP request, returning the offset of the local clock,
+// positive when the local clock is behind the server one
+func QueryOffset(server string, timeout time.Duration) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close() //nolint:errcheck
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	req := make([]byte, ntpPacketSize)
+	req[0] = ntpClientRequest
+	originTime := time.Now()
+	binary.BigEndian.PutUint64(req[40:], toNTPTime(originTime))
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+
+	res := make([]byte, ntpPacketSize)
+	n, err := conn.Read(res)
+	if err != nil {
+		return 0, err
+	}
+	destinationTime := time.Now()
+	if n < ntpPacketSize {
+		return 0, fmt.Errorf("invalid NTP response size %d", n)
+	}
+	if res[0]&0x7 != ntpServerMode {
+		return 0, fmt.Errorf("invalid NTP response mode %d", res[0]&0x7)
+	}
+	if binary.BigEndian.Uint64(res[24:]) != binary.BigEndian.Uint64(req[40:]) {
+		return 0, errors.New("NTP response doesn't match the request")
+	}
+
+	receiveTime := fromNTPTime(binary.BigEndian.Uint64(res[32:]))
+	transmitTime := fromNTPTime(binary.BigEndian.Uint64(res[40:]))
+	return (receiveTime.Sub(originTime) + transmitTime.Sub(destinationTime)) / 2, nil //nolint:gomnd
+}
+
+// toNTPTime encodes the time as a NTP timestamp: seconds since 1900 and the fraction of second
+func toNTPTime(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := (uint64(t.Nanosecond()) << 32) / uint64(time.Second) //nolint:gomnd
+	return seconds<<32 | fraction                                    //nolint:gomnd
+}
+
+// fromNTPTime decodes a NTP timestamp
+func fromNTPTime(ntpTime uint64) time.Time {
+	seconds := int64(ntpTime>>32) - ntpEpochOffset                           //nolint:gomnd
+	nanoseconds := int64((ntpTime & 0xffffffff) * uint64(time.Second) >> 32) //nolint:gomnd
+	return time.Unix(seconds, nanoseconds)
+}
@@ -0,0 +1,84 @@
+package clock
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNTPTestServer starts a NTP server whose clock is ahead of the local one by the offset
+func newNTPTestServer(t *testing.T, offset time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck
+
+	go func() {
+		req := make([]byte, ntpPacketSize)
+		for {
+			n, addr, err := conn.ReadFrom(req)
+			if err != nil {
+				return
+			}
+			if n < ntpPacketSize {
+				continue
+			}
+			res := make([]byte, ntpPacketSize)
+			res[0] = 0x24 // version 4, server mode
+			copy(res[24:32], req[40:48])
+			binary.BigEndian.PutUint64(res[32:], toNTPTime(time.Now().Add(offset)))
+			binary.BigEndian.PutUint64(res[40:], toNTPTime(time.Now().Add(offset)))
+			_, _ = conn.WriteTo(res, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestNTPTime(t *testing.T) {
+	now := time.Unix(1700000000, 123456789)
+	assert.InDelta(t, now.UnixNano(), fromNTPTime(toNTPTime(now)).UnixNano(), 1)
+}
+
+func TestQueryOffset(t *testing.T) {
+	server := newNTPTestServer(t, 10*time.Second)
+
+	offset, err := QueryOffset(server, time.Second)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(10*time.Second), float64(offset), float64(100*time.Millisecond))
+}
+
+func TestSkewGuardCheck(t *testing.T) {
+	guard := NewSkewGuard(Config{
+		NTPServers: []string{"unreachable", "ahead", "behind"},
+		MaxSkew:    types.NewDuration(time.Second),
+	}, nil)
+	offsets := map[string]time.Duration{"ahead": 100 * time.Millisecond, "behind": -5 * time.Second}
+	guard.queryOffset = func(server string, timeout time.Duration) (time.Duration, error) {
+		offset, found := offsets[server]
+		if !found {
+			return 0, errors.New("unreachable")
+		}
+		return offset, nil
+	}
+
+	offset, err := guard.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, offset)
+
+	offsets["ahead"] = -5 * time.Second
+	offset, err = guard.Check(context.Background())
+	assert.ErrorIs(t, err, ErrSkewTooBig)
+	assert.Equal(t, -5*time.Second, offset)
+
+	delete(offsets, "ahead")
+	delete(offsets, "behind")
+	_, err = guard.Check(context.Background())
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrSkewTooBig)
+}
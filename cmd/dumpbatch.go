@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/urfave/cli/v2"
+)
+
+const dumpBatchFlagOutput = "output"
+
+var dumpBatchFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    dumpBatchFlagOutput,
+		Aliases: []string{"o"},
+		Usage:   "File where the raw batch L2 data is written as binary, it's printed as hex when not set",
+	},
+	&configFileFlag,
+	&networkFlag,
+}
+
+// dumpBatch exports the raw batch L2 data of the batch given as argument
+func dumpBatch(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("the batch number must be the only argument")
+	}
+	batchNumber, err := strconv.ParseUint(ctx.Args().First(), 10, 64) //nolint:gomnd
+	if err != nil {
+		return fmt.Errorf("invalid batch number %s: %w", ctx.Args().First(), err)
+	}
+
+	// Load config
+	c, err := config.Load(ctx, true)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	stateSqlDB, err := db.NewSQLDB(c.State.DB)
+	if err != nil {
+		return err
+	}
+	defer stateSqlDB.Close()
+	stateDB := state.NewPostgresStorage(state.Config{}, stateSqlDB)
+
+	batch, err := stateDB.GetBatchByNumber(ctx.Context, batchNumber, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get batch %d: %w", batchNumber, err)
+	}
+
+	outputFile := ctx.String(dumpBatchFlagOutput)
+	if outputFile == "" {
+		fmt.Println(hex.EncodeToHex(batch.BatchL2Data))
+		return nil
+	}
+	return os.WriteFile(outputFile, batch.BatchL2Data, 0600) //nolint:gomnd
+}
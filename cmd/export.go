@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/export"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	exportFlagFromBlock = "from-block"
+	exportFlagToBlock   = "to-block"
+	exportFlagFromBatch = "from-batch"
+	exportFlagToBatch   = "to-batch"
+	exportFlagFormat    = "format"
+	exportFlagOutputDir = "output-dir"
+)
+
+var exportFlags = []cli.Flag{
+	&cli.Uint64Flag{
+		Name:  exportFlagFromBlock,
+		Usage: "First L2 block of the range to export",
+	},
+	&cli.Uint64Flag{
+		Name:  exportFlagToBlock,
+		Usage: "Last L2 block of the range to export",
+	},
+	&cli.Uint64Flag{
+		Name:  exportFlagFromBatch,
+		Usage: "First batch of the range to export, can't be combined with the block range",
+	},
+	&cli.Uint64Flag{
+		Name:  exportFlagToBatch,
+		Usage: "Last batch of the range to export, can't be combined with the block range",
+	},
+	&cli.StringFlag{
+		Name:  exportFlagFormat,
+		Usage: "Format of the exported files: [`csv`, `parquet`]",
+		Value: string(export.FormatCSV),
+	},
+	&cli.StringFlag{
+		Name:     exportFlagOutputDir,
+		Aliases:  []string{"o"},
+		Usage:    "Directory where the blocks, transactions, receipts and logs files are written",
+		Required: true,
+	},
+	&configFileFlag,
+	&networkFlag,
+	&customNetworkFlag,
+}
+
+func exportData(ctx *cli.Context) error {
+	// Load config
+	c, err := config.Load(ctx, true)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	isBlockRange := ctx.IsSet(exportFlagFromBlock) || ctx.IsSet(exportFlagToBlock)
+	isBatchRange := ctx.IsSet(exportFlagFromBatch) || ctx.IsSet(exportFlagToBatch)
+	if isBlockRange == isBatchRange {
+		return errors.New("either a block range or a batch range must be provided")
+	}
+
+	// Connect to SQL
+	stateSqlDB, err := db.NewSQLDB(c.State.DB)
+	if err != nil {
+		return err
+	}
+	defer stateSqlDB.Close()
+	stateDB := state.NewPostgresStorage(state.Config{}, stateSqlDB)
+
+	exporter := export.NewExporter(stateDB)
+	format := export.Format(ctx.String(exportFlagFormat))
+	outputDir := ctx.String(exportFlagOutputDir)
+	if isBlockRange {
+		fromBlock, toBlock := ctx.Uint64(exportFlagFromBlock), ctx.Uint64(exportFlagToBlock)
+		log.Infof("exporting L2 blocks from %d to %d into %s", fromBlock, toBlock, outputDir)
+		err = exporter.ExportBlocks(context.Background(), fromBlock, toBlock, format, outputDir)
+	} else {
+		fromBatch, toBatch := ctx.Uint64(exportFlagFromBatch), ctx.Uint64(exportFlagToBatch)
+		log.Infof("exporting the L2 blocks of batches from %d to %d into %s", fromBatch, toBatch, outputDir)
+		err = exporter.ExportBatches(context.Background(), fromBatch, toBatch, format, outputDir)
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("export finished")
+	return nil
+}
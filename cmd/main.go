@@ -72,7 +72,7 @@ var (
 	httpAPIFlag = cli.StringSliceFlag{
 		Name:     config.FlagHTTPAPI,
 		Aliases:  []string{"ha"},
-		Usage:    fmt.Sprintf("List of JSON RPC apis to be exposed by the server: --http.api=%v,%v,%v,%v,%v,%v", jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIDebug, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3),
+		Usage:    fmt.Sprintf("List of JSON RPC apis to be exposed by the server: --http.api=%v,%v,%v,%v,%v,%v,%v", jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIDebug, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3, jsonrpc.APIAdmin),
 		Required: false,
 		Value:    cli.NewStringSlice(jsonrpc.APIEth, jsonrpc.APINet, jsonrpc.APIZKEVM, jsonrpc.APITxPool, jsonrpc.APIWeb3),
 	}
@@ -166,6 +166,47 @@ func main() {
 			Action:  dumpState,
 			Flags:   dumpStateFlags,
 		},
+		{
+			Name:    "export",
+			Aliases: []string{},
+			Usage:   "Exports the blocks, transactions, receipts and logs of a block or batch range to CSV files for analytics",
+			Action:  exportData,
+			Flags:   exportFlags,
+		},
+		{
+			Name:    "replay-events",
+			Aliases: []string{},
+			Usage:   "Reprocesses the archived L1 logs with the current decoding logic, without scanning L1 again",
+			Action:  replayEvents,
+			Flags:   replayEventsFlags,
+		},
+		{
+			Name:    "resync",
+			Aliases: []string{},
+			Usage:   "Removes the state after a batch so it's synced again from L1 on the next run, the node must be stopped",
+			Action:  resync,
+			Flags:   resyncFlags,
+		},
+		{
+			Name:  "state",
+			Usage: "Checks the local state",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "verify",
+					Usage:  "Reprocesses a batch range in the executor and compares the state roots with the stored ones",
+					Action: verifyState,
+					Flags:  verifyStateFlags,
+				},
+			},
+		},
+		{
+			Name:      "dump-batch",
+			Aliases:   []string{},
+			Usage:     "Exports the raw batch L2 data of a batch",
+			ArgsUsage: "<batch number>",
+			Action:    dumpBatch,
+			Flags:     dumpBatchFlags,
+		},
 		{
 			Name:   "generate-json-schema",
 			Usage:  "Generate the json-schema for the configuration file, and store it on docs/schema.json",
@@ -178,6 +219,20 @@ func main() {
 			Usage:   "Snapshot the state db",
 			Action:  snapshot,
 			Flags:   snapshotFlags,
+			Subcommands: []*cli.Command{
+				{
+					Name:   "create",
+					Usage:  "Export a snapshot of the state db and the hash db",
+					Action: snapshot,
+					Flags:  snapshotFlags,
+				},
+				{
+					Name:   "restore",
+					Usage:  "Import a snapshot of the state db and the hash db to bootstrap a node",
+					Action: restore,
+					Flags:  restoreFlags,
+				},
+			},
 		},
 		{
 			Name:    "restore",
@@ -186,6 +241,13 @@ func main() {
 			Action:  restore,
 			Flags:   restoreFlags,
 		},
+		{
+			Name:    "getProverArtifact",
+			Aliases: []string{},
+			Usage:   "Retrieves a prover input or a final proof from the prover artifacts storage",
+			Action:  getProverArtifact,
+			Flags:   proverArtifactsFlags,
+		},
 	}
 
 	err := app.Run(os.Args)
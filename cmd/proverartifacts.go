@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/0xPolygonHermez/zkevm-node/aggregator/artifacts"
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	proverArtifactsFlagKind  = "kind"
+	proverArtifactsFlagBatch = "batch"
+
+	proverArtifactKindInput      = "input"
+	proverArtifactKindFinalProof = "final-proof"
+)
+
+var proverArtifactsFlags = []cli.Flag{
+	&configFileFlag,
+	&cli.StringFlag{
+		Name:     proverArtifactsFlagKind,
+		Usage:    fmt.Sprintf("Kind of artifact to retrieve: [`%s`, `%s`]", proverArtifactKindInput, proverArtifactKindFinalProof),
+		Required: true,
+	},
+	&cli.Uint64Flag{
+		Name:     proverArtifactsFlagBatch,
+		Usage:    "Batch number of the prover input, or any batch included in the final proof",
+		Required: true,
+	},
+	&cli.StringFlag{
+		Name:     config.FlagOutputFile,
+		Aliases:  []string{"o"},
+		Usage:    "Output file to save the artifact",
+		Required: true,
+	},
+}
+
+func getProverArtifact(cliCtx *cli.Context) error {
+	c, err := config.Load(cliCtx, false)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+
+	if !c.Aggregator.Artifacts.Enabled {
+		return errors.New("prover artifacts storage is not enabled in the aggregator config")
+	}
+	storage, err := artifacts.New(c.Aggregator.Artifacts)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	batchNumber := cliCtx.Uint64(proverArtifactsFlagBatch)
+	var data []byte
+	switch kind := cliCtx.String(proverArtifactsFlagKind); kind {
+	case proverArtifactKindInput:
+		data, err = storage.GetProverInput(ctx, batchNumber)
+	case proverArtifactKindFinalProof:
+		var info *artifacts.FinalProofInfo
+		info, err = storage.FindFinalProof(ctx, batchNumber)
+		if err != nil {
+			break
+		}
+		log.Infof("final proof of batches %d-%d stored at %v", info.BatchNumber, info.BatchNumberFinal, info.StoredAt)
+		data, err = storage.GetFinalProof(ctx, info.BatchNumber, info.BatchNumberFinal)
+	default:
+		return fmt.Errorf("invalid artifact kind %s", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	output := cliCtx.String(config.FlagOutputFile)
+	const outputFilePermissions = 0600
+	if err := os.WriteFile(output, data, outputFilePermissions); err != nil {
+		return err
+	}
+	log.Infof("prover artifact of batch %d saved in %s", batchNumber, output)
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/event/nileventstorage"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
+	"github.com/urfave/cli/v2"
+)
+
+const replayEventsFlagFromBlock = "from-block"
+
+var replayEventsFlags = []cli.Flag{
+	&cli.Uint64Flag{
+		Name:     replayEventsFlagFromBlock,
+		Usage:    "First L1 block of the archived logs to replay, the state is reset to the previous block",
+		Required: true,
+	},
+	&configFileFlag,
+	&networkFlag,
+	&customNetworkFlag,
+}
+
+func replayEvents(ctx *cli.Context) error {
+	// Load config
+	c, err := config.Load(ctx, true)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+	checkStateMigrations(c.State.DB)
+
+	fromBlock := ctx.Uint64(replayEventsFlagFromBlock)
+	if fromBlock <= c.NetworkConfig.Genesis.GenesisBlockNum {
+		return errors.New("the L1 events can only be replayed from a block after the genesis block")
+	}
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		return err
+	}
+	eventLog := event.NewEventLog(c.EventLog, eventStorage)
+
+	stateSqlDB, err := db.NewSQLDB(c.State.DB)
+	if err != nil {
+		return err
+	}
+	defer stateSqlDB.Close()
+
+	etherman, err := newEtherman(*c)
+	if err != nil {
+		return err
+	}
+	l2ChainID, err := etherman.GetL2ChainID()
+	if err != nil {
+		return err
+	}
+
+	st := newState(ctx.Context, c, l2ChainID, []state.ForkIDInterval{}, stateSqlDB, eventLog, true, true)
+	forkIDIntervals, err := forkIDIntervals(ctx.Context, st, etherman, c.NetworkConfig.Genesis.GenesisBlockNum)
+	if err != nil {
+		return err
+	}
+	st.UpdateForkIDIntervalsInMemory(forkIDIntervals)
+
+	ethTxManagerStorage, err := ethtxmanager.NewPostgresStorage(c.State.DB)
+	if err != nil {
+		return err
+	}
+	etm := ethtxmanager.New(c.EthTxManager, etherman, ethTxManagerStorage, st)
+	poolInstance := createPool(c.Pool, c.State.Batch.Constraints, l2ChainID, st, eventLog)
+
+	// The replay only processes the archived logs, so the L1 is not synchronized in parallel
+	c.Synchronizer.UseParallelModeForL1Synchronization = false
+	sy, err := synchronizer.NewSynchronizer(
+		c.IsTrustedSequencer, etherman, []synchronizer.EthermanInterface{}, st, poolInstance, etm,
+		client.NewClient(""), eventLog, c.NetworkConfig.Genesis, c.Synchronizer, c.Log.Environment == "development",
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("replaying the archived L1 events from block %d", fromBlock)
+	if err := sy.ReplayL1Events(fromBlock); err != nil {
+		return err
+	}
+	log.Info("replay of the L1 events finished")
+	return nil
+}
@@ -65,12 +65,17 @@ func restore(ctx *cli.Context) error {
 		log.Error("error converting port to int. Error: ", err)
 		return err
 	}
+	password, err := c.State.DB.ResolvePassword(ctx.Context)
+	if err != nil {
+		log.Error("error resolving the database password. Error: ", err)
+		return err
+	}
 	restore, err := pg.NewRestore(&pg.Postgres{
 		Host:     c.State.DB.Host,
 		Port:     port,
 		DB:       c.State.DB.Name,
 		Username: c.State.DB.User,
-		Password: c.State.DB.Password,
+		Password: password,
 	})
 	if err != nil {
 		log.Error("error: ", err)
@@ -105,12 +110,17 @@ func restore(ctx *cli.Context) error {
 		log.Error("error dropping and creating state schema. Error: ", err)
 		return err
 	}
+	password, err = c.HashDB.ResolvePassword(ctx.Context)
+	if err != nil {
+		log.Error("error resolving the database password. Error: ", err)
+		return err
+	}
 	restore, err = pg.NewRestore(&pg.Postgres{
 		Host:     c.HashDB.Host,
 		Port:     port,
 		DB:       c.HashDB.Name,
 		Username: c.HashDB.User,
-		Password: c.HashDB.Password,
+		Password: password,
 	})
 	if err != nil {
 		log.Error("error: ", err)
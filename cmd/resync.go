@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+	"github.com/urfave/cli/v2"
+)
+
+const resyncFlagFromBatch = "from-batch"
+
+var resyncFlags = []cli.Flag{
+	&cli.Uint64Flag{
+		Name:     resyncFlagFromBatch,
+		Usage:    "Last batch kept in the state, the batches after it are removed and synced again from L1",
+		Required: true,
+	},
+	&configFileFlag,
+	&networkFlag,
+	&customNetworkFlag,
+	&yesFlag,
+}
+
+// resync removes the state after the batch, so the synchronizer syncs it again from L1 the next time
+// the node runs. The L1 blocks are removed from the block that sequenced the next batch, so the
+// batches of the same sequence before it are synced again too. The node must be stopped
+func resync(ctx *cli.Context) error {
+	// Load config
+	c, err := config.Load(ctx, true)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+	checkStateMigrations(c.State.DB)
+
+	stateSqlDB, err := db.NewSQLDB(c.State.DB)
+	if err != nil {
+		return err
+	}
+	defer stateSqlDB.Close()
+	stateDB := state.NewPostgresStorage(state.Config{}, stateSqlDB)
+
+	fromBatch := ctx.Uint64(resyncFlagFromBatch)
+	lastBatch, err := stateDB.GetLastBatchNumber(ctx.Context, nil)
+	if err != nil {
+		return err
+	}
+	if fromBatch >= lastBatch {
+		return fmt.Errorf("nothing to resync, the last batch in the state is %d", lastBatch)
+	}
+
+	// the L1 state is only reset when the next batch is virtualized, otherwise only trusted batches are removed
+	var resetBlock *uint64
+	virtualBatch, err := stateDB.GetVirtualBatch(ctx.Context, fromBatch+1, nil)
+	if err == nil {
+		block := virtualBatch.BlockNumber - 1
+		resetBlock = &block
+	} else if !errors.Is(err, state.ErrNotFound) {
+		return err
+	}
+
+	if !ctx.Bool(config.FlagYes) {
+		warning := fmt.Sprintf("*WARNING* Are you sure you want to remove the batches %d to %d", fromBatch+1, lastBatch)
+		if resetBlock != nil {
+			warning += fmt.Sprintf(" and the L1 blocks after %d", *resetBlock)
+		}
+		fmt.Print(warning, " from the state? [y/N]: ")
+		var input string
+		if _, err := fmt.Scanln(&input); err != nil {
+			return err
+		}
+		input = strings.ToLower(input)
+		if !(input == "y" || input == "yes") {
+			return nil
+		}
+	}
+
+	dbTx, err := stateSqlDB.Begin(ctx.Context)
+	if err != nil {
+		return err
+	}
+	if resetBlock != nil {
+		if err := stateDB.Reset(ctx.Context, *resetBlock, dbTx); err != nil {
+			return rollbackOnError(ctx.Context, dbTx, fmt.Errorf("failed to reset the state to L1 block %d: %w", *resetBlock, err))
+		}
+		ethTxManagerStorage, err := ethtxmanager.NewPostgresStorage(c.State.DB)
+		if err != nil {
+			return rollbackOnError(ctx.Context, dbTx, err)
+		}
+		// the reorg only updates the monitored txs in the storage
+		etm := ethtxmanager.New(c.EthTxManager, nil, ethTxManagerStorage, nil)
+		if err := etm.Reorg(ctx.Context, *resetBlock+1, dbTx); err != nil {
+			return rollbackOnError(ctx.Context, dbTx, fmt.Errorf("failed to reorg the monitored txs from L1 block %d: %w", *resetBlock+1, err))
+		}
+	}
+	if err := stateDB.ResetTrustedState(ctx.Context, fromBatch, dbTx); err != nil {
+		return rollbackOnError(ctx.Context, dbTx, fmt.Errorf("failed to remove the batches after %d: %w", fromBatch, err))
+	}
+	if err := dbTx.Commit(ctx.Context); err != nil {
+		return err
+	}
+
+	if resetBlock != nil {
+		log.Infof("state reset to batch %d and L1 block %d, the node syncs it again from L1 on the next run", fromBatch, *resetBlock)
+	} else {
+		log.Infof("state reset to batch %d, the node syncs it again on the next run", fromBatch)
+	}
+	return nil
+}
+
+// rollbackOnError rolls back the db tx, returning the error that caused the rollback
+func rollbackOnError(ctx context.Context, dbTx pgx.Tx, err error) error {
+	if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+		log.Errorf("error rolling back dbTx. RollbackErr: %s. Error : %v", rollbackErr.Error(), err)
+		return rollbackErr
+	}
+	return err
+}
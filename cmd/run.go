@@ -6,17 +6,23 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"net/http/pprof"
 	"os"
 	"os/signal"
 	"runtime"
+	"slices"
+	"syscall"
 	"time"
 
 	datastreamerlog "github.com/0xPolygonHermez/zkevm-data-streamer/log"
 	"github.com/0xPolygonHermez/zkevm-node"
 	"github.com/0xPolygonHermez/zkevm-node/aggregator"
+	"github.com/0xPolygonHermez/zkevm-node/broker"
+	"github.com/0xPolygonHermez/zkevm-node/clock"
 	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/config/remote"
+	"github.com/0xPolygonHermez/zkevm-node/config/secrets"
 	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/diagnostics"
 	"github.com/0xPolygonHermez/zkevm-node/etherman"
 	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
 	"github.com/0xPolygonHermez/zkevm-node/event"
@@ -25,16 +31,20 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/gasprice"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	jsonrpcTypes "github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/merkletree"
 	"github.com/0xPolygonHermez/zkevm-node/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/pool/memorypoolstorage"
 	"github.com/0xPolygonHermez/zkevm-node/pool/pgpoolstorage"
+	"github.com/0xPolygonHermez/zkevm-node/replay"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer"
 	"github.com/0xPolygonHermez/zkevm-node/sequencesender"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/urfave/cli/v2"
@@ -57,6 +67,15 @@ func start(cliCtx *cli.Context) error {
 	if c.Metrics.Enabled {
 		metrics.Init()
 	}
+	if c.RemoteConfig.Provider != "" {
+		go watchRemoteConfig(cliCtx.Context, c.RemoteConfig, c.RemoteConfigVersion())
+	}
+	if c.Secrets.Provider != "" {
+		go secrets.Watch(cliCtx.Context)
+	}
+	if err := replay.Init(c.Replay); err != nil {
+		return err
+	}
 	components := cliCtx.StringSlice(config.FlagComponents)
 
 	// Only runs migration if the component is the synchronizer and if the flag is deactivated
@@ -73,6 +92,7 @@ func start(cliCtx *cli.Context) error {
 		eventLog                      *event.EventLog
 		eventStorage                  event.Storage
 		cancelFuncs                   []context.CancelFunc
+		shutdown                      gracefulShutdown
 		needsExecutor, needsStateTree bool
 	)
 
@@ -98,6 +118,10 @@ func start(cliCtx *cli.Context) error {
 	}
 	eventLog = event.NewEventLog(c.EventLog, eventStorage)
 
+	if c.Clock.SkewCheckEnabled {
+		startClockSkewGuard(cliCtx.Context, c.Clock, eventLog)
+	}
+
 	// Core State DB
 	stateSqlDB, err := db.NewSQLDB(c.State.DB)
 	if err != nil {
@@ -116,6 +140,14 @@ func start(cliCtx *cli.Context) error {
 	}
 
 	st := newState(cliCtx.Context, c, l2ChainID, []state.ForkIDInterval{}, stateSqlDB, eventLog, needsExecutor, needsStateTree)
+	if c.State.DB.ReadReplica.Host != "" && slices.Contains(components, RPC) {
+		readRouter, err := db.NewReadRouter(c.State.DB, stateSqlDB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go readRouter.Start(cliCtx.Context)
+		st.SetReadRouter(readRouter)
+	}
 	forkIDIntervals, err := forkIDIntervals(cliCtx.Context, st, etherman, c.NetworkConfig.Genesis.GenesisBlockNum)
 	if err != nil {
 		log.Fatal("error getting forkIDs. Error: ", err)
@@ -143,11 +175,38 @@ func start(cliCtx *cli.Context) error {
 		EventID:    event.EventID_NodeComponentStarted,
 	}
 
-	var poolInstance *pool.Pool
+	var (
+		poolInstance *pool.Pool
+		seq          *sequencer.Sequencer
+		agg          *aggregator.Aggregator
+	)
 
 	if c.Metrics.ProfilingEnabled {
 		go startProfilingHttpServer(c.Metrics)
 	}
+	if c.Diagnostics.Enabled {
+		go diagnostics.New(c.Diagnostics, st, etherman).Start(cliCtx.Context)
+	}
+	var brk *broker.Broker
+	if c.Broker.Enabled {
+		brk = broker.New(c.Broker, st)
+		brk.Start(cliCtx.Context)
+	}
+	// the sequencer is created once its config is complete, before the components are started, so the
+	// same instance is shared with the RPC whatever the order of the components is
+	if slices.Contains(components, SEQUENCER) {
+		c.Sequencer.StreamServer.Log = datastreamerlog.Config{
+			Environment: datastreamerlog.LogEnvironment(c.Log.Environment),
+			Level:       c.Log.Level,
+			Outputs:     c.Log.Outputs,
+		}
+		poolInstance = createPool(c.Pool, c.State.Batch.Constraints, l2ChainID, st, eventLog)
+		seq = createSequencer(*c, poolInstance, st, eventLog)
+		if brk != nil {
+			seq.RegisterTxSelectedEventHandler(brk.OnTxSelected)
+			seq.RegisterL2ReorgEventHandler(brk.OnL2Reorg)
+		}
+	}
 	for _, component := range components {
 		switch component {
 		case AGGREGATOR:
@@ -157,23 +216,18 @@ func start(cliCtx *cli.Context) error {
 			if err != nil {
 				log.Fatal(err)
 			}
-			go runAggregator(cliCtx.Context, c.Aggregator, etherman, etm, st)
-		case SEQUENCER:
-			c.Sequencer.StreamServer.Log = datastreamerlog.Config{
-				Environment: datastreamerlog.LogEnvironment(c.Log.Environment),
-				Level:       c.Log.Level,
-				Outputs:     c.Log.Outputs,
+			if agg == nil {
+				agg = createAggregator(c.Aggregator, etherman, etm, st, eventLog)
 			}
+			go runAggregator(cliCtx.Context, agg)
+		case SEQUENCER:
 			ev.Component = event.Component_Sequencer
 			ev.Description = "Running sequencer"
 			err := eventLog.LogEvent(cliCtx.Context, ev)
 			if err != nil {
 				log.Fatal(err)
 			}
-			if poolInstance == nil {
-				poolInstance = createPool(c.Pool, c.State.Batch.Constraints, l2ChainID, st, eventLog)
-			}
-			seq := createSequencer(*c, poolInstance, st, eventLog)
+			shutdown.register("sequencer", shutdownStageProcessing, seq.Stop)
 			go seq.Start(cliCtx.Context)
 		case SEQUENCE_SENDER:
 			ev.Component = event.Component_Sequence_Sender
@@ -202,11 +256,22 @@ func start(cliCtx *cli.Context) error {
 				poolInstance.StartPollingMinSuggestedGasPrice(cliCtx.Context)
 			}
 			poolInstance.StartRefreshingBlockedAddressesPeriodically()
+			// the --http.api flag takes precedence over the EnabledApis of the config file
+			enabledApis := c.RPC.EnabledApis
+			if cliCtx.IsSet(config.FlagHTTPAPI) || len(enabledApis) == 0 {
+				enabledApis = cliCtx.StringSlice(config.FlagHTTPAPI)
+			}
 			apis := map[string]bool{}
-			for _, a := range cliCtx.StringSlice(config.FlagHTTPAPI) {
+			for _, a := range enabledApis {
 				apis[a] = true
 			}
-			go runJSONRPCServer(*c, etherman, l2ChainID, poolInstance, st, apis)
+			// the admin API manages the sequencer and the aggregator when they run in the same process,
+			// the txpool API also reads the sequencer worker when it's available and the WebSockets
+			// notify the txs it selects through the broker. The sequencer is nil unless it runs in this process
+			if agg == nil && apis[jsonrpc.APIAdmin] && slices.Contains(components, AGGREGATOR) {
+				agg = createAggregator(c.Aggregator, etherman, etm, st, eventLog)
+			}
+			go runJSONRPCServer(*c, etherman, l2ChainID, poolInstance, st, stateSqlDB, seq, agg, brk, apis, eventLog, &shutdown)
 		case SYNCHRONIZER:
 			ev.Component = event.Component_Synchronizer
 			ev.Description = "Running synchronizer"
@@ -218,6 +283,9 @@ func start(cliCtx *cli.Context) error {
 				poolInstance = createPool(c.Pool, c.State.Batch.Constraints, l2ChainID, st, eventLog)
 			}
 			go runSynchronizer(*c, etherman, ethTxManagerStorage, st, poolInstance, eventLog)
+			if c.State.Pruning.Enabled {
+				go st.StartPruning(cliCtx.Context, c.State.Pruning)
+			}
 		case ETHTXMANAGER:
 			ev.Component = event.Component_EthTxManager
 			ev.Description = "Running eth tx manager service"
@@ -241,11 +309,17 @@ func start(cliCtx *cli.Context) error {
 		}
 	}
 
+	if c.Replay.Mode == replay.ModeReplay && poolInstance != nil {
+		go replay.ReplayPool(cliCtx.Context, poolInstance)
+	}
+	// the recording is closed when the node stops
+	cancelFuncs = append(cancelFuncs, replay.Close)
+
 	if c.Metrics.Enabled {
 		go startMetricsHttpServer(c.Metrics)
 	}
 
-	waitSignal(cancelFuncs)
+	waitSignal(&shutdown, c.ShutdownTimeout.Duration, cancelFuncs)
 
 	return nil
 }
@@ -288,7 +362,7 @@ func newEtherman(c config.Config) (*etherman.Client, error) {
 func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerStorage *ethtxmanager.PostgresStorage, st *state.State, pool *pool.Pool, eventLog *event.EventLog) {
 	var trustedSequencerURL string
 	var err error
-	if !cfg.IsTrustedSequencer {
+	if !cfg.IsTrustedSequencer && cfg.Synchronizer.TrustedSync.Enabled {
 		if cfg.Synchronizer.TrustedSequencerURL != "" {
 			trustedSequencerURL = cfg.Synchronizer.TrustedSequencerURL
 		} else {
@@ -310,12 +384,12 @@ func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerS
 			if err != nil {
 				log.Fatal(err)
 			}
-			etherManForL1 = append(etherManForL1, eth)
+			etherManForL1 = append(etherManForL1, replay.WrapEtherman(eth))
 		}
 	}
 	etm := ethtxmanager.New(cfg.EthTxManager, etherman, ethTxManagerStorage, st)
 	sy, err := synchronizer.NewSynchronizer(
-		cfg.IsTrustedSequencer, etherman, etherManForL1, st, pool, etm,
+		cfg.IsTrustedSequencer, replay.WrapEtherman(etherman), etherManForL1, st, pool, etm,
 		zkEVMClient, eventLog, cfg.NetworkConfig.Genesis, cfg.Synchronizer, cfg.Log.Environment == "development",
 	)
 	if err != nil {
@@ -326,11 +400,18 @@ func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerS
 	}
 }
 
-func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64, pool *pool.Pool, st *state.State, apis map[string]bool) {
+func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64, pool *pool.Pool, st *state.State, stateSqlDB *pgxpool.Pool, seq *sequencer.Sequencer, agg *aggregator.Aggregator, brk *broker.Broker, apis map[string]bool, eventLog *event.EventLog, shutdown *gracefulShutdown) {
 	var err error
-	storage := jsonrpc.NewStorage()
+	storage := jsonrpc.NewPostgresStorage(stateSqlDB)
 	c.RPC.MaxCumulativeGasUsed = c.State.Batch.Constraints.MaxCumulativeGasUsed
-	c.RPC.L2Coinbase = c.SequenceSender.L2Coinbase
+	c.RPC.L2Coinbase = c.Sequencer.L2Coinbase
+	if c.RPC.L2Coinbase == (common.Address{}) {
+		c.RPC.L2Coinbase, err = etherman.TrustedSequencer()
+		if err != nil {
+			log.Fatalf("error getting trusted sequencer address. Error: %v", err)
+		}
+		log.Warnf("Sequencer.L2Coinbase is not set, the RPC reports the trusted sequencer address %s as the coinbase", c.RPC.L2Coinbase.String())
+	}
 	if !c.IsTrustedSequencer {
 		if c.RPC.SequencerNodeURI == "" {
 			log.Debug("getting trusted sequencer URL from smc")
@@ -342,11 +423,25 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 		log.Debug("SequencerNodeURI ", c.RPC.SequencerNodeURI)
 	}
 
+	// the sequencer is only available when it runs in the same process, the nil
+	// pointer must not be wrapped in the interface
+	var seqInterface jsonrpcTypes.SequencerInterface
+	if seq != nil {
+		seqInterface = seq
+	}
+
 	services := []jsonrpc.Service{}
 	if _, ok := apis[jsonrpc.APIEth]; ok {
+		ethEndpoints := jsonrpc.NewEthEndpoints(c.RPC, chainID, replay.WrapPool(pool), st, etherman, storage)
+		if brk != nil && c.RPC.WebSockets.Enabled {
+			brk.AddSink(jsonrpc.NewBatchStatusSink(ethEndpoints))
+			if seq != nil {
+				brk.AddSink(jsonrpc.NewSelectedTransactionsSink(ethEndpoints))
+			}
+		}
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APIEth,
-			Service: jsonrpc.NewEthEndpoints(c.RPC, chainID, pool, st, etherman, storage),
+			Service: ethEndpoints,
 		})
 	}
 
@@ -360,14 +455,14 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 	if _, ok := apis[jsonrpc.APIZKEVM]; ok {
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APIZKEVM,
-			Service: jsonrpc.NewZKEVMEndpoints(c.RPC, st, etherman),
+			Service: jsonrpc.NewZKEVMEndpoints(c.RPC, pool, st, etherman, storage),
 		})
 	}
 
 	if _, ok := apis[jsonrpc.APITxPool]; ok {
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APITxPool,
-			Service: &jsonrpc.TxPoolEndpoints{},
+			Service: jsonrpc.NewTxPoolEndpoints(pool, seqInterface),
 		})
 	}
 
@@ -385,7 +480,24 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 		})
 	}
 
-	if err := jsonrpc.NewServer(c.RPC, chainID, pool, st, storage, services).Start(); err != nil {
+	if _, ok := apis[jsonrpc.APIAdmin]; ok {
+		var aggInterface jsonrpcTypes.AggregatorInterface
+		if agg != nil {
+			aggInterface = agg
+		}
+		// the executor debug flags are always available, they affect the requests sent by this node
+		services = append(services, jsonrpc.Service{
+			Name:    jsonrpc.APIAdmin,
+			Service: jsonrpc.NewAdminEndpoints(pool, seqInterface, aggInterface, st, st, etherman, eventLog),
+		})
+	}
+
+	server := jsonrpc.NewServer(c.RPC, chainID, pool, st, etherman, storage, services)
+	// the server stops accepting requests and drains the in-flight ones during RPC.ShutdownTimeout
+	shutdown.register("JSON-RPC server", shutdownStageIngress, func(ctx context.Context) error {
+		return server.Stop()
+	})
+	if err := server.Start(); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -396,7 +508,7 @@ func createSequencer(cfg config.Config, pool *pool.Pool, st *state.State, eventL
 		log.Fatal(err)
 	}
 
-	seq, err := sequencer.New(cfg.Sequencer, cfg.State.Batch, pool, st, etherman, eventLog)
+	seq, err := sequencer.New(cfg.Sequencer, cfg.State.Batch, cfg.Pool, pool, st, etherman, eventLog)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -427,12 +539,16 @@ func createSequenceSender(cfg config.Config, pool *pool.Pool, etmStorage *ethtxm
 	return seqSender
 }
 
-func runAggregator(ctx context.Context, c aggregator.Config, etherman *etherman.Client, ethTxManager *ethtxmanager.Client, st *state.State) {
-	agg, err := aggregator.New(c, st, ethTxManager, etherman)
+func createAggregator(c aggregator.Config, etherman *etherman.Client, ethTxManager *ethtxmanager.Client, st *state.State, eventLog *event.EventLog) *aggregator.Aggregator {
+	agg, err := aggregator.New(c, st, ethTxManager, etherman, eventLog)
 	if err != nil {
 		log.Fatal(err)
 	}
-	err = agg.Start(ctx)
+	return &agg
+}
+
+func runAggregator(ctx context.Context, agg *aggregator.Aggregator) {
+	err := agg.Start(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -444,14 +560,15 @@ func runL2GasPriceSuggester(cfg gasprice.Config, state *state.State, pool *pool.
 	gasprice.NewL2GasPriceSuggester(ctx, cfg, pool, etherman, state)
 }
 
-func waitSignal(cancelFuncs []context.CancelFunc) {
+func waitSignal(shutdown *gracefulShutdown, shutdownTimeout time.Duration, cancelFuncs []context.CancelFunc) {
 	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, os.Interrupt)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
 
 	for sig := range signals {
 		switch sig {
-		case os.Interrupt, os.Kill:
+		case os.Interrupt, os.Kill, syscall.SIGTERM:
 			log.Info("terminating application gracefully...")
+			shutdown.run(shutdownTimeout)
 
 			exitStatus := 0
 			for _, cancel := range cancelFuncs {
@@ -462,13 +579,77 @@ func waitSignal(cancelFuncs []context.CancelFunc) {
 	}
 }
 
+// watchRemoteConfig watches the remote config document, as the config is only applied
+// at startup the node is stopped gracefully on changes if it's configured to do it
+func watchRemoteConfig(ctx context.Context, cfg remote.Config, version string) {
+	client, err := remote.NewClient(cfg)
+	if err != nil {
+		log.Errorf("failed to create the remote config client, err: %v", err)
+		return
+	}
+	client.Watch(ctx, version, func(doc remote.Document) {
+		if !cfg.ExitOnChange {
+			log.Warnf("remote config changed to version %s, it will be applied after restarting the node", doc.Version)
+			return
+		}
+		log.Infof("remote config changed to version %s, stopping the node to apply it", doc.Version)
+		p, err := os.FindProcess(os.Getpid())
+		if err == nil {
+			err = p.Signal(os.Interrupt)
+		}
+		if err != nil {
+			log.Errorf("failed to stop the node after the remote config change, err: %v", err)
+		}
+	})
+}
+
+// startClockSkewGuard checks the skew of the local clock at startup, stopping the node when it's
+// too big and HaltOnStartup is set, and keeps checking it in background
+func startClockSkewGuard(ctx context.Context, cfg clock.Config, eventLog *event.EventLog) {
+	guard := clock.NewSkewGuard(cfg, eventLog)
+	offset, err := guard.Check(ctx)
+	if errors.Is(err, clock.ErrSkewTooBig) && cfg.HaltOnStartup {
+		log.Fatal(err)
+	} else if err != nil && !errors.Is(err, clock.ErrSkewTooBig) {
+		log.Warnf("failed to check the local clock skew, err: %v", err)
+	} else if err == nil {
+		log.Infof("local clock offset against NTP: %v", offset)
+	}
+	go guard.Start(ctx)
+}
+
+func checkExecutorCompatibility(ctx context.Context, executorClient executor.ExecutorServiceClient, eventLog *event.EventLog) {
+	proverID, err := executor.CheckCompatibility(ctx, executorClient)
+	if errors.Is(err, executor.ErrExecutorNotCompatible) {
+		ev := &event.Event{
+			ReceivedAt:  time.Now(),
+			Source:      event.Source_Node,
+			Component:   event.Component_Executor,
+			Level:       event.Level_Critical,
+			EventID:     event.EventID_ExecutorIncompatible,
+			Description: err.Error(),
+		}
+		if logErr := eventLog.LogEvent(ctx, ev); logErr != nil {
+			log.Errorf("error storing event: %v", logErr)
+		}
+		log.Fatal(err)
+	} else if err != nil {
+		log.Warnf("failed to check the executor compatibility: %v", err)
+		return
+	}
+	log.Infof("executor compatibility checked, prover ID: %s", proverID)
+}
+
 func newState(ctx context.Context, c *config.Config, l2ChainID uint64, forkIDIntervals []state.ForkIDInterval, sqlDB *pgxpool.Pool, eventLog *event.EventLog, needsExecutor, needsStateTree bool) *state.State {
+	c.State.BridgeIndexing.L2BridgeAddr = c.NetworkConfig.L2BridgeAddr
 	stateDb := state.NewPostgresStorage(c.State, sqlDB)
 
 	// Executor
 	var executorClient executor.ExecutorServiceClient
 	if needsExecutor {
 		executorClient, _, _ = executor.NewExecutorClient(ctx, c.Executor)
+		executorClient = replay.WrapExecutorClient(executorClient)
+		checkExecutorCompatibility(ctx, executorClient, eventLog)
 	}
 
 	// State Tree
@@ -489,17 +670,32 @@ func newState(ctx context.Context, c *config.Config, l2ChainID uint64, forkIDInt
 		MaxLogsCount:                 c.RPC.MaxLogsCount,
 		MaxLogsBlockRange:            c.RPC.MaxLogsBlockRange,
 		MaxNativeBlockHashBlockRange: c.RPC.MaxNativeBlockHashBlockRange,
+		EstimateGasCap:               c.RPC.EstimateGasCap,
+		EstimateGasErrorRatio:        c.RPC.EstimateGasErrorRatio,
+		BridgeIndexing:               c.State.BridgeIndexing,
+		Batch:                        c.State.Batch,
 	}
 
 	st := state.NewState(stateCfg, stateDb, executorClient, stateTree, eventLog)
+	st.SetExecutorDebugConfig(c.Executor.Debug)
 	return st
 }
 
 func createPool(cfgPool pool.Config, constraintsCfg state.BatchConstraintsCfg, l2ChainID uint64, st *state.State, eventLog *event.EventLog) *pool.Pool {
-	runPoolMigrations(cfgPool.DB)
-	poolStorage, err := pgpoolstorage.NewPostgresPoolStorage(cfgPool.DB)
-	if err != nil {
-		log.Fatal(err)
+	var poolStorage pool.Storage
+	switch cfgPool.StorageType {
+	case pool.StorageTypeMemory:
+		log.Warn("the pool is stored in memory, the txs aren't shared with other processes nor kept after a restart")
+		poolStorage = memorypoolstorage.NewMemoryPoolStorage()
+	case pool.StorageTypePostgres, "":
+		runPoolMigrations(cfgPool.DB)
+		pgStorage, err := pgpoolstorage.NewPostgresPoolStorage(cfgPool.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		poolStorage = pgStorage
+	default:
+		log.Fatalf("invalid pool config: unknown storage type %s", cfgPool.StorageType)
 	}
 	poolInstance := pool.NewPool(cfgPool, constraintsCfg, poolStorage, st, l2ChainID, eventLog)
 	return poolInstance
@@ -530,11 +726,7 @@ func startProfilingHttpServer(c metrics.Config) {
 		log.Errorf("failed to create tcp listener for profiling: %v", err)
 		return
 	}
-	mux.HandleFunc(metrics.ProfilingIndexEndpoint, pprof.Index)
-	mux.HandleFunc(metrics.ProfileEndpoint, pprof.Profile)
-	mux.HandleFunc(metrics.ProfilingCmdEndpoint, pprof.Cmdline)
-	mux.HandleFunc(metrics.ProfilingSymbolEndpoint, pprof.Symbol)
-	mux.HandleFunc(metrics.ProfilingTraceEndpoint, pprof.Trace)
+	diagnostics.RegisterProfilingHandlers(mux)
 	profilingServer := &http.Server{
 		Handler:           mux,
 		ReadHeaderTimeout: two * time.Minute,
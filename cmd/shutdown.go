@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// shutdownStage orders the steps of the graceful shutdown, the components receiving requests
+// are stopped before the components processing them
+type shutdownStage int
+
+const (
+	// shutdownStageIngress stops the components receiving requests, like the JSON-RPC server
+	shutdownStageIngress shutdownStage = iota
+	// shutdownStageProcessing stops the components processing the requests, like the sequencer
+	shutdownStageProcessing
+)
+
+// shutdownHook stops a component gracefully, it returns an error if it's not stopped before the context is done
+type shutdownHook struct {
+	name  string
+	stage shutdownStage
+	stop  func(ctx context.Context) error
+}
+
+// gracefulShutdown stops the components of the node before it exits
+type gracefulShutdown struct {
+	mutex sync.Mutex
+	hooks []shutdownHook
+}
+
+// register adds the hook stopping a component, the components can be registered while they start
+func (g *gracefulShutdown) register(name string, stage shutdownStage, stop func(ctx context.Context) error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.hooks = append(g.hooks, shutdownHook{name: name, stage: stage, stop: stop})
+}
+
+// run stops the components stage by stage, the components of the same stage are stopped concurrently.
+// The whole shutdown is bounded by the timeout, the components not stopped by then are abandoned
+func (g *gracefulShutdown) run(timeout time.Duration) {
+	g.mutex.Lock()
+	hooks := make([]shutdownHook, len(g.hooks))
+	copy(hooks, g.hooks)
+	g.mutex.Unlock()
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].stage < hooks[j].stage })
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for i := 0; i < len(hooks); {
+		stage := hooks[i].stage
+		var wg sync.WaitGroup
+		for ; i < len(hooks) && hooks[i].stage == stage; i++ {
+			wg.Add(1)
+			go func(hook shutdownHook) {
+				defer wg.Done()
+				log.Infof("stopping %s", hook.name)
+				if err := hook.stop(ctx); err != nil {
+					log.Errorf("failed to stop %s gracefully: %v", hook.name, err)
+					return
+				}
+				log.Infof("%s stopped", hook.name)
+			}(hooks[i])
+		}
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			log.Warnf("graceful shutdown not finished after %s, exiting", timeout)
+			return
+		}
+	}
+}
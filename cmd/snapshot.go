@@ -31,12 +31,17 @@ func snapshot(ctx *cli.Context) error {
 		log.Error("error converting port to int. Error: ", err)
 		return err
 	}
+	password, err := c.State.DB.ResolvePassword(ctx.Context)
+	if err != nil {
+		log.Error("error resolving the database password. Error: ", err)
+		return err
+	}
 	dump, err := pg.NewDump(&pg.Postgres{
 		Host:     c.State.DB.Host,
 		Port:     port,
 		DB:       c.State.DB.Name,
 		Username: c.State.DB.User,
-		Password: c.State.DB.Password,
+		Password: password,
 	})
 	if err != nil {
 		log.Error("error: ", err)
@@ -50,7 +55,7 @@ func snapshot(ctx *cli.Context) error {
 	if dumpExec.Error != nil {
 		log.Error("error dumping statedb. Error: ", dumpExec.Error.Err)
 		log.Debug("dumpExec.Output: ", dumpExec.Output)
-		return err
+		return dumpExec.Error.Err
 	}
 
 	log.Info("StateDB snapshot success. Saved in ", dumpExec.File)
@@ -60,12 +65,17 @@ func snapshot(ctx *cli.Context) error {
 		log.Error("error converting port to int. Error: ", err)
 		return err
 	}
+	password, err = c.HashDB.ResolvePassword(ctx.Context)
+	if err != nil {
+		log.Error("error resolving the database password. Error: ", err)
+		return err
+	}
 	dump, err = pg.NewDump(&pg.Postgres{
 		Host:     c.HashDB.Host,
 		Port:     port,
 		DB:       c.HashDB.Name,
 		Username: c.HashDB.User,
-		Password: c.HashDB.Password,
+		Password: password,
 	})
 	if err != nil {
 		log.Error("error: ", err)
@@ -79,7 +89,7 @@ func snapshot(ctx *cli.Context) error {
 	if dumpExec.Error != nil {
 		log.Error("error dumping hashdb. Error: ", dumpExec.Error.Err)
 		log.Debug("dumpExec.Output: ", dumpExec.Output)
-		return err
+		return dumpExec.Error.Err
 	}
 
 	log.Info("HashDB snapshot success. Saved in ", dumpExec.File)
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/config"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/event/nileventstorage"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	stateMetrics "github.com/0xPolygonHermez/zkevm-node/state/metrics"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	verifyStateFlagFromBatch = "from-batch"
+	verifyStateFlagToBatch   = "to-batch"
+)
+
+var verifyStateFlags = []cli.Flag{
+	&cli.Uint64Flag{
+		Name:     verifyStateFlagFromBatch,
+		Usage:    "First batch of the range to verify",
+		Required: true,
+	},
+	&cli.Uint64Flag{
+		Name:  verifyStateFlagToBatch,
+		Usage: "Last batch of the range to verify, the last closed batch by default",
+	},
+	&configFileFlag,
+	&networkFlag,
+	&customNetworkFlag,
+}
+
+// verifyState reprocesses the closed batches of the range in the executor, from the state root of
+// the previous batch, and compares the state roots computed with the ones stored in the state
+func verifyState(ctx *cli.Context) error {
+	// Load config
+	c, err := config.Load(ctx, true)
+	if err != nil {
+		return err
+	}
+	setupLog(c.Log)
+	checkStateMigrations(c.State.DB)
+
+	fromBatch := ctx.Uint64(verifyStateFlagFromBatch)
+	if fromBatch == 0 {
+		return errors.New("the genesis batch can't be verified, the range must start at batch 1")
+	}
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		return err
+	}
+	eventLog := event.NewEventLog(c.EventLog, eventStorage)
+
+	stateSqlDB, err := db.NewSQLDB(c.State.DB)
+	if err != nil {
+		return err
+	}
+	defer stateSqlDB.Close()
+
+	etherman, err := newEtherman(*c)
+	if err != nil {
+		return err
+	}
+	l2ChainID, err := etherman.GetL2ChainID()
+	if err != nil {
+		return err
+	}
+
+	st := newState(ctx.Context, c, l2ChainID, []state.ForkIDInterval{}, stateSqlDB, eventLog, true, true)
+	forkIDIntervals, err := forkIDIntervals(ctx.Context, st, etherman, c.NetworkConfig.Genesis.GenesisBlockNum)
+	if err != nil {
+		return err
+	}
+	st.UpdateForkIDIntervalsInMemory(forkIDIntervals)
+
+	toBatch := ctx.Uint64(verifyStateFlagToBatch)
+	if toBatch == 0 {
+		toBatch, err = st.GetLastClosedBatchNumber(ctx.Context, nil)
+		if err != nil {
+			return err
+		}
+	}
+	if fromBatch > toBatch {
+		return fmt.Errorf("invalid batch range %d to %d", fromBatch, toBatch)
+	}
+
+	previousBatch, err := st.GetBatchByNumber(ctx.Context, fromBatch-1, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get batch %d: %w", fromBatch-1, err)
+	}
+	oldStateRoot := previousBatch.StateRoot
+
+	mismatches := 0
+	for batchNumber := fromBatch; batchNumber <= toBatch; batchNumber++ {
+		batch, err := st.GetBatchByNumber(ctx.Context, batchNumber, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get batch %d: %w", batchNumber, err)
+		}
+		isClosed, err := st.IsBatchClosed(ctx.Context, batchNumber, nil)
+		if err != nil {
+			return fmt.Errorf("failed to check if batch %d is closed: %w", batchNumber, err)
+		}
+		if !isClosed {
+			log.Infof("batch %d is not closed, the verification stops", batchNumber)
+			break
+		}
+
+		result, err := st.ProcessBatch(ctx.Context, state.ProcessRequest{
+			BatchNumber:    batch.BatchNumber,
+			GlobalExitRoot: batch.GlobalExitRoot,
+			OldStateRoot:   oldStateRoot,
+			Transactions:   batch.BatchL2Data,
+			Coinbase:       batch.Coinbase,
+			Timestamp:      batch.Timestamp,
+			Caller:         stateMetrics.DiscardCallerLabel,
+		}, false)
+		if err != nil {
+			return fmt.Errorf("failed to process batch %d: %w", batchNumber, err)
+		}
+
+		switch {
+		case result.ExecutorError != nil:
+			mismatches++
+			log.Errorf("batch %d: executor error: %v", batchNumber, result.ExecutorError)
+		case result.NewStateRoot != batch.StateRoot:
+			mismatches++
+			log.Errorf("batch %d: state root mismatch, stored: %s, computed: %s", batchNumber, batch.StateRoot.String(), result.NewStateRoot.String())
+		default:
+			log.Infof("batch %d: state root %s verified", batchNumber, batch.StateRoot.String())
+		}
+		// the next batch is processed from the stored state root, so each mismatch is reported only once
+		oldStateRoot = batch.StateRoot
+	}
+
+	if mismatches > 0 {
+		return fmt.Errorf("%d batches don't match the stored state roots", mismatches)
+	}
+	log.Infof("state verified from batch %d to batch %d", fromBatch, toBatch)
+	return nil
+}
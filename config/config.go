@@ -2,11 +2,19 @@ package config
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/0xPolygonHermez/zkevm-node/aggregator"
+	"github.com/0xPolygonHermez/zkevm-node/broker"
+	"github.com/0xPolygonHermez/zkevm-node/clock"
+	"github.com/0xPolygonHermez/zkevm-node/config/remote"
+	"github.com/0xPolygonHermez/zkevm-node/config/secrets"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/diagnostics"
 	"github.com/0xPolygonHermez/zkevm-node/etherman"
 	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
 	"github.com/0xPolygonHermez/zkevm-node/event"
@@ -16,11 +24,13 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/merkletree"
 	"github.com/0xPolygonHermez/zkevm-node/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/replay"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer"
 	"github.com/0xPolygonHermez/zkevm-node/sequencesender"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 	"github.com/urfave/cli/v2"
@@ -57,6 +67,9 @@ const (
 	FlagDocumentationFileType = "config-file"
 )
 
+// deprecatedSequenceSenderL2CoinbaseKey is the key of the L2 coinbase before it was moved to Sequencer.L2Coinbase
+const deprecatedSequenceSenderL2CoinbaseKey = "SequenceSender.L2Coinbase"
+
 /*
 Config represents the configuration of the entire Hermez Node
 The file is [TOML format]
@@ -82,6 +95,10 @@ type Config struct {
 	ForkUpgradeBatchNumber uint64 `mapstructure:"ForkUpgradeBatchNumber"`
 	// Which is the new forkId
 	ForkUpgradeNewForkId uint64 `mapstructure:"ForkUpgradeNewForkId"`
+	// Max time to stop the components gracefully when the node receives SIGINT or SIGTERM: the
+	// JSON-RPC server drains the in-flight requests, the sequencer closes the open batch and stores
+	// the processed txs. The node exits when it's reached even if they are not stopped
+	ShutdownTimeout types.Duration `mapstructure:"ShutdownTimeout"`
 	// Configure Log level for all the services, allow also to store the logs in a file
 	Log log.Config
 	// Configuration of the etherman (client for access L1)
@@ -111,12 +128,31 @@ type Config struct {
 	MTClient merkletree.Config
 	// Configuration of the metrics service, basically is where is going to publish the metrics
 	Metrics metrics.Config
+	// Configuration of the diagnostics server exposing the profiling endpoints, the goroutine
+	// dumps and the health of the components of the node
+	Diagnostics diagnostics.Config
+	// Configuration of the recording of the external inputs of the node (L1, pool submissions and
+	// executor responses) and their deterministic replay
+	Replay replay.Config
 	// Configuration of the event database connection
 	EventLog event.Config
 	// Configuration of the hash database connection
 	HashDB db.Config
 	// State service configuration
 	State state.Config
+	// Configuration of the guard checking the skew of the local clock against NTP servers
+	Clock clock.Config
+	// Configuration of the broker publishing the events of the new L2 blocks and batches
+	Broker broker.Config
+	// Configuration of the remote provider of the config, the values loaded from it
+	// override the ones of the config file
+	RemoteConfig remote.Config
+	// Configuration of the secret manager resolving the secret references of the passwords
+	// of the databases and the key stores
+	Secrets secrets.Config
+
+	// remoteVersion is the version of the remote config document loaded
+	remoteVersion string
 }
 
 // Default parses the default configuration values.
@@ -177,9 +213,123 @@ func Load(ctx *cli.Context, loadNetworkConfig bool) (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.RemoteConfig.Provider != "" {
+		err = cfg.loadRemoteConfig(ctx.Context, decodeHooks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = cfg.applyDeprecatedKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Secrets.Provider != "" {
+		err = cfg.resolveSecrets(ctx.Context)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if loadNetworkConfig {
 		// Load genesis parameters
 		cfg.loadNetworkConfig(ctx)
 	}
 	return cfg, nil
 }
+
+// applyDeprecatedKeys moves the values of the deprecated config keys to the keys that replaced them,
+// so the configs written for the previous versions keep working
+func (cfg *Config) applyDeprecatedKeys() error {
+	if viper.IsSet(deprecatedSequenceSenderL2CoinbaseKey) {
+		value := viper.GetString(deprecatedSequenceSenderL2CoinbaseKey)
+		if !common.IsHexAddress(value) {
+			return fmt.Errorf("invalid address %q in the deprecated %s", value, deprecatedSequenceSenderL2CoinbaseKey)
+		}
+		l2Coinbase := common.HexToAddress(value)
+		if cfg.Sequencer.L2Coinbase == (common.Address{}) {
+			cfg.Sequencer.L2Coinbase = l2Coinbase
+		} else if cfg.Sequencer.L2Coinbase != l2Coinbase {
+			return fmt.Errorf("the deprecated %s (%s) doesn't match Sequencer.L2Coinbase (%s), remove it",
+				deprecatedSequenceSenderL2CoinbaseKey, l2Coinbase.String(), cfg.Sequencer.L2Coinbase.String())
+		}
+		log.Warnf("%s is deprecated and will be removed, use Sequencer.L2Coinbase instead", deprecatedSequenceSenderL2CoinbaseKey)
+	}
+	return nil
+}
+
+// resolveSecrets replaces the secret references of the key store passwords with their values, as the key
+// stores are only decrypted at startup. The references of the database passwords are kept, they are
+// resolved for each new connection to use the rotated passwords, but they are checked to fail early
+func (cfg *Config) resolveSecrets(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	err := secrets.Init(cfg.Secrets)
+	if err != nil {
+		return err
+	}
+
+	keystores := []*types.KeystoreFileConfig{&cfg.SequenceSender.PrivateKey}
+	for i := range cfg.EthTxManager.PrivateKeys {
+		keystores = append(keystores, &cfg.EthTxManager.PrivateKeys[i])
+	}
+	for i := range cfg.RPC.DevMode.Accounts {
+		keystores = append(keystores, &cfg.RPC.DevMode.Accounts[i])
+	}
+	for _, keystore := range keystores {
+		keystore.Password, err = secrets.Resolve(ctx, keystore.Password)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the password of the key store %s: %w", keystore.Path, err)
+		}
+	}
+
+	dbs := []struct {
+		name string
+		cfg  db.Config
+	}{{"State", cfg.State.DB}, {"Pool", cfg.Pool.DB}, {"EventLog", cfg.EventLog.DB}, {"HashDB", cfg.HashDB}}
+	for _, d := range dbs {
+		if _, err := d.cfg.ResolvePassword(ctx); err != nil {
+			return fmt.Errorf("failed to resolve the password of the %s database: %w", d.name, err)
+		}
+	}
+	log.Infof("secret references resolved with the %s secret manager", cfg.Secrets.Provider)
+	return nil
+}
+
+// RemoteConfigVersion returns the version of the remote config document loaded,
+// empty if the remote config is not enabled
+func (cfg *Config) RemoteConfigVersion() string {
+	return cfg.remoteVersion
+}
+
+// loadRemoteConfig merges the config document of the remote provider over the
+// config file, the env vars still take precedence over it
+func (cfg *Config) loadRemoteConfig(ctx context.Context, decodeHooks []viper.DecoderConfigOption) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client, err := remote.NewClient(cfg.RemoteConfig)
+	if err != nil {
+		return err
+	}
+	doc, err := client.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load the remote config: %w", err)
+	}
+
+	viper.SetConfigType(cfg.RemoteConfig.Format)
+	err = viper.MergeConfig(bytes.NewReader(doc.Content))
+	if err != nil {
+		return fmt.Errorf("failed to merge the remote config: %w", err)
+	}
+	err = viper.Unmarshal(cfg, decodeHooks...)
+	if err != nil {
+		return err
+	}
+
+	cfg.remoteVersion = doc.Version
+	log.Infof("remote config loaded from %s provider, version: %s", cfg.RemoteConfig.Provider, doc.Version)
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/config"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,6 +41,38 @@ func Test_Defaults(t *testing.T) {
 			path:          "Synchronizer.SyncChunkSize",
 			expectedValue: uint64(100),
 		},
+		{
+			path:          "Synchronizer.ArchiveL1Logs",
+			expectedValue: false,
+		},
+		{
+			path:          "Synchronizer.TrustedSync.Enabled",
+			expectedValue: true,
+		},
+		{
+			path:          "Synchronizer.TrustedSync.HaltOnMismatch",
+			expectedValue: false,
+		},
+		{
+			path:          "Synchronizer.L1ReorgCheck.Enabled",
+			expectedValue: true,
+		},
+		{
+			path:          "Synchronizer.L1ReorgCheck.Interval",
+			expectedValue: types.NewDuration(1 * time.Minute),
+		},
+		{
+			path:          "Synchronizer.L1ReorgCheck.Depth",
+			expectedValue: uint64(64),
+		},
+		{
+			path:          "Synchronizer.AccInputHashCheck.Enabled",
+			expectedValue: true,
+		},
+		{
+			path:          "Synchronizer.AccInputHashCheck.Interval",
+			expectedValue: types.NewDuration(1 * time.Minute),
+		},
 		{
 			path:          "Sequencer.WaitPeriodPoolIsEmpty",
 			expectedValue: types.NewDuration(1 * time.Second),
@@ -60,6 +93,22 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.MaxTxLifetime",
 			expectedValue: types.NewDuration(3 * time.Hour),
 		},
+		{
+			path:          "Sequencer.MaxTxNotReadyTime",
+			expectedValue: types.NewDuration(0),
+		},
+		{
+			path:          "Sequencer.MaxWorkerTxs",
+			expectedValue: uint64(100000),
+		},
+		{
+			path:          "Sequencer.L2Coinbase",
+			expectedValue: common.Address{},
+		},
+		{
+			path:          "Sequencer.TxSorter",
+			expectedValue: "gasprice",
+		},
 		{
 			path:          "Sequencer.Finalizer.GERDeadlineTimeout",
 			expectedValue: types.NewDuration(5 * time.Second),
@@ -100,6 +149,154 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.Finalizer.StopSequencerOnBatchNum",
 			expectedValue: uint64(0),
 		},
+		{
+			path:          "Sequencer.Finalizer.MaxTimeWithoutBatches",
+			expectedValue: types.NewDuration(0 * time.Second),
+		},
+		{
+			path:          "Sequencer.Finalizer.BatchClosing.MaxIdleTime",
+			expectedValue: types.NewDuration(0 * time.Second),
+		},
+		{
+			path:          "Sequencer.Finalizer.BatchClosing.MaxOpenTime",
+			expectedValue: types.NewDuration(0 * time.Second),
+		},
+		{
+			path:          "Sequencer.Finalizer.BatchClosing.MinTxs",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "Sequencer.Finalizer.HaltPolicy.Mode",
+			expectedValue: "halt-and-alert",
+		},
+		{
+			path:          "Sequencer.Finalizer.HaltPolicy.Retry.MaxAttempts",
+			expectedValue: 4,
+		},
+		{
+			path:          "Sequencer.Finalizer.HaltPolicy.Retry.InitialBackoff",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "Sequencer.Finalizer.HaltPolicy.Retry.MaxBackoff",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "Sequencer.Finalizer.HaltPolicy.Retry.Multiplier",
+			expectedValue: float64(2),
+		},
+		{
+			path:          "Sequencer.Finalizer.HaltPolicy.Retry.Jitter",
+			expectedValue: float64(0.2),
+		},
+		{
+			path:          "Sequencer.Finalizer.Flush.Strategy",
+			expectedValue: "executor",
+		},
+		{
+			path:          "Sequencer.Finalizer.Flush.TxsInterval",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "Sequencer.Finalizer.PreWarm.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.Finalizer.PreWarm.MaxAge",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "Sequencer.Finalizer.CandidateSimulation.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.MaxProvingTime",
+			expectedValue: types.NewDuration(90 * time.Second),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.HistoryBatches",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.RefreshInterval",
+			expectedValue: types.NewDuration(1 * time.Minute),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.Weights.KeccakHashes",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.Weights.PoseidonHashes",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.Weights.PoseidonPaddings",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.Weights.MemAligns",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.Weights.Arithmetics",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.Weights.Binaries",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "Sequencer.Finalizer.ProvingBudget.Weights.Steps",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.LookAheadTxs",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.CumulativeGasUsed",
+			expectedValue: uint32(95),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.KeccakHashes",
+			expectedValue: uint32(95),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.PoseidonHashes",
+			expectedValue: uint32(95),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.PoseidonPaddings",
+			expectedValue: uint32(95),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.MemAligns",
+			expectedValue: uint32(95),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.Arithmetics",
+			expectedValue: uint32(95),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.Binaries",
+			expectedValue: uint32(95),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.Steps",
+			expectedValue: uint32(95),
+		},
+		{
+			path:          "Sequencer.Finalizer.UtilizationTargets.Targets.BatchBytesSize",
+			expectedValue: uint32(95),
+		},
 		{
 			path:          "Sequencer.Finalizer.TimestampResolution",
 			expectedValue: types.NewDuration(10 * time.Second),
@@ -112,6 +309,18 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.DBManager.L2ReorgRetrievalInterval",
 			expectedValue: types.NewDuration(5 * time.Second),
 		},
+		{
+			path:          "Sequencer.DBManager.StateCacheTTL",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "Sequencer.DBManager.RestoreWorkerOnStart",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.DBManager.RestoreWorkerTxsPerSecond",
+			expectedValue: uint64(1000),
+		},
 		{
 			path:          "Sequencer.StreamServer.Port",
 			expectedValue: uint16(0),
@@ -124,6 +333,58 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.StreamServer.Enabled",
 			expectedValue: false,
 		},
+		{
+			path:          "Sequencer.SelectionAudit.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.SelectionAudit.SamplingRate",
+			expectedValue: float64(0.1),
+		},
+		{
+			path:          "Sequencer.SelectionAudit.MaxCandidatesPerRound",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "Sequencer.SelectionAudit.FlushInterval",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "Sequencer.SelectionAudit.BufferSize",
+			expectedValue: uint64(10000),
+		},
+		{
+			path:          "Sequencer.SelectionAudit.RetentionPeriod",
+			expectedValue: types.NewDuration(24 * time.Hour),
+		},
+		{
+			path:          "Sequencer.TxRetry.MaxAttempts",
+			expectedValue: uint64(5),
+		},
+		{
+			path:          "Sequencer.TxRetry.InitialDelay",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "Sequencer.TxRetry.MaxDelay",
+			expectedValue: types.NewDuration(1 * time.Minute),
+		},
+		{
+			path:          "Sequencer.Policy.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.Repricing.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.Repricing.CheckInterval",
+			expectedValue: types.NewDuration(10 * time.Second),
+		},
+		{
+			path:          "Sequencer.Repricing.Hysteresis",
+			expectedValue: uint64(5),
+		},
 		{
 			path:          "SequenceSender.WaitPeriodSendSequence",
 			expectedValue: types.NewDuration(5 * time.Second),
@@ -149,270 +410,918 @@ func Test_Defaults(t *testing.T) {
 			expectedValue: uint64(5),
 		},
 		{
-			path:          "NetworkConfig.L1Config.ZkEVMAddr",
-			expectedValue: common.HexToAddress("0xa997cfD539E703921fD1e3Cf25b4c241a27a4c7A"),
+			path:          "NetworkConfig.L1Config.ZkEVMAddr",
+			expectedValue: common.HexToAddress("0xa997cfD539E703921fD1e3Cf25b4c241a27a4c7A"),
+		},
+		{
+			path:          "NetworkConfig.L1Config.MaticAddr",
+			expectedValue: common.HexToAddress("0x1319D23c2F7034F52Eb07399702B040bA278Ca49"),
+		},
+		{
+			path:          "NetworkConfig.L1Config.GlobalExitRootManagerAddr",
+			expectedValue: common.HexToAddress("0x4d9427DCA0406358445bC0a8F88C26b704004f74"),
+		},
+		{
+			path:          "Etherman.MultiGasProvider",
+			expectedValue: false,
+		},
+		{
+			path:          "Etherman.Retry.MaxAttempts",
+			expectedValue: 5,
+		},
+		{
+			path:          "Etherman.Retry.InitialBackoff",
+			expectedValue: types.NewDuration(500 * time.Millisecond),
+		},
+		{
+			path:          "Etherman.Retry.MaxBackoff",
+			expectedValue: types.NewDuration(10 * time.Second),
+		},
+		{
+			path:          "Etherman.Retry.Multiplier",
+			expectedValue: float64(2),
+		},
+		{
+			path:          "Etherman.Retry.Jitter",
+			expectedValue: float64(0.2),
+		},
+		{
+			path:          "Etherman.Retry.MaxElapsedTime",
+			expectedValue: types.NewDuration(1 * time.Minute),
+		},
+		{
+			path:          "Etherman.Failover.RequestTimeout",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "Etherman.Failover.HealthCheckInterval",
+			expectedValue: types.NewDuration(10 * time.Second),
+		},
+		{
+			path:          "Etherman.Failover.MaxBlocksBehind",
+			expectedValue: uint64(10),
+		},
+		{
+			path:          "Etherman.Failover.CrossCheckLogs",
+			expectedValue: false,
+		},
+		{
+			path:          "EthTxManager.FrequencyToMonitorTxs",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "EthTxManager.WaitTxToBeMined",
+			expectedValue: types.NewDuration(2 * time.Minute),
+		},
+		{
+			path:          "EthTxManager.WaitTxToBeMined",
+			expectedValue: types.NewDuration(2 * time.Minute),
+		},
+		{
+			path:          "EthTxManager.ForcedGas",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "EthTxManager.GasPriceMarginFactor",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "EthTxManager.MaxGasPriceLimit",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "EthTxManager.DynamicFeeTxs",
+			expectedValue: true,
+		},
+		{
+			path:          "EthTxManager.PriorityFeeStrategy",
+			expectedValue: "suggested",
+		},
+		{
+			path:          "EthTxManager.PriorityFee",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "EthTxManager.BaseFeeMultiplier",
+			expectedValue: float64(2),
+		},
+		{
+			path:          "EthTxManager.FeeBumpPercentage",
+			expectedValue: uint64(10),
+		},
+		{
+			path:          "L2GasPriceSuggester.DefaultGasPriceWei",
+			expectedValue: uint64(2000000000),
+		},
+		{
+			path:          "L2GasPriceSuggester.MaxGasPriceWei",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "MTClient.URI",
+			expectedValue: "zkevm-prover:50061",
+		},
+		{
+			path:          "MTClient.MaxGRPCMessageSize",
+			expectedValue: int(100000000),
+		},
+		{
+			path:          "MTClient.MaxGRPCSendMessageSize",
+			expectedValue: int(100000000),
+		},
+		{
+			path:          "MTClient.GRPCCompression",
+			expectedValue: "",
+		},
+		{
+			path:          "State.DB.User",
+			expectedValue: "state_user",
+		},
+		{
+			path:          "State.DB.Password",
+			expectedValue: "state_password",
+		},
+		{
+			path:          "State.DB.Name",
+			expectedValue: "state_db",
+		},
+		{
+			path:          "State.DB.Host",
+			expectedValue: "zkevm-state-db",
+		},
+		{
+			path:          "State.DB.Port",
+			expectedValue: "5432",
+		},
+		{
+			path:          "State.DB.EnableLog",
+			expectedValue: false,
+		},
+		{
+			path:          "State.DB.MaxConns",
+			expectedValue: 200,
+		},
+		{
+			path:          "State.DB.ReadReplica.Host",
+			expectedValue: "",
+		},
+		{
+			path:          "State.DB.ReadReplica.Port",
+			expectedValue: "5432",
+		},
+		{
+			path:          "State.DB.ReadReplica.MaxConns",
+			expectedValue: 200,
+		},
+		{
+			path:          "State.DB.ReadReplica.MaxLag",
+			expectedValue: types.NewDuration(10 * time.Second),
+		},
+		{
+			path:          "State.DB.ReadReplica.CheckInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "Pool.IntervalToRefreshGasPrices",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "Pool.Retry.MaxAttempts",
+			expectedValue: 3,
+		},
+		{
+			path:          "Pool.Retry.InitialBackoff",
+			expectedValue: types.NewDuration(100 * time.Millisecond),
+		},
+		{
+			path:          "Pool.Retry.MaxBackoff",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "Pool.Retry.Multiplier",
+			expectedValue: float64(2),
+		},
+		{
+			path:          "Pool.Retry.Jitter",
+			expectedValue: float64(0.2),
+		},
+		{
+			path:          "Pool.Retry.MaxElapsedTime",
+			expectedValue: types.NewDuration(0),
+		},
+		{
+			path:          "Pool.MaxTxBytesSize",
+			expectedValue: uint64(100132),
+		},
+		{
+			path:          "Pool.MaxTxDataBytesSize",
+			expectedValue: 100000,
+		},
+		{
+			path:          "Pool.DisablePreExecution",
+			expectedValue: false,
+		},
+
+		{
+			path:          "Pool.DefaultMinGasPriceAllowed",
+			expectedValue: uint64(1000000000),
+		},
+		{
+			path:          "Pool.MaxGasPriceAllowed",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "Pool.MaxGasPriceFactor",
+			expectedValue: float64(0),
+		},
+		{
+			path:          "Pool.MinAllowedGasPriceInterval",
+			expectedValue: types.NewDuration(5 * time.Minute),
+		},
+		{
+			path:          "Pool.PollMinAllowedGasPriceInterval",
+			expectedValue: types.NewDuration(15 * time.Second),
+		},
+		{
+			path:          "Pool.AccountQueue",
+			expectedValue: uint64(64),
+		},
+		{
+			path:          "Pool.GlobalQueue",
+			expectedValue: uint64(1024),
+		},
+		{
+			path:          "Pool.MaxNonceGappedTxsPerAccount",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "Pool.MaxTxsPerAccount",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "Pool.PriceBump",
+			expectedValue: uint64(10),
+		},
+		{
+			path:          "Pool.KnownTxsCacheSize",
+			expectedValue: int(0),
+		},
+		{
+			path:          "Pool.KnownTxsCacheTTL",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "Pool.StorageType",
+			expectedValue: "postgres",
+		},
+		{
+			path:          "Pool.SponsoredTxs.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Pool.SponsoredTxs.MaxGas",
+			expectedValue: uint64(500000),
+		},
+		{
+			path:          "Pool.SponsoredTxs.RateLimitPeriod",
+			expectedValue: types.NewDuration(1 * time.Hour),
+		},
+		{
+			path:          "Pool.SponsoredTxs.MaxTxsPerSender",
+			expectedValue: uint64(5),
+		},
+		{
+			path:          "Pool.SponsoredTxs.MaxTxs",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "Pool.SignatureValidation.Parallelism",
+			expectedValue: int(0),
+		},
+		{
+			path:          "Pool.SignatureValidation.QueueSize",
+			expectedValue: int(1000),
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.L1GasPriceFactor",
+			expectedValue: float64(0.25),
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.ByteGasCost",
+			expectedValue: uint64(16),
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.ZeroByteGasCost",
+			expectedValue: uint64(4),
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.NetProfit",
+			expectedValue: float64(1),
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.BreakEvenFactor",
+			expectedValue: float64(1.1),
+		},
+		{
+			path:          "Pool.EffectiveGasPrice.FinalDeviationPct",
+			expectedValue: uint64(10),
+		},
+		{
+			path:          "Pool.DB.User",
+			expectedValue: "pool_user",
+		},
+		{
+			path:          "Pool.DB.Password",
+			expectedValue: "pool_password",
+		},
+		{
+			path:          "Pool.DB.Name",
+			expectedValue: "pool_db",
+		},
+		{
+			path:          "Pool.DB.Host",
+			expectedValue: "zkevm-pool-db",
+		},
+		{
+			path:          "Pool.DB.Port",
+			expectedValue: "5432",
+		},
+		{
+			path:          "Pool.DB.EnableLog",
+			expectedValue: false,
+		},
+		{
+			path:          "Pool.DB.MaxConns",
+			expectedValue: 200,
+		},
+		{
+			path:          "Pool.DB.ReadReplica.Host",
+			expectedValue: "",
+		},
+		{
+			path:          "Pool.DB.ReadReplica.Port",
+			expectedValue: "5432",
+		},
+		{
+			path:          "Pool.DB.ReadReplica.MaxConns",
+			expectedValue: 200,
+		},
+		{
+			path:          "Pool.DB.ReadReplica.MaxLag",
+			expectedValue: types.NewDuration(10 * time.Second),
+		},
+		{
+			path:          "Pool.DB.ReadReplica.CheckInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
+		},
+		{
+			path:          "RPC.Host",
+			expectedValue: "0.0.0.0",
+		},
+		{
+			path:          "RPC.Port",
+			expectedValue: int(8545),
+		},
+		{
+			path:          "RPC.ReadTimeout",
+			expectedValue: types.NewDuration(60 * time.Second),
+		},
+		{
+			path:          "RPC.WriteTimeout",
+			expectedValue: types.NewDuration(60 * time.Second),
+		},
+		{
+			path:          "RPC.SequencerNodeURI",
+			expectedValue: "",
+		},
+		{
+			path:          "RPC.MaxRequestsPerIPAndSecond",
+			expectedValue: float64(500),
+		},
+		{
+			path:          "RPC.EnableL2SuggestedGasPricePolling",
+			expectedValue: true,
+		},
+		{
+			path:          "RPC.BatchRequestsEnabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.BatchRequestsLimit",
+			expectedValue: uint(20),
+		},
+		{
+			path:          "RPC.MaxLogsCount",
+			expectedValue: uint64(10000),
+		},
+		{
+			path:          "RPC.MaxLogsBlockRange",
+			expectedValue: uint64(10000),
+		},
+		{
+			path:          "RPC.MaxNativeBlockHashBlockRange",
+			expectedValue: uint64(60000),
+		},
+		{
+			path:          "RPC.EstimateGasCap",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "RPC.EstimateGasErrorRatio",
+			expectedValue: 0.015,
+		},
+		{
+			path:          "RPC.MaxFeeHistoryBlockCount",
+			expectedValue: uint64(1024),
+		},
+		{
+			path:          "RPC.MaxMulticallCalls",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "RPC.MulticallConcurrency",
+			expectedValue: uint64(8),
+		},
+		{
+			path:          "RPC.FilterTimeout",
+			expectedValue: types.NewDuration(5 * time.Minute),
+		},
+		{
+			path:          "RPC.FilterCleanupInterval",
+			expectedValue: types.NewDuration(1 * time.Minute),
+		},
+		{
+			path:          "RPC.MaxRequestContentLength",
+			expectedValue: int64(5242880),
+		},
+		{
+			path:          "RPC.MaxRawTransactionSize",
+			expectedValue: uint64(131072),
+		},
+		{
+			path:          "RPC.MaxTracerSize",
+			expectedValue: uint64(65536),
+		},
+		{
+			path:          "RPC.EnableHttpLog",
+			expectedValue: true,
+		},
+		{
+			path:          "RPC.EnabledApis",
+			expectedValue: []string{"eth", "net", "zkevm", "txpool", "web3"},
+		},
+		{
+			path:          "RPC.ReadOnly",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.AdminAuthToken",
+			expectedValue: "",
+		},
+		{
+			path:          "RPC.ArchiveMode",
+			expectedValue: true,
+		},
+		{
+			path:          "RPC.StateHistoryBlocks",
+			expectedValue: uint64(128),
+		},
+		{
+			path:          "RPC.EnableCompression",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.CORS.AllowedOrigins",
+			expectedValue: []string{"*"},
+		},
+		{
+			path:          "RPC.CORS.AllowedHeaders",
+			expectedValue: []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization"},
+		},
+		{
+			path:          "RPC.TLS.CertFile",
+			expectedValue: "",
+		},
+		{
+			path:          "RPC.TLS.KeyFile",
+			expectedValue: "",
+		},
+		{
+			path:          "RPC.WebSockets.Enabled",
+			expectedValue: true,
+		},
+		{
+			path:          "RPC.WebSockets.Host",
+			expectedValue: "0.0.0.0",
+		},
+		{
+			path:          "RPC.WebSockets.Port",
+			expectedValue: int(8546),
+		},
+		{
+			path:          "RPC.WebSockets.ReadLimit",
+			expectedValue: int64(104857600),
+		},
+		{
+			path:          "RPC.WebSockets.DroppedTxsPollingInterval",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "RPC.WebSockets.PendingTxsPollingInterval",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "RPC.WebSockets.AccountChangesPollingInterval",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "RPC.IPC.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.IPC.Path",
+			expectedValue: "zkevm-node.ipc",
+		},
+		{
+			path:          "RPC.IPC.Permissions",
+			expectedValue: "0600",
+		},
+		{
+			path:          "RPC.DevMode.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.RateLimit.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.RateLimit.RequestsPerSecond",
+			expectedValue: float64(100),
+		},
+		{
+			path:          "RPC.RateLimit.Burst",
+			expectedValue: 200,
+		},
+		{
+			path:          "RPC.TraceQueue.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.TraceQueue.MaxConcurrentTraces",
+			expectedValue: uint64(4),
+		},
+		{
+			path:          "RPC.TraceQueue.MaxQueuedTraces",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "RPC.TraceQueue.QueueTimeout",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "RPC.TraceQueue.AsyncEnabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.TraceQueue.ResultsRetention",
+			expectedValue: types.NewDuration(10 * time.Minute),
+		},
+		{
+			path:          "RPC.TraceQueue.MaxStoredJobs",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "RPC.Witness.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.Witness.MaxAccounts",
+			expectedValue: uint64(10000),
+		},
+		{
+			path:          "RPC.Witness.MaxStorageSlots",
+			expectedValue: uint64(100000),
+		},
+		{
+			path:          "RPC.Witness.Queue.Enabled",
+			expectedValue: true,
+		},
+		{
+			path:          "RPC.Witness.Queue.MaxConcurrentTraces",
+			expectedValue: uint64(2),
+		},
+		{
+			path:          "RPC.Witness.Queue.MaxQueuedTraces",
+			expectedValue: uint64(20),
+		},
+		{
+			path:          "RPC.Witness.Queue.QueueTimeout",
+			expectedValue: types.NewDuration(time.Minute),
+		},
+		{
+			path:          "RPC.Witness.Queue.AsyncEnabled",
+			expectedValue: true,
+		},
+		{
+			path:          "RPC.Witness.Queue.ResultsRetention",
+			expectedValue: types.NewDuration(10 * time.Minute),
+		},
+		{
+			path:          "RPC.Witness.Queue.MaxStoredJobs",
+			expectedValue: uint64(100),
+		},
+		{
+			path:          "RPC.ConcurrencyLimit.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "RPC.ConcurrencyLimit.MaxInFlightRequests",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "RPC.ConcurrencyLimit.MaxConcurrentRequestsPerIP",
+			expectedValue: uint64(50),
+		},
+		{
+			path:          "RPC.HealthCheck.Enabled",
+			expectedValue: true,
+		},
+		{
+			path:          "RPC.HealthCheck.Timeout",
+			expectedValue: types.NewDuration(5 * time.Second),
 		},
 		{
-			path:          "NetworkConfig.L1Config.MaticAddr",
-			expectedValue: common.HexToAddress("0x1319D23c2F7034F52Eb07399702B040bA278Ca49"),
+			path:          "RPC.HealthCheck.MaxL2BlocksBehind",
+			expectedValue: uint64(60),
 		},
 		{
-			path:          "NetworkConfig.L1Config.GlobalExitRootManagerAddr",
-			expectedValue: common.HexToAddress("0x4d9427DCA0406358445bC0a8F88C26b704004f74"),
+			path:          "RPC.HealthCheck.MaxBatchesBehind",
+			expectedValue: uint64(10),
 		},
 		{
-			path:          "Etherman.MultiGasProvider",
+			path:          "RPC.WarmUp.Enabled",
 			expectedValue: false,
 		},
 		{
-			path:          "EthTxManager.FrequencyToMonitorTxs",
-			expectedValue: types.NewDuration(1 * time.Second),
+			path:          "RPC.WarmUp.MaxBatchesBehind",
+			expectedValue: uint64(10),
 		},
 		{
-			path:          "EthTxManager.WaitTxToBeMined",
-			expectedValue: types.NewDuration(2 * time.Minute),
+			path:          "RPC.WarmUp.CheckInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
 		},
 		{
-			path:          "EthTxManager.WaitTxToBeMined",
-			expectedValue: types.NewDuration(2 * time.Minute),
+			path:          "RPC.AuditLog.Enabled",
+			expectedValue: false,
 		},
 		{
-			path:          "EthTxManager.ForcedGas",
-			expectedValue: uint64(0),
+			path:          "RPC.AuditLog.Filename",
+			expectedValue: "rpc-audit.log",
 		},
 		{
-			path:          "EthTxManager.GasPriceMarginFactor",
+			path:          "RPC.AuditLog.SampleRate",
 			expectedValue: float64(1),
 		},
 		{
-			path:          "EthTxManager.MaxGasPriceLimit",
-			expectedValue: uint64(0),
+			path:          "RPC.AuditLog.LogParams",
+			expectedValue: false,
 		},
 		{
-			path:          "L2GasPriceSuggester.DefaultGasPriceWei",
-			expectedValue: uint64(2000000000),
+			path:          "RPC.AuditLog.MaxSizeMB",
+			expectedValue: int64(100),
 		},
 		{
-			path:          "L2GasPriceSuggester.MaxGasPriceWei",
-			expectedValue: uint64(0),
+			path:          "RPC.AuditLog.MaxBackups",
+			expectedValue: 5,
 		},
 		{
-			path:          "MTClient.URI",
-			expectedValue: "zkevm-prover:50061",
+			path:          "RPC.ShutdownTimeout",
+			expectedValue: types.NewDuration(30 * time.Second),
 		},
 		{
-			path:          "State.DB.User",
-			expectedValue: "state_user",
+			path:          "Executor.URI",
+			expectedValue: "zkevm-prover:50071",
 		},
 		{
-			path:          "State.DB.Password",
-			expectedValue: "state_password",
+			path:          "Executor.MaxResourceExhaustedAttempts",
+			expectedValue: 3,
 		},
 		{
-			path:          "State.DB.Name",
-			expectedValue: "state_db",
+			path:          "Executor.WaitOnResourceExhaustion",
+			expectedValue: types.NewDuration(1 * time.Second),
 		},
 		{
-			path:          "State.DB.Host",
-			expectedValue: "zkevm-state-db",
+			path:          "Executor.MaxGRPCMessageSize",
+			expectedValue: int(100000000),
 		},
 		{
-			path:          "State.DB.Port",
-			expectedValue: "5432",
+			path:          "Executor.MaxGRPCSendMessageSize",
+			expectedValue: int(100000000),
 		},
 		{
-			path:          "State.DB.EnableLog",
-			expectedValue: false,
+			path:          "Executor.GRPCCompression",
+			expectedValue: "",
 		},
 		{
-			path:          "State.DB.MaxConns",
-			expectedValue: 200,
+			path:          "Executor.Connections",
+			expectedValue: 1,
 		},
 		{
-			path:          "Pool.IntervalToRefreshGasPrices",
-			expectedValue: types.NewDuration(5 * time.Second),
+			path:          "Executor.RequestTimeout",
+			expectedValue: types.NewDuration(60 * time.Second),
 		},
 		{
-			path:          "Pool.MaxTxBytesSize",
-			expectedValue: uint64(100132),
+			path:          "Executor.Retry.MaxAttempts",
+			expectedValue: 3,
 		},
 		{
-			path:          "Pool.MaxTxDataBytesSize",
-			expectedValue: 100000,
+			path:          "Executor.Retry.InitialBackoff",
+			expectedValue: types.NewDuration(100 * time.Millisecond),
 		},
-
 		{
-			path:          "Pool.DefaultMinGasPriceAllowed",
-			expectedValue: uint64(1000000000),
+			path:          "Executor.Retry.MaxBackoff",
+			expectedValue: types.NewDuration(1 * time.Second),
 		},
 		{
-			path:          "Pool.MinAllowedGasPriceInterval",
-			expectedValue: types.NewDuration(5 * time.Minute),
+			path:          "Executor.Retry.Multiplier",
+			expectedValue: float64(2),
 		},
 		{
-			path:          "Pool.PollMinAllowedGasPriceInterval",
-			expectedValue: types.NewDuration(15 * time.Second),
+			path:          "Executor.Retry.Jitter",
+			expectedValue: float64(0.2),
 		},
 		{
-			path:          "Pool.AccountQueue",
-			expectedValue: uint64(64),
+			path:          "Executor.Retry.MaxElapsedTime",
+			expectedValue: types.NewDuration(0),
 		},
 		{
-			path:          "Pool.GlobalQueue",
-			expectedValue: uint64(1024),
+			path:          "Executor.CircuitBreaker.FailureThreshold",
+			expectedValue: 5,
 		},
 		{
-			path:          "Pool.EffectiveGasPrice.Enabled",
-			expectedValue: false,
+			path:          "Executor.CircuitBreaker.OpenTimeout",
+			expectedValue: types.NewDuration(10 * time.Second),
 		},
 		{
-			path:          "Pool.EffectiveGasPrice.L1GasPriceFactor",
-			expectedValue: float64(0.25),
+			path:          "Executor.Debug.TracesDir",
+			expectedValue: "/tmp/zkevm-node/executor-traces",
 		},
 		{
-			path:          "Pool.EffectiveGasPrice.ByteGasCost",
-			expectedValue: uint64(16),
+			path:          "Executor.Debug.MaxOverrideDuration",
+			expectedValue: types.NewDuration(time.Hour),
 		},
 		{
-			path:          "Pool.EffectiveGasPrice.ZeroByteGasCost",
-			expectedValue: uint64(4),
+			path:          "Executor.Debug.Sequencer",
+			expectedValue: executor.DebugFlags{},
 		},
 		{
-			path:          "Pool.EffectiveGasPrice.NetProfit",
-			expectedValue: float64(1),
+			path:          "Executor.Debug.Synchronizer",
+			expectedValue: executor.DebugFlags{},
 		},
 		{
-			path:          "Pool.EffectiveGasPrice.BreakEvenFactor",
-			expectedValue: float64(1.1),
+			path:          "Executor.Debug.RPC",
+			expectedValue: executor.DebugFlags{},
 		},
 		{
-			path:          "Pool.EffectiveGasPrice.FinalDeviationPct",
-			expectedValue: uint64(10),
+			path:          "Metrics.Host",
+			expectedValue: "0.0.0.0",
 		},
 		{
-			path:          "Pool.DB.User",
-			expectedValue: "pool_user",
+			path:          "Metrics.Port",
+			expectedValue: 9091,
 		},
 		{
-			path:          "Pool.DB.Password",
-			expectedValue: "pool_password",
+			path:          "Metrics.Enabled",
+			expectedValue: false,
 		},
 		{
-			path:          "Pool.DB.Name",
-			expectedValue: "pool_db",
+			path:          "Diagnostics.Enabled",
+			expectedValue: false,
 		},
 		{
-			path:          "Pool.DB.Host",
-			expectedValue: "zkevm-pool-db",
+			path:          "Diagnostics.Host",
+			expectedValue: "0.0.0.0",
 		},
 		{
-			path:          "Pool.DB.Port",
-			expectedValue: "5432",
+			path:          "Diagnostics.Port",
+			expectedValue: 6061,
 		},
 		{
-			path:          "Pool.DB.EnableLog",
-			expectedValue: false,
+			path:          "Diagnostics.CheckTimeout",
+			expectedValue: types.NewDuration(5 * time.Second),
 		},
 		{
-			path:          "Pool.DB.MaxConns",
-			expectedValue: 200,
+			path:          "Diagnostics.MaxL1BlocksBehind",
+			expectedValue: uint64(0),
 		},
 		{
-			path:          "RPC.Host",
-			expectedValue: "0.0.0.0",
+			path:          "Diagnostics.MaxBatchesBehind",
+			expectedValue: uint64(0),
 		},
 		{
-			path:          "RPC.Port",
-			expectedValue: int(8545),
+			path:          "Diagnostics.MaxLastBatchAge",
+			expectedValue: types.NewDuration(0),
 		},
 		{
-			path:          "RPC.ReadTimeout",
+			path:          "ShutdownTimeout",
 			expectedValue: types.NewDuration(60 * time.Second),
 		},
 		{
-			path:          "RPC.WriteTimeout",
-			expectedValue: types.NewDuration(60 * time.Second),
+			path:          "Replay.Mode",
+			expectedValue: "",
 		},
 		{
-			path:          "RPC.SequencerNodeURI",
+			path:          "Replay.File",
 			expectedValue: "",
 		},
 		{
-			path:          "RPC.MaxRequestsPerIPAndSecond",
-			expectedValue: float64(500),
+			path:          "Replay.Window",
+			expectedValue: types.NewDuration(0),
 		},
 		{
-			path:          "RPC.EnableL2SuggestedGasPricePolling",
-			expectedValue: true,
+			path:          "RemoteConfig.Provider",
+			expectedValue: "",
 		},
 		{
-			path:          "RPC.BatchRequestsEnabled",
-			expectedValue: false,
+			path:          "RemoteConfig.Format",
+			expectedValue: "toml",
 		},
 		{
-			path:          "RPC.BatchRequestsLimit",
-			expectedValue: uint(20),
+			path:          "RemoteConfig.RequestTimeout",
+			expectedValue: types.NewDuration(10 * time.Second),
 		},
 		{
-			path:          "RPC.MaxLogsCount",
-			expectedValue: uint64(10000),
+			path:          "RemoteConfig.WatchInterval",
+			expectedValue: types.NewDuration(30 * time.Second),
 		},
 		{
-			path:          "RPC.MaxLogsBlockRange",
-			expectedValue: uint64(10000),
+			path:          "RemoteConfig.ExitOnChange",
+			expectedValue: false,
 		},
 		{
-			path:          "RPC.MaxNativeBlockHashBlockRange",
-			expectedValue: uint64(60000),
+			path:          "Secrets.Provider",
+			expectedValue: "",
 		},
 		{
-			path:          "RPC.EnableHttpLog",
-			expectedValue: true,
+			path:          "Secrets.RequestTimeout",
+			expectedValue: types.NewDuration(10 * time.Second),
 		},
 		{
-			path:          "RPC.WebSockets.Enabled",
-			expectedValue: true,
+			path:          "Secrets.RefreshInterval",
+			expectedValue: types.NewDuration(5 * time.Minute),
 		},
 		{
-			path:          "RPC.WebSockets.Host",
-			expectedValue: "0.0.0.0",
+			path:          "Clock.SkewCheckEnabled",
+			expectedValue: false,
 		},
 		{
-			path:          "RPC.WebSockets.Port",
-			expectedValue: int(8546),
+			path:          "Clock.NTPServers",
+			expectedValue: []string{"pool.ntp.org", "time.google.com"},
 		},
 		{
-			path:          "RPC.WebSockets.ReadLimit",
-			expectedValue: int64(104857600),
+			path:          "Clock.MaxSkew",
+			expectedValue: types.NewDuration(time.Second),
 		},
 		{
-			path:          "Executor.URI",
-			expectedValue: "zkevm-prover:50071",
+			path:          "Clock.CheckInterval",
+			expectedValue: types.NewDuration(10 * time.Minute),
 		},
 		{
-			path:          "Executor.MaxResourceExhaustedAttempts",
-			expectedValue: 3,
+			path:          "Clock.Timeout",
+			expectedValue: types.NewDuration(5 * time.Second),
 		},
 		{
-			path:          "Executor.WaitOnResourceExhaustion",
-			expectedValue: types.NewDuration(1 * time.Second),
+			path:          "Clock.HaltOnStartup",
+			expectedValue: false,
 		},
 		{
-			path:          "Executor.MaxGRPCMessageSize",
-			expectedValue: int(100000000),
+			path:          "Broker.Enabled",
+			expectedValue: false,
 		},
 		{
-			path:          "Metrics.Host",
-			expectedValue: "0.0.0.0",
+			path:          "Broker.SinkBufferSize",
+			expectedValue: 1000,
 		},
 		{
-			path:          "Metrics.Port",
-			expectedValue: 9091,
+			path:          "Broker.BatchStatusPollInterval",
+			expectedValue: types.NewDuration(5 * time.Second),
 		},
 		{
-			path:          "Metrics.Enabled",
+			path:          "Broker.GRPC.Enabled",
 			expectedValue: false,
 		},
+		{
+			path:          "Broker.GRPC.Host",
+			expectedValue: "0.0.0.0",
+		},
+		{
+			path:          "Broker.GRPC.Port",
+			expectedValue: 50091,
+		},
 		{
 			path:          "Aggregator.Host",
 			expectedValue: "0.0.0.0",
@@ -449,10 +1358,82 @@ func Test_Defaults(t *testing.T) {
 			path:          "Aggregator.GeneratingProofCleanupThreshold",
 			expectedValue: "10m",
 		},
+		{
+			path:          "Aggregator.BatchProofTimeout",
+			expectedValue: types.NewDuration(0),
+		},
+		{
+			path:          "Aggregator.MaxProverStatusFailures",
+			expectedValue: 10,
+		},
+		{
+			path:          "Aggregator.MaxAggregatedBatches",
+			expectedValue: uint64(0),
+		},
 		{
 			path:          "Aggregator.GasOffset",
 			expectedValue: uint64(0),
 		},
+		{
+			path:          "Aggregator.CheckBatchDataIntegrity",
+			expectedValue: true,
+		},
+		{
+			path:          "Aggregator.FallbackProvers.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Aggregator.FallbackProvers.MaxQueueDepth",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "Aggregator.FallbackProvers.MaxPendingBatchAge",
+			expectedValue: types.NewDuration(0 * time.Second),
+		},
+		{
+			path:          "Aggregator.FallbackProvers.MaxConcurrentProofs",
+			expectedValue: 0,
+		},
+		{
+			path:          "Aggregator.Artifacts.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Aggregator.Artifacts.Endpoint",
+			expectedValue: "",
+		},
+		{
+			path:          "Aggregator.Artifacts.Region",
+			expectedValue: "",
+		},
+		{
+			path:          "Aggregator.Artifacts.Bucket",
+			expectedValue: "",
+		},
+		{
+			path:          "Aggregator.Artifacts.Prefix",
+			expectedValue: "",
+		},
+		{
+			path:          "Aggregator.Artifacts.AccessKeyID",
+			expectedValue: "",
+		},
+		{
+			path:          "Aggregator.Artifacts.SecretAccessKey",
+			expectedValue: "",
+		},
+		{
+			path:          "Aggregator.Artifacts.RequestTimeout",
+			expectedValue: types.NewDuration(30 * time.Second),
+		},
+		{
+			path:          "Aggregator.Artifacts.RetentionPeriod",
+			expectedValue: types.NewDuration(0 * time.Second),
+		},
+		{
+			path:          "Aggregator.Artifacts.RetentionCheckInterval",
+			expectedValue: types.NewDuration(1 * time.Hour),
+		},
 		{
 			path:          "State.Batch.Constraints.MaxTxsPerBatch",
 			expectedValue: uint64(300),
@@ -493,6 +1474,22 @@ func Test_Defaults(t *testing.T) {
 			path:          "State.Batch.Constraints.MaxSteps",
 			expectedValue: uint32(7570538),
 		},
+		{
+			path:          "State.BridgeIndexing.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "State.Pruning.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "State.Pruning.Interval",
+			expectedValue: types.NewDuration(time.Hour),
+		},
+		{
+			path:          "State.Pruning.Retention",
+			expectedValue: types.NewDuration(720 * time.Hour),
+		},
 	}
 	file, err := os.CreateTemp("", "genesisConfig")
 	require.NoError(t, err)
@@ -556,3 +1553,39 @@ func TestEnvVarArrayDecoding(t *testing.T) {
 	assert.Equal(t, "b", cfg.Log.Outputs[1])
 	assert.Equal(t, "c", cfg.Log.Outputs[2])
 }
+
+func TestDeprecatedSequenceSenderL2Coinbase(t *testing.T) {
+	file, err := os.CreateTemp("", "genesisConfig")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Remove(file.Name()))
+	}()
+	require.NoError(t, os.WriteFile(file.Name(), []byte("{}"), 0600))
+	flagSet := flag.NewFlagSet("", flag.PanicOnError)
+	flagSet.String(config.FlagNetwork, "testnet", "")
+	ctx := cli.NewContext(cli.NewApp(), flagSet, nil)
+
+	legacy := "0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D"
+	os.Setenv("ZKEVM_NODE_SEQUENCESENDER_L2COINBASE", legacy)
+	defer func() {
+		os.Unsetenv("ZKEVM_NODE_SEQUENCESENDER_L2COINBASE")
+		os.Unsetenv("ZKEVM_NODE_SEQUENCER_L2COINBASE")
+	}()
+
+	cfg, err := config.Load(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(legacy), cfg.Sequencer.L2Coinbase)
+
+	os.Setenv("ZKEVM_NODE_SEQUENCER_L2COINBASE", legacy)
+	cfg, err = config.Load(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(legacy), cfg.Sequencer.L2Coinbase)
+
+	os.Setenv("ZKEVM_NODE_SEQUENCER_L2COINBASE", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	_, err = config.Load(ctx, true)
+	require.Error(t, err)
+
+	os.Setenv("ZKEVM_NODE_SEQUENCESENDER_L2COINBASE", "invalid")
+	_, err = config.Load(ctx, true)
+	require.Error(t, err)
+}
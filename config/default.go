@@ -5,6 +5,7 @@ const DefaultValues = `
 IsTrustedSequencer = false
 ForkUpgradeBatchNumber = 0
 ForkUpgradeNewForkId = 0
+ShutdownTimeout = "60s"
 
 [Log]
 Environment = "development" # "production" or "development"
@@ -21,6 +22,12 @@ AccountQueue = 64
 	Port = "5432"
 	EnableLog = false	
 	MaxConns = 200
+		[State.DB.ReadReplica]
+		Host = ""
+		Port = "5432"
+		MaxConns = 200
+		MaxLag = "10s"
+		CheckInterval = "5s"
 	[State.Batch]
 		[State.Batch.Constraints]
 		MaxTxsPerBatch = 300
@@ -33,17 +40,51 @@ AccountQueue = 64
 		MaxArithmetics = 236585
 		MaxBinaries = 473170
 		MaxSteps = 7570538
+	[State.BridgeIndexing]
+		Enabled = false
+	[State.Pruning]
+		Enabled = false
+		Interval = "1h"
+		Retention = "720h"
 
 [Pool]
 IntervalToRefreshBlockedAddresses = "5m"
 IntervalToRefreshGasPrices = "5s"
 MaxTxBytesSize=100132
 MaxTxDataBytesSize=100000
+DisablePreExecution = false
 DefaultMinGasPriceAllowed = 1000000000
+MaxGasPriceAllowed = 0
+MaxGasPriceFactor = 0
 MinAllowedGasPriceInterval = "5m"
 PollMinAllowedGasPriceInterval = "15s"
 AccountQueue = 64
 GlobalQueue = 1024
+MaxNonceGappedTxsPerAccount = 0
+MaxTxsPerAccount = 0
+PriceBump = 10
+KnownTxsCacheSize = 0
+KnownTxsCacheTTL = "30s"
+StorageType = "postgres"
+TxTags = []
+    [Pool.Retry]
+	MaxAttempts = 3
+	InitialBackoff = "100ms"
+	MaxBackoff = "1s"
+	Multiplier = 2
+	Jitter = 0.2
+	MaxElapsedTime = "0s"
+    [Pool.SponsoredTxs]
+	Enabled = false
+	Contracts = []
+	Selectors = []
+	MaxGas = 500000
+	RateLimitPeriod = "1h"
+	MaxTxsPerSender = 5
+	MaxTxs = 1000
+    [Pool.SignatureValidation]
+	Parallelism = 0
+	QueueSize = 1000
     [Pool.EffectiveGasPrice]
 	Enabled = false
 	L1GasPriceFactor = 0.25
@@ -60,13 +101,32 @@ GlobalQueue = 1024
 	Port = "5432"
 	EnableLog = false
 	MaxConns = 200
+		[Pool.DB.ReadReplica]
+		Host = ""
+		Port = "5432"
+		MaxConns = 200
+		MaxLag = "10s"
+		CheckInterval = "5s"
 
 [Etherman]
 URL = "http://localhost:8545"
+FallbackURLs = []
 ForkIDChunkSize = 20000
 MultiGasProvider = false
 	[Etherman.Etherscan]
 		ApiKey = ""
+	[Etherman.Retry]
+		MaxAttempts = 5
+		InitialBackoff = "500ms"
+		MaxBackoff = "10s"
+		Multiplier = 2
+		Jitter = 0.2
+		MaxElapsedTime = "1m"
+	[Etherman.Failover]
+		RequestTimeout = "30s"
+		HealthCheckInterval = "10s"
+		MaxBlocksBehind = 10
+		CrossCheckLogs = false
 
 [EthTxManager]
 FrequencyToMonitorTxs = "1s"
@@ -74,6 +134,11 @@ WaitTxToBeMined = "2m"
 ForcedGas = 0
 GasPriceMarginFactor = 1
 MaxGasPriceLimit = 0
+DynamicFeeTxs = true
+PriorityFeeStrategy = "suggested"
+PriorityFee = 0
+BaseFeeMultiplier = 2
+FeeBumpPercentage = 10
 
 [RPC]
 Host = "0.0.0.0"
@@ -88,18 +153,112 @@ BatchRequestsLimit = 20
 MaxLogsCount = 10000
 MaxLogsBlockRange = 10000
 MaxNativeBlockHashBlockRange = 60000
+EstimateGasCap = 0
+EstimateGasErrorRatio = 0.015
+MaxFeeHistoryBlockCount = 1024
+MaxMulticallCalls = 100
+MulticallConcurrency = 8
+FilterTimeout = "5m"
+FilterCleanupInterval = "1m"
+MaxRequestContentLength = 5242880
+MaxRawTransactionSize = 131072
+MaxTracerSize = 65536
 EnableHttpLog = true
+EnabledApis = ["eth", "net", "zkevm", "txpool", "web3"]
+DisabledMethods = []
+ReadOnly = false
+AdminAuthToken = ""
+ArchiveMode = true
+StateHistoryBlocks = 128
+EnableCompression = false
+ShutdownTimeout = "30s"
+TrustedProxies = []
 	[RPC.WebSockets]
 		Enabled = true
 		Host = "0.0.0.0"
 		Port = 8546
 		ReadLimit = 104857600
+		DroppedTxsPollingInterval = "1s"
+		PendingTxsPollingInterval = "1s"
+		AccountChangesPollingInterval = "1s"
+	[RPC.IPC]
+		Enabled = false
+		Path = "zkevm-node.ipc"
+		Permissions = "0600"
+	[RPC.DevMode]
+		Enabled = false
+		Accounts = []
+	[RPC.RateLimit]
+		Enabled = false
+		RequestsPerSecond = 100
+		Burst = 200
+		Methods = []
+		TrustedIPs = []
+	[RPC.ConcurrencyLimit]
+		Enabled = false
+		MaxInFlightRequests = 1000
+		MaxConcurrentRequestsPerIP = 50
+		TrustedIPs = []
+	[RPC.TraceQueue]
+		Enabled = false
+		MaxConcurrentTraces = 4
+		MaxQueuedTraces = 100
+		QueueTimeout = "30s"
+		AsyncEnabled = false
+		ResultsRetention = "10m"
+		MaxStoredJobs = 1000
+	[RPC.Witness]
+		Enabled = false
+		MaxAccounts = 10000
+		MaxStorageSlots = 100000
+		[RPC.Witness.Queue]
+			Enabled = true
+			MaxConcurrentTraces = 2
+			MaxQueuedTraces = 20
+			QueueTimeout = "1m"
+			AsyncEnabled = true
+			ResultsRetention = "10m"
+			MaxStoredJobs = 100
+	[RPC.HealthCheck]
+		Enabled = true
+		Timeout = "5s"
+		MaxL2BlocksBehind = 60
+		MaxBatchesBehind = 10
+	[RPC.WarmUp]
+		Enabled = false
+		MaxBatchesBehind = 10
+		CheckInterval = "5s"
+	[RPC.AuditLog]
+		Enabled = false
+		Filename = "rpc-audit.log"
+		SampleRate = 1
+		Namespaces = []
+		LogParams = false
+		MaxSizeMB = 100
+		MaxBackups = 5
+	[RPC.CORS]
+		AllowedOrigins = ["*"]
+		AllowedHeaders = ["Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization"]
+	[RPC.TLS]
+		CertFile = ""
+		KeyFile = ""
 
 [Synchronizer]
 SyncInterval = "1s"
 SyncChunkSize = 100
 TrustedSequencerURL = "" # If it is empty or not specified, then the value is read from the smc
+ArchiveL1Logs = false
 UseParallelModeForL1Synchronization = true
+	[Synchronizer.TrustedSync]
+		Enabled = true
+		HaltOnMismatch = false
+	[Synchronizer.L1ReorgCheck]
+		Enabled = true
+		Interval = "1m"
+		Depth = 64
+	[Synchronizer.AccInputHashCheck]
+		Enabled = true
+		Interval = "1m"
 	[Synchronizer.L1ParallelSynchronization]
 		NumberOfParallelOfEthereumClients = 10
 		CapacityOfBufferingRollupInfoFromL1 = 25
@@ -120,6 +279,10 @@ BlocksAmountForTxsToBeDeleted = 100
 FrequencyToCheckTxsForDelete = "12h"
 TxLifetimeCheckTimeout = "10m"
 MaxTxLifetime = "3h"
+MaxTxNotReadyTime = "0s"
+L2Coinbase = "0x0000000000000000000000000000000000000000"
+MaxWorkerTxs = 100000
+TxSorter = "gasprice"
 	[Sequencer.Finalizer]
 		GERDeadlineTimeout = "5s"
 		ForcedBatchDeadlineTimeout = "60s"
@@ -133,19 +296,90 @@ MaxTxLifetime = "3h"
 		TimestampResolution = "10s"
 		StopSequencerOnBatchNum = 0
 		SequentialReprocessFullBatch = false
+		MaxTimeWithoutBatches = "0s"
+		SequencingWindows = []
+		[Sequencer.Finalizer.BatchClosing]
+			MaxIdleTime = "0s"
+			MaxOpenTime = "0s"
+			MinTxs = 0
+		[Sequencer.Finalizer.HaltPolicy]
+			Mode = "halt-and-alert"
+			[Sequencer.Finalizer.HaltPolicy.Retry]
+				MaxAttempts = 4
+				InitialBackoff = "1s"
+				MaxBackoff = "30s"
+				Multiplier = 2
+				Jitter = 0.2
+				MaxElapsedTime = "0s"
+		[Sequencer.Finalizer.Flush]
+			Strategy = "executor"
+			TxsInterval = 100
+		[Sequencer.Finalizer.PreWarm]
+			Enabled = false
+			MaxAge = "1s"
+		[Sequencer.Finalizer.CandidateSimulation]
+			Enabled = false
+		[Sequencer.Finalizer.ProvingBudget]
+			Enabled = false
+			MaxProvingTime = "90s"
+			HistoryBatches = 100
+			RefreshInterval = "1m"
+			[Sequencer.Finalizer.ProvingBudget.Weights]
+				KeccakHashes = 1
+				PoseidonHashes = 1
+				PoseidonPaddings = 1
+				MemAligns = 1
+				Arithmetics = 1
+				Binaries = 1
+				Steps = 1
+		[Sequencer.Finalizer.UtilizationTargets]
+			Enabled = false
+			LookAheadTxs = 100
+			[Sequencer.Finalizer.UtilizationTargets.Targets]
+				CumulativeGasUsed = 95
+				KeccakHashes = 95
+				PoseidonHashes = 95
+				PoseidonPaddings = 95
+				MemAligns = 95
+				Arithmetics = 95
+				Binaries = 95
+				Steps = 95
+				BatchBytesSize = 95
 	[Sequencer.DBManager]
 		PoolRetrievalInterval = "500ms"
 		L2ReorgRetrievalInterval = "5s"
+		StateCacheTTL = "1s"
+		RestoreWorkerOnStart = false
+		RestoreWorkerTxsPerSecond = 1000
 	[Sequencer.StreamServer]
 		Port = 0
 		Filename = ""
 		Enabled = false
+	[Sequencer.SelectionAudit]
+		Enabled = false
+		SamplingRate = 0.1
+		MaxCandidatesPerRound = 100
+		FlushInterval = "1s"
+		BufferSize = 10000
+		RetentionPeriod = "24h"
+	[Sequencer.TxRetry]
+		MaxAttempts = 5
+		InitialDelay = "1s"
+		MaxDelay = "1m"
+	[Sequencer.Policy]
+		Enabled = false
+		DeniedSenders = []
+		DeniedRecipients = []
+		DeniedSelectors = []
+	[Sequencer.Repricing]
+		Enabled = false
+		CheckInterval = "10s"
+		Hysteresis = 5
 
 [SequenceSender]
 WaitPeriodSendSequence = "5s"
 LastBatchVirtualizationTimeMaxWaitPeriod = "5s"
 MaxTxSizeForL1 = 131072
-L2Coinbase = "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"
 PrivateKey = {Path = "/pk/sequencer.keystore", Password = "testonly"}
 GasOffset = 80000
 
@@ -159,7 +393,29 @@ TxProfitabilityMinReward = "1.1"
 ProofStatePollingInterval = "5s"
 CleanupLockedProofsInterval = "2m"
 GeneratingProofCleanupThreshold = "10m"
+BatchProofTimeout = "0s"
+MaxProverStatusFailures = 10
+MaxAggregatedBatches = 0
 GasOffset = 0
+ProverVersionsProto = []
+CheckBatchDataIntegrity = true
+	[Aggregator.FallbackProvers]
+	Enabled = false
+	ProverNames = []
+	MaxQueueDepth = 0
+	MaxPendingBatchAge = "0s"
+	MaxConcurrentProofs = 0
+	[Aggregator.Artifacts]
+	Enabled = false
+	Endpoint = ""
+	Region = ""
+	Bucket = ""
+	Prefix = ""
+	AccessKeyID = ""
+	SecretAccessKey = ""
+	RequestTimeout = "30s"
+	RetentionPeriod = "0s"
+	RetentionCheckInterval = "1h"
 
 [L2GasPriceSuggester]
 Type = "follower"
@@ -172,18 +428,97 @@ CleanHistoryTimeRetention = "5m"
 
 [MTClient]
 URI = "zkevm-prover:50061"
+MaxGRPCMessageSize = 100000000
+MaxGRPCSendMessageSize = 100000000
+GRPCCompression = ""
 
 [Executor]
 URI = "zkevm-prover:50071"
 MaxResourceExhaustedAttempts = 3
 WaitOnResourceExhaustion = "1s"
 MaxGRPCMessageSize = 100000000
+MaxGRPCSendMessageSize = 100000000
+GRPCCompression = ""
+Connections = 1
+RequestTimeout = "60s"
+	[Executor.Retry]
+	MaxAttempts = 3
+	InitialBackoff = "100ms"
+	MaxBackoff = "1s"
+	Multiplier = 2
+	Jitter = 0.2
+	MaxElapsedTime = "0s"
+	[Executor.CircuitBreaker]
+	FailureThreshold = 5
+	OpenTimeout = "10s"
+	[Executor.Debug]
+	TracesDir = "/tmp/zkevm-node/executor-traces"
+	MaxOverrideDuration = "1h"
+		[Executor.Debug.Sequencer]
+		ROMLogs = false
+		PersistFailedTxTraces = false
+		[Executor.Debug.Synchronizer]
+		ROMLogs = false
+		PersistFailedTxTraces = false
+		[Executor.Debug.RPC]
+		ROMLogs = false
+		PersistFailedTxTraces = false
 
 [Metrics]
 Host = "0.0.0.0"
 Port = 9091
 Enabled = false
 
+[Diagnostics]
+Enabled = false
+Host = "0.0.0.0"
+Port = 6061
+CheckTimeout = "5s"
+MaxL1BlocksBehind = 0
+MaxBatchesBehind = 0
+MaxLastBatchAge = "0s"
+
+[Replay]
+Mode = ""
+File = ""
+Window = "0s"
+
+[Clock]
+SkewCheckEnabled = false
+NTPServers = ["pool.ntp.org", "time.google.com"]
+MaxSkew = "1s"
+CheckInterval = "10m"
+Timeout = "5s"
+HaltOnStartup = false
+
+[Broker]
+Enabled = false
+SinkBufferSize = 1000
+BatchStatusPollInterval = "5s"
+	[Broker.GRPC]
+	Enabled = false
+	Host = "0.0.0.0"
+	Port = 50091
+
+[RemoteConfig]
+Provider = ""
+URL = ""
+Key = ""
+Format = "toml"
+Token = ""
+Checksum = ""
+PublicKey = ""
+RequestTimeout = "10s"
+WatchInterval = "30s"
+ExitOnChange = false
+
+[Secrets]
+Provider = ""
+URL = ""
+Region = ""
+RequestTimeout = "10s"
+RefreshInterval = "5m"
+
 [HashDB]
 User = "prover_user"
 Password = "prover_pass"
@@ -0,0 +1,50 @@
+package remote
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+const (
+	// ProviderHTTP loads the config from any HTTP server, using the ETag header to detect changes
+	ProviderHTTP = "http"
+	// ProviderConsul loads the config from a key of the Consul KV store
+	ProviderConsul = "consul"
+	// ProviderEtcd loads the config from a key of etcd using its v3 JSON gateway
+	ProviderEtcd = "etcd"
+)
+
+// Config represents the configuration of the remote config provider, the values
+// loaded from the remote provider override the ones of the local config file and
+// are overridden by the env vars
+type Config struct {
+	// Provider is the backend storing the config: http, consul or etcd. Empty disables the remote config
+	Provider string `mapstructure:"Provider"`
+
+	// URL is the address of the provider, for the http provider it's the URL of the config document
+	URL string `mapstructure:"URL"`
+
+	// Key is the key storing the config document in the consul and etcd providers
+	Key string `mapstructure:"Key"`
+
+	// Format is the format of the config document: toml, json or yaml
+	Format string `mapstructure:"Format"`
+
+	// Token is sent as bearer token to the http provider and as ACL token to the consul provider
+	Token string `mapstructure:"Token"`
+
+	// Checksum is the expected sha256 of the config document in hex, when set the
+	// document is rejected if it doesn't match, so it can't be updated remotely
+	Checksum string `mapstructure:"Checksum"`
+
+	// PublicKey is the hex encoded ed25519 public key used to verify the signature of the
+	// config document, the signature is read from the same location adding the .sig suffix
+	PublicKey string `mapstructure:"PublicKey"`
+
+	// RequestTimeout is the timeout of the requests sent to the provider
+	RequestTimeout types.Duration `mapstructure:"RequestTimeout"`
+
+	// WatchInterval is the interval to check if the config document has changed, 0 disables the watch
+	WatchInterval types.Duration `mapstructure:"WatchInterval"`
+
+	// ExitOnChange stops the node gracefully when the config document changes, so the
+	// orchestrator restarts it with the new config
+	ExitOnChange bool `mapstructure:"ExitOnChange"`
+}
@@ -0,0 +1,190 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// provider reads a document from a remote backend, the version identifies the
+// content of the document so the changes can be detected
+type provider interface {
+	// fetch returns the document stored in the key and its version, if the version
+	// is provided and the document hasn't changed it returns ErrNotModified
+	fetch(ctx context.Context, key, version string) ([]byte, string, error)
+}
+
+func newProvider(cfg Config, httpClient *http.Client) (provider, error) {
+	if cfg.URL == "" {
+		return nil, ErrURLNotConfigured
+	}
+	baseURL := strings.TrimSuffix(cfg.URL, "/")
+	switch cfg.Provider {
+	case ProviderHTTP:
+		return &httpProvider{client: httpClient, token: cfg.Token}, nil
+	case ProviderConsul:
+		return &consulProvider{client: httpClient, url: baseURL, token: cfg.Token}, nil
+	case ProviderEtcd:
+		return &etcdProvider{client: httpClient, url: baseURL}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProvider, cfg.Provider)
+	}
+}
+
+// httpProvider reads the document from a URL, the key is the URL itself
+type httpProvider struct {
+	client *http.Client
+	token  string
+}
+
+func (p *httpProvider) fetch(ctx context.Context, key, version string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	if version != "" {
+		req.Header.Set("If-None-Match", version)
+	}
+
+	resp, body, err := do(p.client, req)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, "", ErrNotModified
+	}
+	if err := checkStatus(resp, body); err != nil {
+		return nil, "", err
+	}
+
+	newVersion := resp.Header.Get("ETag")
+	if newVersion == "" {
+		// without ETag the version is the hash of the content, so it can be compared
+		newVersion = checksum(body)
+	}
+	if newVersion == version {
+		return nil, "", ErrNotModified
+	}
+	return body, newVersion, nil
+}
+
+// consulProvider reads the document from the Consul KV store, the version is the modify index of the key
+type consulProvider struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func (p *consulProvider) fetch(ctx context.Context, key, version string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+"/v1/kv/"+strings.TrimPrefix(key, "/")+"?raw", nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if p.token != "" {
+		req.Header.Set("X-Consul-Token", p.token)
+	}
+
+	resp, body, err := do(p.client, req)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkStatus(resp, body); err != nil {
+		return nil, "", err
+	}
+
+	newVersion := resp.Header.Get("X-Consul-Index")
+	if newVersion == "" {
+		newVersion = checksum(body)
+	}
+	if newVersion == version {
+		return nil, "", ErrNotModified
+	}
+	return body, newVersion, nil
+}
+
+// etcdProvider reads the document from etcd using the JSON gateway of the v3 API,
+// the version is the modification revision of the key
+type etcdProvider struct {
+	client *http.Client
+	url    string
+}
+
+type etcdRangeRequest struct {
+	Key string `json:"key"`
+}
+
+type etcdRangeResponse struct {
+	Kvs []struct {
+		Value       string `json:"value"`
+		ModRevision string `json:"mod_revision"`
+	} `json:"kvs"`
+}
+
+func (p *etcdProvider) fetch(ctx context.Context, key, version string) ([]byte, string, error) {
+	reqBody, err := json.Marshal(etcdRangeRequest{Key: base64.StdEncoding.EncodeToString([]byte(key))})
+	if err != nil {
+		return nil, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"/v3/kv/range", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, body, err := do(p.client, req)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkStatus(resp, body); err != nil {
+		return nil, "", err
+	}
+
+	var rangeResp etcdRangeResponse
+	if err := json.Unmarshal(body, &rangeResp); err != nil {
+		return nil, "", fmt.Errorf("failed to decode etcd response: %w", err)
+	}
+	if len(rangeResp.Kvs) == 0 {
+		return nil, "", ErrNotFound
+	}
+	kv := rangeResp.Kvs[0]
+	if kv.ModRevision == version {
+		return nil, "", ErrNotModified
+	}
+	value, err := base64.StdEncoding.DecodeString(kv.Value)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode etcd value: %w", err)
+	}
+	return value, kv.ModRevision, nil
+}
+
+func do(client *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+func checkStatus(resp *http.Response, body []byte) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("request %s %s failed with status %d: %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Package remote loads the configuration of the node from a remote backend, so
+// fleets of nodes can be managed centrally, and watches it to detect changes
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const signatureSuffix = ".sig"
+
+var (
+	// ErrNotFound is returned when the config document doesn't exist in the provider
+	ErrNotFound = errors.New("remote config not found")
+	// ErrNotModified is returned when the config document hasn't changed since the last version loaded
+	ErrNotModified = errors.New("remote config not modified")
+	// ErrUnsupportedProvider is returned when the configured provider is not supported
+	ErrUnsupportedProvider = errors.New("unsupported remote config provider")
+	// ErrURLNotConfigured is returned when the URL of the provider is not configured
+	ErrURLNotConfigured = errors.New("remote config URL not configured")
+	// ErrChecksumMismatch is returned when the checksum of the config document doesn't match the expected one
+	ErrChecksumMismatch = errors.New("remote config checksum mismatch")
+	// ErrInvalidSignature is returned when the signature of the config document is not valid
+	ErrInvalidSignature = errors.New("invalid remote config signature")
+)
+
+// Document is a version of the config document loaded from the provider
+type Document struct {
+	Content []byte
+	Version string
+}
+
+// Client loads and watches the config document stored in a remote provider
+type Client struct {
+	cfg       Config
+	provider  provider
+	publicKey ed25519.PublicKey
+}
+
+// NewClient creates a client of the configured remote provider
+func NewClient(cfg Config) (*Client, error) {
+	p, err := newProvider(cfg, &http.Client{Timeout: cfg.RequestTimeout.Duration})
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{cfg: cfg, provider: p}
+	if cfg.PublicKey != "" {
+		key, err := hex.DecodeString(strings.TrimPrefix(cfg.PublicKey, "0x"))
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid remote config public key %s", cfg.PublicKey)
+		}
+		c.publicKey = key
+	}
+	return c, nil
+}
+
+// Load returns the config document after validating its checksum and signature
+func (c *Client) Load(ctx context.Context) (Document, error) {
+	return c.load(ctx, "")
+}
+
+// Watch checks periodically if the config document has changed until the context
+// is done, calling onChange with each valid new version. The version is the one
+// of the document loaded at startup
+func (c *Client) Watch(ctx context.Context, version string, onChange func(Document)) {
+	if c.cfg.WatchInterval.Duration == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.cfg.WatchInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			doc, err := c.load(ctx, version)
+			if errors.Is(err, ErrNotModified) {
+				continue
+			} else if err != nil {
+				log.Errorf("failed to check the remote config, err: %v", err)
+				continue
+			}
+			log.Infof("remote config changed, version %s -> %s", version, doc.Version)
+			version = doc.Version
+			onChange(doc)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Client) load(ctx context.Context, version string) (Document, error) {
+	content, newVersion, err := c.provider.fetch(ctx, c.key(), version)
+	if err != nil {
+		return Document{}, err
+	}
+
+	if err := c.validate(ctx, content); err != nil {
+		return Document{}, err
+	}
+	return Document{Content: content, Version: newVersion}, nil
+}
+
+func (c *Client) validate(ctx context.Context, content []byte) error {
+	if c.cfg.Checksum != "" && !strings.EqualFold(strings.TrimPrefix(c.cfg.Checksum, "0x"), checksum(content)) {
+		return ErrChecksumMismatch
+	}
+
+	if c.publicKey == nil {
+		return nil
+	}
+	encodedSignature, _, err := c.provider.fetch(ctx, c.key()+signatureSuffix, "")
+	if err != nil {
+		return fmt.Errorf("failed to get the remote config signature: %w", err)
+	}
+	signature, err := decodeSignature(strings.TrimSpace(string(encodedSignature)))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !ed25519.Verify(c.publicKey, content, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// key returns the key of the config document in the provider
+func (c *Client) key() string {
+	if c.cfg.Provider == ProviderHTTP {
+		return c.cfg.URL
+	}
+	return c.cfg.Key
+}
+
+// decodeSignature decodes a signature encoded in hex or base64
+func decodeSignature(s string) ([]byte, error) {
+	if signature, err := hex.DecodeString(strings.TrimPrefix(s, "0x")); err == nil && len(signature) == ed25519.SignatureSize {
+		return signature, nil
+	}
+	signature, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	return signature, nil
+}
+
+func checksum(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
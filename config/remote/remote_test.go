@@ -0,0 +1,189 @@
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend serves the documents using the http, consul and etcd APIs
+type fakeBackend struct {
+	mutex     sync.Mutex
+	documents map[string][]byte
+	versions  map[string]int
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{documents: map[string][]byte{}, versions: map[string]int{}}
+}
+
+func (b *fakeBackend) set(key string, content []byte) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.documents[key] = content
+	b.versions[key]++
+}
+
+func (b *fakeBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch {
+	case r.URL.Path == "/v3/kv/range":
+		var req etcdRangeRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		key, _ := base64.StdEncoding.DecodeString(req.Key)
+		resp := map[string]interface{}{}
+		if content, found := b.documents[string(key)]; found {
+			resp["kvs"] = []map[string]string{{
+				"value":        base64.StdEncoding.EncodeToString(content),
+				"mod_revision": strconv.Itoa(b.versions[string(key)]),
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	case len(r.URL.Path) > len("/v1/kv/") && r.URL.Path[:len("/v1/kv/")] == "/v1/kv/":
+		key := r.URL.Path[len("/v1/kv/"):]
+		content, found := b.documents[key]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("X-Consul-Index", strconv.Itoa(b.versions[key]))
+		_, _ = w.Write(content)
+	default:
+		content, found := b.documents[r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		etag := `"` + strconv.Itoa(b.versions[r.URL.Path]) + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write(content)
+	}
+}
+
+func TestClientProviders(t *testing.T) {
+	backend := newFakeBackend()
+	srv := httptest.NewServer(backend)
+	defer srv.Close()
+
+	backend.set("/node.toml", []byte("[Log]\nLevel = \"info\"\n"))
+	backend.set("nodes/rpc.toml", []byte("[Log]\nLevel = \"debug\"\n"))
+
+	testCases := []struct {
+		cfg      Config
+		expected string
+	}{
+		{cfg: Config{Provider: ProviderHTTP, URL: srv.URL + "/node.toml"}, expected: "info"},
+		{cfg: Config{Provider: ProviderConsul, URL: srv.URL, Key: "nodes/rpc.toml"}, expected: "debug"},
+		{cfg: Config{Provider: ProviderEtcd, URL: srv.URL, Key: "nodes/rpc.toml"}, expected: "debug"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cfg.Provider, func(t *testing.T) {
+			tc.cfg.RequestTimeout = types.NewDuration(time.Second)
+			client, err := NewClient(tc.cfg)
+			require.NoError(t, err)
+
+			doc, err := client.Load(context.Background())
+			require.NoError(t, err)
+			assert.Contains(t, string(doc.Content), tc.expected)
+			assert.NotEmpty(t, doc.Version)
+
+			_, err = client.load(context.Background(), doc.Version)
+			assert.ErrorIs(t, err, ErrNotModified)
+		})
+	}
+
+	client, err := NewClient(Config{Provider: ProviderConsul, URL: srv.URL, Key: "missing"})
+	require.NoError(t, err)
+	_, err = client.Load(context.Background())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = NewClient(Config{Provider: "zookeeper", URL: srv.URL})
+	assert.ErrorIs(t, err, ErrUnsupportedProvider)
+}
+
+func TestClientValidation(t *testing.T) {
+	backend := newFakeBackend()
+	srv := httptest.NewServer(backend)
+	defer srv.Close()
+
+	content := []byte("[Log]\nLevel = \"info\"\n")
+	backend.set("/node.toml", content)
+
+	// checksum
+	client, err := NewClient(Config{Provider: ProviderHTTP, URL: srv.URL + "/node.toml", Checksum: checksum(content)})
+	require.NoError(t, err)
+	_, err = client.Load(context.Background())
+	require.NoError(t, err)
+
+	client, err = NewClient(Config{Provider: ProviderHTTP, URL: srv.URL + "/node.toml", Checksum: checksum([]byte("other"))})
+	require.NoError(t, err)
+	_, err = client.Load(context.Background())
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	// signature
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	cfg := Config{Provider: ProviderHTTP, URL: srv.URL + "/node.toml", PublicKey: hex.EncodeToString(publicKey)}
+	client, err = NewClient(cfg)
+	require.NoError(t, err)
+
+	_, err = client.Load(context.Background())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	backend.set("/node.toml.sig", []byte(hex.EncodeToString(ed25519.Sign(privateKey, content))))
+	_, err = client.Load(context.Background())
+	require.NoError(t, err)
+
+	backend.set("/node.toml.sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte("other")))))
+	_, err = client.Load(context.Background())
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = NewClient(Config{Provider: ProviderHTTP, URL: srv.URL, PublicKey: "0x1234"})
+	assert.Error(t, err)
+}
+
+func TestClientWatch(t *testing.T) {
+	backend := newFakeBackend()
+	srv := httptest.NewServer(backend)
+	defer srv.Close()
+
+	backend.set("/node.toml", []byte("v1"))
+	client, err := NewClient(Config{Provider: ProviderHTTP, URL: srv.URL + "/node.toml", WatchInterval: types.NewDuration(10 * time.Millisecond)})
+	require.NoError(t, err)
+	doc, err := client.Load(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan Document, 1)
+	go client.Watch(ctx, doc.Version, func(doc Document) {
+		changes <- doc
+	})
+
+	backend.set("/node.toml", []byte("v2"))
+	select {
+	case doc := <-changes:
+		assert.Equal(t, "v2", string(doc.Content))
+	case <-time.After(5 * time.Second):
+		t.Fatal("remote config change not detected")
+	}
+}
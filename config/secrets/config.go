@@ -0,0 +1,34 @@
+package secrets
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+const (
+	// ProviderVault reads the secrets from the KV secrets engine of HashiCorp Vault
+	ProviderVault = "vault"
+	// ProviderAWS reads the secrets from AWS Secrets Manager
+	ProviderAWS = "aws"
+)
+
+// Config represents the configuration of the secret manager. The secret config fields (the passwords
+// of the databases and the key stores) can reference a secret as secret://<name>#<field> instead of
+// containing the plaintext value. For vault the name is the API path of the secret, like
+// secret/data/zkevm/db, for aws it's the name or ARN of the secret. The credentials of the secret manager
+// are read from the env vars, so they aren't stored in the config: VAULT_TOKEN for vault, and
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN for aws
+type Config struct {
+	// Provider is the secret manager: vault or aws. Empty disables the secret references
+	Provider string `mapstructure:"Provider"`
+
+	// URL is the address of the vault server, for aws it overrides the regional endpoint of Secrets Manager
+	URL string `mapstructure:"URL"`
+
+	// Region is the AWS region of the secrets
+	Region string `mapstructure:"Region"`
+
+	// RequestTimeout is the timeout of the requests sent to the secret manager
+	RequestTimeout types.Duration `mapstructure:"RequestTimeout"`
+
+	// RefreshInterval is the interval to read again the secrets in use, so the rotated secrets are used
+	// by the new database connections without restarting the node. 0 disables the refresh
+	RefreshInterval types.Duration `mapstructure:"RefreshInterval"`
+}
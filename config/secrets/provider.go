@@ -0,0 +1,259 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	vaultTokenEnv         = "VAULT_TOKEN"
+	awsAccessKeyIDEnv     = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+	awsSessionTokenEnv    = "AWS_SESSION_TOKEN"
+	awsService            = "secretsmanager"
+)
+
+// secret is a version of a secret read from the secret manager, the value of
+// a plain secret without fields is stored in the empty field
+type secret struct {
+	fields  map[string]string
+	version string
+}
+
+// provider reads the secrets from a secret manager
+type provider interface {
+	fetch(ctx context.Context, name string) (secret, error)
+}
+
+func newProvider(cfg Config, httpClient *http.Client) (provider, error) {
+	switch cfg.Provider {
+	case ProviderVault:
+		if cfg.URL == "" {
+			return nil, ErrURLNotConfigured
+		}
+		return &vaultProvider{client: httpClient, url: strings.TrimSuffix(cfg.URL, "/"), token: os.Getenv(vaultTokenEnv)}, nil
+	case ProviderAWS:
+		if cfg.Region == "" {
+			return nil, ErrRegionNotConfigured
+		}
+		url := strings.TrimSuffix(cfg.URL, "/")
+		if url == "" {
+			url = fmt.Sprintf("https://%s.%s.amazonaws.com", awsService, cfg.Region)
+		}
+		return &awsProvider{
+			client:          httpClient,
+			url:             url,
+			region:          cfg.Region,
+			accessKeyID:     os.Getenv(awsAccessKeyIDEnv),
+			secretAccessKey: os.Getenv(awsSecretAccessKeyEnv),
+			sessionToken:    os.Getenv(awsSessionTokenEnv),
+			now:             time.Now,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProvider, cfg.Provider)
+	}
+}
+
+// vaultProvider reads the secrets from the KV secrets engine of Vault, both versions of the
+// engine are supported. The version of the v1 secrets is the hash of their fields
+type vaultProvider struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+type vaultResponse struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type vaultKVv2Data struct {
+	Data     map[string]interface{} `json:"data"`
+	Metadata *struct {
+		Version json.Number `json:"version"`
+	} `json:"metadata"`
+}
+
+func (p *vaultProvider) fetch(ctx context.Context, name string) (secret, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+"/v1/"+strings.TrimPrefix(name, "/"), nil)
+	if err != nil {
+		return secret{}, err
+	}
+	if p.token != "" {
+		req.Header.Set("X-Vault-Token", p.token)
+	}
+
+	body, err := do(p.client, req)
+	if err != nil {
+		return secret{}, err
+	}
+	var resp vaultResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return secret{}, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	var kvv2 vaultKVv2Data
+	if err := json.Unmarshal(resp.Data, &kvv2); err == nil && kvv2.Data != nil && kvv2.Metadata != nil {
+		return secret{fields: stringFields(kvv2.Data), version: kvv2.Metadata.Version.String()}, nil
+	}
+	var kvv1 map[string]interface{}
+	if err := json.Unmarshal(resp.Data, &kvv1); err != nil {
+		return secret{}, fmt.Errorf("failed to decode vault secret: %w", err)
+	}
+	return secret{fields: stringFields(kvv1), version: hashHex(resp.Data)}, nil
+}
+
+// awsProvider reads the secrets from AWS Secrets Manager, signing the requests with the
+// signature version 4. The secret strings containing a JSON object are split in fields
+type awsProvider struct {
+	client          *http.Client
+	url             string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	now             func() time.Time
+}
+
+type awsGetSecretValueRequest struct {
+	SecretID string `json:"SecretId"`
+}
+
+type awsGetSecretValueResponse struct {
+	SecretString string `json:"SecretString"`
+	VersionID    string `json:"VersionId"`
+}
+
+func (p *awsProvider) fetch(ctx context.Context, name string) (secret, error) {
+	if p.accessKeyID == "" || p.secretAccessKey == "" {
+		return secret{}, ErrCredentialsNotConfigured
+	}
+	payload, err := json.Marshal(awsGetSecretValueRequest{SecretID: name})
+	if err != nil {
+		return secret{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"/", bytes.NewReader(payload))
+	if err != nil {
+		return secret{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	p.sign(req, payload)
+
+	body, err := do(p.client, req)
+	if err != nil {
+		return secret{}, err
+	}
+	var resp awsGetSecretValueResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return secret{}, fmt.Errorf("failed to decode secrets manager response: %w", err)
+	}
+
+	fields := map[string]string{"": resp.SecretString}
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(resp.SecretString), &object); err == nil {
+		for field, value := range stringFields(object) {
+			fields[field] = value
+		}
+	}
+	return secret{fields: fields, version: resp.VersionID}, nil
+}
+
+// sign adds the signature version 4 of the request to its headers
+func (p *awsProvider) sign(req *http.Request, payload []byte) {
+	t := p.now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	// the signed headers must be sorted
+	signedHeaders := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	if p.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.sessionToken)
+		signedHeaders = []string{"content-type", "host", "x-amz-date", "x-amz-security-token", "x-amz-target"}
+	}
+	var canonicalHeaders strings.Builder
+	for _, header := range signedHeaders {
+		value := req.Header.Get(header)
+		if header == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(value) + "\n")
+	}
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		hashHex(payload),
+	}, "\n")
+
+	scope := strings.Join([]string{date, p.region, awsService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+	key := hmacSHA256([]byte("AWS4"+p.secretAccessKey), date)
+	key = hmacSHA256(key, p.region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.accessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// stringFields converts the values of the fields of a secret to strings
+func stringFields(object map[string]interface{}) map[string]string {
+	fields := make(map[string]string, len(object))
+	for field, value := range object {
+		if s, ok := value.(string); ok {
+			fields[field] = s
+		} else {
+			fields[field] = fmt.Sprint(value)
+		}
+	}
+	return fields
+}
+
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("request %s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, string(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,169 @@
+// Package secrets resolves the secret references of the config fields against a secret manager,
+// so the passwords of the databases and the key stores aren't stored in plaintext in the config
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// ReferencePrefix is the prefix of the config values referencing a secret as secret://<name>#<field>
+const ReferencePrefix = "secret://"
+
+var (
+	// ErrNotFound is returned when the secret doesn't exist in the secret manager
+	ErrNotFound = errors.New("secret not found")
+	// ErrFieldNotFound is returned when the field referenced doesn't exist in the secret
+	ErrFieldNotFound = errors.New("secret field not found")
+	// ErrUnsupportedProvider is returned when the configured provider is not supported
+	ErrUnsupportedProvider = errors.New("unsupported secret manager provider")
+	// ErrURLNotConfigured is returned when the URL of the vault server is not configured
+	ErrURLNotConfigured = errors.New("secret manager URL not configured")
+	// ErrRegionNotConfigured is returned when the region of AWS Secrets Manager is not configured
+	ErrRegionNotConfigured = errors.New("secret manager region not configured")
+	// ErrCredentialsNotConfigured is returned when the AWS credentials are not set in the env vars
+	ErrCredentialsNotConfigured = errors.New("secret manager credentials not configured")
+	// ErrNotConfigured is returned when a secret is referenced but the secret manager is not configured
+	ErrNotConfigured = errors.New("secret referenced but the secret manager is not configured")
+)
+
+// defaultManager is the manager resolving the references of the config, set when the config is loaded
+var defaultManager atomic.Pointer[Manager]
+
+// IsReference returns true if the config value references a secret
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, ReferencePrefix)
+}
+
+// Init creates the manager resolving the secret references of the config
+func Init(cfg Config) error {
+	m, err := NewManager(cfg)
+	if err != nil {
+		return err
+	}
+	defaultManager.Store(m)
+	return nil
+}
+
+// Resolve returns the value of the secret referenced by the config value, the values that
+// aren't a secret reference are returned as they are
+func Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	m := defaultManager.Load()
+	if m == nil {
+		return "", ErrNotConfigured
+	}
+	return m.Resolve(ctx, value)
+}
+
+// Watch refreshes the secrets in use by the references of the config until the context is done
+func Watch(ctx context.Context) {
+	if m := defaultManager.Load(); m != nil {
+		m.Watch(ctx)
+	}
+}
+
+// Manager reads the secrets from the secret manager, caching them until they are refreshed
+type Manager struct {
+	cfg      Config
+	provider provider
+
+	mutex   sync.RWMutex
+	secrets map[string]secret
+}
+
+// NewManager creates a manager of the configured secret manager
+func NewManager(cfg Config) (*Manager, error) {
+	p, err := newProvider(cfg, &http.Client{Timeout: cfg.RequestTimeout.Duration})
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{cfg: cfg, provider: p, secrets: map[string]secret{}}, nil
+}
+
+// Resolve returns the value of the secret referenced as secret://<name>#<field>, the field can
+// be omitted for the plain secrets and the secrets with a single field
+func (m *Manager) Resolve(ctx context.Context, reference string) (string, error) {
+	name, field, _ := strings.Cut(strings.TrimPrefix(reference, ReferencePrefix), "#")
+	if name == "" {
+		return "", fmt.Errorf("invalid secret reference %s", reference)
+	}
+
+	m.mutex.RLock()
+	s, found := m.secrets[name]
+	m.mutex.RUnlock()
+	if !found {
+		var err error
+		s, err = m.provider.fetch(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the secret %s: %w", name, err)
+		}
+		m.mutex.Lock()
+		m.secrets[name] = s
+		m.mutex.Unlock()
+	}
+
+	if value, found := s.fields[field]; found {
+		return value, nil
+	}
+	if field == "" && len(s.fields) == 1 {
+		for _, value := range s.fields {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrFieldNotFound, reference)
+}
+
+// Watch reads again the secrets in use every refresh interval until the context is done, so the
+// new versions of the rotated secrets are resolved from then on
+func (m *Manager) Watch(ctx context.Context) {
+	if m.cfg.RefreshInterval.Duration == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.cfg.RefreshInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.refresh(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh reads again the cached secrets, keeping the cached version of the ones that fail
+func (m *Manager) refresh(ctx context.Context) {
+	m.mutex.RLock()
+	names := make([]string, 0, len(m.secrets))
+	for name := range m.secrets {
+		names = append(names, name)
+	}
+	m.mutex.RUnlock()
+
+	for _, name := range names {
+		s, err := m.provider.fetch(ctx, name)
+		if err != nil {
+			log.Errorf("failed to refresh the secret %s, err: %v", name, err)
+			continue
+		}
+		m.mutex.Lock()
+		if m.secrets[name].version != s.version {
+			log.Infof("secret %s rotated to version %s", name, s.version)
+		}
+		m.secrets[name] = s
+		m.mutex.Unlock()
+	}
+}
@@ -0,0 +1,171 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretManager serves the secrets using the vault KV and the AWS Secrets Manager APIs
+type fakeSecretManager struct {
+	mutex    sync.Mutex
+	secrets  map[string]string
+	versions map[string]int
+	requests []*http.Request
+}
+
+func newFakeSecretManager() *fakeSecretManager {
+	return &fakeSecretManager{secrets: map[string]string{}, versions: map[string]int{}}
+}
+
+func (m *fakeSecretManager) set(name, value string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.secrets[name] = value
+	m.versions[name]++
+}
+
+func (m *fakeSecretManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests = append(m.requests, r)
+
+	if r.Header.Get("X-Amz-Target") == "secretsmanager.GetSecretValue" {
+		var req awsGetSecretValueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		value, found := m.secrets[req.SecretID]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(awsGetSecretValueResponse{SecretString: value, VersionID: string(rune('0' + m.versions[req.SecretID]))})
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/v1/")
+	value, found := m.secrets[name]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var data interface{} = json.RawMessage(value)
+	if strings.Contains(name, "/data/") {
+		data = map[string]interface{}{
+			"data":     json.RawMessage(value),
+			"metadata": map[string]interface{}{"version": m.versions[name]},
+		}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func TestVaultSecrets(t *testing.T) {
+	t.Setenv(vaultTokenEnv, "token")
+	backend := newFakeSecretManager()
+	backend.set("secret/data/zkevm/db", `{"user":"zkevm","password":"db-password"}`)
+	backend.set("kv/zkevm/keystore", `{"password":"keystore-password"}`)
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	m, err := NewManager(Config{Provider: ProviderVault, URL: server.URL, RequestTimeout: types.NewDuration(time.Second)})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	password, err := m.Resolve(ctx, "secret://secret/data/zkevm/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "db-password", password)
+	assert.Equal(t, "token", backend.requests[0].Header.Get("X-Vault-Token"))
+
+	// the field can be omitted for the secrets with a single field
+	password, err = m.Resolve(ctx, "secret://kv/zkevm/keystore")
+	require.NoError(t, err)
+	assert.Equal(t, "keystore-password", password)
+
+	_, err = m.Resolve(ctx, "secret://secret/data/zkevm/db")
+	assert.ErrorIs(t, err, ErrFieldNotFound)
+	_, err = m.Resolve(ctx, "secret://secret/data/zkevm/other#password")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// the secrets are cached until they are refreshed
+	backend.set("secret/data/zkevm/db", `{"user":"zkevm","password":"rotated-password"}`)
+	password, err = m.Resolve(ctx, "secret://secret/data/zkevm/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "db-password", password)
+	m.refresh(ctx)
+	password, err = m.Resolve(ctx, "secret://secret/data/zkevm/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "rotated-password", password)
+}
+
+func TestAWSSecrets(t *testing.T) {
+	backend := newFakeSecretManager()
+	backend.set("zkevm/db", `{"password":"db-password"}`)
+	backend.set("zkevm/keystore", "keystore-password")
+	server := httptest.NewServer(backend)
+	defer server.Close()
+
+	cfg := Config{Provider: ProviderAWS, URL: server.URL, Region: "eu-west-1", RequestTimeout: types.NewDuration(time.Second)}
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+	_, err = m.Resolve(context.Background(), "secret://zkevm/db#password")
+	assert.ErrorIs(t, err, ErrCredentialsNotConfigured)
+
+	t.Setenv(awsAccessKeyIDEnv, "AKID")
+	t.Setenv(awsSecretAccessKeyEnv, "secret-key")
+	m, err = NewManager(cfg)
+	require.NoError(t, err)
+
+	password, err := m.Resolve(context.Background(), "secret://zkevm/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "db-password", password)
+	password, err = m.Resolve(context.Background(), "secret://zkevm/keystore")
+	require.NoError(t, err)
+	assert.Equal(t, "keystore-password", password)
+
+	authorization := backend.requests[len(backend.requests)-1].Header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/"))
+	assert.Contains(t, authorization, "/eu-west-1/secretsmanager/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-target, Signature=")
+}
+
+func TestAWSSignature(t *testing.T) {
+	p := &awsProvider{
+		region:          "us-east-1",
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		now:             func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://secretsmanager.us-east-1.amazonaws.com/", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	p.sign(req, []byte(`{"SecretId":"zkevm/db"}`))
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	// the signature is deterministic for the same request, credentials and time
+	signature := req.Header.Get("Authorization")
+	p.sign(req, []byte(`{"SecretId":"zkevm/db"}`))
+	assert.Equal(t, signature, req.Header.Get("Authorization"))
+	p.sign(req, []byte(`{"SecretId":"zkevm/other"}`))
+	assert.NotEqual(t, signature, req.Header.Get("Authorization"))
+}
+
+func TestResolve(t *testing.T) {
+	value, err := Resolve(context.Background(), "plaintext")
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", value)
+
+	defaultManager.Store(nil)
+	_, err = Resolve(context.Background(), "secret://zkevm/db#password")
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	_, err = NewManager(Config{Provider: "unknown"})
+	assert.ErrorIs(t, err, ErrUnsupportedProvider)
+}
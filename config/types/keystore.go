@@ -5,6 +5,6 @@ type KeystoreFileConfig struct {
 	// Path is the file path for the key store file
 	Path string `mapstructure:"Path"`
 
-	// Password is the password to decrypt the key store file
+	// Password is the password to decrypt the key store file, it can reference a secret of the secret manager as secret://<name>#<field>
 	Password string `mapstructure:"Password"`
 }
@@ -1,5 +1,12 @@
 package db
 
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/secrets"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+)
+
 // Config provide fields to configure the pool
 type Config struct {
 	// Database name
@@ -8,7 +15,7 @@ type Config struct {
 	// Database User name
 	User string `mapstructure:"User"`
 
-	// Database Password of the user
+	// Database Password of the user, it can reference a secret of the secret manager as secret://<name>#<field>
 	Password string `mapstructure:"Password"`
 
 	// Host address of database
@@ -22,4 +29,32 @@ type Config struct {
 
 	// MaxConns is the maximum number of connections in the pool.
 	MaxConns int `mapstructure:"MaxConns"`
+
+	// ReadReplica is the replica of the database serving the read only queries of the RPC
+	ReadReplica ReplicaConfig `mapstructure:"ReadReplica"`
+}
+
+// ResolvePassword returns the password of the user, reading it from the secret manager when it references a secret
+func (c Config) ResolvePassword(ctx context.Context) (string, error) {
+	return secrets.Resolve(ctx, c.Password)
+}
+
+// ReplicaConfig provide fields to configure a read replica of the database, it's
+// accessed with the user, password and database name of the primary
+type ReplicaConfig struct {
+	// Host address of the replica, the replica is disabled when it's empty
+	Host string `mapstructure:"Host"`
+
+	// Port Number of the replica
+	Port string `mapstructure:"Port"`
+
+	// MaxConns is the maximum number of connections in the pool of the replica
+	MaxConns int `mapstructure:"MaxConns"`
+
+	// MaxLag is the max replication lag allowed, the queries fall back to the primary while the
+	// replica lags behind more than this. 0 means no limit
+	MaxLag types.Duration `mapstructure:"MaxLag"`
+
+	// CheckInterval is the time between the checks of the availability and lag of the replica
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/0xPolygonHermez/zkevm-node/config/secrets"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/gobuffalo/packr/v2"
 	"github.com/jackc/pgx/v4"
@@ -26,11 +27,21 @@ var packrMigrations = map[string]*packr.Box{
 
 // NewSQLDB creates a new SQL DB
 func NewSQLDB(cfg Config) (*pgxpool.Pool, error) {
-	config, err := pgxpool.ParseConfig(fmt.Sprintf("postgres://%s:%s@%s:%s/%s?pool_max_conns=%d", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name, cfg.MaxConns))
+	config, err := pgxpool.ParseConfig(fmt.Sprintf("postgres://%s:%s@%s:%s/%s?pool_max_conns=%d", cfg.User, dsnPassword(cfg), cfg.Host, cfg.Port, cfg.Name, cfg.MaxConns))
 	if err != nil {
 		log.Errorf("Unable to parse DB config: %v\n", err)
 		return nil, err
 	}
+	if secrets.IsReference(cfg.Password) {
+		if err := resolvePassword(context.Background(), config.ConnConfig, cfg); err != nil {
+			log.Errorf("Unable to resolve DB password: %v\n", err)
+			return nil, err
+		}
+		// the password is resolved for each new connection, so the rotated passwords are used without restarting
+		config.BeforeConnect = func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+			return resolvePassword(ctx, connConfig, cfg)
+		}
+	}
 	if cfg.EnableLog {
 		config.ConnConfig.Logger = logger{}
 	}
@@ -63,7 +74,7 @@ func RunMigrationsDown(cfg Config, name string) error {
 // the database updated with the latest changes in either direction,
 // up or down.
 func runMigrations(cfg Config, packrName string, direction migrate.MigrationDirection) error {
-	c, err := pgx.ParseConfig(fmt.Sprintf("postgres://%s:%s@%s:%s/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name))
+	c, err := parseConnConfig(cfg)
 	if err != nil {
 		return err
 	}
@@ -85,7 +96,7 @@ func runMigrations(cfg Config, packrName string, direction migrate.MigrationDire
 }
 
 func checkMigrations(cfg Config, packrName string, direction migrate.MigrationDirection) error {
-	c, err := pgx.ParseConfig(fmt.Sprintf("postgres://%s:%s@%s:%s/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name))
+	c, err := parseConnConfig(cfg)
 	if err != nil {
 		return err
 	}
@@ -124,3 +135,37 @@ func checkMigrations(cfg Config, packrName string, direction migrate.MigrationDi
 	}
 	return nil
 }
+
+// parseConnConfig returns the config of a single connection to the database
+func parseConnConfig(cfg Config) (*pgx.ConnConfig, error) {
+	c, err := pgx.ParseConfig(fmt.Sprintf("postgres://%s:%s@%s:%s/%s", cfg.User, dsnPassword(cfg), cfg.Host, cfg.Port, cfg.Name))
+	if err != nil {
+		return nil, err
+	}
+	if err := resolvePassword(context.Background(), c, cfg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// dsnPassword returns the password of the connection string, the secret references are left
+// out as the password is set in the connection config once it's resolved
+func dsnPassword(cfg Config) string {
+	if secrets.IsReference(cfg.Password) {
+		return ""
+	}
+	return cfg.Password
+}
+
+// resolvePassword sets in the connection config the password of the secret referenced by the config
+func resolvePassword(ctx context.Context, connConfig *pgx.ConnConfig, cfg Config) error {
+	if !secrets.IsReference(cfg.Password) {
+		return nil
+	}
+	password, err := cfg.ResolvePassword(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the database password: %w", err)
+	}
+	connConfig.Password = password
+	return nil
+}
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE pool.transaction ADD COLUMN IF NOT EXISTS status_updated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_transaction_status_updated_at ON pool.transaction (status, status_updated_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS pool.idx_transaction_status_updated_at;
+
+ALTER TABLE pool.transaction DROP COLUMN IF EXISTS status_updated_at;
@@ -0,0 +1,40 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the status updated at column to the pool transactions
+type migrationTest0012 struct{}
+
+func (m migrationTest0012) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0012) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'pool' AND table_name = 'transaction' AND column_name = 'status_updated_at';`
+	row := db.QueryRow(getColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = 'idx_transaction_status_updated_at';`
+	row = db.QueryRow(getIndex)
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+}
+
+func (m migrationTest0012) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'pool' AND table_name = 'transaction' AND column_name = 'status_updated_at';`
+	row := db.QueryRow(getColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0012(t *testing.T) {
+	runMigrationTest(t, 12, migrationTest0012{})
+}
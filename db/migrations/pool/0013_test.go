@@ -0,0 +1,35 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the conditions column to the pool transactions
+type migrationTest0013 struct{}
+
+func (m migrationTest0013) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'pool' AND table_name = 'transaction' AND column_name = 'conditions';`
+	row := db.QueryRow(getColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'pool' AND table_name = 'transaction' AND column_name = 'conditions';`
+	row := db.QueryRow(getColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0013(t *testing.T) {
+	runMigrationTest(t, 13, migrationTest0013{})
+}
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS pool.selection_audit
+(
+    id                  SERIAL PRIMARY KEY,
+    round               BIGINT NOT NULL,
+    position            INTEGER NOT NULL,
+    hash                VARCHAR NOT NULL,
+    from_address        VARCHAR NOT NULL,
+    nonce               DECIMAL(78, 0) NOT NULL,
+    score               DECIMAL(78, 0) NOT NULL,
+    remaining_resources JSONB NOT NULL,
+    decision            VARCHAR NOT NULL,
+    reason              VARCHAR,
+    selected_at         TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_selection_audit_hash ON pool.selection_audit (hash);
+CREATE INDEX IF NOT EXISTS idx_selection_audit_selected_at ON pool.selection_audit (selected_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS pool.selection_audit;
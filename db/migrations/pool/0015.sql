@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS pool.policy_deny_list
+(
+    kind       VARCHAR NOT NULL,
+    value      VARCHAR NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (kind, value)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS pool.policy_deny_list;
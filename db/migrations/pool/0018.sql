@@ -0,0 +1,43 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS pool.dropped_tx
+(
+    id           BIGSERIAL PRIMARY KEY,
+    xid          BIGINT                   NOT NULL DEFAULT pg_current_xact_id()::TEXT::BIGINT,
+    hash         VARCHAR                  NOT NULL,
+    from_address VARCHAR                  NOT NULL,
+    nonce        DECIMAL(78, 0)           NOT NULL,
+    status       VARCHAR(15)              NOT NULL,
+    reason       VARCHAR,
+    dropped_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dropped_tx_xid ON pool.dropped_tx (xid);
+CREATE INDEX IF NOT EXISTS idx_dropped_tx_dropped_at ON pool.dropped_tx (dropped_at);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION pool.log_dropped_tx() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        -- the txs that were already dropped have been notified when their status changed
+        IF OLD.status IS NULL OR OLD.status NOT IN ('failed', 'invalid', 'expired') THEN
+            INSERT INTO pool.dropped_tx (hash, from_address, nonce, status)
+            VALUES (OLD.hash, OLD.from_address, OLD.nonce, 'deleted');
+        END IF;
+        RETURN OLD;
+    END IF;
+    IF NEW.status IN ('failed', 'invalid', 'expired') AND NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO pool.dropped_tx (hash, from_address, nonce, status, reason)
+        VALUES (NEW.hash, NEW.from_address, NEW.nonce, NEW.status, NEW.failed_reason);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER log_dropped_tx AFTER UPDATE OF status OR DELETE ON pool.transaction
+    FOR EACH ROW EXECUTE FUNCTION pool.log_dropped_tx();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS log_dropped_tx ON pool.transaction;
+DROP FUNCTION IF EXISTS pool.log_dropped_tx();
+DROP TABLE IF EXISTS pool.dropped_tx;
@@ -0,0 +1,52 @@
+package pool_migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// this migration adds the dropped_tx table filled by a trigger when a pool tx
+// is dropped or deleted
+type migrationTest0018 struct{}
+
+func (m migrationTest0018) InsertData(db *sql.DB) error {
+	const addTxs = `INSERT INTO pool.transaction (hash, status, nonce, received_at, from_address)
+	                VALUES ('0x1', 'pending', 1, NOW(), '0xa'), ('0x2', 'pending', 2, NOW(), '0xa'), ('0x3', 'failed', 3, NOW(), '0xa');`
+	_, err := db.Exec(addTxs)
+	return err
+}
+
+func (m migrationTest0018) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(`UPDATE pool.transaction SET status = 'invalid', failed_reason = 'reason' WHERE hash = '0x1';`)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE pool.transaction SET status = 'selected' WHERE hash = '0x2';`)
+	require.NoError(t, err)
+	_, err = db.Exec(`DELETE FROM pool.transaction;`)
+	require.NoError(t, err)
+
+	rows, err := db.Query(`SELECT hash, status, COALESCE(reason, '') FROM pool.dropped_tx ORDER BY id;`)
+	require.NoError(t, err)
+	defer rows.Close()
+	var dropped [][3]string
+	for rows.Next() {
+		var d [3]string
+		require.NoError(t, rows.Scan(&d[0], &d[1], &d[2]))
+		dropped = append(dropped, d)
+	}
+	assert.Equal(t, [][3]string{{"0x1", "invalid", "reason"}, {"0x2", "deleted", ""}}, dropped)
+}
+
+func (m migrationTest0018) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'pool' AND table_name = 'dropped_tx';`
+	row := db.QueryRow(getTable)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0018(t *testing.T) {
+	runMigrationTest(t, 18, migrationTest0018{})
+}
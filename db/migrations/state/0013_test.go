@@ -0,0 +1,43 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the table to persist the json rpc filters
+type migrationTest0013 struct{}
+
+func (m migrationTest0013) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const addFilter = `INSERT INTO state.rpc_filter (id, filter_type, parameters, last_poll) VALUES ($1, $2, $3, $4)`
+	_, err := db.Exec(addFilter, "0x1", "log", `{"topics":[]}`, time.Now())
+	assert.NoError(t, err)
+
+	var filterType string
+	err = db.QueryRow(`SELECT filter_type FROM state.rpc_filter WHERE id = $1`, "0x1").Scan(&filterType)
+	assert.NoError(t, err)
+	assert.Equal(t, "log", filterType)
+
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+	var result int
+	assert.NoError(t, db.QueryRow(getIndex, "rpc_filter_last_poll_idx").Scan(&result))
+	assert.Equal(t, 1, result)
+}
+
+func (m migrationTest0013) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name = 'rpc_filter';`
+	var result int
+	assert.NoError(t, db.QueryRow(getTable).Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0013(t *testing.T) {
+	runMigrationTest(t, 13, migrationTest0013{})
+}
@@ -0,0 +1,37 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.bridge_deposit
+(
+    block_num   BIGINT  NOT NULL REFERENCES state.l2block (block_num) ON DELETE CASCADE,
+    tx_hash     VARCHAR NOT NULL,
+    log_index   INTEGER NOT NULL,
+    leaf_type   SMALLINT NOT NULL,
+    orig_net    BIGINT  NOT NULL,
+    orig_addr   VARCHAR NOT NULL,
+    dest_net    BIGINT  NOT NULL,
+    dest_addr   VARCHAR NOT NULL,
+    amount      DECIMAL(78, 0) NOT NULL,
+    metadata    BYTEA,
+    deposit_cnt BIGINT  NOT NULL,
+    PRIMARY KEY (block_num, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS bridge_deposit_dest_addr_idx ON state.bridge_deposit (dest_addr);
+
+CREATE TABLE IF NOT EXISTS state.bridge_claim
+(
+    block_num   BIGINT  NOT NULL REFERENCES state.l2block (block_num) ON DELETE CASCADE,
+    tx_hash     VARCHAR NOT NULL,
+    log_index   INTEGER NOT NULL,
+    index       BIGINT  NOT NULL,
+    orig_net    BIGINT  NOT NULL,
+    orig_addr   VARCHAR NOT NULL,
+    dest_addr   VARCHAR NOT NULL,
+    amount      DECIMAL(78, 0) NOT NULL,
+    PRIMARY KEY (block_num, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS bridge_claim_dest_addr_idx ON state.bridge_claim (dest_addr);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.bridge_claim;
+DROP TABLE IF EXISTS state.bridge_deposit;
@@ -0,0 +1,37 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the tables to index the L2 bridge deposits and claims
+type migrationTest0014 struct{}
+
+func (m migrationTest0014) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+	for _, index := range []string{"bridge_deposit_dest_addr_idx", "bridge_claim_dest_addr_idx"} {
+		var result int
+		assert.NoError(t, db.QueryRow(getIndex, index).Scan(&result))
+		assert.Equal(t, 1, result)
+	}
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name = $1;`
+	for _, table := range []string{"bridge_deposit", "bridge_claim"} {
+		var result int
+		assert.NoError(t, db.QueryRow(getTable, table).Scan(&result))
+		assert.Equal(t, 0, result)
+	}
+}
+
+func TestMigration0014(t *testing.T) {
+	runMigrationTest(t, 14, migrationTest0014{})
+}
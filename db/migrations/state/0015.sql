@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.batch_proving_time
+(
+    batch_num   BIGINT  NOT NULL PRIMARY KEY REFERENCES state.batch (batch_num) ON DELETE CASCADE,
+    prover      VARCHAR,
+    duration_ms BIGINT  NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.batch_proving_time;
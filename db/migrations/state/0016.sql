@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.l1_log
+(
+    block_num  BIGINT  NOT NULL REFERENCES state.block (block_num) ON DELETE CASCADE,
+    log_index  INTEGER NOT NULL,
+    block_hash VARCHAR NOT NULL,
+    tx_hash    VARCHAR NOT NULL,
+    tx_index   INTEGER NOT NULL,
+    address    VARCHAR NOT NULL,
+    topics     VARCHAR[] NOT NULL,
+    data       BYTEA,
+    PRIMARY KEY (block_num, log_index)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.l1_log;
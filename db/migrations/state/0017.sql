@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.batch_lifecycle
+(
+    batch_num    BIGINT  NOT NULL REFERENCES state.batch (batch_num) ON DELETE CASCADE,
+    stage        VARCHAR NOT NULL,
+    l1_tx_hash   VARCHAR,
+    l1_block_num BIGINT REFERENCES state.block (block_num) ON DELETE CASCADE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (batch_num, stage)
+);
+
+INSERT INTO state.batch_lifecycle (batch_num, stage, created_at)
+SELECT batch_num, 'trusted', COALESCE(timestamp, NOW())
+  FROM state.batch
+ WHERE global_exit_root IS NOT NULL AND state_root IS NOT NULL;
+
+INSERT INTO state.batch_lifecycle (batch_num, stage, l1_tx_hash, l1_block_num, created_at)
+SELECT v.batch_num, 'virtualized', v.tx_hash, v.block_num, b.received_at
+  FROM state.virtual_batch v
+  JOIN state.block b ON b.block_num = v.block_num;
+
+INSERT INTO state.batch_lifecycle (batch_num, stage, created_at)
+SELECT batch_num, 'proven', created_at
+  FROM state.batch_proving_time;
+
+INSERT INTO state.batch_lifecycle (batch_num, stage, l1_tx_hash, l1_block_num, created_at)
+SELECT v.batch_num, 'verified', v.tx_hash, v.block_num, b.received_at
+  FROM state.verified_batch v
+  JOIN state.block b ON b.block_num = v.block_num;
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.batch_lifecycle;
@@ -0,0 +1,44 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the table to track the lifecycle of the batches, filled with the existing batches
+type migrationTest0017 struct{}
+
+func (m migrationTest0017) InsertData(db *sql.DB) error {
+	const addBlock = "INSERT INTO state.block (block_num, received_at, block_hash) VALUES ($1, $2, $3)"
+	if _, err := db.Exec(addBlock, 1, "2023-10-10 10:00:00+00", "0x1"); err != nil {
+		return err
+	}
+	const addBatch = `INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num)
+		VALUES ($1, '0x0', '0x0', '0x0', '0x0', '2023-10-10 09:00:00+00', '0x0', NULL, NULL)`
+	if _, err := db.Exec(addBatch, 1); err != nil {
+		return err
+	}
+	const addVirtualBatch = "INSERT INTO state.virtual_batch (batch_num, tx_hash, coinbase, block_num) VALUES ($1, $2, $3, $4)"
+	_, err := db.Exec(addVirtualBatch, 1, "0x2", "0x0", 1)
+	return err
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getStages = `SELECT count(*) FROM state.batch_lifecycle WHERE batch_num = 1 AND stage IN ('trusted', 'virtualized');`
+	var result int
+	assert.NoError(t, db.QueryRow(getStages).Scan(&result))
+	assert.Equal(t, 2, result)
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name = 'batch_lifecycle';`
+	var result int
+	assert.NoError(t, db.QueryRow(getTable).Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0017(t *testing.T) {
+	runMigrationTest(t, 17, migrationTest0017{})
+}
@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS idx_l2block_state_root ON state.l2block (state_root);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.idx_l2block_state_root;
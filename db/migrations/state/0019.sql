@@ -0,0 +1,55 @@
+-- +migrate Up
+-- state_v1 holds the stable views exposed to the external consumers of the state DB, their
+-- columns must not change once released: new columns can be added at the end, any other
+-- change requires a new state_vN schema
+CREATE SCHEMA IF NOT EXISTS state_v1;
+
+CREATE OR REPLACE VIEW state_v1.blocks_v AS
+SELECT l2.block_num,
+       l2.block_hash,
+       l2.parent_hash,
+       l2.state_root,
+       l2.batch_num,
+       l2.received_at,
+       l2.created_at
+  FROM state.l2block l2;
+
+CREATE OR REPLACE VIEW state_v1.receipts_v AS
+SELECT r.tx_hash,
+       r.block_num,
+       l2.block_hash,
+       r.tx_index,
+       r.type,
+       r.status,
+       r.cumulative_gas_used,
+       r.gas_used,
+       r.effective_gas_price,
+       r.contract_address
+  FROM state.receipt r
+  JOIN state.l2block l2 ON l2.block_num = r.block_num;
+
+CREATE OR REPLACE VIEW state_v1.batches_v AS
+SELECT b.batch_num,
+       b.global_exit_root,
+       b.local_exit_root,
+       b.state_root,
+       b.acc_input_hash,
+       b.timestamp,
+       b.coinbase,
+       b.forced_batch_num,
+       b.closing_reason,
+       v.tx_hash   AS virtual_tx_hash,
+       v.block_num AS virtual_block_num,
+       vf.tx_hash   AS verified_tx_hash,
+       vf.block_num AS verified_block_num
+  FROM state.batch b
+  LEFT JOIN state.virtual_batch v ON v.batch_num = b.batch_num
+  LEFT JOIN state.verified_batch vf ON vf.batch_num = b.batch_num;
+
+COMMENT ON SCHEMA state_v1 IS 'Stable views over the state tables, version 1';
+COMMENT ON VIEW state_v1.blocks_v IS 'L2 blocks';
+COMMENT ON VIEW state_v1.receipts_v IS 'Receipts of the L2 transactions';
+COMMENT ON VIEW state_v1.batches_v IS 'Batches with their virtualization and verification on L1, NULL until they happen';
+
+-- +migrate Down
+DROP SCHEMA IF EXISTS state_v1 CASCADE;
@@ -0,0 +1,77 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the state_v1 schema with the stable views over the state tables
+type migrationTest0019 struct{}
+
+func (m migrationTest0019) InsertData(db *sql.DB) error {
+	const addBlock = "INSERT INTO state.block (block_num, received_at, block_hash) VALUES ($1, $2, $3)"
+	if _, err := db.Exec(addBlock, 1, "2023-10-10 10:00:00+00", "0x1"); err != nil {
+		return err
+	}
+	const addBatch = `INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num)
+		VALUES ($1, '0x0', '0x0', '0x0', '0x0', '2023-10-10 09:00:00+00', '0x0', NULL, NULL)`
+	if _, err := db.Exec(addBatch, 1); err != nil {
+		return err
+	}
+	if _, err := db.Exec(addBatch, 2); err != nil {
+		return err
+	}
+	const addVirtualBatch = "INSERT INTO state.virtual_batch (batch_num, tx_hash, coinbase, block_num) VALUES ($1, $2, $3, $4)"
+	if _, err := db.Exec(addVirtualBatch, 1, "0x2", "0x0", 1); err != nil {
+		return err
+	}
+	const addL2Block = `INSERT INTO state.l2block (block_num, block_hash, header, uncles, parent_hash, state_root, received_at, batch_num, created_at)
+		VALUES (1, '0x3', '{}', '{}', '0x0', '0x0', '2023-10-10 09:00:00+00', 1, '2023-10-10 09:00:00+00')`
+	if _, err := db.Exec(addL2Block); err != nil {
+		return err
+	}
+	const addTx = "INSERT INTO state.transaction (hash, encoded, decoded, l2_block_num) VALUES ('0x4', '0x', '{}', 1)"
+	if _, err := db.Exec(addTx); err != nil {
+		return err
+	}
+	const addReceipt = `INSERT INTO state.receipt (tx_hash, type, post_state, status, cumulative_gas_used, gas_used, block_num, tx_index, contract_address, effective_gas_price)
+		VALUES ('0x4', 0, NULL, 1, 21000, 21000, 1, 0, NULL, 1000)`
+	_, err := db.Exec(addReceipt)
+	return err
+}
+
+func (m migrationTest0019) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var blockHash string
+	assert.NoError(t, db.QueryRow("SELECT block_hash FROM state_v1.blocks_v WHERE block_num = 1").Scan(&blockHash))
+	assert.Equal(t, "0x3", blockHash)
+
+	var gasUsed, effectiveGasPrice uint64
+	assert.NoError(t, db.QueryRow("SELECT block_hash, gas_used, effective_gas_price FROM state_v1.receipts_v WHERE tx_hash = '0x4'").Scan(&blockHash, &gasUsed, &effectiveGasPrice))
+	assert.Equal(t, "0x3", blockHash)
+	assert.Equal(t, uint64(21000), gasUsed)
+	assert.Equal(t, uint64(1000), effectiveGasPrice)
+
+	var virtualTxHash, verifiedTxHash sql.NullString
+	assert.NoError(t, db.QueryRow("SELECT virtual_tx_hash, verified_tx_hash FROM state_v1.batches_v WHERE batch_num = 1").Scan(&virtualTxHash, &verifiedTxHash))
+	assert.Equal(t, "0x2", virtualTxHash.String)
+	assert.False(t, verifiedTxHash.Valid)
+	assert.NoError(t, db.QueryRow("SELECT virtual_tx_hash, verified_tx_hash FROM state_v1.batches_v WHERE batch_num = 2").Scan(&virtualTxHash, &verifiedTxHash))
+	assert.False(t, virtualTxHash.Valid)
+}
+
+func (m migrationTest0019) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getSchema = `SELECT count(*) FROM information_schema.schemata WHERE schema_name = 'state_v1';`
+	var result int
+	assert.NoError(t, db.QueryRow(getSchema).Scan(&result))
+	assert.Equal(t, 0, result)
+
+	// the data of the views is kept in the state tables
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM state.receipt").Scan(&result))
+	assert.Equal(t, 1, result)
+}
+
+func TestMigration0019(t *testing.T) {
+	runMigrationTest(t, 19, migrationTest0019{})
+}
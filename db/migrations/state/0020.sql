@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS state.verified_batch_proof
+(
+    batch_num       BIGINT  NOT NULL,
+    batch_num_final BIGINT  NOT NULL PRIMARY KEY REFERENCES state.batch (batch_num) ON DELETE CASCADE,
+    proof           VARCHAR NOT NULL,
+    public_inputs   JSONB   NOT NULL,
+    new_state_root  VARCHAR NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS state.verified_batch_proof;
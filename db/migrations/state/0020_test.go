@@ -0,0 +1,46 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the table of the final proofs sent to L1 to verify the batches
+type migrationTest0020 struct{}
+
+func (m migrationTest0020) InsertData(db *sql.DB) error {
+	const addBatch = `INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num)
+		VALUES ($1, '0x0', '0x0', '0x0', '0x0', '2023-10-10 09:00:00+00', '0x0', NULL, NULL)`
+	_, err := db.Exec(addBatch, 1)
+	return err
+}
+
+func (m migrationTest0020) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const addProof = `INSERT INTO state.verified_batch_proof (batch_num, batch_num_final, proof, public_inputs, new_state_root)
+		VALUES (1, 1, '0x1234', '{"newBatchNum": 1}', '0x5')`
+	_, err := db.Exec(addProof)
+	assert.NoError(t, err)
+
+	_, err = db.Exec(addProof)
+	assert.Error(t, err, "only one proof per final batch")
+
+	// the proof is deleted along with its final batch
+	_, err = db.Exec("DELETE FROM state.batch WHERE batch_num = 1")
+	assert.NoError(t, err)
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM state.verified_batch_proof").Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func (m migrationTest0020) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name = 'verified_batch_proof';`
+	var result int
+	assert.NoError(t, db.QueryRow(getTable).Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0020(t *testing.T) {
+	runMigrationTest(t, 20, migrationTest0020{})
+}
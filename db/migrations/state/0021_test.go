@@ -0,0 +1,59 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the priority fee of the dynamic fee txs to the monitored txs
+type migrationTest0021 struct{}
+
+func (m migrationTest0021) InsertData(db *sql.DB) error {
+	addMonitoredTx := `
+        INSERT INTO state.monitored_txs (owner, id, from_addr, to_addr, nonce, value, data, gas, gas_offset, gas_price, status, history, block_num, created_at, updated_at)
+                                VALUES (   $1, $2,        $3,      $4,    $5,    $6,   $7,  $8,         $9,       $10,    $11,     $12,       $13,        $14,        $15);`
+
+	args := []interface{}{
+		"owner", "id1", common.HexToAddress("0x111").String(), common.HexToAddress("0x222").String(), 333, 444,
+		[]byte{5, 5, 5}, 666, 0, 777, "status", []string{common.HexToHash("0x888").String()}, 999, time.Now(), time.Now(),
+	}
+	_, err := db.Exec(addMonitoredTx, args...)
+	return err
+}
+
+func (m migrationTest0021) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	addMonitoredTx := `
+        INSERT INTO state.monitored_txs (owner, id, from_addr, to_addr, nonce, value, data, gas, gas_offset, gas_price, gas_tip_cap, status, history, block_num, created_at, updated_at)
+                                VALUES (   $1, $2,        $3,      $4,    $5,    $6,   $7,  $8,         $9,       $10,         $11,    $12,     $13,       $14,        $15,        $16);`
+
+	args := []interface{}{
+		"owner", "id2", common.HexToAddress("0x111").String(), common.HexToAddress("0x222").String(), 333, 444,
+		[]byte{5, 5, 5}, 666, 0, 777, 888, "status", []string{common.HexToHash("0x888").String()}, 999, time.Now(), time.Now(),
+	}
+	_, err := db.Exec(addMonitoredTx, args...)
+	assert.NoError(t, err)
+
+	var gasTipCap *uint64
+	getGasTipCapQuery := `SELECT gas_tip_cap FROM state.monitored_txs WHERE id = $1`
+	assert.NoError(t, db.QueryRow(getGasTipCapQuery, "id1").Scan(&gasTipCap))
+	assert.Nil(t, gasTipCap)
+
+	assert.NoError(t, db.QueryRow(getGasTipCapQuery, "id2").Scan(&gasTipCap))
+	assert.NotNil(t, gasTipCap)
+	assert.Equal(t, uint64(888), *gasTipCap)
+}
+
+func (m migrationTest0021) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'state' AND table_name = 'monitored_txs' AND column_name = 'gas_tip_cap'`
+	var result int
+	assert.NoError(t, db.QueryRow(getColumn).Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0021(t *testing.T) {
+	runMigrationTest(t, 21, migrationTest0021{})
+}
@@ -0,0 +1,27 @@
+-- +migrate Up
+ALTER TABLE state.l2block
+    ADD COLUMN logs_bloom BIT(2048);
+
+UPDATE state.l2block
+   SET logs_bloom = ('x' || substring(header->>'logsBloom' FROM 3))::BIT(2048)
+ WHERE header->>'logsBloom' IS NOT NULL;
+
+-- the blooms of the logs of each range of 4096 blocks aggregated, so the log queries skip the ranges
+-- without matching blocks
+CREATE TABLE state.log_bloom_section
+(
+    section_num BIGINT PRIMARY KEY,
+    bloom       BIT(2048) NOT NULL
+);
+
+INSERT INTO state.log_bloom_section (section_num, bloom)
+SELECT block_num / 4096, bit_or(logs_bloom)
+  FROM state.l2block
+ WHERE logs_bloom IS NOT NULL
+ GROUP BY block_num / 4096;
+
+-- +migrate Down
+DROP TABLE state.log_bloom_section;
+
+ALTER TABLE state.l2block
+    DROP COLUMN logs_bloom;
@@ -0,0 +1,78 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the bloom filters of the logs of the l2 blocks and of the sections of 4096 blocks
+type migrationTest0022 struct{}
+
+// migrationTest0022Bloom returns a logs bloom with the provided byte set in the last position
+func migrationTest0022Bloom(b byte) string {
+	return strings.Repeat("00", 255) + fmt.Sprintf("%02x", b)
+}
+
+func (m migrationTest0022) InsertData(db *sql.DB) error {
+	const addBatch = `INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num)
+		VALUES ($1, '0x0', '0x0', '0x0', '0x0', '2023-10-10 09:00:00+00', '0x0', NULL, NULL)`
+	if _, err := db.Exec(addBatch, 1); err != nil {
+		return err
+	}
+	const addL2Block = `INSERT INTO state.l2block (block_num, block_hash, header, uncles, parent_hash, state_root, received_at, batch_num, created_at)
+		VALUES ($1, $2, $3, '{}', '0x0', '0x0', '2023-10-10 09:00:00+00', 1, '2023-10-10 09:00:00+00')`
+	blocks := []struct {
+		number uint64
+		header string
+	}{
+		{1, fmt.Sprintf(`{"logsBloom": "0x%s"}`, migrationTest0022Bloom(0x01))},
+		{2, fmt.Sprintf(`{"logsBloom": "0x%s"}`, migrationTest0022Bloom(0x02))},
+		{3, "{}"},
+		{4097, fmt.Sprintf(`{"logsBloom": "0x%s"}`, migrationTest0022Bloom(0x04))},
+	}
+	for _, b := range blocks {
+		if _, err := db.Exec(addL2Block, b.number, fmt.Sprintf("0x%d", b.number), b.header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m migrationTest0022) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const blockBloomMatches = "SELECT logs_bloom = ('x' || $2)::BIT(2048) FROM state.l2block WHERE block_num = $1"
+	var matches bool
+	assert.NoError(t, db.QueryRow(blockBloomMatches, 1, migrationTest0022Bloom(0x01)).Scan(&matches))
+	assert.True(t, matches)
+	assert.NoError(t, db.QueryRow(blockBloomMatches, 4097, migrationTest0022Bloom(0x04)).Scan(&matches))
+	assert.True(t, matches)
+
+	var isNull bool
+	assert.NoError(t, db.QueryRow("SELECT logs_bloom IS NULL FROM state.l2block WHERE block_num = 3").Scan(&isNull))
+	assert.True(t, isNull)
+
+	// the blooms of the blocks are aggregated by section
+	const sectionBloomMatches = "SELECT bloom = ('x' || $2)::BIT(2048) FROM state.log_bloom_section WHERE section_num = $1"
+	assert.NoError(t, db.QueryRow(sectionBloomMatches, 0, migrationTest0022Bloom(0x03)).Scan(&matches))
+	assert.True(t, matches)
+	assert.NoError(t, db.QueryRow(sectionBloomMatches, 1, migrationTest0022Bloom(0x04)).Scan(&matches))
+	assert.True(t, matches)
+}
+
+func (m migrationTest0022) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name = 'log_bloom_section'`
+	var result int
+	assert.NoError(t, db.QueryRow(getTable).Scan(&result))
+	assert.Equal(t, 0, result)
+
+	const getColumn = `SELECT count(*) FROM information_schema.columns WHERE table_schema = 'state' AND table_name = 'l2block' AND column_name = 'logs_bloom'`
+	assert.NoError(t, db.QueryRow(getColumn).Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0022(t *testing.T) {
+	runMigrationTest(t, 22, migrationTest0022{})
+}
@@ -0,0 +1,12 @@
+-- +migrate Up
+-- the L1 reorgs handled by the synchronizer, the components caching L1 data (e.g. the GER
+-- cached by the sequencer) poll them to invalidate it without handling a trusted reorg
+CREATE TABLE state.l1_reorg
+(
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    block_num BIGINT NOT NULL,
+    reason    VARCHAR NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE state.l1_reorg;
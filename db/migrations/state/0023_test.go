@@ -0,0 +1,36 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the table of the L1 reorgs handled by the synchronizer
+type migrationTest0023 struct{}
+
+func (m migrationTest0023) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0023) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const addL1Reorg = "INSERT INTO state.l1_reorg (block_num, reason) VALUES ($1, $2)"
+	_, err := db.Exec(addL1Reorg, 10, "L1 reorg")
+	assert.NoError(t, err)
+
+	var count int
+	assert.NoError(t, db.QueryRow("SELECT count(*) FROM state.l1_reorg").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func (m migrationTest0023) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getTable = `SELECT count(*) FROM information_schema.tables WHERE table_schema = 'state' AND table_name = 'l1_reorg'`
+	var result int
+	assert.NoError(t, db.QueryRow(getTable).Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0023(t *testing.T) {
+	runMigrationTest(t, 23, migrationTest0023{})
+}
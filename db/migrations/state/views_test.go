@@ -0,0 +1,83 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type viewColumn struct {
+	name     string
+	dataType string
+}
+
+// stableViews are the columns of the stable views as they were released, the views
+// must keep them in the same order and with the same types after any later migration
+var stableViews = map[string][]viewColumn{
+	"state_v1.blocks_v": {
+		{"block_num", "bigint"},
+		{"block_hash", "character varying"},
+		{"parent_hash", "character varying"},
+		{"state_root", "character varying"},
+		{"batch_num", "bigint"},
+		{"received_at", "timestamp with time zone"},
+		{"created_at", "timestamp with time zone"},
+	},
+	"state_v1.receipts_v": {
+		{"tx_hash", "character varying"},
+		{"block_num", "bigint"},
+		{"block_hash", "character varying"},
+		{"tx_index", "integer"},
+		{"type", "integer"},
+		{"status", "bigint"},
+		{"cumulative_gas_used", "bigint"},
+		{"gas_used", "bigint"},
+		{"effective_gas_price", "bigint"},
+		{"contract_address", "character varying"},
+	},
+	"state_v1.batches_v": {
+		{"batch_num", "bigint"},
+		{"global_exit_root", "character varying"},
+		{"local_exit_root", "character varying"},
+		{"state_root", "character varying"},
+		{"acc_input_hash", "character varying"},
+		{"timestamp", "timestamp with time zone"},
+		{"coinbase", "character varying"},
+		{"forced_batch_num", "bigint"},
+		{"closing_reason", "character varying"},
+		{"virtual_tx_hash", "character varying"},
+		{"virtual_block_num", "bigint"},
+		{"verified_tx_hash", "character varying"},
+		{"verified_block_num", "bigint"},
+	},
+}
+
+func TestStableViewsCompatibility(t *testing.T) {
+	d, err := initCleanSQLDB()
+	require.NoError(t, err)
+	require.NoError(t, db.RunMigrationsUp(stateDBCfg, db.StateMigrationName))
+	defer func() {
+		require.NoError(t, db.RunMigrationsDown(stateDBCfg, db.StateMigrationName))
+	}()
+
+	const getColumns = `SELECT column_name, data_type FROM information_schema.columns
+		WHERE table_schema || '.' || table_name = $1 ORDER BY ordinal_position`
+	for view, expected := range stableViews {
+		rows, err := d.Query(getColumns, view)
+		require.NoError(t, err)
+		var columns []viewColumn
+		for rows.Next() {
+			var column viewColumn
+			require.NoError(t, rows.Scan(&column.name, &column.dataType))
+			columns = append(columns, column)
+		}
+		require.NoError(t, rows.Err())
+		require.NoError(t, rows.Close())
+
+		// new columns can only be added after the released ones
+		require.GreaterOrEqual(t, len(columns), len(expected), view)
+		assert.Equal(t, expected, columns[:len(expected)], view)
+	}
+}
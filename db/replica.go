@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// getReplicationLagSQL returns the seconds the replica lags behind the primary, it's 0
+// when all the WAL received was already replayed or the database isn't a replica
+const getReplicationLagSQL = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+	END::FLOAT8`
+
+// ReadRouter routes the read only queries to the read replica while it's available and
+// up to date, falling back to the primary otherwise
+type ReadRouter struct {
+	cfg       ReplicaConfig
+	primary   *pgxpool.Pool
+	replica   *pgxpool.Pool
+	available atomic.Bool
+}
+
+// NewReadRouter creates a ReadRouter for the primary, connecting to the read replica of the
+// config. When no replica is configured all the queries are routed to the primary
+func NewReadRouter(cfg Config, primary *pgxpool.Pool) (*ReadRouter, error) {
+	r := &ReadRouter{
+		cfg:     cfg.ReadReplica,
+		primary: primary,
+	}
+	if cfg.ReadReplica.Host == "" {
+		return r, nil
+	}
+
+	replicaCfg := cfg
+	replicaCfg.Host = cfg.ReadReplica.Host
+	replicaCfg.Port = cfg.ReadReplica.Port
+	replicaCfg.MaxConns = cfg.ReadReplica.MaxConns
+	replica, err := NewSQLDB(replicaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the read replica: %w", err)
+	}
+	r.replica = replica
+	r.check(context.Background())
+	return r, nil
+}
+
+// Start checks the availability and lag of the replica every check interval until the
+// context is done
+func (r *ReadRouter) Start(ctx context.Context) {
+	if r.replica == nil {
+		return
+	}
+	ticker := time.NewTicker(r.cfg.CheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.check(ctx)
+		}
+	}
+}
+
+// Reader returns the replica when it's available, the primary otherwise
+func (r *ReadRouter) Reader() *pgxpool.Pool {
+	if r.replica != nil && r.available.Load() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// Begin starts a read only transaction in the replica when it's available, falling back to
+// the primary if it fails
+func (r *ReadRouter) Begin(ctx context.Context) (pgx.Tx, error) {
+	txOptions := pgx.TxOptions{AccessMode: pgx.ReadOnly}
+	if r.replica != nil && r.available.Load() {
+		tx, err := r.replica.BeginTx(ctx, txOptions)
+		if err == nil {
+			return tx, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		log.Warnf("failed to begin a transaction in the read replica, falling back to the primary: %v", err)
+		r.setAvailable(false)
+	}
+	return r.primary.BeginTx(ctx, txOptions)
+}
+
+// check updates the availability of the replica
+func (r *ReadRouter) check(ctx context.Context) {
+	var lag float64
+	err := r.replica.QueryRow(ctx, getReplicationLagSQL).Scan(&lag)
+	if err != nil {
+		log.Warnf("failed to check the read replica: %v", err)
+		r.setAvailable(false)
+		return
+	}
+	lagDuration := time.Duration(lag * float64(time.Second))
+	if r.cfg.MaxLag.Duration > 0 && lagDuration > r.cfg.MaxLag.Duration {
+		log.Warnf("read replica lags %v behind the primary, max lag allowed %v", lagDuration, r.cfg.MaxLag.Duration)
+		r.setAvailable(false)
+		return
+	}
+	r.setAvailable(true)
+}
+
+func (r *ReadRouter) setAvailable(available bool) {
+	if r.available.Swap(available) == available {
+		return
+	}
+	if available {
+		log.Info("read replica available, routing the read queries to it")
+	} else {
+		log.Warn("read replica unavailable, routing the read queries to the primary")
+	}
+}
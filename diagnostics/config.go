@@ -0,0 +1,31 @@
+package diagnostics
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+// Config has parameters to config the diagnostics HTTP server exposing the profiling
+// endpoints, the goroutine dumps and the health of the components of the node
+type Config struct {
+	// Enabled defines if the diagnostics server is started
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Host is the address to bind the diagnostics server
+	Host string `mapstructure:"Host"`
+
+	// Port is the port to bind the diagnostics server
+	Port int `mapstructure:"Port"`
+
+	// CheckTimeout is the timeout of each component check of the health endpoint
+	CheckTimeout types.Duration `mapstructure:"CheckTimeout"`
+
+	// MaxL1BlocksBehind is the number of L1 blocks the synchronizer can be behind the L1 node
+	// before it's reported as down. 0 disables the check
+	MaxL1BlocksBehind uint64 `mapstructure:"MaxL1BlocksBehind"`
+
+	// MaxBatchesBehind is the number of trusted batches the synchronizer can be behind the
+	// last one seen before it's reported as down. 0 disables the check
+	MaxBatchesBehind uint64 `mapstructure:"MaxBatchesBehind"`
+
+	// MaxLastBatchAge is the time since the last batch was opened before the sequencing is
+	// reported as down, used to alert of a stopped trusted sequencer. 0 disables the check
+	MaxLastBatchAge types.Duration `mapstructure:"MaxLastBatchAge"`
+}
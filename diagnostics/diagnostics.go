@@ -0,0 +1,298 @@
+// Package diagnostics contains the optional HTTP server exposing the runtime diagnostics of
+// the node: the pprof profiles, the goroutine dumps and a health endpoint reporting the status
+// of the components of the node, for the load balancers and the alerting
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	runtimePprof "runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+const (
+	// HealthEndpoint is the endpoint reporting the status of the components of the node
+	HealthEndpoint = "/health"
+	// GoroutinesEndpoint is the endpoint dumping the stack traces of all the goroutines
+	GoroutinesEndpoint = "/debug/goroutines"
+
+	// StatusUp is the status of a component working as expected
+	StatusUp = "up"
+	// StatusDown is the status of a component failing or lagging behind the configured thresholds
+	StatusDown = "down"
+
+	// ComponentSynchronizer is the component reporting the lag of the synchronizer
+	ComponentSynchronizer = "synchronizer"
+	// ComponentSequencing is the component reporting the last sequenced batches
+	ComponentSequencing = "sequencing"
+	// ComponentExecutor is the component reporting the reachability of the executor
+	ComponentExecutor = "executor"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// HealthResponse is the body of the health endpoint
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+// ComponentStatus is the status of a component of the node with the details used to compute it
+type ComponentStatus struct {
+	Status  string                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// componentCheck computes the details of a component, it returns an error if the component is down
+type componentCheck func(ctx context.Context, details map[string]interface{}) error
+
+// Server is the diagnostics HTTP server
+type Server struct {
+	cfg      Config
+	state    stateInterface
+	etherman ethermanInterface
+	checks   map[string]componentCheck
+}
+
+// New creates a diagnostics server, the L1 checks are skipped when the etherman is nil
+func New(cfg Config, st stateInterface, etherman ethermanInterface) *Server {
+	s := &Server{
+		cfg:      cfg,
+		state:    st,
+		etherman: etherman,
+	}
+	s.checks = map[string]componentCheck{
+		ComponentSynchronizer: s.checkSynchronizer,
+		ComponentSequencing:   s.checkSequencing,
+		ComponentExecutor:     s.checkExecutor,
+	}
+	return s
+}
+
+// Start serves the diagnostics endpoints until the context is done
+func (s *Server) Start(ctx context.Context) {
+	address := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("failed to create tcp listener for diagnostics: %v", err)
+		return
+	}
+
+	const timeout = 2 * time.Minute
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: timeout,
+		ReadTimeout:       timeout,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Warnf("failed to shutdown the diagnostics server: %v", err)
+		}
+	}()
+
+	log.Infof("diagnostics server listening on %s", address)
+	if err := srv.Serve(lis); err != nil {
+		if errors.Is(err, http.ErrServerClosed) {
+			log.Warnf("http server for diagnostics stopped")
+			return
+		}
+		log.Errorf("closed http connection for diagnostics server: %v", err)
+	}
+}
+
+// Handler returns the handler serving the diagnostics endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	RegisterProfilingHandlers(mux)
+	mux.HandleFunc(GoroutinesEndpoint, handleGoroutines)
+	mux.HandleFunc(HealthEndpoint, s.handleHealth)
+	return mux
+}
+
+// RegisterProfilingHandlers registers the pprof endpoints in the mux, the named profiles
+// like heap, allocs, block or mutex are served by the index endpoint
+func RegisterProfilingHandlers(mux *http.ServeMux) {
+	mux.HandleFunc(metrics.ProfilingIndexEndpoint, pprof.Index)
+	mux.HandleFunc(metrics.ProfileEndpoint, pprof.Profile)
+	mux.HandleFunc(metrics.ProfilingCmdEndpoint, pprof.Cmdline)
+	mux.HandleFunc(metrics.ProfilingSymbolEndpoint, pprof.Symbol)
+	mux.HandleFunc(metrics.ProfilingTraceEndpoint, pprof.Trace)
+}
+
+// handleGoroutines writes the stack traces of all the goroutines in the same format
+// used by the go runtime when the process panics
+func handleGoroutines(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	const debugFullStacks = 2
+	if err := runtimePprof.Lookup("goroutine").WriteTo(w, debugFullStacks); err != nil {
+		log.Errorf("failed to write the goroutine dump: %v", err)
+	}
+}
+
+// handleHealth responds 200 if all the components are up or 503 if any of them is down,
+// with the status and the details of each component
+func (s *Server) handleHealth(w http.ResponseWriter, req *http.Request) {
+	response := s.Check(req.Context())
+	statusCode := http.StatusOK
+	if response.Status != StatusUp {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("failed to write health response: %v", err)
+	}
+}
+
+// Check runs the checks of all the components concurrently, each one limited by the configured timeout
+func (s *Server) Check(ctx context.Context) HealthResponse {
+	response := HealthResponse{
+		Status:     StatusUp,
+		Components: make(map[string]ComponentStatus, len(s.checks)),
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range s.checks {
+		wg.Add(1)
+		go func(name string, check componentCheck) {
+			defer wg.Done()
+
+			checkCtx := ctx
+			if s.cfg.CheckTimeout.Duration > 0 {
+				var cancel context.CancelFunc
+				checkCtx, cancel = context.WithTimeout(ctx, s.cfg.CheckTimeout.Duration)
+				defer cancel()
+			}
+
+			details := map[string]interface{}{}
+			status := ComponentStatus{Status: StatusUp, Details: details}
+			if err := check(checkCtx, details); err != nil {
+				log.Warnf("diagnostics check %s failed: %v", name, err)
+				status.Status = StatusDown
+				status.Error = err.Error()
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			response.Components[name] = status
+			if status.Status != StatusUp {
+				response.Status = StatusDown
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return response
+}
+
+// checkSynchronizer reports the L1 blocks and the trusted batches the synchronizer is behind
+func (s *Server) checkSynchronizer(ctx context.Context, details map[string]interface{}) error {
+	lastBlock, err := s.state.GetLastBlock(ctx, nil)
+	if err != nil && !errors.Is(err, state.ErrStateNotSynchronized) {
+		return fmt.Errorf("failed to get the last synced L1 block: %w", err)
+	}
+	var lastSyncedBlock uint64
+	if lastBlock != nil {
+		lastSyncedBlock = lastBlock.BlockNumber
+	}
+	details["lastSyncedL1Block"] = lastSyncedBlock
+
+	if s.etherman != nil {
+		latestBlock, err := s.etherman.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get the latest L1 block: %w", err)
+		}
+		var behind uint64
+		if latestBlock > lastSyncedBlock {
+			behind = latestBlock - lastSyncedBlock
+		}
+		details["latestL1Block"] = latestBlock
+		details["l1BlocksBehind"] = behind
+		if s.cfg.MaxL1BlocksBehind > 0 && behind > s.cfg.MaxL1BlocksBehind {
+			return fmt.Errorf("synchronizer is %d L1 blocks behind, max allowed %d", behind, s.cfg.MaxL1BlocksBehind)
+		}
+	}
+
+	syncInfo, err := s.state.GetSyncingInfo(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the syncing info: %w", err)
+	}
+	var batchesBehind uint64
+	if syncInfo.LastBatchNumberSeen > syncInfo.CurrentBatchNumber {
+		batchesBehind = syncInfo.LastBatchNumberSeen - syncInfo.CurrentBatchNumber
+	}
+	details["currentBatch"] = syncInfo.CurrentBatchNumber
+	details["lastBatchSeen"] = syncInfo.LastBatchNumberSeen
+	details["batchesBehind"] = batchesBehind
+	if s.cfg.MaxBatchesBehind > 0 && batchesBehind > s.cfg.MaxBatchesBehind {
+		return fmt.Errorf("synchronizer is %d batches behind, max allowed %d", batchesBehind, s.cfg.MaxBatchesBehind)
+	}
+
+	return nil
+}
+
+// checkSequencing reports the last batch, the last virtual batch and the last batch sequenced in L1
+func (s *Server) checkSequencing(ctx context.Context, details map[string]interface{}) error {
+	lastBatch, err := s.state.GetLastBatchNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the last batch: %w", err)
+	}
+	details["lastBatch"] = lastBatch
+
+	lastBatchTime, err := s.state.GetLastBatchTime(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the time of the last batch: %w", err)
+	}
+	details["lastBatchTime"] = lastBatchTime.UTC()
+
+	lastVirtualBatch, err := s.state.GetLastVirtualBatchNum(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the last virtual batch: %w", err)
+	}
+	details["lastVirtualBatch"] = lastVirtualBatch
+
+	if s.etherman != nil {
+		lastSequencedBatch, err := s.etherman.GetLatestBatchNumber()
+		if err != nil {
+			return fmt.Errorf("failed to get the last batch sequenced in L1: %w", err)
+		}
+		details["lastSequencedBatch"] = lastSequencedBatch
+	}
+
+	if s.cfg.MaxLastBatchAge.Duration > 0 {
+		if age := time.Since(lastBatchTime); age > s.cfg.MaxLastBatchAge.Duration {
+			return fmt.Errorf("last batch opened %s ago, max allowed %s", age.Truncate(time.Second), s.cfg.MaxLastBatchAge.Duration)
+		}
+	}
+
+	return nil
+}
+
+// checkExecutor reports if the executor is reachable reading the last stored flush id
+func (s *Server) checkExecutor(ctx context.Context, details map[string]interface{}) error {
+	flushID, proverID, err := s.state.GetStoredFlushID(ctx)
+	if err != nil {
+		return fmt.Errorf("executor not reachable: %w", err)
+	}
+	details["storedFlushID"] = flushID
+	details["proverID"] = proverID
+	return nil
+}
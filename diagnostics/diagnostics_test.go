@@ -0,0 +1,143 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stateStub struct {
+	lastBlock     uint64
+	syncInfo      state.SyncingInfo
+	lastBatch     uint64
+	lastBatchTime time.Time
+	lastVirtual   uint64
+	executorErr   error
+}
+
+func (s *stateStub) GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Block, error) {
+	return &state.Block{BlockNumber: s.lastBlock}, nil
+}
+
+func (s *stateStub) GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (state.SyncingInfo, error) {
+	return s.syncInfo, nil
+}
+
+func (s *stateStub) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	return s.lastBatch, nil
+}
+
+func (s *stateStub) GetLastBatchTime(ctx context.Context, dbTx pgx.Tx) (time.Time, error) {
+	return s.lastBatchTime, nil
+}
+
+func (s *stateStub) GetLastVirtualBatchNum(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	return s.lastVirtual, nil
+}
+
+func (s *stateStub) GetStoredFlushID(ctx context.Context) (uint64, string, error) {
+	return 1, "prover", s.executorErr
+}
+
+type ethermanStub struct {
+	latestBlock uint64
+	latestBatch uint64
+}
+
+func (e *ethermanStub) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	return e.latestBlock, nil
+}
+
+func (e *ethermanStub) GetLatestBatchNumber() (uint64, error) {
+	return e.latestBatch, nil
+}
+
+func getHealth(t *testing.T, s *Server) (int, HealthResponse) {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HealthEndpoint, nil))
+	var response HealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec.Code, response
+}
+
+func TestHealth(t *testing.T) {
+	st := &stateStub{
+		lastBlock:     100,
+		syncInfo:      state.SyncingInfo{CurrentBatchNumber: 10, LastBatchNumberSeen: 12},
+		lastBatch:     10,
+		lastBatchTime: time.Now(),
+		lastVirtual:   8,
+	}
+	eth := &ethermanStub{latestBlock: 105, latestBatch: 9}
+	cfg := Config{CheckTimeout: types.NewDuration(time.Second), MaxL1BlocksBehind: 10, MaxBatchesBehind: 5}
+	s := New(cfg, st, eth)
+
+	code, response := getHealth(t, s)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusUp, response.Status)
+	require.Len(t, response.Components, 3)
+	synchronizer := response.Components[ComponentSynchronizer]
+	assert.Equal(t, StatusUp, synchronizer.Status)
+	assert.EqualValues(t, 5, synchronizer.Details["l1BlocksBehind"])
+	assert.EqualValues(t, 2, synchronizer.Details["batchesBehind"])
+	sequencing := response.Components[ComponentSequencing]
+	assert.EqualValues(t, 10, sequencing.Details["lastBatch"])
+	assert.EqualValues(t, 8, sequencing.Details["lastVirtualBatch"])
+	assert.EqualValues(t, 9, sequencing.Details["lastSequencedBatch"])
+	assert.Equal(t, StatusUp, response.Components[ComponentExecutor].Status)
+
+	// the synchronizer lags behind L1
+	eth.latestBlock = 200
+	code, response = getHealth(t, s)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusDown, response.Status)
+	assert.Equal(t, StatusDown, response.Components[ComponentSynchronizer].Status)
+	assert.Contains(t, response.Components[ComponentSynchronizer].Error, "100 L1 blocks behind")
+	assert.Equal(t, StatusUp, response.Components[ComponentSequencing].Status)
+	eth.latestBlock = 105
+
+	// the executor is not reachable
+	st.executorErr = errors.New("connection refused")
+	code, response = getHealth(t, s)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusDown, response.Components[ComponentExecutor].Status)
+	assert.Equal(t, StatusUp, response.Components[ComponentSynchronizer].Status)
+	st.executorErr = nil
+
+	// no batch opened for too long
+	s.cfg.MaxLastBatchAge = types.NewDuration(time.Minute)
+	st.lastBatchTime = time.Now().Add(-time.Hour)
+	code, response = getHealth(t, s)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusDown, response.Components[ComponentSequencing].Status)
+}
+
+func TestHealthWithoutEtherman(t *testing.T) {
+	s := New(Config{}, &stateStub{lastBlock: 100, lastBatchTime: time.Now()}, nil)
+
+	code, response := getHealth(t, s)
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotContains(t, response.Components[ComponentSynchronizer].Details, "l1BlocksBehind")
+	assert.NotContains(t, response.Components[ComponentSequencing].Details, "lastSequencedBatch")
+}
+
+func TestGoroutines(t *testing.T) {
+	s := New(Config{}, &stateStub{}, nil)
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, GoroutinesEndpoint, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "goroutine "))
+	assert.Contains(t, rec.Body.String(), "TestGoroutines")
+}
@@ -0,0 +1,25 @@
+package diagnostics
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+)
+
+// stateInterface gathers the methods required to interact with the state.
+type stateInterface interface {
+	GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Block, error)
+	GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (state.SyncingInfo, error)
+	GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	GetLastBatchTime(ctx context.Context, dbTx pgx.Tx) (time.Time, error)
+	GetLastVirtualBatchNum(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	GetStoredFlushID(ctx context.Context) (uint64, string, error)
+}
+
+// ethermanInterface gathers the methods required to interact with the L1 node.
+type ethermanInterface interface {
+	GetLatestBlockNumber(ctx context.Context) (uint64, error)
+	GetLatestBatchNumber() (uint64, error)
+}
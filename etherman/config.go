@@ -1,12 +1,23 @@
 package etherman
 
-import "github.com/0xPolygonHermez/zkevm-node/etherman/etherscan"
+import (
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/etherscan"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+)
 
 // Config represents the configuration of the etherman
 type Config struct {
 	// URL is the URL of the Ethereum node for L1
 	URL string `mapstructure:"URL"`
 
+	// FallbackURLs are the URLs of other Ethereum nodes for L1, the calls fail over to them
+	// in order when the node of URL fails or times out
+	FallbackURLs []string `mapstructure:"FallbackURLs"`
+
+	// Failover is the configuration of the failover between URL and FallbackURLs
+	Failover FailoverConfig `mapstructure:"Failover"`
+
 	// ForkIDChunkSize is the max interval for each call to L1 provider to get the forkIDs
 	ForkIDChunkSize uint64 `mapstructure:"ForkIDChunkSize"`
 
@@ -14,4 +25,27 @@ type Config struct {
 	MultiGasProvider bool `mapstructure:"MultiGasProvider"`
 	// Configuration for use Etherscan as used as gas provider, basically it needs the API-KEY
 	Etherscan etherscan.Config
+
+	// Retry is the retry policy of the reads of the L1 logs and blocks that fail because of the L1 provider
+	Retry retry.Config `mapstructure:"Retry"`
+}
+
+// FailoverConfig represents the configuration of the failover between the L1 nodes,
+// it's only used when there are FallbackURLs
+type FailoverConfig struct {
+	// RequestTimeout is the max time to wait for the response of a L1 node before failing over
+	// to the next one, 0 doesn't limit the time
+	RequestTimeout types.Duration `mapstructure:"RequestTimeout"`
+
+	// HealthCheckInterval is the time between the checks of the latest block of the L1 nodes,
+	// that restore the nodes that recover, 0 disables the health checks
+	HealthCheckInterval types.Duration `mapstructure:"HealthCheckInterval"`
+
+	// MaxBlocksBehind is the max number of blocks a L1 node can be behind the highest
+	// block of the L1 nodes to be healthy, 0 disables the check
+	MaxBlocksBehind uint64 `mapstructure:"MaxBlocksBehind"`
+
+	// CrossCheckLogs compares the logs read from a L1 node with the ones of another healthy
+	// L1 node before processing them, the reads of the logs fail while they differ
+	CrossCheckLogs bool `mapstructure:"CrossCheckLogs"`
 }
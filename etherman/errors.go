@@ -22,6 +22,10 @@ var (
 	ErrNoSigner = errors.New("no signer to authorize the transaction with")
 	// ErrMissingTrieNode means that a node is missing on the trie
 	ErrMissingTrieNode = errors.New("missing trie node")
+	// ErrExecutionReverted is returned when a tx is reverted but the revert reason can't be decoded
+	ErrExecutionReverted = errors.New("execution reverted")
+	// ErrL1LogsMismatch is returned when two L1 nodes return different logs for the same query
+	ErrL1LogsMismatch = errors.New("the logs of the L1 nodes don't match")
 
 	errorsCache = map[string]error{
 		ErrGasRequiredExceedsAllowance.Error():             ErrGasRequiredExceedsAllowance,
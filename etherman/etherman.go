@@ -21,6 +21,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmglobalexitroot"
 	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/test/operations"
 	"github.com/ethereum/go-ethereum"
@@ -108,6 +109,7 @@ type ethereumClient interface {
 	ethereum.GasEstimator
 	ethereum.GasPricer
 	ethereum.LogFilterer
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 	ethereum.TransactionReader
 	ethereum.TransactionSender
 
@@ -149,9 +151,8 @@ type Client struct {
 // NewClient creates a new etherman.
 func NewClient(cfg Config, l1Config L1Config) (*Client, error) {
 	// Connect to ethereum node
-	ethClient, err := ethclient.Dial(cfg.URL)
+	ethClient, err := dialL1(cfg)
 	if err != nil {
-		log.Errorf("error connecting to %s: %+v", cfg.URL, err)
 		return nil, err
 	}
 	// Create smc clients
@@ -198,6 +199,30 @@ func NewClient(cfg Config, l1Config L1Config) (*Client, error) {
 	}, nil
 }
 
+// dialL1 connects to the L1 nodes, when there are fallback URLs the returned client fails over between them
+func dialL1(cfg Config) (l1Backend, error) {
+	urls := append([]string{cfg.URL}, cfg.FallbackURLs...)
+	clients := make([]l1Backend, 0, len(urls))
+	for i, url := range urls {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			log.Errorf("error connecting to L1 endpoint #%d: %+v", i, err)
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	if len(clients) == 1 {
+		if cfg.Failover.CrossCheckLogs {
+			log.Warn("the L1 logs can't be cross checked without FallbackURLs")
+		}
+		return clients[0], nil
+	}
+
+	failover := newFailoverClient(cfg.Failover, clients)
+	failover.startHealthCheck()
+	return failover, nil
+}
+
 // VerifyGenBlockNumber verifies if the genesis Block Number is valid
 func (etherMan *Client) VerifyGenBlockNumber(ctx context.Context, genBlockNumber uint64) (bool, error) {
 	start := time.Now()
@@ -314,11 +339,28 @@ type Order struct {
 
 func (etherMan *Client) readEvents(ctx context.Context, query ethereum.FilterQuery) ([]Block, map[common.Hash][]Order, error) {
 	start := time.Now()
-	logs, err := etherMan.EthClient.FilterLogs(ctx, query)
+	var logs []types.Log
+	err := retry.Do(ctx, "etherman_filter_logs", etherMan.cfg.Retry, nil, func() error {
+		var err error
+		logs, err = etherMan.EthClient.FilterLogs(ctx, query)
+		return err
+	})
 	metrics.GetEventsTime(time.Since(start))
 	if err != nil {
 		return nil, nil, err
 	}
+	blocks, blocksOrder, err := etherMan.DecodeEvents(ctx, logs)
+	if err != nil {
+		return nil, nil, err
+	}
+	metrics.ReadAndProcessAllEventsTime(time.Since(start))
+	return blocks, blocksOrder, nil
+}
+
+// DecodeEvents decodes the L1 logs into the blocks with the rollup info and the
+// order of the events. The logs decoded into a block are kept in it, so they can
+// be archived and replayed later with DecodeEvents
+func (etherMan *Client) DecodeEvents(ctx context.Context, logs []types.Log) ([]Block, map[common.Hash][]Order, error) {
 	var blocks []Block
 	blocksOrder := make(map[common.Hash][]Order)
 	startProcess := time.Now()
@@ -331,9 +373,11 @@ func (etherMan *Client) readEvents(ctx context.Context, query ethereum.FilterQue
 			log.Warnf("error processing event. Retrying... Error: %s. vLog: %+v", err.Error(), vLog)
 			return nil, nil, err
 		}
+		if len(blocks) > 0 && blocks[len(blocks)-1].BlockHash == vLog.BlockHash {
+			blocks[len(blocks)-1].Logs = append(blocks[len(blocks)-1].Logs, vLog)
+		}
 	}
 	metrics.ProcessAllEventTime(time.Since(startProcess))
-	metrics.ReadAndProcessAllEventsTime(time.Since(start))
 	return blocks, blocksOrder, nil
 }
 
@@ -557,6 +601,8 @@ func (etherMan *Client) sequenceBatches(opts bind.TransactOpts, sequences []ethm
 	if err != nil {
 		if parsedErr, ok := tryParseError(err); ok {
 			err = parsedErr
+		} else if revertErr, ok := tryDecodeRevertError(err); ok {
+			err = revertErr
 		}
 	}
 
@@ -601,6 +647,8 @@ func (etherMan *Client) BuildTrustedVerifyBatchesTxData(lastVerifiedBatch, newVe
 	if err != nil {
 		if parsedErr, ok := tryParseError(err); ok {
 			err = parsedErr
+		} else if revertErr, ok := tryDecodeRevertError(err); ok {
+			err = revertErr
 		}
 		return nil, nil, err
 	}
@@ -955,11 +1003,16 @@ func (etherMan *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*t
 
 // EthBlockByNumber function retrieves the ethereum block information by ethereum block number.
 func (etherMan *Client) EthBlockByNumber(ctx context.Context, blockNumber uint64) (*types.Block, error) {
-	block, err := etherMan.EthClient.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
-	if err != nil {
-		if errors.Is(err, ethereum.NotFound) || err.Error() == "block does not exist in blockchain" {
-			return nil, ErrNotFound
+	var block *types.Block
+	err := retry.Do(ctx, "etherman_block_by_number", etherMan.cfg.Retry, nil, func() error {
+		var err error
+		block, err = etherMan.EthClient.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+		if err != nil && (errors.Is(err, ethereum.NotFound) || err.Error() == "block does not exist in blockchain") {
+			return retry.Permanent(ErrNotFound)
 		}
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 	return block, nil
@@ -975,6 +1028,20 @@ func (etherMan *Client) GetLatestBatchNumber() (uint64, error) {
 	return etherMan.ZkEVM.LastBatchSequenced(&bind.CallOpts{Pending: false})
 }
 
+// GetSequencedBatchesInfo retrieves the data stored in the smc for the sequence ending in the
+// provided batch, the returned data is empty if no sequence ends in the batch
+func (etherMan *Client) GetSequencedBatchesInfo(ctx context.Context, lastBatchNumber uint64) (ethmanTypes.SequencedBatchesInfo, error) {
+	info, err := etherMan.ZkEVM.SequencedBatches(&bind.CallOpts{Pending: false, Context: ctx}, lastBatchNumber)
+	if err != nil {
+		return ethmanTypes.SequencedBatchesInfo{}, err
+	}
+	return ethmanTypes.SequencedBatchesInfo{
+		AccInputHash:               info.AccInputHash,
+		SequencedTimestamp:         info.SequencedTimestamp,
+		PreviousLastBatchSequenced: info.PreviousLastBatchSequenced,
+	}, nil
+}
+
 // GetLatestBlockNumber gets the latest block number from the ethereum
 func (etherMan *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
 	return etherMan.getBlockNumber(ctx, rpc.LatestBlockNumber)
@@ -1036,6 +1103,8 @@ func (etherMan *Client) ApproveMatic(ctx context.Context, account common.Address
 	if err != nil {
 		if parsedErr, ok := tryParseError(err); ok {
 			err = parsedErr
+		} else if revertErr, ok := tryDecodeRevertError(err); ok {
+			err = revertErr
 		}
 		return nil, fmt.Errorf("error approving balance to send the batch. Error: %w", err)
 	}
@@ -1088,6 +1157,23 @@ func (etherMan *Client) SuggestedGasPrice(ctx context.Context) (*big.Int, error)
 	return suggestedGasPrice, nil
 }
 
+// SuggestedGasFees returns the base fee of the latest block and the suggested priority fee
+// for the network at the moment, used to build the dynamic fee txs
+func (etherMan *Client) SuggestedGasFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	header, err := etherMan.EthClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.BaseFee == nil {
+		return nil, nil, errors.New("the latest block has no base fee, london is not active yet")
+	}
+	gasTipCap, err := etherMan.EthClient.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header.BaseFee, gasTipCap, nil
+}
+
 // EstimateGas returns the estimated gas for the tx
 func (etherMan *Client) EstimateGas(ctx context.Context, from common.Address, to *common.Address, value *big.Int, data []byte) (uint64, error) {
 	return etherMan.EthClient.EstimateGas(ctx, ethereum.CallMsg{
@@ -1135,7 +1221,7 @@ func (etherMan *Client) GetRevertMessage(ctx context.Context, tx *types.Transact
 	}
 
 	if receipt.Status == types.ReceiptStatusFailed {
-		revertMessage, err := operations.RevertReason(ctx, etherMan.EthClient, tx, receipt.BlockNumber)
+		revertMessage, err := etherMan.revertReason(ctx, tx, receipt.BlockNumber)
 		if err != nil {
 			return "", err
 		}
@@ -1144,6 +1230,36 @@ func (etherMan *Client) GetRevertMessage(ctx context.Context, tx *types.Transact
 	return "", nil
 }
 
+// revertReason replays the tx as a call in the block where it was mined and decodes the
+// revert data, including the custom errors of the rollup contracts
+func (etherMan *Client) revertReason(ctx context.Context, tx *types.Transaction, blockNumber *big.Int) (string, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return "", err
+	}
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	data, err := etherMan.EthClient.CallContract(ctx, msg, blockNumber)
+	if err != nil {
+		if revertErr, ok := tryDecodeRevertError(err); ok {
+			return revertErr.Reason(), nil
+		}
+		return "", err
+	}
+
+	revertErr, ok := DecodeRevertData(data)
+	if !ok {
+		log.Warnf("failed to decode the revert message for tx %v", tx.Hash().String())
+		return "", ErrExecutionReverted
+	}
+	return revertErr.Reason(), nil
+}
+
 // AddOrReplaceAuth adds an authorization or replace an existent one to the same account
 func (etherMan *Client) AddOrReplaceAuth(auth bind.TransactOpts) error {
 	log.Infof("added or replaced authorization for address: %v", auth.From.String())
@@ -0,0 +1,382 @@
+package etherman
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcLimitExceededCode is the JSON-RPC error code returned by the L1 providers when the rate limit is exceeded
+const rpcLimitExceededCode = -32005
+
+// l1Backend is the client of a single L1 node
+type l1Backend interface {
+	ethereumClient
+	bind.ContractBackend
+}
+
+// l1Endpoint is one of the L1 nodes of the failover client
+type l1Endpoint struct {
+	// name identifies the endpoint in the logs without leaking the URL, that can contain an API key
+	name    string
+	client  l1Backend
+	healthy atomic.Bool
+}
+
+// failoverClient sends the calls to the first healthy L1 node, in the configured order, and fails over
+// to the next ones when the node fails or times out. A periodic health check restores the nodes that
+// recover and discards the ones that are too many blocks behind the others
+type failoverClient struct {
+	cfg       FailoverConfig
+	endpoints []*l1Endpoint
+}
+
+func newFailoverClient(cfg FailoverConfig, clients []l1Backend) *failoverClient {
+	f := &failoverClient{cfg: cfg}
+	for i, client := range clients {
+		e := &l1Endpoint{name: fmt.Sprintf("L1 endpoint #%d", i), client: client}
+		e.healthy.Store(true)
+		f.endpoints = append(f.endpoints, e)
+	}
+	return f
+}
+
+// startHealthCheck checks the health of the endpoints periodically
+func (f *failoverClient) startHealthCheck() {
+	if f.cfg.HealthCheckInterval.Duration <= 0 {
+		return
+	}
+	go func() {
+		for {
+			time.Sleep(f.cfg.HealthCheckInterval.Duration)
+			f.checkHealth(context.Background())
+		}
+	}()
+}
+
+// checkHealth marks as healthy the endpoints that return the latest block not more than MaxBlocksBehind
+// blocks behind the highest block returned by all the endpoints
+func (f *failoverClient) checkHealth(ctx context.Context) {
+	heads := make([]uint64, len(f.endpoints))
+	errs := make([]error, len(f.endpoints))
+	var highest uint64
+	for i, e := range f.endpoints {
+		reqCtx, cancel := f.requestContext(ctx)
+		header, err := e.client.HeaderByNumber(reqCtx, nil)
+		cancel()
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		heads[i] = header.Number.Uint64()
+		if heads[i] > highest {
+			highest = heads[i]
+		}
+	}
+
+	for i, e := range f.endpoints {
+		if errs[i] != nil {
+			f.setHealth(e, false, fmt.Sprintf("health check failed: %v", errs[i]))
+		} else if f.cfg.MaxBlocksBehind > 0 && heads[i]+f.cfg.MaxBlocksBehind < highest {
+			f.setHealth(e, false, fmt.Sprintf("block %d is %d blocks behind the highest block %d", heads[i], highest-heads[i], highest))
+		} else {
+			f.setHealth(e, true, fmt.Sprintf("health check passed at block %d", heads[i]))
+		}
+	}
+}
+
+func (f *failoverClient) setHealth(e *l1Endpoint, healthy bool, reason string) {
+	if e.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		log.Infof("%s is healthy again: %s", e.name, reason)
+	} else {
+		log.Warnf("%s is unhealthy, failing over to the next L1 endpoint: %s", e.name, reason)
+	}
+}
+
+// orderedEndpoints returns the healthy endpoints followed by the unhealthy ones, so the calls are
+// still attempted when all the endpoints are unhealthy
+func (f *failoverClient) orderedEndpoints() []*l1Endpoint {
+	ordered := make([]*l1Endpoint, 0, len(f.endpoints))
+	for _, e := range f.endpoints {
+		if e.healthy.Load() {
+			ordered = append(ordered, e)
+		}
+	}
+	for _, e := range f.endpoints {
+		if !e.healthy.Load() {
+			ordered = append(ordered, e)
+		}
+	}
+	return ordered
+}
+
+func (f *failoverClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f.cfg.RequestTimeout.Duration <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, f.cfg.RequestTimeout.Duration)
+}
+
+// isEndpointFailure returns if the error is caused by the endpoint, like a connection error, a timeout
+// or a rate limit, instead of being the response to the call, like a not found or a revert
+func isEndpointFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == rpcLimitExceededCode
+	}
+	return true
+}
+
+// failoverCall runs the call in the endpoints until one of them doesn't fail
+func failoverCall[T any](ctx context.Context, f *failoverClient, method string, call func(ctx context.Context, e *l1Endpoint) (T, error)) (T, error) {
+	var (
+		result T
+		err    error
+	)
+	for _, e := range f.orderedEndpoints() {
+		reqCtx, cancel := f.requestContext(ctx)
+		result, err = call(reqCtx, e)
+		cancel()
+		if err == nil || !isEndpointFailure(ctx, err) {
+			return result, err
+		}
+		f.setHealth(e, false, fmt.Sprintf("%s failed: %v", method, err))
+	}
+	return result, err
+}
+
+// failoverSubscribe subscribes in the first endpoint that doesn't fail, the subscription isn't
+// moved to another endpoint if it fails later
+func failoverSubscribe(ctx context.Context, f *failoverClient, method string, subscribe func(client l1Backend) (ethereum.Subscription, error)) (ethereum.Subscription, error) {
+	var (
+		sub ethereum.Subscription
+		err error
+	)
+	for _, e := range f.orderedEndpoints() {
+		sub, err = subscribe(e.client)
+		if err == nil || !isEndpointFailure(ctx, err) {
+			return sub, err
+		}
+		f.setHealth(e, false, fmt.Sprintf("%s failed: %v", method, err))
+	}
+	return sub, err
+}
+
+// FilterLogs returns the logs of the first healthy endpoint, when CrossCheckLogs is enabled the
+// logs are compared with the ones of another healthy endpoint before returning them
+func (f *failoverClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var served *l1Endpoint
+	logs, err := failoverCall(ctx, f, "FilterLogs", func(ctx context.Context, e *l1Endpoint) ([]types.Log, error) {
+		served = e
+		return e.client.FilterLogs(ctx, query)
+	})
+	if err != nil || !f.cfg.CrossCheckLogs {
+		return logs, err
+	}
+	return logs, f.crossCheckLogs(ctx, query, logs, served)
+}
+
+// crossCheckLogs compares the logs returned by an endpoint with the ones returned by another healthy endpoint
+func (f *failoverClient) crossCheckLogs(ctx context.Context, query ethereum.FilterQuery, logs []types.Log, served *l1Endpoint) error {
+	var checker *l1Endpoint
+	for _, e := range f.endpoints {
+		if e != served && e.healthy.Load() {
+			checker = e
+			break
+		}
+	}
+	if checker == nil {
+		log.Warnf("no other healthy L1 endpoint to cross check the logs of %s", served.name)
+		return nil
+	}
+
+	reqCtx, cancel := f.requestContext(ctx)
+	checkLogs, err := checker.client.FilterLogs(reqCtx, query)
+	cancel()
+	if err != nil {
+		if isEndpointFailure(ctx, err) {
+			f.setHealth(checker, false, fmt.Sprintf("FilterLogs failed: %v", err))
+		}
+		return fmt.Errorf("failed to cross check the logs of %s with %s: %w", served.name, checker.name, err)
+	}
+	if len(logs) != len(checkLogs) {
+		return fmt.Errorf("%w: %s returned %d logs and %s returned %d logs", ErrL1LogsMismatch, served.name, len(logs), checker.name, len(checkLogs))
+	}
+	for i := range logs {
+		if logs[i].BlockHash != checkLogs[i].BlockHash || logs[i].TxHash != checkLogs[i].TxHash ||
+			logs[i].Index != checkLogs[i].Index || logs[i].Removed != checkLogs[i].Removed {
+			return fmt.Errorf("%w: log %d of block %d differs between %s and %s", ErrL1LogsMismatch, i, logs[i].BlockNumber, served.name, checker.name)
+		}
+	}
+	return nil
+}
+
+// SubscribeFilterLogs subscribes to the logs in the first healthy endpoint
+func (f *failoverClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return failoverSubscribe(ctx, f, "SubscribeFilterLogs", func(client l1Backend) (ethereum.Subscription, error) {
+		return client.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
+
+// SubscribeNewHead subscribes to the new headers in the first healthy endpoint
+func (f *failoverClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return failoverSubscribe(ctx, f, "SubscribeNewHead", func(client l1Backend) (ethereum.Subscription, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	})
+}
+
+// BlockByHash returns the block with the given hash
+func (f *failoverClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return failoverCall(ctx, f, "BlockByHash", func(ctx context.Context, e *l1Endpoint) (*types.Block, error) {
+		return e.client.BlockByHash(ctx, hash)
+	})
+}
+
+// BlockByNumber returns the block with the given number
+func (f *failoverClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return failoverCall(ctx, f, "BlockByNumber", func(ctx context.Context, e *l1Endpoint) (*types.Block, error) {
+		return e.client.BlockByNumber(ctx, number)
+	})
+}
+
+// HeaderByHash returns the header with the given hash
+func (f *failoverClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return failoverCall(ctx, f, "HeaderByHash", func(ctx context.Context, e *l1Endpoint) (*types.Header, error) {
+		return e.client.HeaderByHash(ctx, hash)
+	})
+}
+
+// HeaderByNumber returns the header with the given number
+func (f *failoverClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return failoverCall(ctx, f, "HeaderByNumber", func(ctx context.Context, e *l1Endpoint) (*types.Header, error) {
+		return e.client.HeaderByNumber(ctx, number)
+	})
+}
+
+// TransactionCount returns the number of txs in the block with the given hash
+func (f *failoverClient) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return failoverCall(ctx, f, "TransactionCount", func(ctx context.Context, e *l1Endpoint) (uint, error) {
+		return e.client.TransactionCount(ctx, blockHash)
+	})
+}
+
+// TransactionInBlock returns the tx at the given index of the block with the given hash
+func (f *failoverClient) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	return failoverCall(ctx, f, "TransactionInBlock", func(ctx context.Context, e *l1Endpoint) (*types.Transaction, error) {
+		return e.client.TransactionInBlock(ctx, blockHash, index)
+	})
+}
+
+// BalanceAt returns the balance of the account at the given block
+func (f *failoverClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return failoverCall(ctx, f, "BalanceAt", func(ctx context.Context, e *l1Endpoint) (*big.Int, error) {
+		return e.client.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+// StorageAt returns the value of the storage key of the account at the given block
+func (f *failoverClient) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return failoverCall(ctx, f, "StorageAt", func(ctx context.Context, e *l1Endpoint) ([]byte, error) {
+		return e.client.StorageAt(ctx, account, key, blockNumber)
+	})
+}
+
+// CodeAt returns the code of the account at the given block
+func (f *failoverClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return failoverCall(ctx, f, "CodeAt", func(ctx context.Context, e *l1Endpoint) ([]byte, error) {
+		return e.client.CodeAt(ctx, account, blockNumber)
+	})
+}
+
+// NonceAt returns the nonce of the account at the given block
+func (f *failoverClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return failoverCall(ctx, f, "NonceAt", func(ctx context.Context, e *l1Endpoint) (uint64, error) {
+		return e.client.NonceAt(ctx, account, blockNumber)
+	})
+}
+
+// PendingCodeAt returns the code of the account in the pending state
+func (f *failoverClient) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return failoverCall(ctx, f, "PendingCodeAt", func(ctx context.Context, e *l1Endpoint) ([]byte, error) {
+		return e.client.PendingCodeAt(ctx, account)
+	})
+}
+
+// PendingNonceAt returns the nonce of the account in the pending state
+func (f *failoverClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return failoverCall(ctx, f, "PendingNonceAt", func(ctx context.Context, e *l1Endpoint) (uint64, error) {
+		return e.client.PendingNonceAt(ctx, account)
+	})
+}
+
+// CallContract executes the call at the given block
+func (f *failoverClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return failoverCall(ctx, f, "CallContract", func(ctx context.Context, e *l1Endpoint) ([]byte, error) {
+		return e.client.CallContract(ctx, call, blockNumber)
+	})
+}
+
+// EstimateGas estimates the gas needed to execute the call
+func (f *failoverClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return failoverCall(ctx, f, "EstimateGas", func(ctx context.Context, e *l1Endpoint) (uint64, error) {
+		return e.client.EstimateGas(ctx, call)
+	})
+}
+
+// SuggestGasPrice returns the suggested gas price
+func (f *failoverClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return failoverCall(ctx, f, "SuggestGasPrice", func(ctx context.Context, e *l1Endpoint) (*big.Int, error) {
+		return e.client.SuggestGasPrice(ctx)
+	})
+}
+
+// SuggestGasTipCap returns the suggested gas tip cap
+func (f *failoverClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return failoverCall(ctx, f, "SuggestGasTipCap", func(ctx context.Context, e *l1Endpoint) (*big.Int, error) {
+		return e.client.SuggestGasTipCap(ctx)
+	})
+}
+
+// TransactionByHash returns the tx with the given hash and if it's pending
+func (f *failoverClient) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	type txByHash struct {
+		tx        *types.Transaction
+		isPending bool
+	}
+	res, err := failoverCall(ctx, f, "TransactionByHash", func(ctx context.Context, e *l1Endpoint) (txByHash, error) {
+		tx, isPending, err := e.client.TransactionByHash(ctx, txHash)
+		return txByHash{tx: tx, isPending: isPending}, err
+	})
+	return res.tx, res.isPending, err
+}
+
+// TransactionReceipt returns the receipt of the tx with the given hash
+func (f *failoverClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return failoverCall(ctx, f, "TransactionReceipt", func(ctx context.Context, e *l1Endpoint) (*types.Receipt, error) {
+		return e.client.TransactionReceipt(ctx, txHash)
+	})
+}
+
+// SendTransaction sends the tx, it's sent to the next endpoint if the endpoint fails
+func (f *failoverClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := failoverCall(ctx, f, "SendTransaction", func(ctx context.Context, e *l1Endpoint) (struct{}, error) {
+		return struct{}{}, e.client.SendTransaction(ctx, tx)
+	})
+	return err
+}
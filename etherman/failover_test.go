@@ -0,0 +1,115 @@
+package etherman
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errConnectionRefused = errors.New("connection refused")
+
+// fakeL1Backend implements the calls used by the tests, the rest of the calls panic
+type fakeL1Backend struct {
+	l1Backend
+	head  uint64
+	logs  []types.Log
+	err   error
+	calls int
+}
+
+func (b *fakeL1Backend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	b.calls++
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(b.head)}, nil
+}
+
+func (b *fakeL1Backend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	b.calls++
+	return b.logs, b.err
+}
+
+func TestFailoverCall(t *testing.T) {
+	primary := &fakeL1Backend{err: errConnectionRefused}
+	fallback := &fakeL1Backend{head: 10}
+	f := newFailoverClient(FailoverConfig{}, []l1Backend{primary, fallback})
+
+	header, err := f.HeaderByNumber(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), header.Number.Uint64())
+	assert.False(t, f.endpoints[0].healthy.Load())
+
+	// the unhealthy endpoint isn't called while there are healthy ones
+	_, err = f.HeaderByNumber(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 2, fallback.calls)
+
+	// the calls are attempted in the unhealthy endpoints when all of them fail
+	fallback.err = errConnectionRefused
+	_, err = f.HeaderByNumber(context.Background(), nil)
+	require.ErrorIs(t, err, errConnectionRefused)
+	assert.Equal(t, 2, primary.calls)
+
+	// the not found responses don't fail over
+	primary.err = nil
+	fallback.err = ethereum.NotFound
+	f.endpoints[0].healthy.Store(false)
+	f.endpoints[1].healthy.Store(true)
+	_, err = f.HeaderByNumber(context.Background(), nil)
+	require.ErrorIs(t, err, ethereum.NotFound)
+	assert.True(t, f.endpoints[1].healthy.Load())
+}
+
+func TestFailoverCheckHealth(t *testing.T) {
+	primary := &fakeL1Backend{head: 100}
+	lagging := &fakeL1Backend{head: 80}
+	failing := &fakeL1Backend{err: errConnectionRefused}
+	f := newFailoverClient(FailoverConfig{MaxBlocksBehind: 10}, []l1Backend{primary, lagging, failing})
+
+	f.checkHealth(context.Background())
+	assert.True(t, f.endpoints[0].healthy.Load())
+	assert.False(t, f.endpoints[1].healthy.Load())
+	assert.False(t, f.endpoints[2].healthy.Load())
+
+	lagging.head = 95
+	failing.err = nil
+	failing.head = 100
+	f.checkHealth(context.Background())
+	assert.True(t, f.endpoints[1].healthy.Load())
+	assert.True(t, f.endpoints[2].healthy.Load())
+}
+
+func TestFailoverCrossCheckLogs(t *testing.T) {
+	logs := []types.Log{{BlockNumber: 1, BlockHash: common.HexToHash("0x1"), TxHash: common.HexToHash("0x2"), Index: 0}}
+	primary := &fakeL1Backend{logs: logs}
+	checker := &fakeL1Backend{logs: logs}
+	f := newFailoverClient(FailoverConfig{CrossCheckLogs: true}, []l1Backend{primary, checker})
+
+	result, err := f.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, logs, result)
+	assert.Equal(t, 1, checker.calls)
+
+	checker.logs = []types.Log{{BlockNumber: 1, BlockHash: common.HexToHash("0x3"), TxHash: common.HexToHash("0x2"), Index: 0}}
+	_, err = f.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	require.ErrorIs(t, err, ErrL1LogsMismatch)
+
+	checker.logs = nil
+	_, err = f.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	require.ErrorIs(t, err, ErrL1LogsMismatch)
+
+	// without another healthy endpoint the logs aren't cross checked
+	f.endpoints[1].healthy.Store(false)
+	result, err = f.FilterLogs(context.Background(), ethereum.FilterQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, logs, result)
+}
@@ -0,0 +1,137 @@
+package etherman
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevm"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmglobalexitroot"
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const selectorLength = 4
+
+// RevertError is the revert of an L1 tx or call decoded using the ABIs of the
+// known rollup contracts, it contains the human readable name of the error and
+// its arguments instead of the raw revert data
+type RevertError struct {
+	// Contract is the name of the contract that defines the error, empty for the
+	// standard Error(string) reverts
+	Contract string
+	// Name is the name of the error
+	Name string
+	// Args are the decoded arguments of the error as "name=value"
+	Args []string
+	// Data is the raw revert data
+	Data []byte
+}
+
+// Reason returns the human readable revert reason
+func (e *RevertError) Reason() string {
+	name := e.Name
+	if e.Contract != "" {
+		name = e.Contract + "." + e.Name
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(e.Args, ", "))
+}
+
+// Error returns the error message
+func (e *RevertError) Error() string {
+	return "execution reverted: " + e.Reason()
+}
+
+type knownContractABI struct {
+	name     string
+	metadata *bind.MetaData
+}
+
+var (
+	knownContractABIs = []knownContractABI{
+		{name: "PolygonZkEVM", metadata: polygonzkevm.PolygonzkevmMetaData},
+		{name: "PolygonZkEVMBridge", metadata: polygonzkevmbridge.PolygonzkevmbridgeMetaData},
+		{name: "PolygonZkEVMGlobalExitRoot", metadata: polygonzkevmglobalexitroot.PolygonzkevmglobalexitrootMetaData},
+	}
+
+	knownErrorsOnce sync.Once
+	knownErrors     map[[selectorLength]byte]knownError
+)
+
+type knownError struct {
+	contract string
+	abiError abi.Error
+}
+
+func loadKnownErrors() {
+	knownErrors = make(map[[selectorLength]byte]knownError)
+	for _, contract := range knownContractABIs {
+		parsed, err := contract.metadata.GetAbi()
+		if err != nil {
+			log.Errorf("failed to parse %s ABI to decode revert errors: %v", contract.name, err)
+			continue
+		}
+		for _, abiError := range parsed.Errors {
+			var selector [selectorLength]byte
+			copy(selector[:], abiError.ID[:selectorLength])
+			if _, found := knownErrors[selector]; found {
+				continue
+			}
+			knownErrors[selector] = knownError{contract: contract.name, abiError: abiError}
+		}
+	}
+}
+
+// DecodeRevertData decodes the revert data of an L1 tx or call, it supports the
+// standard Error(string) reverts and the custom errors of the known rollup contracts
+func DecodeRevertData(data []byte) (*RevertError, bool) {
+	if len(data) < selectorLength {
+		return nil, false
+	}
+
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return &RevertError{Name: "Error", Args: []string{fmt.Sprintf("%q", reason)}, Data: data}, true
+	}
+
+	knownErrorsOnce.Do(loadKnownErrors)
+	var selector [selectorLength]byte
+	copy(selector[:], data[:selectorLength])
+	known, found := knownErrors[selector]
+	if !found {
+		return nil, false
+	}
+
+	values, err := known.abiError.Inputs.Unpack(data[selectorLength:])
+	if err != nil {
+		return nil, false
+	}
+	args := make([]string, 0, len(values))
+	for i, value := range values {
+		args = append(args, fmt.Sprintf("%s=%v", known.abiError.Inputs[i].Name, value))
+	}
+
+	return &RevertError{Contract: known.contract, Name: known.abiError.Name, Args: args, Data: data}, true
+}
+
+// tryDecodeRevertError decodes the revert data included by the L1 node in the
+// error returned for a reverted call or gas estimation
+func tryDecodeRevertError(err error) (*RevertError, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hex.DecodeHex(hexData)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return DecodeRevertData(data)
+}
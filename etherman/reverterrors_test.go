@@ -0,0 +1,61 @@
+package etherman
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDataError struct {
+	data interface{}
+}
+
+func (e testDataError) Error() string          { return "execution reverted" }
+func (e testDataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevertData(t *testing.T) {
+	customErr := crypto.Keccak256([]byte("OnlyTrustedSequencer()"))[:4]
+	revertErr, ok := DecodeRevertData(customErr)
+	require.True(t, ok)
+	assert.Equal(t, "PolygonZkEVM", revertErr.Contract)
+	assert.Equal(t, "OnlyTrustedSequencer", revertErr.Name)
+	assert.Equal(t, "execution reverted: PolygonZkEVM.OnlyTrustedSequencer()", revertErr.Error())
+
+	bridgeErr := crypto.Keccak256([]byte("AlreadyClaimed()"))[:4]
+	revertErr, ok = DecodeRevertData(bridgeErr)
+	require.True(t, ok)
+	assert.Equal(t, "PolygonZkEVMBridge.AlreadyClaimed()", revertErr.Reason())
+
+	// Error(string) with the "boom" reason
+	standardErr := []byte{0x08, 0xc3, 0x79, 0xa0}
+	standardErr = append(standardErr, make([]byte, 31)...)
+	standardErr = append(standardErr, 0x20)
+	standardErr = append(standardErr, make([]byte, 31)...)
+	standardErr = append(standardErr, 0x04)
+	standardErr = append(standardErr, []byte("boom")...)
+	standardErr = append(standardErr, make([]byte, 28)...)
+	revertErr, ok = DecodeRevertData(standardErr)
+	require.True(t, ok)
+	assert.Equal(t, `Error("boom")`, revertErr.Reason())
+
+	_, ok = DecodeRevertData([]byte{0x01, 0x02, 0x03, 0x04})
+	assert.False(t, ok)
+	_, ok = DecodeRevertData([]byte{0x01})
+	assert.False(t, ok)
+}
+
+func TestTryDecodeRevertError(t *testing.T) {
+	data := crypto.Keccak256([]byte("SequenceZeroBatches()"))[:4]
+	revertErr, ok := tryDecodeRevertError(testDataError{data: hex.EncodeToHex(data)})
+	require.True(t, ok)
+	assert.Equal(t, "SequenceZeroBatches", revertErr.Name)
+
+	_, ok = tryDecodeRevertError(testDataError{data: 1})
+	assert.False(t, ok)
+	_, ok = tryDecodeRevertError(errors.New("execution reverted"))
+	assert.False(t, ok)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevm"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // Block struct
@@ -19,6 +20,8 @@ type Block struct {
 	SequencedForceBatches [][]SequencedForceBatch
 	ForkIDs               []ForkID
 	ReceivedAt            time.Time
+	// Logs are the raw L1 logs decoded into the block
+	Logs []types.Log
 }
 
 // GlobalExitRoot struct
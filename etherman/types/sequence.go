@@ -22,3 +22,11 @@ type Sequence struct {
 func (s Sequence) IsEmpty() bool {
 	return reflect.DeepEqual(s, Sequence{})
 }
+
+// SequencedBatchesInfo is the data stored in the PoE smart contract for the last
+// batch of each sequence
+type SequencedBatchesInfo struct {
+	AccInputHash               common.Hash
+	SequencedTimestamp         uint64
+	PreviousLastBatchSequenced uint64
+}
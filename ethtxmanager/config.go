@@ -2,6 +2,13 @@ package ethtxmanager
 
 import "github.com/0xPolygonHermez/zkevm-node/config/types"
 
+const (
+	// PriorityFeeStrategySuggested uses the priority fee suggested by the L1 node, with PriorityFee as the minimum
+	PriorityFeeStrategySuggested = "suggested"
+	// PriorityFeeStrategyFixed always uses PriorityFee as the priority fee
+	PriorityFeeStrategyFixed = "fixed"
+)
+
 // Config is configuration for ethereum transaction manager
 type Config struct {
 	// FrequencyToMonitorTxs frequency of the resending failed txs
@@ -48,4 +55,26 @@ type Config struct {
 	// max gas price limit: 110
 	// tx gas price = 110
 	MaxGasPriceLimit uint64 `mapstructure:"MaxGasPriceLimit"`
+
+	// DynamicFeeTxs builds the L1 txs as EIP-1559 dynamic fee txs instead of legacy gas price txs.
+	// The max fee per gas is limited by MaxGasPriceLimit the same way as the gas price of the legacy txs
+	DynamicFeeTxs bool `mapstructure:"DynamicFeeTxs"`
+
+	// PriorityFeeStrategy is how the priority fee per gas of the dynamic fee txs is chosen,
+	// "suggested" or "fixed"
+	PriorityFeeStrategy string `mapstructure:"PriorityFeeStrategy"`
+
+	// PriorityFee is the priority fee per gas in wei of the "fixed" strategy and the
+	// min priority fee per gas of the "suggested" strategy
+	PriorityFee uint64 `mapstructure:"PriorityFee"`
+
+	// BaseFeeMultiplier multiplies the base fee of the latest L1 block to get the max fee per gas
+	// of the dynamic fee txs, so they can still be mined if the base fee raises, the priority fee is
+	// added on top of it
+	BaseFeeMultiplier float64 `mapstructure:"BaseFeeMultiplier"`
+
+	// FeeBumpPercentage is the percentage the gas price, or the fees of the dynamic fee txs, is raised
+	// when a tx isn't mined after WaitTxToBeMined and it's resent with the same nonce. The L1 nodes
+	// require a bump of at least 10 to replace a pending tx, 0 disables the bump
+	FeeBumpPercentage uint64 `mapstructure:"FeeBumpPercentage"`
 }
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -21,7 +22,11 @@ import (
 )
 
 const (
-	failureIntervalInSeconds = 5
+	// failureInterval is the time to wait before monitoring the txs again after a failure
+	failureInterval = 5 * time.Second
+	// monitorTxsAttempts is the max number of times the txs are monitored in a row when it fails,
+	// the monitoring is retried in the next interval afterwards
+	monitorTxsAttempts = 3
 	// maxHistorySize           = 10
 )
 
@@ -81,7 +86,7 @@ func (c *Client) Add(ctx context.Context, owner, id string, from common.Address,
 	}
 
 	// get gas price
-	gasPrice, err := c.suggestedGasPrice(ctx)
+	gasPrice, gasTipCap, err := c.suggestedFees(ctx)
 	if err != nil {
 		err := fmt.Errorf("failed to get suggested gas price: %w", err)
 		log.Errorf(err.Error())
@@ -93,7 +98,7 @@ func (c *Client) Add(ctx context.Context, owner, id string, from common.Address,
 		owner: owner, id: id, from: from, to: to,
 		nonce: nonce, value: value, data: data,
 		gas: gas, gasOffset: gasOffset, gasPrice: gasPrice,
-		status: MonitoredTxStatusCreated,
+		gasTipCap: gasTipCap, status: MonitoredTxStatusCreated,
 	}
 
 	// add to storage
@@ -205,9 +210,11 @@ func (c *Client) Start() {
 		case <-c.ctx.Done():
 			return
 		case <-time.After(c.cfg.FrequencyToMonitorTxs.Duration):
-			err := c.monitorTxs(context.Background())
+			err := retry.Do(c.ctx, "ethtxmanager_monitor_txs", retry.Constant(monitorTxsAttempts, failureInterval), nil, func() error {
+				return c.monitorTxs(context.Background())
+			})
 			if err != nil {
-				c.logErrorAndWait("failed to monitor txs: %v", err)
+				log.Errorf("failed to monitor txs: %v", err)
 			}
 		}
 	}
@@ -363,9 +370,11 @@ func (c *Client) monitorTx(ctx context.Context, mTx monitoredTx, logger *log.Log
 			return
 		}
 
-		// review tx and increase gas and gas price if needed
+		// review tx and increase gas and gas price if needed, the fees are bumped when
+		// a sent tx is still pending after waiting for it to be mined
 		if mTx.status == MonitoredTxStatusSent {
-			err := c.reviewMonitoredTx(ctx, &mTx, logger)
+			stuck := !allHistoryTxsWereMined && time.Since(mTx.updatedAt) >= c.cfg.WaitTxToBeMined.Duration
+			err := c.reviewMonitoredTx(ctx, &mTx, stuck, logger)
 			if err != nil {
 				logger.Errorf("failed to review monitored tx: %v", err)
 				return
@@ -524,8 +533,9 @@ func (c *Client) shouldContinueToMonitorThisTx(ctx context.Context, receipt type
 
 // reviewMonitoredTx checks if some field needs to be updated
 // accordingly to the current information stored and the current
-// state of the blockchain
-func (c *Client) reviewMonitoredTx(ctx context.Context, mTx *monitoredTx, mTxLogger *log.Logger) error {
+// state of the blockchain, when the tx is stuck its fees are bumped
+// so it can replace the pending tx with the same nonce
+func (c *Client) reviewMonitoredTx(ctx context.Context, mTx *monitoredTx, stuck bool, mTxLogger *log.Logger) error {
 	mTxLogger.Debug("reviewing")
 	// get gas
 	gas, err := c.etherman.EstimateGas(ctx, mTx.from, mTx.to, mTx.value, mTx.data)
@@ -542,18 +552,40 @@ func (c *Client) reviewMonitoredTx(ctx context.Context, mTx *monitoredTx, mTxLog
 	}
 
 	// get gas price
-	gasPrice, err := c.suggestedGasPrice(ctx)
+	gasPrice, gasTipCap, err := c.suggestedFees(ctx)
 	if err != nil {
 		err := fmt.Errorf("failed to get suggested gas price: %w", err)
 		mTxLogger.Errorf(err.Error())
 		return err
 	}
 
+	// bump the fees of the last sent tx when it's stuck
+	if stuck && c.cfg.FeeBumpPercentage > 0 {
+		mTxLogger.Infof("monitored tx not mined after %v, bumping its fees by %v%%", c.cfg.WaitTxToBeMined.Duration, c.cfg.FeeBumpPercentage)
+		if bumped := c.limitGasPrice(c.bumpFee(mTx.gasPrice)); bumped.Cmp(gasPrice) == 1 {
+			gasPrice = bumped
+		}
+		if mTx.gasTipCap != nil {
+			if bumped := c.bumpFee(mTx.gasTipCap); gasTipCap == nil || bumped.Cmp(gasTipCap) == 1 {
+				gasTipCap = bumped
+			}
+		}
+	}
+
 	// check gas price
 	if gasPrice.Cmp(mTx.gasPrice) == 1 {
 		mTxLogger.Infof("monitored tx gas price updated from %v to %v", mTx.gasPrice.String(), gasPrice.String())
 		mTx.gasPrice = gasPrice
 	}
+
+	// check priority fee, it can't be over the max fee per gas
+	if gasTipCap != nil && (mTx.gasTipCap == nil || gasTipCap.Cmp(mTx.gasTipCap) == 1) {
+		mTxLogger.Infof("monitored tx priority fee updated from %v to %v", mTx.gasTipCap, gasTipCap.String())
+		mTx.gasTipCap = gasTipCap
+	}
+	if mTx.gasTipCap != nil && mTx.gasTipCap.Cmp(mTx.gasPrice) == 1 {
+		mTx.gasTipCap = big.NewInt(0).Set(mTx.gasPrice)
+	}
 	return nil
 }
 
@@ -588,26 +620,67 @@ func (c *Client) suggestedGasPrice(ctx context.Context) (*big.Int, error) {
 	}
 
 	// adjust the gas price by the margin factor
-	marginFactor := big.NewFloat(0).SetFloat64(c.cfg.GasPriceMarginFactor)
-	fGasPrice := big.NewFloat(0).SetInt(gasPrice)
-	adjustedGasPrice, _ := big.NewFloat(0).Mul(fGasPrice, marginFactor).Int(big.NewInt(0))
+	adjustedGasPrice := mulFloat(gasPrice, c.cfg.GasPriceMarginFactor)
+
+	return c.limitGasPrice(adjustedGasPrice), nil
+}
 
+// suggestedFees returns the gas price of the legacy txs, or the max fee per gas and
+// the priority fee per gas when the dynamic fee txs are enabled
+func (c *Client) suggestedFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	if !c.cfg.DynamicFeeTxs {
+		gasPrice, err := c.suggestedGasPrice(ctx)
+		return gasPrice, nil, err
+	}
+
+	baseFee, suggestedGasTipCap, err := c.etherman.SuggestedGasFees(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gasTipCap := big.NewInt(0).SetUint64(c.cfg.PriorityFee)
+	if c.cfg.PriorityFeeStrategy != PriorityFeeStrategyFixed && suggestedGasTipCap.Cmp(gasTipCap) == 1 {
+		gasTipCap = suggestedGasTipCap
+	}
+
+	// leave room for the base fee to raise before the tx is mined
+	multiplier := c.cfg.BaseFeeMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	gasFeeCap := c.limitGasPrice(big.NewInt(0).Add(mulFloat(baseFee, multiplier), gasTipCap))
+	if gasTipCap.Cmp(gasFeeCap) == 1 {
+		gasTipCap = big.NewInt(0).Set(gasFeeCap)
+	}
+
+	return gasFeeCap, gasTipCap, nil
+}
+
+// limitGasPrice returns the gas price limited by the max gas price limit, if configured
+func (c *Client) limitGasPrice(gasPrice *big.Int) *big.Int {
 	// if there is a max gas price limit configured and the current
 	// adjusted gas price is over this limit, set the gas price as the limit
 	if c.cfg.MaxGasPriceLimit > 0 {
 		maxGasPrice := big.NewInt(0).SetUint64(c.cfg.MaxGasPriceLimit)
-		if adjustedGasPrice.Cmp(maxGasPrice) == 1 {
-			adjustedGasPrice.Set(maxGasPrice)
+		if gasPrice.Cmp(maxGasPrice) == 1 {
+			return maxGasPrice
 		}
 	}
+	return gasPrice
+}
 
-	return adjustedGasPrice, nil
+// bumpFee raises the fee by the fee bump percentage, rounding up
+func (c *Client) bumpFee(fee *big.Int) *big.Int {
+	const hundred = 100
+	bumped := big.NewInt(0).Mul(fee, big.NewInt(0).SetUint64(hundred+c.cfg.FeeBumpPercentage))
+	bumped.Add(bumped, big.NewInt(hundred-1))
+	return bumped.Div(bumped, big.NewInt(hundred))
 }
 
-// logErrorAndWait used when an error is detected before trying again
-func (c *Client) logErrorAndWait(msg string, err error) {
-	log.Errorf(msg, err)
-	time.Sleep(failureIntervalInSeconds * time.Second)
+// mulFloat multiplies the value by the factor
+func mulFloat(value *big.Int, factor float64) *big.Int {
+	result, _ := big.NewFloat(0).Mul(big.NewFloat(0).SetInt(value), big.NewFloat(0).SetFloat64(factor)).Int(big.NewInt(0))
+	return result
 }
 
 // ResultHandler used by the caller to handle results
@@ -716,8 +789,12 @@ func CreateLogger(owner, monitoredTxId string, from common.Address, to *common.A
 // CreateMonitoredTxResultLogger creates an instance of logger with all the important
 // fields already set for a MonitoredTxResult
 func CreateMonitoredTxResultLogger(owner string, mTxResult MonitoredTxResult) *log.Logger {
-	return log.WithFields(
+	fields := []interface{}{
 		"owner", owner,
 		"monitoredTxId", mTxResult.ID,
-	)
+	}
+	if revertMessages := mTxResult.RevertMessages(); len(revertMessages) > 0 {
+		fields = append(fields, "revertMessages", revertMessages)
+	}
+	return log.WithFields(fields...)
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/test/dbutils"
 	"github.com/ethereum/go-ethereum"
@@ -982,3 +983,100 @@ func TestFailedToEstimateTxWithForcedGasGetMined(t *testing.T) {
 	require.Equal(t, receipt, result.Txs[signedTx.Hash()].Receipt)
 	require.Equal(t, "", result.Txs[signedTx.Hash()].RevertMessage)
 }
+
+func TestSuggestedDynamicFees(t *testing.T) {
+	testCases := []struct {
+		name              string
+		cfg               Config
+		baseFee           int64
+		suggestedTipCap   int64
+		expectedGasFeeCap int64
+		expectedGasTipCap int64
+	}{
+		{
+			name:              "suggested priority fee",
+			cfg:               Config{PriorityFeeStrategy: PriorityFeeStrategySuggested, PriorityFee: 1, BaseFeeMultiplier: 2},
+			baseFee:           100,
+			suggestedTipCap:   10,
+			expectedGasFeeCap: 210,
+			expectedGasTipCap: 10,
+		},
+		{
+			name:              "suggested priority fee under the min",
+			cfg:               Config{PriorityFeeStrategy: PriorityFeeStrategySuggested, PriorityFee: 20, BaseFeeMultiplier: 2},
+			baseFee:           100,
+			suggestedTipCap:   10,
+			expectedGasFeeCap: 220,
+			expectedGasTipCap: 20,
+		},
+		{
+			name:              "fixed priority fee",
+			cfg:               Config{PriorityFeeStrategy: PriorityFeeStrategyFixed, PriorityFee: 5, BaseFeeMultiplier: 1.5},
+			baseFee:           100,
+			suggestedTipCap:   10,
+			expectedGasFeeCap: 155,
+			expectedGasTipCap: 5,
+		},
+		{
+			name:              "limited max fee",
+			cfg:               Config{PriorityFeeStrategy: PriorityFeeStrategySuggested, BaseFeeMultiplier: 2, MaxGasPriceLimit: 8},
+			baseFee:           100,
+			suggestedTipCap:   10,
+			expectedGasFeeCap: 8,
+			expectedGasTipCap: 8,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			etherman := newEthermanMock(t)
+			tc.cfg.DynamicFeeTxs = true
+			c := New(tc.cfg, etherman, nil, nil)
+
+			ctx := context.Background()
+			etherman.
+				On("SuggestedGasFees", ctx).
+				Return(big.NewInt(tc.baseFee), big.NewInt(tc.suggestedTipCap), nil).
+				Once()
+
+			gasFeeCap, gasTipCap, err := c.suggestedFees(ctx)
+			require.NoError(t, err)
+			require.Equal(t, big.NewInt(tc.expectedGasFeeCap), gasFeeCap)
+			require.Equal(t, big.NewInt(tc.expectedGasTipCap), gasTipCap)
+		})
+	}
+}
+
+func TestReviewMonitoredTxBumpsStuckTxFees(t *testing.T) {
+	etherman := newEthermanMock(t)
+	cfg := Config{DynamicFeeTxs: true, BaseFeeMultiplier: 1, FeeBumpPercentage: 10, MaxGasPriceLimit: 250}
+	c := New(cfg, etherman, nil, nil)
+
+	ctx := context.Background()
+	from := common.HexToAddress("0x1")
+	mTx := monitoredTx{from: from, gas: 21000, gasPrice: big.NewInt(200), gasTipCap: big.NewInt(15)}
+	etherman.
+		On("EstimateGas", ctx, from, mTx.to, mTx.value, mTx.data).
+		Return(uint64(21000), nil)
+	etherman.
+		On("SuggestedGasFees", ctx).
+		Return(big.NewInt(100), big.NewInt(10), nil)
+
+	// the suggested fees are lower than the ones of the sent tx, so they don't change until it's stuck
+	require.NoError(t, c.reviewMonitoredTx(ctx, &mTx, false, log.WithFields()))
+	require.Equal(t, big.NewInt(200), mTx.gasPrice)
+	require.Equal(t, big.NewInt(15), mTx.gasTipCap)
+
+	require.NoError(t, c.reviewMonitoredTx(ctx, &mTx, true, log.WithFields()))
+	require.Equal(t, big.NewInt(220), mTx.gasPrice)
+	require.Equal(t, big.NewInt(17), mTx.gasTipCap)
+
+	require.NoError(t, c.reviewMonitoredTx(ctx, &mTx, true, log.WithFields()))
+	require.Equal(t, big.NewInt(242), mTx.gasPrice)
+	require.Equal(t, big.NewInt(19), mTx.gasTipCap)
+
+	// the bump is limited by the max gas price
+	require.NoError(t, c.reviewMonitoredTx(ctx, &mTx, true, log.WithFields()))
+	require.Equal(t, big.NewInt(250), mTx.gasPrice)
+	require.Equal(t, big.NewInt(21), mTx.gasTipCap)
+}
@@ -224,6 +224,41 @@ func (_m *ethermanMock) SignTx(ctx context.Context, sender common.Address, tx *t
 	return r0, r1
 }
 
+// SuggestedGasFees provides a mock function with given fields: ctx
+func (_m *ethermanMock) SuggestedGasFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *big.Int
+	var r1 *big.Int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*big.Int, *big.Int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *big.Int); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) *big.Int); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // SuggestedGasPrice provides a mock function with given fields: ctx
 func (_m *ethermanMock) SuggestedGasPrice(ctx context.Context) (*big.Int, error) {
 	ret := _m.Called(ctx)
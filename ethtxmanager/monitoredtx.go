@@ -76,9 +76,12 @@ type monitoredTx struct {
 	// tx gas offset
 	gasOffset uint64
 
-	// tx gas price
+	// tx gas price, or max fee per gas of the dynamic fee txs
 	gasPrice *big.Int
 
+	// tx priority fee per gas, only set for the dynamic fee txs
+	gasTipCap *big.Int
+
 	// status of this monitoring
 	status MonitoredTxStatus
 
@@ -99,8 +102,20 @@ type monitoredTx struct {
 	updatedAt time.Time
 }
 
-// Tx uses the current information to build a tx
+// Tx uses the current information to build a tx, a dynamic fee tx is built when it has a priority fee
 func (mTx monitoredTx) Tx() *types.Transaction {
+	if mTx.gasTipCap != nil {
+		return types.NewTx(&types.DynamicFeeTx{
+			To:        mTx.to,
+			Nonce:     mTx.nonce,
+			Value:     mTx.value,
+			Data:      mTx.data,
+			Gas:       mTx.gas + mTx.gasOffset,
+			GasFeeCap: mTx.gasPrice,
+			GasTipCap: mTx.gasTipCap,
+		})
+	}
+
 	tx := types.NewTx(&types.LegacyTx{
 		To:       mTx.to,
 		Nonce:    mTx.nonce,
@@ -170,6 +185,16 @@ func (mTx *monitoredTx) historyHashSlice() []common.Hash {
 	return history
 }
 
+// gasTipCapU64Ptr returns the current gasTipCap field as a uint64 pointer
+func (mTx *monitoredTx) gasTipCapU64Ptr() *uint64 {
+	var gasTipCap *uint64
+	if mTx.gasTipCap != nil {
+		tmp := mTx.gasTipCap.Uint64()
+		gasTipCap = &tmp
+	}
+	return gasTipCap
+}
+
 // blockNumberU64Ptr returns the current blockNumber as a uint64 pointer
 func (mTx *monitoredTx) blockNumberU64Ptr() *uint64 {
 	var blockNumber *uint64
@@ -187,6 +212,17 @@ type MonitoredTxResult struct {
 	Txs    map[common.Hash]TxResult
 }
 
+// RevertMessages returns the decoded revert messages of the reverted txs of the monitored tx
+func (r MonitoredTxResult) RevertMessages() []string {
+	revertMessages := []string{}
+	for _, txResult := range r.Txs {
+		if txResult.RevertMessage != "" {
+			revertMessages = append(revertMessages, txResult.RevertMessage)
+		}
+	}
+	return revertMessages
+}
+
 // TxResult represents the result of a execution of a ethereum transaction in the block chain
 type TxResult struct {
 	Tx            *types.Transaction
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,3 +37,28 @@ func TestTx(t *testing.T) {
 	assert.Equal(t, gas+gasOffset, tx.Gas())
 	assert.Equal(t, gasPrice, tx.GasPrice())
 }
+
+func TestDynamicFeeTx(t *testing.T) {
+	to := common.HexToAddress("0x2")
+	gasFeeCap := big.NewInt(5)
+	gasTipCap := big.NewInt(2)
+
+	mTx := monitoredTx{
+		to:        &to,
+		nonce:     1,
+		value:     big.NewInt(2),
+		data:      []byte("data"),
+		gas:       3,
+		gasOffset: 4,
+		gasPrice:  gasFeeCap,
+		gasTipCap: gasTipCap,
+	}
+
+	tx := mTx.Tx()
+
+	assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+	assert.Equal(t, &to, tx.To())
+	assert.Equal(t, uint64(7), tx.Gas())
+	assert.Equal(t, gasFeeCap, tx.GasFeeCap())
+	assert.Equal(t, gasTipCap, tx.GasTipCap())
+}
@@ -1,9 +1,57 @@
 package event
 
-import "github.com/0xPolygonHermez/zkevm-node/db"
+import (
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+)
+
+const (
+	// NotifierTypeWebhook posts the events as JSON objects
+	NotifierTypeWebhook = "webhook"
+	// NotifierTypeSlack posts the events as Slack incoming webhook messages
+	NotifierTypeSlack = "slack"
+	// NotifierTypePagerDuty posts the events as PagerDuty Events API v2 alerts
+	NotifierTypePagerDuty = "pagerduty"
+)
 
 // Config for event
 type Config struct {
 	// DB is the database configuration
 	DB db.Config `mapstructure:"DB"`
+
+	// Notifiers are the sinks the events are posted to besides being stored, so the operators
+	// are notified of halts, divergences or L1 tx failures without an alerting pipeline
+	Notifiers []NotifierConfig `mapstructure:"Notifiers"`
+}
+
+// NotifierConfig is the configuration of a notification sink
+type NotifierConfig struct {
+	// Name identifies the notifier in the logs
+	Name string `mapstructure:"Name"`
+
+	// Type is the format of the posted payload: "webhook", "slack" or "pagerduty"
+	Type string `mapstructure:"Type"`
+
+	// URL is the endpoint the events are posted to
+	URL string `mapstructure:"URL"`
+
+	// RoutingKey is the integration key of the PagerDuty service, only used by the pagerduty notifier
+	RoutingKey string `mapstructure:"RoutingKey"`
+
+	// MinLevel is the least severe level notified, e.g. "err" notifies the err, crit, alert and emerg events.
+	// All the levels are notified if it's empty
+	MinLevel Level `mapstructure:"MinLevel"`
+
+	// Components are the components whose events are notified, all of them if it's empty
+	Components []Component `mapstructure:"Components"`
+
+	// DedupWindow is the time an event with the same component, id and description is not notified again, 0 disables the dedup
+	DedupWindow types.Duration `mapstructure:"DedupWindow"`
+
+	// Timeout is the timeout of each post, 0 disables it
+	Timeout types.Duration `mapstructure:"Timeout"`
+
+	// Retry is the retry policy of the failed posts
+	Retry retry.Config `mapstructure:"Retry"`
 }
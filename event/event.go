@@ -32,14 +32,38 @@ const (
 	EventID_ExecutorRLPError EventID = "EXECUTOR RLP ERROR"
 	// EventID_FinalizerHalt is triggered when the finalizer halts
 	EventID_FinalizerHalt EventID = "FINALIZER HALT"
+	// EventID_FinalizerResume is triggered when the finalizer is resumed after a halt
+	EventID_FinalizerResume EventID = "FINALIZER RESUME"
 	// EventID_FinalizerRestart is triggered when the finalizer restarts
 	EventID_FinalizerRestart EventID = "FINALIZER RESTART"
 	// EventID_FinalizerBreakEvenGasPriceBigDifference is triggered when the finalizer recalculates the break even gas price and detects a big difference
 	EventID_FinalizerBreakEvenGasPriceBigDifference EventID = "FINALIZER BREAK EVEN GAS PRICE BIG DIFFERENCE"
+	// EventID_ProverIncompatible is triggered when a prover is rejected because it's not compatible with the aggregator
+	EventID_ProverIncompatible EventID = "PROVER INCOMPATIBLE"
+	// EventID_ExecutorIncompatible is triggered when the executor is not compatible with the node
+	EventID_ExecutorIncompatible EventID = "EXECUTOR INCOMPATIBLE"
+	// EventID_L1TxReverted is triggered when an L1 tx sent by the node is reverted
+	EventID_L1TxReverted EventID = "L1 TX REVERTED"
 	// EventID_SynchronizerRestart is triggered when the Synchonizer restarts
 	EventID_SynchronizerRestart EventID = "SYNCHRONIZER RESTART"
 	// EventID_SynchronizerHalt is triggered when the synchronizer halts
 	EventID_SynchronizerHalt EventID = "SYNCHRONIZER HALT"
+	// EventID_L2Reorg is triggered when the sequencer handles an L2 reorg, the description contains the reorged tx hashes
+	EventID_L2Reorg EventID = "L2 REORG"
+	// EventID_FinalizerOOCAtBatchStart is triggered when the first tx of an empty batch is out of counters and it's quarantined,
+	// the description contains the estimated, used and constraint counters of the tx
+	EventID_FinalizerOOCAtBatchStart EventID = "FINALIZER OOC AT BATCH START"
+	// EventID_BatchDataIntegrityMismatch is triggered when the stored data of a sequence doesn't match the
+	// accumulated input hash stored in L1 for it
+	EventID_BatchDataIntegrityMismatch EventID = "BATCH DATA INTEGRITY MISMATCH"
+	// EventID_L1Reorg is triggered when the synchronizer detects an L1 reorg and rolls back the state,
+	// the description contains the last valid L1 block and the first batch that lost its virtual status
+	EventID_L1Reorg EventID = "L1 REORG"
+	// EventID_ClockSkew is triggered when the local clock drifts from the NTP servers more than the allowed skew
+	EventID_ClockSkew EventID = "CLOCK SKEW"
+	// EventID_AccInputHashMismatch is triggered when the accumulated input hash computed locally for a sequence
+	// doesn't match the one stored in L1, the description contains both hashes
+	EventID_AccInputHashMismatch EventID = "ACC INPUT HASH MISMATCH"
 	// Source_Node is the source of the event
 	Source_Node Source = "node"
 
@@ -63,6 +87,8 @@ const (
 	Component_Broadcast Component = "broadcast"
 	// Component_Sequence_Sender is the component that triggered the event
 	Component_Sequence_Sender = "seqsender"
+	// Component_Clock is the component that triggered the event
+	Component_Clock Component = "clock"
 
 	// Level_Emergency is the most severe level
 	Level_Emergency Level = "emerg"
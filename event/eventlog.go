@@ -11,23 +11,38 @@ import (
 
 // EventLog is the main struct for the event log
 type EventLog struct {
-	cfg     Config
-	storage Storage
+	cfg       Config
+	storage   Storage
+	notifiers []*notifier
 }
 
 // NewEventLog creates and initializes an instance of EventLog
 func NewEventLog(cfg Config, storage Storage) *EventLog {
 	return &EventLog{
-		cfg:     cfg,
-		storage: storage,
+		cfg:       cfg,
+		storage:   storage,
+		notifiers: newNotifiers(cfg.Notifiers),
 	}
 }
 
-// LogEvent is used to store an event for runtime debugging
+// LogEvent is used to store an event for runtime debugging, it's posted
+// in background to the notifiers whose configuration it matches
 func (e *EventLog) LogEvent(ctx context.Context, event *Event) error {
+	e.notify(event)
 	return e.storage.LogEvent(ctx, event)
 }
 
+// notify posts the event to the notifiers without blocking the caller
+func (e *EventLog) notify(event *Event) {
+	for _, n := range e.notifiers {
+		go func(n *notifier) {
+			if err := n.notify(context.Background(), event); err != nil {
+				log.Errorf("error notifying event %s: %v", event.EventID, err)
+			}
+		}(n)
+	}
+}
+
 // LogExecutorError is used to store Executor error for runtime debugging
 func (e *EventLog) LogExecutorError(ctx context.Context, responseError executor.ExecutorError, processBatchRequest *executor.ProcessBatchRequest) {
 	timestamp := time.Now()
@@ -45,7 +60,7 @@ func (e *EventLog) LogExecutorError(ctx context.Context, responseError executor.
 			Description: responseError.String(),
 			Json:        string(payload),
 		}
-		err = e.storage.LogEvent(ctx, event)
+		err = e.LogEvent(ctx, event)
 		if err != nil {
 			log.Errorf("error storing event: %v", err)
 		}
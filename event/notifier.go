@@ -0,0 +1,209 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+)
+
+// levelSeverity sorts the levels from the most severe
+var levelSeverity = map[Level]int{
+	Level_Emergency: 0,
+	Level_Alert:     1,
+	Level_Critical:  2,
+	Level_Error:     3,
+	Level_Warning:   4,
+	Level_Notice:    5,
+	Level_Info:      6,
+	Level_Debug:     7,
+}
+
+// notification is the payload posted by the webhook notifiers
+type notification struct {
+	ReceivedAt  time.Time `json:"receivedAt"`
+	IPAddress   string    `json:"ipAddress,omitempty"`
+	Source      Source    `json:"source"`
+	Component   Component `json:"component"`
+	Level       Level     `json:"level"`
+	EventID     EventID   `json:"eventId"`
+	Description string    `json:"description"`
+}
+
+// notifier posts the events matching its configuration to an external endpoint
+type notifier struct {
+	cfg    NotifierConfig
+	client *http.Client
+
+	// notifiedAt is the last time each event was notified, to deduplicate them
+	notifiedAt    map[string]time.Time
+	notifiedAtMux sync.Mutex
+}
+
+// newNotifier creates a notifier checking its configuration
+func newNotifier(cfg NotifierConfig) (*notifier, error) {
+	switch cfg.Type {
+	case NotifierTypeWebhook, NotifierTypeSlack:
+	case NotifierTypePagerDuty:
+		if cfg.RoutingKey == "" {
+			return nil, fmt.Errorf("notifier %s: the routing key is required by the pagerduty notifiers", cfg.Name)
+		}
+	default:
+		return nil, fmt.Errorf("notifier %s: unknown type %q", cfg.Name, cfg.Type)
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("notifier %s: the URL is required", cfg.Name)
+	}
+	if _, ok := levelSeverity[cfg.MinLevel]; cfg.MinLevel != "" && !ok {
+		return nil, fmt.Errorf("notifier %s: unknown level %q", cfg.Name, cfg.MinLevel)
+	}
+
+	return &notifier{
+		cfg:        cfg,
+		client:     &http.Client{Timeout: cfg.Timeout.Duration},
+		notifiedAt: make(map[string]time.Time),
+	}, nil
+}
+
+// matches returns if the event has to be notified according to its level and component
+func (n *notifier) matches(event *Event) bool {
+	if n.cfg.MinLevel != "" {
+		severity, ok := levelSeverity[event.Level]
+		if !ok || severity > levelSeverity[n.cfg.MinLevel] {
+			return false
+		}
+	}
+	if len(n.cfg.Components) == 0 {
+		return true
+	}
+	for _, component := range n.cfg.Components {
+		if component == event.Component {
+			return true
+		}
+	}
+	return false
+}
+
+// isDuplicated returns if the same event has been notified during the dedup window,
+// otherwise it records the event as notified
+func (n *notifier) isDuplicated(event *Event) bool {
+	if n.cfg.DedupWindow.Duration <= 0 {
+		return false
+	}
+	key := fmt.Sprintf("%s/%s/%s", event.Component, event.EventID, event.Description)
+	now := time.Now()
+
+	n.notifiedAtMux.Lock()
+	defer n.notifiedAtMux.Unlock()
+	for k, notifiedAt := range n.notifiedAt {
+		if now.Sub(notifiedAt) >= n.cfg.DedupWindow.Duration {
+			delete(n.notifiedAt, k)
+		}
+	}
+	if _, found := n.notifiedAt[key]; found {
+		return true
+	}
+	n.notifiedAt[key] = now
+	return false
+}
+
+// payload builds the body posted for the event in the format of the notifier type
+func (n *notifier) payload(event *Event) ([]byte, error) {
+	summary := fmt.Sprintf("[%s] %s %s: %s", event.Level, event.Component, event.EventID, event.Description)
+	switch n.cfg.Type {
+	case NotifierTypeSlack:
+		return json.Marshal(map[string]string{"text": summary})
+	case NotifierTypePagerDuty:
+		return json.Marshal(map[string]interface{}{
+			"routing_key":  n.cfg.RoutingKey,
+			"event_action": "trigger",
+			"dedup_key":    fmt.Sprintf("%s/%s", event.Component, event.EventID),
+			"payload": map[string]interface{}{
+				"summary":        summary,
+				"source":         string(event.Source),
+				"severity":       pagerDutySeverity(event.Level),
+				"component":      string(event.Component),
+				"timestamp":      event.ReceivedAt.UTC().Format(time.RFC3339),
+				"custom_details": map[string]string{"eventId": string(event.EventID), "description": event.Description},
+			},
+		})
+	default:
+		return json.Marshal(notification{
+			ReceivedAt:  event.ReceivedAt,
+			IPAddress:   event.IPAddress,
+			Source:      event.Source,
+			Component:   event.Component,
+			Level:       event.Level,
+			EventID:     event.EventID,
+			Description: event.Description,
+		})
+	}
+}
+
+// pagerDutySeverity maps the level of an event to the severities supported by PagerDuty
+func pagerDutySeverity(level Level) string {
+	switch level {
+	case Level_Emergency, Level_Alert, Level_Critical:
+		return "critical"
+	case Level_Error:
+		return "error"
+	case Level_Warning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// notify posts the event if it matches the notifier configuration and it's not duplicated,
+// retrying the failed posts following the retry policy
+func (n *notifier) notify(ctx context.Context, event *Event) error {
+	if !n.matches(event) || n.isDuplicated(event) {
+		return nil
+	}
+	body, err := n.payload(event)
+	if err != nil {
+		return err
+	}
+
+	return retry.Do(ctx, "event notifier "+n.cfg.Name, n.cfg.Retry, nil, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		res, err := n.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close() //nolint:errcheck
+		if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+			return nil
+		}
+		err = fmt.Errorf("notifier %s: unexpected status code %d", n.cfg.Name, res.StatusCode)
+		// the request is not retried when it's rejected, unless it's rate limited
+		if res.StatusCode >= http.StatusBadRequest && res.StatusCode < http.StatusInternalServerError && res.StatusCode != http.StatusTooManyRequests {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+}
+
+// newNotifiers creates the notifiers of the configuration, the invalid ones are logged and skipped
+func newNotifiers(cfgs []NotifierConfig) []*notifier {
+	notifiers := make([]*notifier, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		n, err := newNotifier(cfg)
+		if err != nil {
+			log.Errorf("error creating event notifier: %v", err)
+			continue
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers
+}
@@ -0,0 +1,143 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notifierTestServer struct {
+	*httptest.Server
+	mux      sync.Mutex
+	bodies   [][]byte
+	statuses []int
+}
+
+func newNotifierTestServer(t *testing.T, statuses ...int) *notifierTestServer {
+	s := &notifierTestServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		s.mux.Lock()
+		defer s.mux.Unlock()
+		s.bodies = append(s.bodies, body)
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *notifierTestServer) requests() [][]byte {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.bodies
+}
+
+func newTestEvent(level Level, component Component) *Event {
+	return &Event{
+		ReceivedAt:  time.Now(),
+		Source:      Source_Node,
+		Component:   component,
+		Level:       level,
+		EventID:     EventID_SynchronizerHalt,
+		Description: "synchronizer halted",
+	}
+}
+
+func TestNotifierFiltersEvents(t *testing.T) {
+	server := newNotifierTestServer(t)
+	n, err := newNotifier(NotifierConfig{
+		Name:       "test",
+		Type:       NotifierTypeWebhook,
+		URL:        server.URL,
+		MinLevel:   Level_Error,
+		Components: []Component{Component_Synchronizer},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, n.notify(ctx, newTestEvent(Level_Warning, Component_Synchronizer)))
+	require.NoError(t, n.notify(ctx, newTestEvent(Level_Critical, Component_Sequencer)))
+	assert.Len(t, server.requests(), 0)
+
+	require.NoError(t, n.notify(ctx, newTestEvent(Level_Critical, Component_Synchronizer)))
+	require.Len(t, server.requests(), 1)
+
+	var posted notification
+	require.NoError(t, json.Unmarshal(server.requests()[0], &posted))
+	assert.Equal(t, Level_Critical, posted.Level)
+	assert.Equal(t, Component_Synchronizer, posted.Component)
+	assert.Equal(t, EventID_SynchronizerHalt, posted.EventID)
+}
+
+func TestNotifierDeduplicatesEvents(t *testing.T) {
+	server := newNotifierTestServer(t)
+	n, err := newNotifier(NotifierConfig{
+		Name:        "test",
+		Type:        NotifierTypeSlack,
+		URL:         server.URL,
+		DedupWindow: types.NewDuration(time.Hour),
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, n.notify(ctx, newTestEvent(Level_Critical, Component_Synchronizer)))
+	require.NoError(t, n.notify(ctx, newTestEvent(Level_Critical, Component_Synchronizer)))
+	require.Len(t, server.requests(), 1)
+
+	var posted map[string]string
+	require.NoError(t, json.Unmarshal(server.requests()[0], &posted))
+	assert.Contains(t, posted["text"], string(EventID_SynchronizerHalt))
+}
+
+func TestNotifierRetriesFailedPosts(t *testing.T) {
+	server := newNotifierTestServer(t, http.StatusInternalServerError, http.StatusOK)
+	n, err := newNotifier(NotifierConfig{
+		Name:       "test",
+		Type:       NotifierTypePagerDuty,
+		URL:        server.URL,
+		RoutingKey: "key",
+		Retry:      retry.Constant(3, time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, n.notify(context.Background(), newTestEvent(Level_Critical, Component_Synchronizer)))
+	require.Len(t, server.requests(), 2)
+
+	var posted map[string]interface{}
+	require.NoError(t, json.Unmarshal(server.requests()[1], &posted))
+	assert.Equal(t, "key", posted["routing_key"])
+	assert.Equal(t, "critical", posted["payload"].(map[string]interface{})["severity"])
+
+	// the rejected posts are not retried
+	server.mux.Lock()
+	server.statuses = []int{http.StatusBadRequest}
+	server.mux.Unlock()
+	require.Error(t, n.notify(context.Background(), newTestEvent(Level_Critical, Component_Synchronizer)))
+	assert.Len(t, server.requests(), 3)
+}
+
+func TestNewNotifierChecksConfig(t *testing.T) {
+	_, err := newNotifier(NotifierConfig{Name: "test", Type: "email", URL: "http://localhost"})
+	assert.Error(t, err)
+	_, err = newNotifier(NotifierConfig{Name: "test", Type: NotifierTypePagerDuty, URL: "http://localhost"})
+	assert.Error(t, err)
+	_, err = newNotifier(NotifierConfig{Name: "test", Type: NotifierTypeWebhook})
+	assert.Error(t, err)
+	_, err = newNotifier(NotifierConfig{Name: "test", Type: NotifierTypeWebhook, URL: "http://localhost", MinLevel: "fatal"})
+	assert.Error(t, err)
+}
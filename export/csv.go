@@ -0,0 +1,57 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// csvWriter writes each table to a <table>.csv file in the output directory
+type csvWriter struct {
+	files   map[table]*os.File
+	writers map[table]*csv.Writer
+}
+
+func newCSVWriter(outputDir string) (*csvWriter, error) {
+	if err := os.MkdirAll(outputDir, 0750); err != nil { //nolint:gomnd
+		return nil, err
+	}
+
+	w := &csvWriter{
+		files:   make(map[table]*os.File, len(tables)),
+		writers: make(map[table]*csv.Writer, len(tables)),
+	}
+	for _, t := range tables {
+		f, err := os.Create(filepath.Join(outputDir, fmt.Sprintf("%s.csv", t)))
+		if err != nil {
+			_ = w.close()
+			return nil, err
+		}
+		w.files[t] = f
+		w.writers[t] = csv.NewWriter(f)
+		if err := w.writers[t].Write(schema[t]); err != nil {
+			_ = w.close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *csvWriter) write(t table, record []string) error {
+	return w.writers[t].Write(record)
+}
+
+func (w *csvWriter) close() error {
+	var firstErr error
+	for t, f := range w.files {
+		w.writers[t].Flush()
+		if err := w.writers[t].Error(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,219 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Format is the file format of the exported data
+type Format string
+
+const (
+	// FormatCSV exports each table to a CSV file with a header row
+	FormatCSV Format = "csv"
+	// FormatParquet exports each table to a Parquet file
+	FormatParquet Format = "parquet"
+)
+
+var (
+	// ErrUnsupportedFormat is returned when the requested export format is not supported
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+	// ErrInvalidRange is returned when the start of the range to export is after its end
+	ErrInvalidRange = errors.New("invalid range, the start must not be greater than the end")
+)
+
+// table is one of the exported datasets, each table is written to its own file
+type table string
+
+const (
+	blocksTable       table = "blocks"
+	transactionsTable table = "transactions"
+	receiptsTable     table = "receipts"
+	logsTable         table = "logs"
+)
+
+// tables is the list of exported tables in the order they are written
+var tables = []table{blocksTable, transactionsTable, receiptsTable, logsTable}
+
+// schema contains the columns of each exported table, this is the public contract
+// for the consumers of the exported data, so columns must only be appended
+var schema = map[table][]string{
+	blocksTable:       {"block_number", "block_hash", "parent_hash", "state_root", "timestamp", "gas_limit", "gas_used", "coinbase", "tx_count"},
+	transactionsTable: {"block_number", "block_hash", "tx_index", "tx_hash", "from", "to", "nonce", "value", "gas", "gas_price", "type", "input"},
+	receiptsTable:     {"block_number", "tx_hash", "tx_index", "status", "gas_used", "cumulative_gas_used", "effective_gas_price", "contract_address"},
+	logsTable:         {"block_number", "tx_hash", "tx_index", "log_index", "address", "topic0", "topic1", "topic2", "topic3", "data"},
+}
+
+// Exporter dumps the blocks, transactions, receipts and logs stored in the state
+// into files with a stable schema to be consumed by analytics tools
+type Exporter struct {
+	state stateInterface
+}
+
+// NewExporter creates a new Exporter
+func NewExporter(state stateInterface) *Exporter {
+	return &Exporter{state: state}
+}
+
+// ExportBlocks exports the L2 blocks in the inclusive range [fromBlock, toBlock] into the output directory
+func (e *Exporter) ExportBlocks(ctx context.Context, fromBlock, toBlock uint64, format Format, outputDir string) error {
+	if fromBlock > toBlock {
+		return ErrInvalidRange
+	}
+	return e.export(ctx, format, outputDir, func(w recordWriter) error {
+		for blockNumber := fromBlock; blockNumber <= toBlock; blockNumber++ {
+			block, err := e.state.GetL2BlockByNumber(ctx, blockNumber, nil)
+			if err != nil {
+				return fmt.Errorf("failed to get L2 block %d: %w", blockNumber, err)
+			}
+			if err := e.writeBlock(ctx, w, block); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ExportBatches exports the L2 blocks of the batches in the inclusive range [fromBatch, toBatch] into the output directory
+func (e *Exporter) ExportBatches(ctx context.Context, fromBatch, toBatch uint64, format Format, outputDir string) error {
+	if fromBatch > toBatch {
+		return ErrInvalidRange
+	}
+	return e.export(ctx, format, outputDir, func(w recordWriter) error {
+		for batchNumber := fromBatch; batchNumber <= toBatch; batchNumber++ {
+			blocks, err := e.state.GetL2BlocksByBatchNumber(ctx, batchNumber, nil)
+			if errors.Is(err, state.ErrNotFound) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("failed to get the L2 blocks of batch %d: %w", batchNumber, err)
+			}
+			for i := range blocks {
+				if err := e.writeBlock(ctx, w, &blocks[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (e *Exporter) export(ctx context.Context, format Format, outputDir string, writeRecords func(w recordWriter) error) error {
+	var (
+		w   recordWriter
+		err error
+	)
+	switch format {
+	case FormatCSV:
+		w, err = newCSVWriter(outputDir)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = writeRecords(w)
+	if closeErr := w.close(); closeErr != nil {
+		if err == nil {
+			return closeErr
+		}
+		log.Errorf("failed to close the export files: %v", closeErr)
+	}
+	return err
+}
+
+func (e *Exporter) writeBlock(ctx context.Context, w recordWriter, block *types.Block) error {
+	blockNumber := block.Number().String()
+	err := w.write(blocksTable, []string{
+		blockNumber,
+		block.Hash().String(),
+		block.ParentHash().String(),
+		block.Root().String(),
+		strconv.FormatUint(block.Time(), 10),
+		strconv.FormatUint(block.GasLimit(), 10),
+		strconv.FormatUint(block.GasUsed(), 10),
+		block.Coinbase().String(),
+		strconv.Itoa(len(block.Transactions())),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range block.Transactions() {
+		receipt, err := e.state.GetTransactionReceipt(ctx, tx.Hash(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to get the receipt of tx %s: %w", tx.Hash().String(), err)
+		}
+		txIndex := strconv.FormatUint(uint64(receipt.TransactionIndex), 10)
+
+		from, err := state.GetSender(*tx)
+		if err != nil {
+			return fmt.Errorf("failed to get the sender of tx %s: %w", tx.Hash().String(), err)
+		}
+		to := ""
+		if tx.To() != nil {
+			to = tx.To().String()
+		}
+		err = w.write(transactionsTable, []string{
+			blockNumber,
+			block.Hash().String(),
+			txIndex,
+			tx.Hash().String(),
+			from.String(),
+			to,
+			strconv.FormatUint(tx.Nonce(), 10),
+			tx.Value().String(),
+			strconv.FormatUint(tx.Gas(), 10),
+			tx.GasPrice().String(),
+			strconv.FormatUint(uint64(tx.Type()), 10),
+			hex.EncodeToHex(tx.Data()),
+		})
+		if err != nil {
+			return err
+		}
+
+		effectiveGasPrice := ""
+		if receipt.EffectiveGasPrice != nil {
+			effectiveGasPrice = receipt.EffectiveGasPrice.String()
+		}
+		contractAddress := ""
+		if tx.To() == nil {
+			contractAddress = receipt.ContractAddress.String()
+		}
+		err = w.write(receiptsTable, []string{
+			blockNumber,
+			tx.Hash().String(),
+			txIndex,
+			strconv.FormatUint(receipt.Status, 10),
+			strconv.FormatUint(receipt.GasUsed, 10),
+			strconv.FormatUint(receipt.CumulativeGasUsed, 10),
+			effectiveGasPrice,
+			contractAddress,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, l := range receipt.Logs {
+			topics := make([]string, 4) //nolint:gomnd
+			for i := 0; i < len(l.Topics) && i < len(topics); i++ {
+				topics[i] = l.Topics[i].String()
+			}
+			record := []string{blockNumber, tx.Hash().String(), txIndex, strconv.FormatUint(uint64(l.Index), 10), l.Address.String()}
+			record = append(record, topics...)
+			record = append(record, hex.EncodeToHex(l.Data))
+			if err := w.write(logsTable, record); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readCSV(t *testing.T, outputDir string, tbl table) [][]string {
+	f, err := os.Open(filepath.Join(outputDir, string(tbl)+".csv"))
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestExportBatchesToCSV(t *testing.T) {
+	ctx := context.Background()
+	st := newStateMock(t)
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+	to := common.HexToAddress("0x2")
+	tx, err := types.SignTx(types.NewTransaction(3, to, big.NewInt(10), 21000, big.NewInt(5), []byte{0x1}), types.NewEIP155Signer(big.NewInt(1000)), privateKey)
+	require.NoError(t, err)
+
+	header := &types.Header{Number: big.NewInt(7), GasLimit: 30000, GasUsed: 21000, Time: 100, Root: common.HexToHash("0xaa")}
+	block := types.NewBlock(header, []*types.Transaction{tx}, nil, nil, &trie.StackTrie{})
+	topic := common.HexToHash("0xbb")
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		GasUsed:           21000,
+		CumulativeGasUsed: 21000,
+		EffectiveGasPrice: big.NewInt(5),
+		Logs:              []*types.Log{{Address: to, Topics: []common.Hash{topic}, Data: []byte{0x2}, Index: 0}},
+	}
+
+	st.On("GetL2BlocksByBatchNumber", ctx, uint64(1), nil).Return([]types.Block{*block}, nil).Once()
+	st.On("GetL2BlocksByBatchNumber", ctx, uint64(2), nil).Return(nil, state.ErrNotFound).Once()
+	st.On("GetTransactionReceipt", ctx, tx.Hash(), nil).Return(receipt, nil).Once()
+
+	outputDir := t.TempDir()
+	err = NewExporter(st).ExportBatches(ctx, 1, 2, FormatCSV, outputDir)
+	require.NoError(t, err)
+
+	blocks := readCSV(t, outputDir, blocksTable)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, schema[blocksTable], blocks[0])
+	assert.Equal(t, []string{"7", block.Hash().String(), common.Hash{}.String(), header.Root.String(), "100", "30000", "21000", common.Address{}.String(), "1"}, blocks[1])
+
+	txs := readCSV(t, outputDir, transactionsTable)
+	require.Len(t, txs, 2)
+	assert.Equal(t, schema[transactionsTable], txs[0])
+	assert.Equal(t, []string{"7", block.Hash().String(), "0", tx.Hash().String(), sender.String(), to.String(), "3", "10", "21000", "5", "0", "0x01"}, txs[1])
+
+	receipts := readCSV(t, outputDir, receiptsTable)
+	require.Len(t, receipts, 2)
+	assert.Equal(t, schema[receiptsTable], receipts[0])
+	assert.Equal(t, []string{"7", tx.Hash().String(), "0", "1", "21000", "21000", "5", ""}, receipts[1])
+
+	logs := readCSV(t, outputDir, logsTable)
+	require.Len(t, logs, 2)
+	assert.Equal(t, schema[logsTable], logs[0])
+	assert.Equal(t, []string{"7", tx.Hash().String(), "0", "0", to.String(), topic.String(), "", "", "", "0x02"}, logs[1])
+}
+
+func TestExportErrors(t *testing.T) {
+	exporter := NewExporter(newStateMock(t))
+
+	err := exporter.ExportBlocks(context.Background(), 2, 1, FormatCSV, t.TempDir())
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	err = exporter.ExportBlocks(context.Background(), 1, 2, FormatParquet, t.TempDir())
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
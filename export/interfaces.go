@@ -0,0 +1,22 @@
+package export
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// stateInterface gathers the methods required to read the exported data from the state.
+type stateInterface interface {
+	GetL2BlockByNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (*types.Block, error)
+	GetL2BlocksByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]types.Block, error)
+	GetTransactionReceipt(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Receipt, error)
+}
+
+// recordWriter writes the records of the exported tables in a specific file format.
+type recordWriter interface {
+	write(table table, record []string) error
+	close() error
+}
@@ -22,9 +22,10 @@ const (
 type Config struct {
 	Type EstimatorType `mapstructure:"Type"`
 
-	// DefaultGasPriceWei is used to set the gas price to be used by the default gas pricer or as minimim gas price by the follower gas pricer.
+	// DefaultGasPriceWei is used to set the gas price to be used by the default gas pricer, as minimim gas price by the follower gas pricer
+	// or as initial gas price by the lastnbatches gas pricer.
 	DefaultGasPriceWei uint64 `mapstructure:"DefaultGasPriceWei"`
-	// MaxGasPriceWei is used to limit the gas price returned by the follower gas pricer to a maximum value. It is ignored if 0.
+	// MaxGasPriceWei is used to limit the gas price returned by the follower and lastnbatches gas pricers to a maximum value. It is ignored if 0.
 	MaxGasPriceWei            uint64         `mapstructure:"MaxGasPriceWei"`
 	MaxPrice                  *big.Int       `mapstructure:"MaxPrice"`
 	IgnorePrice               *big.Int       `mapstructure:"IgnorePrice"`
@@ -29,18 +29,21 @@ type LastNL2BlocksGasPrice struct {
 // newLastNL2BlocksGasPriceSuggester init gas price suggester for last n l2 blocks strategy.
 func newLastNL2BlocksGasPriceSuggester(ctx context.Context, cfg Config, state stateInterface, pool poolInterface) *LastNL2BlocksGasPrice {
 	return &LastNL2BlocksGasPrice{
-		cfg:   cfg,
-		ctx:   ctx,
-		state: state,
-		pool:  pool,
+		cfg:       cfg,
+		ctx:       ctx,
+		state:     state,
+		pool:      pool,
+		lastPrice: new(big.Int).SetUint64(cfg.DefaultGasPriceWei),
 	}
 }
 
-// UpdateGasPriceAvg for last n bathes strategy is not needed to implement this function.
+// UpdateGasPriceAvg sets the gas price to the configured percentile of the tips paid in the
+// last CheckBlocks l2 blocks, keeping the last price while no new blocks are found.
 func (g *LastNL2BlocksGasPrice) UpdateGasPriceAvg() {
 	l2BlockNumber, err := g.state.GetLastL2BlockNumber(g.ctx, nil)
 	if err != nil {
 		log.Errorf("failed to get last l2 block number, err: %v", err)
+		return
 	}
 	g.cacheLock.RLock()
 	lastL2BlockNumber, lastPrice := g.lastL2BlockNumber, g.lastPrice
@@ -55,7 +58,7 @@ func (g *LastNL2BlocksGasPrice) UpdateGasPriceAvg() {
 
 	var (
 		sent, exp int
-		number    = lastL2BlockNumber
+		number    = l2BlockNumber
 		result    = make(chan results, g.cfg.CheckBlocks)
 		quit      = make(chan struct{})
 		results   []*big.Int
@@ -71,6 +74,7 @@ func (g *LastNL2BlocksGasPrice) UpdateGasPriceAvg() {
 	for exp > 0 {
 		res := <-result
 		if res.err != nil {
+			log.Errorf("failed to get the txs of the last l2 blocks, err: %v", res.err)
 			close(quit)
 			return
 		}
@@ -87,9 +91,12 @@ func (g *LastNL2BlocksGasPrice) UpdateGasPriceAvg() {
 		sort.Sort(bigIntArray(results))
 		price = results[(len(results)-1)*g.cfg.Percentile/100]
 	}
-	if price.Cmp(g.cfg.MaxPrice) > 0 {
+	if g.cfg.MaxPrice != nil && price.Cmp(g.cfg.MaxPrice) > 0 {
 		price = g.cfg.MaxPrice
 	}
+	if g.cfg.MaxGasPriceWei > 0 && price.Cmp(new(big.Int).SetUint64(g.cfg.MaxGasPriceWei)) > 0 {
+		price = new(big.Int).SetUint64(g.cfg.MaxGasPriceWei)
+	}
 
 	g.cacheLock.Lock()
 	g.lastPrice = price
@@ -110,7 +117,7 @@ func (g *LastNL2BlocksGasPrice) UpdateGasPriceAvg() {
 // getL2BlockTxsTips calculates l2 block transaction gas fees.
 func (g *LastNL2BlocksGasPrice) getL2BlockTxsTips(ctx context.Context, l2BlockNumber uint64, limit int, ignorePrice *big.Int, result chan results, quit chan struct{}) {
 	txs, err := g.state.GetTxsByBlockNumber(ctx, l2BlockNumber, nil)
-	if txs == nil {
+	if err != nil {
 		select {
 		case result <- results{nil, err}:
 		case <-quit:
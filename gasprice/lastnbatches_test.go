@@ -0,0 +1,64 @@
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newTestTx(gasPrice int64) *types.Transaction {
+	return types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(gasPrice), nil)
+}
+
+func TestUpdateGasPriceLastNBatches(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{
+		Type:               LastNBatchesType,
+		DefaultGasPriceWei: 1000,
+		CheckBlocks:        3,
+		Percentile:         50,
+		Factor:             0.5,
+	}
+	stateM := newStateMock(t)
+	poolM := newPoolMock(t)
+	g := newLastNL2BlocksGasPriceSuggester(ctx, cfg, stateM, poolM)
+
+	// the block without txs is sampled with the default gas price
+	stateM.On("GetLastL2BlockNumber", ctx, nil).Return(uint64(3), nil).Twice()
+	stateM.On("GetTxsByBlockNumber", ctx, uint64(3), nil).Return([]*types.Transaction{newTestTx(200), newTestTx(100)}, nil).Once()
+	stateM.On("GetTxsByBlockNumber", ctx, uint64(2), nil).Return([]*types.Transaction{newTestTx(300)}, nil).Once()
+	stateM.On("GetTxsByBlockNumber", ctx, uint64(1), nil).Return([]*types.Transaction{}, nil).Once()
+	poolM.On("SetGasPrices", ctx, uint64(200), uint64(400)).Return(nil).Once()
+	g.UpdateGasPriceAvg()
+
+	// the gas price isn't updated while there are no new blocks
+	g.UpdateGasPriceAvg()
+}
+
+func TestLimitMaxGasPriceLastNBatches(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{
+		Type:               LastNBatchesType,
+		DefaultGasPriceWei: 1000,
+		MaxGasPriceWei:     150,
+		CheckBlocks:        3,
+		Percentile:         50,
+		Factor:             0.5,
+	}
+	stateM := newStateMock(t)
+	poolM := newPoolMock(t)
+	g := newLastNL2BlocksGasPriceSuggester(ctx, cfg, stateM, poolM)
+
+	// the gas price isn't updated if the last block can't be read
+	stateM.On("GetLastL2BlockNumber", ctx, nil).Return(uint64(0), errors.New("state error")).Once()
+	g.UpdateGasPriceAvg()
+
+	stateM.On("GetLastL2BlockNumber", ctx, nil).Return(uint64(1), nil).Once()
+	stateM.On("GetTxsByBlockNumber", ctx, uint64(1), nil).Return([]*types.Transaction{newTestTx(300)}, nil).Once()
+	poolM.On("SetGasPrices", ctx, cfg.MaxGasPriceWei, uint64(300)).Return(nil).Once()
+	g.UpdateGasPriceAvg()
+}
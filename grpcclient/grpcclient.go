@@ -0,0 +1,48 @@
+// Package grpcclient contains the options shared by the gRPC clients of the node
+package grpcclient
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// CompressionNone disables the compression of the messages
+	CompressionNone = ""
+	// CompressionGzip compresses the messages with gzip
+	CompressionGzip = "gzip"
+	// CompressionSnappy compresses the messages with snappy
+	CompressionSnappy = "snappy"
+)
+
+// CallOptions returns the default call options of a client connection with the compression and the
+// max sizes of the received and sent messages, a max size of 0 keeps the gRPC default
+func CallOptions(compression string, maxRecvMsgSize, maxSendMsgSize int) ([]grpc.CallOption, error) {
+	var opts []grpc.CallOption
+	switch compression {
+	case CompressionNone:
+	case CompressionGzip, CompressionSnappy:
+		opts = append(opts, grpc.UseCompressor(compression))
+	default:
+		return nil, fmt.Errorf("unknown gRPC compression %q, the possible values are %q, %q and %q", compression, CompressionNone, CompressionGzip, CompressionSnappy)
+	}
+	if maxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxCallRecvMsgSize(maxRecvMsgSize))
+	}
+	if maxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxCallSendMsgSize(maxSendMsgSize))
+	}
+	return opts, nil
+}
+
+// IsMessageTooLarge returns true if the error is caused by a message exceeding the max
+// message size of the client or the server
+func IsMessageTooLarge(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.ResourceExhausted && strings.Contains(st.Message(), "message larger than max")
+}
@@ -0,0 +1,55 @@
+package grpcclient
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+)
+
+func TestCallOptions(t *testing.T) {
+	opts, err := CallOptions(CompressionNone, 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	opts, err = CallOptions(CompressionSnappy, 100, 100)
+	require.NoError(t, err)
+	assert.Len(t, opts, 3)
+
+	_, err = CallOptions("lz4", 0, 0)
+	assert.Error(t, err)
+}
+
+func TestSnappyCompressor(t *testing.T) {
+	compressor := encoding.GetCompressor(CompressionSnappy)
+	require.NotNil(t, compressor)
+	require.NotNil(t, encoding.GetCompressor(CompressionGzip))
+
+	data := bytes.Repeat([]byte("batchL2Data"), 1000)
+	var compressed bytes.Buffer
+	w, err := compressor.Compress(&compressed)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Less(t, compressed.Len(), len(data))
+
+	r, err := compressor.Decompress(&compressed)
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestIsMessageTooLarge(t *testing.T) {
+	assert.True(t, IsMessageTooLarge(status.Errorf(codes.ResourceExhausted, "grpc: trying to send message larger than max (5000000 vs. 4194304)")))
+	assert.True(t, IsMessageTooLarge(status.Errorf(codes.ResourceExhausted, "grpc: received message larger than max (5000000 vs. 4194304)")))
+	assert.False(t, IsMessageTooLarge(status.Errorf(codes.ResourceExhausted, "executor is busy")))
+	assert.False(t, IsMessageTooLarge(errors.New("message larger than max")))
+}
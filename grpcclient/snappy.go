@@ -0,0 +1,30 @@
+package grpcclient
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+)
+
+func init() {
+	encoding.RegisterCompressor(&snappyCompressor{})
+}
+
+// snappyCompressor is the gRPC compressor of the snappy framing format
+type snappyCompressor struct{}
+
+// Compress returns a writer that compresses with snappy the data written to w
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+// Decompress returns a reader that decompresses with snappy the data read from r
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+// Name returns the name of the compressor used in the grpc-encoding header
+func (c *snappyCompressor) Name() string {
+	return CompressionSnappy
+}
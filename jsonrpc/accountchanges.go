@@ -0,0 +1,129 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// zkevmSubscriptionMethod is the method of the notifications of the zkevm subscriptions
+const zkevmSubscriptionMethod = "zkevm_subscription"
+
+// accountState is the state of an account tracked by the account changes subscriptions
+type accountState struct {
+	balance  *big.Int
+	nonce    *big.Int
+	codeHash common.Hash
+}
+
+func (a accountState) equals(b accountState) bool {
+	return a.balance.Cmp(b.balance) == 0 && a.nonce.Cmp(b.nonce) == 0 && a.codeHash == b.codeHash
+}
+
+// notifyAccountChanges polls the state for the batches closed since the last poll and
+// sends the changes of the watched accounts made by each of them to the account changes
+// subscriptions
+func (z *ZKEVMEndpoints) notifyAccountChanges() {
+	ticker := time.NewTicker(z.cfg.WebSockets.AccountChangesPollingInterval.Duration)
+	defer ticker.Stop()
+
+	ctx := context.Background()
+	var lastBatchNumber *uint64
+	for range ticker.C {
+		closedBatchNumber, err := z.state.GetLastClosedBatchNumber(ctx, nil)
+		if err != nil {
+			log.Errorf("failed to get last closed batch number: %v", err)
+			continue
+		}
+
+		filters, err := z.storage.GetAllAccountChangesFiltersWithWSConn()
+		if err != nil {
+			log.Errorf("failed to get account changes filters with web sockets connections: %v", err)
+			continue
+		}
+		if len(filters) == 0 || lastBatchNumber == nil {
+			lastBatchNumber = &closedBatchNumber
+			continue
+		}
+
+		for batchNumber := *lastBatchNumber + 1; batchNumber <= closedBatchNumber; batchNumber++ {
+			if err := z.notifyBatchAccountChanges(ctx, batchNumber, filters); err != nil {
+				log.Errorf("failed to notify the account changes of batch %v: %v", batchNumber, err)
+				break
+			}
+			*lastBatchNumber = batchNumber
+		}
+	}
+}
+
+// notifyBatchAccountChanges sends the state of the watched accounts changed by the batch
+// to the filters watching them
+func (z *ZKEVMEndpoints) notifyBatchAccountChanges(ctx context.Context, batchNumber uint64, filters []*Filter) error {
+	batch, err := z.state.GetBatchByNumber(ctx, batchNumber, nil)
+	if err != nil {
+		return err
+	}
+	previousBatch, err := z.state.GetBatchByNumber(ctx, batchNumber-1, nil)
+	if err != nil {
+		return err
+	}
+	if batch.StateRoot == previousBatch.StateRoot {
+		return nil
+	}
+
+	watchers := map[common.Address][]*Filter{}
+	for _, filter := range filters {
+		for _, address := range filter.Parameters.([]common.Address) {
+			watchers[address] = append(watchers[address], filter)
+		}
+	}
+
+	for address, addressFilters := range watchers {
+		previous, err := z.getAccountState(ctx, address, previousBatch.StateRoot)
+		if err != nil {
+			return err
+		}
+		current, err := z.getAccountState(ctx, address, batch.StateRoot)
+		if err != nil {
+			return err
+		}
+		if current.equals(previous) {
+			continue
+		}
+
+		data, err := json.Marshal(types.AccountChange{
+			Address:     address,
+			BatchNumber: types.ArgUint64(batchNumber),
+			StateRoot:   batch.StateRoot,
+			Balance:     types.ArgBig(*current.balance),
+			Nonce:       types.ArgBig(*current.nonce),
+			CodeHash:    current.codeHash,
+		})
+		if err != nil {
+			log.Errorf("failed to marshal account change of %v: %v", address.String(), err)
+			continue
+		}
+		for _, filter := range addressFilters {
+			sendSubscriptionResponse(filter, zkevmSubscriptionMethod, data)
+		}
+	}
+	return nil
+}
+
+// getAccountState reads the balance, nonce and code hash leaves of the account at the state root
+func (z *ZKEVMEndpoints) getAccountState(ctx context.Context, address common.Address, root common.Hash) (accountState, error) {
+	proof, err := z.state.GetProof(ctx, address, nil, root)
+	if err != nil {
+		return accountState{}, err
+	}
+	return accountState{
+		balance:  proof.Balance,
+		nonce:    proof.Nonce,
+		codeHash: proof.CodeHash,
+	}, nil
+}
@@ -0,0 +1,211 @@
+package jsonrpc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const (
+	// apiKeyHeader is the header with the API key of the caller
+	apiKeyHeader = "X-Api-Key"
+	// redactedParams replaces the logged params of the methods sending raw txs
+	redactedParams = "[redacted]"
+	// apiKeyHashLength is the number of bytes of the API key hash logged
+	apiKeyHashLength = 8
+	bytesPerMB       = 1024 * 1024
+)
+
+// redactedMethods are the methods whose params contain raw txs, which are never logged
+var redactedMethods = map[string]struct{}{
+	"eth_sendRawTransaction":            {},
+	"eth_sendRawTransactionConditional": {},
+	"eth_sendTransaction":               {},
+}
+
+// auditLogEntry is a line of the audit log
+type auditLogEntry struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	IP         string    `json:"ip"`
+	APIKey     string    `json:"apiKey,omitempty"`
+	ParamsHash string    `json:"paramsHash"`
+	Params     string    `json:"params,omitempty"`
+	LatencyMs  int64     `json:"latencyMs"`
+	Code       int       `json:"code"`
+}
+
+// auditLog writes a sample of the handled requests to a file, so the abuse of the public
+// endpoints can be investigated. The API keys are logged hashed and the raw txs are never logged
+type auditLog struct {
+	cfg         AuditLogConfig
+	sampleRates map[string]float64
+	random      func() float64
+	writer      *rotatingWriter
+}
+
+func newAuditLog(cfg AuditLogConfig) (*auditLog, error) {
+	if cfg.Filename == "" {
+		return nil, errors.New("the audit log filename is empty")
+	}
+	writer, err := newRotatingWriter(cfg.Filename, cfg.MaxSizeMB*bytesPerMB, cfg.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	a := &auditLog{
+		cfg:         cfg,
+		sampleRates: make(map[string]float64, len(cfg.Namespaces)),
+		random:      rand.Float64, //nolint:gosec
+		writer:      writer,
+	}
+	for _, ns := range cfg.Namespaces {
+		a.sampleRates[ns.Namespace] = ns.SampleRate
+	}
+	return a, nil
+}
+
+// record logs the request sent by the client IP and its response if it's sampled
+func (a *auditLog) record(req handleRequest, ip string, resp types.Response, start time.Time) {
+	if !a.isSampled(req.Method) {
+		return
+	}
+
+	paramsHash := sha256.Sum256(req.Params)
+	entry := auditLogEntry{
+		Time:       start.UTC(),
+		Method:     req.Method,
+		IP:         ip,
+		ParamsHash: hex.EncodeToString(paramsHash[:]),
+		LatencyMs:  time.Since(start).Milliseconds(),
+	}
+	if req.HttpRequest != nil {
+		if apiKey := req.HttpRequest.Header.Get(apiKeyHeader); apiKey != "" {
+			apiKeyHash := sha256.Sum256([]byte(apiKey))
+			entry.APIKey = hex.EncodeToString(apiKeyHash[:apiKeyHashLength])
+		}
+	}
+	if a.cfg.LogParams {
+		entry.Params = string(req.Params)
+		if _, found := redactedMethods[req.Method]; found {
+			entry.Params = redactedParams
+		}
+	}
+	if resp.Error != nil {
+		entry.Code = resp.Error.Code
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Errorf("failed to encode the audit log entry of method %s: %v", req.Method, err)
+		return
+	}
+	if err := a.writer.writeLine(line); err != nil {
+		log.Errorf("failed to write the audit log entry of method %s: %v", req.Method, err)
+	}
+}
+
+// isSampled decides if a request of the method is logged, using the sample rate of its namespace
+func (a *auditLog) isSampled(method string) bool {
+	rate := a.cfg.SampleRate
+	if namespace, _, found := strings.Cut(method, "_"); found {
+		if nsRate, found := a.sampleRates[namespace]; found {
+			rate = nsRate
+		}
+	}
+	if rate >= 1 {
+		return true
+	}
+	return rate > 0 && a.random() < rate
+}
+
+func (a *auditLog) close() error {
+	return a.writer.close()
+}
+
+// rotatingWriter appends lines to a file, once the file reaches the max size it's renamed
+// to <filename>.1, the previous backups are shifted and the ones over maxBackups are removed
+type rotatingWriter struct {
+	filename   string
+	maxSize    int64
+	maxBackups int
+
+	file  *os.File
+	size  int64
+	mutex sync.Mutex
+}
+
+func newRotatingWriter(filename string, maxSize int64, maxBackups int) (*rotatingWriter, error) {
+	w := &rotatingWriter{filename: filename, maxSize: maxSize, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingWriter) open() error {
+	file, err := os.OpenFile(w.filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) //nolint:gomnd
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+func (w *rotatingWriter) writeLine(line []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	line = append(line, '\n')
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(line)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	return err
+}
+
+// rotate must be called with the mutex locked
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	if w.maxBackups > 0 {
+		for i := w.maxBackups - 1; i > 0; i-- {
+			_ = os.Rename(w.backupName(i), w.backupName(i+1))
+		}
+		if err := os.Rename(w.filename, w.backupName(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(w.filename); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *rotatingWriter) backupName(i int) string {
+	return fmt.Sprintf("%s.%d", w.filename, i)
+}
+
+func (w *rotatingWriter) close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.file.Close()
+}
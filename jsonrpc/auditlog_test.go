@@ -0,0 +1,86 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	a, err := newAuditLog(AuditLogConfig{
+		Enabled:    true,
+		Filename:   filename,
+		SampleRate: 1,
+		Namespaces: []NamespaceAuditLogConfig{{Namespace: "net", SampleRate: 0}, {Namespace: "debug", SampleRate: 0.5}},
+		LogParams:  true,
+	})
+	require.NoError(t, err)
+	a.random = func() float64 { return 0.6 }
+
+	httpReq, err := http.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, err)
+	httpReq.RemoteAddr = "1.2.3.4:5678"
+	httpReq.Header.Set(apiKeyHeader, "secret")
+	newRequest := func(method, params string) handleRequest {
+		return handleRequest{Request: types.Request{JSONRPC: "2.0", Method: method, Params: json.RawMessage(params)}, HttpRequest: httpReq}
+	}
+
+	a.record(newRequest("eth_getBalance", `["0x1","latest"]`), "1.2.3.4", types.Response{}, time.Now())
+	a.record(newRequest("eth_sendRawTransaction", `["0xf86c"]`), "1.2.3.4", types.Response{Error: &types.ErrorObject{Code: types.DefaultErrorCode}}, time.Now())
+	// not sampled
+	a.record(newRequest("net_version", `[]`), "1.2.3.4", types.Response{}, time.Now())
+	a.record(newRequest("debug_traceTransaction", `["0x1"]`), "1.2.3.4", types.Response{}, time.Now())
+	require.NoError(t, a.close())
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var entry auditLogEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "eth_getBalance", entry.Method)
+	assert.Equal(t, "1.2.3.4", entry.IP)
+	assert.Len(t, entry.APIKey, 2*apiKeyHashLength)
+	assert.NotContains(t, lines[0], "secret")
+	assert.Equal(t, `["0x1","latest"]`, entry.Params)
+	assert.Equal(t, 0, entry.Code)
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, "eth_sendRawTransaction", entry.Method)
+	assert.Equal(t, redactedParams, entry.Params)
+	assert.NotContains(t, lines[1], "0xf86c")
+	assert.NotEmpty(t, entry.ParamsHash)
+	assert.Equal(t, types.DefaultErrorCode, entry.Code)
+}
+
+func TestRotatingWriter(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	w, err := newRotatingWriter(filename, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first", "second", "third", "fourth"} {
+		require.NoError(t, w.writeLine([]byte(line)))
+	}
+	require.NoError(t, w.close())
+
+	read := func(name string) string {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "fourth\n", read(filename))
+	assert.Equal(t, "third\n", read(filename+".1"))
+	assert.Equal(t, "second\n", read(filename+".2"))
+	_, err = os.Stat(filename + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,92 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/broker"
+)
+
+// batchStatusSink is the in-process sink of the event broker notifying the batch
+// status transitions to the WebSocket subscriptions of the eth endpoints
+type batchStatusSink struct {
+	e *EthEndpoints
+}
+
+// NewBatchStatusSink creates the broker sink notifying the batch status transitions to the
+// zkevm_batchStatus WebSocket subscriptions, enabling them in the eth endpoints
+func NewBatchStatusSink(e *EthEndpoints) broker.Sink {
+	e.batchStatusEnabled.Store(true)
+	return &batchStatusSink{e: e}
+}
+
+// Name returns the name of the sink
+func (s *batchStatusSink) Name() string {
+	return "websocket"
+}
+
+// Send notifies the batch status events to the subscriptions, the other events are
+// already notified by the newHeads and logs subscriptions
+func (s *batchStatusSink) Send(event broker.Event) error {
+	if event.Type != broker.EventTypeBatchStatus {
+		return nil
+	}
+	filters, err := s.e.storage.GetAllBatchStatusFiltersWithWSConn()
+	if err != nil {
+		return fmt.Errorf("failed to get all batch status filters with web sockets connections: %w", err)
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(event.BatchStatus)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch status response to subscription: %w", err)
+	}
+	for _, filter := range filters {
+		s.e.sendSubscriptionResponse(filter, data)
+	}
+	return nil
+}
+
+// selectedTxsSink is the in-process sink of the event broker notifying the txs selected
+// by the sequencer to the WebSocket subscriptions of the eth endpoints
+type selectedTxsSink struct {
+	e *EthEndpoints
+}
+
+// NewSelectedTransactionsSink creates the broker sink notifying the txs selected by the
+// sequencer to the zkevm_selectedTransactions WebSocket subscriptions, enabling them in the
+// eth endpoints. It must only be added when the sequencer runs in the same process
+func NewSelectedTransactionsSink(e *EthEndpoints) broker.Sink {
+	e.selectedTxsEnabled.Store(true)
+	return &selectedTxsSink{e: e}
+}
+
+// Name returns the name of the sink
+func (s *selectedTxsSink) Name() string {
+	return "websocket selected txs"
+}
+
+// Send notifies the selected tx events to the subscriptions
+func (s *selectedTxsSink) Send(event broker.Event) error {
+	if event.Type != broker.EventTypeTxSelected {
+		return nil
+	}
+	filters, err := s.e.storage.GetAllSelectedTxFiltersWithWSConn()
+	if err != nil {
+		return fmt.Errorf("failed to get all selected tx filters with web sockets connections: %w", err)
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(event.TxSelected)
+	if err != nil {
+		return fmt.Errorf("failed to marshal selected tx response to subscription: %w", err)
+	}
+	for _, filter := range filters {
+		s.e.sendSubscriptionResponse(filter, data)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package jsonrpc
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+// gzipResponseWriter compresses the body written to the wrapped response writer
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.gz.Write(b)
+}
+
+func (w *gzipResponseWriter) WriteHeader(statusCode int) {
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// acceptsGzip returns true if the client accepts gzip encoded responses
+func acceptsGzip(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0]) == "gzip" { //nolint:gomnd
+			return true
+		}
+	}
+	return false
+}
+
+// withGzip wraps the response writer to compress the response when the client accepts
+// gzip, the returned function must be called once the response is written to flush it
+func withGzip(w http.ResponseWriter, req *http.Request) (http.ResponseWriter, func()) {
+	if !acceptsGzip(req) {
+		return w, func() {}
+	}
+
+	gz := gzipWriterPool.Get().(*gzip.Writer)
+	gz.Reset(w)
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Add("Vary", "Accept-Encoding")
+	return &gzipResponseWriter{ResponseWriter: w, gz: gz}, func() {
+		if err := gz.Close(); err != nil {
+			log.Errorf("failed to flush the gzip response: %v", err)
+		}
+		gzipWriterPool.Put(gz)
+	}
+}
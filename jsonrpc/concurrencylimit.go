@@ -0,0 +1,89 @@
+package jsonrpc
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+var (
+	// errTooManyInFlightRequests is returned when the server is already handling the max number of requests
+	errTooManyInFlightRequests = errors.New("too many requests in flight, try again later")
+	// errTooManyConcurrentRequests is returned when the IP already has the max number of requests being handled
+	errTooManyConcurrentRequests = errors.New("too many concurrent requests from the same IP, try again later")
+)
+
+// concurrencyLimiter limits the number of requests handled at the same time by
+// the server and by each IP, the requests over the limits are rejected instead
+// of queued so a single client can't exhaust the executor and DB connections
+type concurrencyLimiter struct {
+	cfg        ConcurrencyLimitConfig
+	trustedIPs []*net.IPNet
+
+	inFlight      uint64
+	inFlightPerIP map[string]uint64
+	mutex         sync.Mutex
+}
+
+func newConcurrencyLimiter(cfg ConcurrencyLimitConfig) (*concurrencyLimiter, error) {
+	l := &concurrencyLimiter{
+		cfg:           cfg,
+		inFlightPerIP: make(map[string]uint64),
+	}
+	for _, trusted := range cfg.TrustedIPs {
+		ipNet, err := parseIPNet(trusted)
+		if err != nil {
+			return nil, err
+		}
+		l.trustedIPs = append(l.trustedIPs, ipNet)
+	}
+	return l, nil
+}
+
+// acquire reserves a slot to handle a request of the IP, the slot must be
+// released with release once the request is handled
+func (l *concurrencyLimiter) acquire(ip string) error {
+	perIPLimited := l.cfg.MaxConcurrentRequestsPerIP > 0 && !l.isTrusted(ip)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.cfg.MaxInFlightRequests > 0 && l.inFlight >= l.cfg.MaxInFlightRequests {
+		return errTooManyInFlightRequests
+	}
+	if perIPLimited && l.inFlightPerIP[ip] >= l.cfg.MaxConcurrentRequestsPerIP {
+		return errTooManyConcurrentRequests
+	}
+
+	l.inFlight++
+	l.inFlightPerIP[ip]++
+	return nil
+}
+
+// release frees the slot reserved by acquire for a request of the IP
+func (l *concurrencyLimiter) release(ip string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.inFlight > 0 {
+		l.inFlight--
+	}
+	if l.inFlightPerIP[ip] <= 1 {
+		delete(l.inFlightPerIP, ip)
+	} else {
+		l.inFlightPerIP[ip]--
+	}
+}
+
+func (l *concurrencyLimiter) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range l.trustedIPs {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter, err := newConcurrencyLimiter(ConcurrencyLimitConfig{
+		Enabled:                    true,
+		MaxInFlightRequests:        4,
+		MaxConcurrentRequestsPerIP: 2,
+		TrustedIPs:                 []string{"10.0.0.0/8"},
+	})
+	require.NoError(t, err)
+
+	// each IP can have up to 2 requests in flight
+	require.NoError(t, limiter.acquire("1.1.1.1"))
+	require.NoError(t, limiter.acquire("1.1.1.1"))
+	assert.Equal(t, errTooManyConcurrentRequests, limiter.acquire("1.1.1.1"))
+
+	// a released slot can be used again
+	limiter.release("1.1.1.1")
+	require.NoError(t, limiter.acquire("1.1.1.1"))
+
+	// trusted IPs are not limited per IP but count for the in-flight requests
+	require.NoError(t, limiter.acquire("10.1.2.3"))
+	require.NoError(t, limiter.acquire("10.1.2.3"))
+	assert.Equal(t, errTooManyInFlightRequests, limiter.acquire("10.1.2.3"))
+	assert.Equal(t, errTooManyInFlightRequests, limiter.acquire("2.2.2.2"))
+
+	limiter.release("10.1.2.3")
+	require.NoError(t, limiter.acquire("2.2.2.2"))
+
+	// the IPs without requests in flight are removed
+	limiter.release("2.2.2.2")
+	limiter.release("10.1.2.3")
+	limiter.release("1.1.1.1")
+	limiter.release("1.1.1.1")
+	assert.Equal(t, uint64(0), limiter.inFlight)
+	assert.Empty(t, limiter.inFlightPerIP)
+}
+
+func TestConcurrencyLimiterInvalidTrustedIP(t *testing.T) {
+	_, err := newConcurrencyLimiter(ConcurrencyLimitConfig{TrustedIPs: []string{"localhost"}})
+	assert.Error(t, err)
+}
@@ -35,6 +35,9 @@ type Config struct {
 	// WebSockets configuration
 	WebSockets WebSocketsConfig `mapstructure:"WebSockets"`
 
+	// IPC configuration of the unix socket serving the endpoints to the processes of the same host
+	IPC IPCConfig `mapstructure:"IPC"`
+
 	// EnableL2SuggestedGasPricePolling enables polling of the L2 gas price to block tx in the RPC with lower gas price.
 	EnableL2SuggestedGasPricePolling bool `mapstructure:"EnableL2SuggestedGasPricePolling"`
 
@@ -59,9 +62,220 @@ type Config struct {
 	// native block hashes in a single call to the state, if zero it means no limit
 	MaxNativeBlockHashBlockRange uint64 `mapstructure:"MaxNativeBlockHashBlockRange"`
 
+	// EstimateGasCap is the max gas limit tried by eth_estimateGas, if zero the max gas allowed per batch is used
+	EstimateGasCap uint64 `mapstructure:"EstimateGasCap"`
+
+	// EstimateGasErrorRatio is the max ratio between the range of the eth_estimateGas search and the estimated
+	// gas, a higher ratio executes the tx fewer times but can overestimate the gas. If zero the exact gas is searched
+	EstimateGasErrorRatio float64 `mapstructure:"EstimateGasErrorRatio"`
+
+	// MaxFeeHistoryBlockCount is the max number of blocks returned by eth_feeHistory,
+	// bigger block counts are truncated, if zero it means no limit
+	MaxFeeHistoryBlockCount uint64 `mapstructure:"MaxFeeHistoryBlockCount"`
+
+	// MaxMulticallCalls is the max number of calls executed by a single zkevm_multicall request,
+	// if zero it means no limit
+	MaxMulticallCalls uint64 `mapstructure:"MaxMulticallCalls"`
+
+	// MulticallConcurrency is the max number of calls of a zkevm_multicall request executed at
+	// the same time, if zero the calls are executed one by one
+	MulticallConcurrency uint64 `mapstructure:"MulticallConcurrency"`
+
+	// FilterTimeout is the time a filter not bound to a web socket connection is kept
+	// without being polled, after it the filter is uninstalled, if zero filters never expire
+	FilterTimeout types.Duration `mapstructure:"FilterTimeout"`
+
+	// FilterCleanupInterval is the interval to look for expired filters to uninstall them
+	FilterCleanupInterval types.Duration `mapstructure:"FilterCleanupInterval"`
+
+	// MaxRequestContentLength is the max size in bytes of the body of a HTTP request,
+	// if zero the default limit of 5MB is used
+	MaxRequestContentLength int64 `mapstructure:"MaxRequestContentLength"`
+
+	// MaxRawTransactionSize is the max size in bytes of the raw tx accepted by
+	// eth_sendRawTransaction and eth_sendRawTransactionConditional, if zero it means no limit
+	MaxRawTransactionSize uint64 `mapstructure:"MaxRawTransactionSize"`
+
+	// MaxTracerSize is the max size in bytes of each field of the custom tracer and
+	// tracer config accepted by the debug trace methods, if zero it means no limit
+	MaxTracerSize uint64 `mapstructure:"MaxTracerSize"`
+
 	// EnableHttpLog allows the user to enable or disable the logs related to the HTTP
 	// requests to be captured by the server.
 	EnableHttpLog bool `mapstructure:"EnableHttpLog"`
+
+	// EnabledApis defines the namespaces exposed by the server, like eth, net, web3, txpool,
+	// debug, zkevm and admin, the --http.api flag overrides it when provided
+	EnabledApis []string `mapstructure:"EnabledApis"`
+
+	// DisabledMethods defines the methods that are not exposed even if their namespace
+	// is enabled, like debug_traceBlockByNumber, they are reported as not found
+	DisabledMethods []string `mapstructure:"DisabledMethods"`
+
+	// ReadOnly disables the methods that mutate the state of the node, like sending txs,
+	// installing filters and the admin namespace, to serve public replicas safely
+	ReadOnly bool `mapstructure:"ReadOnly"`
+
+	// AdminAuthToken is the token required to call the admin namespace, the requests must send it
+	// in the "Authorization: Bearer <token>" header. When empty the admin namespace is only served
+	// through the IPC socket, it is not registered at all if the IPC is disabled
+	AdminAuthToken string `mapstructure:"AdminAuthToken"`
+
+	// ArchiveMode defines if the state of every historical block is retained by the merkletree,
+	// allowing the state queries like eth_call or eth_getBalance against any block, when disabled
+	// the state is only served for the last StateHistoryBlocks blocks
+	ArchiveMode bool `mapstructure:"ArchiveMode"`
+
+	// StateHistoryBlocks is the number of recent blocks whose state is retained when ArchiveMode is disabled
+	StateHistoryBlocks uint64 `mapstructure:"StateHistoryBlocks"`
+
+	// EnableCompression enables the gzip compression of the HTTP responses for the clients
+	// accepting it and the per message compression of the WebSocket connections
+	EnableCompression bool `mapstructure:"EnableCompression"`
+
+	// CORS configuration of the cross-origin requests allowed by the HTTP and WS servers
+	CORS CORSConfig `mapstructure:"CORS"`
+
+	// TLS configuration to serve the HTTP and WS endpoints over HTTPS and WSS
+	TLS TLSConfig `mapstructure:"TLS"`
+
+	// DevMode configuration, it must never be enabled outside local testing environments
+	DevMode DevModeConfig `mapstructure:"DevMode"`
+
+	// TrustedProxies defines the IPs or CIDR ranges of the reverse proxies in front of the server, the
+	// X-Forwarded-For header is only read from them to get the client IP used by the per IP limits
+	// and the audit log, the remote address of the requests is used otherwise
+	TrustedProxies []string `mapstructure:"TrustedProxies"`
+
+	// RateLimit configuration of the limit applied to the requests per IP and method
+	RateLimit RateLimitConfig `mapstructure:"RateLimit"`
+
+	// ConcurrencyLimit configuration of the limit applied to the requests being handled at the same time
+	ConcurrencyLimit ConcurrencyLimitConfig `mapstructure:"ConcurrencyLimit"`
+
+	// TraceQueue configuration of the queue limiting the debug trace methods run at the same time
+	TraceQueue TraceQueueConfig `mapstructure:"TraceQueue"`
+
+	// Witness configuration of the zkevm_getWitness methods generating the state witness of a block or batch
+	Witness WitnessConfig `mapstructure:"Witness"`
+
+	// ShutdownTimeout is the max time to wait for the in-flight requests to finish when the
+	// server is stopped, the remaining connections are closed after it
+	ShutdownTimeout types.Duration `mapstructure:"ShutdownTimeout"`
+
+	// HealthCheck configuration of the /health/live and /health/ready HTTP endpoints
+	HealthCheck HealthCheckConfig `mapstructure:"HealthCheck"`
+
+	// WarmUp configuration of the gating of the state dependent methods until the node is synced
+	WarmUp WarmUpConfig `mapstructure:"WarmUp"`
+
+	// AuditLog defines the audit log of the handled requests
+	AuditLog AuditLogConfig `mapstructure:"AuditLog"`
+}
+
+// TraceQueueConfig has parameters to config the queue of the debug trace methods, so the
+// expensive traces can't exhaust the executor and stall the regular requests
+type TraceQueueConfig struct {
+	// Enabled defines if the trace queue is enabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxConcurrentTraces is the max number of traces run at the same time, it must be greater than 0
+	MaxConcurrentTraces uint64 `mapstructure:"MaxConcurrentTraces"`
+
+	// MaxQueuedTraces is the max number of traces waiting for a slot, the traces over it are
+	// rejected. 0 means no limit
+	MaxQueuedTraces uint64 `mapstructure:"MaxQueuedTraces"`
+
+	// QueueTimeout is the max time a trace waits for a slot before being rejected
+	QueueTimeout types.Duration `mapstructure:"QueueTimeout"`
+
+	// AsyncEnabled defines if the traces can be submitted as jobs with the debug_submitTrace*
+	// methods, their results are polled by job id with debug_getTraceJob
+	AsyncEnabled bool `mapstructure:"AsyncEnabled"`
+
+	// ResultsRetention is the time the results of the finished trace jobs are kept to be polled
+	ResultsRetention types.Duration `mapstructure:"ResultsRetention"`
+
+	// MaxStoredJobs is the max number of trace jobs stored, new jobs are rejected while the
+	// limit is reached. 0 means no limit
+	MaxStoredJobs uint64 `mapstructure:"MaxStoredJobs"`
+}
+
+// WitnessConfig has parameters to config the generation of the state witnesses, they contain
+// the accounts, storage slots and code accessed by the txs along with their merkle proofs
+type WitnessConfig struct {
+	// Enabled defines if the witness methods are enabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxAccounts is the max number of accounts of a witness, the witnesses over it are
+	// rejected. 0 means no limit
+	MaxAccounts uint64 `mapstructure:"MaxAccounts"`
+
+	// MaxStorageSlots is the max number of storage slots of a witness, the witnesses over it
+	// are rejected. 0 means no limit
+	MaxStorageSlots uint64 `mapstructure:"MaxStorageSlots"`
+
+	// Queue configuration of the queue limiting the witnesses generated at the same time, its
+	// async mode allows to submit the witnesses of the large batches as jobs
+	Queue TraceQueueConfig `mapstructure:"Queue"`
+}
+
+// HealthCheckConfig has parameters to config the HTTP endpoints used by the load balancers
+// to check if the node is alive and ready to handle requests
+type HealthCheckConfig struct {
+	// Enabled defines if the /health/live and /health/ready endpoints are served
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Timeout is the max time to check each dependency of the node
+	Timeout types.Duration `mapstructure:"Timeout"`
+
+	// MaxL2BlocksBehind is the max number of L2 blocks the node can be behind
+	// the last L2 block seen for the node to be ready. 0 disables the check
+	MaxL2BlocksBehind uint64 `mapstructure:"MaxL2BlocksBehind"`
+
+	// MaxBatchesBehind is the max number of batches the node can be behind the last
+	// batch seen for the node to be ready. 0 disables the check
+	MaxBatchesBehind uint64 `mapstructure:"MaxBatchesBehind"`
+}
+
+// WarmUpConfig has parameters to config the warm up of the RPC, while warming up the state dependent
+// methods are rejected with a syncing error and the node is not ready, so the load balancers don't route
+// the traffic to a freshly restored node serving stale data. Once warmed up the methods are always served
+type WarmUpConfig struct {
+	// Enabled defines if the state dependent methods are rejected while the node is warming up
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxBatchesBehind is the max number of batches the node can be behind the trusted tip to be warmed up.
+	// The trusted tip is the last batch of the SequencerNodeURI or the last batch seen in L1 when not set
+	MaxBatchesBehind uint64 `mapstructure:"MaxBatchesBehind"`
+
+	// CheckInterval is the interval to check the sync state of the node while it's warming up
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+}
+
+// DevModeConfig has parameters to config the rpc dev mode
+type DevModeConfig struct {
+	// Enabled defines if the dev mode is enabled, when enabled the accounts are
+	// unlocked and available via eth_accounts and eth_sendTransaction
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Accounts defines the key store files of the accounts to be unlocked
+	Accounts []types.KeystoreFileConfig `mapstructure:"Accounts"`
+}
+
+// IPCConfig has parameters to config the IPC endpoint, a unix socket serving the same methods as the
+// HTTP and WS endpoints to the co-located tools without opening a network port. The requests are a
+// stream of JSON values and each response is written followed by a new line
+type IPCConfig struct {
+	// Enabled defines if the IPC endpoint is enabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Path is the path of the unix socket, the file existing in the path is replaced when the server starts
+	Path string `mapstructure:"Path"`
+
+	// Permissions are the octal file permissions of the unix socket, like "0600". They restrict the local
+	// users allowed to connect, so the admin methods don't require the AdminAuthToken through the socket
+	Permissions string `mapstructure:"Permissions"`
 }
 
 // WebSocketsConfig has parameters to config the rpc websocket support
@@ -77,4 +291,127 @@ type WebSocketsConfig struct {
 
 	// ReadLimit defines the maximum size of a message read from the client (in bytes)
 	ReadLimit int64 `mapstructure:"ReadLimit"`
+
+	// DroppedTxsPollingInterval is the interval to poll the pool for dropped txs to notify
+	// the dropped transactions subscriptions. 0 disables the subscription
+	DroppedTxsPollingInterval types.Duration `mapstructure:"DroppedTxsPollingInterval"`
+
+	// PendingTxsPollingInterval is the interval to poll the pool for new pending txs to notify
+	// the newPendingTransactions subscriptions. 0 disables the subscription
+	PendingTxsPollingInterval types.Duration `mapstructure:"PendingTxsPollingInterval"`
+
+	// AccountChangesPollingInterval is the interval to poll the state for closed batches to notify
+	// the accountChanges subscriptions. 0 disables the subscription
+	AccountChangesPollingInterval types.Duration `mapstructure:"AccountChangesPollingInterval"`
+}
+
+// CORSConfig has parameters to config the Cross-Origin Resource Sharing headers
+type CORSConfig struct {
+	// AllowedOrigins defines the origins allowed to send requests, like https://app.example.com,
+	// `*` allows any origin and if empty any origin is allowed
+	AllowedOrigins []string `mapstructure:"AllowedOrigins"`
+
+	// AllowedHeaders defines the request headers allowed in the cross-origin requests
+	AllowedHeaders []string `mapstructure:"AllowedHeaders"`
+}
+
+// TLSConfig has parameters to config the TLS of the servers, TLS is enabled
+// when both the certificate and the key files are provided
+type TLSConfig struct {
+	// CertFile is the path of the PEM encoded certificate file
+	CertFile string `mapstructure:"CertFile"`
+
+	// KeyFile is the path of the PEM encoded private key file
+	KeyFile string `mapstructure:"KeyFile"`
+}
+
+// Enabled returns true if the certificate and key files are configured
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// RateLimitConfig has parameters to config the rate limit of the requests, each IP
+// has a token bucket per method with a specific limit and a shared one for the rest
+type RateLimitConfig struct {
+	// Enabled defines if the rate limit is enabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// RequestsPerSecond is the number of requests per second allowed to each IP
+	// for the methods without a specific limit
+	RequestsPerSecond float64 `mapstructure:"RequestsPerSecond"`
+
+	// Burst is the max number of requests an IP can send at once for the methods
+	// without a specific limit
+	Burst int `mapstructure:"Burst"`
+
+	// Methods defines specific limits for heavy methods like eth_getLogs
+	Methods []MethodRateLimitConfig `mapstructure:"Methods"`
+
+	// TrustedIPs defines the IPs or CIDR ranges that are never rate limited
+	TrustedIPs []string `mapstructure:"TrustedIPs"`
+}
+
+// ConcurrencyLimitConfig has parameters to config the max number of requests handled at
+// the same time, in total and for each IP, to protect the executor and the DB from a
+// single client sending lots of parallel heavy requests like eth_call
+type ConcurrencyLimitConfig struct {
+	// Enabled defines if the concurrency limit is enabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxInFlightRequests is the max number of requests handled at the same time by the server
+	MaxInFlightRequests uint64 `mapstructure:"MaxInFlightRequests"`
+
+	// MaxConcurrentRequestsPerIP is the max number of requests of each IP handled at the same time
+	MaxConcurrentRequestsPerIP uint64 `mapstructure:"MaxConcurrentRequestsPerIP"`
+
+	// TrustedIPs defines the IPs or CIDR ranges without a per IP limit, they are still
+	// counted in the in-flight requests
+	TrustedIPs []string `mapstructure:"TrustedIPs"`
+}
+
+// AuditLogConfig has parameters to config the audit log of the handled requests, a JSON line per request
+// with the method, the caller IP and API key, the hash of the params, the latency and the result code
+type AuditLogConfig struct {
+	// Enabled defines if the audit log is enabled
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Filename is the path of the audit log file
+	Filename string `mapstructure:"Filename"`
+
+	// SampleRate is the fraction of the requests logged, from 0 to 1, for the namespaces without a specific rate
+	SampleRate float64 `mapstructure:"SampleRate"`
+
+	// Namespaces defines specific sample rates for namespaces like eth or zkevm
+	Namespaces []NamespaceAuditLogConfig `mapstructure:"Namespaces"`
+
+	// LogParams defines if the params are logged besides their hash, the raw txs of
+	// the methods sending txs are always redacted
+	LogParams bool `mapstructure:"LogParams"`
+
+	// MaxSizeMB is the max size of the audit log file before it's rotated, 0 disables the rotation
+	MaxSizeMB int64 `mapstructure:"MaxSizeMB"`
+
+	// MaxBackups is the number of rotated audit log files kept
+	MaxBackups int `mapstructure:"MaxBackups"`
+}
+
+// NamespaceAuditLogConfig has parameters to config the audit log of a namespace
+type NamespaceAuditLogConfig struct {
+	// Namespace is the name of the namespace, like eth
+	Namespace string `mapstructure:"Namespace"`
+
+	// SampleRate is the fraction of the requests of the namespace logged, from 0 to 1
+	SampleRate float64 `mapstructure:"SampleRate"`
+}
+
+// MethodRateLimitConfig has parameters to config the rate limit of a method
+type MethodRateLimitConfig struct {
+	// Method is the name of the JSON-RPC method, like eth_getLogs
+	Method string `mapstructure:"Method"`
+
+	// RequestsPerSecond is the number of requests per second allowed to each IP
+	RequestsPerSecond float64 `mapstructure:"RequestsPerSecond"`
+
+	// Burst is the max number of requests an IP can send at once
+	Burst int `mapstructure:"Burst"`
 }
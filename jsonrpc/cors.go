@@ -0,0 +1,65 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"strings"
+)
+
+const corsAllowAnyOrigin = "*"
+
+// defaultCORSAllowedHeaders are the headers allowed when none is configured
+var defaultCORSAllowedHeaders = []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization"}
+
+// cors sets the Cross-Origin Resource Sharing headers of the responses
+// accordingly to the configured origins and headers
+type cors struct {
+	allowAnyOrigin bool
+	allowedOrigins map[string]struct{}
+	allowedHeaders string
+}
+
+func newCORS(cfg CORSConfig) *cors {
+	c := &cors{
+		allowAnyOrigin: len(cfg.AllowedOrigins) == 0,
+		allowedOrigins: make(map[string]struct{}, len(cfg.AllowedOrigins)),
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == corsAllowAnyOrigin {
+			c.allowAnyOrigin = true
+		}
+		c.allowedOrigins[strings.ToLower(origin)] = struct{}{}
+	}
+
+	allowedHeaders := cfg.AllowedHeaders
+	if len(allowedHeaders) == 0 {
+		allowedHeaders = defaultCORSAllowedHeaders
+	}
+	c.allowedHeaders = strings.Join(allowedHeaders, ", ")
+	return c
+}
+
+// isOriginAllowed returns true if the requests from the origin are allowed,
+// requests without origin are not cross-origin requests so they are always allowed
+func (c *cors) isOriginAllowed(origin string) bool {
+	if origin == "" || c.allowAnyOrigin {
+		return true
+	}
+	_, found := c.allowedOrigins[strings.ToLower(origin)]
+	return found
+}
+
+// setHeaders sets the CORS headers of the response to the request
+func (c *cors) setHeaders(w http.ResponseWriter, req *http.Request) {
+	if c.allowAnyOrigin {
+		w.Header().Set("Access-Control-Allow-Origin", corsAllowAnyOrigin)
+	} else {
+		w.Header().Add("Vary", "Origin")
+		origin := req.Header.Get("Origin")
+		if origin == "" || !c.isOriginAllowed(origin) {
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", c.allowedHeaders)
+}
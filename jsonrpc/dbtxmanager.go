@@ -18,10 +18,24 @@ type DBTxer interface {
 	BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
 }
 
+// DBReadTxer interface to begin read only DB txs, the states implementing it serve the
+// scoped DB txs from the read replica
+type DBReadTxer interface {
+	BeginStateReadTransaction(ctx context.Context) (pgx.Tx, error)
+}
+
 // NewDbTxScope function to initiate DB scopped txs
 func (f *DBTxManager) NewDbTxScope(db DBTxer, scopedFn DBTxScopedFn) (interface{}, types.Error) {
 	ctx := context.Background()
-	dbTx, err := db.BeginStateTransaction(ctx)
+	var (
+		dbTx pgx.Tx
+		err  error
+	)
+	if readTxer, ok := db.(DBReadTxer); ok {
+		dbTx, err = readTxer.BeginStateReadTransaction(ctx)
+	} else {
+		dbTx, err = db.BeginStateTransaction(ctx)
+	}
 	if err != nil {
 		return RPCErrorResponse(types.DefaultErrorCode, "failed to connect to the state", err, true)
 	}
@@ -97,3 +97,26 @@ func TestNewDbTxScope(t *testing.T) {
 		})
 	}
 }
+
+type readTxerMock struct {
+	*mocks.StateMock
+	readTx pgx.Tx
+}
+
+func (r *readTxerMock) BeginStateReadTransaction(ctx context.Context) (pgx.Tx, error) {
+	return r.readTx, nil
+}
+
+func TestNewDbTxScopeUsesReadTx(t *testing.T) {
+	d := mocks.NewDBTxMock(t)
+	d.On("Commit", context.Background()).Return(nil).Once()
+	s := &readTxerMock{StateMock: mocks.NewStateMock(t), readTx: d}
+
+	dbTxManager := DBTxManager{}
+	result, err := dbTxManager.NewDbTxScope(s, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		assert.Equal(t, d, dbTx)
+		return 1, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result)
+}
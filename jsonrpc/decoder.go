@@ -0,0 +1,204 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+)
+
+var (
+	// errEmptyRequestBody is returned when the body of the request only contains white spaces
+	errEmptyRequestBody = errors.New("empty request body")
+	// errInvalidJSONObjectRequest is returned when the body of a single request is not a valid json object
+	errInvalidJSONObjectRequest = errors.New("invalid json object request body")
+	// errInvalidJSONArrayRequest is returned when the body of a batch request is not a valid json array
+	errInvalidJSONArrayRequest = errors.New("invalid json array request body")
+)
+
+// requestDecoder decodes the body of a HTTP request as a stream, the requests of
+// a batch are decoded one by one so the batch limit is enforced before reading the
+// whole body and the body is never buffered twice
+type requestDecoder struct {
+	reader *bufio.Reader
+}
+
+func newRequestDecoder(w http.ResponseWriter, body io.ReadCloser, maxContentLength int64) *requestDecoder {
+	return &requestDecoder{
+		reader: bufio.NewReader(http.MaxBytesReader(w, body, maxContentLength)),
+	}
+}
+
+// newMessageDecoder creates a decoder for a message already read, like the WS ones
+func newMessageDecoder(message []byte) *requestDecoder {
+	return &requestDecoder{
+		reader: bufio.NewReader(bytes.NewReader(message)),
+	}
+}
+
+// isSingleRequest peeks the first non white space char of the body to know if
+// it contains a single request or a batch of requests
+func (d *requestDecoder) isSingleRequest() (bool, error) {
+	for {
+		b, err := d.reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return false, errEmptyRequestBody
+		} else if err != nil {
+			return false, err
+		}
+		if isWhiteSpace(b) {
+			continue
+		}
+		if err := d.reader.UnreadByte(); err != nil {
+			return false, err
+		}
+		return b != '[', nil
+	}
+}
+
+// decodeRequest decodes a single request, the body must not contain anything else
+func (d *requestDecoder) decodeRequest() (types.Request, error) {
+	dec := json.NewDecoder(d.reader)
+
+	var req types.Request
+	if err := dec.Decode(&req); err != nil {
+		return types.Request{}, decodeError(err, errInvalidJSONObjectRequest)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return types.Request{}, decodeError(err, errInvalidJSONObjectRequest)
+	}
+
+	return req, nil
+}
+
+// batchItem is a request of a batch, the items that are valid json but not
+// valid requests are kept to answer them with an error in the same position
+type batchItem struct {
+	types.Request
+	err types.Error
+}
+
+// decodeRequests decodes a batch of requests one by one, failing as soon as the
+// number of requests exceeds the limit. Zero means no limit
+func (d *requestDecoder) decodeRequests(limit uint) ([]batchItem, error) {
+	dec := json.NewDecoder(d.reader)
+
+	if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+		return nil, decodeError(err, errInvalidJSONArrayRequest)
+	}
+
+	requests := []batchItem{}
+	for dec.More() {
+		if limit > 0 && len(requests) >= int(limit) {
+			return nil, types.ErrBatchRequestsLimitExceeded
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, decodeError(err, errInvalidJSONArrayRequest)
+		}
+		var item batchItem
+		if err := json.Unmarshal(raw, &item.Request); err != nil {
+			item.Request = types.Request{JSONRPC: "2.0"}
+			item.err = types.NewRPCError(types.InvalidRequestErrorCode, "Invalid request")
+		}
+		requests = append(requests, item)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, decodeError(err, errInvalidJSONArrayRequest)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, decodeError(err, errInvalidJSONArrayRequest)
+	}
+
+	return requests, nil
+}
+
+// decodeError keeps the error returned when the body exceeds the max content
+// length and replaces any other decoding error by the provided one
+func decodeError(err error, invalidErr error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return err
+	}
+	return invalidErr
+}
+
+// decodeErrorStatusCode returns the HTTP status code for an error returned by the request decoder
+func decodeErrorStatusCode(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || errors.Is(err, types.ErrBatchRequestsLimitExceeded) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+func isWhiteSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// countParams counts the params of a request without decoding their values,
+// it returns false if the params are not a json array
+func countParams(params json.RawMessage) (int, bool) {
+	dec := json.NewDecoder(bytes.NewReader(params))
+	if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+		return 0, false
+	}
+
+	count := 0
+	for dec.More() {
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return 0, false
+		}
+		count++
+	}
+	return count, true
+}
+
+// checkParamsSize walks the params of a request as a stream of tokens and fails
+// if any string value, like the hex encoded data or the items of an array, is
+// bigger than the limit, so the oversized fields are rejected before being decoded
+// into the params of the method. The object keys are not checked
+func checkParamsSize(params json.RawMessage, limit uint64) error {
+	// inObject tracks if each of the nested values being walked is an object,
+	// whose tokens alternate between a key and its value
+	inObject := []bool{}
+	nextIsKey := false
+	dec := json.NewDecoder(bytes.NewReader(params))
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			// io.EOF once all the params are checked, any other error
+			// is reported as invalid params when decoding them
+			return nil
+		}
+
+		if nextIsKey {
+			if _, isKey := t.(string); isKey {
+				nextIsKey = false
+				continue
+			}
+		}
+
+		switch v := t.(type) {
+		case json.Delim:
+			if v == '{' || v == '[' {
+				inObject = append(inObject, v == '{')
+				nextIsKey = v == '{'
+				continue
+			}
+			inObject = inObject[:len(inObject)-1]
+		case string:
+			if uint64(len(v)) > limit {
+				return fmt.Errorf("param too large (%d>%d)", len(v), limit)
+			}
+		}
+		// a value has been walked, the next token of an object is a key
+		nextIsKey = len(inObject) > 0 && inObject[len(inObject)-1]
+	}
+}
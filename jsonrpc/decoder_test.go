@@ -0,0 +1,143 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestDecoder(t *testing.T) {
+	testCases := []struct {
+		name             string
+		body             string
+		maxContentLength int64
+		batchLimit       uint
+		expectedSingle   bool
+		expectedCount    int
+		expectedErr      error
+		expectedStatus   int
+	}{
+		{
+			name:             "single request",
+			body:             ` {"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`,
+			maxContentLength: 1024,
+			expectedSingle:   true,
+			expectedCount:    1,
+		},
+		{
+			name:             "single request with trailing data",
+			body:             `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]} {}`,
+			maxContentLength: 1024,
+			expectedSingle:   true,
+			expectedErr:      errInvalidJSONObjectRequest,
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "batch request",
+			body:             "\n[{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"eth_chainId\"},{\"jsonrpc\":\"2.0\",\"id\":2,\"method\":\"eth_chainId\"}]",
+			maxContentLength: 1024,
+			batchLimit:       2,
+			expectedCount:    2,
+		},
+		{
+			name:             "batch request exceeding the limit",
+			body:             `[{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},{"jsonrpc":"2.0","id":2,"method":"eth_chainId"}`,
+			maxContentLength: 1024,
+			batchLimit:       1,
+			expectedErr:      types.ErrBatchRequestsLimitExceeded,
+			expectedStatus:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:             "body bigger than the max content length",
+			body:             `{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0x` + strings.Repeat("0", 1024) + `"]}`,
+			maxContentLength: 128,
+			expectedSingle:   true,
+			expectedStatus:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:             "empty body",
+			body:             " \r\n\t",
+			maxContentLength: 1024,
+			expectedErr:      errEmptyRequestBody,
+			expectedStatus:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			decoder := newRequestDecoder(httptest.NewRecorder(), req.Body, tc.maxContentLength)
+
+			single, err := decoder.isSingleRequest()
+			if err == nil {
+				assert.Equal(t, tc.expectedSingle, single)
+				count := 0
+				if single {
+					_, err = decoder.decodeRequest()
+					count = 1
+				} else {
+					var requests []batchItem
+					requests, err = decoder.decodeRequests(tc.batchLimit)
+					count = len(requests)
+				}
+				if err == nil {
+					assert.Equal(t, tc.expectedCount, count)
+				}
+			}
+
+			if tc.expectedStatus == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			}
+			assert.Equal(t, tc.expectedStatus, decodeErrorStatusCode(err))
+		})
+	}
+}
+
+func TestDecodeBatchWithInvalidItems(t *testing.T) {
+	decoder := newMessageDecoder([]byte(`[{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}, 1, {"jsonrpc":"2.0","id":"2","method":"eth_blockNumber"}]`))
+
+	single, err := decoder.isSingleRequest()
+	require.NoError(t, err)
+	require.False(t, single)
+
+	requests, err := decoder.decodeRequests(0)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(requests))
+
+	assert.Nil(t, requests[0].err)
+	assert.Equal(t, "eth_chainId", requests[0].Method)
+	require.NotNil(t, requests[1].err)
+	assert.Equal(t, types.InvalidRequestErrorCode, requests[1].err.ErrorCode())
+	assert.Nil(t, requests[2].err)
+	assert.Equal(t, "2", requests[2].ID)
+}
+
+func TestCheckParamsSize(t *testing.T) {
+	params := json.RawMessage(`["0x1234", {"tracer": "` + strings.Repeat("a", 10) + `", "tracerConfig": {"onlyTopCall": true}}]`)
+
+	assert.NoError(t, checkParamsSize(params, 10))
+	assert.EqualError(t, checkParamsSize(params, 9), "param too large (10>9)")
+
+	// the object keys aren't checked, the items of the arrays are
+	nestedParams := json.RawMessage(`[{"` + strings.Repeat("a", 20) + `": ["0x12", {"key": "0x1234"}]}]`)
+	assert.NoError(t, checkParamsSize(nestedParams, 6))
+	assert.EqualError(t, checkParamsSize(nestedParams, 5), "param too large (6>5)")
+
+	count, ok := countParams(params)
+	assert.True(t, ok)
+	assert.Equal(t, 2, count)
+
+	_, ok = countParams(json.RawMessage(`{"tracer": "callTracer"}`))
+	assert.False(t, ok)
+}
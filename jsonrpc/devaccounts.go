@@ -0,0 +1,64 @@
+package jsonrpc
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// devAccounts holds the private keys loaded from the keystores configured
+// for the dev mode, they are used to sign the txs sent via eth_sendTransaction
+type devAccounts struct {
+	addresses []common.Address
+	keys      map[common.Address]*ecdsa.PrivateKey
+}
+
+// newDevAccounts decrypts all the keystores configured for the dev mode
+func newDevAccounts(cfg DevModeConfig) (*devAccounts, error) {
+	d := &devAccounts{
+		addresses: make([]common.Address, 0, len(cfg.Accounts)),
+		keys:      make(map[common.Address]*ecdsa.PrivateKey, len(cfg.Accounts)),
+	}
+
+	for _, account := range cfg.Accounts {
+		keystoreEncrypted, err := os.ReadFile(filepath.Clean(account.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dev account keystore %s: %w", account.Path, err)
+		}
+		key, err := keystore.DecryptKey(keystoreEncrypted, account.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt dev account keystore %s: %w", account.Path, err)
+		}
+		if _, found := d.keys[key.Address]; found {
+			continue
+		}
+		log.Warnf("dev mode account unlocked: %v", key.Address.String())
+		d.addresses = append(d.addresses, key.Address)
+		d.keys[key.Address] = key.PrivateKey
+	}
+
+	return d, nil
+}
+
+// contains returns true if the address belongs to an unlocked dev account
+func (d *devAccounts) contains(address common.Address) bool {
+	_, found := d.keys[address]
+	return found
+}
+
+// signTx signs the tx with the key of the provided dev account
+func (d *devAccounts) signTx(address common.Address, tx *ethTypes.Transaction, chainID uint64) (*ethTypes.Transaction, error) {
+	key, found := d.keys[address]
+	if !found {
+		return nil, fmt.Errorf("unknown account %v", address.String())
+	}
+	signer := ethTypes.LatestSignerForChainID(new(big.Int).SetUint64(chainID))
+	return ethTypes.SignTx(tx, signer, key)
+}
@@ -0,0 +1,277 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxAccInputHashVerificationRange is the max number of batches whose acc input hashes are compared in a single request
+const maxAccInputHashVerificationRange = 1000
+
+// AdminEndpoints contains implementations for the "admin" RPC endpoints,
+// they are intended to be used by the node operator and must not be exposed publicly
+type AdminEndpoints struct {
+	pool          types.PoolInterface
+	sequencer     types.SequencerInterface
+	aggregator    types.AggregatorInterface
+	executorDebug types.ExecutorDebugInterface
+	state         types.StateInterface
+	etherman      types.EthermanInterface
+	eventLog      *event.EventLog
+}
+
+// NewAdminEndpoints returns AdminEndpoints
+func NewAdminEndpoints(pool types.PoolInterface, sequencer types.SequencerInterface, aggregator types.AggregatorInterface, executorDebug types.ExecutorDebugInterface, state types.StateInterface, etherman types.EthermanInterface, eventLog *event.EventLog) *AdminEndpoints {
+	return &AdminEndpoints{
+		pool:          pool,
+		sequencer:     sequencer,
+		aggregator:    aggregator,
+		executorDebug: executorDebug,
+		state:         state,
+		etherman:      etherman,
+		eventLog:      eventLog,
+	}
+}
+
+type sequencerStatusResponse struct {
+	Halted      bool `json:"halted"`
+	Paused      bool `json:"paused"`
+	ReadyTxs    int  `json:"readyTxs"`
+	NotReadyTxs int  `json:"notReadyTxs"`
+}
+
+// SequencerStatus returns the status of the sequencer running in this node and the number
+// of txs held by its worker
+func (a *AdminEndpoints) SequencerStatus() (interface{}, types.Error) {
+	if a.sequencer == nil {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "sequencer is not running in this node")
+	}
+
+	res := sequencerStatusResponse{
+		Halted: a.sequencer.IsFinalizerHalted(),
+		Paused: a.sequencer.IsSequencingPaused(),
+	}
+	workerTxs, err := a.sequencer.GetWorkerTxs()
+	if err != nil {
+		log.Warnf("failed to get the worker txs: %v", err)
+		return nil, types.NewRPCError(types.DefaultErrorCode, "failed to get the worker txs: %v", err)
+	}
+	res.ReadyTxs = len(workerTxs.Ready)
+	res.NotReadyTxs = len(workerTxs.NotReady)
+
+	return res, nil
+}
+
+// ResumeSequencer resumes the sequencer after it has been halted by a critical error,
+// it must be called once the operator has reviewed the cause of the halt
+func (a *AdminEndpoints) ResumeSequencer() (interface{}, types.Error) {
+	if a.sequencer == nil {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "sequencer is not running in this node")
+	}
+
+	if err := a.sequencer.ResumeFinalizer(); err != nil {
+		log.Warnf("failed to resume the sequencer: %v", err)
+		return nil, types.NewRPCError(types.DefaultErrorCode, "failed to resume the sequencer: %v", err)
+	}
+
+	log.Info("sequencer resumed by admin request")
+	return true, nil
+}
+
+// PauseSequencing stops the sequencer from selecting txs for the batches until the sequencing
+// is resumed, the batches are still closed so the forced batches keep being processed
+func (a *AdminEndpoints) PauseSequencing() (interface{}, types.Error) {
+	if a.sequencer == nil {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "sequencer is not running in this node")
+	}
+
+	if err := a.sequencer.PauseSequencing(); err != nil {
+		log.Warnf("failed to pause the sequencing: %v", err)
+		return nil, types.NewRPCError(types.DefaultErrorCode, "failed to pause the sequencing: %v", err)
+	}
+
+	log.Info("sequencing paused by admin request")
+	return true, nil
+}
+
+// ResumeSequencing resumes the selection of txs for the batches after the sequencing was paused
+func (a *AdminEndpoints) ResumeSequencing() (interface{}, types.Error) {
+	if a.sequencer == nil {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "sequencer is not running in this node")
+	}
+
+	if err := a.sequencer.ResumeSequencing(); err != nil {
+		log.Warnf("failed to resume the sequencing: %v", err)
+		return nil, types.NewRPCError(types.DefaultErrorCode, "failed to resume the sequencing: %v", err)
+	}
+
+	log.Info("sequencing resumed by admin request")
+	return true, nil
+}
+
+// DropTransaction sets the pending tx as failed with the optional reason and removes it from
+// the sequencer worker, so it's never included in a batch
+func (a *AdminEndpoints) DropTransaction(hash types.ArgHash, reason *string) (interface{}, types.Error) {
+	from, err := a.pool.DropTx(context.Background(), hash.Hash(), stringOrEmpty(reason))
+	if errors.Is(err, pool.ErrNotFound) || errors.Is(err, pool.ErrTxNotPending) {
+		return nil, types.NewRPCError(types.InvalidParamsErrorCode, err.Error())
+	} else if err != nil {
+		log.Warnf("failed to drop the tx %s: %v", hash.Hash().String(), err)
+		return nil, types.NewRPCError(types.DefaultErrorCode, "failed to drop the tx: %v", err)
+	}
+	a.deleteWorkerTxs(from, hash.Hash())
+
+	log.Infof("tx %s dropped by admin request", hash.Hash().String())
+	return true, nil
+}
+
+// BanSender blocks the address, so the pool rejects its txs, and drops its pending txs. It
+// returns the hashes of the dropped txs
+func (a *AdminEndpoints) BanSender(address types.ArgAddress, reason *string) (interface{}, types.Error) {
+	dropped, err := a.pool.BanSender(context.Background(), address.Address(), stringOrEmpty(reason))
+	a.deleteWorkerTxs(address.Address(), dropped...)
+	if err != nil {
+		log.Warnf("failed to ban the sender %s: %v", address.Address().String(), err)
+		return nil, types.NewRPCError(types.DefaultErrorCode, "failed to ban the sender: %v", err)
+	}
+
+	log.Infof("sender %s banned by admin request", address.Address().String())
+	return dropped, nil
+}
+
+// UnbanSender unblocks the address, so the pool accepts its txs again
+func (a *AdminEndpoints) UnbanSender(address types.ArgAddress) (interface{}, types.Error) {
+	if err := a.pool.UnbanSender(context.Background(), address.Address()); err != nil {
+		log.Warnf("failed to unban the sender %s: %v", address.Address().String(), err)
+		return nil, types.NewRPCError(types.DefaultErrorCode, "failed to unban the sender: %v", err)
+	}
+
+	log.Infof("sender %s unbanned by admin request", address.Address().String())
+	return true, nil
+}
+
+// deleteWorkerTxs removes the dropped txs from the worker, it's only possible when the
+// sequencer runs in this node
+func (a *AdminEndpoints) deleteWorkerTxs(from common.Address, hashes ...common.Hash) {
+	if a.sequencer == nil {
+		return
+	}
+	for _, hash := range hashes {
+		if err := a.sequencer.DeleteWorkerTx(hash, from); err != nil {
+			log.Warnf("failed to delete the tx %s from the worker: %v", hash.String(), err)
+		}
+	}
+}
+
+// ReloadSequencerPolicy reloads the deny lists of the sequencer policy from the pool DB,
+// the txs held by the sequencer that are denied by the new policy are set as failed
+func (a *AdminEndpoints) ReloadSequencerPolicy() (interface{}, types.Error) {
+	if a.sequencer == nil {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "sequencer is not running in this node")
+	}
+
+	if err := a.sequencer.ReloadPolicy(context.Background()); err != nil {
+		log.Warnf("failed to reload the sequencer policy: %v", err)
+		return nil, types.NewRPCError(types.DefaultErrorCode, "failed to reload the sequencer policy: %v", err)
+	}
+
+	log.Info("sequencer policy reloaded by admin request")
+	return true, nil
+}
+
+// ProverVersions returns the identity and the versions negotiated with
+// the provers currently connected to the aggregator running in this node
+func (a *AdminEndpoints) ProverVersions() (interface{}, types.Error) {
+	if a.aggregator == nil {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "aggregator is not running in this node")
+	}
+
+	return a.aggregator.ConnectedProvers(), nil
+}
+
+// ExecutorDebugFlags returns the debug flags of the requests sent to the executor by each
+// origin: sequencer, synchronizer and rpc
+func (a *AdminEndpoints) ExecutorDebugFlags() (interface{}, types.Error) {
+	res := map[string]types.ExecutorDebugFlags{}
+	for origin, status := range a.executorDebug.GetExecutorDebugStatus() {
+		res[origin] = types.NewExecutorDebugFlags(status)
+	}
+	return res, nil
+}
+
+// SetExecutorDebugFlags overrides the debug flags of the requests sent to the executor by the
+// origin for the duration in seconds, the config flags are restored when it elapses or when the
+// duration is zero. Only the requests sent by this node are affected
+func (a *AdminEndpoints) SetExecutorDebugFlags(origin string, flags types.ExecutorDebugFlags, durationSeconds types.ArgUint64) (interface{}, types.Error) {
+	debugFlags := executor.DebugFlags{
+		ROMLogs:               flags.ROMLogs,
+		PersistFailedTxTraces: flags.PersistFailedTxTraces,
+	}
+	duration := time.Duration(durationSeconds) * time.Second
+	if err := a.executorDebug.SetExecutorDebugFlags(origin, debugFlags, duration); err != nil {
+		return nil, types.NewRPCError(types.InvalidParamsErrorCode, err.Error())
+	}
+
+	log.Infof("executor debug flags of the %s requests set by admin request", origin)
+	return true, nil
+}
+
+// VerifyAccInputHashes compares the accumulated input hash computed by this node for the last batch of
+// each sequence including any batch of the range with the one stored in L1 when it was sequenced. It
+// stops at the first divergence, which is returned, or at the first sequence not executed yet
+func (a *AdminEndpoints) VerifyAccInputHashes(fromBatchNumber, toBatchNumber types.ArgUint64) (interface{}, types.Error) {
+	if toBatchNumber < fromBatchNumber {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid batch range", nil, false)
+	}
+	if uint64(toBatchNumber-fromBatchNumber) >= maxAccInputHashVerificationRange {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("batch range exceeds the limit of %d batches", maxAccInputHashVerificationRange), nil, false)
+	}
+
+	l1AccInputHash := func(ctx context.Context, batchNumber uint64) (common.Hash, error) {
+		info, err := a.etherman.GetSequencedBatchesInfo(ctx, batchNumber)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return info.AccInputHash, nil
+	}
+	lastComparedBatchNumber, divergence, err := a.state.CompareAccInputHashes(context.Background(), uint64(fromBatchNumber), uint64(toBatchNumber), l1AccInputHash, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "couldn't compare the acc input hashes with L1", err, true)
+	}
+	if divergence != nil {
+		description := fmt.Sprintf("acc input hash of batch %d diverges from L1, local: %s, L1: %s", divergence.BatchNumber, divergence.AccInputHash, divergence.L1AccInputHash)
+		log.Error(description)
+		if a.eventLog != nil {
+			ev := &event.Event{
+				ReceivedAt:  time.Now(),
+				Source:      event.Source_Node,
+				Component:   event.Component_RPC,
+				Level:       event.Level_Critical,
+				EventID:     event.EventID_AccInputHashMismatch,
+				Description: description,
+			}
+			if err := a.eventLog.LogEvent(context.Background(), ev); err != nil {
+				log.Errorf("error storing acc input hash mismatch event: %v", err)
+			}
+		}
+	}
+
+	return types.NewAccInputHashVerification(lastComparedBatchNumber, divergence), nil
+}
+
+// stringOrEmpty returns the value of the optional string param or an empty string if it's missing
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
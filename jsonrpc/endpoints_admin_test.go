@@ -0,0 +1,186 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdminHandler(t *testing.T, token string) (*Handler, *mocks.PoolMock, *sequencerWorkerMock) {
+	poolMock := mocks.NewPoolMock(t)
+	seq := &sequencerWorkerMock{}
+	h := newJSONRpcHandler()
+	h.adminAuthToken = token
+	h.registerService(Service{Name: APIAdmin, Service: NewAdminEndpoints(poolMock, seq, nil, nil, nil, nil, nil)})
+	return h, poolMock, seq
+}
+
+func newAdminRequest(t *testing.T, method, params, authorization string) handleRequest {
+	httpReq, err := http.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, err)
+	if authorization != "" {
+		httpReq.Header.Set("Authorization", authorization)
+	}
+	return handleRequest{Request: types.Request{JSONRPC: "2.0", Method: method, Params: json.RawMessage(params)}, HttpRequest: httpReq}
+}
+
+func TestAdminAuthToken(t *testing.T) {
+	h, _, _ := newAdminHandler(t, "secret")
+
+	for _, authorization := range []string{"", "Bearer wrong", "secret"} {
+		res := h.Handle(newAdminRequest(t, "admin_pauseSequencing", `[]`, authorization))
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.UnauthorizedErrorCode, res.Error.Code)
+		assert.Equal(t, "unauthorized", res.Error.Message)
+	}
+
+	res := h.Handle(handleRequest{Request: types.Request{JSONRPC: "2.0", Method: "admin_pauseSequencing", Params: json.RawMessage(`[]`)}})
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.UnauthorizedErrorCode, res.Error.Code)
+
+	res = h.Handle(newAdminRequest(t, "admin_pauseSequencing", `[]`, "Bearer secret"))
+	require.Nil(t, res.Error)
+	assert.Equal(t, "true", string(res.Result))
+}
+
+func TestAdminWithoutAuthToken(t *testing.T) {
+	h, _, _ := newAdminHandler(t, "")
+
+	// without a token the admin namespace is only served through the ipc socket
+	for _, authorization := range []string{"", "Bearer "} {
+		res := h.Handle(newAdminRequest(t, "admin_pauseSequencing", `[]`, authorization))
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.UnauthorizedErrorCode, res.Error.Code)
+	}
+
+	res := h.Handle(handleRequest{Request: types.Request{JSONRPC: "2.0", Method: "admin_pauseSequencing", Params: json.RawMessage(`[]`)}, HttpRequest: newIPCRequest()})
+	require.Nil(t, res.Error)
+	assert.Equal(t, "true", string(res.Result))
+}
+
+func TestAdminNotRegisteredWithoutAuthTokenNorIPC(t *testing.T) {
+	services := []Service{{Name: APIAdmin, Service: NewAdminEndpoints(nil, nil, nil, nil, nil, nil, nil)}}
+
+	s := NewServer(Config{}, chainID, nil, nil, nil, nil, services)
+	_, found := s.handler.serviceMap[APIAdmin]
+	assert.False(t, found)
+
+	s = NewServer(Config{IPC: IPCConfig{Enabled: true}}, chainID, nil, nil, nil, nil, services)
+	_, found = s.handler.serviceMap[APIAdmin]
+	assert.True(t, found)
+}
+
+func TestAdminSequencing(t *testing.T) {
+	h, _, seq := newAdminHandler(t, "secret")
+	seq.txs = sequencer.TxsSnapshot{
+		Ready:    map[common.Hash]struct{}{common.HexToHash("0x1"): {}},
+		NotReady: map[common.Hash]struct{}{common.HexToHash("0x2"): {}, common.HexToHash("0x3"): {}},
+	}
+
+	res := h.Handle(newAdminRequest(t, "admin_pauseSequencing", `[]`, "Bearer secret"))
+	require.Nil(t, res.Error)
+	res = h.Handle(newAdminRequest(t, "admin_pauseSequencing", `[]`, "Bearer secret"))
+	require.NotNil(t, res.Error)
+	assert.Equal(t, "failed to pause the sequencing: sequencing is already paused", res.Error.Message)
+
+	res = h.Handle(newAdminRequest(t, "admin_sequencerStatus", `[]`, "Bearer secret"))
+	require.Nil(t, res.Error)
+	assert.JSONEq(t, `{"halted":false,"paused":true,"readyTxs":1,"notReadyTxs":2}`, string(res.Result))
+
+	res = h.Handle(newAdminRequest(t, "admin_resumeSequencing", `[]`, "Bearer secret"))
+	require.Nil(t, res.Error)
+	assert.False(t, seq.paused)
+}
+
+func TestAdminDropTransaction(t *testing.T) {
+	h, poolMock, seq := newAdminHandler(t, "secret")
+	hash := common.HexToHash("0x1")
+	from := common.HexToAddress("0x2")
+
+	poolMock.On("DropTx", context.Background(), hash, "spam").Return(from, nil).Once()
+	res := h.Handle(newAdminRequest(t, "admin_dropTransaction", `["`+hash.String()+`","spam"]`, "Bearer secret"))
+	require.Nil(t, res.Error)
+	assert.Equal(t, []common.Hash{hash}, seq.deleted)
+
+	poolMock.On("DropTx", context.Background(), hash, "").Return(common.Address{}, pool.ErrTxNotPending).Once()
+	res = h.Handle(newAdminRequest(t, "admin_dropTransaction", `["`+hash.String()+`"]`, "Bearer secret"))
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+	assert.Equal(t, pool.ErrTxNotPending.Error(), res.Error.Message)
+}
+
+func TestAdminBanSender(t *testing.T) {
+	h, poolMock, seq := newAdminHandler(t, "secret")
+	sender := common.HexToAddress("0x2")
+	dropped := []common.Hash{common.HexToHash("0x3"), common.HexToHash("0x4")}
+
+	poolMock.On("BanSender", context.Background(), sender, "").Return(dropped, nil).Once()
+	res := h.Handle(newAdminRequest(t, "admin_banSender", `["`+sender.String()+`"]`, "Bearer secret"))
+	require.Nil(t, res.Error)
+	var hashes []common.Hash
+	require.NoError(t, json.Unmarshal(res.Result, &hashes))
+	assert.Equal(t, dropped, hashes)
+	assert.Equal(t, dropped, seq.deleted)
+
+	poolMock.On("UnbanSender", context.Background(), sender).Return(nil).Once()
+	res = h.Handle(newAdminRequest(t, "admin_unbanSender", `["`+sender.String()+`"]`, "Bearer secret"))
+	require.Nil(t, res.Error)
+}
+
+func TestAdminVerifyAccInputHashes(t *testing.T) {
+	stateMock := mocks.NewStateMock(t)
+	ethermanMock := mocks.NewEthermanMock(t)
+	h := newJSONRpcHandler()
+	h.adminAuthToken = "secret"
+	events := &eventStorageMock{}
+	h.registerService(Service{Name: APIAdmin, Service: NewAdminEndpoints(nil, nil, nil, nil, stateMock, ethermanMock, event.NewEventLog(event.Config{}, events))})
+
+	l1AccInputHash := common.HexToHash("0x1")
+	ethermanMock.On("GetSequencedBatchesInfo", context.Background(), uint64(5)).Return(ethmanTypes.SequencedBatchesInfo{AccInputHash: l1AccInputHash}, nil).Once()
+	divergence := &state.AccInputHashDivergence{BatchNumber: 8, AccInputHash: common.HexToHash("0x2"), L1AccInputHash: common.HexToHash("0x3")}
+	stateMock.On("CompareAccInputHashes", context.Background(), uint64(1), uint64(10), mock.Anything, nil).
+		Run(func(args mock.Arguments) {
+			l1AccInputHashFn := args.Get(3).(func(context.Context, uint64) (common.Hash, error))
+			hash, err := l1AccInputHashFn(context.Background(), 5)
+			require.NoError(t, err)
+			assert.Equal(t, l1AccInputHash, hash)
+		}).
+		Return(uint64(5), divergence, nil).Once()
+
+	res := h.Handle(newAdminRequest(t, "admin_verifyAccInputHashes", `["0x1","0xa"]`, "Bearer secret"))
+	require.Nil(t, res.Error)
+	var result types.AccInputHashVerification
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.NewAccInputHashVerification(5, divergence), result)
+	require.Len(t, events.events, 1)
+	assert.Equal(t, event.EventID_AccInputHashMismatch, events.events[0].EventID)
+	assert.Equal(t, event.Level_Critical, events.events[0].Level)
+
+	res = h.Handle(newAdminRequest(t, "admin_verifyAccInputHashes", `["0xa","0x1"]`, "Bearer secret"))
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+	assert.Equal(t, "invalid batch range", res.Error.Message)
+}
+
+// eventStorageMock keeps the logged events in memory
+type eventStorageMock struct {
+	events []*event.Event
+}
+
+func (s *eventStorageMock) LogEvent(ctx context.Context, ev *event.Event) error {
+	s.events = append(s.events, ev)
+	return nil
+}
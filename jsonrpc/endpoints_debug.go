@@ -36,15 +36,25 @@ type DebugEndpoints struct {
 	state    types.StateInterface
 	etherman types.EthermanInterface
 	txMan    DBTxManager
+	// traces is the queue of the traces, nil when it's disabled
+	traces *traceQueue
 }
 
 // NewDebugEndpoints returns DebugEndpoints
 func NewDebugEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *DebugEndpoints {
-	return &DebugEndpoints{
+	d := &DebugEndpoints{
 		cfg:      cfg,
 		state:    state,
 		etherman: etherman,
 	}
+	if cfg.TraceQueue.Enabled {
+		traces, err := newTraceQueue(cfg.TraceQueue)
+		if err != nil {
+			log.Fatalf("invalid trace queue configuration: %v", err)
+		}
+		d.traces = traces
+	}
+	return d
 }
 
 type traceConfig struct {
@@ -78,6 +88,7 @@ type traceTransactionResponse struct {
 }
 
 type traceBlockTransactionResponse struct {
+	TxHash common.Hash `json:"txHash"`
 	Result interface{} `json:"result"`
 }
 
@@ -89,14 +100,91 @@ type traceBatchTransactionResponse struct {
 // TraceTransaction creates a response for debug_traceTransaction request.
 // See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtracetransaction
 func (d *DebugEndpoints) TraceTransaction(hash types.ArgHash, cfg *traceConfig) (interface{}, types.Error) {
-	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
-		return d.buildTraceTransaction(ctx, hash.Hash(), cfg, dbTx)
+	return d.runTrace(func() (interface{}, types.Error) {
+		return d.traceTransaction(hash, cfg)
 	})
 }
 
 // TraceBlockByNumber creates a response for debug_traceBlockByNumber request.
 // See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtraceblockbynumber
 func (d *DebugEndpoints) TraceBlockByNumber(number types.BlockNumber, cfg *traceConfig) (interface{}, types.Error) {
+	return d.runTrace(func() (interface{}, types.Error) {
+		return d.traceBlockByNumber(number, cfg)
+	})
+}
+
+// TraceBlockByHash creates a response for debug_traceBlockByHash request.
+// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtraceblockbyhash
+func (d *DebugEndpoints) TraceBlockByHash(hash types.ArgHash, cfg *traceConfig) (interface{}, types.Error) {
+	return d.runTrace(func() (interface{}, types.Error) {
+		return d.traceBlockByHash(hash, cfg)
+	})
+}
+
+// SubmitTraceTransaction submits a debug_traceTransaction request to be run in background,
+// returning the id of the job to poll its result with debug_getTraceJob
+func (d *DebugEndpoints) SubmitTraceTransaction(hash types.ArgHash, cfg *traceConfig) (interface{}, types.Error) {
+	return d.submitTrace("debug_traceTransaction", func() (interface{}, types.Error) {
+		return d.traceTransaction(hash, cfg)
+	})
+}
+
+// SubmitTraceBlockByNumber submits a debug_traceBlockByNumber request to be run in background,
+// returning the id of the job to poll its result with debug_getTraceJob
+func (d *DebugEndpoints) SubmitTraceBlockByNumber(number types.BlockNumber, cfg *traceConfig) (interface{}, types.Error) {
+	return d.submitTrace("debug_traceBlockByNumber", func() (interface{}, types.Error) {
+		return d.traceBlockByNumber(number, cfg)
+	})
+}
+
+// SubmitTraceBlockByHash submits a debug_traceBlockByHash request to be run in background,
+// returning the id of the job to poll its result with debug_getTraceJob
+func (d *DebugEndpoints) SubmitTraceBlockByHash(hash types.ArgHash, cfg *traceConfig) (interface{}, types.Error) {
+	return d.submitTrace("debug_traceBlockByHash", func() (interface{}, types.Error) {
+		return d.traceBlockByHash(hash, cfg)
+	})
+}
+
+// GetTraceJob returns the status of a trace job submitted with the debug_submitTrace* methods
+// along with its result once it's done, the results are kept during the configured retention
+func (d *DebugEndpoints) GetTraceJob(id string) (interface{}, types.Error) {
+	if d.traces == nil || !d.cfg.TraceQueue.AsyncEnabled {
+		return nil, types.NewRPCError(types.MethodNotSupportedErrorCode, "async traces are disabled")
+	}
+	job, found := d.traces.job(id)
+	if !found {
+		return nil, types.NewRPCError(types.NotFoundErrorCode, fmt.Sprintf("trace job %s not found", id))
+	}
+	return job, nil
+}
+
+// runTrace runs the trace through the trace queue when it's enabled
+func (d *DebugEndpoints) runTrace(fn traceFn) (interface{}, types.Error) {
+	if d.traces == nil {
+		return fn()
+	}
+	return d.traces.run(fn)
+}
+
+// submitTrace submits the trace to the trace queue to be run in background
+func (d *DebugEndpoints) submitTrace(method string, fn traceFn) (interface{}, types.Error) {
+	if d.traces == nil || !d.cfg.TraceQueue.AsyncEnabled {
+		return nil, types.NewRPCError(types.MethodNotSupportedErrorCode, "async traces are disabled")
+	}
+	id, err := d.traces.submit(method, fn)
+	if err != nil {
+		return nil, types.NewRPCError(types.LimitExceededErrorCode, err.Error())
+	}
+	return id, nil
+}
+
+func (d *DebugEndpoints) traceTransaction(hash types.ArgHash, cfg *traceConfig) (interface{}, types.Error) {
+	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		return d.buildTraceTransaction(ctx, hash.Hash(), cfg, dbTx)
+	})
+}
+
+func (d *DebugEndpoints) traceBlockByNumber(number types.BlockNumber, cfg *traceConfig) (interface{}, types.Error) {
 	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, d.state, d.etherman, dbTx)
 		if rpcErr != nil {
@@ -119,9 +207,7 @@ func (d *DebugEndpoints) TraceBlockByNumber(number types.BlockNumber, cfg *trace
 	})
 }
 
-// TraceBlockByHash creates a response for debug_traceBlockByHash request.
-// See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtraceblockbyhash
-func (d *DebugEndpoints) TraceBlockByHash(hash types.ArgHash, cfg *traceConfig) (interface{}, types.Error) {
+func (d *DebugEndpoints) traceBlockByHash(hash types.ArgHash, cfg *traceConfig) (interface{}, types.Error) {
 	return d.txMan.NewDbTxScope(d.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		block, err := d.state.GetL2BlockByHash(ctx, hash.Hash(), dbTx)
 		if errors.Is(err, state.ErrNotFound) {
@@ -141,7 +227,8 @@ func (d *DebugEndpoints) TraceBlockByHash(hash types.ArgHash, cfg *traceConfig)
 
 // TraceBatchByNumber creates a response for debug_traceBatchByNumber request.
 // this endpoint tries to help clients to get traces at once for all the transactions
-// attached to the same batch.
+// attached to the same batch. It isn't run through the trace queue, the trace of each
+// transaction is.
 //
 // IMPORTANT: in order to take advantage of the infrastructure automatically scaling,
 // instead of parallelizing the trace transaction internally and pushing all the load
@@ -287,6 +374,7 @@ func (d *DebugEndpoints) buildTraceBlock(ctx context.Context, txs []*ethTypes.Tr
 			return RPCErrorResponse(types.DefaultErrorCode, errMsg, err, true)
 		}
 		traceBlockTransaction := traceBlockTransactionResponse{
+			TxHash: tx.Hash(),
 			Result: traceTransaction,
 		}
 		traces = append(traces, traceBlockTransaction)
@@ -438,7 +526,7 @@ func (d *DebugEndpoints) buildStructLogs(stateStructLogs []instrumentation.Struc
 func isBuiltInTracer(tracer string) bool {
 	// built-in tracers
 	switch tracer {
-	case "callTracer", "4byteTracer", "prestateTracer", "noopTracer":
+	case "callTracer", "flatCallTracer", "muxTracer", "4byteTracer", "prestateTracer", "noopTracer", "zkevmCounters":
 		return true
 	default:
 		return false
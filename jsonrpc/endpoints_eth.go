@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -19,8 +20,10 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/websocket"
 	"github.com/jackc/pgx/v4"
 )
@@ -30,6 +33,13 @@ const (
 	// to communicate with the state for eth_EstimateGas and eth_Call when
 	// the From field is not specified because it is optional
 	DefaultSenderAddress = "0x1111111111111111111111111111111111111111"
+
+	// maxFeeHistoryRewardPercentiles is the max number of reward percentiles accepted by eth_feeHistory
+	maxFeeHistoryRewardPercentiles = 100
+
+	// rlpStringPrefix and rlpListPrefix are the first bytes of the RLP encoded strings and lists
+	rlpStringPrefix = 0x80
+	rlpListPrefix   = 0xc0
 )
 
 // EthEndpoints contains implementations for the "eth" RPC endpoints
@@ -41,6 +51,18 @@ type EthEndpoints struct {
 	etherman types.EthermanInterface
 	storage  storageInterface
 	txMan    DBTxManager
+
+	droppedTxsNotifierOnce sync.Once
+	pendingTxsNotifierOnce sync.Once
+
+	// batchStatusEnabled is set when the batch status transitions are notified by the event broker
+	batchStatusEnabled atomic.Bool
+	// selectedTxsEnabled is set when the txs selected by the sequencer running in the same
+	// process are notified by the event broker
+	selectedTxsEnabled atomic.Bool
+
+	// devAccounts is only set when the dev mode is enabled
+	devAccounts *devAccounts
 }
 
 // NewEthEndpoints creates an new instance of Eth
@@ -48,9 +70,27 @@ func NewEthEndpoints(cfg Config, chainID uint64, p types.PoolInterface, s types.
 	e := &EthEndpoints{cfg: cfg, chainID: chainID, pool: p, state: s, etherman: etherman, storage: storage}
 	s.RegisterNewL2BlockEventHandler(e.onNewL2Block)
 
+	if cfg.DevMode.Enabled {
+		log.Warn("RPC dev mode is enabled, the configured accounts are unlocked and must never be used outside local testing")
+		accounts, err := newDevAccounts(cfg.DevMode)
+		if err != nil {
+			log.Fatalf("failed to load dev mode accounts: %v", err)
+		}
+		e.devAccounts = accounts
+	}
+
 	return e
 }
 
+// Accounts returns the addresses of the accounts unlocked by the node,
+// they are only available when the dev mode is enabled
+func (e *EthEndpoints) Accounts() (interface{}, types.Error) {
+	if e.devAccounts == nil {
+		return []common.Address{}, nil
+	}
+	return e.devAccounts.addresses, nil
+}
+
 // BlockNumber returns current block number
 func (e *EthEndpoints) BlockNumber() (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -67,13 +107,17 @@ func (e *EthEndpoints) BlockNumber() (interface{}, types.Error) {
 // executed contract and potential error.
 // Note, this function doesn't make any changes in the state/blockchain and is
 // useful to execute view/pure methods and retrieve values.
-func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, stateOverrideArg *types.StateOverride) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		if arg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
 		} else if blockArg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 1", nil, false)
 		}
+		stateOverride, err := stateOverrideArg.ToStateOverride()
+		if err != nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
+		}
 		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
 		if respErr != nil {
 			return nil, respErr
@@ -89,38 +133,44 @@ func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash
 			}
 		}
 
-		// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
-		if arg.Gas == nil || uint64(*arg.Gas) <= 0 {
-			header, err := e.state.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), dbTx)
-			if err != nil {
-				return RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
-			}
-
-			gas := types.ArgUint64(header.GasLimit)
-			arg.Gas = &gas
-		}
+		return e.call(ctx, arg, block, blockToProcess, stateOverride, dbTx)
+	})
+}
 
-		defaultSenderAddress := common.HexToAddress(DefaultSenderAddress)
-		sender, tx, err := arg.ToTransaction(ctx, e.state, e.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
+// call executes the message call on top of the state of the block, the block to process is
+// nil to execute it on top of the latest block
+func (e *EthEndpoints) call(ctx context.Context, arg *types.TxArgs, block *ethTypes.Block, blockToProcess *uint64, stateOverride state.StateOverride, dbTx pgx.Tx) (interface{}, types.Error) {
+	// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
+	if arg.Gas == nil || uint64(*arg.Gas) <= 0 {
+		header, err := e.state.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), dbTx)
 		if err != nil {
-			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
 		}
 
-		result, err := e.state.ProcessUnsignedTransaction(ctx, tx, sender, blockToProcess, true, dbTx)
-		if err != nil {
-			return RPCErrorResponse(types.DefaultErrorCode, "failed to execute the unsigned transaction", err, true)
-		}
+		gas := types.ArgUint64(header.GasLimit)
+		arg.Gas = &gas
+	}
 
-		if result.Reverted() {
-			data := make([]byte, len(result.ReturnValue))
-			copy(data, result.ReturnValue)
-			return nil, types.NewRPCErrorWithData(types.RevertedErrorCode, result.Err.Error(), &data)
-		} else if result.Failed() {
-			return nil, types.NewRPCErrorWithData(types.DefaultErrorCode, result.Err.Error(), nil)
-		}
+	defaultSenderAddress := common.HexToAddress(DefaultSenderAddress)
+	sender, tx, err := arg.ToTransaction(ctx, e.state, e.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+	}
 
-		return types.ArgBytesPtr(result.ReturnValue), nil
-	})
+	result, err := e.state.ProcessUnsignedTransaction(ctx, tx, sender, blockToProcess, true, stateOverride, dbTx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to execute the unsigned transaction", err, true)
+	}
+
+	if result.Reverted() {
+		data := make([]byte, len(result.ReturnValue))
+		copy(data, result.ReturnValue)
+		return nil, types.NewRPCErrorWithData(types.RevertedErrorCode, result.Err.Error(), &data)
+	} else if result.Failed() {
+		return nil, types.NewRPCErrorWithData(types.DefaultErrorCode, result.Err.Error(), nil)
+	}
+
+	return types.ArgBytesPtr(result.ReturnValue), nil
 }
 
 // ChainId returns the chain id of the client
@@ -160,11 +210,15 @@ func (e *EthEndpoints) getCoinbaseFromSequencerNode() (interface{}, types.Error)
 // Note that the estimate may be significantly more than the amount of gas actually
 // used by the transaction, for a variety of reasons including EVM mechanics and
 // node performance.
-func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumberOrHash, stateOverrideArg *types.StateOverride) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		if arg == nil {
 			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
 		}
+		stateOverride, err := stateOverrideArg.ToStateOverride()
+		if err != nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
+		}
 
 		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
 		if respErr != nil {
@@ -188,11 +242,13 @@ func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumbe
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
 		}
 
-		gasEstimation, returnValue, err := e.state.EstimateGas(tx, sender, blockToProcess, dbTx)
+		gasEstimation, returnValue, err := e.state.EstimateGas(tx, sender, blockToProcess, stateOverride, dbTx)
 		if errors.Is(err, runtime.ErrExecutionReverted) {
 			data := make([]byte, len(returnValue))
 			copy(data, returnValue)
 			return nil, types.NewRPCErrorWithData(types.RevertedErrorCode, err.Error(), &data)
+		} else if errors.Is(err, executor.ErrExecutorUnavailable) {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to estimate gas", err, true)
 		} else if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, err.Error(), nil, true)
 		}
@@ -200,7 +256,8 @@ func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumbe
 	})
 }
 
-// GasPrice returns the average gas price based on the last x blocks
+// GasPrice returns the average gas price based on the last x blocks, never
+// suggesting a gas price lower than the minimum the pool is accepting
 func (e *EthEndpoints) GasPrice() (interface{}, types.Error) {
 	ctx := context.Background()
 	if e.cfg.SequencerNodeURI != "" {
@@ -210,7 +267,11 @@ func (e *EthEndpoints) GasPrice() (interface{}, types.Error) {
 	if err != nil {
 		return "0x0", nil
 	}
-	return hex.EncodeUint64(gasPrices.L2GasPrice), nil
+	gasPrice := new(big.Int).SetUint64(gasPrices.L2GasPrice)
+	if minGasPrice := e.pool.GetMinSuggestedGasPrice(); minGasPrice != nil && gasPrice.Cmp(minGasPrice) < 0 {
+		gasPrice = minGasPrice
+	}
+	return hex.EncodeBig(gasPrice), nil
 }
 
 func (e *EthEndpoints) getPriceFromSequencerNode() (interface{}, types.Error) {
@@ -231,6 +292,133 @@ func (e *EthEndpoints) getPriceFromSequencerNode() (interface{}, types.Error) {
 	return gasPrice, nil
 }
 
+// FeeHistory returns the base fee, the gas used ratio and the reward percentiles of
+// a range of blocks ending at newestBlock. The rewards are the effective gas prices
+// paid by the txs of each block, weighted by the gas they used
+func (e *EthEndpoints) FeeHistory(blockCount types.ArgUint64, newestBlock types.BlockNumber, rewardPercentiles []float64) (interface{}, types.Error) {
+	if len(rewardPercentiles) > maxFeeHistoryRewardPercentiles {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("too many reward percentiles, max is %d", maxFeeHistoryRewardPercentiles), nil, false)
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 || (i > 0 && p < rewardPercentiles[i-1]) {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("invalid reward percentile %v, they must be ascending and between 0 and 100", p), nil, false)
+		}
+	}
+
+	count := uint64(blockCount)
+	if e.cfg.MaxFeeHistoryBlockCount > 0 && count > e.cfg.MaxFeeHistoryBlockCount {
+		count = e.cfg.MaxFeeHistoryBlockCount
+	}
+	if count == 0 {
+		return types.FeeHistory{GasUsedRatio: []float64{}}, nil
+	}
+
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		newest, rpcErr := newestBlock.GetNumericBlockNumber(ctx, e.state, e.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		if count > newest+1 {
+			count = newest + 1
+		}
+		oldest := newest + 1 - count
+
+		history := types.FeeHistory{
+			OldestBlock:  types.ArgUint64(oldest),
+			BaseFee:      make([]types.ArgBig, 0, count+1),
+			GasUsedRatio: make([]float64, 0, count),
+		}
+		if len(rewardPercentiles) > 0 {
+			history.Reward = make([][]types.ArgBig, 0, count)
+		}
+
+		baseFee := big.NewInt(0)
+		for number := oldest; number <= newest; number++ {
+			block, err := e.state.GetL2BlockByNumber(ctx, number, dbTx)
+			if errors.Is(err, state.ErrNotFound) {
+				return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("block %d not found, the requested range is beyond the head block", number), nil, false)
+			} else if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block from state by number %v", number), err, true)
+			}
+
+			baseFee = big.NewInt(0)
+			if block.BaseFee() != nil {
+				baseFee = block.BaseFee()
+			}
+			history.BaseFee = append(history.BaseFee, types.ArgBig(*baseFee))
+
+			gasUsedRatio := float64(0)
+			if block.GasLimit() > 0 {
+				gasUsedRatio = float64(block.GasUsed()) / float64(block.GasLimit())
+			}
+			history.GasUsedRatio = append(history.GasUsedRatio, gasUsedRatio)
+
+			if len(rewardPercentiles) > 0 {
+				rewards, rpcErr := e.getBlockRewards(ctx, block, baseFee, rewardPercentiles, dbTx)
+				if rpcErr != nil {
+					return nil, rpcErr
+				}
+				history.Reward = append(history.Reward, rewards)
+			}
+		}
+		// the L2 has no base fee market, so the next block has the same base fee as the newest one
+		history.BaseFee = append(history.BaseFee, types.ArgBig(*baseFee))
+
+		return history, nil
+	})
+}
+
+type txReward struct {
+	gasUsed uint64
+	reward  *big.Int
+}
+
+// getBlockRewards returns the rewards paid by the txs of the block at each percentile
+// of the gas used, the reward of a tx is its effective gas price above the base fee
+func (e *EthEndpoints) getBlockRewards(ctx context.Context, block *ethTypes.Block, baseFee *big.Int, percentiles []float64, dbTx pgx.Tx) ([]types.ArgBig, types.Error) {
+	rewards := make([]types.ArgBig, len(percentiles))
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		for i := range rewards {
+			rewards[i] = types.ArgBig(*big.NewInt(0))
+		}
+		return rewards, nil
+	}
+
+	sorted := make([]txReward, 0, len(txs))
+	for _, tx := range txs {
+		receipt, err := e.state.GetTransactionReceipt(ctx, tx.Hash(), dbTx)
+		if err != nil {
+			_, rpcErr := RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipt for tx %v", tx.Hash().String()), err, true)
+			return nil, rpcErr
+		}
+		gasPrice := tx.GasPrice()
+		if receipt.EffectiveGasPrice != nil {
+			gasPrice = receipt.EffectiveGasPrice
+		}
+		reward := new(big.Int).Sub(gasPrice, baseFee)
+		if reward.Sign() < 0 {
+			reward.SetInt64(0)
+		}
+		sorted = append(sorted, txReward{gasUsed: receipt.GasUsed, reward: reward})
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].reward.Cmp(sorted[j].reward) < 0
+	})
+
+	txIndex := 0
+	sumGasUsed := sorted[0].gasUsed
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(block.GasUsed()) * p / 100) //nolint:gomnd
+		for sumGasUsed < thresholdGasUsed && txIndex < len(sorted)-1 {
+			txIndex++
+			sumGasUsed += sorted[txIndex].gasUsed
+		}
+		rewards[i] = types.ArgBig(*sorted[txIndex].reward)
+	}
+	return rewards, nil
+}
+
 // GetBalance returns the account's balance at the referenced block
 func (e *EthEndpoints) GetBalance(address types.ArgAddress, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -268,7 +456,7 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 		} else if err != nil {
 			return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("failed to get block by hash %v", blockArg.Hash().Hash()))
 		}
-		return block, nil
+		return e.checkBlockStateIsAvailable(ctx, block, dbTx)
 	}
 
 	// Otherwise, try to get the block by number
@@ -283,9 +471,62 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 		return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("failed to get block by number %v", blockNum))
 	}
 
+	return e.checkBlockStateIsAvailable(ctx, block, dbTx)
+}
+
+// getStateRootByArg returns the state root of the block referenced by the argument, the roots of
+// the blocks referenced by number are read from the state root index without loading the block
+func (e *EthEndpoints) getStateRootByArg(ctx context.Context, blockArg *types.BlockNumberOrHash, dbTx pgx.Tx) (common.Hash, types.Error) {
+	if blockArg == nil || blockArg.IsHash() {
+		block, rpcErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		if rpcErr != nil {
+			return common.Hash{}, rpcErr
+		}
+		return block.Root(), nil
+	}
+
+	blockNum, rpcErr := blockArg.Number().GetNumericBlockNumber(ctx, e.state, e.etherman, dbTx)
+	if rpcErr != nil {
+		return common.Hash{}, rpcErr
+	}
+	stateRoot, err := e.state.GetStateRootByL2BlockNumber(ctx, blockNum, dbTx)
+	if errors.Is(err, state.ErrNotFound) {
+		return common.Hash{}, types.NewRPCError(types.DefaultErrorCode, "header not found")
+	} else if err != nil {
+		return common.Hash{}, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("failed to get state root of block %v", blockNum))
+	}
+	if rpcErr := e.checkStateIsAvailable(ctx, blockNum, dbTx); rpcErr != nil {
+		return common.Hash{}, rpcErr
+	}
+	return stateRoot, nil
+}
+
+// checkBlockStateIsAvailable returns the block if its state root is retained by the node
+func (e *EthEndpoints) checkBlockStateIsAvailable(ctx context.Context, block *ethTypes.Block, dbTx pgx.Tx) (*ethTypes.Block, types.Error) {
+	if rpcErr := e.checkStateIsAvailable(ctx, block.NumberU64(), dbTx); rpcErr != nil {
+		return nil, rpcErr
+	}
 	return block, nil
 }
 
+// checkStateIsAvailable returns an error if the state of the block is not retained by the node,
+// when the archive mode is disabled only the state of the last blocks is kept
+func (e *EthEndpoints) checkStateIsAvailable(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) types.Error {
+	if e.cfg.ArchiveMode {
+		return nil
+	}
+
+	lastBlockNumber, err := e.state.GetLastL2BlockNumber(ctx, dbTx)
+	if err != nil {
+		return types.NewRPCError(types.DefaultErrorCode, "failed to get the last block number from state")
+	}
+	if blockNumber+e.cfg.StateHistoryBlocks < lastBlockNumber {
+		return types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("state of block %d is not available, the node is not running in archive mode and only keeps the state of the last %d blocks", blockNumber, e.cfg.StateHistoryBlocks))
+	}
+
+	return nil
+}
+
 // GetBlockByHash returns information about a block by hash
 func (e *EthEndpoints) GetBlockByHash(hash types.ArgHash, fullTx bool) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -480,6 +721,10 @@ func (e *EthEndpoints) GetFilterLogs(filterID string) (interface{}, types.Error)
 
 // GetLogs returns a list of logs accordingly to the provided filter
 func (e *EthEndpoints) GetLogs(filter LogFilter) (interface{}, types.Error) {
+	if err := filter.Validate(); err != nil {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
+	}
+
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		return e.internalGetLogs(ctx, dbTx, filter)
 	})
@@ -495,10 +740,10 @@ func (e *EthEndpoints) internalGetLogs(ctx context.Context, dbTx pgx.Tx, filter
 	logs, err := e.state.GetLogs(ctx, fromBlockNumber, toBlockNumber, filter.Addresses, filter.Topics, filter.BlockHash, filter.Since, dbTx)
 	if errors.Is(err, state.ErrMaxLogsCountLimitExceeded) {
 		errMsg := fmt.Sprintf(state.ErrMaxLogsCountLimitExceeded.Error(), e.cfg.MaxLogsCount)
-		return RPCErrorResponse(types.InvalidParamsErrorCode, errMsg, nil, false)
+		return RPCErrorResponse(types.LimitExceededErrorCode, errMsg, nil, false)
 	} else if errors.Is(err, state.ErrMaxLogsBlockRangeLimitExceeded) {
 		errMsg := fmt.Sprintf(state.ErrMaxLogsBlockRangeLimitExceeded.Error(), e.cfg.MaxLogsBlockRange)
-		return RPCErrorResponse(types.InvalidParamsErrorCode, errMsg, nil, false)
+		return RPCErrorResponse(types.LimitExceededErrorCode, errMsg, nil, false)
 	} else if err != nil {
 		return RPCErrorResponse(types.DefaultErrorCode, "failed to get logs from state", err, true)
 	}
@@ -536,6 +781,29 @@ func (e *EthEndpoints) GetStorageAt(address types.ArgAddress, storageKeyStr stri
 	})
 }
 
+// GetProof returns the merkle proofs of the state tree for the leaves of the
+// provided account and for the provided storage keys at the given block
+func (e *EthEndpoints) GetProof(address types.ArgAddress, storageKeys []types.ArgHash, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	keys := make([]common.Hash, 0, len(storageKeys))
+	for _, storageKey := range storageKeys {
+		keys = append(keys, storageKey.Hash())
+	}
+
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		stateRoot, respErr := e.getStateRootByArg(ctx, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+
+		proof, err := e.state.GetProof(ctx, address.Address(), keys, stateRoot)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get proof from state", err, true)
+		}
+
+		return types.NewAccountProof(proof, stateRoot), nil
+	})
+}
+
 // GetTransactionByBlockHashAndIndex returns information about a transaction by
 // block hash and transaction index position.
 func (e *EthEndpoints) GetTransactionByBlockHashAndIndex(hash types.ArgHash, index types.Index) (interface{}, types.Error) {
@@ -638,6 +906,14 @@ func (e *EthEndpoints) GetTransactionByHash(hash types.ArgHash) (interface{}, ty
 			}
 			return res, nil
 		}
+		// the expired txs are reported with the reason so the sender knows it must be sent again
+		if poolTx.Status == pool.TxStatusExpired {
+			reason := "transaction expired"
+			if poolTx.FailedReason != nil {
+				reason = *poolTx.FailedReason
+			}
+			return RPCErrorResponse(types.TxExpiredErrorCode, reason, nil, false)
+		}
 		return nil, nil
 	})
 }
@@ -663,40 +939,36 @@ func (e *EthEndpoints) getTransactionByHashFromSequencerNode(hash common.Hash) (
 // GetTransactionCount returns account nonce
 func (e *EthEndpoints) GetTransactionCount(address types.ArgAddress, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
-		var (
-			pendingNonce uint64
-			nonce        uint64
-			err          error
-		)
-
-		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
-		if respErr != nil {
-			return nil, respErr
-		}
-
+		isPending := false
 		if blockArg != nil {
 			blockNumArg := blockArg.Number()
 			if blockNumArg != nil && *blockNumArg == types.PendingBlockNumber {
 				if e.cfg.SequencerNodeURI != "" {
 					return e.getTransactionCountFromSequencerNode(address.Address(), blockArg.Number())
 				}
-				pendingNonce, err = e.pool.GetNonce(ctx, address.Address())
-				if err != nil {
-					return RPCErrorResponse(types.DefaultErrorCode, "failed to count pending transactions", err, true)
-				}
+				isPending = true
 			}
 		}
 
-		nonce, err = e.state.GetNonce(ctx, address.Address(), block.Root())
+		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
 
+		nonce, err := e.state.GetNonce(ctx, address.Address(), block.Root())
 		if errors.Is(err, state.ErrNotFound) {
-			return hex.EncodeUint64(0), nil
+			nonce = 0
 		} else if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to count transactions", err, true)
 		}
 
-		if pendingNonce > nonce {
-			nonce = pendingNonce
+		if isPending {
+			// the pending txs of the account, including the ones in the sequencer worker, are still
+			// pending in the pool, the next usable nonce is the one after them without gaps
+			nonce, err = e.pool.GetPendingNonce(ctx, address.Address(), nonce)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, "failed to count pending transactions", err, true)
+			}
 		}
 
 		return hex.EncodeUint64(nonce), nil
@@ -816,7 +1088,7 @@ func (e *EthEndpoints) NewBlockFilter() (interface{}, types.Error) {
 }
 
 // internal
-func (e *EthEndpoints) newBlockFilter(wsConn *atomic.Pointer[websocket.Conn]) (interface{}, types.Error) {
+func (e *EthEndpoints) newBlockFilter(wsConn *concurrentWsConn) (interface{}, types.Error) {
 	id, err := e.storage.NewBlockFilter(wsConn)
 	if err != nil {
 		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new block filter", err, true)
@@ -835,7 +1107,7 @@ func (e *EthEndpoints) NewFilter(filter LogFilter) (interface{}, types.Error) {
 }
 
 // internal
-func (e *EthEndpoints) newFilter(ctx context.Context, wsConn *atomic.Pointer[websocket.Conn], filter LogFilter, dbTx pgx.Tx) (interface{}, types.Error) {
+func (e *EthEndpoints) newFilter(ctx context.Context, wsConn *concurrentWsConn, filter LogFilter, dbTx pgx.Tx) (interface{}, types.Error) {
 	if filter.ShouldFilterByBlockRange() {
 		_, _, rpcErr := filter.GetNumericBlockNumbers(ctx, e.cfg, e.state, e.etherman, nil)
 		if rpcErr != nil {
@@ -861,14 +1133,75 @@ func (e *EthEndpoints) NewPendingTransactionFilter() (interface{}, types.Error)
 }
 
 // internal
-func (e *EthEndpoints) newPendingTransactionFilter(wsConn *atomic.Pointer[websocket.Conn]) (interface{}, types.Error) {
-	return nil, types.NewRPCError(types.DefaultErrorCode, "not supported yet")
-	// id, err := e.storage.NewPendingTransactionFilter(wsConn)
-	// if err != nil {
-	// 	return rpcErrorResponse(types.DefaultErrorCode, "failed to create new pending transaction filter", err)
-	// }
+func (e *EthEndpoints) newPendingTransactionFilter(wsConn *concurrentWsConn) (interface{}, types.Error) {
+	id, err := e.storage.NewPendingTransactionFilter(wsConn)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new pending transaction filter", err, true)
+	}
+
+	return id, nil
+}
+
+// newPendingTransactionSubscription creates a filter to notify the hashes of the
+// txs added to the pool through the provided web socket connection
+func (e *EthEndpoints) newPendingTransactionSubscription(wsConn *concurrentWsConn) (interface{}, types.Error) {
+	if e.cfg.WebSockets.PendingTxsPollingInterval.Duration <= 0 {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "pending transactions subscription is disabled")
+	}
+
+	id, err := e.storage.NewPendingTransactionFilter(wsConn)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new pending transaction filter", err, true)
+	}
+
+	e.pendingTxsNotifierOnce.Do(func() {
+		go e.notifyPendingTxs()
+	})
+
+	return id, nil
+}
+
+func (e *EthEndpoints) newDroppedTransactionFilter(wsConn *concurrentWsConn) (interface{}, types.Error) {
+	if e.cfg.WebSockets.DroppedTxsPollingInterval.Duration <= 0 {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "dropped transactions subscription is disabled")
+	}
+
+	id, err := e.storage.NewDroppedTransactionFilter(wsConn)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new dropped transaction filter", err, true)
+	}
+
+	e.droppedTxsNotifierOnce.Do(func() {
+		go e.notifyDroppedTxs()
+	})
 
-	// return id, nil
+	return id, nil
+}
+
+func (e *EthEndpoints) newBatchStatusFilter(wsConn *concurrentWsConn) (interface{}, types.Error) {
+	if !e.batchStatusEnabled.Load() {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "batch status subscription is disabled")
+	}
+
+	id, err := e.storage.NewBatchStatusFilter(wsConn)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new batch status filter", err, true)
+	}
+
+	return id, nil
+}
+
+func (e *EthEndpoints) newSelectedTransactionFilter(wsConn *concurrentWsConn) (interface{}, types.Error) {
+	if !e.selectedTxsEnabled.Load() {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "selected transactions subscription is disabled")
+	}
+
+	id, err := e.storage.NewSelectedTransactionFilter(wsConn)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new selected transaction filter", err, true)
+	}
+
+	return id, nil
 }
 
 // SendRawTransaction has two different ways to handle new transactions:
@@ -900,7 +1233,7 @@ func (e *EthEndpoints) relayTxToSequencerNode(input string) (interface{}, types.
 	}
 
 	if res.Error != nil {
-		return RPCErrorResponse(res.Error.Code, res.Error.Message, nil, false)
+		return relayedTxErrorResponse(res.Error)
 	}
 
 	txHash := res.Result
@@ -917,13 +1250,189 @@ func (e *EthEndpoints) tryToAddTxToPool(input, ip string) (interface{}, types.Er
 	if err := e.pool.AddTx(context.Background(), *tx, ip); err != nil {
 		// it's not needed to log the error here, because we check and log if needed
 		// for each specific case during the "pool.AddTx" internal steps
-		return RPCErrorResponse(types.DefaultErrorCode, err.Error(), nil, false)
+		return addTxToPoolErrorResponse(err)
 	}
 	log.Infof("TX added to the pool: %v", tx.Hash().Hex())
 
 	return tx.Hash().Hex(), nil
 }
 
+// addTxToPoolErrorResponse builds the response for a tx rejected by the pool with the code
+// of the rejection in the error catalog, underpriced and overpriced txs carry the minimum or
+// maximum gas price allowed as the error data so the sender can resubmit them correctly priced
+// without querying it first
+func addTxToPoolErrorResponse(err error) (interface{}, types.Error) {
+	code := types.PoolErrorCode(err)
+	var gasPriceTooLowErr *pool.GasPriceTooLowError
+	if errors.As(err, &gasPriceTooLowErr) {
+		data := gasPriceTooLowErr.MinGasPrice.Bytes()
+		return RPCErrorResponseWithData(code, err.Error(), &data, nil, false)
+	}
+	var gasPriceTooHighErr *pool.GasPriceTooHighError
+	if errors.As(err, &gasPriceTooHighErr) {
+		data := gasPriceTooHighErr.MaxGasPrice.Bytes()
+		return RPCErrorResponseWithData(code, err.Error(), &data, nil, false)
+	}
+	return RPCErrorResponse(code, err.Error(), nil, false)
+}
+
+// relayedTxErrorResponse builds the response for a tx rejected by the sequencer
+// node, keeping the error data, e.g. the minimum gas price for underpriced txs
+func relayedTxErrorResponse(rpcErr *types.ErrorObject) (interface{}, types.Error) {
+	if rpcErr.Data != nil {
+		data := []byte(*rpcErr.Data)
+		return RPCErrorResponseWithData(rpcErr.Code, rpcErr.Message, &data, nil, false)
+	}
+	return RPCErrorResponse(rpcErr.Code, rpcErr.Message, nil, false)
+}
+
+// SendRawTransactionConditional has to be used to send a tx with preconditions
+// on the storage of known accounts and on the block number and timestamp of
+// the block where it's included. The conditions are checked when the tx is
+// received and again when the sequencer selects it, dropping it if they are not met
+func (e *EthEndpoints) SendRawTransactionConditional(httpRequest *http.Request, input string, conditions types.TransactionConditions) (interface{}, types.Error) {
+	if e.cfg.SequencerNodeURI != "" {
+		return e.relayConditionalTxToSequencerNode(input, conditions)
+	}
+
+	ip := ""
+	ips := httpRequest.Header.Get("X-Forwarded-For")
+	if ips != "" {
+		ip = strings.Split(ips, ",")[0]
+	}
+
+	tx, err := hexToTx(input)
+	if err != nil {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid tx input", err, false)
+	}
+
+	txConditions := conditions.ToTxConditions()
+	if err := txConditions.Validate(); err != nil {
+		return RPCErrorResponse(types.PoolErrorCode(err), err.Error(), nil, false)
+	}
+
+	ctx := context.Background()
+	lastBlock, err := e.state.GetLastL2Block(ctx, nil)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block from state", err, true)
+	}
+	getStorage := pool.NewStorageGetter(lastBlock.Root(), e.state.GetStorageAt)
+	if err := txConditions.Check(ctx, lastBlock.NumberU64()+1, time.Now(), getStorage); err != nil {
+		if errors.Is(err, pool.ErrTxConditionsNotMet) {
+			return RPCErrorResponse(types.TxConditionsNotMetErrorCode, err.Error(), nil, false)
+		}
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to check tx conditions", err, true)
+	}
+
+	log.Infof("adding conditional TX to the pool: %v", tx.Hash().Hex())
+	if err := e.pool.AddConditionalTx(ctx, *tx, ip, txConditions); err != nil {
+		return addTxToPoolErrorResponse(err)
+	}
+	log.Infof("conditional TX added to the pool: %v", tx.Hash().Hex())
+
+	return tx.Hash().Hex(), nil
+}
+
+func (e *EthEndpoints) relayConditionalTxToSequencerNode(input string, conditions types.TransactionConditions) (interface{}, types.Error) {
+	res, err := client.JSONRPCCall(e.cfg.SequencerNodeURI, "eth_sendRawTransactionConditional", input, conditions)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to relay tx to the sequencer node", err, true)
+	}
+
+	if res.Error != nil {
+		return relayedTxErrorResponse(res.Error)
+	}
+
+	return res.Result, nil
+}
+
+// SendTransaction creates a tx from the provided arguments, signs it using the
+// key of an account unlocked by the node and sends it as a raw tx.
+// It's only available when the dev mode is enabled
+func (e *EthEndpoints) SendTransaction(httpRequest *http.Request, arg *types.TxArgs) (interface{}, types.Error) {
+	if e.devAccounts == nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "eth_sendTransaction is only available in dev mode", nil, false)
+	}
+	if arg == nil || arg.From == nil {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument from", nil, false)
+	}
+	if !e.devAccounts.contains(*arg.From) {
+		return RPCErrorResponse(types.DefaultErrorCode, "unknown account", nil, false)
+	}
+
+	res, respErr := e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		return e.newDevTransaction(ctx, arg, dbTx)
+	})
+	if respErr != nil {
+		return nil, respErr
+	}
+
+	signedTx, err := e.devAccounts.signTx(*arg.From, res.(*ethTypes.Transaction), e.chainID)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to sign tx", err, true)
+	}
+	encodedTx, err := signedTx.MarshalBinary()
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to encode tx", err, true)
+	}
+
+	return e.SendRawTransaction(httpRequest, hex.EncodeToHex(encodedTx))
+}
+
+// newDevTransaction builds the unsigned tx for eth_sendTransaction filling the
+// nonce, gas price and gas with the pending values when they are not provided
+func (e *EthEndpoints) newDevTransaction(ctx context.Context, arg *types.TxArgs, dbTx pgx.Tx) (interface{}, types.Error) {
+	sender := *arg.From
+	lastBlock, err := e.state.GetLastL2Block(ctx, dbTx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block from state", err, true)
+	}
+
+	_, tx, err := arg.ToTransaction(ctx, e.state, e.cfg.MaxCumulativeGasUsed, lastBlock.Root(), sender, dbTx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+	}
+	if tx.Type() != ethTypes.LegacyTxType {
+		return RPCErrorResponse(types.DefaultErrorCode, "eth_sendTransaction only supports legacy txs", nil, false)
+	}
+
+	nonce := tx.Nonce()
+	if arg.Nonce != nil {
+		nonce = uint64(*arg.Nonce)
+	} else {
+		nonce, err = e.pool.GetPendingNonce(ctx, sender, nonce)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to count pending transactions", err, true)
+		}
+	}
+
+	gasPrice := tx.GasPrice()
+	if arg.GasPrice == nil {
+		gasPrices, err := e.pool.GetGasPrices(ctx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get gas price", err, true)
+		}
+		gasPrice = new(big.Int).SetUint64(gasPrices.L2GasPrice)
+	}
+
+	gas := tx.Gas()
+	if arg.Gas == nil {
+		gas, _, err = e.state.EstimateGas(tx, sender, nil, nil, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to estimate gas", err, false)
+		}
+	}
+
+	return ethTypes.NewTx(&ethTypes.LegacyTx{
+		Nonce:    nonce,
+		To:       tx.To(),
+		Value:    tx.Value(),
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Data:     tx.Data(),
+	}), nil
+}
+
 // UninstallFilter uninstalls a filter with given id.
 func (e *EthEndpoints) UninstallFilter(filterID string) (interface{}, types.Error) {
 	err := e.storage.UninstallFilter(filterID)
@@ -997,6 +1506,10 @@ func (e *EthEndpoints) ProtocolVersion() (interface{}, types.Error) {
 	return "0x0", nil
 }
 
+// hexToTx decodes a raw tx, either a legacy tx or an EIP-2718 typed tx envelope. The typed
+// txs are accepted in their canonical encoding, type || payload, and in the RLP string wrapped
+// encoding some wallets and libraries send. Whether the tx type can be included in the batches
+// is checked when the tx is added to the pool
 func hexToTx(str string) (*ethTypes.Transaction, error) {
 	tx := new(ethTypes.Transaction)
 
@@ -1004,8 +1517,24 @@ func hexToTx(str string) (*ethTypes.Transaction, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(b) == 0 {
+		return nil, errors.New("empty raw tx")
+	}
 
-	if err := tx.UnmarshalBinary(b); err != nil {
+	err = tx.UnmarshalBinary(b)
+	if err != nil && b[0] >= rlpStringPrefix && b[0] < rlpListPrefix {
+		// an RLP string, the typed tx envelope is wrapped in it
+		if rlpErr := rlp.DecodeBytes(b, tx); rlpErr == nil {
+			err = nil
+		}
+	}
+	if err == nil && tx.Type() > ethTypes.DynamicFeeTxType {
+		// the types decoded by geth but not supported by the network, like the blob txs
+		err = ethTypes.ErrTxTypeNotSupported
+	}
+	if errors.Is(err, ethTypes.ErrTxTypeNotSupported) {
+		return nil, fmt.Errorf("%w: type 0x%02x, the supported types are legacy, access list (0x01) and dynamic fee (0x02)", err, b[0])
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -1024,7 +1553,7 @@ func (e *EthEndpoints) updateFilterLastPoll(filterID string) types.Error {
 // The node will return a subscription id.
 // For each event that matches the subscription a notification with relevant
 // data is sent together with the subscription id.
-func (e *EthEndpoints) Subscribe(wsConn *atomic.Pointer[websocket.Conn], name string, logFilter *LogFilter) (interface{}, types.Error) {
+func (e *EthEndpoints) Subscribe(wsConn *concurrentWsConn, name string, logFilter *LogFilter) (interface{}, types.Error) {
 	switch name {
 	case "newHeads":
 		return e.newBlockFilter(wsConn)
@@ -1037,7 +1566,13 @@ func (e *EthEndpoints) Subscribe(wsConn *atomic.Pointer[websocket.Conn], name st
 			return e.newFilter(ctx, wsConn, lf, dbTx)
 		})
 	case "pendingTransactions", "newPendingTransactions":
-		return e.newPendingTransactionFilter(wsConn)
+		return e.newPendingTransactionSubscription(wsConn)
+	case "zkevm_droppedTransactions", "droppedTransactions":
+		return e.newDroppedTransactionFilter(wsConn)
+	case "zkevm_batchStatus", "batchStatus":
+		return e.newBatchStatusFilter(wsConn)
+	case "zkevm_selectedTransactions", "selectedTransactions":
+		return e.newSelectedTransactionFilter(wsConn)
 	case "syncing":
 		return nil, types.NewRPCError(types.DefaultErrorCode, "not supported yet")
 	default:
@@ -1052,7 +1587,7 @@ func (e *EthEndpoints) Unsubscribe(wsConn *websocket.Conn, filterID string) (int
 
 // uninstallFilterByWSConn uninstalls the filters connected to the
 // provided web socket connection
-func (e *EthEndpoints) uninstallFilterByWSConn(wsConn *atomic.Pointer[websocket.Conn]) error {
+func (e *EthEndpoints) uninstallFilterByWSConn(wsConn *concurrentWsConn) error {
 	return e.storage.UninstallFilterByWSConn(wsConn)
 }
 
@@ -1180,12 +1715,96 @@ func (e *EthEndpoints) notifyNewLogs(wg *sync.WaitGroup, event state.NewL2BlockE
 	log.Debugf("[notifyNewLogs] new l2 block event for block %v took %vms to send all the messages for log filters", event.Block.NumberU64(), time.Since(start).Milliseconds())
 }
 
+// notifyDroppedTxs polls the pool for the txs that were dropped, evicted or deleted
+// and sends them to the dropped transactions subscriptions
+func (e *EthEndpoints) notifyDroppedTxs() {
+	ticker := time.NewTicker(e.cfg.WebSockets.DroppedTxsPollingInterval.Duration)
+	defer ticker.Stop()
+
+	// a zero cursor only returns the current cursor, so the txs dropped
+	// while there are no subscriptions aren't notified
+	var cursor uint64
+	for range ticker.C {
+		filters, err := e.storage.GetAllDroppedTxFiltersWithWSConn()
+		if err != nil {
+			log.Errorf("failed to get dropped tx filters with web sockets connections: %v", err)
+			continue
+		}
+		if len(filters) == 0 {
+			cursor = 0
+		}
+
+		droppedTxs, nextCursor, err := e.pool.GetDroppedTxsSince(context.Background(), cursor)
+		if err != nil {
+			log.Errorf("failed to get dropped txs since cursor %v: %v", cursor, err)
+			continue
+		}
+		cursor = nextCursor
+
+		for _, droppedTx := range droppedTxs {
+			data, err := json.Marshal(types.NewDroppedTransaction(droppedTx))
+			if err != nil {
+				log.Errorf("failed to marshal dropped tx %v: %v", droppedTx.Hash.String(), err)
+				continue
+			}
+			for _, filter := range filters {
+				e.sendSubscriptionResponse(filter, data)
+			}
+		}
+	}
+}
+
+// notifyPendingTxs polls the pool for the txs added since the last poll and
+// sends their hashes to the new pending transactions subscriptions
+func (e *EthEndpoints) notifyPendingTxs() {
+	ticker := time.NewTicker(e.cfg.WebSockets.PendingTxsPollingInterval.Duration)
+	defer ticker.Stop()
+
+	since := time.Now()
+	for range ticker.C {
+		now := time.Now()
+		filters, err := e.storage.GetAllPendingTxFiltersWithWSConn()
+		if err != nil {
+			log.Errorf("failed to get pending tx filters with web sockets connections: %v", err)
+			continue
+		}
+		if len(filters) == 0 {
+			since = now
+			continue
+		}
+
+		txHashes, err := e.pool.GetPendingTxHashesSince(context.Background(), since)
+		if err != nil {
+			log.Errorf("failed to get pending tx hashes since %v: %v", since, err)
+			continue
+		}
+		since = now
+
+		for _, txHash := range txHashes {
+			data, err := json.Marshal(txHash)
+			if err != nil {
+				log.Errorf("failed to marshal pending tx hash %v: %v", txHash.String(), err)
+				continue
+			}
+			for _, filter := range filters {
+				e.sendSubscriptionResponse(filter, data)
+			}
+		}
+	}
+}
+
 func (e *EthEndpoints) sendSubscriptionResponse(filter *Filter, data []byte) {
+	sendSubscriptionResponse(filter, "eth_subscription", data)
+}
+
+// sendSubscriptionResponse writes the notification of the subscription to the web socket
+// connection of the filter, the method is the one of the notifications of the namespace
+func sendSubscriptionResponse(filter *Filter, method string, data []byte) {
 	const errMessage = "Unable to write WS message to filter %v, %s"
 
 	res := types.SubscriptionResponse{
 		JSONRPC: "2.0",
-		Method:  "eth_subscription",
+		Method:  method,
 		Params: types.SubscriptionResponseParams{
 			Subscription: filter.ID,
 			Result:       data,
@@ -1197,7 +1816,7 @@ func (e *EthEndpoints) sendSubscriptionResponse(filter *Filter, data []byte) {
 		return
 	}
 
-	err = filter.WsConn.Load().WriteMessage(websocket.TextMessage, message)
+	err = filter.WsConn.WriteMessage(websocket.TextMessage, message)
 	if err != nil {
 		log.Errorf(fmt.Sprintf(errMessage, filter.ID, err.Error()))
 		return
@@ -7,24 +7,28 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"sync/atomic"
 	"testing"
 	"time"
 
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
-	"github.com/gorilla/websocket"
+	"github.com/holiman/uint256"
 	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -172,11 +176,83 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
 		},
+		{
+			name: "Transaction with state override",
+			params: []interface{}{
+				types.TxArgs{
+					From:     state.HexToAddressPtr("0x1"),
+					To:       state.HexToAddressPtr("0x2"),
+					Gas:      types.ArgUint64Ptr(24000),
+					GasPrice: types.ArgBytesPtr(big.NewInt(1).Bytes()),
+					Value:    types.ArgBytesPtr(big.NewInt(2).Bytes()),
+					Data:     types.ArgBytesPtr([]byte("data")),
+				},
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+				map[string]interface{}{
+					"0x0000000000000000000000000000000000000002": map[string]interface{}{
+						"balance":   "0x3e8",
+						"nonce":     "0x5",
+						"code":      "0x6001",
+						"stateDiff": map[string]string{common.HexToHash("0x1").String(): common.HexToHash("0x2").String()},
+					},
+				},
+			},
+			expectedResult: []byte("hello world"),
+			expectedError:  nil,
+			setupMocks: func(c Config, m *mocksWrapper, testCase *testCase) {
+				nonce := uint64(7)
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				txArgs := testCase.params[0].(types.TxArgs)
+				block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumOne, Root: blockRoot})
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
+				stateOverrideMatchBy := mock.MatchedBy(func(stateOverride state.StateOverride) bool {
+					account, found := stateOverride[common.HexToAddress("0x2")]
+					return len(stateOverride) == 1 && found &&
+						account.Balance.Uint64() == 1000 &&
+						*account.Nonce == 5 &&
+						hex.EncodeToHex(*account.Code) == "0x6001" &&
+						account.State == nil &&
+						(*account.StateDiff)[common.HexToHash("0x1")] == common.HexToHash("0x2")
+				})
+				m.State.
+					On("ProcessUnsignedTransaction", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumOneUint64, true, stateOverrideMatchBy, m.DbTx).
+					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
+					Once()
+			},
+		},
+		{
+			name: "Transaction with invalid state override",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+				},
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+				map[string]interface{}{
+					"0x0000000000000000000000000000000000000002": map[string]interface{}{
+						"state":     map[string]string{common.HexToHash("0x1").String(): common.HexToHash("0x2").String()},
+						"stateDiff": map[string]string{common.HexToHash("0x1").String(): common.HexToHash("0x2").String()},
+					},
+				},
+			},
+			expectedResult: nil,
+			expectedError:  types.NewRPCError(types.InvalidParamsErrorCode, "account 0x0000000000000000000000000000000000000002 has both 'state' and 'stateDiff'"),
+			setupMocks: func(c Config, m *mocksWrapper, testCase *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
 		{
 			name: "Transaction with all information from block by hash with EIP-1898",
 			params: []interface{}{
@@ -216,7 +292,7 @@ func TestCall(t *testing.T) {
 				})
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -258,7 +334,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -300,7 +376,7 @@ func TestCall(t *testing.T) {
 				})
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -340,7 +416,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumTenUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -379,7 +455,7 @@ func TestCall(t *testing.T) {
 				block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumOne, Root: blockRoot})
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -418,7 +494,7 @@ func TestCall(t *testing.T) {
 				block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumOne, Root: blockRoot})
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
 					Once()
 			},
@@ -482,7 +558,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{Err: errors.New("failed to process unsigned transaction")}, nil).
 					Once()
 			},
@@ -524,7 +600,7 @@ func TestCall(t *testing.T) {
 				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
 				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Once()
 				m.State.
-					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, m.DbTx).
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, nilUint64, true, state.StateOverride(nil), m.DbTx).
 					Return(&runtime.ExecutionResult{Err: runtime.ErrExecutionReverted}, nil).
 					Once()
 			},
@@ -571,6 +647,92 @@ func TestChainID(t *testing.T) {
 	assert.Equal(t, s.ChainID(), chainID.Uint64())
 }
 
+func TestAccountsAndSendTransactionDisabledOutsideDevMode(t *testing.T) {
+	s, _, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	res, err := s.JSONRPCCall("eth_accounts")
+	require.NoError(t, err)
+	assert.Nil(t, res.Error)
+
+	var accounts []common.Address
+	err = json.Unmarshal(res.Result, &accounts)
+	require.NoError(t, err)
+	assert.Empty(t, accounts)
+
+	from := common.HexToAddress("0x1")
+	res, err = s.JSONRPCCall("eth_sendTransaction", types.TxArgs{From: &from})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+	assert.Equal(t, "eth_sendTransaction is only available in dev mode", res.Error.Message)
+}
+
+func TestAccountsAndSendTransactionInDevMode(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "testonly")
+	require.NoError(t, err)
+
+	cfg := getSequencerDefaultConfig()
+	cfg.DevMode = DevModeConfig{
+		Enabled:  true,
+		Accounts: []cfgTypes.KeystoreFileConfig{{Path: account.URL.Path, Password: "testonly"}},
+	}
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	res, err := s.JSONRPCCall("eth_accounts")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var accounts []common.Address
+	err = json.Unmarshal(res.Result, &accounts)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{account.Address}, accounts)
+
+	to := common.HexToAddress("0x1")
+	gas := types.ArgUint64(21000)
+	value := types.ArgBytes(big.NewInt(10).Bytes())
+	txArgs := types.TxArgs{From: &account.Address, To: &to, Gas: &gas, Value: &value}
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumTen, Root: blockRoot})
+	m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
+	m.State.On("GetNonce", context.Background(), account.Address, blockRoot).Return(uint64(3), nil).Once()
+	m.Pool.On("GetPendingNonce", context.Background(), account.Address, uint64(3)).Return(uint64(5), nil).Once()
+	m.Pool.On("GetGasPrices", context.Background()).Return(pool.GasPrices{L2GasPrice: 1000000000}, nil).Once()
+
+	var pooledTx ethTypes.Transaction
+	m.Pool.
+		On("AddTx", context.Background(), mock.IsType(ethTypes.Transaction{}), "").
+		Run(func(args mock.Arguments) { pooledTx = args.Get(1).(ethTypes.Transaction) }).
+		Return(nil).
+		Once()
+
+	res, err = s.JSONRPCCall("eth_sendTransaction", txArgs)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var txHash common.Hash
+	err = json.Unmarshal(res.Result, &txHash)
+	require.NoError(t, err)
+	assert.Equal(t, pooledTx.Hash(), txHash)
+
+	// the tx is signed by the dev account with the pending nonce and the network chain ID
+	assert.Equal(t, uint64(5), pooledTx.Nonce())
+	assert.Equal(t, s.ChainID(), pooledTx.ChainId().Uint64())
+	assert.Equal(t, to, *pooledTx.To())
+	assert.Equal(t, big.NewInt(10), pooledTx.Value())
+	assert.Equal(t, uint64(21000), pooledTx.Gas())
+	assert.Equal(t, big.NewInt(1000000000), pooledTx.GasPrice())
+	sender, err := ethTypes.Sender(ethTypes.LatestSignerForChainID(pooledTx.ChainId()), &pooledTx)
+	require.NoError(t, err)
+	assert.Equal(t, account.Address, sender)
+}
+
 func TestCoinbase(t *testing.T) {
 	testCases := []struct {
 		name                   string
@@ -686,7 +848,7 @@ func TestEstimateGas(t *testing.T) {
 					Return(nonce, nil).
 					Once()
 				m.State.
-					On("EstimateGas", txMatchBy, *txArgs.From, nilUint64, m.DbTx).
+					On("EstimateGas", txMatchBy, *txArgs.From, nilUint64, state.StateOverride(nil), m.DbTx).
 					Return(*testCase.expectedResult, nil, nil).
 					Once()
 			},
@@ -727,11 +889,39 @@ func TestEstimateGas(t *testing.T) {
 				m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
 
 				m.State.
-					On("EstimateGas", txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, m.DbTx).
+					On("EstimateGas", txMatchBy, common.HexToAddress(DefaultSenderAddress), nilUint64, state.StateOverride(nil), m.DbTx).
 					Return(*testCase.expectedResult, nil, nil).
 					Once()
 			},
 		},
+		{
+			name: "Executor unavailable",
+			params: []interface{}{
+				types.TxArgs{
+					From: state.HexToAddressPtr("0x1"),
+					To:   state.HexToAddressPtr("0x2"),
+				},
+			},
+			expectedError: types.NewRPCError(types.ExecutorUnavailableErrorCode, executor.ErrExecutorUnavailable.Error()),
+			setupMocks: func(c Config, m *mocksWrapper, testCase *testCase) {
+				txArgs := testCase.params[0].(types.TxArgs)
+
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+
+				block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumTen, Root: blockRoot})
+				m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
+
+				m.State.
+					On("GetNonce", context.Background(), *txArgs.From, blockRoot).
+					Return(uint64(0), nil).
+					Once()
+				m.State.
+					On("EstimateGas", mock.Anything, *txArgs.From, nilUint64, state.StateOverride(nil), m.DbTx).
+					Return(uint64(0), nil, executor.ErrExecutorUnavailable).
+					Once()
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -771,13 +961,15 @@ func TestGasPrice(t *testing.T) {
 	testCases := []struct {
 		name               string
 		gasPrice           uint64
+		minGasPrice        uint64
 		error              error
 		expectedL2GasPrice uint64
 		expectedL1GasPrice uint64
 	}{
-		{"GasPrice nil", 0, nil, 0, 0},
-		{"GasPrice with value", 50, nil, 50, 100},
-		{"failed to get gas price", 50, errors.New("failed to get gas price"), 0, 0},
+		{"GasPrice nil", 0, 0, nil, 0, 0},
+		{"GasPrice with value", 50, 10, nil, 50, 100},
+		{"GasPrice below pool min gas price", 50, 60, nil, 60, 100},
+		{"failed to get gas price", 50, 0, errors.New("failed to get gas price"), 0, 0},
 	}
 
 	for _, testCase := range testCases {
@@ -789,6 +981,12 @@ func TestGasPrice(t *testing.T) {
 					L1GasPrice: testCase.gasPrice,
 				}, testCase.error).
 				Once()
+			if testCase.error == nil {
+				m.Pool.
+					On("GetMinSuggestedGasPrice").
+					Return(new(big.Int).SetUint64(testCase.minGasPrice)).
+					Once()
+			}
 
 			gasPrices, err := c.SuggestGasPrice(context.Background())
 			require.NoError(t, err)
@@ -797,6 +995,42 @@ func TestGasPrice(t *testing.T) {
 	}
 }
 
+func TestFeeHistory(t *testing.T) {
+	s, m, c := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txA := ethTypes.NewTransaction(1, common.HexToAddress("0x111"), big.NewInt(0), 21000, big.NewInt(10), nil)
+	txB := ethTypes.NewTransaction(2, common.HexToAddress("0x111"), big.NewInt(0), 63000, big.NewInt(30), nil)
+	emptyBlock := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: big.NewInt(9), GasLimit: 100})
+	block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: big.NewInt(10), GasLimit: 168000, GasUsed: 84000}).
+		WithBody([]*ethTypes.Transaction{txB, txA}, nil)
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(9), m.DbTx).Return(emptyBlock, nil).Once()
+	m.State.On("GetL2BlockByNumber", context.Background(), uint64(10), m.DbTx).Return(block, nil).Once()
+	m.State.On("GetTransactionReceipt", context.Background(), txA.Hash(), m.DbTx).Return(&ethTypes.Receipt{GasUsed: 21000}, nil).Once()
+	m.State.On("GetTransactionReceipt", context.Background(), txB.Hash(), m.DbTx).Return(&ethTypes.Receipt{GasUsed: 63000, EffectiveGasPrice: big.NewInt(25)}, nil).Once()
+
+	history, err := c.FeeHistory(context.Background(), 2, big.NewInt(10), []float64{10, 50, 90})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(9), history.OldestBlock.Uint64())
+	assert.Equal(t, []float64{0, 0.5}, history.GasUsedRatio)
+	require.Len(t, history.BaseFee, 3)
+	for _, baseFee := range history.BaseFee {
+		assert.Equal(t, uint64(0), baseFee.Uint64())
+	}
+	require.Len(t, history.Reward, 2)
+	assert.Equal(t, "[0 0 0]", fmt.Sprint(history.Reward[0]))
+	assert.Equal(t, "[10 25 25]", fmt.Sprint(history.Reward[1]))
+
+	res, err := s.JSONRPCCall("eth_feeHistory", "0x1", "latest", []float64{50, 10})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
+
 func TestGetBalance(t *testing.T) {
 	s, m, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -986,6 +1220,60 @@ func TestGetBalance(t *testing.T) {
 	}
 }
 
+func TestGetBalanceWithoutArchiveMode(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.ArchiveMode = false
+	cfg.StateHistoryBlocks = 5
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumTen, Root: blockRoot})
+
+	testCases := []struct {
+		name            string
+		lastBlockNumber uint64
+		expectedError   *types.RPCError
+	}{
+		{
+			name:            "get balance of a block whose state is retained",
+			lastBlockNumber: 15,
+		},
+		{
+			name:            "get balance of a block whose state is not retained",
+			lastBlockNumber: 16,
+			expectedError:   types.NewRPCError(types.DefaultErrorCode, "state of block 10 is not available, the node is not running in archive mode and only keeps the state of the last 5 blocks"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
+			m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(testCase.lastBlockNumber, nil).Once()
+			if testCase.expectedError == nil {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("GetBalance", context.Background(), addressArg, blockRoot).Return(big.NewInt(1000), nil).Once()
+			} else {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+			}
+
+			res, err := s.JSONRPCCall("eth_getBalance", addressArg.String(), hex.EncodeBig(blockNumTen))
+			require.NoError(t, err)
+
+			if testCase.expectedError == nil {
+				require.Nil(t, res.Error)
+				var balance string
+				require.NoError(t, json.Unmarshal(res.Result, &balance))
+				assert.Equal(t, "0x3e8", balance)
+			} else {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, testCase.expectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, testCase.expectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
 func TestGetL2BlockByHash(t *testing.T) {
 	type testCase struct {
 		Name           string
@@ -1819,6 +2107,101 @@ func TestGetStorageAt(t *testing.T) {
 	}
 }
 
+func TestGetProof(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	blockNumber := big.NewInt(1)
+	params := []interface{}{
+		addressArg.String(),
+		[]string{keyArg.String()},
+		map[string]interface{}{
+			types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+		},
+	}
+
+	t.Run("failed to get proof", func(t *testing.T) {
+		m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetStateRootByL2BlockNumber", context.Background(), blockNumber.Uint64(), m.DbTx).Return(blockRoot, nil).Once()
+		m.State.
+			On("GetProof", context.Background(), addressArg, []common.Hash{keyArg}, blockRoot).
+			Return(nil, errors.New("failed to get proof")).
+			Once()
+
+		res, err := s.JSONRPCCall("eth_getProof", params...)
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+		assert.Equal(t, "failed to get proof from state", res.Error.Message)
+	})
+
+	t.Run("get proof successfully", func(t *testing.T) {
+		keyProof := func(key uint64, value *big.Int) *merkletree.KeyProof {
+			return &merkletree.KeyProof{
+				Root:     []uint64{1, 2, 3, 4},
+				Key:      []uint64{key, 0, 0, 0},
+				Value:    value,
+				Siblings: [][]uint64{{5, 6, 7, 8}, {9, 10, 11, 12}},
+			}
+		}
+		stateProof := &state.AccountProof{
+			Address:         addressArg,
+			Balance:         big.NewInt(1000),
+			Nonce:           big.NewInt(2),
+			CodeHash:        common.HexToHash("0x3"),
+			CodeLength:      big.NewInt(4),
+			BalanceProof:    keyProof(1, big.NewInt(1000)),
+			NonceProof:      keyProof(2, big.NewInt(2)),
+			CodeHashProof:   keyProof(3, big.NewInt(3)),
+			CodeLengthProof: keyProof(4, big.NewInt(4)),
+			StorageProofs: []state.StorageProof{
+				{Key: keyArg, Value: big.NewInt(0), Proof: &merkletree.KeyProof{
+					Root:     []uint64{1, 2, 3, 4},
+					Key:      []uint64{5, 0, 0, 0},
+					Siblings: [][]uint64{{5, 6, 7, 8}},
+					InsKey:   []uint64{6, 0, 0, 0},
+					InsValue: big.NewInt(7),
+				}},
+			},
+		}
+
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetStateRootByL2BlockNumber", context.Background(), blockNumber.Uint64(), m.DbTx).Return(blockRoot, nil).Once()
+		m.State.
+			On("GetProof", context.Background(), addressArg, []common.Hash{keyArg}, blockRoot).
+			Return(stateProof, nil).
+			Once()
+
+		res, err := s.JSONRPCCall("eth_getProof", params...)
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var result types.AccountProof
+		err = json.Unmarshal(res.Result, &result)
+		require.NoError(t, err)
+
+		assert.Equal(t, addressArg, result.Address)
+		assert.Equal(t, blockRoot, result.StateRoot)
+		assert.Equal(t, "1000", (*big.Int)(&result.Balance).String())
+		assert.Equal(t, "2", (*big.Int)(&result.Nonce).String())
+		assert.Equal(t, common.HexToHash("0x3"), result.CodeHash)
+		assert.Equal(t, "4", (*big.Int)(&result.CodeLength).String())
+		assert.Equal(t, common.HexToHash(merkletree.H4ToString([]uint64{1, 0, 0, 0})), result.BalanceProof.Key)
+		assert.Equal(t, [][]types.ArgUint64{{5, 6, 7, 8}, {9, 10, 11, 12}}, result.BalanceProof.Siblings)
+		require.Len(t, result.StorageProof, 1)
+		storageProof := result.StorageProof[0]
+		assert.Equal(t, keyArg, storageProof.Key)
+		assert.Equal(t, "0", (*big.Int)(&storageProof.Value).String())
+		assert.Nil(t, storageProof.Proof.Value)
+		require.NotNil(t, storageProof.Proof.InsKey)
+		assert.Equal(t, common.HexToHash(merkletree.H4ToString([]uint64{6, 0, 0, 0})), *storageProof.Proof.InsKey)
+		require.NotNil(t, storageProof.Proof.InsValue)
+		assert.Equal(t, "7", (*big.Int)(storageProof.Proof.InsValue).String())
+	})
+}
+
 func TestGetCompilers(t *testing.T) {
 	s, _, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -3000,6 +3383,120 @@ func TestGetTransactionCount(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			Name: "Count txs pending including the pool txs without gaps",
+			Params: []interface{}{
+				addressArg.String(),
+				"pending",
+			},
+			ExpectedResult: uint(13),
+			ExpectedError:  nil,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetLastL2BlockNumber", context.Background(), m.DbTx).
+					Return(blockNumTen.Uint64(), nil).
+					Once()
+
+				block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumTen, Root: blockRoot})
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
+
+				m.State.
+					On("GetNonce", context.Background(), addressArg, blockRoot).
+					Return(uint64(10), nil).
+					Once()
+
+				m.Pool.
+					On("GetPendingNonce", context.Background(), addressArg, uint64(10)).
+					Return(uint64(13), nil).
+					Once()
+			},
+		},
+		{
+			Name: "Count txs pending for an account not in the state",
+			Params: []interface{}{
+				addressArg.String(),
+				"pending",
+			},
+			ExpectedResult: uint(2),
+			ExpectedError:  nil,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetLastL2BlockNumber", context.Background(), m.DbTx).
+					Return(blockNumTen.Uint64(), nil).
+					Once()
+
+				block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumTen, Root: blockRoot})
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
+
+				m.State.
+					On("GetNonce", context.Background(), addressArg, blockRoot).
+					Return(uint64(0), state.ErrNotFound).
+					Once()
+
+				m.Pool.
+					On("GetPendingNonce", context.Background(), addressArg, uint64(0)).
+					Return(uint64(2), nil).
+					Once()
+			},
+		},
+		{
+			Name: "failed to get pending nonce",
+			Params: []interface{}{
+				addressArg.String(),
+				"pending",
+			},
+			ExpectedResult: 0,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to count pending transactions"),
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetLastL2BlockNumber", context.Background(), m.DbTx).
+					Return(blockNumTen.Uint64(), nil).
+					Once()
+
+				block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: blockNumTen, Root: blockRoot})
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumTenUint64, m.DbTx).Return(block, nil).Once()
+
+				m.State.
+					On("GetNonce", context.Background(), addressArg, blockRoot).
+					Return(uint64(10), nil).
+					Once()
+
+				m.Pool.
+					On("GetPendingNonce", context.Background(), addressArg, uint64(10)).
+					Return(uint64(0), errors.New("failed to get pending nonce")).
+					Once()
+			},
+		},
 		{
 			Name: "failed to get last block number",
 			Params: []interface{}{
@@ -3431,6 +3928,26 @@ func TestSendRawTransactionJSONRPCCall(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			Name: "Send dynamic fee TX wrapped in a RLP string successfully",
+			Prepare: func(t *testing.T, tc *testCase) {
+				to := common.HexToAddress("0x1")
+				tx := ethTypes.NewTx(&ethTypes.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 1, GasFeeCap: big.NewInt(2), GasTipCap: big.NewInt(1)})
+
+				txBinary, err := rlp.EncodeToBytes(tx)
+				require.NoError(t, err)
+
+				tc.Input = hex.EncodeToHex(txBinary)
+				tc.ExpectedResult = state.HashPtr(tx.Hash())
+				tc.ExpectedError = nil
+			},
+			SetupMocks: func(t *testing.T, m *mocksWrapper, tc testCase) {
+				m.Pool.
+					On("AddTx", context.Background(), mock.IsType(ethTypes.Transaction{}), "").
+					Return(nil).
+					Once()
+			},
+		},
 		{
 			Name: "Send invalid tx input",
 			Prepare: func(t *testing.T, tc *testCase) {
@@ -3468,6 +3985,64 @@ func TestSendRawTransactionJSONRPCCall(t *testing.T) {
 	}
 }
 
+func TestSendRawTransactionGasPriceTooLow(t *testing.T) {
+	sequencerServer, sequencerMocks, _ := newSequencerMockedServer(t)
+	defer sequencerServer.Stop()
+	nonSequencerServer, _, _ := newNonSequencerMockedServer(t, sequencerServer.ServerURL)
+	defer nonSequencerServer.Stop()
+
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), uint64(1), big.NewInt(1), []byte{})
+	txBinary, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	rawTx := hex.EncodeToHex(txBinary)
+
+	minGasPrice := big.NewInt(1000000000)
+	expectedErr := pool.NewGasPriceTooLowError(minGasPrice)
+	sequencerMocks.Pool.
+		On("AddTx", context.Background(), mock.IsType(ethTypes.Transaction{}), "").
+		Return(expectedErr).
+		Twice()
+
+	for _, server := range []*mockedServer{sequencerServer, nonSequencerServer} {
+		res, err := server.JSONRPCCall("eth_sendRawTransaction", rawTx)
+		require.NoError(t, err)
+
+		require.Nil(t, res.Result)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.GasPriceTooLowErrorCode, res.Error.Code)
+		assert.Equal(t, expectedErr.Error(), res.Error.Message)
+		require.NotNil(t, res.Error.Data)
+		assert.Equal(t, minGasPrice.Bytes(), []byte(*res.Error.Data))
+	}
+}
+
+func TestSendRawTransactionGasPriceTooHigh(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), uint64(1), big.NewInt(1000000000000), []byte{})
+	txBinary, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	rawTx := hex.EncodeToHex(txBinary)
+
+	maxGasPrice := big.NewInt(100000000000)
+	expectedErr := pool.NewGasPriceTooHighError(maxGasPrice)
+	m.Pool.
+		On("AddTx", context.Background(), mock.IsType(ethTypes.Transaction{}), "").
+		Return(expectedErr).
+		Once()
+
+	res, err := s.JSONRPCCall("eth_sendRawTransaction", rawTx)
+	require.NoError(t, err)
+
+	require.Nil(t, res.Result)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.GasPriceTooHighErrorCode, res.Error.Code)
+	assert.Equal(t, expectedErr.Error(), res.Error.Message)
+	require.NotNil(t, res.Error.Data)
+	assert.Equal(t, maxGasPrice.Bytes(), []byte(*res.Error.Data))
+}
+
 func TestSendRawTransactionViaGethForNonSequencerNode(t *testing.T) {
 	sequencerServer, sequencerMocks, _ := newSequencerMockedServer(t)
 	defer sequencerServer.Stop()
@@ -3630,7 +4205,7 @@ func TestNewFilter(t *testing.T) {
 					Once()
 
 				m.Storage.
-					On("NewLogFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{}), mock.IsType(LogFilter{})).
+					On("NewLogFilter", mock.IsType(&concurrentWsConn{}), mock.IsType(LogFilter{})).
 					Return("1", nil).
 					Once()
 			},
@@ -3654,7 +4229,7 @@ func TestNewFilter(t *testing.T) {
 					Once()
 
 				m.Storage.
-					On("NewLogFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{}), mock.IsType(LogFilter{})).
+					On("NewLogFilter", mock.IsType(&concurrentWsConn{}), mock.IsType(LogFilter{})).
 					Return("1", nil).
 					Once()
 			},
@@ -3686,7 +4261,7 @@ func TestNewFilter(t *testing.T) {
 				ToBlock:   &blockNumber10011,
 			},
 			ExpectedResult: "",
-			ExpectedError:  types.NewRPCError(types.InvalidParamsErrorCode, "logs are limited to a 10000 block range"),
+			ExpectedError:  types.NewRPCError(types.LimitExceededErrorCode, "logs are limited to a 10000 block range"),
 			SetupMocks: func(m *mocksWrapper, tc testCase) {
 				m.DbTx.
 					On("Rollback", context.Background()).
@@ -3717,7 +4292,7 @@ func TestNewFilter(t *testing.T) {
 					Return(m.DbTx, nil).
 					Once()
 				m.Storage.
-					On("NewLogFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{}), mock.IsType(LogFilter{})).
+					On("NewLogFilter", mock.IsType(&concurrentWsConn{}), mock.IsType(LogFilter{})).
 					Return("", errors.New("failed to add new filter")).
 					Once()
 			},
@@ -3768,7 +4343,7 @@ func TestNewBlockFilter(t *testing.T) {
 			ExpectedError:  nil,
 			SetupMocks: func(m *mocksWrapper, tc testCase) {
 				m.Storage.
-					On("NewBlockFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{})).
+					On("NewBlockFilter", mock.IsType(&concurrentWsConn{})).
 					Return("1", nil).
 					Once()
 			},
@@ -3779,7 +4354,7 @@ func TestNewBlockFilter(t *testing.T) {
 			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to create new block filter"),
 			SetupMocks: func(m *mocksWrapper, tc testCase) {
 				m.Storage.
-					On("NewBlockFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{})).
+					On("NewBlockFilter", mock.IsType(&concurrentWsConn{})).
 					Return("", errors.New("failed to add new block filter")).
 					Once()
 			},
@@ -3824,33 +4399,27 @@ func TestNewPendingTransactionFilter(t *testing.T) {
 	}
 
 	testCases := []testCase{
-		// {
-		// 	Name:           "New pending transaction filter created successfully",
-		// 	ExpectedResult: "1",
-		// 	ExpectedError:  nil,
-		// 	SetupMocks: func(m *mocks, tc testCase) {
-		// 		m.Storage.
-		// 			On("NewPendingTransactionFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{})).
-		// 			Return("1", nil).
-		// 			Once()
-		// 	},
-		// },
-		// {
-		// 	Name:           "failed to create new pending transaction filter",
-		// 	ExpectedResult: "",
-		// 	ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to create new pending transaction filter"),
-		// 	SetupMocks: func(m *mocks, tc testCase) {
-		// 		m.Storage.
-		// 			On("NewPendingTransactionFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{})).
-		// 			Return("", errors.New("failed to add new pending transaction filter")).
-		// 			Once()
-		// 	},
-		// },
-		{
-			Name:           "can't create pending tx filter",
+		{
+			Name:           "New pending transaction filter created successfully",
+			ExpectedResult: "1",
+			ExpectedError:  nil,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.Storage.
+					On("NewPendingTransactionFilter", mock.IsType(&concurrentWsConn{})).
+					Return("1", nil).
+					Once()
+			},
+		},
+		{
+			Name:           "failed to create new pending transaction filter",
 			ExpectedResult: "",
-			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "not supported yet"),
-			SetupMocks:     func(m *mocksWrapper, tc testCase) {},
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to create new pending transaction filter"),
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.Storage.
+					On("NewPendingTransactionFilter", mock.IsType(&concurrentWsConn{})).
+					Return("", errors.New("failed to add new pending transaction filter")).
+					Once()
+			},
 		},
 	}
 
@@ -4092,7 +4661,7 @@ func TestGetLogs(t *testing.T) {
 					Topics:    [][]common.Hash{{common.HexToHash("0x222")}},
 				}
 				tc.ExpectedResult = nil
-				tc.ExpectedError = types.NewRPCError(types.InvalidParamsErrorCode, "logs are limited to a 10000 block range")
+				tc.ExpectedError = types.NewRPCError(types.LimitExceededErrorCode, "logs are limited to a 10000 block range")
 			},
 			SetupMocks: func(m *mocksWrapper, tc testCase) {
 				m.DbTx.
@@ -4115,7 +4684,7 @@ func TestGetLogs(t *testing.T) {
 					Topics:    [][]common.Hash{{common.HexToHash("0x222")}},
 				}
 				tc.ExpectedResult = nil
-				tc.ExpectedError = types.NewRPCError(types.InvalidParamsErrorCode, "query returned more than 10000 results")
+				tc.ExpectedError = types.NewRPCError(types.LimitExceededErrorCode, "query returned more than 10000 results")
 			},
 			SetupMocks: func(m *mocksWrapper, tc testCase) {
 				var since *time.Time
@@ -4898,7 +5467,7 @@ func TestSubscribeNewHeads(t *testing.T) {
 			Name: "Subscribe to new heads Successfully",
 			SetupMocks: func(m *mocksWrapper, tc testCase) {
 				m.Storage.
-					On("NewBlockFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{})).
+					On("NewBlockFilter", mock.IsType(&concurrentWsConn{})).
 					Return("0x1", nil).
 					Once()
 			},
@@ -4908,7 +5477,7 @@ func TestSubscribeNewHeads(t *testing.T) {
 			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to create new block filter"),
 			SetupMocks: func(m *mocksWrapper, tc testCase) {
 				m.Storage.
-					On("NewBlockFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{})).
+					On("NewBlockFilter", mock.IsType(&concurrentWsConn{})).
 					Return("", fmt.Errorf("failed to add filter to storage")).
 					Once()
 			},
@@ -4976,7 +5545,7 @@ func TestSubscribeNewLogs(t *testing.T) {
 					Once()
 
 				m.Storage.
-					On("NewLogFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{}), mock.IsType(LogFilter{})).
+					On("NewLogFilter", mock.IsType(&concurrentWsConn{}), mock.IsType(LogFilter{})).
 					Return("0x1", nil).
 					Once()
 			},
@@ -5001,14 +5570,14 @@ func TestSubscribeNewLogs(t *testing.T) {
 					Once()
 
 				m.Storage.
-					On("NewLogFilter", mock.IsType(&atomic.Pointer[websocket.Conn]{}), mock.IsType(LogFilter{})).
+					On("NewLogFilter", mock.IsType(&concurrentWsConn{}), mock.IsType(LogFilter{})).
 					Return("", fmt.Errorf("failed to add filter to storage")).
 					Once()
 			},
 		},
 		{
 			Name:          "Subscribe to new logs fails due to max block range limit exceeded",
-			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, "logs are limited to a 10000 block range"),
+			ExpectedError: types.NewRPCError(types.LimitExceededErrorCode, "logs are limited to a 10000 block range"),
 			Prepare: func(t *testing.T, tc *testCase) {
 				tc.Filter = ethereum.FilterQuery{
 					FromBlock: big.NewInt(1), ToBlock: big.NewInt(10002),
@@ -5056,3 +5625,40 @@ func TestSubscribeNewLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestHexToTx(t *testing.T) {
+	to := common.HexToAddress("0x1")
+	txs := []*ethTypes.Transaction{
+		ethTypes.NewTransaction(1, to, big.NewInt(1), 1, big.NewInt(1), []byte{}),
+		ethTypes.NewTx(&ethTypes.AccessListTx{ChainID: big.NewInt(1), Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 1, GasPrice: big.NewInt(1)}),
+		ethTypes.NewTx(&ethTypes.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 1, GasFeeCap: big.NewInt(2), GasTipCap: big.NewInt(1)}),
+	}
+	for _, tx := range txs {
+		canonical, err := tx.MarshalBinary()
+		require.NoError(t, err)
+		wrapped, err := rlp.EncodeToBytes(tx)
+		require.NoError(t, err)
+
+		for _, encoded := range [][]byte{canonical, wrapped} {
+			decoded, err := hexToTx(hex.EncodeToHex(encoded))
+			require.NoError(t, err)
+			assert.Equal(t, tx.Type(), decoded.Type())
+			assert.Equal(t, tx.Hash(), decoded.Hash())
+		}
+	}
+
+	_, err := hexToTx("0x")
+	require.Error(t, err)
+
+	// the blob txs are decoded by geth but not supported
+	blobTx, err := ethTypes.NewTx(&ethTypes.BlobTx{ChainID: uint256.NewInt(1), Nonce: 1, To: to, Value: uint256.NewInt(1), Gas: 1,
+		GasFeeCap: uint256.NewInt(2), GasTipCap: uint256.NewInt(1), BlobFeeCap: uint256.NewInt(1), V: uint256.NewInt(0), R: uint256.NewInt(0), S: uint256.NewInt(0)}).MarshalBinary()
+	require.NoError(t, err)
+	_, err = hexToTx(hex.EncodeToHex(blobTx))
+	require.ErrorIs(t, err, ethTypes.ErrTxTypeNotSupported)
+	assert.Contains(t, err.Error(), "type 0x03")
+
+	_, err = hexToTx("0x7fc0")
+	require.ErrorIs(t, err, ethTypes.ErrTxTypeNotSupported)
+	assert.Contains(t, err.Error(), "type 0x7f")
+}
@@ -1,18 +1,56 @@
 package jsonrpc
 
 import (
+	"context"
+	"fmt"
+	"math/big"
+
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 // TxPoolEndpoints is the txpool jsonrpc endpoint
-type TxPoolEndpoints struct{}
+type TxPoolEndpoints struct {
+	pool      types.PoolInterface
+	sequencer types.SequencerInterface
+}
+
+// NewTxPoolEndpoints returns TxPoolEndpoints, the sequencer is optional and
+// it's only available when the sequencer runs in the same process
+func NewTxPoolEndpoints(pool types.PoolInterface, sequencer types.SequencerInterface) *TxPoolEndpoints {
+	return &TxPoolEndpoints{
+		pool:      pool,
+		sequencer: sequencer,
+	}
+}
+
+type statusResponse struct {
+	Pending types.ArgUint64 `json:"pending"`
+	Queued  types.ArgUint64 `json:"queued"`
+}
 
 type contentResponse struct {
 	Pending map[common.Address]map[uint64]*txPoolTransaction `json:"pending"`
 	Queued  map[common.Address]map[uint64]*txPoolTransaction `json:"queued"`
 }
 
+type contentFromResponse struct {
+	Pending map[uint64]*txPoolTransaction `json:"pending"`
+	Queued  map[uint64]*txPoolTransaction `json:"queued"`
+}
+
+// inspectResponse contains a textual summary of the pool txs and the number of
+// queued txs per account, the queued txs are the ones waiting for a previous nonce
+// or for enough balance to pay their cost
+type inspectResponse struct {
+	Pending     map[common.Address]map[uint64]string `json:"pending"`
+	Queued      map[common.Address]map[uint64]string `json:"queued"`
+	QueuedCount map[common.Address]types.ArgUint64   `json:"queuedCount"`
+}
+
 type txPoolTransaction struct {
 	Nonce       types.ArgUint64 `json:"nonce"`
 	GasPrice    types.ArgBig    `json:"gasPrice"`
@@ -27,13 +65,164 @@ type txPoolTransaction struct {
 	TxIndex     interface{}     `json:"transactionIndex"`
 }
 
+// Status creates a response for txpool_status request.
+// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_status.
+func (e *TxPoolEndpoints) Status() (interface{}, types.Error) {
+	pending, queued, err := e.getTxs(context.Background())
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get pool txs", err, true)
+	}
+
+	return statusResponse{
+		Pending: types.ArgUint64(len(pending)),
+		Queued:  types.ArgUint64(len(queued)),
+	}, nil
+}
+
 // Content creates a response for txpool_content request.
 // See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_content.
 func (e *TxPoolEndpoints) Content() (interface{}, types.Error) {
+	pending, queued, err := e.getTxs(context.Background())
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get pool txs", err, true)
+	}
+
 	resp := contentResponse{
 		Pending: make(map[common.Address]map[uint64]*txPoolTransaction),
 		Queued:  make(map[common.Address]map[uint64]*txPoolTransaction),
 	}
+	for _, tx := range pending {
+		if resp.Pending[tx.From] == nil {
+			resp.Pending[tx.From] = make(map[uint64]*txPoolTransaction)
+		}
+		resp.Pending[tx.From][uint64(tx.Nonce)] = tx
+	}
+	for _, tx := range queued {
+		if resp.Queued[tx.From] == nil {
+			resp.Queued[tx.From] = make(map[uint64]*txPoolTransaction)
+		}
+		resp.Queued[tx.From][uint64(tx.Nonce)] = tx
+	}
+
+	return resp, nil
+}
+
+// ContentFrom creates a response for txpool_contentFrom request.
+// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_contentfrom.
+func (e *TxPoolEndpoints) ContentFrom(address types.ArgAddress) (interface{}, types.Error) {
+	pending, queued, err := e.getTxs(context.Background())
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get pool txs", err, true)
+	}
+
+	from := address.Address()
+	resp := contentFromResponse{
+		Pending: make(map[uint64]*txPoolTransaction),
+		Queued:  make(map[uint64]*txPoolTransaction),
+	}
+	for _, tx := range pending {
+		if tx.From == from {
+			resp.Pending[uint64(tx.Nonce)] = tx
+		}
+	}
+	for _, tx := range queued {
+		if tx.From == from {
+			resp.Queued[uint64(tx.Nonce)] = tx
+		}
+	}
 
 	return resp, nil
 }
+
+// Inspect creates a response for txpool_inspect request.
+// See https://geth.ethereum.org/docs/rpc/ns-txpool#txpool_inspect.
+func (e *TxPoolEndpoints) Inspect() (interface{}, types.Error) {
+	pending, queued, err := e.getTxs(context.Background())
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get pool txs", err, true)
+	}
+
+	resp := inspectResponse{
+		Pending:     make(map[common.Address]map[uint64]string),
+		Queued:      make(map[common.Address]map[uint64]string),
+		QueuedCount: make(map[common.Address]types.ArgUint64),
+	}
+	for _, tx := range pending {
+		if resp.Pending[tx.From] == nil {
+			resp.Pending[tx.From] = make(map[uint64]string)
+		}
+		resp.Pending[tx.From][uint64(tx.Nonce)] = tx.summary()
+	}
+	for _, tx := range queued {
+		if resp.Queued[tx.From] == nil {
+			resp.Queued[tx.From] = make(map[uint64]string)
+		}
+		resp.Queued[tx.From][uint64(tx.Nonce)] = tx.summary()
+		resp.QueuedCount[tx.From]++
+	}
+
+	return resp, nil
+}
+
+// getTxs returns the pending txs of the pool split in pending and queued. When the
+// sequencer runs in this node the txs the worker holds as not ready (nonce gap or
+// not enough balance) are queued, otherwise all of them are considered pending
+func (e *TxPoolEndpoints) getTxs(ctx context.Context) ([]*txPoolTransaction, []*txPoolTransaction, error) {
+	poolTxs, err := e.pool.GetPendingTxs(ctx, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var notReady map[common.Hash]struct{}
+	if e.sequencer != nil {
+		workerTxs, err := e.sequencer.GetWorkerTxs()
+		if err != nil {
+			log.Warnf("failed to get the worker txs, all the pool txs are reported as pending: %v", err)
+		} else {
+			notReady = workerTxs.NotReady
+		}
+	}
+
+	var pending, queued []*txPoolTransaction
+	for _, poolTx := range poolTxs {
+		tx, err := newTxPoolTransaction(poolTx)
+		if err != nil {
+			log.Warnf("failed to get the sender of pool tx %s: %v", poolTx.Hash().String(), err)
+			continue
+		}
+		if _, found := notReady[tx.Hash]; found {
+			queued = append(queued, tx)
+		} else {
+			pending = append(pending, tx)
+		}
+	}
+
+	return pending, queued, nil
+}
+
+func newTxPoolTransaction(poolTx pool.Transaction) (*txPoolTransaction, error) {
+	from, err := state.GetSender(poolTx.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txPoolTransaction{
+		Nonce:    types.ArgUint64(poolTx.Nonce()),
+		GasPrice: types.ArgBig(*poolTx.GasPrice()),
+		Gas:      types.ArgUint64(poolTx.Gas()),
+		To:       poolTx.To(),
+		Value:    types.ArgBig(*poolTx.Value()),
+		Input:    poolTx.Data(),
+		Hash:     poolTx.Hash(),
+		From:     from,
+	}, nil
+}
+
+// summary returns the txpool_inspect textual representation of the tx
+func (tx *txPoolTransaction) summary() string {
+	value, gasPrice := big.Int(tx.Value), big.Int(tx.GasPrice)
+	if tx.To == nil {
+		return fmt.Sprintf("contract creation: %v wei + %v gas × %v wei", &value, uint64(tx.Gas), &gasPrice)
+	}
+	return fmt.Sprintf("%s: %v wei + %v gas × %v wei", tx.To.Hex(), &value, uint64(tx.Gas), &gasPrice)
+}
@@ -0,0 +1,146 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sequencerWorkerMock struct {
+	txs     sequencer.TxsSnapshot
+	err     error
+	paused  bool
+	deleted []common.Hash
+}
+
+func (s *sequencerWorkerMock) IsFinalizerHalted() bool { return false }
+
+func (s *sequencerWorkerMock) ResumeFinalizer() error { return nil }
+
+func (s *sequencerWorkerMock) ReloadPolicy(ctx context.Context) error { return nil }
+
+func (s *sequencerWorkerMock) GetWorkerTxs() (sequencer.TxsSnapshot, error) { return s.txs, s.err }
+
+func (s *sequencerWorkerMock) IsSequencingPaused() bool { return s.paused }
+
+func (s *sequencerWorkerMock) PauseSequencing() error {
+	if s.paused {
+		return sequencer.ErrSequencingAlreadyPaused
+	}
+	s.paused = true
+	return nil
+}
+
+func (s *sequencerWorkerMock) ResumeSequencing() error {
+	if !s.paused {
+		return sequencer.ErrSequencingNotPaused
+	}
+	s.paused = false
+	return nil
+}
+
+func (s *sequencerWorkerMock) DeleteWorkerTx(hash common.Hash, from common.Address) error {
+	s.deleted = append(s.deleted, hash)
+	return nil
+}
+
+func newSignedPoolTxs(t *testing.T, nonces ...uint64) (common.Address, []pool.Transaction) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(1))
+	require.NoError(t, err)
+
+	txs := make([]pool.Transaction, 0, len(nonces))
+	for _, nonce := range nonces {
+		tx := ethTypes.NewTransaction(nonce, common.HexToAddress("0x111"), big.NewInt(2), 21000, big.NewInt(4), []byte{5, 6})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		txs = append(txs, *pool.NewTransaction(*signedTx, "", false))
+	}
+	return auth.From, txs
+}
+
+func TestTxPoolStatus(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	_, txs := newSignedPoolTxs(t, 1, 2)
+	m.Pool.
+		On("GetPendingTxs", context.Background(), uint64(0)).
+		Return(txs, nil).
+		Once()
+
+	res, err := s.JSONRPCCall("txpool_status")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result statusResponse
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.ArgUint64(2), result.Pending)
+	assert.Equal(t, types.ArgUint64(0), result.Queued)
+}
+
+func TestTxPoolContentWithWorker(t *testing.T) {
+	poolMock := mocks.NewPoolMock(t)
+	from, txs := newSignedPoolTxs(t, 1, 3)
+	poolMock.
+		On("GetPendingTxs", context.Background(), uint64(0)).
+		Return(txs, nil).
+		Twice()
+
+	seq := &sequencerWorkerMock{txs: sequencer.TxsSnapshot{
+		Ready:    map[common.Hash]struct{}{txs[0].Hash(): {}},
+		NotReady: map[common.Hash]struct{}{txs[1].Hash(): {}},
+	}}
+	e := NewTxPoolEndpoints(poolMock, seq)
+
+	res, rpcErr := e.Content()
+	require.Nil(t, rpcErr)
+	content := res.(contentResponse)
+	require.Contains(t, content.Pending, from)
+	require.Contains(t, content.Queued, from)
+	assert.Equal(t, txs[0].Hash(), content.Pending[from][1].Hash)
+	assert.Equal(t, txs[1].Hash(), content.Queued[from][3].Hash)
+
+	res, rpcErr = e.ContentFrom(types.ArgAddress(common.HexToAddress("0x222")))
+	require.Nil(t, rpcErr)
+	contentFrom := res.(contentFromResponse)
+	assert.Empty(t, contentFrom.Pending)
+	assert.Empty(t, contentFrom.Queued)
+}
+
+func TestTxPoolInspectWithWorker(t *testing.T) {
+	poolMock := mocks.NewPoolMock(t)
+	from, txs := newSignedPoolTxs(t, 1, 3, 4)
+	poolMock.
+		On("GetPendingTxs", context.Background(), uint64(0)).
+		Return(txs, nil).
+		Once()
+
+	seq := &sequencerWorkerMock{txs: sequencer.TxsSnapshot{
+		Ready:    map[common.Hash]struct{}{txs[0].Hash(): {}},
+		NotReady: map[common.Hash]struct{}{txs[1].Hash(): {}, txs[2].Hash(): {}},
+	}}
+	e := NewTxPoolEndpoints(poolMock, seq)
+
+	res, rpcErr := e.Inspect()
+	require.Nil(t, rpcErr)
+	inspect := res.(inspectResponse)
+	require.Contains(t, inspect.Pending, from)
+	require.Contains(t, inspect.Queued, from)
+	assert.Equal(t, "0x0000000000000000000000000000000000000111: 2 wei + 21000 gas × 4 wei", inspect.Pending[from][1])
+	assert.Len(t, inspect.Queued[from], 2)
+	assert.Equal(t, types.ArgUint64(2), inspect.QueuedCount[from])
+}
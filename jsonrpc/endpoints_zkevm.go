@@ -2,33 +2,70 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
+	"sync"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/websocket"
 	"github.com/jackc/pgx/v4"
 )
 
+const (
+	// defaultBridgeEventsLimit is the number of bridge events returned when no limit is provided
+	defaultBridgeEventsLimit = 100
+	// maxBridgeEventsLimit is the max number of bridge events returned, bigger limits are truncated
+	maxBridgeEventsLimit = 1000
+	// maxBatchDataIntegrityRange is the max number of batches whose data integrity is checked in a single request
+	maxBatchDataIntegrityRange = 100
+	// maxAccountChangesAddresses is the max number of addresses watched by an accountChanges subscription
+	maxAccountChangesAddresses = 1000
+)
+
 // ZKEVMEndpoints contains implementations for the "zkevm" RPC endpoints
 type ZKEVMEndpoints struct {
 	cfg      Config
+	pool     types.PoolInterface
 	state    types.StateInterface
 	etherman types.EthermanInterface
+	storage  storageInterface
 	txMan    DBTxManager
+	// witnesses is the queue of the witnesses, nil when it's disabled
+	witnesses *traceQueue
+	// calls executes the calls of zkevm_multicall the same way as eth_call
+	calls *EthEndpoints
+
+	accountChangesNotifierOnce sync.Once
 }
 
 // NewZKEVMEndpoints returns ZKEVMEndpoints
-func NewZKEVMEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *ZKEVMEndpoints {
-	return &ZKEVMEndpoints{
+func NewZKEVMEndpoints(cfg Config, pool types.PoolInterface, state types.StateInterface, etherman types.EthermanInterface, storage storageInterface) *ZKEVMEndpoints {
+	z := &ZKEVMEndpoints{
 		cfg:      cfg,
+		pool:     pool,
 		state:    state,
 		etherman: etherman,
+		storage:  storage,
+		calls:    &EthEndpoints{cfg: cfg, state: state, etherman: etherman},
+	}
+	if cfg.Witness.Enabled && cfg.Witness.Queue.Enabled {
+		witnesses, err := newTraceQueue(cfg.Witness.Queue)
+		if err != nil {
+			log.Fatalf("invalid witness queue configuration: %v", err)
+		}
+		z.witnesses = witnesses
 	}
+	return z
 }
 
 // ConsolidatedBlockNumber returns last block number related to the last verified batch
@@ -185,6 +222,263 @@ func (z *ZKEVMEndpoints) GetBatchByNumber(batchNumber types.BatchNumber, fullTx
 	})
 }
 
+// GetBatchLifecycle returns the stages of its lifecycle reached by a batch, from being closed
+// in the trusted state to being verified in L1, with the L1 txs that moved it to each stage
+func (z *ZKEVMEndpoints) GetBatchLifecycle(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		transitions, err := z.state.GetBatchLifecycle(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load the lifecycle of batch %v from state", batchNumber), err, true)
+		}
+
+		return types.NewBatchLifecycle(batchNumber, transitions), nil
+	})
+}
+
+// GetVerifiedBatchProof returns the final proof that verified a batch in L1 along with its
+// public inputs, null if the batch isn't verified or the proof wasn't generated by this node
+func (z *ZKEVMEndpoints) GetVerifiedBatchProof(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		proof, err := z.state.GetVerifiedBatchProof(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load the proof of batch %v from state", batchNumber), err, true)
+		}
+
+		return types.NewVerifiedBatchProof(*proof), nil
+	})
+}
+
+// GetAccInputHash returns the accumulated input hash of a batch as computed by this node when the
+// batch was executed, null if the batch doesn't exist or it was not executed yet
+func (z *ZKEVMEndpoints) GetAccInputHash(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		batch, err := z.state.GetBatchByNumber(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load batch from state by number %v", batchNumber), err, true)
+		}
+		if batch.AccInputHash == state.ZeroHash {
+			return nil, nil
+		}
+
+		return batch.AccInputHash, nil
+	})
+}
+
+// VerifyBatchDataIntegrity checks the stored data of the sequences including the batches of the range
+// against the accumulated input hashes stored in L1 when they were sequenced, it's intended to be used
+// by the operator to audit the batches on demand
+func (z *ZKEVMEndpoints) VerifyBatchDataIntegrity(fromBatchNumber, toBatchNumber types.ArgUint64) (interface{}, types.Error) {
+	if toBatchNumber < fromBatchNumber {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid batch range", nil, false)
+	}
+	if uint64(toBatchNumber-fromBatchNumber) >= maxBatchDataIntegrityRange {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("batch range exceeds the limit of %d batches", maxBatchDataIntegrityRange), nil, false)
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		sequences, err := z.state.GetSequencesInRange(ctx, uint64(fromBatchNumber), uint64(toBatchNumber), dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "couldn't load the sequences from state", err, true)
+		}
+
+		res := make([]types.SequenceDataIntegrity, 0, len(sequences))
+		for _, sequence := range sequences {
+			l1Info, err := z.etherman.GetSequencedBatchesInfo(ctx, sequence.ToBatchNumber)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load the L1 data of the sequence ending in batch %d", sequence.ToBatchNumber), err, true)
+			}
+			oldL1Info, err := z.etherman.GetSequencedBatchesInfo(ctx, l1Info.PreviousLastBatchSequenced)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load the L1 data of the sequence ending in batch %d", l1Info.PreviousLastBatchSequenced), err, true)
+			}
+
+			// if the sequence starts at a different batch in L1, the progression computed from the stored
+			// sequence doesn't match the L1 accumulated input hash and the sequence is reported as invalid
+			integrity, err := z.state.CheckSequenceDataIntegrity(ctx, sequence, oldL1Info.AccInputHash, l1Info.AccInputHash, dbTx)
+			if errors.Is(err, state.ErrBatchNotVirtualized) {
+				return RPCErrorResponse(types.DefaultErrorCode, err.Error(), nil, false)
+			} else if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't check the data integrity of the sequence ending in batch %d", sequence.ToBatchNumber), err, true)
+			}
+			res = append(res, types.NewSequenceDataIntegrity(*integrity))
+		}
+
+		return res, nil
+	})
+}
+
+// GetForcedBatchByNumber returns a forced batch along with its status, from pending to be
+// sequenced to verified in L1 once it's included in a batch
+func (z *ZKEVMEndpoints) GetForcedBatchByNumber(forcedBatchNumber types.ArgUint64) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		inclusion, err := z.state.GetForcedBatchInclusion(ctx, uint64(forcedBatchNumber), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load forced batch %v from state", uint64(forcedBatchNumber)), err, true)
+		}
+
+		return types.NewForcedBatch(*inclusion), nil
+	})
+}
+
+// GetInclusionProof returns the merkle patricia proofs of the inclusion of a tx and its receipt
+// in the transactions and receipts tries of its L2 block, so they can be verified against the
+// block header without trusting the node
+func (z *ZKEVMEndpoints) GetInclusionProof(hash types.ArgHash) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		receipt, err := z.state.GetTransactionReceipt(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get transaction receipt", err, true)
+		}
+
+		block, err := z.state.GetL2BlockByNumber(ctx, receipt.BlockNumber.Uint64(), dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get block %d", receipt.BlockNumber.Uint64()), err, true)
+		}
+
+		receipts := make(ethTypes.Receipts, 0, len(block.Transactions()))
+		for _, tx := range block.Transactions() {
+			txReceipt, err := z.state.GetTransactionReceipt(ctx, tx.Hash(), dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get receipt of tx %s", tx.Hash().String()), err, true)
+			}
+			receipts = append(receipts, txReceipt)
+		}
+
+		proof, err := buildInclusionProof(block, receipts, uint64(receipt.TransactionIndex))
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to build inclusion proof", err, true)
+		}
+
+		return proof, nil
+	})
+}
+
+// GetWitness returns the state witness of a block, with the accounts, storage slots and
+// code accessed by its txs along with their merkle proofs against the previous state root
+func (z *ZKEVMEndpoints) GetWitness(number types.BlockNumber) (interface{}, types.Error) {
+	return z.runWitness(func() (interface{}, types.Error) {
+		return z.blockWitness(number)
+	})
+}
+
+// GetBatchWitness returns the state witness of all the blocks of a batch
+func (z *ZKEVMEndpoints) GetBatchWitness(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.runWitness(func() (interface{}, types.Error) {
+		return z.batchWitness(batchNumber)
+	})
+}
+
+// SubmitBatchWitness submits a zkevm_getBatchWitness request to be run in background for
+// the large batches, returning the id of the job to poll its result with zkevm_getWitnessJob
+func (z *ZKEVMEndpoints) SubmitBatchWitness(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	if !z.cfg.Witness.Enabled {
+		return nil, types.NewRPCError(types.MethodNotSupportedErrorCode, "witnesses are disabled")
+	}
+	if z.witnesses == nil || !z.cfg.Witness.Queue.AsyncEnabled {
+		return nil, types.NewRPCError(types.MethodNotSupportedErrorCode, "async witnesses are disabled")
+	}
+	id, err := z.witnesses.submit("zkevm_getBatchWitness", func() (interface{}, types.Error) {
+		return z.batchWitness(batchNumber)
+	})
+	if err != nil {
+		return nil, types.NewRPCError(types.LimitExceededErrorCode, err.Error())
+	}
+	return id, nil
+}
+
+// GetWitnessJob returns the status of a witness job submitted with zkevm_submitBatchWitness
+// along with its result once it's done, the results are kept during the configured retention
+func (z *ZKEVMEndpoints) GetWitnessJob(id string) (interface{}, types.Error) {
+	if z.witnesses == nil || !z.cfg.Witness.Queue.AsyncEnabled {
+		return nil, types.NewRPCError(types.MethodNotSupportedErrorCode, "async witnesses are disabled")
+	}
+	job, found := z.witnesses.job(id)
+	if !found {
+		return nil, types.NewRPCError(types.NotFoundErrorCode, fmt.Sprintf("witness job %s not found", id))
+	}
+	return job, nil
+}
+
+// runWitness runs the witness generation through the witness queue when it's enabled
+func (z *ZKEVMEndpoints) runWitness(fn traceFn) (interface{}, types.Error) {
+	if !z.cfg.Witness.Enabled {
+		return nil, types.NewRPCError(types.MethodNotSupportedErrorCode, "witnesses are disabled")
+	}
+	if z.witnesses == nil {
+		return fn()
+	}
+	return z.witnesses.run(fn)
+}
+
+func (z *ZKEVMEndpoints) blockWitness(number types.BlockNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		block, err := z.state.GetL2BlockByNumber(ctx, blockNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("block #%d not found", blockNumber))
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get block by number", err, true)
+		}
+
+		return z.buildWitness(ctx, []*ethTypes.Block{block}, dbTx)
+	})
+}
+
+func (z *ZKEVMEndpoints) batchWitness(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		blocks, err := z.state.GetL2BlocksByBatchNumber(ctx, batchNumber, dbTx)
+		if err != nil && !errors.Is(err, state.ErrNotFound) {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load blocks of batch %v from state", batchNumber), err, true)
+		} else if len(blocks) == 0 {
+			return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("batch #%d has no blocks", batchNumber))
+		}
+
+		sortedBlocks := make([]*ethTypes.Block, 0, len(blocks))
+		for i := range blocks {
+			sortedBlocks = append(sortedBlocks, &blocks[i])
+		}
+		sort.Slice(sortedBlocks, func(i, j int) bool {
+			return sortedBlocks[i].NumberU64() < sortedBlocks[j].NumberU64()
+		})
+
+		return z.buildWitness(ctx, sortedBlocks, dbTx)
+	})
+}
+
 // GetFullBlockByNumber returns information about a block by block number
 func (z *ZKEVMEndpoints) GetFullBlockByNumber(number types.BlockNumber, fullTx bool) (interface{}, types.Error) {
 	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -288,3 +582,222 @@ func (z *ZKEVMEndpoints) GetNativeBlockHashesInRange(filter NativeBlockHashBlock
 		return nativeBlockHashes, nil
 	})
 }
+
+// GetBridgeDeposits returns the last deposits made in the L2 bridge to the destination
+// address, the newest first, so wallets can track the pending bridge operations
+func (z *ZKEVMEndpoints) GetBridgeDeposits(destinationAddress types.ArgAddress, limit *types.ArgUint64) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		deposits, err := z.state.GetBridgeDepositsByDestinationAddress(ctx, destinationAddress.Address(), bridgeEventsLimit(limit), dbTx)
+		if errors.Is(err, state.ErrBridgeIndexingDisabled) {
+			return RPCErrorResponse(types.MethodNotSupportedErrorCode, err.Error(), nil, false)
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get bridge deposits from state", err, true)
+		}
+
+		res := make([]types.BridgeDeposit, 0, len(deposits))
+		for _, deposit := range deposits {
+			res = append(res, types.NewBridgeDeposit(deposit))
+		}
+		return res, nil
+	})
+}
+
+// GetBridgeClaims returns the last claims made in the L2 bridge to the destination
+// address, the newest first
+func (z *ZKEVMEndpoints) GetBridgeClaims(destinationAddress types.ArgAddress, limit *types.ArgUint64) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		claims, err := z.state.GetBridgeClaimsByDestinationAddress(ctx, destinationAddress.Address(), bridgeEventsLimit(limit), dbTx)
+		if errors.Is(err, state.ErrBridgeIndexingDisabled) {
+			return RPCErrorResponse(types.MethodNotSupportedErrorCode, err.Error(), nil, false)
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get bridge claims from state", err, true)
+		}
+
+		res := make([]types.BridgeClaim, 0, len(claims))
+		for _, claim := range claims {
+			res = append(res, types.NewBridgeClaim(claim))
+		}
+		return res, nil
+	})
+}
+
+func bridgeEventsLimit(limit *types.ArgUint64) uint64 {
+	if limit == nil || *limit == 0 {
+		return defaultBridgeEventsLimit
+	}
+	if *limit > maxBridgeEventsLimit {
+		return maxBridgeEventsLimit
+	}
+	return uint64(*limit)
+}
+
+// GetPoolMinGasPrice returns the minimum gas price the pool is currently accepting,
+// txs with a lower gas price are rejected when sent
+func (z *ZKEVMEndpoints) GetPoolMinGasPrice() (interface{}, types.Error) {
+	if z.cfg.SequencerNodeURI != "" {
+		return z.getPoolMinGasPriceFromSequencerNode()
+	}
+	minGasPrice := z.pool.GetMinSuggestedGasPrice()
+	if minGasPrice == nil {
+		return "0x0", nil
+	}
+	return hex.EncodeBig(minGasPrice), nil
+}
+
+func (z *ZKEVMEndpoints) getPoolMinGasPriceFromSequencerNode() (interface{}, types.Error) {
+	res, err := client.JSONRPCCall(z.cfg.SequencerNodeURI, "zkevm_getPoolMinGasPrice")
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get pool min gas price from sequencer node", err, true)
+	}
+
+	if res.Error != nil {
+		return RPCErrorResponse(res.Error.Code, res.Error.Message, nil, false)
+	}
+
+	var minGasPrice types.ArgBig
+	err = json.Unmarshal(res.Result, &minGasPrice)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to read pool min gas price from sequencer node", err, true)
+	}
+	return hex.EncodeBig((*big.Int)(&minGasPrice)), nil
+}
+
+// SuggestNonceGapResolution analyzes the pending txs of the account against its nonce in the
+// state, returning the txs that fill the nonce gaps to unblock its gapped txs and the ones
+// that replace the gapped txs to cancel them, with their unsigned templates when requested
+func (z *ZKEVMEndpoints) SuggestNonceGapResolution(address types.ArgAddress, buildTxs *bool) (interface{}, types.Error) {
+	if z.cfg.SequencerNodeURI != "" {
+		return z.suggestNonceGapResolutionFromSequencerNode(address, buildTxs)
+	}
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		lastBlock, err := z.state.GetLastL2Block(ctx, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last block number from state", err, true)
+		}
+		nonce, err := z.state.GetNonce(ctx, address.Address(), lastBlock.Root())
+		if errors.Is(err, state.ErrNotFound) {
+			nonce = 0
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get nonce", err, true)
+		}
+
+		resolution, err := z.pool.SuggestNonceGapResolution(ctx, address.Address(), nonce)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to analyze the pending txs of the account", err, true)
+		}
+
+		res := types.NonceGapResolution{
+			Address:      address.Address(),
+			Nonce:        types.ArgUint64(nonce),
+			PendingNonce: types.ArgUint64(resolution.NextNonce),
+			GappedNonces: make([]types.ArgUint64, 0, len(resolution.GappedNonces)),
+			Fill:         make([]types.NonceGapResolutionTx, 0, len(resolution.Fill)),
+			Cancel:       make([]types.NonceGapResolutionTx, 0, len(resolution.Cancel)),
+		}
+		for _, gappedNonce := range resolution.GappedNonces {
+			res.GappedNonces = append(res.GappedNonces, types.ArgUint64(gappedNonce))
+		}
+		withTx := buildTxs != nil && *buildTxs
+		for _, tx := range resolution.Fill {
+			res.Fill = append(res.Fill, nonceGapResolutionTx(address.Address(), tx, withTx))
+		}
+		for _, tx := range resolution.Cancel {
+			res.Cancel = append(res.Cancel, nonceGapResolutionTx(address.Address(), tx, withTx))
+		}
+		return res, nil
+	})
+}
+
+func (z *ZKEVMEndpoints) suggestNonceGapResolutionFromSequencerNode(address types.ArgAddress, buildTxs *bool) (interface{}, types.Error) {
+	res, err := client.JSONRPCCall(z.cfg.SequencerNodeURI, "zkevm_suggestNonceGapResolution", address.Address().String(), buildTxs)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get nonce gap resolution from sequencer node", err, true)
+	}
+
+	if res.Error != nil {
+		return RPCErrorResponse(res.Error.Code, res.Error.Message, nil, false)
+	}
+
+	return res.Result, nil
+}
+
+// nonceGapResolutionTx converts a suggested tx of the pool, building the transfer of 0 to the
+// account itself when requested
+func nonceGapResolutionTx(address common.Address, tx pool.NonceGapResolutionTx, withTx bool) types.NonceGapResolutionTx {
+	res := types.NonceGapResolutionTx{
+		Nonce:    types.ArgUint64(tx.Nonce),
+		GasPrice: types.ArgBig(*tx.GasPrice),
+		Replaces: tx.Replaces,
+	}
+	if withTx {
+		res.Tx = &types.UnsignedTx{
+			From:     address,
+			To:       address,
+			Value:    types.ArgBig(*big.NewInt(0)),
+			Gas:      types.ArgUint64(tx.Gas),
+			GasPrice: types.ArgBig(*tx.GasPrice),
+			Nonce:    types.ArgUint64(tx.Nonce),
+			Input:    types.ArgBytes{},
+		}
+	}
+	return res
+}
+
+// Subscribe creates a new subscription over the zkEVM specific events. The node
+// returns a subscription id and sends the notifications of the events with it
+// through the web socket connection
+func (z *ZKEVMEndpoints) Subscribe(wsConn *concurrentWsConn, name string, addresses []types.ArgAddress) (interface{}, types.Error) {
+	switch name {
+	case "accountChanges":
+		return z.newAccountChangesSubscription(wsConn, addresses)
+	default:
+		return nil, types.NewRPCError(types.DefaultErrorCode, "invalid filter name")
+	}
+}
+
+// Unsubscribe uninstalls the subscription based on the provided filterID
+func (z *ZKEVMEndpoints) Unsubscribe(wsConn *websocket.Conn, filterID string) (interface{}, types.Error) {
+	err := z.storage.UninstallFilter(filterID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to uninstall filter", err, true)
+	}
+
+	return true, nil
+}
+
+// newAccountChangesSubscription creates a filter to notify the changes of the watched
+// accounts made by each closed batch through the provided web socket connection
+func (z *ZKEVMEndpoints) newAccountChangesSubscription(wsConn *concurrentWsConn, addresses []types.ArgAddress) (interface{}, types.Error) {
+	if z.cfg.WebSockets.AccountChangesPollingInterval.Duration <= 0 {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "account changes subscription is disabled")
+	}
+	if len(addresses) == 0 {
+		return nil, types.NewRPCError(types.InvalidParamsErrorCode, "at least one address must be watched")
+	}
+	if len(addresses) > maxAccountChangesAddresses {
+		return nil, types.NewRPCError(types.InvalidParamsErrorCode, fmt.Sprintf("account changes are limited to %d addresses", maxAccountChangesAddresses))
+	}
+
+	watched := make([]common.Address, 0, len(addresses))
+	seen := make(map[common.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		if _, found := seen[address.Address()]; found {
+			continue
+		}
+		seen[address.Address()] = struct{}{}
+		watched = append(watched, address.Address())
+	}
+
+	id, err := z.storage.NewAccountChangesFilter(wsConn, watched)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new account changes filter", err, true)
+	}
+
+	z.accountChangesNotifierOnce.Do(func() {
+		go z.notifyAccountChanges()
+	})
+
+	return id, nil
+}
@@ -4,20 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 	"testing"
 	"time"
 
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1728,3 +1736,773 @@ func signTx(tx *ethTypes.Transaction, chainID uint64) *ethTypes.Transaction {
 	signedTx, _ := auth.Signer(auth.From, tx)
 	return signedTx
 }
+
+func TestGetPoolMinGasPrice(t *testing.T) {
+	sequencerServer, sequencerMocks, _ := newSequencerMockedServer(t)
+	defer sequencerServer.Stop()
+	nonSequencerServer, _, _ := newNonSequencerMockedServer(t, sequencerServer.ServerURL)
+	defer nonSequencerServer.Stop()
+
+	minGasPrice := big.NewInt(1000000000)
+	sequencerMocks.Pool.
+		On("GetMinSuggestedGasPrice").
+		Return(minGasPrice).
+		Twice()
+
+	for _, server := range []*mockedServer{sequencerServer, nonSequencerServer} {
+		res, err := server.JSONRPCCall("zkevm_getPoolMinGasPrice")
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var result types.ArgBig
+		err = json.Unmarshal(res.Result, &result)
+		require.NoError(t, err)
+		assert.Equal(t, minGasPrice.String(), (*big.Int)(&result).String())
+	}
+}
+
+func TestGetBridgeDeposits(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	destinationAddress := common.HexToAddress("0x1")
+	deposit := state.BridgeDeposit{
+		LeafType:           0,
+		OriginNetwork:      1,
+		OriginAddress:      common.HexToAddress("0x2"),
+		DestinationNetwork: 0,
+		DestinationAddress: destinationAddress,
+		Amount:             big.NewInt(1000),
+		Metadata:           []byte{0x1},
+		DepositCount:       5,
+		BlockNumber:        10,
+		TxHash:             common.HexToHash("0x3"),
+		LogIndex:           1,
+	}
+
+	testCases := []struct {
+		Name           string
+		Params         []interface{}
+		ExpectedResult []types.BridgeDeposit
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}{
+		{
+			Name:           "get deposits with the default limit",
+			Params:         []interface{}{destinationAddress.String()},
+			ExpectedResult: []types.BridgeDeposit{types.NewBridgeDeposit(deposit)},
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBridgeDepositsByDestinationAddress", context.Background(), destinationAddress, uint64(defaultBridgeEventsLimit), m.DbTx).
+					Return([]state.BridgeDeposit{deposit}, nil).Once()
+			},
+		},
+		{
+			Name:           "get deposits with a limit bigger than the max",
+			Params:         []interface{}{destinationAddress.String(), hex.EncodeUint64(maxBridgeEventsLimit + 1)},
+			ExpectedResult: []types.BridgeDeposit{},
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBridgeDepositsByDestinationAddress", context.Background(), destinationAddress, uint64(maxBridgeEventsLimit), m.DbTx).
+					Return([]state.BridgeDeposit{}, nil).Once()
+			},
+		},
+		{
+			Name:          "get deposits with the indexing disabled",
+			Params:        []interface{}{destinationAddress.String()},
+			ExpectedError: types.NewRPCError(types.MethodNotSupportedErrorCode, state.ErrBridgeIndexingDisabled.Error()),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBridgeDepositsByDestinationAddress", context.Background(), destinationAddress, uint64(defaultBridgeEventsLimit), m.DbTx).
+					Return(nil, state.ErrBridgeIndexingDisabled).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getBridgeDeposits", tc.Params...)
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result []types.BridgeDeposit
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
+
+func TestGetForcedBatchByNumber(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	batchNumber := uint64(5)
+	virtualizedStage := state.BatchVirtualizedStage
+	forcedBatch := state.ForcedBatch{
+		BlockNumber:       10,
+		ForcedBatchNumber: 1,
+		Sequencer:         common.HexToAddress("0x1"),
+		GlobalExitRoot:    common.HexToHash("0x2"),
+		RawTxsData:        []byte{0x01, 0x02},
+		ForcedAt:          time.Unix(1700000000, 0),
+	}
+
+	testCases := []struct {
+		Name           string
+		Inclusion      *state.ForcedBatchInclusion
+		ExpectedStatus string
+		ExpectedBatch  *types.ArgUint64
+	}{
+		{
+			Name:           "pending forced batch",
+			Inclusion:      &state.ForcedBatchInclusion{ForcedBatch: forcedBatch},
+			ExpectedStatus: types.ForcedBatchPendingStatus,
+		},
+		{
+			Name:           "forced batch included in an open batch",
+			Inclusion:      &state.ForcedBatchInclusion{ForcedBatch: forcedBatch, BatchNumber: &batchNumber},
+			ExpectedStatus: types.ForcedBatchProcessingStatus,
+			ExpectedBatch:  ptrArgUint64FromUint64(batchNumber),
+		},
+		{
+			Name:           "forced batch included in a virtualized batch",
+			Inclusion:      &state.ForcedBatchInclusion{ForcedBatch: forcedBatch, BatchNumber: &batchNumber, Stage: &virtualizedStage},
+			ExpectedStatus: string(state.BatchVirtualizedStage),
+			ExpectedBatch:  ptrArgUint64FromUint64(batchNumber),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+			m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			m.State.On("GetForcedBatchInclusion", context.Background(), uint64(1), m.DbTx).Return(tc.Inclusion, nil).Once()
+
+			res, err := s.JSONRPCCall("zkevm_getForcedBatchByNumber", hex.EncodeUint64(1))
+			require.NoError(t, err)
+			require.Nil(t, res.Error)
+
+			var result types.ForcedBatch
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedStatus, result.Status)
+			assert.Equal(t, tc.ExpectedBatch, result.BatchNumber)
+			assert.Equal(t, types.ArgUint64(1), result.ForcedBatchNumber)
+			assert.Equal(t, forcedBatch.Sequencer, result.Sequencer)
+			assert.Equal(t, types.ArgBytes(forcedBatch.RawTxsData), result.RawTxsData)
+			assert.Equal(t, types.ArgUint64(forcedBatch.ForcedAt.Unix()), result.Timestamp)
+		})
+	}
+
+	t.Run("forced batch not found", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetForcedBatchInclusion", context.Background(), uint64(1), m.DbTx).Return(nil, state.ErrNotFound).Once()
+
+		res, err := s.JSONRPCCall("zkevm_getForcedBatchByNumber", hex.EncodeUint64(1))
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		assert.Equal(t, "null", string(res.Result))
+	})
+}
+
+func TestGetVerifiedBatchProof(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	proof := state.VerifiedBatchProof{
+		BatchNumber:      1,
+		BatchNumberFinal: 3,
+		Proof:            "0x1234",
+		PublicInputs: state.FinalProofPublicInputs{
+			OldStateRoot:   common.HexToHash("0x1"),
+			ChainID:        1001,
+			ForkID:         7,
+			NewStateRoot:   common.HexToHash("0x2"),
+			NewBatchNum:    3,
+			AggregatorAddr: common.HexToAddress("0x3"),
+		},
+		L1TxHash:      common.HexToHash("0x4"),
+		L1BlockNumber: 10,
+	}
+
+	testCases := []struct {
+		Name           string
+		ExpectedResult *types.VerifiedBatchProof
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}{
+		{
+			Name: "get the proof of a verified batch",
+			ExpectedResult: func() *types.VerifiedBatchProof {
+				res := types.NewVerifiedBatchProof(proof)
+				return &res
+			}(),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetVerifiedBatchProof", context.Background(), uint64(2), m.DbTx).Return(&proof, nil).Once()
+			},
+		},
+		{
+			Name:           "get the proof of a batch that isn't verified",
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetVerifiedBatchProof", context.Background(), uint64(2), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:          "failed to get the proof of the batch",
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "couldn't load the proof of batch 2 from state"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetVerifiedBatchProof", context.Background(), uint64(2), m.DbTx).Return(nil, errors.New("failed to get proof")).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getVerifiedBatchProof", hex.EncodeUint64(2))
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result *types.VerifiedBatchProof
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
+
+func TestGetBatchLifecycle(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	l1TxHash := common.HexToHash("0x1")
+	l1BlockNumber := uint64(10)
+	timestamp := time.Unix(1700000000, 0)
+	transitions := []state.BatchLifecycleTransition{
+		{BatchNumber: 1, Stage: state.BatchTrustedStage, Timestamp: timestamp},
+		{BatchNumber: 1, Stage: state.BatchVirtualizedStage, L1TxHash: &l1TxHash, L1BlockNumber: &l1BlockNumber, Timestamp: timestamp.Add(time.Minute)},
+	}
+
+	testCases := []struct {
+		Name           string
+		ExpectedResult *types.BatchLifecycle
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}{
+		{
+			Name: "get the lifecycle of a virtualized batch",
+			ExpectedResult: func() *types.BatchLifecycle {
+				lifecycle := types.NewBatchLifecycle(1, transitions)
+				return &lifecycle
+			}(),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBatchLifecycle", context.Background(), uint64(1), m.DbTx).Return(transitions, nil).Once()
+			},
+		},
+		{
+			Name:           "get the lifecycle of a batch that doesn't exist",
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBatchLifecycle", context.Background(), uint64(1), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:          "failed to get the lifecycle of the batch",
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "couldn't load the lifecycle of batch 1 from state"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBatchLifecycle", context.Background(), uint64(1), m.DbTx).Return(nil, errors.New("failed to get lifecycle")).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getBatchLifecycle", hex.EncodeUint64(1))
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result *types.BatchLifecycle
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
+
+func TestGetAccInputHash(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	accInputHash := common.HexToHash("0x1")
+
+	testCases := []struct {
+		Name           string
+		ExpectedResult *common.Hash
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}{
+		{
+			Name:           "get the acc input hash of an executed batch",
+			ExpectedResult: &accInputHash,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBatchByNumber", context.Background(), uint64(1), m.DbTx).Return(&state.Batch{BatchNumber: 1, AccInputHash: accInputHash}, nil).Once()
+			},
+		},
+		{
+			Name:           "get the acc input hash of a batch not executed yet",
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBatchByNumber", context.Background(), uint64(1), m.DbTx).Return(&state.Batch{BatchNumber: 1}, nil).Once()
+			},
+		},
+		{
+			Name:           "get the acc input hash of a batch that doesn't exist",
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBatchByNumber", context.Background(), uint64(1), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+		{
+			Name:          "failed to get the batch",
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "couldn't load batch from state by number 1"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetBatchByNumber", context.Background(), uint64(1), m.DbTx).Return(nil, errors.New("failed to get batch")).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getAccInputHash", hex.EncodeUint64(1))
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result *common.Hash
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
+
+func TestVerifyBatchDataIntegrity(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	sequence := state.Sequence{FromBatchNumber: 4, ToBatchNumber: 6}
+	oldAccInputHash := common.HexToHash("0x1")
+	l1AccInputHash := common.HexToHash("0x2")
+	integrity := &state.SequenceDataIntegrity{
+		FromBatchNumber:   4,
+		ToBatchNumber:     6,
+		L1AccInputHash:    l1AccInputHash,
+		AccInputHash:      common.HexToHash("0x3"),
+		MismatchedBatches: []uint64{5},
+	}
+
+	testCases := []struct {
+		Name           string
+		From, To       uint64
+		ExpectedResult []types.SequenceDataIntegrity
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}{
+		{
+			Name:           "verify the data integrity of a batch range",
+			From:           5,
+			To:             6,
+			ExpectedResult: []types.SequenceDataIntegrity{types.NewSequenceDataIntegrity(*integrity)},
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetSequencesInRange", context.Background(), uint64(5), uint64(6), m.DbTx).Return([]state.Sequence{sequence}, nil).Once()
+				m.Etherman.On("GetSequencedBatchesInfo", context.Background(), uint64(6)).Return(ethmanTypes.SequencedBatchesInfo{AccInputHash: l1AccInputHash, PreviousLastBatchSequenced: 3}, nil).Once()
+				m.Etherman.On("GetSequencedBatchesInfo", context.Background(), uint64(3)).Return(ethmanTypes.SequencedBatchesInfo{AccInputHash: oldAccInputHash}, nil).Once()
+				m.State.On("CheckSequenceDataIntegrity", context.Background(), sequence, oldAccInputHash, l1AccInputHash, m.DbTx).Return(integrity, nil).Once()
+			},
+		},
+		{
+			Name:          "batch range not virtualized",
+			From:          5,
+			To:            6,
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "batch 4: batch is not virtualized"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetSequencesInRange", context.Background(), uint64(5), uint64(6), m.DbTx).Return([]state.Sequence{sequence}, nil).Once()
+				m.Etherman.On("GetSequencedBatchesInfo", context.Background(), uint64(6)).Return(ethmanTypes.SequencedBatchesInfo{AccInputHash: l1AccInputHash, PreviousLastBatchSequenced: 3}, nil).Once()
+				m.Etherman.On("GetSequencedBatchesInfo", context.Background(), uint64(3)).Return(ethmanTypes.SequencedBatchesInfo{AccInputHash: oldAccInputHash}, nil).Once()
+				m.State.On("CheckSequenceDataIntegrity", context.Background(), sequence, oldAccInputHash, l1AccInputHash, m.DbTx).Return(nil, fmt.Errorf("batch 4: %w", state.ErrBatchNotVirtualized)).Once()
+			},
+		},
+		{
+			Name:          "batch range too big",
+			From:          1,
+			To:            1 + maxBatchDataIntegrityRange,
+			ExpectedError: types.NewRPCError(types.InvalidParamsErrorCode, fmt.Sprintf("batch range exceeds the limit of %d batches", maxBatchDataIntegrityRange)),
+			SetupMocks:    func(m *mocksWrapper) {},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_verifyBatchDataIntegrity", hex.EncodeUint64(tc.From), hex.EncodeUint64(tc.To))
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result []types.SequenceDataIntegrity
+			require.NoError(t, json.Unmarshal(res.Result, &result))
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
+
+func TestGetWitness(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.Witness = WitnessConfig{Enabled: true, MaxStorageSlots: 2}
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	stateRoot := common.HexToHash("0x1234")
+	contract, sender := common.HexToAddress("0x2"), common.HexToAddress("0x1")
+	key1, key2, key3 := common.HexToHash("0x11"), common.HexToHash("0x12"), common.HexToHash("0x13")
+	tx1 := ethTypes.NewTransaction(1, contract, big.NewInt(1), 21000, big.NewInt(1), nil)
+	tx2 := ethTypes.NewTransaction(2, contract, big.NewInt(1), 21000, big.NewInt(1), nil)
+	block := ethTypes.NewBlock(&ethTypes.Header{Number: big.NewInt(2)}, []*ethTypes.Transaction{tx1, tx2}, nil, nil, &trie.StackTrie{})
+	isPrestateTracer := mock.MatchedBy(func(cfg state.TraceConfig) bool {
+		return cfg.Tracer != nil && *cfg.Tracer == witnessTracer
+	})
+	prestate := func(accounts string) *runtime.ExecutionResult {
+		return &runtime.ExecutionResult{ExecutorTraceResult: json.RawMessage(accounts)}
+	}
+	keyProof := &merkletree.KeyProof{Key: []uint64{1, 0, 0, 0}, Value: big.NewInt(1), Siblings: [][]uint64{{5, 6, 7, 8}}}
+	accountProof := func(address common.Address, storageKeys ...common.Hash) *state.AccountProof {
+		proof := &state.AccountProof{
+			Address: address, Balance: big.NewInt(1), Nonce: big.NewInt(1), CodeLength: big.NewInt(0),
+			BalanceProof: keyProof, NonceProof: keyProof, CodeHashProof: keyProof, CodeLengthProof: keyProof,
+		}
+		for _, key := range storageKeys {
+			proof.StorageProofs = append(proof.StorageProofs, state.StorageProof{Key: key, Value: big.NewInt(1), Proof: keyProof})
+		}
+		return proof
+	}
+
+	t.Run("get the witness of a block", func(t *testing.T) {
+		m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2BlockByNumber", context.Background(), uint64(2), m.DbTx).Return(block, nil).Once()
+		m.State.On("GetStateRootByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return(stateRoot, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), tx1.Hash(), isPrestateTracer, m.DbTx).
+			Return(prestate(`{"0x0000000000000000000000000000000000000001":{"balance":"0x1"},"0x0000000000000000000000000000000000000002":{"code":"0x6001","storage":{"0x0000000000000000000000000000000000000000000000000000000000000011":"0x0000000000000000000000000000000000000000000000000000000000000001"}}}`), nil).Once()
+		m.State.On("DebugTransaction", context.Background(), tx2.Hash(), isPrestateTracer, m.DbTx).
+			Return(prestate(`{"0x0000000000000000000000000000000000000002":{"code":"0x6001","storage":{"0x0000000000000000000000000000000000000000000000000000000000000011":"0x0000000000000000000000000000000000000000000000000000000000000002","0x0000000000000000000000000000000000000000000000000000000000000012":"0x0000000000000000000000000000000000000000000000000000000000000000"}}}`), nil).Once()
+		m.State.On("GetProof", context.Background(), sender, []common.Hash{}, stateRoot).Return(accountProof(sender), nil).Once()
+		m.State.On("GetProof", context.Background(), contract, []common.Hash{key1, key2}, stateRoot).Return(accountProof(contract, key1, key2), nil).Once()
+
+		res, err := s.JSONRPCCall("zkevm_getWitness", hex.EncodeUint64(2))
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+
+		var witness types.Witness
+		require.NoError(t, json.Unmarshal(res.Result, &witness))
+		assert.Equal(t, types.ArgUint64(2), witness.FromBlock)
+		assert.Equal(t, types.ArgUint64(2), witness.ToBlock)
+		assert.Equal(t, stateRoot, witness.StateRoot)
+		require.Len(t, witness.Accounts, 2)
+		assert.Equal(t, sender, witness.Accounts[0].Address)
+		assert.Empty(t, witness.Accounts[0].Code)
+		assert.Equal(t, contract, witness.Accounts[1].Address)
+		assert.Equal(t, types.ArgBytes{0x60, 0x01}, witness.Accounts[1].Code)
+		assert.Len(t, witness.Accounts[1].StorageProof, 2)
+	})
+
+	t.Run("witness over the size limit", func(t *testing.T) {
+		m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+		m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+		m.State.On("GetL2BlockByNumber", context.Background(), uint64(2), m.DbTx).Return(block, nil).Once()
+		m.State.On("GetStateRootByL2BlockNumber", context.Background(), uint64(1), m.DbTx).Return(stateRoot, nil).Once()
+		m.State.On("DebugTransaction", context.Background(), tx1.Hash(), isPrestateTracer, m.DbTx).
+			Return(prestate(`{"0x0000000000000000000000000000000000000002":{"storage":{"0x0000000000000000000000000000000000000000000000000000000000000011":"0x0000000000000000000000000000000000000000000000000000000000000001","0x0000000000000000000000000000000000000000000000000000000000000012":"0x0000000000000000000000000000000000000000000000000000000000000001","`+key3.String()+`":"0x0000000000000000000000000000000000000000000000000000000000000001"}}}`), nil).Once()
+
+		res, err := s.JSONRPCCall("zkevm_getWitness", hex.EncodeUint64(2))
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.LimitExceededErrorCode, res.Error.Code)
+		assert.Equal(t, "witness exceeds the size limit of 2 storage slots", res.Error.Message)
+	})
+
+	t.Run("async witnesses disabled", func(t *testing.T) {
+		res, err := s.JSONRPCCall("zkevm_submitBatchWitness", hex.EncodeUint64(1))
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.MethodNotSupportedErrorCode, res.Error.Code)
+	})
+}
+
+func TestSuggestNonceGapResolution(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	address := common.HexToAddress("0x1")
+	root := common.HexToHash("0x1234")
+	block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: big.NewInt(2), Root: root})
+	replaced := common.HexToHash("0x99")
+	resolution := &pool.NonceGapResolution{
+		NextNonce:    4,
+		GappedNonces: []uint64{6},
+		Fill:         []pool.NonceGapResolutionTx{{Nonce: 4, Gas: 21000, GasPrice: big.NewInt(10)}, {Nonce: 5, Gas: 21000, GasPrice: big.NewInt(10)}},
+		Cancel:       []pool.NonceGapResolutionTx{{Nonce: 6, Gas: 21000, GasPrice: big.NewInt(20), Replaces: &replaced}},
+	}
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
+	m.State.On("GetNonce", context.Background(), address, root).Return(uint64(3), nil).Once()
+	m.Pool.On("SuggestNonceGapResolution", context.Background(), address, uint64(3)).Return(resolution, nil).Once()
+
+	res, err := s.JSONRPCCall("zkevm_suggestNonceGapResolution", address.String(), true)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result types.NonceGapResolution
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, address, result.Address)
+	assert.Equal(t, types.ArgUint64(3), result.Nonce)
+	assert.Equal(t, types.ArgUint64(4), result.PendingNonce)
+	assert.Equal(t, []types.ArgUint64{6}, result.GappedNonces)
+	require.Len(t, result.Fill, 2)
+	assert.Equal(t, types.ArgUint64(5), result.Fill[1].Nonce)
+	assert.Nil(t, result.Fill[1].Replaces)
+	require.NotNil(t, result.Fill[1].Tx)
+	assert.Equal(t, address, result.Fill[1].Tx.To)
+	assert.Equal(t, types.ArgUint64(21000), result.Fill[1].Tx.Gas)
+	require.Len(t, result.Cancel, 1)
+	assert.Equal(t, replaced, *result.Cancel[0].Replaces)
+	assert.Equal(t, uint64(20), (*big.Int)(&result.Cancel[0].GasPrice).Uint64())
+	assert.Equal(t, uint64(20), (*big.Int)(&result.Cancel[0].Tx.GasPrice).Uint64())
+}
+
+func TestSubscribeAccountChanges(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.WebSockets.AccountChangesPollingInterval = cfgTypes.NewDuration(10 * time.Millisecond)
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	changed := common.HexToAddress("0x1")
+	unchanged := common.HexToAddress("0x2")
+	previousRoot := common.HexToHash("0x1234")
+	root := common.HexToHash("0x5678")
+
+	var wsConn *concurrentWsConn
+	m.Storage.
+		On("NewAccountChangesFilter", mock.IsType(&concurrentWsConn{}), []common.Address{changed, unchanged}).
+		Run(func(args mock.Arguments) { wsConn = args.Get(0).(*concurrentWsConn) }).
+		Return("0x1", nil).
+		Once()
+	m.Storage.
+		On("GetAllAccountChangesFiltersWithWSConn").
+		Return(func() ([]*Filter, error) {
+			return []*Filter{{ID: "0x1", Type: FilterTypeAccountChanges, Parameters: []common.Address{changed, unchanged}, WsConn: wsConn}}, nil
+		})
+	// the filters of the connection are uninstalled when it's closed
+	m.Storage.On("UninstallFilterByWSConn", mock.IsType(&concurrentWsConn{})).Return(nil).Maybe()
+	m.State.On("GetLastClosedBatchNumber", context.Background(), nil).Return(uint64(1), nil).Once()
+	m.State.On("GetLastClosedBatchNumber", context.Background(), nil).Return(uint64(2), nil)
+	m.State.On("GetBatchByNumber", context.Background(), uint64(1), nil).Return(&state.Batch{BatchNumber: 1, StateRoot: previousRoot}, nil).Once()
+	m.State.On("GetBatchByNumber", context.Background(), uint64(2), nil).Return(&state.Batch{BatchNumber: 2, StateRoot: root}, nil).Once()
+	m.State.On("GetProof", context.Background(), changed, []common.Hash(nil), previousRoot).
+		Return(&state.AccountProof{Address: changed, Balance: big.NewInt(10), Nonce: big.NewInt(0)}, nil).Once()
+	m.State.On("GetProof", context.Background(), changed, []common.Hash(nil), root).
+		Return(&state.AccountProof{Address: changed, Balance: big.NewInt(7), Nonce: big.NewInt(1)}, nil).Once()
+	m.State.On("GetProof", context.Background(), unchanged, []common.Hash(nil), mock.Anything).
+		Return(&state.AccountProof{Address: unchanged, Balance: big.NewInt(5), Nonce: big.NewInt(3)}, nil).Twice()
+
+	ctx := context.Background()
+	c, err := rpc.DialWebsocket(ctx, s.ServerWebSocketsURL, "")
+	require.NoError(t, err)
+	defer c.Close()
+
+	changes := make(chan types.AccountChange, 10)
+	// the duplicated addresses are only watched once
+	sub, err := c.Subscribe(ctx, "zkevm", changes, "accountChanges", []common.Address{changed, unchanged, changed})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, changed, change.Address)
+		assert.Equal(t, types.ArgUint64(2), change.BatchNumber)
+		assert.Equal(t, root, change.StateRoot)
+		assert.Equal(t, uint64(7), (*big.Int)(&change.Balance).Uint64())
+		assert.Equal(t, uint64(1), (*big.Int)(&change.Nonce).Uint64())
+	case err := <-sub.Err():
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "account change not notified")
+	}
+
+	select {
+	case change := <-changes:
+		require.Failf(t, "unexpected account change", "%v", change.Address.String())
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSubscribeAccountChangesInvalidParams(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	m.Storage.On("UninstallFilterByWSConn", mock.IsType(&concurrentWsConn{})).Return(nil).Maybe()
+
+	ctx := context.Background()
+	c, err := rpc.DialWebsocket(ctx, s.ServerWebSocketsURL, "")
+	require.NoError(t, err)
+	defer c.Close()
+
+	changes := make(chan types.AccountChange)
+	_, err = c.Subscribe(ctx, "zkevm", changes, "accountChanges", []common.Address{common.HexToAddress("0x1")})
+	require.Error(t, err)
+	assert.Equal(t, "account changes subscription is disabled", err.Error())
+
+	_, err = c.Subscribe(ctx, "zkevm", changes, "unknown", []common.Address{common.HexToAddress("0x1")})
+	require.Error(t, err)
+	assert.Equal(t, "invalid filter name", err.Error())
+}
+
+func TestMulticall(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: big.NewInt(10), Root: common.HexToHash("0x123")})
+	blockNumber := block.NumberU64()
+	sender := common.HexToAddress(DefaultSenderAddress)
+	toMatchBy := func(to common.Address) interface{} {
+		return mock.MatchedBy(func(tx *ethTypes.Transaction) bool {
+			return tx != nil && tx.To() != nil && *tx.To() == to
+		})
+	}
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetLastL2Block", context.Background(), m.DbTx).Return(block, nil).Once()
+	m.State.
+		On("ProcessUnsignedTransaction", context.Background(), toMatchBy(common.HexToAddress("0x1")), sender, &blockNumber, true, state.StateOverride(nil), nil).
+		Return(&runtime.ExecutionResult{ReturnValue: []byte{1}}, nil).
+		Once()
+	m.State.
+		On("ProcessUnsignedTransaction", context.Background(), toMatchBy(common.HexToAddress("0x2")), sender, &blockNumber, true, state.StateOverride(nil), nil).
+		Return(&runtime.ExecutionResult{ReturnValue: []byte{2}, Err: runtime.ErrExecutionReverted}, nil).
+		Once()
+	m.State.
+		On("ProcessUnsignedTransaction", context.Background(), toMatchBy(common.HexToAddress("0x3")), sender, &blockNumber, true, state.StateOverride(nil), nil).
+		Return(nil, errors.New("failed to process")).
+		Once()
+
+	calls := []types.TxArgs{
+		{To: state.HexToAddressPtr("0x1"), Gas: types.ArgUint64Ptr(21000)},
+		{To: state.HexToAddressPtr("0x2"), Gas: types.ArgUint64Ptr(21000)},
+		{To: state.HexToAddressPtr("0x3"), Gas: types.ArgUint64Ptr(21000)},
+	}
+	res, err := s.JSONRPCCall("zkevm_multicall", calls)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var results []types.MulticallResult
+	require.NoError(t, json.Unmarshal(res.Result, &results))
+	require.Len(t, results, 3)
+
+	require.Nil(t, results[0].Error)
+	assert.Equal(t, types.ArgBytes{1}, *results[0].ReturnData)
+
+	require.NotNil(t, results[1].Error)
+	assert.Nil(t, results[1].ReturnData)
+	assert.Equal(t, types.RevertedErrorCode, results[1].Error.Code)
+	assert.Equal(t, runtime.ErrExecutionReverted.Error(), results[1].Error.Message)
+	assert.Equal(t, types.ArgBytes{2}, *results[1].Error.Data)
+
+	require.NotNil(t, results[2].Error)
+	assert.Equal(t, types.DefaultErrorCode, results[2].Error.Code)
+	assert.Equal(t, "failed to execute the unsigned transaction", results[2].Error.Message)
+
+	// the number of calls is limited
+	res, err = s.JSONRPCCall("zkevm_multicall", append(calls, calls[0]))
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.LimitExceededErrorCode, res.Error.Code)
+
+	res, err = s.JSONRPCCall("zkevm_multicall", []types.TxArgs{})
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.InvalidParamsErrorCode, res.Error.Code)
+}
@@ -1,23 +1,36 @@
 package jsonrpc
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
-	"sync/atomic"
+	"time"
 	"unicode"
 
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
-	"github.com/gorilla/websocket"
 )
 
 const (
 	requiredReturnParamsPerFn = 2
 )
 
+// stateMutatingMethods are the methods disabled in read-only mode besides
+// the whole admin namespace
+var stateMutatingMethods = map[string]struct{}{
+	"eth_sendRawTransaction":            {},
+	"eth_sendRawTransactionConditional": {},
+	"eth_sendTransaction":               {},
+	"eth_newFilter":                     {},
+	"eth_newBlockFilter":                {},
+	"eth_newPendingTransactionFilter":   {},
+	"eth_uninstallFilter":               {},
+}
+
 type serviceData struct {
 	sv      reflect.Value
 	funcMap map[string]*funcData
@@ -36,7 +49,7 @@ func (f *funcData) numParams() int {
 
 type handleRequest struct {
 	types.Request
-	wsConn      *atomic.Pointer[websocket.Conn]
+	wsConn      *concurrentWsConn
 	HttpRequest *http.Request
 }
 
@@ -64,6 +77,24 @@ type handleRequest struct {
 // check the `eth.go` file for more example on how the methods are implemented
 type Handler struct {
 	serviceMap map[string]*serviceData
+	// clientIP resolves the IP of the client used by the per IP limits and the audit log
+	clientIP *clientIPResolver
+	// paramsSizeLimits contains the max size of each param field per method
+	paramsSizeLimits map[string]uint64
+	// rateLimiter limits the requests per IP and method, nil if the rate limit is disabled
+	rateLimiter *rateLimiter
+	// concurrencyLimiter limits the requests handled at the same time, nil if the concurrency limit is disabled
+	concurrencyLimiter *concurrencyLimiter
+	// auditLog logs a sample of the handled requests, nil if the audit log is disabled
+	auditLog *auditLog
+	// readOnly rejects the methods that mutate the state of the node
+	readOnly bool
+	// disabledMethods contains the methods that are not exposed even if their namespace is enabled
+	disabledMethods map[string]struct{}
+	// adminAuthToken is the token required by the admin methods, empty if they don't require it
+	adminAuthToken string
+	// warmUp rejects the state dependent methods until the node is synced, nil if the warm up is disabled
+	warmUp *warmUpGate
 }
 
 func newJSONRpcHandler() *Handler {
@@ -76,14 +107,56 @@ func newJSONRpcHandler() *Handler {
 // Handle is the function that knows which and how a function should
 // be executed when a JSON RPC request is received
 func (h *Handler) Handle(req handleRequest) types.Response {
+	start := time.Now()
+	ip := h.clientIP.ip(req.HttpRequest)
+	resp := h.handle(req, ip)
+
+	method := req.Method
+	if resp.Error != nil && resp.Error.Code == types.NotFoundErrorCode {
+		method = metrics.UnknownMethodLabel
+	}
+	metrics.MethodHandled(method, resp.Error != nil, start)
+	if h.auditLog != nil {
+		h.auditLog.record(req, ip, resp, start)
+	}
+
+	return resp
+}
+
+func (h *Handler) handle(req handleRequest, ip string) types.Response {
 	log := log.WithFields("method", req.Method, "requestId", req.ID)
 	log.Debugf("request params %v", string(req.Params))
 
+	if h.rateLimiter != nil && !h.rateLimiter.allow(ip, req.Method, time.Now()) {
+		return types.NewResponse(req.Request, nil, types.NewRPCError(types.LimitExceededErrorCode, "rate limit exceeded for method %s", req.Method))
+	}
+
+	if h.concurrencyLimiter != nil {
+		if err := h.concurrencyLimiter.acquire(ip); err != nil {
+			return types.NewResponse(req.Request, nil, types.NewRPCError(types.LimitExceededErrorCode, err.Error()))
+		}
+		defer h.concurrencyLimiter.release(ip)
+	}
+
+	if h.readOnly && isStateMutatingMethod(req.Method) {
+		return types.NewResponse(req.Request, nil, types.NewRPCError(types.MethodNotSupportedErrorCode, "method %s is not available, the node is in read-only mode", req.Method))
+	}
+
+	if strings.HasPrefix(req.Method, APIAdmin+"_") && !h.isAdminAuthorized(req.HttpRequest) {
+		return types.NewResponse(req.Request, nil, types.NewRPCError(types.UnauthorizedErrorCode, "unauthorized"))
+	}
+
 	service, fd, err := h.getFnHandler(req.Request)
 	if err != nil {
 		return types.NewResponse(req.Request, nil, err)
 	}
 
+	if h.warmUp != nil {
+		if err := h.warmUp.allow(req.Method); err != nil {
+			return types.NewResponse(req.Request, nil, err)
+		}
+	}
+
 	inArgsOffset := 0
 	inArgs := make([]reflect.Value, fd.inNum)
 	inArgs[0] = service.sv
@@ -93,7 +166,7 @@ func (h *Handler) Handle(req handleRequest) types.Response {
 	firstFuncParamIsWebSocketConn := false
 	firstFuncParamIsHttpRequest := false
 	if funcHasMoreThanOneInputParams {
-		firstFuncParamIsWebSocketConn = fd.reqt[1].AssignableTo(reflect.TypeOf(&atomic.Pointer[websocket.Conn]{}))
+		firstFuncParamIsWebSocketConn = fd.reqt[1].AssignableTo(reflect.TypeOf(&concurrentWsConn{}))
 		firstFuncParamIsHttpRequest = fd.reqt[1].AssignableTo(reflect.TypeOf(&http.Request{}))
 	}
 	if requestHasWebSocketConn && firstFuncParamIsWebSocketConn {
@@ -106,9 +179,14 @@ func (h *Handler) Handle(req handleRequest) types.Response {
 		inArgsOffset++
 	}
 
+	if limit, found := h.paramsSizeLimits[req.Method]; found {
+		if err := checkParamsSize(req.Params, limit); err != nil {
+			return types.NewResponse(req.Request, nil, types.NewRPCError(types.InvalidParamsErrorCode, err.Error()))
+		}
+	}
+
 	// check params passed by request match function params
-	var testStruct []interface{}
-	if err := json.Unmarshal(req.Params, &testStruct); err == nil && len(testStruct) > fd.numParams() {
+	if count, ok := countParams(req.Params); ok && count > fd.numParams() {
 		return types.NewResponse(req.Request, nil, types.NewRPCError(types.InvalidParamsErrorCode, fmt.Sprintf("too many arguments, want at most %d", fd.numParams())))
 	}
 
@@ -142,8 +220,38 @@ func (h *Handler) Handle(req handleRequest) types.Response {
 	return types.NewResponse(req.Request, data, nil)
 }
 
+// isStateMutatingMethod checks if the method mutates the state of the node
+func isStateMutatingMethod(method string) bool {
+	if strings.HasPrefix(method, APIAdmin+"_") {
+		return true
+	}
+	_, found := stateMutatingMethods[method]
+	return found
+}
+
+// isAdminAuthorized checks if the http request sends the admin auth token in its
+// authorization header, the ws requests are checked against the upgrade request. The
+// ipc requests are authorized, the access to the socket is restricted by its permissions.
+// Without an admin auth token the admin namespace is only served through the ipc socket
+func (h *Handler) isAdminAuthorized(httpReq *http.Request) bool {
+	if httpReq == nil {
+		return false
+	}
+	if isIPCRequest(httpReq) {
+		return true
+	}
+	if h.adminAuthToken == "" {
+		return false
+	}
+	token, found := strings.CutPrefix(httpReq.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.adminAuthToken)) == 1
+}
+
 // HandleWs handle websocket requests
-func (h *Handler) HandleWs(reqBody []byte, wsConn *atomic.Pointer[websocket.Conn], httpReq *http.Request) ([]byte, error) {
+func (h *Handler) HandleWs(reqBody []byte, wsConn *concurrentWsConn, httpReq *http.Request) ([]byte, error) {
 	log.Debugf("WS message received: %v", string(reqBody))
 	var req types.Request
 	if err := json.Unmarshal(reqBody, &req); err != nil {
@@ -160,7 +268,7 @@ func (h *Handler) HandleWs(reqBody []byte, wsConn *atomic.Pointer[websocket.Conn
 }
 
 // RemoveFilterByWsConn uninstalls the filter attached to this websocket connection
-func (h *Handler) RemoveFilterByWsConn(wsConn *atomic.Pointer[websocket.Conn]) {
+func (h *Handler) RemoveFilterByWsConn(wsConn *concurrentWsConn) {
 	service, ok := h.serviceMap[APIEth]
 	if !ok {
 		return
@@ -233,6 +341,10 @@ func (h *Handler) getFnHandler(req types.Request) (*serviceData, *funcData, type
 
 	serviceName, funcName := callName[0], callName[1]
 
+	if _, disabled := h.disabledMethods[req.Method]; disabled {
+		return nil, nil, types.NewRPCError(types.NotFoundErrorCode, methodNotFoundErrorMessage)
+	}
+
 	service, ok := h.serviceMap[serviceName]
 	if !ok {
 		log.Infof("Method %s not found", req.Method)
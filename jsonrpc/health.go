@@ -0,0 +1,181 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	healthLivePath  = "/health/live"
+	healthReadyPath = "/health/ready"
+
+	healthStatusOK       = "ok"
+	healthStatusReady    = "ready"
+	healthStatusNotReady = "not_ready"
+	healthStatusUp       = "up"
+	healthStatusDown     = "down"
+)
+
+// healthResponse is the body of the health endpoints
+type healthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]healthCheckResult `json:"checks,omitempty"`
+}
+
+// healthCheckResult is the state of a dependency of the node
+type healthCheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// healthCheck checks a dependency of the node, it returns an error if the dependency is down
+type healthCheck func(ctx context.Context) error
+
+// healthChecker serves the endpoints used by the load balancers to know if the
+// node is alive and if all the dependencies needed to handle requests are up
+type healthChecker struct {
+	cfg      HealthCheckConfig
+	state    types.StateInterface
+	etherman types.EthermanInterface
+	checks   map[string]healthCheck
+}
+
+func newHealthChecker(cfg HealthCheckConfig, state types.StateInterface, etherman types.EthermanInterface) *healthChecker {
+	h := &healthChecker{
+		cfg:      cfg,
+		state:    state,
+		etherman: etherman,
+	}
+	h.checks = map[string]healthCheck{
+		"db":         h.checkDB,
+		"executor":   h.checkExecutor,
+		"merkletree": h.checkMerkleTree,
+		"sync":       h.checkSync,
+	}
+	if etherman != nil {
+		h.checks["l1"] = h.checkL1
+	}
+	return h
+}
+
+// handleLive responds if the process is alive, it doesn't check any dependency
+func (h *healthChecker) handleLive(w http.ResponseWriter, req *http.Request) {
+	writeHealthResponse(w, http.StatusOK, healthResponse{Status: healthStatusOK})
+}
+
+// handleReady checks all the dependencies of the node and responds 200 if all of them
+// are up or 503 if any of them is down, with the state of each dependency
+func (h *healthChecker) handleReady(w http.ResponseWriter, req *http.Request) {
+	response := h.check(req.Context())
+	statusCode := http.StatusOK
+	if response.Status != healthStatusReady {
+		statusCode = http.StatusServiceUnavailable
+	}
+	writeHealthResponse(w, statusCode, response)
+}
+
+// check runs all the checks concurrently, each one limited by the configured timeout
+func (h *healthChecker) check(ctx context.Context) healthResponse {
+	response := healthResponse{
+		Status: healthStatusReady,
+		Checks: make(map[string]healthCheckResult, len(h.checks)),
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func(name string, check healthCheck) {
+			defer wg.Done()
+
+			checkCtx := ctx
+			if h.cfg.Timeout.Duration > 0 {
+				var cancel context.CancelFunc
+				checkCtx, cancel = context.WithTimeout(ctx, h.cfg.Timeout.Duration)
+				defer cancel()
+			}
+
+			result := healthCheckResult{Status: healthStatusUp}
+			if err := check(checkCtx); err != nil {
+				log.Warnf("health check %s failed: %v", name, err)
+				result = healthCheckResult{Status: healthStatusDown, Error: err.Error()}
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			response.Checks[name] = result
+			if result.Status != healthStatusUp {
+				response.Status = healthStatusNotReady
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return response
+}
+
+// checkDB checks the state DB is reachable
+func (h *healthChecker) checkDB(ctx context.Context) error {
+	_, err := h.state.GetLastL2BlockNumber(ctx, nil)
+	return err
+}
+
+// checkExecutor checks the executor is reachable
+func (h *healthChecker) checkExecutor(ctx context.Context) error {
+	_, _, err := h.state.GetStoredFlushID(ctx)
+	return err
+}
+
+// checkMerkleTree checks the merkletree is reachable reading an account at the state root of the last L2 block
+func (h *healthChecker) checkMerkleTree(ctx context.Context) error {
+	block, err := h.state.GetLastL2Block(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the last L2 block: %w", err)
+	}
+	_, err = h.state.GetBalance(ctx, common.Address{}, block.Root())
+	return err
+}
+
+// checkL1 checks the L1 node is reachable
+func (h *healthChecker) checkL1(ctx context.Context) error {
+	_, err := h.etherman.GetSafeBlockNumber(ctx)
+	return err
+}
+
+// checkSync checks the node is not behind the L2 blocks and the batches seen more than the configured thresholds
+func (h *healthChecker) checkSync(ctx context.Context) error {
+	syncInfo, err := h.state.GetSyncingInfo(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if h.cfg.MaxL2BlocksBehind > 0 && syncInfo.LastBlockNumberSeen > syncInfo.CurrentBlockNumber &&
+		syncInfo.LastBlockNumberSeen-syncInfo.CurrentBlockNumber > h.cfg.MaxL2BlocksBehind {
+		return fmt.Errorf("node is %d L2 blocks behind, max allowed %d",
+			syncInfo.LastBlockNumberSeen-syncInfo.CurrentBlockNumber, h.cfg.MaxL2BlocksBehind)
+	}
+
+	if h.cfg.MaxBatchesBehind > 0 && syncInfo.LastBatchNumberSeen > syncInfo.CurrentBatchNumber &&
+		syncInfo.LastBatchNumberSeen-syncInfo.CurrentBatchNumber > h.cfg.MaxBatchesBehind {
+		return fmt.Errorf("node is %d batches behind, max allowed %d",
+			syncInfo.LastBatchNumberSeen-syncInfo.CurrentBatchNumber, h.cfg.MaxBatchesBehind)
+	}
+
+	return nil
+}
+
+func writeHealthResponse(w http.ResponseWriter, statusCode int, response healthResponse) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("failed to write health response: %v", err)
+	}
+}
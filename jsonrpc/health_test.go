@@ -0,0 +1,93 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthLive(t *testing.T) {
+	h := newHealthChecker(HealthCheckConfig{Enabled: true}, mocks.NewStateMock(t), nil)
+
+	res := httptest.NewRecorder()
+	h.handleLive(res, httptest.NewRequest(http.MethodGet, healthLivePath, nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var response healthResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+	assert.Equal(t, healthStatusOK, response.Status)
+}
+
+func TestHealthReady(t *testing.T) {
+	cfg := HealthCheckConfig{Enabled: true, MaxL2BlocksBehind: 10, MaxBatchesBehind: 5}
+	block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: big.NewInt(1), Root: common.HexToHash("0x1")})
+
+	testCases := []struct {
+		name               string
+		syncInfo           state.SyncingInfo
+		l1Err              error
+		expectedStatusCode int
+		expectedChecks     map[string]string
+	}{
+		{
+			name:               "all dependencies up",
+			syncInfo:           state.SyncingInfo{CurrentBlockNumber: 100, LastBlockNumberSeen: 105, CurrentBatchNumber: 10, LastBatchNumberSeen: 12},
+			expectedStatusCode: http.StatusOK,
+			expectedChecks:     map[string]string{"db": healthStatusUp, "executor": healthStatusUp, "merkletree": healthStatusUp, "l1": healthStatusUp, "sync": healthStatusUp},
+		},
+		{
+			name:               "L1 down",
+			syncInfo:           state.SyncingInfo{CurrentBlockNumber: 100, LastBlockNumberSeen: 100, CurrentBatchNumber: 10, LastBatchNumberSeen: 10},
+			l1Err:              errors.New("connection refused"),
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedChecks:     map[string]string{"db": healthStatusUp, "executor": healthStatusUp, "merkletree": healthStatusUp, "l1": healthStatusDown, "sync": healthStatusUp},
+		},
+		{
+			name:               "too many batches behind",
+			syncInfo:           state.SyncingInfo{CurrentBlockNumber: 100, LastBlockNumberSeen: 100, CurrentBatchNumber: 10, LastBatchNumberSeen: 16},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedChecks:     map[string]string{"db": healthStatusUp, "executor": healthStatusUp, "merkletree": healthStatusUp, "l1": healthStatusUp, "sync": healthStatusDown},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			st := mocks.NewStateMock(t)
+			etherman := mocks.NewEthermanMock(t)
+			st.On("GetLastL2BlockNumber", mock.Anything, nil).Return(uint64(1), nil).Once()
+			st.On("GetStoredFlushID", mock.Anything).Return(uint64(1), "prover", nil).Once()
+			st.On("GetLastL2Block", mock.Anything, nil).Return(block, nil).Once()
+			st.On("GetBalance", mock.Anything, common.Address{}, block.Root()).Return(big.NewInt(0), nil).Once()
+			st.On("GetSyncingInfo", mock.Anything, nil).Return(testCase.syncInfo, nil).Once()
+			etherman.On("GetSafeBlockNumber", mock.Anything).Return(uint64(1), testCase.l1Err).Once()
+
+			h := newHealthChecker(cfg, st, etherman)
+			res := httptest.NewRecorder()
+			h.handleReady(res, httptest.NewRequest(http.MethodGet, healthReadyPath, nil))
+			assert.Equal(t, testCase.expectedStatusCode, res.Code)
+
+			var response healthResponse
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&response))
+			if testCase.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, healthStatusReady, response.Status)
+			} else {
+				assert.Equal(t, healthStatusNotReady, response.Status)
+			}
+			require.Len(t, response.Checks, len(testCase.expectedChecks))
+			for name, status := range testCase.expectedChecks {
+				assert.Equal(t, status, response.Checks[name].Status, name)
+			}
+		})
+	}
+}
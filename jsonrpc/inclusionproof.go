@@ -0,0 +1,73 @@
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// proofList collects the trie nodes of a merkle patricia proof in order
+type proofList []types.ArgBytes
+
+// Put implements ethdb.KeyValueWriter
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+// Delete implements ethdb.KeyValueWriter
+func (l *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// proveListItem builds the merkle patricia trie of the list the same way the roots of the
+// block header are derived, returning the root of the trie and the proof of the item at
+// the index
+func proveListItem(list ethTypes.DerivableList, index uint64) (common.Hash, proofList, error) {
+	t := trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+	root := ethTypes.DeriveSha(list, t)
+
+	var proof proofList
+	if err := t.Prove(rlp.AppendUint64(nil, index), &proof); err != nil {
+		return common.Hash{}, nil, err
+	}
+	return root, proof, nil
+}
+
+// buildInclusionProof builds the proofs of the tx at the index and its receipt against the
+// transactions and receipts roots of the block, the receipts must be the ones of all the
+// txs of the block in order
+func buildInclusionProof(block *ethTypes.Block, receipts ethTypes.Receipts, index uint64) (*types.InclusionProof, error) {
+	txsRoot, txProof, err := proveListItem(block.Transactions(), index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove tx: %w", err)
+	}
+	if txsRoot != block.TxHash() {
+		return nil, fmt.Errorf("transactions root %s doesn't match the root %s of block %d", txsRoot.String(), block.TxHash().String(), block.NumberU64())
+	}
+
+	receiptsRoot, receiptProof, err := proveListItem(receipts, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove receipt: %w", err)
+	}
+	if receiptsRoot != block.ReceiptHash() {
+		return nil, fmt.Errorf("receipts root %s doesn't match the root %s of block %d", receiptsRoot.String(), block.ReceiptHash().String(), block.NumberU64())
+	}
+
+	return &types.InclusionProof{
+		BlockHash:        block.Hash(),
+		BlockNumber:      types.ArgUint64(block.NumberU64()),
+		TxHash:           block.Transactions()[index].Hash(),
+		TxIndex:          types.ArgUint64(index),
+		Key:              rlp.AppendUint64(nil, index),
+		TransactionsRoot: txsRoot,
+		TransactionProof: txProof,
+		ReceiptsRoot:     receiptsRoot,
+		ReceiptProof:     receiptProof,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInclusionProof(t *testing.T) {
+	txs := make([]*ethTypes.Transaction, 0, 3)
+	receipts := make(ethTypes.Receipts, 0, 3)
+	for i := uint64(0); i < 3; i++ {
+		tx := ethTypes.NewTransaction(i, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
+		txs = append(txs, tx)
+		receipts = append(receipts, &ethTypes.Receipt{Status: ethTypes.ReceiptStatusSuccessful, CumulativeGasUsed: 21000 * (i + 1), TxHash: tx.Hash(), Logs: []*ethTypes.Log{}})
+	}
+	block := ethTypes.NewBlock(&ethTypes.Header{Number: big.NewInt(10)}, txs, nil, receipts, trie.NewStackTrie(nil))
+
+	proof, err := buildInclusionProof(block, receipts, 1)
+	require.NoError(t, err)
+	assert.Equal(t, block.Hash(), proof.BlockHash)
+	assert.Equal(t, txs[1].Hash(), proof.TxHash)
+	assert.Equal(t, block.TxHash(), proof.TransactionsRoot)
+	assert.Equal(t, block.ReceiptHash(), proof.ReceiptsRoot)
+
+	verify := func(root common.Hash, nodes [][]byte) []byte {
+		db := memorydb.New()
+		for _, node := range nodes {
+			require.NoError(t, db.Put(crypto.Keccak256(node), node))
+		}
+		value, err := trie.VerifyProof(root, rlp.AppendUint64(nil, 1), db)
+		require.NoError(t, err)
+		return value
+	}
+	txNodes := make([][]byte, 0, len(proof.TransactionProof))
+	for _, node := range proof.TransactionProof {
+		txNodes = append(txNodes, node)
+	}
+	encodedTx, err := txs[1].MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, encodedTx, verify(proof.TransactionsRoot, txNodes))
+
+	receiptNodes := make([][]byte, 0, len(proof.ReceiptProof))
+	for _, node := range proof.ReceiptProof {
+		receiptNodes = append(receiptNodes, node)
+	}
+	encodedReceipt, err := receipts[1].MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, encodedReceipt, verify(proof.ReceiptsRoot, receiptNodes))
+
+	// the receipts not matching the block are rejected
+	receipts[1].CumulativeGasUsed++
+	_, err = buildInclusionProof(block, receipts, 1)
+	assert.ErrorContains(t, err, "receipts root")
+}
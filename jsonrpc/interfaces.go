@@ -1,20 +1,30 @@
 package jsonrpc
 
 import (
-	"sync/atomic"
+	"time"
 
-	"github.com/gorilla/websocket"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // storageInterface json rpc internal storage to persist data
 type storageInterface interface {
+	GetAllAccountChangesFiltersWithWSConn() ([]*Filter, error)
+	GetAllBatchStatusFiltersWithWSConn() ([]*Filter, error)
 	GetAllBlockFiltersWithWSConn() ([]*Filter, error)
 	GetAllLogFiltersWithWSConn() ([]*Filter, error)
+	GetAllDroppedTxFiltersWithWSConn() ([]*Filter, error)
+	GetAllPendingTxFiltersWithWSConn() ([]*Filter, error)
+	GetAllSelectedTxFiltersWithWSConn() ([]*Filter, error)
 	GetFilter(filterID string) (*Filter, error)
-	NewBlockFilter(wsConn *atomic.Pointer[websocket.Conn]) (string, error)
-	NewLogFilter(wsConn *atomic.Pointer[websocket.Conn], filter LogFilter) (string, error)
-	NewPendingTransactionFilter(wsConn *atomic.Pointer[websocket.Conn]) (string, error)
+	NewAccountChangesFilter(wsConn *concurrentWsConn, addresses []common.Address) (string, error)
+	NewBatchStatusFilter(wsConn *concurrentWsConn) (string, error)
+	NewBlockFilter(wsConn *concurrentWsConn) (string, error)
+	NewDroppedTransactionFilter(wsConn *concurrentWsConn) (string, error)
+	NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error)
+	NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error)
+	NewSelectedTransactionFilter(wsConn *concurrentWsConn) (string, error)
 	UninstallFilter(filterID string) error
-	UninstallFilterByWSConn(wsConn *atomic.Pointer[websocket.Conn]) error
+	UninstallExpiredFilters(lastPollBefore time.Time) (int, error)
+	UninstallFilterByWSConn(wsConn *concurrentWsConn) error
 	UpdateFilterLastPoll(filterID string) error
 }
@@ -0,0 +1,177 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// ipcRemoteAddr is the remote address of the requests received through the IPC socket,
+// the rate limits and the audit log use it as their IP
+const ipcRemoteAddr = "ipc"
+
+// ipcRequestKey is the context key marking the requests received through the IPC socket
+type ipcRequestKey struct{}
+
+// newIPCRequest creates the HTTP request passed to the endpoints for the requests of an IPC
+// connection, it has no headers and it's marked as an IPC request in its context
+func newIPCRequest() *http.Request {
+	httpReq := &http.Request{
+		Method:     http.MethodPost,
+		Header:     http.Header{},
+		RemoteAddr: ipcRemoteAddr,
+	}
+	return httpReq.WithContext(context.WithValue(context.Background(), ipcRequestKey{}, true))
+}
+
+// isIPCRequest checks if the HTTP request was created for a request received through the IPC socket
+func isIPCRequest(httpReq *http.Request) bool {
+	ipc, _ := httpReq.Context().Value(ipcRequestKey{}).(bool)
+	return ipc
+}
+
+// startIPC listens for connections in the unix socket of the IPC endpoint
+func (s *Server) startIPC() error {
+	if s.ipcLis != nil {
+		return fmt.Errorf("ipc server already started")
+	}
+	if s.config.IPC.Path == "" {
+		return fmt.Errorf("ipc path is not configured")
+	}
+	permissions, err := strconv.ParseUint(s.config.IPC.Permissions, 8, 32) //nolint:gomnd
+	if err != nil {
+		return fmt.Errorf("invalid ipc permissions %s: %w", s.config.IPC.Permissions, err)
+	}
+
+	// the socket file of a previous run that was not stopped gracefully is replaced
+	if err := os.Remove(s.config.IPC.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove the existing ipc socket: %w", err)
+	}
+	lis, err := net.Listen("unix", s.config.IPC.Path)
+	if err != nil {
+		log.Errorf("failed to create unix listener: %v", err)
+		return err
+	}
+	if err := os.Chmod(s.config.IPC.Path, os.FileMode(permissions)); err != nil {
+		_ = lis.Close()
+		return fmt.Errorf("failed to set the permissions of the ipc socket: %w", err)
+	}
+
+	s.ipcMutex.Lock()
+	s.ipcLis = lis
+	s.ipcConns = map[net.Conn]struct{}{}
+	s.ipcMutex.Unlock()
+
+	log.Infof("ipc server started: %s", s.config.IPC.Path)
+	go s.acceptIPC(lis)
+	return nil
+}
+
+// acceptIPC accepts the connections of the IPC socket until the listener is closed
+func (s *Server) acceptIPC(lis net.Listener) {
+	for {
+		conn, err := lis.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			log.Infof("ipc server stopped")
+			return
+		} else if err != nil {
+			log.Errorf("failed to accept ipc connection: %v", err)
+			continue
+		}
+
+		s.ipcMutex.Lock()
+		if s.ipcConns == nil {
+			// the server was stopped while accepting the connection
+			s.ipcMutex.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.ipcConns[conn] = struct{}{}
+		s.ipcMutex.Unlock()
+
+		go s.handleIPC(conn)
+	}
+}
+
+// handleIPC handles the requests of an IPC connection, they are read as a stream of JSON values
+// and each one is a single request or a batch of requests
+func (s *Server) handleIPC(conn net.Conn) {
+	defer func() {
+		s.ipcMutex.Lock()
+		delete(s.ipcConns, conn)
+		s.ipcMutex.Unlock()
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Errorf("unable to close ipc connection: %v", err)
+		}
+	}()
+
+	httpReq := newIPCRequest()
+	decoder := json.NewDecoder(conn)
+	for {
+		var message json.RawMessage
+		if err := decoder.Decode(&message); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			// the stream can't be resynchronized after an invalid JSON value, the connection is closed
+			log.Infof("closing ipc connection due to an invalid message: %v", err)
+			resp, _ := types.NewResponse(types.Request{JSONRPC: "2.0"}, nil, types.NewRPCError(types.InvalidRequestErrorCode, "Invalid json request")).Bytes()
+			_, _ = conn.Write(append(resp, '\n'))
+			return
+		}
+
+		resp, err := s.handleIPCMessage(message, httpReq)
+		if err != nil {
+			log.Errorf("unable to handle ipc request: %v", err)
+			return
+		}
+		if _, err := conn.Write(append(resp, '\n')); err != nil {
+			log.Infof("closing ipc connection: %v", err)
+			return
+		}
+	}
+}
+
+// handleIPCMessage dispatches a single request or a batch of requests received through the IPC socket
+func (s *Server) handleIPCMessage(message []byte, httpReq *http.Request) ([]byte, error) {
+	decoder := newMessageDecoder(message)
+	if single, err := decoder.isSingleRequest(); err == nil && !single {
+		return s.handleWsBatchRequest(decoder, nil, httpReq)
+	}
+
+	defer metrics.RequestHandled(metrics.RequestHandledLabelSingle)
+	request, err := decoder.decodeRequest()
+	if err != nil {
+		return types.NewResponse(types.Request{JSONRPC: "2.0"}, nil, types.NewRPCError(types.InvalidRequestErrorCode, "Invalid json request")).Bytes()
+	}
+	return s.handler.Handle(handleRequest{Request: request, HttpRequest: httpReq}).Bytes()
+}
+
+// stopIPC closes the IPC socket and the open connections, the socket file is removed
+func (s *Server) stopIPC() error {
+	s.ipcMutex.Lock()
+	defer s.ipcMutex.Unlock()
+
+	if s.ipcLis == nil {
+		return nil
+	}
+	if err := s.ipcLis.Close(); err != nil {
+		return err
+	}
+	for conn := range s.ipcConns {
+		_ = conn.Close()
+	}
+	s.ipcLis = nil
+	s.ipcConns = nil
+	return nil
+}
@@ -0,0 +1,87 @@
+package jsonrpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPC(t *testing.T) {
+	cfg := Config{
+		BatchRequestsEnabled: true,
+		AdminAuthToken:       "secret",
+		IPC: IPCConfig{
+			Enabled:     true,
+			Path:        filepath.Join(t.TempDir(), "node.ipc"),
+			Permissions: "0600",
+		},
+	}
+	services := []Service{
+		{Name: APIWeb3, Service: &Web3Endpoints{}},
+		{Name: APIAdmin, Service: NewAdminEndpoints(nil, nil, nil, nil, nil, nil, nil)},
+	}
+	s := NewServer(cfg, chainID, nil, nil, nil, nil, services)
+	require.NoError(t, s.startIPC())
+	defer func() { require.NoError(t, s.stopIPC()) }()
+
+	info, err := os.Stat(cfg.IPC.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	client, err := rpc.DialIPC(context.Background(), cfg.IPC.Path)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var version string
+	require.NoError(t, client.Call(&version, "web3_clientVersion"))
+	assert.Equal(t, zkevm.Version, version)
+
+	batch := []rpc.BatchElem{
+		{Method: "web3_clientVersion", Result: new(string)},
+		{Method: "web3_unknown", Result: new(string)},
+	}
+	require.NoError(t, client.BatchCall(batch))
+	assert.NoError(t, batch[0].Error)
+	assert.Equal(t, zkevm.Version, *batch[0].Result.(*string))
+	assert.Error(t, batch[1].Error)
+
+	// the admin methods don't require the auth token through the socket
+	err = client.Call(nil, "admin_proverVersions")
+	require.Error(t, err)
+	assert.Equal(t, "aggregator is not running in this node", err.Error())
+}
+
+func TestIPCStopClosesConnections(t *testing.T) {
+	cfg := Config{
+		IPC: IPCConfig{
+			Enabled:     true,
+			Path:        filepath.Join(t.TempDir(), "node.ipc"),
+			Permissions: "0660",
+		},
+	}
+	s := NewServer(cfg, chainID, nil, nil, nil, nil, []Service{{Name: APIWeb3, Service: &Web3Endpoints{}}})
+	require.NoError(t, s.startIPC())
+
+	conn, err := net.Dial("unix", cfg.IPC.Path)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"web3_clientVersion","params":[]}`))
+	require.NoError(t, err)
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf[:n]), zkevm.Version)
+
+	require.NoError(t, s.stopIPC())
+	_, err = conn.Read(buf)
+	assert.Error(t, err)
+	_, err = os.Stat(cfg.IPC.Path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -13,7 +13,17 @@ const (
 	requestsHandledName = requestPrefix + "handled"
 	requestDurationName = requestPrefix + "duration"
 
+	methodPrefix       = prefix + "method_"
+	methodRequestsName = methodPrefix + "requests"
+	methodErrorsName   = methodPrefix + "errors"
+	methodDurationName = methodPrefix + "duration"
+
 	requestHandledTypeLabelName = "type"
+	methodLabelName             = "method"
+
+	// UnknownMethodLabel is the method label used for the requests to methods
+	// that don't exist to keep bounded the number of label values
+	UnknownMethodLabel = "unknown"
 )
 
 // RequestHandledLabel represents the possible values for the
@@ -34,8 +44,9 @@ const (
 // Register the metrics for the jsonrpc package.
 func Register() {
 	var (
-		counterVecs []metrics.CounterVecOpts
-		histograms  []prometheus.HistogramOpts
+		counterVecs   []metrics.CounterVecOpts
+		histograms    []prometheus.HistogramOpts
+		histogramVecs []metrics.HistogramVecOpts
 	)
 
 	counterVecs = []metrics.CounterVecOpts{
@@ -46,6 +57,20 @@ func Register() {
 			},
 			Labels: []string{requestHandledTypeLabelName},
 		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: methodRequestsName,
+				Help: "[JSONRPC] number of requests handled per method",
+			},
+			Labels: []string{methodLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: methodErrorsName,
+				Help: "[JSONRPC] number of requests per method that returned an error",
+			},
+			Labels: []string{methodLabelName},
+		},
 	}
 
 	start := 0.1
@@ -59,8 +84,20 @@ func Register() {
 		},
 	}
 
+	histogramVecs = []metrics.HistogramVecOpts{
+		{
+			HistogramOpts: prometheus.HistogramOpts{
+				Name:    methodDurationName,
+				Help:    "[JSONRPC] Histogram for the runtime of requests per method",
+				Buckets: prometheus.ExponentialBuckets(0.001, 2, 15), //nolint:gomnd
+			},
+			Labels: []string{methodLabelName},
+		},
+	}
+
 	metrics.RegisterCounterVecs(counterVecs...)
 	metrics.RegisterHistograms(histograms...)
+	metrics.RegisterHistogramVecs(histogramVecs...)
 }
 
 // RequestHandled increments the requests handled counter vector by one for the
@@ -74,3 +111,14 @@ func RequestHandled(label RequestHandledLabel) {
 func RequestDuration(start time.Time) {
 	metrics.HistogramObserve(requestDurationName, time.Since(start).Seconds())
 }
+
+// MethodHandled increments the requests counter and, if the request failed, the
+// errors counter of the method and observes the duration of the request from the
+// provided starting time.
+func MethodHandled(method string, failed bool, start time.Time) {
+	metrics.CounterVecInc(methodRequestsName, method)
+	if failed {
+		metrics.CounterVecInc(methodErrorsName, method)
+	}
+	metrics.HistogramVecObserve(methodDurationName, method, time.Since(start).Seconds())
+}
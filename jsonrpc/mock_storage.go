@@ -3,9 +3,10 @@
 package jsonrpc
 
 import (
-	atomic "sync/atomic"
+	common "github.com/ethereum/go-ethereum/common"
+
+	time "time"
 
-	websocket "github.com/gorilla/websocket"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +15,58 @@ type storageMock struct {
 	mock.Mock
 }
 
+// GetAllAccountChangesFiltersWithWSConn provides a mock function with given fields:
+func (_m *storageMock) GetAllAccountChangesFiltersWithWSConn() ([]*Filter, error) {
+	ret := _m.Called()
+
+	var r0 []*Filter
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*Filter, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*Filter); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Filter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllBatchStatusFiltersWithWSConn provides a mock function with given fields:
+func (_m *storageMock) GetAllBatchStatusFiltersWithWSConn() ([]*Filter, error) {
+	ret := _m.Called()
+
+	var r0 []*Filter
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*Filter, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*Filter); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Filter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllBlockFiltersWithWSConn provides a mock function with given fields:
 func (_m *storageMock) GetAllBlockFiltersWithWSConn() ([]*Filter, error) {
 	ret := _m.Called()
@@ -40,6 +93,32 @@ func (_m *storageMock) GetAllBlockFiltersWithWSConn() ([]*Filter, error) {
 	return r0, r1
 }
 
+// GetAllDroppedTxFiltersWithWSConn provides a mock function with given fields:
+func (_m *storageMock) GetAllDroppedTxFiltersWithWSConn() ([]*Filter, error) {
+	ret := _m.Called()
+
+	var r0 []*Filter
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*Filter, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*Filter); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Filter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllLogFiltersWithWSConn provides a mock function with given fields:
 func (_m *storageMock) GetAllLogFiltersWithWSConn() ([]*Filter, error) {
 	ret := _m.Called()
@@ -66,6 +145,58 @@ func (_m *storageMock) GetAllLogFiltersWithWSConn() ([]*Filter, error) {
 	return r0, r1
 }
 
+// GetAllPendingTxFiltersWithWSConn provides a mock function with given fields:
+func (_m *storageMock) GetAllPendingTxFiltersWithWSConn() ([]*Filter, error) {
+	ret := _m.Called()
+
+	var r0 []*Filter
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*Filter, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*Filter); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Filter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllSelectedTxFiltersWithWSConn provides a mock function with given fields:
+func (_m *storageMock) GetAllSelectedTxFiltersWithWSConn() ([]*Filter, error) {
+	ret := _m.Called()
+
+	var r0 []*Filter
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*Filter, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*Filter); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Filter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFilter provides a mock function with given fields: filterID
 func (_m *storageMock) GetFilter(filterID string) (*Filter, error) {
 	ret := _m.Called(filterID)
@@ -92,22 +223,70 @@ func (_m *storageMock) GetFilter(filterID string) (*Filter, error) {
 	return r0, r1
 }
 
+// NewAccountChangesFilter provides a mock function with given fields: wsConn, addresses
+func (_m *storageMock) NewAccountChangesFilter(wsConn *concurrentWsConn, addresses []common.Address) (string, error) {
+	ret := _m.Called(wsConn, addresses)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, []common.Address) (string, error)); ok {
+		return rf(wsConn, addresses)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, []common.Address) string); ok {
+		r0 = rf(wsConn, addresses)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn, []common.Address) error); ok {
+		r1 = rf(wsConn, addresses)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBatchStatusFilter provides a mock function with given fields: wsConn
+func (_m *storageMock) NewBatchStatusFilter(wsConn *concurrentWsConn) (string, error) {
+	ret := _m.Called(wsConn)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) (string, error)); ok {
+		return rf(wsConn)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) string); ok {
+		r0 = rf(wsConn)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn) error); ok {
+		r1 = rf(wsConn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewBlockFilter provides a mock function with given fields: wsConn
-func (_m *storageMock) NewBlockFilter(wsConn *atomic.Pointer[websocket.Conn]) (string, error) {
+func (_m *storageMock) NewBlockFilter(wsConn *concurrentWsConn) (string, error) {
 	ret := _m.Called(wsConn)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*atomic.Pointer[websocket.Conn]) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) (string, error)); ok {
 		return rf(wsConn)
 	}
-	if rf, ok := ret.Get(0).(func(*atomic.Pointer[websocket.Conn]) string); ok {
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) string); ok {
 		r0 = rf(wsConn)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*atomic.Pointer[websocket.Conn]) error); ok {
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn) error); ok {
 		r1 = rf(wsConn)
 	} else {
 		r1 = ret.Error(1)
@@ -117,21 +296,21 @@ func (_m *storageMock) NewBlockFilter(wsConn *atomic.Pointer[websocket.Conn]) (s
 }
 
 // NewLogFilter provides a mock function with given fields: wsConn, filter
-func (_m *storageMock) NewLogFilter(wsConn *atomic.Pointer[websocket.Conn], filter LogFilter) (string, error) {
+func (_m *storageMock) NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error) {
 	ret := _m.Called(wsConn, filter)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*atomic.Pointer[websocket.Conn], LogFilter) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, LogFilter) (string, error)); ok {
 		return rf(wsConn, filter)
 	}
-	if rf, ok := ret.Get(0).(func(*atomic.Pointer[websocket.Conn], LogFilter) string); ok {
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn, LogFilter) string); ok {
 		r0 = rf(wsConn, filter)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*atomic.Pointer[websocket.Conn], LogFilter) error); ok {
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn, LogFilter) error); ok {
 		r1 = rf(wsConn, filter)
 	} else {
 		r1 = ret.Error(1)
@@ -140,22 +319,70 @@ func (_m *storageMock) NewLogFilter(wsConn *atomic.Pointer[websocket.Conn], filt
 	return r0, r1
 }
 
+// NewDroppedTransactionFilter provides a mock function with given fields: wsConn
+func (_m *storageMock) NewDroppedTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	ret := _m.Called(wsConn)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) (string, error)); ok {
+		return rf(wsConn)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) string); ok {
+		r0 = rf(wsConn)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn) error); ok {
+		r1 = rf(wsConn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewPendingTransactionFilter provides a mock function with given fields: wsConn
-func (_m *storageMock) NewPendingTransactionFilter(wsConn *atomic.Pointer[websocket.Conn]) (string, error) {
+func (_m *storageMock) NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error) {
 	ret := _m.Called(wsConn)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*atomic.Pointer[websocket.Conn]) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) (string, error)); ok {
 		return rf(wsConn)
 	}
-	if rf, ok := ret.Get(0).(func(*atomic.Pointer[websocket.Conn]) string); ok {
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) string); ok {
 		r0 = rf(wsConn)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(*atomic.Pointer[websocket.Conn]) error); ok {
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn) error); ok {
+		r1 = rf(wsConn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSelectedTransactionFilter provides a mock function with given fields: wsConn
+func (_m *storageMock) NewSelectedTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	ret := _m.Called(wsConn)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) (string, error)); ok {
+		return rf(wsConn)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) string); ok {
+		r0 = rf(wsConn)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn) error); ok {
 		r1 = rf(wsConn)
 	} else {
 		r1 = ret.Error(1)
@@ -178,12 +405,36 @@ func (_m *storageMock) UninstallFilter(filterID string) error {
 	return r0
 }
 
+// UninstallExpiredFilters provides a mock function with given fields: lastPollBefore
+func (_m *storageMock) UninstallExpiredFilters(lastPollBefore time.Time) (int, error) {
+	ret := _m.Called(lastPollBefore)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return rf(lastPollBefore)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = rf(lastPollBefore)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(lastPollBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UninstallFilterByWSConn provides a mock function with given fields: wsConn
-func (_m *storageMock) UninstallFilterByWSConn(wsConn *atomic.Pointer[websocket.Conn]) error {
+func (_m *storageMock) UninstallFilterByWSConn(wsConn *concurrentWsConn) error {
 	ret := _m.Called(wsConn)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*atomic.Pointer[websocket.Conn]) error); ok {
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) error); ok {
 		r0 = rf(wsConn)
 	} else {
 		r0 = ret.Error(0)
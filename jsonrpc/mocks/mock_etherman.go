@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/0xPolygonHermez/zkevm-node/etherman/types"
 )
 
 // EthermanMock is an autogenerated mock type for the EthermanInterface type
@@ -61,6 +63,30 @@ func (_m *EthermanMock) GetSafeBlockNumber(ctx context.Context) (uint64, error)
 	return r0, r1
 }
 
+// GetSequencedBatchesInfo provides a mock function with given fields: ctx, lastBatchNumber
+func (_m *EthermanMock) GetSequencedBatchesInfo(ctx context.Context, lastBatchNumber uint64) (types.SequencedBatchesInfo, error) {
+	ret := _m.Called(ctx, lastBatchNumber)
+
+	var r0 types.SequencedBatchesInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (types.SequencedBatchesInfo, error)); ok {
+		return rf(ctx, lastBatchNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) types.SequencedBatchesInfo); ok {
+		r0 = rf(ctx, lastBatchNumber)
+	} else {
+		r0 = ret.Get(0).(types.SequencedBatchesInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, lastBatchNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewEthermanMock creates a new instance of EthermanMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewEthermanMock(t interface {
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	big "math/big"
+
 	context "context"
 
 	common "github.com/ethereum/go-ethereum/common"
@@ -21,6 +23,20 @@ type PoolMock struct {
 	mock.Mock
 }
 
+// AddConditionalTx provides a mock function with given fields: ctx, tx, ip, conditions
+func (_m *PoolMock) AddConditionalTx(ctx context.Context, tx types.Transaction, ip string, conditions *pool.TxConditions) error {
+	ret := _m.Called(ctx, tx, ip, conditions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.Transaction, string, *pool.TxConditions) error); ok {
+		r0 = rf(ctx, tx, ip, conditions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddTx provides a mock function with given fields: ctx, tx, ip
 func (_m *PoolMock) AddTx(ctx context.Context, tx types.Transaction, ip string) error {
 	ret := _m.Called(ctx, tx, ip)
@@ -35,6 +51,32 @@ func (_m *PoolMock) AddTx(ctx context.Context, tx types.Transaction, ip string)
 	return r0
 }
 
+// BanSender provides a mock function with given fields: ctx, address, reason
+func (_m *PoolMock) BanSender(ctx context.Context, address common.Address, reason string) ([]common.Hash, error) {
+	ret := _m.Called(ctx, address, reason)
+
+	var r0 []common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, string) ([]common.Hash, error)); ok {
+		return rf(ctx, address, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, string) []common.Hash); ok {
+		r0 = rf(ctx, address, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, string) error); ok {
+		r1 = rf(ctx, address, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CountPendingTransactions provides a mock function with given fields: ctx
 func (_m *PoolMock) CountPendingTransactions(ctx context.Context) (uint64, error) {
 	ret := _m.Called(ctx)
@@ -59,6 +101,32 @@ func (_m *PoolMock) CountPendingTransactions(ctx context.Context) (uint64, error
 	return r0, r1
 }
 
+// DropTx provides a mock function with given fields: ctx, hash, reason
+func (_m *PoolMock) DropTx(ctx context.Context, hash common.Hash, reason string) (common.Address, error) {
+	ret := _m.Called(ctx, hash, reason)
+
+	var r0 common.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, string) (common.Address, error)); ok {
+		return rf(ctx, hash, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, string) common.Address); ok {
+		r0 = rf(ctx, hash, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, string) error); ok {
+		r1 = rf(ctx, hash, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGasPrices provides a mock function with given fields: ctx
 func (_m *PoolMock) GetGasPrices(ctx context.Context) (pool.GasPrices, error) {
 	ret := _m.Called(ctx)
@@ -83,23 +151,72 @@ func (_m *PoolMock) GetGasPrices(ctx context.Context) (pool.GasPrices, error) {
 	return r0, r1
 }
 
-// GetNonce provides a mock function with given fields: ctx, address
-func (_m *PoolMock) GetNonce(ctx context.Context, address common.Address) (uint64, error) {
-	ret := _m.Called(ctx, address)
+// GetMinSuggestedGasPrice provides a mock function with given fields:
+func (_m *PoolMock) GetMinSuggestedGasPrice() *big.Int {
+	ret := _m.Called()
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func() *big.Int); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	return r0
+}
+
+// GetDroppedTxsSince provides a mock function with given fields: ctx, cursor
+func (_m *PoolMock) GetDroppedTxsSince(ctx context.Context, cursor uint64) ([]pool.DroppedTx, uint64, error) {
+	ret := _m.Called(ctx, cursor)
+
+	var r0 []pool.DroppedTx
+	var r1 uint64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]pool.DroppedTx, uint64, error)); ok {
+		return rf(ctx, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []pool.DroppedTx); ok {
+		r0 = rf(ctx, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.DroppedTx)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) uint64); ok {
+		r1 = rf(ctx, cursor)
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64) error); ok {
+		r2 = rf(ctx, cursor)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPendingNonce provides a mock function with given fields: ctx, address, currentNonce
+func (_m *PoolMock) GetPendingNonce(ctx context.Context, address common.Address, currentNonce uint64) (uint64, error) {
+	ret := _m.Called(ctx, address, currentNonce)
 
 	var r0 uint64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, common.Address) (uint64, error)); ok {
-		return rf(ctx, address)
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64) (uint64, error)); ok {
+		return rf(ctx, address, currentNonce)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, common.Address) uint64); ok {
-		r0 = rf(ctx, address)
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64) uint64); ok {
+		r0 = rf(ctx, address, currentNonce)
 	} else {
 		r0 = ret.Get(0).(uint64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, common.Address) error); ok {
-		r1 = rf(ctx, address)
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64) error); ok {
+		r1 = rf(ctx, address, currentNonce)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -185,6 +302,46 @@ func (_m *PoolMock) GetTxByHash(ctx context.Context, hash common.Hash) (*pool.Tr
 	return r0, r1
 }
 
+// SuggestNonceGapResolution provides a mock function with given fields: ctx, address, currentNonce
+func (_m *PoolMock) SuggestNonceGapResolution(ctx context.Context, address common.Address, currentNonce uint64) (*pool.NonceGapResolution, error) {
+	ret := _m.Called(ctx, address, currentNonce)
+
+	var r0 *pool.NonceGapResolution
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64) (*pool.NonceGapResolution, error)); ok {
+		return rf(ctx, address, currentNonce)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64) *pool.NonceGapResolution); ok {
+		r0 = rf(ctx, address, currentNonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pool.NonceGapResolution)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64) error); ok {
+		r1 = rf(ctx, address, currentNonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnbanSender provides a mock function with given fields: ctx, address
+func (_m *PoolMock) UnbanSender(ctx context.Context, address common.Address) error {
+	ret := _m.Called(ctx, address)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address) error); ok {
+		r0 = rf(ctx, address)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewPoolMock creates a new instance of PoolMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPoolMock(t interface {
@@ -76,6 +76,65 @@ func (_m *StateMock) BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
 	return r0, r1
 }
 
+// CheckSequenceDataIntegrity provides a mock function with given fields: ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx
+func (_m *StateMock) CheckSequenceDataIntegrity(ctx context.Context, sequence state.Sequence, oldAccInputHash common.Hash, l1AccInputHash common.Hash, dbTx pgx.Tx) (*state.SequenceDataIntegrity, error) {
+	ret := _m.Called(ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx)
+
+	var r0 *state.SequenceDataIntegrity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, state.Sequence, common.Hash, common.Hash, pgx.Tx) (*state.SequenceDataIntegrity, error)); ok {
+		return rf(ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, state.Sequence, common.Hash, common.Hash, pgx.Tx) *state.SequenceDataIntegrity); ok {
+		r0 = rf(ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.SequenceDataIntegrity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, state.Sequence, common.Hash, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, sequence, oldAccInputHash, l1AccInputHash, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompareAccInputHashes provides a mock function with given fields: ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx
+func (_m *StateMock) CompareAccInputHashes(ctx context.Context, fromBatchNumber uint64, toBatchNumber uint64, l1AccInputHash func(context.Context, uint64) (common.Hash, error), dbTx pgx.Tx) (uint64, *state.AccInputHashDivergence, error) {
+	ret := _m.Called(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+
+	var r0 uint64
+	var r1 *state.AccInputHashDivergence
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, func(context.Context, uint64) (common.Hash, error), pgx.Tx) (uint64, *state.AccInputHashDivergence, error)); ok {
+		return rf(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, func(context.Context, uint64) (common.Hash, error), pgx.Tx) uint64); ok {
+		r0 = rf(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, func(context.Context, uint64) (common.Hash, error), pgx.Tx) *state.AccInputHashDivergence); ok {
+		r1 = rf(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*state.AccInputHashDivergence)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, uint64, func(context.Context, uint64) (common.Hash, error), pgx.Tx) error); ok {
+		r2 = rf(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // DebugTransaction provides a mock function with given fields: ctx, transactionHash, traceConfig, dbTx
 func (_m *StateMock) DebugTransaction(ctx context.Context, transactionHash common.Hash, traceConfig state.TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
 	ret := _m.Called(ctx, transactionHash, traceConfig, dbTx)
@@ -102,32 +161,32 @@ func (_m *StateMock) DebugTransaction(ctx context.Context, transactionHash commo
 	return r0, r1
 }
 
-// EstimateGas provides a mock function with given fields: transaction, senderAddress, l2BlockNumber, dbTx
-func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, []byte, error) {
-	ret := _m.Called(transaction, senderAddress, l2BlockNumber, dbTx)
+// EstimateGas provides a mock function with given fields: transaction, senderAddress, l2BlockNumber, stateOverride, dbTx
+func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, stateOverride state.StateOverride, dbTx pgx.Tx) (uint64, []byte, error) {
+	ret := _m.Called(transaction, senderAddress, l2BlockNumber, stateOverride, dbTx)
 
 	var r0 uint64
 	var r1 []byte
 	var r2 error
-	if rf, ok := ret.Get(0).(func(*coretypes.Transaction, common.Address, *uint64, pgx.Tx) (uint64, []byte, error)); ok {
-		return rf(transaction, senderAddress, l2BlockNumber, dbTx)
+	if rf, ok := ret.Get(0).(func(*coretypes.Transaction, common.Address, *uint64, state.StateOverride, pgx.Tx) (uint64, []byte, error)); ok {
+		return rf(transaction, senderAddress, l2BlockNumber, stateOverride, dbTx)
 	}
-	if rf, ok := ret.Get(0).(func(*coretypes.Transaction, common.Address, *uint64, pgx.Tx) uint64); ok {
-		r0 = rf(transaction, senderAddress, l2BlockNumber, dbTx)
+	if rf, ok := ret.Get(0).(func(*coretypes.Transaction, common.Address, *uint64, state.StateOverride, pgx.Tx) uint64); ok {
+		r0 = rf(transaction, senderAddress, l2BlockNumber, stateOverride, dbTx)
 	} else {
 		r0 = ret.Get(0).(uint64)
 	}
 
-	if rf, ok := ret.Get(1).(func(*coretypes.Transaction, common.Address, *uint64, pgx.Tx) []byte); ok {
-		r1 = rf(transaction, senderAddress, l2BlockNumber, dbTx)
+	if rf, ok := ret.Get(1).(func(*coretypes.Transaction, common.Address, *uint64, state.StateOverride, pgx.Tx) []byte); ok {
+		r1 = rf(transaction, senderAddress, l2BlockNumber, stateOverride, dbTx)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(*coretypes.Transaction, common.Address, *uint64, pgx.Tx) error); ok {
-		r2 = rf(transaction, senderAddress, l2BlockNumber, dbTx)
+	if rf, ok := ret.Get(2).(func(*coretypes.Transaction, common.Address, *uint64, state.StateOverride, pgx.Tx) error); ok {
+		r2 = rf(transaction, senderAddress, l2BlockNumber, stateOverride, dbTx)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -187,6 +246,84 @@ func (_m *StateMock) GetBatchByNumber(ctx context.Context, batchNumber uint64, d
 	return r0, r1
 }
 
+// GetBatchLifecycle provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetBatchLifecycle(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]state.BatchLifecycleTransition, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	var r0 []state.BatchLifecycleTransition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]state.BatchLifecycleTransition, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []state.BatchLifecycleTransition); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.BatchLifecycleTransition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBridgeClaimsByDestinationAddress provides a mock function with given fields: ctx, destinationAddress, limit, dbTx
+func (_m *StateMock) GetBridgeClaimsByDestinationAddress(ctx context.Context, destinationAddress common.Address, limit uint64, dbTx pgx.Tx) ([]state.BridgeClaim, error) {
+	ret := _m.Called(ctx, destinationAddress, limit, dbTx)
+
+	var r0 []state.BridgeClaim
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, pgx.Tx) ([]state.BridgeClaim, error)); ok {
+		return rf(ctx, destinationAddress, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, pgx.Tx) []state.BridgeClaim); ok {
+		r0 = rf(ctx, destinationAddress, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.BridgeClaim)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, destinationAddress, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBridgeDepositsByDestinationAddress provides a mock function with given fields: ctx, destinationAddress, limit, dbTx
+func (_m *StateMock) GetBridgeDepositsByDestinationAddress(ctx context.Context, destinationAddress common.Address, limit uint64, dbTx pgx.Tx) ([]state.BridgeDeposit, error) {
+	ret := _m.Called(ctx, destinationAddress, limit, dbTx)
+
+	var r0 []state.BridgeDeposit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, pgx.Tx) ([]state.BridgeDeposit, error)); ok {
+		return rf(ctx, destinationAddress, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, uint64, pgx.Tx) []state.BridgeDeposit); ok {
+		r0 = rf(ctx, destinationAddress, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.BridgeDeposit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, destinationAddress, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCode provides a mock function with given fields: ctx, address, root
 func (_m *StateMock) GetCode(ctx context.Context, address common.Address, root common.Hash) ([]byte, error) {
 	ret := _m.Called(ctx, address, root)
@@ -239,6 +376,32 @@ func (_m *StateMock) GetExitRootByGlobalExitRoot(ctx context.Context, ger common
 	return r0, r1
 }
 
+// GetForcedBatchInclusion provides a mock function with given fields: ctx, forcedBatchNumber, dbTx
+func (_m *StateMock) GetForcedBatchInclusion(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (*state.ForcedBatchInclusion, error) {
+	ret := _m.Called(ctx, forcedBatchNumber, dbTx)
+
+	var r0 *state.ForcedBatchInclusion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.ForcedBatchInclusion, error)); ok {
+		return rf(ctx, forcedBatchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.ForcedBatchInclusion); ok {
+		r0 = rf(ctx, forcedBatchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.ForcedBatchInclusion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, forcedBatchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetL2BlockByHash provides a mock function with given fields: ctx, hash, dbTx
 func (_m *StateMock) GetL2BlockByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*coretypes.Block, error) {
 	ret := _m.Called(ctx, hash, dbTx)
@@ -737,6 +900,58 @@ func (_m *StateMock) GetNonce(ctx context.Context, address common.Address, root
 	return r0, r1
 }
 
+// GetSequencesInRange provides a mock function with given fields: ctx, fromBatchNumber, toBatchNumber, dbTx
+func (_m *StateMock) GetSequencesInRange(ctx context.Context, fromBatchNumber uint64, toBatchNumber uint64, dbTx pgx.Tx) ([]state.Sequence, error) {
+	ret := _m.Called(ctx, fromBatchNumber, toBatchNumber, dbTx)
+
+	var r0 []state.Sequence
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) ([]state.Sequence, error)); ok {
+		return rf(ctx, fromBatchNumber, toBatchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []state.Sequence); ok {
+		r0 = rf(ctx, fromBatchNumber, toBatchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.Sequence)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, fromBatchNumber, toBatchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStateRootByL2BlockNumber provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *StateMock) GetStateRootByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (common.Hash, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (common.Hash, error)); ok {
+		return rf(ctx, blockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) common.Hash); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStorageAt provides a mock function with given fields: ctx, address, position, root
 func (_m *StateMock) GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error) {
 	ret := _m.Called(ctx, address, position, root)
@@ -763,6 +978,63 @@ func (_m *StateMock) GetStorageAt(ctx context.Context, address common.Address, p
 	return r0, r1
 }
 
+// GetProof provides a mock function with given fields: ctx, address, storageKeys, root
+func (_m *StateMock) GetProof(ctx context.Context, address common.Address, storageKeys []common.Hash, root common.Hash) (*state.AccountProof, error) {
+	ret := _m.Called(ctx, address, storageKeys, root)
+
+	var r0 *state.AccountProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, []common.Hash, common.Hash) (*state.AccountProof, error)); ok {
+		return rf(ctx, address, storageKeys, root)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, []common.Hash, common.Hash) *state.AccountProof); ok {
+		r0 = rf(ctx, address, storageKeys, root)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.AccountProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, []common.Hash, common.Hash) error); ok {
+		r1 = rf(ctx, address, storageKeys, root)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoredFlushID provides a mock function with given fields: ctx
+func (_m *StateMock) GetStoredFlushID(ctx context.Context) (uint64, string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 uint64
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (uint64, string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) uint64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) string); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetSyncingInfo provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (state.SyncingInfo, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -952,6 +1224,32 @@ func (_m *StateMock) GetVerifiedBatch(ctx context.Context, batchNumber uint64, d
 	return r0, r1
 }
 
+// GetVerifiedBatchProof provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetVerifiedBatchProof(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatchProof, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	var r0 *state.VerifiedBatchProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.VerifiedBatchProof, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.VerifiedBatchProof); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.VerifiedBatchProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVirtualBatch provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StateMock) GetVirtualBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VirtualBatch, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -1026,25 +1324,25 @@ func (_m *StateMock) IsL2BlockVirtualized(ctx context.Context, blockNumber uint6
 	return r0, r1
 }
 
-// ProcessUnsignedTransaction provides a mock function with given fields: ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx
-func (_m *StateMock) ProcessUnsignedTransaction(ctx context.Context, tx *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
-	ret := _m.Called(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+// ProcessUnsignedTransaction provides a mock function with given fields: ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, dbTx
+func (_m *StateMock) ProcessUnsignedTransaction(ctx context.Context, tx *coretypes.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, stateOverride state.StateOverride, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+	ret := _m.Called(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, dbTx)
 
 	var r0 *runtime.ExecutionResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, pgx.Tx) (*runtime.ExecutionResult, error)); ok {
-		return rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, state.StateOverride, pgx.Tx) (*runtime.ExecutionResult, error)); ok {
+		return rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, dbTx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, pgx.Tx) *runtime.ExecutionResult); ok {
-		r0 = rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, state.StateOverride, pgx.Tx) *runtime.ExecutionResult); ok {
+		r0 = rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, dbTx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*runtime.ExecutionResult)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, pgx.Tx) error); ok {
-		r1 = rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	if rf, ok := ret.Get(1).(func(context.Context, *coretypes.Transaction, common.Address, *uint64, bool, state.StateOverride, pgx.Tx) error); ok {
+		r1 = rf(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, dbTx)
 	} else {
 		r1 = ret.Error(1)
 	}
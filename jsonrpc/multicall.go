@@ -0,0 +1,62 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// Multicall executes independent read only calls on top of the state of the same block in a
+// single request, the block is optional and defaults to the latest one. The calls are executed
+// concurrently and the result of each one is returned in the order of the calls, a failing
+// call only reports its error in its result
+func (z *ZKEVMEndpoints) Multicall(args []types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	if len(args) == 0 {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+	} else if z.cfg.MaxMulticallCalls > 0 && uint64(len(args)) > z.cfg.MaxMulticallCalls {
+		errMsg := fmt.Sprintf("too many calls, the max number of calls of a multicall is %d", z.cfg.MaxMulticallCalls)
+		return RPCErrorResponse(types.LimitExceededErrorCode, errMsg, nil, false)
+	}
+
+	res, respErr := z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		return z.calls.getBlockByArg(ctx, blockArg, dbTx)
+	})
+	if respErr != nil {
+		return nil, respErr
+	}
+	block := res.(*ethTypes.Block)
+
+	// the calls run out of the db tx, that can't be shared by them, so the block is
+	// processed by number to execute all of them on top of the same state root
+	blockNumber := block.NumberU64()
+	concurrency := z.cfg.MulticallConcurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	results := make([]types.MulticallResult, len(args))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range args {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			returnData, err := z.calls.call(context.Background(), &args[i], block, &blockNumber, nil, nil)
+			if err != nil {
+				results[i] = types.NewMulticallResult(nil, err)
+				return
+			}
+			results[i] = types.NewMulticallResult(returnData.(*types.ArgBytes), nil)
+		}(i)
+	}
+	wg.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,241 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PostgresStorage uses the state database to store the filters
+// related to the json rpc server, so they survive restarts of the
+// node and are shared by all the nodes using the same database
+type PostgresStorage struct {
+	db *pgxpool.Pool
+	// wsFilters keeps the filters bound to a web socket connection in memory,
+	// they can't outlive the connection so they are not persisted
+	wsFilters *Storage
+}
+
+// NewPostgresStorage creates and initializes an instance of PostgresStorage
+func NewPostgresStorage(db *pgxpool.Pool) *PostgresStorage {
+	return &PostgresStorage{
+		db:        db,
+		wsFilters: NewStorage(),
+	}
+}
+
+// NewLogFilter persists a new log filter
+func (s *PostgresStorage) NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error) {
+	if wsConn != nil {
+		return s.wsFilters.NewLogFilter(wsConn, filter)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return "", err
+	}
+
+	parameters, err := json.Marshal(&filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode log filter: %w", err)
+	}
+	return s.createFilter(FilterTypeLog, parameters)
+}
+
+// NewBlockFilter persists a new block log filter
+func (s *PostgresStorage) NewBlockFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn != nil {
+		return s.wsFilters.NewBlockFilter(wsConn)
+	}
+	return s.createFilter(FilterTypeBlock, nil)
+}
+
+// NewPendingTransactionFilter persists a new pending transaction filter
+func (s *PostgresStorage) NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn != nil {
+		return s.wsFilters.NewPendingTransactionFilter(wsConn)
+	}
+	return s.createFilter(FilterTypePendingTx, nil)
+}
+
+// NewDroppedTransactionFilter persists a new dropped transaction filter
+func (s *PostgresStorage) NewDroppedTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn != nil {
+		return s.wsFilters.NewDroppedTransactionFilter(wsConn)
+	}
+	return s.createFilter(FilterTypeDroppedTx, nil)
+}
+
+// NewAccountChangesFilter persists a new account changes filter, it's only
+// available for the web socket subscriptions
+func (s *PostgresStorage) NewAccountChangesFilter(wsConn *concurrentWsConn, addresses []common.Address) (string, error) {
+	if wsConn == nil {
+		return "", errors.New("account changes filters require a web socket connection")
+	}
+	return s.wsFilters.NewAccountChangesFilter(wsConn, addresses)
+}
+
+// NewBatchStatusFilter persists a new batch status filter, it's only
+// available for the web socket subscriptions
+func (s *PostgresStorage) NewBatchStatusFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn == nil {
+		return "", errors.New("batch status filters require a web socket connection")
+	}
+	return s.wsFilters.NewBatchStatusFilter(wsConn)
+}
+
+// NewSelectedTransactionFilter persists a new selected transaction filter, it's only
+// available for the web socket subscriptions
+func (s *PostgresStorage) NewSelectedTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	if wsConn == nil {
+		return "", errors.New("selected transaction filters require a web socket connection")
+	}
+	return s.wsFilters.NewSelectedTransactionFilter(wsConn)
+}
+
+// createFilter persists the filter to the database and provides the filter id
+func (s *PostgresStorage) createFilter(t FilterType, parameters []byte) (string, error) {
+	id, err := generateFilterID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate filter ID: %w", err)
+	}
+
+	const createFilterSQL = "INSERT INTO state.rpc_filter (id, filter_type, parameters, last_poll) VALUES ($1, $2, $3, $4)"
+	if _, err := s.db.Exec(context.Background(), createFilterSQL, id, string(t), parameters, time.Now().UTC()); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetAllBlockFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by new blocks
+func (s *PostgresStorage) GetAllBlockFiltersWithWSConn() ([]*Filter, error) {
+	return s.wsFilters.GetAllBlockFiltersWithWSConn()
+}
+
+// GetAllLogFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by new logs
+func (s *PostgresStorage) GetAllLogFiltersWithWSConn() ([]*Filter, error) {
+	return s.wsFilters.GetAllLogFiltersWithWSConn()
+}
+
+// GetAllDroppedTxFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by dropped txs
+func (s *PostgresStorage) GetAllDroppedTxFiltersWithWSConn() ([]*Filter, error) {
+	return s.wsFilters.GetAllDroppedTxFiltersWithWSConn()
+}
+
+// GetAllAccountChangesFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by account changes
+func (s *PostgresStorage) GetAllAccountChangesFiltersWithWSConn() ([]*Filter, error) {
+	return s.wsFilters.GetAllAccountChangesFiltersWithWSConn()
+}
+
+// GetAllBatchStatusFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by batch status transitions
+func (s *PostgresStorage) GetAllBatchStatusFiltersWithWSConn() ([]*Filter, error) {
+	return s.wsFilters.GetAllBatchStatusFiltersWithWSConn()
+}
+
+// GetAllSelectedTxFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by the txs selected by the sequencer
+func (s *PostgresStorage) GetAllSelectedTxFiltersWithWSConn() ([]*Filter, error) {
+	return s.wsFilters.GetAllSelectedTxFiltersWithWSConn()
+}
+
+// GetAllPendingTxFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by new pending txs
+func (s *PostgresStorage) GetAllPendingTxFiltersWithWSConn() ([]*Filter, error) {
+	return s.wsFilters.GetAllPendingTxFiltersWithWSConn()
+}
+
+// GetFilter gets a filter by its id
+func (s *PostgresStorage) GetFilter(filterID string) (*Filter, error) {
+	if filter, err := s.wsFilters.GetFilter(filterID); err == nil {
+		return filter, nil
+	}
+
+	const getFilterSQL = "SELECT filter_type, parameters, last_poll FROM state.rpc_filter WHERE id = $1"
+	var (
+		filterType string
+		parameters []byte
+		lastPoll   time.Time
+	)
+	err := s.db.QueryRow(context.Background(), getFilterSQL, filterID).Scan(&filterType, &parameters, &lastPoll)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	filter := &Filter{
+		ID:       filterID,
+		Type:     FilterType(filterType),
+		LastPoll: lastPoll.UTC(),
+	}
+	if filterType == FilterTypeLog {
+		var logFilter LogFilter
+		if err := json.Unmarshal(parameters, &logFilter); err != nil {
+			return nil, fmt.Errorf("failed to decode log filter %s: %w", filterID, err)
+		}
+		filter.Parameters = logFilter
+	}
+
+	return filter, nil
+}
+
+// UpdateFilterLastPoll updates the last poll to now
+func (s *PostgresStorage) UpdateFilterLastPoll(filterID string) error {
+	if err := s.wsFilters.UpdateFilterLastPoll(filterID); !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	const updateFilterLastPollSQL = "UPDATE state.rpc_filter SET last_poll = $2 WHERE id = $1"
+	commandTag, err := s.db.Exec(context.Background(), updateFilterLastPollSQL, filterID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UninstallFilter deletes a filter by its id
+func (s *PostgresStorage) UninstallFilter(filterID string) error {
+	if err := s.wsFilters.UninstallFilter(filterID); !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	const uninstallFilterSQL = "DELETE FROM state.rpc_filter WHERE id = $1"
+	commandTag, err := s.db.Exec(context.Background(), uninstallFilterSQL, filterID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UninstallFilterByWSConn deletes all filters connected to the provided web socket connection
+func (s *PostgresStorage) UninstallFilterByWSConn(wsConn *concurrentWsConn) error {
+	return s.wsFilters.UninstallFilterByWSConn(wsConn)
+}
+
+// UninstallExpiredFilters deletes the persisted filters that haven't been polled
+// since the provided time, returning how many were deleted
+func (s *PostgresStorage) UninstallExpiredFilters(lastPollBefore time.Time) (int, error) {
+	const uninstallExpiredFiltersSQL = "DELETE FROM state.rpc_filter WHERE last_poll < $1"
+	commandTag, err := s.db.Exec(context.Background(), uninstallExpiredFiltersSQL, lastPollBefore.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return int(commandTag.RowsAffected()), nil
+}
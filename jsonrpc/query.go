@@ -4,14 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"sync/atomic"
 	"time"
 
-	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/gorilla/websocket"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -22,15 +19,26 @@ const (
 	FilterTypeBlock = "block"
 	// FilterTypePendingTx represent a filter of type pending Tx.
 	FilterTypePendingTx = "pendingTx"
+	// FilterTypeDroppedTx represent a filter of type dropped Tx.
+	FilterTypeDroppedTx = "droppedTx"
+	// FilterTypeBatchStatus represent a filter of type batch status.
+	FilterTypeBatchStatus = "batchStatus"
+	// FilterTypeSelectedTx represent a filter of type selected Tx.
+	FilterTypeSelectedTx = "selectedTx"
+	// FilterTypeAccountChanges represent a filter of type account changes.
+	FilterTypeAccountChanges = "accountChanges"
 )
 
+// maxLogFilterTopics is the max number of topic positions of a log filter, as the logs have at most 4 topics
+const maxLogFilterTopics = 4
+
 // Filter represents a filter.
 type Filter struct {
 	ID         string
 	Type       FilterType
 	Parameters interface{}
 	LastPoll   time.Time
-	WsConn     *atomic.Pointer[websocket.Conn]
+	WsConn     *concurrentWsConn
 }
 
 // FilterType express the type of the filter, block, logs, pending transactions
@@ -95,7 +103,7 @@ func (f *LogFilter) MarshalJSON() ([]byte, error) {
 		fromblock := ""
 		obj.FromBlock = &fromblock
 	} else if f.FromBlock != nil {
-		fromblock := hex.EncodeUint64(uint64(*f.FromBlock))
+		fromblock := f.FromBlock.StringOrHex()
 		obj.FromBlock = &fromblock
 	}
 
@@ -103,7 +111,7 @@ func (f *LogFilter) MarshalJSON() ([]byte, error) {
 		toblock := ""
 		obj.ToBlock = &toblock
 	} else if f.ToBlock != nil {
-		toblock := hex.EncodeUint64(uint64(*f.ToBlock))
+		toblock := f.ToBlock.StringOrHex()
 		obj.ToBlock = &toblock
 	}
 
@@ -199,10 +207,14 @@ func (f *LogFilter) UnmarshalJSON(data []byte) error {
 				}
 
 			case []interface{}:
-				// ["", ""]
+				// ["", ""], a null item matches any topic, so the whole position is a wildcard
 				res := []string{}
 
 				for _, i := range raw {
+					if i == nil {
+						res = nil
+						break
+					}
 					if item, ok := i.(string); ok {
 						res = append(res, item)
 					} else {
@@ -273,7 +285,7 @@ func (f *LogFilter) Match(log *types.Log) bool {
 // GetNumericBlockNumbers load the numeric block numbers from state accordingly
 // to the provided from and to block number
 func (f *LogFilter) GetNumericBlockNumbers(ctx context.Context, cfg Config, s types.StateInterface, e types.EthermanInterface, dbTx pgx.Tx) (uint64, uint64, types.Error) {
-	return getNumericBlockNumbers(ctx, s, e, f.FromBlock, f.ToBlock, cfg.MaxLogsBlockRange, state.ErrMaxLogsBlockRangeLimitExceeded, dbTx)
+	return getNumericBlockNumbers(ctx, s, e, f.FromBlock, f.ToBlock, cfg.MaxLogsBlockRange, state.ErrMaxLogsBlockRangeLimitExceeded, types.LimitExceededErrorCode, dbTx)
 }
 
 // ShouldFilterByBlockHash if the filter should consider the block hash value
@@ -291,6 +303,9 @@ func (f *LogFilter) Validate() error {
 	if f.ShouldFilterByBlockHash() && f.ShouldFilterByBlockRange() {
 		return ErrFilterInvalidPayload
 	}
+	if len(f.Topics) > maxLogFilterTopics {
+		return ErrFilterTooManyTopics
+	}
 	return nil
 }
 
@@ -303,12 +318,12 @@ type NativeBlockHashBlockRangeFilter struct {
 // GetNumericBlockNumbers load the numeric block numbers from state accordingly
 // to the provided from and to block number
 func (f *NativeBlockHashBlockRangeFilter) GetNumericBlockNumbers(ctx context.Context, cfg Config, s types.StateInterface, e types.EthermanInterface, dbTx pgx.Tx) (uint64, uint64, types.Error) {
-	return getNumericBlockNumbers(ctx, s, e, &f.FromBlock, &f.ToBlock, cfg.MaxNativeBlockHashBlockRange, state.ErrMaxNativeBlockHashBlockRangeLimitExceeded, dbTx)
+	return getNumericBlockNumbers(ctx, s, e, &f.FromBlock, &f.ToBlock, cfg.MaxNativeBlockHashBlockRange, state.ErrMaxNativeBlockHashBlockRangeLimitExceeded, types.InvalidParamsErrorCode, dbTx)
 }
 
 // getNumericBlockNumbers load the numeric block numbers from state accordingly
 // to the provided from and to block number
-func getNumericBlockNumbers(ctx context.Context, s types.StateInterface, e types.EthermanInterface, fromBlock, toBlock *types.BlockNumber, maxBlockRange uint64, maxBlockRangeErr error, dbTx pgx.Tx) (uint64, uint64, types.Error) {
+func getNumericBlockNumbers(ctx context.Context, s types.StateInterface, e types.EthermanInterface, fromBlock, toBlock *types.BlockNumber, maxBlockRange uint64, maxBlockRangeErr error, maxBlockRangeErrCode int, dbTx pgx.Tx) (uint64, uint64, types.Error) {
 	var fromBlockNumber uint64 = 0
 	if fromBlock != nil {
 		fbn, rpcErr := fromBlock.GetNumericBlockNumber(ctx, s, e, dbTx)
@@ -331,7 +346,7 @@ func getNumericBlockNumbers(ctx context.Context, s types.StateInterface, e types
 	blockRange := toBlockNumber - fromBlockNumber
 	if maxBlockRange > 0 && blockRange > maxBlockRange {
 		errMsg := fmt.Sprintf(maxBlockRangeErr.Error(), maxBlockRange)
-		_, rpcErr := RPCErrorResponse(types.InvalidParamsErrorCode, errMsg, nil, false)
+		_, rpcErr := RPCErrorResponse(maxBlockRangeErrCode, errMsg, nil, false)
 		return 0, 0, rpcErr
 	}
 
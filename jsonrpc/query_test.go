@@ -0,0 +1,141 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFilterUnmarshalJSON(t *testing.T) {
+	topicA := common.HexToHash("0xa")
+	topicB := common.HexToHash("0xb")
+	addrA := common.HexToAddress("0x1")
+	addrB := common.HexToAddress("0x2")
+
+	testCases := []struct {
+		name              string
+		input             string
+		expectedAddresses []common.Address
+		expectedTopics    [][]common.Hash
+		expectedErr       bool
+	}{
+		{
+			name:              "single address and topic",
+			input:             `{"address":"` + addrA.Hex() + `","topics":["` + topicA.Hex() + `"]}`,
+			expectedAddresses: []common.Address{addrA},
+			expectedTopics:    [][]common.Hash{{topicA}},
+		},
+		{
+			name:              "address list",
+			input:             `{"address":["` + addrA.Hex() + `","` + addrB.Hex() + `"]}`,
+			expectedAddresses: []common.Address{addrA, addrB},
+		},
+		{
+			name:           "null wildcard followed by an OR set",
+			input:          `{"topics":[null,["` + topicA.Hex() + `","` + topicB.Hex() + `"]]}`,
+			expectedTopics: [][]common.Hash{{}, {topicA, topicB}},
+		},
+		{
+			name:           "null inside an OR set matches any topic",
+			input:          `{"topics":[["` + topicA.Hex() + `",null],"` + topicB.Hex() + `"]}`,
+			expectedTopics: [][]common.Hash{{}, {topicB}},
+		},
+		{
+			name:           "empty OR set matches any topic",
+			input:          `{"topics":[[],"` + topicB.Hex() + `"]}`,
+			expectedTopics: [][]common.Hash{{}, {topicB}},
+		},
+		{
+			name:        "invalid topic in OR set",
+			input:       `{"topics":[["` + topicA.Hex() + `",1]]}`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid topic length",
+			input:       `{"topics":["0x1234"]}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var filter LogFilter
+			err := json.Unmarshal([]byte(tc.input), &filter)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAddresses, filter.Addresses)
+			assert.Equal(t, tc.expectedTopics, filter.Topics)
+		})
+	}
+}
+
+func TestLogFilterMarshalJSON(t *testing.T) {
+	blockHash := common.HexToHash("0x1")
+	fromBlock := types.SafeBlockNumber
+	toBlock := types.BlockNumber(100)
+	latest := types.LatestBlockNumber
+
+	testCases := []LogFilter{
+		{BlockHash: &blockHash, Addresses: []common.Address{common.HexToAddress("0x2")}},
+		{FromBlock: &fromBlock, ToBlock: &toBlock, Addresses: []common.Address{common.HexToAddress("0x2"), common.HexToAddress("0x3")}},
+		{FromBlock: &latest, Topics: [][]common.Hash{{}, {common.HexToHash("0xa")}, {common.HexToHash("0xb"), common.HexToHash("0xc")}}},
+	}
+
+	for _, filter := range testCases {
+		b, err := json.Marshal(&filter)
+		require.NoError(t, err)
+
+		var decoded LogFilter
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, filter, decoded)
+	}
+}
+
+func TestLogFilterValidate(t *testing.T) {
+	var filter LogFilter
+	require.NoError(t, json.Unmarshal([]byte(`{"topics":[null,null,null,null]}`), &filter))
+	assert.NoError(t, filter.Validate())
+
+	var tooManyTopicsFilter LogFilter
+	require.NoError(t, json.Unmarshal([]byte(`{"topics":[null,null,null,null,null]}`), &tooManyTopicsFilter))
+	assert.ErrorIs(t, tooManyTopicsFilter.Validate(), ErrFilterTooManyTopics)
+}
+
+func TestLogFilterMatch(t *testing.T) {
+	topicA := common.HexToHash("0xa")
+	topicB := common.HexToHash("0xb")
+	topicC := common.HexToHash("0xc")
+	addrA := common.HexToAddress("0x1")
+	addrB := common.HexToAddress("0x2")
+
+	log := &types.Log{Address: addrA, Topics: []common.Hash{topicA, topicB}}
+
+	testCases := []struct {
+		name     string
+		filter   LogFilter
+		expected bool
+	}{
+		{name: "empty filter", filter: LogFilter{}, expected: true},
+		{name: "address in list", filter: LogFilter{Addresses: []common.Address{addrB, addrA}}, expected: true},
+		{name: "address not in list", filter: LogFilter{Addresses: []common.Address{addrB}}, expected: false},
+		{name: "exact topics", filter: LogFilter{Topics: [][]common.Hash{{topicA}, {topicB}}}, expected: true},
+		{name: "wildcard and exact topic", filter: LogFilter{Topics: [][]common.Hash{{}, {topicB}}}, expected: true},
+		{name: "OR set in first position", filter: LogFilter{Topics: [][]common.Hash{{topicC, topicA}}}, expected: true},
+		{name: "OR set without match", filter: LogFilter{Topics: [][]common.Hash{{}, {topicA, topicC}}}, expected: false},
+		{name: "more positions than log topics", filter: LogFilter{Topics: [][]common.Hash{{}, {}, {}}}, expected: false},
+		{name: "topics in wrong order", filter: LogFilter{Topics: [][]common.Hash{{topicB}, {topicA}}}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.filter.Match(log))
+		})
+	}
+}
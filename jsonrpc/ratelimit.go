@@ -0,0 +1,207 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// limitersCleanupInterval is the interval to remove the limiters of the IPs without recent requests
+	limitersCleanupInterval = time.Minute
+	// limiterIdleTimeout is the time without requests after which the limiter of an IP is removed
+	limiterIdleTimeout = 5 * time.Minute
+	// sharedLimiterKey identifies the limiter shared by the methods without a specific limit
+	sharedLimiterKey = "*"
+)
+
+type limiterKey struct {
+	ip     string
+	method string
+}
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter limits the requests per IP and method using token buckets, the
+// methods without a specific limit share the same bucket of each IP
+type rateLimiter struct {
+	cfg        RateLimitConfig
+	methods    map[string]MethodRateLimitConfig
+	trustedIPs []*net.IPNet
+
+	limiters    map[limiterKey]*limiterEntry
+	lastCleanup time.Time
+	mutex       sync.Mutex
+}
+
+func newRateLimiter(cfg RateLimitConfig) (*rateLimiter, error) {
+	l := &rateLimiter{
+		cfg:         cfg,
+		methods:     make(map[string]MethodRateLimitConfig, len(cfg.Methods)),
+		limiters:    make(map[limiterKey]*limiterEntry),
+		lastCleanup: time.Now(),
+	}
+	for _, m := range cfg.Methods {
+		l.methods[m.Method] = m
+	}
+	for _, trusted := range cfg.TrustedIPs {
+		ipNet, err := parseIPNet(trusted)
+		if err != nil {
+			return nil, err
+		}
+		l.trustedIPs = append(l.trustedIPs, ipNet)
+	}
+	return l, nil
+}
+
+// allow returns true if the IP can send a new request for the method
+func (l *rateLimiter) allow(ip, method string, now time.Time) bool {
+	if l.isTrusted(ip) {
+		return true
+	}
+
+	key := limiterKey{ip: ip, method: sharedLimiterKey}
+	requestsPerSecond, burst := l.cfg.RequestsPerSecond, l.cfg.Burst
+	if m, found := l.methods[method]; found {
+		key.method = method
+		requestsPerSecond, burst = m.RequestsPerSecond, m.Burst
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if now.Sub(l.lastCleanup) >= limitersCleanupInterval {
+		l.cleanup(now)
+	}
+
+	entry, found := l.limiters[key]
+	if !found {
+		entry = &limiterEntry{limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst)}
+		l.limiters[key] = entry
+	}
+	entry.lastSeen = now
+	return entry.limiter.AllowN(now, 1)
+}
+
+func (l *rateLimiter) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range l.trustedIPs {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanup removes the limiters without recent requests, it must be called with the mutex locked
+func (l *rateLimiter) cleanup(now time.Time) {
+	for key, entry := range l.limiters {
+		if now.Sub(entry.lastSeen) >= limiterIdleTimeout {
+			delete(l.limiters, key)
+		}
+	}
+	l.lastCleanup = now
+}
+
+// parseIPNet parses an IP or a CIDR range, a single IP is converted to a range containing only it
+func parseIPNet(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted IP range %s: %w", s, err)
+		}
+		return ipNet, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid trusted IP %s", s)
+	}
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 8 * net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// clientIPResolver resolves the IP of the client that sent a request. The X-Forwarded-For
+// header is only read when the request is sent by a trusted proxy, otherwise any client could
+// spoof it to bypass the per IP limits
+type clientIPResolver struct {
+	trustedProxies []*net.IPNet
+}
+
+func newClientIPResolver(trustedProxies []string) (*clientIPResolver, error) {
+	r := &clientIPResolver{}
+	for _, proxy := range trustedProxies {
+		ipNet, err := parseIPNet(proxy)
+		if err != nil {
+			return nil, err
+		}
+		r.trustedProxies = append(r.trustedProxies, ipNet)
+	}
+	return r, nil
+}
+
+// ip returns the IP of the client that sent the request. When it's sent by a trusted proxy
+// the rightmost X-Forwarded-For address that is not a trusted proxy is used, the addresses
+// on its left are set by the client and can't be trusted. Otherwise the remote address is used
+func (r *clientIPResolver) ip(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	ip := remoteIP(req)
+	if !r.isTrustedProxy(ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(forwardedIP) == nil {
+			// the chain is broken, the last hop forwarded by a trusted proxy is used
+			return ip
+		}
+		ip = forwardedIP
+		if !r.isTrustedProxy(ip) {
+			return ip
+		}
+	}
+	return ip
+}
+
+func (r *clientIPResolver) isTrustedProxy(ip string) bool {
+	if r == nil {
+		return false
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range r.trustedProxies {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the IP of the remote address of the request
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
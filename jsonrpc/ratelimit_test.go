@@ -0,0 +1,99 @@
+package jsonrpc
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter, err := newRateLimiter(RateLimitConfig{
+		Enabled:           true,
+		RequestsPerSecond: 1,
+		Burst:             2,
+		Methods: []MethodRateLimitConfig{
+			{Method: "eth_getLogs", RequestsPerSecond: 1, Burst: 1},
+		},
+		TrustedIPs: []string{"10.0.0.0/8", "192.168.1.1"},
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	// the methods without a specific limit share the bucket of the IP
+	assert.True(t, limiter.allow("1.1.1.1", "eth_chainId", now))
+	assert.True(t, limiter.allow("1.1.1.1", "eth_blockNumber", now))
+	assert.False(t, limiter.allow("1.1.1.1", "eth_chainId", now))
+
+	// the methods with a specific limit have their own bucket
+	assert.True(t, limiter.allow("1.1.1.1", "eth_getLogs", now))
+	assert.False(t, limiter.allow("1.1.1.1", "eth_getLogs", now))
+
+	// each IP has its own buckets
+	assert.True(t, limiter.allow("2.2.2.2", "eth_getLogs", now))
+
+	// the tokens are refilled over time
+	assert.True(t, limiter.allow("1.1.1.1", "eth_getLogs", now.Add(time.Second)))
+
+	// trusted IPs are never limited
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.allow("10.1.2.3", "eth_getLogs", now))
+		assert.True(t, limiter.allow("192.168.1.1", "eth_getLogs", now))
+	}
+	assert.True(t, limiter.allow("192.168.1.2", "eth_chainId", now))
+	assert.True(t, limiter.allow("192.168.1.2", "eth_chainId", now))
+	assert.False(t, limiter.allow("192.168.1.2", "eth_chainId", now))
+
+	// the limiters of the IPs without recent requests are removed
+	limiter.allow("3.3.3.3", "eth_chainId", now.Add(limiterIdleTimeout+limitersCleanupInterval))
+	assert.Equal(t, 1, len(limiter.limiters))
+}
+
+func TestRateLimiterInvalidTrustedIP(t *testing.T) {
+	_, err := newRateLimiter(RateLimitConfig{TrustedIPs: []string{"10.0.0.0/33"}})
+	assert.Error(t, err)
+	_, err = newRateLimiter(RateLimitConfig{TrustedIPs: []string{"localhost"}})
+	assert.Error(t, err)
+}
+
+func TestClientIPResolver(t *testing.T) {
+	resolver, err := newClientIPResolver([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.RemoteAddr = "1.2.3.4:5678"
+	assert.Equal(t, "1.2.3.4", resolver.ip(req))
+
+	// the header is ignored if the request isn't sent by a trusted proxy
+	req.Header.Set("X-Forwarded-For", "127.0.0.1")
+	assert.Equal(t, "1.2.3.4", resolver.ip(req))
+
+	// the rightmost address that is not a trusted proxy is used, the client can't spoof it
+	req.RemoteAddr = "10.0.0.1:5678"
+	req.Header.Set("X-Forwarded-For", "127.0.0.1, 5.6.7.8, 192.168.1.1")
+	assert.Equal(t, "5.6.7.8", resolver.ip(req))
+
+	// the headers set by several proxies are joined
+	req.Header.Set("X-Forwarded-For", "127.0.0.1")
+	req.Header.Add("X-Forwarded-For", "5.6.7.8")
+	assert.Equal(t, "5.6.7.8", resolver.ip(req))
+
+	// the last hop forwarded by a trusted proxy is used when the chain is broken
+	req.Header.Set("X-Forwarded-For", "5.6.7.8, invalid, 10.0.0.2")
+	assert.Equal(t, "10.0.0.2", resolver.ip(req))
+
+	req.Header.Del("X-Forwarded-For")
+	assert.Equal(t, "10.0.0.1", resolver.ip(req))
+
+	// without trusted proxies the remote address is always used
+	var noProxies *clientIPResolver
+	req.Header.Set("X-Forwarded-For", "5.6.7.8")
+	assert.Equal(t, "10.0.0.1", noProxies.ip(req))
+
+	assert.Equal(t, "", resolver.ip(nil))
+
+	_, err = newClientIPResolver([]string{"proxy"})
+	assert.Error(t, err)
+}
@@ -1,12 +1,10 @@
 package jsonrpc
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"mime"
 	"net"
 	"net/http"
@@ -18,6 +16,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/didip/tollbooth/v6"
 	"github.com/gorilla/websocket"
 )
@@ -35,6 +34,8 @@ const (
 	APITxPool = "txpool"
 	// APIWeb3 represents the web3 API prefix.
 	APIWeb3 = "web3"
+	// APIAdmin represents the admin API prefix.
+	APIAdmin = "admin"
 
 	wsBufferSizeLimitInBytes = 1024
 	maxRequestContentLength  = 1024 * 1024 * 5
@@ -49,10 +50,20 @@ type Server struct {
 	config     Config
 	chainID    uint64
 	handler    *Handler
+	storage    storageInterface
+	health     *healthChecker
+	cors       *cors
 	srv        *http.Server
 	wsSrv      *http.Server
 	wsUpgrader websocket.Upgrader
 
+	ipcMutex sync.Mutex
+	ipcLis   net.Listener
+	ipcConns map[net.Conn]struct{}
+
+	stopFilterCleanup chan struct{}
+	stopWarmUp        chan struct{}
+
 	connCounterMutex sync.Mutex
 	httpConnCounter  int64
 	wsConnCounter    int64
@@ -77,6 +88,7 @@ func NewServer(
 	chainID uint64,
 	p types.PoolInterface,
 	s types.StateInterface,
+	e types.EthermanInterface,
 	storage storageInterface,
 	services []Service,
 ) *Server {
@@ -85,19 +97,92 @@ func NewServer(
 	}
 
 	handler := newJSONRpcHandler()
+	handler.paramsSizeLimits = paramsSizeLimits(cfg)
+	handler.readOnly = cfg.ReadOnly
+	handler.adminAuthToken = cfg.AdminAuthToken
+	handler.disabledMethods = make(map[string]struct{}, len(cfg.DisabledMethods))
+	for _, method := range cfg.DisabledMethods {
+		handler.disabledMethods[method] = struct{}{}
+	}
+	if cfg.ReadOnly {
+		log.Info("RPC read-only mode is enabled, the methods mutating the node state are disabled")
+	}
+	if cfg.TLS.Enabled() {
+		log.Info("RPC TLS is enabled, the endpoints are served over HTTPS and WSS")
+	}
+	clientIP, err := newClientIPResolver(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("invalid trusted proxies configuration: %v", err)
+	}
+	handler.clientIP = clientIP
+	if cfg.RateLimit.Enabled {
+		limiter, err := newRateLimiter(cfg.RateLimit)
+		if err != nil {
+			log.Fatalf("invalid rate limit configuration: %v", err)
+		}
+		handler.rateLimiter = limiter
+	}
+	if cfg.ConcurrencyLimit.Enabled {
+		limiter, err := newConcurrencyLimiter(cfg.ConcurrencyLimit)
+		if err != nil {
+			log.Fatalf("invalid concurrency limit configuration: %v", err)
+		}
+		handler.concurrencyLimiter = limiter
+	}
+	if cfg.AuditLog.Enabled {
+		auditLog, err := newAuditLog(cfg.AuditLog)
+		if err != nil {
+			log.Fatalf("invalid audit log configuration: %v", err)
+		}
+		handler.auditLog = auditLog
+	}
 
 	for _, service := range services {
+		if service.Name == APIAdmin && cfg.AdminAuthToken == "" && !cfg.IPC.Enabled {
+			log.Warn("the admin namespace is not registered, it requires the RPC.AdminAuthToken to be served over HTTP and WebSockets or the RPC.IPC to be enabled")
+			continue
+		}
 		handler.registerService(service)
 	}
 
 	srv := &Server{
 		config:  cfg,
 		handler: handler,
+		storage: storage,
 		chainID: chainID,
+		cors:    newCORS(cfg.CORS),
+	}
+	if cfg.WarmUp.Enabled {
+		handler.warmUp = newWarmUpGate(cfg.WarmUp, s, newTrustedTip(cfg.SequencerNodeURI, s))
+	}
+	if cfg.HealthCheck.Enabled {
+		srv.health = newHealthChecker(cfg.HealthCheck, s, e)
+		if handler.warmUp != nil {
+			srv.health.checks["warmup"] = handler.warmUp.check
+		}
 	}
 	return srv
 }
 
+// paramsSizeLimits returns the max size of each param field of the methods
+// accepting large payloads, so oversized fields are rejected before decoding them
+func paramsSizeLimits(cfg Config) map[string]uint64 {
+	limits := map[string]uint64{}
+	if cfg.MaxRawTransactionSize > 0 {
+		// raw txs are hex encoded with the 0x prefix
+		rawTxLimit := 2*cfg.MaxRawTransactionSize + 2 //nolint:gomnd
+		limits["eth_sendRawTransaction"] = rawTxLimit
+		limits["eth_sendRawTransactionConditional"] = rawTxLimit
+	}
+	if cfg.MaxTracerSize > 0 {
+		for _, method := range []string{"debug_traceTransaction", "debug_traceBlockByNumber", "debug_traceBlockByHash", "debug_traceBatchByNumber",
+			"debug_submitTraceTransaction", "debug_submitTraceBlockByNumber", "debug_submitTraceBlockByHash"} {
+			limits[method] = cfg.MaxTracerSize
+		}
+	}
+	return limits
+}
+
 // Start initializes the JSON RPC server to listen for request
 func (s *Server) Start() error {
 	metrics.Register()
@@ -106,9 +191,52 @@ func (s *Server) Start() error {
 		go s.startWS()
 	}
 
+	if s.config.IPC.Enabled {
+		if err := s.startIPC(); err != nil {
+			return err
+		}
+	}
+
+	if s.config.FilterTimeout.Duration > 0 && s.stopFilterCleanup == nil {
+		s.stopFilterCleanup = make(chan struct{})
+		go s.cleanupExpiredFilters(s.stopFilterCleanup)
+	}
+
+	if s.handler.warmUp != nil && s.stopWarmUp == nil {
+		s.stopWarmUp = make(chan struct{})
+		go s.handler.warmUp.run(s.stopWarmUp)
+	}
+
 	return s.startHTTP()
 }
 
+// cleanupExpiredFilters periodically uninstalls the filters that haven't
+// been polled during the configured filter timeout
+func (s *Server) cleanupExpiredFilters(stop chan struct{}) {
+	interval := s.config.FilterCleanupInterval.Duration
+	if interval <= 0 {
+		interval = s.config.FilterTimeout.Duration
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			count, err := s.storage.UninstallExpiredFilters(time.Now().Add(-s.config.FilterTimeout.Duration))
+			if err != nil {
+				log.Errorf("failed to uninstall expired filters: %v", err)
+				continue
+			}
+			if count > 0 {
+				log.Debugf("%d expired filters uninstalled", count)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
 // startHTTP starts a server to respond http requests
 func (s *Server) startHTTP() error {
 	if s.srv != nil {
@@ -125,6 +253,12 @@ func (s *Server) startHTTP() error {
 
 	mux := http.NewServeMux()
 
+	// the health endpoints are not rate limited, so the load balancers can always check the node
+	if s.health != nil {
+		mux.HandleFunc(healthLivePath, s.health.handleLive)
+		mux.HandleFunc(healthReadyPath, s.health.handleReady)
+	}
+
 	lmt := tollbooth.NewLimiter(s.config.MaxRequestsPerIPAndSecond, nil)
 	mux.Handle("/", tollbooth.LimitFuncHandler(lmt, s.handle))
 
@@ -135,7 +269,7 @@ func (s *Server) startHTTP() error {
 		WriteTimeout:      s.config.WriteTimeout.Duration,
 	}
 	log.Infof("http server started: %s", address)
-	if err := s.srv.Serve(lis); err != nil {
+	if err := s.serve(s.srv, lis); err != nil {
 		if err == http.ErrServerClosed {
 			log.Infof("http server stopped")
 			return nil
@@ -173,11 +307,15 @@ func (s *Server) startWS() {
 		WriteTimeout:      s.config.WriteTimeout.Duration,
 	}
 	s.wsUpgrader = websocket.Upgrader{
-		ReadBufferSize:  wsBufferSizeLimitInBytes,
-		WriteBufferSize: wsBufferSizeLimitInBytes,
+		ReadBufferSize:    wsBufferSizeLimitInBytes,
+		WriteBufferSize:   wsBufferSizeLimitInBytes,
+		EnableCompression: s.config.EnableCompression,
+		CheckOrigin: func(r *http.Request) bool {
+			return s.cors.isOriginAllowed(r.Header.Get("Origin"))
+		},
 	}
 	log.Infof("websocket server started: %s", address)
-	if err := s.wsSrv.Serve(lis); err != nil {
+	if err := s.serve(s.wsSrv, lis); err != nil {
 		if err == http.ErrServerClosed {
 			log.Infof("websocket server stopped")
 			return
@@ -187,10 +325,19 @@ func (s *Server) startWS() {
 	}
 }
 
-// Stop shutdown the rpc server
+// serve accepts the connections of the listener, over TLS if it is configured
+func (s *Server) serve(srv *http.Server, lis net.Listener) error {
+	if s.config.TLS.Enabled() {
+		return srv.ServeTLS(lis, s.config.TLS.CertFile, s.config.TLS.KeyFile)
+	}
+	return srv.Serve(lis)
+}
+
+// Stop shutdown the rpc server, the in-flight requests are drained during
+// ShutdownTimeout before closing the remaining connections
 func (s *Server) Stop() error {
 	if s.srv != nil {
-		if err := s.srv.Shutdown(context.Background()); err != nil {
+		if err := s.shutdown(s.srv); err != nil {
 			return err
 		}
 
@@ -201,7 +348,7 @@ func (s *Server) Stop() error {
 	}
 
 	if s.wsSrv != nil {
-		if err := s.wsSrv.Shutdown(context.Background()); err != nil {
+		if err := s.shutdown(s.wsSrv); err != nil {
 			return err
 		}
 
@@ -211,14 +358,64 @@ func (s *Server) Stop() error {
 		s.wsSrv = nil
 	}
 
+	if err := s.stopIPC(); err != nil {
+		return err
+	}
+
+	if s.stopFilterCleanup != nil {
+		close(s.stopFilterCleanup)
+		s.stopFilterCleanup = nil
+	}
+
+	if s.stopWarmUp != nil {
+		close(s.stopWarmUp)
+		s.stopWarmUp = nil
+	}
+
+	if s.handler.auditLog != nil {
+		if err := s.handler.auditLog.close(); err != nil {
+			return err
+		}
+		s.handler.auditLog = nil
+	}
+
 	return nil
 }
 
+// shutdown stops accepting new connections and waits for the in-flight requests
+// to finish, if they are not finished after ShutdownTimeout the server is closed
+func (s *Server) shutdown(srv *http.Server) error {
+	ctx := context.Background()
+	if s.config.ShutdownTimeout.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.ShutdownTimeout.Duration)
+		defer cancel()
+	}
+	err := srv.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Warnf("in-flight requests not finished after %s, closing the remaining connections", s.config.ShutdownTimeout.Duration)
+		return nil
+	}
+	return err
+}
+
 func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
+	s.cors.setHeaders(w, req)
 	if req.Method == http.MethodOptions {
 		return
 	}
 
+	if !s.cors.isOriginAllowed(req.Header.Get("Origin")) {
+		handleInvalidRequest(w, fmt.Errorf("origin %s not allowed", req.Header.Get("Origin")), http.StatusForbidden)
+		return
+	}
+
+	if s.config.EnableCompression {
+		var flush func()
+		w, flush = withGzip(w, req)
+		defer flush()
+	}
+
 	if req.Method == http.MethodGet {
 		_, err := w.Write([]byte("zkEVM JSON RPC Server"))
 		if err != nil {
@@ -227,21 +424,15 @@ func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if code, err := validateRequest(req); err != nil {
+	if code, err := validateRequest(req, s.maxRequestContentLength()); err != nil {
 		handleInvalidRequest(w, err, code)
 		return
 	}
 
-	body := io.LimitReader(req.Body, maxRequestContentLength)
-	data, err := io.ReadAll(body)
-	if err != nil {
-		handleError(w, err)
-		return
-	}
-
-	single, err := s.isSingleRequest(data)
+	decoder := newRequestDecoder(w, req.Body, s.maxRequestContentLength())
+	single, err := decoder.isSingleRequest()
 	if err != nil {
-		handleInvalidRequest(w, err, http.StatusBadRequest)
+		handleInvalidRequest(w, err, decodeErrorStatusCode(err))
 		return
 	}
 
@@ -250,29 +441,34 @@ func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
 
 	start := time.Now()
 	w.Header().Set("Content-Type", contentType)
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
 	var respLen int
 	if single {
-		respLen = s.handleSingleRequest(req, w, data)
+		respLen = s.handleSingleRequest(req, w, decoder)
 	} else {
-		respLen = s.handleBatchRequest(req, w, data)
+		respLen = s.handleBatchRequest(req, w, decoder)
 	}
 	metrics.RequestDuration(start)
 	s.combinedLog(req, start, http.StatusOK, respLen)
 }
 
+// maxRequestContentLength returns the max size of the body of a HTTP request
+func (s *Server) maxRequestContentLength() int64 {
+	if s.config.MaxRequestContentLength > 0 {
+		return s.config.MaxRequestContentLength
+	}
+	return maxRequestContentLength
+}
+
 // validateRequest returns a non-zero response code and error message if the
 // request is invalid.
-func validateRequest(req *http.Request) (int, error) {
+func validateRequest(req *http.Request, maxContentLength int64) (int, error) {
 	if req.Method != http.MethodPost {
 		err := errors.New("method " + req.Method + " not allowed")
 		return http.StatusMethodNotAllowed, err
 	}
 
-	if req.ContentLength > maxRequestContentLength {
-		err := fmt.Errorf("content length too large (%d>%d)", req.ContentLength, maxRequestContentLength)
+	if req.ContentLength > maxContentLength {
+		err := fmt.Errorf("content length too large (%d>%d)", req.ContentLength, maxContentLength)
 		return http.StatusRequestEntityTooLarge, err
 	}
 
@@ -289,21 +485,11 @@ func validateRequest(req *http.Request) (int, error) {
 	return http.StatusUnsupportedMediaType, err
 }
 
-func (s *Server) isSingleRequest(data []byte) (bool, error) {
-	x := bytes.TrimLeft(data, " \t\r\n")
-
-	if len(x) == 0 {
-		return false, fmt.Errorf("empty request body")
-	}
-
-	return x[0] != '[', nil
-}
-
-func (s *Server) handleSingleRequest(httpRequest *http.Request, w http.ResponseWriter, data []byte) int {
+func (s *Server) handleSingleRequest(httpRequest *http.Request, w http.ResponseWriter, decoder *requestDecoder) int {
 	defer metrics.RequestHandled(metrics.RequestHandledLabelSingle)
-	request, err := s.parseRequest(data)
+	request, err := decoder.decodeRequest()
 	if err != nil {
-		handleInvalidRequest(w, err, http.StatusBadRequest)
+		handleInvalidRequest(w, err, decodeErrorStatusCode(err))
 		return 0
 	}
 	req := handleRequest{Request: request, HttpRequest: httpRequest}
@@ -323,7 +509,7 @@ func (s *Server) handleSingleRequest(httpRequest *http.Request, w http.ResponseW
 	return len(respBytes)
 }
 
-func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWriter, data []byte) int {
+func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWriter, decoder *requestDecoder) int {
 	// Checking if batch requests are enabled
 	if !s.config.BatchRequestsEnabled {
 		handleInvalidRequest(w, types.ErrBatchRequestsDisabled, http.StatusBadRequest)
@@ -331,29 +517,14 @@ func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWr
 	}
 
 	defer metrics.RequestHandled(metrics.RequestHandledLabelBatch)
-	requests, err := s.parseRequests(data)
+	// the batch requests limit is checked while decoding the requests
+	requests, err := decoder.decodeRequests(s.config.BatchRequestsLimit)
 	if err != nil {
-		handleInvalidRequest(w, err, http.StatusBadRequest)
+		handleInvalidRequest(w, err, decodeErrorStatusCode(err))
 		return 0
 	}
 
-	// Checking if batch requests limit is exceeded
-	if s.config.BatchRequestsLimit > 0 {
-		if len(requests) > int(s.config.BatchRequestsLimit) {
-			handleInvalidRequest(w, types.ErrBatchRequestsLimitExceeded, http.StatusRequestEntityTooLarge)
-			return 0
-		}
-	}
-
-	responses := make([]types.Response, 0, len(requests))
-
-	for _, request := range requests {
-		req := handleRequest{Request: request, HttpRequest: httpRequest}
-		response := s.handler.Handle(req)
-		responses = append(responses, response)
-	}
-
-	respBytes, _ := json.Marshal(responses)
+	respBytes, _ := s.handleBatchItems(requests, httpRequest, nil)
 	_, err = w.Write(respBytes)
 	if err != nil {
 		log.Error(err)
@@ -362,30 +533,43 @@ func (s *Server) handleBatchRequest(httpRequest *http.Request, w http.ResponseWr
 	return len(respBytes)
 }
 
-func (s *Server) parseRequest(data []byte) (types.Request, error) {
-	var req types.Request
+// handleBatchItems dispatches each request of a batch and returns the encoded
+// responses in the same order of the requests. As required by the JSON RPC spec,
+// an empty batch is answered with a single invalid request error
+func (s *Server) handleBatchItems(requests []batchItem, httpRequest *http.Request, wsConn *concurrentWsConn) ([]byte, error) {
+	if len(requests) == 0 {
+		return json.Marshal(types.NewResponse(types.Request{JSONRPC: "2.0"}, nil, types.NewRPCError(types.InvalidRequestErrorCode, "empty batch request")))
+	}
 
-	if err := json.Unmarshal(data, &req); err != nil {
-		return types.Request{}, fmt.Errorf("invalid json object request body")
+	responses := make([]types.Response, 0, len(requests))
+	for _, request := range requests {
+		if request.err != nil {
+			responses = append(responses, types.NewResponse(request.Request, nil, request.err))
+			continue
+		}
+		req := handleRequest{Request: request.Request, HttpRequest: httpRequest, wsConn: wsConn}
+		responses = append(responses, s.handler.Handle(req))
 	}
 
-	return req, nil
+	return json.Marshal(responses)
 }
 
-func (s *Server) parseRequests(data []byte) ([]types.Request, error) {
-	var requests []types.Request
+// handleWsBatchRequest handles a batch of requests received through a WS connection
+func (s *Server) handleWsBatchRequest(decoder *requestDecoder, wsConn *concurrentWsConn, httpRequest *http.Request) ([]byte, error) {
+	if !s.config.BatchRequestsEnabled {
+		return types.NewResponse(types.Request{JSONRPC: "2.0"}, nil, types.NewRPCError(types.InvalidRequestErrorCode, types.ErrBatchRequestsDisabled.Error())).Bytes()
+	}
 
-	if err := json.Unmarshal(data, &requests); err != nil {
-		return nil, fmt.Errorf("invalid json array request body")
+	defer metrics.RequestHandled(metrics.RequestHandledLabelBatch)
+	requests, err := decoder.decodeRequests(s.config.BatchRequestsLimit)
+	if err != nil {
+		return types.NewResponse(types.Request{JSONRPC: "2.0"}, nil, types.NewRPCError(types.InvalidRequestErrorCode, err.Error())).Bytes()
 	}
 
-	return requests, nil
+	return s.handleBatchItems(requests, httpRequest, wsConn)
 }
 
 func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
-	// CORS rule - Allow requests from anywhere
-	s.wsUpgrader.CheckOrigin = func(r *http.Request) bool { return true }
-
 	// Upgrade the connection to a WS one
 	innerWsConn, err := s.wsUpgrader.Upgrade(w, req, nil)
 	if err != nil {
@@ -393,15 +577,14 @@ func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	wsConn := new(atomic.Pointer[websocket.Conn])
-	wsConn.Store(innerWsConn)
+	wsConn := newConcurrentWsConn(innerWsConn)
 
 	// Set read limit
-	wsConn.Load().SetReadLimit(s.config.WebSockets.ReadLimit)
+	wsConn.SetReadLimit(s.config.WebSockets.ReadLimit)
 
 	// Defer WS closure
-	defer func(wsConn *atomic.Pointer[websocket.Conn]) {
-		err = wsConn.Load().Close()
+	defer func(wsConn *concurrentWsConn) {
+		err = wsConn.Close()
 		if err != nil {
 			log.Error(fmt.Sprintf("Unable to gracefully close WS connection, %s", err.Error()))
 		}
@@ -418,7 +601,7 @@ func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
 	}()
 	log.Info("Websocket connection established")
 	for {
-		msgType, message, err := wsConn.Load().ReadMessage()
+		msgType, message, err := wsConn.ReadMessage()
 		if err != nil {
 			if websocket.IsCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure, websocket.CloseAbnormalClosure) {
 				log.Info("Closing WS connection gracefully")
@@ -435,12 +618,18 @@ func (s *Server) handleWs(w http.ResponseWriter, req *http.Request) {
 		}
 
 		if msgType == websocket.TextMessage || msgType == websocket.BinaryMessage {
-			resp, err := s.handler.HandleWs(message, wsConn, req)
+			var resp []byte
+			decoder := newMessageDecoder(message)
+			if single, decodeErr := decoder.isSingleRequest(); decodeErr == nil && !single {
+				resp, err = s.handleWsBatchRequest(decoder, wsConn, req)
+			} else {
+				resp, err = s.handler.HandleWs(message, wsConn, req)
+			}
 			if err != nil {
 				log.Error(fmt.Sprintf("Unable to handle WS request, %s", err.Error()))
-				_ = wsConn.Load().WriteMessage(msgType, []byte(fmt.Sprintf("WS Handle error: %s", err.Error())))
+				_ = wsConn.WriteMessage(msgType, []byte(fmt.Sprintf("WS Handle error: %s", err.Error())))
 			} else {
-				_ = wsConn.Load().WriteMessage(msgType, resp)
+				_ = wsConn.WriteMessage(msgType, resp)
 			}
 		}
 	}
@@ -505,8 +694,13 @@ func RPCErrorResponse(code int, message string, err error, logError bool) (inter
 	return RPCErrorResponseWithData(code, message, nil, err, logError)
 }
 
-// RPCErrorResponseWithData formats error to be returned through RPC
+// RPCErrorResponseWithData formats error to be returned through RPC. The requests failing
+// because the executor is unavailable are reported with a specific error, so the clients can
+// tell them apart from the errors of the request itself
 func RPCErrorResponseWithData(code int, message string, data *[]byte, err error, logError bool) (interface{}, types.Error) {
+	if errors.Is(err, executor.ErrExecutorUnavailable) {
+		code, message, data = types.ExecutorUnavailableErrorCode, executor.ErrExecutorUnavailable.Error(), nil
+	}
 	if logError {
 		if err != nil {
 			log.Errorf("%v: %v", message, err.Error())
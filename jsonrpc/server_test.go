@@ -2,7 +2,9 @@ package jsonrpc
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
@@ -82,14 +84,14 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 	if _, ok := apis[APIZKEVM]; ok {
 		services = append(services, Service{
 			Name:    APIZKEVM,
-			Service: NewZKEVMEndpoints(cfg, st, etherman),
+			Service: NewZKEVMEndpoints(cfg, pool, st, etherman, storage),
 		})
 	}
 
 	if _, ok := apis[APITxPool]; ok {
 		services = append(services, Service{
 			Name:    APITxPool,
-			Service: &TxPoolEndpoints{},
+			Service: NewTxPoolEndpoints(pool, nil),
 		})
 	}
 
@@ -106,7 +108,7 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 			Service: &Web3Endpoints{},
 		})
 	}
-	server := NewServer(cfg, chainID, pool, st, storage, services)
+	server := NewServer(cfg, chainID, pool, st, etherman, storage, services)
 
 	go func() {
 		err := server.Start()
@@ -159,6 +161,9 @@ func getSequencerDefaultConfig() Config {
 		MaxLogsCount:                 10000,
 		MaxLogsBlockRange:            10000,
 		MaxNativeBlockHashBlockRange: 60000,
+		MaxMulticallCalls:            3,
+		MulticallConcurrency:         2,
+		ArchiveMode:                  true,
 		WebSockets: WebSocketsConfig{
 			Enabled:   true,
 			Host:      "0.0.0.0",
@@ -464,6 +469,120 @@ func TestRequestValidation(t *testing.T) {
 	}
 }
 
+func TestReadOnlyMode(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.ReadOnly = true
+	s, _, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	for _, method := range []string{"eth_sendRawTransaction", "eth_newBlockFilter", "eth_uninstallFilter", "admin_resumeSequencer"} {
+		res, err := s.JSONRPCCall(method)
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.MethodNotSupportedErrorCode, res.Error.Code)
+		assert.Equal(t, fmt.Sprintf("method %s is not available, the node is in read-only mode", method), res.Error.Message)
+	}
+
+	res, err := s.JSONRPCCall("eth_chainId")
+	require.NoError(t, err)
+	assert.Nil(t, res.Error)
+}
+
+func TestDisabledMethods(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.DisabledMethods = []string{"eth_chainId"}
+	s, _, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	res, err := s.JSONRPCCall("eth_chainId")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.NotFoundErrorCode, res.Error.Code)
+	assert.Equal(t, "the method eth_chainId does not exist/is not available", res.Error.Message)
+
+	res, err = s.JSONRPCCall("web3_clientVersion")
+	require.NoError(t, err)
+	assert.Nil(t, res.Error)
+}
+
+func TestCORS(t *testing.T) {
+	const allowedOrigin = "https://allowed.example.com"
+	cfg := getSequencerDefaultConfig()
+	cfg.CORS.AllowedOrigins = []string{allowedOrigin}
+	s, _, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	testCases := []struct {
+		Name                 string
+		Method               string
+		Origin               string
+		ExpectedStatusCode   int
+		ExpectedAllowOrigin  string
+		ExpectedAllowHeaders string
+	}{
+		{Name: "preflight from allowed origin", Method: http.MethodOptions, Origin: allowedOrigin, ExpectedStatusCode: http.StatusOK, ExpectedAllowOrigin: allowedOrigin, ExpectedAllowHeaders: "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization"},
+		{Name: "preflight from not allowed origin", Method: http.MethodOptions, Origin: "https://other.example.com", ExpectedStatusCode: http.StatusOK},
+		{Name: "request from allowed origin", Method: http.MethodPost, Origin: allowedOrigin, ExpectedStatusCode: http.StatusOK, ExpectedAllowOrigin: allowedOrigin, ExpectedAllowHeaders: "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization"},
+		{Name: "request from not allowed origin", Method: http.MethodPost, Origin: "https://other.example.com", ExpectedStatusCode: http.StatusForbidden},
+		{Name: "request without origin", Method: http.MethodPost, ExpectedStatusCode: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			httpReq, err := http.NewRequest(tc.Method, s.ServerURL, bytes.NewReader([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`)))
+			require.NoError(t, err)
+			httpReq.Header.Add("Content-type", "application/json")
+			if tc.Origin != "" {
+				httpReq.Header.Add("Origin", tc.Origin)
+			}
+
+			httpRes, err := http.DefaultClient.Do(httpReq)
+			require.NoError(t, err)
+			defer httpRes.Body.Close()
+
+			assert.Equal(t, tc.ExpectedStatusCode, httpRes.StatusCode)
+			assert.Equal(t, tc.ExpectedAllowOrigin, httpRes.Header.Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tc.ExpectedAllowHeaders, httpRes.Header.Get("Access-Control-Allow-Headers"))
+		})
+	}
+}
+
+func TestGzipCompression(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.EnableCompression = true
+	s, _, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	// the compression is disabled in the transport to receive the response as it is sent
+	httpClient := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	for _, acceptGzip := range []bool{true, false} {
+		httpReq, err := http.NewRequest(http.MethodPost, s.ServerURL, bytes.NewReader([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`)))
+		require.NoError(t, err)
+		httpReq.Header.Add("Content-type", "application/json")
+		if acceptGzip {
+			httpReq.Header.Add("Accept-Encoding", "gzip")
+		}
+
+		httpRes, err := httpClient.Do(httpReq)
+		require.NoError(t, err)
+
+		var body io.Reader = httpRes.Body
+		if acceptGzip {
+			require.Equal(t, "gzip", httpRes.Header.Get("Content-Encoding"))
+			body, err = gzip.NewReader(httpRes.Body)
+			require.NoError(t, err)
+		} else {
+			require.Empty(t, httpRes.Header.Get("Content-Encoding"))
+		}
+
+		var res types.Response
+		require.NoError(t, json.NewDecoder(body).Decode(&res))
+		require.NoError(t, httpRes.Body.Close())
+		assert.Nil(t, res.Error)
+		assert.Equal(t, `"0x3e8"`, string(res.Result))
+	}
+}
+
 func TestMaxRequestPerIPPerSec(t *testing.T) {
 	// this is the number of requests the test will execute
 	// it's important to keep this number with an amount of
@@ -4,12 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
 )
 
 // ErrNotFound represent a not found error.
@@ -18,6 +17,9 @@ var ErrNotFound = errors.New("object not found")
 // ErrFilterInvalidPayload indicates there is an invalid payload when creating a filter
 var ErrFilterInvalidPayload = errors.New("invalid argument 0: cannot specify both BlockHash and FromBlock/ToBlock, choose one or the other")
 
+// ErrFilterTooManyTopics indicates the filter has more topic positions than the ones a log can have
+var ErrFilterTooManyTopics = fmt.Errorf("invalid argument 0: too many topics, max is %d", maxLogFilterTopics)
+
 // Storage uses memory to store the data
 // related to the json rpc server
 type Storage struct {
@@ -32,7 +34,7 @@ func NewStorage() *Storage {
 }
 
 // NewLogFilter persists a new log filter
-func (s *Storage) NewLogFilter(wsConn *atomic.Pointer[websocket.Conn], filter LogFilter) (string, error) {
+func (s *Storage) NewLogFilter(wsConn *concurrentWsConn, filter LogFilter) (string, error) {
 	if err := filter.Validate(); err != nil {
 		return "", err
 	}
@@ -41,19 +43,39 @@ func (s *Storage) NewLogFilter(wsConn *atomic.Pointer[websocket.Conn], filter Lo
 }
 
 // NewBlockFilter persists a new block log filter
-func (s *Storage) NewBlockFilter(wsConn *atomic.Pointer[websocket.Conn]) (string, error) {
+func (s *Storage) NewBlockFilter(wsConn *concurrentWsConn) (string, error) {
 	return s.createFilter(FilterTypeBlock, nil, wsConn)
 }
 
 // NewPendingTransactionFilter persists a new pending transaction filter
-func (s *Storage) NewPendingTransactionFilter(wsConn *atomic.Pointer[websocket.Conn]) (string, error) {
+func (s *Storage) NewPendingTransactionFilter(wsConn *concurrentWsConn) (string, error) {
 	return s.createFilter(FilterTypePendingTx, nil, wsConn)
 }
 
+// NewDroppedTransactionFilter persists a new dropped transaction filter
+func (s *Storage) NewDroppedTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	return s.createFilter(FilterTypeDroppedTx, nil, wsConn)
+}
+
+// NewAccountChangesFilter persists a new account changes filter
+func (s *Storage) NewAccountChangesFilter(wsConn *concurrentWsConn, addresses []common.Address) (string, error) {
+	return s.createFilter(FilterTypeAccountChanges, addresses, wsConn)
+}
+
+// NewBatchStatusFilter persists a new batch status filter
+func (s *Storage) NewBatchStatusFilter(wsConn *concurrentWsConn) (string, error) {
+	return s.createFilter(FilterTypeBatchStatus, nil, wsConn)
+}
+
+// NewSelectedTransactionFilter persists a new selected transaction filter
+func (s *Storage) NewSelectedTransactionFilter(wsConn *concurrentWsConn) (string, error) {
+	return s.createFilter(FilterTypeSelectedTx, nil, wsConn)
+}
+
 // create persists the filter to the memory and provides the filter id
-func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *atomic.Pointer[websocket.Conn]) (string, error) {
+func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *concurrentWsConn) (string, error) {
 	lastPoll := time.Now().UTC()
-	id, err := s.generateFilterID()
+	id, err := generateFilterID()
 	if err != nil {
 		return "", fmt.Errorf("failed to generate filter ID: %w", err)
 	}
@@ -68,7 +90,8 @@ func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *ato
 	return id, nil
 }
 
-func (s *Storage) generateFilterID() (string, error) {
+// generateFilterID generates a random id for a new filter
+func generateFilterID() (string, error) {
 	r, err := uuid.NewRandom()
 	if err != nil {
 		return "", err
@@ -119,6 +142,96 @@ func (s *Storage) GetAllLogFiltersWithWSConn() ([]*Filter, error) {
 	return filtersWithWSConn, nil
 }
 
+// GetAllDroppedTxFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by dropped txs
+func (s *Storage) GetAllDroppedTxFiltersWithWSConn() ([]*Filter, error) {
+	filtersWithWSConn := []*Filter{}
+	s.filters.Range(func(key, value any) bool {
+		filter := value.(*Filter)
+		if filter.WsConn == nil || filter.Type != FilterTypeDroppedTx {
+			return true
+		}
+
+		f := filter
+		filtersWithWSConn = append(filtersWithWSConn, f)
+		return true
+	})
+
+	return filtersWithWSConn, nil
+}
+
+// GetAllAccountChangesFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by account changes
+func (s *Storage) GetAllAccountChangesFiltersWithWSConn() ([]*Filter, error) {
+	filtersWithWSConn := []*Filter{}
+	s.filters.Range(func(key, value any) bool {
+		filter := value.(*Filter)
+		if filter.WsConn == nil || filter.Type != FilterTypeAccountChanges {
+			return true
+		}
+
+		f := filter
+		filtersWithWSConn = append(filtersWithWSConn, f)
+		return true
+	})
+
+	return filtersWithWSConn, nil
+}
+
+// GetAllBatchStatusFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by batch status transitions
+func (s *Storage) GetAllBatchStatusFiltersWithWSConn() ([]*Filter, error) {
+	filtersWithWSConn := []*Filter{}
+	s.filters.Range(func(key, value any) bool {
+		filter := value.(*Filter)
+		if filter.WsConn == nil || filter.Type != FilterTypeBatchStatus {
+			return true
+		}
+
+		f := filter
+		filtersWithWSConn = append(filtersWithWSConn, f)
+		return true
+	})
+
+	return filtersWithWSConn, nil
+}
+
+// GetAllSelectedTxFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by the txs selected by the sequencer
+func (s *Storage) GetAllSelectedTxFiltersWithWSConn() ([]*Filter, error) {
+	filtersWithWSConn := []*Filter{}
+	s.filters.Range(func(key, value any) bool {
+		filter := value.(*Filter)
+		if filter.WsConn == nil || filter.Type != FilterTypeSelectedTx {
+			return true
+		}
+
+		f := filter
+		filtersWithWSConn = append(filtersWithWSConn, f)
+		return true
+	})
+
+	return filtersWithWSConn, nil
+}
+
+// GetAllPendingTxFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by new pending txs
+func (s *Storage) GetAllPendingTxFiltersWithWSConn() ([]*Filter, error) {
+	filtersWithWSConn := []*Filter{}
+	s.filters.Range(func(key, value any) bool {
+		filter := value.(*Filter)
+		if filter.WsConn == nil || filter.Type != FilterTypePendingTx {
+			return true
+		}
+
+		f := filter
+		filtersWithWSConn = append(filtersWithWSConn, f)
+		return true
+	})
+
+	return filtersWithWSConn, nil
+}
+
 // GetFilter gets a filter by its id
 func (s *Storage) GetFilter(filterID string) (*Filter, error) {
 	filter, found := s.filters.Load(filterID)
@@ -152,7 +265,7 @@ func (s *Storage) UninstallFilter(filterID string) error {
 }
 
 // UninstallFilterByWSConn deletes all filters connected to the provided web socket connection
-func (s *Storage) UninstallFilterByWSConn(wsConn *atomic.Pointer[websocket.Conn]) error {
+func (s *Storage) UninstallFilterByWSConn(wsConn *concurrentWsConn) error {
 	filterIDsToDelete := []string{}
 	s.filters.Range(func(key, value any) bool {
 		id := key.(string)
@@ -169,3 +282,23 @@ func (s *Storage) UninstallFilterByWSConn(wsConn *atomic.Pointer[websocket.Conn]
 
 	return nil
 }
+
+// UninstallExpiredFilters deletes the filters not bound to a web socket connection
+// that haven't been polled since the provided time, returning how many were deleted
+func (s *Storage) UninstallExpiredFilters(lastPollBefore time.Time) (int, error) {
+	filterIDsToDelete := []string{}
+	s.filters.Range(func(key, value any) bool {
+		id := key.(string)
+		filter := value.(*Filter)
+		if filter.WsConn == nil && filter.LastPoll.Before(lastPollBefore) {
+			filterIDsToDelete = append(filterIDsToDelete, id)
+		}
+		return true
+	})
+
+	for _, filterID := range filterIDsToDelete {
+		s.filters.Delete(filterID)
+	}
+
+	return len(filterIDsToDelete), nil
+}
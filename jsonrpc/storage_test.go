@@ -0,0 +1,40 @@
+package jsonrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageUninstallExpiredFilters(t *testing.T) {
+	s := NewStorage()
+
+	expiredID, err := s.NewBlockFilter(nil)
+	require.NoError(t, err)
+	activeID, err := s.NewLogFilter(nil, LogFilter{})
+	require.NoError(t, err)
+	wsID, err := s.NewBlockFilter(&concurrentWsConn{})
+	require.NoError(t, err)
+
+	// only the filters polled before the limit expire, the ones bound to a web socket connection never do
+	limit := time.Now().UTC()
+	for _, id := range []string{expiredID, wsID} {
+		filter, err := s.GetFilter(id)
+		require.NoError(t, err)
+		filter.LastPoll = limit.Add(-time.Minute)
+	}
+	require.NoError(t, s.UpdateFilterLastPoll(activeID))
+
+	count, err := s.UninstallExpiredFilters(limit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = s.GetFilter(expiredID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.GetFilter(activeID)
+	assert.NoError(t, err)
+	_, err = s.GetFilter(wsID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,206 @@
+package jsonrpc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/google/uuid"
+)
+
+var (
+	// errTraceQueueFull is returned when the max number of traces are already waiting for a slot
+	errTraceQueueFull = errors.New("too many traces queued, try again later")
+	// errTraceQueueTimeout is returned when a trace waited for a slot longer than the queue timeout
+	errTraceQueueTimeout = errors.New("timeout waiting for a trace slot, try again later")
+	// errTraceJobsFull is returned when the max number of trace jobs are already stored and none of them is finished
+	errTraceJobsFull = errors.New("too many trace jobs pending, try again later")
+)
+
+// traceJobStatus is the status of an async trace job
+type traceJobStatus string
+
+const (
+	traceJobQueued  traceJobStatus = "queued"
+	traceJobRunning traceJobStatus = "running"
+	traceJobDone    traceJobStatus = "done"
+	traceJobFailed  traceJobStatus = "failed"
+)
+
+// traceJob is a trace submitted to be run asynchronously
+type traceJob struct {
+	ID          string         `json:"id"`
+	Method      string         `json:"method"`
+	Status      traceJobStatus `json:"status"`
+	Result      interface{}    `json:"result,omitempty"`
+	Error       *string        `json:"error,omitempty"`
+	SubmittedAt int64          `json:"submittedAt"`
+	FinishedAt  *int64         `json:"finishedAt,omitempty"`
+
+	finishedAt time.Time
+}
+
+// traceFn runs a trace
+type traceFn func() (interface{}, types.Error)
+
+// traceQueue limits the traces run at the same time, the traces over the limit wait
+// for a slot up to the queue timeout. The async traces are stored as jobs whose
+// results are kept during the results retention to be polled by their id
+type traceQueue struct {
+	cfg TraceQueueConfig
+
+	slots chan struct{}
+
+	waiting uint64
+	jobs    map[string]*traceJob
+	// jobIDs are the ids of the stored jobs in the order they were submitted
+	jobIDs []string
+	mutex  sync.Mutex
+}
+
+func newTraceQueue(cfg TraceQueueConfig) (*traceQueue, error) {
+	if cfg.MaxConcurrentTraces == 0 {
+		return nil, errors.New("MaxConcurrentTraces must be greater than 0")
+	}
+	return &traceQueue{
+		cfg:   cfg,
+		slots: make(chan struct{}, cfg.MaxConcurrentTraces),
+		jobs:  make(map[string]*traceJob),
+	}, nil
+}
+
+// run runs the trace once a slot is available
+func (q *traceQueue) run(fn traceFn) (interface{}, types.Error) {
+	if err := q.acquire(); err != nil {
+		return nil, types.NewRPCError(types.LimitExceededErrorCode, err.Error())
+	}
+	defer q.release()
+	return fn()
+}
+
+// submit stores a new job for the trace and runs it in background once a slot is
+// available, returning the id of the job
+func (q *traceQueue) submit(method string, fn traceFn) (string, error) {
+	job := &traceJob{
+		ID:          uuid.New().String(),
+		Method:      method,
+		Status:      traceJobQueued,
+		SubmittedAt: time.Now().Unix(),
+	}
+
+	q.mutex.Lock()
+	q.purgeJobs(time.Now())
+	if q.cfg.MaxStoredJobs > 0 && uint64(len(q.jobs)) >= q.cfg.MaxStoredJobs {
+		q.mutex.Unlock()
+		return "", errTraceJobsFull
+	}
+	q.jobs[job.ID] = job
+	q.jobIDs = append(q.jobIDs, job.ID)
+	q.mutex.Unlock()
+
+	go func() {
+		if err := q.acquire(); err != nil {
+			q.finish(job, nil, err.Error())
+			return
+		}
+		defer q.release()
+
+		q.mutex.Lock()
+		job.Status = traceJobRunning
+		q.mutex.Unlock()
+
+		result, rpcErr := fn()
+		if rpcErr != nil {
+			q.finish(job, nil, rpcErr.Error())
+			return
+		}
+		q.finish(job, result, "")
+	}()
+
+	return job.ID, nil
+}
+
+// job returns a copy of the stored job with the id
+func (q *traceQueue) job(id string) (traceJob, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.purgeJobs(time.Now())
+	job, found := q.jobs[id]
+	if !found {
+		return traceJob{}, false
+	}
+	return *job, true
+}
+
+func (q *traceQueue) finish(job *traceJob, result interface{}, errMsg string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	job.finishedAt = time.Now()
+	finishedAt := job.finishedAt.Unix()
+	job.FinishedAt = &finishedAt
+	if errMsg != "" {
+		job.Status = traceJobFailed
+		job.Error = &errMsg
+		return
+	}
+	job.Status = traceJobDone
+	job.Result = result
+}
+
+// purgeJobs deletes the finished jobs older than the results retention, it must be
+// called holding the mutex
+func (q *traceQueue) purgeJobs(now time.Time) {
+	remaining := q.jobIDs[:0]
+	for _, id := range q.jobIDs {
+		job := q.jobs[id]
+		if !job.finishedAt.IsZero() && job.finishedAt.Add(q.cfg.ResultsRetention.Duration).Before(now) {
+			delete(q.jobs, id)
+			continue
+		}
+		remaining = append(remaining, id)
+	}
+	q.jobIDs = remaining
+}
+
+// acquire waits for a slot to run a trace up to the queue timeout, the slot must be
+// released with release once the trace is run
+func (q *traceQueue) acquire() error {
+	select {
+	case q.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	q.mutex.Lock()
+	if q.cfg.MaxQueuedTraces > 0 && q.waiting >= q.cfg.MaxQueuedTraces {
+		q.mutex.Unlock()
+		return errTraceQueueFull
+	}
+	q.waiting++
+	q.mutex.Unlock()
+
+	defer func() {
+		q.mutex.Lock()
+		q.waiting--
+		q.mutex.Unlock()
+	}()
+
+	timer := time.NewTimer(q.cfg.QueueTimeout.Duration)
+	defer timer.Stop()
+	select {
+	case q.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		log.Debugf("trace discarded after waiting %v for a slot", q.cfg.QueueTimeout.Duration)
+		return errTraceQueueTimeout
+	}
+}
+
+// release frees the slot reserved by acquire
+func (q *traceQueue) release() {
+	<-q.slots
+}
@@ -0,0 +1,114 @@
+package jsonrpc
+
+import (
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceQueueRun(t *testing.T) {
+	queue, err := newTraceQueue(TraceQueueConfig{
+		Enabled:             true,
+		MaxConcurrentTraces: 1,
+		MaxQueuedTraces:     1,
+		QueueTimeout:        cfgTypes.NewDuration(100 * time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	// the running trace holds the only slot until it's released
+	require.NoError(t, queue.acquire())
+
+	// the next trace waits for the slot up to the queue timeout
+	queued := make(chan types.Error)
+	go func() {
+		_, rpcErr := queue.run(func() (interface{}, types.Error) { return nil, nil })
+		queued <- rpcErr
+	}()
+	require.Eventually(t, func() bool {
+		queue.mutex.Lock()
+		defer queue.mutex.Unlock()
+		return queue.waiting == 1
+	}, time.Second, time.Millisecond)
+
+	// the queue is full
+	_, rpcErr := queue.run(func() (interface{}, types.Error) { return nil, nil })
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, types.LimitExceededErrorCode, rpcErr.ErrorCode())
+	assert.Equal(t, errTraceQueueFull.Error(), rpcErr.Error())
+
+	// the queued trace times out
+	rpcErr = <-queued
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, errTraceQueueTimeout.Error(), rpcErr.Error())
+
+	// once the slot is released the traces are run
+	queue.release()
+	result, rpcErr := queue.run(func() (interface{}, types.Error) { return "trace", nil })
+	require.Nil(t, rpcErr)
+	assert.Equal(t, "trace", result)
+
+	_, err = newTraceQueue(TraceQueueConfig{Enabled: true})
+	assert.Error(t, err)
+}
+
+func TestTraceQueueSubmit(t *testing.T) {
+	queue, err := newTraceQueue(TraceQueueConfig{
+		Enabled:             true,
+		MaxConcurrentTraces: 1,
+		QueueTimeout:        cfgTypes.NewDuration(time.Second),
+		AsyncEnabled:        true,
+		ResultsRetention:    cfgTypes.NewDuration(time.Minute),
+		MaxStoredJobs:       2,
+	})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	doneID, err := queue.submit("debug_traceTransaction", func() (interface{}, types.Error) {
+		<-release
+		return "trace", nil
+	})
+	require.NoError(t, err)
+	failedID, err := queue.submit("debug_traceBlockByNumber", func() (interface{}, types.Error) {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "block not found")
+	})
+	require.NoError(t, err)
+
+	// the jobs are stored until the limit is reached
+	_, err = queue.submit("debug_traceTransaction", func() (interface{}, types.Error) { return nil, nil })
+	assert.Equal(t, errTraceJobsFull, err)
+
+	job, found := queue.job(doneID)
+	require.True(t, found)
+	assert.Equal(t, "debug_traceTransaction", job.Method)
+	assert.Contains(t, []traceJobStatus{traceJobQueued, traceJobRunning}, job.Status)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		job, _ := queue.job(doneID)
+		return job.Status == traceJobDone
+	}, time.Second, time.Millisecond)
+	job, _ = queue.job(doneID)
+	assert.Equal(t, "trace", job.Result)
+	assert.NotNil(t, job.FinishedAt)
+
+	require.Eventually(t, func() bool {
+		job, _ := queue.job(failedID)
+		return job.Status == traceJobFailed
+	}, time.Second, time.Millisecond)
+	job, _ = queue.job(failedID)
+	require.NotNil(t, job.Error)
+	assert.Equal(t, "block not found", *job.Error)
+
+	// the finished jobs are deleted after the results retention
+	queue.mutex.Lock()
+	queue.purgeJobs(time.Now().Add(2 * time.Minute))
+	queue.mutex.Unlock()
+	_, found = queue.job(doneID)
+	assert.False(t, found)
+	_, found = queue.job("unknown")
+	assert.False(t, found)
+}
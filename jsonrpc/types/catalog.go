@@ -0,0 +1,153 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+)
+
+// Error codes of the txs rejected by the pool, each rejection condition has its own code
+// so the clients don't need to parse the error messages. The codes are stable, a code is
+// never reused for a different condition
+const (
+	// InvalidChainIDErrorCode error code for txs with a chain id different from the network one
+	InvalidChainIDErrorCode = -32010
+	// TxTypeNotSupportedErrorCode error code for txs with a type not supported by the network
+	TxTypeNotSupportedErrorCode = -32011
+	// OversizedTxErrorCode error code for txs bigger than the max tx size
+	OversizedTxErrorCode = -32012
+	// TxDataTooLargeErrorCode error code for txs with data bigger than the max allowed by the executor
+	TxDataTooLargeErrorCode = -32013
+	// ChainIDTooHighErrorCode error code for txs with a chain id bigger than the max allowed by the executor
+	ChainIDTooHighErrorCode = -32014
+	// NegativeValueErrorCode error code for txs with a negative value
+	NegativeValueErrorCode = -32015
+	// InvalidSenderErrorCode error code for txs with an invalid signature
+	InvalidSenderErrorCode = -32016
+	// BlockedSenderErrorCode error code for txs sent by a blocked account
+	BlockedSenderErrorCode = -32017
+	// GasLimitExceededErrorCode error code for txs with a gas limit over the batch gas limit
+	GasLimitExceededErrorCode = -32018
+	// AccountTxLimitReachedErrorCode error code for txs of an account with the max txs in the pool
+	AccountTxLimitReachedErrorCode = -32019
+	// TxPoolFullErrorCode error code for txs rejected because the pool is full
+	TxPoolFullErrorCode = -32020
+	// NonceTooLowErrorCode error code for txs with a nonce lower than the account nonce
+	NonceTooLowErrorCode = -32021
+	// NonceTooHighErrorCode error code for txs with a nonce higher than the allowed by the account queue
+	NonceTooHighErrorCode = -32022
+	// NonceGapLimitReachedErrorCode error code for txs of an account with the max txs with a nonce gap in the pool
+	NonceGapLimitReachedErrorCode = -32023
+	// -32024 was the code of the nonce gap too large error, it's not reused
+	// InsufficientFundsErrorCode error code for txs whose cost is higher than the sender balance
+	InsufficientFundsErrorCode = -32025
+	// IntrinsicGasTooLowErrorCode error code for txs with a gas limit lower than their intrinsic gas
+	IntrinsicGasTooLowErrorCode = -32026
+	// GasUintOverflowErrorCode error code for txs whose gas overflows an uint64
+	GasUintOverflowErrorCode = -32027
+	// GasPriceTooLowErrorCode error code for txs with a gas price lower than the min allowed
+	GasPriceTooLowErrorCode = -32028
+	// GasPriceTooHighErrorCode error code for txs with a gas price higher than the max allowed
+	GasPriceTooHighErrorCode = -32029
+	// EffectiveGasPriceTooLowErrorCode error code for txs with a gas price lower than their effective gas price
+	EffectiveGasPriceTooLowErrorCode = -32030
+	// InvalidIPErrorCode error code for txs sent from an invalid IP address
+	InvalidIPErrorCode = -32031
+	// OutOfCountersErrorCode error code for txs exceeding the zk counters of a batch
+	OutOfCountersErrorCode = -32032
+	// AlreadyKnownErrorCode error code for txs already in the pool
+	AlreadyKnownErrorCode = -32033
+	// ReplacementUnderpricedErrorCode error code for txs replacing a pool tx without the required price bump
+	ReplacementUnderpricedErrorCode = -32034
+	// TxConditionsNotMetErrorCode error code for conditional txs whose conditions are not met
+	TxConditionsNotMetErrorCode = -32035
+	// InvalidTxConditionsErrorCode error code for conditional txs whose conditions are not well formed
+	InvalidTxConditionsErrorCode = -32036
+	// TooManyTxConditionsErrorCode error code for conditional txs checking too many storage slots
+	TooManyTxConditionsErrorCode = -32037
+	// StorageRootConditionNotSupportedErrorCode error code for conditional txs expecting the storage root of an account
+	StorageRootConditionNotSupportedErrorCode = -32038
+	// SponsoredTxGasLimitErrorCode error code for sponsored txs with a gas limit over the max allowed
+	SponsoredTxGasLimitErrorCode = -32039
+	// SponsoredTxRateLimitErrorCode error code for sponsored txs over the rate limit
+	SponsoredTxRateLimitErrorCode = -32040
+)
+
+// CatalogEntry describes an error condition returned by the RPC with its stable code
+type CatalogEntry struct {
+	// Code is the code of the JSON-RPC error
+	Code int `json:"code"`
+	// Name is the stable identifier of the condition, in upper snake case
+	Name string `json:"name"`
+	// Description explains when the error is returned
+	Description string `json:"description"`
+	// Data is the schema of the error data, empty if the error has no data. The error data
+	// is always encoded as a hex string of the described bytes
+	Data string `json:"data,omitempty"`
+
+	// err is the pool error of the condition, nil for the conditions not raised by the pool
+	err error
+}
+
+// ErrorCatalog contains every error condition returned by the RPC, the RPC errors first and then the
+// txs rejected by the pool. The SDKs can branch on the codes instead of parsing the error messages,
+// which are kept compatible with the ones of geth and can change between versions
+var ErrorCatalog = []CatalogEntry{
+	{Code: DefaultErrorCode, Name: "SERVER_ERROR", Description: "generic error of the node, the message describes it"},
+	{Code: RevertedErrorCode, Name: "EXECUTION_REVERTED", Description: "the call or the gas estimation reverted", Data: "ABI encoded revert reason"},
+	{Code: InvalidRequestErrorCode, Name: "INVALID_REQUEST", Description: "the request is not a valid JSON-RPC request"},
+	{Code: NotFoundErrorCode, Name: "METHOD_NOT_FOUND", Description: "the method doesn't exist or is not available"},
+	{Code: InvalidParamsErrorCode, Name: "INVALID_PARAMS", Description: "the params of the request are not valid"},
+	{Code: ParserErrorCode, Name: "PARSE_ERROR", Description: "the request is not valid JSON"},
+	{Code: MethodNotSupportedErrorCode, Name: "METHOD_NOT_SUPPORTED", Description: "the method is disabled in the node, like the methods mutating the state in read-only mode"},
+	{Code: LimitExceededErrorCode, Name: "LIMIT_EXCEEDED", Description: "the request was rejected by the rate limit, the concurrency limit or a queue of the node"},
+	{Code: TxExpiredErrorCode, Name: "TX_EXPIRED", Description: "the tx was evicted from the pool because it expired"},
+	{Code: ExecutorUnavailableErrorCode, Name: "EXECUTOR_UNAVAILABLE", Description: "the executor is unavailable, the request can be retried"},
+	{Code: UnauthorizedErrorCode, Name: "UNAUTHORIZED", Description: "the admin method requires a valid auth token"},
+	{Code: SyncingErrorCode, Name: "SYNCING", Description: "the node is warming up and not synced up to the trusted tip yet",
+		Data: `JSON object {"currentBatchNumber": hex, "trustedBatchNumber": hex}`},
+
+	{Code: InvalidChainIDErrorCode, Name: "INVALID_CHAIN_ID", Description: "the chain id of the tx is not the one of the network", err: pool.ErrInvalidChainID},
+	{Code: TxTypeNotSupportedErrorCode, Name: "TX_TYPE_NOT_SUPPORTED", Description: "the type of the tx is not supported by the network", err: pool.ErrTxTypeNotSupported},
+	{Code: OversizedTxErrorCode, Name: "OVERSIZED_TX", Description: "the tx is bigger than the max tx size", err: pool.ErrOversizedData},
+	{Code: TxDataTooLargeErrorCode, Name: "TX_DATA_TOO_LARGE", Description: "the data of the tx is bigger than the max allowed by the executor", err: pool.ErrTxDataTooLarge},
+	{Code: ChainIDTooHighErrorCode, Name: "CHAIN_ID_TOO_HIGH", Description: "the chain id of the tx doesn't fit in 64 bits", err: pool.ErrChainIDTooHigh},
+	{Code: NegativeValueErrorCode, Name: "NEGATIVE_VALUE", Description: "the value of the tx is negative", err: pool.ErrNegativeValue},
+	{Code: InvalidSenderErrorCode, Name: "INVALID_SENDER", Description: "the signature of the tx is not valid", err: pool.ErrInvalidSender},
+	{Code: BlockedSenderErrorCode, Name: "BLOCKED_SENDER", Description: "the sender of the tx is blocked", err: pool.ErrBlockedSender},
+	{Code: GasLimitExceededErrorCode, Name: "GAS_LIMIT_EXCEEDED", Description: "the gas limit of the tx is higher than the batch gas limit", err: pool.ErrGasLimit},
+	{Code: AccountTxLimitReachedErrorCode, Name: "ACCOUNT_TX_LIMIT_REACHED", Description: "the sender already has the max txs allowed in the pool", err: pool.ErrTxPoolAccountOverflow},
+	{Code: TxPoolFullErrorCode, Name: "TXPOOL_FULL", Description: "the pool is full", err: pool.ErrTxPoolOverflow},
+	{Code: NonceTooLowErrorCode, Name: "NONCE_TOO_LOW", Description: "the nonce of the tx is lower than the nonce of the sender", err: pool.ErrNonceTooLow},
+	{Code: NonceTooHighErrorCode, Name: "NONCE_TOO_HIGH", Description: "the nonce of the tx is higher than the allowed by the account queue", err: pool.ErrNonceTooHigh},
+	{Code: NonceGapLimitReachedErrorCode, Name: "NONCE_GAP_LIMIT_REACHED", Description: "the sender already has the max txs with a nonce gap allowed in the pool", err: pool.ErrNonceGapLimitReached},
+	{Code: InsufficientFundsErrorCode, Name: "INSUFFICIENT_FUNDS", Description: "the balance of the sender doesn't cover gas * price + value", err: pool.ErrInsufficientFunds},
+	{Code: IntrinsicGasTooLowErrorCode, Name: "INTRINSIC_GAS_TOO_LOW", Description: "the gas limit of the tx is lower than its intrinsic gas", err: pool.ErrIntrinsicGas},
+	{Code: GasUintOverflowErrorCode, Name: "GAS_UINT_OVERFLOW", Description: "the gas of the tx overflows an uint64", err: pool.ErrGasUintOverflow},
+	{Code: GasPriceTooLowErrorCode, Name: "GAS_PRICE_TOO_LOW", Description: "the gas price of the tx is lower than the min allowed",
+		Data: "big endian min gas price allowed", err: pool.ErrGasPrice},
+	{Code: GasPriceTooHighErrorCode, Name: "GAS_PRICE_TOO_HIGH", Description: "the gas price of the tx is higher than the max allowed",
+		Data: "big endian max gas price allowed", err: pool.ErrGasPriceTooHigh},
+	{Code: EffectiveGasPriceTooLowErrorCode, Name: "EFFECTIVE_GAS_PRICE_TOO_LOW", Description: "the gas price of the tx is lower than its effective gas price", err: pool.ErrEffectiveGasPriceGasPriceTooLow},
+	{Code: InvalidIPErrorCode, Name: "INVALID_IP", Description: "the IP address of the sender is not valid", err: pool.ErrInvalidIP},
+	{Code: OutOfCountersErrorCode, Name: "OUT_OF_COUNTERS", Description: "the tx exceeds the zk counters of a batch", err: pool.ErrOutOfCounters},
+	{Code: AlreadyKnownErrorCode, Name: "ALREADY_KNOWN", Description: "the tx is already in the pool", err: pool.ErrAlreadyKnown},
+	{Code: ReplacementUnderpricedErrorCode, Name: "REPLACEMENT_UNDERPRICED", Description: "the tx replaces a pool tx without the required price bump", err: pool.ErrReplaceUnderpriced},
+	{Code: TxConditionsNotMetErrorCode, Name: "TX_CONDITIONS_NOT_MET", Description: "the conditions of the conditional tx are not met", err: pool.ErrTxConditionsNotMet},
+	{Code: InvalidTxConditionsErrorCode, Name: "INVALID_TX_CONDITIONS", Description: "the conditions of the conditional tx are not well formed", err: pool.ErrInvalidTxConditions},
+	{Code: TooManyTxConditionsErrorCode, Name: "TOO_MANY_TX_CONDITIONS", Description: "the conditional tx checks too many storage slots", err: pool.ErrTooManyTxConditions},
+	{Code: StorageRootConditionNotSupportedErrorCode, Name: "STORAGE_ROOT_CONDITION_NOT_SUPPORTED", Description: "the conditional tx expects the storage root of an account", err: pool.ErrStorageRootConditionNotSupported},
+	{Code: SponsoredTxGasLimitErrorCode, Name: "SPONSORED_TX_GAS_LIMIT", Description: "the gas limit of the sponsored tx is higher than the max allowed", err: pool.ErrSponsoredTxGasLimit},
+	{Code: SponsoredTxRateLimitErrorCode, Name: "SPONSORED_TX_RATE_LIMIT", Description: "the sponsored txs rate limit was reached", err: pool.ErrSponsoredTxRateLimit},
+}
+
+// PoolErrorCode returns the code of the catalog for an error of the pool, the
+// errors that are not a rejection of the pool get the DefaultErrorCode
+func PoolErrorCode(err error) int {
+	for _, entry := range ErrorCatalog {
+		if entry.err != nil && errors.Is(err, entry.err) {
+			return entry.Code
+		}
+	}
+	return DefaultErrorCode
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCatalog(t *testing.T) {
+	namePattern := regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+	codes := map[int]string{}
+	names := map[string]int{}
+	for _, entry := range ErrorCatalog {
+		assert.Regexp(t, namePattern, entry.Name)
+		assert.NotEmpty(t, entry.Description, entry.Name)
+
+		name, found := codes[entry.Code]
+		assert.False(t, found, "code %d of %s already used by %s", entry.Code, entry.Name, name)
+		codes[entry.Code] = entry.Name
+
+		code, found := names[entry.Name]
+		assert.False(t, found, "name %s of code %d already used by code %d", entry.Name, entry.Code, code)
+		names[entry.Name] = entry.Code
+	}
+}
+
+func TestErrorCatalogCodesAreStable(t *testing.T) {
+	// the codes are used by the clients, they must never change
+	expected := map[string]int{
+		"SERVER_ERROR":                         -32000,
+		"EXECUTION_REVERTED":                   3,
+		"INVALID_REQUEST":                      -32600,
+		"METHOD_NOT_FOUND":                     -32601,
+		"INVALID_PARAMS":                       -32602,
+		"PARSE_ERROR":                          -32700,
+		"METHOD_NOT_SUPPORTED":                 -32004,
+		"LIMIT_EXCEEDED":                       -32005,
+		"TX_EXPIRED":                           -32006,
+		"EXECUTOR_UNAVAILABLE":                 -32007,
+		"UNAUTHORIZED":                         -32008,
+		"SYNCING":                              -32009,
+		"INVALID_CHAIN_ID":                     -32010,
+		"TX_TYPE_NOT_SUPPORTED":                -32011,
+		"OVERSIZED_TX":                         -32012,
+		"TX_DATA_TOO_LARGE":                    -32013,
+		"CHAIN_ID_TOO_HIGH":                    -32014,
+		"NEGATIVE_VALUE":                       -32015,
+		"INVALID_SENDER":                       -32016,
+		"BLOCKED_SENDER":                       -32017,
+		"GAS_LIMIT_EXCEEDED":                   -32018,
+		"ACCOUNT_TX_LIMIT_REACHED":             -32019,
+		"TXPOOL_FULL":                          -32020,
+		"NONCE_TOO_LOW":                        -32021,
+		"NONCE_TOO_HIGH":                       -32022,
+		"NONCE_GAP_LIMIT_REACHED":              -32023,
+		"INSUFFICIENT_FUNDS":                   -32025,
+		"INTRINSIC_GAS_TOO_LOW":                -32026,
+		"GAS_UINT_OVERFLOW":                    -32027,
+		"GAS_PRICE_TOO_LOW":                    -32028,
+		"GAS_PRICE_TOO_HIGH":                   -32029,
+		"EFFECTIVE_GAS_PRICE_TOO_LOW":          -32030,
+		"INVALID_IP":                           -32031,
+		"OUT_OF_COUNTERS":                      -32032,
+		"ALREADY_KNOWN":                        -32033,
+		"REPLACEMENT_UNDERPRICED":              -32034,
+		"TX_CONDITIONS_NOT_MET":                -32035,
+		"INVALID_TX_CONDITIONS":                -32036,
+		"TOO_MANY_TX_CONDITIONS":               -32037,
+		"STORAGE_ROOT_CONDITION_NOT_SUPPORTED": -32038,
+		"SPONSORED_TX_GAS_LIMIT":               -32039,
+		"SPONSORED_TX_RATE_LIMIT":              -32040,
+	}
+	assert.Len(t, ErrorCatalog, len(expected))
+	for _, entry := range ErrorCatalog {
+		assert.Equal(t, expected[entry.Name], entry.Code, entry.Name)
+	}
+}
+
+func TestPoolErrorCode(t *testing.T) {
+	testCases := []struct {
+		err          error
+		expectedCode int
+	}{
+		{err: pool.ErrNonceTooLow, expectedCode: NonceTooLowErrorCode},
+		{err: pool.ErrNonceTooHigh, expectedCode: NonceTooHighErrorCode},
+		{err: fmt.Errorf("%w, max %d pending txs per account", pool.ErrTxPoolAccountOverflow, 10), expectedCode: AccountTxLimitReachedErrorCode},
+		{err: pool.NewGasPriceTooLowError(big.NewInt(1)), expectedCode: GasPriceTooLowErrorCode},
+		{err: pool.NewGasPriceTooHighError(big.NewInt(1)), expectedCode: GasPriceTooHighErrorCode},
+		{err: fmt.Errorf("%w: account %s", pool.ErrStorageRootConditionNotSupported, "0x1"), expectedCode: StorageRootConditionNotSupportedErrorCode},
+		{err: fmt.Errorf("%w: %s", pool.ErrOutOfCounters, "steps"), expectedCode: OutOfCountersErrorCode},
+		{err: errors.New("failed to add tx"), expectedCode: DefaultErrorCode},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedCode, PoolErrorCode(testCase.err), testCase.err.Error())
+	}
+
+	// every pool rejection of the catalog is mapped to its own code
+	for _, entry := range ErrorCatalog {
+		if entry.err != nil {
+			assert.Equal(t, entry.Code, PoolErrorCode(entry.err), entry.Name)
+		}
+	}
+}
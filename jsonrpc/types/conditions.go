@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// KnownAccount is the expected storage of an account provided in the conditions
+// of eth_sendRawTransactionConditional, it's either the storage root hash or an
+// object with the expected values of a set of storage slots
+type KnownAccount struct {
+	StorageRoot  *common.Hash
+	StorageSlots map[common.Hash]common.Hash
+}
+
+// UnmarshalJSON unmarshals a known account from a hash or a slot to value object
+func (a *KnownAccount) UnmarshalJSON(input []byte) error {
+	var root common.Hash
+	if err := json.Unmarshal(input, &root); err == nil {
+		a.StorageRoot = &root
+		a.StorageSlots = nil
+		return nil
+	}
+
+	var slots map[common.Hash]common.Hash
+	if err := json.Unmarshal(input, &slots); err != nil {
+		return fmt.Errorf("invalid known account, it must be a storage root hash or a storage slots object: %w", err)
+	}
+	a.StorageRoot = nil
+	a.StorageSlots = slots
+	return nil
+}
+
+// MarshalJSON marshals a known account into a hash or a slot to value object
+func (a KnownAccount) MarshalJSON() ([]byte, error) {
+	if a.StorageRoot != nil {
+		return json.Marshal(a.StorageRoot)
+	}
+	return json.Marshal(a.StorageSlots)
+}
+
+// TransactionConditions are the preconditions provided to eth_sendRawTransactionConditional
+type TransactionConditions struct {
+	KnownAccounts  map[common.Address]KnownAccount `json:"knownAccounts,omitempty"`
+	BlockNumberMin *ArgUint64                      `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *ArgUint64                      `json:"blockNumberMax,omitempty"`
+	TimestampMin   *ArgUint64                      `json:"timestampMin,omitempty"`
+	TimestampMax   *ArgUint64                      `json:"timestampMax,omitempty"`
+}
+
+// ToTxConditions converts the RPC conditions into the pool tx conditions
+func (c TransactionConditions) ToTxConditions() *pool.TxConditions {
+	conditions := &pool.TxConditions{
+		BlockNumberMin: argUint64ToUint64Ptr(c.BlockNumberMin),
+		BlockNumberMax: argUint64ToUint64Ptr(c.BlockNumberMax),
+		TimestampMin:   argUint64ToUint64Ptr(c.TimestampMin),
+		TimestampMax:   argUint64ToUint64Ptr(c.TimestampMax),
+	}
+	if len(c.KnownAccounts) > 0 {
+		conditions.KnownAccounts = make(map[common.Address]pool.KnownAccount, len(c.KnownAccounts))
+		for address, account := range c.KnownAccounts {
+			conditions.KnownAccounts[address] = pool.KnownAccount{
+				StorageRoot:  account.StorageRoot,
+				StorageSlots: account.StorageSlots,
+			}
+		}
+	}
+	return conditions
+}
+
+func argUint64ToUint64Ptr(a *ArgUint64) *uint64 {
+	if a == nil {
+		return nil
+	}
+	v := uint64(*a)
+	return &v
+}
@@ -15,6 +15,18 @@ const (
 	InvalidParamsErrorCode = -32602
 	// ParserErrorCode error code for parsing errors
 	ParserErrorCode = -32700
+	// MethodNotSupportedErrorCode error code for methods disabled in the node
+	MethodNotSupportedErrorCode = -32004
+	// LimitExceededErrorCode error code for requests rejected by the rate limit
+	LimitExceededErrorCode = -32005
+	// TxExpiredErrorCode error code for txs evicted from the pool because they expired
+	TxExpiredErrorCode = -32006
+	// ExecutorUnavailableErrorCode error code for requests rejected because the executor is unavailable
+	ExecutorUnavailableErrorCode = -32007
+	// UnauthorizedErrorCode error code for requests to the admin namespace without a valid auth token
+	UnauthorizedErrorCode = -32008
+	// SyncingErrorCode error code for requests rejected because the node is still syncing up to the trusted tip
+	SyncingErrorCode = -32009
 )
 
 var (
@@ -5,9 +5,13 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/aggregator/prover"
+	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
@@ -16,12 +20,43 @@ import (
 // PoolInterface contains the methods required to interact with the tx pool.
 type PoolInterface interface {
 	AddTx(ctx context.Context, tx types.Transaction, ip string) error
+	AddConditionalTx(ctx context.Context, tx types.Transaction, ip string, conditions *pool.TxConditions) error
 	GetGasPrices(ctx context.Context) (pool.GasPrices, error)
-	GetNonce(ctx context.Context, address common.Address) (uint64, error)
+	GetMinSuggestedGasPrice() *big.Int
+	GetPendingNonce(ctx context.Context, address common.Address, currentNonce uint64) (uint64, error)
 	GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error)
+	GetDroppedTxsSince(ctx context.Context, cursor uint64) ([]pool.DroppedTx, uint64, error)
 	GetPendingTxs(ctx context.Context, limit uint64) ([]pool.Transaction, error)
 	CountPendingTransactions(ctx context.Context) (uint64, error)
 	GetTxByHash(ctx context.Context, hash common.Hash) (*pool.Transaction, error)
+	SuggestNonceGapResolution(ctx context.Context, address common.Address, currentNonce uint64) (*pool.NonceGapResolution, error)
+	DropTx(ctx context.Context, hash common.Hash, reason string) (common.Address, error)
+	BanSender(ctx context.Context, address common.Address, reason string) ([]common.Hash, error)
+	UnbanSender(ctx context.Context, address common.Address) error
+}
+
+// SequencerInterface contains the methods required to manage the sequencer.
+type SequencerInterface interface {
+	IsFinalizerHalted() bool
+	ResumeFinalizer() error
+	GetWorkerTxs() (sequencer.TxsSnapshot, error)
+	ReloadPolicy(ctx context.Context) error
+	IsSequencingPaused() bool
+	PauseSequencing() error
+	ResumeSequencing() error
+	DeleteWorkerTx(hash common.Hash, from common.Address) error
+}
+
+// AggregatorInterface contains the methods required to inspect the aggregator.
+type AggregatorInterface interface {
+	ConnectedProvers() []prover.Info
+}
+
+// ExecutorDebugInterface contains the methods required to manage the debug flags of the
+// requests sent to the executor.
+type ExecutorDebugInterface interface {
+	GetExecutorDebugStatus() map[string]state.ExecutorDebugStatus
+	SetExecutorDebugFlags(origin string, flags executor.DebugFlags, duration time.Duration) error
 }
 
 // StateInterface gathers the methods required to interact with the state.
@@ -29,7 +64,7 @@ type StateInterface interface {
 	StartToMonitorNewL2Blocks()
 	BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
 	DebugTransaction(ctx context.Context, transactionHash common.Hash, traceConfig state.TraceConfig, dbTx pgx.Tx) (*runtime.ExecutionResult, error)
-	EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, []byte, error)
+	EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, stateOverride state.StateOverride, dbTx pgx.Tx) (uint64, []byte, error)
 	GetBalance(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error)
 	GetCode(ctx context.Context, address common.Address, root common.Hash) ([]byte, error)
 	GetL2BlockByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*types.Block, error)
@@ -46,6 +81,9 @@ type StateInterface interface {
 	GetLogs(ctx context.Context, fromBlock uint64, toBlock uint64, addresses []common.Address, topics [][]common.Hash, blockHash *common.Hash, since *time.Time, dbTx pgx.Tx) ([]*types.Log, error)
 	GetNonce(ctx context.Context, address common.Address, root common.Hash) (uint64, error)
 	GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error)
+	GetProof(ctx context.Context, address common.Address, storageKeys []common.Hash, root common.Hash) (*state.AccountProof, error)
+	GetStateRootByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (common.Hash, error)
+	GetStoredFlushID(ctx context.Context) (uint64, string, error)
 	GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (state.SyncingInfo, error)
 	GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error)
 	GetTransactionByL2BlockHashAndIndex(ctx context.Context, blockHash common.Hash, index uint64, dbTx pgx.Tx) (*types.Transaction, error)
@@ -53,7 +91,7 @@ type StateInterface interface {
 	GetTransactionReceipt(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Receipt, error)
 	IsL2BlockConsolidated(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (bool, error)
 	IsL2BlockVirtualized(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (bool, error)
-	ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*runtime.ExecutionResult, error)
+	ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, stateOverride state.StateOverride, dbTx pgx.Tx) (*runtime.ExecutionResult, error)
 	RegisterNewL2BlockEventHandler(h state.NewL2BlockEventHandler)
 	GetLastVirtualBatchNum(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetLastVerifiedBatch(ctx context.Context, dbTx pgx.Tx) (*state.VerifiedBatch, error)
@@ -62,16 +100,25 @@ type StateInterface interface {
 	GetTransactionsByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (txs []types.Transaction, effectivePercentages []uint8, err error)
 	GetVirtualBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VirtualBatch, error)
 	GetVerifiedBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatch, error)
+	GetVerifiedBatchProof(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatchProof, error)
 	GetExitRootByGlobalExitRoot(ctx context.Context, ger common.Hash, dbTx pgx.Tx) (*state.GlobalExitRoot, error)
 	GetL2BlocksByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]types.Block, error)
 	GetNativeBlockHashesInRange(ctx context.Context, fromBlockNumber uint64, toBlockNumber uint64, dbTx pgx.Tx) ([]common.Hash, error)
+	GetBridgeDepositsByDestinationAddress(ctx context.Context, destinationAddress common.Address, limit uint64, dbTx pgx.Tx) ([]state.BridgeDeposit, error)
+	GetBridgeClaimsByDestinationAddress(ctx context.Context, destinationAddress common.Address, limit uint64, dbTx pgx.Tx) ([]state.BridgeClaim, error)
+	GetBatchLifecycle(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]state.BatchLifecycleTransition, error)
+	GetForcedBatchInclusion(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (*state.ForcedBatchInclusion, error)
 	GetLastClosedBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetLastVerifiedL2BlockNumberUntilL1Block(ctx context.Context, l1FinalizedBlockNumber uint64, dbTx pgx.Tx) (uint64, error)
 	GetLastVerifiedBatchNumberUntilL1Block(ctx context.Context, l1BlockNumber uint64, dbTx pgx.Tx) (uint64, error)
+	GetSequencesInRange(ctx context.Context, fromBatchNumber, toBatchNumber uint64, dbTx pgx.Tx) ([]state.Sequence, error)
+	CheckSequenceDataIntegrity(ctx context.Context, sequence state.Sequence, oldAccInputHash, l1AccInputHash common.Hash, dbTx pgx.Tx) (*state.SequenceDataIntegrity, error)
+	CompareAccInputHashes(ctx context.Context, fromBatchNumber, toBatchNumber uint64, l1AccInputHash func(ctx context.Context, batchNumber uint64) (common.Hash, error), dbTx pgx.Tx) (uint64, *state.AccInputHashDivergence, error)
 }
 
 // EthermanInterface provides integration with L1
 type EthermanInterface interface {
 	GetSafeBlockNumber(ctx context.Context) (uint64, error)
 	GetFinalizedBlockNumber(ctx context.Context) (uint64, error)
+	GetSequencedBatchesInfo(ctx context.Context, lastBatchNumber uint64) (ethmanTypes.SequencedBatchesInfo, error)
 }
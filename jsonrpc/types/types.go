@@ -9,6 +9,9 @@ import (
 	"strings"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -178,14 +181,51 @@ func (arg *ArgAddress) Address() common.Address {
 
 // TxArgs is the transaction argument for the rpc endpoints
 type TxArgs struct {
-	From     *common.Address
-	To       *common.Address
-	Gas      *ArgUint64
-	GasPrice *ArgBytes
-	Value    *ArgBytes
-	Data     *ArgBytes
-	Input    *ArgBytes
-	Nonce    *ArgUint64
+	From                 *common.Address
+	To                   *common.Address
+	Gas                  *ArgUint64
+	GasPrice             *ArgBytes
+	MaxFeePerGas         *ArgBytes
+	MaxPriorityFeePerGas *ArgBytes
+	Value                *ArgBytes
+	Data                 *ArgBytes
+	Input                *ArgBytes
+	Nonce                *ArgUint64
+	AccessList           *types.AccessList
+	Type                 *ArgUint64
+}
+
+// txType returns the type of the tx described by the arguments, when it's
+// not provided it's inferred from the fee and access list fields
+func (args *TxArgs) txType() (uint8, error) {
+	isDynamicFee := args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil
+	if isDynamicFee && args.GasPrice != nil {
+		return 0, fmt.Errorf("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+
+	if args.Type != nil {
+		txType := uint64(*args.Type)
+		switch txType {
+		case types.LegacyTxType, types.AccessListTxType:
+			if isDynamicFee {
+				return 0, fmt.Errorf("maxFeePerGas and maxPriorityFeePerGas are not allowed for tx type %d", txType)
+			}
+		case types.DynamicFeeTxType:
+		default:
+			return 0, fmt.Errorf("%w: type %d", types.ErrTxTypeNotSupported, txType)
+		}
+		if txType == types.LegacyTxType && args.AccessList != nil {
+			return 0, fmt.Errorf("accessList is not allowed for legacy txs")
+		}
+		return uint8(txType), nil
+	}
+
+	if isDynamicFee {
+		return types.DynamicFeeTxType, nil
+	} else if args.AccessList != nil {
+		return types.AccessListTxType, nil
+	}
+	return types.LegacyTxType, nil
 }
 
 // ToTransaction transforms txnArgs into a Transaction
@@ -206,6 +246,11 @@ func (args *TxArgs) ToTransaction(ctx context.Context, st StateInterface, maxCum
 		value.SetBytes(*args.Value)
 	}
 
+	txType, err := args.txType()
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
 	gasPrice := big.NewInt(0)
 	if args.GasPrice != nil {
 		gasPrice.SetBytes(*args.GasPrice)
@@ -225,16 +270,57 @@ func (args *TxArgs) ToTransaction(ctx context.Context, st StateInterface, maxCum
 		gas = uint64(*args.Gas)
 	}
 
-	tx := types.NewTx(&types.LegacyTx{
-		Nonce:    nonce,
-		To:       args.To,
-		Value:    value,
-		Gas:      gas,
-		GasPrice: gasPrice,
-		Data:     data,
-	})
+	var accessList types.AccessList
+	if args.AccessList != nil {
+		accessList = *args.AccessList
+	}
+
+	var txData types.TxData
+	switch txType {
+	case types.DynamicFeeTxType:
+		gasFeeCap := big.NewInt(0)
+		if args.MaxFeePerGas != nil {
+			gasFeeCap.SetBytes(*args.MaxFeePerGas)
+		}
+		gasTipCap := big.NewInt(0)
+		if args.MaxPriorityFeePerGas != nil {
+			gasTipCap.SetBytes(*args.MaxPriorityFeePerGas)
+		}
+		if gasFeeCap.Cmp(gasTipCap) < 0 {
+			return common.Address{}, nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
+		}
+		txData = &types.DynamicFeeTx{
+			Nonce:      nonce,
+			To:         args.To,
+			Value:      value,
+			Gas:        gas,
+			GasFeeCap:  gasFeeCap,
+			GasTipCap:  gasTipCap,
+			Data:       data,
+			AccessList: accessList,
+		}
+	case types.AccessListTxType:
+		txData = &types.AccessListTx{
+			Nonce:      nonce,
+			To:         args.To,
+			Value:      value,
+			Gas:        gas,
+			GasPrice:   gasPrice,
+			Data:       data,
+			AccessList: accessList,
+		}
+	default:
+		txData = &types.LegacyTx{
+			Nonce:    nonce,
+			To:       args.To,
+			Value:    value,
+			Gas:      gas,
+			GasPrice: gasPrice,
+			Data:     data,
+		}
+	}
 
-	return sender, tx, nil
+	return sender, types.NewTx(txData), nil
 }
 
 // Block structure
@@ -506,6 +592,7 @@ type Transaction struct {
 	V           ArgBig          `json:"v"`
 	R           ArgBig          `json:"r"`
 	S           ArgBig          `json:"s"`
+	YParity     *ArgUint64      `json:"yParity,omitempty"`
 	Hash        common.Hash     `json:"hash"`
 	From        common.Address  `json:"from"`
 	BlockHash   *common.Hash    `json:"blockHash"`
@@ -556,6 +643,11 @@ func NewTransaction(
 		ChainID:  ArgBig(*tx.ChainId()),
 		Type:     ArgUint64(tx.Type()),
 	}
+	// the typed txs signature has the y parity instead of the legacy v
+	if tx.Type() != types.LegacyTxType {
+		yParity := ArgUint64(v.Uint64())
+		res.YParity = &yParity
+	}
 
 	if receipt != nil {
 		bn := ArgUint64(receipt.BlockNumber.Uint64())
@@ -594,6 +686,14 @@ type Receipt struct {
 	EffectiveGasPrice *ArgBig         `json:"effectiveGasPrice,omitempty"`
 }
 
+// FeeHistory is the response of eth_feeHistory
+type FeeHistory struct {
+	OldestBlock  ArgUint64  `json:"oldestBlock"`
+	Reward       [][]ArgBig `json:"reward,omitempty"`
+	BaseFee      []ArgBig   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64  `json:"gasUsedRatio"`
+}
+
 // NewReceipt creates a new Receipt instance
 func NewReceipt(tx types.Transaction, r *types.Receipt) (Receipt, error) {
 	to := tx.To()
@@ -668,6 +768,556 @@ func NewLog(l types.Log) Log {
 	}
 }
 
+// DroppedTransaction structure of the notifications sent
+// for the dropped transactions subscription
+type DroppedTransaction struct {
+	Hash      common.Hash    `json:"hash"`
+	From      common.Address `json:"from"`
+	Nonce     ArgUint64      `json:"nonce"`
+	Status    string         `json:"status"`
+	Reason    string         `json:"reason"`
+	DroppedAt ArgUint64      `json:"droppedAt"`
+}
+
+// NewDroppedTransaction creates a new instance of DroppedTransaction
+func NewDroppedTransaction(tx pool.DroppedTx) DroppedTransaction {
+	return DroppedTransaction{
+		Hash:      tx.Hash,
+		From:      tx.From,
+		Nonce:     ArgUint64(tx.Nonce),
+		Status:    tx.Status.String(),
+		Reason:    tx.Reason,
+		DroppedAt: ArgUint64(tx.DroppedAt.Unix()),
+	}
+}
+
+// SelectedTransaction structure of the notifications sent for the selected transactions
+// subscription, the tx can still be rejected by its execution after it's selected
+type SelectedTransaction struct {
+	BatchNumber ArgUint64      `json:"batchNumber"`
+	Position    ArgUint64      `json:"position"`
+	Hash        common.Hash    `json:"hash"`
+	From        common.Address `json:"from"`
+	Nonce       ArgUint64      `json:"nonce"`
+	GasPrice    *ArgBig        `json:"gasPrice"`
+	ZKCounters  ZKCounters     `json:"zkCounters"`
+	SelectedAt  ArgUint64      `json:"selectedAt"`
+}
+
+// ZKCounters are the zk counters reserved for a tx in the batch
+type ZKCounters struct {
+	GasUsed          ArgUint64 `json:"gasUsed"`
+	KeccakHashes     ArgUint64 `json:"keccakHashes"`
+	PoseidonHashes   ArgUint64 `json:"poseidonHashes"`
+	PoseidonPaddings ArgUint64 `json:"poseidonPaddings"`
+	MemAligns        ArgUint64 `json:"memAligns"`
+	Arithmetics      ArgUint64 `json:"arithmetics"`
+	Binaries         ArgUint64 `json:"binaries"`
+	Steps            ArgUint64 `json:"steps"`
+}
+
+// NewSelectedTransaction creates a new instance of SelectedTransaction
+func NewSelectedTransaction(e sequencer.TxSelectedEvent) SelectedTransaction {
+	tx := SelectedTransaction{
+		BatchNumber: ArgUint64(e.BatchNumber),
+		Position:    ArgUint64(e.Position),
+		Hash:        e.Hash,
+		From:        e.From,
+		Nonce:       ArgUint64(e.Nonce),
+		ZKCounters: ZKCounters{
+			GasUsed:          ArgUint64(e.ZKCounters.CumulativeGasUsed),
+			KeccakHashes:     ArgUint64(e.ZKCounters.UsedKeccakHashes),
+			PoseidonHashes:   ArgUint64(e.ZKCounters.UsedPoseidonHashes),
+			PoseidonPaddings: ArgUint64(e.ZKCounters.UsedPoseidonPaddings),
+			MemAligns:        ArgUint64(e.ZKCounters.UsedMemAligns),
+			Arithmetics:      ArgUint64(e.ZKCounters.UsedArithmetics),
+			Binaries:         ArgUint64(e.ZKCounters.UsedBinaries),
+			Steps:            ArgUint64(e.ZKCounters.UsedSteps),
+		},
+		SelectedAt: ArgUint64(e.SelectedAt.UnixMilli()),
+	}
+	if e.GasPrice != nil {
+		tx.GasPrice = (*ArgBig)(e.GasPrice)
+	}
+	return tx
+}
+
+// BridgeDeposit is a deposit made in the L2 bridge returned by zkevm_getBridgeDeposits
+type BridgeDeposit struct {
+	LeafType           ArgUint64      `json:"leafType"`
+	OriginNetwork      ArgUint64      `json:"originNetwork"`
+	OriginAddress      common.Address `json:"originAddress"`
+	DestinationNetwork ArgUint64      `json:"destinationNetwork"`
+	DestinationAddress common.Address `json:"destinationAddress"`
+	Amount             ArgBig         `json:"amount"`
+	Metadata           ArgBytes       `json:"metadata"`
+	DepositCount       ArgUint64      `json:"depositCount"`
+	BlockNumber        ArgUint64      `json:"blockNumber"`
+	TxHash             common.Hash    `json:"transactionHash"`
+	LogIndex           ArgUint64      `json:"logIndex"`
+}
+
+// NewBridgeDeposit creates a BridgeDeposit instance
+func NewBridgeDeposit(d state.BridgeDeposit) BridgeDeposit {
+	return BridgeDeposit{
+		LeafType:           ArgUint64(d.LeafType),
+		OriginNetwork:      ArgUint64(d.OriginNetwork),
+		OriginAddress:      d.OriginAddress,
+		DestinationNetwork: ArgUint64(d.DestinationNetwork),
+		DestinationAddress: d.DestinationAddress,
+		Amount:             ArgBig(*d.Amount),
+		Metadata:           d.Metadata,
+		DepositCount:       ArgUint64(d.DepositCount),
+		BlockNumber:        ArgUint64(d.BlockNumber),
+		TxHash:             d.TxHash,
+		LogIndex:           ArgUint64(d.LogIndex),
+	}
+}
+
+// BridgeClaim is a claim made in the L2 bridge returned by zkevm_getBridgeClaims
+type BridgeClaim struct {
+	Index              ArgUint64      `json:"index"`
+	OriginNetwork      ArgUint64      `json:"originNetwork"`
+	OriginAddress      common.Address `json:"originAddress"`
+	DestinationAddress common.Address `json:"destinationAddress"`
+	Amount             ArgBig         `json:"amount"`
+	BlockNumber        ArgUint64      `json:"blockNumber"`
+	TxHash             common.Hash    `json:"transactionHash"`
+	LogIndex           ArgUint64      `json:"logIndex"`
+}
+
+// NewBridgeClaim creates a BridgeClaim instance
+func NewBridgeClaim(c state.BridgeClaim) BridgeClaim {
+	return BridgeClaim{
+		Index:              ArgUint64(c.Index),
+		OriginNetwork:      ArgUint64(c.OriginNetwork),
+		OriginAddress:      c.OriginAddress,
+		DestinationAddress: c.DestinationAddress,
+		Amount:             ArgBig(*c.Amount),
+		BlockNumber:        ArgUint64(c.BlockNumber),
+		TxHash:             c.TxHash,
+		LogIndex:           ArgUint64(c.LogIndex),
+	}
+}
+
+// ExecutorDebugFlags are the debug flags of the requests sent to the executor by an origin,
+// returned by admin_executorDebugFlags
+type ExecutorDebugFlags struct {
+	ROMLogs               bool       `json:"romLogs"`
+	PersistFailedTxTraces bool       `json:"persistFailedTxTraces"`
+	ExpiresAt             *ArgUint64 `json:"expiresAt,omitempty"`
+}
+
+// NewExecutorDebugFlags creates an ExecutorDebugFlags instance
+func NewExecutorDebugFlags(status state.ExecutorDebugStatus) ExecutorDebugFlags {
+	res := ExecutorDebugFlags{
+		ROMLogs:               status.ROMLogs,
+		PersistFailedTxTraces: status.PersistFailedTxTraces,
+	}
+	if status.ExpiresAt != nil {
+		expiresAt := ArgUint64(status.ExpiresAt.Unix())
+		res.ExpiresAt = &expiresAt
+	}
+	return res
+}
+
+// BatchLifecycle is the lifecycle of a batch returned by zkevm_getBatchLifecycle, the
+// stage is the last one reached by the batch and the transitions are sorted by stage
+type BatchLifecycle struct {
+	Number      ArgUint64                  `json:"number"`
+	Stage       *string                    `json:"stage"`
+	Transitions []BatchLifecycleTransition `json:"transitions"`
+}
+
+// BatchLifecycleTransition is the transition of a batch to a stage of its lifecycle
+type BatchLifecycleTransition struct {
+	Stage         string       `json:"stage"`
+	Timestamp     ArgUint64    `json:"timestamp"`
+	L1TxHash      *common.Hash `json:"l1TransactionHash"`
+	L1BlockNumber *ArgUint64   `json:"l1BlockNumber"`
+}
+
+// NewBatchLifecycle creates a BatchLifecycle instance
+func NewBatchLifecycle(batchNumber uint64, transitions []state.BatchLifecycleTransition) BatchLifecycle {
+	res := BatchLifecycle{
+		Number:      ArgUint64(batchNumber),
+		Transitions: make([]BatchLifecycleTransition, 0, len(transitions)),
+	}
+	for _, t := range transitions {
+		transition := BatchLifecycleTransition{
+			Stage:     string(t.Stage),
+			Timestamp: ArgUint64(t.Timestamp.Unix()),
+			L1TxHash:  t.L1TxHash,
+		}
+		if t.L1BlockNumber != nil {
+			blockNumber := ArgUint64(*t.L1BlockNumber)
+			transition.L1BlockNumber = &blockNumber
+		}
+		res.Transitions = append(res.Transitions, transition)
+	}
+	if len(res.Transitions) > 0 {
+		stage := res.Transitions[len(res.Transitions)-1].Stage
+		res.Stage = &stage
+	}
+	return res
+}
+
+// VerifiedBatchProof is the final proof returned by zkevm_getVerifiedBatchProof, it verified
+// in L1 the range of batches from batchNumber to batchNumberFinal
+type VerifiedBatchProof struct {
+	BatchNumber      ArgUint64              `json:"batchNumber"`
+	BatchNumberFinal ArgUint64              `json:"batchNumberFinal"`
+	Proof            string                 `json:"proof"`
+	PublicInputs     FinalProofPublicInputs `json:"publicInputs"`
+	L1TxHash         common.Hash            `json:"l1TransactionHash"`
+	L1BlockNumber    ArgUint64              `json:"l1BlockNumber"`
+}
+
+// FinalProofPublicInputs are the public inputs of a final proof
+type FinalProofPublicInputs struct {
+	OldStateRoot     common.Hash    `json:"oldStateRoot"`
+	OldAccInputHash  common.Hash    `json:"oldAccInputHash"`
+	OldBatchNum      ArgUint64      `json:"oldBatchNum"`
+	ChainID          ArgUint64      `json:"chainId"`
+	ForkID           ArgUint64      `json:"forkId"`
+	NewStateRoot     common.Hash    `json:"newStateRoot"`
+	NewAccInputHash  common.Hash    `json:"newAccInputHash"`
+	NewLocalExitRoot common.Hash    `json:"newLocalExitRoot"`
+	NewBatchNum      ArgUint64      `json:"newBatchNum"`
+	AggregatorAddr   common.Address `json:"aggregatorAddr"`
+}
+
+// NewVerifiedBatchProof creates a VerifiedBatchProof instance
+func NewVerifiedBatchProof(proof state.VerifiedBatchProof) VerifiedBatchProof {
+	return VerifiedBatchProof{
+		BatchNumber:      ArgUint64(proof.BatchNumber),
+		BatchNumberFinal: ArgUint64(proof.BatchNumberFinal),
+		Proof:            proof.Proof,
+		PublicInputs: FinalProofPublicInputs{
+			OldStateRoot:     proof.PublicInputs.OldStateRoot,
+			OldAccInputHash:  proof.PublicInputs.OldAccInputHash,
+			OldBatchNum:      ArgUint64(proof.PublicInputs.OldBatchNum),
+			ChainID:          ArgUint64(proof.PublicInputs.ChainID),
+			ForkID:           ArgUint64(proof.PublicInputs.ForkID),
+			NewStateRoot:     proof.PublicInputs.NewStateRoot,
+			NewAccInputHash:  proof.PublicInputs.NewAccInputHash,
+			NewLocalExitRoot: proof.PublicInputs.NewLocalExitRoot,
+			NewBatchNum:      ArgUint64(proof.PublicInputs.NewBatchNum),
+			AggregatorAddr:   proof.PublicInputs.AggregatorAddr,
+		},
+		L1TxHash:      proof.L1TxHash,
+		L1BlockNumber: ArgUint64(proof.L1BlockNumber),
+	}
+}
+
+// SequenceDataIntegrity is the outcome returned by zkevm_verifyBatchDataIntegrity of checking the
+// stored data of the batches of a sequence against the accumulated input hash stored in L1
+type SequenceDataIntegrity struct {
+	FromBatchNumber   ArgUint64   `json:"fromBatchNumber"`
+	ToBatchNumber     ArgUint64   `json:"toBatchNumber"`
+	Valid             bool        `json:"valid"`
+	L1AccInputHash    common.Hash `json:"l1AccInputHash"`
+	AccInputHash      common.Hash `json:"accInputHash"`
+	MismatchedBatches []ArgUint64 `json:"mismatchedBatches"`
+}
+
+// NewSequenceDataIntegrity creates a SequenceDataIntegrity instance
+func NewSequenceDataIntegrity(integrity state.SequenceDataIntegrity) SequenceDataIntegrity {
+	res := SequenceDataIntegrity{
+		FromBatchNumber:   ArgUint64(integrity.FromBatchNumber),
+		ToBatchNumber:     ArgUint64(integrity.ToBatchNumber),
+		Valid:             integrity.IsValid(),
+		L1AccInputHash:    integrity.L1AccInputHash,
+		AccInputHash:      integrity.AccInputHash,
+		MismatchedBatches: make([]ArgUint64, 0, len(integrity.MismatchedBatches)),
+	}
+	for _, batchNumber := range integrity.MismatchedBatches {
+		res.MismatchedBatches = append(res.MismatchedBatches, ArgUint64(batchNumber))
+	}
+	return res
+}
+
+// AccInputHashVerification is the outcome returned by admin_verifyAccInputHashes of comparing the
+// accumulated input hashes computed locally for the sequences of a batch range with the ones stored in L1
+type AccInputHashVerification struct {
+	LastComparedBatchNumber ArgUint64               `json:"lastComparedBatchNumber"`
+	Divergence              *AccInputHashDivergence `json:"divergence"`
+}
+
+// AccInputHashDivergence is the first sequence whose accumulated input hash computed locally
+// doesn't match the one stored in L1
+type AccInputHashDivergence struct {
+	BatchNumber    ArgUint64   `json:"batchNumber"`
+	AccInputHash   common.Hash `json:"accInputHash"`
+	L1AccInputHash common.Hash `json:"l1AccInputHash"`
+}
+
+// NewAccInputHashVerification creates an AccInputHashVerification instance
+func NewAccInputHashVerification(lastComparedBatchNumber uint64, divergence *state.AccInputHashDivergence) AccInputHashVerification {
+	res := AccInputHashVerification{
+		LastComparedBatchNumber: ArgUint64(lastComparedBatchNumber),
+	}
+	if divergence != nil {
+		res.Divergence = &AccInputHashDivergence{
+			BatchNumber:    ArgUint64(divergence.BatchNumber),
+			AccInputHash:   divergence.AccInputHash,
+			L1AccInputHash: divergence.L1AccInputHash,
+		}
+	}
+	return res
+}
+
+const (
+	// ForcedBatchPendingStatus is the status of a forced batch not included in any batch yet
+	ForcedBatchPendingStatus = "pending"
+	// ForcedBatchProcessingStatus is the status of a forced batch included in a batch that is not closed yet
+	ForcedBatchProcessingStatus = "processing"
+)
+
+// ForcedBatch is a forced batch returned by zkevm_getForcedBatchByNumber, once it's included
+// in a batch the status is the last stage of its lifecycle reached by that batch
+type ForcedBatch struct {
+	ForcedBatchNumber ArgUint64      `json:"forcedBatchNumber"`
+	BlockNumber       ArgUint64      `json:"blockNumber"`
+	Sequencer         common.Address `json:"sequencer"`
+	GlobalExitRoot    common.Hash    `json:"globalExitRoot"`
+	Timestamp         ArgUint64      `json:"timestamp"`
+	RawTxsData        ArgBytes       `json:"rawTxsData"`
+	Status            string         `json:"status"`
+	BatchNumber       *ArgUint64     `json:"batchNumber"`
+}
+
+// NewForcedBatch creates a ForcedBatch instance
+func NewForcedBatch(inclusion state.ForcedBatchInclusion) ForcedBatch {
+	res := ForcedBatch{
+		ForcedBatchNumber: ArgUint64(inclusion.ForcedBatchNumber),
+		BlockNumber:       ArgUint64(inclusion.BlockNumber),
+		Sequencer:         inclusion.Sequencer,
+		GlobalExitRoot:    inclusion.GlobalExitRoot,
+		Timestamp:         ArgUint64(inclusion.ForcedAt.Unix()),
+		RawTxsData:        inclusion.RawTxsData,
+		Status:            ForcedBatchPendingStatus,
+	}
+	if inclusion.BatchNumber != nil {
+		batchNumber := ArgUint64(*inclusion.BatchNumber)
+		res.BatchNumber = &batchNumber
+		res.Status = ForcedBatchProcessingStatus
+	}
+	if inclusion.Stage != nil {
+		res.Status = string(*inclusion.Stage)
+	}
+	return res
+}
+
+// StateOverride is the collection of overridden accounts of eth_call and eth_estimateGas
+type StateOverride map[common.Address]OverrideAccount
+
+// OverrideAccount indicates the overriding fields of an account during the execution,
+// State replaces the whole storage of the account while StateDiff patches some slots
+type OverrideAccount struct {
+	Nonce     *ArgUint64                   `json:"nonce"`
+	Code      *ArgBytes                    `json:"code"`
+	Balance   *ArgBig                      `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// ToStateOverride converts the state override provided in the RPC request
+// into the one applied by the state
+func (o *StateOverride) ToStateOverride() (state.StateOverride, error) {
+	if o == nil {
+		return nil, nil
+	}
+
+	res := make(state.StateOverride, len(*o))
+	for address, account := range *o {
+		if account.State != nil && account.StateDiff != nil {
+			return nil, fmt.Errorf("account %s has both 'state' and 'stateDiff'", address.String())
+		}
+		overrideAccount := state.OverrideAccount{
+			State:     account.State,
+			StateDiff: account.StateDiff,
+		}
+		if account.Nonce != nil {
+			nonce := uint64(*account.Nonce)
+			overrideAccount.Nonce = &nonce
+		}
+		if account.Code != nil {
+			code := []byte(*account.Code)
+			overrideAccount.Code = &code
+		}
+		if account.Balance != nil {
+			overrideAccount.Balance = (*big.Int)(account.Balance)
+		}
+		res[address] = overrideAccount
+	}
+	return res, nil
+}
+
+// AccountProof is the state tree proof of an account returned by eth_getProof,
+// the zkEVM state is a sparse merkle tree where the balance, nonce, code hash and
+// code length of an account and each of its storage slots are independent leaves
+type AccountProof struct {
+	Address         common.Address `json:"address"`
+	StateRoot       common.Hash    `json:"stateRoot"`
+	Balance         ArgBig         `json:"balance"`
+	Nonce           ArgBig         `json:"nonce"`
+	CodeHash        common.Hash    `json:"codeHash"`
+	CodeLength      ArgBig         `json:"codeLength"`
+	BalanceProof    MerkleProof    `json:"balanceProof"`
+	NonceProof      MerkleProof    `json:"nonceProof"`
+	CodeHashProof   MerkleProof    `json:"codeHashProof"`
+	CodeLengthProof MerkleProof    `json:"codeLengthProof"`
+	StorageProof    []StorageProof `json:"storageProof"`
+}
+
+// StorageProof is the state tree proof of a storage slot of an account
+type StorageProof struct {
+	Key   common.Hash `json:"key"`
+	Value ArgBig      `json:"value"`
+	Proof MerkleProof `json:"proof"`
+}
+
+// MerkleProof is the proof of a leaf of the state tree
+type MerkleProof struct {
+	Key      common.Hash   `json:"key"`
+	Value    *ArgBig       `json:"value"`
+	Siblings [][]ArgUint64 `json:"siblings"`
+	InsKey   *common.Hash  `json:"insKey,omitempty"`
+	InsValue *ArgBig       `json:"insValue,omitempty"`
+	IsOld0   bool          `json:"isOld0"`
+}
+
+// NewAccountProof creates an AccountProof instance
+func NewAccountProof(proof *state.AccountProof, stateRoot common.Hash) AccountProof {
+	res := AccountProof{
+		Address:         proof.Address,
+		StateRoot:       stateRoot,
+		Balance:         ArgBig(*proof.Balance),
+		Nonce:           ArgBig(*proof.Nonce),
+		CodeHash:        proof.CodeHash,
+		CodeLength:      ArgBig(*proof.CodeLength),
+		BalanceProof:    NewMerkleProof(proof.BalanceProof),
+		NonceProof:      NewMerkleProof(proof.NonceProof),
+		CodeHashProof:   NewMerkleProof(proof.CodeHashProof),
+		CodeLengthProof: NewMerkleProof(proof.CodeLengthProof),
+		StorageProof:    make([]StorageProof, 0, len(proof.StorageProofs)),
+	}
+	for _, storageProof := range proof.StorageProofs {
+		res.StorageProof = append(res.StorageProof, StorageProof{
+			Key:   storageProof.Key,
+			Value: ArgBig(*storageProof.Value),
+			Proof: NewMerkleProof(storageProof.Proof),
+		})
+	}
+	return res
+}
+
+// NewMerkleProof creates a MerkleProof instance
+func NewMerkleProof(proof *merkletree.KeyProof) MerkleProof {
+	res := MerkleProof{
+		Key:      common.HexToHash(merkletree.H4ToString(proof.Key)),
+		Siblings: make([][]ArgUint64, 0, len(proof.Siblings)),
+		IsOld0:   proof.IsOld0,
+	}
+	if proof.Value != nil {
+		value := ArgBig(*proof.Value)
+		res.Value = &value
+	}
+	for _, siblings := range proof.Siblings {
+		levelSiblings := make([]ArgUint64, 0, len(siblings))
+		for _, sibling := range siblings {
+			levelSiblings = append(levelSiblings, ArgUint64(sibling))
+		}
+		res.Siblings = append(res.Siblings, levelSiblings)
+	}
+	if proof.InsKey != nil {
+		insKey := common.HexToHash(merkletree.H4ToString(proof.InsKey))
+		res.InsKey = &insKey
+	}
+	if proof.InsValue != nil {
+		insValue := ArgBig(*proof.InsValue)
+		res.InsValue = &insValue
+	}
+	return res
+}
+
+// AccountChange is the state of an account notified by the accountChanges subscription
+// when a closed batch changes it. The zkEVM state tree has no storage root per account,
+// the code hash is notified instead so the contract deployments are tracked too
+type AccountChange struct {
+	Address     common.Address `json:"address"`
+	BatchNumber ArgUint64      `json:"batchNumber"`
+	StateRoot   common.Hash    `json:"stateRoot"`
+	Balance     ArgBig         `json:"balance"`
+	Nonce       ArgBig         `json:"nonce"`
+	CodeHash    common.Hash    `json:"codeHash"`
+}
+
+// Witness is the state witness of a range of L2 blocks returned by zkevm_getWitness,
+// it contains the accounts, storage slots and code accessed by the txs of the blocks
+// along with their merkle proofs against the state root previous to the first block
+type Witness struct {
+	FromBlock ArgUint64        `json:"fromBlock"`
+	ToBlock   ArgUint64        `json:"toBlock"`
+	StateRoot common.Hash      `json:"stateRoot"`
+	Accounts  []WitnessAccount `json:"accounts"`
+}
+
+// WitnessAccount is the proof of an account accessed by the txs of a witness, along
+// with its code when the account is a contract
+type WitnessAccount struct {
+	AccountProof
+	Code ArgBytes `json:"code,omitempty"`
+}
+
+// InclusionProof is the proof of the inclusion of a tx and its receipt in a L2 block returned by
+// zkevm_getInclusionProof, the proofs are the merkle patricia trie nodes from the transactions
+// and receipts roots of the block header to the leaves of the tx and the receipt, whose key
+// is the rlp encoded index of the tx in the block
+type InclusionProof struct {
+	BlockHash        common.Hash `json:"blockHash"`
+	BlockNumber      ArgUint64   `json:"blockNumber"`
+	TxHash           common.Hash `json:"transactionHash"`
+	TxIndex          ArgUint64   `json:"transactionIndex"`
+	Key              ArgBytes    `json:"key"`
+	TransactionsRoot common.Hash `json:"transactionsRoot"`
+	TransactionProof []ArgBytes  `json:"transactionProof"`
+	ReceiptsRoot     common.Hash `json:"receiptsRoot"`
+	ReceiptProof     []ArgBytes  `json:"receiptProof"`
+}
+
+// NonceGapResolution is the response of zkevm_suggestNonceGapResolution, sending the fill txs
+// unblocks the gapped txs of the account, sending also the cancel txs cancels them
+type NonceGapResolution struct {
+	Address      common.Address         `json:"address"`
+	Nonce        ArgUint64              `json:"nonce"`
+	PendingNonce ArgUint64              `json:"pendingNonce"`
+	GappedNonces []ArgUint64            `json:"gappedNonces"`
+	Fill         []NonceGapResolutionTx `json:"fill"`
+	Cancel       []NonceGapResolutionTx `json:"cancel"`
+}
+
+// NonceGapResolutionTx is a tx suggested to resolve a nonce gap, a transfer of 0 to the
+// account itself, with its unsigned template when requested
+type NonceGapResolutionTx struct {
+	Nonce    ArgUint64    `json:"nonce"`
+	GasPrice ArgBig       `json:"gasPrice"`
+	Replaces *common.Hash `json:"replaces,omitempty"`
+	Tx       *UnsignedTx  `json:"tx,omitempty"`
+}
+
+// UnsignedTx is a legacy tx ready to be signed by the sender
+type UnsignedTx struct {
+	From     common.Address `json:"from"`
+	To       common.Address `json:"to"`
+	Value    ArgBig         `json:"value"`
+	Gas      ArgUint64      `json:"gas"`
+	GasPrice ArgBig         `json:"gasPrice"`
+	Nonce    ArgUint64      `json:"nonce"`
+	Input    ArgBytes       `json:"input"`
+}
+
 // ToBatchNumArg converts a big.Int into a batch number rpc parameter
 func ToBatchNumArg(number *big.Int) string {
 	if number == nil {
@@ -679,3 +1329,25 @@ func ToBatchNumArg(number *big.Int) string {
 	}
 	return hex.EncodeBig(number)
 }
+
+// MulticallResult is the result of a call of a zkevm_multicall request, either the data returned
+// by the call or its error, the errors of a call don't affect the rest of the calls
+type MulticallResult struct {
+	ReturnData *ArgBytes    `json:"returnData,omitempty"`
+	Error      *ErrorObject `json:"error,omitempty"`
+}
+
+// NewMulticallResult creates the result of a call from the response of the call
+func NewMulticallResult(returnData *ArgBytes, err Error) MulticallResult {
+	if err == nil {
+		return MulticallResult{ReturnData: returnData}
+	}
+	errorObj := &ErrorObject{
+		Code:    err.ErrorCode(),
+		Message: err.Error(),
+	}
+	if err.ErrorData() != nil {
+		errorObj.Data = ArgBytesPtr(*err.ErrorData())
+	}
+	return MulticallResult{Error: errorObj}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -248,3 +249,78 @@ func hexToBytes(str string) []byte {
 	bytes, _ := hex.DecodeHex(str)
 	return bytes
 }
+
+func TestTransactionConditionsUnmarshal(t *testing.T) {
+	input := `{
+		"knownAccounts": {
+			"0x0000000000000000000000000000000000000001": "0x05b21ee5f65c28a0af8e71290fc33625a1279a8b3d6357ce3ca60f22dbf59e63",
+			"0x0000000000000000000000000000000000000002": {
+				"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+			}
+		},
+		"blockNumberMin": "0x10",
+		"timestampMax": "0x20"
+	}`
+
+	var conditions TransactionConditions
+	err := json.Unmarshal([]byte(input), &conditions)
+	require.NoError(t, err)
+
+	txConditions := conditions.ToTxConditions()
+	require.Len(t, txConditions.KnownAccounts, 2)
+
+	rootAccount := txConditions.KnownAccounts[common.HexToAddress("0x1")]
+	require.NotNil(t, rootAccount.StorageRoot)
+	assert.Equal(t, common.HexToHash("0x05b21ee5f65c28a0af8e71290fc33625a1279a8b3d6357ce3ca60f22dbf59e63"), *rootAccount.StorageRoot)
+	assert.Nil(t, rootAccount.StorageSlots)
+
+	slotsAccount := txConditions.KnownAccounts[common.HexToAddress("0x2")]
+	assert.Nil(t, slotsAccount.StorageRoot)
+	assert.Equal(t, common.HexToHash("0x2"), slotsAccount.StorageSlots[common.HexToHash("0x1")])
+
+	require.NotNil(t, txConditions.BlockNumberMin)
+	assert.Equal(t, uint64(16), *txConditions.BlockNumberMin)
+	assert.Nil(t, txConditions.BlockNumberMax)
+	assert.Nil(t, txConditions.TimestampMin)
+	require.NotNil(t, txConditions.TimestampMax)
+	assert.Equal(t, uint64(32), *txConditions.TimestampMax)
+
+	err = json.Unmarshal([]byte(`{"knownAccounts": {"0x0000000000000000000000000000000000000001": 1}}`), &conditions)
+	require.Error(t, err)
+}
+
+func TestTxArgsType(t *testing.T) {
+	price := ArgBytes{1}
+	accessList := types.AccessList{}
+	dynamicFee := ArgUint64(types.DynamicFeeTxType)
+	legacy := ArgUint64(types.LegacyTxType)
+	unknown := ArgUint64(0x7f)
+
+	testCases := []struct {
+		name         string
+		args         TxArgs
+		expectedType uint8
+		expectError  bool
+	}{
+		{name: "legacy by default", args: TxArgs{GasPrice: &price}, expectedType: types.LegacyTxType},
+		{name: "access list inferred", args: TxArgs{AccessList: &accessList}, expectedType: types.AccessListTxType},
+		{name: "dynamic fee inferred", args: TxArgs{MaxFeePerGas: &price}, expectedType: types.DynamicFeeTxType},
+		{name: "explicit dynamic fee", args: TxArgs{Type: &dynamicFee}, expectedType: types.DynamicFeeTxType},
+		{name: "gas price and max fee", args: TxArgs{GasPrice: &price, MaxFeePerGas: &price}, expectError: true},
+		{name: "legacy with max fee", args: TxArgs{Type: &legacy, MaxPriorityFeePerGas: &price}, expectError: true},
+		{name: "legacy with access list", args: TxArgs{Type: &legacy, AccessList: &accessList}, expectError: true},
+		{name: "unknown type", args: TxArgs{Type: &unknown}, expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			txType, err := testCase.args.txType()
+			if testCase.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedType, txType)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const defaultWarmUpCheckInterval = 5 * time.Second
+
+// warmUpGatedNamespaces are the namespaces whose methods read the state and
+// are rejected while the node is warming up
+var warmUpGatedNamespaces = []string{APIEth, APIZKEVM, APIDebug}
+
+// warmUpExemptMethods are the methods of the gated namespaces that don't depend on
+// the synced state, they are served while the node is warming up
+var warmUpExemptMethods = map[string]struct{}{
+	"eth_accounts":             {},
+	"eth_chainId":              {},
+	"eth_coinbase":             {},
+	"eth_gasPrice":             {},
+	"eth_getCompilers":         {},
+	"eth_protocolVersion":      {},
+	"eth_syncing":              {},
+	"eth_uninstallFilter":      {},
+	"eth_unsubscribe":          {},
+	"debug_getTraceJob":        {},
+	"zkevm_getPoolMinGasPrice": {},
+	"zkevm_getWitnessJob":      {},
+	"zkevm_unsubscribe":        {},
+}
+
+// trustedTipFunc returns the last batch number of the trusted tip
+type trustedTipFunc func(ctx context.Context) (uint64, error)
+
+// syncingErrorData is the data of the error returned to the state dependent methods while the node is warming up
+type syncingErrorData struct {
+	CurrentBatchNumber types.ArgUint64 `json:"currentBatchNumber"`
+	TrustedBatchNumber types.ArgUint64 `json:"trustedBatchNumber"`
+}
+
+// warmUpGate rejects the state dependent methods with a syncing error until the local state
+// is within the configured number of batches of the trusted tip, so a freshly restored node
+// doesn't serve stale data. Once the node is warmed up the gate stays open
+type warmUpGate struct {
+	cfg        WarmUpConfig
+	state      types.StateInterface
+	trustedTip trustedTipFunc
+
+	ready              atomic.Bool
+	currentBatchNumber atomic.Uint64
+	trustedBatchNumber atomic.Uint64
+}
+
+func newWarmUpGate(cfg WarmUpConfig, state types.StateInterface, trustedTip trustedTipFunc) *warmUpGate {
+	return &warmUpGate{
+		cfg:        cfg,
+		state:      state,
+		trustedTip: trustedTip,
+	}
+}
+
+// newTrustedTip returns the trusted tip of the node, the last batch of the trusted sequencer
+// if the node relays to it or the last batch seen in L1 otherwise
+func newTrustedTip(sequencerNodeURI string, state types.StateInterface) trustedTipFunc {
+	if sequencerNodeURI != "" {
+		return client.NewClient(sequencerNodeURI).BatchNumber
+	}
+	return func(ctx context.Context) (uint64, error) {
+		syncInfo, err := state.GetSyncingInfo(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		return syncInfo.LastBatchNumberSeen, nil
+	}
+}
+
+// run checks the sync state every check interval until the node is warmed up or it's stopped
+func (g *warmUpGate) run(stop chan struct{}) {
+	interval := g.cfg.CheckInterval.Duration
+	if interval <= 0 {
+		interval = defaultWarmUpCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := g.update(context.Background()); err != nil {
+			log.Warnf("failed to check the warm up of the node: %v", err)
+		}
+		if g.ready.Load() {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// update compares the last local batch with the trusted tip and opens the gate
+// when the local state is within the configured number of batches
+func (g *warmUpGate) update(ctx context.Context) error {
+	if g.ready.Load() {
+		return nil
+	}
+
+	currentBatchNumber, err := g.state.GetLastBatchNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the last batch number: %w", err)
+	}
+	trustedBatchNumber, err := g.trustedTip(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the trusted batch number: %w", err)
+	}
+	g.currentBatchNumber.Store(currentBatchNumber)
+	g.trustedBatchNumber.Store(trustedBatchNumber)
+
+	if trustedBatchNumber > currentBatchNumber && trustedBatchNumber-currentBatchNumber > g.cfg.MaxBatchesBehind {
+		log.Infof("RPC warming up, the node is %d batches behind the trusted batch %d, max allowed %d",
+			trustedBatchNumber-currentBatchNumber, trustedBatchNumber, g.cfg.MaxBatchesBehind)
+		return nil
+	}
+
+	g.ready.Store(true)
+	log.Infof("RPC warmed up at batch %d, the state dependent methods are served", currentBatchNumber)
+	return nil
+}
+
+// allow checks if the method can be served, the methods not depending on the state are always served
+func (g *warmUpGate) allow(method string) types.Error {
+	if g.ready.Load() || !isWarmUpGatedMethod(method) {
+		return nil
+	}
+
+	currentBatchNumber, trustedBatchNumber := g.currentBatchNumber.Load(), g.trustedBatchNumber.Load()
+	data, _ := json.Marshal(syncingErrorData{
+		CurrentBatchNumber: types.ArgUint64(currentBatchNumber),
+		TrustedBatchNumber: types.ArgUint64(trustedBatchNumber),
+	})
+	return types.NewRPCErrorWithData(types.SyncingErrorCode, "node is syncing, method %s is not available until the node is within %d batches of the trusted batch %d",
+		&data, method, g.cfg.MaxBatchesBehind, trustedBatchNumber)
+}
+
+// check is the health check of the warm up, the node is not ready while it's warming up
+func (g *warmUpGate) check(ctx context.Context) error {
+	if g.ready.Load() {
+		return nil
+	}
+	return fmt.Errorf("node is warming up at batch %d, trusted batch %d", g.currentBatchNumber.Load(), g.trustedBatchNumber.Load())
+}
+
+// isWarmUpGatedMethod checks if the method depends on the synced state
+func isWarmUpGatedMethod(method string) bool {
+	if _, exempt := warmUpExemptMethods[method]; exempt {
+		return false
+	}
+	for _, namespace := range warmUpGatedNamespaces {
+		if strings.HasPrefix(method, namespace+"_") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmUpGate(t *testing.T) {
+	st := mocks.NewStateMock(t)
+	trustedBatchNumber := uint64(100)
+	g := newWarmUpGate(WarmUpConfig{Enabled: true, MaxBatchesBehind: 5}, st, func(ctx context.Context) (uint64, error) {
+		return trustedBatchNumber, nil
+	})
+
+	// the state dependent methods are rejected before the first check
+	assert.Error(t, g.allow("eth_getBalance"))
+	assert.Error(t, g.check(context.Background()))
+
+	st.On("GetLastBatchNumber", mock.Anything, mock.Anything).Return(uint64(90), nil).Once()
+	require.NoError(t, g.update(context.Background()))
+
+	err := g.allow("eth_getBalance")
+	require.Error(t, err)
+	assert.Equal(t, types.SyncingErrorCode, err.ErrorCode())
+	var data syncingErrorData
+	require.NoError(t, json.Unmarshal(*err.ErrorData(), &data))
+	assert.Equal(t, types.ArgUint64(90), data.CurrentBatchNumber)
+	assert.Equal(t, types.ArgUint64(100), data.TrustedBatchNumber)
+	assert.Error(t, g.allow("zkevm_getBatchByNumber"))
+	assert.Error(t, g.allow("debug_traceTransaction"))
+	assert.Error(t, g.check(context.Background()))
+
+	// the methods not depending on the state are always served
+	for _, method := range []string{"eth_chainId", "eth_syncing", "net_version", "web3_clientVersion", "admin_proverVersions", "txpool_status"} {
+		assert.NoError(t, g.allow(method), method)
+	}
+
+	st.On("GetLastBatchNumber", mock.Anything, mock.Anything).Return(uint64(95), nil).Once()
+	require.NoError(t, g.update(context.Background()))
+	assert.NoError(t, g.allow("eth_getBalance"))
+	assert.NoError(t, g.check(context.Background()))
+
+	// once warmed up the gate stays open even if the node falls behind
+	trustedBatchNumber = 200
+	require.NoError(t, g.update(context.Background()))
+	assert.NoError(t, g.allow("eth_getBalance"))
+}
+
+func TestWarmUpGateTrustedTipError(t *testing.T) {
+	st := mocks.NewStateMock(t)
+	g := newWarmUpGate(WarmUpConfig{Enabled: true, MaxBatchesBehind: 5}, st, func(ctx context.Context) (uint64, error) {
+		return 0, errors.New("connection refused")
+	})
+
+	st.On("GetLastBatchNumber", mock.Anything, mock.Anything).Return(uint64(90), nil).Once()
+	require.Error(t, g.update(context.Background()))
+	assert.Error(t, g.allow("eth_getBalance"))
+}
@@ -0,0 +1,152 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// witnessTracer is the tracer collecting the state accessed by each tx of a witness
+const witnessTracer = "prestateTracer"
+
+// errWitnessTooBig is returned when the state accessed by the txs exceeds the witness limits
+var errWitnessTooBig = errors.New("witness exceeds the size limit")
+
+// prestateAccount is the state of an account accessed by a tx as returned by the prestate tracer
+type prestateAccount struct {
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// witnessBuilder collects the accounts, storage slots and code accessed by the txs of a witness
+type witnessBuilder struct {
+	cfg WitnessConfig
+
+	codes    map[common.Address][]byte
+	storage  map[common.Address]map[common.Hash]struct{}
+	numSlots uint64
+}
+
+func newWitnessBuilder(cfg WitnessConfig) *witnessBuilder {
+	return &witnessBuilder{
+		cfg:     cfg,
+		codes:   make(map[common.Address][]byte),
+		storage: make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+// add adds the state accessed by a tx given its prestate trace, checking the witness limits
+func (b *witnessBuilder) add(trace json.RawMessage) error {
+	var accounts map[common.Address]prestateAccount
+	if err := json.Unmarshal(trace, &accounts); err != nil {
+		return fmt.Errorf("failed to decode prestate trace: %w", err)
+	}
+
+	for address, account := range accounts {
+		slots, found := b.storage[address]
+		if !found {
+			slots = make(map[common.Hash]struct{})
+			b.storage[address] = slots
+		}
+		if _, found := b.codes[address]; !found && len(account.Code) > 0 {
+			b.codes[address] = account.Code
+		}
+		for key := range account.Storage {
+			if _, found := slots[key]; !found {
+				slots[key] = struct{}{}
+				b.numSlots++
+			}
+		}
+	}
+
+	if b.cfg.MaxAccounts > 0 && uint64(len(b.storage)) > b.cfg.MaxAccounts {
+		return fmt.Errorf("%w of %d accounts", errWitnessTooBig, b.cfg.MaxAccounts)
+	}
+	if b.cfg.MaxStorageSlots > 0 && b.numSlots > b.cfg.MaxStorageSlots {
+		return fmt.Errorf("%w of %d storage slots", errWitnessTooBig, b.cfg.MaxStorageSlots)
+	}
+	return nil
+}
+
+// addresses returns the accessed addresses sorted, so the witnesses are deterministic
+func (b *witnessBuilder) addresses() []common.Address {
+	addresses := make([]common.Address, 0, len(b.storage))
+	for address := range b.storage {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+	return addresses
+}
+
+// storageKeys returns the accessed storage keys of the address sorted
+func (b *witnessBuilder) storageKeys(address common.Address) []common.Hash {
+	keys := make([]common.Hash, 0, len(b.storage[address]))
+	for key := range b.storage[address] {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].Bytes(), keys[j].Bytes()) < 0
+	})
+	return keys
+}
+
+// buildWitness generates the witness of the blocks, which must be consecutive and sorted,
+// tracing their txs and proving the accessed state against the state root previous to them
+func (z *ZKEVMEndpoints) buildWitness(ctx context.Context, blocks []*ethTypes.Block, dbTx pgx.Tx) (interface{}, types.Error) {
+	fromBlock, toBlock := blocks[0].NumberU64(), blocks[len(blocks)-1].NumberU64()
+	if fromBlock == 0 {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, "the witness of the genesis block is not supported", nil, false)
+	}
+
+	stateRoot, err := z.state.GetStateRootByL2BlockNumber(ctx, fromBlock-1, dbTx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get state root of block %d", fromBlock-1), err, true)
+	}
+
+	tracer := witnessTracer
+	builder := newWitnessBuilder(z.cfg.Witness)
+	for _, block := range blocks {
+		for _, tx := range block.Transactions() {
+			result, err := z.state.DebugTransaction(ctx, tx.Hash(), state.TraceConfig{Tracer: &tracer}, dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to trace tx %s", tx.Hash().String()), err, true)
+			}
+			if err := builder.add(result.ExecutorTraceResult); errors.Is(err, errWitnessTooBig) {
+				return RPCErrorResponse(types.LimitExceededErrorCode, err.Error(), nil, false)
+			} else if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to collect the state accessed by tx %s", tx.Hash().String()), err, true)
+			}
+		}
+	}
+
+	witness := types.Witness{
+		FromBlock: types.ArgUint64(fromBlock),
+		ToBlock:   types.ArgUint64(toBlock),
+		StateRoot: stateRoot,
+		Accounts:  make([]types.WitnessAccount, 0, len(builder.storage)),
+	}
+	for _, address := range builder.addresses() {
+		proof, err := z.state.GetProof(ctx, address, builder.storageKeys(address), stateRoot)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("failed to get proof of account %s", address.String()), err, true)
+		}
+		witness.Accounts = append(witness.Accounts, types.WitnessAccount{
+			AccountProof: types.NewAccountProof(proof, stateRoot),
+			Code:         builder.codes[address],
+		})
+	}
+
+	return witness, nil
+}
@@ -0,0 +1,42 @@
+package jsonrpc
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// concurrentWsConn wraps a web socket connection to serialize its writes, the responses
+// are written by the connection handler while the subscription notifications are sent
+// from their own goroutines, and the connection supports a single concurrent writer
+type concurrentWsConn struct {
+	wsConn     *websocket.Conn
+	writeMutex *sync.Mutex
+}
+
+// newConcurrentWsConn wraps the web socket connection
+func newConcurrentWsConn(wsConn *websocket.Conn) *concurrentWsConn {
+	return &concurrentWsConn{wsConn: wsConn, writeMutex: &sync.Mutex{}}
+}
+
+// ReadMessage reads the next message of the connection, it's only called by the connection handler
+func (c *concurrentWsConn) ReadMessage() (messageType int, p []byte, err error) {
+	return c.wsConn.ReadMessage()
+}
+
+// WriteMessage writes a message to the connection holding its write lock
+func (c *concurrentWsConn) WriteMessage(messageType int, data []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return c.wsConn.WriteMessage(messageType, data)
+}
+
+// SetReadLimit sets the max size in bytes of the messages read from the connection
+func (c *concurrentWsConn) SetReadLimit(limit int64) {
+	c.wsConn.SetReadLimit(limit)
+}
+
+// Close closes the connection
+func (c *concurrentWsConn) Close() error {
+	return c.wsConn.Close()
+}
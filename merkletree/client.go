@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/grpcclient"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/merkletree/hashdb"
 	"google.golang.org/grpc"
@@ -12,8 +13,13 @@ import (
 
 // NewMTDBServiceClient creates a new MTDB client.
 func NewMTDBServiceClient(ctx context.Context, c Config) (hashdb.HashDBServiceClient, *grpc.ClientConn, context.CancelFunc) {
+	callOpts, err := grpcclient.CallOptions(c.GRPCCompression, c.MaxGRPCMessageSize, c.MaxGRPCSendMessageSize)
+	if err != nil {
+		log.Fatalf("invalid merkletree gRPC options: %v", err)
+	}
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(callOpts...),
 		grpc.WithBlock(),
 	}
 	const maxWaitSeconds = 120
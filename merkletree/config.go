@@ -4,4 +4,12 @@ package merkletree
 type Config struct {
 	// URI is the server URI.
 	URI string `mapstructure:"URI"`
+	// MaxGRPCMessageSize is the max size of the messages received from the server
+	MaxGRPCMessageSize int `mapstructure:"MaxGRPCMessageSize"`
+	// MaxGRPCSendMessageSize is the max size of the messages sent to the server, the requests exceeding
+	// it fail before being sent. It should match the max message size accepted by the server
+	MaxGRPCSendMessageSize int `mapstructure:"MaxGRPCSendMessageSize"`
+	// GRPCCompression is the compression of the messages sent to the server, the possible values
+	// are "" (no compression), "gzip" and "snappy". The server must support it
+	GRPCCompression string `mapstructure:"GRPCCompression"`
 }
@@ -13,13 +13,25 @@ import (
 
 // StateTree provides methods to access and modify state in merkletree
 type StateTree struct {
-	grpcClient hashdb.HashDBServiceClient
+	grpcClient  hashdb.HashDBServiceClient
+	persistence hashdb.Persistence
 }
 
 // NewStateTree creates new StateTree.
 func NewStateTree(client hashdb.HashDBServiceClient) *StateTree {
 	return &StateTree{
-		grpcClient: client,
+		grpcClient:  client,
+		persistence: hashdb.Persistence_PERSISTENCE_DATABASE,
+	}
+}
+
+// Temporary returns a StateTree sharing the connection to the hashdb whose changes
+// are kept temporarily by the hashdb and never stored in the database, it's used
+// to build state roots that only live during an execution, like state overrides.
+func (tree *StateTree) Temporary() *StateTree {
+	return &StateTree{
+		grpcClient:  tree.grpcClient,
+		persistence: hashdb.Persistence_PERSISTENCE_TEMPORARY,
 	}
 }
 
@@ -123,6 +135,60 @@ func (tree *StateTree) GetStorageAt(ctx context.Context, address common.Address,
 	return fea2scalar(proof.Value), nil
 }
 
+// GetProof returns the merkle proof of the provided key, the key of an account
+// leaf can be built with KeyEthAddrBalance, KeyEthAddrNonce, KeyContractCode,
+// KeyCodeLength and KeyContractStorage.
+func (tree *StateTree) GetProof(ctx context.Context, key []byte, root []byte) (*KeyProof, error) {
+	r := new(big.Int).SetBytes(root)
+	k := new(big.Int).SetBytes(key)
+	rootH4, keyH4 := scalarToh4(r), scalarToh4(k)
+
+	result, err := tree.grpcClient.Get(ctx, &hashdb.GetRequest{
+		Root:    &hashdb.Fea{Fe0: rootH4[0], Fe1: rootH4[1], Fe2: rootH4[2], Fe3: rootH4[3]},
+		Key:     &hashdb.Fea{Fe0: keyH4[0], Fe1: keyH4[1], Fe2: keyH4[2], Fe3: keyH4[3]},
+		Details: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &KeyProof{
+		Root:     rootH4,
+		Key:      keyH4,
+		Siblings: make([][]uint64, len(result.Siblings)),
+		IsOld0:   result.IsOld0,
+	}
+	for level, siblings := range result.Siblings {
+		if level >= uint64(len(proof.Siblings)) {
+			return nil, fmt.Errorf("unexpected sibling level %d in proof with %d levels", level, len(proof.Siblings))
+		}
+		proof.Siblings[level] = siblings.Sibling
+	}
+	if proof.Value, err = feaStringToScalar(result.Value); err != nil {
+		return nil, err
+	}
+	if proof.InsValue, err = feaStringToScalar(result.InsValue); err != nil {
+		return nil, err
+	}
+	if result.InsKey != nil {
+		proof.InsKey = []uint64{result.InsKey.Fe0, result.InsKey.Fe1, result.InsKey.Fe2, result.InsKey.Fe3}
+	}
+	return proof, nil
+}
+
+// feaStringToScalar converts a fea string returned by the hashdb into a
+// scalar, returning nil for empty values
+func feaStringToScalar(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	fea, err := string2fea(value)
+	if err != nil {
+		return nil, err
+	}
+	return fea2scalar(fea), nil
+}
+
 // SetBalance sets balance.
 func (tree *StateTree) SetBalance(ctx context.Context, address common.Address, balance *big.Int, root []byte, uuid string) (newRoot []byte, proof *UpdateProof, err error) {
 	if balance.Cmp(big.NewInt(0)) == -1 {
@@ -179,7 +245,7 @@ func (tree *StateTree) SetCode(ctx context.Context, address common.Address, code
 	}
 
 	// store smart contract code by its hash
-	err = tree.setProgram(ctx, scCodeHash4, code, true)
+	err = tree.setProgram(ctx, scCodeHash4, code, tree.persistence == hashdb.Persistence_PERSISTENCE_DATABASE)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -282,7 +348,7 @@ func (tree *StateTree) set(ctx context.Context, oldRoot, key, value []uint64, uu
 		OldRoot:     &hashdb.Fea{Fe0: oldRoot[0], Fe1: oldRoot[1], Fe2: oldRoot[2], Fe3: oldRoot[3]},
 		Key:         &hashdb.Fea{Fe0: key[0], Fe1: key[1], Fe2: key[2], Fe3: key[3]},
 		Value:       feaValue,
-		Persistence: hashdb.Persistence_PERSISTENCE_DATABASE,
+		Persistence: tree.persistence,
 		BatchUuid:   uuid,
 	})
 	if err != nil {
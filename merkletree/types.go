@@ -1,5 +1,7 @@
 package merkletree
 
+import "math/big"
+
 // ResultCode represents the result code.
 type ResultCode int64
 
@@ -26,6 +28,28 @@ type Proof struct {
 	Value []uint64
 }
 
+// KeyProof is the merkle proof of a key of the state tree, generated on Get
+// operation with details.
+type KeyProof struct {
+	// Root is the proof root.
+	Root []uint64
+	// Key is the proof key.
+	Key []uint64
+	// Value is the value of the key, nil if the key is not set.
+	Value *big.Int
+	// Siblings are the siblings of the path from the root to the key leaf,
+	// ordered by level.
+	Siblings [][]uint64
+	// InsKey is the key of the leaf found in the path of the key when the key
+	// is not set, used to prove its non inclusion.
+	InsKey []uint64
+	// InsValue is the value of the leaf found in the path of the key when the
+	// key is not set.
+	InsValue *big.Int
+	// IsOld0 is true if the path of the key ends in an empty node.
+	IsOld0 bool
+}
+
 // UpdateProof is a proof generated on Set operation.
 type UpdateProof struct {
 	// OldRoot is the update proof old root.
@@ -0,0 +1,88 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DropTx sets the pending tx as failed on behalf of the operator, so it's not selected for a
+// batch anymore. It returns the sender of the tx
+func (p *Pool) DropTx(ctx context.Context, hash common.Hash, reason string) (common.Address, error) {
+	tx, err := p.Storage.GetTxByHash(ctx, hash)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if tx.Status != TxStatusPending {
+		return common.Address{}, ErrTxNotPending
+	}
+	from, err := state.GetSender(tx.Transaction)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	failedReason := droppedByOperatorReason(reason)
+	if err := p.UpdateTxStatus(ctx, hash, TxStatusFailed, false, &failedReason); err != nil {
+		return common.Address{}, err
+	}
+	log.Infof("tx %s dropped by the operator: %s", hash.String(), failedReason)
+	return from, nil
+}
+
+// BanSender blocks the address, so its new txs are rejected by the pool, and sets its pending
+// txs as failed. It returns the hashes of the dropped txs
+func (p *Pool) BanSender(ctx context.Context, address common.Address, reason string) ([]common.Hash, error) {
+	if err := p.Storage.AddBlockedAddress(ctx, address, reason); err != nil {
+		return nil, err
+	}
+	p.blockedAddresses.Store(address.String(), 1)
+
+	nonces, err := p.Storage.GetNoncesByFromAndStatus(ctx, address, TxStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	failedReason := droppedByOperatorReason(ErrBlockedSender.Error())
+	if reason != "" {
+		failedReason = droppedByOperatorReason(fmt.Sprintf("%s, %s", ErrBlockedSender.Error(), reason))
+	}
+	dropped := []common.Hash{}
+	for _, nonce := range nonces {
+		txs, err := p.Storage.GetTxsByFromAndNonce(ctx, address, nonce)
+		if err != nil {
+			return dropped, err
+		}
+		for _, tx := range txs {
+			if tx.Status != TxStatusPending {
+				continue
+			}
+			hash := tx.Hash()
+			if err := p.UpdateTxStatus(ctx, hash, TxStatusFailed, false, &failedReason); err != nil {
+				return dropped, err
+			}
+			dropped = append(dropped, hash)
+		}
+	}
+	log.Infof("sender %s banned by the operator, dropped txs: %d", address.String(), len(dropped))
+	return dropped, nil
+}
+
+// UnbanSender unblocks the address, so the pool accepts its txs again
+func (p *Pool) UnbanSender(ctx context.Context, address common.Address) error {
+	if err := p.Storage.DeleteBlockedAddress(ctx, address); err != nil {
+		return err
+	}
+	p.blockedAddresses.Delete(address.String())
+	log.Infof("sender %s unbanned by the operator", address.String())
+	return nil
+}
+
+// droppedByOperatorReason is the failed reason of the txs dropped by the operator
+func droppedByOperatorReason(reason string) string {
+	if reason == "" {
+		return "dropped by the operator"
+	}
+	return fmt.Sprintf("dropped by the operator: %s", reason)
+}
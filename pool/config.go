@@ -3,6 +3,15 @@ package pool
 import (
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// StorageTypePostgres stores the pool in the postgres pool database
+	StorageTypePostgres = "postgres"
+	// StorageTypeMemory stores the pool in the memory of the process
+	StorageTypeMemory = "memory"
 )
 
 // Config is the pool configuration
@@ -14,32 +23,144 @@ type Config struct {
 	// IntervalToRefreshGasPrices is the time to wait to refresh the gas prices
 	IntervalToRefreshGasPrices types.Duration `mapstructure:"IntervalToRefreshGasPrices"`
 
+	// Retry is the retry policy of the refreshes of the gas prices and the blocked addresses
+	// that fail, the last loaded values are kept until a refresh succeeds
+	Retry retry.Config `mapstructure:"Retry"`
+
 	// MaxTxBytesSize is the max size of a transaction in bytes
 	MaxTxBytesSize uint64 `mapstructure:"MaxTxBytesSize"`
 
 	// MaxTxDataBytesSize is the max size of the data field of a transaction in bytes
 	MaxTxDataBytesSize int `mapstructure:"MaxTxDataBytesSize"`
 
-	// DB is the database configuration
+	// DisablePreExecution skips the execution of the new txs before adding them to the pool. It saves an executor
+	// round trip per tx, but the txs out of the batch counters are not rejected until the sequencer processes them,
+	// the txs are stored without counters and the break even gas price is calculated with the tx gas limit
+	DisablePreExecution bool `mapstructure:"DisablePreExecution"`
+
+	// StorageType is the backend of the pool storage, "postgres" or "memory". The memory backend allows to
+	// run RPC-only nodes without a pool database, but the txs aren't shared between processes nor kept
+	// after a restart, so it can't be used when the sequencer runs in a different process
+	StorageType string `mapstructure:"StorageType"`
+
+	// DB is the database configuration, only used by the postgres storage
 	DB db.Config `mapstructure:"DB"`
 
 	// DefaultMinGasPriceAllowed is the default min gas price to suggest
 	DefaultMinGasPriceAllowed uint64 `mapstructure:"DefaultMinGasPriceAllowed"`
 
+	// MaxGasPriceAllowed is the max gas price accepted for a tx, 0 disables the limit
+	MaxGasPriceAllowed uint64 `mapstructure:"MaxGasPriceAllowed"`
+
+	// MaxGasPriceFactor is the max gas price accepted for a tx as a multiple of the min suggested
+	// gas price, 0 disables the limit. If both limits are set the lower one is applied
+	MaxGasPriceFactor float64 `mapstructure:"MaxGasPriceFactor"`
+
 	// MinAllowedGasPriceInterval is the interval to look back of the suggested min gas price for a tx
 	MinAllowedGasPriceInterval types.Duration `mapstructure:"MinAllowedGasPriceInterval"`
 
 	// PollMinAllowedGasPriceInterval is the interval to poll the suggested min gas price for a tx
 	PollMinAllowedGasPriceInterval types.Duration `mapstructure:"PollMinAllowedGasPriceInterval"`
 
-	// AccountQueue represents the maximum number of non-executable transaction slots permitted per account
+	// AccountQueue is the maximum distance between the next usable nonce of an account, after its pending
+	// txs without gaps, and the nonce of a new tx, the txs beyond it are rejected, 0 disables the limit
 	AccountQueue uint64 `mapstructure:"AccountQueue"`
 
 	// GlobalQueue represents the maximum number of non-executable transaction slots for all accounts
 	GlobalQueue uint64 `mapstructure:"GlobalQueue"`
 
+	// MaxNonceGappedTxsPerAccount is the maximum number of txs of an account that can't be
+	// executed because of a nonce gap, once reached a new tx with a lower nonce evicts the
+	// gapped tx with the highest nonce and the ones with a higher nonce are rejected, 0 disables the limit
+	MaxNonceGappedTxsPerAccount uint64 `mapstructure:"MaxNonceGappedTxsPerAccount"`
+
+	// MaxTxsPerAccount is the maximum number of pending txs of an account, once reached the new txs
+	// of the account are rejected unless they replace a pending tx with the same nonce, 0 disables the limit.
+	// It's also the max number of txs of an account held by the sequencer worker, where a new tx of the account
+	// evicts its not ready tx with the lowest efficiency if the new tx has higher efficiency
+	MaxTxsPerAccount uint64 `mapstructure:"MaxTxsPerAccount"`
+
+	// PriceBump is the minimum gasPrice increase, as a percentage, required for a new tx to replace a pending
+	// tx with the same sender and nonce, 0 only requires the new tx not to be cheaper. It's also applied by the
+	// sequencer worker, so the replacements accepted by the pool are not discarded by the worker
+	PriceBump uint64 `mapstructure:"PriceBump"`
+
 	// EffectiveGasPrice is the config for the effective gas price calculation
 	EffectiveGasPrice EffectiveGasPriceCfg `mapstructure:"EffectiveGasPrice"`
+
+	// KnownTxsCacheSize is the number of recently added tx hashes kept in memory to
+	// reject the resubmissions of the pending txs without validating them again, 0 disables the cache.
+	// The cache is per process, it doesn't see the status changes made by the sequencer or the
+	// synchronizer running in other processes until its entries expire after the KnownTxsCacheTTL
+	KnownTxsCacheSize int `mapstructure:"KnownTxsCacheSize"`
+
+	// KnownTxsCacheTTL is the time a tx hash is kept in the known txs cache, 0 keeps it until it's evicted
+	KnownTxsCacheTTL types.Duration `mapstructure:"KnownTxsCacheTTL"`
+
+	// SignatureValidation is the config of the validation of the signature and chain ID of the received txs
+	SignatureValidation SignatureValidationCfg `mapstructure:"SignatureValidation"`
+
+	// TxTags are the operator-defined tags of the received txs, a tx gets the first tag it matches.
+	// The sequencer uses them to only sequence the txs of a tag during its sequencing windows
+	TxTags []TxTagCfg `mapstructure:"TxTags"`
+
+	// SponsoredTxs is the config of the txs accepted with zero gas price, e.g. the bridge claims
+	SponsoredTxs SponsoredTxsCfg `mapstructure:"SponsoredTxs"`
+}
+
+// SponsoredTxsCfg contains the configuration of the sponsored txs, that are accepted with zero gas price
+// when they call one of the configured contracts and selectors, so the users bridging funds in can claim
+// them without having L2 gas. They are rate limited per sender and globally to prevent abuses
+type SponsoredTxsCfg struct {
+	// Enabled is a flag to enable/disable the sponsored txs
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Contracts are the addresses of the contracts whose calls can be sponsored
+	Contracts []common.Address `mapstructure:"Contracts"`
+
+	// Selectors are the 4-byte function selectors, in hex format like "0x2cffd02e", whose calls can
+	// be sponsored. If it's empty any call to the contracts can be sponsored
+	Selectors []string `mapstructure:"Selectors"`
+
+	// MaxGas is the max gas limit of a sponsored tx, 0 disables the limit
+	MaxGas uint64 `mapstructure:"MaxGas"`
+
+	// RateLimitPeriod is the period the rate limits are applied to
+	RateLimitPeriod types.Duration `mapstructure:"RateLimitPeriod"`
+
+	// MaxTxsPerSender is the max number of sponsored txs accepted from a sender during the rate limit period, 0 disables the limit
+	MaxTxsPerSender uint64 `mapstructure:"MaxTxsPerSender"`
+
+	// MaxTxs is the max number of sponsored txs accepted during the rate limit period, 0 disables the limit
+	MaxTxs uint64 `mapstructure:"MaxTxs"`
+}
+
+// TxTagCfg contains the configuration of a tx tag. A tx matches the tag if it matches all the
+// criteria that are not empty, a tag without criteria doesn't match any tx
+type TxTagCfg struct {
+	// Name is the name of the tag
+	Name string `mapstructure:"Name"`
+
+	// Senders are the addresses whose txs match the tag
+	Senders []common.Address `mapstructure:"Senders"`
+
+	// Selectors are the 4-byte function selectors, in hex format like "0xa9059cbb", whose calls match the tag
+	Selectors []string `mapstructure:"Selectors"`
+
+	// Endpoints are the JSON-RPC methods the txs are sent through, "eth_sendRawTransaction"
+	// or "eth_sendRawTransactionConditional", that match the tag
+	Endpoints []string `mapstructure:"Endpoints"`
+}
+
+// SignatureValidationCfg contains the configuration of the goroutines validating the signature and
+// chain ID of the received txs, the sender recovery is the most expensive step of the tx validation
+type SignatureValidationCfg struct {
+	// Parallelism is the number of goroutines validating signatures, 0 means the number of CPUs
+	Parallelism int `mapstructure:"Parallelism"`
+
+	// QueueSize is the max number of txs waiting to be validated, once reached the
+	// received txs wait for a free slot in the queue
+	QueueSize int `mapstructure:"QueueSize"`
 }
 
 // EffectiveGasPriceCfg contains the configuration properties for the effective gas price
@@ -61,3 +61,32 @@ func TestIsWithinConstraints(t *testing.T) {
 		})
 	}
 }
+
+func TestExceededConstraints(t *testing.T) {
+	cfg := state.BatchConstraintsCfg{
+		MaxCumulativeGasUsed: 500,
+		MaxKeccakHashes:      100,
+		MaxPoseidonHashes:    200,
+		MaxPoseidonPaddings:  150,
+		MaxMemAligns:         1000,
+		MaxArithmetics:       2000,
+		MaxBinaries:          3000,
+		MaxSteps:             4000,
+	}
+
+	exceeded := cfg.ExceededConstraints(state.ZKCounters{CumulativeGasUsed: 500, UsedKeccakHashes: 50, UsedSteps: 2000})
+	if len(exceeded) != 0 {
+		t.Errorf("Expected no exceeded constraints, got %v", exceeded)
+	}
+
+	exceeded = cfg.ExceededConstraints(state.ZKCounters{CumulativeGasUsed: 300, UsedKeccakHashes: 120, UsedSteps: 4500})
+	expected := []string{"KeccakHashes 120 exceeds the max 100 by 20", "Steps 4500 exceeds the max 4000 by 500"}
+	if len(exceeded) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, exceeded)
+	}
+	for i := range expected {
+		if exceeded[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], exceeded[i])
+		}
+	}
+}
@@ -2,6 +2,8 @@ package pool
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -20,6 +22,14 @@ var (
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
 
+	// ErrTxDataTooLarge is returned if the data of a transaction is bigger than the
+	// config MaxTxDataBytesSize, the executor can't process it.
+	ErrTxDataTooLarge = errors.New("data size bigger than allowed")
+
+	// ErrChainIDTooHigh is returned if the chain id of a transaction doesn't fit in
+	// the 64 bits supported by the executor.
+	ErrChainIDTooHigh = errors.New("chain id higher than allowed")
+
 	// ErrNegativeValue is a sanity error to ensure no one is able to specify a
 	// transaction with a negative value.
 	ErrNegativeValue = errors.New("negative value")
@@ -36,7 +46,7 @@ var (
 
 	// ErrTxPoolAccountOverflow is returned if the account sending the transaction
 	// has already reached the limit of transactions in the pool set by the config
-	// AccountQueue and can't accept another remote transaction.
+	// MaxTxsPerAccount and can't accept another remote transaction.
 	ErrTxPoolAccountOverflow = errors.New("account has reached the tx limit in the txpool")
 
 	// ErrTxPoolOverflow is returned if the transaction pool is full and can't accept
@@ -48,9 +58,18 @@ var (
 	ErrNonceTooLow = errors.New("nonce too low")
 
 	// ErrNonceTooHigh is returned if the nonce of a transaction is higher than the
-	// current + the configured AccountQueue.
+	// next usable nonce of the account + the configured AccountQueue.
 	ErrNonceTooHigh = errors.New("nonce too high")
 
+	// ErrNonceGapLimitReached is returned if the nonce of a transaction leaves a gap
+	// and the account has already reached the limit of gapped transactions in the pool
+	// set by the config MaxNonceGappedTxsPerAccount.
+	ErrNonceGapLimitReached = errors.New("nonce too high, account has reached the limit of txs with a nonce gap in the txpool")
+
+	// ErrNonceGappedTxEvicted is the failed reason of the transactions evicted from
+	// the pool to make room for a gapped transaction with a lower nonce.
+	ErrNonceGappedTxEvicted = errors.New("evicted by a tx with a lower nonce, account has reached the limit of txs with a nonce gap in the txpool")
+
 	// ErrInsufficientFunds is returned if the total cost of executing a transaction
 	// is higher than the balance of the user's account.
 	ErrInsufficientFunds = errors.New("insufficient funds for gas * price + value")
@@ -65,6 +84,9 @@ var (
 	// ErrGasPrice is returned if the transaction has specified lower gas price than the minimum allowed.
 	ErrGasPrice = errors.New("gas price too low")
 
+	// ErrGasPriceTooHigh is returned if the transaction has specified higher gas price than the maximum allowed.
+	ErrGasPriceTooHigh = errors.New("gas price too high")
+
 	// ErrReceivedZeroL1GasPrice is returned if the L1 gas price is 0.
 	ErrReceivedZeroL1GasPrice = errors.New("received L1 gas price 0")
 
@@ -76,4 +98,72 @@ var (
 
 	// ErrZeroL1GasPrice is returned if the L1 gas price is 0.
 	ErrZeroL1GasPrice = errors.New("L1 gas price 0")
+
+	// ErrTxConditionsNotMet is returned if the conditions of a conditional tx are not met.
+	ErrTxConditionsNotMet = errors.New("transaction conditions not met")
+
+	// ErrInvalidTxConditions is returned if the conditions of a conditional tx are not well formed.
+	ErrInvalidTxConditions = errors.New("invalid transaction conditions")
+
+	// ErrTooManyTxConditions is returned if a conditional tx checks too many storage slots.
+	ErrTooManyTxConditions = errors.New("too many transaction conditions")
+
+	// ErrStorageRootConditionNotSupported is returned if a conditional tx expects the storage root
+	// of an account, the state tree doesn't keep a storage root per account.
+	ErrStorageRootConditionNotSupported = errors.New("storage root conditions are not supported")
+
+	// ErrSponsoredTxGasLimit is returned if a sponsored tx has a gas limit higher than the max allowed
+	// for the sponsored txs.
+	ErrSponsoredTxGasLimit = errors.New("gas limit too high for a sponsored transaction")
+
+	// ErrSponsoredTxRateLimit is returned if the sender or the pool has already reached the limit of
+	// sponsored txs of the rate limit period.
+	ErrSponsoredTxRateLimit = errors.New("sponsored transactions rate limit reached, try again later")
+
+	// ErrTxNotPending is returned when the operator drops a transaction that isn't pending.
+	ErrTxNotPending = errors.New("transaction is not pending")
 )
+
+// GasPriceTooLowError is returned if the transaction has specified lower gas price
+// than the minimum allowed, it carries the minimum gas price the pool was accepting
+// when the transaction was rejected so the sender can resubmit it correctly priced
+type GasPriceTooLowError struct {
+	MinGasPrice *big.Int
+}
+
+// NewGasPriceTooLowError creates a new GasPriceTooLowError
+func NewGasPriceTooLowError(minGasPrice *big.Int) error {
+	return &GasPriceTooLowError{MinGasPrice: minGasPrice}
+}
+
+// Error returns the error message
+func (e *GasPriceTooLowError) Error() string {
+	return fmt.Sprintf("%s, min gas price allowed is %s", ErrGasPrice.Error(), e.MinGasPrice.String())
+}
+
+// Unwrap returns ErrGasPrice, so the error can be checked with errors.Is
+func (e *GasPriceTooLowError) Unwrap() error {
+	return ErrGasPrice
+}
+
+// GasPriceTooHighError is returned if the transaction has specified higher gas price
+// than the maximum allowed, it carries the maximum gas price the pool was accepting
+// when the transaction was rejected
+type GasPriceTooHighError struct {
+	MaxGasPrice *big.Int
+}
+
+// NewGasPriceTooHighError creates a new GasPriceTooHighError
+func NewGasPriceTooHighError(maxGasPrice *big.Int) error {
+	return &GasPriceTooHighError{MaxGasPrice: maxGasPrice}
+}
+
+// Error returns the error message
+func (e *GasPriceTooHighError) Error() string {
+	return fmt.Sprintf("%s, max gas price allowed is %s", ErrGasPriceTooHigh.Error(), e.MaxGasPrice.String())
+}
+
+// Unwrap returns ErrGasPriceTooHigh, so the error can be checked with errors.Is
+func (e *GasPriceTooHighError) Unwrap() error {
+	return ErrGasPriceTooHigh
+}
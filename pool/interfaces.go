@@ -11,7 +11,10 @@ import (
 	"github.com/jackc/pgx/v4"
 )
 
-type storage interface {
+// Storage is the storage of the pool txs, gas prices and the lists managed by the operator.
+// It's implemented by the postgres storage of the pgpoolstorage package and the in-memory
+// storage of the memorypoolstorage package, selected with the StorageType of the config
+type Storage interface {
 	AddTx(ctx context.Context, tx Transaction) error
 	CountTransactionsByStatus(ctx context.Context, status ...TxStatus) (uint64, error)
 	CountTransactionsByFromAndStatus(ctx context.Context, from common.Address, status ...TxStatus) (uint64, error)
@@ -19,9 +22,12 @@ type storage interface {
 	GetGasPrices(ctx context.Context) (uint64, uint64, error)
 	GetNonce(ctx context.Context, address common.Address) (uint64, error)
 	GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error)
+	GetDroppedTxsSince(ctx context.Context, cursor uint64) ([]DroppedTx, uint64, error)
+	GetNoncesByFromAndStatus(ctx context.Context, from common.Address, status ...TxStatus) ([]uint64, error)
 	GetTxsByFromAndNonce(ctx context.Context, from common.Address, nonce uint64) ([]Transaction, error)
 	GetTxsByStatus(ctx context.Context, state TxStatus, limit uint64) ([]Transaction, error)
 	GetNonWIPPendingTxs(ctx context.Context) ([]Transaction, error)
+	GetWIPPendingTxs(ctx context.Context) ([]Transaction, error)
 	IsTxPending(ctx context.Context, hash common.Hash) (bool, error)
 	SetGasPrices(ctx context.Context, l2GasPrice uint64, l1GasPrice uint64) error
 	DeleteGasPricesHistoryOlderThan(ctx context.Context, date time.Time) error
@@ -29,6 +35,7 @@ type storage interface {
 	UpdateTxsStatus(ctx context.Context, updateInfo []TxStatusUpdateInfo) error
 	UpdateTxStatus(ctx context.Context, updateInfo TxStatusUpdateInfo) error
 	UpdateTxWIPStatus(ctx context.Context, hash common.Hash, isWIP bool) error
+	UpdateTxZKCounters(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) error
 	GetTxs(ctx context.Context, filterStatus TxStatus, minGasPrice, limit uint64) ([]*Transaction, error)
 	GetTxFromAddressFromByHash(ctx context.Context, hash common.Hash) (common.Address, uint64, error)
 	GetTxByHash(ctx context.Context, hash common.Hash) (*Transaction, error)
@@ -36,11 +43,18 @@ type storage interface {
 	DeleteTransactionByHash(ctx context.Context, hash common.Hash) error
 	MarkWIPTxsAsPending(ctx context.Context) error
 	GetAllAddressesBlocked(ctx context.Context) ([]common.Address, error)
+	AddBlockedAddress(ctx context.Context, address common.Address, reason string) error
+	DeleteBlockedAddress(ctx context.Context, address common.Address) error
 	MinL2GasPriceSince(ctx context.Context, timestamp time.Time) (uint64, error)
+	AddSelectionAuditEntries(ctx context.Context, entries []SelectionAuditEntry) error
+	DeleteSelectionAuditOlderThan(ctx context.Context, date time.Time) error
+	GetPolicyDenyLists(ctx context.Context) (PolicyDenyLists, error)
 }
 
 type stateInterface interface {
 	GetBalance(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error)
+	GetForkIDByBatchNumber(batchNumber uint64) uint64
+	GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
 	GetLastL2Block(ctx context.Context, dbTx pgx.Tx) (*types.Block, error)
 	GetNonce(ctx context.Context, address common.Address, root common.Hash) (uint64, error)
 	GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error)
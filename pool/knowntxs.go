@@ -0,0 +1,93 @@
+package pool
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// knownTxs is a bounded LRU cache of the hashes of the transactions recently
+// accepted by the pool, used to reject the resubmissions of the pending txs without validating them again.
+// The cache is kept in the memory of each process. The hashes are removed when the status of the txs is
+// updated or they are deleted through the pool of the same process, the updates made by the sequencer or
+// the synchronizer running in another process are only seen once the hash expires after the ttl
+type knownTxs struct {
+	size    int
+	ttl     time.Duration
+	list    *list.List
+	entries map[common.Hash]*list.Element
+	mutex   sync.Mutex
+}
+
+// knownTx is an entry of the knownTxs cache
+type knownTx struct {
+	hash    common.Hash
+	addedAt time.Time
+}
+
+// newKnownTxs creates a cache able to hold up to size hashes for the ttl, a size of zero
+// disables the cache and a ttl of zero keeps the hashes until they are evicted
+func newKnownTxs(size int, ttl time.Duration) *knownTxs {
+	return &knownTxs{
+		size:    size,
+		ttl:     ttl,
+		list:    list.New(),
+		entries: make(map[common.Hash]*list.Element, size),
+	}
+}
+
+// contains checks if the hash is in the cache and not expired at now, refreshing its position if so
+func (k *knownTxs) contains(hash common.Hash, now time.Time) bool {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	e, found := k.entries[hash]
+	if !found {
+		return false
+	}
+	if k.ttl > 0 && now.Sub(e.Value.(knownTx).addedAt) > k.ttl {
+		k.list.Remove(e)
+		delete(k.entries, hash)
+		return false
+	}
+	k.list.MoveToFront(e)
+	return true
+}
+
+// add adds the hash to the cache at now evicting the least recently used one if the cache is full
+func (k *knownTxs) add(hash common.Hash, now time.Time) {
+	if k.size <= 0 {
+		return
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if e, found := k.entries[hash]; found {
+		e.Value = knownTx{hash: hash, addedAt: now}
+		k.list.MoveToFront(e)
+		return
+	}
+
+	if k.list.Len() >= k.size {
+		oldest := k.list.Back()
+		k.list.Remove(oldest)
+		delete(k.entries, oldest.Value.(knownTx).hash)
+	}
+	k.entries[hash] = k.list.PushFront(knownTx{hash: hash, addedAt: now})
+}
+
+// remove removes the hashes from the cache
+func (k *knownTxs) remove(hashes ...common.Hash) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	for _, hash := range hashes {
+		if e, found := k.entries[hash]; found {
+			k.list.Remove(e)
+			delete(k.entries, hash)
+		}
+	}
+}
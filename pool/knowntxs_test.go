@@ -0,0 +1,135 @@
+package pool
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/clock"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKnownTxs(t *testing.T) {
+	k := newKnownTxs(2, 0)
+	now := time.Now()
+	h1, h2, h3 := common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3")
+
+	k.add(h1, now)
+	k.add(h2, now)
+	assert.True(t, k.contains(h1, now))
+	assert.True(t, k.contains(h2, now))
+
+	// h1 is the least recently used, so it must be evicted
+	k.add(h3, now)
+	assert.False(t, k.contains(h1, now))
+	assert.True(t, k.contains(h2, now))
+	assert.True(t, k.contains(h3, now))
+
+	k.remove(h2)
+	assert.False(t, k.contains(h2, now))
+	assert.True(t, k.contains(h3, now))
+}
+
+func TestKnownTxsTTL(t *testing.T) {
+	k := newKnownTxs(10, time.Minute)
+	now := time.Now()
+	h1, h2 := common.HexToHash("0x1"), common.HexToHash("0x2")
+
+	k.add(h1, now)
+	k.add(h2, now.Add(30*time.Second))
+	assert.True(t, k.contains(h1, now.Add(time.Minute)))
+
+	// the hashes expire after the ttl, so the status changes made by other processes are seen
+	assert.False(t, k.contains(h1, now.Add(time.Minute+time.Second)))
+	assert.True(t, k.contains(h2, now.Add(time.Minute+time.Second)))
+
+	// adding the hash again refreshes it
+	k.add(h1, now.Add(2*time.Minute))
+	assert.True(t, k.contains(h1, now.Add(2*time.Minute)))
+}
+
+func TestKnownTxsDisabled(t *testing.T) {
+	k := newKnownTxs(0, 0)
+	h := common.HexToHash("0x1")
+	k.add(h, time.Now())
+	assert.False(t, k.contains(h, time.Now()))
+}
+
+// statusStorage is a pool storage recording the status updates, the rest of its
+// methods panic so the tests fail if the storage is unexpectedly queried
+type statusStorage struct {
+	Storage
+	updates []TxStatusUpdateInfo
+	deleted []common.Hash
+}
+
+func (s *statusStorage) DeleteTransactionByHash(ctx context.Context, hash common.Hash) error {
+	s.deleted = append(s.deleted, hash)
+	return nil
+}
+
+func (s *statusStorage) UpdateTxStatus(ctx context.Context, updateInfo TxStatusUpdateInfo) error {
+	s.updates = append(s.updates, updateInfo)
+	return nil
+}
+
+func (s *statusStorage) UpdateTxsStatus(ctx context.Context, updateInfos []TxStatusUpdateInfo) error {
+	s.updates = append(s.updates, updateInfos...)
+	return nil
+}
+
+func (s *statusStorage) DeleteTransactionsByHashes(ctx context.Context, hashes []common.Hash) error {
+	s.deleted = append(s.deleted, hashes...)
+	return nil
+}
+
+func TestKnownTxsRemovedWhenLeavingPending(t *testing.T) {
+	ctx := context.Background()
+	tx := *types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1)})
+	s := &statusStorage{}
+	p := &Pool{Storage: s, clock: clock.System, knownTxs: newKnownTxs(10, time.Minute)}
+	h1, h2, h3 := common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3")
+
+	// the cached resubmission is rejected without querying the storage
+	p.knownTxs.add(tx.Hash(), time.Now())
+	assert.ErrorIs(t, p.AddTx(ctx, tx, "127.0.0.1"), ErrAlreadyKnown)
+	assert.True(t, p.knownTxs.contains(tx.Hash(), time.Now()))
+
+	// the tx stays known while it's pending
+	require.NoError(t, p.UpdateTxStatus(ctx, tx.Hash(), TxStatusPending, true, nil))
+	assert.True(t, p.knownTxs.contains(tx.Hash(), time.Now()))
+
+	failedReason := "failed"
+	require.NoError(t, p.UpdateTxStatus(ctx, tx.Hash(), TxStatusFailed, false, &failedReason))
+	assert.False(t, p.knownTxs.contains(tx.Hash(), time.Now()))
+
+	p.knownTxs.add(h1, time.Now())
+	p.knownTxs.add(h2, time.Now())
+	require.NoError(t, p.UpdateTxsStatus(ctx, []TxStatusUpdateInfo{
+		{Hash: h1, NewStatus: TxStatusExpired},
+		{Hash: h2, NewStatus: TxStatusPending},
+	}))
+	assert.False(t, p.knownTxs.contains(h1, time.Now()))
+	assert.True(t, p.knownTxs.contains(h2, time.Now()))
+
+	p.knownTxs.add(h3, time.Now())
+	require.NoError(t, p.DeleteTransactionsByHashes(ctx, []common.Hash{h2, h3}))
+	assert.False(t, p.knownTxs.contains(h2, time.Now()))
+	assert.False(t, p.knownTxs.contains(h3, time.Now()))
+
+	p.knownTxs.add(h1, time.Now())
+	require.NoError(t, p.DeleteTransactionByHash(ctx, h1))
+	assert.False(t, p.knownTxs.contains(h1, time.Now()))
+
+	// the txs reorged by the synchronizer are deleted through the pool
+	p.knownTxs.add(tx.Hash(), time.Now())
+	require.NoError(t, p.DeleteReorgedTransactions(ctx, []*types.Transaction{&tx}))
+	assert.False(t, p.knownTxs.contains(tx.Hash(), time.Now()))
+
+	assert.Len(t, s.updates, 4)
+	assert.Equal(t, []common.Hash{h2, h3, h1, tx.Hash()}, s.deleted)
+}
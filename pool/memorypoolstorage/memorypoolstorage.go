@@ -0,0 +1,586 @@
+package memorypoolstorage
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MemoryPoolStorage is an implementation of the pool storage that keeps the data
+// in the memory of the process. It allows to run RPC-only nodes without a pool database,
+// but the data isn't shared with other processes and it's lost when the node restarts.
+// The deny lists of the sequencer policy are always empty
+type MemoryPoolStorage struct {
+	txs            map[common.Hash]*txRecord
+	gasPrices      []gasPriceRecord
+	selectionAudit []pool.SelectionAuditEntry
+	blocked        map[common.Address]string
+	droppedTxs     []droppedTxRecord
+	droppedTxsSeq  uint64
+	mutex          sync.RWMutex
+}
+
+// txRecord is a pool tx with the fields the postgres storage keeps in its own columns
+type txRecord struct {
+	tx   pool.Transaction
+	from common.Address
+}
+
+// droppedTxRecord is a dropped tx with the sequence number used as cursor
+type droppedTxRecord struct {
+	seq uint64
+	tx  pool.DroppedTx
+}
+
+type gasPriceRecord struct {
+	l2GasPrice uint64
+	l1GasPrice uint64
+	timestamp  time.Time
+}
+
+// NewMemoryPoolStorage creates and initializes an instance of MemoryPoolStorage
+func NewMemoryPoolStorage() *MemoryPoolStorage {
+	return &MemoryPoolStorage{
+		txs:     make(map[common.Hash]*txRecord),
+		blocked: make(map[common.Address]string),
+	}
+}
+
+// AddTx adds a transaction to the pool with the provided status, replacing the tx with the same hash
+func (m *MemoryPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) error {
+	from, err := state.GetSender(tx.Transaction)
+	if err != nil {
+		return err
+	}
+	tx.FailedReason = nil
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.txs[tx.Hash()] = &txRecord{tx: tx, from: from}
+	return nil
+}
+
+// GetTxsByStatus returns an array of transactions filtered by status sorted by gas price
+// limit parameter is used to limit amount txs, if limit = 0, then there is no limit
+func (m *MemoryPoolStorage) GetTxsByStatus(ctx context.Context, status pool.TxStatus, limit uint64) ([]pool.Transaction, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	records := m.filter(func(r *txRecord) bool { return r.tx.Status == status })
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].tx.GasPrice().Cmp(records[j].tx.GasPrice()) > 0
+	})
+	if limit > 0 && uint64(len(records)) > limit {
+		records = records[:limit]
+	}
+	return toTxs(records), nil
+}
+
+// GetNonWIPPendingTxs returns the pending transactions that aren't marked as WIP
+func (m *MemoryPoolStorage) GetNonWIPPendingTxs(ctx context.Context) ([]pool.Transaction, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	records := m.filter(func(r *txRecord) bool { return !r.tx.IsWIP && r.tx.Status == pool.TxStatusPending })
+	return toTxs(records), nil
+}
+
+// GetWIPPendingTxs returns the pending transactions marked as WIP, sorted by
+// sender and nonce
+func (m *MemoryPoolStorage) GetWIPPendingTxs(ctx context.Context) ([]pool.Transaction, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	records := m.filter(func(r *txRecord) bool { return r.tx.IsWIP && r.tx.Status == pool.TxStatusPending })
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].from != records[j].from {
+			return records[i].from.Hex() < records[j].from.Hex()
+		}
+		return records[i].tx.Nonce() < records[j].tx.Nonce()
+	})
+	return toTxs(records), nil
+}
+
+// GetPendingTxHashesSince returns the pending tx since the given time.
+func (m *MemoryPoolStorage) GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	records := m.filter(func(r *txRecord) bool {
+		return r.tx.Status == pool.TxStatusPending && !r.tx.ReceivedAt.Before(since)
+	})
+	hashes := make([]common.Hash, 0, len(records))
+	for _, r := range records {
+		hashes = append(hashes, r.tx.Hash())
+	}
+	return hashes, nil
+}
+
+// GetDroppedTxsSince returns the txs that have been dropped (failed, invalid, expired or deleted)
+// since the given cursor and the cursor to use in the next call. A zero cursor only returns the
+// current cursor
+func (m *MemoryPoolStorage) GetDroppedTxsSince(ctx context.Context, cursor uint64) ([]pool.DroppedTx, uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	nextCursor := m.droppedTxsSeq + 1
+	if cursor == 0 {
+		return nil, nextCursor, nil
+	}
+
+	var droppedTxs []pool.DroppedTx
+	for _, r := range m.droppedTxs {
+		if r.seq >= cursor {
+			droppedTxs = append(droppedTxs, r.tx)
+		}
+	}
+	return droppedTxs, nextCursor, nil
+}
+
+// GetTxs gets txs with the lowest nonce
+func (m *MemoryPoolStorage) GetTxs(ctx context.Context, filterStatus pool.TxStatus, minGasPrice, limit uint64) ([]*pool.Transaction, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	min := new(big.Int).SetUint64(minGasPrice)
+	records := m.filter(func(r *txRecord) bool {
+		return r.tx.Status == filterStatus && r.tx.GasPrice().Cmp(min) >= 0
+	})
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].tx.Nonce() < records[j].tx.Nonce()
+	})
+	if uint64(len(records)) > limit {
+		records = records[:limit]
+	}
+
+	txs := make([]*pool.Transaction, 0, len(records))
+	for _, r := range records {
+		tx := r.tx
+		txs = append(txs, &tx)
+	}
+	return txs, nil
+}
+
+// CountTransactionsByStatus get number of transactions
+// accordingly to the provided statuses
+func (m *MemoryPoolStorage) CountTransactionsByStatus(ctx context.Context, status ...pool.TxStatus) (uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	records := m.filter(func(r *txRecord) bool { return hasStatus(r, status) })
+	return uint64(len(records)), nil
+}
+
+// CountTransactionsByFromAndStatus get number of transactions
+// accordingly to the from address and provided statuses
+func (m *MemoryPoolStorage) CountTransactionsByFromAndStatus(ctx context.Context, from common.Address, status ...pool.TxStatus) (uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	records := m.filter(func(r *txRecord) bool { return r.from == from && hasStatus(r, status) })
+	return uint64(len(records)), nil
+}
+
+// GetNoncesByFromAndStatus gets the distinct nonces of the transactions
+// of the from address with the provided statuses sorted in ascending order
+func (m *MemoryPoolStorage) GetNoncesByFromAndStatus(ctx context.Context, from common.Address, status ...pool.TxStatus) ([]uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	records := m.filter(func(r *txRecord) bool { return r.from == from && hasStatus(r, status) })
+	found := make(map[uint64]struct{}, len(records))
+	var nonces []uint64
+	for _, r := range records {
+		if _, ok := found[r.tx.Nonce()]; ok {
+			continue
+		}
+		found[r.tx.Nonce()] = struct{}{}
+		nonces = append(nonces, r.tx.Nonce())
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	return nonces, nil
+}
+
+// UpdateTxStatus updates a transaction status accordingly to the
+// provided status and hash
+func (m *MemoryPoolStorage) UpdateTxStatus(ctx context.Context, updateInfo pool.TxStatusUpdateInfo) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.updateTxStatus(updateInfo)
+	return nil
+}
+
+// UpdateTxsStatus updates transactions status accordingly to the provided status and hashes
+func (m *MemoryPoolStorage) UpdateTxsStatus(ctx context.Context, updateInfos []pool.TxStatusUpdateInfo) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, updateInfo := range updateInfos {
+		m.updateTxStatus(updateInfo)
+	}
+	return nil
+}
+
+func (m *MemoryPoolStorage) updateTxStatus(updateInfo pool.TxStatusUpdateInfo) {
+	r, found := m.txs[updateInfo.Hash]
+	if !found {
+		return
+	}
+	statusChanged := r.tx.Status != updateInfo.NewStatus
+	r.tx.Status = updateInfo.NewStatus
+	r.tx.IsWIP = updateInfo.IsWIP
+	if updateInfo.FailedReason != nil {
+		failedReason := *updateInfo.FailedReason
+		r.tx.FailedReason = &failedReason
+	}
+	if statusChanged && isDropped(r.tx.Status) {
+		m.addDroppedTx(r, r.tx.Status)
+	}
+}
+
+// deleteTx deletes the tx and records it as dropped unless it was already
+// dropped, the caller must hold the mutex
+func (m *MemoryPoolStorage) deleteTx(hash common.Hash) {
+	r, found := m.txs[hash]
+	if !found {
+		return
+	}
+	delete(m.txs, hash)
+	if !isDropped(r.tx.Status) {
+		m.addDroppedTx(r, pool.TxStatusDeleted)
+	}
+}
+
+// addDroppedTx records the tx as dropped with the given status, the caller must hold the mutex
+func (m *MemoryPoolStorage) addDroppedTx(r *txRecord, status pool.TxStatus) {
+	droppedTx := pool.DroppedTx{
+		Hash:      r.tx.Hash(),
+		From:      r.from,
+		Nonce:     r.tx.Nonce(),
+		Status:    status,
+		DroppedAt: time.Now(),
+	}
+	if status != pool.TxStatusDeleted && r.tx.FailedReason != nil {
+		droppedTx.Reason = *r.tx.FailedReason
+	}
+	m.droppedTxsSeq++
+	m.droppedTxs = append(m.droppedTxs, droppedTxRecord{seq: m.droppedTxsSeq, tx: droppedTx})
+}
+
+// DeleteTransactionsByHashes deletes txs by their hashes
+func (m *MemoryPoolStorage) DeleteTransactionsByHashes(ctx context.Context, hashes []common.Hash) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, hash := range hashes {
+		m.deleteTx(hash)
+	}
+	return nil
+}
+
+// DeleteTransactionByHash deletes tx by its hash
+func (m *MemoryPoolStorage) DeleteTransactionByHash(ctx context.Context, hash common.Hash) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.deleteTx(hash)
+	return nil
+}
+
+// DeleteFailedTransactionsOlderThan deletes all failed transactions older than the given date
+// and the dropped txs notified before that date
+func (m *MemoryPoolStorage) DeleteFailedTransactionsOlderThan(ctx context.Context, date time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for hash, r := range m.txs {
+		if r.tx.Status == pool.TxStatusFailed && r.tx.ReceivedAt.Before(date) {
+			delete(m.txs, hash)
+		}
+	}
+
+	droppedTxs := make([]droppedTxRecord, 0, len(m.droppedTxs))
+	for _, r := range m.droppedTxs {
+		if !r.tx.DroppedAt.Before(date) {
+			droppedTxs = append(droppedTxs, r)
+		}
+	}
+	m.droppedTxs = droppedTxs
+	return nil
+}
+
+// SetGasPrices sets the latest l2 and l1 gas prices
+func (m *MemoryPoolStorage) SetGasPrices(ctx context.Context, l2GasPrice, l1GasPrice uint64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.gasPrices = append(m.gasPrices, gasPriceRecord{
+		l2GasPrice: l2GasPrice,
+		l1GasPrice: l1GasPrice,
+		timestamp:  time.Now().UTC(),
+	})
+	return nil
+}
+
+// GetGasPrices returns the latest l2 and l1 gas prices
+func (m *MemoryPoolStorage) GetGasPrices(ctx context.Context) (uint64, uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if len(m.gasPrices) == 0 {
+		return 0, 0, nil
+	}
+	last := m.gasPrices[len(m.gasPrices)-1]
+	return last.l2GasPrice, last.l1GasPrice, nil
+}
+
+// DeleteGasPricesHistoryOlderThan deletes all gas prices older than the given date except the last one
+func (m *MemoryPoolStorage) DeleteGasPricesHistoryOlderThan(ctx context.Context, date time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.gasPrices) == 0 {
+		return nil
+	}
+	gasPrices := make([]gasPriceRecord, 0, len(m.gasPrices))
+	for _, gasPrice := range m.gasPrices[:len(m.gasPrices)-1] {
+		if !gasPrice.timestamp.Before(date) {
+			gasPrices = append(gasPrices, gasPrice)
+		}
+	}
+	m.gasPrices = append(gasPrices, m.gasPrices[len(m.gasPrices)-1])
+	return nil
+}
+
+// MinL2GasPriceSince returns the min L2 gas price after given timestamp
+func (m *MemoryPoolStorage) MinL2GasPriceSince(ctx context.Context, timestamp time.Time) (uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var gasPrice uint64
+	for _, gp := range m.gasPrices {
+		if gp.timestamp.Before(timestamp) {
+			continue
+		}
+		if gasPrice == 0 || gp.l2GasPrice < gasPrice {
+			gasPrice = gp.l2GasPrice
+		}
+	}
+	if gasPrice == 0 {
+		return 0, state.ErrNotFound
+	}
+	return gasPrice, nil
+}
+
+// IsTxPending determines if the tx associated to the given hash is pending or
+// not.
+func (m *MemoryPoolStorage) IsTxPending(ctx context.Context, hash common.Hash) (bool, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	r, found := m.txs[hash]
+	return found && r.tx.Status == pool.TxStatusPending, nil
+}
+
+// GetTxsByFromAndNonce get all the transactions from the pool with the same from and nonce
+func (m *MemoryPoolStorage) GetTxsByFromAndNonce(ctx context.Context, from common.Address, nonce uint64) ([]pool.Transaction, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	records := m.filter(func(r *txRecord) bool { return r.from == from && r.tx.Nonce() == nonce })
+	return toTxs(records), nil
+}
+
+// GetTxFromAddressFromByHash gets tx from address by hash
+func (m *MemoryPoolStorage) GetTxFromAddressFromByHash(ctx context.Context, hash common.Hash) (common.Address, uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	r, found := m.txs[hash]
+	if !found {
+		return common.Address{}, 0, pool.ErrNotFound
+	}
+	return r.from, r.tx.Nonce(), nil
+}
+
+// GetNonce gets the nonce to the provided address accordingly to the txs in the pool
+func (m *MemoryPoolStorage) GetNonce(ctx context.Context, address common.Address) (uint64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var nonce *uint64
+	for _, r := range m.txs {
+		if r.from != address || (r.tx.Status != pool.TxStatusPending && r.tx.Status != pool.TxStatusSelected) {
+			continue
+		}
+		if n := r.tx.Nonce(); nonce == nil || n > *nonce {
+			nonce = &n
+		}
+	}
+	if nonce == nil {
+		return 0, nil
+	}
+	return *nonce + 1, nil
+}
+
+// GetTxByHash gets a transaction in the pool by its hash
+func (m *MemoryPoolStorage) GetTxByHash(ctx context.Context, hash common.Hash) (*pool.Transaction, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	r, found := m.txs[hash]
+	if !found {
+		return nil, pool.ErrNotFound
+	}
+	tx := r.tx
+	return &tx, nil
+}
+
+// GetTxZkCountersByHash gets a transaction zkcounters by its hash
+func (m *MemoryPoolStorage) GetTxZkCountersByHash(ctx context.Context, hash common.Hash) (*state.ZKCounters, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	r, found := m.txs[hash]
+	if !found {
+		return nil, pool.ErrNotFound
+	}
+	zkCounters := r.tx.ZKCounters
+	return &zkCounters, nil
+}
+
+// UpdateTxZKCounters updates the zkcounters of a transaction with the ones used when it was executed
+func (m *MemoryPoolStorage) UpdateTxZKCounters(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if r, found := m.txs[hash]; found {
+		r.tx.ZKCounters = zkCounters
+	}
+	return nil
+}
+
+// MarkWIPTxsAsPending updates WIP status to non WIP
+func (m *MemoryPoolStorage) MarkWIPTxsAsPending(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, r := range m.txs {
+		r.tx.IsWIP = false
+	}
+	return nil
+}
+
+// UpdateTxWIPStatus updates a transaction wip status accordingly to the
+// provided WIP status and hash
+func (m *MemoryPoolStorage) UpdateTxWIPStatus(ctx context.Context, hash common.Hash, isWIP bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if r, found := m.txs[hash]; found {
+		r.tx.IsWIP = isWIP
+	}
+	return nil
+}
+
+// GetAllAddressesBlocked get all addresses blocked
+func (m *MemoryPoolStorage) GetAllAddressesBlocked(ctx context.Context) ([]common.Address, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var addrs []common.Address
+	for addr := range m.blocked {
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// AddBlockedAddress blocks the address, replacing the reason if it's already blocked
+func (m *MemoryPoolStorage) AddBlockedAddress(ctx context.Context, address common.Address, reason string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.blocked[address] = reason
+	return nil
+}
+
+// DeleteBlockedAddress unblocks the address
+func (m *MemoryPoolStorage) DeleteBlockedAddress(ctx context.Context, address common.Address) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.blocked, address)
+	return nil
+}
+
+// GetPolicyDenyLists gets the deny lists of the sequencer policy, they are always empty in the memory storage
+func (m *MemoryPoolStorage) GetPolicyDenyLists(ctx context.Context) (pool.PolicyDenyLists, error) {
+	return pool.PolicyDenyLists{}, nil
+}
+
+// AddSelectionAuditEntries stores the entries of the sequencer selection audit log
+func (m *MemoryPoolStorage) AddSelectionAuditEntries(ctx context.Context, entries []pool.SelectionAuditEntry) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.selectionAudit = append(m.selectionAudit, entries...)
+	return nil
+}
+
+// DeleteSelectionAuditOlderThan deletes the entries of the sequencer selection audit log older than the given date
+func (m *MemoryPoolStorage) DeleteSelectionAuditOlderThan(ctx context.Context, date time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entries := make([]pool.SelectionAuditEntry, 0, len(m.selectionAudit))
+	for _, entry := range m.selectionAudit {
+		if !entry.SelectedAt.Before(date) {
+			entries = append(entries, entry)
+		}
+	}
+	m.selectionAudit = entries
+	return nil
+}
+
+// filter returns the records matching the condition sorted by the time they were received,
+// the caller must hold the mutex
+func (m *MemoryPoolStorage) filter(match func(r *txRecord) bool) []*txRecord {
+	records := make([]*txRecord, 0)
+	for _, r := range m.txs {
+		if match(r) {
+			records = append(records, r)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].tx.ReceivedAt.Before(records[j].tx.ReceivedAt)
+	})
+	return records
+}
+
+func isDropped(status pool.TxStatus) bool {
+	return status == pool.TxStatusFailed || status == pool.TxStatusInvalid || status == pool.TxStatusExpired
+}
+
+func hasStatus(r *txRecord, status []pool.TxStatus) bool {
+	for _, s := range status {
+		if r.tx.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func toTxs(records []*txRecord) []pool.Transaction {
+	txs := make([]pool.Transaction, 0, len(records))
+	for _, r := range records {
+		txs = append(txs, r.tx)
+	}
+	return txs
+}
@@ -0,0 +1,132 @@
+package memorypoolstorage
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryPoolStorageTxs(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryPoolStorage()
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+	signer := types.NewEIP155Signer(big.NewInt(1000))
+	newTx := func(nonce uint64, gasPrice int64) pool.Transaction {
+		to := common.HexToAddress("0x1")
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: nonce, To: &to, Value: big.NewInt(0), Gas: 21000, GasPrice: big.NewInt(gasPrice)}), signer, privateKey)
+		require.NoError(t, err)
+		return *pool.NewTransaction(*tx, "", false)
+	}
+
+	tx0, tx1, tx2 := newTx(0, 1), newTx(1, 3), newTx(2, 2)
+	for _, tx := range []pool.Transaction{tx0, tx1, tx2} {
+		require.NoError(t, s.AddTx(ctx, tx))
+	}
+
+	nonce, err := s.GetNonce(ctx, from)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+	nonce, err = s.GetNonce(ctx, common.HexToAddress("0x2"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), nonce)
+
+	txs, err := s.GetTxsByStatus(ctx, pool.TxStatusPending, 2)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, tx1.Hash(), txs[0].Hash(), "sorted by gas price")
+	assert.Equal(t, tx2.Hash(), txs[1].Hash())
+
+	addr, txNonce, err := s.GetTxFromAddressFromByHash(ctx, tx1.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, from, addr)
+	assert.Equal(t, uint64(1), txNonce)
+	_, _, err = s.GetTxFromAddressFromByHash(ctx, common.HexToHash("0x1"))
+	assert.ErrorIs(t, err, pool.ErrNotFound)
+
+	dropped, cursor, err := s.GetDroppedTxsSince(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, dropped)
+	failedReason := "reverted"
+	require.NoError(t, s.UpdateTxStatus(ctx, pool.TxStatusUpdateInfo{Hash: tx2.Hash(), NewStatus: pool.TxStatusFailed, FailedReason: &failedReason}))
+	dropped, cursor, err = s.GetDroppedTxsSince(ctx, cursor)
+	require.NoError(t, err)
+	require.Len(t, dropped, 1)
+	assert.Equal(t, tx2.Hash(), dropped[0].Hash)
+	assert.Equal(t, from, dropped[0].From)
+	assert.Equal(t, pool.TxStatusFailed, dropped[0].Status)
+	assert.Equal(t, failedReason, dropped[0].Reason)
+	dropped, _, err = s.GetDroppedTxsSince(ctx, cursor)
+	require.NoError(t, err)
+	assert.Empty(t, dropped, "the cursor skips the txs already returned")
+
+	count, err := s.CountTransactionsByFromAndStatus(ctx, from, pool.TxStatusPending)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+	nonces, err := s.GetNoncesByFromAndStatus(ctx, from, pool.TxStatusPending, pool.TxStatusFailed)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0, 1, 2}, nonces)
+
+	require.NoError(t, s.UpdateTxWIPStatus(ctx, tx0.Hash(), true))
+	wip, err := s.GetWIPPendingTxs(ctx)
+	require.NoError(t, err)
+	require.Len(t, wip, 1)
+	assert.Equal(t, tx0.Hash(), wip[0].Hash())
+	require.NoError(t, s.MarkWIPTxsAsPending(ctx))
+	nonWIP, err := s.GetNonWIPPendingTxs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, nonWIP, 2)
+
+	require.NoError(t, s.DeleteTransactionsByHashes(ctx, []common.Hash{tx0.Hash(), tx2.Hash()}))
+	_, err = s.GetTxByHash(ctx, tx0.Hash())
+	assert.ErrorIs(t, err, pool.ErrNotFound)
+	dropped, _, err = s.GetDroppedTxsSince(ctx, cursor)
+	require.NoError(t, err)
+	require.Len(t, dropped, 1, "the failed tx was already notified")
+	assert.Equal(t, tx0.Hash(), dropped[0].Hash)
+	assert.Equal(t, pool.TxStatusDeleted, dropped[0].Status)
+	pending, err := s.IsTxPending(ctx, tx1.Hash())
+	require.NoError(t, err)
+	assert.True(t, pending)
+}
+
+func TestMemoryPoolStorageGasPrices(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryPoolStorage()
+
+	l2GasPrice, l1GasPrice, err := s.GetGasPrices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), l2GasPrice)
+	assert.Equal(t, uint64(0), l1GasPrice)
+	_, err = s.MinL2GasPriceSince(ctx, time.Now().Add(-time.Minute))
+	assert.ErrorIs(t, err, state.ErrNotFound)
+
+	require.NoError(t, s.SetGasPrices(ctx, 20, 200))
+	require.NoError(t, s.SetGasPrices(ctx, 10, 100))
+	require.NoError(t, s.SetGasPrices(ctx, 30, 300))
+
+	l2GasPrice, l1GasPrice, err = s.GetGasPrices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(30), l2GasPrice)
+	assert.Equal(t, uint64(300), l1GasPrice)
+	minGasPrice, err := s.MinL2GasPriceSince(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), minGasPrice)
+
+	// the last gas price is kept
+	require.NoError(t, s.DeleteGasPricesHistoryOlderThan(ctx, time.Now().Add(time.Minute)))
+	minGasPrice, err = s.MinL2GasPriceSince(ctx, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(30), minGasPrice)
+}
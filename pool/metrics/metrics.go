@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	prefix                = "pool_"
+	txPrefix              = prefix + "transaction_"
+	txReceivedName        = txPrefix + "received"
+	txDuplicatedName      = txPrefix + "duplicated"
+	txDuplicatedLabelName = "source"
+
+	signatureValidationPrefix          = prefix + "signature_validation_"
+	signatureValidatedName             = signatureValidationPrefix + "validated"
+	signatureValidatedLabelName        = "result"
+	signatureValidationTimeName        = signatureValidationPrefix + "time"
+	signatureValidationQueuedTimeName  = signatureValidationPrefix + "queued_time"
+	signatureValidationQueueLengthName = signatureValidationPrefix + "queue_length"
+)
+
+// TxDuplicatedLabel represents the possible values for the
+// `pool_transaction_duplicated` metric `source` label.
+type TxDuplicatedLabel string
+
+const (
+	// TxDuplicatedLabelCache represents a duplicated tx detected by the in-memory cache
+	TxDuplicatedLabelCache TxDuplicatedLabel = "cache"
+	// TxDuplicatedLabelStorage represents a duplicated tx detected by the storage
+	TxDuplicatedLabelStorage TxDuplicatedLabel = "storage"
+)
+
+// SignatureValidatedLabel represents the possible values for the
+// `pool_signature_validation_validated` metric `result` label.
+type SignatureValidatedLabel string
+
+const (
+	// SignatureValidatedLabelValid represents a tx with a valid signature and chain ID
+	SignatureValidatedLabelValid SignatureValidatedLabel = "valid"
+	// SignatureValidatedLabelInvalid represents a tx with an invalid signature or chain ID
+	SignatureValidatedLabelInvalid SignatureValidatedLabel = "invalid"
+)
+
+// Register the metrics for the pool package.
+func Register() {
+	var (
+		counters    []prometheus.CounterOpts
+		counterVecs []metrics.CounterVecOpts
+	)
+
+	counters = []prometheus.CounterOpts{
+		{
+			Name: txReceivedName,
+			Help: "[POOL] number of transactions received by the pool",
+		},
+	}
+
+	counterVecs = []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: txDuplicatedName,
+				Help: "[POOL] number of transactions rejected because they were already known",
+			},
+			Labels: []string{txDuplicatedLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: signatureValidatedName,
+				Help: "[POOL] number of transactions whose signature and chain ID were validated",
+			},
+			Labels: []string{signatureValidatedLabelName},
+		},
+	}
+
+	histograms := []prometheus.HistogramOpts{
+		{
+			Name: signatureValidationTimeName,
+			Help: "[POOL] time to validate the signature and chain ID of a transaction",
+		},
+		{
+			Name: signatureValidationQueuedTimeName,
+			Help: "[POOL] time a transaction waits in the queue to validate its signature",
+		},
+	}
+
+	gauges := []prometheus.GaugeOpts{
+		{
+			Name: signatureValidationQueueLengthName,
+			Help: "[POOL] number of transactions waiting to validate their signature",
+		},
+	}
+
+	metrics.RegisterCounters(counters...)
+	metrics.RegisterCounterVecs(counterVecs...)
+	metrics.RegisterHistograms(histograms...)
+	metrics.RegisterGauges(gauges...)
+}
+
+// TxReceived increments the transactions received counter by one.
+func TxReceived() {
+	metrics.CounterInc(txReceivedName)
+}
+
+// TxDuplicated increments the duplicated transactions counter vector by one
+// for the given label.
+func TxDuplicated(label TxDuplicatedLabel) {
+	metrics.CounterVecInc(txDuplicatedName, string(label))
+}
+
+// SignatureValidated increments the validated signatures counter vector by one
+// for the given label.
+func SignatureValidated(label SignatureValidatedLabel) {
+	metrics.CounterVecInc(signatureValidatedName, string(label))
+}
+
+// SignatureValidationTime observes the time to validate the signature of a transaction.
+func SignatureValidationTime(duration time.Duration) {
+	metrics.HistogramObserve(signatureValidationTimeName, duration.Seconds())
+}
+
+// SignatureValidationQueuedTime observes the time a transaction waited in the
+// queue to validate its signature.
+func SignatureValidationQueuedTime(duration time.Duration) {
+	metrics.HistogramObserve(signatureValidationQueuedTimeName, duration.Seconds())
+}
+
+// SignatureValidationQueueLength sets the gauge for the number of transactions
+// waiting to validate their signature.
+func SignatureValidationQueueLength(length int) {
+	metrics.GaugeSet(signatureValidationQueueLengthName, float64(length))
+}
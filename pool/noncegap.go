@@ -0,0 +1,111 @@
+package pool
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// NonceGapResolutionTx is a tx suggested to resolve a nonce gap of an account, it's a
+// transfer of 0 to the account itself that only consumes the nonce
+type NonceGapResolutionTx struct {
+	Nonce    uint64
+	Gas      uint64
+	GasPrice *big.Int
+	// Replaces is the hash of the pending tx replaced by the suggested one, if any
+	Replaces *common.Hash
+}
+
+// NonceGapResolution are the txs suggested to unblock or cancel the pending txs of an
+// account that can't be executed because of a nonce gap
+type NonceGapResolution struct {
+	// NextNonce is the nonce after the pending txs of the account that can be executed
+	NextNonce uint64
+	// GappedNonces are the nonces of the pending txs that can't be executed
+	GappedNonces []uint64
+	// Fill are the txs filling the missing nonces, sending them unblocks the gapped txs
+	Fill []NonceGapResolutionTx
+	// Cancel are the txs replacing the gapped txs, sending them along with the fill txs
+	// cancels the gapped txs
+	Cancel []NonceGapResolutionTx
+}
+
+// SuggestNonceGapResolution analyzes the pending txs of the account after the current nonce of
+// the state, suggesting the txs needed to fill the nonce gaps and to cancel the gapped txs
+func (p *Pool) SuggestNonceGapResolution(ctx context.Context, address common.Address, currentNonce uint64) (*NonceGapResolution, error) {
+	nonces, err := p.Storage.GetNoncesByFromAndStatus(ctx, address, TxStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	gapped, nextNonce := nonceGappedNonces(currentNonce, nonces)
+	resolution := &NonceGapResolution{
+		NextNonce:    nextNonce,
+		GappedNonces: gapped,
+		Fill:         []NonceGapResolutionTx{},
+		Cancel:       []NonceGapResolutionTx{},
+	}
+	if len(gapped) == 0 {
+		return resolution, nil
+	}
+
+	gasPrice := p.suggestedGasPrice()
+	for nonce := nextNonce; nonce < gapped[len(gapped)-1]; nonce++ {
+		if containsNonce(gapped, nonce) {
+			continue
+		}
+		resolution.Fill = append(resolution.Fill, NonceGapResolutionTx{
+			Nonce:    nonce,
+			Gas:      params.TxGas,
+			GasPrice: gasPrice,
+		})
+	}
+
+	for _, nonce := range gapped {
+		txs, err := p.Storage.GetTxsByFromAndNonce(ctx, address, nonce)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			if tx.Status != TxStatusPending {
+				continue
+			}
+			hash := tx.Hash()
+			resolution.Cancel = append(resolution.Cancel, NonceGapResolutionTx{
+				Nonce:    nonce,
+				Gas:      params.TxGas,
+				GasPrice: cancelGasPrice(state.GetTxGasPrice(tx.Transaction), tx.Gas(), gasPrice, p.cfg.PriceBump),
+				Replaces: &hash,
+			})
+		}
+	}
+	return resolution, nil
+}
+
+// suggestedGasPrice returns the gas price suggested for the resolution txs, the highest of the
+// current L2 gas price and the min gas price accepted by the pool
+func (p *Pool) suggestedGasPrice() *big.Int {
+	gasPrice := p.GetMinSuggestedGasPrice()
+	_, l2GasPrice := p.GetL1AndL2GasPrice()
+	if l2 := new(big.Int).SetUint64(l2GasPrice); l2.Cmp(gasPrice) > 0 {
+		gasPrice = l2
+	}
+	return gasPrice
+}
+
+// cancelGasPrice returns the gas price of a tx with TxGas replacing a tx with the given gas price and gas,
+// the replacement must pay at least the same as the replaced tx and bump its gas price by priceBump percent
+// to be accepted by validateTx
+func cancelGasPrice(replacedGasPrice *big.Int, replacedGas uint64, minGasPrice *big.Int, priceBump uint64) *big.Int {
+	gasPrice := new(big.Int).Mul(replacedGasPrice, new(big.Int).SetUint64(replacedGas))
+	gasPrice = gasPrice.Add(gasPrice, new(big.Int).SetUint64(params.TxGas-1)).Div(gasPrice, new(big.Int).SetUint64(params.TxGas))
+	if bumped := new(big.Int).Mul(replacedGasPrice, new(big.Int).SetUint64(100+priceBump)); bumped.Div(bumped, big.NewInt(100)).Cmp(gasPrice) > 0 { //nolint:gomnd
+		gasPrice = bumped
+	}
+	if gasPrice.Cmp(minGasPrice) < 0 {
+		gasPrice = new(big.Int).Set(minGasPrice)
+	}
+	return gasPrice
+}
@@ -0,0 +1,33 @@
+package pool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelGasPrice(t *testing.T) {
+	minGasPrice := big.NewInt(1000)
+	tests := []struct {
+		name             string
+		replacedGasPrice int64
+		replacedGas      uint64
+		priceBump        uint64
+		expected         int64
+	}{
+		{"plain transfer is bumped", 2000, 21000, 10, 2200},
+		{"plain transfer without price bump", 2000, 21000, 0, 2000},
+		{"total cost of a tx with more gas", 2000, 42000, 10, 4000},
+		{"min gas price", 500, 21000, 10, 1000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			replacedGasPrice := big.NewInt(tc.replacedGasPrice)
+			gasPrice := cancelGasPrice(replacedGasPrice, tc.replacedGas, minGasPrice, tc.priceBump)
+			assert.Equal(t, big.NewInt(tc.expected), gasPrice)
+			assert.False(t, IsReplacementUnderpriced(replacedGasPrice, gasPrice, tc.priceBump))
+			assert.Equal(t, big.NewInt(1000), minGasPrice, "the min gas price is not modified")
+		})
+	}
+}
@@ -3,11 +3,13 @@ package pgpoolstorage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/db"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
@@ -20,6 +22,8 @@ import (
 // that uses a postgres database to store the data
 type PostgresPoolStorage struct {
 	db *pgxpool.Pool
+	// readRouter routes the queries serving the RPC to the read replica
+	readRouter *db.ReadRouter
 }
 
 // NewPostgresPoolStorage creates and initializes an instance of PostgresPoolStorage
@@ -28,9 +32,15 @@ func NewPostgresPoolStorage(cfg db.Config) (*PostgresPoolStorage, error) {
 	if err != nil {
 		return nil, err
 	}
+	readRouter, err := db.NewReadRouter(cfg, poolDB)
+	if err != nil {
+		return nil, err
+	}
+	go readRouter.Start(context.Background())
 
 	return &PostgresPoolStorage{
-		db: poolDB,
+		db:         poolDB,
+		readRouter: readRouter,
 	}, nil
 }
 
@@ -74,10 +84,13 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 			from_address,
 			is_wip,
 			ip,
-			failed_reason
+			failed_reason,
+			conditions,
+			tag,
+			sponsored
 		) 
 		VALUES 
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NULL)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NULL, $19, $20, $21)
 			ON CONFLICT (hash) DO UPDATE SET 
 			encoded = $2,
 			decoded = $3,
@@ -96,7 +109,10 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 			from_address = $16,
 			is_wip = $17,
 			ip = $18,
-			failed_reason = NULL
+			failed_reason = NULL,
+			conditions = $19,
+			tag = $20,
+			sponsored = $21
 	`
 
 	// Get FromAddress from the JSON data
@@ -106,6 +122,19 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 	}
 	fromAddress := data.String()
 
+	var conditions []byte
+	if tx.Conditions != nil {
+		conditions, err = json.Marshal(tx.Conditions)
+		if err != nil {
+			return err
+		}
+	}
+
+	var tag *string
+	if tx.Tag != "" {
+		tag = &tx.Tag
+	}
+
 	if _, err := p.db.Exec(ctx, sql,
 		hash,
 		encoded,
@@ -124,7 +153,10 @@ func (p *PostgresPoolStorage) AddTx(ctx context.Context, tx pool.Transaction) er
 		tx.ReceivedAt,
 		fromAddress,
 		tx.IsWIP,
-		tx.IP); err != nil {
+		tx.IP,
+		conditions,
+		tag,
+		tx.Sponsored); err != nil {
 		return err
 	}
 	return nil
@@ -141,11 +173,11 @@ func (p *PostgresPoolStorage) GetTxsByStatus(ctx context.Context, status pool.Tx
 	)
 	if limit == 0 {
 		sql = `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, used_poseidon_paddings, used_mem_aligns,
-				used_arithmetics, used_binaries, used_steps, failed_reason FROM pool.transaction WHERE status = $1 ORDER BY gas_price DESC`
+				used_arithmetics, used_binaries, used_steps, failed_reason, conditions, tag, sponsored FROM pool.transaction WHERE status = $1 ORDER BY gas_price DESC`
 		rows, err = p.db.Query(ctx, sql, status.String())
 	} else {
 		sql = `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, used_poseidon_paddings, used_mem_aligns,
-				used_arithmetics, used_binaries, used_steps, failed_reason FROM pool.transaction WHERE status = $1 ORDER BY gas_price DESC LIMIT $2`
+				used_arithmetics, used_binaries, used_steps, failed_reason, conditions, tag, sponsored FROM pool.transaction WHERE status = $1 ORDER BY gas_price DESC LIMIT $2`
 		rows, err = p.db.Query(ctx, sql, status.String(), limit)
 	}
 	if err != nil {
@@ -174,7 +206,7 @@ func (p *PostgresPoolStorage) GetNonWIPPendingTxs(ctx context.Context) ([]pool.T
 	)
 
 	sql = `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, used_poseidon_paddings, used_mem_aligns,
-		used_arithmetics, used_binaries, used_steps, failed_reason FROM pool.transaction WHERE is_wip IS FALSE and status = $1`
+		used_arithmetics, used_binaries, used_steps, failed_reason, conditions, tag, sponsored FROM pool.transaction WHERE is_wip IS FALSE and status = $1`
 	rows, err = p.db.Query(ctx, sql, pool.TxStatusPending)
 
 	if err != nil {
@@ -194,10 +226,34 @@ func (p *PostgresPoolStorage) GetNonWIPPendingTxs(ctx context.Context) ([]pool.T
 	return txs, nil
 }
 
+// GetWIPPendingTxs returns the pending transactions marked as WIP, sorted by
+// sender and nonce
+func (p *PostgresPoolStorage) GetWIPPendingTxs(ctx context.Context) ([]pool.Transaction, error) {
+	sql := `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, used_poseidon_paddings, used_mem_aligns,
+		used_arithmetics, used_binaries, used_steps, failed_reason, conditions, tag, sponsored FROM pool.transaction WHERE is_wip IS TRUE and status = $1
+		ORDER BY from_address, nonce`
+	rows, err := p.db.Query(ctx, sql, pool.TxStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txs := make([]pool.Transaction, 0, len(rows.RawValues()))
+	for rows.Next() {
+		tx, err := scanTx(rows)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, *tx)
+	}
+
+	return txs, nil
+}
+
 // GetPendingTxHashesSince returns the pending tx since the given time.
 func (p *PostgresPoolStorage) GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error) {
 	sql := "SELECT hash FROM pool.transaction WHERE status = $1 AND received_at >= $2"
-	rows, err := p.db.Query(ctx, sql, pool.TxStatusPending, since)
+	rows, err := p.readRouter.Reader().Query(ctx, sql, pool.TxStatusPending, since)
 	if err != nil {
 		return nil, err
 	}
@@ -215,6 +271,64 @@ func (p *PostgresPoolStorage) GetPendingTxHashesSince(ctx context.Context, since
 	return hashes, nil
 }
 
+// GetDroppedTxsSince returns the txs that have been dropped (failed, invalid, expired or deleted)
+// since the given cursor and the cursor to use in the next call. The events are read from the
+// primary and the cursor is the xmin of the snapshot, so the events of the transactions that
+// are still in progress are returned once they commit instead of being skipped. A zero cursor
+// only returns the current cursor
+func (p *PostgresPoolStorage) GetDroppedTxsSince(ctx context.Context, cursor uint64) ([]pool.DroppedTx, uint64, error) {
+	const getCursorSQL = "SELECT pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT"
+	var nextCursor uint64
+	if err := p.db.QueryRow(ctx, getCursorSQL).Scan(&nextCursor); err != nil {
+		return nil, 0, err
+	}
+	if cursor == 0 {
+		return nil, nextCursor, nil
+	}
+	if cursor >= nextCursor {
+		return nil, cursor, nil
+	}
+
+	const getDroppedTxsSQL = `SELECT hash, from_address, nonce, status, reason, dropped_at
+	                            FROM pool.dropped_tx
+	                           WHERE xid >= $1 AND xid < $2
+	                        ORDER BY xid, id`
+	rows, err := p.db.Query(ctx, getDroppedTxsSQL, cursor, nextCursor)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var droppedTxs []pool.DroppedTx
+	for rows.Next() {
+		var (
+			hash, from, status string
+			nonce              uint64
+			reason             *string
+			droppedAt          time.Time
+		)
+		if err := rows.Scan(&hash, &from, &nonce, &status, &reason, &droppedAt); err != nil {
+			return nil, 0, err
+		}
+		droppedTx := pool.DroppedTx{
+			Hash:      common.HexToHash(hash),
+			From:      common.HexToAddress(from),
+			Nonce:     nonce,
+			Status:    pool.TxStatus(status),
+			DroppedAt: droppedAt,
+		}
+		if reason != nil {
+			droppedTx.Reason = *reason
+		}
+		droppedTxs = append(droppedTxs, droppedTx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return droppedTxs, nextCursor, nil
+}
+
 // GetTxs gets txs with the lowest nonce
 func (p *PostgresPoolStorage) GetTxs(ctx context.Context, filterStatus pool.TxStatus, minGasPrice, limit uint64) ([]*pool.Transaction, error) {
 	query := `
@@ -368,10 +482,34 @@ func (p *PostgresPoolStorage) CountTransactionsByFromAndStatus(ctx context.Conte
 	return counter, nil
 }
 
+// GetNoncesByFromAndStatus gets the distinct nonces of the transactions
+// of the from address with the provided statuses sorted in ascending order
+func (p *PostgresPoolStorage) GetNoncesByFromAndStatus(ctx context.Context, from common.Address, status ...pool.TxStatus) ([]uint64, error) {
+	sql := "SELECT DISTINCT nonce FROM pool.transaction WHERE from_address = $1 AND status = ANY ($2) ORDER BY nonce"
+	rows, err := p.db.Query(ctx, sql, from.String(), status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nonces []uint64
+	for rows.Next() {
+		var nonce uint64
+		if err := rows.Scan(&nonce); err != nil {
+			return nil, err
+		}
+		nonces = append(nonces, nonce)
+	}
+
+	return nonces, rows.Err()
+}
+
 // UpdateTxStatus updates a transaction status accordingly to the
 // provided status and hash
 func (p *PostgresPoolStorage) UpdateTxStatus(ctx context.Context, updateInfo pool.TxStatusUpdateInfo) error {
-	sql := "UPDATE pool.transaction SET status = $1, is_wip = $2"
+	sql := "UPDATE pool.transaction SET status = $1, is_wip = $2, status_updated_at = NOW()"
 	args := []interface{}{updateInfo.NewStatus, updateInfo.IsWIP}
 
 	if updateInfo.FailedReason != nil {
@@ -416,9 +554,15 @@ func (p *PostgresPoolStorage) DeleteTransactionsByHashes(ctx context.Context, ha
 }
 
 // DeleteFailedTransactionsOlderThan deletes all failed transactions older than the given date
+// and the dropped txs notified before that date
 func (p *PostgresPoolStorage) DeleteFailedTransactionsOlderThan(ctx context.Context, date time.Time) error {
 	sql := `DELETE FROM pool.transaction WHERE status = 'failed' and received_at < $1`
 
+	if _, err := p.db.Exec(ctx, sql, date); err != nil {
+		return err
+	}
+
+	sql = `DELETE FROM pool.dropped_tx WHERE dropped_at < $1`
 	if _, err := p.db.Exec(ctx, sql, date); err != nil {
 		return err
 	}
@@ -504,7 +648,7 @@ func (p *PostgresPoolStorage) IsTxPending(ctx context.Context, hash common.Hash)
 // GetTxsByFromAndNonce get all the transactions from the pool with the same from and nonce
 func (p *PostgresPoolStorage) GetTxsByFromAndNonce(ctx context.Context, from common.Address, nonce uint64) ([]pool.Transaction, error) {
 	sql := `SELECT encoded, status, received_at, is_wip, ip, cumulative_gas_used, used_keccak_hashes, used_poseidon_hashes, 
-				   used_poseidon_paddings, used_mem_aligns,	used_arithmetics, used_binaries, used_steps, failed_reason
+				   used_poseidon_paddings, used_mem_aligns,	used_arithmetics, used_binaries, used_steps, failed_reason, conditions, tag, sponsored
 	          FROM pool.transaction
 			 WHERE from_address = $1
 			   AND nonce = $2`
@@ -593,7 +737,7 @@ func (p *PostgresPoolStorage) GetTxByHash(ctx context.Context, hash common.Hash)
 	sql := `SELECT encoded, status, received_at, is_wip, ip
 	          FROM pool.transaction
 			 WHERE hash = $1`
-	err := p.db.QueryRow(ctx, sql, hash.String()).Scan(&encoded, &status, &receivedAt, &isWIP, &ip)
+	err := p.readRouter.Reader().QueryRow(ctx, sql, hash.String()).Scan(&encoded, &status, &receivedAt, &isWIP, &ip)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, pool.ErrNotFound
 	} else if err != nil {
@@ -635,10 +779,13 @@ func scanTx(rows pgx.Rows) (*pool.Transaction, error) {
 		usedBinaries         uint32
 		usedSteps            uint32
 		failedReason         *string
+		conditions           []byte
+		tag                  *string
+		sponsored            bool
 	)
 
 	if err := rows.Scan(&encoded, &status, &receivedAt, &isWIP, &ip, &cumulativeGasUsed, &usedKeccakHashes, &usedPoseidonHashes,
-		&usedPoseidonPaddings, &usedMemAligns, &usedArithmetics, &usedBinaries, &usedSteps, &failedReason); err != nil {
+		&usedPoseidonPaddings, &usedMemAligns, &usedArithmetics, &usedBinaries, &usedSteps, &failedReason, &conditions, &tag, &sponsored); err != nil {
 		return nil, err
 	}
 
@@ -666,6 +813,16 @@ func scanTx(rows pgx.Rows) (*pool.Transaction, error) {
 	tx.ZKCounters.UsedBinaries = usedBinaries
 	tx.ZKCounters.UsedSteps = usedSteps
 	tx.FailedReason = failedReason
+	if len(conditions) > 0 {
+		tx.Conditions = new(pool.TxConditions)
+		if err := json.Unmarshal(conditions, tx.Conditions); err != nil {
+			return nil, err
+		}
+	}
+	if tag != nil {
+		tx.Tag = *tag
+	}
+	tx.Sponsored = sponsored
 
 	return tx, nil
 }
@@ -697,6 +854,15 @@ func (p *PostgresPoolStorage) GetTxZkCountersByHash(ctx context.Context, hash co
 	return &zkCounters, nil
 }
 
+// UpdateTxZKCounters updates the zkcounters of a transaction with the ones used when it was executed
+func (p *PostgresPoolStorage) UpdateTxZKCounters(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) error {
+	sql := `UPDATE pool.transaction SET cumulative_gas_used = $2, used_keccak_hashes = $3, used_poseidon_hashes = $4, used_poseidon_paddings = $5,
+			used_mem_aligns = $6, used_arithmetics = $7, used_binaries = $8, used_steps = $9 WHERE hash = $1`
+	_, err := p.db.Exec(ctx, sql, hash.String(), zkCounters.CumulativeGasUsed, zkCounters.UsedKeccakHashes, zkCounters.UsedPoseidonHashes,
+		zkCounters.UsedPoseidonPaddings, zkCounters.UsedMemAligns, zkCounters.UsedArithmetics, zkCounters.UsedBinaries, zkCounters.UsedSteps)
+	return err
+}
+
 // MarkWIPTxsAsPending updates WIP status to non WIP
 func (p *PostgresPoolStorage) MarkWIPTxsAsPending(ctx context.Context) error {
 	const query = `UPDATE pool.transaction SET is_wip = false WHERE is_wip = true`
@@ -742,3 +908,95 @@ func (p *PostgresPoolStorage) GetAllAddressesBlocked(ctx context.Context) ([]com
 
 	return addrs, nil
 }
+
+// AddBlockedAddress blocks the address, replacing the reason if it's already blocked
+func (p *PostgresPoolStorage) AddBlockedAddress(ctx context.Context, address common.Address, reason string) error {
+	sql := `INSERT INTO pool.blocked (addr, block_reason) VALUES ($1, $2)
+		ON CONFLICT (addr) DO UPDATE SET block_reason = EXCLUDED.block_reason`
+	_, err := p.db.Exec(ctx, sql, address.String(), reason)
+	return err
+}
+
+// DeleteBlockedAddress unblocks the address
+func (p *PostgresPoolStorage) DeleteBlockedAddress(ctx context.Context, address common.Address) error {
+	sql := `DELETE FROM pool.blocked WHERE addr = $1`
+	_, err := p.db.Exec(ctx, sql, address.String())
+	return err
+}
+
+// GetPolicyDenyLists gets the deny lists of the sequencer policy
+func (p *PostgresPoolStorage) GetPolicyDenyLists(ctx context.Context) (pool.PolicyDenyLists, error) {
+	sql := `SELECT kind, value FROM pool.policy_deny_list`
+
+	lists := pool.PolicyDenyLists{}
+	rows, err := p.db.Query(ctx, sql)
+	if err != nil {
+		return lists, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind, value string
+		if err := rows.Scan(&kind, &value); err != nil {
+			return lists, err
+		}
+		switch pool.PolicyDenyKind(kind) {
+		case pool.PolicyDenyKindSender:
+			lists.Senders = append(lists.Senders, common.HexToAddress(value))
+		case pool.PolicyDenyKindRecipient:
+			lists.Recipients = append(lists.Recipients, common.HexToAddress(value))
+		case pool.PolicyDenyKindSelector:
+			selector := common.FromHex(value)
+			if len(selector) != len([4]byte{}) {
+				log.Warnf("ignoring invalid selector %s of the policy deny list", value)
+				continue
+			}
+			lists.Selectors = append(lists.Selectors, [4]byte(selector))
+		default:
+			log.Warnf("ignoring unknown kind %s of the policy deny list", kind)
+		}
+	}
+
+	return lists, rows.Err()
+}
+
+// AddSelectionAuditEntries stores the entries of the sequencer selection audit log
+func (p *PostgresPoolStorage) AddSelectionAuditEntries(ctx context.Context, entries []pool.SelectionAuditEntry) error {
+	const sql = `
+		INSERT INTO pool.selection_audit
+		(round, position, hash, from_address, nonce, score, remaining_resources, decision, reason, selected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	batch := &pgx.Batch{}
+	for _, entry := range entries {
+		remainingResources, err := json.Marshal(entry.RemainingResources)
+		if err != nil {
+			return err
+		}
+		score := "0"
+		if entry.Score != nil {
+			score = entry.Score.String()
+		}
+		batch.Queue(sql, entry.Round, entry.Position, entry.Hash.Hex(), entry.From.Hex(), entry.Nonce,
+			score, remainingResources, entry.Decision.String(), entry.Reason, entry.SelectedAt)
+	}
+
+	results := p.db.SendBatch(ctx, batch)
+	for range entries {
+		if _, err := results.Exec(); err != nil {
+			_ = results.Close()
+			return err
+		}
+	}
+	return results.Close()
+}
+
+// DeleteSelectionAuditOlderThan deletes the entries of the sequencer selection audit log older than the given date
+func (p *PostgresPoolStorage) DeleteSelectionAuditOlderThan(ctx context.Context, date time.Time) error {
+	sql := `DELETE FROM pool.selection_audit WHERE selected_at < $1`
+
+	if _, err := p.db.Exec(ctx, sql, date); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package pool
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// PolicyDenyKindSender represents a denied tx sender
+	PolicyDenyKindSender PolicyDenyKind = "sender"
+	// PolicyDenyKindRecipient represents a denied tx recipient
+	PolicyDenyKindRecipient PolicyDenyKind = "recipient"
+	// PolicyDenyKindSelector represents a denied 4-byte function selector
+	PolicyDenyKindSelector PolicyDenyKind = "selector"
+)
+
+// PolicyDenyKind represents the kind of the values of the sequencer policy deny lists
+type PolicyDenyKind string
+
+// String returns a representation of the deny kind in a string format
+func (k PolicyDenyKind) String() string {
+	return string(k)
+}
+
+// PolicyDenyLists are the senders, recipients and 4-byte function selectors whose txs
+// are rejected by the sequencer policy
+type PolicyDenyLists struct {
+	Senders    []common.Address
+	Recipients []common.Address
+	Selectors  [][4]byte
+}
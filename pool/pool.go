@@ -5,11 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/clock"
 	"github.com/0xPolygonHermez/zkevm-node/event"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
@@ -37,7 +41,7 @@ var (
 // Pool is an implementation of the Pool interface
 // that uses a postgres database to store the data
 type Pool struct {
-	storage
+	Storage
 	state                   stateInterface
 	chainID                 uint64
 	cfg                     Config
@@ -50,6 +54,11 @@ type Pool struct {
 	gasPrices               GasPrices
 	gasPricesMux            *sync.RWMutex
 	effectiveGasPrice       *EffectiveGasPrice
+	knownTxs                *knownTxs
+	sigValidator            *signatureValidator
+	txTagger                *txTagger
+	sponsoredTxs            *sponsoredTxs
+	clock                   clock.Clock
 }
 
 type preExecutionResponse struct {
@@ -58,7 +67,9 @@ type preExecutionResponse struct {
 	isOOC                bool
 	isOOG                bool
 	isReverted           bool
-	txResponse           *state.ProcessTransactionResponse
+	// oocReason describes the counters exceeded when isOOC is set
+	oocReason  string
+	txResponse *state.ProcessTransactionResponse
 }
 
 // GasPrices contains the gas prices for L2 and L1
@@ -68,23 +79,37 @@ type GasPrices struct {
 }
 
 // NewPool creates and initializes an instance of Pool
-func NewPool(cfg Config, batchConstraintsCfg state.BatchConstraintsCfg, s storage, st stateInterface, chainID uint64, eventLog *event.EventLog) *Pool {
-	startTimestamp := time.Now()
+func NewPool(cfg Config, batchConstraintsCfg state.BatchConstraintsCfg, s Storage, st stateInterface, chainID uint64, eventLog *event.EventLog) *Pool {
+	startTimestamp := clock.System.Now()
+	tagger, err := newTxTagger(cfg.TxTags)
+	if err != nil {
+		log.Fatalf("invalid pool tx tags config: %v", err)
+	}
+	sponsored, err := newSponsoredTxs(cfg.SponsoredTxs)
+	if err != nil {
+		log.Fatalf("invalid pool sponsored txs config: %v", err)
+	}
 	p := &Pool{
 		cfg:                     cfg,
 		batchConstraintsCfg:     batchConstraintsCfg,
 		startTimestamp:          startTimestamp,
-		storage:                 s,
+		Storage:                 s,
 		state:                   st,
 		chainID:                 chainID,
 		blockedAddresses:        sync.Map{},
 		minSuggestedGasPriceMux: new(sync.RWMutex),
 		minSuggestedGasPrice:    big.NewInt(int64(cfg.DefaultMinGasPriceAllowed)),
 		eventLog:                eventLog,
+		clock:                   clock.System,
 		gasPrices:               GasPrices{0, 0},
 		gasPricesMux:            new(sync.RWMutex),
 		effectiveGasPrice:       NewEffectiveGasPrice(cfg.EffectiveGasPrice, cfg.DefaultMinGasPriceAllowed),
+		knownTxs:                newKnownTxs(cfg.KnownTxsCacheSize, cfg.KnownTxsCacheTTL.Duration),
+		sigValidator:            newSignatureValidator(cfg.SignatureValidation, chainID),
+		txTagger:                tagger,
+		sponsoredTxs:            sponsored,
 	}
+	metrics.Register()
 	p.refreshGasPrices()
 	go func(cfg *Config, p *Pool) {
 		for {
@@ -98,7 +123,12 @@ func NewPool(cfg Config, batchConstraintsCfg state.BatchConstraintsCfg, s storag
 
 // refresGasPRices refreshes the gas price
 func (p *Pool) refreshGasPrices() {
-	gasPrices, err := p.GetGasPrices(context.Background())
+	var gasPrices GasPrices
+	err := retry.Do(context.Background(), "pool_refresh_gas_prices", p.cfg.Retry, nil, func() error {
+		var err error
+		gasPrices, err = p.GetGasPrices(context.Background())
+		return err
+	})
 	if err != nil {
 		log.Error("failed to load gas prices")
 		return
@@ -124,7 +154,12 @@ func (p *Pool) StartRefreshingBlockedAddressesPeriodically() {
 
 // refreshBlockedAddresses refreshes the list of blocked addresses for the provided instance of pool
 func (p *Pool) refreshBlockedAddresses() {
-	blockedAddresses, err := p.storage.GetAllAddressesBlocked(context.Background())
+	var blockedAddresses []common.Address
+	err := retry.Do(context.Background(), "pool_refresh_blocked_addresses", p.cfg.Retry, nil, func() error {
+		var err error
+		blockedAddresses, err = p.Storage.GetAllAddressesBlocked(context.Background())
+		return err
+	})
 	if err != nil {
 		log.Error("failed to load blocked addresses")
 		return
@@ -170,59 +205,112 @@ func (p *Pool) StartPollingMinSuggestedGasPrice(ctx context.Context) {
 
 // AddTx adds a transaction to the pool with the pending state
 func (p *Pool) AddTx(ctx context.Context, tx types.Transaction, ip string) error {
+	return p.addTx(ctx, tx, ip, TxEndpointSendRawTransaction, nil)
+}
+
+// AddConditionalTx adds a transaction to the pool with the conditions that must
+// be met when it's selected to be included in a batch, if the conditions are nil
+// the tx is added as a regular one
+func (p *Pool) AddConditionalTx(ctx context.Context, tx types.Transaction, ip string, conditions *TxConditions) error {
+	return p.addTx(ctx, tx, ip, TxEndpointSendRawTransactionConditional, conditions)
+}
+
+// addTx validates and adds a transaction received through the endpoint to the pool
+func (p *Pool) addTx(ctx context.Context, tx types.Transaction, ip, endpoint string, conditions *TxConditions) error {
+	metrics.TxReceived()
+
+	// fast path for resubmissions of txs recently accepted by the pool, the hashes are removed
+	// from the cache once the txs leave the pending status or are deleted through this pool, and
+	// they expire after the KnownTxsCacheTTL, so the txs that failed, are invalid, expired or reorged
+	// by another process can be resubmitted and are validated again
+	if p.knownTxs.contains(tx.Hash(), p.clock.Now()) {
+		metrics.TxDuplicated(metrics.TxDuplicatedLabelCache)
+		return ErrAlreadyKnown
+	}
+
 	poolTx := NewTransaction(tx, ip, false)
 	if err := p.validateTx(ctx, *poolTx); err != nil {
+		if errors.Is(err, ErrAlreadyKnown) {
+			metrics.TxDuplicated(metrics.TxDuplicatedLabelStorage)
+			p.knownTxs.add(tx.Hash(), p.clock.Now())
+		}
+		return err
+	}
+
+	if conditions != nil {
+		if err := conditions.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := p.storeTx(ctx, tx, ip, endpoint, false, conditions); err != nil {
 		return err
 	}
+	p.knownTxs.add(tx.Hash(), p.clock.Now())
 
-	return p.StoreTx(ctx, tx, ip, false)
+	return nil
 }
 
 // StoreTx adds a transaction to the pool with the pending state
 func (p *Pool) StoreTx(ctx context.Context, tx types.Transaction, ip string, isWIP bool) error {
-	// Execute transaction to calculate its zkCounters
-	preExecutionResponse, err := p.preExecuteTx(ctx, tx)
-	if errors.Is(err, runtime.ErrIntrinsicInvalidBatchGasLimit) {
-		return ErrGasLimit
-	} else if preExecutionResponse.isExecutorLevelError {
-		// Do not add tx to the pool
-		return err
-	} else if err != nil {
-		log.Errorf("Pre execution error: %v", err)
-		return err
-	}
+	return p.storeTx(ctx, tx, ip, "", isWIP, nil)
+}
 
-	if preExecutionResponse.isOOC {
-		event := &event.Event{
-			ReceivedAt:  time.Now(),
-			IPAddress:   ip,
-			Source:      event.Source_Node,
-			Component:   event.Component_Pool,
-			Level:       event.Level_Warning,
-			EventID:     event.EventID_PreexecutionOOC,
-			Description: tx.Hash().String(),
+func (p *Pool) storeTx(ctx context.Context, tx types.Transaction, ip, endpoint string, isWIP bool, conditions *TxConditions) error {
+	// Execute transaction to calculate its zkCounters
+	var preExecutionResponse preExecutionResponse
+	preExecutionGasUsed := tx.Gas()
+	if !p.cfg.DisablePreExecution {
+		var err error
+		preExecutionResponse, err = p.preExecuteTx(ctx, tx)
+		if errors.Is(err, runtime.ErrIntrinsicInvalidBatchGasLimit) {
+			return ErrGasLimit
+		} else if preExecutionResponse.isExecutorLevelError {
+			// Do not add tx to the pool
+			return err
+		} else if err != nil {
+			log.Errorf("Pre execution error: %v", err)
+			return err
 		}
 
-		err := p.eventLog.LogEvent(ctx, event)
-		if err != nil {
-			log.Errorf("error adding event: %v", err)
-		}
-		// Do not add tx to the pool
-		return ErrOutOfCounters
-	} else if preExecutionResponse.isOOG {
-		event := &event.Event{
-			ReceivedAt:  time.Now(),
-			IPAddress:   ip,
-			Source:      event.Source_Node,
-			Component:   event.Component_Pool,
-			Level:       event.Level_Warning,
-			EventID:     event.EventID_PreexecutionOOG,
-			Description: tx.Hash().String(),
-		}
-
-		err := p.eventLog.LogEvent(ctx, event)
-		if err != nil {
-			log.Errorf("error adding event: %v", err)
+		if preExecutionResponse.isOOC {
+			event := &event.Event{
+				ReceivedAt:  p.clock.Now(),
+				IPAddress:   ip,
+				Source:      event.Source_Node,
+				Component:   event.Component_Pool,
+				Level:       event.Level_Warning,
+				EventID:     event.EventID_PreexecutionOOC,
+				Description: tx.Hash().String(),
+			}
+
+			err := p.eventLog.LogEvent(ctx, event)
+			if err != nil {
+				log.Errorf("error adding event: %v", err)
+			}
+			// Do not add tx to the pool
+			if preExecutionResponse.oocReason != "" {
+				return fmt.Errorf("%w: %s", ErrOutOfCounters, preExecutionResponse.oocReason)
+			}
+			return ErrOutOfCounters
+		} else if preExecutionResponse.isOOG {
+			event := &event.Event{
+				ReceivedAt:  p.clock.Now(),
+				IPAddress:   ip,
+				Source:      event.Source_Node,
+				Component:   event.Component_Pool,
+				Level:       event.Level_Warning,
+				EventID:     event.EventID_PreexecutionOOG,
+				Description: tx.Hash().String(),
+			}
+
+			err := p.eventLog.LogEvent(ctx, event)
+			if err != nil {
+				log.Errorf("error adding event: %v", err)
+			}
+		}
+		if preExecutionResponse.txResponse != nil {
+			preExecutionGasUsed = preExecutionResponse.txResponse.GasUsed
 		}
 	}
 
@@ -231,20 +319,35 @@ func (p *Pool) StoreTx(ctx context.Context, tx types.Transaction, ip string, isW
 		return err
 	}
 
-	err = p.ValidateBreakEvenGasPrice(ctx, tx, preExecutionResponse.txResponse.GasUsed, gasPrices)
-	if err != nil {
-		return err
+	// the sponsored txs pay no fee, so they can't break even
+	sponsored := p.sponsoredTxs.isSponsored(tx)
+	if !sponsored {
+		err = p.ValidateBreakEvenGasPrice(ctx, tx, preExecutionGasUsed, gasPrices)
+		if err != nil {
+			return err
+		}
 	}
 
 	poolTx := NewTransaction(tx, ip, isWIP)
+	poolTx.ReceivedAt = p.clock.Now()
 	poolTx.ZKCounters = preExecutionResponse.usedZkCounters
+	poolTx.Sponsored = sponsored
+	poolTx.Conditions = conditions
+	if len(p.txTagger.tags) > 0 {
+		from, err := state.GetSender(tx)
+		if err != nil {
+			return err
+		}
+		poolTx.Tag = p.txTagger.tag(tx, from, endpoint)
+	}
 
-	return p.storage.AddTx(ctx, *poolTx)
+	return p.Storage.AddTx(ctx, *poolTx)
 }
 
 // ValidateBreakEvenGasPrice validates the effective gas price
 func (p *Pool) ValidateBreakEvenGasPrice(ctx context.Context, tx types.Transaction, preExecutionGasUsed uint64, gasPrices GasPrices) error {
-	breakEvenGasPrice, err := p.effectiveGasPrice.CalculateBreakEvenGasPrice(tx.Data(), tx.GasPrice(), preExecutionGasUsed, gasPrices.L1GasPrice)
+	txGasPrice := state.GetTxGasPrice(tx)
+	breakEvenGasPrice, err := p.effectiveGasPrice.CalculateBreakEvenGasPrice(tx.Data(), txGasPrice, preExecutionGasUsed, gasPrices.L1GasPrice)
 	if err != nil {
 		if p.cfg.EffectiveGasPrice.Enabled {
 			log.Errorf("error calculating BreakEvenGasPrice: %v", err)
@@ -262,20 +365,20 @@ func (p *Pool) ValidateBreakEvenGasPrice(ctx context.Context, tx types.Transacti
 	breakEvenGasPriceWithFactor := new(big.Int)
 	tmpFactor.Int(breakEvenGasPriceWithFactor)
 
-	if breakEvenGasPriceWithFactor.Cmp(tx.GasPrice()) == 1 { // breakEvenGasPriceWithMargin > tx.GasPrice()
+	if breakEvenGasPriceWithFactor.Cmp(txGasPrice) == 1 { // breakEvenGasPriceWithMargin > tx.GasPrice()
 		// check against L2GasPrice now
 		L2GasPrice := big.NewInt(0).SetUint64(gasPrices.L2GasPrice)
-		if tx.GasPrice().Cmp(L2GasPrice) == -1 { // tx.GasPrice() < gasPrices.L2GasPrice
+		if txGasPrice.Cmp(L2GasPrice) == -1 { // tx.GasPrice() < gasPrices.L2GasPrice
 			// reject tx
 			reject = true
 		} else {
 			// accept loss
-			loss = loss.Sub(breakEvenGasPriceWithFactor, tx.GasPrice())
+			loss = loss.Sub(breakEvenGasPriceWithFactor, txGasPrice)
 		}
 	}
 
 	log.Infof("egp-log: tx.GasPrice(): %v, breakEvenGasPrice: %v, breakEvenGasPriceWithFactor: %v, gasUsed: %v, reject: %t, loss: %v, L1GasPrice: %d, L2GasPrice: %d, Enabled: %t, tx: %s",
-		tx.GasPrice(), breakEvenGasPrice, breakEvenGasPriceWithFactor, preExecutionGasUsed, reject, loss, gasPrices.L1GasPrice, gasPrices.L2GasPrice, p.cfg.EffectiveGasPrice.Enabled, tx.Hash().String())
+		txGasPrice, breakEvenGasPrice, breakEvenGasPriceWithFactor, preExecutionGasUsed, reject, loss, gasPrices.L1GasPrice, gasPrices.L2GasPrice, p.cfg.EffectiveGasPrice.Enabled, tx.Hash().String())
 
 	// Reject transaction if EffectiveGasPrice is enabled
 	if p.cfg.EffectiveGasPrice.Enabled && reject {
@@ -300,6 +403,9 @@ func (p *Pool) preExecuteTx(ctx context.Context, tx types.Transaction) (preExecu
 		} else {
 			response.isOOC = isOOC
 			response.isOOG = isOOG
+			if isOOC {
+				response.oocReason = err.Error()
+			}
 			if processBatchResponse.Responses != nil && len(processBatchResponse.Responses) > 0 {
 				response.usedZkCounters = processBatchResponse.UsedZkCounters
 				response.txResponse = processBatchResponse.Responses[0]
@@ -315,10 +421,14 @@ func (p *Pool) preExecuteTx(ctx context.Context, tx types.Transaction) (preExecu
 			response.isReverted = errors.Is(errorToCheck, runtime.ErrExecutionReverted)
 			response.isOOC = executor.IsROMOutOfCountersError(executor.RomErrorCode(errorToCheck))
 			response.isOOG = errors.Is(errorToCheck, runtime.ErrOutOfGas)
+			if response.isOOC {
+				response.oocReason = errorToCheck.Error()
+			}
 		} else {
 			if !p.batchConstraintsCfg.IsWithinConstraints(processBatchResponse.UsedZkCounters) {
 				response.isOOC = true
-				log.Errorf("OutOfCounters Error (Node level) for tx: %s", tx.Hash().String())
+				response.oocReason = strings.Join(p.batchConstraintsCfg.ExceededConstraints(processBatchResponse.UsedZkCounters), ", ")
+				log.Errorf("OutOfCounters Error (Node level) for tx: %s: %s", tx.Hash().String(), response.oocReason)
 			}
 		}
 
@@ -333,28 +443,36 @@ func (p *Pool) preExecuteTx(ctx context.Context, tx types.Transaction) (preExecu
 // limit parameter is used to limit amount of pending txs from the db,
 // if limit = 0, then there is no limit
 func (p *Pool) GetPendingTxs(ctx context.Context, limit uint64) ([]Transaction, error) {
-	return p.storage.GetTxsByStatus(ctx, TxStatusPending, limit)
+	return p.Storage.GetTxsByStatus(ctx, TxStatusPending, limit)
 }
 
 // GetNonWIPPendingTxs from the pool
 func (p *Pool) GetNonWIPPendingTxs(ctx context.Context) ([]Transaction, error) {
-	return p.storage.GetNonWIPPendingTxs(ctx)
+	return p.Storage.GetNonWIPPendingTxs(ctx)
+}
+
+// GetWIPPendingTxs gets the pending txs marked as WIP from the pool, sorted by sender and nonce
+func (p *Pool) GetWIPPendingTxs(ctx context.Context) ([]Transaction, error) {
+	return p.Storage.GetWIPPendingTxs(ctx)
 }
 
 // GetSelectedTxs gets selected txs from the pool db
 func (p *Pool) GetSelectedTxs(ctx context.Context, limit uint64) ([]Transaction, error) {
-	return p.storage.GetTxsByStatus(ctx, TxStatusSelected, limit)
+	return p.Storage.GetTxsByStatus(ctx, TxStatusSelected, limit)
 }
 
 // GetPendingTxHashesSince returns the hashes of pending tx since the given date.
 func (p *Pool) GetPendingTxHashesSince(ctx context.Context, since time.Time) ([]common.Hash, error) {
-	return p.storage.GetPendingTxHashesSince(ctx, since)
+	return p.Storage.GetPendingTxHashesSince(ctx, since)
 }
 
 // UpdateTxStatus updates a transaction state accordingly to the
 // provided state and hash
 func (p *Pool) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus TxStatus, isWIP bool, failedReason *string) error {
-	return p.storage.UpdateTxStatus(ctx, TxStatusUpdateInfo{
+	if newStatus != TxStatusPending {
+		p.knownTxs.remove(hash)
+	}
+	return p.Storage.UpdateTxStatus(ctx, TxStatusUpdateInfo{
 		Hash:         hash,
 		NewStatus:    newStatus,
 		IsWIP:        isWIP,
@@ -362,31 +480,54 @@ func (p *Pool) UpdateTxStatus(ctx context.Context, hash common.Hash, newStatus T
 	})
 }
 
+// UpdateTxsStatus updates the status of the transactions accordingly to the
+// provided update infos
+func (p *Pool) UpdateTxsStatus(ctx context.Context, updateInfos []TxStatusUpdateInfo) error {
+	for _, updateInfo := range updateInfos {
+		if updateInfo.NewStatus != TxStatusPending {
+			p.knownTxs.remove(updateInfo.Hash)
+		}
+	}
+	return p.Storage.UpdateTxsStatus(ctx, updateInfos)
+}
+
+// DeleteTransactionByHash deletes the transaction from the pool
+func (p *Pool) DeleteTransactionByHash(ctx context.Context, hash common.Hash) error {
+	p.knownTxs.remove(hash)
+	return p.Storage.DeleteTransactionByHash(ctx, hash)
+}
+
+// DeleteTransactionsByHashes deletes the transactions from the pool
+func (p *Pool) DeleteTransactionsByHashes(ctx context.Context, hashes []common.Hash) error {
+	p.knownTxs.remove(hashes...)
+	return p.Storage.DeleteTransactionsByHashes(ctx, hashes)
+}
+
 // SetGasPrices sets the current L2 Gas Price and L1 Gas Price
 func (p *Pool) SetGasPrices(ctx context.Context, l2GasPrice uint64, l1GasPrice uint64) error {
-	return p.storage.SetGasPrices(ctx, l2GasPrice, l1GasPrice)
+	return p.Storage.SetGasPrices(ctx, l2GasPrice, l1GasPrice)
 }
 
 // DeleteGasPricesHistoryOlderThan deletes gas prices older than a given date except the most recent one
 func (p *Pool) DeleteGasPricesHistoryOlderThan(ctx context.Context, date time.Time) error {
-	return p.storage.DeleteGasPricesHistoryOlderThan(ctx, date)
+	return p.Storage.DeleteGasPricesHistoryOlderThan(ctx, date)
 }
 
 // GetGasPrices returns the current L2 Gas Price and L1 Gas Price
 func (p *Pool) GetGasPrices(ctx context.Context) (GasPrices, error) {
-	l2GasPrice, l1GasPrice, err := p.storage.GetGasPrices(ctx)
+	l2GasPrice, l1GasPrice, err := p.Storage.GetGasPrices(ctx)
 	return GasPrices{L1GasPrice: l1GasPrice, L2GasPrice: l2GasPrice}, err
 }
 
 // CountPendingTransactions get number of pending transactions
 // used in bench tests
 func (p *Pool) CountPendingTransactions(ctx context.Context) (uint64, error) {
-	return p.storage.CountTransactionsByStatus(ctx, TxStatusPending)
+	return p.Storage.CountTransactionsByStatus(ctx, TxStatusPending)
 }
 
 // IsTxPending check if tx is still pending
 func (p *Pool) IsTxPending(ctx context.Context, hash common.Hash) (bool, error) {
-	return p.storage.IsTxPending(ctx, hash)
+	return p.Storage.IsTxPending(ctx, hash)
 }
 
 func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
@@ -395,31 +536,23 @@ func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 		return ErrInvalidIP
 	}
 
-	// Make sure the transaction is signed properly.
-	if err := state.CheckSignature(poolTx.Transaction); err != nil {
-		return ErrInvalidSender
-	}
-
-	// check chain id
-	txChainID := poolTx.ChainId().Uint64()
-	if txChainID != p.chainID && txChainID != 0 {
-		return ErrInvalidChainID
+	// Make sure the transaction is signed properly for the chain and get its sender
+	from, err := p.sigValidator.validate(ctx, &poolTx.Transaction)
+	if err != nil {
+		return err
 	}
 
-	// Accept only legacy transactions until EIP-2718/2930 activates.
+	// Accept only the tx types that can be encoded in the batches of the current fork
 	if poolTx.Type() != types.LegacyTxType {
-		return ErrTxTypeNotSupported
-	}
-
-	// check Pre EIP155 txs signature
-	if txChainID == 0 && !state.IsPreEIP155Tx(poolTx.Transaction) {
-		return ErrInvalidSender
-	}
-
-	// gets tx sender for validations
-	from, err := state.GetSender(poolTx.Transaction)
-	if err != nil {
-		return ErrInvalidSender
+		lastBatchNumber, err := p.state.GetLastBatchNumber(ctx, nil)
+		if err != nil {
+			log.Errorf("failed to get last batch number while adding tx to the pool", err)
+			return err
+		}
+		forkID := p.state.GetForkIDByBatchNumber(lastBatchNumber)
+		if !state.IsTxTypeSupported(poolTx.Type(), forkID) {
+			return fmt.Errorf("%w: type 0x%02x txs can't be included in the batches of fork %d yet, send a legacy tx", ErrTxTypeNotSupported, poolTx.Type(), forkID)
+		}
 	}
 
 	// Reject transactions over defined size to prevent DOS attacks
@@ -459,7 +592,7 @@ func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 
 	// check if sender has reached the limit of transactions in the pool
 	if p.cfg.AccountQueue > 0 {
-		// txCount, err := p.storage.CountTransactionsByFromAndStatus(ctx, from, TxStatusPending)
+		// txCount, err := p.Storage.CountTransactionsByFromAndStatus(ctx, from, TxStatusPending)
 		// if err != nil {
 		// 	return err
 		// }
@@ -467,16 +600,24 @@ func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 		// 	return ErrTxPoolAccountOverflow
 		// }
 
-		// Ensure the transaction does not jump out of the expected AccountQueue
+		// Ensure the transaction does not jump out of the expected AccountQueue, it starts at the
+		// next usable nonce so the pending txs without gaps don't take slots from it
 		if poolTx.Nonce() > currentNonce+p.cfg.AccountQueue-1 {
-			log.Infof("%v: %v", ErrNonceTooHigh.Error(), from.String())
-			return ErrNonceTooHigh
+			nextNonce, err := p.GetPendingNonce(ctx, from, currentNonce)
+			if err != nil {
+				log.Errorf("failed to get the pending nonce of the account while adding tx to the pool", err)
+				return err
+			}
+			if poolTx.Nonce() > nextNonce+p.cfg.AccountQueue-1 {
+				log.Infof("%v: %v", ErrNonceTooHigh.Error(), from.String())
+				return ErrNonceTooHigh
+			}
 		}
 	}
 
 	// check if the pool is full
 	if p.cfg.GlobalQueue > 0 {
-		txCount, err := p.storage.CountTransactionsByStatus(ctx, TxStatusPending)
+		txCount, err := p.Storage.CountTransactionsByStatus(ctx, TxStatusPending)
 		if err != nil {
 			log.Errorf("failed to count pool txs by status pending while adding tx to the pool", err)
 			return err
@@ -486,15 +627,25 @@ func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 		}
 	}
 
-	// Reject transactions with a gas price lower than the minimum gas price
-	p.minSuggestedGasPriceMux.RLock()
-	gasPriceCmp := poolTx.GasPrice().Cmp(p.minSuggestedGasPrice)
-	if gasPriceCmp == -1 {
-		log.Debugf("low gas price: minSuggestedGasPrice %v got %v", p.minSuggestedGasPrice, poolTx.GasPrice())
+	// Reject transactions with a gas price lower than the minimum gas price, unless they are sponsored
+	minGasPrice := p.GetMinSuggestedGasPrice()
+	txGasPrice := state.GetTxGasPrice(poolTx.Transaction)
+	sponsored := p.sponsoredTxs.isSponsored(poolTx.Transaction)
+	if sponsored {
+		if err := p.sponsoredTxs.validate(poolTx.Transaction); err != nil {
+			return err
+		}
+	} else if txGasPrice.Cmp(minGasPrice) == -1 {
+		log.Debugf("low gas price: minSuggestedGasPrice %v got %v", minGasPrice, txGasPrice)
+		return NewGasPriceTooLowError(minGasPrice)
 	}
-	p.minSuggestedGasPriceMux.RUnlock()
-	if gasPriceCmp == -1 {
-		return ErrGasPrice
+
+	// Reject transactions with a gas price higher than the maximum gas price, to protect the senders from
+	// mispriced txs and to limit how much a tx can outbid the others in the sequencer selection
+	maxGasPrice := getMaxGasPriceAllowed(minGasPrice, p.cfg.MaxGasPriceAllowed, p.cfg.MaxGasPriceFactor)
+	if maxGasPrice != nil && txGasPrice.Cmp(maxGasPrice) > 0 {
+		log.Debugf("high gas price: maxGasPriceAllowed %v got %v", maxGasPrice, txGasPrice)
+		return NewGasPriceTooHighError(maxGasPrice)
 	}
 
 	// Transactor should have enough funds to cover the costs
@@ -520,7 +671,7 @@ func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 
 	// try to get a transaction from the pool with the same nonce to check
 	// if the new one has a price bump
-	oldTxs, err := p.storage.GetTxsByFromAndNonce(ctx, from, poolTx.Nonce())
+	oldTxs, err := p.Storage.GetTxsByFromAndNonce(ctx, from, poolTx.Nonce())
 	if err != nil {
 		log.Errorf("failed to txs for the same account and nonce while adding tx to the pool", err)
 		return err
@@ -529,14 +680,16 @@ func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 	// check if the new transaction has more gas than all the other txs in the pool
 	// with the same from and nonce to be able to replace the current txs by the new
 	// when being selected
+	replacesTx := false
 	for _, oldTx := range oldTxs {
 		// discard invalid txs
-		if oldTx.Status == TxStatusInvalid || oldTx.Status == TxStatusFailed {
+		if oldTx.Status == TxStatusInvalid || oldTx.Status == TxStatusFailed || oldTx.Status == TxStatusExpired {
 			continue
 		}
+		replacesTx = true
 
-		oldTxPrice := new(big.Int).Mul(oldTx.GasPrice(), new(big.Int).SetUint64(oldTx.Gas()))
-		txPrice := new(big.Int).Mul(poolTx.GasPrice(), new(big.Int).SetUint64(poolTx.Gas()))
+		oldTxPrice := new(big.Int).Mul(state.GetTxGasPrice(oldTx.Transaction), new(big.Int).SetUint64(oldTx.Gas()))
+		txPrice := new(big.Int).Mul(txGasPrice, new(big.Int).SetUint64(poolTx.Gas()))
 
 		if oldTx.Hash() == poolTx.Hash() {
 			return ErrAlreadyKnown
@@ -546,6 +699,23 @@ func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 		if oldTxPrice.Cmp(txPrice) > 0 {
 			return ErrReplaceUnderpriced
 		}
+
+		// the worker discards the replacements without the price bump, so they are rejected here
+		if p.cfg.PriceBump > 0 && IsReplacementUnderpriced(state.GetTxGasPrice(oldTx.Transaction), txGasPrice, p.cfg.PriceBump) {
+			return ErrReplaceUnderpriced
+		}
+	}
+
+	// check if sender has reached the limit of pending transactions, the replacements don't take a new slot
+	if p.cfg.MaxTxsPerAccount > 0 && !replacesTx {
+		txCount, err := p.Storage.CountTransactionsByFromAndStatus(ctx, from, TxStatusPending)
+		if err != nil {
+			log.Errorf("failed to count pool txs by from and status pending while adding tx to the pool", err)
+			return err
+		}
+		if txCount >= p.cfg.MaxTxsPerAccount {
+			return fmt.Errorf("%w, max %d pending txs per account", ErrTxPoolAccountOverflow, p.cfg.MaxTxsPerAccount)
+		}
 	}
 
 	// Executor field size requirements check
@@ -553,17 +723,126 @@ func (p *Pool) validateTx(ctx context.Context, poolTx Transaction) error {
 		return err
 	}
 
+	// check if sender has reached the limit of transactions with a nonce gap
+	if err := p.checkNonceGap(ctx, from, poolTx.Nonce(), currentNonce); err != nil {
+		return err
+	}
+
+	// the sponsored txs are counted once the rest of validations passed
+	if sponsored {
+		if err := p.sponsoredTxs.allow(from, p.clock.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkNonceGap ensures the account doesn't exceed the limit of pending txs that can't
+// be executed because of a nonce gap. Once the limit is reached a tx with a lower nonce
+// than the gapped ones evicts the one with the highest nonce, otherwise it's rejected
+func (p *Pool) checkNonceGap(ctx context.Context, from common.Address, nonce, currentNonce uint64) error {
+	if p.cfg.MaxNonceGappedTxsPerAccount == 0 || nonce <= currentNonce {
+		return nil
+	}
+
+	nonces, err := p.Storage.GetNoncesByFromAndStatus(ctx, from, TxStatusPending)
+	if err != nil {
+		log.Errorf("failed to get the nonces of the pending txs of the account while adding tx to the pool", err)
+		return err
+	}
+
+	gapped, nextNonce := nonceGappedNonces(currentNonce, nonces)
+	// the tx is not gapped when it fills the gap or replaces a pending tx
+	if nonce <= nextNonce || containsNonce(gapped, nonce) {
+		return nil
+	}
+	if uint64(len(gapped)) < p.cfg.MaxNonceGappedTxsPerAccount {
+		return nil
+	}
+
+	highestNonce := gapped[len(gapped)-1]
+	if nonce > highestNonce {
+		log.Infof("%v: %v", ErrNonceGapLimitReached.Error(), from.String())
+		return ErrNonceGapLimitReached
+	}
+
+	return p.evictNonceGappedTxs(ctx, from, highestNonce)
+}
+
+// GetPendingNonce returns the next usable nonce of the account, the nonce after its pending txs that
+// can be executed after the current nonce without gaps. The pending txs after a nonce gap are not
+// counted, so the txs sent with the returned nonce fill the gap instead of replacing them
+func (p *Pool) GetPendingNonce(ctx context.Context, address common.Address, currentNonce uint64) (uint64, error) {
+	nonces, err := p.Storage.GetNoncesByFromAndStatus(ctx, address, TxStatusPending)
+	if err != nil {
+		return 0, err
+	}
+	_, nextNonce := nonceGappedNonces(currentNonce, nonces)
+	return nextNonce, nil
+}
+
+// evictNonceGappedTxs sets as failed the pending txs of the account with the provided
+// nonce, txs already handled by the sequencer can't be evicted
+func (p *Pool) evictNonceGappedTxs(ctx context.Context, from common.Address, nonce uint64) error {
+	txs, err := p.Storage.GetTxsByFromAndNonce(ctx, from, nonce)
+	if err != nil {
+		log.Errorf("failed to get the txs to evict while adding tx to the pool", err)
+		return err
+	}
+
+	failedReason := ErrNonceGappedTxEvicted.Error()
+	for _, tx := range txs {
+		if tx.Status != TxStatusPending {
+			continue
+		}
+		if tx.IsWIP {
+			return ErrNonceGapLimitReached
+		}
+		log.Infof("evicting tx %v of %v with nonce %d: %v", tx.Hash().String(), from.String(), nonce, failedReason)
+		if err := p.UpdateTxStatus(ctx, tx.Hash(), TxStatusFailed, false, &failedReason); err != nil {
+			log.Errorf("failed to evict tx %v while adding tx to the pool", tx.Hash().String(), err)
+			return err
+		}
+	}
+
 	return nil
 }
 
+// nonceGappedNonces splits the sorted nonces of the pending txs of an account in the
+// ones that can be executed after the current nonce without gaps, returning the next
+// nonce after them, and the gapped ones
+func nonceGappedNonces(currentNonce uint64, nonces []uint64) (gapped []uint64, nextNonce uint64) {
+	nextNonce = currentNonce
+	for _, nonce := range nonces {
+		if nonce < nextNonce {
+			continue
+		} else if nonce == nextNonce {
+			nextNonce++
+		} else {
+			gapped = append(gapped, nonce)
+		}
+	}
+	return gapped, nextNonce
+}
+
+func containsNonce(nonces []uint64, nonce uint64) bool {
+	for _, n := range nonces {
+		if n == nonce {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Pool) pollMinSuggestedGasPrice(ctx context.Context) {
-	fromTimestamp := time.Now().UTC().Add(-p.cfg.MinAllowedGasPriceInterval.Duration)
+	fromTimestamp := p.clock.Now().UTC().Add(-p.cfg.MinAllowedGasPriceInterval.Duration)
 	// Ensuring we don't use a timestamp before the pool start as it may be using older L1 gas price factor
 	if fromTimestamp.Before(p.startTimestamp) {
 		fromTimestamp = p.startTimestamp
 	}
 
-	l2GasPrice, err := p.storage.MinL2GasPriceSince(ctx, fromTimestamp)
+	l2GasPrice, err := p.Storage.MinL2GasPriceSince(ctx, fromTimestamp)
 	if err != nil {
 		p.minSuggestedGasPriceMux.Lock()
 		// Ensuring we always have suggested minimum gas price
@@ -605,11 +884,11 @@ func (p *Pool) checkTxFieldCompatibilityWithExecutor(ctx context.Context, tx typ
 
 	dataSize := len(tx.Data())
 	if dataSize > p.cfg.MaxTxDataBytesSize {
-		return fmt.Errorf("data size bigger than allowed, current size is %v bytes and max allowed is %v bytes", dataSize, p.cfg.MaxTxDataBytesSize)
+		return fmt.Errorf("%w, current size is %v bytes and max allowed is %v bytes", ErrTxDataTooLarge, dataSize, p.cfg.MaxTxDataBytesSize)
 	}
 
 	if tx.ChainId().Cmp(maxUint64BigInt) == 1 {
-		return fmt.Errorf("chain id higher than allowed, max allowed is %v", uint64(math.MaxUint64))
+		return fmt.Errorf("%w, max allowed is %v", ErrChainIDTooHigh, uint64(math.MaxUint64))
 	}
 
 	return nil
@@ -622,14 +901,18 @@ func (p *Pool) DeleteReorgedTransactions(ctx context.Context, transactions []*ty
 	for _, tx := range transactions {
 		hashes = append(hashes, tx.Hash())
 	}
-
-	return p.storage.DeleteTransactionsByHashes(ctx, hashes)
+	return p.DeleteTransactionsByHashes(ctx, hashes)
 }
 
 // UpdateTxWIPStatus updates a transaction wip status accordingly to the
 // provided WIP status and hash
 func (p *Pool) UpdateTxWIPStatus(ctx context.Context, hash common.Hash, isWIP bool) error {
-	return p.storage.UpdateTxWIPStatus(ctx, hash, isWIP)
+	return p.Storage.UpdateTxWIPStatus(ctx, hash, isWIP)
+}
+
+// UpdateTxZKCounters updates the zkcounters of a tx with the ones used when it was executed
+func (p *Pool) UpdateTxZKCounters(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) error {
+	return p.Storage.UpdateTxZKCounters(ctx, hash, zkCounters)
 }
 
 // GetDefaultMinGasPriceAllowed return the configured DefaultMinGasPriceAllowed value
@@ -637,6 +920,15 @@ func (p *Pool) GetDefaultMinGasPriceAllowed() uint64 {
 	return p.cfg.DefaultMinGasPriceAllowed
 }
 
+// GetMinSuggestedGasPrice returns the minimum gas price the pool is currently
+// accepting, which is the lowest L2 gas price suggested in the configured
+// MinAllowedGasPriceInterval
+func (p *Pool) GetMinSuggestedGasPrice() *big.Int {
+	p.minSuggestedGasPriceMux.RLock()
+	defer p.minSuggestedGasPriceMux.RUnlock()
+	return new(big.Int).Set(p.minSuggestedGasPrice)
+}
+
 // GetL1AndL2GasPrice returns the L1 and L2 gas price from memory struct
 func (p *Pool) GetL1AndL2GasPrice() (uint64, uint64) {
 	p.gasPricesMux.RLock()
@@ -646,6 +938,15 @@ func (p *Pool) GetL1AndL2GasPrice() (uint64, uint64) {
 	return gasPrices.L1GasPrice, gasPrices.L2GasPrice
 }
 
+// IsReplacementUnderpriced checks if the gasPrice of a new tx is not higher enough to replace the tx with the
+// same sender and nonce, it must be higher than the gasPrice of the replaced tx by at least priceBump percent.
+// It's shared by the pool and the worker, so both apply the same replacement rule
+func IsReplacementUnderpriced(oldGasPrice, newGasPrice *big.Int, priceBump uint64) bool {
+	minGasPrice := new(big.Int).Mul(oldGasPrice, new(big.Int).SetUint64(100+priceBump)) //nolint:gomnd
+	minGasPrice.Div(minGasPrice, big.NewInt(100))                                       //nolint:gomnd
+	return newGasPrice.Cmp(minGasPrice) < 0
+}
+
 const (
 	txDataNonZeroGas      uint64 = 16
 	txGasContractCreation uint64 = 53000
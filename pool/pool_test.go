@@ -252,6 +252,67 @@ func Test_AddTx(t *testing.T) {
 	assert.Equal(t, 1, c, "invalid number of txs in the pool")
 }
 
+func Test_AddTx_ResubmitFailedTx(t *testing.T) {
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	require.NoError(t, err)
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	poolSqlDB, err := db.NewSQLDB(poolDBCfg)
+	require.NoError(t, err)
+	defer poolSqlDB.Close() //nolint:gosec,errcheck
+
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	st := newState(stateSqlDB, eventLog)
+
+	genesisBlock := state.Block{
+		BlockNumber: 0,
+		BlockHash:   state.ZeroHash,
+		ParentHash:  state.ZeroHash,
+		ReceivedAt:  time.Now(),
+	}
+	ctx := context.Background()
+	dbTx, err := st.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	_, err = st.SetGenesis(ctx, genesisBlock, genesis, dbTx)
+	require.NoError(t, err)
+	require.NoError(t, dbTx.Commit(ctx))
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	const chainID = 2576980377
+	knownTxsCfg := cfg
+	knownTxsCfg.KnownTxsCacheSize = 10
+	p := setupPool(t, knownTxsCfg, bc, s, st, chainID, ctx, eventLog)
+
+	tx := new(ethTypes.Transaction)
+	b, err := hex.DecodeHex("0xf86880843b9aca008252089400000000000000000000000000000000000000008080850133333355a03ee24709870c8dbc67884c9c8acb864c1aceaaa7332b9a3db0d7a5d7c68eb8e4a0302980b070f5e3ffca3dc27b07daf69d66ab27d4df648e0b3ed059cf23aa168d")
+	require.NoError(t, err)
+	require.NoError(t, tx.UnmarshalBinary(b))
+
+	require.NoError(t, p.AddTx(ctx, *tx, ip))
+
+	// the tx is pending, the resubmission is rejected
+	err = p.AddTx(ctx, *tx, ip)
+	require.ErrorIs(t, err, pool.ErrAlreadyKnown)
+
+	// once the tx failed it can be resubmitted
+	failedReason := "failed"
+	require.NoError(t, p.UpdateTxStatus(ctx, tx.Hash(), pool.TxStatusFailed, false, &failedReason))
+	require.NoError(t, p.AddTx(ctx, *tx, ip))
+
+	poolTx, err := p.GetTxByHash(ctx, tx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, pool.TxStatusPending, poolTx.Status)
+}
+
 func Test_AddTx_OversizedData(t *testing.T) {
 	initOrResetDB(t)
 
@@ -1066,7 +1127,7 @@ func Test_TryAddIncompatibleTxs(t *testing.T) {
 				require.NoError(t, err)
 				return *signedTx
 			},
-			expectedError: fmt.Errorf("data size bigger than allowed, current size is %v bytes and max allowed is %v bytes", 30001, 30000),
+			expectedError: fmt.Errorf("%w, current size is %v bytes and max allowed is %v bytes", pool.ErrTxDataTooLarge, 30001, 30000),
 		},
 		{
 			name: "chain id over 64 bits",
@@ -1078,7 +1139,7 @@ func Test_TryAddIncompatibleTxs(t *testing.T) {
 				require.NoError(t, err)
 				return *signedTx
 			},
-			expectedError: fmt.Errorf("chain id higher than allowed, max allowed is %v", uint64(math.MaxUint64)),
+			expectedError: fmt.Errorf("%w, max allowed is %v", pool.ErrChainIDTooHigh, uint64(math.MaxUint64)),
 		},
 	}
 	for _, testCase := range testCases {
@@ -1350,6 +1411,9 @@ func Test_AddTx_GasPriceErr(t *testing.T) {
 			err = p.AddTx(ctx, *signedTx, ip)
 			if tc.expectedError != nil {
 				require.ErrorIs(t, err, tc.expectedError)
+				var gasPriceTooLowErr *pool.GasPriceTooLowError
+				require.ErrorAs(t, err, &gasPriceTooLowErr)
+				assert.Equal(t, p.GetMinSuggestedGasPrice().String(), gasPriceTooLowErr.MinGasPrice.String())
 			} else {
 				require.NoError(t, err)
 			}
@@ -1703,18 +1767,23 @@ func Test_AddTx_AccountQueueLimit(t *testing.T) {
 		nonce++
 	}
 
-	tx := ethTypes.NewTx(&ethTypes.LegacyTx{
-		Nonce:    nonce,
-		Value:    big.NewInt(0),
-		Gas:      uint64(1000000),
-		GasPrice: gasPrice,
-	})
+	signTx := func(nonce uint64) *ethTypes.Transaction {
+		tx := ethTypes.NewTx(&ethTypes.LegacyTx{
+			Nonce:    nonce,
+			Value:    big.NewInt(0),
+			Gas:      uint64(1000000),
+			GasPrice: gasPrice,
+		})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		return signedTx
+	}
 
-	signedTx, err := auth.Signer(auth.From, tx)
-	require.NoError(t, err)
+	// the account queue starts at the next usable nonce, after the pending txs
+	err = p.AddTx(ctx, *signTx(nonce + cfg.AccountQueue), ip)
+	require.ErrorIs(t, err, pool.ErrNonceTooHigh)
 
-	err = p.AddTx(ctx, *signedTx, ip)
-	require.Error(t, err, pool.ErrNonceTooHigh)
+	require.NoError(t, p.AddTx(ctx, *signTx(nonce + cfg.AccountQueue - 1), ip))
 }
 
 func Test_AddTx_GlobalQueueLimit(t *testing.T) {
@@ -1887,6 +1956,193 @@ func Test_AddTx_NonceTooHigh(t *testing.T) {
 	require.Error(t, err, pool.ErrNonceTooHigh)
 }
 
+func Test_AddTx_ReplacementPriceBump(t *testing.T) {
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	poolSqlDB, err := db.NewSQLDB(poolDBCfg)
+	require.NoError(t, err)
+	defer poolSqlDB.Close() //nolint:gosec,errcheck
+
+	st := newState(stateSqlDB, eventLog)
+
+	// generate accounts
+	genesisBlock := state.Block{
+		BlockNumber: 0,
+		BlockHash:   state.ZeroHash,
+		ParentHash:  state.ZeroHash,
+		ReceivedAt:  time.Now(),
+	}
+	genesis := state.Genesis{
+		GenesisActions: []*state.GenesisAction{
+			{
+				Address: senderAddress,
+				Type:    int(merkletree.LeafTypeBalance),
+				Value:   "1000000000000000000000",
+			},
+		},
+	}
+	ctx := context.Background()
+	dbTx, err := st.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	_, err = st.SetGenesis(ctx, genesisBlock, genesis, dbTx)
+	require.NoError(t, err)
+	require.NoError(t, dbTx.Commit(ctx))
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	priceBumpCfg := cfg
+	priceBumpCfg.PriceBump = 10
+	p := setupPool(t, priceBumpCfg, bc, s, st, chainID.Uint64(), ctx, eventLog)
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderPrivateKey, "0x"))
+	require.NoError(t, err)
+
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	require.NoError(t, err)
+
+	addTx := func(price *big.Int) error {
+		tx := ethTypes.NewTx(&ethTypes.LegacyTx{
+			Nonce:    0,
+			To:       &common.Address{},
+			Value:    big.NewInt(0),
+			Gas:      gasLimit,
+			GasPrice: price,
+		})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		return p.AddTx(ctx, *signedTx, ip)
+	}
+
+	require.NoError(t, addTx(big.NewInt(10000000000)))
+
+	// a replacement paying more but less than 10% more is rejected
+	err = addTx(big.NewInt(10500000000))
+	require.ErrorIs(t, err, pool.ErrReplaceUnderpriced)
+
+	// a replacement paying 10% more is accepted
+	require.NoError(t, addTx(big.NewInt(11000000000)))
+}
+
+func Test_AddTx_NonceGapLimit(t *testing.T) {
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	poolSqlDB, err := db.NewSQLDB(poolDBCfg)
+	require.NoError(t, err)
+	defer poolSqlDB.Close() //nolint:gosec,errcheck
+
+	st := newState(stateSqlDB, eventLog)
+
+	genesisBlock := state.Block{
+		BlockNumber: 0,
+		BlockHash:   state.ZeroHash,
+		ParentHash:  state.ZeroHash,
+		ReceivedAt:  time.Now(),
+	}
+	genesis := state.Genesis{
+		GenesisActions: []*state.GenesisAction{
+			{
+				Address: senderAddress,
+				Type:    int(merkletree.LeafTypeBalance),
+				Value:   "1000000000000000000000",
+			},
+		},
+	}
+	ctx := context.Background()
+	dbTx, err := st.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	_, err = st.SetGenesis(ctx, genesisBlock, genesis, dbTx)
+	require.NoError(t, err)
+	require.NoError(t, dbTx.Commit(ctx))
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	nonceGapCfg := cfg
+	nonceGapCfg.MaxNonceGappedTxsPerAccount = 2
+	nonceGapCfg.AccountQueue = 8
+	p := setupPool(t, nonceGapCfg, bc, s, st, chainID.Uint64(), ctx, eventLog)
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderPrivateKey, "0x"))
+	require.NoError(t, err)
+
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	require.NoError(t, err)
+
+	signTx := func(nonce uint64) *ethTypes.Transaction {
+		tx := ethTypes.NewTx(&ethTypes.LegacyTx{
+			Nonce:    nonce,
+			Value:    big.NewInt(0),
+			Gas:      uint64(1000000),
+			GasPrice: gasPrice,
+		})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		return signedTx
+	}
+
+	// nonce 0 is executable, 5 and 6 are gapped
+	for _, nonce := range []uint64{0, 5, 6} {
+		require.NoError(t, p.AddTx(ctx, *signTx(nonce), ip))
+	}
+
+	// the gapped txs limit is reached, a higher nonce is rejected
+	err = p.AddTx(ctx, *signTx(7), ip)
+	require.ErrorIs(t, err, pool.ErrNonceGapLimitReached)
+
+	// a tx filling the gap doesn't count as gapped
+	require.NoError(t, p.AddTx(ctx, *signTx(1), ip))
+
+	// a lower gapped nonce evicts the gapped tx with the highest nonce
+	tx6 := signTx(6)
+	require.NoError(t, p.AddTx(ctx, *signTx(3), ip))
+	var status, failedReason string
+	err = poolSqlDB.QueryRow(ctx, "SELECT status, failed_reason FROM pool.transaction WHERE hash = $1", tx6.Hash().Hex()).Scan(&status, &failedReason)
+	require.NoError(t, err)
+	assert.Equal(t, pool.TxStatusFailed, pool.TxStatus(status))
+	assert.Equal(t, pool.ErrNonceGappedTxEvicted.Error(), failedReason)
+
+	// the pending nonce is the one after the txs without gaps: 0 and 1
+	pendingNonce, err := p.GetPendingNonce(ctx, auth.From, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), pendingNonce)
+
+	// the account queue starts at the pending nonce
+	err = p.AddTx(ctx, *signTx(10), ip)
+	require.ErrorIs(t, err, pool.ErrNonceTooHigh)
+
+	// once the gap is filled the account queue moves with the pending nonce
+	require.NoError(t, p.AddTx(ctx, *signTx(2), ip))
+	require.NoError(t, p.AddTx(ctx, *signTx(4), ip))
+	require.NoError(t, p.AddTx(ctx, *signTx(9), ip))
+	err = p.AddTx(ctx, *signTx(14), ip)
+	require.ErrorIs(t, err, pool.ErrNonceTooHigh)
+}
+
 func Test_AddTx_IPValidation(t *testing.T) {
 	var tests = []struct {
 		name     string
@@ -2022,3 +2278,221 @@ func createSignedTx(t *testing.T, dataLen int, gasPrice *big.Int, gasLimit uint6
 	require.NoError(t, err)
 	return signedTx
 }
+
+func Test_SuggestNonceGapResolution(t *testing.T) {
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	st := newState(stateSqlDB, eventLog)
+
+	genesisBlock := state.Block{
+		BlockNumber: 0,
+		BlockHash:   state.ZeroHash,
+		ParentHash:  state.ZeroHash,
+		ReceivedAt:  time.Now(),
+	}
+	genesis := state.Genesis{
+		GenesisActions: []*state.GenesisAction{
+			{
+				Address: senderAddress,
+				Type:    int(merkletree.LeafTypeBalance),
+				Value:   "1000000000000000000000",
+			},
+		},
+	}
+	ctx := context.Background()
+	dbTx, err := st.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	_, err = st.SetGenesis(ctx, genesisBlock, genesis, dbTx)
+	require.NoError(t, err)
+	require.NoError(t, dbTx.Commit(ctx))
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	priceBumpCfg := cfg
+	priceBumpCfg.PriceBump = 10
+	p := setupPool(t, priceBumpCfg, bc, s, st, chainID.Uint64(), ctx, eventLog)
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderPrivateKey, "0x"))
+	require.NoError(t, err)
+
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	require.NoError(t, err)
+
+	// the tx with nonce 5 is a plain transfer, so the cancel tx has the same gas
+	txs := map[uint64]*ethTypes.Transaction{}
+	for _, nonce := range []uint64{0, 3, 5} {
+		var to *common.Address
+		gas := uint64(1000000)
+		if nonce == 5 {
+			to, gas = &auth.From, 21000
+		}
+		tx := ethTypes.NewTx(&ethTypes.LegacyTx{
+			Nonce:    nonce,
+			To:       to,
+			Value:    big.NewInt(0),
+			Gas:      gas,
+			GasPrice: gasPrice,
+		})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		require.NoError(t, p.AddTx(ctx, *signedTx, ip))
+		txs[nonce] = signedTx
+	}
+
+	// nonce 0 is executable, 3 and 5 are gapped
+	resolution, err := p.SuggestNonceGapResolution(ctx, auth.From, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), resolution.NextNonce)
+	assert.Equal(t, []uint64{3, 5}, resolution.GappedNonces)
+
+	require.Len(t, resolution.Fill, 3)
+	for i, nonce := range []uint64{1, 2, 4} {
+		assert.Equal(t, nonce, resolution.Fill[i].Nonce)
+		assert.Equal(t, uint64(21000), resolution.Fill[i].Gas)
+		assert.Nil(t, resolution.Fill[i].Replaces)
+		assert.True(t, resolution.Fill[i].GasPrice.Cmp(p.GetMinSuggestedGasPrice()) >= 0)
+	}
+
+	// the cancel txs pay at least the same as the replaced txs and are accepted as their replacements
+	require.Len(t, resolution.Cancel, 2)
+	for i, nonce := range []uint64{3, 5} {
+		cancel := resolution.Cancel[i]
+		assert.Equal(t, nonce, cancel.Nonce)
+		require.NotNil(t, cancel.Replaces)
+		assert.Equal(t, txs[nonce].Hash(), *cancel.Replaces)
+		replacedPrice := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(txs[nonce].Gas()))
+		assert.True(t, new(big.Int).Mul(cancel.GasPrice, big.NewInt(int64(cancel.Gas))).Cmp(replacedPrice) >= 0)
+
+		tx := ethTypes.NewTx(&ethTypes.LegacyTx{
+			Nonce:    cancel.Nonce,
+			To:       &auth.From,
+			Value:    big.NewInt(0),
+			Gas:      cancel.Gas,
+			GasPrice: cancel.GasPrice,
+		})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		require.NoError(t, p.AddTx(ctx, *signedTx, ip))
+	}
+
+	// no gaps when the state nonce is after the gapped txs
+	resolution, err = p.SuggestNonceGapResolution(ctx, auth.From, 6)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), resolution.NextNonce)
+	assert.Empty(t, resolution.GappedNonces)
+	assert.Empty(t, resolution.Fill)
+	assert.Empty(t, resolution.Cancel)
+}
+
+func Test_DropTxAndBanSender(t *testing.T) {
+	eventStorage, err := nileventstorage.NewNilEventStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventLog := event.NewEventLog(event.Config{}, eventStorage)
+
+	initOrResetDB(t)
+
+	stateSqlDB, err := db.NewSQLDB(stateDBCfg)
+	if err != nil {
+		panic(err)
+	}
+	defer stateSqlDB.Close() //nolint:gosec,errcheck
+
+	st := newState(stateSqlDB, eventLog)
+
+	genesisBlock := state.Block{
+		BlockNumber: 0,
+		BlockHash:   state.ZeroHash,
+		ParentHash:  state.ZeroHash,
+		ReceivedAt:  time.Now(),
+	}
+	genesis := state.Genesis{
+		GenesisActions: []*state.GenesisAction{
+			{
+				Address: senderAddress,
+				Type:    int(merkletree.LeafTypeBalance),
+				Value:   "1000000000000000000000",
+			},
+		},
+	}
+	ctx := context.Background()
+	dbTx, err := st.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	_, err = st.SetGenesis(ctx, genesisBlock, genesis, dbTx)
+	require.NoError(t, err)
+	require.NoError(t, dbTx.Commit(ctx))
+
+	s, err := pgpoolstorage.NewPostgresPoolStorage(poolDBCfg)
+	require.NoError(t, err)
+
+	p := setupPool(t, cfg, bc, s, st, chainID.Uint64(), ctx, eventLog)
+
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderPrivateKey, "0x"))
+	require.NoError(t, err)
+
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	require.NoError(t, err)
+
+	signTx := func(nonce uint64, value int64) *ethTypes.Transaction {
+		tx := ethTypes.NewTx(&ethTypes.LegacyTx{
+			Nonce:    nonce,
+			Value:    big.NewInt(value),
+			Gas:      uint64(1000000),
+			GasPrice: gasPrice,
+		})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		return signedTx
+	}
+	txs := []*ethTypes.Transaction{signTx(0, 0), signTx(1, 0), signTx(2, 0)}
+	for _, tx := range txs {
+		require.NoError(t, p.AddTx(ctx, *tx, ip))
+	}
+
+	from, err := p.DropTx(ctx, txs[2].Hash(), "spam")
+	require.NoError(t, err)
+	assert.Equal(t, auth.From, from)
+	dropped, err := s.GetTxByHash(ctx, txs[2].Hash())
+	require.NoError(t, err)
+	assert.Equal(t, pool.TxStatusFailed, dropped.Status)
+	require.NotNil(t, dropped.FailedReason)
+	assert.Equal(t, "dropped by the operator: spam", *dropped.FailedReason)
+
+	_, err = p.DropTx(ctx, txs[2].Hash(), "spam")
+	assert.ErrorIs(t, err, pool.ErrTxNotPending)
+
+	hashes, err := p.BanSender(ctx, auth.From, "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []common.Hash{txs[0].Hash(), txs[1].Hash()}, hashes)
+	for _, tx := range txs[:2] {
+		banned, err := s.GetTxByHash(ctx, tx.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, pool.TxStatusFailed, banned.Status)
+	}
+	blocked, err := s.GetAllAddressesBlocked(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{auth.From}, blocked)
+
+	err = p.AddTx(ctx, *signTx(0, 1), ip)
+	assert.ErrorIs(t, err, pool.ErrBlockedSender)
+
+	require.NoError(t, p.UnbanSender(ctx, auth.From))
+	blocked, err = s.GetAllAddressesBlocked(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, blocked)
+	require.NoError(t, p.AddTx(ctx, *signTx(0, 1), ip))
+}
@@ -0,0 +1,45 @@
+package pool
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// SelectionDecisionSelected represents a candidate selected to be processed
+	SelectionDecisionSelected SelectionDecision = "selected"
+	// SelectionDecisionSkipped represents a candidate skipped in a selection round
+	SelectionDecisionSkipped SelectionDecision = "skipped"
+)
+
+// SelectionDecision represents the decision taken by the sequencer for a tx
+// candidate in a selection round
+type SelectionDecision string
+
+// String returns a representation of the selection decision in a string format
+func (d SelectionDecision) String() string {
+	return string(d)
+}
+
+// SelectionAuditEntry records the decision taken by the sequencer for a tx
+// candidate in a selection round, so it's possible to know why a tx was not included
+type SelectionAuditEntry struct {
+	// Round identifies the selection round, it's unique since the sequencer started
+	Round uint64
+	// Position is the position of the candidate in the list of ready txs sorted by efficiency
+	Position int
+	Hash     common.Hash
+	From     common.Address
+	Nonce    uint64
+	// Score is the efficiency used to sort the candidates, currently the gas price
+	Score *big.Int
+	// RemainingResources are the batch resources available in the round
+	RemainingResources state.BatchResources
+	Decision           SelectionDecision
+	// Reason explains why the candidate was skipped
+	Reason     string
+	SelectedAt time.Time
+}
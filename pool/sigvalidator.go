@@ -0,0 +1,114 @@
+package pool
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// signatureValidator validates the signature and chain ID of the received txs in a fixed set of
+// goroutines, so the sender recovery of the txs received at the same time is done in parallel and
+// a spam wave can't use more CPUs than the configured ones
+type signatureValidator struct {
+	chainID uint64
+	jobs    chan *signatureValidationJob
+}
+
+type signatureValidationJob struct {
+	tx       *types.Transaction
+	queuedAt time.Time
+	result   chan signatureValidationResult
+}
+
+type signatureValidationResult struct {
+	from common.Address
+	err  error
+}
+
+func newSignatureValidator(cfg SignatureValidationCfg, chainID uint64) *signatureValidator {
+	v := &signatureValidator{
+		chainID: chainID,
+		jobs:    make(chan *signatureValidationJob, cfg.QueueSize),
+	}
+
+	parallelism := cfg.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+	for i := 0; i < parallelism; i++ {
+		go v.run()
+	}
+	return v
+}
+
+// validate queues the tx to validate its signature and chain ID and waits for the
+// result, it returns the sender of the tx if they are valid
+func (v *signatureValidator) validate(ctx context.Context, tx *types.Transaction) (common.Address, error) {
+	job := &signatureValidationJob{
+		tx:       tx,
+		queuedAt: time.Now(),
+		result:   make(chan signatureValidationResult, 1),
+	}
+
+	select {
+	case v.jobs <- job:
+		metrics.SignatureValidationQueueLength(len(v.jobs))
+	case <-ctx.Done():
+		return common.Address{}, ctx.Err()
+	}
+
+	select {
+	case result := <-job.result:
+		return result.from, result.err
+	case <-ctx.Done():
+		return common.Address{}, ctx.Err()
+	}
+}
+
+func (v *signatureValidator) run() {
+	for job := range v.jobs {
+		metrics.SignatureValidationQueuedTime(time.Since(job.queuedAt))
+
+		start := time.Now()
+		from, err := v.validateSignature(job.tx)
+		metrics.SignatureValidationTime(time.Since(start))
+		if err != nil {
+			metrics.SignatureValidated(metrics.SignatureValidatedLabelInvalid)
+		} else {
+			metrics.SignatureValidated(metrics.SignatureValidatedLabelValid)
+		}
+
+		job.result <- signatureValidationResult{from: from, err: err}
+	}
+}
+
+// validateSignature checks the signature and chain ID of the tx and recovers its sender
+func (v *signatureValidator) validateSignature(tx *types.Transaction) (common.Address, error) {
+	// Make sure the transaction is signed properly.
+	if err := state.CheckSignature(*tx); err != nil {
+		return common.Address{}, ErrInvalidSender
+	}
+
+	// check chain id
+	txChainID := tx.ChainId().Uint64()
+	if txChainID != v.chainID && txChainID != 0 {
+		return common.Address{}, ErrInvalidChainID
+	}
+
+	// check Pre EIP155 txs signature
+	if txChainID == 0 && !state.IsPreEIP155Tx(*tx) {
+		return common.Address{}, ErrInvalidSender
+	}
+
+	// gets tx sender for validations
+	from, err := state.GetSender(*tx)
+	if err != nil {
+		return common.Address{}, ErrInvalidSender
+	}
+	return from, nil
+}
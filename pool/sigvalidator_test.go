@@ -0,0 +1,48 @@
+package pool
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureValidator(t *testing.T) {
+	const chainID = 1001
+	ctx := context.Background()
+	v := newSignatureValidator(SignatureValidationCfg{Parallelism: 2, QueueSize: 10}, chainID)
+
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	newSignedTx := func(txChainID int64) *types.Transaction {
+		tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(txChainID)), privateKey)
+		require.NoError(t, err)
+		return signedTx
+	}
+
+	sender, err := v.validate(ctx, newSignedTx(chainID))
+	require.NoError(t, err)
+	assert.Equal(t, from, sender)
+
+	_, err = v.validate(ctx, newSignedTx(chainID+1))
+	assert.ErrorIs(t, err, ErrInvalidChainID)
+
+	unsignedTx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+	_, err = v.validate(ctx, unsignedTx)
+	assert.ErrorIs(t, err, ErrInvalidSender)
+
+	// the txs are not queued once the context is done
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	stoppedValidator := &signatureValidator{chainID: chainID, jobs: make(chan *signatureValidationJob)}
+	_, err = stoppedValidator.validate(canceledCtx, newSignedTx(chainID))
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,105 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// sponsoredTxs identifies the sponsored txs, accepted with zero gas price, and applies their rate limits.
+// The rate limits are counted in memory, so they apply to each pool instance
+type sponsoredTxs struct {
+	cfg       SponsoredTxsCfg
+	contracts map[common.Address]struct{}
+	selectors map[[4]byte]struct{}
+
+	// periodStart is the start of the current rate limit period
+	periodStart time.Time
+	// txs is the number of sponsored txs accepted during the current rate limit period
+	txs uint64
+	// txsPerSender is the number of sponsored txs accepted from each sender during the current rate limit period
+	txsPerSender map[common.Address]uint64
+	mutex        sync.Mutex
+}
+
+func newSponsoredTxs(cfg SponsoredTxsCfg) (*sponsoredTxs, error) {
+	s := &sponsoredTxs{
+		cfg:          cfg,
+		contracts:    make(map[common.Address]struct{}, len(cfg.Contracts)),
+		selectors:    make(map[[4]byte]struct{}, len(cfg.Selectors)),
+		txsPerSender: make(map[common.Address]uint64),
+	}
+	if !cfg.Enabled {
+		return s, nil
+	}
+
+	if len(cfg.Contracts) == 0 {
+		return nil, errors.New("sponsored txs enabled without contracts")
+	}
+	for _, contract := range cfg.Contracts {
+		s.contracts[contract] = struct{}{}
+	}
+	for _, value := range cfg.Selectors {
+		selector := common.FromHex(value)
+		if len(selector) != len([4]byte{}) {
+			return nil, fmt.Errorf("invalid sponsored txs selector %q, it must be 4 bytes in hex format", value)
+		}
+		s.selectors[[4]byte(selector)] = struct{}{}
+	}
+	return s, nil
+}
+
+// isSponsored returns true if the tx has zero gas price and calls one of the sponsored contracts and selectors
+func (s *sponsoredTxs) isSponsored(tx types.Transaction) bool {
+	if !s.cfg.Enabled || tx.To() == nil || state.GetTxGasPrice(tx).Sign() != 0 {
+		return false
+	}
+	if _, found := s.contracts[*tx.To()]; !found {
+		return false
+	}
+	if len(s.selectors) > 0 {
+		if len(tx.Data()) < len([4]byte{}) {
+			return false
+		}
+		if _, found := s.selectors[[4]byte(tx.Data()[:len([4]byte{})])]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks the gas limit of the sponsored tx
+func (s *sponsoredTxs) validate(tx types.Transaction) error {
+	if s.cfg.MaxGas > 0 && tx.Gas() > s.cfg.MaxGas {
+		return fmt.Errorf("%w, max %d", ErrSponsoredTxGasLimit, s.cfg.MaxGas)
+	}
+	return nil
+}
+
+// allow counts a sponsored tx of the sender if neither the sender nor the pool have
+// reached their limit of sponsored txs of the current rate limit period
+func (s *sponsoredTxs) allow(sender common.Address, now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if now.Sub(s.periodStart) >= s.cfg.RateLimitPeriod.Duration {
+		s.periodStart = now
+		s.txs = 0
+		s.txsPerSender = make(map[common.Address]uint64)
+	}
+
+	if s.cfg.MaxTxs > 0 && s.txs >= s.cfg.MaxTxs {
+		return ErrSponsoredTxRateLimit
+	}
+	if s.cfg.MaxTxsPerSender > 0 && s.txsPerSender[sender] >= s.cfg.MaxTxsPerSender {
+		return ErrSponsoredTxRateLimit
+	}
+	s.txs++
+	s.txsPerSender[sender]++
+	return nil
+}
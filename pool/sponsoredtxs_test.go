@@ -0,0 +1,59 @@
+package pool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSponsoredTxs(t *testing.T) {
+	bridge := common.HexToAddress("0x1")
+	sponsored, err := newSponsoredTxs(SponsoredTxsCfg{
+		Enabled:         true,
+		Contracts:       []common.Address{bridge},
+		Selectors:       []string{"0x2cffd02e"},
+		MaxGas:          100000,
+		RateLimitPeriod: cfgTypes.NewDuration(time.Hour),
+		MaxTxsPerSender: 1,
+		MaxTxs:          2,
+	})
+	require.NoError(t, err)
+
+	claim := []byte{0x2c, 0xff, 0xd0, 0x2e, 0x01}
+	newTx := func(to common.Address, gasPrice int64, gas uint64, data []byte) types.Transaction {
+		return *types.NewTx(&types.LegacyTx{To: &to, Value: big.NewInt(0), Gas: gas, GasPrice: big.NewInt(gasPrice), Data: data})
+	}
+
+	assert.True(t, sponsored.isSponsored(newTx(bridge, 0, 21000, claim)))
+	assert.False(t, sponsored.isSponsored(newTx(bridge, 1, 21000, claim)), "paid txs aren't sponsored")
+	assert.False(t, sponsored.isSponsored(newTx(common.HexToAddress("0x2"), 0, 21000, claim)), "contract doesn't match")
+	assert.False(t, sponsored.isSponsored(newTx(bridge, 0, 21000, []byte{0x01, 0x02, 0x03, 0x04})), "selector doesn't match")
+	assert.False(t, sponsored.isSponsored(newTx(bridge, 0, 21000, nil)), "no data")
+
+	assert.NoError(t, sponsored.validate(newTx(bridge, 0, 100000, claim)))
+	assert.ErrorIs(t, sponsored.validate(newTx(bridge, 0, 100001, claim)), ErrSponsoredTxGasLimit)
+
+	now := time.Now()
+	sender1, sender2, sender3 := common.HexToAddress("0x11"), common.HexToAddress("0x12"), common.HexToAddress("0x13")
+	assert.NoError(t, sponsored.allow(sender1, now))
+	assert.ErrorIs(t, sponsored.allow(sender1, now), ErrSponsoredTxRateLimit, "sender limit reached")
+	assert.NoError(t, sponsored.allow(sender2, now))
+	assert.ErrorIs(t, sponsored.allow(sender3, now), ErrSponsoredTxRateLimit, "global limit reached")
+	// the limits are reset once the period ends
+	assert.NoError(t, sponsored.allow(sender1, now.Add(time.Hour)))
+
+	disabled, err := newSponsoredTxs(SponsoredTxsCfg{Contracts: []common.Address{bridge}})
+	require.NoError(t, err)
+	assert.False(t, disabled.isSponsored(newTx(bridge, 0, 21000, claim)))
+
+	_, err = newSponsoredTxs(SponsoredTxsCfg{Enabled: true})
+	assert.Error(t, err)
+	_, err = newSponsoredTxs(SponsoredTxsCfg{Enabled: true, Contracts: []common.Address{bridge}, Selectors: []string{"0x2cff"}})
+	assert.Error(t, err)
+}
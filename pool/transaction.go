@@ -17,6 +17,11 @@ const (
 	TxStatusSelected TxStatus = "selected"
 	// TxStatusFailed represents a tx that has been failed after processing
 	TxStatusFailed TxStatus = "failed"
+	// TxStatusExpired represents a tx that has been evicted because it was in the pool for too long
+	TxStatusExpired TxStatus = "expired"
+	// TxStatusDeleted represents a tx that has been deleted from the pool, it's
+	// never stored as the status of a tx, it's only reported for the dropped txs
+	TxStatusDeleted TxStatus = "deleted"
 )
 
 // TxStatus represents the state of a tx
@@ -35,6 +40,17 @@ type TxStatusUpdateInfo struct {
 	FailedReason *string
 }
 
+// DroppedTx represents a pool tx that has been dropped from the pool
+// because it failed, it was invalid, it expired or it was deleted
+type DroppedTx struct {
+	Hash      common.Hash
+	From      common.Address
+	Nonce     uint64
+	Status    TxStatus
+	Reason    string
+	DroppedAt time.Time
+}
+
 // Transaction represents a pool tx
 type Transaction struct {
 	types.Transaction
@@ -45,6 +61,11 @@ type Transaction struct {
 	IsWIP                 bool
 	IP                    string
 	FailedReason          *string
+	Conditions            *TxConditions
+	// Tag is the operator-defined tag of the tx, empty if it doesn't match any tag
+	Tag string
+	// Sponsored is true if the tx was accepted with zero gas price because it calls a sponsored contract
+	Sponsored bool
 }
 
 // NewTransaction creates a new transaction
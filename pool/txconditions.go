@@ -0,0 +1,108 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// MaxTxConditionsStorageSlots is the max number of storage slots that can be
+	// checked by the known accounts conditions of a single conditional tx, they are
+	// read one by one from the executor by the sequencer while it's filling a batch
+	MaxTxConditionsStorageSlots = 32
+)
+
+// KnownAccount contains the expected storage of an account for a conditional tx,
+// either the storage root or the values of a set of storage slots. The storage root
+// conditions are rejected because the state tree has no storage root per account
+type KnownAccount struct {
+	StorageRoot  *common.Hash                `json:"storageRoot,omitempty"`
+	StorageSlots map[common.Hash]common.Hash `json:"storageSlots,omitempty"`
+}
+
+// TxConditions contains the preconditions of a conditional tx sent using
+// eth_sendRawTransactionConditional, the tx is dropped from the pool if they
+// are not met when it's selected to be included in a batch
+type TxConditions struct {
+	KnownAccounts  map[common.Address]KnownAccount `json:"knownAccounts,omitempty"`
+	BlockNumberMin *uint64                         `json:"blockNumberMin,omitempty"`
+	BlockNumberMax *uint64                         `json:"blockNumberMax,omitempty"`
+	TimestampMin   *uint64                         `json:"timestampMin,omitempty"`
+	TimestampMax   *uint64                         `json:"timestampMax,omitempty"`
+}
+
+// StorageGetter returns the value of the storage slot of an account
+type StorageGetter func(ctx context.Context, address common.Address, slot common.Hash) (common.Hash, error)
+
+// Validate checks the conditions are well formed and supported
+func (c *TxConditions) Validate() error {
+	slots := 0
+	for address, account := range c.KnownAccounts {
+		if account.StorageRoot != nil {
+			return fmt.Errorf("%w: account %s", ErrStorageRootConditionNotSupported, address.String())
+		}
+		slots += len(account.StorageSlots)
+	}
+	if slots > MaxTxConditionsStorageSlots {
+		return fmt.Errorf("%w: %d storage slots, max %d", ErrTooManyTxConditions, slots, MaxTxConditionsStorageSlots)
+	}
+	if c.BlockNumberMin != nil && c.BlockNumberMax != nil && *c.BlockNumberMin > *c.BlockNumberMax {
+		return fmt.Errorf("%w: blockNumberMin %d > blockNumberMax %d", ErrInvalidTxConditions, *c.BlockNumberMin, *c.BlockNumberMax)
+	}
+	if c.TimestampMin != nil && c.TimestampMax != nil && *c.TimestampMin > *c.TimestampMax {
+		return fmt.Errorf("%w: timestampMin %d > timestampMax %d", ErrInvalidTxConditions, *c.TimestampMin, *c.TimestampMax)
+	}
+	return nil
+}
+
+// Check checks the conditions against the block number and the timestamp of the
+// L2 block where the tx is going to be included and the storage of the known accounts
+func (c *TxConditions) Check(ctx context.Context, blockNumber uint64, timestamp time.Time, getStorage StorageGetter) error {
+	if c.BlockNumberMin != nil && blockNumber < *c.BlockNumberMin {
+		return fmt.Errorf("%w: block number %d lower than min %d", ErrTxConditionsNotMet, blockNumber, *c.BlockNumberMin)
+	}
+	if c.BlockNumberMax != nil && blockNumber > *c.BlockNumberMax {
+		return fmt.Errorf("%w: block number %d greater than max %d", ErrTxConditionsNotMet, blockNumber, *c.BlockNumberMax)
+	}
+	ts := uint64(timestamp.Unix())
+	if c.TimestampMin != nil && ts < *c.TimestampMin {
+		return fmt.Errorf("%w: timestamp %d lower than min %d", ErrTxConditionsNotMet, ts, *c.TimestampMin)
+	}
+	if c.TimestampMax != nil && ts > *c.TimestampMax {
+		return fmt.Errorf("%w: timestamp %d greater than max %d", ErrTxConditionsNotMet, ts, *c.TimestampMax)
+	}
+
+	for address, account := range c.KnownAccounts {
+		if account.StorageRoot != nil {
+			return fmt.Errorf("%w: account %s", ErrStorageRootConditionNotSupported, address.String())
+		}
+		for slot, expected := range account.StorageSlots {
+			value, err := getStorage(ctx, address, slot)
+			if err != nil {
+				return err
+			}
+			if value != expected {
+				return fmt.Errorf("%w: account %s slot %s has value %s, expected %s",
+					ErrTxConditionsNotMet, address.String(), slot.String(), value.String(), expected.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewStorageGetter returns a StorageGetter that reads the storage from the
+// provided state root using the given state storage reader
+func NewStorageGetter(root common.Hash, getStorageAt func(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error)) StorageGetter {
+	return func(ctx context.Context, address common.Address, slot common.Hash) (common.Hash, error) {
+		value, err := getStorageAt(ctx, address, slot.Big(), root)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return common.BigToHash(value), nil
+	}
+}
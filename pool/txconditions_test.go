@@ -0,0 +1,48 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxConditions(t *testing.T) {
+	ctx := context.Background()
+	address := common.HexToAddress("0x1")
+	slot := common.HexToHash("0x1")
+	storage := map[common.Hash]common.Hash{slot: common.HexToHash("0x2")}
+	getStorage := func(ctx context.Context, a common.Address, s common.Hash) (common.Hash, error) {
+		return storage[s], nil
+	}
+	uint64Ptr := func(v uint64) *uint64 { return &v }
+	now := time.Unix(100, 0) //nolint:gomnd
+
+	conditions := TxConditions{
+		KnownAccounts: map[common.Address]KnownAccount{
+			address: {StorageSlots: map[common.Hash]common.Hash{slot: common.HexToHash("0x2")}},
+		},
+		BlockNumberMin: uint64Ptr(10),
+		BlockNumberMax: uint64Ptr(20),
+		TimestampMin:   uint64Ptr(50),
+		TimestampMax:   uint64Ptr(150),
+	}
+	require.NoError(t, conditions.Validate())
+	assert.NoError(t, conditions.Check(ctx, 15, now, getStorage))
+	assert.ErrorIs(t, conditions.Check(ctx, 9, now, getStorage), ErrTxConditionsNotMet)
+	assert.ErrorIs(t, conditions.Check(ctx, 21, now, getStorage), ErrTxConditionsNotMet)
+	assert.ErrorIs(t, conditions.Check(ctx, 15, time.Unix(151, 0), getStorage), ErrTxConditionsNotMet)
+
+	storage[slot] = common.HexToHash("0x3")
+	assert.ErrorIs(t, conditions.Check(ctx, 15, now, getStorage), ErrTxConditionsNotMet)
+
+	invalid := TxConditions{BlockNumberMin: uint64Ptr(2), BlockNumberMax: uint64Ptr(1)}
+	assert.ErrorIs(t, invalid.Validate(), ErrInvalidTxConditions)
+
+	root := common.HexToHash("0x1")
+	withRoot := TxConditions{KnownAccounts: map[common.Address]KnownAccount{address: {StorageRoot: &root}}}
+	assert.ErrorIs(t, withRoot.Validate(), ErrStorageRootConditionNotSupported)
+}
@@ -0,0 +1,97 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// TxEndpointSendRawTransaction is the endpoint of the txs sent through eth_sendRawTransaction
+	TxEndpointSendRawTransaction = "eth_sendRawTransaction"
+	// TxEndpointSendRawTransactionConditional is the endpoint of the txs sent through eth_sendRawTransactionConditional
+	TxEndpointSendRawTransactionConditional = "eth_sendRawTransactionConditional"
+)
+
+// txTag is a parsed TxTagCfg
+type txTag struct {
+	name      string
+	senders   map[common.Address]struct{}
+	selectors map[[4]byte]struct{}
+	endpoints map[string]struct{}
+}
+
+// txTagger tags the received txs with the first operator-defined tag they match
+type txTagger struct {
+	tags []txTag
+}
+
+func newTxTagger(cfgs []TxTagCfg) (*txTagger, error) {
+	t := &txTagger{tags: make([]txTag, 0, len(cfgs))}
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			return nil, errors.New("tx tag without name")
+		}
+
+		tag := txTag{
+			name:      cfg.Name,
+			senders:   make(map[common.Address]struct{}, len(cfg.Senders)),
+			selectors: make(map[[4]byte]struct{}, len(cfg.Selectors)),
+			endpoints: make(map[string]struct{}, len(cfg.Endpoints)),
+		}
+		for _, sender := range cfg.Senders {
+			tag.senders[sender] = struct{}{}
+		}
+		for _, value := range cfg.Selectors {
+			selector := common.FromHex(value)
+			if len(selector) != len([4]byte{}) {
+				return nil, fmt.Errorf("invalid selector %q of tx tag %s, it must be 4 bytes in hex format", value, cfg.Name)
+			}
+			tag.selectors[[4]byte(selector)] = struct{}{}
+		}
+		for _, endpoint := range cfg.Endpoints {
+			tag.endpoints[endpoint] = struct{}{}
+		}
+		t.tags = append(t.tags, tag)
+	}
+	return t, nil
+}
+
+// tag returns the name of the first tag matched by the tx sent by the sender through the endpoint,
+// or an empty string if it doesn't match any tag
+func (t *txTagger) tag(tx types.Transaction, sender common.Address, endpoint string) string {
+	for _, tag := range t.tags {
+		if tag.matches(tx, sender, endpoint) {
+			return tag.name
+		}
+	}
+	return ""
+}
+
+// matches returns true if the tx matches all the criteria of the tag that are not empty
+func (t *txTag) matches(tx types.Transaction, sender common.Address, endpoint string) bool {
+	if len(t.senders) == 0 && len(t.selectors) == 0 && len(t.endpoints) == 0 {
+		return false
+	}
+	if len(t.senders) > 0 {
+		if _, found := t.senders[sender]; !found {
+			return false
+		}
+	}
+	if len(t.selectors) > 0 {
+		if len(tx.Data()) < len([4]byte{}) {
+			return false
+		}
+		if _, found := t.selectors[[4]byte(tx.Data()[:len([4]byte{})])]; !found {
+			return false
+		}
+	}
+	if len(t.endpoints) > 0 {
+		if _, found := t.endpoints[endpoint]; !found {
+			return false
+		}
+	}
+	return true
+}
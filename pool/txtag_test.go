@@ -0,0 +1,52 @@
+package pool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxTagger(t *testing.T) {
+	settler := common.HexToAddress("0x1")
+	tagger, err := newTxTagger([]TxTagCfg{
+		{Name: "batch-settlement", Senders: []common.Address{settler}, Selectors: []string{"0xa9059cbb"}},
+		{Name: "conditional", Endpoints: []string{TxEndpointSendRawTransactionConditional}},
+		{Name: "empty"},
+	})
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x2")
+	newTx := func(data []byte) types.Transaction {
+		return *types.NewTx(&types.LegacyTx{To: &to, Value: big.NewInt(0), Gas: 21000, GasPrice: big.NewInt(1), Data: data})
+	}
+
+	testCases := []struct {
+		name        string
+		tx          types.Transaction
+		sender      common.Address
+		endpoint    string
+		expectedTag string
+	}{
+		{"all the criteria match", newTx([]byte{0xa9, 0x05, 0x9c, 0xbb, 0x01}), settler, TxEndpointSendRawTransaction, "batch-settlement"},
+		{"selector doesn't match", newTx([]byte{0x01, 0x02, 0x03, 0x04}), settler, TxEndpointSendRawTransaction, ""},
+		{"sender doesn't match", newTx([]byte{0xa9, 0x05, 0x9c, 0xbb}), to, TxEndpointSendRawTransaction, ""},
+		{"no data", newTx(nil), settler, TxEndpointSendRawTransaction, ""},
+		{"first matching tag", newTx([]byte{0xa9, 0x05, 0x9c, 0xbb}), settler, TxEndpointSendRawTransactionConditional, "batch-settlement"},
+		{"endpoint matches", newTx(nil), to, TxEndpointSendRawTransactionConditional, "conditional"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expectedTag, tagger.tag(testCase.tx, testCase.sender, testCase.endpoint))
+		})
+	}
+
+	_, err = newTxTagger([]TxTagCfg{{Name: "invalid", Selectors: []string{"0xa9"}}})
+	assert.Error(t, err)
+	_, err = newTxTagger([]TxTagCfg{{Senders: []common.Address{settler}}})
+	assert.Error(t, err)
+}
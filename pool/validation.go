@@ -1,8 +1,27 @@
 package pool
 
-import "net"
+import (
+	"math/big"
+	"net"
+)
 
 // IsValidIP returns true if the given string is a valid IP address
 func IsValidIP(ip string) bool {
 	return ip != "" && net.ParseIP(ip) != nil
 }
+
+// getMaxGasPriceAllowed returns the max gas price accepted for a tx, the lower of the absolute
+// limit and the multiple of the min suggested gas price. It returns nil if both limits are disabled
+func getMaxGasPriceAllowed(minSuggestedGasPrice *big.Int, maxGasPriceAllowed uint64, maxGasPriceFactor float64) *big.Int {
+	var maxGasPrice *big.Int
+	if maxGasPriceAllowed > 0 {
+		maxGasPrice = new(big.Int).SetUint64(maxGasPriceAllowed)
+	}
+	if maxGasPriceFactor > 0 {
+		factorGasPrice, _ := new(big.Float).Mul(new(big.Float).SetInt(minSuggestedGasPrice), big.NewFloat(maxGasPriceFactor)).Int(nil)
+		if maxGasPrice == nil || factorGasPrice.Cmp(maxGasPrice) < 0 {
+			maxGasPrice = factorGasPrice
+		}
+	}
+	return maxGasPrice
+}
@@ -1,6 +1,7 @@
 package pool
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,3 +26,26 @@ func Test_IsValidIP(t *testing.T) {
 		})
 	}
 }
+
+func Test_getMaxGasPriceAllowed(t *testing.T) {
+	minSuggestedGasPrice := big.NewInt(1000000000)
+	var tests = []struct {
+		name               string
+		maxGasPriceAllowed uint64
+		maxGasPriceFactor  float64
+		expected           *big.Int
+	}{
+		{"Disabled", 0, 0, nil},
+		{"Absolute limit", 5000000000, 0, big.NewInt(5000000000)},
+		{"Factor limit", 0, 2.5, big.NewInt(2500000000)},
+		{"Absolute limit lower than factor limit", 2000000000, 10, big.NewInt(2000000000)},
+		{"Factor limit lower than absolute limit", 20000000000, 10, big.NewInt(10000000000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getMaxGasPriceAllowed(minSuggestedGasPrice, tt.maxGasPriceAllowed, tt.maxGasPriceFactor)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package replay
+
+import "github.com/0xPolygonHermez/zkevm-node/config/types"
+
+const (
+	// ModeRecord records the external inputs of the node in the file
+	ModeRecord = "record"
+	// ModeReplay serves the external inputs recorded in the file instead of the real sources
+	ModeReplay = "replay"
+)
+
+// Config represents the configuration of the recording and the replay of the external inputs of
+// the node: the L1 reads of the synchronizer, the txs submitted to the pool through the JSON-RPC
+// server and the executor responses. A recording replayed against a fresh node, with empty
+// databases and the same config, reproduces the same execution deterministically
+type Config struct {
+	// Mode is record or replay, empty disables the recording and the replay
+	Mode string `mapstructure:"Mode"`
+
+	// File is the file the inputs are recorded to or replayed from, one JSON entry per line
+	File string `mapstructure:"File"`
+
+	// Window is the time the inputs are recorded for since the node starts, 0 records until the node stops
+	Window types.Duration `mapstructure:"Window"`
+}
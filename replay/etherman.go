@@ -0,0 +1,121 @@
+package replay
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// etherMan records or replays the L1 reads of the synchronizer
+type etherMan struct {
+	synchronizer.EthermanInterface
+	session *session
+}
+
+// rollupInfo are the responses of the methods returning the L1 blocks with the rollup events
+type rollupInfo struct {
+	Blocks []etherman.Block                 `json:"blocks"`
+	Order  map[common.Hash][]etherman.Order `json:"order"`
+}
+
+// WrapEtherman returns the etherman recording or replaying the L1 reads of the synchronizer, the
+// etherman is returned as it is when the recording and the replay are disabled
+func WrapEtherman(e synchronizer.EthermanInterface) synchronizer.EthermanInterface {
+	s := current.Load()
+	if s == nil {
+		return e
+	}
+	return &etherMan{EthermanInterface: e, session: s}
+}
+
+// HeaderByNumber returns the header of the L1 block, the latest one if the number is nil
+func (e *etherMan) HeaderByNumber(ctx context.Context, number *big.Int) (*ethTypes.Header, error) {
+	return call(e.session, SourceL1, "HeaderByNumber", number, jsonCodec[*ethTypes.Header](), func() (*ethTypes.Header, error) {
+		return e.EthermanInterface.HeaderByNumber(ctx, number)
+	})
+}
+
+// GetRollupInfoByBlockRange returns the rollup events of the L1 block range
+func (e *etherMan) GetRollupInfoByBlockRange(ctx context.Context, fromBlock uint64, toBlock *uint64) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	request := struct {
+		FromBlock uint64  `json:"fromBlock"`
+		ToBlock   *uint64 `json:"toBlock"`
+	}{fromBlock, toBlock}
+	info, err := call(e.session, SourceL1, "GetRollupInfoByBlockRange", request, jsonCodec[rollupInfo](), func() (rollupInfo, error) {
+		blocks, order, err := e.EthermanInterface.GetRollupInfoByBlockRange(ctx, fromBlock, toBlock)
+		return rollupInfo{Blocks: blocks, Order: order}, err
+	})
+	return info.Blocks, info.Order, err
+}
+
+// EthBlockByNumber returns the L1 block, it's recorded with its RLP encoding
+func (e *etherMan) EthBlockByNumber(ctx context.Context, blockNumber uint64) (*ethTypes.Block, error) {
+	return call(e.session, SourceL1, "EthBlockByNumber", blockNumber, rlpBlockCodec(), func() (*ethTypes.Block, error) {
+		return e.EthermanInterface.EthBlockByNumber(ctx, blockNumber)
+	})
+}
+
+// GetLatestBatchNumber returns the last batch sequenced in L1
+func (e *etherMan) GetLatestBatchNumber() (uint64, error) {
+	return call(e.session, SourceL1, "GetLatestBatchNumber", nil, jsonCodec[uint64](), e.EthermanInterface.GetLatestBatchNumber)
+}
+
+// GetTrustedSequencerURL returns the URL of the trusted sequencer set in L1
+func (e *etherMan) GetTrustedSequencerURL() (string, error) {
+	return call(e.session, SourceL1, "GetTrustedSequencerURL", nil, jsonCodec[string](), e.EthermanInterface.GetTrustedSequencerURL)
+}
+
+// VerifyGenBlockNumber checks the genesis block number against L1
+func (e *etherMan) VerifyGenBlockNumber(ctx context.Context, genBlockNumber uint64) (bool, error) {
+	return call(e.session, SourceL1, "VerifyGenBlockNumber", genBlockNumber, jsonCodec[bool](), func() (bool, error) {
+		return e.EthermanInterface.VerifyGenBlockNumber(ctx, genBlockNumber)
+	})
+}
+
+// GetLatestVerifiedBatchNum returns the last batch verified in L1
+func (e *etherMan) GetLatestVerifiedBatchNum() (uint64, error) {
+	return call(e.session, SourceL1, "GetLatestVerifiedBatchNum", nil, jsonCodec[uint64](), e.EthermanInterface.GetLatestVerifiedBatchNum)
+}
+
+// GetSequencedBatchesInfo returns the data stored in L1 for the sequence ending in the batch
+func (e *etherMan) GetSequencedBatchesInfo(ctx context.Context, lastBatchNumber uint64) (ethmanTypes.SequencedBatchesInfo, error) {
+	return call(e.session, SourceL1, "GetSequencedBatchesInfo", lastBatchNumber, jsonCodec[ethmanTypes.SequencedBatchesInfo](), func() (ethmanTypes.SequencedBatchesInfo, error) {
+		return e.EthermanInterface.GetSequencedBatchesInfo(ctx, lastBatchNumber)
+	})
+}
+
+// DecodeEvents decodes the rollup events of the L1 logs
+func (e *etherMan) DecodeEvents(ctx context.Context, logs []ethTypes.Log) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	info, err := call(e.session, SourceL1, "DecodeEvents", logs, jsonCodec[rollupInfo](), func() (rollupInfo, error) {
+		blocks, order, err := e.EthermanInterface.DecodeEvents(ctx, logs)
+		return rollupInfo{Blocks: blocks, Order: order}, err
+	})
+	return info.Blocks, info.Order, err
+}
+
+// rlpBlockCodec encodes the L1 blocks with their RLP encoding, their JSON format doesn't include the txs
+func rlpBlockCodec() codec[*ethTypes.Block] {
+	return codec[*ethTypes.Block]{
+		encode: func(b *ethTypes.Block) ([]byte, error) {
+			data, err := rlp.EncodeToBytes(b)
+			if err != nil {
+				return nil, err
+			}
+			return jsonCodec[[]byte]().encode(data)
+		},
+		decode: func(data []byte) (*ethTypes.Block, error) {
+			raw, err := jsonCodec[[]byte]().decode(data)
+			if err != nil {
+				return nil, err
+			}
+			b := new(ethTypes.Block)
+			return b, rlp.DecodeBytes(raw, b)
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// executorClient records or replays the responses of the executor
+type executorClient struct {
+	executor.ExecutorServiceClient
+	session *session
+}
+
+// WrapExecutorClient returns the executor client recording or replaying its responses, the
+// client is returned as it is when the recording and the replay are disabled
+func WrapExecutorClient(c executor.ExecutorServiceClient) executor.ExecutorServiceClient {
+	s := current.Load()
+	if s == nil {
+		return c
+	}
+	return &executorClient{ExecutorServiceClient: c, session: s}
+}
+
+// ProcessBatch processes the batch in the executor, the context id of the request is ignored to
+// match the calls since it's random for each call
+func (c *executorClient) ProcessBatch(ctx context.Context, in *executor.ProcessBatchRequest, opts ...grpc.CallOption) (*executor.ProcessBatchResponse, error) {
+	request, ok := proto.Clone(in).(*executor.ProcessBatchRequest)
+	if !ok {
+		return c.ExecutorServiceClient.ProcessBatch(ctx, in, opts...)
+	}
+	request.ContextId = ""
+	return call(c.session, SourceExecutor, "ProcessBatch", protoRequest(request), protoCodec[*executor.ProcessBatchResponse](),
+		func() (*executor.ProcessBatchResponse, error) {
+			return c.ExecutorServiceClient.ProcessBatch(ctx, in, opts...)
+		})
+}
+
+// GetFlushStatus returns the flush status of the executor
+func (c *executorClient) GetFlushStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*executor.GetFlushStatusResponse, error) {
+	return call(c.session, SourceExecutor, "GetFlushStatus", nil, protoCodec[*executor.GetFlushStatusResponse](),
+		func() (*executor.GetFlushStatusResponse, error) {
+			return c.ExecutorServiceClient.GetFlushStatus(ctx, in, opts...)
+		})
+}
+
+// protoRequest returns the JSON format of the proto request, nil if it can't be encoded
+func protoRequest(m proto.Message) json.RawMessage {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// protoCodec encodes the proto responses with their JSON format
+func protoCodec[T proto.Message]() codec[T] {
+	return codec[T]{
+		encode: func(m T) ([]byte, error) { return protojson.Marshal(m) },
+		decode: func(data []byte) (T, error) {
+			var m T
+			m, _ = m.ProtoReflect().New().Interface().(T)
+			err := protojson.Unmarshal(data, m)
+			return m, err
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// poolSubmission is the request of a tx submitted to the pool
+type poolSubmission struct {
+	Tx         hexutil.Bytes      `json:"tx"`
+	IP         string             `json:"ip"`
+	Conditions *pool.TxConditions `json:"conditions,omitempty"`
+}
+
+// recordingPool records the txs submitted to the pool through the JSON-RPC server
+type recordingPool struct {
+	types.PoolInterface
+	recorder *recorder
+}
+
+// WrapPool returns the pool recording the txs submitted to it, the pool is returned as it is
+// when the inputs are not being recorded, the recorded txs are submitted by ReplayPool
+func WrapPool(p types.PoolInterface) types.PoolInterface {
+	s := current.Load()
+	if s == nil || s.recorder == nil {
+		return p
+	}
+	return &recordingPool{PoolInterface: p, recorder: s.recorder}
+}
+
+// AddTx adds the tx to the pool
+func (p *recordingPool) AddTx(ctx context.Context, tx ethTypes.Transaction, ip string) error {
+	err := p.PoolInterface.AddTx(ctx, tx, ip)
+	p.record("AddTx", tx, ip, nil, err)
+	return err
+}
+
+// AddConditionalTx adds the tx with the conditions to the pool
+func (p *recordingPool) AddConditionalTx(ctx context.Context, tx ethTypes.Transaction, ip string, conditions *pool.TxConditions) error {
+	err := p.PoolInterface.AddConditionalTx(ctx, tx, ip, conditions)
+	p.record("AddConditionalTx", tx, ip, conditions, err)
+	return err
+}
+
+func (p *recordingPool) record(method string, tx ethTypes.Transaction, ip string, conditions *pool.TxConditions, err error) {
+	raw, encodeErr := tx.MarshalBinary()
+	if encodeErr != nil {
+		log.Errorf("failed to encode the tx %s submitted to the pool: %v", tx.Hash().String(), encodeErr)
+		return
+	}
+	request, encodeErr := json.Marshal(poolSubmission{Tx: raw, IP: ip, Conditions: conditions})
+	if encodeErr != nil {
+		log.Errorf("failed to encode the submission of the tx %s to the pool: %v", tx.Hash().String(), encodeErr)
+		return
+	}
+	p.recorder.record(Entry{Source: SourcePool, Method: method, Key: requestKey(SourcePool, method, request), Request: request}, err)
+}
+
+// ReplayPool submits the recorded txs to the pool at the same offsets they were submitted
+// since the recording started, until all of them are submitted or the context is done
+func ReplayPool(ctx context.Context, p types.PoolInterface) {
+	s := current.Load()
+	if s == nil || s.player == nil {
+		return
+	}
+
+	start := time.Now()
+	for _, e := range s.player.submissions {
+		if wait := time.Until(start.Add(e.Offset)); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		var submission poolSubmission
+		if err := json.Unmarshal(e.Request, &submission); err != nil {
+			log.Errorf("failed to decode the recorded submission %d: %v", e.Seq, err)
+			continue
+		}
+		tx := new(ethTypes.Transaction)
+		if err := tx.UnmarshalBinary(submission.Tx); err != nil {
+			log.Errorf("failed to decode the tx of the recorded submission %d: %v", e.Seq, err)
+			continue
+		}
+
+		var err error
+		if e.Method == "AddConditionalTx" {
+			err = p.AddConditionalTx(ctx, *tx, submission.IP, submission.Conditions)
+		} else {
+			err = p.AddTx(ctx, *tx, submission.IP)
+		}
+		var errMessage string
+		if err != nil {
+			errMessage = err.Error()
+		}
+		if errMessage != e.Error {
+			log.Warnf("replay diverged, the recorded submission %d of the tx %s returned %q instead of %q", e.Seq, tx.Hash().String(), errMessage, e.Error)
+		}
+	}
+	log.Infof("%d recorded txs submitted to the pool", len(s.player.submissions))
+}
@@ -0,0 +1,298 @@
+// Package replay records the external inputs of the node (the L1 reads of the synchronizer, the
+// pool submissions and the executor responses) for a time window and replays them against a fresh
+// node, so the consensus and selection divergences can be reproduced deterministically.
+//
+// The calls are matched by the digest of their method and request, the calls with the same digest
+// are replayed in the order they were recorded. The calls that weren't recorded mean the replayed
+// node diverged from the recorded one, they are logged and forwarded to the real source
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// Source is the external source of a recorded input
+type Source string
+
+const (
+	// SourceL1 are the reads of L1 done by the synchronizer
+	SourceL1 Source = "l1"
+	// SourcePool are the txs submitted to the pool through the JSON-RPC server
+	SourcePool Source = "pool"
+	// SourceExecutor are the responses of the executor
+	SourceExecutor Source = "executor"
+)
+
+var (
+	// ErrUnknownMode is returned when the configured mode is not supported
+	ErrUnknownMode = errors.New("unknown replay mode")
+	// ErrFileNotConfigured is returned when the recording file is not configured
+	ErrFileNotConfigured = errors.New("replay file not configured")
+
+	// knownErrors are the errors replayed as the sentinel errors the callers check
+	knownErrors = []error{etherman.ErrNotFound}
+)
+
+// Entry is an input recorded, the request and the response use the JSON format of the method
+type Entry struct {
+	Seq      uint64          `json:"seq"`
+	Offset   time.Duration   `json:"offset"`
+	Source   Source          `json:"source"`
+	Method   string          `json:"method"`
+	Key      string          `json:"key"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// session is the recording or the replay in progress
+type session struct {
+	recorder *recorder
+	player   *player
+}
+
+// current is the session of the node, nil when the recording and the replay are disabled
+var current atomic.Pointer[session]
+
+// Init starts the recording or the replay configured
+func Init(cfg Config) error {
+	if cfg.Mode == "" {
+		return nil
+	}
+	if cfg.File == "" {
+		return ErrFileNotConfigured
+	}
+
+	var s session
+	switch cfg.Mode {
+	case ModeRecord:
+		r, err := newRecorder(cfg)
+		if err != nil {
+			return err
+		}
+		s.recorder = r
+		log.Infof("recording the external inputs to %s", cfg.File)
+	case ModeReplay:
+		p, err := newPlayer(cfg.File)
+		if err != nil {
+			return err
+		}
+		s.player = p
+		log.Infof("replaying %d external inputs from %s", p.entries, cfg.File)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownMode, cfg.Mode)
+	}
+	current.Store(&s)
+	return nil
+}
+
+// Close stops the recording in progress, flushing the file
+func Close() {
+	if s := current.Load(); s != nil && s.recorder != nil {
+		s.recorder.close()
+	}
+}
+
+// codec encodes the responses of the recorded calls
+type codec[T any] struct {
+	encode func(T) ([]byte, error)
+	decode func([]byte) (T, error)
+}
+
+// jsonCodec encodes the responses with their JSON format
+func jsonCodec[T any]() codec[T] {
+	return codec[T]{
+		encode: func(v T) ([]byte, error) { return json.Marshal(v) },
+		decode: func(data []byte) (T, error) {
+			var v T
+			err := json.Unmarshal(data, &v)
+			return v, err
+		},
+	}
+}
+
+// call records the call of the method or replays its recorded response, the request identifies the call
+func call[T any](s *session, source Source, method string, request interface{}, c codec[T], fn func() (T, error)) (T, error) {
+	if s == nil {
+		return fn()
+	}
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		log.Errorf("failed to encode the request of %s %s: %v", source, method, err)
+		return fn()
+	}
+	key := requestKey(source, method, requestData)
+
+	if s.player != nil {
+		e, found := s.player.next(key)
+		if !found {
+			log.Warnf("replay diverged, %s %s %s was not recorded, forwarding it", source, method, string(requestData))
+			return fn()
+		}
+		var response T
+		if len(e.Response) > 0 {
+			response, err = c.decode(e.Response)
+			if err != nil {
+				return response, fmt.Errorf("failed to decode the recorded response %d of %s %s: %w", e.Seq, source, method, err)
+			}
+		}
+		return response, replayedError(e.Error)
+	}
+
+	response, callErr := fn()
+	var responseData []byte
+	if callErr == nil {
+		responseData, err = c.encode(response)
+		if err != nil {
+			log.Errorf("failed to encode the response of %s %s: %v", source, method, err)
+			return response, callErr
+		}
+	}
+	s.recorder.record(Entry{Source: source, Method: method, Key: key, Request: requestData, Response: responseData}, callErr)
+	return response, callErr
+}
+
+// requestKey is the digest identifying the calls of the method with the same request
+func requestKey(source Source, method string, request []byte) string {
+	h := sha256.New()
+	h.Write([]byte(source))
+	h.Write([]byte{0})
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write(request)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replayedError returns the recorded error, as the sentinel error when it's a known one
+func replayedError(message string) error {
+	if message == "" {
+		return nil
+	}
+	for _, err := range knownErrors {
+		if err.Error() == message {
+			return err
+		}
+	}
+	return errors.New(message)
+}
+
+// recorder writes the entries recorded to the file until the window ends
+type recorder struct {
+	mutex  sync.Mutex
+	file   *os.File
+	enc    *json.Encoder
+	start  time.Time
+	window time.Duration
+	seq    uint64
+	closed bool
+}
+
+func newRecorder(cfg Config) (*recorder, error) {
+	file, err := os.Create(cfg.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the recording file: %w", err)
+	}
+	return &recorder{file: file, enc: json.NewEncoder(file), start: time.Now(), window: cfg.Window.Duration}, nil
+}
+
+func (r *recorder) record(e Entry, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.closed {
+		return
+	}
+	offset := time.Since(r.start)
+	if r.window > 0 && offset > r.window {
+		log.Infof("recording window of %s ended, %d external inputs recorded", r.window, r.seq)
+		r.closeLocked()
+		return
+	}
+
+	r.seq++
+	e.Seq = r.seq
+	e.Offset = offset
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if encodeErr := r.enc.Encode(e); encodeErr != nil {
+		log.Errorf("failed to record the entry %d of %s %s: %v", e.Seq, e.Source, e.Method, encodeErr)
+	}
+}
+
+func (r *recorder) close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.closeLocked()
+}
+
+func (r *recorder) closeLocked() {
+	if r.closed {
+		return
+	}
+	r.closed = true
+	if err := r.file.Close(); err != nil {
+		log.Errorf("failed to close the recording file: %v", err)
+	}
+}
+
+// player serves the entries recorded in the file
+type player struct {
+	mutex       sync.Mutex
+	calls       map[string][]*Entry
+	submissions []*Entry
+	entries     int
+}
+
+func newPlayer(file string) (*player, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the recording file: %w", err)
+	}
+	defer f.Close()
+	return readRecording(f)
+}
+
+// readRecording reads the entries of a recording, indexing the calls by their key
+func readRecording(r io.Reader) (*player, error) {
+	p := &player{calls: map[string][]*Entry{}}
+	dec := json.NewDecoder(r)
+	for {
+		var e Entry
+		if err := dec.Decode(&e); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read the entry %d of the recording: %w", p.entries+1, err)
+		}
+		p.entries++
+		if e.Source == SourcePool {
+			p.submissions = append(p.submissions, &e)
+			continue
+		}
+		p.calls[e.Key] = append(p.calls[e.Key], &e)
+	}
+	return p, nil
+}
+
+// next returns the next recorded call with the key
+func (p *player) next(key string) (*Entry, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	calls := p.calls[key]
+	if len(calls) == 0 {
+		return nil, false
+	}
+	p.calls[key] = calls[1:]
+	return calls[0], true
+}
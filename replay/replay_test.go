@@ -0,0 +1,130 @@
+package replay
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type ethermanStub struct {
+	synchronizer.EthermanInterface
+	calls       int
+	latestBatch uint64
+}
+
+func (e *ethermanStub) HeaderByNumber(ctx context.Context, number *big.Int) (*ethTypes.Header, error) {
+	e.calls++
+	return &ethTypes.Header{Number: number, Difficulty: big.NewInt(0), GasLimit: e.latestBatch}, nil
+}
+
+func (e *ethermanStub) GetLatestBatchNumber() (uint64, error) {
+	e.calls++
+	if e.latestBatch == 0 {
+		return 0, etherman.ErrNotFound
+	}
+	return e.latestBatch, nil
+}
+
+type executorStub struct {
+	executor.ExecutorServiceClient
+	calls     int
+	stateRoot []byte
+}
+
+func (c *executorStub) ProcessBatch(ctx context.Context, in *executor.ProcessBatchRequest, opts ...grpc.CallOption) (*executor.ProcessBatchResponse, error) {
+	c.calls++
+	return &executor.ProcessBatchResponse{NewStateRoot: c.stateRoot, CntSteps: 100}, nil
+}
+
+type poolStub struct {
+	types.PoolInterface
+	txs []common.Hash
+}
+
+func (p *poolStub) AddTx(ctx context.Context, tx ethTypes.Transaction, ip string) error {
+	p.txs = append(p.txs, tx.Hash())
+	return nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	defer current.Store(nil)
+	file := filepath.Join(t.TempDir(), "recording.jsonl")
+	ctx := context.Background()
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	request := &executor.ProcessBatchRequest{OldBatchNum: 1, ContextId: "recorded"}
+
+	require.NoError(t, Init(Config{Mode: ModeRecord, File: file}))
+	recordedEtherman := &ethermanStub{latestBatch: 7}
+	l1 := WrapEtherman(recordedEtherman)
+	header, err := l1.HeaderByNumber(ctx, big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), header.Number.Uint64())
+	latestBatch, err := l1.GetLatestBatchNumber()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), latestBatch)
+	recordedEtherman.latestBatch = 0
+	_, err = l1.GetLatestBatchNumber()
+	assert.ErrorIs(t, err, etherman.ErrNotFound)
+
+	_, err = WrapExecutorClient(&executorStub{stateRoot: []byte{1}}).ProcessBatch(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, WrapPool(&poolStub{}).AddTx(ctx, *tx, "127.0.0.1"))
+	Close()
+
+	// the replayed calls are served from the recording, in the order they were recorded
+	require.NoError(t, Init(Config{Mode: ModeReplay, File: file}))
+	replayedEtherman := &ethermanStub{latestBatch: 100}
+	l1 = WrapEtherman(replayedEtherman)
+	header, err = l1.HeaderByNumber(ctx, big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), header.Number.Uint64())
+	assert.Equal(t, uint64(7), header.GasLimit)
+	latestBatch, err = l1.GetLatestBatchNumber()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), latestBatch)
+	_, err = l1.GetLatestBatchNumber()
+	assert.ErrorIs(t, err, etherman.ErrNotFound)
+	assert.Equal(t, 0, replayedEtherman.calls)
+
+	// the calls not recorded are forwarded to the real source
+	header, err = l1.HeaderByNumber(ctx, big.NewInt(11))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), header.GasLimit)
+	assert.Equal(t, 1, replayedEtherman.calls)
+
+	// the context id of the executor requests is ignored
+	replayedExecutor := &executorStub{stateRoot: []byte{2}}
+	request.ContextId = "replayed"
+	response, err := WrapExecutorClient(replayedExecutor).ProcessBatch(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, response.NewStateRoot)
+	assert.Equal(t, uint32(100), response.CntSteps)
+	assert.Equal(t, 0, replayedExecutor.calls)
+
+	// the txs are only submitted by the replay of the pool
+	p := &poolStub{}
+	assert.Equal(t, p, WrapPool(p))
+	ReplayPool(ctx, p)
+	assert.Equal(t, []common.Hash{tx.Hash()}, p.txs)
+}
+
+func TestInit(t *testing.T) {
+	defer current.Store(nil)
+	require.NoError(t, Init(Config{}))
+	assert.Nil(t, current.Load())
+
+	assert.ErrorIs(t, Init(Config{Mode: ModeRecord}), ErrFileNotConfigured)
+	assert.ErrorIs(t, Init(Config{Mode: "unknown", File: "recording.jsonl"}), ErrUnknownMode)
+	assert.Error(t, Init(Config{Mode: ModeReplay, File: filepath.Join(t.TempDir(), "missing.jsonl")}))
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	prefix             = "retry_"
+	retriedName        = prefix + "retried"
+	exhaustedName      = prefix + "exhausted"
+	operationLabelName = "operation"
+)
+
+// Register the metrics for the retry package.
+func Register() {
+	counterVecs := []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: retriedName,
+				Help: "[RETRY] number of retries of an operation after a retryable error",
+			},
+			Labels: []string{operationLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: exhaustedName,
+				Help: "[RETRY] number of times an operation failed after running out of attempts or time",
+			},
+			Labels: []string{operationLabelName},
+		},
+	}
+
+	metrics.RegisterCounterVecs(counterVecs...)
+}
+
+// Retried increments the retries counter vector by one for the given operation.
+func Retried(operation string) {
+	metrics.CounterVecInc(retriedName, operation)
+}
+
+// Exhausted increments the exhausted retries counter vector by one for the
+// given operation.
+func Exhausted(operation string) {
+	metrics.CounterVecInc(exhaustedName, operation)
+}
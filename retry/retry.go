@@ -0,0 +1,160 @@
+// Package retry contains the retry policies shared by the components of the node to run
+// the operations that can fail transiently, like the calls to L1, the executor or the databases
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/retry/metrics"
+)
+
+var (
+	registerMetrics sync.Once
+	// random returns a number in [0, 1), it's overridden by the tests
+	random = rand.Float64 //nolint:gosec
+)
+
+// Config is the retry policy of an operation. The operation runs until it succeeds, it fails with
+// a non retryable error, the attempts or the elapsed time are exhausted or the context is done.
+// The zero value runs the operation once
+type Config struct {
+	// MaxAttempts is the max number of times the operation runs, including the first one, 0 doesn't
+	// limit the attempts when MaxElapsedTime is set and disables the retries otherwise
+	MaxAttempts int `mapstructure:"MaxAttempts"`
+
+	// InitialBackoff is the time to wait before the first retry
+	InitialBackoff types.Duration `mapstructure:"InitialBackoff"`
+
+	// MaxBackoff is the max time to wait between two attempts, 0 doesn't limit the backoff
+	MaxBackoff types.Duration `mapstructure:"MaxBackoff"`
+
+	// Multiplier is the factor applied to the backoff after each retry, a value under 1 keeps the backoff constant
+	Multiplier float64 `mapstructure:"Multiplier"`
+
+	// Jitter is the fraction of the backoff randomly added or subtracted to each wait, so the
+	// components retrying at the same time don't hit the failing service in sync, from 0 to 1
+	Jitter float64 `mapstructure:"Jitter"`
+
+	// MaxElapsedTime is the max time since the first attempt to start a retry, 0 doesn't limit the time
+	MaxElapsedTime types.Duration `mapstructure:"MaxElapsedTime"`
+}
+
+// Constant returns a policy that runs the operation up to maxAttempts times waiting the same backoff between them
+func Constant(maxAttempts int, backoff time.Duration) Config {
+	return Config{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: types.NewDuration(backoff),
+	}
+}
+
+// permanentError wraps an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps the error returned by an operation to stop retrying it regardless of the
+// retryable classification, Do returns the wrapped error
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do runs the operation following the retry policy. isRetryable classifies the errors returned by
+// the operation, a nil classifier retries all of them except the ones wrapped with Permanent.
+// It returns nil once the operation succeeds, the last error of the operation when it can't be
+// retried anymore or the context error when the context is done while waiting for a retry.
+// The retries are counted in the metrics labeled by the operation name
+func Do(ctx context.Context, operation string, cfg Config, isRetryable func(error) bool, fn func() error) error {
+	registerMetrics.Do(metrics.Register)
+
+	start := time.Now()
+	backoff := cfg.InitialBackoff.Duration
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if !cfg.retries() || (isRetryable != nil && !isRetryable(err)) {
+			return err
+		}
+
+		wait := cfg.withJitter(backoff)
+		if (cfg.MaxAttempts > 0 && attempt >= cfg.MaxAttempts) ||
+			(cfg.MaxElapsedTime.Duration > 0 && time.Since(start)+wait > cfg.MaxElapsedTime.Duration) {
+			metrics.Exhausted(operation)
+			log.Warnf("%s failed after %d attempts in %v: %v", operation, attempt, time.Since(start), err)
+			return err
+		}
+
+		log.Debugf("%s failed on attempt %d, retrying in %v: %v", operation, attempt, wait, err)
+		metrics.Retried(operation)
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+		backoff = cfg.nextBackoff(backoff)
+	}
+}
+
+// retries returns if the policy allows to retry the operation
+func (cfg Config) retries() bool {
+	return cfg.MaxAttempts != 1 && (cfg.MaxAttempts > 0 || cfg.MaxElapsedTime.Duration > 0)
+}
+
+// nextBackoff returns the backoff of the next retry
+func (cfg Config) nextBackoff(backoff time.Duration) time.Duration {
+	if cfg.Multiplier > 1 {
+		backoff = time.Duration(float64(backoff) * cfg.Multiplier)
+	}
+	if cfg.MaxBackoff.Duration > 0 && backoff > cfg.MaxBackoff.Duration {
+		backoff = cfg.MaxBackoff.Duration
+	}
+	return backoff
+}
+
+// withJitter randomizes the backoff by up to the configured fraction in both directions
+func (cfg Config) withJitter(backoff time.Duration) time.Duration {
+	if cfg.Jitter <= 0 || backoff <= 0 {
+		return backoff
+	}
+	jitter := cfg.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+	delta := jitter * float64(backoff)
+	return time.Duration(float64(backoff) - delta + 2*delta*random()) //nolint:gomnd
+}
+
+// sleep waits for the duration or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errTransient = errors.New("transient")
+	errFatal     = errors.New("fatal")
+)
+
+func isTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestDo(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cfg           Config
+		errs          []error
+		expectedErr   error
+		expectedCalls int
+	}{
+		{
+			name:          "succeeds at first attempt",
+			cfg:           Constant(3, time.Millisecond),
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:          "succeeds after retrying",
+			cfg:           Constant(3, time.Millisecond),
+			errs:          []error{errTransient, errTransient, nil},
+			expectedCalls: 3,
+		},
+		{
+			name:          "returns the last error when the attempts are exhausted",
+			cfg:           Constant(3, time.Millisecond),
+			errs:          []error{errTransient, errTransient, errTransient, nil},
+			expectedErr:   errTransient,
+			expectedCalls: 3,
+		},
+		{
+			name:          "doesn't retry with the zero value",
+			cfg:           Config{},
+			errs:          []error{errTransient, nil},
+			expectedErr:   errTransient,
+			expectedCalls: 1,
+		},
+		{
+			name:          "doesn't retry non retryable errors",
+			cfg:           Constant(3, time.Millisecond),
+			errs:          []error{errFatal, nil},
+			expectedErr:   errFatal,
+			expectedCalls: 1,
+		},
+		{
+			name:          "doesn't retry permanent errors",
+			cfg:           Constant(3, time.Millisecond),
+			errs:          []error{Permanent(errTransient), nil},
+			expectedErr:   errTransient,
+			expectedCalls: 1,
+		},
+		{
+			name: "stops once the max elapsed time is reached",
+			cfg: Config{
+				InitialBackoff: types.NewDuration(20 * time.Millisecond),
+				MaxElapsedTime: types.NewDuration(50 * time.Millisecond),
+			},
+			errs:          []error{errTransient, errTransient, errTransient, errTransient, nil},
+			expectedErr:   errTransient,
+			expectedCalls: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), "test", tc.cfg, isTransient, func() error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.expectedErr)
+				var permanent *permanentError
+				assert.False(t, errors.As(err, &permanent))
+			}
+			assert.Equal(t, tc.expectedCalls, calls)
+		})
+	}
+}
+
+func TestDoContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	cfg := Config{
+		InitialBackoff: types.NewDuration(time.Hour),
+		MaxElapsedTime: types.NewDuration(2 * time.Hour),
+	}
+	err := Do(ctx, "test", cfg, nil, func() error {
+		calls++
+		cancel()
+		return errTransient
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
+
+func TestNextBackoff(t *testing.T) {
+	cfg := Config{
+		InitialBackoff: types.NewDuration(time.Second),
+		MaxBackoff:     types.NewDuration(5 * time.Second),
+		Multiplier:     2,
+	}
+	backoff := cfg.InitialBackoff.Duration
+	var backoffs []time.Duration
+	for i := 0; i < 4; i++ {
+		backoff = cfg.nextBackoff(backoff)
+		backoffs = append(backoffs, backoff)
+	}
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, backoffs)
+
+	constant := Constant(3, time.Second)
+	assert.Equal(t, time.Second, constant.nextBackoff(time.Second))
+}
+
+func TestWithJitter(t *testing.T) {
+	defer func(f func() float64) { random = f }(random)
+	cfg := Config{Jitter: 0.5}
+
+	random = func() float64 { return 0 }
+	assert.Equal(t, 500*time.Millisecond, cfg.withJitter(time.Second))
+	random = func() float64 { return 0.5 }
+	assert.Equal(t, time.Second, cfg.withJitter(time.Second))
+	random = func() float64 { return 0.75 }
+	assert.Equal(t, 1250*time.Millisecond, cfg.withJitter(time.Second))
+
+	assert.Equal(t, time.Second, Config{}.withJitter(time.Second))
+}
@@ -4,7 +4,9 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/clock"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/ethereum/go-ethereum/common"
@@ -20,6 +22,22 @@ type addrQueue struct {
 	notReadyTxs       map[uint64]*TxTracker
 	forcedTxs         map[common.Hash]struct{}
 	pendingTxsToStore map[common.Hash]struct{}
+	// priceBump is the min gasPrice increase percentage for a new tx to replace an existing tx with the same nonce
+	priceBump uint64
+	// reorged is set while the nonce and balance are refreshed after an L2 reorg, the readyTx is
+	// kept out of the txSortedList meanwhile as it could have been selected with a stale nonce
+	reorged bool
+	// workerTxs is updated when a tx is added or deleted, nil when the addrQueue is not held by a worker
+	workerTxs *workerTxs
+	// clock provides the time the txs are set as not ready and expired at
+	clock clock.Clock
+}
+
+// workerTxs keeps the running count of the txs held by the addrQueues of a worker and their notReady
+// txs sorted by the TxSorter, so the worker limits are checked without walking all the addrQueues
+type workerTxs struct {
+	count       int
+	notReadyTxs *txSortedList
 }
 
 // newAddrQueue creates and init a addrQueue
@@ -33,53 +51,124 @@ func newAddrQueue(addr common.Address, nonce uint64, balance *big.Int) *addrQueu
 		notReadyTxs:       make(map[uint64]*TxTracker),
 		forcedTxs:         make(map[common.Hash]struct{}),
 		pendingTxsToStore: make(map[common.Hash]struct{}),
+		clock:             clock.System,
 	}
 }
 
 // addTx adds a tx to the addrQueue and updates the ready a notReady Txs. Also if the new tx matches
-// an existing tx with the same nonce but the new tx has better or equal gasPrice, we will return in the replacedTx
-// the existing tx with lower gasPrice (the replacedTx will be later set as failed in the pool).
-// If the existing tx has better gasPrice then we will drop the new tx (dropReason = ErrDuplicatedNonce)
+// an existing tx with the same nonce but the new tx has a gasPrice higher by at least priceBump percent,
+// we will return in the replacedTx the existing tx (the replacedTx will be later set as failed in the pool).
+// If the existing tx has better gasPrice then we will drop the new tx (dropReason = ErrDuplicatedNonce),
+// and if the new tx gasPrice is not high enough we will drop it too (dropReason = ErrReplacementUnderpriced)
 func (a *addrQueue) addTx(tx *TxTracker) (newReadyTx, prevReadyTx, replacedTx *TxTracker, dropReason error) {
 	var repTx *TxTracker
 
 	if a.currentNonce == tx.Nonce { // Is a possible readyTx
-		// We set the tx as readyTx if we do not have one assigned or if the gasPrice is better or equal than the current readyTx
-		if a.readyTx == nil || ((a.readyTx != nil) && (tx.GasPrice.Cmp(a.readyTx.GasPrice) >= 0)) {
-			oldReadyTx := a.readyTx
-			if (oldReadyTx != nil) && (oldReadyTx.HashStr != tx.HashStr) {
-				// if it is a different tx then we need to return the replaced tx to set as failed in the pool
-				repTx = oldReadyTx
+		// We set the tx as readyTx if we do not have one assigned or if the new tx can replace the current one,
+		// that can be the readyTx or a notReadyTx with the current nonce if there was not enough balance
+		oldReadyTx := a.readyTx
+		existingTx := oldReadyTx
+		if existingTx == nil {
+			existingTx = a.notReadyTxs[tx.Nonce]
+		}
+		if existingTx != nil {
+			if err := a.checkReplacement(existingTx, tx); err != nil {
+				return nil, nil, nil, err
 			}
-			if a.currentBalance.Cmp(tx.Cost) >= 0 {
-				a.readyTx = tx
-				return tx, oldReadyTx, repTx, nil
-			} else { // If there is not enough balance we set the new tx as notReadyTxs
-				a.readyTx = nil
-				a.notReadyTxs[tx.Nonce] = tx
-				return nil, oldReadyTx, repTx, nil
+			if existingTx.HashStr != tx.HashStr {
+				// if it is a different tx then we need to return the replaced tx to set as failed in the pool
+				repTx = existingTx
 			}
-		} else { // We have an already readytx with the same nonce and better gas price, we discard the new tx
-			return nil, nil, nil, ErrDuplicatedNonce
+		}
+		if a.currentBalance.Cmp(tx.Cost) >= 0 {
+			a.setReadyTx(tx)
+			a.deleteNotReadyTx(tx.Nonce)
+			return tx, oldReadyTx, repTx, nil
+		} else { // If there is not enough balance we set the new tx as notReadyTxs
+			a.clearReadyTx()
+			a.setNotReadyTx(tx)
+			return nil, oldReadyTx, repTx, nil
 		}
 	} else if a.currentNonce > tx.Nonce {
 		return nil, nil, nil, runtime.ErrIntrinsicInvalidNonce
 	}
 
 	nrTx, found := a.notReadyTxs[tx.Nonce]
-	if !found || ((found) && (tx.GasPrice.Cmp(nrTx.GasPrice) >= 0)) {
-		a.notReadyTxs[tx.Nonce] = tx
-		if (found) && (nrTx.HashStr != tx.HashStr) {
+	if found {
+		if err := a.checkReplacement(nrTx, tx); err != nil {
+			return nil, nil, nil, err
+		}
+		if nrTx.HashStr != tx.HashStr {
 			// if it is a different tx then we need to return the replaced tx to set as failed in the pool
 			repTx = nrTx
 		}
-		return nil, nil, repTx, nil
-	} else {
-		// We have an already notReadytx with the same nonce and better gas price, we discard the new tx
-		return nil, nil, nil, ErrDuplicatedNonce
+	}
+	a.setNotReadyTx(tx)
+	return nil, nil, repTx, nil
+}
+
+// setReadyTx sets the tx as the readyTx of the addrQueue
+func (a *addrQueue) setReadyTx(tx *TxTracker) {
+	tx.NotReadySince = time.Time{}
+	if a.readyTx == nil && a.workerTxs != nil {
+		a.workerTxs.count++
+	}
+	a.readyTx = tx
+}
+
+// clearReadyTx removes the readyTx of the addrQueue
+func (a *addrQueue) clearReadyTx() {
+	if a.readyTx != nil && a.workerTxs != nil {
+		a.workerTxs.count--
+	}
+	a.readyTx = nil
+}
+
+// setNotReadyTx adds the tx to the notReadyTxs replacing the tx with the same nonce, keeping the
+// time it was first set as not ready
+func (a *addrQueue) setNotReadyTx(tx *TxTracker) {
+	if tx.NotReadySince.IsZero() {
+		tx.NotReadySince = a.clock.Now()
+	}
+	a.deleteNotReadyTx(tx.Nonce)
+	a.notReadyTxs[tx.Nonce] = tx
+	if a.workerTxs != nil {
+		a.workerTxs.count++
+		a.workerTxs.notReadyTxs.add(tx)
+	}
+}
+
+// deleteNotReadyTx deletes the notReadyTx with the nonce, if any
+func (a *addrQueue) deleteNotReadyTx(nonce uint64) {
+	tx, found := a.notReadyTxs[nonce]
+	if !found {
+		return
+	}
+	delete(a.notReadyTxs, nonce)
+	if a.workerTxs != nil {
+		a.workerTxs.count--
+		a.workerTxs.notReadyTxs.delete(tx)
 	}
 }
 
+// checkReplacement checks if the new tx can replace the existing tx with the same nonce. The same tx can
+// always be added again with a better or equal gasPrice, a different tx needs a gasPrice higher than
+// the existing one by at least priceBump percent
+func (a *addrQueue) checkReplacement(existingTx, newTx *TxTracker) error {
+	if newTx.GasPrice.Cmp(existingTx.GasPrice) < 0 {
+		// We have an already tx with the same nonce and better gas price, we discard the new tx
+		return ErrDuplicatedNonce
+	}
+	if existingTx.HashStr == newTx.HashStr || a.priceBump == 0 {
+		return nil
+	}
+
+	if pool.IsReplacementUnderpriced(existingTx.GasPrice, newTx.GasPrice, a.priceBump) {
+		return ErrReplacementUnderpriced
+	}
+	return nil
+}
+
 // addForcedTx adds a forced tx to the list of forced txs
 func (a *addrQueue) addForcedTx(txHash common.Hash) {
 	a.forcedTxs[txHash] = struct{}{}
@@ -90,31 +179,104 @@ func (a *addrQueue) addPendingTxToStore(txHash common.Hash) {
 	a.pendingTxsToStore[txHash] = struct{}{}
 }
 
-// ExpireTransactions removes the txs that have been in the queue for more than maxTime
-func (a *addrQueue) ExpireTransactions(maxTime time.Duration) ([]*TxTracker, *TxTracker) {
+// ExpireTransactions removes the txs that have been in the queue for more than maxTime and the notReadyTxs
+// that have not been ready for more than maxNotReadyTime (0 disables it). The FailedReason of the expired txs is set
+func (a *addrQueue) ExpireTransactions(maxTime time.Duration, maxNotReadyTime time.Duration) ([]*TxTracker, *TxTracker) {
 	var (
 		txs         []*TxTracker
 		prevReadyTx *TxTracker
 	)
 
+	now := a.clock.Now()
 	for _, txTracker := range a.notReadyTxs {
-		if txTracker.ReceivedAt.Add(maxTime).Before(time.Now()) {
+		var reason error
+		if txTracker.ReceivedAt.Add(maxTime).Before(now) {
+			reason = ErrExpiredTransaction
+		} else if maxNotReadyTime > 0 && !txTracker.NotReadySince.IsZero() && txTracker.NotReadySince.Add(maxNotReadyTime).Before(now) {
+			reason = ErrNotReadyTransactionExpired
+		}
+		if reason != nil {
+			failedReason := reason.Error()
+			txTracker.FailedReason = &failedReason
 			txs = append(txs, txTracker)
-			delete(a.notReadyTxs, txTracker.Nonce)
-			log.Debugf("Deleting notReadyTx %s from addrQueue %s", txTracker.HashStr, a.fromStr)
+			a.deleteNotReadyTx(txTracker.Nonce)
+			log.Debugf("Deleting notReadyTx %s from addrQueue %s: %v", txTracker.HashStr, a.fromStr, reason)
 		}
 	}
 
-	if a.readyTx != nil && a.readyTx.ReceivedAt.Add(maxTime).Before(time.Now()) {
+	if a.readyTx != nil && a.readyTx.ReceivedAt.Add(maxTime).Before(now) {
 		prevReadyTx = a.readyTx
+		failedReason := ErrExpiredTransaction.Error()
+		prevReadyTx.FailedReason = &failedReason
 		txs = append(txs, a.readyTx)
-		a.readyTx = nil
+		a.clearReadyTx()
 		log.Debugf("Deleting readyTx %s from addrQueue %s", prevReadyTx.HashStr, a.fromStr)
 	}
 
 	return txs, prevReadyTx
 }
 
+// deleteDeniedTxs deletes the txs denied by the policy, setting the policy error as their FailedReason.
+// It returns the deleted txs and the readyTx if it has been deleted
+func (a *addrQueue) deleteDeniedTxs(policy *txPolicy) ([]*TxTracker, *TxTracker) {
+	var (
+		txs         []*TxTracker
+		prevReadyTx *TxTracker
+	)
+
+	for _, txTracker := range a.notReadyTxs {
+		if reason := policy.check(txTracker); reason != nil {
+			failedReason := reason.Error()
+			txTracker.FailedReason = &failedReason
+			txs = append(txs, txTracker)
+			a.deleteNotReadyTx(txTracker.Nonce)
+			log.Debugf("Deleting notReadyTx %s from addrQueue %s: %v", txTracker.HashStr, a.fromStr, reason)
+		}
+	}
+
+	if a.readyTx != nil {
+		if reason := policy.check(a.readyTx); reason != nil {
+			prevReadyTx = a.readyTx
+			failedReason := reason.Error()
+			prevReadyTx.FailedReason = &failedReason
+			txs = append(txs, a.readyTx)
+			a.clearReadyTx()
+			log.Debugf("Deleting readyTx %s from addrQueue %s: %v", prevReadyTx.HashStr, a.fromStr, reason)
+		}
+	}
+
+	return txs, prevReadyTx
+}
+
+// hasNonce returns true if the addrQueue has a ready or notReady tx with the nonce
+func (a *addrQueue) hasNonce(nonce uint64) bool {
+	if a.readyTx != nil && a.readyTx.Nonce == nonce {
+		return true
+	}
+	_, found := a.notReadyTxs[nonce]
+	return found
+}
+
+// txsCount returns the number of ready and notReady txs of the addrQueue
+func (a *addrQueue) txsCount() int {
+	count := len(a.notReadyTxs)
+	if a.readyTx != nil {
+		count++
+	}
+	return count
+}
+
+// lowestNotReadyTx returns the notReadyTx sorted last by the sorter, or nil if there are no notReadyTxs
+func (a *addrQueue) lowestNotReadyTx(sorter TxSorter) *TxTracker {
+	var lowestTx *TxTracker
+	for _, nrTx := range a.notReadyTxs {
+		if lowestTx == nil || sorter.IsGreaterThan(lowestTx, nrTx) {
+			lowestTx = nrTx
+		}
+	}
+	return lowestTx
+}
+
 // IsEmpty returns true if the addrQueue is empty
 func (a *addrQueue) IsEmpty() bool {
 	return a.readyTx == nil && len(a.notReadyTxs) == 0 && len(a.forcedTxs) == 0 && len(a.pendingTxsToStore) == 0
@@ -127,13 +289,13 @@ func (a *addrQueue) deleteTx(txHash common.Hash) (deletedReadyTx *TxTracker) {
 	if (a.readyTx != nil) && (a.readyTx.HashStr == txHashStr) {
 		log.Infof("Deleting readyTx %s from addrQueue %s", txHashStr, a.fromStr)
 		prevReadyTx := a.readyTx
-		a.readyTx = nil
+		a.clearReadyTx()
 		return prevReadyTx
 	} else {
 		for _, txTracker := range a.notReadyTxs {
 			if txTracker.HashStr == txHashStr {
 				log.Infof("Deleting notReadyTx %s from addrQueue %s", txHashStr, a.fromStr)
-				delete(a.notReadyTxs, txTracker.Nonce)
+				a.deleteNotReadyTx(txTracker.Nonce)
 			}
 		}
 		return nil
@@ -180,7 +342,7 @@ func (a *addrQueue) updateCurrentNonceBalance(nonce *uint64, balance *big.Int) (
 			}
 			for _, txTracker := range txsToDelete {
 				log.Infof("Deleting notReadyTx with nonce %d from addrQueue %s", txTracker.Nonce, a.fromStr)
-				delete(a.notReadyTxs, txTracker.Nonce)
+				a.deleteNotReadyTx(txTracker.Nonce)
 			}
 		}
 	}
@@ -190,7 +352,7 @@ func (a *addrQueue) updateCurrentNonceBalance(nonce *uint64, balance *big.Int) (
 		// set readyTx=nil. Later we will move the tx to notReadyTxs
 		if (a.readyTx.Nonce != a.currentNonce) || (a.currentBalance.Cmp(a.readyTx.Cost) < 0) {
 			oldReadyTx = a.readyTx
-			a.readyTx = nil
+			a.clearReadyTx()
 		}
 	}
 
@@ -200,9 +362,9 @@ func (a *addrQueue) updateCurrentNonceBalance(nonce *uint64, balance *big.Int) (
 		nrTx, found := a.notReadyTxs[a.currentNonce]
 		if found {
 			if a.currentBalance.Cmp(nrTx.Cost) >= 0 {
-				a.readyTx = nrTx
+				a.setReadyTx(nrTx)
 				log.Infof("Moving notReadyTx %s to readyTx for addrQueue %s", nrTx.HashStr, a.fromStr)
-				delete(a.notReadyTxs, a.currentNonce)
+				a.deleteNotReadyTx(a.currentNonce)
 			}
 		}
 	}
@@ -210,7 +372,7 @@ func (a *addrQueue) updateCurrentNonceBalance(nonce *uint64, balance *big.Int) (
 	// We add the oldReadyTx to notReadyTxs (if it has a valid nonce) at this point to avoid check it again in the previous if statement
 	if oldReadyTx != nil && oldReadyTx.Nonce > a.currentNonce {
 		log.Infof("Marking readyTx %s as notReadyTx from addrQueue %s", oldReadyTx.HashStr, a.fromStr)
-		a.notReadyTxs[oldReadyTx.Nonce] = oldReadyTx
+		a.setNotReadyTx(oldReadyTx)
 	}
 
 	return a.readyTx, oldReadyTx, txsToDelete
@@ -227,7 +389,12 @@ func (a *addrQueue) UpdateTxZKCounters(txHash common.Hash, counters state.ZKCoun
 		for _, txTracker := range a.notReadyTxs {
 			if txTracker.HashStr == txHashStr {
 				log.Debugf("Updating notReadyTx %s with new ZKCounters from addrQueue %s", txHashStr, a.fromStr)
+				// the notReadyTxs of the worker are sorted by the TxSorter, so the tx is added again with the new counters
+				resort := a.workerTxs != nil && a.workerTxs.notReadyTxs.delete(txTracker)
 				txTracker.updateZKCounters(counters)
+				if resort {
+					a.workerTxs.notReadyTxs.add(txTracker)
+				}
 				break
 			}
 		}
@@ -3,8 +3,12 @@ package sequencer
 import (
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/clock"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type notReadyTx struct {
@@ -89,7 +93,7 @@ func processAddTxTestCases(t *testing.T, testCases []addrQueueAddTxTestCase) {
 }
 
 func TestAddrQueue(t *testing.T) {
-	addr = addrQueue{fromStr: "0x99999", currentNonce: 1, currentBalance: new(big.Int).SetInt64(10), notReadyTxs: make(map[uint64]*TxTracker)}
+	addr = addrQueue{fromStr: "0x99999", currentNonce: 1, currentBalance: new(big.Int).SetInt64(10), notReadyTxs: make(map[uint64]*TxTracker), clock: clock.System}
 
 	addTxTestCases := []addrQueueAddTxTestCase{
 		{
@@ -212,3 +216,95 @@ func TestAddrQueue(t *testing.T) {
 		}
 	})
 }
+
+func TestAddrQueuePriceBump(t *testing.T) {
+	addr = addrQueue{fromStr: "0x99999", currentNonce: 1, currentBalance: new(big.Int).SetInt64(10), notReadyTxs: make(map[uint64]*TxTracker), priceBump: 10, clock: clock.System}
+
+	processAddTxTestCases(t, []addrQueueAddTxTestCase{
+		{
+			name: "Add ready tx 0x1 nonce 1", hash: common.Hash{0x1}, nonce: 1, gasPrice: new(big.Int).SetInt64(100), cost: new(big.Int).SetInt64(5),
+			expectedReadyTx: common.Hash{0x1},
+		},
+		{
+			name: "Add tx 0x11 nonce 1 with gasPrice not bumped enough", hash: common.Hash{0x11}, nonce: 1, gasPrice: new(big.Int).SetInt64(109), cost: new(big.Int).SetInt64(5),
+			expectedReadyTx: common.Hash{0x1},
+			err:             ErrReplacementUnderpriced,
+		},
+		{
+			name: "Replace readyTx 0x1 by tx 0x11 with gasPrice bumped", hash: common.Hash{0x11}, nonce: 1, gasPrice: new(big.Int).SetInt64(110), cost: new(big.Int).SetInt64(5),
+			expectedReadyTx:    common.Hash{0x11},
+			expectedReplacedTx: common.Hash{0x1},
+		},
+		{
+			name: "Add not ready tx 0x2 nonce 2", hash: common.Hash{0x2}, nonce: 2, gasPrice: new(big.Int).SetInt64(100), cost: new(big.Int).SetInt64(5),
+			expectedReadyTx: common.Hash{0x11},
+			expectedNotReadyTx: []notReadyTx{
+				{nonce: 2, hash: common.Hash{0x2}},
+			},
+		},
+		{
+			name: "Add tx 0x22 nonce 2 with the same gasPrice", hash: common.Hash{0x22}, nonce: 2, gasPrice: new(big.Int).SetInt64(100), cost: new(big.Int).SetInt64(5),
+			expectedReadyTx: common.Hash{0x11},
+			expectedNotReadyTx: []notReadyTx{
+				{nonce: 2, hash: common.Hash{0x2}},
+			},
+			err: ErrReplacementUnderpriced,
+		},
+		{
+			name: "Replace notReadyTx 0x2 by tx 0x22 with gasPrice bumped", hash: common.Hash{0x22}, nonce: 2, gasPrice: new(big.Int).SetInt64(120), cost: new(big.Int).SetInt64(5),
+			expectedReadyTx: common.Hash{0x11},
+			expectedNotReadyTx: []notReadyTx{
+				{nonce: 2, hash: common.Hash{0x22}},
+			},
+			expectedReplacedTx: common.Hash{0x2},
+		},
+	})
+}
+
+func TestAddrQueueExpireTransactions(t *testing.T) {
+	now := time.Now()
+	fakeClock := clock.NewFake(now)
+	a := newAddrQueue(common.Address{0x99}, 1, new(big.Int).SetInt64(10))
+	a.clock = fakeClock
+
+	// ready tx received long ago
+	oldReadyTx := newTestTxTracker(common.Hash{0x1}, 1, new(big.Int).SetInt64(100), new(big.Int).SetInt64(5))
+	oldReadyTx.ReceivedAt = now.Add(-2 * time.Hour)
+	// not ready tx (nonce gap) waiting for too long
+	gappedTx := newTestTxTracker(common.Hash{0x3}, 3, new(big.Int).SetInt64(100), new(big.Int).SetInt64(5))
+	gappedTx.ReceivedAt = now
+	for _, tx := range []*TxTracker{oldReadyTx, gappedTx} {
+		_, _, _, err := a.addTx(tx)
+		require.NoError(t, err)
+	}
+	assert.True(t, oldReadyTx.NotReadySince.IsZero())
+	assert.Equal(t, now, gappedTx.NotReadySince)
+
+	// recent not ready tx
+	fakeClock.Advance(20 * time.Second)
+	recentTx := newTestTxTracker(common.Hash{0x4}, 4, new(big.Int).SetInt64(100), new(big.Int).SetInt64(5))
+	recentTx.ReceivedAt = fakeClock.Now()
+	_, _, _, err := a.addTx(recentTx)
+	require.NoError(t, err)
+	assert.Equal(t, fakeClock.Now(), recentTx.NotReadySince)
+
+	// the gapped tx has not been ready for 40 seconds and the recent tx for 20 seconds
+	fakeClock.Advance(20 * time.Second)
+	expiredTxs, prevReadyTx := a.ExpireTransactions(time.Hour, 30*time.Second)
+	assert.Equal(t, oldReadyTx, prevReadyTx)
+	require.Len(t, expiredTxs, 2)
+	for _, tx := range expiredTxs {
+		require.NotNil(t, tx.FailedReason)
+		switch tx.Hash {
+		case oldReadyTx.Hash:
+			assert.Equal(t, ErrExpiredTransaction.Error(), *tx.FailedReason)
+		case gappedTx.Hash:
+			assert.Equal(t, ErrNotReadyTransactionExpired.Error(), *tx.FailedReason)
+		default:
+			t.Fatalf("unexpected expired tx %s", tx.HashStr)
+		}
+	}
+	assert.Nil(t, a.readyTx)
+	assert.Len(t, a.notReadyTxs, 1)
+	assert.Equal(t, recentTx, a.notReadyTxs[4])
+}
@@ -47,7 +47,8 @@ func (c *closingSignalsManager) checkGERUpdate() {
 			maxBlockNumber = lastL1BlockNumber - c.cfg.GERFinalityNumberOfBlocks
 		}
 
-		ger, _, err := c.dbManager.GetLatestGer(c.ctx, maxBlockNumber)
+		// the GER is read from the state, the cached one is invalidated when it's updated
+		ger, _, err := c.dbManager.GetLatestGerUpdate(c.ctx, maxBlockNumber)
 		if err != nil {
 			log.Errorf("error checking GER update: %v", err)
 			continue
@@ -3,6 +3,8 @@ package sequencer
 import (
 	"github.com/0xPolygonHermez/zkevm-data-streamer/log"
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // Config represents the configuration of a sequencer
@@ -23,6 +25,26 @@ type Config struct {
 	// MaxTxLifetime is the time a tx can be in the sequencer/worker memory
 	MaxTxLifetime types.Duration `mapstructure:"MaxTxLifetime"`
 
+	// MaxTxNotReadyTime is the time a tx can wait in the worker memory without being ready to be
+	// selected, because of a nonce gap or not enough balance, before it's expired. 0 disables it
+	MaxTxNotReadyTime types.Duration `mapstructure:"MaxTxNotReadyTime"`
+
+	// L2Coinbase defines which address is going to receive the fees of the L2 txs. It's decoupled
+	// from the L1 account used to sequence the batches, so the L1 wallet can be rotated without
+	// changing the fee recipient. If it's not set, the trusted sequencer address is used.
+	// The deprecated SequenceSender.L2Coinbase is still read when this value is not set
+	L2Coinbase common.Address `mapstructure:"L2Coinbase"`
+
+	// MaxWorkerTxs is the max number of txs held in the worker memory. Once reached, a new tx evicts the
+	// not ready tx with the lowest efficiency, if the new tx has higher efficiency, or it's rejected
+	// otherwise. 0 disables the limit
+	MaxWorkerTxs uint64 `mapstructure:"MaxWorkerTxs"`
+
+	// TxSorter is the policy used to sort the txs selected for the batches, the possible values are
+	// "gasprice" (higher gasPrice first), "efficiency" (higher fee per unit of the batch resources used
+	// first) and "fifo" (older txs first)
+	TxSorter string `mapstructure:"TxSorter"`
+
 	// Finalizer's specific config properties
 	Finalizer FinalizerCfg `mapstructure:"Finalizer"`
 
@@ -31,6 +53,76 @@ type Config struct {
 
 	// StreamServerCfg is the config for the stream server
 	StreamServer StreamServerCfg `mapstructure:"StreamServer"`
+
+	// SelectionAudit is the config for the tx selection audit log
+	SelectionAudit SelectionAuditCfg `mapstructure:"SelectionAudit"`
+
+	// TxRetry is the config for the retries of the txs that fail because of a transient executor error
+	TxRetry TxRetryCfg `mapstructure:"TxRetry"`
+
+	// Policy is the config for the deny lists of the txs accepted by the worker
+	Policy PolicyCfg `mapstructure:"Policy"`
+
+	// Repricing is the config for the demotion of the ready txs priced below the suggested gas price
+	Repricing RepricingCfg `mapstructure:"Repricing"`
+}
+
+// RepricingCfg contains the configuration of the repricing of the txs held by the worker. The price floor
+// follows the L2 suggested gas price, the ready txs priced below it are demoted so they aren't selected
+// and fail again and again, and they are promoted back when the floor drops below their gas price
+type RepricingCfg struct {
+	// Enabled is a flag to enable/disable the repricing
+	Enabled bool `mapstructure:"Enabled"`
+	// CheckInterval is the interval to check the L2 suggested gas price
+	CheckInterval types.Duration `mapstructure:"CheckInterval"`
+	// Hysteresis is the min change, in percentage of the current price floor, of the suggested gas price to
+	// update the floor. It avoids demoting and promoting the txs again and again on small price fluctuations
+	Hysteresis uint64 `mapstructure:"Hysteresis"`
+}
+
+// PolicyCfg contains the configuration of the sequencer policy, that rejects the txs of denied senders,
+// to denied recipients or calling denied 4-byte function selectors. The deny lists of the config are
+// merged with the ones stored in the pool DB, that can be reloaded at runtime with the admin RPC
+type PolicyCfg struct {
+	// Enabled is a flag to enable/disable the sequencer policy
+	Enabled bool `mapstructure:"Enabled"`
+	// DeniedSenders are the addresses whose txs are rejected
+	DeniedSenders []common.Address `mapstructure:"DeniedSenders"`
+	// DeniedRecipients are the addresses the txs sent to are rejected
+	DeniedRecipients []common.Address `mapstructure:"DeniedRecipients"`
+	// DeniedSelectors are the 4-byte function selectors, in hex format like "0xa9059cbb", whose calls are rejected
+	DeniedSelectors []string `mapstructure:"DeniedSelectors"`
+}
+
+// TxRetryCfg contains the configuration of the retries of the txs that fail to be processed because
+// of a transient executor error, like a connection error. Instead of being discarded these txs are
+// skipped by the worker and selected again once their retry delay expires
+type TxRetryCfg struct {
+	// MaxAttempts is the max number of times a tx is skipped before discarding it as failed. 0 disables the retries
+	MaxAttempts uint64 `mapstructure:"MaxAttempts"`
+	// InitialDelay is the time a tx is skipped after its first failure, it's doubled on each new attempt
+	InitialDelay types.Duration `mapstructure:"InitialDelay"`
+	// MaxDelay is the max time a tx is skipped between attempts. 0 means no limit
+	MaxDelay types.Duration `mapstructure:"MaxDelay"`
+}
+
+// SelectionAuditCfg contains the configuration of the tx selection audit log, that
+// records for each tx candidate of a selection round the decision taken by the worker
+type SelectionAuditCfg struct {
+	// Enabled is a flag to enable/disable the selection audit log
+	Enabled bool `mapstructure:"Enabled"`
+	// SamplingRate is the fraction of selection rounds recorded, from 0 to 1
+	SamplingRate float64 `mapstructure:"SamplingRate"`
+	// MaxCandidatesPerRound is the max number of candidates recorded per selection round,
+	// the selected tx is always recorded. 0 means no limit
+	MaxCandidatesPerRound uint64 `mapstructure:"MaxCandidatesPerRound"`
+	// FlushInterval is the interval to persist the recorded entries in the pool DB
+	FlushInterval types.Duration `mapstructure:"FlushInterval"`
+	// BufferSize is the max number of entries waiting to be persisted, the rounds
+	// recorded when the buffer is full are discarded
+	BufferSize uint64 `mapstructure:"BufferSize"`
+	// RetentionPeriod is the time the entries are kept in the pool DB. 0 means forever
+	RetentionPeriod types.Duration `mapstructure:"RetentionPeriod"`
 }
 
 // StreamServerCfg contains the data streamer's configuration properties
@@ -83,10 +175,203 @@ type FinalizerCfg struct {
 	// SequentialReprocessFullBatch indicates if the reprocess of a closed batch (sanity check) must be done in a
 	// sequential way (instead than in parallel)
 	SequentialReprocessFullBatch bool `mapstructure:"SequentialReprocessFullBatch"`
+
+	// MaxTimeWithoutBatches is the max time an empty batch is kept open when there is no activity, once
+	// reached the empty batch is closed so it can be sequenced keeping the L1 verification cadence and
+	// the GER updates flowing on low traffic networks. 0 disables it
+	MaxTimeWithoutBatches types.Duration `mapstructure:"MaxTimeWithoutBatches"`
+
+	// BatchClosing contains the time and txs conditions to close the batches
+	BatchClosing BatchClosingCfg `mapstructure:"BatchClosing"`
+
+	// HaltPolicy is the policy applied by the finalizer when a critical error happens
+	HaltPolicy HaltPolicyCfg `mapstructure:"HaltPolicy"`
+
+	// ProvingBudget limits the proving complexity of the batches to keep their proving time under a target
+	ProvingBudget ProvingBudgetCfg `mapstructure:"ProvingBudget"`
+
+	// UtilizationTargets closes the batches early when a resource reaches its target utilization
+	UtilizationTargets UtilizationTargetsCfg `mapstructure:"UtilizationTargets"`
+
+	// SequencingWindows are the periods of each batch reserved to the txs of a tag, during a window only
+	// the txs with its tag are selected. Outside the windows all the txs are selected
+	SequencingWindows []SequencingWindowCfg `mapstructure:"SequencingWindows"`
+
+	// Flush is the strategy used to flush the state writes of the processed txs to the merkle tree backend
+	Flush FlushCfg `mapstructure:"Flush"`
+
+	// PreWarm precomputes the checks to open the next batch while the current one is filling
+	PreWarm BatchPreWarmCfg `mapstructure:"PreWarm"`
+
+	// CandidateSimulation simulates each candidate tx against the WIP state root before including it in the batch
+	CandidateSimulation CandidateSimulationCfg `mapstructure:"CandidateSimulation"`
+}
+
+// BatchPreWarmCfg contains the configuration of the next batch template, prepared while the finalizer is waiting
+// for new txs. The GER and the timestamp of the next batch are already known in memory, the template saves the
+// check of the synchronizer status when the batch is opened, so the gap between batches is shorter
+type BatchPreWarmCfg struct {
+	// Enabled is a flag to enable/disable the next batch template
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxAge is the max age of the template to be used when the next batch is opened, the older templates are
+	// refreshed while waiting for new txs and ignored when opening the batch
+	MaxAge types.Duration `mapstructure:"MaxAge"`
+}
+
+// CandidateSimulationCfg contains the configuration of the two-phase inclusion of the txs in the batch. The candidate
+// tx is first executed without updating the merkle tree, the txs failing without changing the state root or not
+// fitting the remaining resources of the batch are handled with the simulation, so they don't take an execution
+// updating the merkle tree. The included txs are executed twice, so it's only worth when the failures are frequent
+type CandidateSimulationCfg struct {
+	// Enabled is a flag to enable/disable the simulation of the candidate txs
+	Enabled bool `mapstructure:"Enabled"`
+}
+
+// BatchClosingCfg contains the configuration of the time and txs conditions to close the batches, on top
+// of the TimestampResolution and the resource conditions (see ResourcePercentageToCloseBatch and UtilizationTargets)
+type BatchClosingCfg struct {
+	// MaxIdleTime is the max time a batch with txs is kept open without adding new txs. 0 disables it
+	MaxIdleTime types.Duration `mapstructure:"MaxIdleTime"`
+
+	// MaxOpenTime is the max time a batch with txs is kept open, it's applied regardless of MinTxs. 0 disables it
+	MaxOpenTime types.Duration `mapstructure:"MaxOpenTime"`
+
+	// MinTxs is the min number of txs of a batch to be closed because of the TimestampResolution or the MaxIdleTime,
+	// the batches with less txs are kept open until they are closed by any other condition
+	MinTxs uint64 `mapstructure:"MinTxs"`
+}
+
+// SequencingWindowCfg contains the configuration of a sequencing window, the period of each
+// batch, relative to the time the batch is opened, when only the txs with the tag are selected
+type SequencingWindowCfg struct {
+	// Tag is the tag of the pool txs selected during the window, as defined in Pool.TxTags
+	Tag string `mapstructure:"Tag"`
+
+	// Start is the time since the batch is opened when the window starts
+	Start types.Duration `mapstructure:"Start"`
+
+	// End is the time since the batch is opened when the window ends
+	End types.Duration `mapstructure:"End"`
+}
+
+// UtilizationTargetsCfg contains the configuration of the early close of the batches. When a resource
+// of the batch reaches its target utilization, the first ready txs of the worker are checked and the
+// batch is closed if none of them fits in the remaining resources, instead of waiting for the deadlines
+type UtilizationTargetsCfg struct {
+	// Enabled is a flag to enable/disable the early close of the batches
+	Enabled bool `mapstructure:"Enabled"`
+
+	// LookAheadTxs is the number of ready txs, in the order they are selected, checked to fit in the
+	// remaining resources of the batch. 0 means all the ready txs
+	LookAheadTxs uint64 `mapstructure:"LookAheadTxs"`
+
+	// Targets are the target utilization of each resource, as a percentage of its batch constraint
+	Targets ResourceUtilizationTargets `mapstructure:"Targets"`
+}
+
+// ResourceUtilizationTargets contains the target utilization percentage of each batch resource, 0 disables the target of the resource
+type ResourceUtilizationTargets struct {
+	CumulativeGasUsed uint32 `mapstructure:"CumulativeGasUsed"`
+	KeccakHashes      uint32 `mapstructure:"KeccakHashes"`
+	PoseidonHashes    uint32 `mapstructure:"PoseidonHashes"`
+	PoseidonPaddings  uint32 `mapstructure:"PoseidonPaddings"`
+	MemAligns         uint32 `mapstructure:"MemAligns"`
+	Arithmetics       uint32 `mapstructure:"Arithmetics"`
+	Binaries          uint32 `mapstructure:"Binaries"`
+	Steps             uint32 `mapstructure:"Steps"`
+	BatchBytesSize    uint32 `mapstructure:"BatchBytesSize"`
+}
+
+// ProvingBudgetCfg contains the configuration of the limit of the batches proving complexity. The
+// complexity of a batch is the weighted sum of its counters, each one relative to its batch constraint,
+// and the proving time per complexity unit is learned from the proving times stored by the aggregator
+type ProvingBudgetCfg struct {
+	// Enabled is a flag to enable/disable the limit of the batches proving complexity
+	Enabled bool `mapstructure:"Enabled"`
+
+	// MaxProvingTime is the max time a batch should take to be proven, it should not be higher
+	// than the verification cadence of the aggregator (VerifyProofInterval)
+	MaxProvingTime types.Duration `mapstructure:"MaxProvingTime"`
+
+	// HistoryBatches is the number of the last proven batches used to learn the proving time per complexity unit
+	HistoryBatches uint64 `mapstructure:"HistoryBatches"`
+
+	// RefreshInterval is the time between the updates of the learned proving time per complexity unit
+	RefreshInterval types.Duration `mapstructure:"RefreshInterval"`
+
+	// Weights are the weights of each counter in the batch complexity
+	Weights ProvingComplexityWeights `mapstructure:"Weights"`
+}
+
+// ProvingComplexityWeights contains the weight of each counter in the batch proving complexity
+type ProvingComplexityWeights struct {
+	KeccakHashes     float64 `mapstructure:"KeccakHashes"`
+	PoseidonHashes   float64 `mapstructure:"PoseidonHashes"`
+	PoseidonPaddings float64 `mapstructure:"PoseidonPaddings"`
+	MemAligns        float64 `mapstructure:"MemAligns"`
+	Arithmetics      float64 `mapstructure:"Arithmetics"`
+	Binaries         float64 `mapstructure:"Binaries"`
+	Steps            float64 `mapstructure:"Steps"`
+}
+
+const (
+	// HaltPolicyModeHalt halts the finalizer until it's resumed by an operator
+	HaltPolicyModeHalt = "halt"
+	// HaltPolicyModeHaltAndAlert halts the finalizer and logs a critical event to alert the operator
+	HaltPolicyModeHaltAndAlert = "halt-and-alert"
+	// HaltPolicyModeRetry retries the failed operation with backoff before halting and alerting
+	HaltPolicyModeRetry = "retry"
+)
+
+// HaltPolicyCfg contains the configuration of the finalizer halt policy, it's applied when storing a tx,
+// executing a batch or processing a tx with an executor DB error fails. A failed sanity check always halts the finalizer until the sequencer is restarted
+type HaltPolicyCfg struct {
+	// Mode is the action taken on a critical error, the possible values are
+	// "halt", "halt-and-alert" and "retry"
+	Mode string `mapstructure:"Mode"`
+
+	// Retry is the retry policy of the failed operation before halting when Mode is "retry"
+	Retry retry.Config `mapstructure:"Retry"`
+}
+
+const (
+	// FlushStrategyExecutor leaves the flushes to the executor, that persists the state writes in background
+	FlushStrategyExecutor = "executor"
+	// FlushStrategyTx flushes the state writes after each processed tx
+	FlushStrategyTx = "tx"
+	// FlushStrategyTxs flushes the state writes every TxsInterval processed txs and when the batch is closed
+	FlushStrategyTxs = "txs"
+	// FlushStrategyBatch flushes the state writes when the batch is closed
+	FlushStrategyBatch = "batch"
+)
+
+// FlushCfg contains the configuration of how often the state writes of the processed txs are flushed to the
+// merkle tree backend. The processed txs are stored in the state DB only once the executor reports their state
+// writes as flushed, whatever the strategy is, so the state DB never gets ahead of the tree. The strategy sets
+// the state writes that can be lost, and processed again, if the executor crashes: "tx" loses at most one tx
+// but adds a flush round trip to each tx, "txs" and "batch" trade a bigger window for a higher throughput, and
+// "executor" (the default) relies on the executor flushes, which is the fastest option and suits the setups with
+// well replicated tree storage. On start the finalizer checks the state root of the wip batch is in the tree
+type FlushCfg struct {
+	// Strategy is the flush strategy, the possible values are "executor", "tx", "txs" and "batch"
+	Strategy string `mapstructure:"Strategy"`
+
+	// TxsInterval is the number of processed txs between flushes when Strategy is "txs"
+	TxsInterval uint64 `mapstructure:"TxsInterval"`
 }
 
 // DBManagerCfg contains the DBManager's configuration properties
 type DBManagerCfg struct {
 	PoolRetrievalInterval    types.Duration `mapstructure:"PoolRetrievalInterval"`
 	L2ReorgRetrievalInterval types.Duration `mapstructure:"L2ReorgRetrievalInterval"`
+	// StateCacheTTL is the max time the last batch number, last L2 block header and latest GER are cached
+	// by the dbManager. They are invalidated when the sequencer modifies them, so the TTL only bounds the
+	// staleness of the values updated by other components. 0 disables the cache
+	StateCacheTTL types.Duration `mapstructure:"StateCacheTTL"`
+	// RestoreWorkerOnStart enables restoring in the worker the txs it had before a restart (the pending txs marked
+	// as WIP in the pool) with their stored zkcounters, instead of marking them as non WIP to be loaded again
+	RestoreWorkerOnStart bool `mapstructure:"RestoreWorkerOnStart"`
+	// RestoreWorkerTxsPerSecond is the max number of txs restored per second in the worker on start. 0 means no limit
+	RestoreWorkerTxsPerSecond uint64 `mapstructure:"RestoreWorkerTxsPerSecond"`
 }
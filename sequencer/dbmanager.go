@@ -3,15 +3,18 @@ package sequencer
 import (
 	"context"
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -28,8 +31,12 @@ type dbManager struct {
 	ctx                          context.Context
 	batchConstraints             state.BatchConstraintsCfg
 	numberOfStateInconsistencies uint64
+	numberOfL1Reorgs             uint64
 	streamServer                 *datastreamer.StreamServer
 	dataToStream                 chan state.DSL2FullBlock
+	cache                        *stateCache
+	// l2ReorgDetected is set from the L2 reorg detection until the finalizer handles it, the processed txs are not stored meanwhile
+	l2ReorgDetected atomic.Bool
 }
 
 func (d *dbManager) GetBatchByNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
@@ -53,11 +60,16 @@ func newDBManager(ctx context.Context, config DBManagerCfg, txPool txPool, state
 	if err != nil {
 		log.Error("failed to get number of reorgs: %v", err)
 	}
+	numberOfL1Reorgs, err := stateInterface.CountL1Reorgs(ctx, nil)
+	if err != nil {
+		log.Errorf("failed to get number of L1 reorgs: %v", err)
+	}
 
 	return &dbManager{ctx: ctx, cfg: config, txPool: txPool,
 		state: stateInterface, worker: worker, l2ReorgCh: closingSignalCh.L2ReorgCh,
-		batchConstraints: batchConstraints, numberOfStateInconsistencies: numberOfReorgs,
-		dataToStream: make(chan state.DSL2FullBlock, batchConstraints.MaxTxsPerBatch*datastreamChannelMultiplier)}
+		batchConstraints: batchConstraints, numberOfStateInconsistencies: numberOfReorgs, numberOfL1Reorgs: numberOfL1Reorgs,
+		dataToStream: make(chan state.DSL2FullBlock, batchConstraints.MaxTxsPerBatch*datastreamChannelMultiplier),
+		cache:        newStateCache(config.StateCacheTTL.Duration)}
 }
 
 // Start stars the dbManager routines
@@ -67,6 +79,7 @@ func (d *dbManager) Start() {
 		for {
 			time.Sleep(d.cfg.L2ReorgRetrievalInterval.Duration)
 			d.checkStateInconsistency()
+			d.checkL1Reorgs()
 		}
 	}()
 	if d.streamServer != nil {
@@ -76,12 +89,40 @@ func (d *dbManager) Start() {
 
 // GetLastBatchNumber get the latest batch number from state
 func (d *dbManager) GetLastBatchNumber(ctx context.Context) (uint64, error) {
-	return d.state.GetLastBatchNumber(ctx, nil)
+	if batchNumber, found := d.cache.getLastBatchNumber(); found {
+		return batchNumber, nil
+	}
+
+	generation := d.cache.currentGeneration()
+	batchNumber, err := d.state.GetLastBatchNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	d.cache.setLastBatchNumber(generation, batchNumber)
+
+	return batchNumber, nil
 }
 
 // OpenBatch opens a new batch to star processing transactions
-func (d *dbManager) OpenBatch(ctx context.Context, processingContext state.ProcessingContext, dbTx pgx.Tx) error {
-	return d.state.OpenBatch(ctx, processingContext, dbTx)
+func (d *dbManager) OpenBatch(ctx context.Context, processingContext state.ProcessingContext) error {
+	dbTx, err := d.BeginStateTransaction(ctx)
+	if err != nil {
+		log.Errorf("failed to begin state transaction for opening a batch, err: %v", err)
+		return err
+	}
+	err = d.state.OpenBatch(ctx, processingContext, dbTx)
+	if err != nil {
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			log.Errorf("failed to rollback dbTx when opening batch that gave err: %v. Rollback err: %v", err, rollbackErr)
+		}
+		return err
+	}
+	if err := dbTx.Commit(ctx); err != nil {
+		log.Errorf("failed to commit dbTx when opening batch, err: %v", err)
+		return err
+	}
+	d.cache.invalidateBatches()
+	return nil
 }
 
 // CreateFirstBatch is using during genesis
@@ -112,6 +153,7 @@ func (d *dbManager) CreateFirstBatch(ctx context.Context, sequencerAddress commo
 		log.Errorf("failed to commit dbTx when opening batch, err: %v", err)
 		return processingCtx
 	}
+	d.cache.invalidateBatches()
 	return processingCtx
 }
 
@@ -125,12 +167,92 @@ func (d *dbManager) checkStateInconsistency() {
 
 	if stateInconsistenciesDetected != d.numberOfStateInconsistencies {
 		log.Warnf("New State Inconsistency detected")
+		d.numberOfStateInconsistencies = stateInconsistenciesDetected
+		d.cache.invalidateAll()
+		// the finalizer handles the reorg from its loop, once it stops processing txs
+		d.l2ReorgDetected.Store(true)
 		d.l2ReorgCh <- L2ReorgEvent{}
 	}
 }
 
+// checkL1Reorgs checks if the synchronizer handled an L1 reorg. The trusted state is not changed by it,
+// the trusted reorgs are checked by checkStateInconsistency, but the cached L1 data (e.g. the latest GER)
+// may belong to the reorged blocks, so the cache is invalidated
+func (d *dbManager) checkL1Reorgs() {
+	l1Reorgs, err := d.state.CountL1Reorgs(d.ctx, nil)
+	if err != nil {
+		log.Errorf("failed to get number of L1 reorgs: %v", err)
+		return
+	}
+
+	if l1Reorgs != d.numberOfL1Reorgs {
+		log.Warnf("L1 reorg detected, invalidating the state cache")
+		d.numberOfL1Reorgs = l1Reorgs
+		d.cache.invalidateAll()
+	}
+}
+
+// HandleL2Reorg is called by the finalizer to handle the detected L2 reorg once the txs processed before it are
+// dropped, the processed txs are stored again from now on. It returns the hashes of the reorged txs
+func (d *dbManager) HandleL2Reorg() []common.Hash {
+	d.l2ReorgDetected.Store(false)
+	return d.handleL2Reorg()
+}
+
+// handleL2Reorg removes from the worker the txs reorged by the synchronizer and marks them as pending
+// in the pool, so they are loaded again in the worker after the nonce and balance of their senders
+// are refreshed with the new state. It returns the hashes of the reorged txs
+func (d *dbManager) handleL2Reorg() []common.Hash {
+	// The synchronizer stores the reorged txs again in the pool as WIP, so they are
+	// the WIP txs that are not held by the worker, as the txs dropped by the finalizer
+	poolTransactions, err := d.txPool.GetWIPPendingTxs(d.ctx)
+	if err != nil && err != pool.ErrNotFound {
+		log.Errorf("failed to get the reorged txs from the pool, err: %v", err)
+		return nil
+	}
+	snapshot := d.worker.GetTxsSnapshot()
+
+	reorgedTxs := make([]*TxTracker, 0)
+	for _, tx := range poolTransactions {
+		if _, found := snapshot.Ready[tx.Hash()]; found {
+			continue
+		}
+		if _, found := snapshot.NotReady[tx.Hash()]; found {
+			continue
+		}
+		txTracker, err := d.worker.NewTxTracker(tx.Transaction, tx.ZKCounters, tx.IP)
+		if err != nil {
+			log.Errorf("failed to create the tracker of the reorged tx %s, err: %v", tx.Hash().String(), err)
+			continue
+		}
+		reorgedTxs = append(reorgedTxs, txTracker)
+	}
+	log.Infof("handling L2 reorg of %d txs", len(reorgedTxs))
+
+	txsToDelete := d.worker.HandleL2Reorg(d.ctx, reorgedTxs)
+	for _, txToDelete := range txsToDelete {
+		err := d.txPool.UpdateTxStatus(d.ctx, txToDelete.Hash, pool.TxStatusFailed, false, txToDelete.FailedReason)
+		if err != nil {
+			log.Errorf("failed to update status to failed in the pool for tx: %s, err: %v", txToDelete.Hash.String(), err)
+		}
+	}
+
+	txHashes := make([]common.Hash, 0, len(reorgedTxs))
+	for _, tx := range reorgedTxs {
+		if err := d.txPool.UpdateTxWIPStatus(d.ctx, tx.Hash, false); err != nil {
+			log.Errorf("failed to mark the reorged tx %s as pending, err: %v", tx.Hash.String(), err)
+		}
+		txHashes = append(txHashes, tx.Hash)
+	}
+	return txHashes
+}
+
 // loadFromPool keeps loading transactions from the pool
 func (d *dbManager) loadFromPool() {
+	if d.cfg.RestoreWorkerOnStart {
+		d.restoreWorker()
+	}
+
 	for {
 		time.Sleep(d.cfg.PoolRetrievalInterval.Duration)
 
@@ -228,11 +350,46 @@ func (d *dbManager) sendDataToStreamer() {
 	}
 }
 
+// restoreWorker adds to the worker the pending txs marked as WIP in the pool, that are the txs
+// the worker had before the sequencer was restarted. They are added sorted by sender and nonce
+// with their stored zkcounters, limiting the number of txs restored per second
+func (d *dbManager) restoreWorker() {
+	poolTransactions, err := d.txPool.GetWIPPendingTxs(d.ctx)
+	if err != nil && err != pool.ErrNotFound {
+		log.Errorf("failed to get the WIP txs to restore the worker, marking them as pending, err: %v", err)
+		if err := d.txPool.MarkWIPTxsAsPending(d.ctx); err != nil {
+			log.Errorf("failed to mark WIP txs as pending, err: %v", err)
+		}
+		return
+	}
+
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if d.cfg.RestoreWorkerTxsPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(d.cfg.RestoreWorkerTxsPerSecond), int(d.cfg.RestoreWorkerTxsPerSecond))
+	}
+
+	log.Infof("restoring %d txs in the worker", len(poolTransactions))
+	start := time.Now()
+	for _, tx := range poolTransactions {
+		if err := limiter.Wait(d.ctx); err != nil {
+			log.Errorf("worker restore interrupted, err: %v", err)
+			return
+		}
+		if err := d.addTxToWorker(tx); err != nil {
+			log.Errorf("error restoring transaction %s in worker: %v", tx.Hash().String(), err)
+		}
+	}
+	log.Infof("%d txs restored in the worker in %v", len(poolTransactions), time.Since(start))
+}
+
 func (d *dbManager) addTxToWorker(tx pool.Transaction) error {
 	txTracker, err := d.worker.NewTxTracker(tx.Transaction, tx.ZKCounters, tx.IP)
 	if err != nil {
 		return err
 	}
+	txTracker.Conditions = tx.Conditions
+	txTracker.Tag = tx.Tag
+	txTracker.Sponsored = tx.Sponsored
 	replacedTx, dropReason := d.worker.AddTxTracker(d.ctx, txTracker)
 	if dropReason != nil {
 		failedReason := dropReason.Error()
@@ -240,15 +397,28 @@ func (d *dbManager) addTxToWorker(tx pool.Transaction) error {
 	} else {
 		if replacedTx != nil {
 			failedReason := ErrReplacedTransaction.Error()
+			if replacedTx.FailedReason != nil {
+				// the tx has been evicted by the new tx
+				failedReason = *replacedTx.FailedReason
+			}
 			error := d.txPool.UpdateTxStatus(d.ctx, replacedTx.Hash, pool.TxStatusFailed, false, &failedReason)
 			if error != nil {
 				log.Warnf("error when setting as failed replacedTx(%s)", replacedTx.HashStr)
 			}
 		}
+		if tx.IsWIP {
+			return nil
+		}
 		return d.txPool.UpdateTxWIPStatus(d.ctx, tx.Hash(), true)
 	}
 }
 
+// UpdateTxZKCounters stores in the pool the zkcounters used by a tx when it was executed,
+// so they are not estimated again if the tx is loaded again in the worker
+func (d *dbManager) UpdateTxZKCounters(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) error {
+	return d.txPool.UpdateTxZKCounters(ctx, hash, zkCounters)
+}
+
 // BeginStateTransaction starts a db transaction in the state
 func (d *dbManager) BeginStateTransaction(ctx context.Context) (pgx.Tx, error) {
 	return d.state.BeginStateTransaction(ctx)
@@ -259,11 +429,27 @@ func (d *dbManager) DeleteTransactionFromPool(ctx context.Context, txHash common
 	return d.txPool.DeleteTransactionByHash(ctx, txHash)
 }
 
-// StoreProcessedTxAndDeleteFromPool stores a tx into the state and changes it status in the pool
-func (d *dbManager) StoreProcessedTxAndDeleteFromPool(ctx context.Context, tx transactionToStore) error {
+// StoreProcessedTx stores a tx into the state. The state changes are committed at once, so it can be retried
+// until it succeeds, and once committed the tx is sent to the data streamer. The tx status in the pool is not
+// updated, it's done apart to not store the tx again if the pool update fails. The tx is not stored once an L2
+// reorg is detected, it was processed on the reorged state
+func (d *dbManager) StoreProcessedTx(ctx context.Context, tx transactionToStore) error {
 	d.checkStateInconsistency()
+	if d.l2ReorgDetected.Load() {
+		return ErrL2ReorgDetected
+	}
 
 	log.Debugf("Storing tx %v", tx.response.TxHash)
+	forkID := d.state.GetForkIDByBatchNumber(tx.batchNumber)
+	txData, err := state.EncodeTransaction(tx.response.Tx, uint8(tx.response.EffectivePercentage), forkID)
+	if err != nil {
+		return err
+	}
+	binaryTxData, err := tx.response.Tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
 	dbTx, err := d.BeginStateTransaction(ctx)
 	if err != nil {
 		return err
@@ -271,6 +457,10 @@ func (d *dbManager) StoreProcessedTxAndDeleteFromPool(ctx context.Context, tx tr
 
 	l2BlockHeader, err := d.state.StoreTransaction(ctx, tx.batchNumber, tx.response, tx.coinbase, uint64(tx.timestamp.Unix()), tx.egpLog, dbTx)
 	if err != nil {
+		err2 := dbTx.Rollback(ctx)
+		if err2 != nil {
+			log.Errorf("failed to rollback dbTx when storing tx that gave err: %v. Rollback err: %v", err, err2)
+		}
 		return err
 	}
 
@@ -283,12 +473,6 @@ func (d *dbManager) StoreProcessedTxAndDeleteFromPool(ctx context.Context, tx tr
 		}
 		return err
 	}
-
-	forkID := d.state.GetForkIDByBatchNumber(tx.batchNumber)
-	txData, err := state.EncodeTransaction(tx.response.Tx, uint8(tx.response.EffectivePercentage), forkID)
-	if err != nil {
-		return err
-	}
 	batch.BatchL2Data = append(batch.BatchL2Data, txData...)
 
 	if !tx.isForcedBatch {
@@ -306,19 +490,12 @@ func (d *dbManager) StoreProcessedTxAndDeleteFromPool(ctx context.Context, tx tr
 	if err != nil {
 		return err
 	}
+	d.cache.invalidateBatches()
 
-	// Change Tx status to selected
-	err = d.txPool.UpdateTxStatus(ctx, tx.response.TxHash, pool.TxStatusSelected, false, nil)
-	if err != nil {
-		return err
-	}
-
-	log.Infof("StoreProcessedTxAndDeleteFromPool: successfully stored tx: %v for batch: %v", tx.response.TxHash.String(), tx.batchNumber)
+	log.Infof("StoreProcessedTx: successfully stored tx: %v for batch: %v", tx.response.TxHash.String(), tx.batchNumber)
 
 	// Send data to streamer
 	if d.streamServer != nil {
-		forkID := d.state.GetForkIDByBatchNumber(tx.batchNumber)
-
 		l2Block := state.DSL2Block{
 			BatchNumber:    tx.batchNumber,
 			L2BlockNumber:  l2BlockHeader.Number.Uint64(),
@@ -330,11 +507,6 @@ func (d *dbManager) StoreProcessedTxAndDeleteFromPool(ctx context.Context, tx tr
 			StateRoot:      l2BlockHeader.Root,
 		}
 
-		binaryTxData, err := tx.response.Tx.MarshalBinary()
-		if err != nil {
-			return err
-		}
-
 		l2Transaction := state.DSL2Transaction{
 			EffectiveGasPricePercentage: uint8(tx.response.EffectivePercentage),
 			IsValid:                     1,
@@ -514,7 +686,30 @@ func (d *dbManager) GetLastNBatches(ctx context.Context, numBatches uint) ([]*st
 
 // GetLatestGer gets the latest global exit root
 func (d *dbManager) GetLatestGer(ctx context.Context, gerFinalityNumberOfBlocks uint64) (state.GlobalExitRoot, time.Time, error) {
-	return d.state.GetLatestGer(ctx, gerFinalityNumberOfBlocks)
+	if ger, receivedAt, found := d.cache.getLatestGER(gerFinalityNumberOfBlocks); found {
+		return ger, receivedAt, nil
+	}
+
+	generation := d.cache.currentGeneration()
+	ger, receivedAt, err := d.state.GetLatestGer(ctx, gerFinalityNumberOfBlocks)
+	if err != nil {
+		return ger, receivedAt, err
+	}
+	d.cache.setLatestGER(generation, gerFinalityNumberOfBlocks, ger, receivedAt)
+
+	return ger, receivedAt, nil
+}
+
+// GetLatestGerUpdate gets the latest global exit root from the state bypassing the cache, it's used to
+// detect the GER updates stored by the synchronizer so the cached GER is invalidated once it changes
+func (d *dbManager) GetLatestGerUpdate(ctx context.Context, maxBlockNumber uint64) (state.GlobalExitRoot, time.Time, error) {
+	ger, receivedAt, err := d.state.GetLatestGer(ctx, maxBlockNumber)
+	if err != nil {
+		return ger, receivedAt, err
+	}
+	d.cache.invalidateGERIfChanged(ger.GlobalExitRoot)
+
+	return ger, receivedAt, nil
 }
 
 // CloseBatch closes a batch in the state
@@ -554,6 +749,7 @@ func (d *dbManager) CloseBatch(ctx context.Context, params ClosingBatchParameter
 			log.Errorf("CloseBatch error committing: %v", err)
 			return err
 		}
+		d.cache.invalidateBatches()
 	}
 
 	return nil
@@ -603,6 +799,12 @@ func (d *dbManager) ProcessForcedBatch(ForcedBatchNumber uint64, request state.P
 	// Process Batch
 	processBatchResponse, err := d.state.ProcessSequencerBatch(d.ctx, request.BatchNumber, forcedBatch.RawTxsData, request.Caller, dbTx)
 	if err != nil {
+		if rollbackErr := dbTx.Rollback(d.ctx); rollbackErr != nil {
+			log.Errorf(
+				"failed to rollback dbTx when processing a forced batch that gave err: %v. Rollback err: %v",
+				err, rollbackErr,
+			)
+		}
 		log.Errorf("failed to process a forced batch, err: %v", err)
 		return nil, err
 	}
@@ -645,6 +847,8 @@ func (d *dbManager) ProcessForcedBatch(ForcedBatchNumber uint64, request state.P
 		}
 		tryToCloseAndCommit = err != nil
 	}
+	d.cache.invalidateBatches()
+	metrics.BatchClosed(string(state.ForcedBatchClosingReason), len(processBatchResponse.Responses))
 
 	return processBatchResponse, nil
 }
@@ -656,7 +860,22 @@ func (d *dbManager) GetForcedBatchesSince(ctx context.Context, forcedBatchNumber
 
 // GetLastL2BlockHeader gets the last l2 block number
 func (d *dbManager) GetLastL2BlockHeader(ctx context.Context, dbTx pgx.Tx) (*types.Header, error) {
-	return d.state.GetLastL2BlockHeader(ctx, dbTx)
+	// the cache is bypassed inside a db transaction to read its own writes
+	if dbTx != nil {
+		return d.state.GetLastL2BlockHeader(ctx, dbTx)
+	}
+	if header, found := d.cache.getLastL2BlockHeader(); found {
+		return header, nil
+	}
+
+	generation := d.cache.currentGeneration()
+	header, err := d.state.GetLastL2BlockHeader(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	d.cache.setLastL2BlockHeader(generation, header)
+
+	return header, nil
 }
 
 func (d *dbManager) GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Block, error) {
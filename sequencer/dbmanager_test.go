@@ -19,6 +19,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/test/testutils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 )
@@ -117,10 +118,10 @@ func TestOpenBatch(t *testing.T) {
 		GlobalExitRoot: common.Hash{},
 	}
 
-	err = testDbManager.OpenBatch(ctx, processingContext, dbTx)
-	require.NoError(t, err)
 	err = dbTx.Commit(ctx)
 	require.NoError(t, err)
+	err = testDbManager.OpenBatch(ctx, processingContext)
+	require.NoError(t, err)
 	cleanupDBManager()
 }
 
@@ -141,10 +142,10 @@ func TestGetLastBatchNumber(t *testing.T) {
 		GlobalExitRoot: common.Hash{},
 	}
 
-	err = testDbManager.OpenBatch(ctx, processingContext, dbTx)
-	require.NoError(t, err)
 	err = dbTx.Commit(ctx)
 	require.NoError(t, err)
+	err = testDbManager.OpenBatch(ctx, processingContext)
+	require.NoError(t, err)
 
 	lastBatchNum, err := testDbManager.GetLastBatchNumber(ctx)
 	require.NoError(t, err)
@@ -167,3 +168,20 @@ func TestCreateFirstBatch(t *testing.T) {
 	require.Equal(t, uint64(1), processingContext.BatchNumber)
 	cleanupDBManager()
 }
+
+func TestDBManagerStoreProcessedTxAfterL2Reorg(t *testing.T) {
+	stateMock := NewStateMock(t)
+	l2ReorgCh := make(chan L2ReorgEvent, 1)
+	d := &dbManager{ctx: context.Background(), state: stateMock, l2ReorgCh: l2ReorgCh, cache: newStateCache(time.Minute)}
+	txToStore := transactionToStore{batchNumber: 1}
+
+	// the reorg is detected before storing the tx, so it's not stored
+	stateMock.On("CountReorgs", mock.Anything, mock.Anything).Return(uint64(1), nil).Once()
+	require.ErrorIs(t, d.StoreProcessedTx(context.Background(), txToStore), ErrL2ReorgDetected)
+	require.Len(t, l2ReorgCh, 1)
+
+	// the txs are not stored until the finalizer handles the reorg
+	stateMock.On("CountReorgs", mock.Anything, mock.Anything).Return(uint64(1), nil).Once()
+	require.ErrorIs(t, d.StoreProcessedTx(context.Background(), txToStore), ErrL2ReorgDetected)
+	require.Len(t, l2ReorgCh, 1)
+}
@@ -5,13 +5,36 @@ import "errors"
 var (
 	// ErrExpiredTransaction happens when the transaction is expired
 	ErrExpiredTransaction = errors.New("transaction expired")
+	// ErrNotReadyTransactionExpired happens when the transaction has not been ready to be selected
+	// (nonce gap or not enough balance) for more than MaxTxNotReadyTime
+	ErrNotReadyTransactionExpired = errors.New("transaction expired, not ready to be processed for too long")
 	// ErrEffectiveGasPriceReprocess happens when the effective gas price requires reexecution
 	ErrEffectiveGasPriceReprocess = errors.New("effective gas price requires reprocessing the transaction")
 	// ErrDuplicatedNonce is returned when adding a new tx to the worker and there is an existing tx
 	// with the same nonce and higher gasPrice (in this case we keep the existing tx)
 	ErrDuplicatedNonce = errors.New("duplicated nonce")
+	// ErrReplacementUnderpriced is returned when adding a new tx to the worker and there is an existing tx
+	// with the same nonce and the gasPrice of the new tx is not higher enough (PriceBump) to replace it
+	ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
 	// ErrReplacedTransaction is returned when an existing tx is replaced by a new tx with the same nonce and higher gasPrice
 	ErrReplacedTransaction = errors.New("replaced transaction")
+	// ErrAccountTxsLimitReached is returned when adding a new tx to the worker and its sender already has MaxTxsPerAccount
+	// txs in the worker, none of them is a not ready tx with lower efficiency than the new one
+	ErrAccountTxsLimitReached = errors.New("account has reached the max number of txs in the sequencer")
+	// ErrWorkerFull is returned when adding a new tx to the worker and it already has MaxWorkerTxs txs,
+	// none of them is a not ready tx with lower efficiency than the new one
+	ErrWorkerFull = errors.New("sequencer is full, the tx has lower efficiency than the queued txs")
+	// ErrEvictedTransaction is set as the failed reason of a not ready tx evicted from the worker by a new tx
+	// with higher efficiency when the MaxTxsPerAccount or MaxWorkerTxs limit is reached
+	ErrEvictedTransaction = errors.New("transaction evicted by a tx with higher efficiency, the sequencer queue limit was reached")
+	// ErrPolicyDeniedSender is returned when adding a new tx to the worker and its sender is in the policy deny list
+	ErrPolicyDeniedSender = errors.New("sender is denied by the sequencer policy")
+	// ErrPolicyDeniedRecipient is returned when adding a new tx to the worker and its recipient is in the policy deny list
+	ErrPolicyDeniedRecipient = errors.New("recipient is denied by the sequencer policy")
+	// ErrPolicyDeniedSelector is returned when adding a new tx to the worker and its function selector is in the policy deny list
+	ErrPolicyDeniedSelector = errors.New("function selector is denied by the sequencer policy")
+	// ErrPolicyNotEnabled is returned when trying to reload the sequencer policy and it's not enabled
+	ErrPolicyNotEnabled = errors.New("sequencer policy is not enabled")
 	// ErrGetBatchByNumber happens when we get an error trying to get a batch by number (GetBatchByNumber)
 	ErrGetBatchByNumber = errors.New("get batch by number error")
 	// ErrDecodeBatchL2Data happens when we get an error trying to decode BatchL2Data (DecodeTxs)
@@ -25,4 +48,27 @@ var (
 	ErrStateRootNoMatch = errors.New("state root no match")
 	// ErrExecutorError happens when we got an executor error when processing a batch
 	ErrExecutorError = errors.New("executor error")
+	// ErrFinalizerNotHalted is returned when trying to resume the finalizer and it's not halted
+	ErrFinalizerNotHalted = errors.New("finalizer is not halted")
+	// ErrFinalizerHaltNotResumable is returned when trying to resume the finalizer and it's halted by an error
+	// that can't be recovered by resuming it, the sequencer must be restarted
+	ErrFinalizerHaltNotResumable = errors.New("finalizer is halted by an error that can't be resumed, the sequencer must be restarted")
+	// ErrFinalizerStopping is returned by a critical operation that fails while the finalizer is stopping, it's
+	// not halted since it can't be resumed anymore
+	ErrFinalizerStopping = errors.New("finalizer is stopping")
+	// ErrFinalizerNotStarted is returned when trying to access the finalizer before the sequencer is started
+	ErrFinalizerNotStarted = errors.New("finalizer is not started")
+	// ErrSequencingNotPaused is returned when trying to resume the sequencing and it's not paused
+	ErrSequencingNotPaused = errors.New("sequencing is not paused")
+	// ErrSequencingAlreadyPaused is returned when trying to pause the sequencing and it's already paused
+	ErrSequencingAlreadyPaused = errors.New("sequencing is already paused")
+	// ErrWorkerNotStarted is returned when trying to access the worker before the sequencer is started
+	ErrWorkerNotStarted = errors.New("worker is not started")
+	// ErrProvingBudgetExceeded happens when including a tx in the batch exceeds the max proving complexity of the batch
+	ErrProvingBudgetExceeded = errors.New("proving budget exceeded")
+	// ErrOutOfCountersAtBatchStart happens when a tx exceeds the counters of an empty batch, so it can't be included in any batch
+	ErrOutOfCountersAtBatchStart = errors.New("out of counters at batch start")
+	// ErrL2ReorgDetected is returned when a processed tx is not stored because an L2 reorg was detected and
+	// the finalizer has not handled it yet
+	ErrL2ReorgDetected = errors.New("L2 reorg detected, the processed txs are not stored until it's handled")
 )
@@ -5,22 +5,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/clock"
 	"github.com/0xPolygonHermez/zkevm-node/event"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	stateMetrics "github.com/0xPolygonHermez/zkevm-node/state/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/jackc/pgx/v4"
 )
 
 const (
@@ -30,7 +32,9 @@ const (
 )
 
 var (
-	now = time.Now
+	// now returns the current time of the clock used by the finalizer, the tests replace it with
+	// a fake clock
+	now = clock.System.Now
 )
 
 // finalizer represents the finalizer component of the sequencer.
@@ -38,7 +42,7 @@ type finalizer struct {
 	cfg                     FinalizerCfg
 	closingSignalCh         ClosingSignalCh
 	isSynced                func(ctx context.Context) bool
-	sequencerAddress        common.Address
+	l2Coinbase              common.Address
 	worker                  workerInterface
 	dbManager               dbManagerInterface
 	executor                stateInterface
@@ -55,7 +59,8 @@ type finalizer struct {
 	nextForcedBatches       []state.ForcedBatch
 	nextForcedBatchDeadline int64
 	nextForcedBatchesMux    *sync.RWMutex
-	handlingL2Reorg         bool
+	// handlingL2Reorg is set once an L2 reorg is received until the finalizeBatches loop handles it
+	handlingL2Reorg atomic.Bool
 	// event log
 	eventLog *event.EventLog
 	// effective gas price calculation
@@ -68,6 +73,28 @@ type finalizer struct {
 	proverID                     string
 	lastPendingFlushID           uint64
 	pendingFlushIDCond           *sync.Cond
+	// txsSinceFlush is the number of processed txs since the last flush of the state writes
+	txsSinceFlush uint64
+	// nextBatchTemplate is the template of the next batch prepared while waiting for new txs, nil if there is no template
+	nextBatchTemplate *batchTemplate
+	// halt policy, haltNotResumable is set once the finalizer is halted by an error that can't be resumed
+	halted           atomic.Bool
+	haltNotResumable atomic.Bool
+	resumeCh         chan struct{}
+	// txsNotStored is set when a processed tx of the WIP batch fails to be stored, the batch can't be closed
+	txsNotStored atomic.Bool
+	// paused stops selecting txs for the batches while the operator has paused the sequencing
+	paused atomic.Bool
+	// proving budget, nil when it's disabled
+	provingBudget *provingBudget
+	// txSelectedHandlers are notified of the txs selected for the WIP batch
+	txSelectedHandlers []TxSelectedEventHandler
+	// l2ReorgHandlers are notified of the txs reorged from the trusted state
+	l2ReorgHandlers []L2ReorgEventHandler
+	// graceful shutdown, stopCh is closed to stop the finalizer and stoppedCh once it's stopped
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	stoppedCh chan struct{}
 }
 
 type transactionToStore struct {
@@ -95,13 +122,25 @@ type WipBatch struct {
 	globalExitRoot     common.Hash // 0x000...0 (ZeroHash) means to not update
 	remainingResources state.BatchResources
 	countOfTxs         int
+	lastTxTimestamp    time.Time
 	closingReason      state.ClosingReason
+	// provingBudgetReached is set when a tx didn't fit in the batch because of the proving budget
+	provingBudgetReached bool
 }
 
 func (w *WipBatch) isEmpty() bool {
 	return w.countOfTxs == 0
 }
 
+// lastActivity returns the time the last tx was added to the batch, or the batch timestamp if
+// no tx was added since the batch was opened or restored from the state
+func (w *WipBatch) lastActivity() time.Time {
+	if w.lastTxTimestamp.After(w.timestamp) {
+		return w.lastTxTimestamp
+	}
+	return w.timestamp
+}
+
 // newFinalizer returns a new instance of Finalizer.
 func newFinalizer(
 	cfg FinalizerCfg,
@@ -109,7 +148,7 @@ func newFinalizer(
 	worker workerInterface,
 	dbManager dbManagerInterface,
 	executor stateInterface,
-	sequencerAddr common.Address,
+	l2Coinbase common.Address,
 	isSynced func(ctx context.Context) bool,
 	closingSignalCh ClosingSignalCh,
 	batchConstraints state.BatchConstraintsCfg,
@@ -119,7 +158,7 @@ func newFinalizer(
 		cfg:                cfg,
 		closingSignalCh:    closingSignalCh,
 		isSynced:           isSynced,
-		sequencerAddress:   sequencerAddr,
+		l2Coinbase:         l2Coinbase,
 		worker:             worker,
 		dbManager:          dbManager,
 		executor:           executor,
@@ -135,7 +174,6 @@ func newFinalizer(
 		nextForcedBatches:       make([]state.ForcedBatch, 0),
 		nextForcedBatchDeadline: 0,
 		nextForcedBatchesMux:    new(sync.RWMutex),
-		handlingL2Reorg:         false,
 		// event log
 		eventLog: eventLog,
 		// effective gas price calculation instance
@@ -148,9 +186,23 @@ func newFinalizer(
 		proverID:           "",
 		lastPendingFlushID: 0,
 		pendingFlushIDCond: sync.NewCond(&sync.Mutex{}),
+		resumeCh:           make(chan struct{}, 1),
+		stopCh:             make(chan struct{}),
+		stoppedCh:          make(chan struct{}),
+	}
+	switch cfg.Flush.Strategy {
+	case "", FlushStrategyExecutor, FlushStrategyTx, FlushStrategyTxs, FlushStrategyBatch:
+	default:
+		log.Fatalf("invalid finalizer config: unknown flush strategy %s", cfg.Flush.Strategy)
+	}
+	if cfg.ProvingBudget.Enabled {
+		f.provingBudget = newProvingBudget(cfg.ProvingBudget, batchConstraints, executor)
 	}
 
 	f.reprocessFullBatchError.Store(false)
+	f.halted.Store(false)
+	f.haltNotResumable.Store(false)
+	f.handlingL2Reorg.Store(false)
 
 	return &f
 }
@@ -173,6 +225,8 @@ func (f *finalizer) Start(ctx context.Context, batch *WipBatch, processingReq *s
 		f.processRequest = *processingReq
 	}
 
+	f.reconcileFlushedState(ctx)
+
 	// Closing signals receiver
 	go f.listenForClosingSignals(ctx)
 
@@ -182,6 +236,11 @@ func (f *finalizer) Start(ctx context.Context, batch *WipBatch, processingReq *s
 	// Store Pending transactions
 	go f.storePendingTransactions(ctx)
 
+	// Learn the batches proving budget
+	if f.provingBudget != nil {
+		go f.provingBudget.start(ctx)
+	}
+
 	// Processing transactions and finalizing batches
 	f.finalizeBatches(ctx)
 }
@@ -285,9 +344,7 @@ func (f *finalizer) listenForClosingSignals(ctx context.Context) {
 		// L2Reorg ch
 		case <-f.closingSignalCh.L2ReorgCh:
 			log.Debug("finalizer received L2 reorg event")
-			f.handlingL2Reorg = true
-			f.halt(ctx, fmt.Errorf("L2 reorg event received"))
-			return
+			f.handlingL2Reorg.Store(true)
 		}
 	}
 }
@@ -324,15 +381,32 @@ func (f *finalizer) finalizeBatches(ctx context.Context) {
 	for {
 		start := now()
 		if f.batch.batchNumber == f.cfg.StopSequencerOnBatchNum {
-			f.halt(ctx, fmt.Errorf("finalizer reached stop sequencer batch number: %v", f.cfg.StopSequencerOnBatchNum))
+			f.waitForStop(ctx)
+		}
+
+		if f.handlingL2Reorg.Load() {
+			f.handleL2Reorg(ctx)
 		}
 
-		tx := f.worker.GetBestFittingTx(f.batch.remainingResources)
+		var tx *TxTracker
+		// no txs are selected while the sequencing is paused, the batches are still closed by their deadlines
+		if !f.paused.Load() && !f.isStopping() && !f.handlingL2Reorg.Load() {
+			if tag, found := activeSequencingWindowTag(f.cfg.SequencingWindows, now().Sub(f.batch.timestamp)); found {
+				tx = f.worker.GetBestFittingTaggedTx(f.batch.remainingResources, tag)
+			} else {
+				tx = f.worker.GetBestFittingTx(f.batch.remainingResources)
+			}
+		}
 		metrics.WorkerProcessingTime(time.Since(start))
 		if tx != nil {
 			log.Debugf("processing tx: %s", tx.Hash.Hex())
 			showNotFoundTxLog = true
 
+			if tx.Conditions != nil && !f.checkTxConditions(ctx, tx) {
+				continue
+			}
+			f.notifyTxSelected(tx)
+
 			firstTxProcess := true
 
 			f.sharedResourcesMux.Lock()
@@ -357,6 +431,9 @@ func (f *finalizer) finalizeBatches(ctx context.Context) {
 				log.Debug("no transactions to be processed. Waiting...")
 				showNotFoundTxLog = false
 			}
+			if f.cfg.PreWarm.Enabled {
+				f.preWarmNextBatch(ctx)
+			}
 			if f.cfg.SleepDuration.Duration > 0 {
 				time.Sleep(f.cfg.SleepDuration.Duration)
 			}
@@ -368,14 +445,22 @@ func (f *finalizer) finalizeBatches(ctx context.Context) {
 			f.halt(ctx, fmt.Errorf("halting Sequencer because of error reprocessing full batch (sanity check). Check previous errors in logs to know which was the cause"))
 		}
 
-		if f.isDeadlineEncountered() {
-			log.Infof("closing batch %d because deadline was encountered.", f.batch.batchNumber)
-			f.finalizeBatch(ctx)
-		} else if f.isBatchFull() || f.isBatchAlmostFull() {
-			log.Infof("closing batch %d because it's almost full.", f.batch.batchNumber)
+		if f.haltNotResumable.Load() {
+			// the finalizer was halted by an error that can't be resumed and it has been asked to stop
+			f.stopFinalizing(ctx)
+			return
+		}
+
+		if f.isDeadlineEncountered() || f.isBatchFull() || f.isBatchAlmostFull() || f.isProvingBudgetReached() || f.isUtilizationTargetReached() {
+			log.Infof("closing batch %d with %d txs, reason: %s", f.batch.batchNumber, f.batch.countOfTxs, f.batch.closingReason)
 			f.finalizeBatch(ctx)
 		}
 
+		if f.isStopping() {
+			f.stopFinalizing(ctx)
+			return
+		}
+
 		if err := ctx.Err(); err != nil {
 			log.Infof("stopping finalizer because of context, err: %s", err)
 			return
@@ -417,16 +502,105 @@ func (f *finalizer) finalizeBatch(ctx context.Context) {
 		metrics.ProcessingTime(time.Since(start))
 	}()
 
-	var err error
-	f.batch, err = f.newWIPBatch(ctx)
+	wipBatch, err := f.newWIPBatch(ctx)
 	for err != nil {
+		if errors.Is(err, ErrFinalizerHaltNotResumable) {
+			// the current batch is kept open, it's discarded when the sequencer is restarted
+			return
+		}
 		log.Errorf("failed to create new work-in-progress batch, Err: %s", err)
-		f.batch, err = f.newWIPBatch(ctx)
+		wipBatch, err = f.newWIPBatch(ctx)
 	}
+	f.batch = wipBatch
 }
 
-// halt halts the finalizer
+// halt halts the finalizer because of an error that can't be recovered by resuming it (e.g. a state root
+// mismatch of the sanity check), the sequencer must be restarted. A critical event is always logged, whatever
+// the halt policy mode is, and the finalizer stays halted until it's stopped
 func (f *finalizer) halt(ctx context.Context, err error) {
+	f.logHaltEvent(ctx, err)
+
+	f.haltNotResumable.Store(true)
+	f.halted.Store(true)
+
+	for {
+		log.Errorf("fatal error: %s", err)
+		log.Error("halting the finalizer, the sequencer must be restarted")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.stopCh:
+			log.Warnf("finalizer stopped while halted due to error: %s", err)
+			return
+		case <-time.After(5 * time.Second): //nolint:gomnd
+		}
+	}
+}
+
+// haltUntilResumed halts the finalizer applying the halt policy, the critical event is logged unless the mode
+// is "halt" and the finalizer waits until it's resumed by the operator after reviewing the error
+func (f *finalizer) haltUntilResumed(ctx context.Context, err error) {
+	if f.cfg.HaltPolicy.Mode != HaltPolicyModeHalt {
+		f.logHaltEvent(ctx, err)
+	}
+
+	// a resume received after the previous halt finished doesn't apply to this one
+	select {
+	case <-f.resumeCh:
+	default:
+	}
+	f.halted.Store(true)
+	defer f.halted.Store(false)
+
+	for {
+		log.Errorf("fatal error: %s", err)
+		log.Error("halting the finalizer until it's resumed")
+
+		select {
+		case <-f.resumeCh:
+			log.Infof("finalizer resumed after halt due to error: %s", err)
+			event := &event.Event{
+				ReceivedAt:  time.Now(),
+				Source:      event.Source_Node,
+				Component:   event.Component_Sequencer,
+				Level:       event.Level_Warning,
+				EventID:     event.EventID_FinalizerResume,
+				Description: fmt.Sprintf("finalizer resumed after halt due to error: %s", err),
+			}
+			eventErr := f.eventLog.LogEvent(ctx, event)
+			if eventErr != nil {
+				log.Errorf("error storing finalizer resume event: %v", eventErr)
+			}
+			return
+		case <-ctx.Done():
+			return
+		case <-f.stopCh:
+			log.Warnf("finalizer stopped while halted due to error: %s", err)
+			return
+		case <-time.After(5 * time.Second): //nolint:gomnd
+		}
+	}
+}
+
+// waitForStop stops the sequencing once the StopSequencerOnBatchNum batch is reached, it's not an
+// error so the halt policy isn't applied, the finalizer waits until the sequencer is stopped
+func (f *finalizer) waitForStop(ctx context.Context) {
+	for {
+		log.Warnf("finalizer reached stop sequencer batch number: %v, waiting for the sequencer to be stopped", f.cfg.StopSequencerOnBatchNum)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.stopCh:
+			return
+		case <-time.After(5 * time.Second): //nolint:gomnd
+		}
+	}
+}
+
+// logHaltEvent logs the critical event of the halt of the finalizer
+func (f *finalizer) logHaltEvent(ctx context.Context, err error) {
 	event := &event.Event{
 		ReceivedAt:  time.Now(),
 		Source:      event.Source_Node,
@@ -440,11 +614,47 @@ func (f *finalizer) halt(ctx context.Context, err error) {
 	if eventErr != nil {
 		log.Errorf("error storing finalizer halt event: %v", eventErr)
 	}
+}
+
+// resume resumes the finalizer if it's halted waiting to be resumed. The resume is buffered, so it's not lost
+// if the halted finalizer isn't waiting on the channel at that moment
+func (f *finalizer) resume() error {
+	if f.haltNotResumable.Load() {
+		return ErrFinalizerHaltNotResumable
+	}
+	if !f.halted.Load() {
+		return ErrFinalizerNotHalted
+	}
+	select {
+	case f.resumeCh <- struct{}{}:
+	default:
+		// the finalizer is already being resumed
+	}
+	return nil
+}
+
+// runCriticalOperation runs an operation whose failure must not be ignored applying the halt policy.
+// When the operation fails it's retried (if the policy allows it) and after that the finalizer is halted until
+// it's resumed, the operation is tried again once the finalizer is resumed, so it only returns an error if ctx is
+// done or the finalizer is stopping, the failed operation isn't retried nor halted while stopping
+func (f *finalizer) runCriticalOperation(ctx context.Context, operation func() error) error {
+	var retryCfg retry.Config
+	if f.cfg.HaltPolicy.Mode == HaltPolicyModeRetry {
+		retryCfg = f.cfg.HaltPolicy.Retry
+	}
+	isRetryable := func(error) bool { return !f.isStopping() }
 
 	for {
-		log.Errorf("fatal error: %s", err)
-		log.Error("halting the finalizer")
-		time.Sleep(5 * time.Second) //nolint:gomnd
+		err := retry.Do(ctx, "finalizer_critical_operation", retryCfg, isRetryable, operation)
+		if err == nil {
+			return nil
+		} else if ctx.Err() != nil {
+			return err
+		} else if f.isStopping() {
+			return fmt.Errorf("%w: %v", ErrFinalizerStopping, err)
+		}
+
+		f.haltUntilResumed(ctx, err)
 	}
 }
 
@@ -502,6 +712,7 @@ func (f *finalizer) newWIPBatch(ctx context.Context) (*WipBatch, error) {
 		if err != nil {
 			// There is an error reprocessing the batch. We halt the execution of the Sequencer at this point
 			f.halt(ctx, fmt.Errorf("halting Sequencer because of error reprocessing full batch %d (sanity check). Error: %s ", f.batch.batchNumber, err))
+			return nil, ErrFinalizerHaltNotResumable
 		}
 	} else {
 		// Do the full batch reprocess in parallel
@@ -572,8 +783,11 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 		f.processRequest.Transactions = tx.RawTx
 		hashStr = tx.HashStr
 
-		// If it is the first time we process this tx then we calculate the EffectiveGasPrice
-		if firstTxProcess {
+		// If it is the first time we process this tx then we calculate the EffectiveGasPrice. The sponsored
+		// txs pay no fee, so there is no effective gas price to calculate
+		if firstTxProcess && tx.Sponsored {
+			tx.IsLastExecution = true
+		} else if firstTxProcess {
 			// Get L1 gas price and store in txTracker to make it consistent during the lifespan of the transaction
 			tx.L1GasPrice, tx.L2GasPrice = f.dbManager.GetL1AndL2GasPrice()
 			// Calculate EffectiveGasPrice
@@ -611,7 +825,7 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 		}
 
 		effectivePercentage, err := f.effectiveGasPrice.CalculateEffectiveGasPricePercentage(tx.GasPrice, tx.EffectiveGasPrice)
-		if err != nil {
+		if err != nil && !tx.Sponsored {
 			if f.effectiveGasPrice.IsEnabled() {
 				return nil, err
 			} else {
@@ -623,8 +837,8 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 			tx.EGPLog.Percentage = effectivePercentage
 		}
 
-		// If EGP is disabled we use tx GasPrice (MaxEffectivePercentage=255)
-		if !f.effectiveGasPrice.IsEnabled() {
+		// If EGP is disabled or the tx is sponsored we use tx GasPrice (MaxEffectivePercentage=255)
+		if !f.effectiveGasPrice.IsEnabled() || tx.Sponsored {
 			effectivePercentage = state.MaxEffectivePercentage
 		}
 
@@ -641,14 +855,35 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 		f.processRequest.Transactions = []byte{}
 	}
 
+	var simulation *candidateSimulation
+	if tx != nil && f.cfg.CandidateSimulation.Enabled {
+		simulation, errWg, err = f.simulateCandidateTx(ctx, tx)
+		if err != nil {
+			return errWg, err
+		}
+	}
+
 	log.Infof("processTransaction: single tx. Batch.BatchNumber: %d, BatchNumber: %d, OldStateRoot: %s, txHash: %s, GER: %s", f.batch.batchNumber, f.processRequest.BatchNumber, f.processRequest.OldStateRoot, hashStr, f.processRequest.GlobalExitRoot.String())
-	processBatchResponse, err := f.executor.ProcessBatch(ctx, f.processRequest, true)
-	if err != nil && errors.Is(err, runtime.ErrExecutorDBError) {
-		log.Errorf("failed to process transaction: %s", err)
+	var processBatchResponse *state.ProcessBatchResponse
+	// the executor DB errors are fatal, the halt policy is applied and the tx is processed again once it's resumed
+	criticalErr := f.runCriticalOperation(ctx, func() error {
+		processBatchResponse, err = f.executor.ProcessBatch(ctx, f.processRequest, true)
+		if err != nil && errors.Is(err, runtime.ErrExecutorDBError) {
+			log.Errorf("failed to process transaction: %s", err)
+			return err
+		}
+		return nil
+	})
+	if criticalErr != nil {
+		return nil, criticalErr
+	}
+	if err != nil && tx != nil && isTransientExecutorError(err) {
+		f.retryTxLater(ctx, tx, err)
 		return nil, err
 	} else if err == nil && !processBatchResponse.IsRomLevelError && len(processBatchResponse.Responses) == 0 && tx != nil {
 		err = fmt.Errorf("executor returned no errors and no responses for tx: %s", tx.HashStr)
 		f.halt(ctx, err)
+		return nil, err
 	} else if processBatchResponse.IsExecutorLevelError && tx != nil {
 		log.Errorf("error received from executor. Error: %v", err)
 		// Delete tx from the worker
@@ -665,6 +900,10 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 		return nil, err
 	}
 
+	if simulation != nil {
+		simulation.checkConflict(f.processRequest, processBatchResponse)
+	}
+
 	oldStateRoot := f.batch.stateRoot
 	if len(processBatchResponse.Responses) > 0 && tx != nil {
 		errWg, err = f.handleProcessTransactionResponse(ctx, tx, processBatchResponse, oldStateRoot)
@@ -764,8 +1003,10 @@ func (f *finalizer) handleProcessTransactionResponse(ctx context.Context, tx *Tx
 	f.updateLastPendingFlushID(result.FlushID)
 
 	f.addPendingTxToStore(ctx, txToStore)
+	f.flushAfterTx(ctx)
 
 	f.batch.countOfTxs++
+	f.batch.lastTxTimestamp = now()
 
 	f.updateWorkerAfterSuccessfulProcessing(ctx, tx.Hash, tx.From, false, result)
 
@@ -810,6 +1051,7 @@ func (f *finalizer) handleForcedTxsProcessResp(ctx context.Context, request stat
 		f.updateLastPendingFlushID(result.FlushID)
 
 		f.addPendingTxToStore(ctx, txToStore)
+		f.flushAfterTx(ctx)
 
 		if err == nil {
 			f.updateWorkerAfterSuccessfulProcessing(ctx, txResp.TxHash, from, true, result)
@@ -860,19 +1102,82 @@ func (f *finalizer) CompareTxEffectiveGasPrice(ctx context.Context, tx *TxTracke
 
 // storeProcessedTx stores the processed transaction in the database.
 func (f *finalizer) storeProcessedTx(ctx context.Context, txToStore transactionToStore) {
+	// the txs processed before an L2 reorg are dropped, they are loaded again from the pool once it's handled
+	if f.handlingL2Reorg.Load() {
+		log.Warnf("storeProcessedTx: dropping processed txToStore %s because of the L2 reorg", txToStore.hash.String())
+		return
+	}
 	if txToStore.response != nil {
 		log.Infof("storeProcessedTx: storing processed txToStore: %s", txToStore.response.TxHash.String())
 	} else {
 		log.Info("storeProcessedTx: storing processed txToStore")
 	}
-	err := f.dbManager.StoreProcessedTxAndDeleteFromPool(ctx, txToStore)
+	dropped := false
+	err := f.runCriticalOperation(ctx, func() error {
+		err := f.dbManager.StoreProcessedTx(ctx, txToStore)
+		if errors.Is(err, ErrL2ReorgDetected) {
+			dropped = true
+			return nil
+		} else if err != nil {
+			log.Errorf("database error on storing processed transaction, err: %v", err)
+		}
+		return err
+	})
 	if err != nil {
-		log.Info("halting the finalizer because of a database error on storing processed transaction")
-		f.halt(ctx, err)
+		log.Errorf("failed to store processed transaction, err: %v", err)
+		f.txsNotStored.Store(true)
+		return
+	}
+	if dropped {
+		log.Warnf("storeProcessedTx: dropping processed txToStore %s because of the L2 reorg", txToStore.hash.String())
+		return
+	}
+
+	// the tx is already stored in the state, only the pool status update is retried if it fails
+	err = f.runCriticalOperation(ctx, func() error {
+		err := f.dbManager.UpdateTxStatus(ctx, txToStore.response.TxHash, pool.TxStatusSelected, false, nil)
+		if err != nil {
+			log.Errorf("database error on updating the pool status of the stored transaction, err: %v", err)
+		}
+		return err
+	})
+	if err != nil {
+		log.Errorf("failed to update the pool status of the stored transaction, err: %v", err)
+		return
 	}
 	metrics.TxProcessed(metrics.TxProcessedLabelSuccessful, 1)
 }
 
+// checkTxConditions checks the preconditions of a conditional tx against the L2 block
+// where it's going to be included, the tx is dropped if they are not met and it's
+// retried later if they can't be checked
+func (f *finalizer) checkTxConditions(ctx context.Context, tx *TxTracker) bool {
+	header, err := f.dbManager.GetLastL2BlockHeader(ctx, nil)
+	if err != nil {
+		f.retryTxLater(ctx, tx, fmt.Errorf("failed to get last L2 block header to check the tx conditions: %w", err))
+		return false
+	}
+
+	getStorage := pool.NewStorageGetter(f.batch.stateRoot, f.executor.GetStorageAt)
+	err = tx.Conditions.Check(ctx, header.Number.Uint64()+1, f.batch.timestamp, getStorage)
+	if err == nil {
+		return true
+	} else if !errors.Is(err, pool.ErrTxConditionsNotMet) && !errors.Is(err, pool.ErrStorageRootConditionNotSupported) {
+		f.retryTxLater(ctx, tx, fmt.Errorf("failed to check the tx conditions: %w", err))
+		return false
+	}
+
+	log.Infof("dropping tx %s because its conditions are not met: %v", tx.HashStr, err)
+	f.worker.DeleteTx(tx.Hash, tx.From)
+	failedReason := err.Error()
+	if err := f.dbManager.UpdateTxStatus(ctx, tx.Hash, pool.TxStatusFailed, false, &failedReason); err != nil {
+		log.Errorf("failed to update status to failed in the pool for tx: %s, err: %s", tx.Hash.String(), err)
+	} else {
+		metrics.TxProcessed(metrics.TxProcessedLabelFailed, 1)
+	}
+	return false
+}
+
 func (f *finalizer) updateWorkerAfterSuccessfulProcessing(ctx context.Context, txHash common.Hash, txFrom common.Address, isForced bool, result *state.ProcessBatchResponse) {
 	// Delete the transaction from the worker
 	if isForced {
@@ -897,6 +1202,38 @@ func (f *finalizer) updateWorkerAfterSuccessfulProcessing(ctx context.Context, t
 	metrics.WorkerProcessingTime(time.Since(start))
 }
 
+// retryTxLater skips a tx that failed because of a transient executor error so it's selected
+// again after a delay, once it reaches the max number of attempts it's discarded as failed
+func (f *finalizer) retryTxLater(ctx context.Context, tx *TxTracker, txErr error) {
+	if f.worker.RetryTxLater(tx.Hash, tx.From) {
+		log.Warnf("transient error processing tx %s, it will be retried later, err: %v", tx.HashStr, txErr)
+		return
+	}
+
+	log.Errorf("transient error processing tx %s, discarding it as it can't be retried, err: %v", tx.HashStr, txErr)
+	f.worker.DeleteTx(tx.Hash, tx.From)
+	failedReason := txErr.Error()
+	err := f.dbManager.UpdateTxStatus(ctx, tx.Hash, pool.TxStatusFailed, false, &failedReason)
+	if err != nil {
+		log.Errorf("failed to update status to failed in the pool for tx: %s, err: %s", tx.Hash.String(), err)
+	} else {
+		metrics.TxProcessed(metrics.TxProcessedLabelFailed, 1)
+	}
+}
+
+// isTransientExecutorError returns if the executor failed to process a tx for a reason not related to
+// the tx itself, like a connection error or an unspecified executor error, so it can be retried
+func isTransientExecutorError(err error) bool {
+	if errors.Is(err, executor.ErrExecutorUnspecified) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, executor.ErrExecutorUnknown) || errors.Is(err, executor.ErrROMUnknown) {
+		return false
+	}
+	// the errors returned by the executor or the ROM while processing the tx are not transient
+	return executor.ExecutorErrorCode(err) == math.MaxInt32 && executor.RomErrorCode(err) == math.MaxInt32
+}
+
 // handleProcessTransactionError handles the error of a transaction
 func (f *finalizer) handleProcessTransactionError(ctx context.Context, result *state.ProcessBatchResponse, tx *TxTracker) *sync.WaitGroup {
 	txResponse := result.Responses[0]
@@ -1026,7 +1363,7 @@ func (f *finalizer) syncWithState(ctx context.Context, lastBatchNum *uint64) err
 		BatchNumber:    *lastBatchNum,
 		OldStateRoot:   f.batch.stateRoot,
 		GlobalExitRoot: f.batch.globalExitRoot,
-		Coinbase:       f.sequencerAddress,
+		Coinbase:       f.l2Coinbase,
 		Timestamp:      f.batch.timestamp,
 		Transactions:   make([]byte, 0, 1),
 		Caller:         stateMetrics.SequencerCallerLabel,
@@ -1078,15 +1415,23 @@ func (f *finalizer) processForcedBatch(ctx context.Context, lastBatchNumberInSta
 		OldStateRoot:   stateRoot,
 		GlobalExitRoot: forcedBatch.GlobalExitRoot,
 		Transactions:   forcedBatch.RawTxsData,
-		Coinbase:       f.sequencerAddress,
+		Coinbase:       f.l2Coinbase,
 		Timestamp:      now(),
 		Caller:         stateMetrics.SequencerCallerLabel,
 	}
 
-	response, err := f.dbManager.ProcessForcedBatch(forcedBatch.ForcedBatchNumber, request)
+	var response *state.ProcessBatchResponse
+	err := f.runCriticalOperation(ctx, func() error {
+		var err error
+		response, err = f.dbManager.ProcessForcedBatch(forcedBatch.ForcedBatchNumber, request)
+		if err != nil {
+			// If there is EXECUTOR (Batch level) error, apply the halt policy.
+			return fmt.Errorf("failed to process forced batch, Executor err: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		// If there is EXECUTOR (Batch level) error, halt the finalizer.
-		f.halt(ctx, fmt.Errorf("failed to process forced batch, Executor err: %w", err))
+		log.Error(err)
 		return lastBatchNumberInState, stateRoot
 	}
 
@@ -1117,35 +1462,23 @@ func (f *finalizer) processForcedBatch(ctx context.Context, lastBatchNumberInSta
 
 // openWIPBatch opens a new batch in the state and returns it as WipBatch
 func (f *finalizer) openWIPBatch(ctx context.Context, batchNum uint64, ger, stateRoot common.Hash) (*WipBatch, error) {
-	dbTx, err := f.dbManager.BeginStateTransaction(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin state transaction to open batch, err: %w", err)
-	}
-
 	// open next batch
-	openBatchResp, err := f.openBatch(ctx, batchNum, ger, dbTx)
+	openBatchResp, err := f.openBatch(ctx, batchNum, ger)
 	if err != nil {
-		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
-			return nil, fmt.Errorf(
-				"failed to rollback dbTx: %s. Rollback err: %w",
-				rollbackErr.Error(), err,
-			)
-		}
 		return nil, err
 	}
-	if err := dbTx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit database transaction for opening a batch, err: %w", err)
-	}
 
-	// Check if synchronizer is up-to-date
-	for !f.isSynced(ctx) {
-		log.Info("wait for synchronizer to sync last batch")
-		time.Sleep(time.Second)
+	// Check if synchronizer is up-to-date, unless it was checked by the template of the batch
+	if !f.useNextBatchTemplate() {
+		for !f.isSynced(ctx) {
+			log.Info("wait for synchronizer to sync last batch")
+			time.Sleep(time.Second)
+		}
 	}
 
 	return &WipBatch{
 		batchNumber:        batchNum,
-		coinbase:           f.sequencerAddress,
+		coinbase:           f.l2Coinbase,
 		initialStateRoot:   stateRoot,
 		stateRoot:          stateRoot,
 		timestamp:          openBatchResp.Timestamp,
@@ -1174,18 +1507,23 @@ func (f *finalizer) closeBatch(ctx context.Context) error {
 		BatchResources:       usedResources,
 		ClosingReason:        f.batch.closingReason,
 	}
-	return f.dbManager.CloseBatch(ctx, receipt)
+	if err := f.dbManager.CloseBatch(ctx, receipt); err != nil {
+		return err
+	}
+	metrics.BatchClosed(string(f.batch.closingReason), len(transactions))
+	f.flushAfterBatch(ctx)
+	return nil
 }
 
 // openBatch opens a new batch in the state
-func (f *finalizer) openBatch(ctx context.Context, num uint64, ger common.Hash, dbTx pgx.Tx) (state.ProcessingContext, error) {
+func (f *finalizer) openBatch(ctx context.Context, num uint64, ger common.Hash) (state.ProcessingContext, error) {
 	processingCtx := state.ProcessingContext{
 		BatchNumber:    num,
-		Coinbase:       f.sequencerAddress,
+		Coinbase:       f.l2Coinbase,
 		Timestamp:      now(),
 		GlobalExitRoot: ger,
 	}
-	err := f.dbManager.OpenBatch(ctx, processingCtx, dbTx)
+	err := f.dbManager.OpenBatch(ctx, processingCtx)
 	if err != nil {
 		return state.ProcessingContext{}, fmt.Errorf("failed to open new batch, err: %w", err)
 	}
@@ -1308,6 +1646,7 @@ func (f *finalizer) isDeadlineEncountered() bool {
 	// Forced batch deadline
 	if f.nextForcedBatchDeadline != 0 && now().Unix() >= f.nextForcedBatchDeadline {
 		log.Infof("Closing batch: %d, forced batch deadline encountered.", f.batch.batchNumber)
+		f.batch.closingReason = state.ForcedBatchDeadlineClosingReason
 		return true
 	}
 	// Global Exit Root deadline
@@ -1316,12 +1655,31 @@ func (f *finalizer) isDeadlineEncountered() bool {
 		f.batch.closingReason = state.GlobalExitRootDeadlineClosingReason
 		return true
 	}
+	// Max open time deadline, applied even if the batch doesn't have the min txs
+	if f.cfg.BatchClosing.MaxOpenTime.Duration > 0 && !f.batch.isEmpty() && f.batch.timestamp.Add(f.cfg.BatchClosing.MaxOpenTime.Duration).Before(now()) {
+		log.Infof("Closing batch: %d, because of max open time.", f.batch.batchNumber)
+		f.batch.closingReason = state.MaxOpenTimeDeadlineClosingReason
+		return true
+	}
+	hasMinTxs := !f.batch.isEmpty() && uint64(f.batch.countOfTxs) >= f.cfg.BatchClosing.MinTxs
 	// Timestamp resolution deadline
-	if !f.batch.isEmpty() && f.batch.timestamp.Add(f.cfg.TimestampResolution.Duration).Before(time.Now()) {
+	if hasMinTxs && f.batch.timestamp.Add(f.cfg.TimestampResolution.Duration).Before(now()) {
 		log.Infof("Closing batch: %d, because of timestamp resolution.", f.batch.batchNumber)
 		f.batch.closingReason = state.TimeoutResolutionDeadlineClosingReason
 		return true
 	}
+	// Idle deadline, close the batch when no txs were added to it for too long
+	if f.cfg.BatchClosing.MaxIdleTime.Duration > 0 && hasMinTxs && f.batch.lastActivity().Add(f.cfg.BatchClosing.MaxIdleTime.Duration).Before(now()) {
+		log.Infof("Closing batch: %d, because of max idle time.", f.batch.batchNumber)
+		f.batch.closingReason = state.IdleDeadlineClosingReason
+		return true
+	}
+	// Keep alive deadline, close the empty batch to keep the batches flowing to L1 when there is no activity
+	if f.cfg.MaxTimeWithoutBatches.Duration > 0 && f.batch.isEmpty() && f.batch.timestamp.Add(f.cfg.MaxTimeWithoutBatches.Duration).Before(now()) {
+		log.Infof("Closing empty batch: %d, because of max time without batches.", f.batch.batchNumber)
+		f.batch.closingReason = state.KeepAliveDeadlineClosingReason
+		return true
+	}
 	return false
 }
 
@@ -1332,18 +1690,141 @@ func (f *finalizer) checkRemainingResources(result *state.ProcessBatchResponse,
 		Bytes:      uint64(len(tx.RawTx)),
 	}
 
+	remainingResources := f.batch.remainingResources
 	err := f.batch.remainingResources.Sub(usedResources)
-	if err != nil {
+	if err != nil && f.batch.isEmpty() {
+		// the tx doesn't fit even in an empty batch, so selecting it again would only keep the finalizer spinning on it
+		f.quarantineTx(tx, usedResources)
+		return ErrOutOfCountersAtBatchStart
+	} else if err != nil {
 		log.Infof("current transaction exceeds the batch limit, updating metadata for tx in worker and continuing")
 		start := time.Now()
 		f.worker.UpdateTxZKCounters(result.Responses[0].TxHash, tx.From, usedResources.ZKCounters)
 		metrics.WorkerProcessingTime(time.Since(start))
+		if err := f.dbManager.UpdateTxZKCounters(context.Background(), result.Responses[0].TxHash, usedResources.ZKCounters); err != nil {
+			log.Warnf("failed to store the zkcounters of tx %s in the pool, err: %v", result.Responses[0].TxHash.String(), err)
+		}
 		return err
 	}
 
+	// a tx is always included in an empty batch so it can't be kept out of every batch
+	if f.provingBudget != nil && !f.batch.isEmpty() {
+		batchResources := getUsedBatchResources(f.batchConstraints, f.batch.remainingResources)
+		if f.provingBudget.isExceeded(batchResources.ZKCounters) {
+			log.Infof("current transaction exceeds the batch proving budget, keeping it for the next batch")
+			f.batch.remainingResources = remainingResources
+			f.batch.provingBudgetReached = true
+			return ErrProvingBudgetExceeded
+		}
+	}
+
 	return nil
 }
 
+// quarantineTx deletes from the worker a tx that exceeds the counters of an empty batch, marking it as invalid
+// in the pool, and logs an event with its estimated and used counters compared to the batch constraints
+func (f *finalizer) quarantineTx(tx *TxTracker, usedResources state.BatchResources) {
+	log.Errorf("tx %s is out of counters at the start of batch %d, quarantining it", tx.HashStr, f.batch.batchNumber)
+	start := time.Now()
+	f.worker.DeleteTx(tx.Hash, tx.From)
+	metrics.WorkerProcessingTime(time.Since(start))
+
+	failedReason := ErrOutOfCountersAtBatchStart.Error()
+	if err := f.dbManager.UpdateTxStatus(context.Background(), tx.Hash, pool.TxStatusInvalid, false, &failedReason); err != nil {
+		log.Errorf("failed to update status to invalid in the pool for tx: %s, err: %s", tx.HashStr, err)
+	} else {
+		metrics.TxProcessed(metrics.TxProcessedLabelInvalid, 1)
+	}
+
+	description, err := json.Marshal(struct {
+		TxHash      string                    `json:"txHash"`
+		BatchNumber uint64                    `json:"batchNumber"`
+		Estimated   state.BatchResources      `json:"estimated"`
+		Used        state.BatchResources      `json:"used"`
+		Constraints state.BatchConstraintsCfg `json:"constraints"`
+	}{
+		TxHash:      tx.HashStr,
+		BatchNumber: f.batch.batchNumber,
+		Estimated:   tx.BatchResources,
+		Used:        usedResources,
+		Constraints: f.batchConstraints,
+	})
+	if err != nil {
+		log.Errorf("failed to encode the counters of the quarantined tx %s, err: %v", tx.HashStr, err)
+	}
+	event := &event.Event{
+		ReceivedAt:  time.Now(),
+		Source:      event.Source_Node,
+		Component:   event.Component_Sequencer,
+		Level:       event.Level_Warning,
+		EventID:     event.EventID_FinalizerOOCAtBatchStart,
+		Description: string(description),
+	}
+	if err := f.eventLog.LogEvent(context.Background(), event); err != nil {
+		log.Errorf("error storing OOC at batch start event: %v", err)
+	}
+}
+
+// isProvingBudgetReached checks if a tx didn't fit in the batch because of the proving budget
+func (f *finalizer) isProvingBudgetReached() bool {
+	if f.batch.provingBudgetReached {
+		log.Infof("Closing batch: %d, because it reached the proving budget", f.batch.batchNumber)
+		f.batch.closingReason = state.ProvingBudgetClosingReason
+		return true
+	}
+	return false
+}
+
+// isUtilizationTargetReached checks if a resource of the batch reached its target utilization and none
+// of the next txs to be selected fits in the remaining resources, so there is no need to wait for a deadline
+func (f *finalizer) isUtilizationTargetReached() bool {
+	if !f.cfg.UtilizationTargets.Enabled || f.batch.isEmpty() {
+		return false
+	}
+
+	resourceDesc := f.getResourceOverUtilizationTarget()
+	if resourceDesc == "" {
+		return false
+	}
+	if f.worker.HasFittingTx(f.batch.remainingResources, f.cfg.UtilizationTargets.LookAheadTxs) {
+		return false
+	}
+
+	log.Infof("Closing batch: %d, because it reached %s utilization target and no tx fits in the remaining resources", f.batch.batchNumber, resourceDesc)
+	f.batch.closingReason = state.UtilizationTargetClosingReason
+	return true
+}
+
+// getResourceOverUtilizationTarget returns the first resource of the batch whose utilization
+// reached its target, or an empty string if none reached it
+func (f *finalizer) getResourceOverUtilizationTarget() string {
+	targets := f.cfg.UtilizationTargets.Targets
+	used := getUsedBatchResources(f.batchConstraints, f.batch.remainingResources)
+	zkCounters := used.ZKCounters
+
+	resources := []struct {
+		desc       string
+		used, max  uint64
+		targetPerc uint32
+	}{
+		{"MaxBatchBytesSize", used.Bytes, f.batchConstraints.MaxBatchBytesSize, targets.BatchBytesSize},
+		{"MaxSteps", uint64(zkCounters.UsedSteps), uint64(f.batchConstraints.MaxSteps), targets.Steps},
+		{"MaxPoseidonPaddings", uint64(zkCounters.UsedPoseidonPaddings), uint64(f.batchConstraints.MaxPoseidonPaddings), targets.PoseidonPaddings},
+		{"MaxPoseidonHashes", uint64(zkCounters.UsedPoseidonHashes), uint64(f.batchConstraints.MaxPoseidonHashes), targets.PoseidonHashes},
+		{"MaxBinaries", uint64(zkCounters.UsedBinaries), uint64(f.batchConstraints.MaxBinaries), targets.Binaries},
+		{"MaxKeccakHashes", uint64(zkCounters.UsedKeccakHashes), uint64(f.batchConstraints.MaxKeccakHashes), targets.KeccakHashes},
+		{"MaxArithmetics", uint64(zkCounters.UsedArithmetics), uint64(f.batchConstraints.MaxArithmetics), targets.Arithmetics},
+		{"MaxMemAligns", uint64(zkCounters.UsedMemAligns), uint64(f.batchConstraints.MaxMemAligns), targets.MemAligns},
+		{"MaxCumulativeGasUsed", zkCounters.CumulativeGasUsed, f.batchConstraints.MaxCumulativeGasUsed, targets.CumulativeGasUsed},
+	}
+	for _, r := range resources {
+		if r.targetPerc > 0 && r.max > 0 && r.used*oneHundred >= r.max*uint64(r.targetPerc) {
+			return r.desc
+		}
+	}
+	return ""
+}
+
 // isBatchAlmostFull checks if the current batch remaining resources are under the Constraints threshold for most efficient moment to close a batch
 func (f *finalizer) isBatchAlmostFull() bool {
 	resources := f.batch.remainingResources
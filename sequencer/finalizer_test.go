@@ -15,6 +15,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/event/nileventstorage"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	stateMetrics "github.com/0xPolygonHermez/zkevm-node/state/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
@@ -137,7 +138,7 @@ func TestNewFinalizer(t *testing.T) {
 	assert.Equal(t, f.worker, workerMock)
 	assert.Equal(t, dbManagerMock, dbManagerMock)
 	assert.Equal(t, f.executor, executorMock)
-	assert.Equal(t, f.sequencerAddress, seqAddr)
+	assert.Equal(t, f.l2Coinbase, seqAddr)
 	assert.Equal(t, f.closingSignalCh, closingSignalCh)
 	assert.Equal(t, f.batchConstraints, bc)
 }
@@ -318,6 +319,7 @@ func TestFinalizer_handleProcessTransactionResponse(t *testing.T) {
 			}
 			if tc.expectedUpdateTxCall {
 				workerMock.On("UpdateTxZKCounters", txTracker.Hash, txTracker.From, tc.executorResponse.UsedZkCounters).Return().Once()
+				dbManagerMock.On("UpdateTxZKCounters", mock.Anything, txTracker.Hash, tc.executorResponse.UsedZkCounters).Return(nil).Once()
 			}
 			if tc.expectedError == nil {
 				//dbManagerMock.On("GetGasPrices", ctx).Return(pool.GasPrices{L1GasPrice: 0, L2GasPrice: 0}, nilErr).Once()
@@ -387,7 +389,7 @@ func TestFinalizer_newWIPBatch(t *testing.T) {
 	newBatchNum := f.batch.batchNumber + 1
 	expectedNewWipBatch := &WipBatch{
 		batchNumber:        newBatchNum,
-		coinbase:           f.sequencerAddress,
+		coinbase:           f.l2Coinbase,
 		initialStateRoot:   newHash,
 		stateRoot:          newHash,
 		timestamp:          now(),
@@ -554,13 +556,7 @@ func TestFinalizer_newWIPBatch(t *testing.T) {
 					dbManagerMock.On("ProcessForcedBatch", tc.forcedBatches[0].ForcedBatchNumber, processRequest).Return(tc.reprocessFullBatchResponse, nilErr).Once()
 				}
 				if tc.closeBatchErr == nil {
-					dbManagerMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nilErr).Once()
-					dbManagerMock.On("OpenBatch", ctx, mock.Anything, dbTxMock).Return(tc.openBatchErr).Once()
-					if tc.openBatchErr == nil {
-						dbTxMock.On("Commit", ctx).Return(nilErr).Once()
-					} else {
-						dbTxMock.On("Rollback", ctx).Return(nilErr).Once()
-					}
+					dbManagerMock.On("OpenBatch", ctx, mock.Anything).Return(tc.openBatchErr).Once()
 				}
 				executorMock.On("ProcessBatch", ctx, f.processRequest, false).Return(tc.reprocessFullBatchResponse, tc.reprocessBatchErr).Once()
 			}
@@ -632,7 +628,7 @@ func TestFinalizer_syncWithState(t *testing.T) {
 			batches:       batches,
 			expectedBatch: &WipBatch{
 				batchNumber:        one + 1,
-				coinbase:           f.sequencerAddress,
+				coinbase:           f.l2Coinbase,
 				initialStateRoot:   oldHash,
 				stateRoot:          oldHash,
 				timestamp:          testNow(),
@@ -641,7 +637,7 @@ func TestFinalizer_syncWithState(t *testing.T) {
 			},
 			expectedProcessingCtx: state.ProcessingContext{
 				BatchNumber:    one + 1,
-				Coinbase:       f.sequencerAddress,
+				Coinbase:       f.l2Coinbase,
 				Timestamp:      testNow(),
 				GlobalExitRoot: oldHash,
 			},
@@ -655,7 +651,7 @@ func TestFinalizer_syncWithState(t *testing.T) {
 			ger:           common.Hash{},
 			expectedBatch: &WipBatch{
 				batchNumber:        one,
-				coinbase:           f.sequencerAddress,
+				coinbase:           f.l2Coinbase,
 				initialStateRoot:   oldHash,
 				stateRoot:          oldHash,
 				timestamp:          testNow(),
@@ -664,7 +660,7 @@ func TestFinalizer_syncWithState(t *testing.T) {
 			},
 			expectedProcessingCtx: state.ProcessingContext{
 				BatchNumber:    one,
-				Coinbase:       f.sequencerAddress,
+				Coinbase:       f.l2Coinbase,
 				Timestamp:      testNow(),
 				GlobalExitRoot: oldHash,
 			},
@@ -705,7 +701,7 @@ func TestFinalizer_syncWithState(t *testing.T) {
 			openBatchErr:  testErr,
 			expectedProcessingCtx: state.ProcessingContext{
 				BatchNumber:    one + 1,
-				Coinbase:       f.sequencerAddress,
+				Coinbase:       f.l2Coinbase,
 				Timestamp:      testNow(),
 				GlobalExitRoot: oldHash,
 			},
@@ -719,7 +715,7 @@ func TestFinalizer_syncWithState(t *testing.T) {
 			ger:           oldHash,
 			expectedProcessingCtx: state.ProcessingContext{
 				BatchNumber:    one + 1,
-				Coinbase:       f.sequencerAddress,
+				Coinbase:       f.l2Coinbase,
 				Timestamp:      testNow(),
 				GlobalExitRoot: oldHash,
 			},
@@ -752,20 +748,10 @@ func TestFinalizer_syncWithState(t *testing.T) {
 				if tc.isBatchClosed {
 					if tc.getLastBatchErr == nil && tc.isBatchClosedErr == nil {
 						dbManagerMock.Mock.On("GetLatestGer", ctx, f.cfg.GERFinalityNumberOfBlocks).Return(state.GlobalExitRoot{GlobalExitRoot: tc.ger}, testNow(), tc.getLatestGERErr).Once()
-						if tc.getLatestGERErr == nil {
-							dbManagerMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nil).Once()
-							if tc.openBatchErr == nil {
-								dbTxMock.On("Commit", ctx).Return(nil).Once()
-							}
-						}
 					}
 
 					if tc.getLastBatchErr == nil && tc.isBatchClosedErr == nil && tc.getLatestGERErr == nil {
-						dbManagerMock.On("OpenBatch", ctx, tc.expectedProcessingCtx, dbTxMock).Return(tc.openBatchErr).Once()
-					}
-
-					if tc.expectedErr != nil && tc.openBatchErr != nil {
-						dbTxMock.On("Rollback", ctx).Return(nil).Once()
+						dbManagerMock.On("OpenBatch", ctx, tc.expectedProcessingCtx).Return(tc.openBatchErr).Once()
 					}
 				} else {
 					dbManagerMock.Mock.On("GetWIPBatch", ctx).Return(tc.expectedBatch, tc.getWIPBatchErr).Once()
@@ -788,6 +774,80 @@ func TestFinalizer_syncWithState(t *testing.T) {
 	}
 }
 
+func TestFinalizer_handleL2Reorg(t *testing.T) {
+	// arrange
+	f = setupFinalizer(true)
+	f.closingSignalCh = ClosingSignalCh{
+		ForcedBatchCh: make(chan state.ForcedBatch),
+		GERCh:         make(chan common.Hash),
+		L2ReorgCh:     make(chan L2ReorgEvent),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var reorgs []L2ReorgEvent
+	f.l2ReorgHandlers = []L2ReorgEventHandler{func(e L2ReorgEvent) {
+		reorgs = append(reorgs, e)
+	}}
+	f.nextBatchTemplate = &batchTemplate{}
+	lastBatch := &state.Batch{BatchNumber: 2, StateRoot: oldHash}
+	wipBatch := &WipBatch{batchNumber: 2, initialStateRoot: oldHash, stateRoot: oldHash}
+	txToStore := transactionToStore{hash: txHash2, from: senderAddr, response: &state.ProcessTransactionResponse{TxHash: txHash2}}
+	workerMock.On("AddPendingTxToStore", txHash2, senderAddr).Return().Once()
+	workerMock.On("DeletePendingTxToStore", txHash2, senderAddr).Return().Once()
+	dbManagerMock.On("HandleL2Reorg").Return([]common.Hash{txHash}).Once()
+	dbManagerMock.On("GetLastBatch", ctx).Return(lastBatch, nil).Once()
+	dbManagerMock.On("IsBatchClosed", ctx, lastBatch.BatchNumber).Return(false, nil).Once()
+	dbManagerMock.On("GetWIPBatch", ctx).Return(wipBatch, nil).Once()
+	var wg sync.WaitGroup
+	wg.Add(2) //nolint:gomnd
+	go func() {
+		defer wg.Done()
+		f.listenForClosingSignals(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		f.storePendingTransactions(ctx)
+	}()
+
+	// act
+	f.closingSignalCh.L2ReorgCh <- L2ReorgEvent{}
+	require.Eventually(t, f.handlingL2Reorg.Load, time.Second, time.Millisecond)
+	// the tx processed before the reorg is handled is dropped
+	f.addPendingTxToStore(ctx, txToStore)
+	f.handleL2Reorg(ctx)
+	cancel()
+	wg.Wait()
+
+	// assert
+	assert.False(t, f.handlingL2Reorg.Load())
+	assert.Equal(t, []L2ReorgEvent{{TxHashes: []common.Hash{txHash}}}, reorgs)
+	assert.Equal(t, wipBatch, f.batch)
+	assert.Equal(t, lastBatch.BatchNumber, f.processRequest.BatchNumber)
+	assert.Nil(t, f.nextBatchTemplate)
+	assert.False(t, f.halted.Load())
+	dbManagerMock.AssertExpectations(t)
+	dbManagerMock.AssertNotCalled(t, "StoreProcessedTx", mock.Anything, mock.Anything)
+	workerMock.AssertExpectations(t)
+}
+
+func TestFinalizer_storeProcessedTxDropsAfterL2ReorgDetected(t *testing.T) {
+	// arrange
+	f = setupFinalizer(false)
+	txToStore := transactionToStore{
+		hash:     txHash,
+		response: &state.ProcessTransactionResponse{TxHash: txHash},
+	}
+	dbManagerMock.On("StoreProcessedTx", ctx, txToStore).Return(ErrL2ReorgDetected).Once()
+
+	// act
+	f.storeProcessedTx(ctx, txToStore)
+
+	// assert
+	dbManagerMock.AssertExpectations(t)
+	dbManagerMock.AssertNotCalled(t, "UpdateTxStatus", ctx, txHash, pool.TxStatusSelected, false, (*string)(nil))
+	assert.False(t, f.halted.Load())
+}
+
 func TestFinalizer_processForcedBatches(t *testing.T) {
 	var err error
 	f = setupFinalizer(false)
@@ -976,7 +1036,7 @@ func TestFinalizer_processForcedBatches(t *testing.T) {
 						OldStateRoot:   stateRootHashes[i],
 						GlobalExitRoot: forcedBatch.GlobalExitRoot,
 						Transactions:   forcedBatch.RawTxsData,
-						Coinbase:       f.sequencerAddress,
+						Coinbase:       f.l2Coinbase,
 						Timestamp:      now(),
 						Caller:         stateMetrics.SequencerCallerLabel,
 					}
@@ -1042,7 +1102,7 @@ func TestFinalizer_openWIPBatch(t *testing.T) {
 	batchNum := f.batch.batchNumber + 1
 	expectedWipBatch := &WipBatch{
 		batchNumber:        batchNum,
-		coinbase:           f.sequencerAddress,
+		coinbase:           f.l2Coinbase,
 		initialStateRoot:   oldHash,
 		stateRoot:          oldHash,
 		timestamp:          now(),
@@ -1052,9 +1112,6 @@ func TestFinalizer_openWIPBatch(t *testing.T) {
 	testCases := []struct {
 		name         string
 		openBatchErr error
-		beginTxErr   error
-		commitErr    error
-		rollbackErr  error
 		expectedWip  *WipBatch
 		expectedErr  error
 	}{
@@ -1062,47 +1119,17 @@ func TestFinalizer_openWIPBatch(t *testing.T) {
 			name:        "Success",
 			expectedWip: expectedWipBatch,
 		},
-		{
-			name:        "Error BeginTransaction",
-			beginTxErr:  testErr,
-			expectedErr: fmt.Errorf("failed to begin state transaction to open batch, err: %w", testErr),
-		},
 		{
 			name:         "Error OpenBatch",
 			openBatchErr: testErr,
 			expectedErr:  fmt.Errorf("failed to open new batch, err: %w", testErr),
 		},
-		{
-			name:        "Error Commit",
-			commitErr:   testErr,
-			expectedErr: fmt.Errorf("failed to commit database transaction for opening a batch, err: %w", testErr),
-		},
-		{
-			name:         "Error Rollback",
-			openBatchErr: testErr,
-			rollbackErr:  testErr,
-			expectedErr: fmt.Errorf(
-				"failed to rollback dbTx: %s. Rollback err: %w",
-				testErr.Error(), openBatchError,
-			),
-		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// arrange
-			dbManagerMock.On("BeginStateTransaction", ctx).Return(dbTxMock, tc.beginTxErr).Once()
-			if tc.beginTxErr == nil {
-				dbManagerMock.On("OpenBatch", ctx, mock.Anything, dbTxMock).Return(tc.openBatchErr).Once()
-			}
-
-			if tc.expectedErr != nil && (tc.rollbackErr != nil || tc.openBatchErr != nil) {
-				dbTxMock.On("Rollback", ctx).Return(tc.rollbackErr).Once()
-			}
-
-			if tc.expectedErr == nil || tc.commitErr != nil {
-				dbTxMock.On("Commit", ctx).Return(tc.commitErr).Once()
-			}
+			dbManagerMock.On("OpenBatch", ctx, mock.Anything).Return(tc.openBatchErr).Once()
 
 			// act
 			wipBatch, err := f.openWIPBatch(ctx, batchNum, oldHash, oldHash)
@@ -1117,7 +1144,6 @@ func TestFinalizer_openWIPBatch(t *testing.T) {
 				assert.Equal(t, tc.expectedWip, wipBatch)
 			}
 			dbManagerMock.AssertExpectations(t)
-			dbTxMock.AssertExpectations(t)
 		})
 	}
 }
@@ -1197,7 +1223,7 @@ func TestFinalizer_openBatch(t *testing.T) {
 			managerErr: nil,
 			expectedCtx: state.ProcessingContext{
 				BatchNumber:    batchNum,
-				Coinbase:       f.sequencerAddress,
+				Coinbase:       f.l2Coinbase,
 				Timestamp:      now(),
 				GlobalExitRoot: oldHash,
 			},
@@ -1215,10 +1241,10 @@ func TestFinalizer_openBatch(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// arrange
-			dbManagerMock.Mock.On("OpenBatch", mock.Anything, mock.Anything, mock.Anything).Return(tc.managerErr).Once()
+			dbManagerMock.Mock.On("OpenBatch", mock.Anything, mock.Anything).Return(tc.managerErr).Once()
 
 			// act
-			actualCtx, err := f.openBatch(ctx, tc.batchNum, oldHash, nil)
+			actualCtx, err := f.openBatch(ctx, tc.batchNum, oldHash)
 
 			// assert
 			if tc.expectedErr != nil {
@@ -1249,6 +1275,7 @@ func TestFinalizer_isDeadlineEncountered(t *testing.T) {
 		nextDelayedBatch            int64
 		expected                    bool
 		timestampResolutionDeadline bool
+		keepAliveDeadline           bool
 	}{
 		{
 			name:     "No deadlines",
@@ -1274,6 +1301,11 @@ func TestFinalizer_isDeadlineEncountered(t *testing.T) {
 			timestampResolutionDeadline: true,
 			expected:                    true,
 		},
+		{
+			name:              "Keep alive deadline",
+			keepAliveDeadline: true,
+			expected:          true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1290,10 +1322,21 @@ func TestFinalizer_isDeadlineEncountered(t *testing.T) {
 			// specifically for "Timestamp resolution deadline" test case
 			if tc.timestampResolutionDeadline == true {
 				// ensure that the batch is not empty and the timestamp is in the past
+				f.cfg.TimestampResolution = cfgTypes.NewDuration(time.Second)
+				defer func() { f.cfg.TimestampResolution = cfgTypes.NewDuration(0) }()
 				f.batch.timestamp = now().Add(-f.cfg.TimestampResolution.Duration * 2)
 				f.batch.countOfTxs = 1
 			}
 
+			// specifically for "Keep alive deadline" test case
+			if tc.keepAliveDeadline == true {
+				// ensure that the batch is empty and it has been open for longer than the max time without batches
+				f.cfg.MaxTimeWithoutBatches = cfgTypes.NewDuration(time.Minute)
+				defer func() { f.cfg.MaxTimeWithoutBatches = cfgTypes.NewDuration(0) }()
+				f.batch.timestamp = now().Add(-f.cfg.MaxTimeWithoutBatches.Duration * 2)
+				f.batch.countOfTxs = 0
+			}
+
 			// act
 			actual := f.isDeadlineEncountered()
 
@@ -1303,6 +1346,109 @@ func TestFinalizer_isDeadlineEncountered(t *testing.T) {
 	}
 }
 
+func TestFinalizer_isDeadlineEncounteredBatchClosing(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		batchClosing          BatchClosingCfg
+		timestampResolution   time.Duration
+		openFor               time.Duration
+		idleFor               time.Duration
+		countOfTxs            int
+		expected              bool
+		expectedClosingReason state.ClosingReason
+	}{
+		{
+			name:                  "Max open time deadline applied regardless of the min txs",
+			batchClosing:          BatchClosingCfg{MaxOpenTime: cfgTypes.NewDuration(time.Minute), MinTxs: 10},
+			timestampResolution:   time.Hour,
+			openFor:               2 * time.Minute,
+			idleFor:               time.Second,
+			countOfTxs:            1,
+			expected:              true,
+			expectedClosingReason: state.MaxOpenTimeDeadlineClosingReason,
+		},
+		{
+			name:                  "Idle deadline",
+			batchClosing:          BatchClosingCfg{MaxIdleTime: cfgTypes.NewDuration(10 * time.Second), MinTxs: 2},
+			timestampResolution:   time.Hour,
+			openFor:               30 * time.Second,
+			idleFor:               20 * time.Second,
+			countOfTxs:            2,
+			expected:              true,
+			expectedClosingReason: state.IdleDeadlineClosingReason,
+		},
+		{
+			name:                "Idle deadline not reached",
+			batchClosing:        BatchClosingCfg{MaxIdleTime: cfgTypes.NewDuration(10 * time.Second)},
+			timestampResolution: time.Hour,
+			openFor:             30 * time.Second,
+			idleFor:             5 * time.Second,
+			countOfTxs:          2,
+			expected:            false,
+		},
+		{
+			name:                "Idle deadline without the min txs",
+			batchClosing:        BatchClosingCfg{MaxIdleTime: cfgTypes.NewDuration(10 * time.Second), MinTxs: 2},
+			timestampResolution: time.Hour,
+			openFor:             30 * time.Second,
+			idleFor:             20 * time.Second,
+			countOfTxs:          1,
+			expected:            false,
+		},
+		{
+			name:                "Timestamp resolution deadline without the min txs",
+			batchClosing:        BatchClosingCfg{MinTxs: 2},
+			timestampResolution: 10 * time.Second,
+			openFor:             30 * time.Second,
+			idleFor:             20 * time.Second,
+			countOfTxs:          1,
+			expected:            false,
+		},
+		{
+			name:                  "Timestamp resolution deadline",
+			batchClosing:          BatchClosingCfg{MinTxs: 2},
+			timestampResolution:   10 * time.Second,
+			openFor:               10*time.Second + time.Millisecond,
+			idleFor:               time.Second,
+			countOfTxs:            2,
+			expected:              true,
+			expectedClosingReason: state.TimeoutResolutionDeadlineClosingReason,
+		},
+		{
+			name:                "Timestamp resolution deadline just reached",
+			batchClosing:        BatchClosingCfg{MinTxs: 2},
+			timestampResolution: 10 * time.Second,
+			openFor:             10 * time.Second,
+			idleFor:             time.Second,
+			countOfTxs:          2,
+			expected:            false,
+		},
+	}
+
+	now = testNow
+	defer func() {
+		now = time.Now
+	}()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			f = setupFinalizer(true)
+			f.cfg.BatchClosing = tc.batchClosing
+			f.cfg.TimestampResolution = cfgTypes.NewDuration(tc.timestampResolution)
+			f.batch.timestamp = now().Add(-tc.openFor)
+			f.batch.lastTxTimestamp = now().Add(-tc.idleFor)
+			f.batch.countOfTxs = tc.countOfTxs
+
+			// act
+			actual := f.isDeadlineEncountered()
+
+			// assert
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.expectedClosingReason, f.batch.closingReason)
+		})
+	}
+}
+
 func TestFinalizer_checkRemainingResources(t *testing.T) {
 	// arrange
 	f = setupFinalizer(true)
@@ -1317,6 +1463,7 @@ func TestFinalizer_checkRemainingResources(t *testing.T) {
 		Bytes:      10000,
 	}
 	f.batch.remainingResources = remainingResources
+	f.batch.countOfTxs = 1
 	testCases := []struct {
 		name                 string
 		remaining            state.BatchResources
@@ -1358,6 +1505,7 @@ func TestFinalizer_checkRemainingResources(t *testing.T) {
 			dbManagerMock.On("AddEvent", ctx, mock.Anything, nil).Return(nil)
 			if tc.expectedWorkerUpdate {
 				workerMock.On("UpdateTxZKCounters", txResponse.TxHash, tc.expectedTxTracker.From, result.UsedZkCounters).Return().Once()
+				dbManagerMock.On("UpdateTxZKCounters", mock.Anything, txResponse.TxHash, result.UsedZkCounters).Return(nil).Once()
 			}
 
 			// act
@@ -1379,6 +1527,30 @@ func TestFinalizer_checkRemainingResources(t *testing.T) {
 	}
 }
 
+func TestFinalizer_checkRemainingResourcesAtBatchStart(t *testing.T) {
+	// arrange
+	f = setupFinalizer(true)
+	ctx = context.Background()
+	tx := &TxTracker{Hash: oldHash, HashStr: oldHash.String(), From: senderAddr, RawTx: []byte("test")}
+	result := &state.ProcessBatchResponse{
+		UsedZkCounters: state.ZKCounters{UsedKeccakHashes: bc.MaxKeccakHashes + 1},
+		Responses:      []*state.ProcessTransactionResponse{{TxHash: oldHash}},
+	}
+	remainingResources := f.batch.remainingResources
+	workerMock.On("DeleteTx", tx.Hash, tx.From).Return().Once()
+	dbManagerMock.On("UpdateTxStatus", mock.Anything, tx.Hash, pool.TxStatusInvalid, false, mock.Anything).Return(nil).Once()
+
+	// act
+	err := f.checkRemainingResources(result, tx)
+
+	// assert
+	assert.ErrorIs(t, err, ErrOutOfCountersAtBatchStart)
+	assert.Equal(t, remainingResources, f.batch.remainingResources)
+	workerMock.AssertExpectations(t)
+	dbManagerMock.AssertExpectations(t)
+	workerMock.AssertNotCalled(t, "UpdateTxZKCounters", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestFinalizer_handleTransactionError(t *testing.T) {
 	// arrange
 	f = setupFinalizer(true)
@@ -1523,6 +1695,8 @@ func Test_processTransaction(t *testing.T) {
 		expectedErr            error
 		expectedStoredTx       transactionToStore
 		expectedUpdateTxStatus pool.TxStatus
+		expectedRetryTxCall    bool
+		retryTxScheduled       bool
 	}{
 		{
 			name:             "Successful transaction processing",
@@ -1558,6 +1732,24 @@ func Test_processTransaction(t *testing.T) {
 			expectedErr:            runtime.ErrOutOfCountersKeccak,
 			expectedUpdateTxStatus: pool.TxStatusInvalid,
 		},
+		{
+			name:                "Transient executor err retried later",
+			ctx:                 context.Background(),
+			tx:                  txTracker,
+			executorErr:         testErr,
+			expectedErr:         testErr,
+			expectedRetryTxCall: true,
+			retryTxScheduled:    true,
+		},
+		{
+			name:                   "Transient executor err without retries left",
+			ctx:                    context.Background(),
+			tx:                     txTracker,
+			executorErr:            executor.ErrExecutorUnspecified,
+			expectedErr:            executor.ErrExecutorUnspecified,
+			expectedRetryTxCall:    true,
+			expectedUpdateTxStatus: pool.TxStatusFailed,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1594,6 +1786,13 @@ func Test_processTransaction(t *testing.T) {
 				workerMock.On("DeleteTx", tc.tx.Hash, tc.tx.From).Return().Once()
 			}
 
+			if tc.expectedRetryTxCall {
+				workerMock.On("RetryTxLater", tc.tx.Hash, tc.tx.From).Return(tc.retryTxScheduled).Once()
+				if !tc.retryTxScheduled {
+					workerMock.On("DeleteTx", tc.tx.Hash, tc.tx.From).Return().Once()
+				}
+			}
+
 			errWg, err := f.processTransaction(tc.ctx, tc.tx, true)
 
 			if tc.expectedStoredTx.batchResponse != nil {
@@ -1617,6 +1816,89 @@ func Test_processTransaction(t *testing.T) {
 	}
 }
 
+func TestFinalizer_checkTxConditions(t *testing.T) {
+	address := common.HexToAddress("0x1")
+	slot := common.HexToHash("0x1")
+	header := &types.Header{Number: big.NewInt(10)}
+	testCases := []struct {
+		name             string
+		headerErr        error
+		storageValue     *big.Int
+		storageErr       error
+		retryTxScheduled bool
+		expectedResult   bool
+		expectedRetry    bool
+		expectedDropped  bool
+	}{
+		{
+			name:           "conditions met",
+			storageValue:   big.NewInt(2),
+			expectedResult: true,
+		},
+		{
+			name:            "conditions not met",
+			storageValue:    big.NewInt(3),
+			expectedDropped: true,
+		},
+		{
+			name:             "header read error",
+			headerErr:        testErr,
+			retryTxScheduled: true,
+			expectedRetry:    true,
+		},
+		{
+			name:             "storage read error",
+			storageErr:       testErr,
+			retryTxScheduled: true,
+			expectedRetry:    true,
+		},
+		{
+			name:            "storage read error without retries left",
+			storageErr:      testErr,
+			expectedRetry:   true,
+			expectedDropped: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			f = setupFinalizer(true)
+			ctx := context.Background()
+			dbManager, stateMock, worker := new(DbManagerMock), new(StateMock), new(WorkerMock)
+			f.dbManager, f.executor, f.worker = dbManager, stateMock, worker
+			tx := &TxTracker{
+				Hash:    txHash,
+				HashStr: txHash.String(),
+				From:    senderAddr,
+				Conditions: &pool.TxConditions{KnownAccounts: map[common.Address]pool.KnownAccount{
+					address: {StorageSlots: map[common.Hash]common.Hash{slot: common.HexToHash("0x2")}},
+				}},
+			}
+			dbManager.On("GetLastL2BlockHeader", ctx, nil).Return(header, tc.headerErr).Once()
+			if tc.headerErr == nil {
+				stateMock.On("GetStorageAt", ctx, address, slot.Big(), f.batch.stateRoot).Return(tc.storageValue, tc.storageErr).Once()
+			}
+			if tc.expectedRetry {
+				worker.On("RetryTxLater", tx.Hash, tx.From).Return(tc.retryTxScheduled).Once()
+			}
+			if tc.expectedDropped {
+				worker.On("DeleteTx", tx.Hash, tx.From).Return().Once()
+				dbManager.On("UpdateTxStatus", ctx, tx.Hash, pool.TxStatusFailed, false, mock.Anything).Return(nil).Once()
+			}
+
+			// act
+			result := f.checkTxConditions(ctx, tx)
+
+			// assert
+			assert.Equal(t, tc.expectedResult, result)
+			dbManager.AssertExpectations(t)
+			stateMock.AssertExpectations(t)
+			worker.AssertExpectations(t)
+		})
+	}
+}
+
 func Test_handleForcedTxsProcessResp(t *testing.T) {
 	var chainID = new(big.Int).SetInt64(400)
 	var pvtKey = "0x28b2b0318721be8c8339199172cd7cc8f5e273800a35616ec893083a4b32c02e"
@@ -1874,7 +2156,8 @@ func TestFinalizer_storeProcessedTx(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// arrange
-			dbManagerMock.On("StoreProcessedTxAndDeleteFromPool", ctx, tc.expectedTxToStore).Return(nilErr)
+			dbManagerMock.On("StoreProcessedTx", ctx, tc.expectedTxToStore).Return(nilErr)
+			dbManagerMock.On("UpdateTxStatus", ctx, tc.expectedTxToStore.response.TxHash, pool.TxStatusSelected, false, (*string)(nil)).Return(nilErr)
 
 			// act
 			f.storeProcessedTx(ctx, tc.expectedTxToStore)
@@ -1885,6 +2168,28 @@ func TestFinalizer_storeProcessedTx(t *testing.T) {
 	}
 }
 
+func TestFinalizer_storeProcessedTxRetriesOnlyThePoolUpdate(t *testing.T) {
+	// arrange
+	f = setupFinalizer(false)
+	ctx := context.Background()
+	f.cfg.HaltPolicy = HaltPolicyCfg{Mode: HaltPolicyModeRetry, Retry: retry.Constant(4, time.Millisecond)}
+	txToStore := transactionToStore{
+		batchNumber: 1,
+		coinbase:    seqAddr,
+		response:    &state.ProcessTransactionResponse{TxHash: txHash},
+	}
+	dbManagerMock.On("StoreProcessedTx", ctx, txToStore).Return(nilErr).Once()
+	dbManagerMock.On("UpdateTxStatus", ctx, txHash, pool.TxStatusSelected, false, (*string)(nil)).Return(testErr).Once()
+	dbManagerMock.On("UpdateTxStatus", ctx, txHash, pool.TxStatusSelected, false, (*string)(nil)).Return(nilErr).Once()
+
+	// act
+	f.storeProcessedTx(ctx, txToStore)
+
+	// assert
+	dbManagerMock.AssertExpectations(t)
+	dbManagerMock.AssertNumberOfCalls(t, "StoreProcessedTx", 1)
+}
+
 func TestFinalizer_updateWorkerAfterSuccessfulProcessing(t *testing.T) {
 	testCases := []struct {
 		name                  string
@@ -2362,6 +2667,43 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 	}
 }
 
+func TestFinalizer_isUtilizationTargetReached(t *testing.T) {
+	testCases := []struct {
+		name           string
+		usedSteps      uint32
+		hasFittingTx   bool
+		expectedResult bool
+	}{
+		{name: "Target not reached", usedSteps: bc.MaxSteps*95/100 - 1, expectedResult: false},
+		{name: "Target reached and a tx fits", usedSteps: bc.MaxSteps*95/100 + 1, hasFittingTx: true, expectedResult: false},
+		{name: "Target reached and no tx fits", usedSteps: bc.MaxSteps*95/100 + 1, hasFittingTx: false, expectedResult: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			f = setupFinalizer(true)
+			f.cfg.UtilizationTargets = UtilizationTargetsCfg{Enabled: true, LookAheadTxs: 10, Targets: ResourceUtilizationTargets{Steps: 95}}
+			f.batch.countOfTxs = 1
+			f.batch.remainingResources = getMaxRemainingResources(bc)
+			f.batch.remainingResources.ZKCounters.UsedSteps = bc.MaxSteps - tc.usedSteps
+			if tc.usedSteps > bc.MaxSteps*95/100 {
+				workerMock.On("HasFittingTx", f.batch.remainingResources, uint64(10)).Return(tc.hasFittingTx).Once()
+			}
+
+			// act
+			result := f.isUtilizationTargetReached()
+
+			// assert
+			assert.Equal(t, tc.expectedResult, result)
+			if tc.expectedResult {
+				assert.Equal(t, state.UtilizationTargetClosingReason, f.batch.closingReason)
+			}
+			workerMock.AssertExpectations(t)
+		})
+	}
+}
+
 func TestFinalizer_setNextForcedBatchDeadline(t *testing.T) {
 	// arrange
 	f = setupFinalizer(false)
@@ -2505,6 +2847,137 @@ func Test_sortForcedBatches(t *testing.T) {
 	}
 }
 
+func TestFinalizer_runCriticalOperation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		haltPolicy    HaltPolicyCfg
+		failures      int
+		expectedCalls int
+		expectHalt    bool
+	}{
+		{
+			name:          "success without failures",
+			haltPolicy:    HaltPolicyCfg{Mode: HaltPolicyModeHalt},
+			failures:      0,
+			expectedCalls: 1,
+		},
+		{
+			name:          "retry until success",
+			haltPolicy:    HaltPolicyCfg{Mode: HaltPolicyModeRetry, Retry: retry.Constant(4, time.Millisecond)},
+			failures:      2,
+			expectedCalls: 3,
+		},
+		{
+			name:          "retries exhausted halts until resumed",
+			haltPolicy:    HaltPolicyCfg{Mode: HaltPolicyModeRetry, Retry: retry.Constant(2, time.Millisecond)},
+			failures:      2,
+			expectedCalls: 3,
+			expectHalt:    true,
+		},
+		{
+			name:          "halt until resumed",
+			haltPolicy:    HaltPolicyCfg{Mode: HaltPolicyModeHaltAndAlert},
+			failures:      1,
+			expectedCalls: 2,
+			expectHalt:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			f = setupFinalizer(false)
+			f.cfg.HaltPolicy = tc.haltPolicy
+			calls := 0
+			operation := func() error {
+				calls++
+				if calls <= tc.failures {
+					return errors.New("operation failed")
+				}
+				return nil
+			}
+			if tc.expectHalt {
+				go func() {
+					// retry until the finalizer is halted and waiting to be resumed
+					for f.resume() != nil {
+						time.Sleep(time.Millisecond)
+					}
+				}()
+			}
+
+			// act
+			err := f.runCriticalOperation(context.Background(), operation)
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCalls, calls)
+			assert.False(t, f.halted.Load())
+			assert.ErrorIs(t, f.resume(), ErrFinalizerNotHalted)
+		})
+	}
+}
+
+func TestFinalizer_processTransactionAppliesHaltPolicyToExecutorDBErrors(t *testing.T) {
+	// arrange
+	f = setupFinalizer(true)
+	f.cfg.HaltPolicy = HaltPolicyCfg{Mode: HaltPolicyModeRetry, Retry: retry.Constant(2, time.Millisecond)}
+	ctx := context.Background()
+	executorMock.On("ProcessBatch", ctx, mock.Anything, true).Return(nil, runtime.ErrExecutorDBError).Once()
+	executorMock.On("ProcessBatch", ctx, mock.Anything, true).Return(&state.ProcessBatchResponse{NewStateRoot: oldHash}, nil).Once()
+
+	// act
+	_, err := f.processTransaction(ctx, nil, true)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, oldHash, f.batch.stateRoot)
+	assert.False(t, f.halted.Load())
+	executorMock.AssertExpectations(t)
+}
+
+func TestFinalizer_haltUntilResumed(t *testing.T) {
+	// arrange
+	f = setupFinalizer(false)
+	f.cfg.HaltPolicy = HaltPolicyCfg{Mode: HaltPolicyModeHaltAndAlert}
+	done := make(chan struct{})
+
+	// act
+	go func() {
+		f.haltUntilResumed(context.Background(), errors.New("failed to store processed tx"))
+		close(done)
+	}()
+
+	// assert
+	require.Eventually(t, f.halted.Load, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return f.resume() == nil }, time.Second, time.Millisecond)
+	<-done
+	assert.False(t, f.halted.Load())
+	assert.ErrorIs(t, f.resume(), ErrFinalizerNotHalted)
+}
+
+func TestFinalizer_haltNotResumable(t *testing.T) {
+	// arrange
+	f = setupFinalizer(false)
+	f.cfg.HaltPolicy = HaltPolicyCfg{Mode: HaltPolicyModeHalt}
+	done := make(chan struct{})
+
+	// act
+	go func() {
+		f.halt(context.Background(), errors.New("halting Sequencer because of error reprocessing full batch (sanity check)"))
+		close(done)
+	}()
+
+	// assert
+	require.Eventually(t, f.halted.Load, time.Second, time.Millisecond)
+	assert.ErrorIs(t, f.resume(), ErrFinalizerHaltNotResumable)
+	assert.True(t, f.halted.Load())
+
+	f.stopOnce.Do(func() { close(f.stopCh) })
+	<-done
+	assert.True(t, f.halted.Load())
+	assert.ErrorIs(t, f.resume(), ErrFinalizerHaltNotResumable)
+}
+
 func setupFinalizer(withWipBatch bool) *finalizer {
 	wipBatch := new(WipBatch)
 	dbManagerMock = new(DbManagerMock)
@@ -2537,7 +3010,7 @@ func setupFinalizer(withWipBatch bool) *finalizer {
 		cfg:                cfg,
 		closingSignalCh:    closingSignalCh,
 		isSynced:           isSynced,
-		sequencerAddress:   seqAddr,
+		l2Coinbase:         seqAddr,
 		worker:             workerMock,
 		dbManager:          dbManagerMock,
 		executor:           executorMock,
@@ -2553,7 +3026,6 @@ func setupFinalizer(withWipBatch bool) *finalizer {
 		nextForcedBatches:            make([]state.ForcedBatch, 0),
 		nextForcedBatchDeadline:      0,
 		nextForcedBatchesMux:         new(sync.RWMutex),
-		handlingL2Reorg:              false,
 		effectiveGasPrice:            pool.NewEffectiveGasPrice(poolCfg.EffectiveGasPrice, poolCfg.DefaultMinGasPriceAllowed),
 		eventLog:                     eventLog,
 		pendingTransactionsToStore:   make(chan transactionToStore, bc.MaxTxsPerBatch*pendingTxsBufferSizeMultiplier),
@@ -2563,5 +3035,8 @@ func setupFinalizer(withWipBatch bool) *finalizer {
 		proverID:                     "",
 		lastPendingFlushID:           0,
 		pendingFlushIDCond:           sync.NewCond(new(sync.Mutex)),
+		resumeCh:                     make(chan struct{}, 1),
+		stopCh:                       make(chan struct{}),
+		stoppedCh:                    make(chan struct{}),
 	}
 }
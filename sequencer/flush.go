@@ -0,0 +1,57 @@
+package sequencer
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// flushAfterTx flushes the state writes to the merkle tree backend after a processed tx,
+// when the flush strategy is "tx" or it's "txs" and the txs interval has been reached
+func (f *finalizer) flushAfterTx(ctx context.Context) {
+	switch f.cfg.Flush.Strategy {
+	case FlushStrategyTx:
+		f.flushStateWrites(ctx)
+	case FlushStrategyTxs:
+		f.txsSinceFlush++
+		if f.txsSinceFlush >= f.cfg.Flush.TxsInterval {
+			f.flushStateWrites(ctx)
+		}
+	}
+}
+
+// flushAfterBatch flushes the state writes to the merkle tree backend after the batch is closed,
+// when the flush strategy is "batch" or it's "txs" and there are txs not flushed yet
+func (f *finalizer) flushAfterBatch(ctx context.Context) {
+	switch f.cfg.Flush.Strategy {
+	case FlushStrategyBatch:
+		f.flushStateWrites(ctx)
+	case FlushStrategyTxs:
+		if f.txsSinceFlush > 0 {
+			f.flushStateWrites(ctx)
+		}
+	}
+}
+
+// flushStateWrites flushes the state writes to the merkle tree backend. If it fails the
+// writes are flushed later by the executor or by the next flush of the strategy
+func (f *finalizer) flushStateWrites(ctx context.Context) {
+	if err := f.dbManager.FlushMerkleTree(ctx); err != nil {
+		log.Errorf("failed to flush the state writes to the merkle tree, Err: %v", err)
+		return
+	}
+	f.txsSinceFlush = 0
+}
+
+// reconcileFlushedState flushes the state writes left pending by a previous run and checks the state root
+// of the wip batch is in the merkle tree. If it isn't, the state writes were lost before being flushed and
+// the sequencer can't continue until the tree is restored
+func (f *finalizer) reconcileFlushedState(ctx context.Context) {
+	if err := f.dbManager.FlushMerkleTree(ctx); err != nil {
+		log.Fatalf("failed to flush the pending state writes to the merkle tree, Err: %v", err)
+	}
+	if _, err := f.dbManager.GetBalanceByStateRoot(ctx, f.batch.coinbase, f.batch.stateRoot); err != nil {
+		log.Fatalf("state root %s of the wip batch %d is not in the merkle tree, the state writes were lost before being flushed, Err: %v",
+			f.batch.stateRoot.String(), f.batch.batchNumber, err)
+	}
+}
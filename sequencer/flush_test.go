@@ -0,0 +1,60 @@
+package sequencer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinalizerFlushStrategies(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name            string
+		cfg             FlushCfg
+		txs             int
+		expectedFlushes int
+	}{
+		{name: "executor", cfg: FlushCfg{Strategy: FlushStrategyExecutor}, txs: 5, expectedFlushes: 0},
+		{name: "tx", cfg: FlushCfg{Strategy: FlushStrategyTx}, txs: 5, expectedFlushes: 5},
+		{name: "txs", cfg: FlushCfg{Strategy: FlushStrategyTxs, TxsInterval: 2}, txs: 5, expectedFlushes: 3},
+		{name: "txs without pending writes at batch close", cfg: FlushCfg{Strategy: FlushStrategyTxs, TxsInterval: 5}, txs: 5, expectedFlushes: 1},
+		{name: "batch", cfg: FlushCfg{Strategy: FlushStrategyBatch}, txs: 5, expectedFlushes: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbManagerMock := NewDbManagerMock(t)
+			f := &finalizer{cfg: FinalizerCfg{Flush: tc.cfg}, dbManager: dbManagerMock}
+			if tc.expectedFlushes > 0 {
+				dbManagerMock.On("FlushMerkleTree", ctx).Return(nil).Times(tc.expectedFlushes)
+			}
+
+			for i := 0; i < tc.txs; i++ {
+				f.flushAfterTx(ctx)
+			}
+			f.flushAfterBatch(ctx)
+
+			dbManagerMock.AssertNumberOfCalls(t, "FlushMerkleTree", tc.expectedFlushes)
+			assert.Equal(t, uint64(0), f.txsSinceFlush)
+		})
+	}
+}
+
+func TestFinalizerFlushFailureKeepsPendingTxs(t *testing.T) {
+	ctx := context.Background()
+	dbManagerMock := NewDbManagerMock(t)
+	f := &finalizer{cfg: FinalizerCfg{Flush: FlushCfg{Strategy: FlushStrategyTxs, TxsInterval: 2}}, dbManager: dbManagerMock}
+
+	dbManagerMock.On("FlushMerkleTree", ctx).Return(errors.New("unavailable")).Once()
+	f.flushAfterTx(ctx)
+	f.flushAfterTx(ctx)
+	assert.Equal(t, uint64(2), f.txsSinceFlush)
+
+	// the next tx retries the flush
+	dbManagerMock.On("FlushMerkleTree", ctx).Return(nil).Once()
+	f.flushAfterTx(ctx)
+	assert.Equal(t, uint64(0), f.txsSinceFlush)
+}
@@ -0,0 +1,69 @@
+package sequencer
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// L2ReorgEventHandler handles the txs reorged from the trusted state, so the components caching
+// data of the txs (e.g. the receipts) can invalidate it. It's called from the finalizer loop so it
+// must not block
+type L2ReorgEventHandler func(e L2ReorgEvent)
+
+// RegisterL2ReorgEventHandler adds a handler of the txs reorged from the trusted state, the
+// handlers must be registered before the sequencer is started
+func (s *Sequencer) RegisterL2ReorgEventHandler(h L2ReorgEventHandler) {
+	s.l2ReorgHandlers = append(s.l2ReorgHandlers, h)
+}
+
+// handleL2Reorg resumes the sequencing after an L2 reorg. It's called from the finalizeBatches loop, so no tx
+// is processed meanwhile and the WIP batch is only updated by it. The txs processed before the reorg are
+// dropped instead of stored, then the worker is refreshed with the reorged state and the WIP batch is
+// loaded again from the state as done on start
+func (f *finalizer) handleL2Reorg(ctx context.Context) {
+	// the pending txs to store are dropped while handlingL2Reorg is set
+	f.pendingTransactionsToStoreWG.Wait()
+	// a reorg received from now on is handled again in the next loop iteration
+	f.handlingL2Reorg.Store(false)
+
+	l2Reorg := L2ReorgEvent{TxHashes: f.dbManager.HandleL2Reorg()}
+	f.logL2ReorgEvent(ctx, l2Reorg)
+	for _, h := range f.l2ReorgHandlers {
+		h(l2Reorg)
+	}
+
+	// the template was prepared on the reorged state
+	f.nextBatchTemplate = nil
+	err := f.runCriticalOperation(ctx, func() error {
+		return f.syncWithState(ctx, nil)
+	})
+	if err != nil {
+		log.Errorf("failed to sync the WIP batch with the state after the L2 reorg, err: %v", err)
+		return
+	}
+	log.Infof("sequencing resumed after the L2 reorg on batch %d", f.batch.batchNumber)
+}
+
+// logL2ReorgEvent stores an event with the reorged txs
+func (f *finalizer) logL2ReorgEvent(ctx context.Context, l2Reorg L2ReorgEvent) {
+	txHashes := make([]string, 0, len(l2Reorg.TxHashes))
+	for _, txHash := range l2Reorg.TxHashes {
+		txHashes = append(txHashes, txHash.String())
+	}
+
+	event := &event.Event{
+		ReceivedAt:  time.Now(),
+		Source:      event.Source_Node,
+		Component:   event.Component_Sequencer,
+		Level:       event.Level_Warning,
+		EventID:     event.EventID_L2Reorg,
+		Description: strings.Join(txHashes, ","),
+	}
+	if err := f.eventLog.LogEvent(ctx, event); err != nil {
+		log.Errorf("error storing L2 reorg event: %v", err)
+	}
+}
@@ -28,8 +28,38 @@ const (
 	WorkerPrefix = Prefix + "worker_"
 	// WorkerProcessingTimeName is the name of the metric that shows the worker processing time.
 	WorkerProcessingTimeName = WorkerPrefix + "processing_time"
+	// WorkerReadyTxsName is the name of the metric that shows the number of ready txs held by the worker.
+	WorkerReadyTxsName = WorkerPrefix + "ready_txs"
+	// WorkerNotReadyTxsName is the name of the metric that shows the number of not ready txs held by the worker.
+	WorkerNotReadyTxsName = WorkerPrefix + "not_ready_txs"
+	// WorkerNotReadyAccountsName is the name of the metric that shows the number of accounts with not ready txs in the worker.
+	WorkerNotReadyAccountsName = WorkerPrefix + "not_ready_accounts"
+	// WorkerAddrQueuesName is the name of the metric that shows the number of address queues held by the worker.
+	WorkerAddrQueuesName = WorkerPrefix + "addr_queues"
+	// WorkerAddrQueueMaxTxsName is the name of the metric that shows the number of txs of the biggest address queue of the worker.
+	WorkerAddrQueueMaxTxsName = WorkerPrefix + "addr_queue_max_txs"
+	// WorkerSortedTxsName is the name of the metric that shows the length of the list of txs sorted by efficiency of the worker.
+	WorkerSortedTxsName = WorkerPrefix + "sorted_txs"
+	// WorkerOccupancyName is the name of the metric that shows the ratio between the txs held by the worker and MaxWorkerTxs.
+	WorkerOccupancyName = WorkerPrefix + "occupancy"
+	// WorkerTxsLimitedName is the name of the metric that counts the txs rejected or evicted because the worker limits were reached.
+	WorkerTxsLimitedName = WorkerPrefix + "txs_limited"
+	// WorkerTxsLimitedLabelName is the name of the label for the txs rejected or evicted because the worker limits were reached.
+	WorkerTxsLimitedLabelName = "action"
+	// WorkerGetBestFittingTxTimeName is the name of the metric that shows the time to get the best fitting tx from the worker.
+	WorkerGetBestFittingTxTimeName = WorkerPrefix + "get_best_fitting_tx_time"
+	// CandidateSimulationsName is the name of the metric that counts the simulations of the candidate txs by result.
+	CandidateSimulationsName = Prefix + "candidate_simulations"
+	// CandidateSimulationsLabelName is the name of the label for the result of the simulations of the candidate txs.
+	CandidateSimulationsLabelName = "result"
+	// BatchTxsName is the name of the metric that shows the number of txs of the closed batches.
+	BatchTxsName = Prefix + "batch_txs"
+	// BatchClosedName is the name of the metric that counts the closed batches.
+	BatchClosedName = Prefix + "batch_closed"
 	// TxProcessedLabelName is the name of the label for the processed transactions.
 	TxProcessedLabelName = "status"
+	// BatchClosedLabelName is the name of the label for the closing reason of the closed batches.
+	BatchClosedLabelName = "reason"
 )
 
 // TxProcessedLabel represents the possible values for the
@@ -43,6 +73,32 @@ const (
 	TxProcessedLabelInvalid TxProcessedLabel = "invalid"
 	// TxProcessedLabelFailed represents a failed transaction
 	TxProcessedLabelFailed TxProcessedLabel = "failed"
+	// TxProcessedLabelExpired represents a transaction evicted from the worker because it expired
+	TxProcessedLabelExpired TxProcessedLabel = "expired"
+)
+
+// WorkerTxsLimitedLabel represents the possible values for the
+// `sequencer_worker_txs_limited` metric `action` label.
+type WorkerTxsLimitedLabel string
+
+const (
+	// WorkerTxsLimitedLabelRejected represents a new tx rejected because the limit was reached
+	WorkerTxsLimitedLabelRejected WorkerTxsLimitedLabel = "rejected"
+	// WorkerTxsLimitedLabelEvicted represents a not ready tx evicted by a new tx with higher efficiency
+	WorkerTxsLimitedLabelEvicted WorkerTxsLimitedLabel = "evicted"
+)
+
+// CandidateSimulationLabel represents the possible values for the
+// `sequencer_candidate_simulations` metric `result` label.
+type CandidateSimulationLabel string
+
+const (
+	// CandidateSimulationLabelPassed represents a candidate tx included in the batch as simulated
+	CandidateSimulationLabelPassed CandidateSimulationLabel = "passed"
+	// CandidateSimulationLabelRejected represents a candidate tx handled with the simulation without including it
+	CandidateSimulationLabelRejected CandidateSimulationLabel = "rejected"
+	// CandidateSimulationLabelConflict represents a candidate tx whose execution differed from its simulation
+	CandidateSimulationLabelConflict CandidateSimulationLabel = "conflict"
 )
 
 // Register the metrics for the sequencer package.
@@ -73,6 +129,27 @@ func Register() {
 			},
 			Labels: []string{TxProcessedLabelName},
 		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: BatchClosedName,
+				Help: "[SEQUENCER] number of batches closed",
+			},
+			Labels: []string{BatchClosedLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: WorkerTxsLimitedName,
+				Help: "[SEQUENCER] number of txs rejected or evicted because the max txs per account or the max txs of the worker were reached",
+			},
+			Labels: []string{WorkerTxsLimitedLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: CandidateSimulationsName,
+				Help: "[SEQUENCER] number of simulations of the candidate txs by result",
+			},
+			Labels: []string{CandidateSimulationsLabelName},
+		},
 	}
 
 	gauges = []prometheus.GaugeOpts{
@@ -88,6 +165,34 @@ func Register() {
 			Name: SequenceRewardInMaticName,
 			Help: "[SEQUENCER] reward for a sequence in Matic",
 		},
+		{
+			Name: WorkerReadyTxsName,
+			Help: "[SEQUENCER] number of ready txs held by the worker",
+		},
+		{
+			Name: WorkerNotReadyTxsName,
+			Help: "[SEQUENCER] number of not ready txs (nonce gap or not enough balance) held by the worker",
+		},
+		{
+			Name: WorkerNotReadyAccountsName,
+			Help: "[SEQUENCER] number of accounts with not ready txs in the worker",
+		},
+		{
+			Name: WorkerAddrQueuesName,
+			Help: "[SEQUENCER] number of address queues held by the worker",
+		},
+		{
+			Name: WorkerAddrQueueMaxTxsName,
+			Help: "[SEQUENCER] number of txs of the biggest address queue of the worker",
+		},
+		{
+			Name: WorkerSortedTxsName,
+			Help: "[SEQUENCER] number of txs in the worker list sorted by efficiency",
+		},
+		{
+			Name: WorkerOccupancyName,
+			Help: "[SEQUENCER] ratio between the txs held by the worker and the max txs of the worker",
+		},
 	}
 
 	histograms = []prometheus.HistogramOpts{
@@ -99,6 +204,16 @@ func Register() {
 			Name: WorkerProcessingTimeName,
 			Help: "[SEQUENCER] worker processing time",
 		},
+		{
+			Name:    WorkerGetBestFittingTxTimeName,
+			Help:    "[SEQUENCER] time to get the best fitting tx from the worker",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 2, 15), //nolint:gomnd
+		},
+		{
+			Name:    BatchTxsName,
+			Help:    "[SEQUENCER] number of txs of the closed batches",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10), //nolint:gomnd
+		},
 	}
 
 	metrics.RegisterCounters(counters...)
@@ -151,3 +266,50 @@ func WorkerProcessingTime(lastProcessTime time.Duration) {
 	execTimeInSeconds := float64(lastProcessTime) / float64(time.Second)
 	metrics.HistogramObserve(WorkerProcessingTimeName, execTimeInSeconds)
 }
+
+// WorkerSize sets the gauges for the number of ready and not ready txs held by
+// the worker, the number of accounts with not ready txs, the number of address
+// queues and the number of txs of the biggest one.
+func WorkerSize(readyTxs, notReadyTxs, notReadyAccounts, addrQueues, addrQueueMaxTxs int) {
+	metrics.GaugeSet(WorkerReadyTxsName, float64(readyTxs))
+	metrics.GaugeSet(WorkerNotReadyTxsName, float64(notReadyTxs))
+	metrics.GaugeSet(WorkerNotReadyAccountsName, float64(notReadyAccounts))
+	metrics.GaugeSet(WorkerAddrQueuesName, float64(addrQueues))
+	metrics.GaugeSet(WorkerAddrQueueMaxTxsName, float64(addrQueueMaxTxs))
+}
+
+// WorkerSortedTxs sets the gauge for the length of the list of txs sorted by
+// efficiency of the worker.
+func WorkerSortedTxs(length int) {
+	metrics.GaugeSet(WorkerSortedTxsName, float64(length))
+}
+
+// WorkerOccupancy sets the gauge for the ratio between the txs held by the worker and its max txs.
+func WorkerOccupancy(ratio float64) {
+	metrics.GaugeSet(WorkerOccupancyName, ratio)
+}
+
+// WorkerTxsLimited increases the counter of txs rejected or evicted because the worker limits were reached.
+func WorkerTxsLimited(action WorkerTxsLimitedLabel) {
+	metrics.CounterVecInc(WorkerTxsLimitedName, string(action))
+}
+
+// CandidateSimulation increases the counter of simulations of the candidate txs for the given result.
+func CandidateSimulation(result CandidateSimulationLabel) {
+	metrics.CounterVecInc(CandidateSimulationsName, string(result))
+}
+
+// WorkerGetBestFittingTxTime observes the time to get the best fitting tx on the histogram.
+func WorkerGetBestFittingTxTime(lastProcessTime time.Duration) {
+	metrics.HistogramObserve(WorkerGetBestFittingTxTimeName, lastProcessTime.Seconds())
+}
+
+// BatchClosed observes the number of txs of a closed batch and increases the
+// counter of closed batches for the given closing reason.
+func BatchClosed(reason string, txs int) {
+	if reason == "" {
+		reason = "unknown"
+	}
+	metrics.CounterVecInc(BatchClosedName, reason)
+	metrics.HistogramObserve(BatchTxsName, float64(txs))
+}
@@ -521,6 +521,37 @@ func (_m *DbManagerMock) GetLatestGer(ctx context.Context, maxBlockNumber uint64
 	return r0, r1, r2
 }
 
+// GetLatestGerUpdate provides a mock function with given fields: ctx, maxBlockNumber
+func (_m *DbManagerMock) GetLatestGerUpdate(ctx context.Context, maxBlockNumber uint64) (state.GlobalExitRoot, time.Time, error) {
+	ret := _m.Called(ctx, maxBlockNumber)
+
+	var r0 state.GlobalExitRoot
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (state.GlobalExitRoot, time.Time, error)); ok {
+		return rf(ctx, maxBlockNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) state.GlobalExitRoot); ok {
+		r0 = rf(ctx, maxBlockNumber)
+	} else {
+		r0 = ret.Get(0).(state.GlobalExitRoot)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) time.Time); ok {
+		r1 = rf(ctx, maxBlockNumber)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64) error); ok {
+		r2 = rf(ctx, maxBlockNumber)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetLatestVirtualBatchTimestamp provides a mock function with given fields: ctx, dbTx
 func (_m *DbManagerMock) GetLatestVirtualBatchTimestamp(ctx context.Context, dbTx pgx.Tx) (time.Time, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -637,6 +668,22 @@ func (_m *DbManagerMock) GetWIPBatch(ctx context.Context) (*WipBatch, error) {
 	return r0, r1
 }
 
+// HandleL2Reorg provides a mock function with given fields:
+func (_m *DbManagerMock) HandleL2Reorg() []common.Hash {
+	ret := _m.Called()
+
+	var r0 []common.Hash
+	if rf, ok := ret.Get(0).(func() []common.Hash); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Hash)
+		}
+	}
+
+	return r0
+}
+
 // IsBatchClosed provides a mock function with given fields: ctx, batchNum
 func (_m *DbManagerMock) IsBatchClosed(ctx context.Context, batchNum uint64) (bool, error) {
 	ret := _m.Called(ctx, batchNum)
@@ -661,13 +708,13 @@ func (_m *DbManagerMock) IsBatchClosed(ctx context.Context, batchNum uint64) (bo
 	return r0, r1
 }
 
-// OpenBatch provides a mock function with given fields: ctx, processingContext, dbTx
-func (_m *DbManagerMock) OpenBatch(ctx context.Context, processingContext state.ProcessingContext, dbTx pgx.Tx) error {
-	ret := _m.Called(ctx, processingContext, dbTx)
+// OpenBatch provides a mock function with given fields: ctx, processingContext
+func (_m *DbManagerMock) OpenBatch(ctx context.Context, processingContext state.ProcessingContext) error {
+	ret := _m.Called(ctx, processingContext)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, state.ProcessingContext, pgx.Tx) error); ok {
-		r0 = rf(ctx, processingContext, dbTx)
+	if rf, ok := ret.Get(0).(func(context.Context, state.ProcessingContext) error); ok {
+		r0 = rf(ctx, processingContext)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -701,8 +748,8 @@ func (_m *DbManagerMock) ProcessForcedBatch(ForcedBatchNumber uint64, request st
 	return r0, r1
 }
 
-// StoreProcessedTxAndDeleteFromPool provides a mock function with given fields: ctx, tx
-func (_m *DbManagerMock) StoreProcessedTxAndDeleteFromPool(ctx context.Context, tx transactionToStore) error {
+// StoreProcessedTx provides a mock function with given fields: ctx, tx
+func (_m *DbManagerMock) StoreProcessedTx(ctx context.Context, tx transactionToStore) error {
 	ret := _m.Called(ctx, tx)
 
 	var r0 error
@@ -729,6 +776,20 @@ func (_m *DbManagerMock) UpdateTxStatus(ctx context.Context, hash common.Hash, n
 	return r0
 }
 
+// UpdateTxZKCounters provides a mock function with given fields: ctx, hash, zkCounters
+func (_m *DbManagerMock) UpdateTxZKCounters(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) error {
+	ret := _m.Called(ctx, hash, zkCounters)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, state.ZKCounters) error); ok {
+		r0 = rf(ctx, hash, zkCounters)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewDbManagerMock creates a new instance of DbManagerMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDbManagerMock(t interface {
@@ -4,15 +4,10 @@ package sequencer
 
 import (
 	context "context"
-	big "math/big"
 
 	common "github.com/ethereum/go-ethereum/common"
 
-	coretypes "github.com/ethereum/go-ethereum/core/types"
-
 	mock "github.com/stretchr/testify/mock"
-
-	types "github.com/0xPolygonHermez/zkevm-node/etherman/types"
 )
 
 // EthermanMock is an autogenerated mock type for the etherman type
@@ -20,91 +15,6 @@ type EthermanMock struct {
 	mock.Mock
 }
 
-// BuildSequenceBatchesTxData provides a mock function with given fields: sender, sequences, l2CoinBase
-func (_m *EthermanMock) BuildSequenceBatchesTxData(sender common.Address, sequences []types.Sequence, l2CoinBase common.Address) (*common.Address, []byte, error) {
-	ret := _m.Called(sender, sequences, l2CoinBase)
-
-	var r0 *common.Address
-	var r1 []byte
-	var r2 error
-	if rf, ok := ret.Get(0).(func(common.Address, []types.Sequence, common.Address) (*common.Address, []byte, error)); ok {
-		return rf(sender, sequences, l2CoinBase)
-	}
-	if rf, ok := ret.Get(0).(func(common.Address, []types.Sequence, common.Address) *common.Address); ok {
-		r0 = rf(sender, sequences, l2CoinBase)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*common.Address)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(common.Address, []types.Sequence, common.Address) []byte); ok {
-		r1 = rf(sender, sequences, l2CoinBase)
-	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).([]byte)
-		}
-	}
-
-	if rf, ok := ret.Get(2).(func(common.Address, []types.Sequence, common.Address) error); ok {
-		r2 = rf(sender, sequences, l2CoinBase)
-	} else {
-		r2 = ret.Error(2)
-	}
-
-	return r0, r1, r2
-}
-
-// EstimateGasSequenceBatches provides a mock function with given fields: sender, sequences, l2CoinBase
-func (_m *EthermanMock) EstimateGasSequenceBatches(sender common.Address, sequences []types.Sequence, l2CoinBase common.Address) (*coretypes.Transaction, error) {
-	ret := _m.Called(sender, sequences, l2CoinBase)
-
-	var r0 *coretypes.Transaction
-	var r1 error
-	if rf, ok := ret.Get(0).(func(common.Address, []types.Sequence, common.Address) (*coretypes.Transaction, error)); ok {
-		return rf(sender, sequences, l2CoinBase)
-	}
-	if rf, ok := ret.Get(0).(func(common.Address, []types.Sequence, common.Address) *coretypes.Transaction); ok {
-		r0 = rf(sender, sequences, l2CoinBase)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*coretypes.Transaction)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(common.Address, []types.Sequence, common.Address) error); ok {
-		r1 = rf(sender, sequences, l2CoinBase)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// GetLastBatchTimestamp provides a mock function with given fields:
-func (_m *EthermanMock) GetLastBatchTimestamp() (uint64, error) {
-	ret := _m.Called()
-
-	var r0 uint64
-	var r1 error
-	if rf, ok := ret.Get(0).(func() (uint64, error)); ok {
-		return rf()
-	}
-	if rf, ok := ret.Get(0).(func() uint64); ok {
-		r0 = rf()
-	} else {
-		r0 = ret.Get(0).(uint64)
-	}
-
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // GetLatestBatchNumber provides a mock function with given fields:
 func (_m *EthermanMock) GetLatestBatchNumber() (uint64, error) {
 	ret := _m.Called()
@@ -153,56 +63,6 @@ func (_m *EthermanMock) GetLatestBlockNumber(ctx context.Context) (uint64, error
 	return r0, r1
 }
 
-// GetLatestBlockTimestamp provides a mock function with given fields: ctx
-func (_m *EthermanMock) GetLatestBlockTimestamp(ctx context.Context) (uint64, error) {
-	ret := _m.Called(ctx)
-
-	var r0 uint64
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (uint64, error)); ok {
-		return rf(ctx)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context) uint64); ok {
-		r0 = rf(ctx)
-	} else {
-		r0 = ret.Get(0).(uint64)
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// GetSendSequenceFee provides a mock function with given fields: numBatches
-func (_m *EthermanMock) GetSendSequenceFee(numBatches uint64) (*big.Int, error) {
-	ret := _m.Called(numBatches)
-
-	var r0 *big.Int
-	var r1 error
-	if rf, ok := ret.Get(0).(func(uint64) (*big.Int, error)); ok {
-		return rf(numBatches)
-	}
-	if rf, ok := ret.Get(0).(func(uint64) *big.Int); ok {
-		r0 = rf(numBatches)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*big.Int)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(uint64) error); ok {
-		r1 = rf(numBatches)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // TrustedSequencer provides a mock function with given fields:
 func (_m *EthermanMock) TrustedSequencer() (common.Address, error) {
 	ret := _m.Called()
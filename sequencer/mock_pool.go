@@ -21,6 +21,20 @@ type PoolMock struct {
 	mock.Mock
 }
 
+// AddSelectionAuditEntries provides a mock function with given fields: ctx, entries
+func (_m *PoolMock) AddSelectionAuditEntries(ctx context.Context, entries []pool.SelectionAuditEntry) error {
+	ret := _m.Called(ctx, entries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []pool.SelectionAuditEntry) error); ok {
+		r0 = rf(ctx, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteFailedTransactionsOlderThan provides a mock function with given fields: ctx, date
 func (_m *PoolMock) DeleteFailedTransactionsOlderThan(ctx context.Context, date time.Time) error {
 	ret := _m.Called(ctx, date)
@@ -35,6 +49,20 @@ func (_m *PoolMock) DeleteFailedTransactionsOlderThan(ctx context.Context, date
 	return r0
 }
 
+// DeleteSelectionAuditOlderThan provides a mock function with given fields: ctx, date
+func (_m *PoolMock) DeleteSelectionAuditOlderThan(ctx context.Context, date time.Time) error {
+	ret := _m.Called(ctx, date)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, date)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteTransactionByHash provides a mock function with given fields: ctx, hash
 func (_m *PoolMock) DeleteTransactionByHash(ctx context.Context, hash common.Hash) error {
 	ret := _m.Called(ctx, hash)
@@ -151,6 +179,56 @@ func (_m *PoolMock) GetNonWIPPendingTxs(ctx context.Context) ([]pool.Transaction
 	return r0, r1
 }
 
+// GetPolicyDenyLists provides a mock function with given fields: ctx
+func (_m *PoolMock) GetPolicyDenyLists(ctx context.Context) (pool.PolicyDenyLists, error) {
+	ret := _m.Called(ctx)
+
+	var r0 pool.PolicyDenyLists
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (pool.PolicyDenyLists, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) pool.PolicyDenyLists); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(pool.PolicyDenyLists)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWIPPendingTxs provides a mock function with given fields: ctx
+func (_m *PoolMock) GetWIPPendingTxs(ctx context.Context) ([]pool.Transaction, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []pool.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]pool.Transaction, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []pool.Transaction); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTxZkCountersByHash provides a mock function with given fields: ctx, hash
 func (_m *PoolMock) GetTxZkCountersByHash(ctx context.Context, hash common.Hash) (*state.ZKCounters, error) {
 	ret := _m.Called(ctx, hash)
@@ -219,6 +297,20 @@ func (_m *PoolMock) UpdateTxWIPStatus(ctx context.Context, hash common.Hash, isW
 	return r0
 }
 
+// UpdateTxZKCounters provides a mock function with given fields: ctx, hash, zkCounters
+func (_m *PoolMock) UpdateTxZKCounters(ctx context.Context, hash common.Hash, zkCounters state.ZKCounters) error {
+	ret := _m.Called(ctx, hash, zkCounters)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, state.ZKCounters) error); ok {
+		r0 = rf(ctx, hash, zkCounters)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewPoolMock creates a new instance of PoolMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPoolMock(t interface {
@@ -94,6 +94,30 @@ func (_m *StateMock) CloseBatch(ctx context.Context, receipt state.ProcessingRec
 	return r0
 }
 
+// CountL1Reorgs provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) CountL1Reorgs(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (uint64, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) uint64); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CountReorgs provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) CountReorgs(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -406,6 +430,32 @@ func (_m *StateMock) GetLastBatch(ctx context.Context, dbTx pgx.Tx) (*state.Batc
 	return r0, r1
 }
 
+// GetLastBatchProvingTimes provides a mock function with given fields: ctx, limit, dbTx
+func (_m *StateMock) GetLastBatchProvingTimes(ctx context.Context, limit uint64, dbTx pgx.Tx) ([]state.BatchProvingTime, error) {
+	ret := _m.Called(ctx, limit, dbTx)
+
+	var r0 []state.BatchProvingTime
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]state.BatchProvingTime, error)); ok {
+		return rf(ctx, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []state.BatchProvingTime); ok {
+		r0 = rf(ctx, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.BatchProvingTime)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastBatchNumber provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -746,6 +796,32 @@ func (_m *StateMock) GetNonceByStateRoot(ctx context.Context, address common.Add
 	return r0, r1
 }
 
+// GetStorageAt provides a mock function with given fields: ctx, address, position, root
+func (_m *StateMock) GetStorageAt(ctx context.Context, address common.Address, position *big.Int, root common.Hash) (*big.Int, error) {
+	ret := _m.Called(ctx, address, position, root)
+
+	var r0 *big.Int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, *big.Int, common.Hash) (*big.Int, error)); ok {
+		return rf(ctx, address, position, root)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, *big.Int, common.Hash) *big.Int); ok {
+		r0 = rf(ctx, address, position, root)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, *big.Int, common.Hash) error); ok {
+		r1 = rf(ctx, address, position, root)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStoredFlushID provides a mock function with given fields: ctx
 func (_m *StateMock) GetStoredFlushID(ctx context.Context) (uint64, string, error) {
 	ret := _m.Called(ctx)
@@ -71,6 +71,22 @@ func (_m *WorkerMock) DeleteTx(txHash common.Hash, from common.Address) {
 	_m.Called(txHash, from)
 }
 
+// GetBestFittingTaggedTx provides a mock function with given fields: resources, tag
+func (_m *WorkerMock) GetBestFittingTaggedTx(resources state.BatchResources, tag string) *TxTracker {
+	ret := _m.Called(resources, tag)
+
+	var r0 *TxTracker
+	if rf, ok := ret.Get(0).(func(state.BatchResources, string) *TxTracker); ok {
+		r0 = rf(resources, tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*TxTracker)
+		}
+	}
+
+	return r0
+}
+
 // GetBestFittingTx provides a mock function with given fields: resources
 func (_m *WorkerMock) GetBestFittingTx(resources state.BatchResources) *TxTracker {
 	ret := _m.Called(resources)
@@ -87,9 +103,34 @@ func (_m *WorkerMock) GetBestFittingTx(resources state.BatchResources) *TxTracke
 	return r0
 }
 
-// HandleL2Reorg provides a mock function with given fields: txHashes
-func (_m *WorkerMock) HandleL2Reorg(txHashes []common.Hash) {
-	_m.Called(txHashes)
+// GetTxsSnapshot provides a mock function with given fields:
+func (_m *WorkerMock) GetTxsSnapshot() TxsSnapshot {
+	ret := _m.Called()
+
+	var r0 TxsSnapshot
+	if rf, ok := ret.Get(0).(func() TxsSnapshot); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(TxsSnapshot)
+	}
+
+	return r0
+}
+
+// HandleL2Reorg provides a mock function with given fields: ctx, reorgedTxs
+func (_m *WorkerMock) HandleL2Reorg(ctx context.Context, reorgedTxs []*TxTracker) []*TxTracker {
+	ret := _m.Called(ctx, reorgedTxs)
+
+	var r0 []*TxTracker
+	if rf, ok := ret.Get(0).(func(context.Context, []*TxTracker) []*TxTracker); ok {
+		r0 = rf(ctx, reorgedTxs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*TxTracker)
+		}
+	}
+
+	return r0
 }
 
 // MoveTxToNotReady provides a mock function with given fields: txHash, from, actualNonce, actualBalance
@@ -108,6 +149,20 @@ func (_m *WorkerMock) MoveTxToNotReady(txHash common.Hash, from common.Address,
 	return r0
 }
 
+// HasFittingTx provides a mock function with given fields: resources, lookAheadTxs
+func (_m *WorkerMock) HasFittingTx(resources state.BatchResources, lookAheadTxs uint64) bool {
+	ret := _m.Called(resources, lookAheadTxs)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(state.BatchResources, uint64) bool); ok {
+		r0 = rf(resources, lookAheadTxs)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // NewTxTracker provides a mock function with given fields: tx, counters, ip
 func (_m *WorkerMock) NewTxTracker(tx types.Transaction, counters state.ZKCounters, ip string) (*TxTracker, error) {
 	ret := _m.Called(tx, counters, ip)
@@ -134,6 +189,20 @@ func (_m *WorkerMock) NewTxTracker(tx types.Transaction, counters state.ZKCounte
 	return r0, r1
 }
 
+// RetryTxLater provides a mock function with given fields: txHash, from
+func (_m *WorkerMock) RetryTxLater(txHash common.Hash, from common.Address) bool {
+	ret := _m.Called(txHash, from)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(common.Hash, common.Address) bool); ok {
+		r0 = rf(txHash, from)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // UpdateAfterSingleSuccessfulTxExecution provides a mock function with given fields: from, touchedAddresses
 func (_m *WorkerMock) UpdateAfterSingleSuccessfulTxExecution(from common.Address, touchedAddresses map[common.Address]*state.InfoReadWrite) []*TxTracker {
 	ret := _m.Called(from, touchedAddresses)
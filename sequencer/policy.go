@@ -0,0 +1,66 @@
+package sequencer
+
+import (
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// txPolicy rejects the txs of denied senders, to denied recipients or calling denied
+// 4-byte function selectors. It's immutable, a reload replaces the whole policy
+type txPolicy struct {
+	deniedSenders    map[common.Address]struct{}
+	deniedRecipients map[common.Address]struct{}
+	deniedSelectors  map[[4]byte]struct{}
+}
+
+// newTxPolicy creates a txPolicy merging the deny lists of the config with the ones stored in the pool DB
+func newTxPolicy(cfg PolicyCfg, lists pool.PolicyDenyLists) (*txPolicy, error) {
+	p := &txPolicy{
+		deniedSenders:    make(map[common.Address]struct{}, len(cfg.DeniedSenders)+len(lists.Senders)),
+		deniedRecipients: make(map[common.Address]struct{}, len(cfg.DeniedRecipients)+len(lists.Recipients)),
+		deniedSelectors:  make(map[[4]byte]struct{}, len(cfg.DeniedSelectors)+len(lists.Selectors)),
+	}
+
+	for _, senders := range [][]common.Address{cfg.DeniedSenders, lists.Senders} {
+		for _, addr := range senders {
+			p.deniedSenders[addr] = struct{}{}
+		}
+	}
+	for _, recipients := range [][]common.Address{cfg.DeniedRecipients, lists.Recipients} {
+		for _, addr := range recipients {
+			p.deniedRecipients[addr] = struct{}{}
+		}
+	}
+	for _, value := range cfg.DeniedSelectors {
+		selector := common.FromHex(value)
+		if len(selector) != len([4]byte{}) {
+			return nil, fmt.Errorf("invalid denied selector %q, it must be 4 bytes in hex format", value)
+		}
+		p.deniedSelectors[[4]byte(selector)] = struct{}{}
+	}
+	for _, selector := range lists.Selectors {
+		p.deniedSelectors[selector] = struct{}{}
+	}
+
+	return p, nil
+}
+
+// check returns an error if the tx is denied by the policy
+func (p *txPolicy) check(tx *TxTracker) error {
+	if _, found := p.deniedSenders[tx.From]; found {
+		return ErrPolicyDeniedSender
+	}
+	if tx.To != nil {
+		if _, found := p.deniedRecipients[*tx.To]; found {
+			return ErrPolicyDeniedRecipient
+		}
+	}
+	if len(tx.Selector) == len([4]byte{}) {
+		if _, found := p.deniedSelectors[[4]byte(tx.Selector)]; found {
+			return ErrPolicyDeniedSelector
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package sequencer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxPolicyCheck(t *testing.T) {
+	cfg := PolicyCfg{
+		Enabled:          true,
+		DeniedSenders:    []common.Address{{1}},
+		DeniedRecipients: []common.Address{{2}},
+		DeniedSelectors:  []string{"0xa9059cbb"},
+	}
+	lists := pool.PolicyDenyLists{
+		Senders:   []common.Address{{3}},
+		Selectors: [][4]byte{{0x09, 0x5e, 0xa7, 0xb3}},
+	}
+	policy, err := newTxPolicy(cfg, lists)
+	require.NoError(t, err)
+
+	allowedTo := common.Address{4}
+	deniedTo := common.Address{2}
+	testCases := []struct {
+		name        string
+		tx          *TxTracker
+		expectedErr error
+	}{
+		{"allowed tx", &TxTracker{From: common.Address{5}, To: &allowedTo, Selector: []byte{0x01, 0x02, 0x03, 0x04}}, nil},
+		{"contract creation", &TxTracker{From: common.Address{5}}, nil},
+		{"sender denied by config", &TxTracker{From: common.Address{1}, To: &allowedTo}, ErrPolicyDeniedSender},
+		{"sender denied by DB", &TxTracker{From: common.Address{3}, To: &allowedTo}, ErrPolicyDeniedSender},
+		{"recipient denied", &TxTracker{From: common.Address{5}, To: &deniedTo}, ErrPolicyDeniedRecipient},
+		{"selector denied by config", &TxTracker{From: common.Address{5}, To: &allowedTo, Selector: []byte{0xa9, 0x05, 0x9c, 0xbb}}, ErrPolicyDeniedSelector},
+		{"selector denied by DB", &TxTracker{From: common.Address{5}, To: &allowedTo, Selector: []byte{0x09, 0x5e, 0xa7, 0xb3}}, ErrPolicyDeniedSelector},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expectedErr, policy.check(testCase.tx))
+		})
+	}
+
+	_, err = newTxPolicy(PolicyCfg{Enabled: true, DeniedSelectors: []string{"0xa9059c"}}, pool.PolicyDenyLists{})
+	assert.Error(t, err)
+}
+
+func TestWorkerUpdatePolicy(t *testing.T) {
+	ctx := context.Background()
+	worker := initWorker(NewStateMock(t), rcMax)
+
+	from := common.Address{1}
+	to := common.Address{2}
+	newTx := func(hash byte, nonce uint64, selector []byte) *TxTracker {
+		return &TxTracker{Hash: common.Hash{hash}, HashStr: common.Hash{hash}.String(), From: from, FromStr: from.String(), To: &to,
+			Selector: selector, Nonce: nonce, GasPrice: new(big.Int).SetInt64(1), Cost: new(big.Int).SetInt64(1), IP: validIP}
+	}
+	worker.pool[from.String()] = newAddrQueue(from, 1, new(big.Int).SetInt64(10))
+
+	_, err := worker.AddTxTracker(ctx, newTx(1, 1, []byte{0xa9, 0x05, 0x9c, 0xbb}))
+	require.NoError(t, err)
+	_, err = worker.AddTxTracker(ctx, newTx(2, 3, nil))
+	require.NoError(t, err)
+	require.Equal(t, 1, worker.txSortedList.len())
+
+	policy, err := newTxPolicy(PolicyCfg{Enabled: true, DeniedSelectors: []string{"0xa9059cbb"}}, pool.PolicyDenyLists{})
+	require.NoError(t, err)
+
+	// the ready tx calls a denied selector, it's deleted and can't be selected anymore
+	deletedTxs := worker.UpdatePolicy(policy)
+	require.Len(t, deletedTxs, 1)
+	assert.Equal(t, common.Hash{1}, deletedTxs[0].Hash)
+	require.NotNil(t, deletedTxs[0].FailedReason)
+	assert.Equal(t, ErrPolicyDeniedSelector.Error(), *deletedTxs[0].FailedReason)
+	assert.Equal(t, 0, worker.txSortedList.len())
+	assert.Equal(t, 1, worker.pool[from.String()].txsCount())
+
+	// the new txs calling a denied selector are rejected
+	_, err = worker.AddTxTracker(ctx, newTx(3, 1, []byte{0xa9, 0x05, 0x9c, 0xbb}))
+	assert.ErrorIs(t, err, ErrPolicyDeniedSelector)
+
+	// denying the recipient deletes the remaining tx and the empty addrQueue
+	policy, err = newTxPolicy(PolicyCfg{Enabled: true, DeniedRecipients: []common.Address{to}}, pool.PolicyDenyLists{})
+	require.NoError(t, err)
+	deletedTxs = worker.UpdatePolicy(policy)
+	require.Len(t, deletedTxs, 1)
+	assert.Equal(t, common.Hash{2}, deletedTxs[0].Hash)
+	assert.Empty(t, worker.pool)
+}
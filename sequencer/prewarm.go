@@ -0,0 +1,38 @@
+package sequencer
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// batchTemplate contains the checks to open the next batch done while the current batch is filling
+type batchTemplate struct {
+	synced     bool
+	preparedAt time.Time
+}
+
+// preWarmNextBatch prepares the template of the next batch if there isn't one or it's too old,
+// it's called while waiting for new txs so the checks don't delay the selection of txs
+func (f *finalizer) preWarmNextBatch(ctx context.Context) {
+	if f.nextBatchTemplate != nil && now().Sub(f.nextBatchTemplate.preparedAt) < f.cfg.PreWarm.MaxAge.Duration {
+		return
+	}
+	f.nextBatchTemplate = &batchTemplate{
+		synced:     f.isSynced(ctx),
+		preparedAt: now(),
+	}
+}
+
+// useNextBatchTemplate consumes the template of the next batch, it returns true if the template
+// is recent enough and the synchronizer was synced when it was prepared
+func (f *finalizer) useNextBatchTemplate() bool {
+	template := f.nextBatchTemplate
+	f.nextBatchTemplate = nil
+	if template == nil || !template.synced || now().Sub(template.preparedAt) >= f.cfg.PreWarm.MaxAge.Duration {
+		return false
+	}
+	log.Debugf("opening batch with the template prepared at %v", template.preparedAt)
+	return true
+}
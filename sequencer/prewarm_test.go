@@ -0,0 +1,54 @@
+package sequencer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinalizerPreWarmNextBatch(t *testing.T) {
+	ctx := context.Background()
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() {
+		now = time.Now
+	}()
+
+	synced, syncChecks := true, 0
+	f := &finalizer{
+		cfg: FinalizerCfg{PreWarm: BatchPreWarmCfg{Enabled: true, MaxAge: types.NewDuration(time.Second)}},
+		isSynced: func(ctx context.Context) bool {
+			syncChecks++
+			return synced
+		},
+	}
+
+	// without a template the sync must be checked when opening the batch
+	assert.False(t, f.useNextBatchTemplate())
+
+	// the template is prepared once while it's fresh
+	f.preWarmNextBatch(ctx)
+	f.preWarmNextBatch(ctx)
+	assert.Equal(t, 1, syncChecks)
+	assert.True(t, f.useNextBatchTemplate())
+	// the template is consumed
+	assert.False(t, f.useNextBatchTemplate())
+
+	// an old template is refreshed, and ignored when opening the batch
+	f.preWarmNextBatch(ctx)
+	current = current.Add(time.Second)
+	assert.False(t, f.useNextBatchTemplate())
+	f.preWarmNextBatch(ctx)
+	current = current.Add(2 * time.Second)
+	f.preWarmNextBatch(ctx)
+	assert.Equal(t, 4, syncChecks)
+	assert.True(t, f.useNextBatchTemplate())
+
+	// a template prepared while not synced is not used
+	synced = false
+	f.preWarmNextBatch(ctx)
+	assert.False(t, f.useNextBatchTemplate())
+}
@@ -0,0 +1,109 @@
+package sequencer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// provingBudget limits the proving complexity of the batches so they can be proven
+// in less than the configured max proving time. The proving time per complexity unit
+// is learned from the proving times of the last batches stored by the aggregator
+type provingBudget struct {
+	cfg         ProvingBudgetCfg
+	constraints state.BatchConstraintsCfg
+	state       stateInterface
+
+	mutex sync.RWMutex
+	// maxComplexity is the max complexity of a batch, 0 when there are no proving
+	// times to learn from yet and the batches are not limited
+	maxComplexity float64
+}
+
+func newProvingBudget(cfg ProvingBudgetCfg, constraints state.BatchConstraintsCfg, st stateInterface) *provingBudget {
+	return &provingBudget{
+		cfg:         cfg,
+		constraints: constraints,
+		state:       st,
+	}
+}
+
+// start updates periodically the max complexity of the batches until the context is done
+func (b *provingBudget) start(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.RefreshInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		if err := b.update(ctx); err != nil {
+			log.Errorf("failed to update the batches proving budget, err: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update learns the proving time per complexity unit from the last proven batches
+// and updates the max complexity of the batches accordingly
+func (b *provingBudget) update(ctx context.Context) error {
+	provingTimes, err := b.state.GetLastBatchProvingTimes(ctx, b.cfg.HistoryBatches, nil)
+	if err != nil {
+		return err
+	}
+
+	var totalComplexity, totalSeconds float64
+	for _, provingTime := range provingTimes {
+		totalComplexity += b.complexity(provingTime.BatchResources.ZKCounters)
+		totalSeconds += provingTime.Duration.Seconds()
+	}
+	if totalComplexity == 0 || totalSeconds == 0 {
+		log.Debugf("no proving times to learn the batches proving budget from")
+		return nil
+	}
+
+	secondsPerUnit := totalSeconds / totalComplexity
+	maxComplexity := b.cfg.MaxProvingTime.Duration.Seconds() / secondsPerUnit
+
+	b.mutex.Lock()
+	b.maxComplexity = maxComplexity
+	b.mutex.Unlock()
+
+	log.Infof("batches proving budget updated from %d proven batches, proving time per complexity unit: %fs, max complexity: %f",
+		len(provingTimes), secondsPerUnit, maxComplexity)
+	return nil
+}
+
+// complexity returns the weighted sum of the counters, each one relative to its batch constraint
+func (b *provingBudget) complexity(counters state.ZKCounters) float64 {
+	ratio := func(used uint32, limit uint32) float64 {
+		if limit == 0 {
+			return 0
+		}
+		return float64(used) / float64(limit)
+	}
+
+	w := b.cfg.Weights
+	return w.KeccakHashes*ratio(counters.UsedKeccakHashes, b.constraints.MaxKeccakHashes) +
+		w.PoseidonHashes*ratio(counters.UsedPoseidonHashes, b.constraints.MaxPoseidonHashes) +
+		w.PoseidonPaddings*ratio(counters.UsedPoseidonPaddings, b.constraints.MaxPoseidonPaddings) +
+		w.MemAligns*ratio(counters.UsedMemAligns, b.constraints.MaxMemAligns) +
+		w.Arithmetics*ratio(counters.UsedArithmetics, b.constraints.MaxArithmetics) +
+		w.Binaries*ratio(counters.UsedBinaries, b.constraints.MaxBinaries) +
+		w.Steps*ratio(counters.UsedSteps, b.constraints.MaxSteps)
+}
+
+// isExceeded returns true if the complexity of a batch using the provided counters
+// is higher than the max complexity learned
+func (b *provingBudget) isExceeded(counters state.ZKCounters) bool {
+	b.mutex.RLock()
+	maxComplexity := b.maxComplexity
+	b.mutex.RUnlock()
+
+	return maxComplexity > 0 && b.complexity(counters) > maxComplexity
+}
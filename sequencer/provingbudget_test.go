@@ -0,0 +1,48 @@
+package sequencer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvingBudget(t *testing.T) {
+	ctx := context.Background()
+	stateMock := NewStateMock(t)
+	constraints := state.BatchConstraintsCfg{
+		MaxKeccakHashes:     100,
+		MaxPoseidonHashes:   100,
+		MaxPoseidonPaddings: 100,
+		MaxMemAligns:        100,
+		MaxArithmetics:      100,
+		MaxBinaries:         100,
+		MaxSteps:            1000,
+	}
+	cfg := ProvingBudgetCfg{
+		Enabled:        true,
+		MaxProvingTime: types.NewDuration(60 * time.Second),
+		HistoryBatches: 10,
+		Weights:        ProvingComplexityWeights{Steps: 1, KeccakHashes: 1},
+	}
+	b := newProvingBudget(cfg, constraints, stateMock)
+
+	// without proving times the batches are not limited
+	stateMock.On("GetLastBatchProvingTimes", ctx, uint64(10), nil).Return(nil, nil).Once()
+	require.NoError(t, b.update(ctx))
+	assert.False(t, b.isExceeded(state.ZKCounters{UsedSteps: 1000, UsedKeccakHashes: 100}))
+
+	// complexity 0.5 + 0.5 = 1 proven in 60s and 0.5 proven in 30s: 60s per complexity unit
+	stateMock.On("GetLastBatchProvingTimes", ctx, uint64(10), nil).Return([]state.BatchProvingTime{
+		{BatchNumber: 2, Duration: 60 * time.Second, BatchResources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 500, UsedKeccakHashes: 50}}},
+		{BatchNumber: 1, Duration: 30 * time.Second, BatchResources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 500, UsedBinaries: 100}}},
+	}, nil).Once()
+	require.NoError(t, b.update(ctx))
+	assert.InDelta(t, 1, b.maxComplexity, 0.0001)
+	assert.False(t, b.isExceeded(state.ZKCounters{UsedSteps: 500, UsedKeccakHashes: 50}))
+	assert.True(t, b.isExceeded(state.ZKCounters{UsedSteps: 600, UsedKeccakHashes: 50}))
+}
@@ -0,0 +1,50 @@
+package sequencer
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// repriceWorkerTxs updates periodically the price floor of the worker with the L2 suggested gas price,
+// so the ready txs priced below it are demoted and the ones priced above it are promoted back
+func (s *Sequencer) repriceWorkerTxs(ctx context.Context, worker *Worker) {
+	var priceFloor *big.Int
+	ticker := time.NewTicker(s.cfg.Repricing.CheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, l2GasPrice := s.pool.GetL1AndL2GasPrice()
+		if l2GasPrice == 0 {
+			// the gas prices haven't been loaded yet
+			continue
+		}
+		suggestedPrice := new(big.Int).SetUint64(l2GasPrice)
+		if !priceFloorChanged(priceFloor, suggestedPrice, s.cfg.Repricing.Hysteresis) {
+			continue
+		}
+
+		log.Infof("updating worker price floor from %v to %v", priceFloor, suggestedPrice)
+		priceFloor = suggestedPrice
+		worker.UpdatePriceFloor(priceFloor)
+	}
+}
+
+// priceFloorChanged returns if the suggested gas price differs from the current price
+// floor by more than the hysteresis percentage of the floor
+func priceFloorChanged(priceFloor, suggestedPrice *big.Int, hysteresis uint64) bool {
+	if priceFloor == nil {
+		return true
+	}
+	diff := new(big.Int).Sub(suggestedPrice, priceFloor)
+	diff.Abs(diff).Mul(diff, big.NewInt(100)) //nolint:gomnd
+	maxDiff := new(big.Int).Mul(priceFloor, new(big.Int).SetUint64(hysteresis))
+	return diff.Cmp(maxDiff) > 0
+}
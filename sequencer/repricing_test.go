@@ -0,0 +1,21 @@
+package sequencer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceFloorChanged(t *testing.T) {
+	floor := big.NewInt(1000)
+
+	assert.True(t, priceFloorChanged(nil, big.NewInt(1000), 5), "the first suggested price is always applied")
+	assert.False(t, priceFloorChanged(floor, big.NewInt(1000), 5))
+	assert.False(t, priceFloorChanged(floor, big.NewInt(1050), 5), "within the hysteresis")
+	assert.False(t, priceFloorChanged(floor, big.NewInt(950), 5), "within the hysteresis")
+	assert.True(t, priceFloorChanged(floor, big.NewInt(1051), 5))
+	assert.True(t, priceFloorChanged(floor, big.NewInt(949), 5))
+	assert.True(t, priceFloorChanged(floor, big.NewInt(1001), 0), "any change without hysteresis")
+	assert.False(t, priceFloorChanged(floor, big.NewInt(1000), 0))
+}
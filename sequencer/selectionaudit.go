@@ -0,0 +1,133 @@
+package sequencer
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// selectionAuditor records the decisions taken by the worker in the selection
+// rounds and persists them asynchronously, so the selection is never blocked
+type selectionAuditor struct {
+	cfg     SelectionAuditCfg
+	storage txPool
+
+	round      atomic.Uint64
+	pending    []pool.SelectionAuditEntry
+	pendingMux sync.Mutex
+}
+
+func newSelectionAuditor(cfg SelectionAuditCfg, storage txPool) *selectionAuditor {
+	return &selectionAuditor{
+		cfg:     cfg,
+		storage: storage,
+	}
+}
+
+// sample returns true if the current selection round must be recorded
+func (a *selectionAuditor) sample() bool {
+	if a.cfg.SamplingRate >= 1 {
+		return true
+	}
+	return rand.Float64() < a.cfg.SamplingRate //nolint:gosec
+}
+
+// record adds the entries of a selection round to the ones waiting to be
+// persisted, the whole round is discarded if the buffer is full
+func (a *selectionAuditor) record(entries []pool.SelectionAuditEntry) {
+	a.pendingMux.Lock()
+	defer a.pendingMux.Unlock()
+
+	if a.cfg.BufferSize > 0 && uint64(len(a.pending)+len(entries)) > a.cfg.BufferSize {
+		log.Warnf("selection audit log buffer is full, discarding %d entries of round %d", len(entries), entries[0].Round)
+		return
+	}
+	a.pending = append(a.pending, entries...)
+}
+
+// Start persists periodically the recorded entries until the context is done
+func (a *selectionAuditor) Start(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.FlushInterval.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.flush(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *selectionAuditor) flush(ctx context.Context) {
+	a.pendingMux.Lock()
+	entries := a.pending
+	a.pending = nil
+	a.pendingMux.Unlock()
+
+	if len(entries) == 0 {
+		return
+	}
+	if err := a.storage.AddSelectionAuditEntries(ctx, entries); err != nil {
+		log.Errorf("failed to store %d selection audit log entries, err: %v", len(entries), err)
+	}
+}
+
+// auditSelectionRound records the candidates considered by GetBestFittingTx. As
+// the candidates are evaluated in order, all the ones before the selected tx have
+// been skipped because they didn't fit in the remaining resources. It must be
+// called with the worker mutex locked
+func (w *Worker) auditSelectionRound(resources state.BatchResources, foundAt int) {
+	round := w.selectionAudit.round.Add(1)
+	now := time.Now()
+
+	skipped := w.txSortedList.len()
+	if foundAt != -1 {
+		skipped = foundAt
+	}
+	if maxCandidates := w.selectionAudit.cfg.MaxCandidatesPerRound; maxCandidates > 0 && uint64(skipped) > maxCandidates {
+		skipped = int(maxCandidates)
+	}
+	if skipped == 0 && foundAt == -1 {
+		return
+	}
+
+	entries := make([]pool.SelectionAuditEntry, 0, skipped+1)
+	for i := 0; i < skipped; i++ {
+		tx := w.txSortedList.getByIndex(i)
+		reason := "it does not fit in the remaining resources"
+		remaining := resources
+		if err := remaining.Sub(tx.BatchResources); err != nil {
+			reason = err.Error()
+		}
+		entries = append(entries, newSelectionAuditEntry(round, i, tx, resources, pool.SelectionDecisionSkipped, reason, now))
+	}
+	if foundAt != -1 {
+		tx := w.txSortedList.getByIndex(foundAt)
+		entries = append(entries, newSelectionAuditEntry(round, foundAt, tx, resources, pool.SelectionDecisionSelected, "", now))
+	}
+
+	w.selectionAudit.record(entries)
+}
+
+func newSelectionAuditEntry(round uint64, position int, tx *TxTracker, resources state.BatchResources, decision pool.SelectionDecision, reason string, now time.Time) pool.SelectionAuditEntry {
+	return pool.SelectionAuditEntry{
+		Round:              round,
+		Position:           position,
+		Hash:               tx.Hash,
+		From:               tx.From,
+		Nonce:              tx.Nonce,
+		Score:              tx.GasPrice,
+		RemainingResources: resources,
+		Decision:           decision,
+		Reason:             reason,
+		SelectedAt:         now,
+	}
+}
@@ -0,0 +1,57 @@
+package sequencer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditSelectionRound(t *testing.T) {
+	worker := NewWorker(Config{}, pool.Config{}, nil, rcMax)
+	worker.selectionAudit = newSelectionAuditor(SelectionAuditCfg{Enabled: true, SamplingRate: 1}, nil)
+
+	for i, usedSteps := range []uint32{8, 6, 2} {
+		worker.txSortedList.add(&TxTracker{
+			Hash:     common.Hash{byte(i + 1)},
+			HashStr:  common.Hash{byte(i + 1)}.String(),
+			From:     common.Address{byte(i + 1)},
+			GasPrice: big.NewInt(int64(100 - i)),
+			BatchResources: state.BatchResources{
+				Bytes:      1,
+				ZKCounters: state.ZKCounters{UsedSteps: usedSteps},
+			},
+		})
+	}
+
+	resources := state.BatchResources{Bytes: 10, ZKCounters: state.ZKCounters{UsedSteps: 5}}
+	tx := worker.GetBestFittingTx(resources)
+	require.NotNil(t, tx)
+	assert.Equal(t, common.Hash{3}, tx.Hash)
+
+	entries := worker.selectionAudit.pending
+	require.Len(t, entries, 3)
+	for i, entry := range entries {
+		assert.Equal(t, uint64(1), entry.Round)
+		assert.Equal(t, i, entry.Position)
+		assert.Equal(t, resources, entry.RemainingResources)
+	}
+	assert.Equal(t, pool.SelectionDecisionSkipped, entries[0].Decision)
+	assert.NotEmpty(t, entries[0].Reason)
+	assert.Equal(t, pool.SelectionDecisionSkipped, entries[1].Decision)
+	assert.Equal(t, pool.SelectionDecisionSelected, entries[2].Decision)
+	assert.Equal(t, common.Hash{3}, entries[2].Hash)
+
+	worker.selectionAudit.cfg.MaxCandidatesPerRound = 1
+	worker.selectionAudit.pending = nil
+	worker.GetBestFittingTx(resources)
+	entries = worker.selectionAudit.pending
+	require.Len(t, entries, 2)
+	assert.Equal(t, uint64(2), entries[0].Round)
+	assert.Equal(t, pool.SelectionDecisionSkipped, entries[0].Decision)
+	assert.Equal(t, pool.SelectionDecisionSelected, entries[1].Decision)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
@@ -27,7 +28,14 @@ type Sequencer struct {
 	eventLog *event.EventLog
 	etherman etherman
 
-	address common.Address
+	l2Coinbase common.Address
+	txSorter   TxSorter
+
+	finalizer atomic.Pointer[finalizer]
+	worker    atomic.Pointer[Worker]
+
+	txSelectedHandlers []TxSelectedEventHandler
+	l2ReorgHandlers    []L2ReorgEventHandler
 }
 
 // L2ReorgEvent is the event that is triggered when a reorg happens in the L2
@@ -43,20 +51,44 @@ type ClosingSignalCh struct {
 }
 
 // New init sequencer
-func New(cfg Config, batchCfg state.BatchConfig, txPool txPool, state stateInterface, etherman etherman, eventLog *event.EventLog) (*Sequencer, error) {
+func New(cfg Config, batchCfg state.BatchConfig, poolCfg pool.Config, txPool txPool, state stateInterface, etherman etherman, eventLog *event.EventLog) (*Sequencer, error) {
 	addr, err := etherman.TrustedSequencer()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trusted sequencer address, err: %v", err)
 	}
+	l2Coinbase := cfg.L2Coinbase
+	if l2Coinbase == (common.Address{}) {
+		log.Warnf("Sequencer.L2Coinbase is not set, the fees of the L2 txs go to the trusted sequencer address %s", addr.String())
+		l2Coinbase = addr
+	}
+	log.Infof("trusted sequencer address: %s, L2 coinbase: %s", addr.String(), l2Coinbase.String())
+
+	txSorter, err := NewTxSorter(cfg.TxSorter, batchCfg.Constraints)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSequencingWindows(cfg.Finalizer.SequencingWindows); err != nil {
+		return nil, err
+	}
+
+	if cfg.Policy.Enabled {
+		// validate the deny lists of the config, the policy is loaded with the DB lists on start
+		if _, err := newTxPolicy(cfg.Policy, pool.PolicyDenyLists{}); err != nil {
+			return nil, err
+		}
+	}
 
 	sequencer := &Sequencer{
-		cfg:      cfg,
-		batchCfg: batchCfg,
-		pool:     txPool,
-		state:    state,
-		etherman: etherman,
-		address:  addr,
-		eventLog: eventLog,
+		cfg:        cfg,
+		batchCfg:   batchCfg,
+		poolCfg:    poolCfg,
+		pool:       txPool,
+		state:      state,
+		etherman:   etherman,
+		l2Coinbase: l2Coinbase,
+		txSorter:   txSorter,
+		eventLog:   eventLog,
 	}
 
 	return sequencer, nil
@@ -76,12 +108,29 @@ func (s *Sequencer) Start(ctx context.Context) {
 		L2ReorgCh:     make(chan L2ReorgEvent),
 	}
 
-	err := s.pool.MarkWIPTxsAsPending(ctx)
-	if err != nil {
-		log.Fatalf("failed to mark WIP txs as pending, err: %v", err)
+	// the WIP txs are kept as WIP to be restored in the worker by the dbManager
+	if !s.cfg.DBManager.RestoreWorkerOnStart {
+		err := s.pool.MarkWIPTxsAsPending(ctx)
+		if err != nil {
+			log.Fatalf("failed to mark WIP txs as pending, err: %v", err)
+		}
 	}
 
-	worker := NewWorker(s.state, s.batchCfg.Constraints)
+	worker := NewWorker(s.cfg, s.poolCfg, s.state, s.batchCfg.Constraints)
+	worker.setTxSorter(s.txSorter)
+	if s.cfg.SelectionAudit.Enabled {
+		worker.selectionAudit = newSelectionAuditor(s.cfg.SelectionAudit, s.pool)
+		go worker.selectionAudit.Start(ctx)
+	}
+	s.worker.Store(worker)
+	if s.cfg.Policy.Enabled {
+		if err := s.reloadPolicy(ctx, worker); err != nil {
+			log.Fatalf("failed to load sequencer policy, err: %v", err)
+		}
+	}
+	if s.cfg.Repricing.Enabled {
+		go s.repriceWorkerTxs(ctx, worker)
+	}
 	dbManager := newDBManager(ctx, s.cfg.DBManager, s.pool, s.state, worker, closingSignalCh, s.batchCfg.Constraints)
 
 	// Start stream server if enabled
@@ -102,9 +151,12 @@ func (s *Sequencer) Start(ctx context.Context) {
 
 	go dbManager.Start()
 
-	finalizer := newFinalizer(s.cfg.Finalizer, s.poolCfg, worker, dbManager, s.state, s.address, s.isSynced, closingSignalCh, s.batchCfg.Constraints, s.eventLog)
+	finalizer := newFinalizer(s.cfg.Finalizer, s.poolCfg, worker, dbManager, s.state, s.l2Coinbase, s.isSynced, closingSignalCh, s.batchCfg.Constraints, s.eventLog)
+	finalizer.txSelectedHandlers = s.txSelectedHandlers
+	finalizer.l2ReorgHandlers = s.l2ReorgHandlers
 
 	currBatch, processingReq := s.bootstrap(ctx, dbManager, finalizer)
+	s.finalizer.Store(finalizer)
 	go finalizer.Start(ctx, currBatch, processingReq)
 
 	closingSignalsManager := newClosingSignalsManager(ctx, finalizer.dbManager, closingSignalCh, finalizer.cfg, s.etherman)
@@ -118,11 +170,10 @@ func (s *Sequencer) Start(ctx context.Context) {
 	go func() {
 		for {
 			time.Sleep(s.cfg.TxLifetimeCheckTimeout.Duration)
-			txTrackers := worker.ExpireTransactions(s.cfg.MaxTxLifetime.Duration)
-			failedReason := ErrExpiredTransaction.Error()
+			txTrackers := worker.ExpireTransactions(s.cfg.MaxTxLifetime.Duration, s.cfg.MaxTxNotReadyTime.Duration)
 			for _, txTracker := range txTrackers {
-				err := s.pool.UpdateTxStatus(ctx, txTracker.Hash, pool.TxStatusFailed, false, &failedReason)
-				metrics.TxProcessed(metrics.TxProcessedLabelFailed, 1)
+				err := s.pool.UpdateTxStatus(ctx, txTracker.Hash, pool.TxStatusExpired, false, txTracker.FailedReason)
+				metrics.TxProcessed(metrics.TxProcessedLabelExpired, 1)
 				if err != nil {
 					log.Errorf("failed to update tx status, err: %v", err)
 				}
@@ -162,7 +213,7 @@ func (s *Sequencer) bootstrap(ctx context.Context, dbManager *dbManager, finaliz
 		///////////////////
 		// GENESIS Batch //
 		///////////////////
-		processingCtx := dbManager.CreateFirstBatch(ctx, s.address)
+		processingCtx := dbManager.CreateFirstBatch(ctx, s.l2Coinbase)
 		timestamp := processingCtx.Timestamp
 		_, oldStateRoot, err := finalizer.getLastBatchNumAndOldStateRoot(ctx)
 		if err != nil {
@@ -223,6 +274,13 @@ func (s *Sequencer) purgeOldPoolTxs(ctx context.Context) {
 			continue
 		}
 		log.Infof("failed txs deleted from the pool")
+
+		if s.cfg.SelectionAudit.Enabled && s.cfg.SelectionAudit.RetentionPeriod.Duration > 0 {
+			err = s.pool.DeleteSelectionAuditOlderThan(ctx, time.Now().Add(-s.cfg.SelectionAudit.RetentionPeriod.Duration))
+			if err != nil {
+				log.Errorf("failed to delete old selection audit log entries, err: %v", err)
+			}
+		}
 	}
 }
 
@@ -235,6 +293,111 @@ func waitTick(ctx context.Context, ticker *time.Ticker) {
 	}
 }
 
+// IsFinalizerHalted returns true if the finalizer is halted because of a critical error
+func (s *Sequencer) IsFinalizerHalted() bool {
+	f := s.finalizer.Load()
+	return f != nil && f.halted.Load()
+}
+
+// ResumeFinalizer resumes the finalizer after it has been halted by a critical error,
+// it must be called once the operator has reviewed the cause of the halt. The halts caused
+// by a failed sanity check can't be resumed, the sequencer must be restarted
+func (s *Sequencer) ResumeFinalizer() error {
+	f := s.finalizer.Load()
+	if f == nil {
+		return ErrFinalizerNotStarted
+	}
+	return f.resume()
+}
+
+// IsSequencingPaused returns true if the sequencing has been paused by the operator
+func (s *Sequencer) IsSequencingPaused() bool {
+	f := s.finalizer.Load()
+	return f != nil && f.paused.Load()
+}
+
+// PauseSequencing stops selecting txs for the batches until the sequencing is resumed, the
+// batches are still closed by their deadlines so the forced batches keep being processed
+func (s *Sequencer) PauseSequencing() error {
+	f := s.finalizer.Load()
+	if f == nil {
+		return ErrFinalizerNotStarted
+	}
+	if !f.paused.CompareAndSwap(false, true) {
+		return ErrSequencingAlreadyPaused
+	}
+	log.Warn("sequencing paused, no txs are selected until it's resumed")
+	return nil
+}
+
+// ResumeSequencing resumes the selection of txs for the batches after the sequencing was paused
+func (s *Sequencer) ResumeSequencing() error {
+	f := s.finalizer.Load()
+	if f == nil {
+		return ErrFinalizerNotStarted
+	}
+	if !f.paused.CompareAndSwap(true, false) {
+		return ErrSequencingNotPaused
+	}
+	log.Info("sequencing resumed")
+	return nil
+}
+
+// DeleteWorkerTx deletes the tx from the worker, so it's not selected for a batch anymore
+func (s *Sequencer) DeleteWorkerTx(hash common.Hash, from common.Address) error {
+	w := s.worker.Load()
+	if w == nil {
+		return ErrWorkerNotStarted
+	}
+	w.DeleteTx(hash, from)
+	return nil
+}
+
+// GetWorkerTxs returns the hashes of the txs held by the worker split in ready and not ready
+func (s *Sequencer) GetWorkerTxs() (TxsSnapshot, error) {
+	w := s.worker.Load()
+	if w == nil {
+		return TxsSnapshot{}, ErrWorkerNotStarted
+	}
+	return w.GetTxsSnapshot(), nil
+}
+
+// ReloadPolicy reloads the deny lists of the sequencer policy from the pool DB, merged with the ones
+// of the config. The txs held by the worker that are denied by the new policy are set as failed
+func (s *Sequencer) ReloadPolicy(ctx context.Context) error {
+	if !s.cfg.Policy.Enabled {
+		return ErrPolicyNotEnabled
+	}
+	w := s.worker.Load()
+	if w == nil {
+		return ErrWorkerNotStarted
+	}
+	return s.reloadPolicy(ctx, w)
+}
+
+func (s *Sequencer) reloadPolicy(ctx context.Context, worker *Worker) error {
+	lists, err := s.pool.GetPolicyDenyLists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get policy deny lists, err: %w", err)
+	}
+	policy, err := newTxPolicy(s.cfg.Policy, lists)
+	if err != nil {
+		return err
+	}
+
+	txTrackers := worker.UpdatePolicy(policy)
+	for _, txTracker := range txTrackers {
+		err := s.pool.UpdateTxStatus(ctx, txTracker.Hash, pool.TxStatusFailed, false, txTracker.FailedReason)
+		if err != nil {
+			log.Errorf("failed to update status of tx %s denied by the sequencer policy, err: %v", txTracker.HashStr, err)
+		}
+	}
+	log.Infof("sequencer policy loaded, denied senders: %d, recipients: %d, selectors: %d, deleted txs: %d",
+		len(policy.deniedSenders), len(policy.deniedRecipients), len(policy.deniedSelectors), len(txTrackers))
+
+	return nil
+}
+
 func (s *Sequencer) isSynced(ctx context.Context) bool {
 	lastSyncedBatchNum, err := s.state.GetLastVirtualBatchNum(ctx, nil)
 	if err != nil && err != state.ErrNotFound {
@@ -0,0 +1,30 @@
+package sequencer
+
+import (
+	"fmt"
+	"time"
+)
+
+// validateSequencingWindows checks the sequencing windows have a tag and end after they start
+func validateSequencingWindows(windows []SequencingWindowCfg) error {
+	for i, window := range windows {
+		if window.Tag == "" {
+			return fmt.Errorf("sequencing window %d has no tag", i)
+		}
+		if window.End.Duration <= window.Start.Duration {
+			return fmt.Errorf("sequencing window %d of tag %s: end %v must be after start %v", i, window.Tag, window.End.Duration, window.Start.Duration)
+		}
+	}
+	return nil
+}
+
+// activeSequencingWindowTag returns the tag of the first sequencing window active at the elapsed
+// time since the batch was opened, and false if there isn't any active window
+func activeSequencingWindowTag(windows []SequencingWindowCfg, elapsed time.Duration) (string, bool) {
+	for _, window := range windows {
+		if elapsed >= window.Start.Duration && elapsed < window.End.Duration {
+			return window.Tag, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,63 @@
+package sequencer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActiveSequencingWindowTag(t *testing.T) {
+	windows := []SequencingWindowCfg{
+		{Tag: "batch-settlement", Start: types.NewDuration(0), End: types.NewDuration(2 * time.Second)},
+		{Tag: "oracle", Start: types.NewDuration(5 * time.Second), End: types.NewDuration(6 * time.Second)},
+	}
+	require.NoError(t, validateSequencingWindows(windows))
+
+	testCases := []struct {
+		elapsed     time.Duration
+		expectedTag string
+		found       bool
+	}{
+		{0, "batch-settlement", true},
+		{time.Second, "batch-settlement", true},
+		{2 * time.Second, "", false},
+		{5 * time.Second, "oracle", true},
+		{7 * time.Second, "", false},
+	}
+	for _, testCase := range testCases {
+		tag, found := activeSequencingWindowTag(windows, testCase.elapsed)
+		assert.Equal(t, testCase.expectedTag, tag, testCase.elapsed)
+		assert.Equal(t, testCase.found, found, testCase.elapsed)
+	}
+
+	assert.Error(t, validateSequencingWindows([]SequencingWindowCfg{{End: types.NewDuration(time.Second)}}))
+	assert.Error(t, validateSequencingWindows([]SequencingWindowCfg{{Tag: "oracle", Start: types.NewDuration(time.Second), End: types.NewDuration(time.Second)}}))
+}
+
+func TestWorkerGetBestFittingTaggedTx(t *testing.T) {
+	ctx := context.Background()
+	worker := initWorker(NewStateMock(t), rcMax)
+
+	newTx := func(from common.Address, gasPrice int64, tag string) *TxTracker {
+		return &TxTracker{Hash: common.Hash{from[0]}, HashStr: common.Hash{from[0]}.String(), From: from, FromStr: from.String(), Nonce: 1,
+			GasPrice: new(big.Int).SetInt64(gasPrice), Cost: new(big.Int).SetInt64(1), IP: validIP, Tag: tag}
+	}
+	for _, tx := range []*TxTracker{newTx(common.Address{1}, 10, ""), newTx(common.Address{2}, 5, "batch-settlement"), newTx(common.Address{3}, 1, "oracle")} {
+		worker.pool[tx.FromStr] = newAddrQueue(tx.From, 1, new(big.Int).SetInt64(10))
+		_, err := worker.AddTxTracker(ctx, tx)
+		require.NoError(t, err)
+	}
+
+	resources := state.BatchResources{Bytes: rcMax.MaxBatchBytesSize}
+	assert.Equal(t, common.Hash{1}, worker.GetBestFittingTx(resources).Hash)
+	assert.Equal(t, common.Hash{2}, worker.GetBestFittingTaggedTx(resources, "batch-settlement").Hash)
+	assert.Equal(t, common.Hash{3}, worker.GetBestFittingTaggedTx(resources, "oracle").Hash)
+	assert.Nil(t, worker.GetBestFittingTaggedTx(resources, "unknown"))
+}
@@ -0,0 +1,87 @@
+package sequencer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// Stop stops the sequencer gracefully: no more txs are selected, the tx being processed is finished,
+// the processed txs are stored, the open batch is closed if it has txs and the WIP txs of the worker
+// are returned to the pool as pending. It returns an error if the context is done before
+func (s *Sequencer) Stop(ctx context.Context) error {
+	f := s.finalizer.Load()
+	if f == nil {
+		// the sequencer is waiting for the synchronizer, there is no batch open
+		return nil
+	}
+	if err := f.stop(ctx); err != nil {
+		return err
+	}
+
+	// the WIP txs are kept as WIP to be restored in the worker by the dbManager on restart
+	if !s.cfg.DBManager.RestoreWorkerOnStart {
+		if err := s.pool.MarkWIPTxsAsPending(ctx); err != nil {
+			return fmt.Errorf("failed to mark WIP txs as pending, err: %w", err)
+		}
+	}
+	log.Info("sequencer stopped")
+	return nil
+}
+
+// stop asks the finalizer to stop and waits until it's stopped or the context is done
+func (f *finalizer) stop(ctx context.Context) error {
+	f.stopOnce.Do(func() { close(f.stopCh) })
+	select {
+	case <-f.stoppedCh:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("finalizer not stopped, the open batch may not be closed, err: %w", ctx.Err())
+	}
+}
+
+// isStopping returns true once the finalizer has been asked to stop
+func (f *finalizer) isStopping() bool {
+	select {
+	case <-f.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// stopFinalizing finishes the open batch when the finalizer is stopped. The processed txs are stored
+// and the batch is closed if it has txs, so it's not left half-closed. The empty batch is kept open,
+// as well as the batch that fails to be closed, since the state is rolled back to its stored txs and
+// the finalizer resumes it on restart. The batch isn't closed either when the finalizer is halted by an
+// error that can't be resumed (e.g. a failed sanity check) or when one of its processed txs failed to be stored
+func (f *finalizer) stopFinalizing(ctx context.Context) {
+	defer close(f.stoppedCh)
+	f.sharedResourcesMux.Lock()
+	defer f.sharedResourcesMux.Unlock()
+
+	log.Infof("stopping finalizer, waiting for the processed txs to be stored")
+	f.pendingTransactionsToStoreWG.Wait()
+
+	if f.haltNotResumable.Load() {
+		log.Warnf("finalizer stopped while halted by an error that can't be resumed, batch %d kept open", f.batch.batchNumber)
+		return
+	}
+	if f.txsNotStored.Load() {
+		log.Warnf("finalizer stopped with processed txs not stored, batch %d kept open with its stored txs", f.batch.batchNumber)
+		return
+	}
+	if f.batch.isEmpty() {
+		log.Infof("finalizer stopped, empty batch %d kept open", f.batch.batchNumber)
+		return
+	}
+
+	f.batch.closingReason = state.GracefulShutdownClosingReason
+	if err := f.closeBatch(ctx); err != nil {
+		log.Errorf("failed to close batch %d while stopping the finalizer, it's kept open with its stored txs, err: %v", f.batch.batchNumber, err)
+		return
+	}
+	log.Infof("finalizer stopped, batch %d closed with %d txs", f.batch.batchNumber, f.batch.countOfTxs)
+}
@@ -0,0 +1,114 @@
+package sequencer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalizer_stop(t *testing.T) {
+	t.Run("empty batch kept open", func(t *testing.T) {
+		f = setupFinalizer(true)
+		go func() {
+			<-f.stopCh
+			f.stopFinalizing(ctx)
+		}()
+
+		require.NoError(t, f.stop(ctx))
+		assert.True(t, f.isStopping())
+		assert.Equal(t, state.EmptyClosingReason, f.batch.closingReason)
+		dbManagerMock.AssertExpectations(t)
+	})
+
+	t.Run("batch with txs closed", func(t *testing.T) {
+		f = setupFinalizer(true)
+		f.batch.countOfTxs = 1
+		txs := make([]types.Transaction, 0)
+		dbManagerMock.On("GetTransactionsByBatchNumber", ctx, f.batch.batchNumber).Return(txs, []uint8{}, nilErr).Once()
+		dbManagerMock.On("CloseBatch", ctx, ClosingBatchParameters{
+			BatchNumber:          f.batch.batchNumber,
+			StateRoot:            f.batch.stateRoot,
+			LocalExitRoot:        f.batch.localExitRoot,
+			Txs:                  txs,
+			EffectivePercentages: []uint8{},
+			BatchResources:       getUsedBatchResources(f.batchConstraints, f.batch.remainingResources),
+			ClosingReason:        state.GracefulShutdownClosingReason,
+		}).Return(nilErr).Once()
+
+		f.stopOnce.Do(func() { close(f.stopCh) })
+		f.stopFinalizing(ctx)
+		require.NoError(t, f.stop(ctx))
+		dbManagerMock.AssertExpectations(t)
+	})
+
+	t.Run("batch kept open when it fails to close", func(t *testing.T) {
+		f = setupFinalizer(true)
+		f.batch.countOfTxs = 1
+		dbManagerMock.On("GetTransactionsByBatchNumber", ctx, f.batch.batchNumber).Return(nil, nil, fmt.Errorf("some err")).Once()
+
+		f.stopOnce.Do(func() { close(f.stopCh) })
+		f.stopFinalizing(ctx)
+		require.NoError(t, f.stop(ctx))
+		dbManagerMock.AssertExpectations(t)
+	})
+
+	t.Run("batch kept open when halted by an error that can't be resumed", func(t *testing.T) {
+		f = setupFinalizer(true)
+		f.batch.countOfTxs = 1
+		f.haltNotResumable.Store(true)
+
+		f.stopOnce.Do(func() { close(f.stopCh) })
+		f.stopFinalizing(ctx)
+		require.NoError(t, f.stop(ctx))
+		assert.Equal(t, state.EmptyClosingReason, f.batch.closingReason)
+		dbManagerMock.AssertExpectations(t)
+	})
+
+	t.Run("batch kept open when a processed tx fails to be stored", func(t *testing.T) {
+		f = setupFinalizer(true)
+		f.cfg.HaltPolicy = HaltPolicyCfg{Mode: HaltPolicyModeHalt}
+		f.batch.countOfTxs = 1
+		txToStore := transactionToStore{
+			batchNumber: f.batch.batchNumber,
+			coinbase:    seqAddr,
+			response:    &state.ProcessTransactionResponse{TxHash: txHash},
+		}
+		dbManagerMock.On("StoreProcessedTx", ctx, txToStore).Return(testErr)
+
+		f.pendingTransactionsToStoreWG.Add(1)
+		go func() {
+			defer f.pendingTransactionsToStoreWG.Done()
+			f.storeProcessedTx(ctx, txToStore)
+		}()
+		require.Eventually(t, f.halted.Load, time.Second, time.Millisecond)
+		go func() {
+			<-f.stopCh
+			f.stopFinalizing(ctx)
+		}()
+
+		// the failed store isn't retried nor halted again once the finalizer is stopping
+		timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, f.stop(timeoutCtx))
+		assert.True(t, f.txsNotStored.Load())
+		assert.Equal(t, state.EmptyClosingReason, f.batch.closingReason)
+		dbManagerMock.AssertNotCalled(t, "UpdateTxStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		dbManagerMock.AssertExpectations(t)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		f = setupFinalizer(true)
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		err := f.stop(timeoutCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
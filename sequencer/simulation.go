@@ -0,0 +1,65 @@
+package sequencer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+)
+
+// candidateSimulation is the simulation of a candidate tx, executed without updating the merkle tree against
+// the WIP state root with the system changes applied by its inclusion: the GER, only applied with the first
+// tx of the batch, and the batch timestamp
+type candidateSimulation struct {
+	request state.ProcessRequest
+	result  *state.ProcessBatchResponse
+}
+
+// simulateCandidateTx is the first phase of the inclusion of the candidate tx, the request must be ready to be
+// processed. The txs failing without changing the state root (intrinsic and OOC errors) or not fitting the
+// remaining resources of the batch are handled with the simulation and the error is returned. It returns nil
+// if the executor fails to simulate the tx, the execution updating the merkle tree handles the failure
+func (f *finalizer) simulateCandidateTx(ctx context.Context, tx *TxTracker) (*candidateSimulation, *sync.WaitGroup, error) {
+	result, err := f.executor.ProcessBatch(ctx, f.processRequest, false)
+	if err != nil || result.IsExecutorLevelError || len(result.Responses) == 0 {
+		log.Debugf("failed to simulate tx %s, processing it without simulation, err: %v", tx.HashStr, err)
+		return nil, nil, nil
+	}
+
+	txErr := result.Responses[0].RomError
+	if !state.IsStateRootChanged(executor.RomErrorCode(txErr)) {
+		log.Infof("simulation of tx %s failed without changing the state root, err: %v", tx.HashStr, txErr)
+		metrics.CandidateSimulation(metrics.CandidateSimulationLabelRejected)
+		return nil, f.handleProcessTransactionError(ctx, result, tx), txErr
+	}
+
+	remainingResources := f.batch.remainingResources
+	if err := remainingResources.Sub(state.BatchResources{ZKCounters: result.UsedZkCounters, Bytes: uint64(len(tx.RawTx))}); err != nil {
+		log.Infof("simulation of tx %s exceeds the remaining resources of the batch, err: %v", tx.HashStr, err)
+		metrics.CandidateSimulation(metrics.CandidateSimulationLabelRejected)
+		// the resources are checked again to update the counters of the tx in the worker or quarantine it
+		return nil, nil, f.checkRemainingResources(result, tx)
+	}
+
+	return &candidateSimulation{request: f.processRequest, result: result}, nil, nil
+}
+
+// checkConflict compares the execution of the candidate tx updating the merkle tree with its simulation, it returns
+// true if they differ because the state or the system changes the tx was simulated against changed in between
+func (s *candidateSimulation) checkConflict(request state.ProcessRequest, result *state.ProcessBatchResponse) bool {
+	conflict := s.request.BatchNumber != request.BatchNumber || s.request.OldStateRoot != request.OldStateRoot ||
+		s.request.GlobalExitRoot != request.GlobalExitRoot || !s.request.Timestamp.Equal(request.Timestamp) ||
+		s.result.NewStateRoot != result.NewStateRoot ||
+		(len(result.Responses) > 0 && executor.RomErrorCode(result.Responses[0].RomError) != executor.RomErrorCode(s.result.Responses[0].RomError))
+	if conflict {
+		log.Warnf("execution of tx %s differs from its simulation, simulated state root: %s, state root: %s",
+			s.result.Responses[0].TxHash.String(), s.result.NewStateRoot.String(), result.NewStateRoot.String())
+		metrics.CandidateSimulation(metrics.CandidateSimulationLabelConflict)
+	} else {
+		metrics.CandidateSimulation(metrics.CandidateSimulationLabelPassed)
+	}
+	return conflict
+}
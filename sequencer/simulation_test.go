@@ -0,0 +1,118 @@
+package sequencer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalizer_simulateCandidateTx(t *testing.T) {
+	ctx := context.Background()
+	tx := &TxTracker{Hash: txHash, HashStr: txHash.String(), From: senderAddr, RawTx: []byte{0, 0, 1, 2, 3, 4, 5}}
+	successfulBatchResp := &state.ProcessBatchResponse{
+		NewStateRoot: newHash,
+		Responses:    []*state.ProcessTransactionResponse{{TxHash: txHash, StateRoot: newHash}},
+	}
+
+	t.Run("tx failing without changing the state root is handled with the simulation", func(t *testing.T) {
+		f := setupFinalizer(true)
+		oocBatchResp := &state.ProcessBatchResponse{
+			NewStateRoot: oldHash,
+			Responses:    []*state.ProcessTransactionResponse{{TxHash: txHash, StateRoot: oldHash, RomError: runtime.ErrOutOfCountersKeccak}},
+		}
+		executorMock.On("ProcessBatch", ctx, f.processRequest, false).Return(oocBatchResp, nil).Once()
+		workerMock.On("DeleteTx", txHash, senderAddr).Return().Once()
+		dbManagerMock.On("UpdateTxStatus", ctx, txHash, pool.TxStatusInvalid, false, mock.Anything).Return(nil).Once()
+
+		simulation, errWg, err := f.simulateCandidateTx(ctx, tx)
+		require.ErrorIs(t, err, runtime.ErrOutOfCountersKeccak)
+		require.NotNil(t, errWg)
+		errWg.Wait()
+		assert.Nil(t, simulation)
+		executorMock.AssertNotCalled(t, "ProcessBatch", ctx, mock.Anything, true)
+		workerMock.AssertExpectations(t)
+		dbManagerMock.AssertExpectations(t)
+	})
+
+	t.Run("tx exceeding the remaining resources is handled with the simulation", func(t *testing.T) {
+		f := setupFinalizer(true)
+		f.batch.countOfTxs = 1
+		f.batch.remainingResources.ZKCounters.UsedKeccakHashes = 1
+		remainingResources := f.batch.remainingResources
+		batchResp := &state.ProcessBatchResponse{
+			NewStateRoot:   newHash,
+			UsedZkCounters: state.ZKCounters{UsedKeccakHashes: 2},
+			Responses:      []*state.ProcessTransactionResponse{{TxHash: txHash, StateRoot: newHash}},
+		}
+		executorMock.On("ProcessBatch", ctx, f.processRequest, false).Return(batchResp, nil).Once()
+		workerMock.On("UpdateTxZKCounters", txHash, senderAddr, batchResp.UsedZkCounters).Return().Once()
+		dbManagerMock.On("UpdateTxZKCounters", mock.Anything, txHash, batchResp.UsedZkCounters).Return(nil).Once()
+
+		simulation, _, err := f.simulateCandidateTx(ctx, tx)
+		require.Error(t, err)
+		assert.Nil(t, simulation)
+		assert.Equal(t, remainingResources, f.batch.remainingResources)
+		workerMock.AssertExpectations(t)
+		dbManagerMock.AssertExpectations(t)
+	})
+
+	t.Run("successful simulation doesn't consume the remaining resources", func(t *testing.T) {
+		f := setupFinalizer(true)
+		remainingResources := f.batch.remainingResources
+		executorMock.On("ProcessBatch", ctx, f.processRequest, false).Return(successfulBatchResp, nil).Once()
+
+		simulation, errWg, err := f.simulateCandidateTx(ctx, tx)
+		require.NoError(t, err)
+		assert.Nil(t, errWg)
+		require.NotNil(t, simulation)
+		assert.Equal(t, remainingResources, f.batch.remainingResources)
+		assert.False(t, simulation.checkConflict(f.processRequest, successfulBatchResp))
+	})
+
+	t.Run("failed simulation is skipped", func(t *testing.T) {
+		f := setupFinalizer(true)
+		executorMock.On("ProcessBatch", ctx, f.processRequest, false).Return(nil, testErr).Once()
+
+		simulation, errWg, err := f.simulateCandidateTx(ctx, tx)
+		require.NoError(t, err)
+		assert.Nil(t, errWg)
+		assert.Nil(t, simulation)
+	})
+}
+
+func TestCandidateSimulation_checkConflict(t *testing.T) {
+	request := state.ProcessRequest{BatchNumber: 1, OldStateRoot: oldHash, GlobalExitRoot: oldHash}
+	simulation := &candidateSimulation{
+		request: request,
+		result: &state.ProcessBatchResponse{
+			NewStateRoot: newHash,
+			Responses:    []*state.ProcessTransactionResponse{{TxHash: txHash, StateRoot: newHash}},
+		},
+	}
+
+	assert.False(t, simulation.checkConflict(request, simulation.result))
+
+	// the GER applied with the first tx of the batch changed
+	changedGER := request
+	changedGER.GlobalExitRoot = common.HexToHash("0x03")
+	assert.True(t, simulation.checkConflict(changedGER, simulation.result))
+
+	// the execution reached a different state
+	assert.True(t, simulation.checkConflict(request, &state.ProcessBatchResponse{
+		NewStateRoot: common.HexToHash("0x04"),
+		Responses:    []*state.ProcessTransactionResponse{{TxHash: txHash, StateRoot: common.HexToHash("0x04")}},
+	}))
+
+	// the execution failed
+	assert.True(t, simulation.checkConflict(request, &state.ProcessBatchResponse{
+		NewStateRoot: newHash,
+		Responses:    []*state.ProcessTransactionResponse{{TxHash: txHash, StateRoot: newHash, RomError: runtime.ErrOutOfGas}},
+	}))
+}
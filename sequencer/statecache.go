@@ -0,0 +1,190 @@
+package sequencer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// cachedValue is a value cached by the stateCache with its expiration time
+type cachedValue[T any] struct {
+	value     T
+	expiresAt time.Time
+	valid     bool
+}
+
+func (c *cachedValue[T]) get() (T, bool) {
+	if !c.valid || !now().Before(c.expiresAt) {
+		var empty T
+		return empty, false
+	}
+	return c.value, true
+}
+
+func (c *cachedValue[T]) set(value T, ttl time.Duration) {
+	c.value = value
+	c.expiresAt = now().Add(ttl)
+	c.valid = true
+}
+
+func (c *cachedValue[T]) invalidate() {
+	var empty T
+	c.value = empty
+	c.valid = false
+}
+
+// cachedGER is the latest GER cached for a given finality number of blocks
+type cachedGER struct {
+	finalityNumberOfBlocks uint64
+	ger                    state.GlobalExitRoot
+	receivedAt             time.Time
+}
+
+// stateCache is a read-through cache of the state values queried by the finalizer loop. The values
+// are invalidated when the dbManager modifies them or detects that another component did it (e.g. the
+// GER updated by the synchronizer), and expire after the ttl to bound the staleness of the changes that
+// aren't detected. The fills read the generation before querying the state and are discarded if the
+// cache has been invalidated meanwhile, so an old value isn't stored again after its invalidation.
+// The batch constraints aren't cached, they come from the config and are already kept in memory
+type stateCache struct {
+	ttl               time.Duration
+	mutex             sync.Mutex
+	generation        uint64
+	lastBatchNumber   cachedValue[uint64]
+	lastL2BlockHeader cachedValue[*types.Header]
+	latestGER         cachedValue[cachedGER]
+}
+
+// newStateCache creates a new stateCache, a ttl of zero disables the cache
+func newStateCache(ttl time.Duration) *stateCache {
+	return &stateCache{ttl: ttl}
+}
+
+func (c *stateCache) enabled() bool {
+	return c != nil && c.ttl > 0
+}
+
+// currentGeneration returns the generation of the cache, it must be read before querying the
+// state for the value to fill
+func (c *stateCache) currentGeneration() uint64 {
+	if !c.enabled() {
+		return 0
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.generation
+}
+
+func (c *stateCache) getLastBatchNumber() (uint64, bool) {
+	if !c.enabled() {
+		return 0, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lastBatchNumber.get()
+}
+
+func (c *stateCache) setLastBatchNumber(generation uint64, batchNumber uint64) {
+	if !c.enabled() {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.lastBatchNumber.set(batchNumber, c.ttl)
+}
+
+func (c *stateCache) getLastL2BlockHeader() (*types.Header, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	header, found := c.lastL2BlockHeader.get()
+	if !found {
+		return nil, false
+	}
+	// return a copy so the callers can't modify the cached header
+	return types.CopyHeader(header), true
+}
+
+func (c *stateCache) setLastL2BlockHeader(generation uint64, header *types.Header) {
+	if !c.enabled() || header == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.lastL2BlockHeader.set(types.CopyHeader(header), c.ttl)
+}
+
+func (c *stateCache) getLatestGER(finalityNumberOfBlocks uint64) (state.GlobalExitRoot, time.Time, bool) {
+	if !c.enabled() {
+		return state.GlobalExitRoot{}, time.Time{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ger, found := c.latestGER.get()
+	if !found || ger.finalityNumberOfBlocks != finalityNumberOfBlocks {
+		return state.GlobalExitRoot{}, time.Time{}, false
+	}
+	return ger.ger, ger.receivedAt, true
+}
+
+func (c *stateCache) setLatestGER(generation uint64, finalityNumberOfBlocks uint64, ger state.GlobalExitRoot, receivedAt time.Time) {
+	if !c.enabled() {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.latestGER.set(cachedGER{finalityNumberOfBlocks: finalityNumberOfBlocks, ger: ger, receivedAt: receivedAt}, c.ttl)
+}
+
+// invalidateBatches invalidates the values that change when a batch is opened, closed or a tx is stored
+func (c *stateCache) invalidateBatches() {
+	if !c.enabled() {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generation++
+	c.lastBatchNumber.invalidate()
+	c.lastL2BlockHeader.invalidate()
+}
+
+// invalidateGERIfChanged invalidates the cached GER if it differs from the latest GER read from the state
+func (c *stateCache) invalidateGERIfChanged(ger common.Hash) {
+	if !c.enabled() {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.latestGER.valid && c.latestGER.value.ger.GlobalExitRoot == ger {
+		return
+	}
+	c.generation++
+	c.latestGER.invalidate()
+}
+
+// invalidateAll invalidates all the cached values
+func (c *stateCache) invalidateAll() {
+	if !c.enabled() {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generation++
+	c.lastBatchNumber.invalidate()
+	c.lastL2BlockHeader.invalidate()
+	c.latestGER.invalidate()
+}
@@ -0,0 +1,160 @@
+package sequencer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStateCache(t *testing.T) {
+	now = testNow
+	defer func() {
+		now = time.Now
+	}()
+
+	c := newStateCache(time.Second)
+
+	_, found := c.getLastBatchNumber()
+	assert.False(t, found)
+
+	c.setLastBatchNumber(c.currentGeneration(), 10)
+	c.setLastL2BlockHeader(c.currentGeneration(), &types.Header{Number: big.NewInt(20)})
+	ger := state.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x1")}
+	c.setLatestGER(c.currentGeneration(), 64, ger, testNow())
+
+	batchNumber, found := c.getLastBatchNumber()
+	assert.True(t, found)
+	assert.Equal(t, uint64(10), batchNumber)
+
+	header, found := c.getLastL2BlockHeader()
+	assert.True(t, found)
+	assert.Equal(t, uint64(20), header.Number.Uint64())
+
+	cachedGER, _, found := c.getLatestGER(64)
+	assert.True(t, found)
+	assert.Equal(t, ger.GlobalExitRoot, cachedGER.GlobalExitRoot)
+	_, _, found = c.getLatestGER(32)
+	assert.False(t, found)
+
+	// the batch related values are invalidated, the GER is kept
+	c.invalidateBatches()
+	_, found = c.getLastBatchNumber()
+	assert.False(t, found)
+	_, found = c.getLastL2BlockHeader()
+	assert.False(t, found)
+	_, _, found = c.getLatestGER(64)
+	assert.True(t, found)
+
+	// the values expire after the ttl
+	c.setLastBatchNumber(c.currentGeneration(), 11)
+	now = func() time.Time {
+		return testNow().Add(2 * time.Second)
+	}
+	_, found = c.getLastBatchNumber()
+	assert.False(t, found)
+	_, _, found = c.getLatestGER(64)
+	assert.False(t, found)
+}
+
+func TestStateCacheDisabled(t *testing.T) {
+	c := newStateCache(0)
+	c.setLastBatchNumber(c.currentGeneration(), 10)
+	_, found := c.getLastBatchNumber()
+	assert.False(t, found)
+}
+
+func TestStateCacheDiscardsFillsOlderThanTheInvalidation(t *testing.T) {
+	c := newStateCache(time.Minute)
+
+	// the fill started before the invalidation is discarded
+	generation := c.currentGeneration()
+	c.invalidateBatches()
+	c.setLastBatchNumber(generation, 10)
+	_, found := c.getLastBatchNumber()
+	assert.False(t, found)
+
+	generation = c.currentGeneration()
+	c.setLastBatchNumber(generation, 11)
+	batchNumber, found := c.getLastBatchNumber()
+	assert.True(t, found)
+	assert.Equal(t, uint64(11), batchNumber)
+}
+
+func TestDBManagerGetLatestGerUpdate(t *testing.T) {
+	ctx := context.Background()
+	stateMock := NewStateMock(t)
+	d := &dbManager{ctx: ctx, state: stateMock, cache: newStateCache(time.Minute)}
+	ger := state.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x1")}
+	d.cache.setLatestGER(d.cache.currentGeneration(), 64, ger, time.Now())
+
+	// the GER is not updated, the cached one is kept
+	stateMock.On("GetLatestGer", ctx, uint64(100)).Return(ger, time.Now(), nil).Once()
+	_, _, err := d.GetLatestGerUpdate(ctx, 100)
+	assert.NoError(t, err)
+	_, _, found := d.cache.getLatestGER(64)
+	assert.True(t, found)
+
+	// the synchronizer stored a new GER, the cached one is invalidated
+	newGER := state.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x2")}
+	stateMock.On("GetLatestGer", ctx, uint64(101)).Return(newGER, time.Now(), nil).Once()
+	latestGER, _, err := d.GetLatestGerUpdate(ctx, 101)
+	assert.NoError(t, err)
+	assert.Equal(t, newGER.GlobalExitRoot, latestGER.GlobalExitRoot)
+	_, _, found = d.cache.getLatestGER(64)
+	assert.False(t, found)
+}
+
+func TestDBManagerCheckL1Reorgs(t *testing.T) {
+	stateMock := NewStateMock(t)
+	d := &dbManager{ctx: context.Background(), state: stateMock, numberOfL1Reorgs: 1, cache: newStateCache(time.Minute)}
+	ger := state.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x1")}
+	d.cache.setLatestGER(d.cache.currentGeneration(), 64, ger, time.Now())
+
+	// no new L1 reorg, the cached GER is kept
+	stateMock.On("CountL1Reorgs", mock.Anything, mock.Anything).Return(uint64(1), nil).Once()
+	d.checkL1Reorgs()
+	_, _, found := d.cache.getLatestGER(64)
+	assert.True(t, found)
+
+	// the GER may belong to the reorged blocks, it's invalidated
+	stateMock.On("CountL1Reorgs", mock.Anything, mock.Anything).Return(uint64(2), nil).Once()
+	d.checkL1Reorgs()
+	_, _, found = d.cache.getLatestGER(64)
+	assert.False(t, found)
+	assert.Equal(t, uint64(2), d.numberOfL1Reorgs)
+}
+
+func TestDBManagerOpenBatch(t *testing.T) {
+	ctx := context.Background()
+	stateMock := NewStateMock(t)
+	dbTxMock := NewDbTxMock(t)
+	d := &dbManager{ctx: ctx, state: stateMock, cache: newStateCache(time.Minute)}
+	processingCtx := state.ProcessingContext{BatchNumber: 11}
+
+	// the batch number read before the commit is the previous one, it's invalidated once committed
+	stateMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nil).Once()
+	stateMock.On("OpenBatch", ctx, processingCtx, dbTxMock).Return(nil).Once()
+	dbTxMock.On("Commit", ctx).Run(func(args mock.Arguments) {
+		d.cache.setLastBatchNumber(d.cache.currentGeneration(), 10)
+	}).Return(nil).Once()
+	assert.NoError(t, d.OpenBatch(ctx, processingCtx))
+	_, found := d.cache.getLastBatchNumber()
+	assert.False(t, found)
+
+	// the cache is kept if the batch isn't opened
+	d.cache.setLastBatchNumber(d.cache.currentGeneration(), 10)
+	stateMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nil).Once()
+	stateMock.On("OpenBatch", ctx, processingCtx, dbTxMock).Return(testErr).Once()
+	dbTxMock.On("Rollback", ctx).Return(nil).Once()
+	assert.ErrorIs(t, d.OpenBatch(ctx, processingCtx), testErr)
+	batchNumber, found := d.cache.getLastBatchNumber()
+	assert.True(t, found)
+	assert.Equal(t, uint64(10), batchNumber)
+}
@@ -0,0 +1,55 @@
+package sequencer
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TxSelectedEvent is triggered when the worker hands a tx to the finalizer to be added to
+// the WIP batch, before the tx is executed. The tx can still be rejected by its execution,
+// so the position is the one the tx takes in the batch only if it's added to it
+type TxSelectedEvent struct {
+	BatchNumber uint64
+	Position    uint64
+	Hash        common.Hash
+	From        common.Address
+	Nonce       uint64
+	GasPrice    *big.Int
+	ZKCounters  state.ZKCounters
+	SelectedAt  time.Time
+}
+
+// TxSelectedEventHandler handles the txs selected for the batches, it's called from the
+// finalizer loop so it must not block
+type TxSelectedEventHandler func(e TxSelectedEvent)
+
+// RegisterTxSelectedEventHandler adds a handler of the txs selected for the batches, the
+// handlers must be registered before the sequencer is started
+func (s *Sequencer) RegisterTxSelectedEventHandler(h TxSelectedEventHandler) {
+	s.txSelectedHandlers = append(s.txSelectedHandlers, h)
+}
+
+// notifyTxSelected triggers the event of the tx selected for the WIP batch
+func (f *finalizer) notifyTxSelected(tx *TxTracker) {
+	if len(f.txSelectedHandlers) == 0 {
+		return
+	}
+	e := TxSelectedEvent{
+		BatchNumber: f.batch.batchNumber,
+		Position:    uint64(f.batch.countOfTxs),
+		Hash:        tx.Hash,
+		From:        tx.From,
+		Nonce:       tx.Nonce,
+		ZKCounters:  tx.BatchResources.ZKCounters,
+		SelectedAt:  now(),
+	}
+	if tx.GasPrice != nil {
+		e.GasPrice = new(big.Int).Set(tx.GasPrice)
+	}
+	for _, h := range f.txSelectedHandlers {
+		h(e)
+	}
+}
@@ -0,0 +1,43 @@
+package sequencer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalizer_notifyTxSelected(t *testing.T) {
+	f := setupFinalizer(true)
+	// no handlers registered
+	f.notifyTxSelected(&TxTracker{Hash: txHash, From: senderAddr})
+
+	var events []TxSelectedEvent
+	f.txSelectedHandlers = []TxSelectedEventHandler{func(e TxSelectedEvent) { events = append(events, e) }}
+	f.batch.countOfTxs = 3
+	counters := state.ZKCounters{CumulativeGasUsed: 21000, UsedKeccakHashes: 2, UsedSteps: 100}
+	tx := &TxTracker{
+		Hash:           txHash,
+		From:           senderAddr,
+		Nonce:          7,
+		GasPrice:       big.NewInt(1000),
+		BatchResources: state.BatchResources{ZKCounters: counters},
+	}
+
+	f.notifyTxSelected(tx)
+	require.Len(t, events, 1)
+	e := events[0]
+	assert.Equal(t, f.batch.batchNumber, e.BatchNumber)
+	assert.Equal(t, uint64(3), e.Position)
+	assert.Equal(t, txHash, e.Hash)
+	assert.Equal(t, senderAddr, e.From)
+	assert.Equal(t, uint64(7), e.Nonce)
+	assert.Equal(t, counters, e.ZKCounters)
+	assert.False(t, e.SelectedAt.IsZero())
+
+	// the event doesn't share the gas price of the tracker, which can be bumped by the worker
+	tx.GasPrice.SetInt64(2000)
+	assert.Equal(t, big.NewInt(1000), e.GasPrice)
+}
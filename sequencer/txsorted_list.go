@@ -2,24 +2,44 @@ package sequencer
 
 import (
 	"fmt"
-	"sort"
+	"math/rand"
 	"sync"
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
 )
 
-// txSortedList represents a list of tx sorted by gasPrice
+// txSortedList represents a list of tx sorted by the TxSorter policy. It's implemented as a treap where
+// each node keeps the min value of each resource used by the txs of its subtree, so the subtrees without
+// any tx fitting in the remaining resources of the batch are skipped when looking for the best fitting tx.
+// The add and delete are O(log n), the search isn't, see findFirstFitting
 type txSortedList struct {
-	list   map[string]*TxTracker
-	sorted []*TxTracker
-	mutex  sync.Mutex
+	list   map[string]*txSortedListNode
+	root   *txSortedListNode
+	sorter TxSorter
+	// seq is the insertion sequence, used to keep the txs sorted equal in the order they were added
+	seq   uint64
+	mutex sync.Mutex
+}
+
+// txSortedListNode is a node of the txSortedList treap
+type txSortedListNode struct {
+	tx       *TxTracker
+	seq      uint64
+	priority uint64
+	left     *txSortedListNode
+	right    *txSortedListNode
+	// size is the number of txs in the subtree
+	size int
+	// minResources has the min value of each resource used by the txs in the subtree
+	minResources state.BatchResources
 }
 
 // newTxSortedList creates and init an txSortedList
-func newTxSortedList() *txSortedList {
+func newTxSortedList(sorter TxSorter) *txSortedList {
 	return &txSortedList{
-		list:   make(map[string]*TxTracker),
-		sorted: []*TxTracker{},
+		list:   make(map[string]*txSortedListNode),
+		sorter: sorter,
 	}
 }
 
@@ -29,8 +49,14 @@ func (e *txSortedList) add(tx *TxTracker) bool {
 	defer e.mutex.Unlock()
 
 	if _, found := e.list[tx.HashStr]; !found {
-		e.list[tx.HashStr] = tx
-		e.addSort(tx)
+		e.seq++
+		node := &txSortedListNode{tx: tx, seq: e.seq, priority: rand.Uint64()} //nolint:gosec
+		node.update()
+		e.list[tx.HashStr] = node
+
+		left, right := e.split(e.root, node)
+		e.root = e.merge(e.merge(left, node), right)
+		log.Infof("Added tx(%s) to txSortedList. With gasPrice(%d) from total(%d)", tx.HashStr, tx.GasPrice, e.root.size)
 		return true
 	}
 	return false
@@ -41,40 +67,14 @@ func (e *txSortedList) delete(tx *TxTracker) bool {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
-	if tx, found := e.list[tx.HashStr]; found {
-		sLen := len(e.sorted)
-		i := sort.Search(sLen, func(i int) bool {
-			return e.isGreaterOrEqualThan(tx, e.list[e.sorted[i].HashStr])
-		})
-
-		// i is the index of the first tx that has equal (or lower) gasPrice than the tx. From here we need to go down in the list
-		// looking for the sorted[i].HashStr equal to tx.HashStr to get the index of tx in the sorted slice.
-		// We need to go down until we find the tx or we have a tx with different (lower) gasPrice or we reach the end of the list
-		for {
-			if i == sLen {
-				log.Errorf("Error deleting tx (%s) from txSortedList, we reach the end of the list", tx.HashStr)
-				return false
-			}
-
-			if (e.sorted[i].GasPrice.Cmp(tx.GasPrice)) != 0 {
-				// we have a tx with different (lower) GasPrice than the tx we are looking for, therefore we haven't found the tx
-				log.Errorf("Error deleting tx (%s) from txSortedList, not found in the list of txs with same gasPrice: %s", tx.HashStr)
-				return false
-			}
-
-			if e.sorted[i].HashStr == tx.HashStr {
-				break
-			}
-
-			i = i + 1
+	if node, found := e.list[tx.HashStr]; found {
+		var deleted bool
+		e.root, deleted = e.deleteNode(e.root, node)
+		if !deleted {
+			log.Errorf("Error deleting tx (%s) from txSortedList, not found in the sorted txs", tx.HashStr)
+			return false
 		}
-
 		delete(e.list, tx.HashStr)
-
-		copy(e.sorted[i:], e.sorted[i+1:])
-		e.sorted[sLen-1] = nil
-		e.sorted = e.sorted[:sLen-1]
-
 		return true
 	}
 	return false
@@ -85,9 +85,37 @@ func (e *txSortedList) getByIndex(i int) *TxTracker {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
-	tx := e.sorted[i]
+	node := e.root
+	for node != nil {
+		leftSize := node.left.getSize()
+		if i < leftSize {
+			node = node.left
+		} else if i == leftSize {
+			return node.tx
+		} else {
+			i -= leftSize + 1
+			node = node.right
+		}
+	}
+	return nil
+}
+
+// getBestFitting returns the first tx in the txSortedList that fits in the resources and its
+// position, or nil and -1 if none fits
+func (e *txSortedList) getBestFitting(resources state.BatchResources) (*TxTracker, int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.findFirstFitting(e.root, resources, 0)
+}
 
-	return tx
+// getBestFittingWithTag returns the first tx with the tag in the txSortedList that fits in the
+// resources and its position, or nil and -1 if none fits
+func (e *txSortedList) getBestFittingWithTag(resources state.BatchResources, tag string) (*TxTracker, int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.findFirstFittingWithTag(e.root, resources, tag, 0)
 }
 
 // len returns the length of the txSortedList
@@ -95,9 +123,7 @@ func (e *txSortedList) len() int {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
-	l := len(e.sorted)
-
-	return l
+	return e.root.getSize()
 }
 
 // print prints the contents of the txSortedList
@@ -105,48 +131,186 @@ func (e *txSortedList) Print() {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
-	fmt.Println("Len: ", len(e.sorted))
-	for _, txi := range e.sorted {
-		fmt.Printf("Hash=%s, gasPrice=%d\n", txi.HashStr, txi.GasPrice)
-	}
+	fmt.Println("Len: ", e.root.getSize())
+	e.inOrder(e.root, func(tx *TxTracker) {
+		fmt.Printf("Hash=%s, gasPrice=%d\n", tx.HashStr, tx.GasPrice)
+	})
 }
 
-// addSort adds the tx to the txSortedList in a sorted way
-func (e *txSortedList) addSort(tx *TxTracker) {
-	i := sort.Search(len(e.sorted), func(i int) bool {
-		return e.isGreaterThan(tx, e.list[e.sorted[i].HashStr])
-	})
+// GetSorted returns the sorted list of tx
+func (e *txSortedList) GetSorted() []*TxTracker {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
 
-	e.sorted = append(e.sorted, nil)
-	copy(e.sorted[i+1:], e.sorted[i:])
-	e.sorted[i] = tx
-	log.Infof("Added tx(%s) to txSortedList. With gasPrice(%d) at index(%d) from total(%d)", tx.HashStr, tx.GasPrice, i, len(e.sorted))
+	sorted := make([]*TxTracker, 0, e.root.getSize())
+	e.inOrder(e.root, func(tx *TxTracker) {
+		sorted = append(sorted, tx)
+	})
+	return sorted
 }
 
-// isGreaterThan returns true if the tx1 has greater gasPrice than tx2
-func (e *txSortedList) isGreaterThan(tx1 *TxTracker, tx2 *TxTracker) bool {
-	cmp := tx1.GasPrice.Cmp(tx2.GasPrice)
-	if cmp == 1 {
+// isBefore returns true if the node a is sorted before the node b, the nodes sorted
+// equal by the TxSorter are kept in the order they were added
+func (e *txSortedList) isBefore(a *txSortedListNode, b *txSortedListNode) bool {
+	if e.sorter.IsGreaterThan(a.tx, b.tx) {
 		return true
-	} else {
+	}
+	if e.sorter.IsGreaterThan(b.tx, a.tx) {
 		return false
 	}
+	return a.seq < b.seq
 }
 
-// isGreaterOrEqualThan returns true if the tx1 has greater or equal gasPrice than tx2
-func (e *txSortedList) isGreaterOrEqualThan(tx1 *TxTracker, tx2 *TxTracker) bool {
-	cmp := tx1.GasPrice.Cmp(tx2.GasPrice)
-	if cmp >= 0 {
-		return true
+// split splits the subtree in the nodes sorted before the node and the rest
+func (e *txSortedList) split(root *txSortedListNode, node *txSortedListNode) (*txSortedListNode, *txSortedListNode) {
+	if root == nil {
+		return nil, nil
+	}
+	if e.isBefore(root, node) {
+		left, right := e.split(root.right, node)
+		root.right = left
+		root.update()
+		return root, right
+	}
+	left, right := e.split(root.left, node)
+	root.left = right
+	root.update()
+	return left, root
+}
+
+// merge merges two subtrees, all the nodes of the left one are sorted before the nodes of the right one
+func (e *txSortedList) merge(left *txSortedListNode, right *txSortedListNode) *txSortedListNode {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	if left.priority > right.priority {
+		left.right = e.merge(left.right, right)
+		left.update()
+		return left
+	}
+	right.left = e.merge(left, right.left)
+	right.update()
+	return right
+}
+
+// deleteNode deletes the node from the subtree
+func (e *txSortedList) deleteNode(root *txSortedListNode, node *txSortedListNode) (*txSortedListNode, bool) {
+	if root == nil {
+		return nil, false
+	}
+	if root == node {
+		return e.merge(root.left, root.right), true
+	}
+
+	var deleted bool
+	if e.isBefore(node, root) {
+		root.left, deleted = e.deleteNode(root.left, node)
 	} else {
-		return false
+		root.right, deleted = e.deleteNode(root.right, node)
 	}
+	if deleted {
+		root.update()
+	}
+	return root, deleted
 }
 
-// GetSorted returns the sorted list of tx
-func (e *txSortedList) GetSorted() []*TxTracker {
-	e.mutex.Lock()
-	defer e.mutex.Unlock()
+// findFirstFitting returns the first tx of the subtree that fits in the resources and its position,
+// offset is the position of the first tx of the subtree. The subtrees whose min resources don't fit
+// are skipped as none of their txs can fit. The min of each resource can come from a different tx, so
+// a subtree can pass the check without any of its txs fitting. The search is O(log n) when a single
+// resource discards the subtrees, but in the worst case, with every tx exhausting a different resource,
+// it visits the whole treap and it's O(n) like the linear scan
+func (e *txSortedList) findFirstFitting(node *txSortedListNode, resources state.BatchResources, offset int) (*TxTracker, int) {
+	if node == nil || !fitsIn(node.minResources, resources) {
+		return nil, -1
+	}
+	if tx, i := e.findFirstFitting(node.left, resources, offset); tx != nil {
+		return tx, i
+	}
+	leftSize := node.left.getSize()
+	if fitsIn(node.tx.BatchResources, resources) {
+		return node.tx, offset + leftSize
+	}
+	return e.findFirstFitting(node.right, resources, offset+leftSize+1)
+}
+
+// findFirstFittingWithTag returns the first tx with the tag of the subtree that fits in the resources and
+// its position, offset is the position of the first tx of the subtree. As in findFirstFitting, the subtrees
+// whose min resources don't fit are skipped
+func (e *txSortedList) findFirstFittingWithTag(node *txSortedListNode, resources state.BatchResources, tag string, offset int) (*TxTracker, int) {
+	if node == nil || !fitsIn(node.minResources, resources) {
+		return nil, -1
+	}
+	if tx, i := e.findFirstFittingWithTag(node.left, resources, tag, offset); tx != nil {
+		return tx, i
+	}
+	leftSize := node.left.getSize()
+	if node.tx.Tag == tag && fitsIn(node.tx.BatchResources, resources) {
+		return node.tx, offset + leftSize
+	}
+	return e.findFirstFittingWithTag(node.right, resources, tag, offset+leftSize+1)
+}
 
-	return e.sorted
+// inOrder calls fn with the txs of the subtree in order
+func (e *txSortedList) inOrder(node *txSortedListNode, fn func(tx *TxTracker)) {
+	if node == nil {
+		return
+	}
+	e.inOrder(node.left, fn)
+	fn(node.tx)
+	e.inOrder(node.right, fn)
+}
+
+// getSize returns the number of txs of the subtree
+func (n *txSortedListNode) getSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// update recalculates the size and min resources of the node from its tx and children
+func (n *txSortedListNode) update() {
+	n.size = 1 + n.left.getSize() + n.right.getSize()
+	n.minResources = n.tx.BatchResources
+	if n.left != nil {
+		n.minResources = minResources(n.minResources, n.left.minResources)
+	}
+	if n.right != nil {
+		n.minResources = minResources(n.minResources, n.right.minResources)
+	}
+}
+
+// fitsIn returns if every resource used is lower or equal than the available one
+func fitsIn(used state.BatchResources, available state.BatchResources) bool {
+	u, a := used.ZKCounters, available.ZKCounters
+	return used.Bytes <= available.Bytes &&
+		u.CumulativeGasUsed <= a.CumulativeGasUsed &&
+		u.UsedKeccakHashes <= a.UsedKeccakHashes &&
+		u.UsedPoseidonHashes <= a.UsedPoseidonHashes &&
+		u.UsedPoseidonPaddings <= a.UsedPoseidonPaddings &&
+		u.UsedMemAligns <= a.UsedMemAligns &&
+		u.UsedArithmetics <= a.UsedArithmetics &&
+		u.UsedBinaries <= a.UsedBinaries &&
+		u.UsedSteps <= a.UsedSteps
+}
+
+// minResources returns the min value of each resource
+func minResources(a state.BatchResources, b state.BatchResources) state.BatchResources {
+	return state.BatchResources{
+		ZKCounters: state.ZKCounters{
+			CumulativeGasUsed:    min(a.ZKCounters.CumulativeGasUsed, b.ZKCounters.CumulativeGasUsed),
+			UsedKeccakHashes:     min(a.ZKCounters.UsedKeccakHashes, b.ZKCounters.UsedKeccakHashes),
+			UsedPoseidonHashes:   min(a.ZKCounters.UsedPoseidonHashes, b.ZKCounters.UsedPoseidonHashes),
+			UsedPoseidonPaddings: min(a.ZKCounters.UsedPoseidonPaddings, b.ZKCounters.UsedPoseidonPaddings),
+			UsedMemAligns:        min(a.ZKCounters.UsedMemAligns, b.ZKCounters.UsedMemAligns),
+			UsedArithmetics:      min(a.ZKCounters.UsedArithmetics, b.ZKCounters.UsedArithmetics),
+			UsedBinaries:         min(a.ZKCounters.UsedBinaries, b.ZKCounters.UsedBinaries),
+			UsedSteps:            min(a.ZKCounters.UsedSteps, b.ZKCounters.UsedSteps),
+		},
+		Bytes: min(a.Bytes, b.Bytes),
+	}
 }
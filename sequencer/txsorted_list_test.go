@@ -4,8 +4,13 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
 )
 
 // randomBigInt is a shortcut for generating a random big.Int
@@ -25,7 +30,7 @@ func randomBigInt() *big.Int {
 }
 
 func TestTxSortedList(t *testing.T) {
-	el := newTxSortedList()
+	el := newTxSortedList(&gasPriceTxSorter{})
 	nItems := 100
 
 	for i := 0; i < nItems; i++ {
@@ -46,7 +51,7 @@ func TestTxSortedList(t *testing.T) {
 }
 
 func TestTxSortedListDelete(t *testing.T) {
-	el := newTxSortedList()
+	el := newTxSortedList(&gasPriceTxSorter{})
 
 	el.add(&TxTracker{HashStr: "0x01", GasPrice: new(big.Int).SetInt64(10)})
 	el.add(&TxTracker{HashStr: "0x02", GasPrice: new(big.Int).SetInt64(20)})
@@ -59,7 +64,7 @@ func TestTxSortedListDelete(t *testing.T) {
 
 	sort := []string{"0x05", "0x04", "0x02", "0x03", "0x06", "0x07", "0x01", "0x08"}
 
-	for index, tx := range el.sorted {
+	for index, tx := range el.GetSorted() {
 		if sort[index] != tx.HashStr {
 			t.Fatalf("Sort error. Expected %s, Actual %s", sort[index], tx.HashStr)
 		}
@@ -88,7 +93,7 @@ func TestTxSortedListDelete(t *testing.T) {
 }
 
 func TestTxSortedListBench(t *testing.T) {
-	el := newTxSortedList()
+	el := newTxSortedList(&gasPriceTxSorter{})
 
 	start := time.Now()
 	for i := 0; i < 10000; i++ {
@@ -112,3 +117,99 @@ func TestTxSortedListBench(t *testing.T) {
 	elapsed = time.Since(start)
 	t.Logf("TxSortedList adding the 10003 item (GasPrice=1000) took %s", elapsed)
 }
+
+func TestTxSortedListGetBestFitting(t *testing.T) {
+	el := newTxSortedList(&gasPriceTxSorter{})
+
+	newTx := func(hash string, gasPrice int64, steps uint32, bytes uint64) *TxTracker {
+		return &TxTracker{HashStr: hash, GasPrice: new(big.Int).SetInt64(gasPrice),
+			BatchResources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: steps}, Bytes: bytes}}
+	}
+	el.add(newTx("0x01", 100, 50, 10))
+	el.add(newTx("0x02", 90, 10, 50))
+	el.add(newTx("0x03", 80, 10, 10))
+	el.add(newTx("0x04", 70, 1, 1))
+
+	testCases := []struct {
+		resources     state.BatchResources
+		expectedHash  string
+		expectedIndex int
+	}{
+		{resources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 100}, Bytes: 100}, expectedHash: "0x01", expectedIndex: 0},
+		{resources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 20}, Bytes: 100}, expectedHash: "0x02", expectedIndex: 1},
+		{resources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 20}, Bytes: 20}, expectedHash: "0x03", expectedIndex: 2},
+		{resources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 5}, Bytes: 5}, expectedHash: "0x04", expectedIndex: 3},
+		{resources: state.BatchResources{}, expectedIndex: -1},
+	}
+	for _, testCase := range testCases {
+		tx, index := el.getBestFitting(testCase.resources)
+		assert.Equal(t, testCase.expectedIndex, index)
+		if testCase.expectedIndex == -1 {
+			assert.Nil(t, tx)
+		} else {
+			assert.Equal(t, testCase.expectedHash, tx.HashStr)
+			assert.Equal(t, tx, el.getByIndex(index))
+		}
+	}
+
+	// the min resources are updated when the txs are deleted
+	el.delete(&TxTracker{HashStr: "0x04"})
+	tx, index := el.getBestFitting(state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 5}, Bytes: 5})
+	assert.Nil(t, tx)
+	assert.Equal(t, -1, index)
+}
+
+// newBenchmarkTxSortedList returns a txSortedList with n txs with random gasPrice and resources
+func newBenchmarkTxSortedList(n int) *txSortedList {
+	el := newTxSortedList(&gasPriceTxSorter{})
+	for i := 0; i < n; i++ {
+		el.add(&TxTracker{HashStr: fmt.Sprintf("0x%d", i), GasPrice: randomBigInt(), BatchResources: state.BatchResources{
+			ZKCounters: state.ZKCounters{
+				CumulativeGasUsed: uint64(mrand.Intn(1000000)), //nolint:gosec
+				UsedSteps:         uint32(mrand.Intn(1000000)), //nolint:gosec
+				UsedKeccakHashes:  uint32(mrand.Intn(1000)),    //nolint:gosec
+			},
+			Bytes: uint64(mrand.Intn(10000)), //nolint:gosec
+		}})
+	}
+	return el
+}
+
+func BenchmarkTxSortedListGetBestFitting(b *testing.B) {
+	el := newBenchmarkTxSortedList(100000)
+	// only a small fraction of the txs fit, so most of the list must be discarded
+	resources := state.BatchResources{
+		ZKCounters: state.ZKCounters{CumulativeGasUsed: 10000, UsedSteps: 10000, UsedKeccakHashes: 10},
+		Bytes:      100,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		el.getBestFitting(resources)
+	}
+}
+
+func BenchmarkTxSortedListAddDelete(b *testing.B) {
+	el := newBenchmarkTxSortedList(100000)
+	tx := &TxTracker{HashStr: "0xbench", GasPrice: randomBigInt()}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		el.add(tx)
+		el.delete(tx)
+	}
+}
+
+func BenchmarkWorkerGetBestFittingTx(b *testing.B) {
+	worker := NewWorker(Config{}, pool.Config{}, nil, rcMax)
+	worker.txSortedList = newBenchmarkTxSortedList(100000)
+	resources := state.BatchResources{
+		ZKCounters: state.ZKCounters{CumulativeGasUsed: 10000, UsedSteps: 10000, UsedKeccakHashes: 10},
+		Bytes:      100,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		worker.GetBestFittingTx(resources)
+	}
+}
@@ -0,0 +1,112 @@
+package sequencer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+const (
+	// TxSorterGasPrice selects first the txs with higher gasPrice
+	TxSorterGasPrice = "gasprice"
+	// TxSorterEfficiency selects first the txs paying more fees per unit of the batch resources they use
+	TxSorterEfficiency = "efficiency"
+	// TxSorterFIFO selects first the txs received earlier
+	TxSorterFIFO = "fifo"
+)
+
+// TxSorter is the policy used by the worker to sort the ready txs, they are selected for the next batch in that order
+type TxSorter interface {
+	// IsGreaterThan returns true if tx1 must be selected before tx2
+	IsGreaterThan(tx1 *TxTracker, tx2 *TxTracker) bool
+}
+
+// NewTxSorter creates the TxSorter for the policy set in the config, the batch constraints are
+// used to weight the resources used by the txs. The sponsored txs are sorted first with any policy
+func NewTxSorter(policy string, constraints state.BatchConstraintsCfg) (TxSorter, error) {
+	switch policy {
+	case TxSorterGasPrice, "":
+		return &sponsoredTxSorter{&gasPriceTxSorter{}}, nil
+	case TxSorterEfficiency:
+		return &sponsoredTxSorter{&efficiencyTxSorter{constraints: constraints}}, nil
+	case TxSorterFIFO:
+		return &sponsoredTxSorter{&fifoTxSorter{}}, nil
+	default:
+		return nil, fmt.Errorf("unknown tx sorter %q, the possible values are %q, %q and %q", policy, TxSorterGasPrice, TxSorterEfficiency, TxSorterFIFO)
+	}
+}
+
+// sponsoredTxSorter sorts the sponsored txs before the rest of txs in the order they were received, and the
+// rest of txs with the sorter of the policy. The sponsored txs pay no fee, so the fee based sorters would
+// keep them last while there are paid txs. Their number is limited by the rate limits of the pool
+type sponsoredTxSorter struct {
+	TxSorter
+}
+
+// IsGreaterThan returns true if tx1 is sponsored and tx2 isn't, or if both are sponsored and tx1 was received before
+// tx2, otherwise the txs are sorted with the sorter of the policy
+func (s *sponsoredTxSorter) IsGreaterThan(tx1 *TxTracker, tx2 *TxTracker) bool {
+	if tx1.Sponsored != tx2.Sponsored {
+		return tx1.Sponsored
+	}
+	if tx1.Sponsored {
+		return tx1.ReceivedAt.Before(tx2.ReceivedAt)
+	}
+	return s.TxSorter.IsGreaterThan(tx1, tx2)
+}
+
+// gasPriceTxSorter sorts the txs by gasPrice
+type gasPriceTxSorter struct{}
+
+// IsGreaterThan returns true if the tx1 has greater gasPrice than tx2
+func (s *gasPriceTxSorter) IsGreaterThan(tx1 *TxTracker, tx2 *TxTracker) bool {
+	return tx1.GasPrice.Cmp(tx2.GasPrice) == 1
+}
+
+// efficiencyTxSorter sorts the txs by the fee they pay divided by the share of the batch
+// resources they use, so the txs that use the scarcest resources of the batch must pay more
+type efficiencyTxSorter struct {
+	constraints state.BatchConstraintsCfg
+}
+
+// IsGreaterThan returns true if the tx1 has greater efficiency than tx2
+func (s *efficiencyTxSorter) IsGreaterThan(tx1 *TxTracker, tx2 *TxTracker) bool {
+	return s.efficiency(tx1).Cmp(s.efficiency(tx2)) == 1
+}
+
+// efficiency returns the fee of the tx divided by the sum of the share of each batch resource used by the tx
+func (s *efficiencyTxSorter) efficiency(tx *TxTracker) *big.Float {
+	fee := new(big.Float).SetInt(new(big.Int).Mul(tx.GasPrice, new(big.Int).SetUint64(tx.Gas)))
+
+	counters := tx.BatchResources.ZKCounters
+	usage := resourceShare(counters.CumulativeGasUsed, s.constraints.MaxCumulativeGasUsed) +
+		resourceShare(uint64(counters.UsedKeccakHashes), uint64(s.constraints.MaxKeccakHashes)) +
+		resourceShare(uint64(counters.UsedPoseidonHashes), uint64(s.constraints.MaxPoseidonHashes)) +
+		resourceShare(uint64(counters.UsedPoseidonPaddings), uint64(s.constraints.MaxPoseidonPaddings)) +
+		resourceShare(uint64(counters.UsedMemAligns), uint64(s.constraints.MaxMemAligns)) +
+		resourceShare(uint64(counters.UsedArithmetics), uint64(s.constraints.MaxArithmetics)) +
+		resourceShare(uint64(counters.UsedBinaries), uint64(s.constraints.MaxBinaries)) +
+		resourceShare(uint64(counters.UsedSteps), uint64(s.constraints.MaxSteps)) +
+		resourceShare(tx.BatchResources.Bytes, s.constraints.MaxBatchBytesSize)
+	if usage == 0 {
+		return fee
+	}
+	return fee.Quo(fee, big.NewFloat(usage))
+}
+
+// resourceShare returns the share of the max value of a batch resource that is used
+func resourceShare(used, max uint64) float64 {
+	if max == 0 {
+		return 0
+	}
+	return float64(used) / float64(max)
+}
+
+// fifoTxSorter sorts the txs by the time they were received
+type fifoTxSorter struct{}
+
+// IsGreaterThan returns true if the tx1 was received before tx2
+func (s *fifoTxSorter) IsGreaterThan(tx1 *TxTracker, tx2 *TxTracker) bool {
+	return tx1.ReceivedAt.Before(tx2.ReceivedAt)
+}
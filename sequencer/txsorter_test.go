@@ -0,0 +1,79 @@
+package sequencer
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxSorters(t *testing.T) {
+	now := time.Now()
+	// cheap tx using few resources
+	tx1 := &TxTracker{HashStr: "0x01", GasPrice: new(big.Int).SetInt64(10), Gas: 1, ReceivedAt: now.Add(time.Second),
+		BatchResources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 1}}}
+	// expensive tx using a lot of resources
+	tx2 := &TxTracker{HashStr: "0x02", GasPrice: new(big.Int).SetInt64(20), Gas: 1, ReceivedAt: now.Add(2 * time.Second),
+		BatchResources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 5}}}
+	// tx received first
+	tx3 := &TxTracker{HashStr: "0x03", GasPrice: new(big.Int).SetInt64(5), Gas: 1, ReceivedAt: now,
+		BatchResources: state.BatchResources{ZKCounters: state.ZKCounters{UsedSteps: 4}}}
+
+	testCases := []struct {
+		policy         string
+		expectedSorted []string
+	}{
+		{policy: TxSorterGasPrice, expectedSorted: []string{"0x02", "0x01", "0x03"}},
+		{policy: TxSorterEfficiency, expectedSorted: []string{"0x01", "0x02", "0x03"}},
+		{policy: TxSorterFIFO, expectedSorted: []string{"0x03", "0x01", "0x02"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.policy, func(t *testing.T) {
+			sorter, err := NewTxSorter(testCase.policy, rcMax)
+			require.NoError(t, err)
+
+			el := newTxSortedList(sorter)
+			el.add(tx1)
+			el.add(tx2)
+			el.add(tx3)
+			for i, hash := range testCase.expectedSorted {
+				assert.Equal(t, hash, el.getByIndex(i).HashStr)
+			}
+
+			assert.True(t, el.delete(tx1))
+			assert.True(t, el.delete(tx2))
+			assert.True(t, el.delete(tx3))
+			assert.Equal(t, 0, el.len())
+		})
+	}
+}
+
+func TestTxSortersSponsoredFirst(t *testing.T) {
+	now := time.Now()
+	paid := &TxTracker{HashStr: "0x01", GasPrice: new(big.Int).SetInt64(10), Gas: 1, ReceivedAt: now}
+	sponsored1 := &TxTracker{HashStr: "0x02", GasPrice: new(big.Int), Gas: 1, ReceivedAt: now.Add(2 * time.Second), Sponsored: true}
+	sponsored2 := &TxTracker{HashStr: "0x03", GasPrice: new(big.Int), Gas: 1, ReceivedAt: now.Add(time.Second), Sponsored: true}
+
+	for _, policy := range []string{TxSorterGasPrice, TxSorterEfficiency, TxSorterFIFO} {
+		t.Run(policy, func(t *testing.T) {
+			sorter, err := NewTxSorter(policy, rcMax)
+			require.NoError(t, err)
+
+			el := newTxSortedList(sorter)
+			el.add(paid)
+			el.add(sponsored1)
+			el.add(sponsored2)
+			for i, hash := range []string{"0x03", "0x02", "0x01"} {
+				assert.Equal(t, hash, el.getByIndex(i).HashStr)
+			}
+		})
+	}
+}
+
+func TestNewTxSorterUnknownPolicy(t *testing.T) {
+	_, err := NewTxSorter("random", rcMax)
+	assert.Error(t, err)
+}
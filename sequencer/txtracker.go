@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -15,6 +16,8 @@ type TxTracker struct {
 	HashStr           string
 	From              common.Address
 	FromStr           string
+	To                *common.Address // To is the recipient of the tx, nil for contract creations
+	Selector          []byte          // Selector is the 4-byte function selector of the tx data, if any
 	Nonce             uint64
 	Gas               uint64 // To check if it fits into a batch
 	GasPrice          *big.Int
@@ -29,6 +32,12 @@ type TxTracker struct {
 	EGPLog            state.EffectiveGasPriceLog
 	L1GasPrice        uint64
 	L2GasPrice        uint64
+	Conditions        *pool.TxConditions // Conditions are the preconditions of a conditional tx, checked when it's selected
+	Tag               string             // Tag is the operator-defined tag of the tx, used to select it during its sequencing windows
+	RetryAttempts     uint64             // RetryAttempts is the number of times the tx has been skipped because of a transient error
+	RetryAt           time.Time          // RetryAt is the time the tx can be selected again after being skipped
+	NotReadySince     time.Time          // NotReadySince is the time the tx was moved to the notReadyTxs, zero while it's ready
+	Sponsored         bool               // Sponsored is true if the tx was accepted by the pool with zero gas price because it calls a sponsored contract
 }
 
 // newTxTracker creates and inti a TxTracker
@@ -43,14 +52,21 @@ func newTxTracker(tx types.Transaction, counters state.ZKCounters, ip string) (*
 		return nil, err
 	}
 
+	var selector []byte
+	if len(tx.Data()) >= len([4]byte{}) {
+		selector = tx.Data()[:len([4]byte{})]
+	}
+
 	txTracker := &TxTracker{
 		Hash:     tx.Hash(),
 		HashStr:  tx.Hash().String(),
 		From:     addr,
 		FromStr:  addr.String(),
+		To:       tx.To(),
+		Selector: selector,
 		Nonce:    tx.Nonce(),
 		Gas:      tx.Gas(),
-		GasPrice: tx.GasPrice(),
+		GasPrice: state.GetTxGasPrice(tx),
 		Cost:     tx.Cost(),
 		BatchResources: state.BatchResources{
 			Bytes:      tx.Size(),
@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"runtime"
 	"sync"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/clock"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -22,20 +23,54 @@ type Worker struct {
 	workerMutex      sync.Mutex
 	state            stateInterface
 	batchConstraints state.BatchConstraintsCfg
+	selectionAudit   *selectionAuditor
+	txRetryCfg       TxRetryCfg
+	// priceBump is the min gasPrice increase percentage to replace a tx with the same nonce
+	priceBump uint64
+	// maxTxsPerAccount is the max number of txs of an account held by the worker, 0 disables it
+	maxTxsPerAccount uint64
+	// maxTxs is the max number of txs held by the worker, 0 disables it
+	maxTxs uint64
+	// workerTxs is shared with the addrQueues to keep the count of the txs held by the worker
+	workerTxs *workerTxs
+	// policy rejects the denied txs, nil when the sequencer policy is disabled
+	policy *txPolicy
+	// retryTxs are the ready txs skipped because of a transient error, they are
+	// kept out of the txSortedList until their retry time is reached
+	retryTxs map[string]*TxTracker
+	// priceFloor is the min gas price of the ready txs that can be selected, the ready txs priced
+	// below it are kept out of the txSortedList. nil when the repricing is disabled
+	priceFloor *big.Int
+	// clock provides the time of the tx retries and expirations, it's shared with the addrQueues
+	clock clock.Clock
 }
 
 // NewWorker creates an init a worker
-func NewWorker(state stateInterface, constraints state.BatchConstraintsCfg) *Worker {
+func NewWorker(cfg Config, poolCfg pool.Config, state stateInterface, constraints state.BatchConstraintsCfg) *Worker {
 	w := Worker{
 		pool:             make(map[string]*addrQueue),
-		txSortedList:     newTxSortedList(),
+		txSortedList:     newTxSortedList(&gasPriceTxSorter{}),
 		state:            state,
 		batchConstraints: constraints,
+		retryTxs:         make(map[string]*TxTracker),
+		txRetryCfg:       cfg.TxRetry,
+		priceBump:        poolCfg.PriceBump,
+		maxTxsPerAccount: poolCfg.MaxTxsPerAccount,
+		maxTxs:           cfg.MaxWorkerTxs,
+		workerTxs:        &workerTxs{notReadyTxs: newTxSortedList(&gasPriceTxSorter{})},
+		clock:            clock.System,
 	}
 
 	return &w
 }
 
+// setTxSorter sets the TxSorter used to sort the ready txs to select and the notReady txs to evict,
+// it must be called before adding any tx to the worker
+func (w *Worker) setTxSorter(sorter TxSorter) {
+	w.txSortedList = newTxSortedList(sorter)
+	w.workerTxs.notReadyTxs = newTxSortedList(sorter)
+}
+
 // NewTxTracker creates and inits a TxTracker
 func (w *Worker) NewTxTracker(tx types.Transaction, counters state.ZKCounters, ip string) (*TxTracker, error) {
 	return newTxTracker(tx, counters, ip)
@@ -51,6 +86,15 @@ func (w *Worker) AddTxTracker(ctx context.Context, tx *TxTracker) (replacedTx *T
 		return nil, pool.ErrInvalidIP
 	}
 
+	// Make sure the tx is not denied by the sequencer policy
+	if w.policy != nil {
+		if err := w.policy.check(tx); err != nil {
+			log.Infof("AddTx tx(%s) from addr(%s) denied by the sequencer policy, reason: %s", tx.HashStr, tx.FromStr, err.Error())
+			w.workerMutex.Unlock()
+			return nil, err
+		}
+	}
+
 	// Make sure the transaction's batch resources are within the constraints.
 	if !w.batchConstraints.IsWithinConstraints(tx.BatchResources.ZKCounters) {
 		log.Errorf("OutOfCounters Error (Node level)  for tx: %s", tx.Hash.String())
@@ -83,6 +127,9 @@ func (w *Worker) AddTxTracker(ctx context.Context, tx *TxTracker) (replacedTx *T
 		}
 
 		addr = newAddrQueue(tx.From, nonce.Uint64(), balance)
+		addr.priceBump = w.priceBump
+		addr.workerTxs = w.workerTxs
+		addr.clock = w.clock
 
 		// Lock again the worker
 		w.workerMutex.Lock()
@@ -91,6 +138,17 @@ func (w *Worker) AddTxTracker(ctx context.Context, tx *TxTracker) (replacedTx *T
 		log.Infof("AddTx new addrQueue created for addr(%s) nonce(%d) balance(%s)", tx.FromStr, nonce.Uint64(), balance.String())
 	}
 
+	// Make room for the tx if the account or the worker have reached their limit of txs
+	evictedTx, dropReason := w.checkTxsLimits(addr, tx)
+	if dropReason != nil {
+		log.Infof("AddTx tx(%s) dropped from addrQueue(%s), reason: %s", tx.HashStr, tx.FromStr, dropReason.Error())
+		if addr.IsEmpty() {
+			delete(w.pool, addr.fromStr)
+		}
+		w.workerMutex.Unlock()
+		return nil, dropReason
+	}
+
 	// Add the txTracker to Addr and get the newReadyTx and prevReadyTx
 	log.Infof("AddTx new tx(%s) nonce(%d) gasPrice(%d) to addrQueue(%s) nonce(%d) balance(%d)", tx.HashStr, tx.Nonce, tx.GasPrice, addr.fromStr, addr.currentNonce, addr.currentBalance)
 	var newReadyTx, prevReadyTx, repTx *TxTracker
@@ -106,19 +164,86 @@ func (w *Worker) AddTxTracker(ctx context.Context, tx *TxTracker) (replacedTx *T
 		log.Infof("AddTx prevReadyTx(%s) nonce(%d) gasPrice(%d) addr(%s) deleted from TxSortedList", prevReadyTx.HashStr, prevReadyTx.Nonce, prevReadyTx.GasPrice, tx.FromStr)
 		w.txSortedList.delete(prevReadyTx)
 	}
-	if newReadyTx != nil {
+	if newReadyTx != nil && !addr.reorged && !w.isWaitingRetry(newReadyTx) && !w.isUnderpriced(newReadyTx) {
 		log.Infof("AddTx newReadyTx(%s) nonce(%d) gasPrice(%d) addr(%s) added to TxSortedList", newReadyTx.HashStr, newReadyTx.Nonce, newReadyTx.GasPrice, tx.FromStr)
 		w.txSortedList.add(newReadyTx)
 	}
 
 	if repTx != nil {
-		log.Infof("AddTx replacedTx(%s) nonce(%d) gasPrice(%d) addr(%s) has been replaced", repTx.HashStr, repTx.Nonce, repTx.GasPrice, tx.FromStr)
+		delete(w.retryTxs, repTx.HashStr)
+		log.Infof("AddTx replacedTx(%s) nonce(%d) gasPrice(%d) addr(%s) has been replaced by tx(%s) gasPrice(%d)", repTx.HashStr, repTx.Nonce, repTx.GasPrice, tx.FromStr, tx.HashStr, tx.GasPrice)
+	} else if evictedTx != nil {
+		// the evicted tx is returned as replaced, its FailedReason tells it was evicted
+		repTx = evictedTx
 	}
 
 	w.workerMutex.Unlock()
 	return repTx, nil
 }
 
+// checkTxsLimits checks if adding the tx exceeds the MaxTxsPerAccount or MaxWorkerTxs limits. In that case the not ready
+// tx with the lowest efficiency of the account (or of the worker) is evicted if the new tx has higher efficiency, otherwise
+// the new tx is rejected. It returns the evicted tx. The worker limit is checked with the running count of the txs and the
+// notReady txs sorted by the TxSorter, without walking the addrQueues. The worker mutex must be held by the caller
+func (w *Worker) checkTxsLimits(addr *addrQueue, tx *TxTracker) (*TxTracker, error) {
+	// the txs replacing an existing one and the txs with an invalid nonce don't take a new slot
+	if tx.Nonce < addr.currentNonce || addr.hasNonce(tx.Nonce) {
+		return nil, nil
+	}
+
+	if w.maxTxsPerAccount > 0 && uint64(addr.txsCount()) >= w.maxTxsPerAccount {
+		evictedTx := w.evictNotReadyTx(tx, addr.lowestNotReadyTx(w.txSortedList.sorter))
+		if evictedTx == nil {
+			metrics.WorkerTxsLimited(metrics.WorkerTxsLimitedLabelRejected)
+			return nil, ErrAccountTxsLimitReached
+		}
+		metrics.WorkerTxsLimited(metrics.WorkerTxsLimitedLabelEvicted)
+		return evictedTx, nil
+	}
+
+	if w.maxTxs > 0 {
+		metrics.WorkerOccupancy(float64(w.workerTxs.count) / float64(w.maxTxs))
+
+		if uint64(w.workerTxs.count) >= w.maxTxs {
+			var lowestTx *TxTracker
+			if notReadyTxs := w.workerTxs.notReadyTxs; notReadyTxs.len() > 0 {
+				lowestTx = notReadyTxs.getByIndex(notReadyTxs.len() - 1)
+			}
+			evictedTx := w.evictNotReadyTx(tx, lowestTx)
+			if evictedTx == nil {
+				metrics.WorkerTxsLimited(metrics.WorkerTxsLimitedLabelRejected)
+				return nil, ErrWorkerFull
+			}
+			metrics.WorkerTxsLimited(metrics.WorkerTxsLimitedLabelEvicted)
+			return evictedTx, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// evictNotReadyTx deletes the notReady tx with the lowest efficiency (the one sorted last by the TxSorter) if the
+// tx is sorted before it. It returns the evicted tx with its FailedReason set, or nil if no tx has been evicted
+func (w *Worker) evictNotReadyTx(tx *TxTracker, lowestTx *TxTracker) *TxTracker {
+	if lowestTx == nil || !w.txSortedList.sorter.IsGreaterThan(tx, lowestTx) {
+		return nil
+	}
+
+	lowestQueue, found := w.pool[lowestTx.FromStr]
+	if !found {
+		log.Errorf("AddTx notReadyTx(%s) addrQueue(%s) not found", lowestTx.HashStr, lowestTx.FromStr)
+		return nil
+	}
+	lowestQueue.deleteTx(lowestTx.Hash)
+	if lowestQueue.IsEmpty() {
+		delete(w.pool, lowestQueue.fromStr)
+	}
+	failedReason := ErrEvictedTransaction.Error()
+	lowestTx.FailedReason = &failedReason
+	log.Infof("AddTx notReadyTx(%s) nonce(%d) gasPrice(%d) addr(%s) evicted by tx(%s) gasPrice(%d)", lowestTx.HashStr, lowestTx.Nonce, lowestTx.GasPrice, lowestQueue.fromStr, tx.HashStr, tx.GasPrice)
+	return lowestTx
+}
+
 func (w *Worker) applyAddressUpdate(from common.Address, fromNonce *uint64, fromBalance *big.Int) (*TxTracker, *TxTracker, []*TxTracker) {
 	addrQueue, found := w.pool[from.String()]
 
@@ -130,7 +255,7 @@ func (w *Worker) applyAddressUpdate(from common.Address, fromNonce *uint64, from
 			log.Infof("applyAddressUpdate prevReadyTx(%s) nonce(%d) gasPrice(%d) deleted from TxSortedList", prevReadyTx.Hash.String(), prevReadyTx.Nonce, prevReadyTx.GasPrice)
 			w.txSortedList.delete(prevReadyTx)
 		}
-		if newReadyTx != nil {
+		if newReadyTx != nil && !addrQueue.reorged && !w.isWaitingRetry(newReadyTx) && !w.isUnderpriced(newReadyTx) {
 			log.Infof("applyAddressUpdate newReadyTx(%s) nonce(%d) gasPrice(%d) added to TxSortedList", newReadyTx.Hash.String(), newReadyTx.Nonce, newReadyTx.GasPrice)
 			w.txSortedList.add(newReadyTx)
 		}
@@ -193,6 +318,8 @@ func (w *Worker) DeleteTx(txHash common.Hash, addr common.Address) {
 	w.workerMutex.Lock()
 	defer w.workerMutex.Unlock()
 
+	delete(w.retryTxs, txHash.String())
+
 	addrQueue, found := w.pool[addr.String()]
 	if found {
 		deletedReadyTx := addrQueue.deleteTx(txHash)
@@ -205,6 +332,66 @@ func (w *Worker) DeleteTx(txHash common.Hash, addr common.Address) {
 	}
 }
 
+// RetryTxLater skips the readyTx of the addrQueue after it fails because of a transient error,
+// keeping it out of the txSortedList during a delay that doubles on each attempt. It returns
+// false if the tx can't be retried because it has reached the max number of attempts
+func (w *Worker) RetryTxLater(txHash common.Hash, addr common.Address) bool {
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	addrQueue, found := w.pool[addr.String()]
+	if !found || addrQueue.readyTx == nil || addrQueue.readyTx.Hash != txHash {
+		log.Warnf("RetryTxLater tx(%s) is not the readyTx of addrQueue(%s)", txHash.String(), addr.String())
+		return false
+	}
+
+	tx := addrQueue.readyTx
+	if tx.RetryAttempts >= w.txRetryCfg.MaxAttempts {
+		log.Infof("RetryTxLater tx(%s) has reached the max number of attempts(%d)", tx.HashStr, w.txRetryCfg.MaxAttempts)
+		return false
+	}
+
+	tx.RetryAttempts++
+	delay, maxDelay := w.txRetryCfg.InitialDelay.Duration, w.txRetryCfg.MaxDelay.Duration
+	for i := uint64(1); i < tx.RetryAttempts && (maxDelay == 0 || delay < maxDelay); i++ {
+		delay *= 2
+	}
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+	tx.RetryAt = w.clock.Now().Add(delay)
+
+	w.txSortedList.delete(tx)
+	w.retryTxs[tx.HashStr] = tx
+	log.Infof("RetryTxLater tx(%s) attempt(%d) deleted from TxSortedList until %v", tx.HashStr, tx.RetryAttempts, tx.RetryAt)
+
+	return true
+}
+
+// isWaitingRetry returns if the tx has been skipped because of a transient error and its retry time hasn't been reached
+func (w *Worker) isWaitingRetry(tx *TxTracker) bool {
+	_, found := w.retryTxs[tx.HashStr]
+	return found
+}
+
+// revisitRetryTxs adds back to the txSortedList the skipped txs that have reached
+// their retry time and are still the readyTx of their addrQueue
+func (w *Worker) revisitRetryTxs() {
+	currentTime := w.clock.Now()
+	for hashStr, tx := range w.retryTxs {
+		if currentTime.Before(tx.RetryAt) {
+			continue
+		}
+		delete(w.retryTxs, hashStr)
+
+		addrQueue, found := w.pool[tx.FromStr]
+		if found && !addrQueue.reorged && addrQueue.readyTx == tx && !w.isUnderpriced(tx) {
+			log.Infof("revisitRetryTxs tx(%s) added to TxSortedList", tx.HashStr)
+			w.txSortedList.add(tx)
+		}
+	}
+}
+
 // DeleteForcedTx deletes a forced tx from the addrQueue
 func (w *Worker) DeleteForcedTx(txHash common.Hash, addr common.Address) {
 	w.workerMutex.Lock()
@@ -236,7 +423,13 @@ func (w *Worker) UpdateTxZKCounters(txHash common.Hash, addr common.Address, cou
 	addrQueue, found := w.pool[addr.String()]
 
 	if found {
+		// The txSortedList keeps the position and the resources of the readyTx, so it's added again with the new counters
+		readyTx := addrQueue.readyTx
+		resort := readyTx != nil && readyTx.Hash == txHash && w.txSortedList.delete(readyTx)
 		addrQueue.UpdateTxZKCounters(txHash, counters)
+		if resort {
+			w.txSortedList.add(readyTx)
+		}
 	} else {
 		log.Warnf("UpdateTxZKCounters addrQueue(%s) not found", addr.String())
 	}
@@ -289,48 +482,19 @@ func (w *Worker) GetBestFittingTx(resources state.BatchResources) *TxTracker {
 	w.workerMutex.Lock()
 	defer w.workerMutex.Unlock()
 
-	var (
-		tx         *TxTracker
-		foundMutex sync.RWMutex
-	)
-
-	nGoRoutines := runtime.NumCPU()
-	foundAt := -1
-
-	wg := sync.WaitGroup{}
-	wg.Add(nGoRoutines)
-
-	// Each go routine looks for a fitting tx
-	for i := 0; i < nGoRoutines; i++ {
-		go func(n int, bresources state.BatchResources) {
-			defer wg.Done()
-			for i := n; i < w.txSortedList.len(); i += nGoRoutines {
-				foundMutex.RLock()
-				if foundAt != -1 && i > foundAt {
-					foundMutex.RUnlock()
-					return
-				}
-				foundMutex.RUnlock()
-
-				txCandidate := w.txSortedList.getByIndex(i)
-				err := bresources.Sub(txCandidate.BatchResources)
-				if err != nil {
-					// We don't add this Tx
-					continue
-				}
-
-				foundMutex.Lock()
-				if foundAt == -1 || foundAt > i {
-					foundAt = i
-					tx = txCandidate
-				}
-				foundMutex.Unlock()
-
-				return
-			}
-		}(i, resources)
+	start := time.Now()
+	defer func() {
+		metrics.WorkerGetBestFittingTxTime(time.Since(start))
+	}()
+
+	w.revisitRetryTxs()
+	metrics.WorkerSortedTxs(w.txSortedList.len())
+
+	tx, foundAt := w.txSortedList.getBestFitting(resources)
+
+	if w.selectionAudit != nil && w.selectionAudit.sample() {
+		w.auditSelectionRound(resources, foundAt)
 	}
-	wg.Wait()
 
 	if foundAt != -1 {
 		log.Infof("GetBestFittingTx found tx(%s) at index(%d) with gasPrice(%d)", tx.Hash.String(), foundAt, tx.GasPrice)
@@ -339,8 +503,40 @@ func (w *Worker) GetBestFittingTx(resources state.BatchResources) *TxTracker {
 	return tx
 }
 
-// ExpireTransactions deletes old txs
-func (w *Worker) ExpireTransactions(maxTime time.Duration) []*TxTracker {
+// GetBestFittingTaggedTx gets the most efficient tx with the tag that fits in the available batch resources,
+// it's used during the sequencing windows of the tag
+func (w *Worker) GetBestFittingTaggedTx(resources state.BatchResources, tag string) *TxTracker {
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	start := time.Now()
+	defer func() {
+		metrics.WorkerGetBestFittingTxTime(time.Since(start))
+	}()
+
+	w.revisitRetryTxs()
+
+	tx, foundAt := w.txSortedList.getBestFittingWithTag(resources, tag)
+	if foundAt != -1 {
+		log.Infof("GetBestFittingTaggedTx found tx(%s) with tag(%s) at index(%d) with gasPrice(%d)", tx.Hash.String(), tag, foundAt, tx.GasPrice)
+	}
+
+	return tx
+}
+
+// HasFittingTx returns if any of the first lookAheadTxs ready txs, in the order they are selected,
+// fits in the resources. 0 means all the ready txs are checked
+func (w *Worker) HasFittingTx(resources state.BatchResources, lookAheadTxs uint64) bool {
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	tx, foundAt := w.txSortedList.getBestFitting(resources)
+	return tx != nil && (lookAheadTxs == 0 || uint64(foundAt) < lookAheadTxs)
+}
+
+// ExpireTransactions deletes the txs older than maxTime and the txs that have not been ready
+// to be selected for more than maxNotReadyTime (0 disables it)
+func (w *Worker) ExpireTransactions(maxTime time.Duration, maxNotReadyTime time.Duration) []*TxTracker {
 	w.workerMutex.Lock()
 	defer w.workerMutex.Unlock()
 
@@ -348,7 +544,7 @@ func (w *Worker) ExpireTransactions(maxTime time.Duration) []*TxTracker {
 
 	log.Info("ExpireTransactions start. addrQueue len: ", len(w.pool))
 	for _, addrQueue := range w.pool {
-		subTxs, prevReadyTx := addrQueue.ExpireTransactions(maxTime)
+		subTxs, prevReadyTx := addrQueue.ExpireTransactions(maxTime, maxNotReadyTime)
 		txs = append(txs, subTxs...)
 
 		if prevReadyTx != nil {
@@ -361,10 +557,210 @@ func (w *Worker) ExpireTransactions(maxTime time.Duration) []*TxTracker {
 	}
 	log.Info("ExpireTransactions end. addrQueue len: ", len(w.pool), " deleteCount: ", len(txs))
 
+	w.updateSizeMetrics()
+
+	return txs
+}
+
+// UpdatePolicy replaces the sequencer policy and deletes the txs denied by the new policy,
+// so they can't be selected anymore. It returns the deleted txs with their FailedReason
+func (w *Worker) UpdatePolicy(policy *txPolicy) []*TxTracker {
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	w.policy = policy
+
+	var txs []*TxTracker
+	for _, addrQueue := range w.pool {
+		subTxs, prevReadyTx := addrQueue.deleteDeniedTxs(policy)
+		txs = append(txs, subTxs...)
+
+		if prevReadyTx != nil {
+			w.txSortedList.delete(prevReadyTx)
+			delete(w.retryTxs, prevReadyTx.HashStr)
+		}
+
+		if addrQueue.IsEmpty() {
+			delete(w.pool, addrQueue.fromStr)
+		}
+	}
+	log.Infof("UpdatePolicy addrQueue len: %d, deleteCount: %d", len(w.pool), len(txs))
+
+	w.updateSizeMetrics()
+
 	return txs
 }
 
-// HandleL2Reorg handles the L2 reorg signal
-func (w *Worker) HandleL2Reorg(txHashes []common.Hash) {
-	log.Fatal("L2 Reorg detected. Restarting to sync with the new L2 state...")
+// UpdatePriceFloor sets the min gas price of the ready txs that can be selected, nil disables it. The ready
+// txs priced below the new floor are demoted, removing them from the txSortedList, and the demoted txs that
+// are not priced below it anymore are promoted back. It returns the number of demoted and promoted txs
+func (w *Worker) UpdatePriceFloor(floor *big.Int) (demoted, promoted int) {
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	w.priceFloor = floor
+	for _, addrQueue := range w.pool {
+		tx := addrQueue.readyTx
+		if tx == nil || addrQueue.reorged || w.isWaitingRetry(tx) {
+			continue
+		}
+		if w.isUnderpriced(tx) {
+			if w.txSortedList.delete(tx) {
+				log.Infof("UpdatePriceFloor tx(%s) gasPrice(%d) demoted, deleted from TxSortedList", tx.HashStr, tx.GasPrice)
+				demoted++
+			}
+		} else if w.txSortedList.add(tx) {
+			log.Infof("UpdatePriceFloor tx(%s) gasPrice(%d) promoted, added to TxSortedList", tx.HashStr, tx.GasPrice)
+			promoted++
+		}
+	}
+	log.Infof("UpdatePriceFloor priceFloor(%d) demoteCount: %d, promoteCount: %d", floor, demoted, promoted)
+
+	w.updateSizeMetrics()
+
+	return demoted, promoted
+}
+
+// isUnderpriced returns if the tx is priced below the price floor, the sponsored txs are never underpriced
+func (w *Worker) isUnderpriced(tx *TxTracker) bool {
+	return w.priceFloor != nil && !tx.Sponsored && tx.GasPrice.Cmp(w.priceFloor) < 0
+}
+
+// updateSizeMetrics sets the metrics of the number of txs held by the worker, the
+// worker mutex must be held by the caller
+func (w *Worker) updateSizeMetrics() {
+	var readyTxs, notReadyTxs, notReadyAccounts, addrQueueMaxTxs int
+	for _, addrQueue := range w.pool {
+		addrQueueTxs := len(addrQueue.notReadyTxs)
+		if addrQueue.readyTx != nil {
+			readyTxs++
+			addrQueueTxs++
+		}
+		if len(addrQueue.notReadyTxs) > 0 {
+			notReadyTxs += len(addrQueue.notReadyTxs)
+			notReadyAccounts++
+		}
+		if addrQueueTxs > addrQueueMaxTxs {
+			addrQueueMaxTxs = addrQueueTxs
+		}
+	}
+	metrics.WorkerSize(readyTxs, notReadyTxs, notReadyAccounts, len(w.pool), addrQueueMaxTxs)
+	metrics.WorkerSortedTxs(w.txSortedList.len())
+}
+
+// HandleL2Reorg removes the reorged txs from the worker and refreshes from the new state the nonce
+// and balance of their senders. The addrQueues of the senders are marked as reorged until they are
+// refreshed, so none of their txs is selected meanwhile. It returns the txs that are not valid anymore
+// with the refreshed nonces
+func (w *Worker) HandleL2Reorg(ctx context.Context, reorgedTxs []*TxTracker) []*TxTracker {
+	w.workerMutex.Lock()
+	reorgedAddrs := make(map[common.Address]struct{})
+	for _, tx := range reorgedTxs {
+		delete(w.retryTxs, tx.HashStr)
+
+		addrQueue, found := w.pool[tx.FromStr]
+		if !found {
+			continue
+		}
+		deletedReadyTx := addrQueue.deleteTx(tx.Hash)
+		if deletedReadyTx != nil {
+			log.Infof("HandleL2Reorg tx(%s) deleted from TxSortedList", deletedReadyTx.HashStr)
+			w.txSortedList.delete(deletedReadyTx)
+		}
+		if addrQueue.IsEmpty() {
+			delete(w.pool, tx.FromStr)
+			continue
+		}
+		if addrQueue.readyTx != nil {
+			w.txSortedList.delete(addrQueue.readyTx)
+		}
+		addrQueue.reorged = true
+		reorgedAddrs[tx.From] = struct{}{}
+	}
+	w.workerMutex.Unlock()
+
+	// Unlock the worker to let execute other worker functions while reading the new state
+	updates := make([]*state.InfoReadWrite, 0, len(reorgedAddrs))
+	root, err := w.state.GetLastStateRoot(ctx, nil)
+	if err != nil {
+		log.Errorf("HandleL2Reorg GetLastStateRoot error: %v", err)
+	}
+	for addr := range reorgedAddrs {
+		update := &state.InfoReadWrite{Address: addr}
+		if err == nil {
+			update.Nonce, update.Balance = w.getNonceBalance(ctx, addr, root)
+		}
+		updates = append(updates, update)
+	}
+
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	txsToDelete := make([]*TxTracker, 0)
+	for _, update := range updates {
+		addrQueue, found := w.pool[update.Address.String()]
+		if !found {
+			continue
+		}
+		addrQueue.reorged = false
+		newReadyTx, _, txsToDeleteTemp := w.applyAddressUpdate(update.Address, update.Nonce, update.Balance)
+		txsToDelete = append(txsToDelete, txsToDeleteTemp...)
+
+		// The readyTx was removed from the TxSortedList when the addrQueue was marked as reorged
+		if newReadyTx == nil && addrQueue.readyTx != nil && !w.isWaitingRetry(addrQueue.readyTx) && !w.isUnderpriced(addrQueue.readyTx) {
+			w.txSortedList.add(addrQueue.readyTx)
+		}
+		log.Infof("HandleL2Reorg addrQueue(%s) refreshed with nonce(%d) balance(%s)", addrQueue.fromStr, addrQueue.currentNonce, addrQueue.currentBalance.String())
+	}
+	w.updateSizeMetrics()
+
+	return txsToDelete
+}
+
+// getNonceBalance returns the nonce and balance of the address in the state root, they
+// are nil if they can't be read so the addrQueue keeps its current values
+func (w *Worker) getNonceBalance(ctx context.Context, addr common.Address, root common.Hash) (*uint64, *big.Int) {
+	var nonce *uint64
+	n, err := w.state.GetNonceByStateRoot(ctx, addr, root)
+	if err != nil {
+		log.Errorf("HandleL2Reorg GetNonceByStateRoot error for addr(%s): %v", addr.String(), err)
+	} else {
+		nonceU64 := n.Uint64()
+		nonce = &nonceU64
+	}
+	balance, err := w.state.GetBalanceByStateRoot(ctx, addr, root)
+	if err != nil {
+		log.Errorf("HandleL2Reorg GetBalanceByStateRoot error for addr(%s): %v", addr.String(), err)
+		balance = nil
+	}
+	return nonce, balance
+}
+
+// TxsSnapshot contains the hashes of the txs held by the worker, the ready txs
+// can be selected for the next batch while the not ready ones are waiting for a
+// previous nonce or for enough balance to pay their cost
+type TxsSnapshot struct {
+	Ready    map[common.Hash]struct{}
+	NotReady map[common.Hash]struct{}
+}
+
+// GetTxsSnapshot returns the hashes of the txs currently held by the worker
+func (w *Worker) GetTxsSnapshot() TxsSnapshot {
+	w.workerMutex.Lock()
+	defer w.workerMutex.Unlock()
+
+	snapshot := TxsSnapshot{
+		Ready:    make(map[common.Hash]struct{}),
+		NotReady: make(map[common.Hash]struct{}),
+	}
+	for _, addrQueue := range w.pool {
+		if addrQueue.readyTx != nil {
+			snapshot.Ready[addrQueue.readyTx.Hash] = struct{}{}
+		}
+		for _, tx := range addrQueue.notReadyTxs {
+			snapshot.NotReady[tx.Hash] = struct{}{}
+		}
+	}
+
+	return snapshot
 }
@@ -4,11 +4,15 @@ import (
 	"context"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/clock"
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -287,6 +291,204 @@ func TestWorkerGetBestTx(t *testing.T) {
 }
 
 func initWorker(stateMock *StateMock, rcMax state.BatchConstraintsCfg) *Worker {
-	worker := NewWorker(stateMock, rcMax)
+	worker := NewWorker(Config{}, pool.Config{}, stateMock, rcMax)
 	return worker
 }
+
+func TestWorkerGetTxsSnapshot(t *testing.T) {
+	worker := initWorker(NewStateMock(t), rcMax)
+
+	readyTx := &TxTracker{Hash: common.Hash{1}, HashStr: common.Hash{1}.String(), From: common.Address{1}, Nonce: 1}
+	notReadyTx := &TxTracker{Hash: common.Hash{2}, HashStr: common.Hash{2}.String(), From: common.Address{1}, Nonce: 3}
+	addrQueue := newAddrQueue(common.Address{1}, 1, new(big.Int).SetInt64(10))
+	addrQueue.readyTx = readyTx
+	addrQueue.notReadyTxs[notReadyTx.Nonce] = notReadyTx
+	worker.pool[addrQueue.fromStr] = addrQueue
+
+	snapshot := worker.GetTxsSnapshot()
+	assert.Equal(t, map[common.Hash]struct{}{readyTx.Hash: {}}, snapshot.Ready)
+	assert.Equal(t, map[common.Hash]struct{}{notReadyTx.Hash: {}}, snapshot.NotReady)
+}
+
+func TestWorkerRetryTxLater(t *testing.T) {
+	fakeClock := clock.NewFake(time.Now())
+	worker := NewWorker(Config{
+		TxRetry: TxRetryCfg{
+			MaxAttempts:  3,
+			InitialDelay: types.NewDuration(time.Second),
+			MaxDelay:     types.NewDuration(3 * time.Second),
+		},
+	}, pool.Config{}, NewStateMock(t), rcMax)
+	worker.clock = fakeClock
+
+	tx := &TxTracker{Hash: common.Hash{1}, HashStr: common.Hash{1}.String(), From: common.Address{1}, FromStr: common.Address{1}.String(), Nonce: 1, GasPrice: new(big.Int).SetInt64(1), Cost: new(big.Int)}
+	addrQueue := newAddrQueue(common.Address{1}, 1, new(big.Int).SetInt64(10))
+	addrQueue.readyTx = tx
+	worker.pool[addrQueue.fromStr] = addrQueue
+	worker.txSortedList.add(tx)
+
+	assert.False(t, worker.RetryTxLater(common.Hash{2}, tx.From))
+
+	// the delay is doubled on each attempt until the max delay
+	for _, expectedDelay := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		require.True(t, worker.RetryTxLater(tx.Hash, tx.From))
+		assert.Equal(t, fakeClock.Now().Add(expectedDelay), tx.RetryAt)
+
+		// the tx is skipped until its retry time, even if its addrQueue is updated
+		worker.applyAddressUpdate(tx.From, nil, new(big.Int).SetInt64(20))
+		assert.Nil(t, worker.GetBestFittingTx(state.BatchResources{}))
+
+		fakeClock.Advance(expectedDelay)
+		assert.Equal(t, tx, worker.GetBestFittingTx(state.BatchResources{}))
+	}
+
+	assert.False(t, worker.RetryTxLater(tx.Hash, tx.From))
+}
+
+func TestWorkerUpdatePriceFloor(t *testing.T) {
+	worker := initWorker(NewStateMock(t), rcMax)
+
+	newReadyTx := func(hash common.Hash, from common.Address, gasPrice int64, sponsored bool) *TxTracker {
+		tx := &TxTracker{Hash: hash, HashStr: hash.String(), From: from, FromStr: from.String(), Nonce: 1, GasPrice: new(big.Int).SetInt64(gasPrice), Cost: new(big.Int), Sponsored: sponsored}
+		addrQueue := newAddrQueue(from, 1, new(big.Int).SetInt64(10))
+		addrQueue.readyTx = tx
+		worker.pool[addrQueue.fromStr] = addrQueue
+		worker.txSortedList.add(tx)
+		return tx
+	}
+	cheapTx := newReadyTx(common.Hash{1}, common.Address{1}, 5, false)
+	expensiveTx := newReadyTx(common.Hash{2}, common.Address{2}, 20, false)
+	sponsoredTx := newReadyTx(common.Hash{3}, common.Address{3}, 0, true)
+
+	// the txs priced below the floor are demoted, the sponsored txs are never demoted
+	demoted, promoted := worker.UpdatePriceFloor(big.NewInt(10))
+	assert.Equal(t, 1, demoted)
+	assert.Equal(t, 0, promoted)
+	assert.Equal(t, []*TxTracker{expensiveTx, sponsoredTx}, worker.txSortedList.GetSorted())
+
+	// a demoted tx isn't selected again when its addrQueue is updated
+	worker.applyAddressUpdate(cheapTx.From, nil, new(big.Int).SetInt64(20))
+	assert.Equal(t, 2, worker.txSortedList.len())
+
+	// the demoted txs are promoted back when the floor drops
+	demoted, promoted = worker.UpdatePriceFloor(big.NewInt(5))
+	assert.Equal(t, 0, demoted)
+	assert.Equal(t, 1, promoted)
+	assert.Equal(t, []*TxTracker{expensiveTx, cheapTx, sponsoredTx}, worker.txSortedList.GetSorted())
+
+	// the repricing can be disabled
+	worker.UpdatePriceFloor(big.NewInt(30))
+	assert.Equal(t, []*TxTracker{sponsoredTx}, worker.txSortedList.GetSorted())
+	_, promoted = worker.UpdatePriceFloor(nil)
+	assert.Equal(t, 2, promoted)
+}
+
+func TestWorkerHandleL2Reorg(t *testing.T) {
+	ctx := context.Background()
+	stateMock := NewStateMock(t)
+	worker := initWorker(stateMock, rcMax)
+
+	newTx := func(hash common.Hash, from common.Address, nonce uint64) *TxTracker {
+		return &TxTracker{Hash: hash, HashStr: hash.String(), From: from, FromStr: from.String(), Nonce: nonce, GasPrice: new(big.Int).SetInt64(1), Cost: new(big.Int).SetInt64(1)}
+	}
+
+	// the sender of the reorged tx keeps the txs with the next nonces in the worker
+	addr1Tx1 := newTx(common.Hash{1}, common.Address{1}, 1)
+	addr1Tx2 := newTx(common.Hash{2}, common.Address{1}, 2)
+	addrQueue1 := newAddrQueue(common.Address{1}, 1, new(big.Int).SetInt64(10))
+	addrQueue1.readyTx = addr1Tx1
+	addrQueue1.notReadyTxs[addr1Tx2.Nonce] = addr1Tx2
+	worker.pool[addrQueue1.fromStr] = addrQueue1
+	worker.txSortedList.add(addr1Tx1)
+
+	// the reorged tx is held by the worker
+	addr2Tx := newTx(common.Hash{3}, common.Address{2}, 5)
+	addrQueue2 := newAddrQueue(common.Address{2}, 5, new(big.Int).SetInt64(10))
+	addrQueue2.readyTx = addr2Tx
+	worker.pool[addrQueue2.fromStr] = addrQueue2
+	worker.txSortedList.add(addr2Tx)
+
+	root := common.Hash{0xa}
+	stateMock.On("GetLastStateRoot", ctx, nil).Return(root, nil).Once()
+	stateMock.On("GetNonceByStateRoot", ctx, common.Address{1}, root).Return(new(big.Int).SetUint64(0), nil).Once()
+	stateMock.On("GetBalanceByStateRoot", ctx, common.Address{1}, root).Return(new(big.Int).SetInt64(20), nil).Once()
+
+	reorgedTxs := []*TxTracker{newTx(common.Hash{9}, common.Address{1}, 0), newTx(addr2Tx.Hash, common.Address{2}, 5)}
+	txsToDelete := worker.HandleL2Reorg(ctx, reorgedTxs)
+	assert.Empty(t, txsToDelete)
+
+	// the addrQueue of the reorged tx held by the worker is removed
+	_, found := worker.pool[addrQueue2.fromStr]
+	assert.False(t, found)
+
+	// the txs of the sender wait for the reorged tx to be loaded again
+	assert.False(t, addrQueue1.reorged)
+	assert.Equal(t, uint64(0), addrQueue1.currentNonce)
+	assert.Equal(t, new(big.Int).SetInt64(20), addrQueue1.currentBalance)
+	assert.Nil(t, addrQueue1.readyTx)
+	assert.Len(t, addrQueue1.notReadyTxs, 2)
+	assert.Equal(t, 0, worker.txSortedList.len())
+}
+
+func TestWorkerTxsLimits(t *testing.T) {
+	ctx := context.Background()
+	worker := NewWorker(Config{}, pool.Config{MaxTxsPerAccount: 2}, NewStateMock(t), rcMax)
+
+	from := common.Address{1}
+	newTx := func(hash byte, nonce uint64, gasPrice int64) *TxTracker {
+		return &TxTracker{Hash: common.Hash{hash}, HashStr: common.Hash{hash}.String(), From: from, FromStr: from.String(), Nonce: nonce,
+			GasPrice: new(big.Int).SetInt64(gasPrice), Cost: new(big.Int).SetInt64(1), IP: validIP}
+	}
+	addrQueue := newAddrQueue(from, 1, new(big.Int).SetInt64(10))
+	addrQueue.workerTxs = worker.workerTxs
+	worker.pool[from.String()] = addrQueue
+
+	_, err := worker.AddTxTracker(ctx, newTx(1, 1, 10))
+	require.NoError(t, err)
+	_, err = worker.AddTxTracker(ctx, newTx(3, 3, 5))
+	require.NoError(t, err)
+
+	// the account limit is reached and the new tx has lower gasPrice than the not ready tx
+	_, err = worker.AddTxTracker(ctx, newTx(4, 4, 3))
+	assert.ErrorIs(t, err, ErrAccountTxsLimitReached)
+
+	// the new tx has higher gasPrice than the not ready tx, which is evicted
+	evictedTx, err := worker.AddTxTracker(ctx, newTx(4, 4, 8))
+	require.NoError(t, err)
+	require.NotNil(t, evictedTx)
+	assert.Equal(t, common.Hash{3}, evictedTx.Hash)
+	require.NotNil(t, evictedTx.FailedReason)
+	assert.Equal(t, ErrEvictedTransaction.Error(), *evictedTx.FailedReason)
+
+	// the replacements don't take a new slot
+	replacedTx, err := worker.AddTxTracker(ctx, newTx(5, 4, 9))
+	require.NoError(t, err)
+	require.NotNil(t, replacedTx)
+	assert.Equal(t, common.Hash{4}, replacedTx.Hash)
+	assert.Nil(t, replacedTx.FailedReason)
+
+	worker.maxTxsPerAccount = 0
+	worker.maxTxs = 3
+	_, err = worker.AddTxTracker(ctx, newTx(6, 5, 1))
+	require.NoError(t, err)
+
+	// the worker is full and the new tx has lower gasPrice than all the not ready txs
+	_, err = worker.AddTxTracker(ctx, newTx(7, 6, 0))
+	assert.ErrorIs(t, err, ErrWorkerFull)
+
+	// the not ready tx with the lowest gasPrice is evicted
+	evictedTx, err = worker.AddTxTracker(ctx, newTx(7, 6, 2))
+	require.NoError(t, err)
+	require.NotNil(t, evictedTx)
+	assert.Equal(t, common.Hash{6}, evictedTx.Hash)
+	assert.Equal(t, 3, worker.pool[from.String()].txsCount())
+	assert.Equal(t, 3, worker.workerTxs.count)
+	assert.Equal(t, 2, worker.workerTxs.notReadyTxs.len())
+
+	// the running count follows the txs deleted from the worker
+	worker.DeleteTx(common.Hash{1}, from)
+	assert.Equal(t, 2, worker.workerTxs.count)
+	worker.DeleteTx(common.Hash{7}, from)
+	assert.Equal(t, 1, worker.workerTxs.count)
+	assert.Equal(t, 1, worker.workerTxs.notReadyTxs.len())
+}
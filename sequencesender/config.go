@@ -18,10 +18,9 @@ type Config struct {
 	// to validate whether they fit into the pool or not.
 	MaxTxSizeForL1 uint64 `mapstructure:"MaxTxSizeForL1"`
 	// SenderAddress defines which private key the eth tx manager needs to use
-	// to sign the L1 txs
+	// to sign the L1 txs. The L2 fees recipient sent to L1 is the coinbase of the
+	// sequenced batches, see Sequencer.L2Coinbase
 	SenderAddress common.Address
-	// L2Coinbase defines which address is going to receive the fees
-	L2Coinbase common.Address `mapstructure:"L2Coinbase"`
 	// PrivateKey defines all the key store files that are going
 	// to be read in order to provide the private keys to sign the L1 txs
 	PrivateKey types.KeystoreFileConfig `mapstructure:"PrivateKey"`
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/clock"
 	ethman "github.com/0xPolygonHermez/zkevm-node/etherman"
 	"github.com/0xPolygonHermez/zkevm-node/etherman/types"
 	"github.com/0xPolygonHermez/zkevm-node/ethtxmanager"
@@ -13,6 +15,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
@@ -27,6 +30,10 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+	// ErrBatchTimestampBeforeLastTimestamp is returned if the timestamp of a batch to be
+	// sequenced is lower than the last timestamp sequenced in L1, the rollup contract
+	// would always revert the sequence
+	ErrBatchTimestampBeforeLastTimestamp = errors.New("batch timestamp is lower than the last sequenced timestamp")
 )
 
 // SequenceSender represents a sequence sender
@@ -36,6 +43,7 @@ type SequenceSender struct {
 	ethTxManager ethTxManager
 	etherman     etherman
 	eventLog     *event.EventLog
+	clock        clock.Clock
 }
 
 // New inits sequence sender
@@ -46,6 +54,7 @@ func New(cfg Config, state stateInterface, etherman etherman, manager ethTxManag
 		etherman:     etherman,
 		ethTxManager: manager,
 		eventLog:     eventLog,
+		clock:        clock.System,
 	}, nil
 }
 
@@ -65,6 +74,7 @@ func (s *SequenceSender) tryToSendSequence(ctx context.Context, ticker *time.Tic
 			retry = true
 			mTxResultLogger := ethtxmanager.CreateMonitoredTxResultLogger(ethTxManagerOwner, result)
 			mTxResultLogger.Error("failed to send sequence, TODO: review this fatal and define what to do in this case")
+			s.logL1TxRevertedEvent(ctx, result)
 		}
 	}, nil)
 
@@ -81,7 +91,7 @@ func (s *SequenceSender) tryToSendSequence(ctx context.Context, ticker *time.Tic
 
 	// Check if should send sequence to L1
 	log.Infof("getting sequences to send")
-	sequences, err := s.getSequencesToSend(ctx)
+	sequences, l2Coinbase, err := s.getSequencesToSend(ctx)
 	if err != nil || len(sequences) == 0 {
 		if err != nil {
 			log.Errorf("error getting sequences: %v", err)
@@ -107,7 +117,7 @@ func (s *SequenceSender) tryToSendSequence(ctx context.Context, ticker *time.Tic
 	metrics.SequencesSentToL1(float64(sequenceCount))
 
 	// add sequence to be monitored
-	to, data, err := s.etherman.BuildSequenceBatchesTxData(s.cfg.SenderAddress, sequences, s.cfg.L2Coinbase)
+	to, data, err := s.etherman.BuildSequenceBatchesTxData(s.cfg.SenderAddress, sequences, l2Coinbase)
 	if err != nil {
 		log.Error("error estimating new sequenceBatches to add to eth tx manager: ", err)
 		return
@@ -126,30 +136,45 @@ func (s *SequenceSender) tryToSendSequence(ctx context.Context, ticker *time.Tic
 // getSequencesToSend generates an array of sequences to be send to L1.
 // If the array is empty, it doesn't necessarily mean that there are no sequences to be sent,
 // it could be that it's not worth it to do so yet.
-func (s *SequenceSender) getSequencesToSend(ctx context.Context) ([]types.Sequence, error) {
+func (s *SequenceSender) getSequencesToSend(ctx context.Context) ([]types.Sequence, common.Address, error) {
 	lastVirtualBatchNum, err := s.state.GetLastVirtualBatchNum(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get last virtual batch num, err: %w", err)
+		return nil, common.Address{}, fmt.Errorf("failed to get last virtual batch num, err: %w", err)
 	}
 
 	currentBatchNumToSequence := lastVirtualBatchNum + 1
 	sequences := []types.Sequence{}
+	// all the batches of a sequence must have been processed with the same coinbase,
+	// it's the L2 fees recipient sent to L1 that is used to verify them
+	var l2Coinbase common.Address
 	// var estimatedGas uint64
 
 	var tx *ethTypes.Transaction
 
+	// The rollup contract reverts the sequence if a batch timestamp is lower than the
+	// previous one or higher than the timestamp of the L1 block including it, so the
+	// batches are validated before estimating instead of looping on reverted txs
+	lastTimestamp, err := s.etherman.GetLastBatchTimestamp()
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get last batch timestamp from L1, err: %w", err)
+	}
+	l1Timestamp, err := s.etherman.GetLatestBlockTimestamp(ctx)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get latest L1 block timestamp, err: %w", err)
+	}
+
 	// Add sequences until too big for a single L1 tx or last batch is reached
 	for {
 		//Check if the next batch belongs to a new forkid, in this case we need to stop sequencing as we need to
 		//wait the upgrade of forkid is completed and s.cfg.NumBatchForkIdUpgrade is disabled (=0) again
 		if (s.cfg.ForkUpgradeBatchNumber != 0) && (currentBatchNumToSequence == (s.cfg.ForkUpgradeBatchNumber + 1)) {
-			return nil, fmt.Errorf("aborting sequencing process as we reached the batch %d where a new forkid is applied (upgrade)", s.cfg.ForkUpgradeBatchNumber+1)
+			return nil, common.Address{}, fmt.Errorf("aborting sequencing process as we reached the batch %d where a new forkid is applied (upgrade)", s.cfg.ForkUpgradeBatchNumber+1)
 		}
 
 		// Check if batch is closed
 		isClosed, err := s.state.IsBatchClosed(ctx, currentBatchNumToSequence, nil)
 		if err != nil {
-			return nil, err
+			return nil, common.Address{}, err
 		}
 		if !isClosed {
 			// Reached current (WIP) batch
@@ -158,8 +183,29 @@ func (s *SequenceSender) getSequencesToSend(ctx context.Context) ([]types.Sequen
 		// Add new sequence
 		batch, err := s.state.GetBatchByNumber(ctx, currentBatchNumToSequence, nil)
 		if err != nil {
-			return nil, err
+			return nil, common.Address{}, err
+		}
+		if len(sequences) == 0 {
+			l2Coinbase = batch.Coinbase
+		} else if batch.Coinbase != l2Coinbase {
+			log.Infof("sequence should be sent to L1, as batch %d has a different coinbase %s than the previous ones %s",
+				batch.BatchNumber, batch.Coinbase.String(), l2Coinbase.String())
+			return sequences, l2Coinbase, nil
+		}
+
+		batchTimestamp := uint64(batch.Timestamp.Unix())
+		if batchTimestamp < lastTimestamp {
+			return nil, common.Address{}, fmt.Errorf("%w: batch %d timestamp %d, last timestamp %d",
+				ErrBatchTimestampBeforeLastTimestamp, batch.BatchNumber, batchTimestamp, lastTimestamp)
+		}
+		if batchTimestamp > l1Timestamp {
+			// The batch can't be sequenced until L1 time reaches its timestamp,
+			// the sequence is scheduled with the batches that are valid now
+			log.Infof("batch %d timestamp %d is ahead of the latest L1 block timestamp %d, waiting for L1 to sequence it",
+				batch.BatchNumber, batchTimestamp, l1Timestamp)
+			break
 		}
+		lastTimestamp = batchTimestamp
 
 		seq := types.Sequence{
 			GlobalExitRoot: batch.GlobalExitRoot,
@@ -171,14 +217,14 @@ func (s *SequenceSender) getSequencesToSend(ctx context.Context) ([]types.Sequen
 		if batch.ForcedBatchNum != nil {
 			forcedBatch, err := s.state.GetForcedBatch(ctx, *batch.ForcedBatchNum, nil)
 			if err != nil {
-				return nil, err
+				return nil, common.Address{}, err
 			}
 			seq.ForcedBatchTimestamp = forcedBatch.ForcedAt.Unix()
 		}
 
 		sequences = append(sequences, seq)
 		// Check if can be send
-		tx, err = s.etherman.EstimateGasSequenceBatches(s.cfg.SenderAddress, sequences, s.cfg.L2Coinbase)
+		tx, err = s.etherman.EstimateGasSequenceBatches(s.cfg.SenderAddress, sequences, l2Coinbase)
 		if err == nil && tx.Size() > s.cfg.MaxTxSizeForL1 {
 			metrics.SequencesOvesizedDataError()
 			log.Infof("oversized Data on TX oldHash %s (txSize %d > %d)", tx.Hash(), tx.Size(), s.cfg.MaxTxSizeForL1)
@@ -189,17 +235,17 @@ func (s *SequenceSender) getSequencesToSend(ctx context.Context) ([]types.Sequen
 			sequences, err = s.handleEstimateGasSendSequenceErr(ctx, sequences, currentBatchNumToSequence, err)
 			if sequences != nil {
 				// Handling the error gracefully, re-processing the sequence as a sanity check
-				_, err = s.etherman.EstimateGasSequenceBatches(s.cfg.SenderAddress, sequences, s.cfg.L2Coinbase)
-				return sequences, err
+				_, err = s.etherman.EstimateGasSequenceBatches(s.cfg.SenderAddress, sequences, l2Coinbase)
+				return sequences, l2Coinbase, err
 			}
-			return sequences, err
+			return sequences, l2Coinbase, err
 		}
 		// estimatedGas = tx.Gas()
 
 		//Check if the current batch is the last before a change to a new forkid, in this case we need to close and send the sequence to L1
 		if (s.cfg.ForkUpgradeBatchNumber != 0) && (currentBatchNumToSequence == (s.cfg.ForkUpgradeBatchNumber)) {
 			log.Infof("sequence should be sent to L1, as we have reached the batch %d from which a new forkid is applied (upgrade)", s.cfg.ForkUpgradeBatchNumber)
-			return sequences, nil
+			return sequences, l2Coinbase, nil
 		}
 
 		// Increase batch num for next iteration
@@ -209,24 +255,24 @@ func (s *SequenceSender) getSequencesToSend(ctx context.Context) ([]types.Sequen
 	// Reached latest batch. Decide if it's worth to send the sequence, or wait for new batches
 	if len(sequences) == 0 {
 		log.Info("no batches to be sequenced")
-		return nil, nil
+		return nil, common.Address{}, nil
 	}
 
 	lastBatchVirtualizationTime, err := s.state.GetTimeForLatestBatchVirtualization(ctx, nil)
 	if err != nil && !errors.Is(err, state.ErrNotFound) {
 		log.Warnf("failed to get last l1 interaction time, err: %v. Sending sequences as a conservative approach", err)
-		return sequences, nil
+		return sequences, l2Coinbase, nil
 	}
-	if lastBatchVirtualizationTime.Before(time.Now().Add(-s.cfg.LastBatchVirtualizationTimeMaxWaitPeriod.Duration)) {
+	if lastBatchVirtualizationTime.Before(s.clock.Now().Add(-s.cfg.LastBatchVirtualizationTimeMaxWaitPeriod.Duration)) {
 		// TODO: implement check profitability
 		// if s.checker.IsSendSequencesProfitable(new(big.Int).SetUint64(estimatedGas), sequences) {
 		log.Info("sequence should be sent to L1, because too long since didn't send anything to L1")
-		return sequences, nil
+		return sequences, l2Coinbase, nil
 		//}
 	}
 
 	log.Info("not enough time has passed since last batch was virtualized, and the sequence could be bigger")
-	return nil, nil
+	return nil, common.Address{}, nil
 }
 
 // handleEstimateGasSendSequenceErr handles an error on the estimate gas. It will return:
@@ -336,3 +382,22 @@ func (s *SequenceSender) isSynced(ctx context.Context) bool {
 
 	return true
 }
+
+// logL1TxRevertedEvent logs the event for a sequence tx reverted in L1 including the decoded revert messages
+func (s *SequenceSender) logL1TxRevertedEvent(ctx context.Context, result ethtxmanager.MonitoredTxResult) {
+	if s.eventLog == nil {
+		return
+	}
+
+	ev := &event.Event{
+		ReceivedAt:  time.Now(),
+		Source:      event.Source_Node,
+		Component:   event.Component_Sequence_Sender,
+		Level:       event.Level_Error,
+		EventID:     event.EventID_L1TxReverted,
+		Description: fmt.Sprintf("sequence tx %s reverted: %s", result.ID, strings.Join(result.RevertMessages(), "; ")),
+	}
+	if err := s.eventLog.LogEvent(ctx, ev); err != nil {
+		log.Errorf("error storing event: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package state
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AccountProof contains the merkle proofs of the leaves of an account and of
+// some of its storage slots in the state tree
+type AccountProof struct {
+	Address         common.Address
+	Balance         *big.Int
+	Nonce           *big.Int
+	CodeHash        common.Hash
+	CodeLength      *big.Int
+	BalanceProof    *merkletree.KeyProof
+	NonceProof      *merkletree.KeyProof
+	CodeHashProof   *merkletree.KeyProof
+	CodeLengthProof *merkletree.KeyProof
+	StorageProofs   []StorageProof
+}
+
+// StorageProof contains the merkle proof of a storage slot of an account
+type StorageProof struct {
+	Key   common.Hash
+	Value *big.Int
+	Proof *merkletree.KeyProof
+}
+
+// GetProof returns the merkle proofs of the account leaves of the given address
+// and of the provided storage keys in the state tree with the given root
+func (s *State) GetProof(ctx context.Context, address common.Address, storageKeys []common.Hash, root common.Hash) (*AccountProof, error) {
+	if s.tree == nil {
+		return nil, ErrStateTreeNil
+	}
+
+	getLeafProof := func(key []byte, err error) (*merkletree.KeyProof, *big.Int, error) {
+		if err != nil {
+			return nil, nil, err
+		}
+		proof, err := s.tree.GetProof(ctx, key, root.Bytes())
+		if err != nil {
+			return nil, nil, err
+		}
+		value := proof.Value
+		if value == nil {
+			value = big.NewInt(0)
+		}
+		return proof, value, nil
+	}
+
+	accountProof := &AccountProof{Address: address}
+	var err error
+	if accountProof.BalanceProof, accountProof.Balance, err = getLeafProof(merkletree.KeyEthAddrBalance(address)); err != nil {
+		return nil, err
+	}
+	if accountProof.NonceProof, accountProof.Nonce, err = getLeafProof(merkletree.KeyEthAddrNonce(address)); err != nil {
+		return nil, err
+	}
+	var codeHash *big.Int
+	if accountProof.CodeHashProof, codeHash, err = getLeafProof(merkletree.KeyContractCode(address)); err != nil {
+		return nil, err
+	}
+	accountProof.CodeHash = common.BigToHash(codeHash)
+	if accountProof.CodeLengthProof, accountProof.CodeLength, err = getLeafProof(merkletree.KeyCodeLength(address)); err != nil {
+		return nil, err
+	}
+
+	accountProof.StorageProofs = make([]StorageProof, 0, len(storageKeys))
+	for _, storageKey := range storageKeys {
+		proof, value, err := getLeafProof(merkletree.KeyContractStorage(address, storageKey.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		accountProof.StorageProofs = append(accountProof.StorageProofs, StorageProof{Key: storageKey, Value: value, Proof: proof})
+	}
+
+	return accountProof, nil
+}
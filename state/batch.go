@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/grpcclient"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -66,6 +69,18 @@ const (
 	TimeoutResolutionDeadlineClosingReason ClosingReason = "timeout resolution deadline"
 	// GlobalExitRootDeadlineClosingReason is the closing reason used when Global Exit Root deadline is reached
 	GlobalExitRootDeadlineClosingReason ClosingReason = "Global Exit Root deadline"
+	// IdleDeadlineClosingReason is the closing reason used when no txs were added to the batch for too long
+	IdleDeadlineClosingReason ClosingReason = "idle deadline"
+	// MaxOpenTimeDeadlineClosingReason is the closing reason used when the batch was open for too long
+	MaxOpenTimeDeadlineClosingReason ClosingReason = "max open time deadline"
+	// KeepAliveDeadlineClosingReason is the closing reason used when an empty batch is closed because no batches were closed for too long
+	KeepAliveDeadlineClosingReason ClosingReason = "keep alive deadline"
+	// ProvingBudgetClosingReason is the closing reason used when the batch reached the max proving complexity
+	ProvingBudgetClosingReason ClosingReason = "proving budget"
+	// UtilizationTargetClosingReason is the closing reason used when a resource of the batch reached its target utilization and no tx fits in the remaining resources
+	UtilizationTargetClosingReason ClosingReason = "utilization target"
+	// GracefulShutdownClosingReason is the closing reason used when the batch is closed because the sequencer is stopped
+	GracefulShutdownClosingReason ClosingReason = "graceful shutdown"
 )
 
 // ProcessingReceipt indicates the outcome (StateRoot, AccInputHash) of processing a batch
@@ -255,6 +270,9 @@ func (s *State) ExecuteBatch(ctx context.Context, batch Batch, updateMerkleTree
 	processBatchResponse, err := s.executorClient.ProcessBatch(ctx, processBatchRequest)
 	if err != nil {
 		log.Error("error executing batch: ", err)
+		if grpcclient.IsMessageTooLarge(err) {
+			return nil, fmt.Errorf("%w: %v", runtime.ErrGRPCMessageTooLarge, err)
+		}
 		return nil, err
 	} else if processBatchResponse != nil && processBatchResponse.Error != executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR {
 		err = executor.ExecutorErr(processBatchResponse.Error)
@@ -343,12 +361,18 @@ func (s *State) sendBatchRequestToExecutor(ctx context.Context, processBatchRequ
 		log.Debugf("processBatch[processBatchRequest.ForkId]: %v", processBatchRequest.ForkId)
 		log.Debugf("processBatch[processBatchRequest.ContextId]: %v", processBatchRequest.ContextId)
 	}
+	origin := executorOrigin(caller)
+	debugFlags := s.executorDebugStatus(origin).DebugFlags
 	now := time.Now()
-	res, err := s.executorClient.ProcessBatch(ctx, processBatchRequest)
+	res, err := s.executorClient.ProcessBatch(executor.WithDebugFlags(ctx, debugFlags), processBatchRequest)
 	if err != nil {
 		log.Errorf("Error s.executorClient.ProcessBatch: %v", err)
 		log.Errorf("Error s.executorClient.ProcessBatch: %s", err.Error())
 		log.Errorf("Error s.executorClient.ProcessBatch response: %v", res)
+		if grpcclient.IsMessageTooLarge(err) {
+			log.Errorf("batch %d request size %d bytes", processBatchRequest.OldBatchNum+1, proto.Size(processBatchRequest))
+			err = fmt.Errorf("%w: %v", runtime.ErrGRPCMessageTooLarge, err)
+		}
 	} else if res.Error != executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR {
 		err = executor.ExecutorErr(res.Error)
 		s.eventLog.LogExecutorError(ctx, res.Error, processBatchRequest)
@@ -358,6 +382,9 @@ func (s *State) sendBatchRequestToExecutor(ctx context.Context, processBatchRequ
 		metrics.ExecutorProcessingTime(string(caller), elapsed)
 	}
 	log.Infof("Batch: %d took %v to be processed by the executor ", processBatchRequest.OldBatchNum+1, elapsed)
+	if err == nil && debugFlags.PersistFailedTxTraces {
+		go s.persistFailedTxTraces(context.Background(), origin, processBatchRequest, res)
+	}
 
 	return res, err
 }
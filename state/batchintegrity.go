@@ -0,0 +1,131 @@
+package state
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+)
+
+// SequenceDataIntegrity is the outcome of checking the data stored for the batches of
+// a sequence against the accumulated input hash stored in L1 for the sequence
+type SequenceDataIntegrity struct {
+	FromBatchNumber uint64
+	ToBatchNumber   uint64
+	// L1AccInputHash is the accumulated input hash stored in L1 for the last batch of the sequence
+	L1AccInputHash common.Hash
+	// AccInputHash is the accumulated input hash computed from the stored batch data
+	AccInputHash common.Hash
+	// MismatchedBatches are the batches whose stored accumulated input hash doesn't match
+	// the one computed from their data
+	MismatchedBatches []uint64
+}
+
+// IsValid returns true if the stored batch data matches the accumulated input hash progression
+func (i SequenceDataIntegrity) IsValid() bool {
+	return i.AccInputHash == i.L1AccInputHash && len(i.MismatchedBatches) == 0
+}
+
+// CalculateAccInputHash computes the accumulated input hash of a batch the same way the
+// PoE smart contract does when the batch is sequenced
+func CalculateAccInputHash(oldAccInputHash common.Hash, batchL2Data []byte, globalExitRoot common.Hash, timestamp uint64, coinbase common.Address) common.Hash {
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], timestamp)
+
+	return crypto.Keccak256Hash(
+		oldAccInputHash.Bytes(),
+		crypto.Keccak256(batchL2Data),
+		globalExitRoot.Bytes(),
+		ts[:],
+		coinbase.Bytes(),
+	)
+}
+
+// CheckSequenceDataIntegrity recomputes the accumulated input hash progression of the batches of
+// the sequence from their stored data, starting at oldAccInputHash, and compares it with the
+// accumulated input hash stored in L1 for the last batch of the sequence. The coinbase used is the
+// one of the L1 sequencing event of each batch
+func (s *State) CheckSequenceDataIntegrity(ctx context.Context, sequence Sequence, oldAccInputHash, l1AccInputHash common.Hash, dbTx pgx.Tx) (*SequenceDataIntegrity, error) {
+	result := &SequenceDataIntegrity{
+		FromBatchNumber:   sequence.FromBatchNumber,
+		ToBatchNumber:     sequence.ToBatchNumber,
+		L1AccInputHash:    l1AccInputHash,
+		MismatchedBatches: []uint64{},
+	}
+
+	accInputHash := oldAccInputHash
+	for batchNumber := sequence.FromBatchNumber; batchNumber <= sequence.ToBatchNumber; batchNumber++ {
+		virtualBatch, err := s.GetVirtualBatch(ctx, batchNumber, dbTx)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("batch %d: %w", batchNumber, ErrBatchNotVirtualized)
+		} else if err != nil {
+			return nil, err
+		}
+
+		batch, err := s.GetBatchByNumber(ctx, batchNumber, dbTx)
+		if err != nil {
+			return nil, err
+		}
+
+		accInputHash = CalculateAccInputHash(accInputHash, batch.BatchL2Data, batch.GlobalExitRoot, uint64(batch.Timestamp.Unix()), virtualBatch.Coinbase)
+		// the accumulated input hash is not stored for the batches that were not executed yet
+		if batch.AccInputHash != ZeroHash && batch.AccInputHash != accInputHash {
+			result.MismatchedBatches = append(result.MismatchedBatches, batchNumber)
+		}
+	}
+	result.AccInputHash = accInputHash
+
+	return result, nil
+}
+
+// AccInputHashDivergence is a sequence whose accumulated input hash computed locally doesn't match
+// the one stored in L1 when it was sequenced
+type AccInputHashDivergence struct {
+	// BatchNumber is the last batch of the sequence
+	BatchNumber uint64
+	// AccInputHash is the accumulated input hash computed locally for the batch
+	AccInputHash common.Hash
+	// L1AccInputHash is the accumulated input hash stored in L1 for the batch
+	L1AccInputHash common.Hash
+}
+
+// CompareAccInputHashes compares the accumulated input hash computed locally for the last batch of each
+// sequence including any batch of the range with the one stored in L1, returned by l1AccInputHash.
+// It stops at the first divergence, which is returned, or at the first sequence whose last batch
+// was not executed yet. It returns the last batch compared too, zero if none
+func (s *State) CompareAccInputHashes(ctx context.Context, fromBatchNumber, toBatchNumber uint64, l1AccInputHash func(ctx context.Context, batchNumber uint64) (common.Hash, error), dbTx pgx.Tx) (uint64, *AccInputHashDivergence, error) {
+	sequences, err := s.GetSequencesInRange(ctx, fromBatchNumber, toBatchNumber, dbTx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var lastComparedBatchNumber uint64
+	for _, sequence := range sequences {
+		batch, err := s.GetBatchByNumber(ctx, sequence.ToBatchNumber, dbTx)
+		if err != nil {
+			return lastComparedBatchNumber, nil, err
+		}
+		if batch.AccInputHash == ZeroHash {
+			break
+		}
+
+		l1Hash, err := l1AccInputHash(ctx, sequence.ToBatchNumber)
+		if err != nil {
+			return lastComparedBatchNumber, nil, fmt.Errorf("failed to get the L1 accumulated input hash of batch %d, %w", sequence.ToBatchNumber, err)
+		}
+		if l1Hash != batch.AccInputHash {
+			return lastComparedBatchNumber, &AccInputHashDivergence{
+				BatchNumber:    sequence.ToBatchNumber,
+				AccInputHash:   batch.AccInputHash,
+				L1AccInputHash: l1Hash,
+			}, nil
+		}
+		lastComparedBatchNumber = sequence.ToBatchNumber
+	}
+
+	return lastComparedBatchNumber, nil, nil
+}
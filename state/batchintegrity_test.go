@@ -0,0 +1,38 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateAccInputHash(t *testing.T) {
+	oldAccInputHash := common.HexToHash("0x1")
+	batchL2Data := []byte{0xee, 0x80, 0x84}
+	ger := common.HexToHash("0x2")
+	coinbase := common.HexToAddress("0x617b3a3528F9cDd6630fd3301B9c8911F7Bf063D")
+
+	// abi.encodePacked(oldAccInputHash, keccak256(batchL2Data), globalExitRoot, uint64 timestamp, coinbase)
+	packed := append([]byte{}, oldAccInputHash.Bytes()...)
+	packed = append(packed, crypto.Keccak256(batchL2Data)...)
+	packed = append(packed, ger.Bytes()...)
+	packed = append(packed, 0, 0, 0, 0, 0x65, 0x4b, 0x3a, 0x10)
+	packed = append(packed, coinbase.Bytes()...)
+
+	accInputHash := state.CalculateAccInputHash(oldAccInputHash, batchL2Data, ger, 0x654b3a10, coinbase)
+	assert.Equal(t, crypto.Keccak256Hash(packed), accInputHash)
+
+	// the hash depends on the previous accumulated input hash
+	assert.NotEqual(t, accInputHash, state.CalculateAccInputHash(common.Hash{}, batchL2Data, ger, 0x654b3a10, coinbase))
+
+	// the integrity is broken when the computed hash differs from the L1 one
+	integrity := state.SequenceDataIntegrity{L1AccInputHash: accInputHash, AccInputHash: accInputHash}
+	assert.True(t, integrity.IsValid())
+	integrity.MismatchedBatches = []uint64{3}
+	assert.False(t, integrity.IsValid())
+	integrity = state.SequenceDataIntegrity{L1AccInputHash: accInputHash}
+	assert.False(t, integrity.IsValid())
+}
@@ -0,0 +1,32 @@
+package state
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BatchLifecycleStage is a stage reached by a batch since it's closed in the trusted state
+// until its state is verified in L1
+type BatchLifecycleStage string
+
+const (
+	// BatchTrustedStage is reached when the batch is closed in the trusted state
+	BatchTrustedStage BatchLifecycleStage = "trusted"
+	// BatchVirtualizedStage is reached when the batch is sequenced in L1
+	BatchVirtualizedStage BatchLifecycleStage = "virtualized"
+	// BatchProvenStage is reached when the proof of the batch is generated by the aggregator
+	BatchProvenStage BatchLifecycleStage = "proven"
+	// BatchVerifiedStage is reached when the final proof including the batch is verified in L1
+	BatchVerifiedStage BatchLifecycleStage = "verified"
+)
+
+// BatchLifecycleTransition is the transition of a batch to a stage of its lifecycle, along with
+// the L1 tx and block where it happened for the stages reached in L1
+type BatchLifecycleTransition struct {
+	BatchNumber   uint64
+	Stage         BatchLifecycleStage
+	L1TxHash      *common.Hash
+	L1BlockNumber *uint64
+	Timestamp     time.Time
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman/smartcontracts/polygonzkevmbridge"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// bridgeEventSignatureHash is the topic of the event emitted by the bridge for each deposit
+	bridgeEventSignatureHash = common.HexToHash("0x501781209a1f8899323b96b4ef08b168df93e0a90c673d1e4cce39366cb62f9b")
+	// claimEventSignatureHash is the topic of the event emitted by the bridge for each claim
+	claimEventSignatureHash = common.HexToHash("0x25308c93ceeed162da955b3f7ce3e3f93606579e40fb92029faa9efe27545983")
+)
+
+// BridgeDeposit is a deposit made in the L2 bridge to be claimed in another network
+type BridgeDeposit struct {
+	LeafType           uint8
+	OriginNetwork      uint32
+	OriginAddress      common.Address
+	DestinationNetwork uint32
+	DestinationAddress common.Address
+	Amount             *big.Int
+	Metadata           []byte
+	DepositCount       uint32
+	BlockNumber        uint64
+	TxHash             common.Hash
+	LogIndex           uint
+}
+
+// BridgeClaim is a claim made in the L2 bridge of a deposit from another network
+type BridgeClaim struct {
+	Index              uint32
+	OriginNetwork      uint32
+	OriginAddress      common.Address
+	DestinationAddress common.Address
+	Amount             *big.Int
+	BlockNumber        uint64
+	TxHash             common.Hash
+	LogIndex           uint
+}
+
+// bridgeEventsFromLogs decodes the deposit and claim events emitted by the bridge in the logs
+func bridgeEventsFromLogs(bridgeAddr common.Address, blockNumber uint64, logs []*types.Log) ([]BridgeDeposit, []BridgeClaim, error) {
+	var (
+		deposits []BridgeDeposit
+		claims   []BridgeClaim
+		filterer *polygonzkevmbridge.PolygonzkevmbridgeFilterer
+	)
+	for _, l := range logs {
+		if l.Address != bridgeAddr || len(l.Topics) == 0 {
+			continue
+		}
+		if l.Topics[0] != bridgeEventSignatureHash && l.Topics[0] != claimEventSignatureHash {
+			continue
+		}
+		if filterer == nil {
+			var err error
+			filterer, err = polygonzkevmbridge.NewPolygonzkevmbridgeFilterer(bridgeAddr, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		switch l.Topics[0] {
+		case bridgeEventSignatureHash:
+			deposit, err := filterer.ParseBridgeEvent(*l)
+			if err != nil {
+				return nil, nil, err
+			}
+			deposits = append(deposits, BridgeDeposit{
+				LeafType:           deposit.LeafType,
+				OriginNetwork:      deposit.OriginNetwork,
+				OriginAddress:      deposit.OriginAddress,
+				DestinationNetwork: deposit.DestinationNetwork,
+				DestinationAddress: deposit.DestinationAddress,
+				Amount:             deposit.Amount,
+				Metadata:           deposit.Metadata,
+				DepositCount:       deposit.DepositCount,
+				BlockNumber:        blockNumber,
+				TxHash:             l.TxHash,
+				LogIndex:           l.Index,
+			})
+		case claimEventSignatureHash:
+			claim, err := filterer.ParseClaimEvent(*l)
+			if err != nil {
+				return nil, nil, err
+			}
+			claims = append(claims, BridgeClaim{
+				Index:              claim.Index,
+				OriginNetwork:      claim.OriginNetwork,
+				OriginAddress:      claim.OriginAddress,
+				DestinationAddress: claim.DestinationAddress,
+				Amount:             claim.Amount,
+				BlockNumber:        blockNumber,
+				TxHash:             l.TxHash,
+				LogIndex:           l.Index,
+			})
+		}
+	}
+	return deposits, claims, nil
+}
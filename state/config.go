@@ -1,8 +1,11 @@
 package state
 
 import (
+	"fmt"
+
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // Config is state config
@@ -45,6 +48,44 @@ type Config struct {
 	// MaxNativeBlockHashBlockRange is a configuration to set the max range for block number when querying
 	// native block hashes in a single call to the state, if zero it means no limit
 	MaxNativeBlockHashBlockRange uint64
+
+	// EstimateGasCap is the max gas limit tried by the gas estimation, if zero the max gas allowed per batch is used
+	EstimateGasCap uint64
+
+	// EstimateGasErrorRatio is the max ratio between the range of the gas estimation search and the estimated gas,
+	// a higher ratio executes the tx fewer times but can overestimate the gas. If zero the exact gas is searched
+	EstimateGasErrorRatio float64
+
+	// BridgeIndexing configuration of the indexing of the L2 bridge events
+	BridgeIndexing BridgeIndexingConfig `mapstructure:"BridgeIndexing"`
+
+	// Pruning configuration of the deletion of the historical data no longer needed by the node
+	Pruning PruningConfig `mapstructure:"Pruning"`
+}
+
+// PruningConfig represents the configuration of the pruning of the proving times, lifecycle
+// transitions and proofs of the verified batches, the data of the batches not verified yet
+// is never pruned
+type PruningConfig struct {
+	// Enabled defines if the historical data is pruned
+	Enabled bool `mapstructure:"Enabled"`
+
+	// Interval is the time between two prunings
+	Interval types.Duration `mapstructure:"Interval"`
+
+	// Retention is the time the historical data is kept since it's stored
+	Retention types.Duration `mapstructure:"Retention"`
+}
+
+// BridgeIndexingConfig represents the configuration of the indexing of the deposit and
+// claim events of the L2 bridge, so they can be queried without the bridge service
+type BridgeIndexingConfig struct {
+	// Enabled defines if the bridge events are indexed when the L2 blocks are stored
+	Enabled bool `mapstructure:"Enabled"`
+
+	// L2BridgeAddr is the address of the bridge smart contract in L2, it is
+	// taken from the network config
+	L2BridgeAddr common.Address
 }
 
 // BatchConfig represents the configuration of the batch constraints
@@ -77,3 +118,23 @@ func (c BatchConstraintsCfg) IsWithinConstraints(counters ZKCounters) bool {
 		counters.UsedBinaries <= c.MaxBinaries &&
 		counters.UsedSteps <= c.MaxSteps
 }
+
+// ExceededConstraints describes the counters over their batch constraint, with the used and the max
+// values and the excess, it's empty if the counters are within the batch constraints
+func (c BatchConstraintsCfg) ExceededConstraints(counters ZKCounters) []string {
+	exceeded := []string{}
+	check := func(name string, used, maxValue uint64) {
+		if used > maxValue {
+			exceeded = append(exceeded, fmt.Sprintf("%s %d exceeds the max %d by %d", name, used, maxValue, used-maxValue))
+		}
+	}
+	check("CumulativeGasUsed", counters.CumulativeGasUsed, c.MaxCumulativeGasUsed)
+	check("KeccakHashes", uint64(counters.UsedKeccakHashes), uint64(c.MaxKeccakHashes))
+	check("PoseidonHashes", uint64(counters.UsedPoseidonHashes), uint64(c.MaxPoseidonHashes))
+	check("PoseidonPaddings", uint64(counters.UsedPoseidonPaddings), uint64(c.MaxPoseidonPaddings))
+	check("MemAligns", uint64(counters.UsedMemAligns), uint64(c.MaxMemAligns))
+	check("Arithmetics", uint64(counters.UsedArithmetics), uint64(c.MaxArithmetics))
+	check("Binaries", uint64(counters.UsedBinaries), uint64(c.MaxBinaries))
+	check("Steps", uint64(counters.UsedSteps), uint64(c.MaxSteps))
+	return exceeded
+}
@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 
 	"github.com/0xPolygonHermez/zkevm-data-streamer/datastreamer"
 	"github.com/0xPolygonHermez/zkevm-node/log"
@@ -23,6 +24,8 @@ const (
 	EntryTypeL2BlockEnd datastreamer.EntryType = 3
 	// EntryTypeUpdateGER represents a GER update
 	EntryTypeUpdateGER datastreamer.EntryType = 4
+	// EntryTypeRollback represents a rollback of the entries overwritten by a reorg of the trusted state
+	EntryTypeRollback datastreamer.EntryType = 5
 	// BookMarkTypeL2Block represents a L2 block bookmark
 	BookMarkTypeL2Block byte = 0
 )
@@ -189,6 +192,29 @@ func (g DSUpdateGER) Decode(data []byte) DSUpdateGER {
 	return g
 }
 
+// DSRollback represents a rollback of the data stream after a reorg of the trusted state, the
+// L2 blocks after the L2 block number and the GER updates of the batches after the batch number
+// sent before it must be discarded, the entries after it replace them
+type DSRollback struct {
+	BatchNumber   uint64 // 8 bytes
+	L2BlockNumber uint64 // 8 bytes
+}
+
+// Encode returns the encoded DSRollback as a byte slice
+func (r DSRollback) Encode() []byte {
+	bytes := make([]byte, 0)
+	bytes = binary.LittleEndian.AppendUint64(bytes, r.BatchNumber)
+	bytes = binary.LittleEndian.AppendUint64(bytes, r.L2BlockNumber)
+	return bytes
+}
+
+// Decode decodes the DSRollback from a byte slice
+func (r DSRollback) Decode(data []byte) DSRollback {
+	r.BatchNumber = binary.LittleEndian.Uint64(data[0:8])
+	r.L2BlockNumber = binary.LittleEndian.Uint64(data[8:16])
+	return r
+}
+
 // DSState gathers the methods required to interact with the data stream state.
 type DSState interface {
 	GetDSGenesisBlock(ctx context.Context, dbTx pgx.Tx) (*DSL2Block, error)
@@ -203,6 +229,8 @@ func GenerateDataStreamerFile(ctx context.Context, streamServer *datastreamer.St
 
 	var currentBatchNumber uint64 = 0
 	var currentL2Block uint64 = 0
+	// replayCurrentBatch is set when the L2 blocks of the current batch after the current one must be sent again
+	var replayCurrentBatch = false
 
 	if header.TotalEntries == 0 {
 		// Get Genesis block
@@ -284,6 +312,34 @@ func GenerateDataStreamerFile(ctx context.Context, streamServer *datastreamer.St
 			}
 			currentBatchNumber = binary.LittleEndian.Uint64(firstEntry.Data[0:8])
 		}
+
+		rollback, batchComplete, err := findDSRollback(ctx, streamServer, stateDB, header.TotalEntries)
+		if err != nil {
+			return err
+		}
+		if rollback != nil {
+			log.Warnf("Data stream entries overwritten by a reorg of the trusted state, rolling back to batch %d and L2 block %d",
+				rollback.BatchNumber, rollback.L2BlockNumber)
+
+			err = streamServer.StartAtomicOp()
+			if err != nil {
+				return err
+			}
+
+			_, err = streamServer.AddStreamEntry(EntryTypeRollback, rollback.Encode())
+			if err != nil {
+				return err
+			}
+
+			err = streamServer.CommitAtomicOp()
+			if err != nil {
+				return err
+			}
+
+			currentBatchNumber = rollback.BatchNumber
+			currentL2Block = rollback.L2BlockNumber
+			replayCurrentBatch = !batchComplete
+		}
 	}
 
 	log.Infof("Current Batch number: %d", currentBatchNumber)
@@ -298,7 +354,9 @@ func GenerateDataStreamerFile(ctx context.Context, streamServer *datastreamer.St
 	}
 
 	// Start on the current batch number + 1
-	currentBatchNumber++
+	if !replayCurrentBatch {
+		currentBatchNumber++
+	}
 
 	var err error
 
@@ -323,6 +381,15 @@ func GenerateDataStreamerFile(ctx context.Context, streamServer *datastreamer.St
 
 		currentBatchNumber++
 
+		if replayCurrentBatch {
+			// Skip the L2 blocks of the batch that weren't overwritten
+			for len(l2blocks) > 0 && l2blocks[0].L2BlockNumber <= currentL2Block {
+				currentGER = l2blocks[0].GlobalExitRoot
+				l2blocks = l2blocks[1:]
+			}
+			replayCurrentBatch = false
+		}
+
 		if len(l2blocks) == 0 {
 			// Empty batch
 			// Check if there is a GER update
@@ -418,3 +485,92 @@ func GenerateDataStreamerFile(ctx context.Context, streamServer *datastreamer.St
 
 	return err
 }
+
+// findDSRollback walks back the entries of the data stream until the last one matching the trusted
+// state. It returns nil when the latest entry matches, otherwise the rollback to the last entry
+// matching and whether its batch was completely sent, the entries after it must be sent again
+func findDSRollback(ctx context.Context, streamServer *datastreamer.StreamServer, stateDB DSState, totalEntries uint64) (*DSRollback, bool, error) {
+	var (
+		blockEnd *DSL2BlockEnd
+		// rolledBack is the oldest rollback after the current entry, the entries it discarded are skipped
+		rolledBack *DSRollback
+		// gerRollback is the rollback to a GER update matching the state, waiting for the L2 block before it
+		gerRollback *DSRollback
+		mismatch    bool
+		batchBlocks = map[uint64]map[uint64]common.Hash{}
+	)
+
+	for entryNumber := totalEntries; entryNumber > 0; {
+		entryNumber--
+		entry, err := streamServer.GetEntry(entryNumber)
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch entry.Type {
+		case EntryTypeRollback:
+			rollback := DSRollback{}.Decode(entry.Data)
+			// the entries sent again after a rollback may be incomplete
+			mismatch = true
+			if rolledBack == nil || rollback.L2BlockNumber < rolledBack.L2BlockNumber {
+				rolledBack = &rollback
+			}
+		case EntryTypeL2BlockEnd:
+			end := DSL2BlockEnd{}.Decode(entry.Data)
+			if gerRollback != nil {
+				gerRollback.L2BlockNumber = end.L2BlockNumber
+				return gerRollback, true, nil
+			}
+			blockEnd = &end
+		case EntryTypeL2BlockStart:
+			start := DSL2BlockStart{}.Decode(entry.Data)
+			if gerRollback != nil || blockEnd == nil || blockEnd.L2BlockNumber != start.L2BlockNumber ||
+				(rolledBack != nil && start.L2BlockNumber > rolledBack.L2BlockNumber) {
+				continue
+			}
+			hashes, ok := batchBlocks[start.BatchNumber]
+			if !ok {
+				l2blocks, err := stateDB.GetDSL2Blocks(ctx, start.BatchNumber, nil)
+				if err != nil {
+					return nil, false, err
+				}
+				hashes = make(map[uint64]common.Hash, len(l2blocks))
+				for _, l2block := range l2blocks {
+					hashes[l2block.L2BlockNumber] = l2block.BlockHash
+				}
+				batchBlocks[start.BatchNumber] = hashes
+			}
+			if hash, found := hashes[start.L2BlockNumber]; found && hash == blockEnd.BlockHash {
+				if !mismatch {
+					return nil, false, nil
+				}
+				return &DSRollback{BatchNumber: start.BatchNumber, L2BlockNumber: start.L2BlockNumber}, false, nil
+			}
+			log.Debugf("Data stream L2 block %d of batch %d doesn't match the state", start.L2BlockNumber, start.BatchNumber)
+			mismatch = true
+		case EntryTypeUpdateGER:
+			updateGER := DSUpdateGER{}.Decode(entry.Data)
+			if gerRollback != nil || (rolledBack != nil && updateGER.BatchNumber > rolledBack.BatchNumber) {
+				continue
+			}
+			batch, err := stateDB.GetDSBatch(ctx, updateGER.BatchNumber, nil)
+			if err != nil && !errors.Is(err, ErrStateNotSynchronized) {
+				return nil, false, err
+			}
+			if err == nil && batch.GlobalExitRoot == updateGER.GlobalExitRoot && batch.StateRoot == updateGER.StateRoot {
+				if !mismatch {
+					return nil, false, nil
+				}
+				gerRollback = &DSRollback{BatchNumber: updateGER.BatchNumber}
+				continue
+			}
+			log.Debugf("Data stream GER update of batch %d doesn't match the state", updateGER.BatchNumber)
+			mismatch = true
+		}
+	}
+
+	if gerRollback != nil {
+		return gerRollback, true, nil
+	}
+	return nil, false, errors.New("no entry of the data stream matches the state")
+}
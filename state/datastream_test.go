@@ -0,0 +1,15 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDSRollbackEncoding(t *testing.T) {
+	rollback := state.DSRollback{BatchNumber: 12, L2BlockNumber: 345}
+	encoded := rollback.Encode()
+	assert.Len(t, encoded, 16)
+	assert.Equal(t, rollback, state.DSRollback{}.Decode(encoded))
+}
@@ -69,6 +69,18 @@ var (
 	// ErrMaxNativeBlockHashBlockRangeLimitExceeded returned when the range between block number range
 	// to filter native block hashes is bigger than the configured limit
 	ErrMaxNativeBlockHashBlockRangeLimitExceeded = errors.New("native block hashes are limited to a %v block range")
+	// ErrStorageOverrideNotSupported returned when a state override replaces the whole storage
+	// of an account, the state tree can't enumerate the storage slots to clear them
+	ErrStorageOverrideNotSupported = errors.New("overriding the whole storage of an account is not supported, use stateDiff instead")
+	// ErrBridgeIndexingDisabled is returned when the bridge events are queried
+	// but their indexing is disabled
+	ErrBridgeIndexingDisabled = errors.New("bridge events indexing is disabled")
+	// ErrBatchNotVirtualized is returned when the integrity of the data of a batch that
+	// has not been sequenced in L1 yet is checked
+	ErrBatchNotVirtualized = errors.New("batch is not virtualized")
+	// ErrBatchDataIntegrity is returned when the data of a batch doesn't match the
+	// accumulated input hash progression
+	ErrBatchDataIntegrity = errors.New("batch data doesn't match the accumulated input hash")
 
 	zkCounterErrPrefix = "ZKCounter: "
 )
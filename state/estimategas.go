@@ -0,0 +1,96 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// maxGasEstimationIterations bounds the executions of the binary search of the gas estimation,
+// it's only reached when the error ratio is zero and the range is huge
+const maxGasEstimationIterations = 64
+
+// gasEstimationResult is the result of executing the tx with a gas limit during the gas estimation
+type gasEstimationResult struct {
+	// failed is true if the execution failed, for any reason, with the gas limit
+	failed bool
+	// reverted is true if the execution failed because the EVM reverted
+	reverted bool
+	// gasUsed is the gas used by the execution as reported by the executor, after the refunds
+	gasUsed uint64
+	// gasRefunded is the gas refunded at the end of the execution as reported by the executor
+	gasRefunded uint64
+	// returnValue is the data returned by the execution, the revert data if it reverted
+	returnValue []byte
+	// err is the error of the failed execution
+	err error
+}
+
+// gasEstimationTest executes the tx with the gas limit, the error returned aborts the estimation
+type gasEstimationTest func(gas uint64) (gasEstimationResult, error)
+
+// searchGasLimit searches the lowest gas limit the tx doesn't fail with, between the highest
+// gas limit known to fail, lowEnd, and the highest gas limit allowed, highEnd.
+//
+// The tx is executed first with highEnd: if it fails there is no gas limit it succeeds with, and
+// the revert data is returned when it reverted. Otherwise the gas used reported by the executor
+// drives the search: the gas used is a lower bound, and the gas used plus the refunds and the
+// call stipend, scaled by the 63/64 rule of EIP-150, is tried first as it's enough for most txs.
+// The search stops when the range is below the error ratio of the high end.
+func searchGasLimit(lowEnd, highEnd uint64, errorRatio float64, test gasEstimationTest) (uint64, []byte, error) {
+	result, err := test(highEnd)
+	if err != nil {
+		return 0, nil, err
+	}
+	if result.failed {
+		if result.reverted {
+			return 0, result.returnValue, result.err
+		}
+		return 0, nil, fmt.Errorf("gas required exceeds allowance (%d): %w", highEnd, result.err)
+	}
+
+	if result.gasUsed > 0 && result.gasUsed-1 > lowEnd {
+		lowEnd = result.gasUsed - 1
+	}
+
+	// the refunds are only applied at the end of the execution and the calls keep 1/64 of the
+	// remaining gas, so the gas limit needed by the tx can be higher than the gas it uses
+	const callGasFraction = 64
+	optimistic := (result.gasUsed + result.gasRefunded + params.CallStipend) * callGasFraction / (callGasFraction - 1)
+	if optimistic > lowEnd && optimistic < highEnd {
+		result, err = test(optimistic)
+		if err != nil {
+			return 0, nil, err
+		}
+		if result.failed {
+			lowEnd = optimistic
+		} else {
+			highEnd = optimistic
+		}
+	}
+
+	for i := 0; lowEnd+1 < highEnd && i < maxGasEstimationIterations; i++ {
+		if errorRatio > 0 && float64(highEnd-lowEnd)/float64(highEnd) < errorRatio {
+			break
+		}
+		mid := lowEnd + (highEnd-lowEnd)/2 //nolint:gomnd
+		// the search is skewed to the low end, most of the txs need a gas limit close to the gas they use
+		if lowEnd > 0 && mid > lowEnd*2 { //nolint:gomnd
+			mid = lowEnd * 2 //nolint:gomnd
+		}
+
+		result, err = test(mid)
+		if err != nil {
+			return 0, nil, err
+		}
+		// the failures at lower gas limits are caused by the lack of gas, even the reverts, since the
+		// tx succeeds with the high end
+		if result.failed {
+			lowEnd = mid
+		} else {
+			highEnd = mid
+		}
+	}
+
+	return highEnd, nil, nil
+}
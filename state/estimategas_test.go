@@ -0,0 +1,109 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGasEstimationTest simulates a tx that needs the required gas limit to succeed, using the
+// gas used and getting the gas refunded at the end, the executions with a lower gas limit fail
+// with the failure error
+func fakeGasEstimationTest(required, used, refunded uint64, failure error, executions *[]uint64) gasEstimationTest {
+	return func(gas uint64) (gasEstimationResult, error) {
+		*executions = append(*executions, gas)
+		if gas < required {
+			return gasEstimationResult{
+				failed:   true,
+				reverted: errors.Is(failure, runtime.ErrExecutionReverted),
+				gasUsed:  gas,
+				err:      failure,
+			}, nil
+		}
+		return gasEstimationResult{gasUsed: used, gasRefunded: refunded}, nil
+	}
+}
+
+func TestSearchGasLimit(t *testing.T) {
+	const lowEnd, highEnd = 21000 - 1, 30000000
+
+	testCases := []struct {
+		name       string
+		required   uint64
+		used       uint64
+		refunded   uint64
+		failure    error
+		errorRatio float64
+	}{
+		{name: "gas used is enough", required: 50000, used: 50000, failure: runtime.ErrOutOfGas},
+		{name: "refunds returned at the end", required: 80000, used: 50000, refunded: 30000, failure: runtime.ErrOutOfGas},
+		{name: "gas kept by the calls", required: 120000, used: 100000, failure: runtime.ErrOutOfGas},
+		{name: "state dependent revert with lower gas", required: 200000, used: 60000, failure: runtime.ErrExecutionReverted},
+		{name: "exact search", required: 123457, used: 100000, failure: runtime.ErrOutOfGas},
+		{name: "error ratio", required: 123457, used: 100000, failure: runtime.ErrOutOfGas, errorRatio: 0.015},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var executions []uint64
+			gas, returnValue, err := searchGasLimit(lowEnd, highEnd, tc.errorRatio, fakeGasEstimationTest(tc.required, tc.used, tc.refunded, tc.failure, &executions))
+			require.NoError(t, err)
+			assert.Nil(t, returnValue)
+			assert.Equal(t, uint64(highEnd), executions[0])
+			assert.LessOrEqual(t, len(executions), maxGasEstimationIterations)
+
+			assert.GreaterOrEqual(t, gas, tc.required)
+			if tc.errorRatio == 0 {
+				assert.Equal(t, tc.required, gas)
+			} else {
+				assert.LessOrEqual(t, float64(gas-tc.required)/float64(gas), tc.errorRatio)
+			}
+		})
+	}
+}
+
+func TestSearchGasLimitUsesGasUsedFeedback(t *testing.T) {
+	var executions []uint64
+	gas, _, err := searchGasLimit(21000-1, 30000000, 0.015, fakeGasEstimationTest(50000, 50000, 0, runtime.ErrOutOfGas, &executions))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, gas, uint64(50000))
+	// the high end, the optimistic gas limit and a few executions within the error ratio
+	assert.LessOrEqual(t, len(executions), 5)
+}
+
+func TestSearchGasLimitFailures(t *testing.T) {
+	t.Run("reverted with the high end returns the revert data", func(t *testing.T) {
+		revertData := []byte{0x08, 0xc3, 0x79, 0xa0}
+		gas, returnValue, err := searchGasLimit(21000-1, 100000, 0, func(gas uint64) (gasEstimationResult, error) {
+			return gasEstimationResult{failed: true, reverted: true, gasUsed: 30000, returnValue: revertData, err: runtime.ErrExecutionReverted}, nil
+		})
+		assert.ErrorIs(t, err, runtime.ErrExecutionReverted)
+		assert.Equal(t, revertData, returnValue)
+		assert.Zero(t, gas)
+	})
+
+	t.Run("out of gas with the high end", func(t *testing.T) {
+		var executions []uint64
+		_, returnValue, err := searchGasLimit(21000-1, 100000, 0, fakeGasEstimationTest(200000, 200000, 0, runtime.ErrOutOfGas, &executions))
+		assert.ErrorIs(t, err, runtime.ErrOutOfGas)
+		assert.EqualError(t, err, "gas required exceeds allowance (100000): out of gas")
+		assert.Nil(t, returnValue)
+		assert.Equal(t, []uint64{100000}, executions)
+	})
+
+	t.Run("executor errors abort the estimation", func(t *testing.T) {
+		executorErr := errors.New("executor unavailable")
+		executions := 0
+		_, _, err := searchGasLimit(21000-1, 100000, 0, func(gas uint64) (gasEstimationResult, error) {
+			executions++
+			if executions == 1 {
+				return gasEstimationResult{gasUsed: 30000}, nil
+			}
+			return gasEstimationResult{}, executorErr
+		})
+		assert.ErrorIs(t, err, executorErr)
+		assert.Equal(t, 2, executions)
+	})
+}
@@ -0,0 +1,191 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state/metrics"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ExecutorOriginSequencer is the origin of the requests of the sequencer
+	ExecutorOriginSequencer = "sequencer"
+	// ExecutorOriginSynchronizer is the origin of the requests of the synchronizer
+	ExecutorOriginSynchronizer = "synchronizer"
+	// ExecutorOriginRPC is the origin of the requests of the eth_call RPC endpoint
+	ExecutorOriginRPC = "rpc"
+)
+
+// ExecutorDebugStatus are the debug flags of the requests of an origin
+type ExecutorDebugStatus struct {
+	executor.DebugFlags
+	// ExpiresAt is the time the flags overridden at runtime expire, nil when the flags are the config ones
+	ExpiresAt *time.Time
+}
+
+type executorDebugOverride struct {
+	flags     executor.DebugFlags
+	expiresAt time.Time
+}
+
+// failedTxTrace is the trace of a failing tx persisted in the traces dir
+type failedTxTrace struct {
+	Origin      string          `json:"origin"`
+	BatchNumber uint64          `json:"batchNumber"`
+	TxHash      string          `json:"txHash"`
+	Request     json.RawMessage `json:"request"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// SetExecutorDebugConfig sets the debug flags of the requests sent to the executor for each origin,
+// they are taken from the executor config
+func (s *State) SetExecutorDebugConfig(cfg executor.DebugConfig) {
+	s.executorDebugCfg = cfg
+}
+
+// SetExecutorDebugFlags overrides the debug flags of the requests of the origin for the duration,
+// a zero duration restores the config flags
+func (s *State) SetExecutorDebugFlags(origin string, flags executor.DebugFlags, duration time.Duration) error {
+	if _, err := s.configExecutorDebugFlags(origin); err != nil {
+		return err
+	}
+	if maxDuration := s.executorDebugCfg.MaxOverrideDuration.Duration; maxDuration > 0 && duration > maxDuration {
+		return fmt.Errorf("the duration %v exceeds the max override duration %v", duration, maxDuration)
+	}
+
+	s.executorDebugMux.Lock()
+	defer s.executorDebugMux.Unlock()
+	if duration <= 0 {
+		delete(s.executorDebugOverrides, origin)
+		log.Infof("executor debug flags of the %s requests restored", origin)
+		return nil
+	}
+	s.executorDebugOverrides[origin] = executorDebugOverride{flags: flags, expiresAt: time.Now().Add(duration)}
+	log.Infof("executor debug flags of the %s requests set to %+v for %v", origin, flags, duration)
+	return nil
+}
+
+// GetExecutorDebugStatus returns the debug flags of the requests of each origin
+func (s *State) GetExecutorDebugStatus() map[string]ExecutorDebugStatus {
+	status := map[string]ExecutorDebugStatus{}
+	for _, origin := range []string{ExecutorOriginSequencer, ExecutorOriginSynchronizer, ExecutorOriginRPC} {
+		status[origin] = s.executorDebugStatus(origin)
+	}
+	return status
+}
+
+func (s *State) executorDebugStatus(origin string) ExecutorDebugStatus {
+	s.executorDebugMux.RLock()
+	override, found := s.executorDebugOverrides[origin]
+	s.executorDebugMux.RUnlock()
+	if found && time.Now().Before(override.expiresAt) {
+		return ExecutorDebugStatus{DebugFlags: override.flags, ExpiresAt: &override.expiresAt}
+	}
+	flags, _ := s.configExecutorDebugFlags(origin)
+	return ExecutorDebugStatus{DebugFlags: flags}
+}
+
+func (s *State) configExecutorDebugFlags(origin string) (executor.DebugFlags, error) {
+	switch origin {
+	case ExecutorOriginSequencer:
+		return s.executorDebugCfg.Sequencer, nil
+	case ExecutorOriginSynchronizer:
+		return s.executorDebugCfg.Synchronizer, nil
+	case ExecutorOriginRPC:
+		return s.executorDebugCfg.RPC, nil
+	default:
+		return executor.DebugFlags{}, fmt.Errorf("invalid executor request origin %q", origin)
+	}
+}
+
+// executorOrigin returns the origin of the requests of the caller, the requests not measured
+// are the reprocessing of the batches done by the sequencer
+func executorOrigin(caller metrics.CallerLabel) string {
+	if caller == metrics.SynchronizerCallerLabel {
+		return ExecutorOriginSynchronizer
+	}
+	return ExecutorOriginSequencer
+}
+
+// persistFailedTxTraces executes again the request generating the execute trace of each failing tx
+// of the response, persisting them in the traces dir
+func (s *State) persistFailedTxTraces(ctx context.Context, origin string, request *executor.ProcessBatchRequest, response *executor.ProcessBatchResponse) {
+	for _, txResponse := range response.Responses {
+		if txResponse.Error == executor.RomError_ROM_ERROR_NO_ERROR {
+			continue
+		}
+		txHash := hex.EncodeToHex(txResponse.TxHash)
+
+		traceRequest := proto.Clone(request).(*executor.ProcessBatchRequest)
+		traceRequest.UpdateMerkleTree = cFalse
+		traceRequest.ContextId = uuid.NewString()
+		traceRequest.TraceConfig = &executor.TraceConfig{
+			EnableMemory:                 cTrue,
+			EnableReturnData:             cTrue,
+			TxHashToGenerateExecuteTrace: txResponse.TxHash,
+		}
+		traceResponse, err := s.executorClient.ProcessBatch(ctx, traceRequest)
+		if err != nil {
+			log.Errorf("failed to generate the execute trace of the failing tx %s: %v", txHash, err)
+			continue
+		}
+		for _, tracedTx := range traceResponse.Responses {
+			if hex.EncodeToHex(tracedTx.TxHash) == txHash {
+				txResponse = tracedTx
+				break
+			}
+		}
+
+		if err := s.writeFailedTxTrace(origin, request, txResponse); err != nil {
+			log.Errorf("failed to persist the execute trace of the failing tx %s: %v", txHash, err)
+		}
+	}
+}
+
+func (s *State) writeFailedTxTrace(origin string, request *executor.ProcessBatchRequest, txResponse *executor.ProcessTransactionResponse) error {
+	// the db and contracts of the debug requests aren't persisted
+	request = proto.Clone(request).(*executor.ProcessBatchRequest)
+	request.Db = nil
+	request.ContractsBytecode = nil
+	requestJSON, err := protojson.Marshal(request)
+	if err != nil {
+		return err
+	}
+	responseJSON, err := protojson.Marshal(txResponse)
+	if err != nil {
+		return err
+	}
+
+	trace := failedTxTrace{
+		Origin:      origin,
+		BatchNumber: request.OldBatchNum + 1,
+		TxHash:      hex.EncodeToHex(txResponse.TxHash),
+		Request:     requestJSON,
+		Response:    responseJSON,
+	}
+	data, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := s.executorDebugCfg.TracesDir
+	if err := os.MkdirAll(dir, 0755); err != nil { //nolint:gomnd
+		return err
+	}
+	fileName := filepath.Join(dir, fmt.Sprintf("%s-%d-%s.json", origin, trace.BatchNumber, trace.TxHash))
+	if err := os.WriteFile(fileName, data, 0644); err != nil { //nolint:gomnd,gosec
+		return err
+	}
+	log.Infof("execute trace of the failing tx %s persisted in %s", trace.TxHash, fileName)
+	return nil
+}
@@ -0,0 +1,40 @@
+package state_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutorDebugFlags(t *testing.T) {
+	status := testState.GetExecutorDebugStatus()
+	require.Len(t, status, 3)
+	assert.Equal(t, state.ExecutorDebugStatus{}, status[state.ExecutorOriginSequencer])
+	assert.Equal(t, state.ExecutorDebugStatus{}, status[state.ExecutorOriginSynchronizer])
+	assert.Equal(t, state.ExecutorDebugStatus{}, status[state.ExecutorOriginRPC])
+
+	// only the sequencer requests are debugged, temporarily
+	flags := executor.DebugFlags{ROMLogs: true, PersistFailedTxTraces: true}
+	require.NoError(t, testState.SetExecutorDebugFlags(state.ExecutorOriginSequencer, flags, time.Minute))
+	status = testState.GetExecutorDebugStatus()
+	assert.Equal(t, flags, status[state.ExecutorOriginSequencer].DebugFlags)
+	require.NotNil(t, status[state.ExecutorOriginSequencer].ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *status[state.ExecutorOriginSequencer].ExpiresAt, time.Second)
+	assert.Equal(t, state.ExecutorDebugStatus{}, status[state.ExecutorOriginSynchronizer])
+	assert.Equal(t, state.ExecutorDebugStatus{}, status[state.ExecutorOriginRPC])
+
+	// a zero duration restores the config flags
+	require.NoError(t, testState.SetExecutorDebugFlags(state.ExecutorOriginSequencer, flags, 0))
+	assert.Equal(t, state.ExecutorDebugStatus{}, testState.GetExecutorDebugStatus()[state.ExecutorOriginSequencer])
+
+	// the override expires
+	require.NoError(t, testState.SetExecutorDebugFlags(state.ExecutorOriginRPC, flags, 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, state.ExecutorDebugStatus{}, testState.GetExecutorDebugStatus()[state.ExecutorOriginRPC])
+
+	assert.Error(t, testState.SetExecutorDebugFlags("aggregator", flags, time.Minute))
+}
@@ -15,3 +15,13 @@ type ForcedBatch struct {
 	RawTxsData        []byte
 	ForcedAt          time.Time
 }
+
+// ForcedBatchInclusion is a forced batch along with the batch that includes it in the trusted
+// state and the last stage of its lifecycle reached by that batch
+type ForcedBatchInclusion struct {
+	ForcedBatch
+	// BatchNumber is nil while the forced batch is pending to be sequenced
+	BatchNumber *uint64
+	// Stage is nil while the batch including the forced batch is not closed
+	Stage *BatchLifecycleStage
+}
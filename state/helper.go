@@ -8,6 +8,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -54,7 +55,26 @@ func EncodeTransactions(txs []types.Transaction, effectivePercentages []uint8, f
 	return batchL2Data, nil
 }
 
+// IsTxTypeSupported returns true if the txs of the provided type can be encoded in the
+// batch L2 data of the fork. None of the current forks support EIP-2718 typed txs yet
+func IsTxTypeSupported(txType uint8, forkID uint64) bool {
+	return txType == types.LegacyTxType
+}
+
+// GetTxGasPrice returns the gas price paid by the tx. There is no base fee in L2, so
+// for the dynamic fee txs it's the lowest between the fee cap and the tip cap
+func GetTxGasPrice(tx types.Transaction) *big.Int {
+	if tx.Type() != types.DynamicFeeTxType {
+		return tx.GasPrice()
+	}
+	return math.BigMin(tx.GasFeeCap(), tx.GasTipCap())
+}
+
 func prepareRPLTxData(tx types.Transaction) ([]byte, error) {
+	if tx.Type() != types.LegacyTxType {
+		return nil, fmt.Errorf("%w: type %d can't be encoded in the batch L2 data", types.ErrTxTypeNotSupported, tx.Type())
+	}
+
 	v, r, s := tx.RawSignatureValues()
 	sign := 1 - (v.Uint64() & 1)
 
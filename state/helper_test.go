@@ -146,6 +146,24 @@ func TestEncodePre155BatchL2DataForkID5(t *testing.T) {
 	assert.Equal(t, pre155, rawtxs)
 }
 
+func TestEncodeDynamicFeeTransaction(t *testing.T) {
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1000), //nolint:gomnd
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10), //nolint:gomnd
+		Gas:       21000,          //nolint:gomnd
+		To:        &common.Address{},
+		Value:     big.NewInt(0),
+	})
+
+	assert.False(t, state.IsTxTypeSupported(tx.Type(), forkID5))
+	assert.Equal(t, big.NewInt(1), state.GetTxGasPrice(*tx))
+
+	_, err := state.EncodeTransactions([]types.Transaction{*tx}, []uint8{state.MaxEffectivePercentage}, forkID5)
+	require.ErrorIs(t, err, types.ErrTxTypeNotSupported)
+}
+
 func TestMaliciousTransaction(t *testing.T) {
 	b := []byte{
 		0xee, 0x80, 0x84, 0x3b, 0x9a, 0xca, 0x00, 0x83, 0x01, 0x86, 0xa0, 0x94,
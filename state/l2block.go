@@ -26,7 +26,12 @@ type NewL2BlockEvent struct {
 // when a new l2 block is detected. This is used by the RPC WebSocket
 // filter subscription but can be used by any other component that
 // needs to react to a new L2 block added to the state.
+// The monitoring is only started once, the later calls do nothing.
 func (s *State) StartToMonitorNewL2Blocks() {
+	s.monitorNewL2BlocksOnce.Do(s.startToMonitorNewL2Blocks)
+}
+
+func (s *State) startToMonitorNewL2Blocks() {
 	lastL2Block, err := s.GetLastL2Block(context.Background(), nil)
 	if errors.Is(err, ErrStateNotSynchronized) {
 		lastL2Block = types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0)})
@@ -42,18 +47,29 @@ func (s *State) StartToMonitorNewL2Blocks() {
 // that will be triggered when a new l2 block event is triggered
 func (s *State) RegisterNewL2BlockEventHandler(h NewL2BlockEventHandler) {
 	log.Info("new l2 block event handler registered")
+	s.newL2BlockEventHandlersMux.Lock()
+	defer s.newL2BlockEventHandlersMux.Unlock()
 	s.newL2BlockEventHandlers = append(s.newL2BlockEventHandlers, h)
 }
 
+// getNewL2BlockEventHandlers returns the handlers registered, they can be registered
+// while the events are handled
+func (s *State) getNewL2BlockEventHandlers() []NewL2BlockEventHandler {
+	s.newL2BlockEventHandlersMux.RLock()
+	defer s.newL2BlockEventHandlersMux.RUnlock()
+	return s.newL2BlockEventHandlers
+}
+
 func (s *State) handleEvents() {
 	for newL2BlockEvent := range s.newL2BlockEvents {
 		log.Debugf("[handleEvents] new l2 block event detected for block: %v", newL2BlockEvent.Block.NumberU64())
-		if len(s.newL2BlockEventHandlers) == 0 {
+		handlers := s.getNewL2BlockEventHandlers()
+		if len(handlers) == 0 {
 			continue
 		}
 
 		wg := sync.WaitGroup{}
-		for _, handler := range s.newL2BlockEventHandlers {
+		for _, handler := range handlers {
 			wg.Add(1)
 			go func(h NewL2BlockEventHandler, e NewL2BlockEvent) {
 				defer func() {
@@ -78,7 +94,7 @@ func (s *State) monitorNewL2Blocks() {
 	}
 
 	for {
-		if len(s.newL2BlockEventHandlers) == 0 {
+		if len(s.getNewL2BlockEventHandlers()) == 0 {
 			waitNextCycle()
 			continue
 		}
@@ -0,0 +1,136 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// logBloomSectionSize is the number of consecutive blocks whose logs blooms are aggregated
+// in a section, it must match the size used by the migration creating state.log_bloom_section
+const logBloomSectionSize = 4096
+
+// logsBloomMasks returns the bloom of each value as a hex string without prefix, a bloom
+// matches the value when all the bits of its mask are set
+func logsBloomMasks(values [][]byte) []string {
+	masks := make([]string, 0, len(values))
+	for _, value := range values {
+		var bloom types.Bloom
+		bloom.Add(value)
+		masks = append(masks, hex.EncodeToString(bloom.Bytes()))
+	}
+	return masks
+}
+
+// logsBloomFilter builds the condition matching the blooms of the column with the addresses
+// and topics filters, every group of values must have at least one of them in the bloom.
+// The params of the masks are numbered from firstParam
+func logsBloomFilter(column string, addresses []common.Address, topics [][]common.Hash, firstParam int) (string, []interface{}) {
+	groups := [][][]byte{}
+	if len(addresses) > 0 {
+		values := make([][]byte, 0, len(addresses))
+		for _, address := range addresses {
+			values = append(values, address.Bytes())
+		}
+		groups = append(groups, values)
+	}
+	for _, topic := range topics {
+		if len(topic) == 0 {
+			continue
+		}
+		values := make([][]byte, 0, len(topic))
+		for _, hash := range topic {
+			values = append(values, hash.Bytes())
+		}
+		groups = append(groups, values)
+	}
+
+	conditions := make([]string, 0, len(groups))
+	args := make([]interface{}, 0, len(groups))
+	for i, values := range groups {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM unnest($%d::TEXT[]) m WHERE (%s & ('x' || m)::BIT(2048)) = ('x' || m)::BIT(2048))",
+			firstParam+i, column))
+		args = append(args, logsBloomMasks(values))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// getLogsBloomCandidates returns the numbers of the blocks in the range whose logs blooms may
+// contain logs matching the addresses and topics filters. It returns false when there are no
+// filters, so all the blocks of the range are candidates.
+//
+// The sections whose aggregated bloom doesn't match are skipped, then the bloom of each
+// remaining block is checked. The blocks stored before the blooms were computed have no bloom
+// and are always candidates
+func (p *PostgresStorage) getLogsBloomCandidates(ctx context.Context, fromBlock, toBlock uint64, addresses []common.Address, topics [][]common.Hash, dbTx pgx.Tx) ([]int64, bool, error) {
+	sectionFilter, sectionArgs := logsBloomFilter("s.bloom", addresses, topics, 3) //nolint:gomnd
+	if sectionFilter == "" {
+		return nil, false, nil
+	}
+	e := p.getExecQuerier(dbTx)
+
+	getSkippedSectionsSQL := fmt.Sprintf(`
+		SELECT s.section_num
+		  FROM state.log_bloom_section s
+		 WHERE s.section_num BETWEEN $1 AND $2
+		   AND NOT (%s)`, sectionFilter)
+	args := append([]interface{}{fromBlock / logBloomSectionSize, toBlock / logBloomSectionSize}, sectionArgs...)
+	rows, err := e.Query(ctx, getSkippedSectionsSQL, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	skippedSections := []int64{}
+	for rows.Next() {
+		var section int64
+		if err := rows.Scan(&section); err != nil {
+			rows.Close()
+			return nil, false, err
+		}
+		skippedSections = append(skippedSections, section)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	blockFilter, blockArgs := logsBloomFilter("b.logs_bloom", addresses, topics, 4) //nolint:gomnd
+	getCandidatesSQL := fmt.Sprintf(`
+		SELECT b.block_num
+		  FROM state.l2block b
+		 WHERE b.block_num BETWEEN $1 AND $2
+		   AND NOT (b.block_num / %d = ANY($3))
+		   AND (b.logs_bloom IS NULL OR (%s))
+		 ORDER BY b.block_num`, logBloomSectionSize, blockFilter)
+	args = append([]interface{}{fromBlock, toBlock, skippedSections}, blockArgs...)
+	rows, err = e.Query(ctx, getCandidatesSQL, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+	candidates := []int64{}
+	for rows.Next() {
+		var blockNumber int64
+		if err := rows.Scan(&blockNumber); err != nil {
+			return nil, false, err
+		}
+		candidates = append(candidates, blockNumber)
+	}
+	return candidates, true, rows.Err()
+}
+
+// addLogBloomSection aggregates the logs bloom of the block into the bloom of its section
+func (p *PostgresStorage) addLogBloomSection(ctx context.Context, blockNumber uint64, bloom types.Bloom, dbTx pgx.Tx) error {
+	const addLogBloomSectionSQL = `
+		INSERT INTO state.log_bloom_section (section_num, bloom) VALUES ($1, ('x' || $2)::BIT(2048))
+		    ON CONFLICT (section_num) DO UPDATE SET bloom = state.log_bloom_section.bloom | EXCLUDED.bloom`
+
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, addLogBloomSectionSQL, blockNumber/logBloomSectionSize, hex.EncodeToString(bloom.Bytes()))
+	return err
+}
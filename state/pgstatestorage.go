@@ -8,6 +8,8 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/db"
+	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/ethereum/go-ethereum/common"
@@ -28,16 +30,22 @@ const (
 type PostgresStorage struct {
 	cfg Config
 	*pgxpool.Pool
+	readRouter *db.ReadRouter
 }
 
 // NewPostgresStorage creates a new StateDB
 func NewPostgresStorage(cfg Config, db *pgxpool.Pool) *PostgresStorage {
 	return &PostgresStorage{
-		cfg,
-		db,
+		cfg:  cfg,
+		Pool: db,
 	}
 }
 
+// SetReadRouter sets the router of the read only transactions to the read replica
+func (p *PostgresStorage) SetReadRouter(readRouter *db.ReadRouter) {
+	p.readRouter = readRouter
+}
+
 // getExecQuerier determines which execQuerier to use, dbTx or the main pgxpool
 func (p *PostgresStorage) getExecQuerier(dbTx pgx.Tx) execQuerier {
 	if dbTx != nil {
@@ -98,6 +106,66 @@ func (p *PostgresStorage) AddBlock(ctx context.Context, block *Block, dbTx pgx.T
 	return err
 }
 
+// AddL1Logs archives the raw L1 logs consumed by the synchronizer
+func (p *PostgresStorage) AddL1Logs(ctx context.Context, logs []types.Log, dbTx pgx.Tx) error {
+	const addL1LogSQL = `INSERT INTO state.l1_log (block_num, log_index, block_hash, tx_hash, tx_index, address, topics, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (block_num, log_index) DO NOTHING`
+
+	e := p.getExecQuerier(dbTx)
+	for _, l := range logs {
+		topics := make([]string, 0, len(l.Topics))
+		for _, topic := range l.Topics {
+			topics = append(topics, topic.String())
+		}
+		_, err := e.Exec(ctx, addL1LogSQL, l.BlockNumber, l.Index, l.BlockHash.String(), l.TxHash.String(), l.TxIndex, l.Address.String(), topics, l.Data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetL1Logs returns the archived raw L1 logs from the provided L1 block number
+// onwards, sorted in the order they were emitted
+func (p *PostgresStorage) GetL1Logs(ctx context.Context, fromBlockNumber uint64, dbTx pgx.Tx) ([]types.Log, error) {
+	const getL1LogsSQL = `SELECT block_num, log_index, block_hash, tx_hash, tx_index, address, topics, data
+		  FROM state.l1_log
+		 WHERE block_num >= $1
+		 ORDER BY block_num, log_index`
+
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, getL1LogsSQL, fromBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []types.Log
+	for rows.Next() {
+		var (
+			l                          types.Log
+			blockHash, txHash, address string
+			topics                     []string
+			logIndex, txIndex          uint64
+		)
+		if err := rows.Scan(&l.BlockNumber, &logIndex, &blockHash, &txHash, &txIndex, &address, &topics, &l.Data); err != nil {
+			return nil, err
+		}
+		l.Index = uint(logIndex)
+		l.TxIndex = uint(txIndex)
+		l.BlockHash = common.HexToHash(blockHash)
+		l.TxHash = common.HexToHash(txHash)
+		l.Address = common.HexToAddress(address)
+		for _, topic := range topics {
+			l.Topics = append(l.Topics, common.HexToHash(topic))
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, rows.Err()
+}
+
 // GetTxsOlderThanNL1Blocks get txs hashes to delete from tx pool
 func (p *PostgresStorage) GetTxsOlderThanNL1Blocks(ctx context.Context, nL1Blocks uint64, dbTx pgx.Tx) ([]common.Hash, error) {
 	var batchNum, blockNum uint64
@@ -183,6 +251,36 @@ func (p *PostgresStorage) GetPreviousBlock(ctx context.Context, offset uint64, d
 	return &block, err
 }
 
+// GetLastBlocks gets the last limit L1 blocks stored, ordered from the latest to the oldest.
+func (p *PostgresStorage) GetLastBlocks(ctx context.Context, limit uint64, dbTx pgx.Tx) ([]Block, error) {
+	const getLastBlocksSQL = "SELECT block_num, block_hash, parent_hash, received_at FROM state.block ORDER BY block_num DESC LIMIT $1"
+
+	q := p.getExecQuerier(dbTx)
+
+	rows, err := q.Query(ctx, getLastBlocksSQL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := make([]Block, 0, limit)
+	for rows.Next() {
+		var (
+			blockHash  string
+			parentHash string
+			block      Block
+		)
+		if err := rows.Scan(&block.BlockNumber, &blockHash, &parentHash, &block.ReceivedAt); err != nil {
+			return nil, err
+		}
+		block.BlockHash = common.HexToHash(blockHash)
+		block.ParentHash = common.HexToHash(parentHash)
+		blocks = append(blocks, block)
+	}
+
+	return blocks, rows.Err()
+}
+
 // AddGlobalExitRoot adds a new ExitRoot to the db
 func (p *PostgresStorage) AddGlobalExitRoot(ctx context.Context, exitRoot *GlobalExitRoot, dbTx pgx.Tx) error {
 	const addGlobalExitRootSQL = "INSERT INTO state.exit_root (block_num, timestamp, mainnet_exit_root, rollup_exit_root, global_exit_root) VALUES ($1, $2, $3, $4, $5)"
@@ -327,6 +425,44 @@ func (p *PostgresStorage) GetForcedBatch(ctx context.Context, forcedBatchNumber
 	return &forcedBatch, nil
 }
 
+// GetForcedBatchInclusion returns the forced batch along with the batch including it and the last
+// stage of its lifecycle reached by that batch. It returns ErrNotFound if the forced batch doesn't exist
+func (p *PostgresStorage) GetForcedBatchInclusion(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (*ForcedBatchInclusion, error) {
+	const getForcedBatchInclusionSQL = `SELECT f.forced_batch_num, f.global_exit_root, f.timestamp, f.raw_txs_data, f.coinbase, f.block_num, b.batch_num,
+		       (SELECT l.stage FROM state.batch_lifecycle l WHERE l.batch_num = b.batch_num
+		         ORDER BY CASE l.stage WHEN 'trusted' THEN 1 WHEN 'virtualized' THEN 2 WHEN 'proven' THEN 3 WHEN 'verified' THEN 4 END DESC
+		         LIMIT 1)
+		  FROM state.forced_batch f
+		  LEFT JOIN state.batch b ON b.forced_batch_num = f.forced_batch_num
+		 WHERE f.forced_batch_num = $1`
+
+	var (
+		inclusion      ForcedBatchInclusion
+		globalExitRoot string
+		rawTxs         string
+		seq            string
+		stage          *string
+	)
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, getForcedBatchInclusionSQL, forcedBatchNumber).Scan(&inclusion.ForcedBatchNumber, &globalExitRoot, &inclusion.ForcedAt, &rawTxs, &seq, &inclusion.BlockNumber, &inclusion.BatchNumber, &stage)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	inclusion.RawTxsData, err = hex.DecodeString(rawTxs)
+	if err != nil {
+		return nil, err
+	}
+	inclusion.Sequencer = common.HexToAddress(seq)
+	inclusion.GlobalExitRoot = common.HexToHash(globalExitRoot)
+	if stage != nil {
+		s := BatchLifecycleStage(*stage)
+		inclusion.Stage = &s
+	}
+	return &inclusion, nil
+}
+
 // GetForcedBatchesSince gets L1 forced batches since forcedBatchNumber
 func (p *PostgresStorage) GetForcedBatchesSince(ctx context.Context, forcedBatchNumber, maxBlockNumber uint64, dbTx pgx.Tx) ([]*ForcedBatch, error) {
 	const getForcedBatchesSQL = "SELECT forced_batch_num, global_exit_root, timestamp, raw_txs_data, coinbase, block_num FROM state.forced_batch WHERE forced_batch_num > $1 AND block_num <= $2 ORDER BY forced_batch_num ASC"
@@ -358,7 +494,10 @@ func (p *PostgresStorage) AddVerifiedBatch(ctx context.Context, verifiedBatch *V
 	e := p.getExecQuerier(dbTx)
 	const addVerifiedBatchSQL = "INSERT INTO state.verified_batch (block_num, batch_num, tx_hash, aggregator, state_root, is_trusted) VALUES ($1, $2, $3, $4, $5, $6)"
 	_, err := e.Exec(ctx, addVerifiedBatchSQL, verifiedBatch.BlockNumber, verifiedBatch.BatchNumber, verifiedBatch.TxHash.String(), verifiedBatch.Aggregator.String(), verifiedBatch.StateRoot.String(), verifiedBatch.IsTrusted)
-	return err
+	if err != nil {
+		return err
+	}
+	return p.addBatchLifecycleTransition(ctx, verifiedBatch.BatchNumber, BatchVerifiedStage, &verifiedBatch.TxHash, &verifiedBatch.BlockNumber, dbTx)
 }
 
 // GetVerifiedBatch get an L1 verifiedBatch.
@@ -899,7 +1038,10 @@ func (p *PostgresStorage) AddVirtualBatch(ctx context.Context, virtualBatch *Vir
 	const addVirtualBatchSQL = "INSERT INTO state.virtual_batch (batch_num, tx_hash, coinbase, block_num, sequencer_addr) VALUES ($1, $2, $3, $4, $5)"
 	e := p.getExecQuerier(dbTx)
 	_, err := e.Exec(ctx, addVirtualBatchSQL, virtualBatch.BatchNumber, virtualBatch.TxHash.String(), virtualBatch.Coinbase.String(), virtualBatch.BlockNumber, virtualBatch.SequencerAddr.String())
-	return err
+	if err != nil {
+		return err
+	}
+	return p.addBatchLifecycleTransition(ctx, virtualBatch.BatchNumber, BatchVirtualizedStage, &virtualBatch.TxHash, &virtualBatch.BlockNumber, dbTx)
 }
 
 // GetVirtualBatch get an L1 virtualBatch.
@@ -985,8 +1127,11 @@ func (p *PostgresStorage) closeBatch(ctx context.Context, receipt ProcessingRece
 	}
 	_, err = e.Exec(ctx, closeBatchSQL, receipt.StateRoot.String(), receipt.LocalExitRoot.String(),
 		receipt.AccInputHash.String(), receipt.BatchL2Data, string(batchResourcesJsonBytes), receipt.ClosingReason, receipt.BatchNumber)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return p.addBatchLifecycleTransition(ctx, receipt.BatchNumber, BatchTrustedStage, nil, nil, dbTx)
 }
 
 // UpdateGERInOpenBatch update ger in open batch
@@ -1494,8 +1639,8 @@ func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2
 
 	const addTransactionSQL = "INSERT INTO state.transaction (hash, encoded, decoded, l2_block_num, effective_percentage, egp_log) VALUES($1, $2, $3, $4, $5, $6)"
 	const addL2BlockSQL = `
-        INSERT INTO state.l2block (block_num, block_hash, header, uncles, parent_hash, state_root, received_at, batch_num, created_at)
-                           VALUES (       $1,         $2,     $3,     $4,          $5,         $6,          $7,        $8,         $9)`
+        INSERT INTO state.l2block (block_num, block_hash, header, uncles, parent_hash, state_root, received_at, batch_num, created_at, logs_bloom)
+                           VALUES (       $1,         $2,     $3,     $4,          $5,         $6,          $7,        $8,         $9, ('x' || $10)::BIT(2048))`
 
 	var header = "{}"
 	if l2Block.Header() != nil {
@@ -1515,10 +1660,14 @@ func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2
 		uncles = string(unclesBytes)
 	}
 
+	logsBloom := types.CreateBloom(receipts)
 	if _, err := e.Exec(ctx, addL2BlockSQL,
 		l2Block.Number().Uint64(), l2Block.Hash().String(), header, uncles,
 		l2Block.ParentHash().String(), l2Block.Root().String(),
-		l2Block.ReceivedAt, batchNumber, time.Now().UTC()); err != nil {
+		l2Block.ReceivedAt, batchNumber, time.Now().UTC(), hex.EncodeToString(logsBloom.Bytes())); err != nil {
+		return err
+	}
+	if err := p.addLogBloomSection(ctx, l2Block.NumberU64(), logsBloom, dbTx); err != nil {
 		return err
 	}
 
@@ -1562,6 +1711,12 @@ func (p *PostgresStorage) AddL2Block(ctx context.Context, batchNumber uint64, l2
 			}
 		}
 	}
+
+	if p.cfg.BridgeIndexing.Enabled {
+		if err := p.addBridgeEvents(ctx, l2Block.NumberU64(), receipts, dbTx); err != nil {
+			return err
+		}
+	}
 	log.Debugf("[AddL2Block] l2 block %v took %vms to be added", l2Block.NumberU64(), time.Since(start).Milliseconds())
 	return nil
 }
@@ -1902,6 +2057,37 @@ func (p *PostgresStorage) GetL2BlockHeaderByNumber(ctx context.Context, blockNum
 	return header, nil
 }
 
+// GetStateRootByL2BlockNumber gets the state root of the L2 block without loading the block
+func (p *PostgresStorage) GetStateRootByL2BlockNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (common.Hash, error) {
+	const getStateRootByL2BlockNumberSQL = "SELECT state_root FROM state.l2block WHERE block_num = $1"
+
+	var stateRoot string
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getStateRootByL2BlockNumberSQL, blockNumber).Scan(&stateRoot)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return common.Hash{}, ErrNotFound
+	} else if err != nil {
+		return common.Hash{}, err
+	}
+	return common.HexToHash(stateRoot), nil
+}
+
+// GetL2BlockNumberByStateRoot gets the number of the L2 block and the batch whose state root is
+// the provided one, if several blocks have the same state root the first one is returned
+func (p *PostgresStorage) GetL2BlockNumberByStateRoot(ctx context.Context, stateRoot common.Hash, dbTx pgx.Tx) (uint64, uint64, error) {
+	const getL2BlockNumberByStateRootSQL = "SELECT block_num, batch_num FROM state.l2block WHERE state_root = $1 ORDER BY block_num ASC LIMIT 1"
+
+	var blockNumber, batchNumber uint64
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getL2BlockNumberByStateRootSQL, stateRoot.String()).Scan(&blockNumber, &batchNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, 0, ErrNotFound
+	} else if err != nil {
+		return 0, 0, err
+	}
+	return blockNumber, batchNumber, nil
+}
+
 // GetL2BlockHashesSince gets the block hashes added since the provided date
 func (p *PostgresStorage) GetL2BlockHashesSince(ctx context.Context, since time.Time, dbTx pgx.Tx) ([]common.Hash, error) {
 	const getL2BlockHashesSinceSQL = "SELECT block_hash FROM state.l2block WHERE created_at >= $1"
@@ -1986,6 +2172,7 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 
 	const queryFilterByBlockHash = `AND b.block_hash = $7 `
 	const queryFilterByBlockNumbers = `AND b.block_num BETWEEN $7 AND $8 `
+	const queryFilterByBlockNumberList = `AND b.block_num = any($7) `
 
 	const queryOrder = `ORDER BY b.block_num ASC, l.log_index ASC`
 
@@ -1998,6 +2185,10 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 		queryCount +
 		queryBody +
 		queryFilterByBlockNumbers
+	const queryToCountLogsByBlockNumberList = "" +
+		queryCount +
+		queryBody +
+		queryFilterByBlockNumberList
 
 	// select queries
 	const queryToSelectLogsByBlockHash = "" +
@@ -2010,6 +2201,11 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 		queryBody +
 		queryFilterByBlockNumbers +
 		queryOrder
+	const queryToSelectLogsByBlockNumberList = "" +
+		querySelect +
+		queryBody +
+		queryFilterByBlockNumberList +
+		queryOrder
 
 	args := []interface{}{}
 
@@ -2049,9 +2245,24 @@ func (p *PostgresStorage) GetLogs(ctx context.Context, fromBlock uint64, toBlock
 			return nil, ErrMaxLogsBlockRangeLimitExceeded
 		}
 
-		args = append(args, fromBlock, toBlock)
-		queryToCount = queryToCountLogsByBlockNumbers
-		queryToSelect = queryToSelectLogsByBlockNumbers
+		// the logs blooms skip the blocks without matching logs
+		candidates, filtered, err := p.getLogsBloomCandidates(ctx, fromBlock, toBlock, addresses, topics, dbTx)
+		if err != nil {
+			return nil, err
+		}
+		if filtered && len(candidates) == 0 {
+			return []*types.Log{}, nil
+		}
+
+		if filtered && uint64(len(candidates)) <= blockRange {
+			args = append(args, candidates)
+			queryToCount = queryToCountLogsByBlockNumberList
+			queryToSelect = queryToSelectLogsByBlockNumberList
+		} else {
+			args = append(args, fromBlock, toBlock)
+			queryToCount = queryToCountLogsByBlockNumbers
+			queryToSelect = queryToSelectLogsByBlockNumbers
+		}
 	}
 
 	q := p.getExecQuerier(dbTx)
@@ -2171,6 +2382,116 @@ func (p *PostgresStorage) AddLog(ctx context.Context, l *types.Log, dbTx pgx.Tx)
 	return err
 }
 
+// addBridgeEvents indexes the deposits and claims emitted by the L2 bridge in the receipts of a L2 block
+func (p *PostgresStorage) addBridgeEvents(ctx context.Context, blockNumber uint64, receipts []*types.Receipt, dbTx pgx.Tx) error {
+	const addDepositSQL = `
+        INSERT INTO state.bridge_deposit (block_num, tx_hash, log_index, leaf_type, orig_net, orig_addr, dest_net, dest_addr, amount, metadata, deposit_cnt)
+                                  VALUES (       $1,      $2,        $3,        $4,       $5,        $6,       $7,        $8,     $9,      $10,         $11)`
+	const addClaimSQL = `
+        INSERT INTO state.bridge_claim (block_num, tx_hash, log_index, index, orig_net, orig_addr, dest_addr, amount)
+                                VALUES (       $1,      $2,        $3,    $4,       $5,        $6,        $7,     $8)`
+
+	e := p.getExecQuerier(dbTx)
+	for _, receipt := range receipts {
+		deposits, claims, err := bridgeEventsFromLogs(p.cfg.BridgeIndexing.L2BridgeAddr, blockNumber, receipt.Logs)
+		if err != nil {
+			return err
+		}
+		for _, d := range deposits {
+			if _, err := e.Exec(ctx, addDepositSQL, d.BlockNumber, d.TxHash.String(), d.LogIndex, d.LeafType, d.OriginNetwork,
+				d.OriginAddress.String(), d.DestinationNetwork, d.DestinationAddress.String(), d.Amount.String(), d.Metadata, d.DepositCount); err != nil {
+				return err
+			}
+		}
+		for _, c := range claims {
+			if _, err := e.Exec(ctx, addClaimSQL, c.BlockNumber, c.TxHash.String(), c.LogIndex, c.Index, c.OriginNetwork,
+				c.OriginAddress.String(), c.DestinationAddress.String(), c.Amount.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetBridgeDepositsByDestinationAddress returns the last deposits made in the L2 bridge
+// to the destination address, the newest first
+func (p *PostgresStorage) GetBridgeDepositsByDestinationAddress(ctx context.Context, destinationAddress common.Address, limit uint64, dbTx pgx.Tx) ([]BridgeDeposit, error) {
+	if !p.cfg.BridgeIndexing.Enabled {
+		return nil, ErrBridgeIndexingDisabled
+	}
+
+	const getDepositsSQL = `
+        SELECT block_num, tx_hash, log_index, leaf_type, orig_net, orig_addr, dest_net, dest_addr, amount::VARCHAR, metadata, deposit_cnt
+          FROM state.bridge_deposit
+         WHERE dest_addr = $1
+         ORDER BY block_num DESC, log_index DESC
+         LIMIT $2`
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getDepositsSQL, destinationAddress.String(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deposits := []BridgeDeposit{}
+	for rows.Next() {
+		var (
+			d                                  BridgeDeposit
+			txHash, origAddr, destAddr, amount string
+		)
+		if err := rows.Scan(&d.BlockNumber, &txHash, &d.LogIndex, &d.LeafType, &d.OriginNetwork, &origAddr,
+			&d.DestinationNetwork, &destAddr, &amount, &d.Metadata, &d.DepositCount); err != nil {
+			return nil, err
+		}
+		d.TxHash = common.HexToHash(txHash)
+		d.OriginAddress = common.HexToAddress(origAddr)
+		d.DestinationAddress = common.HexToAddress(destAddr)
+		d.Amount, _ = new(big.Int).SetString(amount, encoding.Base10)
+		deposits = append(deposits, d)
+	}
+	return deposits, rows.Err()
+}
+
+// GetBridgeClaimsByDestinationAddress returns the last claims made in the L2 bridge
+// to the destination address, the newest first
+func (p *PostgresStorage) GetBridgeClaimsByDestinationAddress(ctx context.Context, destinationAddress common.Address, limit uint64, dbTx pgx.Tx) ([]BridgeClaim, error) {
+	if !p.cfg.BridgeIndexing.Enabled {
+		return nil, ErrBridgeIndexingDisabled
+	}
+
+	const getClaimsSQL = `
+        SELECT block_num, tx_hash, log_index, index, orig_net, orig_addr, dest_addr, amount::VARCHAR
+          FROM state.bridge_claim
+         WHERE dest_addr = $1
+         ORDER BY block_num DESC, log_index DESC
+         LIMIT $2`
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getClaimsSQL, destinationAddress.String(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	claims := []BridgeClaim{}
+	for rows.Next() {
+		var (
+			c                                  BridgeClaim
+			txHash, origAddr, destAddr, amount string
+		)
+		if err := rows.Scan(&c.BlockNumber, &txHash, &c.LogIndex, &c.Index, &c.OriginNetwork, &origAddr, &destAddr, &amount); err != nil {
+			return nil, err
+		}
+		c.TxHash = common.HexToHash(txHash)
+		c.OriginAddress = common.HexToAddress(origAddr)
+		c.DestinationAddress = common.HexToAddress(destAddr)
+		c.Amount, _ = new(big.Int).SetString(amount, encoding.Base10)
+		claims = append(claims, c)
+	}
+	return claims, rows.Err()
+}
+
 // GetExitRootByGlobalExitRoot returns the mainnet and rollup exit root given
 // a global exit root number.
 func (p *PostgresStorage) GetExitRootByGlobalExitRoot(ctx context.Context, ger common.Hash, dbTx pgx.Tx) (*GlobalExitRoot, error) {
@@ -2229,6 +2550,28 @@ func (p *PostgresStorage) GetSequences(ctx context.Context, lastVerifiedBatchNum
 	return sequences, err
 }
 
+// GetSequencesInRange gets the sequences including any batch of the batch range, both included
+func (p *PostgresStorage) GetSequencesInRange(ctx context.Context, fromBatchNumber, toBatchNumber uint64, dbTx pgx.Tx) ([]Sequence, error) {
+	const getSequencesInRangeSQL = "SELECT from_batch_num, to_batch_num FROM state.sequences WHERE to_batch_num >= $1 AND from_batch_num <= $2 ORDER BY from_batch_num ASC"
+	q := p.getExecQuerier(dbTx)
+
+	rows, err := q.Query(ctx, getSequencesInRangeSQL, fromBatchNumber, toBatchNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sequences := []Sequence{}
+	for rows.Next() {
+		var sequence Sequence
+		if err := rows.Scan(&sequence.FromBatchNumber, &sequence.ToBatchNumber); err != nil {
+			return sequences, err
+		}
+		sequences = append(sequences, sequence)
+	}
+	return sequences, rows.Err()
+}
+
 // GetVirtualBatchToProve return the next batch that is not proved, neither in
 // proved process.
 func (p *PostgresStorage) GetVirtualBatchToProve(ctx context.Context, lastVerfiedBatchNumber uint64, dbTx pgx.Tx) (*Batch, error) {
@@ -2315,8 +2658,9 @@ func (p *PostgresStorage) GetProofReadyToVerify(ctx context.Context, lastVerfied
 	return proof, err
 }
 
-// GetProofsToAggregate return the next to proof that it is possible to aggregate
-func (p *PostgresStorage) GetProofsToAggregate(ctx context.Context, dbTx pgx.Tx) (*Proof, *Proof, error) {
+// GetProofsToAggregate return the next to proof that it is possible to aggregate, the proofs whose
+// aggregation would cover more than maxBatches batches are skipped. 0 means no limit
+func (p *PostgresStorage) GetProofsToAggregate(ctx context.Context, maxBatches uint64, dbTx pgx.Tx) (*Proof, *Proof, error) {
 	var (
 		proof1 *Proof = &Proof{}
 		proof2 *Proof = &Proof{}
@@ -2363,13 +2707,14 @@ func (p *PostgresStorage) GetProofsToAggregate(ctx context.Context, dbTx pgx.Tx)
 						EXISTS ( SELECT 1 FROM state.sequences s WHERE p2.batch_num = s.from_batch_num) AND
 						EXISTS ( SELECT 1 FROM state.sequences s WHERE p2.batch_num_final = s.to_batch_num)
 					)
-				)
+				) AND
+			  ($1 = 0 OR p2.batch_num_final - p1.batch_num + 1 <= $1)
 		ORDER BY p1.batch_num ASC
 		LIMIT 1
 		`
 
 	e := p.getExecQuerier(dbTx)
-	row := e.QueryRow(ctx, getProofsToAggregateSQL)
+	row := e.QueryRow(ctx, getProofsToAggregateSQL, maxBatches)
 	err := row.Scan(
 		&proof1.BatchNumber, &proof1.BatchNumberFinal, &proof1.Proof, &proof1.ProofID, &proof1.InputProver, &proof1.Prover, &proof1.ProverID, &proof1.GeneratingSince, &proof1.CreatedAt, &proof1.UpdatedAt,
 		&proof2.BatchNumber, &proof2.BatchNumberFinal, &proof2.Proof, &proof2.ProofID, &proof2.InputProver, &proof2.Prover, &proof2.ProverID, &proof2.GeneratingSince, &proof2.CreatedAt, &proof2.UpdatedAt)
@@ -2410,6 +2755,242 @@ func (p *PostgresStorage) DeleteGeneratedProofs(ctx context.Context, batchNumber
 	return err
 }
 
+// AddBatchProvingTime stores the time it took to generate the proof of a batch,
+// if it was already stored it's overwritten
+func (p *PostgresStorage) AddBatchProvingTime(ctx context.Context, batchNumber uint64, prover *string, duration time.Duration, dbTx pgx.Tx) error {
+	const addBatchProvingTimeSQL = `INSERT INTO state.batch_proving_time (batch_num, prover, duration_ms) VALUES ($1, $2, $3)
+		ON CONFLICT (batch_num) DO UPDATE SET prover = EXCLUDED.prover, duration_ms = EXCLUDED.duration_ms, created_at = NOW()`
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, addBatchProvingTimeSQL, batchNumber, prover, duration.Milliseconds())
+	if err != nil {
+		return err
+	}
+	return p.addBatchLifecycleTransition(ctx, batchNumber, BatchProvenStage, nil, nil, dbTx)
+}
+
+// addBatchLifecycleTransition stores the transition of a batch to a stage, only the first
+// time the batch reaches the stage is kept
+func (p *PostgresStorage) addBatchLifecycleTransition(ctx context.Context, batchNumber uint64, stage BatchLifecycleStage, l1TxHash *common.Hash, l1BlockNumber *uint64, dbTx pgx.Tx) error {
+	const addBatchLifecycleTransitionSQL = `INSERT INTO state.batch_lifecycle (batch_num, stage, l1_tx_hash, l1_block_num) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (batch_num, stage) DO NOTHING`
+
+	var txHash *string
+	if l1TxHash != nil {
+		s := l1TxHash.String()
+		txHash = &s
+	}
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, addBatchLifecycleTransitionSQL, batchNumber, string(stage), txHash, l1BlockNumber)
+	return err
+}
+
+// GetBatchLifecycle returns the transitions of the batch to the stages of its lifecycle it has reached,
+// sorted by stage. It returns ErrNotFound if the batch doesn't exist
+func (p *PostgresStorage) GetBatchLifecycle(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]BatchLifecycleTransition, error) {
+	const getBatchLifecycleSQL = `SELECT l.stage, l.l1_tx_hash, l.l1_block_num, l.created_at
+		  FROM state.batch b
+		  LEFT JOIN state.batch_lifecycle l ON l.batch_num = b.batch_num
+		 WHERE b.batch_num = $1
+		 ORDER BY CASE l.stage WHEN 'trusted' THEN 1 WHEN 'virtualized' THEN 2 WHEN 'proven' THEN 3 WHEN 'verified' THEN 4 END`
+
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, getBatchLifecycleSQL, batchNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batchFound := false
+	transitions := []BatchLifecycleTransition{}
+	for rows.Next() {
+		var (
+			stage, txHash *string
+			blockNumber   *uint64
+			createdAt     *time.Time
+		)
+		if err := rows.Scan(&stage, &txHash, &blockNumber, &createdAt); err != nil {
+			return nil, err
+		}
+		batchFound = true
+		if stage == nil {
+			// the batch exists but it hasn't reached any stage yet
+			continue
+		}
+
+		transition := BatchLifecycleTransition{
+			BatchNumber:   batchNumber,
+			Stage:         BatchLifecycleStage(*stage),
+			L1BlockNumber: blockNumber,
+			Timestamp:     *createdAt,
+		}
+		if txHash != nil {
+			hash := common.HexToHash(*txHash)
+			transition.L1TxHash = &hash
+		}
+		transitions = append(transitions, transition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !batchFound {
+		return nil, ErrNotFound
+	}
+
+	return transitions, nil
+}
+
+// GetBatchLifecycleTransitionsSince returns the transitions of the batches to the stages of their
+// lifecycle stored after the provided time, sorted by time
+func (p *PostgresStorage) GetBatchLifecycleTransitionsSince(ctx context.Context, since time.Time, dbTx pgx.Tx) ([]BatchLifecycleTransition, error) {
+	const getBatchLifecycleTransitionsSinceSQL = `SELECT batch_num, stage, l1_tx_hash, l1_block_num, created_at
+		  FROM state.batch_lifecycle
+		 WHERE created_at > $1
+		 ORDER BY created_at, batch_num`
+
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, getBatchLifecycleTransitionsSinceSQL, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []BatchLifecycleTransition{}
+	for rows.Next() {
+		var (
+			transition BatchLifecycleTransition
+			stage      string
+			txHash     *string
+		)
+		if err := rows.Scan(&transition.BatchNumber, &stage, &txHash, &transition.L1BlockNumber, &transition.Timestamp); err != nil {
+			return nil, err
+		}
+		transition.Stage = BatchLifecycleStage(stage)
+		if txHash != nil {
+			hash := common.HexToHash(*txHash)
+			transition.L1TxHash = &hash
+		}
+		transitions = append(transitions, transition)
+	}
+	return transitions, rows.Err()
+}
+
+// GetLastBatchProvingTimes returns the proving times of the last proven batches
+// along with the resources they used, the most recent ones first
+func (p *PostgresStorage) GetLastBatchProvingTimes(ctx context.Context, limit uint64, dbTx pgx.Tx) ([]BatchProvingTime, error) {
+	const getLastBatchProvingTimesSQL = `SELECT t.batch_num, t.prover, t.duration_ms, b.batch_resources
+		  FROM state.batch_proving_time t
+		  JOIN state.batch b ON b.batch_num = t.batch_num
+		 WHERE b.batch_resources IS NOT NULL
+		 ORDER BY t.batch_num DESC
+		 LIMIT $1`
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, getLastBatchProvingTimesSQL, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var provingTimes []BatchProvingTime
+	for rows.Next() {
+		var (
+			provingTime    BatchProvingTime
+			durationMs     int64
+			batchResources []byte
+		)
+		if err := rows.Scan(&provingTime.BatchNumber, &provingTime.Prover, &durationMs, &batchResources); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(batchResources, &provingTime.BatchResources); err != nil {
+			return nil, err
+		}
+		provingTime.Duration = time.Duration(durationMs) * time.Millisecond
+		provingTimes = append(provingTimes, provingTime)
+	}
+
+	return provingTimes, rows.Err()
+}
+
+// PruneHistory deletes the proving times, lifecycle transitions and proofs of the verified batches
+// stored before the provided time, the data of the batches not verified yet and the final proof of
+// the last verified batch are kept. It returns the number of rows deleted from each table
+func (p *PostgresStorage) PruneHistory(ctx context.Context, before time.Time, dbTx pgx.Tx) (map[string]int64, error) {
+	const getLastVerifiedBatchNumSQL = "SELECT COALESCE(MAX(batch_num), 0) FROM state.verified_batch"
+	prunes := []struct {
+		table string
+		sql   string
+	}{
+		{"batch_proving_time", "DELETE FROM state.batch_proving_time WHERE created_at < $1 AND batch_num <= $2"},
+		{"batch_lifecycle", "DELETE FROM state.batch_lifecycle WHERE created_at < $1 AND batch_num <= $2"},
+		{"verified_batch_proof", "DELETE FROM state.verified_batch_proof WHERE created_at < $1 AND batch_num_final < $2"},
+		{"proof", "DELETE FROM state.proof WHERE updated_at < $1 AND batch_num_final <= $2 AND generating_since IS NULL"},
+	}
+
+	e := p.getExecQuerier(dbTx)
+	var lastVerifiedBatchNum uint64
+	if err := e.QueryRow(ctx, getLastVerifiedBatchNumSQL).Scan(&lastVerifiedBatchNum); err != nil {
+		return nil, err
+	}
+
+	deleted := make(map[string]int64, len(prunes))
+	for _, prune := range prunes {
+		commandTag, err := e.Exec(ctx, prune.sql, before, lastVerifiedBatchNum)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prune state.%s: %w", prune.table, err)
+		}
+		deleted[prune.table] = commandTag.RowsAffected()
+	}
+	return deleted, nil
+}
+
+// AddVerifiedBatchProof stores the final proof sent to L1 to verify a range of batches,
+// if a proof of the same final batch was already stored it's overwritten
+func (p *PostgresStorage) AddVerifiedBatchProof(ctx context.Context, proof *VerifiedBatchProof, dbTx pgx.Tx) error {
+	const addVerifiedBatchProofSQL = `INSERT INTO state.verified_batch_proof (batch_num, batch_num_final, proof, public_inputs, new_state_root)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (batch_num_final) DO UPDATE SET batch_num = EXCLUDED.batch_num, proof = EXCLUDED.proof,
+		public_inputs = EXCLUDED.public_inputs, new_state_root = EXCLUDED.new_state_root, created_at = NOW()`
+
+	publicInputs, err := json.Marshal(proof.PublicInputs)
+	if err != nil {
+		return err
+	}
+	e := p.getExecQuerier(dbTx)
+	_, err = e.Exec(ctx, addVerifiedBatchProofSQL, proof.BatchNumber, proof.BatchNumberFinal, proof.Proof,
+		publicInputs, proof.PublicInputs.NewStateRoot.String())
+	return err
+}
+
+// GetVerifiedBatchProof returns the final proof that verified the given batch in L1, it returns
+// ErrNotFound if the batch isn't verified yet or the proof that verified it wasn't generated by this node
+func (p *PostgresStorage) GetVerifiedBatchProof(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*VerifiedBatchProof, error) {
+	const getVerifiedBatchProofSQL = `SELECT p.batch_num, p.batch_num_final, p.proof, p.public_inputs, p.created_at, v.tx_hash, v.block_num
+		  FROM state.verified_batch_proof p
+		  JOIN state.verified_batch v ON v.batch_num = p.batch_num_final AND v.state_root = p.new_state_root
+		 WHERE p.batch_num <= $1 AND p.batch_num_final >= $1
+		 ORDER BY p.batch_num_final
+		 LIMIT 1`
+
+	var (
+		proof        VerifiedBatchProof
+		publicInputs []byte
+		txHash       string
+	)
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, getVerifiedBatchProofSQL, batchNumber).Scan(&proof.BatchNumber, &proof.BatchNumberFinal,
+		&proof.Proof, &publicInputs, &proof.CreatedAt, &txHash, &proof.L1BlockNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(publicInputs, &proof.PublicInputs); err != nil {
+		return nil, err
+	}
+	proof.L1TxHash = common.HexToHash(txHash)
+
+	return &proof, nil
+}
+
 // CleanupGeneratedProofs deletes from the storage the generated proofs up to
 // the specified batch number included.
 func (p *PostgresStorage) CleanupGeneratedProofs(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error {
@@ -2524,6 +3105,28 @@ func (p *PostgresStorage) AddTrustedReorg(ctx context.Context, reorg *TrustedReo
 	return err
 }
 
+// AddL1Reorg is used to store the L1 reorgs handled by the synchronizer
+func (p *PostgresStorage) AddL1Reorg(ctx context.Context, reorg *L1Reorg, dbTx pgx.Tx) error {
+	const insertL1ReorgSQL = "INSERT INTO state.l1_reorg (timestamp, block_num, reason) VALUES (NOW(), $1, $2)"
+
+	e := p.getExecQuerier(dbTx)
+	_, err := e.Exec(ctx, insertL1ReorgSQL, reorg.BlockNumber, reorg.Reason)
+	return err
+}
+
+// CountL1Reorgs returns the number of L1 reorgs
+func (p *PostgresStorage) CountL1Reorgs(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
+	const countL1ReorgsSQL = "SELECT COUNT(*) FROM state.l1_reorg"
+
+	var count uint64
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, countL1ReorgsSQL).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // CountReorgs returns the number of reorgs
 func (p *PostgresStorage) CountReorgs(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	const countReorgsSQL = "SELECT COUNT(*) FROM state.trusted_reorg"
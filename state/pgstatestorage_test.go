@@ -178,6 +178,18 @@ func TestAddAndGetSequences(t *testing.T) {
 	require.Equal(t, uint64(7), sequences[0].ToBatchNumber)
 	require.Equal(t, uint64(8), sequences[1].ToBatchNumber)
 
+	sequences, err = testState.GetSequencesInRange(ctx, 4, 5, dbTx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(sequences))
+	require.Equal(t, uint64(3), sequences[0].FromBatchNumber)
+	require.Equal(t, uint64(7), sequences[0].ToBatchNumber)
+
+	sequences, err = testState.GetSequencesInRange(ctx, 7, 7, dbTx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(sequences))
+	require.Equal(t, uint64(3), sequences[0].FromBatchNumber)
+	require.Equal(t, uint64(7), sequences[1].FromBatchNumber)
+
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
@@ -339,6 +351,139 @@ func TestForcedBatch(t *testing.T) {
 	assert.Equal(t, forcedBatch.ForcedAt.Unix(), fb.ForcedAt.Unix())
 	assert.Equal(t, forcedBatch.GlobalExitRoot, fb.GlobalExitRoot)
 }
+
+func TestGetForcedBatchInclusion(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	block := &state.Block{BlockNumber: 1, ReceivedAt: time.Now()}
+	require.NoError(t, testState.AddBlock(ctx, block, dbTx))
+	forcedBatch := state.ForcedBatch{
+		BlockNumber:       1,
+		ForcedBatchNumber: 1,
+		Sequencer:         common.HexToAddress("0x2536C2745Ac4A584656A830f7bdCd329c94e8F30"),
+		RawTxsData:        []byte{0x01, 0x02},
+		ForcedAt:          time.Now(),
+		GlobalExitRoot:    common.HexToHash("0x40a885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9a0"),
+	}
+	require.NoError(t, testState.AddForcedBatch(ctx, &forcedBatch, dbTx))
+
+	_, err = testState.GetForcedBatchInclusion(ctx, 2, dbTx)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+
+	// pending
+	inclusion, err := testState.GetForcedBatchInclusion(ctx, 1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, forcedBatch.Sequencer, inclusion.Sequencer)
+	assert.Equal(t, forcedBatch.RawTxsData, inclusion.RawTxsData)
+	assert.Equal(t, forcedBatch.GlobalExitRoot, inclusion.GlobalExitRoot)
+	assert.Nil(t, inclusion.BatchNumber)
+	assert.Nil(t, inclusion.Stage)
+
+	// included in an open batch
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num, forced_batch_num) VALUES (1, 1)")
+	require.NoError(t, err)
+	inclusion, err = testState.GetForcedBatchInclusion(ctx, 1, dbTx)
+	require.NoError(t, err)
+	require.NotNil(t, inclusion.BatchNumber)
+	assert.Equal(t, uint64(1), *inclusion.BatchNumber)
+	assert.Nil(t, inclusion.Stage)
+
+	// the last stage reached by the batch is returned
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch_lifecycle (batch_num, stage) VALUES (1, 'trusted')")
+	require.NoError(t, err)
+	require.NoError(t, testState.AddBatchProvingTime(ctx, 1, nil, time.Second, dbTx))
+	inclusion, err = testState.GetForcedBatchInclusion(ctx, 1, dbTx)
+	require.NoError(t, err)
+	require.NotNil(t, inclusion.Stage)
+	assert.Equal(t, state.BatchProvenStage, *inclusion.Stage)
+}
+
+func TestGetBatchLifecycleTransitionsSince(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES (1), (2)")
+	require.NoError(t, err)
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch_lifecycle (batch_num, stage, created_at) VALUES (1, 'trusted', $1), (2, 'trusted', $2)",
+		time.Unix(100, 0), time.Unix(200, 0))
+	require.NoError(t, err)
+	require.NoError(t, testState.AddBatchProvingTime(ctx, 1, nil, time.Second, dbTx))
+
+	transitions, err := testState.GetBatchLifecycleTransitionsSince(ctx, time.Unix(150, 0), dbTx)
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, uint64(2), transitions[0].BatchNumber)
+	assert.Equal(t, state.BatchTrustedStage, transitions[0].Stage)
+	assert.Equal(t, uint64(1), transitions[1].BatchNumber)
+	assert.Equal(t, state.BatchProvenStage, transitions[1].Stage)
+
+	transitions, err = testState.GetBatchLifecycleTransitionsSince(ctx, time.Now().Add(time.Hour), dbTx)
+	require.NoError(t, err)
+	assert.Empty(t, transitions)
+}
+
+func TestVerifiedBatchProof(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	block := &state.Block{BlockNumber: 1, ReceivedAt: time.Now()}
+	require.NoError(t, testState.AddBlock(ctx, block, dbTx))
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES (1), (2), (3)")
+	require.NoError(t, err)
+
+	stateRoot := common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f2")
+	proof := &state.VerifiedBatchProof{
+		BatchNumber:      1,
+		BatchNumberFinal: 2,
+		Proof:            "0x1234",
+		PublicInputs: state.FinalProofPublicInputs{
+			OldBatchNum:    0,
+			ChainID:        1001,
+			ForkID:         7,
+			NewStateRoot:   stateRoot,
+			NewBatchNum:    2,
+			AggregatorAddr: common.HexToAddress("0x1"),
+		},
+	}
+	require.NoError(t, testState.AddVerifiedBatchProof(ctx, proof, dbTx))
+
+	// the proof isn't returned until the batches are verified in L1
+	_, err = testState.GetVerifiedBatchProof(ctx, 1, dbTx)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+
+	txHash := common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f1")
+	for batchNumber := uint64(1); batchNumber <= 2; batchNumber++ {
+		require.NoError(t, testState.AddVirtualBatch(ctx, &state.VirtualBatch{BlockNumber: 1, BatchNumber: batchNumber, TxHash: txHash}, dbTx))
+	}
+	require.NoError(t, testState.AddVerifiedBatch(ctx, &state.VerifiedBatch{BlockNumber: 1, BatchNumber: 2, StateRoot: stateRoot, TxHash: txHash}, dbTx))
+
+	for batchNumber := uint64(1); batchNumber <= 2; batchNumber++ {
+		verifiedProof, err := testState.GetVerifiedBatchProof(ctx, batchNumber, dbTx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), verifiedProof.BatchNumber)
+		assert.Equal(t, uint64(2), verifiedProof.BatchNumberFinal)
+		assert.Equal(t, proof.Proof, verifiedProof.Proof)
+		assert.Equal(t, proof.PublicInputs, verifiedProof.PublicInputs)
+		assert.Equal(t, txHash, verifiedProof.L1TxHash)
+		assert.Equal(t, uint64(1), verifiedProof.L1BlockNumber)
+	}
+	_, err = testState.GetVerifiedBatchProof(ctx, 3, dbTx)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+}
+
 func TestCleanupLockedProofs(t *testing.T) {
 	require := require.New(t)
 	assert := assert.New(t)
@@ -849,6 +994,124 @@ func TestGetLogs(t *testing.T) {
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
+func TestGetLogsFilteredByBloom(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+
+	cfg := state.Config{
+		MaxLogsCount:      8,
+		MaxLogsBlockRange: 10,
+	}
+	pgStateStorage = state.NewPostgresStorage(cfg, stateDb)
+	testState.PostgresStorage = pgStateStorage
+
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = testState.PostgresStorage.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES ($1)", batchNumber)
+	assert.NoError(t, err)
+
+	// each block has a log emitted by its own address with its own topic
+	addresses := []common.Address{common.HexToAddress("0x111"), common.HexToAddress("0x222"), common.HexToAddress("0x333")}
+	topics := []common.Hash{common.HexToHash("0xaaa"), common.HexToHash("0xbbb"), common.HexToHash("0xccc")}
+	for i := 0; i < 3; i++ {
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       nil,
+			Value:    new(big.Int),
+			Gas:      0,
+			GasPrice: big.NewInt(0),
+		})
+
+		receipt := &types.Receipt{
+			Type:              uint8(tx.Type()),
+			PostState:         state.ZeroHash.Bytes(),
+			CumulativeGasUsed: 0,
+			EffectiveGasPrice: big.NewInt(0),
+			BlockNumber:       big.NewInt(int64(i) + 1),
+			GasUsed:           tx.Gas(),
+			TxHash:            tx.Hash(),
+			TransactionIndex:  0,
+			Status:            types.ReceiptStatusSuccessful,
+			Logs:              []*types.Log{{Address: addresses[i], Topics: []common.Hash{topics[i]}, TxHash: tx.Hash()}},
+		}
+
+		header := &types.Header{
+			Number:     big.NewInt(int64(i) + 1),
+			ParentHash: state.ZeroHash,
+			Coinbase:   state.ZeroAddress,
+			Root:       state.ZeroHash,
+			GasUsed:    1,
+			GasLimit:   10,
+			Time:       uint64(time.Now().Unix()),
+		}
+
+		transactions := []*types.Transaction{tx}
+		receipts := []*types.Receipt{receipt}
+		l2Block := types.NewBlock(header, transactions, []*types.Header{}, receipts, &trie.StackTrie{})
+		receipt.BlockHash = l2Block.Hash()
+
+		storeTxsEGPData := []state.StoreTxEGPData{{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage}}
+		err = testState.AddL2Block(ctx, batchNumber, l2Block, receipts, storeTxsEGPData, dbTx)
+		require.NoError(t, err)
+	}
+
+	type testCase struct {
+		name                string
+		addresses           []common.Address
+		topics              [][]common.Hash
+		expectedBlockNumber []uint64
+	}
+
+	testCases := []testCase{
+		{
+			name:                "filtered by address",
+			addresses:           []common.Address{addresses[1]},
+			expectedBlockNumber: []uint64{2},
+		},
+		{
+			name:                "filtered by any of the addresses",
+			addresses:           []common.Address{addresses[0], addresses[2]},
+			expectedBlockNumber: []uint64{1, 3},
+		},
+		{
+			name:                "filtered by address and topic",
+			addresses:           []common.Address{addresses[2]},
+			topics:              [][]common.Hash{{topics[2]}},
+			expectedBlockNumber: []uint64{3},
+		},
+		{
+			name:                "address and topic of different blocks",
+			addresses:           []common.Address{addresses[0]},
+			topics:              [][]common.Hash{{topics[1]}},
+			expectedBlockNumber: []uint64{},
+		},
+		{
+			name:                "address without logs",
+			addresses:           []common.Address{common.HexToAddress("0x444")},
+			expectedBlockNumber: []uint64{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logs, err := testState.GetLogs(ctx, 1, 3, testCase.addresses, testCase.topics, nil, nil, dbTx)
+			require.NoError(t, err)
+
+			blockNumbers := []uint64{}
+			for _, l := range logs {
+				blockNumbers = append(blockNumbers, l.BlockNumber)
+			}
+			assert.Equal(t, testCase.expectedBlockNumber, blockNumbers)
+		})
+	}
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
 func TestGetNativeBlockHashesInRange(t *testing.T) {
 	initOrResetDB()
 
@@ -967,3 +1230,145 @@ func TestGetNativeBlockHashesInRange(t *testing.T) {
 
 	require.NoError(t, dbTx.Commit(ctx))
 }
+
+func TestStateRootIndex(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES ($1)", batchNumber)
+	require.NoError(t, err)
+
+	stateRoot := common.HexToHash("0x1234")
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		ParentHash: state.ZeroHash,
+		Coinbase:   state.ZeroAddress,
+		Root:       stateRoot,
+		GasLimit:   10,
+		Time:       uint64(time.Now().Unix()),
+	}
+	l2Block := types.NewBlock(header, []*types.Transaction{}, []*types.Header{}, []*types.Receipt{}, &trie.StackTrie{})
+	err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, []*types.Receipt{}, []state.StoreTxEGPData{}, dbTx)
+	require.NoError(t, err)
+
+	root, err := pgStateStorage.GetStateRootByL2BlockNumber(ctx, 1, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, stateRoot, root)
+
+	blockNumber, blockBatchNumber, err := pgStateStorage.GetL2BlockNumberByStateRoot(ctx, stateRoot, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), blockNumber)
+	assert.Equal(t, batchNumber, blockBatchNumber)
+
+	_, err = pgStateStorage.GetStateRootByL2BlockNumber(ctx, 2, dbTx)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+	_, _, err = pgStateStorage.GetL2BlockNumberByStateRoot(ctx, common.HexToHash("0x5678"), dbTx)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+}
+
+func TestGetLastBlocks(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	for i := uint64(1); i <= 3; i++ {
+		block := &state.Block{
+			BlockNumber: i,
+			BlockHash:   common.BigToHash(new(big.Int).SetUint64(i)),
+			ParentHash:  common.BigToHash(new(big.Int).SetUint64(i - 1)),
+			ReceivedAt:  time.Now(),
+		}
+		err = testState.AddBlock(ctx, block, dbTx)
+		require.NoError(t, err)
+	}
+
+	blocks, err := testState.GetLastBlocks(ctx, 2, dbTx)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, uint64(3), blocks[0].BlockNumber)
+	assert.Equal(t, common.BigToHash(big.NewInt(3)), blocks[0].BlockHash)
+	assert.Equal(t, uint64(2), blocks[1].BlockNumber)
+	assert.Equal(t, common.BigToHash(big.NewInt(1)), blocks[1].ParentHash)
+
+	blocks, err = testState.GetLastBlocks(ctx, 10, dbTx)
+	require.NoError(t, err)
+	assert.Len(t, blocks, 3)
+}
+
+func TestAddL1Reorg(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	count, err := testState.CountL1Reorgs(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+
+	err = testState.AddL1Reorg(ctx, &state.L1Reorg{BlockNumber: 10, Reason: "L1 reorg"}, dbTx)
+	require.NoError(t, err)
+
+	count, err = testState.CountL1Reorgs(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	// the L1 reorgs are not trusted reorgs
+	count, err = testState.CountReorgs(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+}
+
+func TestPruneHistory(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	block := &state.Block{BlockNumber: 1, ReceivedAt: time.Now()}
+	require.NoError(t, testState.AddBlock(ctx, block, dbTx))
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num) VALUES (1), (2), (3)")
+	require.NoError(t, err)
+
+	prover := "prover"
+	for batchNumber := uint64(1); batchNumber <= 3; batchNumber++ {
+		require.NoError(t, testState.AddBatchProvingTime(ctx, batchNumber, &prover, time.Second, dbTx))
+	}
+	stateRoot := common.HexToHash("0x1")
+	txHash := common.HexToHash("0x2")
+	for batchNumber := uint64(1); batchNumber <= 2; batchNumber++ {
+		require.NoError(t, testState.AddVerifiedBatchProof(ctx, &state.VerifiedBatchProof{BatchNumber: batchNumber, BatchNumberFinal: batchNumber, Proof: "0x1234", PublicInputs: state.FinalProofPublicInputs{NewStateRoot: stateRoot}}, dbTx))
+		require.NoError(t, testState.AddVirtualBatch(ctx, &state.VirtualBatch{BlockNumber: 1, BatchNumber: batchNumber, TxHash: txHash}, dbTx))
+		require.NoError(t, testState.AddVerifiedBatch(ctx, &state.VerifiedBatch{BlockNumber: 1, BatchNumber: batchNumber, StateRoot: stateRoot, TxHash: txHash}, dbTx))
+	}
+
+	// nothing is pruned within the retention
+	deleted, err := testState.PruneHistory(ctx, time.Now().Add(-time.Hour), dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted["batch_proving_time"])
+	assert.Equal(t, int64(0), deleted["verified_batch_proof"])
+
+	// the data of the batch not verified and the proof of the last verified batch are kept
+	deleted, err = testState.PruneHistory(ctx, time.Now().Add(time.Hour), dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted["batch_proving_time"])
+	assert.Equal(t, int64(1), deleted["verified_batch_proof"])
+
+	_, err = testState.GetVerifiedBatchProof(ctx, 1, dbTx)
+	assert.ErrorIs(t, err, state.ErrNotFound)
+	_, err = testState.GetVerifiedBatchProof(ctx, 2, dbTx)
+	assert.NoError(t, err)
+}
@@ -1,6 +1,10 @@
 package state
 
-import "time"
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 // Proof struct
 type Proof struct {
@@ -20,3 +24,39 @@ type Proof struct {
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
+
+// BatchProvingTime is the time it took to generate the proof of a batch along
+// with the resources used by the batch
+type BatchProvingTime struct {
+	BatchNumber    uint64
+	Prover         *string
+	Duration       time.Duration
+	BatchResources BatchResources
+}
+
+// VerifiedBatchProof is the final proof sent to L1 to verify a range of batches
+// along with its public inputs. The L1 fields are only set once the final batch
+// of the range has been verified in L1 with the same state root as the proof
+type VerifiedBatchProof struct {
+	BatchNumber      uint64
+	BatchNumberFinal uint64
+	Proof            string
+	PublicInputs     FinalProofPublicInputs
+	L1TxHash         common.Hash
+	L1BlockNumber    uint64
+	CreatedAt        time.Time
+}
+
+// FinalProofPublicInputs are the public inputs of a final proof
+type FinalProofPublicInputs struct {
+	OldStateRoot     common.Hash    `json:"oldStateRoot"`
+	OldAccInputHash  common.Hash    `json:"oldAccInputHash"`
+	OldBatchNum      uint64         `json:"oldBatchNum"`
+	ChainID          uint64         `json:"chainId"`
+	ForkID           uint64         `json:"forkId"`
+	NewStateRoot     common.Hash    `json:"newStateRoot"`
+	NewAccInputHash  common.Hash    `json:"newAccInputHash"`
+	NewLocalExitRoot common.Hash    `json:"newLocalExitRoot"`
+	NewBatchNum      uint64         `json:"newBatchNum"`
+	AggregatorAddr   common.Address `json:"aggregatorAddr"`
+}
@@ -0,0 +1,34 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+// StartPruning prunes the historical data older than the retention every pruning interval
+// until the context is done
+func (s *State) StartPruning(ctx context.Context, cfg PruningConfig) {
+	ticker := time.NewTicker(cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.prune(ctx, cfg)
+		}
+	}
+}
+
+func (s *State) prune(ctx context.Context, cfg PruningConfig) {
+	before := time.Now().Add(-cfg.Retention.Duration)
+	start := time.Now()
+	deleted, err := s.PruneHistory(ctx, before, nil)
+	if err != nil {
+		log.Errorf("failed to prune the state history before %v, err: %v", before, err)
+		return
+	}
+	log.Infof("state history before %v pruned in %v, deleted rows: %v", before, time.Since(start), deleted)
+}
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrExecutorUnavailable is returned without sending the request while the circuit breaker is
+// open, after several consecutive requests failed because the executor was unreachable or hung
+var ErrExecutorUnavailable = errors.New("executor unavailable")
+
+// isUnavailableErr returns if the error of a request means that the executor is unreachable
+// or didn't answer in time
+func isUnavailableErr(err error) bool {
+	code := status.Code(err)
+	return code == codes.Unavailable || code == codes.DeadlineExceeded || errors.Is(err, context.DeadlineExceeded)
+}
+
+// circuitBreaker stops sending requests to the executor once the consecutive failures reach
+// the threshold. After the open timeout a single probe request is let through, the circuit
+// is closed again if it succeeds and stays open for another timeout otherwise
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// allow returns if a request can be sent to the executor
+func (b *circuitBreaker) allow() bool {
+	if b.cfg.FailureThreshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cfg.OpenTimeout.Duration {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record updates the state of the circuit with the result of a request allowed by it. The
+// requests cancelled by the caller don't tell anything about the executor and are ignored
+func (b *circuitBreaker) record(err error, callerErr error) {
+	if b.cfg.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case callerErr != nil:
+		b.probing = false
+	case isUnavailableErr(err):
+		b.failures++
+		if b.probing || (b.openedAt.IsZero() && b.failures >= b.cfg.FailureThreshold) {
+			if b.openedAt.IsZero() {
+				log.Warnf("executor unavailable after %d consecutive failed requests, rejecting the requests for %v: %v",
+					b.failures, b.cfg.OpenTimeout.Duration, err)
+			}
+			b.openedAt = b.now()
+			b.probing = false
+		}
+	default:
+		if !b.openedAt.IsZero() {
+			log.Info("executor available again, accepting the requests")
+		}
+		b.failures = 0
+		b.openedAt = time.Time{}
+		b.probing = false
+	}
+}
@@ -2,50 +2,88 @@ package executor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os/exec"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/grpcclient"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
-// NewExecutorClient is the executor client constructor.
+// ErrExecutorNotCompatible is returned when the executor doesn't implement the API expected by the node
+var ErrExecutorNotCompatible = errors.New("executor is not compatible with the node")
+
+// NewExecutorClient is the executor client constructor. It opens the configured number of
+// connections to the executor, the connection returned is the first one and the cancel function
+// closes the rest of them.
 func NewExecutorClient(ctx context.Context, c Config) (ExecutorServiceClient, *grpc.ClientConn, context.CancelFunc) {
+	callOpts, err := grpcclient.CallOptions(c.GRPCCompression, c.MaxGRPCMessageSize, c.MaxGRPCSendMessageSize)
+	if err != nil {
+		log.Fatalf("invalid executor gRPC options: %v", err)
+	}
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(c.MaxGRPCMessageSize)),
+		grpc.WithDefaultCallOptions(callOpts...),
 		grpc.WithBlock(),
 	}
 	const maxWaitSeconds = 120
 	const maxRetries = 5
+	const delay = 2 * time.Second
 	ctx, cancel := context.WithTimeout(ctx, maxWaitSeconds*time.Second)
 
-	connectionRetries := 0
-
-	var executorConn *grpc.ClientConn
-	var err error
-	delay := 2
-	for connectionRetries < maxRetries {
-		log.Infof("trying to connect to executor: %v", c.URI)
-		executorConn, err = grpc.DialContext(ctx, c.URI, opts...)
+	connections := c.Connections
+	if connections < 1 {
+		connections = 1
+	}
+	executorConns := make([]*grpc.ClientConn, 0, connections)
+	clients := make([]ExecutorServiceClient, 0, connections)
+	for i := 0; i < connections; i++ {
+		var executorConn *grpc.ClientConn
+		err = retry.Do(ctx, "executor_dial", retry.Constant(maxRetries, delay), nil, func() error {
+			log.Infof("trying to connect to executor: %v", c.URI)
+			var err error
+			executorConn, err = grpc.DialContext(ctx, c.URI, opts...)
+			if err != nil {
+				out, err := exec.Command("docker", []string{"logs", "zkevm-prover"}...).Output()
+				if err == nil {
+					log.Infof("Prover logs:\n%s\n", out)
+				}
+			}
+			return err
+		})
 		if err != nil {
-			log.Infof("Retrying connection to executor #%d", connectionRetries)
-			time.Sleep(time.Duration(delay) * time.Second)
-			connectionRetries = connectionRetries + 1
-			out, err := exec.Command("docker", []string{"logs", "zkevm-prover"}...).Output()
-			if err == nil {
-				log.Infof("Prover logs:\n%s\n", out)
+			log.Fatalf("fail to dial: %v", err)
+		}
+		executorConns = append(executorConns, executorConn)
+		clients = append(clients, NewExecutorServiceClient(executorConn))
+	}
+	log.Infof("connected to executor with %d connections", connections)
+	closeConns := func() {
+		cancel()
+		for _, executorConn := range executorConns[1:] {
+			if err := executorConn.Close(); err != nil {
+				log.Errorf("failed to close executor connection: %v", err)
 			}
-		} else {
-			log.Infof("connected to executor")
-			break
 		}
 	}
+	return newClientPool(c, clients), executorConns[0], closeConns
+}
 
-	if connectionRetries == maxRetries {
-		log.Fatalf("fail to dial: %v", err)
+// CheckCompatibility checks that the executor implements the API expected by the node
+// asking for its flush status, it returns the prover ID that runs the executor
+func CheckCompatibility(ctx context.Context, client ExecutorServiceClient) (string, error) {
+	res, err := client.GetFlushStatus(ctx, &emptypb.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		return "", fmt.Errorf("%w: %v", ErrExecutorNotCompatible, err)
+	} else if err != nil {
+		return "", err
 	}
-	executorClient := NewExecutorServiceClient(executorConn)
-	return executorClient, executorConn, cancel
+	return res.ProverId, nil
 }
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// clientPool is an ExecutorServiceClient distributing the requests among several connections
+// to the executor. The idempotent requests are limited by the request timeout, retried when the
+// executor is unreachable or hung and rejected by the circuit breaker while the executor is
+// unavailable, so a hung executor doesn't stall the callers. The requests updating the merkle
+// tree are sent as they are, cancelling them could leave the tree partially updated
+type clientPool struct {
+	cfg     Config
+	clients []ExecutorServiceClient
+	next    atomic.Uint64
+	breaker *circuitBreaker
+}
+
+func newClientPool(cfg Config, clients []ExecutorServiceClient) *clientPool {
+	return &clientPool{
+		cfg:     cfg,
+		clients: clients,
+		breaker: newCircuitBreaker(cfg.CircuitBreaker),
+	}
+}
+
+// ProcessBatch sends the request to the executor, the requests that don't update the merkle
+// tree are idempotent and retried
+func (p *clientPool) ProcessBatch(ctx context.Context, in *ProcessBatchRequest, opts ...grpc.CallOption) (*ProcessBatchResponse, error) {
+	var res *ProcessBatchResponse
+	err := p.call(ctx, "executor_process_batch", in.UpdateMerkleTree == 0, func(ctx context.Context, client ExecutorServiceClient) error {
+		var err error
+		res, err = client.ProcessBatch(ctx, in, opts...)
+		return err
+	})
+	return res, err
+}
+
+// GetFlushStatus sends the request to the executor, it's idempotent and retried
+func (p *clientPool) GetFlushStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetFlushStatusResponse, error) {
+	var res *GetFlushStatusResponse
+	err := p.call(ctx, "executor_get_flush_status", true, func(ctx context.Context, client ExecutorServiceClient) error {
+		var err error
+		res, err = client.GetFlushStatus(ctx, in, opts...)
+		return err
+	})
+	return res, err
+}
+
+// call runs the request in the next client of the pool
+func (p *clientPool) call(ctx context.Context, operation string, idempotent bool, fn func(context.Context, ExecutorServiceClient) error) error {
+	if !idempotent {
+		return fn(ctx, p.client())
+	}
+	isRetryable := func(err error) bool {
+		return ctx.Err() == nil && isUnavailableErr(err)
+	}
+	return retry.Do(ctx, operation, p.cfg.Retry, isRetryable, func() error {
+		if !p.breaker.allow() {
+			return retry.Permanent(ErrExecutorUnavailable)
+		}
+		reqCtx, cancel := ctx, context.CancelFunc(func() {})
+		if p.cfg.RequestTimeout.Duration > 0 {
+			reqCtx, cancel = context.WithTimeout(ctx, p.cfg.RequestTimeout.Duration)
+		}
+		defer cancel()
+		err := fn(reqCtx, p.client())
+		p.breaker.record(err, ctx.Err())
+		return err
+	})
+}
+
+// client returns the next client of the pool
+func (p *clientPool) client() ExecutorServiceClient {
+	return p.clients[(p.next.Add(1)-1)%uint64(len(p.clients))]
+}
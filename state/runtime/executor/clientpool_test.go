@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type fakeExecutorClient struct {
+	calls       int
+	errs        []error
+	hang        bool
+	hasDeadline bool
+}
+
+func (c *fakeExecutorClient) ProcessBatch(ctx context.Context, in *ProcessBatchRequest, opts ...grpc.CallOption) (*ProcessBatchResponse, error) {
+	if err := c.result(ctx); err != nil {
+		return nil, err
+	}
+	return &ProcessBatchResponse{}, nil
+}
+
+func (c *fakeExecutorClient) GetFlushStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetFlushStatusResponse, error) {
+	if err := c.result(ctx); err != nil {
+		return nil, err
+	}
+	return &GetFlushStatusResponse{}, nil
+}
+
+func (c *fakeExecutorClient) result(ctx context.Context) error {
+	c.calls++
+	_, c.hasDeadline = ctx.Deadline()
+	if c.hang {
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if len(c.errs) == 0 {
+		return nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return err
+}
+
+func TestClientPoolRoundRobin(t *testing.T) {
+	clients := []*fakeExecutorClient{{}, {}, {}}
+	pool := newClientPool(Config{}, []ExecutorServiceClient{clients[0], clients[1], clients[2]})
+
+	for i := 0; i < 7; i++ {
+		_, err := pool.GetFlushStatus(context.Background(), &emptypb.Empty{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, clients[0].calls)
+	assert.Equal(t, 2, clients[1].calls)
+	assert.Equal(t, 2, clients[2].calls)
+}
+
+func TestClientPoolRetriesIdempotentRequests(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	cfg := Config{Retry: retry.Constant(3, 0)}
+
+	client := &fakeExecutorClient{errs: []error{unavailable, unavailable}}
+	pool := newClientPool(cfg, []ExecutorServiceClient{client})
+	_, err := pool.ProcessBatch(context.Background(), &ProcessBatchRequest{UpdateMerkleTree: 0})
+	require.NoError(t, err)
+	assert.Equal(t, 3, client.calls)
+
+	// the requests updating the merkle tree aren't retried
+	client = &fakeExecutorClient{errs: []error{unavailable}}
+	pool = newClientPool(cfg, []ExecutorServiceClient{client})
+	_, err = pool.ProcessBatch(context.Background(), &ProcessBatchRequest{UpdateMerkleTree: 1})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 1, client.calls)
+
+	// the errors of the request itself aren't retried
+	client = &fakeExecutorClient{errs: []error{status.Error(codes.InvalidArgument, "invalid request")}}
+	pool = newClientPool(cfg, []ExecutorServiceClient{client})
+	_, err = pool.ProcessBatch(context.Background(), &ProcessBatchRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestClientPoolRequestTimeout(t *testing.T) {
+	client := &fakeExecutorClient{hang: true}
+	pool := newClientPool(Config{RequestTimeout: types.NewDuration(10 * time.Millisecond)}, []ExecutorServiceClient{client})
+
+	_, err := pool.ProcessBatch(context.Background(), &ProcessBatchRequest{UpdateMerkleTree: 0})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, 1, client.calls)
+
+	// the requests updating the merkle tree aren't limited
+	client = &fakeExecutorClient{}
+	pool = newClientPool(Config{RequestTimeout: types.NewDuration(10 * time.Millisecond)}, []ExecutorServiceClient{client})
+	_, err = pool.ProcessBatch(context.Background(), &ProcessBatchRequest{UpdateMerkleTree: 1})
+	require.NoError(t, err)
+	assert.False(t, client.hasDeadline)
+}
+
+func TestClientPoolCircuitBreaker(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	client := &fakeExecutorClient{errs: []error{unavailable, unavailable, unavailable}}
+	pool := newClientPool(Config{
+		CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: types.NewDuration(time.Minute)},
+	}, []ExecutorServiceClient{client})
+	now := time.Now()
+	pool.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := pool.GetFlushStatus(context.Background(), &emptypb.Empty{})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+	// the circuit is open, the requests are rejected without calling the executor
+	_, err := pool.GetFlushStatus(context.Background(), &emptypb.Empty{})
+	assert.ErrorIs(t, err, ErrExecutorUnavailable)
+	assert.Equal(t, 2, client.calls)
+
+	// the failed probe keeps the circuit open
+	now = now.Add(time.Minute)
+	_, err = pool.GetFlushStatus(context.Background(), &emptypb.Empty{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = pool.GetFlushStatus(context.Background(), &emptypb.Empty{})
+	assert.ErrorIs(t, err, ErrExecutorUnavailable)
+	assert.Equal(t, 3, client.calls)
+
+	// the successful probe closes the circuit
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		_, err = pool.GetFlushStatus(context.Background(), &emptypb.Empty{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 5, client.calls)
+
+	// the requests updating the merkle tree aren't rejected by the open circuit
+	client.errs = []error{unavailable, unavailable}
+	for i := 0; i < 2; i++ {
+		_, err = pool.GetFlushStatus(context.Background(), &emptypb.Empty{})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+	_, err = pool.ProcessBatch(context.Background(), &ProcessBatchRequest{UpdateMerkleTree: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 8, client.calls)
+}
@@ -1,6 +1,9 @@
 package executor
 
-import "github.com/0xPolygonHermez/zkevm-node/config/types"
+import (
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
+)
 
 // Config represents the configuration of the executor server
 type Config struct {
@@ -9,5 +12,35 @@ type Config struct {
 	MaxResourceExhaustedAttempts int `mapstructure:"MaxResourceExhaustedAttempts"`
 	// WaitOnResourceExhaustion is the time to wait before retrying a transaction because of resource exhaustion
 	WaitOnResourceExhaustion types.Duration `mapstructure:"WaitOnResourceExhaustion"`
-	MaxGRPCMessageSize       int            `mapstructure:"MaxGRPCMessageSize"`
+	// MaxGRPCMessageSize is the max size of the messages received from the executor
+	MaxGRPCMessageSize int `mapstructure:"MaxGRPCMessageSize"`
+	// MaxGRPCSendMessageSize is the max size of the messages sent to the executor, the requests exceeding
+	// it fail before being sent. It should match the max message size accepted by the executor
+	MaxGRPCSendMessageSize int `mapstructure:"MaxGRPCSendMessageSize"`
+	// GRPCCompression is the compression of the messages sent to the executor, the possible values
+	// are "" (no compression), "gzip" and "snappy". The executor must support it
+	GRPCCompression string `mapstructure:"GRPCCompression"`
+	// Debug has the debug flags of the requests sent to the executor for each origin
+	Debug DebugConfig `mapstructure:"Debug"`
+	// Connections is the number of connections opened to the executor, the requests are
+	// distributed among them in round robin
+	Connections int `mapstructure:"Connections"`
+	// RequestTimeout is the max time an idempotent request to the executor can take, 0 doesn't
+	// limit it. The requests updating the merkle tree are never limited
+	RequestTimeout types.Duration `mapstructure:"RequestTimeout"`
+	// Retry is the retry policy of the idempotent requests failing because the executor is
+	// unreachable or the request timed out, the requests updating the merkle tree aren't retried
+	Retry retry.Config `mapstructure:"Retry"`
+	// CircuitBreaker rejects the idempotent requests while the executor is unavailable
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"CircuitBreaker"`
+}
+
+// CircuitBreakerConfig is the configuration of the circuit breaker of the executor client
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive requests failing because the executor is
+	// unreachable or the request timed out that opens the circuit, 0 disables the circuit breaker
+	FailureThreshold int `mapstructure:"FailureThreshold"`
+	// OpenTimeout is the time the requests are rejected once the circuit is open, after it a
+	// single request is sent to check if the executor is available again
+	OpenTimeout types.Duration `mapstructure:"OpenTimeout"`
 }
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"google.golang.org/grpc/metadata"
+)
+
+// ROMLogsMetadataKey is the gRPC metadata key asking the executor to log the execution of the
+// ROM for a request
+const ROMLogsMetadataKey = "x-zkevm-rom-logs"
+
+// DebugFlags are the debug flags of the requests sent to the executor
+type DebugFlags struct {
+	// ROMLogs asks the executor to log the execution of the ROM, the flag is passed through
+	// as gRPC metadata so it's ignored by the executors not supporting it
+	ROMLogs bool `mapstructure:"ROMLogs"`
+	// PersistFailedTxTraces persists the full execute trace of the txs failing in the requests,
+	// the request is executed again by the executor to generate the trace of each failing tx
+	PersistFailedTxTraces bool `mapstructure:"PersistFailedTxTraces"`
+}
+
+// DebugConfig has the debug flags of the requests sent to the executor for each origin of the
+// requests, they can be overridden temporarily at runtime by the admin RPC
+type DebugConfig struct {
+	// Sequencer are the flags of the requests of the sequencer
+	Sequencer DebugFlags `mapstructure:"Sequencer"`
+	// Synchronizer are the flags of the requests of the synchronizer
+	Synchronizer DebugFlags `mapstructure:"Synchronizer"`
+	// RPC are the flags of the requests of the eth_call RPC endpoint
+	RPC DebugFlags `mapstructure:"RPC"`
+	// TracesDir is the directory where the traces of the failing txs are persisted
+	TracesDir string `mapstructure:"TracesDir"`
+	// MaxOverrideDuration is the max time the flags can be overridden at runtime
+	MaxOverrideDuration types.Duration `mapstructure:"MaxOverrideDuration"`
+}
+
+// WithDebugFlags returns the context of a request with the debug flags passed through as gRPC metadata
+func WithDebugFlags(ctx context.Context, flags DebugFlags) context.Context {
+	if flags.ROMLogs {
+		ctx = metadata.AppendToOutgoingContext(ctx, ROMLogsMetadataKey, "1")
+	}
+	return ctx
+}
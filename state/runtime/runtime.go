@@ -237,6 +237,8 @@ var (
 
 	// ErrGRPCResourceExhaustedAsTimeout indicates a GRPC resource exhausted error
 	ErrGRPCResourceExhaustedAsTimeout = errors.New("request timed out")
+	// ErrGRPCMessageTooLarge indicates a GRPC message exceeds the max message size of the node or the executor
+	ErrGRPCMessageTooLarge = errors.New("gRPC message exceeds the max message size, increase MaxGRPCMessageSize/MaxGRPCSendMessageSize or enable GRPCCompression")
 )
 
 // ExecutionResult includes all output after executing given evm
@@ -33,9 +33,15 @@ type State struct {
 	tree           *merkletree.StateTree
 	eventLog       *event.EventLog
 
-	lastL2BlockSeen         atomic.Pointer[types.Block]
-	newL2BlockEvents        chan NewL2BlockEvent
-	newL2BlockEventHandlers []NewL2BlockEventHandler
+	lastL2BlockSeen            atomic.Pointer[types.Block]
+	newL2BlockEvents           chan NewL2BlockEvent
+	newL2BlockEventHandlers    []NewL2BlockEventHandler
+	newL2BlockEventHandlersMux sync.RWMutex
+	monitorNewL2BlocksOnce     sync.Once
+
+	executorDebugCfg       executor.DebugConfig
+	executorDebugOverrides map[string]executorDebugOverride
+	executorDebugMux       sync.RWMutex
 }
 
 // NewState creates a new State
@@ -53,6 +59,7 @@ func NewState(cfg Config, storage *PostgresStorage, executorClient executor.Exec
 		eventLog:                eventLog,
 		newL2BlockEvents:        make(chan NewL2BlockEvent, newL2BlockEventBufferSize),
 		newL2BlockEventHandlers: []NewL2BlockEventHandler{},
+		executorDebugOverrides:  map[string]executorDebugOverride{},
 	}
 
 	return state
@@ -67,6 +74,15 @@ func (s *State) BeginStateTransaction(ctx context.Context) (pgx.Tx, error) {
 	return tx, nil
 }
 
+// BeginStateReadTransaction starts a read only state transaction, it's started in the read
+// replica when it's configured and available
+func (s *State) BeginStateReadTransaction(ctx context.Context) (pgx.Tx, error) {
+	if s.readRouter == nil {
+		return s.BeginStateTransaction(ctx)
+	}
+	return s.readRouter.Begin(ctx)
+}
+
 // GetBalance from a given address
 func (s *State) GetBalance(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error) {
 	if s.tree == nil {
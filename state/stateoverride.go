@@ -0,0 +1,68 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+)
+
+// OverrideAccount indicates the overriding fields of an account during the
+// execution of an unsigned transaction, the fields left nil are not overridden
+type OverrideAccount struct {
+	Nonce     *uint64
+	Code      *[]byte
+	Balance   *big.Int
+	State     *map[common.Hash]common.Hash
+	StateDiff *map[common.Hash]common.Hash
+}
+
+// StateOverride is the collection of overridden accounts
+type StateOverride map[common.Address]OverrideAccount
+
+// applyStateOverride applies the overrides on top of the provided state root in a
+// temporary state tree, returning the root to be used to execute the transaction
+func (s *State) applyStateOverride(ctx context.Context, root common.Hash, stateOverride StateOverride) (common.Hash, error) {
+	if len(stateOverride) == 0 {
+		return root, nil
+	}
+	if s.tree == nil {
+		return common.Hash{}, ErrStateTreeNil
+	}
+
+	tree := s.tree.Temporary()
+	batchUUID := uuid.NewString()
+	newRoot := root.Bytes()
+	var err error
+	for address, account := range stateOverride {
+		if account.State != nil {
+			return common.Hash{}, fmt.Errorf("account %s: %w", address.String(), ErrStorageOverrideNotSupported)
+		}
+		if account.Nonce != nil {
+			if newRoot, _, err = tree.SetNonce(ctx, address, new(big.Int).SetUint64(*account.Nonce), newRoot, batchUUID); err != nil {
+				return common.Hash{}, fmt.Errorf("failed to override nonce of account %s: %w", address.String(), err)
+			}
+		}
+		if account.Balance != nil {
+			if newRoot, _, err = tree.SetBalance(ctx, address, account.Balance, newRoot, batchUUID); err != nil {
+				return common.Hash{}, fmt.Errorf("failed to override balance of account %s: %w", address.String(), err)
+			}
+		}
+		if account.Code != nil {
+			if newRoot, _, err = tree.SetCode(ctx, address, *account.Code, newRoot, batchUUID); err != nil {
+				return common.Hash{}, fmt.Errorf("failed to override code of account %s: %w", address.String(), err)
+			}
+		}
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				if newRoot, _, err = tree.SetStorageAt(ctx, address, key.Big(), value.Big(), newRoot, batchUUID); err != nil {
+					return common.Hash{}, fmt.Errorf("failed to override storage of account %s: %w", address.String(), err)
+				}
+			}
+		}
+	}
+
+	return common.BytesToHash(newRoot), nil
+}
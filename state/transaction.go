@@ -11,8 +11,10 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/grpcclient"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/retry"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/fakevm"
@@ -30,6 +32,7 @@ import (
 	"github.com/jackc/pgx/v4"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -224,7 +227,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 	if receipt.BlockNumber.Uint64() > 0 {
 		previousBlockNumber = receipt.BlockNumber.Uint64() - 1
 	}
-	previousBlock, err := s.GetL2BlockByNumber(ctx, previousBlockNumber, dbTx)
+	oldStateRoot, err := s.GetStateRootByL2BlockNumber(ctx, previousBlockNumber, dbTx)
 	if err != nil {
 		return nil, err
 	}
@@ -238,7 +241,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 	forkId := s.GetForkIDByBatchNumber(batch.BatchNumber)
 
 	// gets batch that including the previous l2 block
-	previousBatch, err := s.GetBatchByL2BlockNumber(ctx, previousBlock.NumberU64(), dbTx)
+	previousBatch, err := s.GetBatchByL2BlockNumber(ctx, previousBlockNumber, dbTx)
 	if err != nil {
 		return nil, err
 	}
@@ -252,7 +255,7 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 	var txHashToGenerateCallTrace []byte
 	var txHashToGenerateExecuteTrace []byte
 
-	if traceConfig.IsDefaultTracer() {
+	if traceConfig.IsDefaultTracer() || traceConfig.IsZKEVMCountersTracer() {
 		txHashToGenerateExecuteTrace = transactionHash.Bytes()
 	} else {
 		txHashToGenerateCallTrace = transactionHash.Bytes()
@@ -287,7 +290,14 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		}
 	}
 
-	oldStateRoot := previousBlock.Root()
+	// the zkevmCounters tracer only needs the opcodes of the steps and their gas
+	if traceConfig.IsZKEVMCountersTracer() {
+		traceConfigRequest.DisableStorage = cTrue
+		traceConfigRequest.DisableStack = cTrue
+		traceConfigRequest.EnableMemory = cFalse
+		traceConfigRequest.EnableReturnData = cFalse
+	}
+
 	processBatchRequest := &executor.ProcessBatchRequest{
 		OldBatchNum:     batch.BatchNumber - 1,
 		OldStateRoot:    oldStateRoot.Bytes(),
@@ -368,6 +378,15 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 		return result, nil
 	}
 
+	// the tx is executed alone in the batch, so the counters of the batch are the ones of the tx
+	if traceConfig.IsZKEVMCountersTracer() {
+		trace := NewZKCountersTrace(result.StructLogs, convertedResponse.UsedZkCounters, s.cfg.Batch.Constraints)
+		if result.ExecutorTraceResult, err = json.Marshal(trace); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
 	senderAddress, err := GetSender(*tx)
 	if err != nil {
 		return nil, err
@@ -422,6 +441,13 @@ func (s *State) DebugTransaction(ctx context.Context, transactionHash common.Has
 			log.Errorf("debug transaction: failed to create callTracer, err: %v", err)
 			return nil, fmt.Errorf("failed to create callTracer, err: %v", err)
 		}
+	} else if traceConfig.IsFlatCallTracer() || traceConfig.IsMuxTracer() {
+		// these tracers wrap other native tracers, so they are created by name
+		customTracer, err = tracers.DefaultDirectory.New(*traceConfig.Tracer, tracerContext, traceConfig.TracerConfig)
+		if err != nil {
+			log.Errorf("debug transaction: failed to create %s, err: %v", *traceConfig.Tracer, err)
+			return nil, fmt.Errorf("failed to create %s, err: %v", *traceConfig.Tracer, err)
+		}
 	} else if traceConfig.IsNoopTracer() {
 		customTracer, err = native.NewNoopTracer(tracerContext, traceConfig.TracerConfig)
 		if err != nil {
@@ -735,7 +761,7 @@ func (s *State) PreProcessTransaction(ctx context.Context, tx *types.Transaction
 		return nil, err
 	}
 
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, nil, false, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, sender, nil, false, nil, dbTx)
 	if err != nil {
 		return response, err
 	}
@@ -743,10 +769,11 @@ func (s *State) PreProcessTransaction(ctx context.Context, tx *types.Transaction
 	return response, nil
 }
 
-// ProcessUnsignedTransaction processes the given unsigned transaction.
-func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
+// ProcessUnsignedTransaction processes the given unsigned transaction, applying
+// the state overrides, if any, on top of the state of the block.
+func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, stateOverride StateOverride, dbTx pgx.Tx) (*runtime.ExecutionResult, error) {
 	result := new(runtime.ExecutionResult)
-	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, dbTx)
+	response, err := s.internalProcessUnsignedTransaction(ctx, tx, senderAddress, l2BlockNumber, noZKEVMCounters, stateOverride, dbTx)
 	if err != nil {
 		return nil, err
 	}
@@ -768,9 +795,7 @@ func (s *State) ProcessUnsignedTransaction(ctx context.Context, tx *types.Transa
 }
 
 // ProcessUnsignedTransaction processes the given unsigned transaction.
-func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
-	var attempts = 1
-
+func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, noZKEVMCounters bool, stateOverride StateOverride, dbTx pgx.Tx) (*ProcessBatchResponse, error) {
 	if s.executorClient == nil {
 		return nil, ErrExecutorNil
 	}
@@ -810,6 +835,11 @@ func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *type
 		}
 	}
 
+	stateRoot, err = s.applyStateOverride(ctx, stateRoot, stateOverride)
+	if err != nil {
+		return nil, err
+	}
+
 	forkID := s.GetForkIDByBatchNumber(lastBatch.BatchNumber)
 	loadedNonce, err := s.tree.GetNonce(ctx, senderAddress, stateRoot.Bytes())
 	if err != nil {
@@ -855,45 +885,44 @@ func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *type
 	log.Debugf("internalProcessUnsignedTransaction[processBatchRequest.ForkId]: %v", processBatchRequest.ForkId)
 	log.Debugf("internalProcessUnsignedTransaction[processBatchRequest.ContextId]: %v", processBatchRequest.ContextId)
 
-	// Send Batch to the Executor
-	processBatchResponse, err := s.executorClient.ProcessBatch(ctx, processBatchRequest)
+	// Send Batch to the Executor, retrying while the executor is exhausted
+	var processBatchResponse *executor.ProcessBatchResponse
+	isExhausted := func(err error) bool {
+		return status.Code(err) == codes.ResourceExhausted || (processBatchResponse != nil && processBatchResponse.Error == executor.ExecutorError(executor.ExecutorError_EXECUTOR_ERROR_DB_ERROR))
+	}
+	retryCfg := retry.Constant(s.cfg.MaxResourceExhaustedAttempts, s.cfg.WaitOnResourceExhaustion.Duration)
+	debugFlags := s.executorDebugStatus(ExecutorOriginRPC).DebugFlags
+	err = retry.Do(ctx, "state_process_unsigned_transaction", retryCfg, isExhausted, func() error {
+		var err error
+		processBatchResponse, err = s.executorClient.ProcessBatch(executor.WithDebugFlags(ctx, debugFlags), processBatchRequest)
+		return err
+	})
 	if err != nil {
-		if status.Code(err) == codes.ResourceExhausted || (processBatchResponse != nil && processBatchResponse.Error == executor.ExecutorError(executor.ExecutorError_EXECUTOR_ERROR_DB_ERROR)) {
-			log.Errorf("error processing unsigned transaction ", err)
-			for attempts < s.cfg.MaxResourceExhaustedAttempts {
-				time.Sleep(s.cfg.WaitOnResourceExhaustion.Duration)
-				log.Errorf("retrying to process unsigned transaction")
-				processBatchResponse, err = s.executorClient.ProcessBatch(ctx, processBatchRequest)
-				if status.Code(err) == codes.ResourceExhausted || (processBatchResponse != nil && processBatchResponse.Error == executor.ExecutorError(executor.ExecutorError_EXECUTOR_ERROR_DB_ERROR)) {
-					log.Errorf("error processing unsigned transaction ", err)
-					attempts++
-					continue
-				}
-				break
-			}
+		if grpcclient.IsMessageTooLarge(err) {
+			// retrying doesn't help, the request or the response doesn't fit in the max message size
+			log.Errorf("error processing unsigned transaction, request size %d bytes: %v", proto.Size(processBatchRequest), err)
+			return nil, fmt.Errorf("%w: %v", runtime.ErrGRPCMessageTooLarge, err)
 		}
 
-		if err != nil {
-			if status.Code(err) == codes.ResourceExhausted || (processBatchResponse != nil && processBatchResponse.Error == executor.ExecutorError(executor.ExecutorError_EXECUTOR_ERROR_DB_ERROR)) {
-				log.Error("reporting error as time out")
-				return nil, runtime.ErrGRPCResourceExhaustedAsTimeout
-			}
-			// Log the error
-			event := &event.Event{
-				ReceivedAt:  time.Now(),
-				Source:      event.Source_Node,
-				Level:       event.Level_Error,
-				EventID:     event.EventID_ExecutorError,
-				Description: fmt.Sprintf("error processing unsigned transaction %s: %v", tx.Hash(), err),
-			}
+		if isExhausted(err) {
+			log.Error("reporting error as time out")
+			return nil, runtime.ErrGRPCResourceExhaustedAsTimeout
+		}
+		// Log the error
+		event := &event.Event{
+			ReceivedAt:  time.Now(),
+			Source:      event.Source_Node,
+			Level:       event.Level_Error,
+			EventID:     event.EventID_ExecutorError,
+			Description: fmt.Sprintf("error processing unsigned transaction %s: %v", tx.Hash(), err),
+		}
 
-			err2 := s.eventLog.LogEvent(context.Background(), event)
-			if err2 != nil {
-				log.Errorf("error logging event %v", err2)
-			}
-			log.Errorf("error processing unsigned transaction ", err)
-			return nil, err
+		err2 := s.eventLog.LogEvent(context.Background(), event)
+		if err2 != nil {
+			log.Errorf("error logging event %v", err2)
 		}
+		log.Errorf("error processing unsigned transaction ", err)
+		return nil, err
 	}
 
 	if err == nil && processBatchResponse.Error != executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR {
@@ -901,6 +930,9 @@ func (s *State) internalProcessUnsignedTransaction(ctx context.Context, tx *type
 		s.eventLog.LogExecutorError(ctx, processBatchResponse.Error, processBatchRequest)
 		return nil, err
 	}
+	if debugFlags.PersistFailedTxTraces {
+		go s.persistFailedTxTraces(context.Background(), ExecutorOriginRPC, processBatchRequest, processBatchResponse)
+	}
 
 	response, err := s.convertToProcessBatchResponse(processBatchResponse)
 	if err != nil {
@@ -984,8 +1016,8 @@ func CheckSupersetBatchTransactions(existingTxHashes []common.Hash, processedTxs
 	return nil
 }
 
-// EstimateGas for a transaction
-func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, dbTx pgx.Tx) (uint64, []byte, error) {
+// EstimateGas for a transaction, applying the state overrides, if any, on top of the state of the block
+func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common.Address, l2BlockNumber *uint64, stateOverride StateOverride, dbTx pgx.Tx) (uint64, []byte, error) {
 	const ethTransferGas = 21000
 
 	var lowEnd uint64
@@ -1007,6 +1039,11 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 		stateRoot = l2Block.Root()
 	}
 
+	stateRoot, err = s.applyStateOverride(ctx, stateRoot, stateOverride)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	loadedNonce, err := s.tree.GetNonce(ctx, senderAddress, stateRoot.Bytes())
 	if err != nil {
 		return 0, nil, err
@@ -1036,10 +1073,17 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 		}
 	}
 
-	if transaction.Gas() != 0 && transaction.Gas() > lowEnd {
+	gasCap := s.cfg.MaxCumulativeGasUsed
+	if s.cfg.EstimateGasCap > 0 && s.cfg.EstimateGasCap < gasCap {
+		gasCap = s.cfg.EstimateGasCap
+	}
+	if transaction.Gas() != 0 && transaction.Gas() > lowEnd && transaction.Gas() < gasCap {
 		highEnd = transaction.Gas()
 	} else {
-		highEnd = s.cfg.MaxCumulativeGasUsed
+		highEnd = gasCap
+	}
+	if highEnd < lowEnd {
+		return 0, nil, fmt.Errorf("%w: intrinsic gas %d exceeds the gas allowance %d", ErrNotEnoughIntrinsicGas, lowEnd, highEnd)
 	}
 
 	var availableBalance *big.Int
@@ -1077,9 +1121,11 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 		}
 	}
 
-	// Run the transaction with the specified gas value.
-	// Returns a status indicating if the transaction failed, if it was reverted and the accompanying error
-	testTransaction := func(gas uint64, nonce uint64, shouldOmitErr bool) (failed, reverted bool, gasUsed uint64, returnValue []byte, err error) {
+	// Run the transaction with the specified gas value, the executor errors abort the estimation
+	var executions int
+	estimationStart := time.Now()
+	testTransaction := func(gas uint64) (gasEstimationResult, error) {
+		executions++
 		tx := types.NewTx(&types.LegacyTx{
 			Nonce:    nonce,
 			To:       transaction.To(),
@@ -1094,7 +1140,7 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 		batchL2Data, err := EncodeUnsignedTransaction(*tx, s.cfg.ChainID, nil, forkID)
 		if err != nil {
 			log.Errorf("error encoding unsigned transaction ", err)
-			return false, false, gasUsed, nil, err
+			return gasEstimationResult{}, err
 		}
 
 		// Create a batch to be sent to the executor
@@ -1113,113 +1159,48 @@ func (s *State) EstimateGas(transaction *types.Transaction, senderAddress common
 			ContextId:        uuid.NewString(),
 		}
 
-		log.Debugf("EstimateGas[processBatchRequest.OldBatchNum]: %v", processBatchRequest.OldBatchNum)
-		// log.Debugf("EstimateGas[processBatchRequest.BatchL2Data]: %v", hex.EncodeToHex(processBatchRequest.BatchL2Data))
-		log.Debugf("EstimateGas[processBatchRequest.From]: %v", processBatchRequest.From)
-		log.Debugf("EstimateGas[processBatchRequest.OldStateRoot]: %v", hex.EncodeToHex(processBatchRequest.OldStateRoot))
-		log.Debugf("EstimateGas[processBatchRequest.globalExitRoot]: %v", hex.EncodeToHex(processBatchRequest.GlobalExitRoot))
-		log.Debugf("EstimateGas[processBatchRequest.OldAccInputHash]: %v", hex.EncodeToHex(processBatchRequest.OldAccInputHash))
-		log.Debugf("EstimateGas[processBatchRequest.EthTimestamp]: %v", processBatchRequest.EthTimestamp)
-		log.Debugf("EstimateGas[processBatchRequest.Coinbase]: %v", processBatchRequest.Coinbase)
-		log.Debugf("EstimateGas[processBatchRequest.UpdateMerkleTree]: %v", processBatchRequest.UpdateMerkleTree)
-		log.Debugf("EstimateGas[processBatchRequest.ChainId]: %v", processBatchRequest.ChainId)
-		log.Debugf("EstimateGas[processBatchRequest.ForkId]: %v", processBatchRequest.ForkId)
-		log.Debugf("EstimateGas[processBatchRequest.ContextId]: %v", processBatchRequest.ContextId)
+		log.Debugf("EstimateGas: trying to execute tx with %v gas, batch %v, state root %v, context %v",
+			gas, processBatchRequest.OldBatchNum, hex.EncodeToHex(processBatchRequest.OldStateRoot), processBatchRequest.ContextId)
 
 		txExecutionOnExecutorTime := time.Now()
 		processBatchResponse, err := s.executorClient.ProcessBatch(ctx, processBatchRequest)
 		log.Debugf("executor time: %vms", time.Since(txExecutionOnExecutorTime).Milliseconds())
 		if err != nil {
 			log.Errorf("error estimating gas: %v", err)
-			return false, false, gasUsed, nil, err
+			return gasEstimationResult{}, err
 		}
 		if processBatchResponse.Error != executor.ExecutorError_EXECUTOR_ERROR_NO_ERROR {
 			err = executor.ExecutorErr(processBatchResponse.Error)
 			s.eventLog.LogExecutorError(ctx, processBatchResponse.Error, processBatchRequest)
-			return false, false, gasUsed, nil, err
+			return gasEstimationResult{}, err
+		}
+		txResponse := processBatchResponse.Responses[0]
+		result := gasEstimationResult{
+			gasUsed:     txResponse.GasUsed,
+			gasRefunded: txResponse.GasRefunded,
 		}
-		gasUsed = processBatchResponse.Responses[0].GasUsed
-
-		// Check if an out of gas error happened during EVM execution
-		if processBatchResponse.Responses[0].Error != executor.RomError_ROM_ERROR_NO_ERROR {
-			err := executor.RomErr(processBatchResponse.Responses[0].Error)
-
-			if (isGasEVMError(err) || isGasApplyError(err)) && shouldOmitErr {
-				// Specifying the transaction failed, but not providing an error
-				// is an indication that a valid error occurred due to low gas,
-				// which will increase the lower bound for the search
-				return true, false, gasUsed, nil, nil
-			}
 
+		if txResponse.Error != executor.RomError_ROM_ERROR_NO_ERROR {
+			err := executor.RomErr(txResponse.Error)
+			result.failed = true
+			result.err = err
 			if isEVMRevertError(err) {
 				// The EVM reverted during execution, attempt to extract the
 				// error message and return it
-				returnValue := processBatchResponse.Responses[0].ReturnValue
-				return true, true, gasUsed, returnValue, constructErrorFromRevert(err, returnValue)
+				result.reverted = true
+				result.returnValue = txResponse.ReturnValue
+				result.err = constructErrorFromRevert(err, txResponse.ReturnValue)
+			} else if !isGasEVMError(err) && !isGasApplyError(err) {
+				log.Debugf("EstimateGas: tx failed with %v gas: %v", gas, err)
 			}
-
-			return true, false, gasUsed, nil, err
-		}
-
-		return false, false, gasUsed, nil, nil
-	}
-
-	txExecutions := []time.Duration{}
-	var totalExecutionTime time.Duration
-
-	// Check if the highEnd is a good value to make the transaction pass
-	failed, reverted, gasUsed, returnValue, err := testTransaction(highEnd, nonce, false)
-	log.Debugf("Estimate gas. Trying to execute TX with %v gas", highEnd)
-	if failed {
-		if reverted {
-			return 0, returnValue, err
 		}
 
-		// The transaction shouldn't fail, for whatever reason, at highEnd
-		return 0, nil, fmt.Errorf(
-			"unable to apply transaction even for the highest gas limit %d: %w",
-			highEnd,
-			err,
-		)
-	}
-
-	if lowEnd < gasUsed {
-		lowEnd = gasUsed
-	}
-
-	// Start the binary search for the lowest possible gas price
-	for (lowEnd < highEnd) && (highEnd-lowEnd) > 4096 {
-		txExecutionStart := time.Now()
-		mid := (lowEnd + highEnd) / uint64(two)
-
-		log.Debugf("Estimate gas. Trying to execute TX with %v gas", mid)
-
-		failed, reverted, _, _, testErr := testTransaction(mid, nonce, true)
-		executionTime := time.Since(txExecutionStart)
-		totalExecutionTime += executionTime
-		txExecutions = append(txExecutions, executionTime)
-		if testErr != nil && !reverted {
-			// Reverts are ignored in the binary search, but are checked later on
-			// during the execution for the optimal gas limit found
-			return 0, nil, testErr
-		}
-
-		if failed {
-			// If the transaction failed => increase the gas
-			lowEnd = mid + 1
-		} else {
-			// If the transaction didn't fail => make this ok value the high end
-			highEnd = mid
-		}
+		return result, nil
 	}
 
-	executions := int64(len(txExecutions))
-	if executions > 0 {
-		log.Infof("EstimateGas executed TX %v %d times in %d milliseconds", transaction.Hash(), executions, totalExecutionTime.Milliseconds())
-	} else {
-		log.Error("Estimate gas. Tx not executed")
-	}
-	return highEnd, nil, nil
+	gas, returnValue, err := searchGasLimit(lowEnd-1, highEnd, s.cfg.EstimateGasErrorRatio, testTransaction)
+	log.Infof("EstimateGas executed TX %v %d times in %d milliseconds", transaction.Hash(), executions, time.Since(estimationStart).Milliseconds())
+	return gas, returnValue, err
 }
 
 // Checks if executor level valid gas errors occurred
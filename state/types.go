@@ -229,6 +229,16 @@ func (t *TraceConfig) IsCallTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "callTracer"
 }
 
+// IsFlatCallTracer returns true when should use flatCallTracer
+func (t *TraceConfig) IsFlatCallTracer() bool {
+	return t.Tracer != nil && *t.Tracer == "flatCallTracer"
+}
+
+// IsMuxTracer returns true when should use muxTracer
+func (t *TraceConfig) IsMuxTracer() bool {
+	return t.Tracer != nil && *t.Tracer == "muxTracer"
+}
+
 // IsNoopTracer returns true when should use noopTracer
 func (t *TraceConfig) IsNoopTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "noopTracer"
@@ -239,6 +249,11 @@ func (t *TraceConfig) IsPrestateTracer() bool {
 	return t.Tracer != nil && *t.Tracer == "prestateTracer"
 }
 
+// IsZKEVMCountersTracer returns true when should use zkevmCounters tracer
+func (t *TraceConfig) IsZKEVMCountersTracer() bool {
+	return t.Tracer != nil && *t.Tracer == "zkevmCounters"
+}
+
 // IsJSCustomTracer returns true when should use js custom tracer
 func (t *TraceConfig) IsJSCustomTracer() bool {
 	return t.Tracer != nil && strings.Contains(*t.Tracer, "result") && strings.Contains(*t.Tracer, "fault")
@@ -250,6 +265,13 @@ type TrustedReorg struct {
 	Reason      string
 }
 
+// L1Reorg represents an L1 reorg handled by the synchronizer, BlockNumber is
+// the last valid L1 block the state was reset to
+type L1Reorg struct {
+	BlockNumber uint64
+	Reason      string
+}
+
 // HexToAddressPtr create an address from a hex and returns its pointer
 func HexToAddressPtr(hex string) *common.Address {
 	a := common.HexToAddress(hex)
@@ -0,0 +1,73 @@
+package state
+
+import (
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation"
+)
+
+// ZKCountersTrace is the result of the zkevmCounters tracer, it reports the zk counters
+// consumed by a tx along with the batch constraints, so the txs that don't fit in a
+// batch are detected before they are sent.
+//
+// The executor only reports the counters consumed by the whole execution, the opcodes
+// breakdown reports the executions and the gas of each opcode to locate the hot spots
+type ZKCountersTrace struct {
+	Total    ZKCountersTraceCounters       `json:"total"`
+	Limits   ZKCountersTraceCounters       `json:"limits"`
+	Exceeded []string                      `json:"exceeded"`
+	Opcodes  map[string]ZKCountersTraceOps `json:"opcodes"`
+}
+
+// ZKCountersTraceCounters are the zk counters reported by the zkevmCounters tracer
+type ZKCountersTraceCounters struct {
+	GasUsed          uint64 `json:"gasUsed"`
+	KeccakHashes     uint32 `json:"keccakHashes"`
+	PoseidonHashes   uint32 `json:"poseidonHashes"`
+	PoseidonPaddings uint32 `json:"poseidonPaddings"`
+	MemAligns        uint32 `json:"memAligns"`
+	Arithmetics      uint32 `json:"arithmetics"`
+	Binaries         uint32 `json:"binaries"`
+	Steps            uint32 `json:"steps"`
+}
+
+// ZKCountersTraceOps are the executions and the gas of an opcode reported by the
+// zkevmCounters tracer
+type ZKCountersTraceOps struct {
+	Count uint64 `json:"count"`
+	Gas   uint64 `json:"gas"`
+}
+
+// NewZKCountersTrace creates the zkevmCounters trace of a tx from its steps and the
+// counters used by its execution
+func NewZKCountersTrace(structLogs []instrumentation.StructLog, counters ZKCounters, constraints BatchConstraintsCfg) ZKCountersTrace {
+	trace := ZKCountersTrace{
+		Total: ZKCountersTraceCounters{
+			GasUsed:          counters.CumulativeGasUsed,
+			KeccakHashes:     counters.UsedKeccakHashes,
+			PoseidonHashes:   counters.UsedPoseidonHashes,
+			PoseidonPaddings: counters.UsedPoseidonPaddings,
+			MemAligns:        counters.UsedMemAligns,
+			Arithmetics:      counters.UsedArithmetics,
+			Binaries:         counters.UsedBinaries,
+			Steps:            counters.UsedSteps,
+		},
+		Limits: ZKCountersTraceCounters{
+			GasUsed:          constraints.MaxCumulativeGasUsed,
+			KeccakHashes:     constraints.MaxKeccakHashes,
+			PoseidonHashes:   constraints.MaxPoseidonHashes,
+			PoseidonPaddings: constraints.MaxPoseidonPaddings,
+			MemAligns:        constraints.MaxMemAligns,
+			Arithmetics:      constraints.MaxArithmetics,
+			Binaries:         constraints.MaxBinaries,
+			Steps:            constraints.MaxSteps,
+		},
+		Exceeded: constraints.ExceededConstraints(counters),
+		Opcodes:  make(map[string]ZKCountersTraceOps),
+	}
+	for _, structLog := range structLogs {
+		ops := trace.Opcodes[structLog.Op]
+		ops.Count++
+		ops.Gas += structLog.GasCost
+		trace.Opcodes[structLog.Op] = ops
+	}
+	return trace
+}
@@ -0,0 +1,50 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime/instrumentation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewZKCountersTrace(t *testing.T) {
+	structLogs := []instrumentation.StructLog{
+		{Op: "PUSH1", GasCost: 3},
+		{Op: "PUSH1", GasCost: 3},
+		{Op: "SHA3", GasCost: 36},
+		{Op: "SSTORE", GasCost: 20000},
+		{Op: "STOP", GasCost: 0},
+	}
+	counters := state.ZKCounters{
+		CumulativeGasUsed: 43000,
+		UsedKeccakHashes:  3,
+		UsedArithmetics:   40,
+		UsedSteps:         1200,
+	}
+	constraints := state.BatchConstraintsCfg{
+		MaxCumulativeGasUsed: 30000000,
+		MaxKeccakHashes:      2,
+		MaxPoseidonHashes:    252357,
+		MaxPoseidonPaddings:  135191,
+		MaxMemAligns:         236585,
+		MaxArithmetics:       236585,
+		MaxBinaries:          473170,
+		MaxSteps:             7570538,
+	}
+
+	trace := state.NewZKCountersTrace(structLogs, counters, constraints)
+
+	assert.Equal(t, uint64(43000), trace.Total.GasUsed)
+	assert.Equal(t, uint32(3), trace.Total.KeccakHashes)
+	assert.Equal(t, uint32(40), trace.Total.Arithmetics)
+	assert.Equal(t, uint32(1200), trace.Total.Steps)
+	assert.Equal(t, uint32(2), trace.Limits.KeccakHashes)
+	assert.Equal(t, []string{"KeccakHashes 3 exceeds the max 2 by 1"}, trace.Exceeded)
+	assert.Equal(t, map[string]state.ZKCountersTraceOps{
+		"PUSH1":  {Count: 2, Gas: 6},
+		"SHA3":   {Count: 1, Gas: 36},
+		"SSTORE": {Count: 1, Gas: 20000},
+		"STOP":   {Count: 1, Gas: 0},
+	}, trace.Opcodes)
+}
@@ -0,0 +1,90 @@
+package synchronizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// checkAccInputHashes compares, every AccInputHashCheck.Interval, the accumulated input hashes computed
+// locally for the sequences virtualized since the last comparison with the ones stored in L1. The
+// comparison starts after the last verified batch. The first divergence is alerted with an event, the
+// following sequences are not compared since their accumulated input hashes diverge too
+func (s *ClientSynchronizer) checkAccInputHashes() {
+	if !s.cfg.AccInputHashCheck.Enabled || time.Since(s.lastAccInputHashCheck) < s.cfg.AccInputHashCheck.Interval.Duration {
+		return
+	}
+	s.lastAccInputHashCheck = time.Now()
+
+	lastVirtualBatchNum, err := s.state.GetLastVirtualBatchNum(s.ctx, nil)
+	if err != nil {
+		log.Warnf("error getting the last virtual batch to compare the acc input hashes. Error: %v", err)
+		return
+	}
+	// the state has been reset, the batches synced again are compared again
+	if lastVirtualBatchNum < s.accInputHashCheckedUntil {
+		s.accInputHashCheckedUntil = lastVirtualBatchNum
+		s.accInputHashDiverged = false
+	}
+	if s.accInputHashDiverged {
+		return
+	}
+	if s.accInputHashCheckedUntil == 0 {
+		lastVerifiedBatch, err := s.state.GetLastVerifiedBatch(s.ctx, nil)
+		if err != nil && !errors.Is(err, state.ErrNotFound) {
+			log.Warnf("error getting the last verified batch to compare the acc input hashes. Error: %v", err)
+			return
+		} else if err == nil {
+			s.accInputHashCheckedUntil = lastVerifiedBatch.BatchNumber
+		}
+	}
+	if lastVirtualBatchNum <= s.accInputHashCheckedUntil {
+		return
+	}
+
+	lastComparedBatchNum, divergence, err := s.state.CompareAccInputHashes(s.ctx, s.accInputHashCheckedUntil+1, lastVirtualBatchNum, s.l1AccInputHash, nil)
+	if lastComparedBatchNum > s.accInputHashCheckedUntil {
+		s.accInputHashCheckedUntil = lastComparedBatchNum
+	}
+	if err != nil {
+		log.Warnf("error comparing the acc input hashes of batches %d to %d with L1. Error: %v", s.accInputHashCheckedUntil+1, lastVirtualBatchNum, err)
+		return
+	}
+	if divergence == nil {
+		log.Debugf("acc input hashes compared with L1 until batch %d", s.accInputHashCheckedUntil)
+		return
+	}
+
+	s.accInputHashDiverged = true
+	description := fmt.Sprintf("acc input hash of batch %d diverges from L1, local: %s, L1: %s", divergence.BatchNumber, divergence.AccInputHash, divergence.L1AccInputHash)
+	log.Error(description)
+	if s.eventLog != nil {
+		event := &event.Event{
+			ReceivedAt:  time.Now(),
+			Source:      event.Source_Node,
+			Component:   event.Component_Synchronizer,
+			Level:       event.Level_Critical,
+			EventID:     event.EventID_AccInputHashMismatch,
+			Description: description,
+		}
+		err = s.eventLog.LogEvent(context.Background(), event)
+		if err != nil {
+			log.Errorf("error storing acc input hash mismatch event: %v", err)
+		}
+	}
+}
+
+// l1AccInputHash returns the accumulated input hash stored in L1 for the sequence ending in the batch
+func (s *ClientSynchronizer) l1AccInputHash(ctx context.Context, batchNumber uint64) (common.Hash, error) {
+	info, err := s.etherMan.GetSequencedBatchesInfo(ctx, batchNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return info.AccInputHash, nil
+}
@@ -0,0 +1,62 @@
+package synchronizer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGivenAccInputHashDivergenceWhenCheckingAccInputHashesThenAlertOnlyTheFirstOne(t *testing.T) {
+	genesis, cfg, m := setupGenericTest(t)
+	cfg.AccInputHashCheck = AccInputHashCheckConfig{Enabled: true, Interval: cfgTypes.Duration{Duration: 0}}
+	sync, err := NewSynchronizer(false, m.Etherman, []EthermanInterface{m.Etherman}, m.State, m.Pool, m.EthTxManager, m.ZKEVMClient, nil, *genesis, *cfg, false)
+	require.NoError(t, err)
+	s := sync.(*ClientSynchronizer)
+
+	l1AccInputHash := common.HexToHash("0x1")
+	m.Etherman.On("GetSequencedBatchesInfo", s.ctx, uint64(7)).Return(ethmanTypes.SequencedBatchesInfo{AccInputHash: l1AccInputHash}, nil).Once()
+	m.State.On("GetLastVirtualBatchNum", s.ctx, nil).Return(uint64(10), nil).Once()
+	m.State.On("GetLastVerifiedBatch", s.ctx, nil).Return(&state.VerifiedBatch{BatchNumber: 4}, nil).Once()
+	m.State.On("CompareAccInputHashes", s.ctx, uint64(5), uint64(10), mock.Anything, nil).
+		Run(func(args mock.Arguments) {
+			l1AccInputHashFn := args.Get(3).(func(context.Context, uint64) (common.Hash, error))
+			hash, err := l1AccInputHashFn(s.ctx, 7)
+			require.NoError(t, err)
+			assert.Equal(t, l1AccInputHash, hash)
+		}).
+		Return(uint64(7), &state.AccInputHashDivergence{BatchNumber: 10, AccInputHash: common.HexToHash("0x2"), L1AccInputHash: common.HexToHash("0x3")}, nil).Once()
+
+	s.checkAccInputHashes()
+	assert.True(t, s.accInputHashDiverged)
+	assert.Equal(t, uint64(7), s.accInputHashCheckedUntil)
+
+	// the sequences after the divergence are not compared
+	m.State.On("GetLastVirtualBatchNum", s.ctx, nil).Return(uint64(12), nil).Once()
+	s.checkAccInputHashes()
+	m.State.AssertNumberOfCalls(t, "CompareAccInputHashes", 1)
+}
+
+func TestGivenNoDivergenceWhenCheckingAccInputHashesThenCompareOnlyOncePerInterval(t *testing.T) {
+	genesis, cfg, m := setupGenericTest(t)
+	cfg.AccInputHashCheck = AccInputHashCheckConfig{Enabled: true, Interval: cfgTypes.Duration{Duration: time.Hour}}
+	sync, err := NewSynchronizer(false, m.Etherman, []EthermanInterface{m.Etherman}, m.State, m.Pool, m.EthTxManager, m.ZKEVMClient, nil, *genesis, *cfg, false)
+	require.NoError(t, err)
+	s := sync.(*ClientSynchronizer)
+
+	m.State.On("GetLastVirtualBatchNum", s.ctx, nil).Return(uint64(10), nil).Once()
+	m.State.On("GetLastVerifiedBatch", s.ctx, nil).Return(nil, state.ErrNotFound).Once()
+	m.State.On("CompareAccInputHashes", s.ctx, uint64(1), uint64(10), mock.Anything, nil).Return(uint64(10), nil, nil).Once()
+
+	s.checkAccInputHashes()
+	s.checkAccInputHashes()
+	assert.False(t, s.accInputHashDiverged)
+	assert.Equal(t, uint64(10), s.accInputHashCheckedUntil)
+}
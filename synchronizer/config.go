@@ -12,6 +12,16 @@ type Config struct {
 	SyncChunkSize uint64 `mapstructure:"SyncChunkSize"`
 	// TrustedSequencerURL is the rpc url to connect and sync the trusted state
 	TrustedSequencerURL string `mapstructure:"TrustedSequencerURL"`
+	// TrustedSync is the configuration of the synchronization of the trusted state from the trusted sequencer RPC
+	TrustedSync TrustedSyncConfig `mapstructure:"TrustedSync"`
+	// ArchiveL1Logs enables storing the raw L1 logs consumed by the synchronizer, so they
+	// can be reprocessed with the replay-events command without scanning L1 again
+	ArchiveL1Logs bool `mapstructure:"ArchiveL1Logs"`
+	// L1ReorgCheck is the configuration of the periodic verification of the stored L1 blocks
+	L1ReorgCheck L1ReorgCheckConfig `mapstructure:"L1ReorgCheck"`
+	// AccInputHashCheck is the configuration of the periodic comparison of the accumulated input hashes
+	// computed locally with the ones stored in L1
+	AccInputHashCheck AccInputHashCheckConfig `mapstructure:"AccInputHashCheck"`
 
 	// L1ParallelSynchronization Use new L1 synchronization that do in parallel request to L1 and process the data
 	// If false use the legacy sequential mode
@@ -20,11 +30,44 @@ type Config struct {
 	L1ParallelSynchronization L1ParallelSynchronizationConfig `mapstructure:"L1ParallelSynchronization"`
 }
 
+// TrustedSyncConfig is the configuration of the synchronization of the batches and L2 blocks from the RPC
+// of the trusted sequencer, before they are sequenced in L1. Once a batch is virtualized, its trusted data
+// is checked against the data sequenced in L1
+type TrustedSyncConfig struct {
+	// Enabled syncs the trusted state, so the node serves the L2 blocks in near real time. If it's false
+	// only the state sequenced in L1 is synced
+	Enabled bool `mapstructure:"Enabled"`
+	// HaltOnMismatch halts the synchronizer when the trusted data of a batch doesn't match the data sequenced
+	// in L1, instead of replacing it with the L1 data and registering a trusted reorg
+	HaltOnMismatch bool `mapstructure:"HaltOnMismatch"`
+}
+
+// L1ReorgCheckConfig is the configuration of the periodic verification of the hashes of the
+// last stored L1 blocks against L1, it detects the reorgs of blocks older than the latest one
+type L1ReorgCheckConfig struct {
+	// Enabled enables the periodic verification
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time between verifications
+	Interval types.Duration `mapstructure:"Interval"`
+	// Depth is the number of last stored L1 blocks verified on each check
+	Depth uint64 `mapstructure:"Depth"`
+}
+
+// AccInputHashCheckConfig is the configuration of the periodic comparison of the accumulated input hash
+// computed locally for the last batch of each new sequence with the one stored in L1 when it was
+// sequenced, it detects a divergence of the local state before the batches are proved
+type AccInputHashCheckConfig struct {
+	// Enabled enables the periodic comparison
+	Enabled bool `mapstructure:"Enabled"`
+	// Interval is the time between comparisons
+	Interval types.Duration `mapstructure:"Interval"`
+}
+
 // L1ParallelSynchronizationConfig Configuration for parallel mode (if UseParallelModeForL1Synchronization is true)
 type L1ParallelSynchronizationConfig struct {
 	// NumberOfParallelOfEthereumClients Number of clients used to synchronize with L1
 	// (if UseParallelModeForL1Synchronization is true)
-	NumberOfParallelOfEthereumClients uint64 `mapstructue:"NumberOfParallelOfEthereumClients"`
+	NumberOfParallelOfEthereumClients uint64 `mapstructure:"NumberOfParallelOfEthereumClients"`
 	// CapacityOfBufferingRollupInfoFromL1 Size of the buffer used to store rollup information from L1, must be >= to NumberOfEthereumClientsToSync
 	// sugested twice of NumberOfParallelOfEthereumClients
 	// (if UseParallelModeForL1Synchronization is true)
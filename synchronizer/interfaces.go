@@ -5,6 +5,7 @@ import (
 	"math/big"
 
 	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/metrics"
@@ -23,14 +24,19 @@ type EthermanInterface interface {
 	GetTrustedSequencerURL() (string, error)
 	VerifyGenBlockNumber(ctx context.Context, genBlockNumber uint64) (bool, error)
 	GetLatestVerifiedBatchNum() (uint64, error)
+	DecodeEvents(ctx context.Context, logs []ethTypes.Log) ([]etherman.Block, map[common.Hash][]etherman.Order, error)
+	GetSequencedBatchesInfo(ctx context.Context, lastBatchNumber uint64) (ethmanTypes.SequencedBatchesInfo, error)
 }
 
 // stateInterface gathers the methods required to interact with the state.
 type stateInterface interface {
 	GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Block, error)
+	GetLastBlocks(ctx context.Context, limit uint64, dbTx pgx.Tx) ([]state.Block, error)
 	AddGlobalExitRoot(ctx context.Context, exitRoot *state.GlobalExitRoot, dbTx pgx.Tx) error
 	AddForcedBatch(ctx context.Context, forcedBatch *state.ForcedBatch, dbTx pgx.Tx) error
 	AddBlock(ctx context.Context, block *state.Block, dbTx pgx.Tx) error
+	AddL1Logs(ctx context.Context, logs []ethTypes.Log, dbTx pgx.Tx) error
+	GetL1Logs(ctx context.Context, fromBlockNumber uint64, dbTx pgx.Tx) ([]ethTypes.Log, error)
 	Reset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error
 	GetPreviousBlock(ctx context.Context, offset uint64, dbTx pgx.Tx) (*state.Block, error)
 	GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
@@ -52,6 +58,7 @@ type stateInterface interface {
 	AddSequence(ctx context.Context, sequence state.Sequence, dbTx pgx.Tx) error
 	AddAccumulatedInputHash(ctx context.Context, batchNum uint64, accInputHash common.Hash, dbTx pgx.Tx) error
 	AddTrustedReorg(ctx context.Context, trustedReorg *state.TrustedReorg, dbTx pgx.Tx) error
+	AddL1Reorg(ctx context.Context, reorg *state.L1Reorg, dbTx pgx.Tx) error
 	GetReorgedTransactions(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]*ethTypes.Transaction, error)
 	ResetForkID(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) error
 	GetForkIDs(ctx context.Context, dbTx pgx.Tx) ([]state.ForkIDInterval, error)
@@ -62,6 +69,7 @@ type stateInterface interface {
 	UpdateBatchL2Data(ctx context.Context, batchNumber uint64, batchL2Data []byte, dbTx pgx.Tx) error
 	GetForkIDByBatchNumber(batchNumber uint64) uint64
 	GetStoredFlushID(ctx context.Context) (uint64, string, error)
+	CompareAccInputHashes(ctx context.Context, fromBatchNumber, toBatchNumber uint64, l1AccInputHash func(ctx context.Context, batchNumber uint64) (common.Hash, error), dbTx pgx.Tx) (uint64, *state.AccInputHashDivergence, error)
 }
 
 type ethTxManager interface {
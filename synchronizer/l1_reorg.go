@@ -0,0 +1,135 @@
+package synchronizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/event"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// detectL1Reorg checks if the latest stored L1 block has been reorged and, every L1ReorgCheck.Interval,
+// verifies the hashes of the last L1ReorgCheck.Depth stored blocks too, so the reorgs of older blocks
+// are detected. It returns the last valid block if a reorg is detected
+func (s *ClientSynchronizer) detectL1Reorg(lastEthBlockSynced *state.Block) (*state.Block, error) {
+	block, err := s.checkReorg(lastEthBlockSynced)
+	if err != nil || block != nil {
+		return block, err
+	}
+	if !s.cfg.L1ReorgCheck.Enabled || time.Since(s.lastL1ReorgCheck) < s.cfg.L1ReorgCheck.Interval.Duration {
+		return nil, nil
+	}
+	block, err = s.checkStoredL1Blocks()
+	if err != nil {
+		return nil, err
+	}
+	s.lastL1ReorgCheck = time.Now()
+	return block, nil
+}
+
+// checkStoredL1Blocks compares the hashes of the last stored L1 blocks with the ones in L1.
+// It returns the stored block previous to the first reorged one, nil if none has been reorged
+func (s *ClientSynchronizer) checkStoredL1Blocks() (*state.Block, error) {
+	blocks, err := s.state.GetLastBlocks(s.ctx, s.cfg.L1ReorgCheck.Depth, nil)
+	if err != nil {
+		log.Errorf("error getting the last stored L1 blocks. Error: %v", err)
+		return nil, err
+	}
+	// the blocks are sorted from the latest, they are checked from the oldest to find the first reorged one
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if blocks[i].BlockNumber <= s.genesis.GenesisBlockNum {
+			continue
+		}
+		block, err := s.etherMan.EthBlockByNumber(s.ctx, blocks[i].BlockNumber)
+		if err != nil {
+			log.Errorf("error getting block %d from L1. Error: %v", blocks[i].BlockNumber, err)
+			return nil, err
+		}
+		if block.Hash() == blocks[i].BlockHash {
+			continue
+		}
+		log.Warnf("L1 reorg detected in stored block %d. Stored hash: %s, L1 hash: %s", blocks[i].BlockNumber, blocks[i].BlockHash, block.Hash())
+		// If the previous stored block has been reorged too, it's detected by checkReorg in the next iteration
+		previousBlock, err := s.state.GetPreviousBlock(s.ctx, uint64(i+1), nil)
+		if errors.Is(err, state.ErrNotFound) {
+			log.Warn("no stored block previous to the reorged one, resetting the whole state")
+			return &state.Block{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return previousBlock, nil
+	}
+	return nil, nil
+}
+
+// rollbackL1Reorg resets the state to the last valid L1 block after a reorg. The virtual and verified
+// batches of the reorged blocks are removed with them and the L1 reorg is stored, so the components
+// caching L1 data (e.g. the GER cached by the sequencer) invalidate it. A trusted reorg is only
+// registered, for the sequencer to handle it, if the trusted batches are removed too
+func (s *ClientSynchronizer) rollbackL1Reorg(blockNumber uint64) error {
+	lastVirtualBatchNum, err := s.state.GetLastVirtualBatchNum(s.ctx, nil)
+	if err != nil {
+		log.Errorf("error getting the last virtual batch before resetting the state. Error: %v", err)
+		return err
+	}
+	lastBatchNum, err := s.state.GetLastBatchNumber(s.ctx, nil)
+	if err != nil {
+		log.Errorf("error getting the last batch before resetting the state. Error: %v", err)
+		return err
+	}
+	err = s.resetState(blockNumber)
+	if err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("state reset to L1 block %d", blockNumber)
+	newLastVirtualBatchNum, err := s.state.GetLastVirtualBatchNum(s.ctx, nil)
+	if err != nil {
+		log.Errorf("error getting the last virtual batch after resetting the state. Error: %v", err)
+	} else if newLastVirtualBatchNum < lastVirtualBatchNum {
+		description += fmt.Sprintf(", batches %d to %d are not virtualized anymore", newLastVirtualBatchNum+1, lastVirtualBatchNum)
+	}
+	l1Reorg := state.L1Reorg{
+		BlockNumber: blockNumber,
+		Reason:      "L1 reorg, " + description,
+	}
+	err = s.state.AddL1Reorg(s.ctx, &l1Reorg, nil)
+	if err != nil {
+		log.Errorf("error storing the L1 reorg register. Error: %v", err)
+	}
+
+	newLastBatchNum, err := s.state.GetLastBatchNumber(s.ctx, nil)
+	if err != nil {
+		log.Errorf("error getting the last batch after resetting the state. Error: %v", err)
+	} else if newLastBatchNum < lastBatchNum {
+		description += fmt.Sprintf(", trusted batches %d to %d removed", newLastBatchNum+1, lastBatchNum)
+		tr := state.TrustedReorg{
+			BatchNumber: newLastBatchNum + 1,
+			Reason:      "L1 reorg, " + description,
+		}
+		err = s.state.AddTrustedReorg(s.ctx, &tr, nil)
+		if err != nil {
+			log.Errorf("error storing the trusted reorg register of the L1 reorg. Error: %v", err)
+		}
+	}
+	log.Warnf("L1 reorg handled, %s", description)
+
+	if s.eventLog != nil {
+		event := &event.Event{
+			ReceivedAt:  time.Now(),
+			Source:      event.Source_Node,
+			Component:   event.Component_Synchronizer,
+			Level:       event.Level_Warning,
+			EventID:     event.EventID_L1Reorg,
+			Description: description,
+		}
+		err = s.eventLog.LogEvent(context.Background(), event)
+		if err != nil {
+			log.Errorf("error storing L1 reorg event: %v", err)
+		}
+	}
+	return nil
+}
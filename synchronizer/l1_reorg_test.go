@@ -0,0 +1,118 @@
+package synchronizer
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	cfgTypes "github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newL1ReorgTestBlock(blockNumber uint64, extra string) *ethTypes.Block {
+	return ethTypes.NewBlockWithHeader(&ethTypes.Header{
+		Number: new(big.Int).SetUint64(blockNumber),
+		Extra:  []byte(extra),
+	})
+}
+
+func TestGivenStoredBlockReorgedWhenCheckingStoredL1BlocksThenReturnPreviousStoredBlock(t *testing.T) {
+	genesis, cfg, m := setupGenericTest(t)
+	genesis.GenesisBlockNum = 0
+	cfg.L1ReorgCheck = L1ReorgCheckConfig{Enabled: true, Interval: cfgTypes.Duration{Duration: time.Minute}, Depth: 3}
+	sync, err := NewSynchronizer(false, m.Etherman, []EthermanInterface{m.Etherman}, m.State, m.Pool, m.EthTxManager, m.ZKEVMClient, nil, *genesis, *cfg, false)
+	require.NoError(t, err)
+	s := sync.(*ClientSynchronizer)
+
+	storedBlocks := []state.Block{}
+	for _, blockNumber := range []uint64{30, 20, 10} {
+		l1Block := newL1ReorgTestBlock(blockNumber, "")
+		storedBlocks = append(storedBlocks, state.Block{BlockNumber: blockNumber, BlockHash: l1Block.Hash(), ParentHash: l1Block.ParentHash()})
+	}
+	m.State.On("GetLastBlocks", s.ctx, uint64(3), nil).Return(storedBlocks, nil).Once()
+	m.Etherman.On("EthBlockByNumber", s.ctx, uint64(10)).Return(newL1ReorgTestBlock(10, ""), nil).Once()
+	m.Etherman.On("EthBlockByNumber", s.ctx, uint64(20)).Return(newL1ReorgTestBlock(20, "reorged"), nil).Once()
+	m.State.On("GetPreviousBlock", s.ctx, uint64(2), nil).Return(&storedBlocks[2], nil).Once()
+
+	block, err := s.checkStoredL1Blocks()
+	require.NoError(t, err)
+	require.NotNil(t, block)
+	assert.Equal(t, uint64(10), block.BlockNumber)
+}
+
+func TestGivenNoStoredBlockReorgedWhenDetectingL1ReorgThenCheckStoredBlocksOnlyOncePerInterval(t *testing.T) {
+	genesis, cfg, m := setupGenericTest(t)
+	genesis.GenesisBlockNum = 0
+	cfg.L1ReorgCheck = L1ReorgCheckConfig{Enabled: true, Interval: cfgTypes.Duration{Duration: time.Hour}, Depth: 2}
+	sync, err := NewSynchronizer(false, m.Etherman, []EthermanInterface{m.Etherman}, m.State, m.Pool, m.EthTxManager, m.ZKEVMClient, nil, *genesis, *cfg, false)
+	require.NoError(t, err)
+	s := sync.(*ClientSynchronizer)
+
+	l1Block20 := newL1ReorgTestBlock(20, "")
+	l1Block10 := newL1ReorgTestBlock(10, "")
+	lastBlock := state.Block{BlockNumber: 20, BlockHash: l1Block20.Hash(), ParentHash: l1Block20.ParentHash()}
+	storedBlocks := []state.Block{lastBlock, {BlockNumber: 10, BlockHash: l1Block10.Hash(), ParentHash: l1Block10.ParentHash()}}
+	m.Etherman.On("EthBlockByNumber", s.ctx, uint64(20)).Return(l1Block20, nil)
+	m.Etherman.On("EthBlockByNumber", s.ctx, uint64(10)).Return(l1Block10, nil).Once()
+	m.State.On("GetLastBlocks", s.ctx, uint64(2), nil).Return(storedBlocks, nil).Once()
+
+	block, err := s.detectL1Reorg(&lastBlock)
+	require.NoError(t, err)
+	assert.Nil(t, block)
+
+	// the stored blocks are not checked again until the interval elapses
+	block, err = s.detectL1Reorg(&lastBlock)
+	require.NoError(t, err)
+	assert.Nil(t, block)
+	m.State.AssertNumberOfCalls(t, "GetLastBlocks", 1)
+}
+
+func TestGivenL1ReorgWhenRollingBackThenTrustedReorgIsOnlyStoredIfTrustedBatchesAreRemoved(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		lastBatchNumAfter    uint64
+		expectedTrustedReorg *state.TrustedReorg
+	}{
+		{
+			name:              "trusted batches kept",
+			lastBatchNumAfter: 7,
+		},
+		{
+			name:              "trusted batches removed",
+			lastBatchNumAfter: 4,
+			expectedTrustedReorg: &state.TrustedReorg{
+				BatchNumber: 5,
+				Reason:      "L1 reorg, state reset to L1 block 10, batches 4 to 5 are not virtualized anymore, trusted batches 5 to 7 removed",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			genesis, cfg, m := setupGenericTest(t)
+			m.EthTxManager = newEthTxManagerMock(t)
+			sync, err := NewSynchronizer(false, m.Etherman, []EthermanInterface{m.Etherman}, m.State, m.Pool, m.EthTxManager, m.ZKEVMClient, nil, *genesis, *cfg, false)
+			require.NoError(t, err)
+			s := sync.(*ClientSynchronizer)
+
+			m.State.On("GetLastVirtualBatchNum", s.ctx, nil).Return(uint64(5), nil).Once()
+			m.State.On("GetLastBatchNumber", s.ctx, nil).Return(uint64(7), nil).Once()
+			m.State.On("BeginStateTransaction", s.ctx).Return(m.DbTx, nil).Once()
+			m.State.On("Reset", s.ctx, uint64(10), m.DbTx).Return(nil).Once()
+			m.EthTxManager.On("Reorg", s.ctx, uint64(11), m.DbTx).Return(nil).Once()
+			m.DbTx.On("Commit", s.ctx).Return(nil).Once()
+			m.State.On("GetLastVirtualBatchNum", s.ctx, nil).Return(uint64(3), nil).Once()
+			m.State.On("AddL1Reorg", s.ctx, &state.L1Reorg{BlockNumber: 10, Reason: "L1 reorg, state reset to L1 block 10, batches 4 to 5 are not virtualized anymore"}, nil).Return(nil).Once()
+			m.State.On("GetLastBatchNumber", s.ctx, nil).Return(tc.lastBatchNumAfter, nil).Once()
+			// the sequencer only handles a trusted reorg if the trusted batches are removed
+			if tc.expectedTrustedReorg != nil {
+				m.State.On("AddTrustedReorg", s.ctx, tc.expectedTrustedReorg, nil).Return(nil).Once()
+			}
+
+			require.NoError(t, s.rollbackL1Reorg(10))
+		})
+	}
+}
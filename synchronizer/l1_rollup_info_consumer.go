@@ -240,6 +240,13 @@ func (l *l1RollupInfoConsumer) processUnsafe(rollupInfo rollupInfoByBlockRangeRe
 	} else {
 		tmpStateBlock := convertEthmanBlockToStateBlock(&blocks[len(blocks)-1])
 		lastEthBlockSynced = &tmpStateBlock
+		// The last block of the range is stored as a checkpoint when it's after the blocks with rollup info,
+		// so an interrupted sync resumes after the range instead of requesting it again
+		if lb := rollupInfo.lastBlockOfRange; lb != nil && lb.NumberU64() > tmpStateBlock.BlockNumber {
+			blocks = append(blocks[:len(blocks):len(blocks)], convertL1BlockToEthBlock(lb))
+			checkpointBlock := convertL1BlockToStateBlock(lb)
+			lastEthBlockSynced = &checkpointBlock
+		}
 		logBlocks(blocks)
 		err := l.synchronizer.processBlockRange(blocks, order)
 		if err != nil {
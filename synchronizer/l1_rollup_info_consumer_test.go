@@ -144,6 +144,35 @@ func TestGivenConsumerWhenNextBlockNumberIsNoSetThenFirstRollupInfoSetIt(t *test
 	require.Equal(t, uint64(123), resultBlock.BlockNumber)
 }
 
+func TestGivenConsumerWhenRangeHasBlocksAndLastBlockOfRangeThenStoreItAsCheckpoint(t *testing.T) {
+	ctxTimeout, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	data := setupConsumerTest(t)
+	defer cancel()
+	lastBlockOfRange := types.NewBlock(&types.Header{Number: big.NewInt(200)}, nil, nil, nil, nil)
+	responseRollupInfoByBlockRange := rollupInfoByBlockRangeResult{
+		blockRange: blockRange{
+			fromBlock: 100,
+			toBlock:   200,
+		},
+		blocks:           []etherman.Block{{BlockNumber: 123}},
+		order:            map[common.Hash][]etherman.Order{},
+		lastBlockOfRange: lastBlockOfRange,
+	}
+
+	data.ch <- *newL1SyncMessageData(&responseRollupInfoByBlockRange)
+	data.ch <- *newL1SyncMessageControl(eventProducerIsFullySynced)
+	data.syncMock.
+		On("processBlockRange", []etherman.Block{{BlockNumber: 123}, convertL1BlockToEthBlock(lastBlockOfRange)}, mock.Anything).
+		Return(nil).
+		Once()
+	err := data.sut.Start(ctxTimeout, nil)
+	require.NoError(t, err)
+	resultBlock, ok := data.sut.GetLastEthBlockSynced()
+	require.True(t, ok)
+	require.Equal(t, uint64(200), resultBlock.BlockNumber)
+	require.Equal(t, 1, len(responseRollupInfoByBlockRange.blocks))
+}
+
 func setupConsumerTest(t *testing.T) consumerTestData {
 	syncMock := newSynchronizerProcessBlockRangeMock(t)
 	ch := make(chan l1SyncMessage, 10)
@@ -37,6 +37,40 @@ const (
 
 	// ProcessTrustedBatchTimeName is the name of the label to process trusted batch.
 	ProcessTrustedBatchTimeName = Prefix + "process_trusted_batch_time"
+
+	// BatchApplyTimeName is the name of the metric with the time applying a batch to the state, by origin.
+	BatchApplyTimeName = Prefix + "batch_apply_time"
+
+	// BatchesAppliedName is the name of the metric counting the batches applied to the state, by origin.
+	BatchesAppliedName = Prefix + "batches_applied"
+
+	// DivergencesName is the name of the metric counting the divergences detected, by origin.
+	DivergencesName = Prefix + "divergences"
+
+	// L1LastBatchNumberName is the name of the metric with the last batch applied from L1.
+	L1LastBatchNumberName = Prefix + "l1_last_batch_number"
+
+	// L1HeadBatchNumberName is the name of the metric with the last batch sequenced in L1.
+	L1HeadBatchNumberName = Prefix + "l1_head_batch_number"
+
+	// TrustedLastBatchNumberName is the name of the metric with the last batch applied from the trusted sequencer.
+	TrustedLastBatchNumberName = Prefix + "trusted_last_batch_number"
+
+	// TrustedHeadBatchNumberName is the name of the metric with the last batch of the trusted sequencer.
+	TrustedHeadBatchNumberName = Prefix + "trusted_head_batch_number"
+
+	// OriginLabelName is the name of the label with the origin of the batch.
+	OriginLabelName = "origin"
+)
+
+// Origin is the feed a batch is synced from
+type Origin string
+
+const (
+	// L1Origin is the origin of the batches virtualized in L1
+	L1Origin Origin = "l1"
+	// TrustedOrigin is the origin of the batches broadcast by the trusted sequencer
+	TrustedOrigin Origin = "trusted"
 )
 
 // Register the metrics for the synchronizer package.
@@ -81,6 +115,55 @@ func Register() {
 	}
 
 	metrics.RegisterHistograms(histograms...)
+
+	histogramVecs := []metrics.HistogramVecOpts{
+		{
+			HistogramOpts: prometheus.HistogramOpts{
+				Name: BatchApplyTimeName,
+				Help: "[SYNCHRONIZER] time applying a batch to the state by origin",
+			},
+			Labels: []string{OriginLabelName},
+		},
+	}
+	metrics.RegisterHistogramVecs(histogramVecs...)
+
+	counterVecs := []metrics.CounterVecOpts{
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: BatchesAppliedName,
+				Help: "[SYNCHRONIZER] number of batches applied to the state by origin",
+			},
+			Labels: []string{OriginLabelName},
+		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: DivergencesName,
+				Help: "[SYNCHRONIZER] number of divergences between the local state and the origin",
+			},
+			Labels: []string{OriginLabelName},
+		},
+	}
+	metrics.RegisterCounterVecs(counterVecs...)
+
+	gauges := []prometheus.GaugeOpts{
+		{
+			Name: L1LastBatchNumberName,
+			Help: "[SYNCHRONIZER] last batch applied from L1",
+		},
+		{
+			Name: L1HeadBatchNumberName,
+			Help: "[SYNCHRONIZER] last batch sequenced in L1",
+		},
+		{
+			Name: TrustedLastBatchNumberName,
+			Help: "[SYNCHRONIZER] last batch applied from the trusted sequencer",
+		},
+		{
+			Name: TrustedHeadBatchNumberName,
+			Help: "[SYNCHRONIZER] last batch of the trusted sequencer",
+		},
+	}
+	metrics.RegisterGauges(gauges...)
 }
 
 // InitializationTime observes the time initializing the synchronizer on the histogram.
@@ -136,3 +219,30 @@ func ProcessTrustedBatchTime(lastProcessTime time.Duration) {
 	execTimeInSeconds := float64(lastProcessTime) / float64(time.Second)
 	metrics.HistogramObserve(ProcessTrustedBatchTimeName, execTimeInSeconds)
 }
+
+// BatchApplied observes the time applying a batch from the origin and sets it as the last batch applied from it.
+func BatchApplied(origin Origin, batchNumber uint64, applyTime time.Duration) {
+	execTimeInSeconds := float64(applyTime) / float64(time.Second)
+	metrics.HistogramVecObserve(BatchApplyTimeName, string(origin), execTimeInSeconds)
+	metrics.CounterVecInc(BatchesAppliedName, string(origin))
+	if origin == L1Origin {
+		metrics.GaugeSet(L1LastBatchNumberName, float64(batchNumber))
+	} else {
+		metrics.GaugeSet(TrustedLastBatchNumberName, float64(batchNumber))
+	}
+}
+
+// HeadBatchNumber sets the last batch available in the origin, the gap with the last batch applied from
+// the origin is the lag of its feed.
+func HeadBatchNumber(origin Origin, batchNumber uint64) {
+	if origin == L1Origin {
+		metrics.GaugeSet(L1HeadBatchNumberName, float64(batchNumber))
+	} else {
+		metrics.GaugeSet(TrustedHeadBatchNumberName, float64(batchNumber))
+	}
+}
+
+// Divergence increases the divergences detected between the local state and the origin.
+func Divergence(origin Origin) {
+	metrics.CounterVecInc(DivergencesName, string(origin))
+}
@@ -10,6 +10,8 @@ import (
 
 	etherman "github.com/0xPolygonHermez/zkevm-node/etherman"
 
+	ethermantypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
+
 	mock "github.com/stretchr/testify/mock"
 
 	types "github.com/ethereum/go-ethereum/core/types"
@@ -20,6 +22,41 @@ type ethermanMock struct {
 	mock.Mock
 }
 
+// DecodeEvents provides a mock function with given fields: ctx, logs
+func (_m *ethermanMock) DecodeEvents(ctx context.Context, logs []types.Log) ([]etherman.Block, map[common.Hash][]etherman.Order, error) {
+	ret := _m.Called(ctx, logs)
+
+	var r0 []etherman.Block
+	var r1 map[common.Hash][]etherman.Order
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []types.Log) ([]etherman.Block, map[common.Hash][]etherman.Order, error)); ok {
+		return rf(ctx, logs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []types.Log) []etherman.Block); ok {
+		r0 = rf(ctx, logs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]etherman.Block)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []types.Log) map[common.Hash][]etherman.Order); ok {
+		r1 = rf(ctx, logs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(map[common.Hash][]etherman.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []types.Log) error); ok {
+		r2 = rf(ctx, logs)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // EthBlockByNumber provides a mock function with given fields: ctx, blockNumber
 func (_m *ethermanMock) EthBlockByNumber(ctx context.Context, blockNumber uint64) (*types.Block, error) {
 	ret := _m.Called(ctx, blockNumber)
@@ -129,6 +166,30 @@ func (_m *ethermanMock) GetRollupInfoByBlockRange(ctx context.Context, fromBlock
 	return r0, r1, r2
 }
 
+// GetSequencedBatchesInfo provides a mock function with given fields: ctx, lastBatchNumber
+func (_m *ethermanMock) GetSequencedBatchesInfo(ctx context.Context, lastBatchNumber uint64) (ethermantypes.SequencedBatchesInfo, error) {
+	ret := _m.Called(ctx, lastBatchNumber)
+
+	var r0 ethermantypes.SequencedBatchesInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (ethermantypes.SequencedBatchesInfo, error)); ok {
+		return rf(ctx, lastBatchNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ethermantypes.SequencedBatchesInfo); ok {
+		r0 = rf(ctx, lastBatchNumber)
+	} else {
+		r0 = ret.Get(0).(ethermantypes.SequencedBatchesInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, lastBatchNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTrustedSequencerURL provides a mock function with given fields:
 func (_m *ethermanMock) GetTrustedSequencerURL() (string, error) {
 	ret := _m.Called()
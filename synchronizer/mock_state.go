@@ -95,6 +95,34 @@ func (_m *stateMock) AddGlobalExitRoot(ctx context.Context, exitRoot *state.Glob
 	return r0
 }
 
+// AddL1Logs provides a mock function with given fields: ctx, logs, dbTx
+func (_m *stateMock) AddL1Logs(ctx context.Context, logs []types.Log, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, logs, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []types.Log, pgx.Tx) error); ok {
+		r0 = rf(ctx, logs, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddL1Reorg provides a mock function with given fields: ctx, reorg, dbTx
+func (_m *stateMock) AddL1Reorg(ctx context.Context, reorg *state.L1Reorg, dbTx pgx.Tx) error {
+	ret := _m.Called(ctx, reorg, dbTx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *state.L1Reorg, pgx.Tx) error); ok {
+		r0 = rf(ctx, reorg, dbTx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddSequence provides a mock function with given fields: ctx, sequence, dbTx
 func (_m *stateMock) AddSequence(ctx context.Context, sequence state.Sequence, dbTx pgx.Tx) error {
 	ret := _m.Called(ctx, sequence, dbTx)
@@ -191,6 +219,39 @@ func (_m *stateMock) CloseBatch(ctx context.Context, receipt state.ProcessingRec
 	return r0
 }
 
+// CompareAccInputHashes provides a mock function with given fields: ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx
+func (_m *stateMock) CompareAccInputHashes(ctx context.Context, fromBatchNumber uint64, toBatchNumber uint64, l1AccInputHash func(context.Context, uint64) (common.Hash, error), dbTx pgx.Tx) (uint64, *state.AccInputHashDivergence, error) {
+	ret := _m.Called(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+
+	var r0 uint64
+	var r1 *state.AccInputHashDivergence
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, func(context.Context, uint64) (common.Hash, error), pgx.Tx) (uint64, *state.AccInputHashDivergence, error)); ok {
+		return rf(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, func(context.Context, uint64) (common.Hash, error), pgx.Tx) uint64); ok {
+		r0 = rf(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, func(context.Context, uint64) (common.Hash, error), pgx.Tx) *state.AccInputHashDivergence); ok {
+		r1 = rf(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*state.AccInputHashDivergence)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint64, uint64, func(context.Context, uint64) (common.Hash, error), pgx.Tx) error); ok {
+		r2 = rf(ctx, fromBatchNumber, toBatchNumber, l1AccInputHash, dbTx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // ExecuteBatch provides a mock function with given fields: ctx, batch, updateMerkleTree, dbTx
 func (_m *stateMock) ExecuteBatch(ctx context.Context, batch state.Batch, updateMerkleTree bool, dbTx pgx.Tx) (*executor.ProcessBatchResponse, error) {
 	ret := _m.Called(ctx, batch, updateMerkleTree, dbTx)
@@ -283,6 +344,32 @@ func (_m *stateMock) GetForkIDs(ctx context.Context, dbTx pgx.Tx) ([]state.ForkI
 	return r0, r1
 }
 
+// GetL1Logs provides a mock function with given fields: ctx, fromBlockNumber, dbTx
+func (_m *stateMock) GetL1Logs(ctx context.Context, fromBlockNumber uint64, dbTx pgx.Tx) ([]types.Log, error) {
+	ret := _m.Called(ctx, fromBlockNumber, dbTx)
+
+	var r0 []types.Log
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]types.Log, error)); ok {
+		return rf(ctx, fromBlockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []types.Log); ok {
+		r0 = rf(ctx, fromBlockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Log)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, fromBlockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastBatchNumber provides a mock function with given fields: ctx, dbTx
 func (_m *stateMock) GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -333,6 +420,32 @@ func (_m *stateMock) GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Bloc
 	return r0, r1
 }
 
+// GetLastBlocks provides a mock function with given fields: ctx, limit, dbTx
+func (_m *stateMock) GetLastBlocks(ctx context.Context, limit uint64, dbTx pgx.Tx) ([]state.Block, error) {
+	ret := _m.Called(ctx, limit, dbTx)
+
+	var r0 []state.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]state.Block, error)); ok {
+		return rf(ctx, limit, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []state.Block); ok {
+		r0 = rf(ctx, limit, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.Block)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, limit, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastVerifiedBatch provides a mock function with given fields: ctx, dbTx
 func (_m *stateMock) GetLastVerifiedBatch(ctx context.Context, dbTx pgx.Tx) (*state.VerifiedBatch, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -27,10 +27,18 @@ const (
 	forkID5 = 5
 )
 
+var (
+	// ErrReplayFromGenesis is returned when replaying the L1 events from the block 0
+	ErrReplayFromGenesis = errors.New("L1 events can't be replayed from the block 0")
+	// ErrNoL1LogsToReplay is returned when there are no archived L1 logs to replay
+	ErrNoL1LogsToReplay = errors.New("there are no archived L1 logs to replay")
+)
+
 // Synchronizer connects L1 and L2
 type Synchronizer interface {
 	Sync() error
 	Stop()
+	ReplayL1Events(fromBlockNumber uint64) error
 }
 
 // ClientSynchronizer connects L1 and L2
@@ -62,6 +70,14 @@ type ClientSynchronizer struct {
 	// Previous value returned by state.GetStoredFlushID, is used for decide if write a log or not
 	previousExecutorFlushID uint64
 	l1SyncOrchestration     *l1SyncOrchestration
+	// time of the last verification of the stored L1 blocks
+	lastL1ReorgCheck time.Time
+	// time of the last comparison of the accumulated input hashes with L1
+	lastAccInputHashCheck time.Time
+	// last batch whose accumulated input hash has been compared with L1
+	accInputHashCheckedUntil uint64
+	// true once a divergence of the accumulated input hashes has been alerted
+	accInputHashDiverged bool
 }
 
 // NewSynchronizer creates and initializes an instance of Synchronizer
@@ -270,6 +286,7 @@ func (s *ClientSynchronizer) Sync() error {
 				log.Warn("error getting latest sequenced batch in the rollup. Error: ", err)
 				continue
 			}
+			metrics.HeadBatchNumber(metrics.L1Origin, latestSequencedBatchNumber)
 			latestSyncedBatch, err := s.state.GetLastBatchNumber(s.ctx, nil)
 			if err != nil {
 				log.Warn("error getting latest batch synced in the db. Error: ", err)
@@ -288,7 +305,7 @@ func (s *ClientSynchronizer) Sync() error {
 			}
 			log.Infof("latestSequencedBatchNumber: %d, latestSyncedBatch: %d, lastVerifiedBatchNumber: %d", latestSequencedBatchNumber, latestSyncedBatch, lastVerifiedBatchNumber)
 			// Sync trusted state
-			if latestSyncedBatch >= latestSequencedBatchNumber {
+			if s.cfg.TrustedSync.Enabled && latestSyncedBatch >= latestSequencedBatchNumber {
 				startTrusted := time.Now()
 				log.Info("Syncing trusted state")
 				err = s.syncTrustedState(latestSyncedBatch)
@@ -331,6 +348,7 @@ func (s *ClientSynchronizer) Sync() error {
 					continue
 				}
 			}
+			s.checkAccInputHashes()
 			metrics.FullSyncIterationTime(time.Since(start))
 			log.Info("L1 state fully synchronized")
 		}
@@ -341,14 +359,14 @@ func (s *ClientSynchronizer) Sync() error {
 // lastEthBlockSynced -> last block synced in the db
 func (s *ClientSynchronizer) syncBlocksParallel(lastEthBlockSynced *state.Block) (*state.Block, error) {
 	// This function will read events fromBlockNum to latestEthBlock. Check reorg to be sure that everything is ok.
-	block, err := s.checkReorg(lastEthBlockSynced)
+	block, err := s.detectL1Reorg(lastEthBlockSynced)
 	if err != nil {
 		log.Errorf("error checking reorgs. Retrying... Err: %v", err)
 		return lastEthBlockSynced, fmt.Errorf("error checking reorgs")
 	}
 	if block != nil {
 		log.Infof("reorg detected. Resetting the state from block %v to block %v", lastEthBlockSynced.BlockNumber, block.BlockNumber)
-		err = s.resetState(block.BlockNumber)
+		err = s.rollbackL1Reorg(block.BlockNumber)
 		if err != nil {
 			log.Errorf("error resetting the state to a previous block. Retrying... Err: %v", err)
 			s.l1SyncOrchestration.reset(lastEthBlockSynced.BlockNumber)
@@ -367,13 +385,13 @@ func (s *ClientSynchronizer) syncBlocksParallel(lastEthBlockSynced *state.Block)
 // This function syncs the node from a specific block to the latest
 func (s *ClientSynchronizer) syncBlocksSequential(lastEthBlockSynced *state.Block) (*state.Block, error) {
 	// This function will read events fromBlockNum to latestEthBlock. Check reorg to be sure that everything is ok.
-	block, err := s.checkReorg(lastEthBlockSynced)
+	block, err := s.detectL1Reorg(lastEthBlockSynced)
 	if err != nil {
 		log.Errorf("error checking reorgs. Retrying... Err: %v", err)
 		return lastEthBlockSynced, fmt.Errorf("error checking reorgs")
 	}
 	if block != nil {
-		err = s.resetState(block.BlockNumber)
+		err = s.rollbackL1Reorg(block.BlockNumber)
 		if err != nil {
 			log.Errorf("error resetting the state to a previous block. Retrying... Err: %v", err)
 			return lastEthBlockSynced, fmt.Errorf("error resetting the state to a previous block")
@@ -477,6 +495,7 @@ func (s *ClientSynchronizer) syncTrustedState(latestSyncedBatch uint64) error {
 		log.Warn("syncTrustedState: error syncing trusted state. Error: ", err)
 		return err
 	}
+	metrics.HeadBatchNumber(metrics.TrustedOrigin, lastTrustedStateBatchNumber)
 
 	log.Debug("syncTrustedState: lastTrustedStateBatchNumber ", lastTrustedStateBatchNumber)
 	log.Debug("syncTrustedState: latestSyncedBatch ", latestSyncedBatch)
@@ -566,6 +585,18 @@ func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order ma
 			}
 			return err
 		}
+		if s.cfg.ArchiveL1Logs && len(blocks[i].Logs) > 0 {
+			err = s.state.AddL1Logs(s.ctx, blocks[i].Logs, dbTx)
+			if err != nil {
+				log.Errorf("error archiving L1 logs. BlockNumber: %d, error: %v", blocks[i].BlockNumber, err)
+				rollbackErr := dbTx.Rollback(s.ctx)
+				if rollbackErr != nil {
+					log.Errorf("error rolling back state to archive L1 logs. BlockNumber: %d, rollbackErr: %s, error : %v", blocks[i].BlockNumber, rollbackErr.Error(), err)
+					return rollbackErr
+				}
+				return err
+			}
+		}
 		for _, element := range order[blocks[i].BlockHash] {
 			switch element.Name {
 			case etherman.SequenceBatchesOrder:
@@ -625,6 +656,39 @@ func (s *ClientSynchronizer) processBlockRange(blocks []etherman.Block, order ma
 	return nil
 }
 
+// ReplayL1Events reprocesses the archived L1 logs from the provided L1 block number
+// onwards with the current decoding logic. The logs are decoded before touching the
+// state, then the state is reset to the previous block and the decoded blocks are
+// processed again as if they were just read from L1
+func (s *ClientSynchronizer) ReplayL1Events(fromBlockNumber uint64) error {
+	if fromBlockNumber == 0 {
+		return ErrReplayFromGenesis
+	}
+	logs, err := s.state.GetL1Logs(s.ctx, fromBlockNumber, nil)
+	if err != nil {
+		log.Errorf("error getting the archived L1 logs from block %d. Error: %v", fromBlockNumber, err)
+		return err
+	}
+	if len(logs) == 0 {
+		return ErrNoL1LogsToReplay
+	}
+	blocks, order, err := s.etherMan.DecodeEvents(s.ctx, logs)
+	if err != nil {
+		log.Errorf("error decoding the archived L1 logs from block %d. Error: %v", fromBlockNumber, err)
+		return err
+	}
+	log.Infof("replaying %d archived L1 logs decoded into %d blocks from block %d", len(logs), len(blocks), fromBlockNumber)
+
+	err = s.resetState(fromBlockNumber - 1)
+	if err != nil {
+		log.Errorf("error resetting the state to block %d. Error: %v", fromBlockNumber-1, err)
+		return err
+	}
+	// the reset removes the archived logs of the replayed blocks, so they are archived again
+	s.cfg.ArchiveL1Logs = true
+	return s.processBlockRange(blocks, order)
+}
+
 // This function allows reset the state until an specific ethereum block
 func (s *ClientSynchronizer) resetState(blockNumber uint64) error {
 	log.Info("Reverting synchronization to block: ", blockNumber)
@@ -774,9 +838,10 @@ func (s *ClientSynchronizer) checkTrustedState(batch state.Batch, tBatch *state.
 		if tBatch.StateRoot == (common.Hash{}) {
 			log.Warnf("incomplete trusted batch %d detected. Syncing full batch from L1", tBatch.BatchNumber)
 		} else {
-			log.Warnf("missmatch in trusted state detected for Batch Number: %d. Reasons: %s", tBatch.BatchNumber, reason)
+			log.WithFields("origin", metrics.L1Origin).Warnf("missmatch in trusted state detected for Batch Number: %d. Reasons: %s", tBatch.BatchNumber, reason)
+			metrics.Divergence(metrics.L1Origin)
 		}
-		if s.isTrustedSequencer {
+		if s.isTrustedSequencer || s.cfg.TrustedSync.HaltOnMismatch {
 			s.halt(s.ctx, fmt.Errorf("TRUSTED REORG DETECTED! Batch: %d", batch.BatchNumber))
 		}
 		// Store trusted reorg register
@@ -879,6 +944,7 @@ func (s *ClientSynchronizer) processSequenceBatches(sequencedBatches []etherman.
 		return nil
 	}
 	for _, sbatch := range sequencedBatches {
+		applyStart := time.Now()
 		virtualBatch := state.VirtualBatch{
 			BatchNumber:   sbatch.BatchNumber,
 			TxHash:        sbatch.TxHash,
@@ -1072,6 +1138,7 @@ func (s *ClientSynchronizer) processSequenceBatches(sequencedBatches []etherman.
 			log.Errorf("error storing virtualBatch. BatchNumber: %d, BlockNumber: %d, error: %v", virtualBatch.BatchNumber, blockNumber, err)
 			return err
 		}
+		batchApplied(metrics.L1Origin, virtualBatch.BatchNumber, applyStart)
 	}
 	// Insert the sequence to allow the aggregator verify the sequence batches
 	seq := state.Sequence{
@@ -1338,6 +1405,7 @@ func (s *ClientSynchronizer) processTrustedVerifyBatches(lastVerifiedBatch ether
 }
 
 func (s *ClientSynchronizer) processTrustedBatch(trustedBatch *types.Batch, dbTx pgx.Tx) ([]*state.Batch, *common.Hash, error) {
+	applyStart := time.Now()
 	log.Debugf("Processing trusted batch: %d", uint64(trustedBatch.Number))
 	trustedBatchL2Data := trustedBatch.BatchL2Data
 	batches := s.trustedState.lastTrustedBatches
@@ -1438,7 +1506,8 @@ func (s *ClientSynchronizer) processTrustedBatch(trustedBatch *types.Batch, dbTx
 						for _, tx := range syncedTxs {
 							log.Error("synced txHash : ", tx.Hash())
 						}
-						log.Errorf("batch: %d, stateRoot calculated (%s) is different from the stateRoot (%s) received during the trustedState synchronization", uint64(trustedBatch.Number), *s.trustedState.lastStateRoot, trustedBatch.StateRoot)
+						log.WithFields("origin", metrics.TrustedOrigin).Errorf("batch: %d, stateRoot calculated (%s) is different from the stateRoot (%s) received during the trustedState synchronization", uint64(trustedBatch.Number), *s.trustedState.lastStateRoot, trustedBatch.StateRoot)
+						metrics.Divergence(metrics.TrustedOrigin)
 						return nil, nil, fmt.Errorf("batch: %d, stateRoot calculated (%s) is different from the stateRoot (%s) received during the trustedState synchronization", uint64(trustedBatch.Number), *s.trustedState.lastStateRoot, trustedBatch.StateRoot)
 					}
 					receipt := state.ProcessingReceipt{
@@ -1485,6 +1554,7 @@ func (s *ClientSynchronizer) processTrustedBatch(trustedBatch *types.Batch, dbTx
 					batches[0].StateRoot = trustedBatch.StateRoot
 					batches[0].LocalExitRoot = trustedBatch.LocalExitRoot
 				}
+				batchApplied(metrics.TrustedOrigin, uint64(trustedBatch.Number), applyStart)
 				return batches, &trustedBatch.StateRoot, nil
 			}
 		}
@@ -1522,7 +1592,8 @@ func (s *ClientSynchronizer) processTrustedBatch(trustedBatch *types.Batch, dbTx
 		if trustedBatch.StateRoot != processBatchResp.NewStateRoot {
 			log.Error("trustedBatchL2Data: ", trustedBatchL2Data)
 			log.Error("request.Transactions: ", request.Transactions)
-			log.Errorf("batch: %d after processing some txs, stateRoot calculated (%s) is different from the stateRoot (%s) received during the trustedState synchronization", uint64(trustedBatch.Number), processBatchResp.NewStateRoot.String(), trustedBatch.StateRoot.String())
+			log.WithFields("origin", metrics.TrustedOrigin).Errorf("batch: %d after processing some txs, stateRoot calculated (%s) is different from the stateRoot (%s) received during the trustedState synchronization", uint64(trustedBatch.Number), processBatchResp.NewStateRoot.String(), trustedBatch.StateRoot.String())
+			metrics.Divergence(metrics.TrustedOrigin)
 			return nil, nil, fmt.Errorf("batch: %d, stateRoot calculated (%s) is different from the stateRoot (%s) received during the trustedState synchronization", uint64(trustedBatch.Number), processBatchResp.NewStateRoot.String(), trustedBatch.StateRoot.String())
 		}
 		receipt := state.ProcessingReceipt{
@@ -1554,9 +1625,17 @@ func (s *ClientSynchronizer) processTrustedBatch(trustedBatch *types.Batch, dbTx
 	}
 
 	log.Infof("Batch %d synchronized", uint64(trustedBatch.Number))
+	batchApplied(metrics.TrustedOrigin, uint64(trustedBatch.Number), applyStart)
 	return batches, &processBatchResp.NewStateRoot, nil
 }
 
+// batchApplied records the apply time of a batch synchronized from the origin and logs it with the origin
+func batchApplied(origin metrics.Origin, batchNumber uint64, start time.Time) {
+	applyTime := time.Since(start)
+	metrics.BatchApplied(origin, batchNumber, applyTime)
+	log.WithFields("origin", origin).Debugf("batch %d applied in %s", batchNumber, applyTime)
+}
+
 func (s *ClientSynchronizer) reorgPool(dbTx pgx.Tx) error {
 	latestBatchNum, err := s.etherMan.GetLatestBatchNumber()
 	if err != nil {
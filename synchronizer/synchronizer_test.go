@@ -94,6 +94,7 @@ func TestForcedBatch(t *testing.T) {
 	cfg := Config{
 		SyncInterval:                        cfgTypes.Duration{Duration: 1 * time.Second},
 		SyncChunkSize:                       10,
+		TrustedSync:                         TrustedSyncConfig{Enabled: true},
 		UseParallelModeForL1Synchronization: false,
 		L1ParallelSynchronization: L1ParallelSynchronizationConfig{
 			NumberOfParallelOfEthereumClients:   1,
@@ -345,6 +346,7 @@ func TestSequenceForcedBatch(t *testing.T) {
 	cfg := Config{
 		SyncInterval:                        cfgTypes.Duration{Duration: 1 * time.Second},
 		SyncChunkSize:                       10,
+		TrustedSync:                         TrustedSyncConfig{Enabled: true},
 		UseParallelModeForL1Synchronization: false,
 		L1ParallelSynchronization: L1ParallelSynchronizationConfig{
 			NumberOfParallelOfEthereumClients:   1,
@@ -581,6 +583,7 @@ func setupGenericTest(t *testing.T) (*state.Genesis, *Config, *mocks) {
 	cfg := Config{
 		SyncInterval:  cfgTypes.Duration{Duration: 1 * time.Second},
 		SyncChunkSize: 10,
+		TrustedSync:   TrustedSyncConfig{Enabled: true},
 	}
 
 	m := mocks{
@@ -0,0 +1,239 @@
+// Package reorgsim provides a harness to drive synthetic L1 reorgs and trusted
+// state rewrites against a node whose synchronizer uses the simulated etherman,
+// so operators and CI can validate their recovery runbooks programmatically.
+package reorgsim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	ethmanTypes "github.com/0xPolygonHermez/zkevm-node/etherman/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// simulatedChainID is the chain ID required by the simulated etherman
+	simulatedChainID = 1337
+
+	defaultPollInterval = 100 * time.Millisecond
+	// reorgTimeShift is added to the time of the first block mined by a reorg
+	reorgTimeShift = time.Second
+)
+
+var (
+	// ErrStateNotConfigured is returned when an operation needs the state of the node
+	// but the harness has been created without it
+	ErrStateNotConfigured = errors.New("state is not configured in the harness")
+	// ErrInvalidReorgDepth is returned when the reorg depth is zero or deeper than the chain
+	ErrInvalidReorgDepth = errors.New("invalid reorg depth")
+	// ErrNotConsistent is returned when the L1 blocks stored in the state don't belong to the canonical chain
+	ErrNotConsistent = errors.New("state is not consistent with the L1 canonical chain")
+)
+
+// stateInterface contains the methods of the state required by the harness
+type stateInterface interface {
+	BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
+	GetLastBlock(ctx context.Context, dbTx pgx.Tx) (*state.Block, error)
+	GetPreviousBlock(ctx context.Context, offset uint64, dbTx pgx.Tx) (*state.Block, error)
+	UpdateBatchL2Data(ctx context.Context, batchNumber uint64, batchL2Data []byte, dbTx pgx.Tx) error
+}
+
+// Harness drives synthetic L1 reorgs and trusted state rewrites against the
+// synchronizer of a node that uses the simulated etherman
+type Harness struct {
+	// Etherman is the simulated etherman to be used by the synchronizer under test
+	Etherman *etherman.Client
+	// Backend is the simulated L1 blockchain
+	Backend *backends.SimulatedBackend
+	// Auth is the account that deployed the contracts, it's the trusted sequencer and aggregator
+	Auth *bind.TransactOpts
+
+	state        stateInterface
+	pollInterval time.Duration
+}
+
+// New creates a harness with a fresh simulated L1 where the rollup contracts are
+// deployed. The state is optional, it's only required for the operations that check
+// or rewrite the state of the node
+func New(cfg etherman.Config, st stateInterface) (*Harness, error) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(simulatedChainID))
+	if err != nil {
+		return nil, err
+	}
+	ethman, backend, _, _, err := etherman.NewSimulatedEtherman(cfg, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Harness{
+		Etherman:     ethman,
+		Backend:      backend,
+		Auth:         auth,
+		state:        st,
+		pollInterval: defaultPollInterval,
+	}, nil
+}
+
+// Head returns the number and the hash of the current L1 canonical head
+func (h *Harness) Head(ctx context.Context) (uint64, common.Hash, error) {
+	header, err := h.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+	return header.Number.Uint64(), header.Hash(), nil
+}
+
+// MineBlocks mines the provided number of L1 blocks including the pending txs in the first one
+func (h *Harness) MineBlocks(n int) {
+	for i := 0; i < n; i++ {
+		h.Backend.Commit()
+	}
+}
+
+// SequenceBatches sends the sequences to the rollup contract and mines the L1 block
+// that includes them, it returns the hash of the L1 tx
+func (h *Harness) SequenceBatches(ctx context.Context, sequences []ethmanTypes.Sequence) (common.Hash, error) {
+	tx, err := h.Etherman.EstimateGasSequenceBatches(h.Auth.From, sequences, h.Auth.From)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to build sequence batches tx: %w", err)
+	}
+	if err := h.Backend.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send sequence batches tx: %w", err)
+	}
+	h.Backend.Commit()
+
+	return tx.Hash(), nil
+}
+
+// ReorgL1 discards the last depth L1 blocks and mines newBlocks on top of the common
+// ancestor. To make the new chain canonical, newBlocks must be greater than depth, the
+// txs included in the discarded blocks are not replayed. The time of the first new block
+// is shifted, so the new blocks never have the hashes of the discarded ones even if they
+// are empty. It returns the new head hash
+func (h *Harness) ReorgL1(ctx context.Context, depth uint64, newBlocks int) (common.Hash, error) {
+	headNumber, _, err := h.Head(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if depth == 0 || depth > headNumber {
+		return common.Hash{}, fmt.Errorf("%w: %d, head %d", ErrInvalidReorgDepth, depth, headNumber)
+	}
+	if uint64(newBlocks) <= depth {
+		return common.Hash{}, fmt.Errorf("%w: %d new blocks can't replace %d blocks", ErrInvalidReorgDepth, newBlocks, depth)
+	}
+
+	ancestor, err := h.Backend.HeaderByNumber(ctx, new(big.Int).SetUint64(headNumber-depth))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := h.Backend.Fork(ctx, ancestor.Hash()); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to fork L1 at block %d: %w", ancestor.Number.Uint64(), err)
+	}
+	if err := h.Backend.AdjustTime(reorgTimeShift); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to shift the time of the new L1 blocks: %w", err)
+	}
+	h.MineBlocks(newBlocks)
+
+	_, newHead, err := h.Head(ctx)
+	return newHead, err
+}
+
+// RewriteTrustedBatch replaces the L2 data of a trusted batch stored in the state of
+// the node, simulating a trusted state that differs from the one later sequenced in L1
+func (h *Harness) RewriteTrustedBatch(ctx context.Context, batchNumber uint64, batchL2Data []byte) error {
+	if h.state == nil {
+		return ErrStateNotConfigured
+	}
+
+	dbTx, err := h.state.BeginStateTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	if err := h.state.UpdateBatchL2Data(ctx, batchNumber, batchL2Data, dbTx); err != nil {
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			return fmt.Errorf("failed to rollback dbTx: %s, error: %w", rollbackErr.Error(), err)
+		}
+		return err
+	}
+	return dbTx.Commit(ctx)
+}
+
+// CheckConsistency checks that the last depth L1 blocks stored in the state
+// by the synchronizer belong to the L1 canonical chain
+func (h *Harness) CheckConsistency(ctx context.Context, depth uint64) error {
+	if h.state == nil {
+		return ErrStateNotConfigured
+	}
+
+	for offset := uint64(0); offset < depth; offset++ {
+		block, err := h.state.GetPreviousBlock(ctx, offset, nil)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		header, err := h.Backend.HeaderByNumber(ctx, new(big.Int).SetUint64(block.BlockNumber))
+		if err != nil {
+			return err
+		}
+		if header.Hash() != block.BlockHash {
+			return fmt.Errorf("%w: block %d stored with hash %s, canonical hash %s",
+				ErrNotConsistent, block.BlockNumber, block.BlockHash.String(), header.Hash().String())
+		}
+	}
+
+	return nil
+}
+
+// WaitForSync waits until the synchronizer has stored the current L1 head in the
+// state and the last depth stored blocks belong to the canonical chain
+func (h *Harness) WaitForSync(ctx context.Context, depth uint64, timeout time.Duration) error {
+	if h.state == nil {
+		return ErrStateNotConfigured
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		_, headHash, err := h.Head(ctx)
+		if err != nil {
+			return err
+		}
+		lastBlock, err := h.state.GetLastBlock(ctx, nil)
+		if err == nil && lastBlock.BlockHash == headHash {
+			lastErr = h.CheckConsistency(ctx, depth)
+			if lastErr == nil {
+				return nil
+			}
+		} else if err != nil && !errors.Is(err, state.ErrNotFound) {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("timeout waiting for the synchronizer, last error: %w", lastErr)
+			}
+			return fmt.Errorf("timeout waiting for the synchronizer to reach L1 head %s", headHash.String())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package reorgsim
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/etherman"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReorgL1(t *testing.T) {
+	ctx := context.Background()
+	h, err := New(etherman.Config{ForkIDChunkSize: 10}, nil) //nolint:gomnd
+	require.NoError(t, err)
+
+	h.MineBlocks(5)
+	headNumber, headHash, err := h.Head(ctx)
+	require.NoError(t, err)
+	firstReorgedNumber := new(big.Int).SetUint64(headNumber - 2)
+	firstReorged, err := h.Backend.HeaderByNumber(ctx, firstReorgedNumber)
+	require.NoError(t, err)
+
+	_, err = h.ReorgL1(ctx, 0, 1)
+	require.ErrorIs(t, err, ErrInvalidReorgDepth)
+	_, err = h.ReorgL1(ctx, 3, 3)
+	require.ErrorIs(t, err, ErrInvalidReorgDepth)
+
+	newHeadHash, err := h.ReorgL1(ctx, 3, 4)
+	require.NoError(t, err)
+	newHeadNumber, currentHeadHash, err := h.Head(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, newHeadHash, currentHeadHash)
+	assert.NotEqual(t, headHash, newHeadHash)
+	assert.Equal(t, headNumber+1, newHeadNumber)
+	// the empty block replacing the first reorged one has a different hash
+	firstNew, err := h.Backend.HeaderByNumber(ctx, firstReorgedNumber)
+	require.NoError(t, err)
+	assert.NotEqual(t, firstReorged.Hash(), firstNew.Hash())
+
+	err = h.CheckConsistency(ctx, 1)
+	assert.ErrorIs(t, err, ErrStateNotConfigured)
+}
@@ -0,0 +1,21 @@
+package testchain
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/metrics"
+	"github.com/jackc/pgx/v4"
+)
+
+// stateInterface gathers the methods of the node state required to build a chain.
+type stateInterface interface {
+	BeginStateTransaction(ctx context.Context) (pgx.Tx, error)
+	SetGenesis(ctx context.Context, block state.Block, genesis state.Genesis, dbTx pgx.Tx) ([]byte, error)
+	AddForkIDInterval(ctx context.Context, newForkID state.ForkIDInterval, dbTx pgx.Tx) error
+	GetLastBatchNumber(ctx context.Context, dbTx pgx.Tx) (uint64, error)
+	OpenBatch(ctx context.Context, processingContext state.ProcessingContext, dbTx pgx.Tx) error
+	ProcessSequencerBatch(ctx context.Context, batchNumber uint64, batchL2Data []byte, caller metrics.CallerLabel, dbTx pgx.Tx) (*state.ProcessBatchResponse, error)
+	StoreTransactions(ctx context.Context, batchNumber uint64, processedTxs []*state.ProcessTransactionResponse, txsEGPLog []*state.EffectiveGasPriceLog, dbTx pgx.Tx) error
+	CloseBatch(ctx context.Context, receipt state.ProcessingReceipt, dbTx pgx.Tx) error
+}
@@ -0,0 +1,43 @@
+package testchain
+
+import "github.com/0xPolygonHermez/zkevm-node/state"
+
+// CounterProfile returns the zk counters recorded as used by a batch given the counters
+// actually used by the executor to process it, so the batches can emulate any load
+// profile regardless of the txs they include
+type CounterProfile func(used state.ZKCounters) state.ZKCounters
+
+// ExecutorProfile records the counters used by the executor
+func ExecutorProfile(used state.ZKCounters) state.ZKCounters {
+	return used
+}
+
+// FixedProfile records the given counters
+func FixedProfile(counters state.ZKCounters) CounterProfile {
+	return func(state.ZKCounters) state.ZKCounters {
+		return counters
+	}
+}
+
+// NearLimitProfile records the given percentage of the batch constraints, e.g. 95 emulates
+// batches closed right before running out of counters
+func NearLimitProfile(constraints state.BatchConstraintsCfg, percentage uint64) CounterProfile {
+	scale := func(max uint64) uint64 {
+		return max * percentage / 100 //nolint:gomnd
+	}
+	scale32 := func(max uint32) uint32 {
+		return uint32(scale(uint64(max)))
+	}
+	return func(state.ZKCounters) state.ZKCounters {
+		return state.ZKCounters{
+			CumulativeGasUsed:    scale(constraints.MaxCumulativeGasUsed),
+			UsedKeccakHashes:     scale32(constraints.MaxKeccakHashes),
+			UsedPoseidonHashes:   scale32(constraints.MaxPoseidonHashes),
+			UsedPoseidonPaddings: scale32(constraints.MaxPoseidonPaddings),
+			UsedMemAligns:        scale32(constraints.MaxMemAligns),
+			UsedArithmetics:      scale32(constraints.MaxArithmetics),
+			UsedBinaries:         scale32(constraints.MaxBinaries),
+			UsedSteps:            scale32(constraints.MaxSteps),
+		}
+	}
+}
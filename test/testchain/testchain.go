@@ -0,0 +1,409 @@
+// Package testchain builds deterministic L2 chains against the state of a node, so the
+// components reading the state can be tested with realistic chains: the accounts and
+// contracts are derived from a seed, the batches are processed by the executor and their
+// timestamps and zk counters are chosen by the test.
+package testchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	transferGas = 21000
+)
+
+var (
+	// ErrUnknownName is returned when there is no account or contract with the given name
+	ErrUnknownName = errors.New("unknown account or contract name")
+	// ErrDuplicatedName is returned when an account or contract with the same name already exists
+	ErrDuplicatedName = errors.New("duplicated account or contract name")
+	// ErrTxNotProcessed is returned when a tx of a batch doesn't change the state root
+	ErrTxNotProcessed = errors.New("tx not processed by the executor")
+)
+
+// Config is the configuration of the chain built by a Builder
+type Config struct {
+	// Seed from which the keys of the accounts and the addresses of the contracts are derived
+	Seed string
+	// ChainID is the L2 chain id used to sign the txs
+	ChainID uint64
+	// ForkID of the chain since the first batch
+	ForkID uint64
+	// Coinbase of the batches
+	Coinbase common.Address
+	// GenesisTimestamp is the timestamp of the genesis, the batch N is timestamped
+	// at GenesisTimestamp + N * BatchInterval
+	GenesisTimestamp time.Time
+	// BatchInterval is the time elapsed between consecutive batches
+	BatchInterval time.Duration
+	// GasPrice of the txs
+	GasPrice *big.Int
+}
+
+// Account is an externally owned account funded in the genesis
+type Account struct {
+	Name       string
+	Address    common.Address
+	PrivateKey *ecdsa.PrivateKey
+	Balance    *big.Int
+}
+
+// Contract is a contract deployed in the genesis
+type Contract struct {
+	Name    string
+	Address common.Address
+	Code    []byte
+	Storage map[common.Hash]common.Hash
+}
+
+// BatchSpec is the specification of a batch added to the chain
+type BatchSpec struct {
+	Txs []*types.Transaction
+	// Profile returns the counters recorded as used by the batch, the counters used
+	// by the executor are recorded when it's nil
+	Profile CounterProfile
+	// ClosingReason recorded for the batch, state.BatchFullClosingReason when empty
+	ClosingReason state.ClosingReason
+}
+
+// Batch is a batch added to the chain
+type Batch struct {
+	Number    uint64
+	Timestamp time.Time
+	StateRoot common.Hash
+	Txs       []*types.Transaction
+	Counters  state.ZKCounters
+}
+
+// Builder builds a deterministic chain on top of the state of a node, two builders with
+// the same config and the same sequence of calls build the same chain
+type Builder struct {
+	cfg   Config
+	state stateInterface
+
+	accounts  []*Account
+	contracts []*Contract
+	names     map[string]common.Address
+	nonces    map[common.Address]uint64
+}
+
+// New creates a Builder instance
+func New(cfg Config, st stateInterface) *Builder {
+	if cfg.GasPrice == nil {
+		cfg.GasPrice = big.NewInt(0)
+	}
+	return &Builder{
+		cfg:    cfg,
+		state:  st,
+		names:  make(map[string]common.Address),
+		nonces: make(map[common.Address]uint64),
+	}
+}
+
+// AddAccount adds an account funded with the balance in the genesis, its key is derived
+// from the seed and the name
+func (b *Builder) AddAccount(name string, balance *big.Int) (*Account, error) {
+	if _, found := b.names[name]; found {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicatedName, name)
+	}
+	privateKey, err := crypto.ToECDSA(b.derive("account", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the key of account %s: %w", name, err)
+	}
+	account := &Account{
+		Name:       name,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+		Balance:    new(big.Int).Set(balance),
+	}
+	b.accounts = append(b.accounts, account)
+	b.names[name] = account.Address
+	return account, nil
+}
+
+// AddContract adds a contract with the code and storage in the genesis, its address is
+// derived from the seed and the name
+func (b *Builder) AddContract(name string, code []byte, storage map[common.Hash]common.Hash) (*Contract, error) {
+	if _, found := b.names[name]; found {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicatedName, name)
+	}
+	contract := &Contract{
+		Name:    name,
+		Address: common.BytesToAddress(b.derive("contract", name)),
+		Code:    code,
+		Storage: storage,
+	}
+	b.contracts = append(b.contracts, contract)
+	b.names[name] = contract.Address
+	return contract, nil
+}
+
+// Address returns the address of the account or contract with the given name
+func (b *Builder) Address(name string) (common.Address, error) {
+	address, found := b.names[name]
+	if !found {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrUnknownName, name)
+	}
+	return address, nil
+}
+
+// GenesisActions returns the genesis actions funding the accounts and deploying the
+// contracts, in the order they were added
+func (b *Builder) GenesisActions() []*state.GenesisAction {
+	actions := make([]*state.GenesisAction, 0, len(b.accounts)+len(b.contracts))
+	for _, account := range b.accounts {
+		actions = append(actions, &state.GenesisAction{
+			Address: account.Address.String(),
+			Type:    int(merkletree.LeafTypeBalance),
+			Value:   account.Balance.String(),
+		})
+	}
+	for _, contract := range b.contracts {
+		actions = append(actions, &state.GenesisAction{
+			Address:  contract.Address.String(),
+			Type:     int(merkletree.LeafTypeCode),
+			Bytecode: hex.EncodeToHex(contract.Code),
+		})
+		// the storage slots are sorted so the genesis doesn't depend on the map order
+		for _, slot := range sortedSlots(contract.Storage) {
+			actions = append(actions, &state.GenesisAction{
+				Address:         contract.Address.String(),
+				Type:            int(merkletree.LeafTypeStorage),
+				StoragePosition: slot.Big().String(),
+				Value:           contract.Storage[slot].Big().String(),
+			})
+		}
+	}
+	return actions
+}
+
+// Genesis stores the genesis with the accounts and contracts added so far and the fork id
+// of the chain, returning the genesis state root
+func (b *Builder) Genesis(ctx context.Context) (common.Hash, error) {
+	var root common.Hash
+	err := b.withDBTx(ctx, func(dbTx pgx.Tx) error {
+		block := state.Block{
+			BlockNumber: 0,
+			BlockHash:   state.ZeroHash,
+			ParentHash:  state.ZeroHash,
+			ReceivedAt:  b.cfg.GenesisTimestamp,
+		}
+		newRoot, err := b.state.SetGenesis(ctx, block, state.Genesis{GenesisActions: b.GenesisActions()}, dbTx)
+		if err != nil {
+			return fmt.Errorf("failed to set the genesis: %w", err)
+		}
+		root = common.BytesToHash(newRoot)
+
+		forkID := state.ForkIDInterval{
+			FromBatchNumber: 1,
+			ToBatchNumber:   math.MaxUint64,
+			ForkId:          b.cfg.ForkID,
+			Version:         "testchain",
+			BlockNumber:     block.BlockNumber,
+		}
+		if err := b.state.AddForkIDInterval(ctx, forkID, dbTx); err != nil {
+			return fmt.Errorf("failed to add the fork id: %w", err)
+		}
+		return nil
+	})
+	return root, err
+}
+
+// Transfer returns a signed tx transferring the value between the accounts or contracts
+// with the given names, the nonce of the sender is consumed
+func (b *Builder) Transfer(from, to string, value *big.Int) (*types.Transaction, error) {
+	toAddress, err := b.Address(to)
+	if err != nil {
+		return nil, err
+	}
+	return b.signTx(from, &toAddress, value, transferGas, nil)
+}
+
+// Call returns a signed tx calling the contract with the given name, the nonce of the
+// sender is consumed
+func (b *Builder) Call(from, contract string, data []byte, gas uint64) (*types.Transaction, error) {
+	toAddress, err := b.Address(contract)
+	if err != nil {
+		return nil, err
+	}
+	return b.signTx(from, &toAddress, big.NewInt(0), gas, data)
+}
+
+// Deploy returns a signed tx deploying a contract and the address where it's deployed,
+// the nonce of the sender is consumed
+func (b *Builder) Deploy(from string, code []byte, gas uint64) (*types.Transaction, common.Address, error) {
+	tx, err := b.signTx(from, nil, big.NewInt(0), gas, code)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	fromAddress, _ := b.Address(from)
+	return tx, crypto.CreateAddress(fromAddress, tx.Nonce()), nil
+}
+
+// AddBatch processes the txs of the spec in a new batch and closes it recording the
+// counters of its profile. All the txs must be processed, the nonces consumed by the
+// txs are not restored if the batch can't be added
+func (b *Builder) AddBatch(ctx context.Context, spec BatchSpec) (*Batch, error) {
+	var batch *Batch
+	err := b.withDBTx(ctx, func(dbTx pgx.Tx) error {
+		lastBatchNumber, err := b.state.GetLastBatchNumber(ctx, dbTx)
+		if err != nil {
+			return fmt.Errorf("failed to get the last batch number: %w", err)
+		}
+		batchNumber := lastBatchNumber + 1
+		timestamp := b.cfg.GenesisTimestamp.Add(time.Duration(batchNumber) * b.cfg.BatchInterval)
+
+		processingCtx := state.ProcessingContext{
+			BatchNumber:    batchNumber,
+			Coinbase:       b.cfg.Coinbase,
+			Timestamp:      timestamp,
+			GlobalExitRoot: state.ZeroHash,
+		}
+		if err := b.state.OpenBatch(ctx, processingCtx, dbTx); err != nil {
+			return fmt.Errorf("failed to open batch %d: %w", batchNumber, err)
+		}
+
+		txs := make([]types.Transaction, 0, len(spec.Txs))
+		effectivePercentages := make([]uint8, 0, len(spec.Txs))
+		for _, tx := range spec.Txs {
+			txs = append(txs, *tx)
+			effectivePercentages = append(effectivePercentages, state.MaxEffectivePercentage)
+		}
+		batchL2Data, err := state.EncodeTransactions(txs, effectivePercentages, b.cfg.ForkID)
+		if err != nil {
+			return fmt.Errorf("failed to encode the txs of batch %d: %w", batchNumber, err)
+		}
+
+		response, err := b.state.ProcessSequencerBatch(ctx, batchNumber, batchL2Data, metrics.SequencerCallerLabel, dbTx)
+		if err != nil {
+			return fmt.Errorf("failed to process batch %d: %w", batchNumber, err)
+		}
+		if response.ExecutorError != nil {
+			return fmt.Errorf("failed to process batch %d: %w", batchNumber, response.ExecutorError)
+		}
+		for _, txResponse := range response.Responses {
+			if !txResponse.ChangesStateRoot {
+				return fmt.Errorf("%w: tx %s of batch %d, err: %v", ErrTxNotProcessed, txResponse.TxHash, batchNumber, txResponse.RomError)
+			}
+		}
+		if len(response.Responses) != len(spec.Txs) {
+			return fmt.Errorf("%w: %d of %d txs of batch %d processed", ErrTxNotProcessed, len(response.Responses), len(spec.Txs), batchNumber)
+		}
+
+		if len(response.Responses) > 0 {
+			if err := b.state.StoreTransactions(ctx, batchNumber, response.Responses, nil, dbTx); err != nil {
+				return fmt.Errorf("failed to store the txs of batch %d: %w", batchNumber, err)
+			}
+		}
+
+		profile := spec.Profile
+		if profile == nil {
+			profile = ExecutorProfile
+		}
+		closingReason := spec.ClosingReason
+		if closingReason == state.EmptyClosingReason {
+			closingReason = state.BatchFullClosingReason
+		}
+		counters := profile(response.UsedZkCounters)
+		receipt := state.ProcessingReceipt{
+			BatchNumber:    batchNumber,
+			StateRoot:      response.NewStateRoot,
+			LocalExitRoot:  response.NewLocalExitRoot,
+			AccInputHash:   response.NewAccInputHash,
+			BatchL2Data:    batchL2Data,
+			ClosingReason:  closingReason,
+			BatchResources: state.BatchResources{ZKCounters: counters, Bytes: uint64(len(batchL2Data))},
+		}
+		if err := b.state.CloseBatch(ctx, receipt, dbTx); err != nil {
+			return fmt.Errorf("failed to close batch %d: %w", batchNumber, err)
+		}
+
+		batch = &Batch{
+			Number:    batchNumber,
+			Timestamp: timestamp,
+			StateRoot: response.NewStateRoot,
+			Txs:       spec.Txs,
+			Counters:  counters,
+		}
+		return nil
+	})
+	return batch, err
+}
+
+func (b *Builder) signTx(from string, to *common.Address, value *big.Int, gas uint64, data []byte) (*types.Transaction, error) {
+	account := b.account(from)
+	if account == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownName, from)
+	}
+	nonce := b.nonces[account.Address]
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       to,
+		Value:    value,
+		Gas:      gas,
+		GasPrice: b.cfg.GasPrice,
+		Data:     data,
+	})
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(new(big.Int).SetUint64(b.cfg.ChainID)), account.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx of account %s: %w", from, err)
+	}
+	b.nonces[account.Address] = nonce + 1
+	return signedTx, nil
+}
+
+func (b *Builder) account(name string) *Account {
+	for _, account := range b.accounts {
+		if account.Name == name {
+			return account
+		}
+	}
+	return nil
+}
+
+// derive returns 32 bytes derived from the seed, the kind and the name
+func (b *Builder) derive(kind, name string) []byte {
+	return crypto.Keccak256([]byte(b.cfg.Seed), []byte(kind), []byte(name))
+}
+
+func (b *Builder) withDBTx(ctx context.Context, fn func(dbTx pgx.Tx) error) error {
+	dbTx, err := b.state.BeginStateTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin the state transaction: %w", err)
+	}
+	if err := fn(dbTx); err != nil {
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			log.Errorf("failed to rollback the state transaction, err: %v", rollbackErr)
+		}
+		return err
+	}
+	return dbTx.Commit(ctx)
+}
+
+func sortedSlots(storage map[common.Hash]common.Hash) []common.Hash {
+	slots := make([]common.Hash, 0, len(storage))
+	for slot := range storage {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].Big().Cmp(slots[j].Big()) < 0
+	})
+	return slots
+}
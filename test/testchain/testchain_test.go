@@ -0,0 +1,117 @@
+package testchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBuilder(t *testing.T, seed string) *Builder {
+	b := New(Config{Seed: seed, ChainID: 1001, GasPrice: big.NewInt(1)}, nil)
+	_, err := b.AddAccount("alice", big.NewInt(1000))
+	require.NoError(t, err)
+	_, err = b.AddAccount("bob", big.NewInt(2000))
+	require.NoError(t, err)
+	_, err = b.AddContract("counter", []byte{0x60, 0x00}, map[common.Hash]common.Hash{{2}: {0x22}, {1}: {0x11}})
+	require.NoError(t, err)
+	return b
+}
+
+func TestBuilderIsDeterministic(t *testing.T) {
+	b1 := newTestBuilder(t, "seed")
+	b2 := newTestBuilder(t, "seed")
+	b3 := newTestBuilder(t, "another seed")
+
+	for _, name := range []string{"alice", "bob", "counter"} {
+		address1, err := b1.Address(name)
+		require.NoError(t, err)
+		address2, err := b2.Address(name)
+		require.NoError(t, err)
+		address3, err := b3.Address(name)
+		require.NoError(t, err)
+		assert.Equal(t, address1, address2)
+		assert.NotEqual(t, address1, address3)
+	}
+
+	tx1, err := b1.Transfer("alice", "bob", big.NewInt(10))
+	require.NoError(t, err)
+	tx2, err := b2.Transfer("alice", "bob", big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, tx1.Hash(), tx2.Hash())
+	assert.Equal(t, b1.GenesisActions(), b2.GenesisActions())
+
+	_, err = b1.AddAccount("alice", big.NewInt(1))
+	assert.ErrorIs(t, err, ErrDuplicatedName)
+	_, err = b1.Transfer("carol", "bob", big.NewInt(1))
+	assert.ErrorIs(t, err, ErrUnknownName)
+}
+
+func TestBuilderTxs(t *testing.T) {
+	b := newTestBuilder(t, "seed")
+	alice, err := b.Address("alice")
+	require.NoError(t, err)
+	counter, err := b.Address("counter")
+	require.NoError(t, err)
+	signer := types.NewEIP155Signer(big.NewInt(1001))
+
+	transfer, err := b.Transfer("alice", "bob", big.NewInt(10))
+	require.NoError(t, err)
+	call, err := b.Call("alice", "counter", []byte{0x01}, 50000)
+	require.NoError(t, err)
+	deploy, deployedAt, err := b.Deploy("alice", []byte{0x60, 0x00}, 100000)
+	require.NoError(t, err)
+
+	for i, tx := range []*types.Transaction{transfer, call, deploy} {
+		assert.Equal(t, uint64(i), tx.Nonce())
+		sender, err := types.Sender(signer, tx)
+		require.NoError(t, err)
+		assert.Equal(t, alice, sender)
+	}
+	assert.Equal(t, counter, *call.To())
+	assert.Nil(t, deploy.To())
+	assert.NotEqual(t, common.Address{}, deployedAt)
+
+	// the nonce of bob is not consumed by the txs of alice
+	bobTx, err := b.Transfer("bob", "alice", big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), bobTx.Nonce())
+}
+
+func TestBuilderGenesisActions(t *testing.T) {
+	b := newTestBuilder(t, "seed")
+	actions := b.GenesisActions()
+	require.Len(t, actions, 5)
+
+	assert.Equal(t, int(merkletree.LeafTypeBalance), actions[0].Type)
+	assert.Equal(t, "1000", actions[0].Value)
+	assert.Equal(t, int(merkletree.LeafTypeBalance), actions[1].Type)
+	assert.Equal(t, "2000", actions[1].Value)
+	assert.Equal(t, int(merkletree.LeafTypeCode), actions[2].Type)
+	assert.Equal(t, "0x6000", actions[2].Bytecode)
+	// the storage slots are sorted by position
+	assert.Equal(t, int(merkletree.LeafTypeStorage), actions[3].Type)
+	assert.Equal(t, common.Hash{1}.Big().String(), actions[3].StoragePosition)
+	assert.Equal(t, common.Hash{0x11}.Big().String(), actions[3].Value)
+	assert.Equal(t, common.Hash{2}.Big().String(), actions[4].StoragePosition)
+}
+
+func TestCounterProfiles(t *testing.T) {
+	used := state.ZKCounters{CumulativeGasUsed: 21000, UsedSteps: 1000}
+	assert.Equal(t, used, ExecutorProfile(used))
+
+	fixed := state.ZKCounters{UsedKeccakHashes: 10}
+	assert.Equal(t, fixed, FixedProfile(fixed)(used))
+
+	constraints := state.BatchConstraintsCfg{MaxCumulativeGasUsed: 1000, MaxKeccakHashes: 100, MaxSteps: 200}
+	counters := NearLimitProfile(constraints, 95)(used)
+	assert.Equal(t, uint64(950), counters.CumulativeGasUsed)
+	assert.Equal(t, uint32(95), counters.UsedKeccakHashes)
+	assert.Equal(t, uint32(190), counters.UsedSteps)
+	assert.True(t, constraints.IsWithinConstraints(counters))
+}
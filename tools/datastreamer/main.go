@@ -323,6 +323,11 @@ func reprocess(cliCtx *cli.Context) error {
 			printEntry(currentEntry)
 			entryToUpdate = nil
 			continue
+		case state.EntryTypeRollback:
+			// the old state root of the entries sent again after a rollback isn't the one of the previous entry
+			printEntry(currentEntry)
+			printColored(color.FgRed, "Error: rehashing the entries after a rollback is not supported\n")
+			os.Exit(1)
 		case state.EntryTypeUpdateGER:
 			printEntry(currentEntry)
 			processBatchRequest = &executor.ProcessBatchRequest{
@@ -720,6 +725,16 @@ func printEntry(entry datastreamer.FileEntry) {
 		printColored(color.FgHiWhite, fmt.Sprintf("%d\n", updateGer.ForkID))
 		printColored(color.FgGreen, "State Root......: ")
 		printColored(color.FgHiWhite, fmt.Sprint(updateGer.StateRoot.Hex()+"\n"))
+	case state.EntryTypeRollback:
+		rollback := state.DSRollback{}.Decode(entry.Data)
+		printColored(color.FgGreen, "Entry Type......: ")
+		printColored(color.FgHiYellow, "Rollback\n")
+		printColored(color.FgGreen, "Entry Number....: ")
+		printColored(color.FgHiWhite, fmt.Sprintf("%d\n", entry.Number))
+		printColored(color.FgGreen, "Batch Number....: ")
+		printColored(color.FgHiWhite, fmt.Sprintf("%d\n", rollback.BatchNumber))
+		printColored(color.FgGreen, "L2 Block Number.: ")
+		printColored(color.FgHiWhite, fmt.Sprintf("%d\n", rollback.L2BlockNumber))
 	}
 }
 